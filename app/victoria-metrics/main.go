@@ -22,6 +22,7 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/procutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promscrape"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/pushmetrics"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/pushprofiles"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/storage"
 )
 
@@ -33,7 +34,7 @@ var (
 	minScrapeInterval = flag.Duration("dedup.minScrapeInterval", 0, "Leave only the last sample in every time series per each discrete interval "+
 		"equal to -dedup.minScrapeInterval > 0. See also -streamAggr.dedupInterval and https://docs.victoriametrics.com/#deduplication")
 	dryRun = flag.Bool("dryRun", false, "Whether to check config files without running VictoriaMetrics. The following config files are checked: "+
-		"-promscrape.config, -relabelConfig and -streamAggr.config. Unknown config entries aren't allowed in -promscrape.config by default. "+
+		"-promscrape.config, -relabelConfig, -ingest.allowMetrics, -ingest.denyMetrics and -streamAggr.config. Unknown config entries aren't allowed in -promscrape.config by default. "+
 		"This can be changed with -promscrape.config.strictParse=false command-line flag")
 	inmemoryDataFlushInterval = flag.Duration("inmemoryDataFlushInterval", 5*time.Second, "The interval for guaranteed saving of in-memory data to disk. "+
 		"The saved data survives unclean shutdowns such as OOM crash, hardware reset, SIGKILL, etc. "+
@@ -59,6 +60,9 @@ func main() {
 		if err := vminsertrelabel.CheckRelabelConfig(); err != nil {
 			logger.Fatalf("error when checking -relabelConfig: %s", err)
 		}
+		if err := vminsertrelabel.CheckMetricFilters(); err != nil {
+			logger.Fatalf("error when checking metric name filters: %s", err)
+		}
 		if err := vminsertcommon.CheckStreamAggrConfig(); err != nil {
 			logger.Fatalf("error when checking -streamAggr.config: %s", err)
 		}
@@ -84,8 +88,10 @@ func main() {
 	logger.Infof("started VictoriaMetrics in %.3f seconds", time.Since(startTime).Seconds())
 
 	pushmetrics.Init()
+	pushprofiles.Init()
 	sig := procutil.WaitForSigterm()
 	logger.Infof("received signal %s", sig)
+	pushprofiles.Stop()
 	pushmetrics.Stop()
 
 	stopSelfScraper()
@@ -122,12 +128,14 @@ func requestHandler(w http.ResponseWriter, r *http.Request) bool {
 			{"metric-relabel-debug", "debug metric relabeling"},
 			{"expand-with-exprs", "WITH expressions' tutorial"},
 			{"api/v1/targets", "advanced information about discovered targets in JSON format"},
+			{"api/v1/targets/cardinality", "top targets by scraped series, new series churn and scrape duration over the last hour"},
 			{"config", "-promscrape.config contents"},
 			{"metrics", "available service metrics"},
 			{"flags", "command-line flags"},
 			{"api/v1/status/tsdb", "tsdb status page"},
 			{"api/v1/status/top_queries", "top queries"},
 			{"api/v1/status/active_queries", "active queries"},
+			{"api/v1/status/health", "health summary"},
 			{"-/reload", "reload configuration"},
 		})
 		return true
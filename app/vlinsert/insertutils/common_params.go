@@ -110,6 +110,9 @@ type logMessageProcessor struct {
 
 	cp *CommonParams
 	lr *logstorage.LogRows
+
+	// fieldsBuf is a buffer for fields with the added sample rate field
+	fieldsBuf []logstorage.Field
 }
 
 func (lmp *logMessageProcessor) initPeriodicFlush() {
@@ -150,6 +153,23 @@ func (lmp *logMessageProcessor) AddRow(timestamp int64, fields []logstorage.Fiel
 		return
 	}
 
+	if sampler != nil {
+		sampleRate, keep := sampler.Sample(fields, lmp.cp.StreamFields)
+		if !keep {
+			rowsDroppedTotalSampling.Inc()
+			return
+		}
+		if sampleRate != "" {
+			// Do not modify fields, since they are owned by the caller.
+			lmp.fieldsBuf = append(lmp.fieldsBuf[:0], fields...)
+			lmp.fieldsBuf = append(lmp.fieldsBuf, logstorage.Field{
+				Name:  logstorage.SampleRateFieldName,
+				Value: sampleRate,
+			})
+			fields = lmp.fieldsBuf
+		}
+	}
+
 	lmp.lr.MustAdd(lmp.cp.TenantID, timestamp, fields)
 	if lmp.cp.Debug {
 		s := lmp.lr.GetRowString(0)
@@ -0,0 +1,37 @@
+package insertutils
+
+import (
+	"flag"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fs/fscore"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logstorage"
+)
+
+var samplingRulesFile = flag.String("insert.samplingRulesFile", "", "Optional path to a file with sampling rules for the ingested logs. "+
+	"The path can point either to local file or to http url. "+
+	"See https://docs.victoriametrics.com/victorialogs/data-ingestion/#sampling")
+
+var sampler *logstorage.Sampler
+
+// MustInitSampler initializes sampler from -insert.samplingRulesFile.
+//
+// It must be called before the data ingestion starts.
+func MustInitSampler() {
+	if *samplingRulesFile == "" {
+		return
+	}
+	data, err := fscore.ReadFileOrHTTP(*samplingRulesFile)
+	if err != nil {
+		logger.Fatalf("cannot read -insert.samplingRulesFile=%q: %s", *samplingRulesFile, err)
+	}
+	s, err := logstorage.ParseSamplingRules(data)
+	if err != nil {
+		logger.Fatalf("cannot parse -insert.samplingRulesFile=%q: %s", *samplingRulesFile, err)
+	}
+	sampler = s
+}
+
+var rowsDroppedTotalSampling = metrics.NewCounter(`vl_rows_dropped_total{reason="sampling"}`)
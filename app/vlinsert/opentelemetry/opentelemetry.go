@@ -0,0 +1,259 @@
+package opentelemetry
+
+import (
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vlinsert/insertutils"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vlstorage"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/httpserver"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logstorage"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/common"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/writeconcurrencylimiter"
+)
+
+// RequestHandler processes OpenTelemetry insert requests
+func RequestHandler(path string, w http.ResponseWriter, r *http.Request) bool {
+	switch path {
+	case "/v1/logs":
+		handleLogs(r, w)
+		return true
+	case "/v1/traces":
+		handleTraces(r, w)
+		return true
+	default:
+		return false
+	}
+}
+
+// See https://opentelemetry.io/docs/specs/otlp/#otlphttp
+func handleLogs(r *http.Request, w http.ResponseWriter) {
+	startTime := time.Now()
+	requestsLogsTotal.Inc()
+
+	data, err := readRequestBody(r)
+	if err != nil {
+		httpserver.Errorf(w, r, "%s", err)
+		return
+	}
+	cp, err := insertutils.GetCommonParams(r)
+	if err != nil {
+		httpserver.Errorf(w, r, "cannot parse common params from request: %s", err)
+		return
+	}
+	if err := vlstorage.CanWriteData(); err != nil {
+		httpserver.Errorf(w, r, "%s", err)
+		return
+	}
+
+	var req pb.ExportLogsServiceRequest
+	if err := req.UnmarshalProtobuf(data); err != nil {
+		httpserver.Errorf(w, r, "cannot parse OpenTelemetry protobuf request: %s", err)
+		return
+	}
+	if len(cp.StreamFields) == 0 {
+		// Use resource attributes as stream fields by default.
+		cp.StreamFields = getStreamFields(getLogsResources(&req))
+	}
+
+	lmp := cp.NewLogMessageProcessor()
+	n := pushLogs(&req, lmp)
+	lmp.MustClose()
+
+	rowsIngestedLogsTotal.Add(n)
+
+	// update requestLogsDuration only for successfully parsed requests
+	// There is no need in updating requestLogsDuration for request errors,
+	// since their timings are usually much smaller than the timing for successful request parsing.
+	requestLogsDuration.UpdateDuration(startTime)
+}
+
+// See https://opentelemetry.io/docs/specs/otlp/#otlphttp
+func handleTraces(r *http.Request, w http.ResponseWriter) {
+	startTime := time.Now()
+	requestsTracesTotal.Inc()
+
+	data, err := readRequestBody(r)
+	if err != nil {
+		httpserver.Errorf(w, r, "%s", err)
+		return
+	}
+	cp, err := insertutils.GetCommonParams(r)
+	if err != nil {
+		httpserver.Errorf(w, r, "cannot parse common params from request: %s", err)
+		return
+	}
+	if err := vlstorage.CanWriteData(); err != nil {
+		httpserver.Errorf(w, r, "%s", err)
+		return
+	}
+
+	var req pb.ExportTraceServiceRequest
+	if err := req.UnmarshalProtobuf(data); err != nil {
+		httpserver.Errorf(w, r, "cannot parse OpenTelemetry protobuf request: %s", err)
+		return
+	}
+	if len(cp.StreamFields) == 0 {
+		// Use resource attributes as stream fields by default.
+		cp.StreamFields = getStreamFields(getTracesResources(&req))
+	}
+
+	lmp := cp.NewLogMessageProcessor()
+	n := pushSpans(&req, lmp)
+	lmp.MustClose()
+
+	rowsIngestedTracesTotal.Add(n)
+
+	// update requestTracesDuration only for successfully parsed requests
+	// There is no need in updating requestTracesDuration for request errors,
+	// since their timings are usually much smaller than the timing for successful request parsing.
+	requestTracesDuration.UpdateDuration(startTime)
+}
+
+var (
+	requestsLogsTotal     = metrics.NewCounter(`vl_http_requests_total{path="/insert/opentelemetry/v1/logs",format="protobuf"}`)
+	rowsIngestedLogsTotal = metrics.NewCounter(`vl_rows_ingested_total{type="opentelemetry",format="protobuf"}`)
+	requestLogsDuration   = metrics.NewHistogram(`vl_http_request_duration_seconds{path="/insert/opentelemetry/v1/logs",format="protobuf"}`)
+
+	requestsTracesTotal     = metrics.NewCounter(`vl_http_requests_total{path="/insert/opentelemetry/v1/traces",format="protobuf"}`)
+	rowsIngestedTracesTotal = metrics.NewCounter(`vl_rows_ingested_total{type="opentelemetry_traces",format="protobuf"}`)
+	requestTracesDuration   = metrics.NewHistogram(`vl_http_request_duration_seconds{path="/insert/opentelemetry/v1/traces",format="protobuf"}`)
+)
+
+// readRequestBody reads protobuf-encoded request body from r.
+func readRequestBody(r *http.Request) ([]byte, error) {
+	if r.Header.Get("Content-Type") == "application/json" {
+		return nil, fmt.Errorf("json encoding isn't supported for opentelemetry format. Use protobuf encoding")
+	}
+
+	reader := r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := common.GetGzipReader(reader)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read gzipped OpenTelemetry protobuf request: %w", err)
+		}
+		defer common.PutGzipReader(zr)
+		reader = zr
+	}
+
+	wcr := writeconcurrencylimiter.GetReader(reader)
+	data, err := io.ReadAll(wcr)
+	writeconcurrencylimiter.PutReader(wcr)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read request body: %w", err)
+	}
+	return data, nil
+}
+
+func getLogsResources(req *pb.ExportLogsServiceRequest) []*pb.Resource {
+	resources := make([]*pb.Resource, 0, len(req.ResourceLogs))
+	for _, rl := range req.ResourceLogs {
+		resources = append(resources, rl.Resource)
+	}
+	return resources
+}
+
+func getTracesResources(req *pb.ExportTraceServiceRequest) []*pb.Resource {
+	resources := make([]*pb.Resource, 0, len(req.ResourceSpans))
+	for _, rs := range req.ResourceSpans {
+		resources = append(resources, rs.Resource)
+	}
+	return resources
+}
+
+// getStreamFields returns sorted names of attributes for the given resources.
+func getStreamFields(resources []*pb.Resource) []string {
+	var streamFields []string
+	for _, r := range resources {
+		if r == nil {
+			continue
+		}
+		for _, a := range r.Attributes {
+			if !slices.Contains(streamFields, a.Key) {
+				streamFields = append(streamFields, a.Key)
+			}
+		}
+	}
+	slices.Sort(streamFields)
+	return streamFields
+}
+
+// pushLogs pushes logs from req to lmp and returns the number of pushed logs.
+//
+// Resource attributes and log attributes are stored as log fields, while the log body is stored in the _msg field.
+func pushLogs(req *pb.ExportLogsServiceRequest, lmp insertutils.LogMessageProcessor) int {
+	var fields []logstorage.Field
+	rowsIngested := 0
+	currentTimestamp := time.Now().UnixNano()
+	for _, rl := range req.ResourceLogs {
+		fields = fields[:0]
+		if rl.Resource != nil {
+			fields = appendAttributesToFields(fields, rl.Resource.Attributes)
+		}
+		commonFieldsLen := len(fields)
+		for _, sl := range rl.ScopeLogs {
+			for _, lr := range sl.LogRecords {
+				fields = fields[:commonFieldsLen]
+				fields = appendAttributesToFields(fields, lr.Attributes)
+				if severity := lr.FormatSeverity(); severity != "" {
+					fields = append(fields, logstorage.Field{
+						Name:  "severity",
+						Value: severity,
+					})
+				}
+				if len(lr.TraceID) > 0 {
+					fields = append(fields, logstorage.Field{
+						Name:  "trace_id",
+						Value: hex.EncodeToString(lr.TraceID),
+					})
+				}
+				if len(lr.SpanID) > 0 {
+					fields = append(fields, logstorage.Field{
+						Name:  "span_id",
+						Value: hex.EncodeToString(lr.SpanID),
+					})
+				}
+				msg := ""
+				if lr.Body != nil {
+					msg = lr.Body.FormatString()
+				}
+				fields = append(fields, logstorage.Field{
+					Name:  "_msg",
+					Value: msg,
+				})
+
+				ts := int64(lr.TimeUnixNano)
+				if ts == 0 {
+					ts = int64(lr.ObservedTimeUnixNano)
+				}
+				if ts == 0 {
+					ts = currentTimestamp
+				}
+				lmp.AddRow(ts, fields)
+				rowsIngested++
+			}
+		}
+	}
+	return rowsIngested
+}
+
+func appendAttributesToFields(dst []logstorage.Field, attributes []*pb.KeyValue) []logstorage.Field {
+	for _, a := range attributes {
+		value := ""
+		if a.Value != nil {
+			value = a.Value.FormatString()
+		}
+		dst = append(dst, logstorage.Field{
+			Name:  a.Key,
+			Value: value,
+		})
+	}
+	return dst
+}
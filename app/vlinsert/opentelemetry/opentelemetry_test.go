@@ -0,0 +1,113 @@
+package opentelemetry
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vlinsert/insertutils"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+)
+
+func TestPushLogs(t *testing.T) {
+	f := func(req *pb.ExportLogsServiceRequest, streamFieldsExpected []string, timestampsExpected []int64, resultExpected string) {
+		t.Helper()
+
+		// Verify the request survives protobuf round-trip
+		data := req.MarshalProtobuf(nil)
+		var r pb.ExportLogsServiceRequest
+		if err := r.UnmarshalProtobuf(data); err != nil {
+			t.Fatalf("unexpected error when unmarshaling request: %s", err)
+		}
+
+		streamFields := getStreamFields(getLogsResources(&r))
+		if !reflect.DeepEqual(streamFields, streamFieldsExpected) {
+			t.Fatalf("unexpected stream fields; got %q; want %q", streamFields, streamFieldsExpected)
+		}
+
+		tlp := &insertutils.TestLogMessageProcessor{}
+		n := pushLogs(&r, tlp)
+		if err := tlp.Verify(n, timestampsExpected, resultExpected); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stringValue := func(s string) *pb.AnyValue {
+		return &pb.AnyValue{
+			StringValue: &s,
+		}
+	}
+	intValue := func(n int64) *pb.AnyValue {
+		return &pb.AnyValue{
+			IntValue: &n,
+		}
+	}
+
+	// empty request
+	f(&pb.ExportLogsServiceRequest{}, nil, nil, "")
+
+	// multiple resources
+	f(&pb.ExportLogsServiceRequest{
+		ResourceLogs: []*pb.ResourceLogs{
+			{
+				Resource: &pb.Resource{
+					Attributes: []*pb.KeyValue{
+						{
+							Key:   "service.name",
+							Value: stringValue("foo"),
+						},
+						{
+							Key:   "host",
+							Value: stringValue("h1"),
+						},
+					},
+				},
+				ScopeLogs: []*pb.ScopeLogs{
+					{
+						LogRecords: []*pb.LogRecord{
+							{
+								TimeUnixNano: 1234,
+								SeverityText: "error",
+								Body:         stringValue("cannot open file"),
+								Attributes: []*pb.KeyValue{
+									{
+										Key:   "user_id",
+										Value: intValue(42),
+									},
+								},
+								TraceID: []byte{0x01, 0x02, 0xab, 0xcd},
+								SpanID:  []byte{0xff, 0x00},
+							},
+							{
+								ObservedTimeUnixNano: 5678,
+								SeverityNumber:       9,
+								Body:                 stringValue("started"),
+							},
+						},
+					},
+				},
+			},
+			{
+				Resource: &pb.Resource{
+					Attributes: []*pb.KeyValue{
+						{
+							Key:   "service.name",
+							Value: stringValue("bar"),
+						},
+					},
+				},
+				ScopeLogs: []*pb.ScopeLogs{
+					{
+						LogRecords: []*pb.LogRecord{
+							{
+								TimeUnixNano: 9012,
+								Body:         intValue(123),
+							},
+						},
+					},
+				},
+			},
+		},
+	}, []string{"host", "service.name"}, []int64{1234, 5678, 9012}, `{"service.name":"foo","host":"h1","user_id":"42","severity":"error","trace_id":"0102abcd","span_id":"ff00","_msg":"cannot open file"}
+{"service.name":"foo","host":"h1","severity":"INFO","_msg":"started"}
+{"service.name":"bar","_msg":"123"}`)
+}
@@ -0,0 +1,160 @@
+package opentelemetry
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vlinsert/insertutils"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logstorage"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+)
+
+// pushSpans pushes spans from req to lmp and returns the number of pushed spans.
+//
+// Every span is stored as a wide event with the span name in the _msg field and the span start time in the _time field.
+// Resource attributes and span attributes are stored as log fields, so spans can be queried with LogsQL by trace_id, span_id and attributes.
+func pushSpans(req *pb.ExportTraceServiceRequest, lmp insertutils.LogMessageProcessor) int {
+	var fields []logstorage.Field
+	rowsIngested := 0
+	currentTimestamp := time.Now().UnixNano()
+	for _, rs := range req.ResourceSpans {
+		fields = fields[:0]
+		if rs.Resource != nil {
+			fields = appendAttributesToFields(fields, rs.Resource.Attributes)
+		}
+		commonFieldsLen := len(fields)
+		for _, ss := range rs.ScopeSpans {
+			for _, s := range ss.Spans {
+				fields = fields[:commonFieldsLen]
+				fields = appendAttributesToFields(fields, s.Attributes)
+				fields = appendSpanFields(fields, s)
+
+				ts := int64(s.StartTimeUnixNano)
+				if ts == 0 {
+					ts = currentTimestamp
+				}
+				lmp.AddRow(ts, fields)
+				rowsIngested++
+			}
+		}
+	}
+	return rowsIngested
+}
+
+func appendSpanFields(dst []logstorage.Field, s *pb.Span) []logstorage.Field {
+	dst = appendHexField(dst, "trace_id", s.TraceID)
+	dst = appendHexField(dst, "span_id", s.SpanID)
+	dst = appendHexField(dst, "parent_span_id", s.ParentSpanID)
+	if s.TraceState != "" {
+		dst = append(dst, logstorage.Field{
+			Name:  "trace_state",
+			Value: s.TraceState,
+		})
+	}
+	dst = append(dst, logstorage.Field{
+		Name:  "kind",
+		Value: s.Kind.String(),
+	})
+	if s.EndTimeUnixNano >= s.StartTimeUnixNano && s.StartTimeUnixNano > 0 {
+		dst = append(dst, logstorage.Field{
+			Name:  "duration",
+			Value: strconv.FormatUint(s.EndTimeUnixNano-s.StartTimeUnixNano, 10),
+		})
+	}
+	if s.Status != nil {
+		dst = append(dst, logstorage.Field{
+			Name:  "status_code",
+			Value: s.Status.Code.String(),
+		})
+		if s.Status.Message != "" {
+			dst = append(dst, logstorage.Field{
+				Name:  "status_message",
+				Value: s.Status.Message,
+			})
+		}
+	}
+	if len(s.Events) > 0 {
+		dst = append(dst, logstorage.Field{
+			Name:  "events",
+			Value: marshalSpanEvents(s.Events),
+		})
+	}
+	if len(s.Links) > 0 {
+		dst = append(dst, logstorage.Field{
+			Name:  "links",
+			Value: marshalSpanLinks(s.Links),
+		})
+	}
+	dst = append(dst, logstorage.Field{
+		Name:  "_msg",
+		Value: s.Name,
+	})
+	return dst
+}
+
+func appendHexField(dst []logstorage.Field, name string, b []byte) []logstorage.Field {
+	if len(b) == 0 {
+		return dst
+	}
+	return append(dst, logstorage.Field{
+		Name:  name,
+		Value: hex.EncodeToString(b),
+	})
+}
+
+type spanEvent struct {
+	Time       string            `json:"time,omitempty"`
+	Name       string            `json:"name"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// marshalSpanEvents returns JSON array for the given events.
+func marshalSpanEvents(events []*pb.SpanEvent) string {
+	a := make([]spanEvent, len(events))
+	for i, e := range events {
+		a[i].Name = e.Name
+		if e.TimeUnixNano > 0 {
+			a[i].Time = time.Unix(0, int64(e.TimeUnixNano)).UTC().Format(time.RFC3339Nano)
+		}
+		a[i].Attributes = getAttributesMap(e.Attributes)
+	}
+	data, _ := json.Marshal(a)
+	return string(data)
+}
+
+type spanLink struct {
+	TraceID    string            `json:"trace_id"`
+	SpanID     string            `json:"span_id"`
+	TraceState string            `json:"trace_state,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// marshalSpanLinks returns JSON array for the given links.
+func marshalSpanLinks(links []*pb.SpanLink) string {
+	a := make([]spanLink, len(links))
+	for i, l := range links {
+		a[i].TraceID = hex.EncodeToString(l.TraceID)
+		a[i].SpanID = hex.EncodeToString(l.SpanID)
+		a[i].TraceState = l.TraceState
+		a[i].Attributes = getAttributesMap(l.Attributes)
+	}
+	data, _ := json.Marshal(a)
+	return string(data)
+}
+
+func getAttributesMap(attributes []*pb.KeyValue) map[string]string {
+	if len(attributes) == 0 {
+		return nil
+	}
+	m := make(map[string]string, len(attributes))
+	for _, a := range attributes {
+		value := ""
+		if a.Value != nil {
+			value = a.Value.FormatString()
+		}
+		m[a.Key] = value
+	}
+	return m
+}
@@ -0,0 +1,114 @@
+package opentelemetry
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vlinsert/insertutils"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+)
+
+func TestPushSpans(t *testing.T) {
+	f := func(req *pb.ExportTraceServiceRequest, streamFieldsExpected []string, timestampsExpected []int64, resultExpected string) {
+		t.Helper()
+
+		// Verify the request survives protobuf round-trip
+		data := req.MarshalProtobuf(nil)
+		var r pb.ExportTraceServiceRequest
+		if err := r.UnmarshalProtobuf(data); err != nil {
+			t.Fatalf("unexpected error when unmarshaling request: %s", err)
+		}
+
+		streamFields := getStreamFields(getTracesResources(&r))
+		if !reflect.DeepEqual(streamFields, streamFieldsExpected) {
+			t.Fatalf("unexpected stream fields; got %q; want %q", streamFields, streamFieldsExpected)
+		}
+
+		tlp := &insertutils.TestLogMessageProcessor{}
+		n := pushSpans(&r, tlp)
+		if err := tlp.Verify(n, timestampsExpected, resultExpected); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stringValue := func(s string) *pb.AnyValue {
+		return &pb.AnyValue{
+			StringValue: &s,
+		}
+	}
+
+	// empty request
+	f(&pb.ExportTraceServiceRequest{}, nil, nil, "")
+
+	// spans with events, links and status
+	f(&pb.ExportTraceServiceRequest{
+		ResourceSpans: []*pb.ResourceSpans{
+			{
+				Resource: &pb.Resource{
+					Attributes: []*pb.KeyValue{
+						{
+							Key:   "service.name",
+							Value: stringValue("api"),
+						},
+					},
+				},
+				ScopeSpans: []*pb.ScopeSpans{
+					{
+						Spans: []*pb.Span{
+							{
+								TraceID:           []byte{0x01, 0x02},
+								SpanID:            []byte{0x03, 0x04},
+								Name:              "GET /users",
+								Kind:              pb.SpanKindServer,
+								StartTimeUnixNano: 1000,
+								EndTimeUnixNano:   1500,
+								Attributes: []*pb.KeyValue{
+									{
+										Key:   "http.method",
+										Value: stringValue("GET"),
+									},
+								},
+								Status: &pb.SpanStatus{
+									Code: pb.SpanStatusCodeOk,
+								},
+							},
+							{
+								TraceID:           []byte{0x01, 0x02},
+								SpanID:            []byte{0x05, 0x06},
+								ParentSpanID:      []byte{0x03, 0x04},
+								TraceState:        "k=v",
+								Name:              "SELECT users",
+								Kind:              pb.SpanKindClient,
+								StartTimeUnixNano: 1100,
+								EndTimeUnixNano:   1300,
+								Events: []*pb.SpanEvent{
+									{
+										TimeUnixNano: 1200,
+										Name:         "exception",
+										Attributes: []*pb.KeyValue{
+											{
+												Key:   "exception.message",
+												Value: stringValue("timeout"),
+											},
+										},
+									},
+								},
+								Links: []*pb.SpanLink{
+									{
+										TraceID: []byte{0xaa},
+										SpanID:  []byte{0xbb},
+									},
+								},
+								Status: &pb.SpanStatus{
+									Code:    pb.SpanStatusCodeError,
+									Message: "query timeout",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, []string{"service.name"}, []int64{1000, 1100}, `{"service.name":"api","http.method":"GET","trace_id":"0102","span_id":"0304","kind":"server","duration":"500","status_code":"ok","_msg":"GET /users"}
+{"service.name":"api","trace_id":"0102","span_id":"0506","parent_span_id":"0304","trace_state":"k=v","kind":"client","duration":"200","status_code":"error","status_message":"query timeout","events":"[{\"time\":\"1970-01-01T00:00:00.0000012Z\",\"name\":\"exception\",\"attributes\":{\"exception.message\":\"timeout\"}}]","links":"[{\"trace_id\":\"aa\",\"span_id\":\"bb\"}]","_msg":"SELECT users"}`)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"net/http"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -560,6 +561,263 @@ func (tp *tailProcessor) getTailRows() ([][]logstorage.Field, error) {
 	return tailRows, nil
 }
 
+// ProcessStatsQueryRequest handles /select/logsql/stats_query request.
+//
+// See https://docs.victoriametrics.com/victorialogs/querying/#querying-log-stats
+func ProcessStatsQueryRequest(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	tenantIDs, err := getTenantIDs(r)
+	if err != nil {
+		httpserver.Errorf(w, r, "%s", err)
+		return
+	}
+
+	// Obtain time
+	timestamp, okTime, err := getTimeNsec(r, "time")
+	if err != nil {
+		httpserver.Errorf(w, r, "%s", err)
+		return
+	}
+	if !okTime {
+		timestamp = time.Now().UnixNano()
+	}
+
+	// Parse query
+	qStr := r.FormValue("query")
+	q, err := logstorage.ParseQueryAtTimestamp(qStr, timestamp)
+	if err != nil {
+		httpserver.Errorf(w, r, "cannot parse query [%s]: %s", qStr, err)
+		return
+	}
+	byFields, err := q.GetStatsByFields()
+	if err != nil {
+		httpserver.Errorf(w, r, "%s", err)
+		return
+	}
+	q.AddTimeFilter(math.MinInt64, timestamp)
+	q.Optimize()
+
+	var rows []statsRow
+	var rowsLock sync.Mutex
+	writeBlock := func(_ uint, timestamps []int64, columns []logstorage.BlockColumn) {
+		for i := range timestamps {
+			labels := getStatsLabels(byFields, columns, i)
+			for _, c := range columns {
+				if slices.Contains(byFields, c.Name) {
+					continue
+				}
+				r := statsRow{
+					name:   strings.Clone(c.Name),
+					labels: labels,
+					point: statsPoint{
+						timestamp: timestamp,
+						value:     strings.Clone(c.Values[i]),
+					},
+				}
+
+				rowsLock.Lock()
+				rows = append(rows, r)
+				rowsLock.Unlock()
+			}
+		}
+	}
+
+	if err := vlstorage.RunQuery(ctx, tenantIDs, q, writeBlock); err != nil {
+		httpserver.Errorf(w, r, "cannot execute query [%s]: %s", q, err)
+		return
+	}
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	WriteStatsQueryResponse(w, rows)
+}
+
+// ProcessStatsQueryRangeRequest handles /select/logsql/stats_query_range request.
+//
+// See https://docs.victoriametrics.com/victorialogs/querying/#querying-log-range-stats
+func ProcessStatsQueryRangeRequest(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	tenantIDs, err := getTenantIDs(r)
+	if err != nil {
+		httpserver.Errorf(w, r, "%s", err)
+		return
+	}
+
+	// Obtain start, end and step
+	start, okStart, err := getTimeNsec(r, "start")
+	if err != nil {
+		httpserver.Errorf(w, r, "%s", err)
+		return
+	}
+	end, okEnd, err := getTimeNsec(r, "end")
+	if err != nil {
+		httpserver.Errorf(w, r, "%s", err)
+		return
+	}
+	if !okEnd {
+		end = time.Now().UnixNano()
+	}
+	if !okStart {
+		start = math.MinInt64
+	}
+	if start > end {
+		httpserver.Errorf(w, r, "'start' cannot exceed 'end'")
+		return
+	}
+	stepStr := r.FormValue("step")
+	if stepStr == "" {
+		stepStr = "1d"
+	}
+	step, err := promutils.ParseDuration(stepStr)
+	if err != nil {
+		httpserver.Errorf(w, r, "cannot parse 'step' arg: %s", err)
+		return
+	}
+	if step <= 0 {
+		httpserver.Errorf(w, r, "'step' must be bigger than zero")
+		return
+	}
+
+	// Parse query
+	qStr := r.FormValue("query")
+	q, err := logstorage.ParseQueryAtTimestamp(qStr, end)
+	if err != nil {
+		httpserver.Errorf(w, r, "cannot parse query [%s]: %s", qStr, err)
+		return
+	}
+
+	// Align time buckets to start, so the returned timestamps are start, start+step, start+2*step, etc.
+	var offset int64
+	if okStart {
+		offset = start % int64(step)
+		if offset < 0 {
+			offset += int64(step)
+		}
+	}
+	byFields, err := q.GetStatsByFieldsAddGroupingByTime(int64(step), offset)
+	if err != nil {
+		httpserver.Errorf(w, r, "%s", err)
+		return
+	}
+	q.AddTimeFilter(start, end)
+	q.Optimize()
+
+	m := make(map[string]*statsSeries)
+	var mLock sync.Mutex
+	writeBlock := func(_ uint, timestamps []int64, columns []logstorage.BlockColumn) {
+		byColumns := getByFieldsColumns(byFields, columns)
+		bb := blockResultPool.Get()
+		for i := range timestamps {
+			timestamp := int64(math.MinInt64)
+			for _, c := range columns {
+				if c.Name == "_time" {
+					if ts, ok := logstorage.TryParseTimestampRFC3339Nano(c.Values[i]); ok {
+						timestamp = ts
+					}
+					break
+				}
+			}
+			if timestamp == math.MinInt64 {
+				logger.Panicf("BUG: missing _time column in the results for the query [%s]", q)
+			}
+
+			var labels []logstorage.Field
+			for _, c := range columns {
+				if c.Name == "_time" || slices.Contains(byFields, c.Name) {
+					continue
+				}
+
+				// The series key consists of the stats result name and the `by (...)` fields.
+				bb.B = append(bb.B[:0], c.Name...)
+				bb.B = append(bb.B, 0)
+				WriteFieldsForHits(bb, byColumns, i)
+				p := statsPoint{
+					timestamp: timestamp,
+					value:     strings.Clone(c.Values[i]),
+				}
+
+				mLock.Lock()
+				ss, ok := m[string(bb.B)]
+				if !ok {
+					if labels == nil {
+						labels = getStatsLabels(byFields, columns, i)
+					}
+					ss = &statsSeries{
+						key:    string(bb.B),
+						name:   strings.Clone(c.Name),
+						labels: labels,
+					}
+					m[ss.key] = ss
+				}
+				ss.points = append(ss.points, p)
+				mLock.Unlock()
+			}
+		}
+		blockResultPool.Put(bb)
+	}
+
+	if err := vlstorage.RunQuery(ctx, tenantIDs, q, writeBlock); err != nil {
+		httpserver.Errorf(w, r, "cannot execute query [%s]: %s", q, err)
+		return
+	}
+
+	series := make([]*statsSeries, 0, len(m))
+	for _, ss := range m {
+		sort.Slice(ss.points, func(i, j int) bool {
+			return ss.points[i].timestamp < ss.points[j].timestamp
+		})
+		series = append(series, ss)
+	}
+	sort.Slice(series, func(i, j int) bool {
+		return series[i].key < series[j].key
+	})
+
+	// Write response
+	w.Header().Set("Content-Type", "application/json")
+	WriteStatsQueryRangeResponse(w, series)
+}
+
+type statsRow struct {
+	name   string
+	labels []logstorage.Field
+	point  statsPoint
+}
+
+type statsSeries struct {
+	key    string
+	name   string
+	labels []logstorage.Field
+	points []statsPoint
+}
+
+type statsPoint struct {
+	timestamp int64
+	value     string
+}
+
+// getStatsLabels returns labels for the row at rowIdx from columns with byFields names.
+func getStatsLabels(byFields []string, columns []logstorage.BlockColumn, rowIdx int) []logstorage.Field {
+	var labels []logstorage.Field
+	for _, c := range columns {
+		if !slices.Contains(byFields, c.Name) {
+			continue
+		}
+		labels = append(labels, logstorage.Field{
+			Name:  strings.Clone(c.Name),
+			Value: strings.Clone(c.Values[rowIdx]),
+		})
+	}
+	return labels
+}
+
+func getByFieldsColumns(byFields []string, columns []logstorage.BlockColumn) []logstorage.BlockColumn {
+	var result []logstorage.BlockColumn
+	for _, c := range columns {
+		if slices.Contains(byFields, c.Name) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
 // ProcessQueryRequest handles /select/logsql/query request.
 //
 // See https://docs.victoriametrics.com/victorialogs/querying/#http-api
@@ -577,6 +835,20 @@ func ProcessQueryRequest(ctx context.Context, w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// Parse optional page_token query arg
+	var pt *pageToken
+	if s := r.FormValue("page_token"); s != "" {
+		if limit <= 0 || !q.CanReturnLastNResults() {
+			httpserver.Errorf(w, r, "page_token query arg can be used only with limit query arg and with the query, which can return the last N results; query=[%s]", q)
+			return
+		}
+		pt, err = parsePageToken(s)
+		if err != nil {
+			httpserver.Errorf(w, r, "%s", err)
+			return
+		}
+	}
+
 	bw := getBufferedWriter(w)
 	defer func() {
 		bw.FlushIgnoreErrors()
@@ -586,11 +858,16 @@ func ProcessQueryRequest(ctx context.Context, w http.ResponseWriter, r *http.Req
 
 	if limit > 0 {
 		if q.CanReturnLastNResults() {
-			rows, err := getLastNQueryResults(ctx, tenantIDs, q, limit)
+			rows, err := getLastNQueryResults(ctx, tenantIDs, q, limit, pt)
 			if err != nil {
 				httpserver.Errorf(w, r, "%s", err)
 				return
 			}
+			if len(rows) >= limit {
+				// There may be more rows before the returned rows. Return the token for the next page.
+				ptNext := newNextPageToken(pt, rows)
+				w.Header().Set("VL-Next-Page-Token", ptNext.String())
+			}
 			bb := blockResultPool.Get()
 			b := bb.B
 			for i := range rows {
@@ -631,13 +908,49 @@ var blockResultPool bytesutil.ByteBufferPool
 type row struct {
 	timestamp int64
 	fields    []logstorage.Field
+
+	// hash is the hash of fields. It is used for stable ordering of rows with identical timestamps.
+	// It is set by getLastNRows.
+	hash uint64
 }
 
-func getLastNQueryResults(ctx context.Context, tenantIDs []logstorage.TenantID, q *logstorage.Query, limit int) ([]row, error) {
+// getLastNQueryResults returns up to limit the most recent rows for q.
+//
+// If pt isn't nil, then only rows before pt are returned.
+func getLastNQueryResults(ctx context.Context, tenantIDs []logstorage.TenantID, q *logstorage.Query, limit int, pt *pageToken) ([]row, error) {
+	if pt == nil {
+		return getLastNQueryResultsInternal(ctx, tenantIDs, q, limit)
+	}
+
+	// Rows with the pt timestamp must be filtered by pt after executing the query, so read them separately.
+	// All these rows must be read, since they are ordered by hash.
+	qEdge := q.Clone()
+	qEdge.AddTimeFilter(pt.timestamp, pt.timestamp)
+	qEdge.Optimize()
+	edgeRows, err := getQueryResultsWithLimit(ctx, tenantIDs, qEdge, math.MaxInt, pt)
+	if err != nil {
+		return nil, err
+	}
+	edgeRows = getLastNRows(edgeRows, limit)
+	if len(edgeRows) >= limit || pt.timestamp == math.MinInt64 {
+		return edgeRows, nil
+	}
+
+	// The remaining rows go before the pt timestamp, so they don't need filtering by pt
+	// and the limit can be pushed down to the query.
+	q.AddTimeFilter(math.MinInt64, pt.timestamp-1)
+	rows, err := getLastNQueryResultsInternal(ctx, tenantIDs, q, limit-len(edgeRows))
+	if err != nil {
+		return nil, err
+	}
+	return append(rows, edgeRows...), nil
+}
+
+func getLastNQueryResultsInternal(ctx context.Context, tenantIDs []logstorage.TenantID, q *logstorage.Query, limit int) ([]row, error) {
 	limitUpper := 2 * limit
 	q.AddPipeLimit(uint64(limitUpper))
 	q.Optimize()
-	rows, err := getQueryResultsWithLimit(ctx, tenantIDs, q, limitUpper)
+	rows, err := getQueryResultsWithLimit(ctx, tenantIDs, q, limitUpper, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -656,7 +969,7 @@ func getLastNQueryResults(ctx context.Context, tenantIDs []logstorage.TenantID,
 	for {
 		q = qOrig.Clone()
 		q.AddTimeFilter(start, end)
-		rows, err := getQueryResultsWithLimit(ctx, tenantIDs, q, limitUpper)
+		rows, err := getQueryResultsWithLimit(ctx, tenantIDs, q, limitUpper, nil)
 		if err != nil {
 			return nil, err
 		}
@@ -677,8 +990,14 @@ func getLastNQueryResults(ctx context.Context, tenantIDs []logstorage.TenantID,
 }
 
 func getLastNRows(rows []row, limit int) []row {
+	for i := range rows {
+		rows[i].hash = getRowHash(rows[i].fields)
+	}
 	sort.Slice(rows, func(i, j int) bool {
-		return rows[i].timestamp < rows[j].timestamp
+		if rows[i].timestamp != rows[j].timestamp {
+			return rows[i].timestamp < rows[j].timestamp
+		}
+		return rows[i].hash < rows[j].hash
 	})
 	if len(rows) > limit {
 		rows = rows[len(rows)-limit:]
@@ -686,12 +1005,16 @@ func getLastNRows(rows []row, limit int) []row {
 	return rows
 }
 
-func getQueryResultsWithLimit(ctx context.Context, tenantIDs []logstorage.TenantID, q *logstorage.Query, limit int) ([]row, error) {
+// getQueryResultsWithLimit returns up to limit rows for q.
+//
+// If pt isn't nil, then only rows before pt are returned. pt.offset rows identical to the row pt points to are skipped.
+func getQueryResultsWithLimit(ctx context.Context, tenantIDs []logstorage.TenantID, q *logstorage.Query, limit int, pt *pageToken) ([]row, error) {
 	ctxWithCancel, cancel := context.WithCancel(ctx)
 	defer cancel()
 
 	var rows []row
 	var rowsLock sync.Mutex
+	skipped := uint64(0)
 	writeBlock := func(_ uint, timestamps []int64, columns []logstorage.BlockColumn) {
 		rowsLock.Lock()
 		defer rowsLock.Unlock()
@@ -703,6 +1026,17 @@ func getQueryResultsWithLimit(ctx context.Context, tenantIDs []logstorage.Tenant
 				f.Name = strings.Clone(columns[j].Name)
 				f.Value = strings.Clone(columns[j].Values[i])
 			}
+			if pt != nil {
+				n := pt.compareRow(timestamp, fields)
+				if n > 0 {
+					continue
+				}
+				if n == 0 && skipped < pt.offset {
+					// Identical rows are indistinguishable, so skip any pt.offset of them.
+					skipped++
+					continue
+				}
+			}
 			rows = append(rows, row{
 				timestamp: timestamp,
 				fields:    fields,
@@ -720,13 +1054,21 @@ func getQueryResultsWithLimit(ctx context.Context, tenantIDs []logstorage.Tenant
 	return rows, nil
 }
 
-func parseCommonArgs(r *http.Request) (*logstorage.Query, []logstorage.TenantID, error) {
-	// Extract tenantID
+func getTenantIDs(r *http.Request) ([]logstorage.TenantID, error) {
 	tenantID, err := logstorage.GetTenantIDFromRequest(r)
 	if err != nil {
-		return nil, nil, fmt.Errorf("cannot obtain tenanID: %w", err)
+		return nil, fmt.Errorf("cannot obtain tenanID: %w", err)
 	}
 	tenantIDs := []logstorage.TenantID{tenantID}
+	return tenantIDs, nil
+}
+
+func parseCommonArgs(r *http.Request) (*logstorage.Query, []logstorage.TenantID, error) {
+	// Extract tenantID
+	tenantIDs, err := getTenantIDs(r)
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// Parse query
 	qStr := r.FormValue("query")
@@ -0,0 +1,116 @@
+package logsql
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+
+	"github.com/cespare/xxhash/v2"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logstorage"
+)
+
+// pageTokenVersion is the version of the page token format.
+//
+// It must be incremented when the format of the page token changes.
+const pageTokenVersion = 2
+
+// pageToken is an opaque continuation token for paging through /select/logsql/query results with the limit query arg.
+//
+// Query results are returned in the order of (timestamp, rowHash). The token points to the oldest row returned at the previous page,
+// so the next page contains up to limit rows, which go before this row. Identical rows have identical (timestamp, rowHash),
+// so the token also contains the number of such rows already returned at the previous pages. This allows paging through millions of results
+// without re-scanning the already returned rows, since the next page is searched only on the time range ending at the token timestamp.
+type pageToken struct {
+	// timestamp is the timestamp of the oldest row returned at the previous page
+	timestamp int64
+
+	// rowHash is the hash of the oldest row returned at the previous page. See getRowHash
+	rowHash uint64
+
+	// offset is the number of already returned rows with the given timestamp and rowHash
+	offset uint64
+}
+
+// String returns string representation for pt, which can be parsed with parsePageToken.
+func (pt *pageToken) String() string {
+	b := []byte{pageTokenVersion}
+	b = encoding.MarshalInt64(b, pt.timestamp)
+	b = encoding.MarshalUint64(b, pt.rowHash)
+	b = encoding.MarshalUint64(b, pt.offset)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func parsePageToken(s string) (*pageToken, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode page token %q: %w", s, err)
+	}
+	if len(b) != 1+8+8+8 {
+		return nil, fmt.Errorf("unexpected page token length; got %d bytes; want %d bytes", len(b), 1+8+8+8)
+	}
+	if b[0] != pageTokenVersion {
+		return nil, fmt.Errorf("unsupported page token version; got %d; want %d", b[0], pageTokenVersion)
+	}
+	b = b[1:]
+	pt := &pageToken{
+		timestamp: encoding.UnmarshalInt64(b),
+		rowHash:   encoding.UnmarshalUint64(b[8:]),
+		offset:    encoding.UnmarshalUint64(b[16:]),
+	}
+	return pt, nil
+}
+
+// compareRow returns -1 if the row with the given timestamp and fields goes before pt, 1 if it goes after pt
+// and 0 if it is identical to the row pt points to.
+func (pt *pageToken) compareRow(timestamp int64, fields []logstorage.Field) int {
+	if timestamp != pt.timestamp {
+		if timestamp < pt.timestamp {
+			return -1
+		}
+		return 1
+	}
+	h := getRowHash(fields)
+	switch {
+	case h < pt.rowHash:
+		return -1
+	case h > pt.rowHash:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// newNextPageToken returns the token for the page following the rows returned for the page with the token pt.
+//
+// rows must be sorted in the order of (timestamp, hash). pt may be nil for the first page.
+func newNextPageToken(pt *pageToken, rows []row) *pageToken {
+	r := &rows[0]
+	ptNext := &pageToken{
+		timestamp: r.timestamp,
+		rowHash:   r.hash,
+	}
+	for i := range rows {
+		if rows[i].timestamp != r.timestamp || rows[i].hash != r.hash {
+			break
+		}
+		ptNext.offset++
+	}
+	if pt != nil && pt.timestamp == ptNext.timestamp && pt.rowHash == ptNext.rowHash {
+		ptNext.offset += pt.offset
+	}
+	return ptNext
+}
+
+// getRowHash returns hash for the row with the given fields.
+//
+// The hash doesn't depend on the order of fields.
+func getRowHash(fields []logstorage.Field) uint64 {
+	fieldsSorted := append([]logstorage.Field{}, fields...)
+	sort.Slice(fieldsSorted, func(i, j int) bool {
+		return fieldsSorted[i].Name < fieldsSorted[j].Name
+	})
+	b := logstorage.MarshalFieldsToJSON(nil, fieldsSorted)
+	return xxhash.Sum64(b)
+}
@@ -0,0 +1,142 @@
+package logsql
+
+import (
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logstorage"
+)
+
+func TestPageTokenMarshalUnmarshal(t *testing.T) {
+	f := func(timestamp int64, rowHash, offset uint64) {
+		t.Helper()
+
+		pt := &pageToken{
+			timestamp: timestamp,
+			rowHash:   rowHash,
+			offset:    offset,
+		}
+		s := pt.String()
+		ptParsed, err := parsePageToken(s)
+		if err != nil {
+			t.Fatalf("cannot parse page token %q: %s", s, err)
+		}
+		if *ptParsed != *pt {
+			t.Fatalf("unexpected page token parsed from %q; got %+v; want %+v", s, ptParsed, pt)
+		}
+	}
+
+	f(0, 0, 0)
+	f(1, 2, 3)
+	f(-1234567890, 1<<63, 1)
+	f(1728986400123456789, 0xffffffffffffffff, 100)
+}
+
+func TestParsePageTokenFailure(t *testing.T) {
+	f := func(s string) {
+		t.Helper()
+
+		pt, err := parsePageToken(s)
+		if err == nil {
+			t.Fatalf("expecting non-nil error when parsing %q; got %+v", s, pt)
+		}
+	}
+
+	f("")
+	f("foo")
+	f("!@#$")
+
+	// unsupported version
+	f("AQECAwQFBgcICQoLDA0ODxAREhMUFRYXGA")
+
+	// invalid length
+	f("AgECAwQFBgcICQoLDA0ODxAREhMU")
+	f("AgECAwQFBgcICQoLDA0ODxAREhMUFRYXGBka")
+}
+
+func TestGetRowHash(t *testing.T) {
+	fields1 := []logstorage.Field{
+		{Name: "_msg", Value: "foo"},
+		{Name: "level", Value: "error"},
+	}
+	fields2 := []logstorage.Field{
+		{Name: "level", Value: "error"},
+		{Name: "_msg", Value: "foo"},
+	}
+	fields3 := []logstorage.Field{
+		{Name: "level", Value: "info"},
+		{Name: "_msg", Value: "foo"},
+	}
+
+	h1 := getRowHash(fields1)
+	h2 := getRowHash(fields2)
+	h3 := getRowHash(fields3)
+	if h1 != h2 {
+		t.Fatalf("the hash mustn't depend on the order of fields; got %d and %d", h1, h2)
+	}
+	if h1 == h3 {
+		t.Fatalf("the hash must differ for distinct rows")
+	}
+	if fields2[0].Name != "level" {
+		t.Fatalf("getRowHash mustn't modify the original fields")
+	}
+}
+
+func TestPageTokenCompareRow(t *testing.T) {
+	fields := []logstorage.Field{
+		{Name: "_msg", Value: "foo"},
+	}
+	h := getRowHash(fields)
+	pt := &pageToken{
+		timestamp: 10,
+		rowHash:   h,
+	}
+
+	f := func(timestamp int64, fields []logstorage.Field, resultExpected int) {
+		t.Helper()
+
+		result := pt.compareRow(timestamp, fields)
+		if result != resultExpected {
+			t.Fatalf("unexpected result for timestamp=%d, fields=%v; got %d; want %d", timestamp, fields, result, resultExpected)
+		}
+	}
+
+	f(9, fields, -1)
+	f(11, fields, 1)
+	f(10, fields, 0)
+
+	otherFields := []logstorage.Field{
+		{Name: "_msg", Value: "bar"},
+	}
+	if getRowHash(otherFields) < h {
+		f(10, otherFields, -1)
+	} else {
+		f(10, otherFields, 1)
+	}
+}
+
+func TestNewNextPageToken(t *testing.T) {
+	f := func(pt *pageToken, rows []row, ptExpected *pageToken) {
+		t.Helper()
+
+		ptNext := newNextPageToken(pt, rows)
+		if *ptNext != *ptExpected {
+			t.Fatalf("unexpected next page token; got %+v; want %+v", ptNext, ptExpected)
+		}
+	}
+
+	rows := []row{
+		{timestamp: 1, hash: 5},
+		{timestamp: 1, hash: 5},
+		{timestamp: 1, hash: 6},
+		{timestamp: 2, hash: 1},
+	}
+
+	// the first page
+	f(nil, rows, &pageToken{timestamp: 1, rowHash: 5, offset: 2})
+
+	// the previous page ended at another row
+	f(&pageToken{timestamp: 3, rowHash: 5, offset: 1}, rows, &pageToken{timestamp: 1, rowHash: 5, offset: 2})
+
+	// the previous page ended at the identical row
+	f(&pageToken{timestamp: 1, rowHash: 5, offset: 3}, rows[:2], &pageToken{timestamp: 1, rowHash: 5, offset: 5})
+}
@@ -0,0 +1,166 @@
+package loki
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// convertLogQLToLogsQL converts LogQL log query s to LogsQL query.
+//
+// Only stream selector and line filters are supported, since this is enough for the majority of log panels in Grafana.
+// See https://grafana.com/docs/loki/latest/query/log_queries/
+func convertLogQLToLogsQL(s string) (string, error) {
+	streamFilter, tail, err := parseLogQLStreamSelector(s)
+	if err != nil {
+		return "", err
+	}
+
+	filters := []string{streamFilter}
+	for {
+		tail = skipSpaces(tail)
+		if tail == "" {
+			return strings.Join(filters, " "), nil
+		}
+		var filter string
+		filter, tail, err = parseLogQLLineFilter(tail)
+		if err != nil {
+			return "", err
+		}
+		if filter != "" {
+			filters = append(filters, filter)
+		}
+	}
+}
+
+// parseLogQLStreamSelector parses LogQL stream selector at the beginning of s.
+//
+// It returns LogsQL stream filter for the parsed stream selector and the tail after the stream selector.
+func parseLogQLStreamSelector(s string) (string, string, error) {
+	s = skipSpaces(s)
+	if !strings.HasPrefix(s, "{") {
+		return "", s, fmt.Errorf("missing stream selector at the beginning of the query; only log queries are supported")
+	}
+	s = s[1:]
+
+	var matchers []string
+	for {
+		s = skipSpaces(s)
+		if strings.HasPrefix(s, "}") {
+			s = s[1:]
+			break
+		}
+		if len(matchers) > 0 {
+			if !strings.HasPrefix(s, ",") {
+				return "", s, fmt.Errorf("missing ',' after %s in stream selector", matchers[len(matchers)-1])
+			}
+			s = skipSpaces(s[1:])
+		}
+
+		n := 0
+		for n < len(s) && isLabelNameChar(s[n]) {
+			n++
+		}
+		if n == 0 {
+			return "", s, fmt.Errorf("missing label name in stream selector at [%s]", s)
+		}
+		name := s[:n]
+		s = skipSpaces(s[n:])
+
+		op := ""
+		for _, opCandidate := range []string{"=~", "!~", "!=", "="} {
+			if strings.HasPrefix(s, opCandidate) {
+				op = opCandidate
+				break
+			}
+		}
+		if op == "" {
+			return "", s, fmt.Errorf("missing label matcher operation after %q in stream selector; supported operations: =, !=, =~, !~", name)
+		}
+		s = skipSpaces(s[len(op):])
+
+		value, tail, err := parseLogQLString(s)
+		if err != nil {
+			return "", s, fmt.Errorf("cannot parse value for %s%s matcher in stream selector: %w", name, op, err)
+		}
+		s = tail
+
+		matchers = append(matchers, name+op+strconv.Quote(value))
+	}
+	if len(matchers) == 0 {
+		return "", s, fmt.Errorf("stream selector must contain at least one label matcher")
+	}
+	return "_stream:{" + strings.Join(matchers, ",") + "}", s, nil
+}
+
+// parseLogQLLineFilter parses LogQL line filter at the beginning of s.
+//
+// It returns LogsQL filter for the parsed line filter and the tail after the line filter.
+// The returned filter is empty if the line filter matches all the log lines.
+func parseLogQLLineFilter(s string) (string, string, error) {
+	op := ""
+	for _, opCandidate := range []string{"|=", "!=", "|~", "!~"} {
+		if strings.HasPrefix(s, opCandidate) {
+			op = opCandidate
+			break
+		}
+	}
+	if op == "" {
+		if strings.HasPrefix(s, "|") {
+			return "", s, fmt.Errorf("unsupported pipeline stage at [%s]; only line filters are supported: |=, !=, |~, !~", s)
+		}
+		return "", s, fmt.Errorf("unexpected tail [%s]; expecting line filter: |=, !=, |~, !~", s)
+	}
+
+	value, tail, err := parseLogQLString(skipSpaces(s[len(op):]))
+	if err != nil {
+		return "", s, fmt.Errorf("cannot parse value for %q line filter: %w", op, err)
+	}
+
+	switch op {
+	case "|=":
+		if value == "" {
+			return "", tail, nil
+		}
+		return "_msg:~" + strconv.Quote(regexp.QuoteMeta(value)), tail, nil
+	case "!=":
+		return "!_msg:~" + strconv.Quote(regexp.QuoteMeta(value)), tail, nil
+	case "|~":
+		if _, err := regexp.Compile(value); err != nil {
+			return "", s, fmt.Errorf("invalid regexp %q for %q line filter: %w", value, op, err)
+		}
+		return "_msg:~" + strconv.Quote(value), tail, nil
+	default:
+		if _, err := regexp.Compile(value); err != nil {
+			return "", s, fmt.Errorf("invalid regexp %q for %q line filter: %w", value, op, err)
+		}
+		return "!_msg:~" + strconv.Quote(value), tail, nil
+	}
+}
+
+// parseLogQLString parses double-quoted or backtick-quoted string at the beginning of s.
+//
+// It returns the unquoted string and the tail after the string.
+func parseLogQLString(s string) (string, string, error) {
+	if len(s) == 0 || (s[0] != '"' && s[0] != '`') {
+		return "", s, fmt.Errorf("missing quoted string at [%s]", s)
+	}
+	qPrefix, err := strconv.QuotedPrefix(s)
+	if err != nil {
+		return "", s, fmt.Errorf("cannot find the end of quoted string at [%s]", s)
+	}
+	value, err := strconv.Unquote(qPrefix)
+	if err != nil {
+		return "", s, fmt.Errorf("cannot unquote %s: %w", qPrefix, err)
+	}
+	return value, s[len(qPrefix):], nil
+}
+
+func isLabelNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_'
+}
+
+func skipSpaces(s string) string {
+	return strings.TrimLeft(s, " \t\r\n")
+}
@@ -0,0 +1,83 @@
+package loki
+
+import (
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logstorage"
+)
+
+func TestConvertLogQLToLogsQL_Success(t *testing.T) {
+	f := func(s, resultExpected string) {
+		t.Helper()
+
+		result, err := convertLogQLToLogsQL(s)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if result != resultExpected {
+			t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, resultExpected)
+		}
+		if _, err := logstorage.ParseQuery(result); err != nil {
+			t.Fatalf("cannot parse the resulting LogsQL query [%s]: %s", result, err)
+		}
+	}
+
+	// stream selector
+	f(`{app="nginx"}`, `_stream:{app="nginx"}`)
+	f(` { app = "nginx" , env!="dev",host=~"h.+", job !~ `+"`a\\d`"+` } `, `_stream:{app="nginx",env!="dev",host=~"h.+",job!~"a\\d"}`)
+	f(`{app="a\"b"}`, `_stream:{app="a\"b"}`)
+
+	// line filters
+	f(`{app="nginx"} |= "error"`, `_stream:{app="nginx"} _msg:~"error"`)
+	f(`{app="nginx"} |= "GET /foo.bar"`, `_stream:{app="nginx"} _msg:~"GET /foo\\.bar"`)
+	f(`{app="nginx"} != "debug"`, `_stream:{app="nginx"} !_msg:~"debug"`)
+	f(`{app="nginx"} |~ "err(or)?"`, `_stream:{app="nginx"} _msg:~"err(or)?"`)
+	f(`{app="nginx"} !~ `+"`\\d+ms`", `_stream:{app="nginx"} !_msg:~"\\d+ms"`)
+	f(`{app="nginx"}|="error"!="timeout" |~"foo"`, `_stream:{app="nginx"} _msg:~"error" !_msg:~"timeout" _msg:~"foo"`)
+
+	// empty line filter matches all the lines
+	f(`{app="nginx"} |= ""`, `_stream:{app="nginx"}`)
+}
+
+func TestConvertLogQLToLogsQL_Failure(t *testing.T) {
+	f := func(s string) {
+		t.Helper()
+
+		result, err := convertLogQLToLogsQL(s)
+		if err == nil {
+			t.Fatalf("expecting non-nil error; got %s", result)
+		}
+	}
+
+	// missing stream selector
+	f(``)
+	f(`error`)
+	f(`|= "error"`)
+
+	// metric queries
+	f(`count_over_time({app="nginx"}[5m])`)
+	f(`sum by (level) (rate({app="nginx"}[1m]))`)
+
+	// invalid stream selector
+	f(`{}`)
+	f(`{app}`)
+	f(`{app="nginx"`)
+	f(`{app="nginx" env="dev"}`)
+	f(`{app=nginx}`)
+	f(`{app>"nginx"}`)
+	f(`{="nginx"}`)
+	f(`{app='nginx'}`)
+	f(`{app="nginx}`)
+
+	// invalid line filters
+	f(`{app="nginx"} error`)
+	f(`{app="nginx"} |= error`)
+	f(`{app="nginx"} |~ "("`)
+	f(`{app="nginx"} !~ "["`)
+	f(`{app="nginx"} |= "a" or "b"`)
+
+	// unsupported pipeline stages
+	f(`{app="nginx"} | json`)
+	f(`{app="nginx"} |= "error" | logfmt | level="error"`)
+	f(`{app="nginx"} | line_format "{{.msg}}"`)
+}
@@ -0,0 +1,291 @@
+package loki
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vlstorage"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/httpserver"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/httputils"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logstorage"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promutils"
+)
+
+// ProcessLabelsRequest handles /select/loki/api/v1/labels request.
+//
+// It returns log stream field names in the format compatible with Loki labels API.
+//
+// See https://docs.victoriametrics.com/victorialogs/querying/#grafana-loki-datasource
+func ProcessLabelsRequest(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	q, tenantIDs, err := parseLabelsArgs(r)
+	if err != nil {
+		httpserver.Errorf(w, r, "%s", err)
+		return
+	}
+
+	q.Optimize()
+	names, err := vlstorage.GetStreamFieldNames(ctx, tenantIDs, q)
+	if err != nil {
+		httpserver.Errorf(w, r, "cannot obtain labels: %s", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	WriteLabelsResponse(w, getSortedValues(names))
+}
+
+// ProcessLabelValuesRequest handles /select/loki/api/v1/label/<labelName>/values request.
+//
+// It returns log stream field values for the given labelName in the format compatible with Loki label values API.
+//
+// See https://docs.victoriametrics.com/victorialogs/querying/#grafana-loki-datasource
+func ProcessLabelValuesRequest(ctx context.Context, w http.ResponseWriter, r *http.Request, labelName string) {
+	q, tenantIDs, err := parseLabelsArgs(r)
+	if err != nil {
+		httpserver.Errorf(w, r, "%s", err)
+		return
+	}
+
+	q.Optimize()
+	values, err := vlstorage.GetStreamFieldValues(ctx, tenantIDs, q, labelName, 0)
+	if err != nil {
+		httpserver.Errorf(w, r, "cannot obtain values for label %q: %s", labelName, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	WriteLabelsResponse(w, getSortedValues(values))
+}
+
+// ProcessQueryRangeRequest handles /select/loki/api/v1/query_range request.
+//
+// It executes LogQL log query and returns the matching log lines grouped by log streams
+// in the format compatible with Loki query_range API.
+//
+// See https://docs.victoriametrics.com/victorialogs/querying/#grafana-loki-datasource
+func ProcessQueryRangeRequest(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	tenantIDs, err := getTenantIDs(r)
+	if err != nil {
+		httpserver.Errorf(w, r, "%s", err)
+		return
+	}
+
+	// Parse time range. The defaults match Loki defaults.
+	end, err := getTimeNsec(r, "end", time.Now().UnixNano())
+	if err != nil {
+		httpserver.Errorf(w, r, "%s", err)
+		return
+	}
+	start, err := getTimeNsec(r, "start", end-time.Hour.Nanoseconds())
+	if err != nil {
+		httpserver.Errorf(w, r, "%s", err)
+		return
+	}
+
+	// Parse limit and direction
+	limit, err := httputils.GetInt(r, "limit")
+	if err != nil {
+		httpserver.Errorf(w, r, "%s", err)
+		return
+	}
+	if limit <= 0 {
+		limit = 100
+	}
+	isForward := false
+	switch direction := r.FormValue("direction"); direction {
+	case "", "backward", "BACKWARD":
+	case "forward", "FORWARD":
+		isForward = true
+	default:
+		httpserver.Errorf(w, r, "unsupported direction=%q; supported values: forward, backward", direction)
+		return
+	}
+
+	// Parse query
+	logqlStr := r.FormValue("query")
+	qStr, err := convertLogQLToLogsQL(logqlStr)
+	if err != nil {
+		httpserver.Errorf(w, r, "cannot parse query [%s]: %s", logqlStr, err)
+		return
+	}
+	sortOrder := " desc"
+	if isForward {
+		sortOrder = ""
+	}
+	qStr += fmt.Sprintf(" | fields _time, _stream, _msg | sort by (_time)%s limit %d", sortOrder, limit)
+	q, err := logstorage.ParseQueryAtTimestamp(qStr, end)
+	if err != nil {
+		httpserver.Errorf(w, r, "cannot parse query [%s]: %s", qStr, err)
+		return
+	}
+	q.AddTimeFilter(start, end)
+	q.Optimize()
+
+	m := make(map[string]*lokiStream)
+	var mLock sync.Mutex
+	writeBlock := func(_ uint, timestamps []int64, columns []logstorage.BlockColumn) {
+		var timeValues, streamValues, msgValues []string
+		for _, c := range columns {
+			switch c.Name {
+			case "_time":
+				timeValues = c.Values
+			case "_stream":
+				streamValues = c.Values
+			case "_msg":
+				msgValues = c.Values
+			}
+		}
+
+		mLock.Lock()
+		defer mLock.Unlock()
+
+		for i := range timestamps {
+			timestamp, ok := int64(0), false
+			if timeValues != nil {
+				timestamp, ok = logstorage.TryParseTimestampRFC3339Nano(timeValues[i])
+			}
+			if !ok {
+				continue
+			}
+			streamStr := ""
+			if streamValues != nil {
+				streamStr = streamValues[i]
+			}
+			line := ""
+			if msgValues != nil {
+				line = strings.Clone(msgValues[i])
+			}
+
+			ls, ok := m[streamStr]
+			if !ok {
+				ls = &lokiStream{}
+				streamStr = strings.Clone(streamStr)
+				ls.labels, _ = logstorage.ParseStreamFields(nil, streamStr)
+				m[streamStr] = ls
+			}
+			ls.entries = append(ls.entries, lokiEntry{
+				timestamp: timestamp,
+				line:      line,
+			})
+		}
+	}
+
+	if err := vlstorage.RunQuery(ctx, tenantIDs, q, writeBlock); err != nil {
+		httpserver.Errorf(w, r, "cannot execute query [%s]: %s", q, err)
+		return
+	}
+
+	// Sort streams by their names and log entries according to the requested direction.
+	streamStrs := make([]string, 0, len(m))
+	for streamStr := range m {
+		streamStrs = append(streamStrs, streamStr)
+	}
+	sort.Strings(streamStrs)
+	streams := make([]*lokiStream, len(streamStrs))
+	for i, streamStr := range streamStrs {
+		ls := m[streamStr]
+		entries := ls.entries
+		sort.SliceStable(entries, func(i, j int) bool {
+			if isForward {
+				return entries[i].timestamp < entries[j].timestamp
+			}
+			return entries[i].timestamp > entries[j].timestamp
+		})
+		streams[i] = ls
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	WriteQueryRangeResponse(w, streams)
+}
+
+type lokiStream struct {
+	labels  []logstorage.Field
+	entries []lokiEntry
+}
+
+type lokiEntry struct {
+	timestamp int64
+	line      string
+}
+
+// parseLabelsArgs parses common args for labels and label values requests.
+//
+// The optional query arg must contain LogQL stream selector. The time range defaults to the last 6 hours like in Loki.
+func parseLabelsArgs(r *http.Request) (*logstorage.Query, []logstorage.TenantID, error) {
+	tenantIDs, err := getTenantIDs(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	end, err := getTimeNsec(r, "end", time.Now().UnixNano())
+	if err != nil {
+		return nil, nil, err
+	}
+	start, err := getTimeNsec(r, "start", end-6*time.Hour.Nanoseconds())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	qStr := "*"
+	if logqlStr := r.FormValue("query"); logqlStr != "" {
+		qStr, err = convertLogQLToLogsQL(logqlStr)
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot parse query [%s]: %w", logqlStr, err)
+		}
+	}
+	q, err := logstorage.ParseQueryAtTimestamp(qStr, end)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot parse query [%s]: %w", qStr, err)
+	}
+	q.AddTimeFilter(start, end)
+
+	return q, tenantIDs, nil
+}
+
+func getSortedValues(vhs []logstorage.ValueWithHits) []string {
+	a := make([]string, 0, len(vhs))
+	for _, vh := range vhs {
+		a = append(a, vh.Value)
+	}
+	sort.Strings(a)
+	return a
+}
+
+func getTenantIDs(r *http.Request) ([]logstorage.TenantID, error) {
+	tenantID, err := logstorage.GetTenantIDFromRequest(r)
+	if err != nil {
+		return nil, fmt.Errorf("cannot obtain tenantID: %w", err)
+	}
+	tenantIDs := []logstorage.TenantID{tenantID}
+	return tenantIDs, nil
+}
+
+// getTimeNsec returns the timestamp in nanoseconds from the given argName query arg.
+//
+// It returns defaultValue if argName query arg is missing.
+//
+// Integer values with more than 10 digits are treated as Unix timestamps in nanoseconds like Loki does,
+// since Grafana passes timestamps in nanoseconds to Loki datasource.
+// Other values are parsed in any format supported by VictoriaMetrics. See https://docs.victoriametrics.com/#timestamp-formats
+func getTimeNsec(r *http.Request, argName string, defaultValue int64) (int64, error) {
+	s := r.FormValue(argName)
+	if s == "" {
+		return defaultValue, nil
+	}
+	if len(s) > 10 {
+		if nsecs, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return nsecs, nil
+		}
+	}
+	nsecs, err := promutils.ParseTimeAt(s, time.Now().UnixNano())
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse %s=%s: %w", argName, s, err)
+	}
+	return nsecs, nil
+}
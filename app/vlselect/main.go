@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vlselect/logsql"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vlselect/loki"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/cgroup"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/httpserver"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/httputils"
@@ -193,6 +194,14 @@ func processSelectRequest(ctx context.Context, w http.ResponseWriter, r *http.Re
 		logsqlQueryRequests.Inc()
 		logsql.ProcessQueryRequest(ctx, w, r)
 		return true
+	case "/select/logsql/stats_query":
+		logsqlStatsQueryRequests.Inc()
+		logsql.ProcessStatsQueryRequest(ctx, w, r)
+		return true
+	case "/select/logsql/stats_query_range":
+		logsqlStatsQueryRangeRequests.Inc()
+		logsql.ProcessStatsQueryRangeRequest(ctx, w, r)
+		return true
 	case "/select/logsql/stream_field_names":
 		logsqlStreamFieldNamesRequests.Inc()
 		logsql.ProcessStreamFieldNamesRequest(ctx, w, r)
@@ -209,9 +218,26 @@ func processSelectRequest(ctx context.Context, w http.ResponseWriter, r *http.Re
 		logsqlStreamsRequests.Inc()
 		logsql.ProcessStreamsRequest(ctx, w, r)
 		return true
-	default:
-		return false
+	case "/select/loki/api/v1/labels":
+		lokiLabelsRequests.Inc()
+		loki.ProcessLabelsRequest(ctx, w, r)
+		return true
+	case "/select/loki/api/v1/query_range":
+		lokiQueryRangeRequests.Inc()
+		loki.ProcessQueryRangeRequest(ctx, w, r)
+		return true
 	}
+
+	if strings.HasPrefix(path, "/select/loki/api/v1/label/") {
+		s := path[len("/select/loki/api/v1/label/"):]
+		if strings.HasSuffix(s, "/values") {
+			lokiLabelValuesRequests.Inc()
+			labelName := s[:len(s)-len("/values")]
+			loki.ProcessLabelValuesRequest(ctx, w, r, labelName)
+			return true
+		}
+	}
+	return false
 }
 
 // getMaxQueryDuration returns the maximum duration for query from r.
@@ -232,9 +258,15 @@ var (
 	logsqlFieldValuesRequests       = metrics.NewCounter(`vl_http_requests_total{path="/select/logsql/field_values"}`)
 	logsqlHitsRequests              = metrics.NewCounter(`vl_http_requests_total{path="/select/logsql/hits"}`)
 	logsqlQueryRequests             = metrics.NewCounter(`vl_http_requests_total{path="/select/logsql/query"}`)
+	logsqlStatsQueryRequests        = metrics.NewCounter(`vl_http_requests_total{path="/select/logsql/stats_query"}`)
+	logsqlStatsQueryRangeRequests   = metrics.NewCounter(`vl_http_requests_total{path="/select/logsql/stats_query_range"}`)
 	logsqlStreamFieldNamesRequests  = metrics.NewCounter(`vl_http_requests_total{path="/select/logsql/stream_field_names"}`)
 	logsqlStreamFieldValuesRequests = metrics.NewCounter(`vl_http_requests_total{path="/select/logsql/stream_field_values"}`)
 	logsqlStreamIDsRequests         = metrics.NewCounter(`vl_http_requests_total{path="/select/logsql/stream_ids"}`)
 	logsqlStreamsRequests           = metrics.NewCounter(`vl_http_requests_total{path="/select/logsql/streams"}`)
 	logsqlTailRequests              = metrics.NewCounter(`vl_http_requests_total{path="/select/logsql/tail"}`)
+
+	lokiLabelsRequests      = metrics.NewCounter(`vl_http_requests_total{path="/select/loki/api/v1/labels"}`)
+	lokiLabelValuesRequests = metrics.NewCounter(`vl_http_requests_total{path="/select/loki/api/v1/label/{}/values"}`)
+	lokiQueryRangeRequests  = metrics.NewCounter(`vl_http_requests_total{path="/select/loki/api/v1/query_range"}`)
 )
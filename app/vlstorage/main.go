@@ -23,6 +23,9 @@ var (
 		"see https://docs.victoriametrics.com/victorialogs/#retention ; see also -retention.maxDiskSpaceUsageBytes")
 	maxDiskSpaceUsageBytes = flagutil.NewBytes("retention.maxDiskSpaceUsageBytes", 0, "The maximum disk space usage at -storageDataPath before older per-day "+
 		"partitions are automatically dropped; see https://docs.victoriametrics.com/victorialogs/#retention-by-disk-space-usage ; see also -retentionPeriod")
+	retentionMinFreeDiskSpaceBytes = flagutil.NewBytes("retention.minFreeDiskSpaceBytes", 0, "The minimum free disk space at -storageDataPath before older per-day "+
+		"partitions are automatically dropped; it should be bigger than -storage.minFreeDiskSpaceBytes in order to prevent from switching to read-only mode; "+
+		"see https://docs.victoriametrics.com/victorialogs/#retention-by-free-disk-space ; see also -retention.maxDiskSpaceUsageBytes")
 	futureRetention = flagutil.NewDuration("futureRetention", "2d", "Log entries with timestamps bigger than now+futureRetention are rejected during data ingestion; "+
 		"see https://docs.victoriametrics.com/victorialogs/#retention")
 	storageDataPath = flag.String("storageDataPath", "victoria-logs-data", "Path to directory where to store VictoriaLogs data; "+
@@ -36,7 +39,11 @@ var (
 	logIngestedRows = flag.Bool("logIngestedRows", false, "Whether to log all the ingested log entries; this can be useful for debugging of data ingestion; "+
 		"see https://docs.victoriametrics.com/victorialogs/data-ingestion/ ; see also -logNewStreams")
 	minFreeDiskSpaceBytes = flagutil.NewBytes("storage.minFreeDiskSpaceBytes", 10e6, "The minimum free disk space at -storageDataPath after which "+
-		"the storage stops accepting new data")
+		"the storage stops accepting new data; see also -retention.minFreeDiskSpaceBytes")
+	geoipDatabasePath = flagutil.NewArrayString("geoip.databasePath", "Optional path to database in MaxMind DB format such as GeoLite2-City.mmdb or GeoLite2-ASN.mmdb; "+
+		"the database is used by geoip pipe for resolving IP addresses at query time; see https://docs.victoriametrics.com/victorialogs/logsql/#geoip-pipe")
+	indexedFields = flagutil.NewArrayString("indexedFields", "Optional list of log fields to index for fast 'field:=value' lookups such as trace_id or user_id; "+
+		"see https://docs.victoriametrics.com/victorialogs/#indexed-fields")
 )
 
 // Init initializes vlstorage.
@@ -51,13 +58,16 @@ func Init() {
 		logger.Fatalf("-retentionPeriod cannot be smaller than a day; got %s", retentionPeriod)
 	}
 	cfg := &logstorage.StorageConfig{
-		Retention:              retentionPeriod.Duration(),
-		MaxDiskSpaceUsageBytes: maxDiskSpaceUsageBytes.N,
-		FlushInterval:          *inmemoryDataFlushInterval,
-		FutureRetention:        futureRetention.Duration(),
-		LogNewStreams:          *logNewStreams,
-		LogIngestedRows:        *logIngestedRows,
-		MinFreeDiskSpaceBytes:  minFreeDiskSpaceBytes.N,
+		Retention:                      retentionPeriod.Duration(),
+		MaxDiskSpaceUsageBytes:         maxDiskSpaceUsageBytes.N,
+		FlushInterval:                  *inmemoryDataFlushInterval,
+		FutureRetention:                futureRetention.Duration(),
+		LogNewStreams:                  *logNewStreams,
+		LogIngestedRows:                *logIngestedRows,
+		MinFreeDiskSpaceBytes:          minFreeDiskSpaceBytes.N,
+		RetentionMinFreeDiskSpaceBytes: retentionMinFreeDiskSpaceBytes.N,
+		GeoIPDatabasePaths:             *geoipDatabasePath,
+		IndexedFields:                  *indexedFields,
 	}
 	logger.Infof("opening storage at -storageDataPath=%s", *storageDataPath)
 	startTime := time.Now()
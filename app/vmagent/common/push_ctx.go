@@ -1,10 +1,16 @@
 package common
 
 import (
+	"math"
 	"sync"
+	"time"
 
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/auth"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promrelabel"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/tenantmetrics"
 )
 
 // PushCtx is a context used for populating WriteRequest.
@@ -31,6 +37,29 @@ func (ctx *PushCtx) Reset() {
 	ctx.Samples = ctx.Samples[:0]
 }
 
+// UpdateIngestionLag updates h with the lag in seconds between the current time and the newest sample in ctx.WriteRequest.
+//
+// The per-tenant histogram from hm is updated too if at isn't nil.
+func (ctx *PushCtx) UpdateIngestionLag(at *auth.Token, h *metrics.Histogram, hm *tenantmetrics.HistogramMap) {
+	maxTimestamp := int64(math.MinInt64)
+	tss := ctx.WriteRequest.Timeseries
+	for i := range tss {
+		samples := tss[i].Samples
+		for j := range samples {
+			maxTimestamp = max(maxTimestamp, samples[j].Timestamp)
+		}
+	}
+	if maxTimestamp == math.MinInt64 {
+		// There are no samples
+		return
+	}
+	lag := max(float64(time.Now().UnixMilli()-maxTimestamp)/1e3, 0)
+	h.Update(lag)
+	if at != nil {
+		hm.Get(at).Update(lag)
+	}
+}
+
 // GetPushCtx returns PushCtx from pool.
 //
 // Call PutPushCtx when the ctx is no longer needed.
@@ -18,6 +18,8 @@ var (
 	rowsInserted       = metrics.NewCounter(`vmagent_rows_inserted_total{type="csvimport"}`)
 	rowsTenantInserted = tenantmetrics.NewCounterMap(`vmagent_tenant_inserted_rows_total{type="csvimport"}`)
 	rowsPerInsert      = metrics.NewHistogram(`vmagent_rows_per_insert{type="csvimport"}`)
+	ingestionLag       = metrics.NewHistogram(`vmagent_ingestion_lag_seconds{type="csvimport"}`)
+	tenantIngestionLag = tenantmetrics.NewHistogramMap(`vmagent_tenant_ingestion_lag_seconds{type="csvimport"}`)
 )
 
 // InsertHandler processes csv data from req.
@@ -68,6 +70,7 @@ func insertRows(at *auth.Token, rows []parser.Row, extraLabels []prompbmarshal.L
 	if !remotewrite.TryPush(at, &ctx.WriteRequest) {
 		return remotewrite.ErrQueueFullHTTPRetry
 	}
+	ctx.UpdateIngestionLag(at, ingestionLag, tenantIngestionLag)
 	rowsInserted.Add(len(rows))
 	if at != nil {
 		rowsTenantInserted.Get(at).Add(len(rows))
@@ -19,6 +19,8 @@ var (
 	rowsInserted       = metrics.NewCounter(`vmagent_rows_inserted_total{type="datadogsketches"}`)
 	rowsTenantInserted = tenantmetrics.NewCounterMap(`vmagent_tenant_inserted_rows_total{type="datadogsketches"}`)
 	rowsPerInsert      = metrics.NewHistogram(`vmagent_rows_per_insert{type="datadogsketches"}`)
+	ingestionLag       = metrics.NewHistogram(`vmagent_ingestion_lag_seconds{type="datadogsketches"}`)
+	tenantIngestionLag = tenantmetrics.NewHistogramMap(`vmagent_tenant_ingestion_lag_seconds{type="datadogsketches"}`)
 )
 
 // InsertHandlerForHTTP processes remote write for DataDog POST /api/beta/sketches request.
@@ -42,7 +44,7 @@ func insertRows(at *auth.Token, sketches []*datadogsketches.Sketch, extraLabels
 	labels := ctx.Labels[:0]
 	samples := ctx.Samples[:0]
 	for _, sketch := range sketches {
-		ms := sketch.ToSummary()
+		ms := sketch.ToMetrics()
 		for _, m := range ms {
 			labelsLen := len(labels)
 			labels = append(labels, prompbmarshal.Label{
@@ -86,6 +88,7 @@ func insertRows(at *auth.Token, sketches []*datadogsketches.Sketch, extraLabels
 	if !remotewrite.TryPush(at, &ctx.WriteRequest) {
 		return remotewrite.ErrQueueFullHTTPRetry
 	}
+	ctx.UpdateIngestionLag(at, ingestionLag, tenantIngestionLag)
 	rowsInserted.Add(rowsTotal)
 	if at != nil {
 		rowsTenantInserted.Get(at).Add(rowsTotal)
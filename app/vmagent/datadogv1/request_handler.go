@@ -19,6 +19,8 @@ var (
 	rowsInserted       = metrics.NewCounter(`vmagent_rows_inserted_total{type="datadogv1"}`)
 	rowsTenantInserted = tenantmetrics.NewCounterMap(`vmagent_tenant_inserted_rows_total{type="datadogv1"}`)
 	rowsPerInsert      = metrics.NewHistogram(`vmagent_rows_per_insert{type="datadogv1"}`)
+	ingestionLag       = metrics.NewHistogram(`vmagent_ingestion_lag_seconds{type="datadogv1"}`)
+	tenantIngestionLag = tenantmetrics.NewHistogramMap(`vmagent_tenant_ingestion_lag_seconds{type="datadogv1"}`)
 )
 
 // InsertHandlerForHTTP processes remote write for DataDog POST /api/v1/series request.
@@ -90,6 +92,7 @@ func insertRows(at *auth.Token, series []datadogv1.Series, extraLabels []prompbm
 	if !remotewrite.TryPush(at, &ctx.WriteRequest) {
 		return remotewrite.ErrQueueFullHTTPRetry
 	}
+	ctx.UpdateIngestionLag(at, ingestionLag, tenantIngestionLag)
 	rowsInserted.Add(rowsTotal)
 	if at != nil {
 		rowsTenantInserted.Get(at).Add(rowsTotal)
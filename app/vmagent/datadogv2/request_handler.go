@@ -19,6 +19,8 @@ var (
 	rowsInserted       = metrics.NewCounter(`vmagent_rows_inserted_total{type="datadogv2"}`)
 	rowsTenantInserted = tenantmetrics.NewCounterMap(`vmagent_tenant_inserted_rows_total{type="datadogv2"}`)
 	rowsPerInsert      = metrics.NewHistogram(`vmagent_rows_per_insert{type="datadogv2"}`)
+	ingestionLag       = metrics.NewHistogram(`vmagent_ingestion_lag_seconds{type="datadogv2"}`)
+	tenantIngestionLag = tenantmetrics.NewHistogramMap(`vmagent_tenant_ingestion_lag_seconds{type="datadogv2"}`)
 )
 
 // InsertHandlerForHTTP processes remote write for DataDog POST /api/v2/series request.
@@ -93,6 +95,7 @@ func insertRows(at *auth.Token, series []datadogv2.Series, extraLabels []prompbm
 	if !remotewrite.TryPush(at, &ctx.WriteRequest) {
 		return remotewrite.ErrQueueFullHTTPRetry
 	}
+	ctx.UpdateIngestionLag(at, ingestionLag, tenantIngestionLag)
 	rowsInserted.Add(rowsTotal)
 	if at != nil {
 		rowsTenantInserted.Get(at).Add(rowsTotal)
@@ -9,12 +9,15 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
 	parser "github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/graphite"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/graphite/stream"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/tenantmetrics"
 	"github.com/VictoriaMetrics/metrics"
 )
 
 var (
-	rowsInserted  = metrics.NewCounter(`vmagent_rows_inserted_total{type="graphite"}`)
-	rowsPerInsert = metrics.NewHistogram(`vmagent_rows_per_insert{type="graphite"}`)
+	rowsInserted       = metrics.NewCounter(`vmagent_rows_inserted_total{type="graphite"}`)
+	rowsPerInsert      = metrics.NewHistogram(`vmagent_rows_per_insert{type="graphite"}`)
+	ingestionLag       = metrics.NewHistogram(`vmagent_ingestion_lag_seconds{type="graphite"}`)
+	tenantIngestionLag = tenantmetrics.NewHistogramMap(`vmagent_tenant_ingestion_lag_seconds{type="graphite"}`)
 )
 
 // InsertHandler processes remote write for graphite plaintext protocol.
@@ -26,6 +29,15 @@ func InsertHandler(r io.Reader) error {
 	})
 }
 
+// InsertPickleHandler processes remote write for graphite pickle protocol.
+//
+// See https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-pickle-protocol
+func InsertPickleHandler(r io.Reader) error {
+	return stream.ParsePickle(r, func(rows []parser.Row) error {
+		return insertRows(nil, rows)
+	})
+}
+
 func insertRows(at *auth.Token, rows []parser.Row) error {
 	ctx := common.GetPushCtx()
 	defer common.PutPushCtx(ctx)
@@ -62,6 +74,7 @@ func insertRows(at *auth.Token, rows []parser.Row) error {
 	if !remotewrite.TryPush(at, &ctx.WriteRequest) {
 		return remotewrite.ErrQueueFullHTTPRetry
 	}
+	ctx.UpdateIngestionLag(at, ingestionLag, tenantIngestionLag)
 	rowsInserted.Add(len(rows))
 	rowsPerInsert.Update(float64(len(rows)))
 	return nil
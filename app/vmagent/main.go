@@ -18,6 +18,7 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmagent/datadogv2"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmagent/graphite"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmagent/influx"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmagent/jsonlimport"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmagent/native"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmagent/newrelic"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmagent/opentelemetry"
@@ -26,6 +27,7 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmagent/prometheusimport"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmagent/promremotewrite"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmagent/remotewrite"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmagent/statsd"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmagent/vmimport"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/auth"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/buildinfo"
@@ -36,14 +38,20 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/influxutils"
 	graphiteserver "github.com/VictoriaMetrics/VictoriaMetrics/lib/ingestserver/graphite"
 	influxserver "github.com/VictoriaMetrics/VictoriaMetrics/lib/ingestserver/influx"
+	otelserver "github.com/VictoriaMetrics/VictoriaMetrics/lib/ingestserver/opentelemetry"
 	opentsdbserver "github.com/VictoriaMetrics/VictoriaMetrics/lib/ingestserver/opentsdb"
 	opentsdbhttpserver "github.com/VictoriaMetrics/VictoriaMetrics/lib/ingestserver/opentsdbhttp"
+	statsdserver "github.com/VictoriaMetrics/VictoriaMetrics/lib/ingestserver/statsd"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/procutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promscrape"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/common"
+	graphiteparser "github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/graphite"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/firehose"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/stream"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/pushmetrics"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/pushprofiles"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/stringsutil"
 )
 
@@ -63,6 +71,10 @@ var (
 		"See also -graphiteListenAddr.useProxyProtocol")
 	graphiteUseProxyProtocol = flag.Bool("graphiteListenAddr.useProxyProtocol", false, "Whether to use proxy protocol for connections accepted at -graphiteListenAddr . "+
 		"See https://www.haproxy.org/download/1.8/doc/proxy-protocol.txt")
+	graphitePickleListenAddr = flag.String("graphitePickleListenAddr", "", "TCP address to listen for Graphite data in pickle protocol, which is used by carbon-relay. "+
+		"Usually :2004 must be set. Doesn't work if empty. See also -graphitePickleListenAddr.useProxyProtocol")
+	graphitePickleUseProxyProtocol = flag.Bool("graphitePickleListenAddr.useProxyProtocol", false, "Whether to use proxy protocol for connections accepted at -graphitePickleListenAddr . "+
+		"See https://www.haproxy.org/download/1.8/doc/proxy-protocol.txt")
 	opentsdbListenAddr = flag.String("opentsdbListenAddr", "", "TCP and UDP address to listen for OpenTSDB metrics. "+
 		"Telnet put messages and HTTP /api/put messages are simultaneously served on TCP port. "+
 		"Usually :4242 must be set. Doesn't work if empty. See also -opentsdbListenAddr.useProxyProtocol")
@@ -72,6 +84,14 @@ var (
 		"See also -opentsdbHTTPListenAddr.useProxyProtocol")
 	opentsdbHTTPUseProxyProtocol = flag.Bool("opentsdbHTTPListenAddr.useProxyProtocol", false, "Whether to use proxy protocol for connections accepted "+
 		"at -opentsdbHTTPListenAddr . See https://www.haproxy.org/download/1.8/doc/proxy-protocol.txt")
+	opentelemetryGRPCListenAddr = flag.String("opentelemetryGRPCListenAddr", "", "TCP address to listen for OpenTelemetry metrics sent via OTLP/gRPC protocol. Usually :4317 must be set. Doesn't work if empty. "+
+		"See also -opentelemetryGRPCListenAddr.useProxyProtocol")
+	opentelemetryGRPCUseProxyProtocol = flag.Bool("opentelemetryGRPCListenAddr.useProxyProtocol", false, "Whether to use proxy protocol for connections accepted "+
+		"at -opentelemetryGRPCListenAddr . See https://www.haproxy.org/download/1.8/doc/proxy-protocol.txt")
+	statsdListenAddr = flag.String("statsdListenAddr", "", "TCP and UDP address to listen for StatsD data. Usually :8125 must be set. Doesn't work if empty. "+
+		"The received data is aggregated on the client side every -statsd.flushInterval. See also -statsdListenAddr.useProxyProtocol")
+	statsdUseProxyProtocol = flag.Bool("statsdListenAddr.useProxyProtocol", false, "Whether to use proxy protocol for connections accepted at -statsdListenAddr . "+
+		"See https://www.haproxy.org/download/1.8/doc/proxy-protocol.txt")
 	configAuthKey = flagutil.NewPassword("configAuthKey", "Authorization key for accessing /config page. It must be passed via authKey query arg. It overrides -httpAuth.*")
 	reloadAuthKey = flagutil.NewPassword("reloadAuthKey", "Auth key for /-/reload http endpoint. It must be passed via authKey query arg. It overrides -httpAuth.*")
 	dryRun        = flag.Bool("dryRun", false, "Whether to check config files without running vmagent. The following files are checked: "+
@@ -80,10 +100,13 @@ var (
 )
 
 var (
-	influxServer       *influxserver.Server
-	graphiteServer     *graphiteserver.Server
-	opentsdbServer     *opentsdbserver.Server
-	opentsdbhttpServer *opentsdbhttpserver.Server
+	influxServer         *influxserver.Server
+	graphiteServer       *graphiteserver.Server
+	graphitePickleServer *graphiteserver.Server
+	opentsdbServer       *opentsdbserver.Server
+	opentsdbhttpServer   *opentsdbhttpserver.Server
+	otelGRPCServer       *otelserver.Server
+	statsdServer         *statsdserver.Server
 )
 
 var (
@@ -131,14 +154,21 @@ func main() {
 	remotewrite.StartIngestionRateLimiter()
 	remotewrite.Init()
 	common.StartUnmarshalWorkers()
+	stream.InitAttributesMapping()
 	if len(*influxListenAddr) > 0 {
 		influxServer = influxserver.MustStart(*influxListenAddr, *influxUseProxyProtocol, func(r io.Reader) error {
 			return influx.InsertHandlerForReader(nil, r, false)
 		})
 	}
+	if len(*graphiteListenAddr) > 0 || len(*graphitePickleListenAddr) > 0 {
+		graphiteparser.InitMapping()
+	}
 	if len(*graphiteListenAddr) > 0 {
 		graphiteServer = graphiteserver.MustStart(*graphiteListenAddr, *graphiteUseProxyProtocol, graphite.InsertHandler)
 	}
+	if len(*graphitePickleListenAddr) > 0 {
+		graphitePickleServer = graphiteserver.MustStartPickle(*graphitePickleListenAddr, *graphitePickleUseProxyProtocol, graphite.InsertPickleHandler)
+	}
 	if len(*opentsdbListenAddr) > 0 {
 		httpInsertHandler := getOpenTSDBHTTPInsertHandler()
 		opentsdbServer = opentsdbserver.MustStart(*opentsdbListenAddr, *opentsdbUseProxyProtocol, opentsdb.InsertHandler, httpInsertHandler)
@@ -147,6 +177,17 @@ func main() {
 		httpInsertHandler := getOpenTSDBHTTPInsertHandler()
 		opentsdbhttpServer = opentsdbhttpserver.MustStart(*opentsdbHTTPListenAddr, *opentsdbHTTPUseProxyProtocol, httpInsertHandler)
 	}
+	if len(*opentelemetryGRPCListenAddr) > 0 {
+		otelGRPCServer = otelserver.MustStart(*opentelemetryGRPCListenAddr, *opentelemetryGRPCUseProxyProtocol, func(r io.Reader) (*pb.ExportMetricsPartialSuccess, error) {
+			return opentelemetry.InsertHandlerForReader(nil, r)
+		}, func(req *pb.ExportMetricsServiceRequest) (*pb.ExportMetricsPartialSuccess, error) {
+			return opentelemetry.InsertHandlerForRequest(nil, req)
+		})
+	}
+	if len(*statsdListenAddr) > 0 {
+		statsd.Init()
+		statsdServer = statsdserver.MustStart(*statsdListenAddr, *statsdUseProxyProtocol, statsd.InsertHandler)
+	}
 
 	promscrape.Init(remotewrite.PushDropSamplesOnFailure)
 
@@ -154,9 +195,11 @@ func main() {
 	logger.Infof("started vmagent in %.3f seconds", time.Since(startTime).Seconds())
 
 	pushmetrics.Init()
+	pushprofiles.Init()
 	sig := procutil.WaitForSigterm()
 	logger.Infof("received signal %s", sig)
 	remotewrite.StopIngestionRateLimiter()
+	pushprofiles.Stop()
 	pushmetrics.Stop()
 
 	startTime = time.Now()
@@ -174,12 +217,22 @@ func main() {
 	if len(*graphiteListenAddr) > 0 {
 		graphiteServer.MustStop()
 	}
+	if len(*graphitePickleListenAddr) > 0 {
+		graphitePickleServer.MustStop()
+	}
 	if len(*opentsdbListenAddr) > 0 {
 		opentsdbServer.MustStop()
 	}
 	if len(*opentsdbHTTPListenAddr) > 0 {
 		opentsdbhttpServer.MustStop()
 	}
+	if len(*opentelemetryGRPCListenAddr) > 0 {
+		otelGRPCServer.MustStop()
+	}
+	if len(*statsdListenAddr) > 0 {
+		statsdServer.MustStop()
+		statsd.MustStop()
+	}
 	common.StopUnmarshalWorkers()
 	remotewrite.Stop()
 
@@ -234,6 +287,7 @@ func requestHandler(w http.ResponseWriter, r *http.Request) bool {
 			{"service-discovery", "labels before and after relabeling for discovered targets"},
 			{"metric-relabel-debug", "debug metric relabeling"},
 			{"api/v1/targets", "advanced information about discovered targets in JSON format"},
+			{"api/v1/targets/cardinality", "top targets by scraped series, new series churn and scrape duration over the last hour"},
 			{"config", "-promscrape.config contents"},
 			{"metrics", "available service metrics"},
 			{"flags", "command-line flags"},
@@ -296,6 +350,15 @@ func requestHandler(w http.ResponseWriter, r *http.Request) bool {
 		}
 		w.WriteHeader(http.StatusNoContent)
 		return true
+	case "/prometheus/api/v1/import/jsonl", "/api/v1/import/jsonl":
+		jsonlimportRequests.Inc()
+		if err := jsonlimport.InsertHandler(nil, r); err != nil {
+			jsonlimportErrors.Inc()
+			httpserver.Errorf(w, r, "%s", err)
+			return true
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return true
 	case "/prometheus/api/v1/import/native", "/api/v1/import/native":
 		nativeimportRequests.Inc()
 		if err := native.InsertHandler(nil, r); err != nil {
@@ -305,7 +368,7 @@ func requestHandler(w http.ResponseWriter, r *http.Request) bool {
 		}
 		w.WriteHeader(http.StatusNoContent)
 		return true
-	case "/influx/write", "/influx/api/v2/write", "/write", "/api/v2/write":
+	case "/influx/write", "/write":
 		influxWriteRequests.Inc()
 		if err := influx.InsertHandlerForHTTP(nil, r); err != nil {
 			influxWriteErrors.Inc()
@@ -314,6 +377,15 @@ func requestHandler(w http.ResponseWriter, r *http.Request) bool {
 		}
 		w.WriteHeader(http.StatusNoContent)
 		return true
+	case "/influx/api/v2/write", "/api/v2/write":
+		influxWriteRequests.Inc()
+		if err := influx.InsertHandlerForHTTP(nil, r); err != nil {
+			influxWriteErrors.Inc()
+			influxutils.WriteV2ErrorResponse(w, r, err)
+			return true
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return true
 	case "/influx/query", "/query":
 		influxQueryRequests.Inc()
 		influxutils.WriteDatabaseNames(w)
@@ -324,11 +396,16 @@ func requestHandler(w http.ResponseWriter, r *http.Request) bool {
 		return true
 	case "/opentelemetry/api/v1/push", "/opentelemetry/v1/metrics":
 		opentelemetryPushRequests.Inc()
-		if err := opentelemetry.InsertHandler(nil, r); err != nil {
+		ps, err := opentelemetry.InsertHandler(nil, r)
+		if err != nil {
 			opentelemetryPushErrors.Inc()
 			httpserver.Errorf(w, r, "%s", err)
 			return true
 		}
+		if ps != nil {
+			stream.WritePartialSuccessResponse(w, r, ps)
+			return true
+		}
 		firehose.WriteSuccessResponse(w, r)
 		return true
 	case "/newrelic":
@@ -354,6 +431,17 @@ func requestHandler(w http.ResponseWriter, r *http.Request) bool {
 		w.WriteHeader(202)
 		fmt.Fprintf(w, `{"status":"ok"}`)
 		return true
+	case "/newrelic/metric/v1":
+		newrelicMetricAPIWriteRequests.Inc()
+		if err := newrelic.InsertMetricAPIHandlerForHTTP(nil, r); err != nil {
+			newrelicMetricAPIWriteErrors.Inc()
+			httpserver.Errorf(w, r, "%s", err)
+			return true
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(202)
+		fmt.Fprintf(w, `{"status":"ok"}`)
+		return true
 	case "/datadog/api/v1/series":
 		datadogv1WriteRequests.Inc()
 		if err := datadogv1.InsertHandlerForHTTP(nil, r); err != nil {
@@ -425,6 +513,10 @@ func requestHandler(w http.ResponseWriter, r *http.Request) bool {
 		promscrapeTargetRelabelDebugRequests.Inc()
 		promscrape.WriteTargetRelabelDebug(w, r)
 		return true
+	case "/prometheus/api/v1/targets/cardinality", "/api/v1/targets/cardinality":
+		promscrapeTargetsCardinalityRequests.Inc()
+		promscrape.WriteTargetsCardinalityReport(w, r)
+		return true
 	case "/prometheus/api/v1/targets", "/api/v1/targets":
 		promscrapeAPIV1TargetsRequests.Inc()
 		w.Header().Set("Content-Type", "application/json")
@@ -546,6 +638,15 @@ func processMultitenantRequest(w http.ResponseWriter, r *http.Request, path stri
 		}
 		w.WriteHeader(http.StatusNoContent)
 		return true
+	case "prometheus/api/v1/import/jsonl":
+		jsonlimportRequests.Inc()
+		if err := jsonlimport.InsertHandler(at, r); err != nil {
+			jsonlimportErrors.Inc()
+			httpserver.Errorf(w, r, "%s", err)
+			return true
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return true
 	case "prometheus/api/v1/import/native":
 		nativeimportRequests.Inc()
 		if err := native.InsertHandler(at, r); err != nil {
@@ -555,7 +656,7 @@ func processMultitenantRequest(w http.ResponseWriter, r *http.Request, path stri
 		}
 		w.WriteHeader(http.StatusNoContent)
 		return true
-	case "influx/write", "influx/api/v2/write":
+	case "influx/write":
 		influxWriteRequests.Inc()
 		if err := influx.InsertHandlerForHTTP(at, r); err != nil {
 			influxWriteErrors.Inc()
@@ -564,6 +665,15 @@ func processMultitenantRequest(w http.ResponseWriter, r *http.Request, path stri
 		}
 		w.WriteHeader(http.StatusNoContent)
 		return true
+	case "influx/api/v2/write":
+		influxWriteRequests.Inc()
+		if err := influx.InsertHandlerForHTTP(at, r); err != nil {
+			influxWriteErrors.Inc()
+			influxutils.WriteV2ErrorResponse(w, r, err)
+			return true
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return true
 	case "influx/query":
 		influxQueryRequests.Inc()
 		influxutils.WriteDatabaseNames(w)
@@ -574,11 +684,16 @@ func processMultitenantRequest(w http.ResponseWriter, r *http.Request, path stri
 		return true
 	case "opentelemetry/api/v1/push", "opentelemetry/v1/metrics":
 		opentelemetryPushRequests.Inc()
-		if err := opentelemetry.InsertHandler(at, r); err != nil {
+		ps, err := opentelemetry.InsertHandler(at, r)
+		if err != nil {
 			opentelemetryPushErrors.Inc()
 			httpserver.Errorf(w, r, "%s", err)
 			return true
 		}
+		if ps != nil {
+			stream.WritePartialSuccessResponse(w, r, ps)
+			return true
+		}
 		firehose.WriteSuccessResponse(w, r)
 		return true
 	case "newrelic":
@@ -604,6 +719,17 @@ func processMultitenantRequest(w http.ResponseWriter, r *http.Request, path stri
 		w.WriteHeader(202)
 		fmt.Fprintf(w, `{"status":"ok"}`)
 		return true
+	case "newrelic/metric/v1":
+		newrelicMetricAPIWriteRequests.Inc()
+		if err := newrelic.InsertMetricAPIHandlerForHTTP(at, r); err != nil {
+			newrelicMetricAPIWriteErrors.Inc()
+			httpserver.Errorf(w, r, "%s", err)
+			return true
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(202)
+		fmt.Fprintf(w, `{"status":"ok"}`)
+		return true
 	case "datadog/api/v1/series":
 		datadogv1WriteRequests.Inc()
 		if err := datadogv1.InsertHandlerForHTTP(at, r); err != nil {
@@ -673,6 +799,9 @@ var (
 	csvimportRequests = metrics.NewCounter(`vmagent_http_requests_total{path="/api/v1/import/csv", protocol="csvimport"}`)
 	csvimportErrors   = metrics.NewCounter(`vmagent_http_request_errors_total{path="/api/v1/import/csv", protocol="csvimport"}`)
 
+	jsonlimportRequests = metrics.NewCounter(`vmagent_http_requests_total{path="/api/v1/import/jsonl", protocol="jsonlimport"}`)
+	jsonlimportErrors   = metrics.NewCounter(`vmagent_http_request_errors_total{path="/api/v1/import/jsonl", protocol="jsonlimport"}`)
+
 	prometheusimportRequests = metrics.NewCounter(`vmagent_http_requests_total{path="/api/v1/import/prometheus", protocol="prometheusimport"}`)
 	prometheusimportErrors   = metrics.NewCounter(`vmagent_http_request_errors_total{path="/api/v1/import/prometheus", protocol="prometheusimport"}`)
 
@@ -705,6 +834,9 @@ var (
 	newrelicWriteRequests = metrics.NewCounter(`vm_http_requests_total{path="/newrelic/infra/v2/metrics/events/bulk", protocol="newrelic"}`)
 	newrelicWriteErrors   = metrics.NewCounter(`vm_http_request_errors_total{path="/newrelic/infra/v2/metrics/events/bulk", protocol="newrelic"}`)
 
+	newrelicMetricAPIWriteRequests = metrics.NewCounter(`vm_http_requests_total{path="/newrelic/metric/v1", protocol="newrelic"}`)
+	newrelicMetricAPIWriteErrors   = metrics.NewCounter(`vm_http_request_errors_total{path="/newrelic/metric/v1", protocol="newrelic"}`)
+
 	newrelicInventoryRequests = metrics.NewCounter(`vm_http_requests_total{path="/newrelic/inventory/deltas", protocol="newrelic"}`)
 	newrelicCheckRequest      = metrics.NewCounter(`vm_http_requests_total{path="/newrelic", protocol="newrelic"}`)
 
@@ -714,7 +846,8 @@ var (
 	promscrapeMetricRelabelDebugRequests = metrics.NewCounter(`vmagent_http_requests_total{path="/metric-relabel-debug"}`)
 	promscrapeTargetRelabelDebugRequests = metrics.NewCounter(`vmagent_http_requests_total{path="/target-relabel-debug"}`)
 
-	promscrapeAPIV1TargetsRequests = metrics.NewCounter(`vmagent_http_requests_total{path="/api/v1/targets"}`)
+	promscrapeAPIV1TargetsRequests       = metrics.NewCounter(`vmagent_http_requests_total{path="/api/v1/targets"}`)
+	promscrapeTargetsCardinalityRequests = metrics.NewCounter(`vmagent_http_requests_total{path="/api/v1/targets/cardinality"}`)
 
 	promscrapeTargetResponseRequests = metrics.NewCounter(`vmagent_http_requests_total{path="/target_response"}`)
 	promscrapeTargetResponseErrors   = metrics.NewCounter(`vmagent_http_request_errors_total{path="/target_response"}`)
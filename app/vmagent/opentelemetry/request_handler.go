@@ -1,7 +1,7 @@
 package opentelemetry
 
 import (
-	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmagent/common"
@@ -10,6 +10,7 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
 	parserCommon "github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/common"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/firehose"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/stream"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/tenantmetrics"
 	"github.com/VictoriaMetrics/metrics"
@@ -19,26 +20,57 @@ var (
 	rowsInserted       = metrics.NewCounter(`vmagent_rows_inserted_total{type="opentelemetry"}`)
 	rowsTenantInserted = tenantmetrics.NewCounterMap(`vmagent_tenant_inserted_rows_total{type="opentelemetry"}`)
 	rowsPerInsert      = metrics.NewHistogram(`vmagent_rows_per_insert{type="opentelemetry"}`)
+	ingestionLag       = metrics.NewHistogram(`vmagent_ingestion_lag_seconds{type="opentelemetry"}`)
+	tenantIngestionLag = tenantmetrics.NewHistogramMap(`vmagent_tenant_ingestion_lag_seconds{type="opentelemetry"}`)
 )
 
 // InsertHandler processes opentelemetry metrics.
-func InsertHandler(at *auth.Token, req *http.Request) error {
+//
+// It returns non-nil partial success if some data points from req were rejected.
+// Partial success isn't returned for AWS Firehose requests, since AWS Firehose doesn't support it.
+func InsertHandler(at *auth.Token, req *http.Request) (*pb.ExportMetricsPartialSuccess, error) {
 	extraLabels, err := parserCommon.GetExtraLabels(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	isGzipped := req.Header.Get("Content-Encoding") == "gzip"
+	ce := req.Header.Get("Content-Encoding")
 	var processBody func([]byte) ([]byte, error)
-	if req.Header.Get("Content-Type") == "application/json" {
+	isFirehose := false
+	if stream.IsJSONRequest(req) {
 		if req.Header.Get("X-Amz-Firehose-Protocol-Version") != "" {
 			processBody = firehose.ProcessRequestBody
+			isFirehose = true
 		} else {
-			return fmt.Errorf("json encoding isn't supported for opentelemetry format. Use protobuf encoding")
+			processBody = stream.ProcessJSONRequestBody
 		}
 	}
-	return stream.ParseStream(req.Body, isGzipped, processBody, func(tss []prompbmarshal.TimeSeries) error {
+	ps, err := stream.ParseStreamExt(req.Body, ce, processBody, func(tss []prompbmarshal.TimeSeries) error {
 		return insertRows(at, tss, extraLabels)
 	})
+	if err != nil || isFirehose {
+		return nil, err
+	}
+	return ps, nil
+}
+
+// InsertHandlerForReader processes protobuf-encoded opentelemetry metrics from r.
+//
+// It is used for processing requests to OTLP/gRPC server.
+// It returns non-nil partial success if some data points from r were rejected.
+func InsertHandlerForReader(at *auth.Token, r io.Reader) (*pb.ExportMetricsPartialSuccess, error) {
+	return stream.ParseStreamExt(r, "", nil, func(tss []prompbmarshal.TimeSeries) error {
+		return insertRows(at, tss, nil)
+	})
+}
+
+// InsertHandlerForRequest processes opentelemetry metrics from the already decoded req.
+//
+// It is used for processing OpenTelemetry Arrow batches at OTLP/gRPC server.
+// It returns non-nil partial success if some data points from req were rejected.
+func InsertHandlerForRequest(at *auth.Token, req *pb.ExportMetricsServiceRequest) (*pb.ExportMetricsPartialSuccess, error) {
+	return stream.ParseRequest(req, func(tss []prompbmarshal.TimeSeries) error {
+		return insertRows(at, tss, nil)
+	})
 }
 
 func insertRows(at *auth.Token, tss []prompbmarshal.TimeSeries, extraLabels []prompbmarshal.Label) error {
@@ -65,13 +97,21 @@ func insertRows(at *auth.Token, tss []prompbmarshal.TimeSeries, extraLabels []pr
 	ctx.WriteRequest.Timeseries = tssDst
 	ctx.Labels = labels
 	ctx.Samples = samples
-	if !remotewrite.TryPush(at, &ctx.WriteRequest) {
+	ok, rowsDropped, dropReason := remotewrite.TryPushExt(at, &ctx.WriteRequest)
+	if !ok {
 		return remotewrite.ErrQueueFullHTTPRetry
 	}
+	ctx.UpdateIngestionLag(at, ingestionLag, tenantIngestionLag)
 	rowsInserted.Add(rowsTotal)
 	if at != nil {
 		rowsTenantInserted.Get(at).Add(rowsTotal)
 	}
 	rowsPerInsert.Update(float64(rowsTotal))
+	if rowsDropped > 0 {
+		return &stream.RejectedSamplesError{
+			Count:  rowsDropped,
+			Reason: dropReason,
+		}
+	}
 	return nil
 }
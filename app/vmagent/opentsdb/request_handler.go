@@ -14,6 +14,7 @@ import (
 var (
 	rowsInserted  = metrics.NewCounter(`vmagent_rows_inserted_total{type="opentsdb"}`)
 	rowsPerInsert = metrics.NewHistogram(`vmagent_rows_per_insert{type="opentsdb"}`)
+	ingestionLag  = metrics.NewHistogram(`vmagent_ingestion_lag_seconds{type="opentsdb"}`)
 )
 
 // InsertHandler processes remote write for OpenTSDB put protocol.
@@ -59,6 +60,7 @@ func insertRows(rows []parser.Row) error {
 	if !remotewrite.TryPush(nil, &ctx.WriteRequest) {
 		return remotewrite.ErrQueueFullHTTPRetry
 	}
+	ctx.UpdateIngestionLag(nil, ingestionLag, nil)
 	rowsInserted.Add(len(rows))
 	rowsPerInsert.Update(float64(len(rows)))
 	return nil
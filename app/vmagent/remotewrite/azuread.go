@@ -0,0 +1,220 @@
+package remotewrite
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
+)
+
+var (
+	azureADUse = flagutil.NewArrayBool("remoteWrite.azuread.useAzureAD", "Enables Azure AD authorization for the corresponding -remoteWrite.url. "+
+		"It is expected that other -remoteWrite.azuread.* command-line flags are set if Azure AD authorization is enabled. "+
+		"See https://docs.victoriametrics.com/vmagent/#azure-ad-authorization")
+	azureADCloud = flagutil.NewArrayString("remoteWrite.azuread.cloud", "Optional Azure cloud to use for the corresponding -remoteWrite.url if -remoteWrite.azuread.useAzureAD is set. "+
+		"Supported values: AzurePublic, AzureChina, AzureGovernment. Defaults to AzurePublic")
+	azureADClientID = flagutil.NewArrayString("remoteWrite.azuread.clientID", "Optional client ID to use for the corresponding -remoteWrite.url if -remoteWrite.azuread.useAzureAD is set. "+
+		"It is used as Azure AD application client ID if -remoteWrite.azuread.clientSecret is set. Otherwise it is used as the client ID of user-assigned managed identity")
+	azureADClientSecret = flagutil.NewArrayString("remoteWrite.azuread.clientSecret", "Optional client secret to use for the corresponding -remoteWrite.url if -remoteWrite.azuread.useAzureAD is set. "+
+		"If it isn't set, then the token is obtained for the managed identity via Azure Instance Metadata Service")
+	azureADTenantID = flagutil.NewArrayString("remoteWrite.azuread.tenantID", "Optional tenant ID to use for the corresponding -remoteWrite.url if -remoteWrite.azuread.useAzureAD is set. "+
+		"It must be set if -remoteWrite.azuread.clientSecret is set")
+)
+
+// azureADCloudConfig contains Azure AD endpoints for the particular Azure cloud.
+type azureADCloudConfig struct {
+	authorityHost string
+	audience      string
+}
+
+var azureADClouds = map[string]azureADCloudConfig{
+	"AzurePublic": {
+		authorityHost: "https://login.microsoftonline.com",
+		audience:      "https://monitor.azure.com",
+	},
+	"AzureChina": {
+		authorityHost: "https://login.chinacloudapi.cn",
+		audience:      "https://monitor.azure.cn",
+	},
+	"AzureGovernment": {
+		authorityHost: "https://login.microsoftonline.us",
+		audience:      "https://monitor.azure.us",
+	},
+}
+
+// azureIMDSTokenURL is the url of Azure Instance Metadata Service for obtaining managed identity tokens.
+//
+// See https://learn.microsoft.com/en-us/entra/identity/managed-identities-azure-resources/how-to-use-vm-token
+const azureIMDSTokenURL = "http://169.254.169.254/metadata/identity/oauth2/token"
+
+// azureADConfig obtains and caches Azure AD access tokens for remote write requests.
+type azureADConfig struct {
+	client *http.Client
+
+	// newRequest creates a request for obtaining a fresh access token.
+	newRequest func() (*http.Request, error)
+
+	tokenLock     sync.Mutex
+	token         string
+	tokenDeadline time.Time
+}
+
+func getAzureADConfig(argIdx int) (*azureADConfig, error) {
+	if !azureADUse.GetOptionalArg(argIdx) {
+		return nil, nil
+	}
+	cloud := azureADCloud.GetOptionalArg(argIdx)
+	if cloud == "" {
+		cloud = "AzurePublic"
+	}
+	cc, ok := azureADClouds[cloud]
+	if !ok {
+		return nil, fmt.Errorf("unsupported -remoteWrite.azuread.cloud=%q; supported values: AzurePublic, AzureChina, AzureGovernment", cloud)
+	}
+	clientID := azureADClientID.GetOptionalArg(argIdx)
+	clientSecret := azureADClientSecret.GetOptionalArg(argIdx)
+	tenantID := azureADTenantID.GetOptionalArg(argIdx)
+	if clientSecret == "" {
+		return newAzureADManagedIdentityConfig(azureIMDSTokenURL, cc.audience, clientID), nil
+	}
+	if clientID == "" {
+		return nil, fmt.Errorf("missing -remoteWrite.azuread.clientID; it must be set together with -remoteWrite.azuread.clientSecret")
+	}
+	if tenantID == "" {
+		return nil, fmt.Errorf("missing -remoteWrite.azuread.tenantID; it must be set together with -remoteWrite.azuread.clientSecret")
+	}
+	tokenURL := fmt.Sprintf("%s/%s/oauth2/v2.0/token", cc.authorityHost, url.PathEscape(tenantID))
+	return newAzureADClientSecretConfig(tokenURL, cc.audience, clientID, clientSecret), nil
+}
+
+// newAzureADClientSecretConfig returns azureADConfig, which obtains tokens via OAuth2 client credentials flow.
+//
+// See https://learn.microsoft.com/en-us/entra/identity-platform/v2-oauth2-client-creds-grant-flow
+func newAzureADClientSecretConfig(tokenURL, audience, clientID, clientSecret string) *azureADConfig {
+	body := url.Values{
+		"grant_type":    {"client_credentials"},
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"scope":         {audience + "/.default"},
+	}.Encode()
+	return &azureADConfig{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		newRequest: func() (*http.Request, error) {
+			req, err := http.NewRequest(http.MethodPost, tokenURL, strings.NewReader(body))
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+			return req, nil
+		},
+	}
+}
+
+// newAzureADManagedIdentityConfig returns azureADConfig, which obtains tokens for managed identity via Azure Instance Metadata Service.
+//
+// clientID may be empty if the system-assigned managed identity must be used.
+func newAzureADManagedIdentityConfig(imdsURL, audience, clientID string) *azureADConfig {
+	args := url.Values{
+		"api-version": {"2018-02-01"},
+		"resource":    {audience},
+	}
+	if clientID != "" {
+		args.Set("client_id", clientID)
+	}
+	tokenURL := imdsURL + "?" + args.Encode()
+	return &azureADConfig{
+		client: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		newRequest: func() (*http.Request, error) {
+			req, err := http.NewRequest(http.MethodGet, tokenURL, nil)
+			if err != nil {
+				return nil, err
+			}
+			req.Header.Set("Metadata", "true")
+			return req, nil
+		},
+	}
+}
+
+// SetHeaders sets Authorization header with Azure AD access token to req.
+func (cfg *azureADConfig) SetHeaders(req *http.Request) error {
+	token, err := cfg.getFreshToken()
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return nil
+}
+
+// getFreshToken returns fresh access token.
+//
+// The token is refreshed if needed.
+func (cfg *azureADConfig) getFreshToken() (string, error) {
+	cfg.tokenLock.Lock()
+	defer cfg.tokenLock.Unlock()
+
+	if time.Until(cfg.tokenDeadline) > time.Minute {
+		// the token isn't expired yet.
+		return cfg.token, nil
+	}
+	token, expiresIn, err := cfg.getToken()
+	if err != nil {
+		return "", fmt.Errorf("cannot obtain Azure AD access token: %w", err)
+	}
+	cfg.token = token
+	cfg.tokenDeadline = time.Now().Add(expiresIn)
+	return token, nil
+}
+
+func (cfg *azureADConfig) getToken() (string, time.Duration, error) {
+	req, err := cfg.newRequest()
+	if err != nil {
+		return "", 0, fmt.Errorf("cannot create token request: %w", err)
+	}
+	resp, err := cfg.client.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("cannot perform token request to %q: %w", req.URL.Redacted(), err)
+	}
+	data, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return "", 0, fmt.Errorf("cannot read token response from %q: %w", req.URL.Redacted(), err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("unexpected status code for token request to %q; got %d; want %d; response body: %q",
+			req.URL.Redacted(), resp.StatusCode, http.StatusOK, data)
+	}
+	return parseAzureADTokenResponse(data)
+}
+
+// parseAzureADTokenResponse parses the access token and its lifetime from data.
+//
+// Azure AD returns expires_in as a number, while Azure Instance Metadata Service returns it as a string.
+func parseAzureADTokenResponse(data []byte) (string, time.Duration, error) {
+	var r struct {
+		AccessToken string          `json:"access_token"`
+		ExpiresIn   json.RawMessage `json:"expires_in"`
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return "", 0, fmt.Errorf("cannot parse token response: %w", err)
+	}
+	if r.AccessToken == "" {
+		return "", 0, fmt.Errorf("missing access_token in token response")
+	}
+	s := strings.Trim(string(r.ExpiresIn), `"`)
+	expiresIn, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("cannot parse expires_in=%q in token response: %w", s, err)
+	}
+	return r.AccessToken, time.Duration(expiresIn) * time.Second, nil
+}
@@ -0,0 +1,131 @@
+package remotewrite
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParseAzureADTokenResponse(t *testing.T) {
+	f := func(data, tokenExpected string, expiresInExpected time.Duration) {
+		t.Helper()
+
+		token, expiresIn, err := parseAzureADTokenResponse([]byte(data))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if token != tokenExpected {
+			t.Fatalf("unexpected token; got %q; want %q", token, tokenExpected)
+		}
+		if expiresIn != expiresInExpected {
+			t.Fatalf("unexpected expiresIn; got %s; want %s", expiresIn, expiresInExpected)
+		}
+	}
+
+	// Azure AD response
+	f(`{"token_type":"Bearer","expires_in":3599,"access_token":"foo"}`, "foo", 3599*time.Second)
+
+	// Azure Instance Metadata Service response
+	f(`{"access_token":"bar","expires_in":"86399","resource":"https://monitor.azure.com","token_type":"Bearer"}`, "bar", 86399*time.Second)
+}
+
+func TestParseAzureADTokenResponseFailure(t *testing.T) {
+	f := func(data string) {
+		t.Helper()
+
+		_, _, err := parseAzureADTokenResponse([]byte(data))
+		if err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	f(``)
+	f(`{"expires_in":3599}`)
+	f(`{"access_token":"foo"}`)
+	f(`{"access_token":"foo","expires_in":"bar"}`)
+}
+
+func TestAzureADConfigClientSecret(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Method != http.MethodPost {
+			t.Errorf("unexpected method; got %q; want %q", r.Method, http.MethodPost)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Errorf("cannot parse form: %s", err)
+		}
+		for k, v := range map[string]string{
+			"grant_type":    "client_credentials",
+			"client_id":     "client-id",
+			"client_secret": "client-secret",
+			"scope":         "https://monitor.azure.com/.default",
+		} {
+			if got := r.PostForm.Get(k); got != v {
+				t.Errorf("unexpected %s; got %q; want %q", k, got, v)
+			}
+		}
+		fmt.Fprintf(w, `{"token_type":"Bearer","expires_in":3599,"access_token":"token-%d"}`, requests)
+	}))
+	defer srv.Close()
+
+	cfg := newAzureADClientSecretConfig(srv.URL, "https://monitor.azure.com", "client-id", "client-secret")
+	checkAzureADHeaders(t, cfg, "Bearer token-1")
+
+	// The cached token must be used
+	checkAzureADHeaders(t, cfg, "Bearer token-1")
+
+	// The expired token must be refreshed
+	cfg.tokenDeadline = time.Now()
+	checkAzureADHeaders(t, cfg, "Bearer token-2")
+}
+
+func TestAzureADConfigManagedIdentity(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" {
+			t.Errorf("missing Metadata header")
+		}
+		q := r.URL.Query()
+		for k, v := range map[string]string{
+			"api-version": "2018-02-01",
+			"resource":    "https://monitor.azure.cn",
+			"client_id":   "identity-id",
+		} {
+			if got := q.Get(k); got != v {
+				t.Errorf("unexpected %s; got %q; want %q", k, got, v)
+			}
+		}
+		fmt.Fprintf(w, `{"access_token":"foo","expires_in":"86399","token_type":"Bearer"}`)
+	}))
+	defer srv.Close()
+
+	cfg := newAzureADManagedIdentityConfig(srv.URL, "https://monitor.azure.cn", "identity-id")
+	checkAzureADHeaders(t, cfg, "Bearer foo")
+}
+
+func TestAzureADConfigFailure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	cfg := newAzureADClientSecretConfig(srv.URL, "https://monitor.azure.com", "client-id", "client-secret")
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/api/v1/write", nil)
+	if err := cfg.SetHeaders(req); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+}
+
+func checkAzureADHeaders(t *testing.T, cfg *azureADConfig, authExpected string) {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodPost, "http://localhost/api/v1/write", nil)
+	if err := cfg.SetHeaders(req); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if auth := req.Header.Get("Authorization"); auth != authExpected {
+		t.Fatalf("unexpected Authorization header; got %q; want %q", auth, authExpected)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/VictoriaMetrics/metrics"
+	"google.golang.org/grpc"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/awsapi"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
@@ -89,27 +90,38 @@ type client struct {
 	// Whether to use VictoriaMetrics remote write protocol for sending the data to remoteWriteURL
 	useVMProto bool
 
+	// openTelemetryProto is set to the OpenTelemetry protocol used for sending the data to remoteWriteURL.
+	//
+	// See -remoteWrite.opentelemetryProto
+	openTelemetryProto string
+
+	// grpcConn is used for sending the data to remoteWriteURL via OTLP/gRPC.
+	grpcConn *grpc.ClientConn
+
 	fq *persistentqueue.FastQueue
 	hc *http.Client
 
 	retryMinInterval time.Duration
 	retryMaxTime     time.Duration
+	retryPolicy      *retryPolicy
 
 	sendBlock func(block []byte) bool
 	authCfg   *promauth.Config
 	awsCfg    *awsapi.Config
+	azureCfg  *azureADConfig
 
 	rl *ratelimiter.RateLimiter
 
-	bytesSent       *metrics.Counter
-	blocksSent      *metrics.Counter
-	requestDuration *metrics.Histogram
-	requestsOKCount *metrics.Counter
-	errorsCount     *metrics.Counter
-	packetsDropped  *metrics.Counter
-	rateLimit       *metrics.Gauge
-	retriesCount    *metrics.Counter
-	sendDuration    *metrics.FloatCounter
+	bytesSent        *metrics.Counter
+	blocksSent       *metrics.Counter
+	requestDuration  *metrics.Histogram
+	requestsOKCount  *metrics.Counter
+	errorsCount      *metrics.Counter
+	packetsDropped   *metrics.Counter
+	deadLetterBlocks *metrics.Counter
+	rateLimit        *metrics.Gauge
+	retriesCount     *metrics.Counter
+	sendDuration     *metrics.FloatCounter
 
 	wg     sync.WaitGroup
 	stopCh chan struct{}
@@ -124,6 +136,17 @@ func newHTTPClient(argIdx int, remoteWriteURL, sanitizedURL string, fq *persiste
 	if err != nil {
 		logger.Fatalf("cannot initialize AWS Config for -remoteWrite.url=%q: %s", remoteWriteURL, err)
 	}
+	azureCfg, err := getAzureADConfig(argIdx)
+	if err != nil {
+		logger.Fatalf("cannot initialize Azure AD config for -remoteWrite.url=%q: %s", remoteWriteURL, err)
+	}
+	rp, err := getRetryPolicy(argIdx)
+	if err != nil {
+		logger.Fatalf("cannot initialize retry policy for -remoteWrite.url=%q: %s", remoteWriteURL, err)
+	}
+	if awsCfg != nil && azureCfg != nil {
+		logger.Fatalf("-remoteWrite.aws.useSigv4 and -remoteWrite.azuread.useAzureAD cannot be set simultaneously for -remoteWrite.url=%q", sanitizedURL)
+	}
 	tr := &http.Transport{
 		DialContext:         netutil.NewStatDialFunc("vmagent_remotewrite"),
 		TLSHandshakeTimeout: tlsHandshakeTimeout.GetOptionalArg(argIdx),
@@ -152,13 +175,21 @@ func newHTTPClient(argIdx int, remoteWriteURL, sanitizedURL string, fq *persiste
 		remoteWriteURL:   remoteWriteURL,
 		authCfg:          authCfg,
 		awsCfg:           awsCfg,
+		azureCfg:         azureCfg,
 		fq:               fq,
 		hc:               hc,
 		retryMinInterval: retryMinInterval.GetOptionalArg(argIdx),
 		retryMaxTime:     retryMaxTime.GetOptionalArg(argIdx),
+		retryPolicy:      rp,
 		stopCh:           make(chan struct{}),
 	}
 	c.sendBlock = c.sendBlockHTTP
+	if c.initOpenTelemetry(argIdx) {
+		if forceVMProto.GetOptionalArg(argIdx) {
+			logger.Fatalf("-remoteWrite.forceVMProto and -remoteWrite.opentelemetryProto cannot be set simultaneously for -remoteWrite.url=%s", sanitizedURL)
+		}
+		return c
+	}
 
 	useVMProto := forceVMProto.GetOptionalArg(argIdx)
 	usePromProto := forcePromProto.GetOptionalArg(argIdx)
@@ -196,6 +227,7 @@ func (c *client) init(argIdx, concurrency int, sanitizedURL string) {
 	c.requestsOKCount = metrics.GetOrCreateCounter(fmt.Sprintf(`vmagent_remotewrite_requests_total{url=%q, status_code="2XX"}`, c.sanitizedURL))
 	c.errorsCount = metrics.GetOrCreateCounter(fmt.Sprintf(`vmagent_remotewrite_errors_total{url=%q}`, c.sanitizedURL))
 	c.packetsDropped = metrics.GetOrCreateCounter(fmt.Sprintf(`vmagent_remotewrite_packets_dropped_total{url=%q}`, c.sanitizedURL))
+	c.deadLetterBlocks = metrics.GetOrCreateCounter(fmt.Sprintf(`vmagent_remotewrite_dead_letter_blocks_total{url=%q}`, c.sanitizedURL))
 	c.retriesCount = metrics.GetOrCreateCounter(fmt.Sprintf(`vmagent_remotewrite_retries_count_total{url=%q}`, c.sanitizedURL))
 	c.sendDuration = metrics.GetOrCreateFloatCounter(fmt.Sprintf(`vmagent_remotewrite_send_duration_seconds_total{url=%q}`, c.sanitizedURL))
 	metrics.GetOrCreateGauge(fmt.Sprintf(`vmagent_remotewrite_queues{url=%q}`, c.sanitizedURL), func() float64 {
@@ -214,6 +246,9 @@ func (c *client) init(argIdx, concurrency int, sanitizedURL string) {
 func (c *client) MustStop() {
 	close(c.stopCh)
 	c.wg.Wait()
+	if c.grpcConn != nil {
+		_ = c.grpcConn.Close()
+	}
 	logger.Infof("stopped client for -remoteWrite.url=%q", c.sanitizedURL)
 }
 
@@ -384,7 +419,9 @@ func (c *client) newRequest(url string, body []byte) (*http.Request, error) {
 	h := req.Header
 	h.Set("User-Agent", "vmagent")
 	h.Set("Content-Type", "application/x-protobuf")
-	if c.useVMProto {
+	if c.openTelemetryProto == openTelemetryProtoHTTP {
+		h.Set("Content-Encoding", "gzip")
+	} else if c.useVMProto {
 		h.Set("Content-Encoding", "zstd")
 		h.Set("X-VictoriaMetrics-Remote-Write-Version", "1")
 	} else {
@@ -397,31 +434,53 @@ func (c *client) newRequest(url string, body []byte) (*http.Request, error) {
 			return nil, fmt.Errorf("cannot sign remoteWrite request with AWS sigv4: %w", err)
 		}
 	}
+	if c.azureCfg != nil {
+		if err := c.azureCfg.SetHeaders(req); err != nil {
+			return nil, fmt.Errorf("cannot set Azure AD authorization for remoteWrite request: %w", err)
+		}
+	}
 	return req, nil
 }
 
 // sendBlockHTTP sends the given block to c.remoteWriteURL.
 //
 // The function returns false only if c.stopCh is closed.
-// Otherwise, it tries sending the block to remote storage indefinitely.
+// Otherwise, it tries sending the block to remote storage according to c.retryPolicy.
 func (c *client) sendBlockHTTP(block []byte) bool {
-	c.rl.Register(len(block))
+	return c.sendRequestHTTP(block, block)
+}
+
+// sendRequestHTTP sends the given body to c.remoteWriteURL.
+//
+// block must contain the original block from the queue for the body. It is passed to c.rejectBlock if the body is rejected.
+//
+// The function returns false only if c.stopCh is closed.
+// Otherwise, it tries sending the body to remote storage according to c.retryPolicy.
+func (c *client) sendRequestHTTP(body, block []byte) bool {
+	c.rl.Register(len(body))
 	maxRetryDuration := timeutil.AddJitterToDuration(c.retryMaxTime)
 	retryDuration := timeutil.AddJitterToDuration(c.retryMinInterval)
 	retriesCount := 0
+	sendStartTime := time.Now()
 
 again:
 	startTime := time.Now()
-	resp, err := c.doRequest(c.remoteWriteURL, block)
+	resp, err := c.doRequest(c.remoteWriteURL, body)
 	c.requestDuration.UpdateDuration(startTime)
 	if err != nil {
 		c.errorsCount.Inc()
+		if c.retryPolicy.isRetryDurationExceeded(sendStartTime) {
+			remoteWriteRejectedLogger.Errorf("couldn't send a block with size %d bytes to %q during -remoteWrite.maxRetryDuration=%s: %s; skipping the block",
+				len(body), c.sanitizedURL, c.retryPolicy.maxRetryDuration, err)
+			c.rejectBlock(block)
+			return true
+		}
 		retryDuration *= 2
 		if retryDuration > maxRetryDuration {
 			retryDuration = maxRetryDuration
 		}
 		logger.Warnf("couldn't send a block with size %d bytes to %q: %s; re-sending the block in %.3f seconds",
-			len(block), c.sanitizedURL, err, retryDuration.Seconds())
+			len(body), c.sanitizedURL, err, retryDuration.Seconds())
 		t := timerpool.Get(retryDuration)
 		select {
 		case <-c.stopCh:
@@ -437,43 +496,46 @@ again:
 	if statusCode/100 == 2 {
 		_ = resp.Body.Close()
 		c.requestsOKCount.Inc()
-		c.bytesSent.Add(len(block))
+		c.bytesSent.Add(len(body))
 		c.blocksSent.Inc()
 		return true
 	}
 	metrics.GetOrCreateCounter(fmt.Sprintf(`vmagent_remotewrite_requests_total{url=%q, status_code="%d"}`, c.sanitizedURL, statusCode)).Inc()
-	if statusCode == 409 || statusCode == 400 {
-		body, err := io.ReadAll(resp.Body)
+	if !c.retryPolicy.isRetryableStatusCode(statusCode) {
+		respBody, err := io.ReadAll(resp.Body)
 		_ = resp.Body.Close()
 		if err != nil {
 			remoteWriteRejectedLogger.Errorf("sending a block with size %d bytes to %q was rejected (skipping the block): status code %d; "+
 				"failed to read response body: %s",
-				len(block), c.sanitizedURL, statusCode, err)
+				len(body), c.sanitizedURL, statusCode, err)
 		} else {
 			remoteWriteRejectedLogger.Errorf("sending a block with size %d bytes to %q was rejected (skipping the block): status code %d; response body: %s",
-				len(block), c.sanitizedURL, statusCode, string(body))
+				len(body), c.sanitizedURL, statusCode, string(respBody))
 		}
-		// Just drop block on 409 and 400 status codes like Prometheus does.
-		// See https://github.com/VictoriaMetrics/VictoriaMetrics/issues/873
-		// and https://github.com/VictoriaMetrics/VictoriaMetrics/issues/1149
-		_ = resp.Body.Close()
-		c.packetsDropped.Inc()
+		c.rejectBlock(block)
 		return true
 	}
 
 	// Unexpected status code returned
+	if c.retryPolicy.isRetryDurationExceeded(sendStartTime) {
+		_ = resp.Body.Close()
+		remoteWriteRejectedLogger.Errorf("couldn't send a block with size %d bytes to %q during -remoteWrite.maxRetryDuration=%s: status code %d; skipping the block",
+			len(body), c.sanitizedURL, c.retryPolicy.maxRetryDuration, statusCode)
+		c.rejectBlock(block)
+		return true
+	}
 	retriesCount++
 	retryDuration *= 2
 	if retryDuration > maxRetryDuration {
 		retryDuration = maxRetryDuration
 	}
-	body, err := io.ReadAll(resp.Body)
+	respBody, err := io.ReadAll(resp.Body)
 	_ = resp.Body.Close()
 	if err != nil {
 		logger.Errorf("cannot read response body from %q during retry #%d: %s", c.sanitizedURL, retriesCount, err)
 	} else {
 		logger.Errorf("unexpected status code received after sending a block with size %d bytes to %q during retry #%d: %d; response body=%q; "+
-			"re-sending the block in %.3f seconds", len(block), c.sanitizedURL, retriesCount, statusCode, body, retryDuration.Seconds())
+			"re-sending the block in %.3f seconds", len(body), c.sanitizedURL, retriesCount, statusCode, respBody, retryDuration.Seconds())
 	}
 	t := timerpool.Get(retryDuration)
 	select {
@@ -0,0 +1,326 @@
+package remotewrite
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/golang/snappy"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/buildinfo"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/decimal"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompb"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/timerpool"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/timeutil"
+)
+
+var openTelemetryProto = flagutil.NewArrayString("remoteWrite.opentelemetryProto", "Optional OpenTelemetry protocol to use for sending data to the corresponding -remoteWrite.url. "+
+	"Supported values: 'http' - OTLP/HTTP with protobuf encoding, 'grpc' - OTLP/gRPC. For example, -remoteWrite.url=http://otel-collector:4318/v1/metrics -remoteWrite.opentelemetryProto=http . "+
+	"By default, the data is sent via VictoriaMetrics or Prometheus remote write protocol. See https://docs.victoriametrics.com/vmagent/#sending-data-via-opentelemetry-protocol")
+
+// The supported values for -remoteWrite.opentelemetryProto
+const (
+	openTelemetryProtoHTTP = "http"
+	openTelemetryProtoGRPC = "grpc"
+)
+
+// metricsServiceExportMethod is the full name for OTLP/gRPC method for exporting metrics.
+//
+// See https://github.com/open-telemetry/opentelemetry-proto/blob/main/opentelemetry/proto/collector/metrics/v1/metrics_service.proto
+const metricsServiceExportMethod = "/opentelemetry.proto.collector.metrics.v1.MetricsService/Export"
+
+// initOpenTelemetry configures c for sending data via OpenTelemetry protocol if it is enabled via -remoteWrite.opentelemetryProto.
+//
+// Blocks are stored in the queue in Prometheus remote write format and are converted to OpenTelemetry format just before sending.
+func (c *client) initOpenTelemetry(argIdx int) bool {
+	proto := openTelemetryProto.GetOptionalArg(argIdx)
+	switch proto {
+	case "":
+		return false
+	case openTelemetryProtoHTTP:
+		c.openTelemetryProto = proto
+		c.sendBlock = c.sendBlockOpenTelemetryHTTP
+	case openTelemetryProtoGRPC:
+		conn, err := c.newGRPCClientConn()
+		if err != nil {
+			logger.Fatalf("cannot initialize OTLP/gRPC client for -remoteWrite.url=%q: %s", c.sanitizedURL, err)
+		}
+		c.openTelemetryProto = proto
+		c.grpcConn = conn
+		c.sendBlock = c.sendBlockOpenTelemetryGRPC
+	default:
+		logger.Fatalf("unsupported -remoteWrite.opentelemetryProto=%q for -remoteWrite.url=%q; supported values: %q, %q",
+			proto, c.sanitizedURL, openTelemetryProtoHTTP, openTelemetryProtoGRPC)
+	}
+	return true
+}
+
+func (c *client) newGRPCClientConn() (*grpc.ClientConn, error) {
+	u, err := url.Parse(c.remoteWriteURL)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse url: %w", err)
+	}
+	var creds credentials.TransportCredentials
+	switch u.Scheme {
+	case "http":
+		creds = insecure.NewCredentials()
+	case "https":
+		tlsCfg, err := c.authCfg.GetTLSConfig()
+		if err != nil {
+			return nil, fmt.Errorf("cannot initialize tls config: %w", err)
+		}
+		creds = credentials.NewTLS(tlsCfg)
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q; supported schemes: http, https", u.Scheme)
+	}
+	return grpc.NewClient(u.Host, grpc.WithTransportCredentials(creds), grpc.WithDefaultCallOptions(grpc.ForceCodec(rawCodec{})))
+}
+
+// convertBlockToOpenTelemetry converts snappy-compressed Prometheus remote write block to protobuf-encoded OTLP ExportMetricsServiceRequest.
+//
+// false is returned if the block cannot be converted.
+func (c *client) convertBlockToOpenTelemetry(block []byte) ([]byte, bool) {
+	data, err := snappy.Decode(nil, block)
+	if err != nil {
+		logger.Errorf("cannot decompress block with size %d bytes for sending it to %q: %s; dropping the block", len(block), c.sanitizedURL, err)
+		c.packetsDropped.Inc()
+		return nil, false
+	}
+	var wr prompb.WriteRequest
+	if err := wr.UnmarshalProtobuf(data); err != nil {
+		logger.Errorf("cannot unmarshal block with size %d bytes for sending it to %q: %s; dropping the block", len(block), c.sanitizedURL, err)
+		c.packetsDropped.Inc()
+		return nil, false
+	}
+	req := newOpenTelemetryRequest(wr.Timeseries)
+	return req.MarshalProtobuf(nil), true
+}
+
+// sendBlockOpenTelemetryHTTP sends the given block to c.remoteWriteURL via OTLP/HTTP.
+func (c *client) sendBlockOpenTelemetryHTTP(block []byte) bool {
+	data, ok := c.convertBlockToOpenTelemetry(block)
+	if !ok {
+		return true
+	}
+	var bb bytes.Buffer
+	zw := gzip.NewWriter(&bb)
+	_, _ = zw.Write(data)
+	_ = zw.Close()
+	return c.sendRequestHTTP(bb.Bytes(), block)
+}
+
+// sendBlockOpenTelemetryGRPC sends the given block to c.remoteWriteURL via OTLP/gRPC.
+//
+// The function returns false only if c.stopCh is closed.
+// Otherwise, it tries sending the block to remote storage according to c.retryPolicy.
+func (c *client) sendBlockOpenTelemetryGRPC(block []byte) bool {
+	data, ok := c.convertBlockToOpenTelemetry(block)
+	if !ok {
+		return true
+	}
+	c.rl.Register(len(data))
+	maxRetryDuration := timeutil.AddJitterToDuration(c.retryMaxTime)
+	retryDuration := timeutil.AddJitterToDuration(c.retryMinInterval)
+	sendStartTime := time.Now()
+
+again:
+	startTime := time.Now()
+	err := c.invokeGRPCExport(data)
+	c.requestDuration.UpdateDuration(startTime)
+	if err == nil {
+		c.requestsOKCount.Inc()
+		c.bytesSent.Add(len(data))
+		c.blocksSent.Inc()
+		return true
+	}
+	c.errorsCount.Inc()
+	if !isRetryableGRPCError(err) {
+		remoteWriteRejectedLogger.Errorf("sending a block with size %d bytes to %q was rejected (skipping the block): %s", len(data), c.sanitizedURL, err)
+		c.rejectBlock(block)
+		return true
+	}
+	if c.retryPolicy.isRetryDurationExceeded(sendStartTime) {
+		remoteWriteRejectedLogger.Errorf("couldn't send a block with size %d bytes to %q during -remoteWrite.maxRetryDuration=%s: %s; skipping the block",
+			len(data), c.sanitizedURL, c.retryPolicy.maxRetryDuration, err)
+		c.rejectBlock(block)
+		return true
+	}
+	retryDuration *= 2
+	if retryDuration > maxRetryDuration {
+		retryDuration = maxRetryDuration
+	}
+	logger.Warnf("couldn't send a block with size %d bytes to %q: %s; re-sending the block in %.3f seconds",
+		len(data), c.sanitizedURL, err, retryDuration.Seconds())
+	t := timerpool.Get(retryDuration)
+	select {
+	case <-c.stopCh:
+		timerpool.Put(t)
+		return false
+	case <-t.C:
+		timerpool.Put(t)
+	}
+	c.retriesCount.Inc()
+	goto again
+}
+
+func (c *client) invokeGRPCExport(data []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.hc.Timeout)
+	defer cancel()
+
+	var kvs []string
+	for k, vs := range c.authCfg.GetHTTPHeadersNoAuth() {
+		for _, v := range vs {
+			kvs = append(kvs, strings.ToLower(k), v)
+		}
+	}
+	ah, err := c.authCfg.GetAuthHeader()
+	if err != nil {
+		return fmt.Errorf("failed to obtain Authorization request header: %w", err)
+	}
+	if ah != "" {
+		kvs = append(kvs, "authorization", ah)
+	}
+	if len(kvs) > 0 {
+		ctx = metadata.AppendToOutgoingContext(ctx, kvs...)
+	}
+	var resp []byte
+	return c.grpcConn.Invoke(ctx, metricsServiceExportMethod, data, &resp)
+}
+
+// isRetryableGRPCError returns true if the request must be retried on the given err.
+//
+// See https://opentelemetry.io/docs/specs/otlp/#failures
+func isRetryableGRPCError(err error) bool {
+	switch status.Code(err) {
+	case codes.Canceled, codes.DeadlineExceeded, codes.Aborted, codes.OutOfRange, codes.Unavailable, codes.DataLoss, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// rawCodec passes protobuf-encoded messages as is, since they are marshaled with lib/protoparser/opentelemetry/pb.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	b, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("BUG: unexpected type %T; want []byte", v)
+	}
+	return b, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("BUG: unexpected type %T; want *[]byte", v)
+	}
+	*b = append((*b)[:0], data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+// newOpenTelemetryRequest converts tss into OpenTelemetry request.
+//
+// Metrics with _total suffix are converted into monotonic cumulative sums, while the rest of metrics are converted into gauges.
+// Staleness markers are converted into data points with NO_RECORDED_VALUE flag.
+func newOpenTelemetryRequest(tss []prompb.TimeSeries) *pb.ExportMetricsServiceRequest {
+	var ms []*pb.Metric
+	metricsByName := make(map[string]*pb.Metric)
+	for i := range tss {
+		ts := &tss[i]
+		metricName := ""
+		var attrs []*pb.KeyValue
+		for _, label := range ts.Labels {
+			if label.Name == "__name__" {
+				metricName = label.Value
+				continue
+			}
+			attrs = append(attrs, newStringKeyValue(label.Name, label.Value))
+		}
+
+		m := metricsByName[metricName]
+		if m == nil {
+			m = &pb.Metric{
+				Name: metricName,
+			}
+			if strings.HasSuffix(metricName, "_total") {
+				m.Sum = &pb.Sum{
+					AggregationTemporality: pb.AggregationTemporalityCumulative,
+					IsMonotonic:            true,
+				}
+			} else {
+				m.Gauge = &pb.Gauge{}
+			}
+			metricsByName[metricName] = m
+			ms = append(ms, m)
+		}
+		for _, s := range ts.Samples {
+			v := s.Value
+			dp := &pb.NumberDataPoint{
+				Attributes:   attrs,
+				TimeUnixNano: uint64(s.Timestamp) * 1e6,
+				DoubleValue:  &v,
+			}
+			if decimal.IsStaleNaN(v) {
+				// See https://github.com/open-telemetry/opentelemetry-proto/blob/main/opentelemetry/proto/metrics/v1/metrics.proto
+				dp.Flags = 1
+				v = math.NaN()
+			}
+			if m.Sum != nil {
+				m.Sum.DataPoints = append(m.Sum.DataPoints, dp)
+			} else {
+				m.Gauge.DataPoints = append(m.Gauge.DataPoints, dp)
+			}
+		}
+	}
+	return &pb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*pb.ResourceMetrics{
+			{
+				Resource: openTelemetryResource,
+				ScopeMetrics: []*pb.ScopeMetrics{
+					{
+						Metrics: ms,
+					},
+				},
+			},
+		},
+	}
+}
+
+var openTelemetryResource = newOpenTelemetryResource()
+
+func newOpenTelemetryResource() *pb.Resource {
+	r := &pb.Resource{}
+	r.Attributes = append(r.Attributes, newStringKeyValue("service.name", "vmagent"))
+	if buildinfo.Version != "" {
+		r.Attributes = append(r.Attributes, newStringKeyValue("service.version", buildinfo.Version))
+	}
+	return r
+}
+
+func newStringKeyValue(key, value string) *pb.KeyValue {
+	return &pb.KeyValue{
+		Key: key,
+		Value: &pb.AnyValue{
+			StringValue: &value,
+		},
+	}
+}
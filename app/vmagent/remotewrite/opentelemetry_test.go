@@ -0,0 +1,152 @@
+package remotewrite
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/decimal"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompb"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+)
+
+func TestNewOpenTelemetryRequest(t *testing.T) {
+	tss := []prompb.TimeSeries{
+		{
+			Labels: []prompb.Label{
+				{
+					Name:  "__name__",
+					Value: "http_requests_total",
+				},
+				{
+					Name:  "path",
+					Value: "/foo",
+				},
+			},
+			Samples: []prompb.Sample{
+				{
+					Value:     10,
+					Timestamp: 1000,
+				},
+				{
+					Value:     12,
+					Timestamp: 2000,
+				},
+			},
+		},
+		{
+			Labels: []prompb.Label{
+				{
+					Name:  "__name__",
+					Value: "temperature",
+				},
+			},
+			Samples: []prompb.Sample{
+				{
+					Value:     decimal.StaleNaN,
+					Timestamp: 3000,
+				},
+			},
+		},
+		{
+			Labels: []prompb.Label{
+				{
+					Name:  "__name__",
+					Value: "http_requests_total",
+				},
+				{
+					Name:  "path",
+					Value: "/bar",
+				},
+			},
+			Samples: []prompb.Sample{
+				{
+					Value:     5,
+					Timestamp: 1000,
+				},
+			},
+		},
+	}
+	req := newOpenTelemetryRequest(tss)
+
+	if len(req.ResourceMetrics) != 1 || len(req.ResourceMetrics[0].ScopeMetrics) != 1 {
+		t.Fatalf("unexpected number of resource metrics or scope metrics in the request")
+	}
+	if req.ResourceMetrics[0].Resource != openTelemetryResource {
+		t.Fatalf("unexpected resource in the request")
+	}
+	ms := req.ResourceMetrics[0].ScopeMetrics[0].Metrics
+	if len(ms) != 2 {
+		t.Fatalf("unexpected number of metrics; got %d; want 2", len(ms))
+	}
+
+	f := func(m *pb.Metric, name string, isSum bool, dpsExpected []*pb.NumberDataPoint) {
+		t.Helper()
+
+		if m.Name != name {
+			t.Fatalf("unexpected metric name; got %q; want %q", m.Name, name)
+		}
+		var dps []*pb.NumberDataPoint
+		if isSum {
+			if m.Sum == nil || m.Gauge != nil {
+				t.Fatalf("expecting sum metric for %q", name)
+			}
+			if m.Sum.AggregationTemporality != pb.AggregationTemporalityCumulative || !m.Sum.IsMonotonic {
+				t.Fatalf("expecting monotonic cumulative sum for %q", name)
+			}
+			dps = m.Sum.DataPoints
+		} else {
+			if m.Gauge == nil || m.Sum != nil {
+				t.Fatalf("expecting gauge metric for %q", name)
+			}
+			dps = m.Gauge.DataPoints
+		}
+		if len(dps) != len(dpsExpected) {
+			t.Fatalf("unexpected number of data points for %q; got %d; want %d", name, len(dps), len(dpsExpected))
+		}
+		for i, dp := range dps {
+			dpExpected := dpsExpected[i]
+			if !reflect.DeepEqual(dp.Attributes, dpExpected.Attributes) {
+				t.Fatalf("unexpected attributes for %q at data point #%d", name, i)
+			}
+			if dp.TimeUnixNano != dpExpected.TimeUnixNano {
+				t.Fatalf("unexpected timestamp for %q at data point #%d; got %d; want %d", name, i, dp.TimeUnixNano, dpExpected.TimeUnixNano)
+			}
+			if dp.Flags != dpExpected.Flags {
+				t.Fatalf("unexpected flags for %q at data point #%d; got %d; want %d", name, i, dp.Flags, dpExpected.Flags)
+			}
+			v, vExpected := *dp.DoubleValue, *dpExpected.DoubleValue
+			if v != vExpected && !(math.IsNaN(v) && math.IsNaN(vExpected)) {
+				t.Fatalf("unexpected value for %q at data point #%d; got %v; want %v", name, i, v, vExpected)
+			}
+		}
+	}
+
+	float64Ptr := func(v float64) *float64 {
+		return &v
+	}
+	f(ms[0], "http_requests_total", true, []*pb.NumberDataPoint{
+		{
+			Attributes:   []*pb.KeyValue{newStringKeyValue("path", "/foo")},
+			TimeUnixNano: 1e9,
+			DoubleValue:  float64Ptr(10),
+		},
+		{
+			Attributes:   []*pb.KeyValue{newStringKeyValue("path", "/foo")},
+			TimeUnixNano: 2e9,
+			DoubleValue:  float64Ptr(12),
+		},
+		{
+			Attributes:   []*pb.KeyValue{newStringKeyValue("path", "/bar")},
+			TimeUnixNano: 1e9,
+			DoubleValue:  float64Ptr(5),
+		},
+	})
+	f(ms[1], "temperature", false, []*pb.NumberDataPoint{
+		{
+			TimeUnixNano: 3e9,
+			DoubleValue:  float64Ptr(math.NaN()),
+			Flags:        1,
+		},
+	})
+}
@@ -39,12 +39,13 @@ type pendingSeries struct {
 	periodicFlusherWG sync.WaitGroup
 }
 
-func newPendingSeries(fq *persistentqueue.FastQueue, isVMRemoteWrite bool, significantFigures, roundDigits int) *pendingSeries {
+func newPendingSeries(fq *persistentqueue.FastQueue, isVMRemoteWrite bool, significantFigures, roundDigits int, sp *samplesProcessor) *pendingSeries {
 	var ps pendingSeries
 	ps.wr.fq = fq
 	ps.wr.isVMRemoteWrite = isVMRemoteWrite
 	ps.wr.significantFigures = significantFigures
 	ps.wr.roundDigits = roundDigits
+	ps.wr.sp = sp
 	ps.stopCh = make(chan struct{})
 	ps.periodicFlusherWG.Add(1)
 	go func() {
@@ -107,6 +108,9 @@ type writeRequest struct {
 	// How many decimal digits after point must be left before sending the writeRequest to fq.
 	roundDigits int
 
+	// Optional processor for samples before sending the writeRequest to fq.
+	sp *samplesProcessor
+
 	wr prompbmarshal.WriteRequest
 
 	tss     []prompbmarshal.TimeSeries
@@ -118,7 +122,7 @@ type writeRequest struct {
 }
 
 func (wr *writeRequest) reset() {
-	// Do not reset lastFlushTime, fq, isVMRemoteWrite, significantFigures and roundDigits, since they are re-used.
+	// Do not reset lastFlushTime, fq, isVMRemoteWrite, significantFigures, roundDigits and sp, since they are re-used.
 
 	wr.wr.Timeseries = nil
 
@@ -190,6 +194,9 @@ func (wr *writeRequest) tryPush(src []prompbmarshal.TimeSeries) bool {
 		adjustSampleValues(tsSrc.Samples, wr.significantFigures, wr.roundDigits)
 		tssDst = append(tssDst, prompbmarshal.TimeSeries{})
 		wr.copyTimeSeries(&tssDst[len(tssDst)-1], tsSrc)
+		if wr.sp != nil && !wr.processSamples(&tssDst[len(tssDst)-1]) {
+			tssDst = tssDst[:len(tssDst)-1]
+		}
 	}
 
 	wr.tss = tssDst
@@ -221,6 +228,21 @@ func (wr *writeRequest) copyTimeSeries(dst, src *prompbmarshal.TimeSeries) {
 	wr.buf = buf
 }
 
+// processSamples processes samples for ts, which was just copied to wr, with wr.sp.
+//
+// Samples are processed in the copy, so the original time series isn't modified.
+// It returns false if all the samples were dropped. In this case the caller must drop ts.
+func (wr *writeRequest) processSamples(ts *prompbmarshal.TimeSeries) bool {
+	samplesLen := len(ts.Samples)
+	ts.Samples = wr.sp.process(ts.Samples)
+	wr.samples = wr.samples[:len(wr.samples)-(samplesLen-len(ts.Samples))]
+	if len(ts.Samples) > 0 {
+		return true
+	}
+	wr.labels = wr.labels[:len(wr.labels)-len(ts.Labels)]
+	return false
+}
+
 // marshalConcurrency limits the maximum number of concurrent workers, which marshal and compress WriteRequest.
 var marshalConcurrencyCh = make(chan struct{}, cgroup.AvailableCPUs())
 
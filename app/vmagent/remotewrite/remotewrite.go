@@ -93,6 +93,9 @@ var (
 		"See https://docs.victoriametrics.com/vmagent#disabling-on-disk-persistence . See also -remoteWrite.dropSamplesOnOverload")
 	dropSamplesOnOverload = flag.Bool("remoteWrite.dropSamplesOnOverload", false, "Whether to drop samples when -remoteWrite.disableOnDiskQueue is set and if the samples "+
 		"cannot be pushed into the configured -remoteWrite.url systems in a timely manner. See https://docs.victoriametrics.com/vmagent#disabling-on-disk-persistence")
+	drainTimeout = flag.Duration("remoteWrite.drainTimeout", 0, "The maximum duration to wait on graceful shutdown until the pending data is sent to the configured -remoteWrite.url systems. "+
+		"The data, which couldn't be sent during this time, remains at -remoteWrite.tmpDataPath and is sent after the restart. "+
+		"The data is dropped if -remoteWrite.disableOnDiskQueue is set. See https://docs.victoriametrics.com/vmagent/#graceful-shutdown")
 )
 
 var (
@@ -376,8 +379,10 @@ func Stop() {
 		deduplicatorGlobal = nil
 	}
 
+	// All the rwctxs share the same drain deadline, since they send the pending data concurrently.
+	drainDeadline := time.Now().Add(*drainTimeout)
 	for _, rwctx := range rwctxsGlobal {
-		rwctx.MustStop()
+		rwctx.MustStop(drainDeadline)
 	}
 	rwctxsGlobal = nil
 
@@ -395,7 +400,7 @@ func Stop() {
 //
 // PushDropSamplesOnFailure can modify wr contents.
 func PushDropSamplesOnFailure(at *auth.Token, wr *prompbmarshal.WriteRequest) {
-	_ = tryPush(at, wr, true)
+	_, _, _ = tryPush(at, wr, true)
 }
 
 // TryPush tries sending wr to the configured remote storage systems set via -remoteWrite.url
@@ -405,10 +410,19 @@ func PushDropSamplesOnFailure(at *auth.Token, wr *prompbmarshal.WriteRequest) {
 //
 // The caller must return ErrQueueFullHTTPRetry to the client, which sends wr, if TryPush returns false.
 func TryPush(at *auth.Token, wr *prompbmarshal.WriteRequest) bool {
+	ok, _, _ := tryPush(at, wr, dropSamplesOnFailureGlobal)
+	return ok
+}
+
+// TryPushExt works the same as TryPush, but also returns the number of samples from wr, which were dropped
+// because of -remoteWrite.maxHourlySeries and -remoteWrite.maxDailySeries limits or because all the remote storage queues are blocked.
+//
+// The reason for the first drop is returned additionally to the number of dropped samples.
+func TryPushExt(at *auth.Token, wr *prompbmarshal.WriteRequest) (bool, int, string) {
 	return tryPush(at, wr, dropSamplesOnFailureGlobal)
 }
 
-func tryPush(at *auth.Token, wr *prompbmarshal.WriteRequest, forceDropSamplesOnFailure bool) bool {
+func tryPush(at *auth.Token, wr *prompbmarshal.WriteRequest, forceDropSamplesOnFailure bool) (bool, int, string) {
 	tss := wr.Timeseries
 
 	if at == nil && MultitenancyEnabled() {
@@ -430,12 +444,12 @@ func tryPush(at *auth.Token, wr *prompbmarshal.WriteRequest, forceDropSamplesOnF
 	if !ok {
 		// At least a single remote write queue is blocked and dropSamplesOnFailure isn't set.
 		// Return false to the caller, so it could re-send samples again.
-		return false
+		return false, 0, ""
 	}
 	if len(rwctxs) == 0 {
 		// All the remote write queues are skipped because they are blocked and dropSamplesOnFailure is set to true.
 		// Return true to the caller, so it doesn't re-send the samples again.
-		return true
+		return true, getRowsCount(tss), "all the remote storage queues are blocked"
 	}
 
 	var rctx *relabelCtx
@@ -453,6 +467,8 @@ func tryPush(at *auth.Token, wr *prompbmarshal.WriteRequest, forceDropSamplesOnF
 
 	sas := sasGlobal.Load()
 
+	rowsDropped := 0
+	dropReason := ""
 	for len(tss) > 0 {
 		// Process big tss in smaller blocks in order to reduce the maximum memory usage
 		samplesCount := 0
@@ -486,7 +502,13 @@ func tryPush(at *auth.Token, wr *prompbmarshal.WriteRequest, forceDropSamplesOnF
 			rowsDroppedByGlobalRelabel.Add(rowsCountBeforeRelabel - rowsCountAfterRelabel)
 		}
 		sortLabelsIfNeeded(tssBlock)
-		tssBlock = limitSeriesCardinality(tssBlock)
+		tssBlock, n, reason := limitSeriesCardinality(tssBlock)
+		if n > 0 {
+			if rowsDropped == 0 {
+				dropReason = reason
+			}
+			rowsDropped += n
+		}
 		if sas.IsEnabled() {
 			matchIdxs := matchIdxsPool.Get()
 			matchIdxs.B = sas.Push(tssBlock, matchIdxs.B)
@@ -499,10 +521,10 @@ func tryPush(at *auth.Token, wr *prompbmarshal.WriteRequest, forceDropSamplesOnF
 			tssBlock = tssBlock[:0]
 		}
 		if !tryPushBlockToRemoteStorages(rwctxs, tssBlock, forceDropSamplesOnFailure) {
-			return false
+			return false, 0, ""
 		}
 	}
-	return true
+	return true, rowsDropped, dropReason
 }
 
 func getEligibleRemoteWriteCtxs(tss []prompbmarshal.TimeSeries, forceDropSamplesOnFailure bool) ([]*remoteWriteCtx, bool) {
@@ -678,27 +700,40 @@ func sortLabelsIfNeeded(tss []prompbmarshal.TimeSeries) {
 	}
 }
 
-func limitSeriesCardinality(tss []prompbmarshal.TimeSeries) []prompbmarshal.TimeSeries {
+// limitSeriesCardinality drops series exceeding -remoteWrite.maxHourlySeries and -remoteWrite.maxDailySeries limits from tss.
+//
+// It returns the remaining series and the number of dropped samples together with the reason for the first drop.
+func limitSeriesCardinality(tss []prompbmarshal.TimeSeries) ([]prompbmarshal.TimeSeries, int, string) {
 	if hourlySeriesLimiter == nil && dailySeriesLimiter == nil {
-		return tss
+		return tss, 0, ""
 	}
 	dst := make([]prompbmarshal.TimeSeries, 0, len(tss))
+	rowsDropped := 0
+	dropReason := ""
 	for i := range tss {
 		labels := tss[i].Labels
 		h := getLabelsHash(labels)
 		if hourlySeriesLimiter != nil && !hourlySeriesLimiter.Add(h) {
 			hourlySeriesLimitRowsDropped.Add(len(tss[i].Samples))
 			logSkippedSeries(labels, "-remoteWrite.maxHourlySeries", hourlySeriesLimiter.MaxItems())
+			if rowsDropped == 0 {
+				dropReason = "the series exceeds -remoteWrite.maxHourlySeries limit"
+			}
+			rowsDropped += len(tss[i].Samples)
 			continue
 		}
 		if dailySeriesLimiter != nil && !dailySeriesLimiter.Add(h) {
 			dailySeriesLimitRowsDropped.Add(len(tss[i].Samples))
 			logSkippedSeries(labels, "-remoteWrite.maxDailySeries", dailySeriesLimiter.MaxItems())
+			if rowsDropped == 0 {
+				dropReason = "the series exceeds -remoteWrite.maxDailySeries limit"
+			}
+			rowsDropped += len(tss[i].Samples)
 			continue
 		}
 		dst = append(dst, tss[i])
 	}
-	return dst
+	return dst, rowsDropped, dropReason
 }
 
 var (
@@ -824,9 +859,11 @@ func newRemoteWriteCtx(argIdx int, remoteWriteURL *url.URL, maxInmemoryBlocks in
 		// since every pendingSeries can saturate up to a single CPU.
 		pssLen = n
 	}
+	tooOldSamplesDropped := metrics.GetOrCreateCounter(fmt.Sprintf(`vmagent_remotewrite_too_old_samples_dropped_total{path=%q,url=%q}`, queuePath, sanitizedURL))
+	sp := newSamplesProcessor(argIdx, tooOldSamplesDropped)
 	pss := make([]*pendingSeries, pssLen)
 	for i := range pss {
-		pss[i] = newPendingSeries(fq, c.useVMProto, sf, rd)
+		pss[i] = newPendingSeries(fq, c.useVMProto, sf, rd, sp)
 	}
 
 	rwctx := &remoteWriteCtx{
@@ -846,7 +883,10 @@ func newRemoteWriteCtx(argIdx int, remoteWriteURL *url.URL, maxInmemoryBlocks in
 	return rwctx
 }
 
-func (rwctx *remoteWriteCtx) MustStop() {
+// MustStop stops rwctx.
+//
+// It waits until the pending data is sent to the remote storage until the drainDeadline.
+func (rwctx *remoteWriteCtx) MustStop(drainDeadline time.Time) {
 	// sas and deduplicator must be stopped before rwctx is closed
 	// because they can write pending series to rwctx.pss if there are any
 	sas := rwctx.sas.Swap(nil)
@@ -862,6 +902,7 @@ func (rwctx *remoteWriteCtx) MustStop() {
 	}
 	rwctx.idx = 0
 	rwctx.pss = nil
+	rwctx.waitForDrain(drainDeadline)
 	rwctx.fq.UnblockAllReaders()
 	rwctx.c.MustStop()
 	rwctx.c = nil
@@ -873,6 +914,37 @@ func (rwctx *remoteWriteCtx) MustStop() {
 	rwctx.rowsDroppedByRelabel = nil
 }
 
+// waitForDrain waits until all the pending data at rwctx.fq is sent to the remote storage or until the deadline.
+func (rwctx *remoteWriteCtx) waitForDrain(deadline time.Time) {
+	pendingBytes := rwctx.fq.GetPendingBytes()
+	if pendingBytes == 0 || !time.Now().Before(deadline) {
+		return
+	}
+	logger.Infof("waiting for sending %d bytes of pending data to -remoteWrite.url=%q until -remoteWrite.drainTimeout=%s deadline",
+		pendingBytes, rwctx.c.sanitizedURL, *drainTimeout)
+	startTime := time.Now()
+	t := time.NewTicker(100 * time.Millisecond)
+	defer t.Stop()
+	for {
+		pendingBytes = rwctx.fq.GetPendingBytes()
+		if pendingBytes == 0 {
+			logger.Infof("sent all the pending data to -remoteWrite.url=%q in %.3f seconds", rwctx.c.sanitizedURL, time.Since(startTime).Seconds())
+			return
+		}
+		if !time.Now().Before(deadline) {
+			if rwctx.fq.IsPersistentQueueDisabled() {
+				logger.Warnf("dropping %d bytes of pending data for -remoteWrite.url=%q, since it couldn't be sent during -remoteWrite.drainTimeout=%s "+
+					"and -remoteWrite.disableOnDiskQueue is set", pendingBytes, rwctx.c.sanitizedURL, *drainTimeout)
+			} else {
+				logger.Warnf("couldn't send %d bytes of pending data to -remoteWrite.url=%q during -remoteWrite.drainTimeout=%s; "+
+					"the data will be sent after the restart", pendingBytes, rwctx.c.sanitizedURL, *drainTimeout)
+			}
+			return
+		}
+		<-t.C
+	}
+}
+
 // TryPush sends tss series to the configured remote write endpoint
 //
 // TryPush doesn't modify tss, so tss can be passed concurrently to TryPush across distinct rwctx instances.
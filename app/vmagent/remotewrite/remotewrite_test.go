@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/persistentqueue"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promrelabel"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/streamaggr"
@@ -67,7 +68,7 @@ func TestRemoteWriteContext_TryPush_ImmutableTimeseries(t *testing.T) {
 		allRelabelConfigs.Store(rcs)
 
 		pss := make([]*pendingSeries, 1)
-		pss[0] = newPendingSeries(nil, true, 0, 100)
+		pss[0] = newPendingSeries(nil, true, 0, 100, nil)
 		rwctx := &remoteWriteCtx{
 			idx:                    0,
 			streamAggrKeepInput:    keepInput,
@@ -167,3 +168,39 @@ metric{env="dev"} 15
 metric{env="bar"} 25
 `)
 }
+
+func TestRemoteWriteContext_WaitForDrain(t *testing.T) {
+	f := func(readBlocks bool, pendingBytesExpected uint64) {
+		t.Helper()
+
+		fq := persistentqueue.MustOpenFastQueue(t.TempDir(), "test", 10, 0, true)
+		defer fq.MustClose()
+		rwctx := &remoteWriteCtx{
+			fq: fq,
+			c: &client{
+				sanitizedURL: "test",
+			},
+		}
+		if !fq.TryWriteBlock([]byte("foobar")) {
+			t.Fatalf("cannot write block to the queue")
+		}
+
+		if readBlocks {
+			go func() {
+				// Simulate the client, which sends the pending data to the remote storage.
+				time.Sleep(50 * time.Millisecond)
+				_, _ = fq.MustReadBlock(nil)
+			}()
+		}
+		rwctx.waitForDrain(time.Now().Add(time.Second))
+		if pendingBytes := fq.GetPendingBytes(); pendingBytes != pendingBytesExpected {
+			t.Fatalf("unexpected pending bytes after waitForDrain; got %d; want %d", pendingBytes, pendingBytesExpected)
+		}
+	}
+
+	// the pending data is sent before the deadline
+	f(true, 0)
+
+	// the pending data couldn't be sent until the deadline
+	f(false, 6)
+}
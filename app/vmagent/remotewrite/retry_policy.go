@@ -0,0 +1,150 @@
+package remotewrite
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fs"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+)
+
+var (
+	retryStatusCodes = flagutil.NewArrayString("remoteWrite.retryStatusCodes", "Optional list of HTTP status codes, which must be retried "+
+		"when returned by the corresponding -remoteWrite.url. Status codes must be delimited by ';'. Ranges are supported, e.g. '429;500-599'. "+
+		"Data blocks rejected with other non-2xx status codes are dropped or are written to -remoteWrite.deadLetterDir. "+
+		"By default data blocks rejected with 400 and 409 status codes are dropped, while all the other status codes are retried. "+
+		"See https://docs.victoriametrics.com/vmagent/#retry-policy")
+	maxRetryDuration = flagutil.NewArrayDuration("remoteWrite.maxRetryDuration", 0, "The maximum duration for retrying to send a block of data "+
+		"to the corresponding -remoteWrite.url. The block is dropped or is written to -remoteWrite.deadLetterDir after this duration. "+
+		"By default the block is retried until it is accepted by the remote storage. See https://docs.victoriametrics.com/vmagent/#retry-policy")
+	deadLetterDir = flagutil.NewArrayString("remoteWrite.deadLetterDir", "Optional path to directory for storing data blocks, which were permanently rejected "+
+		"by the corresponding -remoteWrite.url. Such blocks are dropped if this flag isn't set. See https://docs.victoriametrics.com/vmagent/#retry-policy")
+)
+
+// retryPolicy determines how to handle data blocks, which couldn't be sent to remote storage.
+type retryPolicy struct {
+	// retryStatusCodes contains status code ranges, which must be retried.
+	//
+	// If it is empty, then all the status codes except of 400 and 409 are retried.
+	retryStatusCodes []statusCodeRange
+
+	// maxRetryDuration is the maximum duration for retrying a single block. Zero means retrying indefinitely.
+	maxRetryDuration time.Duration
+
+	// deadLetterDir is the directory for permanently rejected blocks. Such blocks are dropped if it is empty.
+	deadLetterDir string
+}
+
+type statusCodeRange struct {
+	min int
+	max int
+}
+
+func getRetryPolicy(argIdx int) (*retryPolicy, error) {
+	s := retryStatusCodes.GetOptionalArg(argIdx)
+	codes, err := parseStatusCodeRanges(s)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse -remoteWrite.retryStatusCodes=%q: %w", s, err)
+	}
+	dir := deadLetterDir.GetOptionalArg(argIdx)
+	if dir != "" {
+		fs.MustMkdirIfNotExist(dir)
+	}
+	rp := &retryPolicy{
+		retryStatusCodes: codes,
+		maxRetryDuration: maxRetryDuration.GetOptionalArg(argIdx),
+		deadLetterDir:    dir,
+	}
+	return rp, nil
+}
+
+func parseStatusCodeRanges(s string) ([]statusCodeRange, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var ranges []statusCodeRange
+	for _, item := range strings.Split(s, ";") {
+		item = strings.TrimSpace(item)
+		minStr, maxStr, isRange := strings.Cut(item, "-")
+		minCode, err := parseStatusCode(minStr)
+		if err != nil {
+			return nil, err
+		}
+		maxCode := minCode
+		if isRange {
+			maxCode, err = parseStatusCode(maxStr)
+			if err != nil {
+				return nil, err
+			}
+			if maxCode < minCode {
+				return nil, fmt.Errorf("the upper bound cannot be smaller than the lower bound in the range %q", item)
+			}
+		}
+		ranges = append(ranges, statusCodeRange{
+			min: minCode,
+			max: maxCode,
+		})
+	}
+	return ranges, nil
+}
+
+func parseStatusCode(s string) (int, error) {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("cannot parse status code %q: %w", s, err)
+	}
+	if n < 100 || n > 599 {
+		return 0, fmt.Errorf("status code %d must be in the range [100...599]", n)
+	}
+	return n, nil
+}
+
+// isRetryableStatusCode returns true if the block rejected with the given non-2xx statusCode must be retried.
+func (rp *retryPolicy) isRetryableStatusCode(statusCode int) bool {
+	if len(rp.retryStatusCodes) == 0 {
+		// Just drop block on 409 and 400 status codes like Prometheus does.
+		// See https://github.com/VictoriaMetrics/VictoriaMetrics/issues/873
+		// and https://github.com/VictoriaMetrics/VictoriaMetrics/issues/1149
+		return statusCode != 409 && statusCode != 400
+	}
+	for _, r := range rp.retryStatusCodes {
+		if statusCode >= r.min && statusCode <= r.max {
+			return true
+		}
+	}
+	return false
+}
+
+// isRetryDurationExceeded returns true if the block, which is sent since startTime, mustn't be retried anymore.
+func (rp *retryPolicy) isRetryDurationExceeded(startTime time.Time) bool {
+	return rp.maxRetryDuration > 0 && time.Since(startTime) >= rp.maxRetryDuration
+}
+
+// rejectBlock drops the given block or writes it to c.retryPolicy.deadLetterDir if it is set.
+func (c *client) rejectBlock(block []byte) {
+	c.packetsDropped.Inc()
+	dir := c.retryPolicy.deadLetterDir
+	if dir == "" {
+		return
+	}
+	ext := "snappy"
+	if c.useVMProto {
+		ext = "zstd"
+	}
+	name := fmt.Sprintf("%016X_%016X.%s", time.Now().UnixNano(), deadLetterBlockIdx.Add(1), ext)
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, block, 0o600); err != nil {
+		logger.Errorf("cannot write data block rejected by %q to -remoteWrite.deadLetterDir: %s; dropping the block", c.sanitizedURL, err)
+		return
+	}
+	c.deadLetterBlocks.Inc()
+}
+
+// deadLetterBlockIdx is used for generating unique file names for blocks written to -remoteWrite.deadLetterDir.
+var deadLetterBlockIdx atomic.Uint64
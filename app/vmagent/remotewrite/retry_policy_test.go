@@ -0,0 +1,157 @@
+package remotewrite
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promauth"
+	"github.com/VictoriaMetrics/metrics"
+)
+
+func TestParseStatusCodeRangesSuccess(t *testing.T) {
+	f := func(s string, resultExpected []statusCodeRange) {
+		t.Helper()
+
+		result, err := parseStatusCodeRanges(s)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(result, resultExpected) {
+			t.Fatalf("unexpected result\ngot\n%v\nwant\n%v", result, resultExpected)
+		}
+	}
+
+	f("", nil)
+	f("429", []statusCodeRange{{min: 429, max: 429}})
+	f("429; 500-599", []statusCodeRange{{min: 429, max: 429}, {min: 500, max: 599}})
+}
+
+func TestParseStatusCodeRangesFailure(t *testing.T) {
+	f := func(s string) {
+		t.Helper()
+
+		if _, err := parseStatusCodeRanges(s); err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	f(";")
+	f("foo")
+	f("42")
+	f("500-")
+	f("500-600")
+	f("599-500")
+}
+
+func TestRetryPolicyIsRetryableStatusCode(t *testing.T) {
+	f := func(retryStatusCodes string, statusCode int, resultExpected bool) {
+		t.Helper()
+
+		codes, err := parseStatusCodeRanges(retryStatusCodes)
+		if err != nil {
+			t.Fatalf("cannot parse status codes: %s", err)
+		}
+		rp := &retryPolicy{
+			retryStatusCodes: codes,
+		}
+		if result := rp.isRetryableStatusCode(statusCode); result != resultExpected {
+			t.Fatalf("unexpected result for status code %d; got %v; want %v", statusCode, result, resultExpected)
+		}
+	}
+
+	// default policy
+	f("", 400, false)
+	f("", 409, false)
+	f("", 403, true)
+	f("", 429, true)
+	f("", 503, true)
+
+	// custom policy
+	f("429;500-599", 429, true)
+	f("429;500-599", 503, true)
+	f("429;500-599", 403, false)
+	f("429;500-599", 400, false)
+}
+
+func TestClientSendBlockHTTPRetryPolicy(t *testing.T) {
+	f := func(statusCode int, rp *retryPolicy, requestsExpected, deadLetterFilesExpected int) {
+		t.Helper()
+
+		requests := 0
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			requests++
+			w.WriteHeader(statusCode)
+		}))
+		defer srv.Close()
+
+		authCfg, err := (&promauth.Options{}).NewConfig()
+		if err != nil {
+			t.Fatalf("cannot create auth config: %s", err)
+		}
+		s := metrics.NewSet()
+		c := &client{
+			sanitizedURL:     "test",
+			remoteWriteURL:   srv.URL,
+			hc:               srv.Client(),
+			authCfg:          authCfg,
+			retryMinInterval: time.Millisecond,
+			retryMaxTime:     time.Millisecond,
+			retryPolicy:      rp,
+			stopCh:           make(chan struct{}),
+			requestDuration:  s.NewHistogram(`test_request_duration`),
+			errorsCount:      s.NewCounter(`test_errors`),
+			packetsDropped:   s.NewCounter(`test_packets_dropped`),
+			deadLetterBlocks: s.NewCounter(`test_dead_letter_blocks`),
+			retriesCount:     s.NewCounter(`test_retries`),
+		}
+
+		if !c.sendBlockHTTP([]byte("foobar")) {
+			t.Fatalf("unexpected sendBlockHTTP result")
+		}
+		if requests != requestsExpected {
+			t.Fatalf("unexpected number of requests; got %d; want %d", requests, requestsExpected)
+		}
+		if n := int(c.packetsDropped.Get()); n != 1 {
+			t.Fatalf("unexpected number of dropped blocks; got %d; want 1", n)
+		}
+		if rp.deadLetterDir == "" {
+			return
+		}
+		matches, err := filepath.Glob(filepath.Join(rp.deadLetterDir, "*.snappy"))
+		if err != nil {
+			t.Fatalf("cannot read dead-letter dir: %s", err)
+		}
+		if len(matches) != deadLetterFilesExpected {
+			t.Fatalf("unexpected number of files at dead-letter dir; got %d; want %d", len(matches), deadLetterFilesExpected)
+		}
+		for _, path := range matches {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("cannot read %q: %s", path, err)
+			}
+			if string(data) != "foobar" {
+				t.Fatalf("unexpected block contents at %q; got %q; want %q", path, data, "foobar")
+			}
+		}
+	}
+
+	// permanent error with the default policy
+	f(http.StatusBadRequest, &retryPolicy{}, 1, 0)
+
+	// status code isn't listed at retryStatusCodes
+	f(http.StatusForbidden, &retryPolicy{
+		retryStatusCodes: []statusCodeRange{{min: 429, max: 429}},
+		deadLetterDir:    t.TempDir(),
+	}, 1, 1)
+
+	// retryable status code with exceeded maxRetryDuration
+	f(http.StatusServiceUnavailable, &retryPolicy{
+		maxRetryDuration: time.Nanosecond,
+		deadLetterDir:    t.TempDir(),
+	}, 1, 1)
+}
@@ -0,0 +1,83 @@
+package remotewrite
+
+import (
+	"sort"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+)
+
+var (
+	roundTimestamps = flagutil.NewArrayDuration("remoteWrite.roundTimestamps", 0, "Optional duration for rounding sample timestamps "+
+		"before sending them to the corresponding -remoteWrite.url. For example, -remoteWrite.roundTimestamps=1s rounds timestamps to seconds. "+
+		"This option may be used for improving data compression for the stored timestamps. By default timestamps aren't rounded. "+
+		"See https://docs.victoriametrics.com/vmagent/#adjusting-samples-before-sending")
+	sortSamples = flagutil.NewArrayBool("remoteWrite.sortSamples", "Whether to sort samples by timestamp for every time series "+
+		"before sending them to the corresponding -remoteWrite.url. This may be needed for remote storage systems, which do not accept out-of-order samples. "+
+		"See https://docs.victoriametrics.com/vmagent/#adjusting-samples-before-sending")
+	maxSampleAge = flagutil.NewArrayDuration("remoteWrite.maxSampleAge", 0, "Optional maximum age for samples sent to the corresponding -remoteWrite.url. "+
+		"Older samples are dropped. This may be useful for remote storage systems, which reject too old samples. By default samples of any age are sent. "+
+		"See https://docs.victoriametrics.com/vmagent/#adjusting-samples-before-sending")
+)
+
+// samplesProcessor adjusts sample timestamps and drops too old samples before sending them to remote storage.
+type samplesProcessor struct {
+	// roundTimestampsMsecs is the interval in milliseconds for rounding sample timestamps.
+	roundTimestampsMsecs int64
+
+	// sortSamples is set to true if samples must be sorted by timestamp.
+	sortSamples bool
+
+	// maxSampleAgeMsecs is the maximum age in milliseconds for samples to send.
+	maxSampleAgeMsecs int64
+
+	tooOldSamplesDropped *metrics.Counter
+}
+
+// newSamplesProcessor returns samplesProcessor for -remoteWrite.url at argIdx.
+//
+// nil is returned if samples mustn't be processed.
+func newSamplesProcessor(argIdx int, tooOldSamplesDropped *metrics.Counter) *samplesProcessor {
+	sp := &samplesProcessor{
+		roundTimestampsMsecs: roundTimestamps.GetOptionalArg(argIdx).Milliseconds(),
+		sortSamples:          sortSamples.GetOptionalArg(argIdx),
+		maxSampleAgeMsecs:    maxSampleAge.GetOptionalArg(argIdx).Milliseconds(),
+		tooOldSamplesDropped: tooOldSamplesDropped,
+	}
+	if sp.roundTimestampsMsecs <= 0 && !sp.sortSamples && sp.maxSampleAgeMsecs <= 0 {
+		return nil
+	}
+	return sp
+}
+
+// process processes samples in place and returns the result.
+//
+// The returned samples may be shorter than the original samples if some of them were dropped.
+func (sp *samplesProcessor) process(samples []prompbmarshal.Sample) []prompbmarshal.Sample {
+	if sp.maxSampleAgeMsecs > 0 {
+		minTimestamp := time.Now().UnixMilli() - sp.maxSampleAgeMsecs
+		dst := samples[:0]
+		for _, s := range samples {
+			if s.Timestamp >= minTimestamp {
+				dst = append(dst, s)
+			}
+		}
+		sp.tooOldSamplesDropped.Add(len(samples) - len(dst))
+		samples = dst
+	}
+	if d := sp.roundTimestampsMsecs; d > 0 {
+		for i := range samples {
+			s := &samples[i]
+			s.Timestamp = ((s.Timestamp + d/2) / d) * d
+		}
+	}
+	if sp.sortSamples && !sort.SliceIsSorted(samples, func(i, j int) bool { return samples[i].Timestamp < samples[j].Timestamp }) {
+		sort.SliceStable(samples, func(i, j int) bool {
+			return samples[i].Timestamp < samples[j].Timestamp
+		})
+	}
+	return samples
+}
@@ -0,0 +1,120 @@
+package remotewrite
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+)
+
+func TestSamplesProcessorProcess(t *testing.T) {
+	f := func(sp *samplesProcessor, samples, resultExpected []prompbmarshal.Sample, droppedExpected uint64) {
+		t.Helper()
+
+		sp.tooOldSamplesDropped = metrics.NewSet().NewCounter(`too_old_samples_dropped`)
+		result := sp.process(samples)
+		if !reflect.DeepEqual(result, resultExpected) {
+			t.Fatalf("unexpected result\ngot\n%v\nwant\n%v", result, resultExpected)
+		}
+		if n := sp.tooOldSamplesDropped.Get(); n != droppedExpected {
+			t.Fatalf("unexpected number of dropped samples; got %d; want %d", n, droppedExpected)
+		}
+	}
+
+	// round timestamps
+	f(&samplesProcessor{
+		roundTimestampsMsecs: 1000,
+	}, []prompbmarshal.Sample{
+		{Value: 1, Timestamp: 1234},
+		{Value: 2, Timestamp: 1500},
+		{Value: 3, Timestamp: 2499},
+	}, []prompbmarshal.Sample{
+		{Value: 1, Timestamp: 1000},
+		{Value: 2, Timestamp: 2000},
+		{Value: 3, Timestamp: 2000},
+	}, 0)
+
+	// sort samples
+	f(&samplesProcessor{
+		sortSamples: true,
+	}, []prompbmarshal.Sample{
+		{Value: 3, Timestamp: 3000},
+		{Value: 1, Timestamp: 1000},
+		{Value: 2, Timestamp: 2000},
+	}, []prompbmarshal.Sample{
+		{Value: 1, Timestamp: 1000},
+		{Value: 2, Timestamp: 2000},
+		{Value: 3, Timestamp: 3000},
+	}, 0)
+
+	// drop too old samples
+	now := time.Now().UnixMilli()
+	f(&samplesProcessor{
+		maxSampleAgeMsecs: time.Hour.Milliseconds(),
+	}, []prompbmarshal.Sample{
+		{Value: 1, Timestamp: now - 2*time.Hour.Milliseconds()},
+		{Value: 2, Timestamp: now - time.Minute.Milliseconds()},
+		{Value: 3, Timestamp: now - 3*time.Hour.Milliseconds()},
+	}, []prompbmarshal.Sample{
+		{Value: 2, Timestamp: now - time.Minute.Milliseconds()},
+	}, 2)
+
+	// all the samples are too old
+	f(&samplesProcessor{
+		maxSampleAgeMsecs: time.Hour.Milliseconds(),
+	}, []prompbmarshal.Sample{
+		{Value: 1, Timestamp: now - 2*time.Hour.Milliseconds()},
+	}, []prompbmarshal.Sample{}, 1)
+}
+
+func TestWriteRequestTryPushProcessSamples(t *testing.T) {
+	now := time.Now().UnixMilli()
+	sp := &samplesProcessor{
+		maxSampleAgeMsecs:    time.Hour.Milliseconds(),
+		tooOldSamplesDropped: metrics.NewSet().NewCounter(`too_old_samples_dropped`),
+	}
+	var wr writeRequest
+	wr.roundDigits = 100
+	wr.sp = sp
+	src := []prompbmarshal.TimeSeries{
+		{
+			Labels: []prompbmarshal.Label{{Name: "__name__", Value: "too_old"}},
+			Samples: []prompbmarshal.Sample{
+				{Value: 1, Timestamp: now - 2*time.Hour.Milliseconds()},
+			},
+		},
+		{
+			Labels: []prompbmarshal.Label{{Name: "__name__", Value: "fresh"}},
+			Samples: []prompbmarshal.Sample{
+				{Value: 2, Timestamp: now - 2*time.Hour.Milliseconds()},
+				{Value: 3, Timestamp: now},
+			},
+		},
+	}
+	if !wr.tryPush(src) {
+		t.Fatalf("unexpected tryPush failure")
+	}
+
+	tssExpected := []prompbmarshal.TimeSeries{
+		{
+			Labels: []prompbmarshal.Label{{Name: "__name__", Value: "fresh"}},
+			Samples: []prompbmarshal.Sample{
+				{Value: 3, Timestamp: now},
+			},
+		},
+	}
+	if !reflect.DeepEqual(wr.tss, tssExpected) {
+		t.Fatalf("unexpected time series\ngot\n%v\nwant\n%v", wr.tss, tssExpected)
+	}
+	if len(wr.labels) != 1 || len(wr.samples) != 1 {
+		t.Fatalf("unexpected buffers; got %d labels and %d samples; want 1 label and 1 sample", len(wr.labels), len(wr.samples))
+	}
+
+	// The original samples mustn't be modified
+	if len(src[1].Samples) != 2 || src[1].Samples[1].Value != 3 {
+		t.Fatalf("unexpected modification of the original samples: %v", src[1].Samples)
+	}
+}
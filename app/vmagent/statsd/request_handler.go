@@ -0,0 +1,54 @@
+package statsd
+
+import (
+	"io"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmagent/remotewrite"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+	parser "github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/statsd"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/statsd/stream"
+	"github.com/VictoriaMetrics/metrics"
+)
+
+var (
+	rowsInserted  = metrics.NewCounter(`vmagent_rows_inserted_total{type="statsd"}`)
+	rowsPerInsert = metrics.NewHistogram(`vmagent_rows_per_insert{type="statsd"}`)
+)
+
+var aggregator *parser.Aggregator
+
+// Init initializes the aggregation of StatsD data.
+//
+// MustStop must be called when the StatsD data is no longer received.
+func Init() {
+	aggregator = stream.NewAggregator(pushTimeSeries)
+}
+
+// MustStop stops the aggregation of StatsD data and sends the remaining aggregated samples to remote storage.
+func MustStop() {
+	aggregator.MustStop()
+	aggregator = nil
+}
+
+// InsertHandler processes StatsD data.
+//
+// See https://github.com/statsd/statsd/blob/master/docs/metric_types.md
+func InsertHandler(r io.Reader) error {
+	return stream.Parse(r, func(rows []parser.Row) error {
+		aggregator.Push(rows)
+		return nil
+	})
+}
+
+func pushTimeSeries(tss []prompbmarshal.TimeSeries) {
+	rowsTotal := 0
+	for i := range tss {
+		rowsTotal += len(tss[i].Samples)
+	}
+	wr := &prompbmarshal.WriteRequest{
+		Timeseries: tss,
+	}
+	remotewrite.PushDropSamplesOnFailure(nil, wr)
+	rowsInserted.Add(rowsTotal)
+	rowsPerInsert.Update(float64(rowsTotal))
+}
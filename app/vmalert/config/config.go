@@ -140,6 +140,12 @@ type Rule struct {
 	// UpdateEntriesLimit defines max number of rule's state updates stored in memory.
 	// Overrides `-rule.updateEntriesLimit`.
 	UpdateEntriesLimit *int `yaml:"update_entries_limit,omitempty"`
+	// Optional HTTP URL parameters added to each rule request.
+	// Params have priority over the group params with the same name.
+	Params url.Values `yaml:"params,omitempty"`
+	// Headers contains optional HTTP headers added to each rule request.
+	// Headers have priority over the group headers with the same key.
+	Headers []Header `yaml:"headers,omitempty"`
 
 	// Catches all undefined fields and must be empty after parsing.
 	XXX map[string]any `yaml:",inline"`
@@ -257,6 +263,13 @@ func Parse(pathPatterns []string, validateTplFn ValidateTplFn, validateExpressio
 	return groups, nil
 }
 
+// ParseBytes parses rule configs from data.
+//
+// name is used as File for the parsed groups.
+func ParseBytes(name string, data []byte, validateTplFn ValidateTplFn, validateExpressions bool) ([]Group, error) {
+	return parse(map[string][]byte{name: data}, validateTplFn, validateExpressions)
+}
+
 func parse(files map[string][]byte, validateTplFn ValidateTplFn, validateExpressions bool) ([]Group, error) {
 	errGroup := new(utils.ErrGroup)
 	var groups []Group
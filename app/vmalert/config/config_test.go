@@ -613,4 +613,29 @@ rules:
     expr: sum by(job) (up == 1)
 `, url.Values{"nocache": {"1"}, "denyPartialResponse": {"true"}})
 	})
+	t.Run("rule params", func(t *testing.T) {
+		var g Group
+		data := `
+name: TestGroup
+params:
+  nocache: ["1"]
+rules:
+  - alert: ExampleAlertAlwaysFiring
+    expr: sum by(job) (up == 1)
+    params:
+      extra_label: ["env=dev"]
+    headers:
+      - "TenantID: foo"
+`
+		if err := yaml.Unmarshal([]byte(data), &g); err != nil {
+			t.Fatalf("failed to unmarshal: %s", err)
+		}
+		r := g.Rules[0]
+		if got, exp := r.Params.Encode(), "extra_label=env%3Ddev"; got != exp {
+			t.Fatalf("expected to have %q; got %q", exp, got)
+		}
+		if len(r.Headers) != 1 || r.Headers[0].Key != "TenantID" || r.Headers[0].Value != "foo" {
+			t.Fatalf("unexpected rule headers: %v", r.Headers)
+		}
+	})
 }
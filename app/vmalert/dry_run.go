@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmalert/config"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmalert/notifier"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmalert/rule"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/httpserver"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+)
+
+// dryRunFile is used as a file name for groups passed to dry run API.
+//
+// It prevents from clashing metrics of dry run groups with metrics of groups loaded from -rule files.
+const dryRunFile = "dry_run"
+
+// maxDryRunRequestSize limits the size of rule groups config passed to dry run API.
+const maxDryRunRequestSize = 4 * 1024 * 1024
+
+type dryRunResponse struct {
+	Status string `json:"status"`
+	Data   struct {
+		Groups []apiDryRunGroup `json:"groups"`
+	} `json:"data"`
+}
+
+// apiDryRunGroup contains results of a single dry run evaluation of the group
+type apiDryRunGroup struct {
+	apiGroup
+	// Series contains time series produced by the group rules.
+	// For alerting rules these are ALERTS and ALERTS_FOR_STATE series.
+	Series []apiSeries `json:"series"`
+}
+
+// apiSeries represents a single time series produced by rule evaluation
+type apiSeries struct {
+	Labels map[string]string `json:"labels"`
+	// Value is a string in order to support NaN values
+	Value     string `json:"value"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// dryRun validates rule groups config from r body, evaluates them once at the current time
+// against the configured datasource and returns the produced series and alerts.
+//
+// Alerts aren't sent to notifiers and series aren't written to remote storage.
+func (rh *requestHandler) dryRun(r *http.Request) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r.Body, maxDryRunRequestSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read request body: %w", err)
+	}
+	if len(data) > maxDryRunRequestSize {
+		return nil, errResponse(fmt.Errorf("too big request body; mustn't exceed %d bytes", maxDryRunRequestSize), http.StatusRequestEntityTooLarge)
+	}
+	groupsCfg, err := config.ParseBytes(dryRunFile, data, notifier.ValidateTemplates, true)
+	if err != nil {
+		return nil, errResponse(fmt.Errorf("invalid rule groups config: %w", err), http.StatusBadRequest)
+	}
+
+	evalTS := time.Now()
+	resp := dryRunResponse{Status: "success"}
+	resp.Data.Groups = make([]apiDryRunGroup, 0, len(groupsCfg))
+	for _, cfg := range groupsCfg {
+		g := rule.NewGroup(cfg, rh.m.querierBuilder, *evaluationInterval, rh.m.labels)
+		var sc seriesCollector
+		// Evaluation errors are stored in the rules state and are returned via lastError fields.
+		_ = g.DryRun(r.Context(), &sc, evalTS)
+		resp.Data.Groups = append(resp.Data.Groups, apiDryRunGroup{
+			apiGroup: groupToAPI(g),
+			Series:   sc.series,
+		})
+	}
+
+	b, err := json.Marshal(resp)
+	if err != nil {
+		return nil, &httpserver.ErrorWithStatusCode{
+			Err:        fmt.Errorf(`error encoding dry run results: %w`, err),
+			StatusCode: http.StatusInternalServerError,
+		}
+	}
+	return b, nil
+}
+
+// seriesCollector implements remotewrite.RWClient interface by collecting the pushed series in memory.
+type seriesCollector struct {
+	mu     sync.Mutex
+	series []apiSeries
+}
+
+// Push implements remotewrite.RWClient interface
+func (sc *seriesCollector) Push(ts prompbmarshal.TimeSeries) error {
+	labels := make(map[string]string, len(ts.Labels))
+	for _, l := range ts.Labels {
+		labels[l.Name] = l.Value
+	}
+	sc.mu.Lock()
+	for _, s := range ts.Samples {
+		sc.series = append(sc.series, apiSeries{
+			Labels:    labels,
+			Value:     strconv.FormatFloat(s.Value, 'f', -1, 64),
+			Timestamp: s.Timestamp,
+		})
+	}
+	sc.mu.Unlock()
+	return nil
+}
+
+// Close implements remotewrite.RWClient interface
+func (sc *seriesCollector) Close() error {
+	return nil
+}
@@ -27,6 +27,13 @@ var (
 		"Max number of data points expected in one request. It affects the max time range for every `/query_range` request during the replay. The higher the value, the less requests will be made during replay.")
 	replayRuleRetryAttempts = flag.Int("replay.ruleRetryAttempts", 5,
 		"Defines how many retries to make before giving up on rule if request for it returns an error.")
+	replayRuleEvaluationConcurrency = flag.Int("replay.ruleEvaluationConcurrency", 1, "The maximum number of concurrent `/query_range` requests "+
+		"when replaying recording rule over the time range split into multiple requests according to -replay.maxDatapointsPerQuery. "+
+		"Alerting rules are always replayed sequentially, since their state depends on the previously replayed time ranges.")
+	replayCheckpointFile = flag.String("replay.checkpointFile", "", "Optional path to the file for storing replay progress. "+
+		"If set, then vmalert stores the end of the last replayed time range per every rule to this file, "+
+		"so the interrupted replay is resumed from the last replayed time range when vmalert is restarted with the same rules and the same file. "+
+		"Samples pushed to -remoteWrite.url but not delivered before the interruption may be lost.")
 	disableProgressBar = flag.Bool("replay.disableProgressBar", false, "Whether to disable rendering progress bars during the replay. "+
 		"Progress bar rendering might be verbose or break the logs parsing, so it is recommended to be disabled when not used in interactive mode.")
 )
@@ -35,6 +42,9 @@ func replay(groupsCfg []config.Group, qb datasource.QuerierBuilder, rw remotewri
 	if *replayMaxDatapoints < 1 {
 		return fmt.Errorf("replay.maxDatapointsPerQuery can't be lower than 1")
 	}
+	if *replayRuleEvaluationConcurrency < 1 {
+		return fmt.Errorf("replay.ruleEvaluationConcurrency can't be lower than 1")
+	}
 	tFrom, err := time.Parse(time.RFC3339, *replayFrom)
 	if err != nil {
 		return fmt.Errorf("failed to parse replay.timeFrom=%q: %w", *replayFrom, err)
@@ -64,16 +74,26 @@ func replay(groupsCfg []config.Group, qb datasource.QuerierBuilder, rw remotewri
 		labels[s[:n]] = s[n+1:]
 	}
 
+	var cp *rule.ReplayCheckpoint
+	if *replayCheckpointFile != "" {
+		cp, err = rule.LoadReplayCheckpoint(*replayCheckpointFile)
+		if err != nil {
+			return err
+		}
+	}
+
 	fmt.Printf("Replay mode:"+
 		"\nfrom: \t%v "+
 		"\nto: \t%v "+
-		"\nmax data points per request: %d\n",
-		tFrom, tTo, *replayMaxDatapoints)
+		"\nmax data points per request: %d"+
+		"\nrule evaluation concurrency: %d\n",
+		tFrom, tTo, *replayMaxDatapoints, *replayRuleEvaluationConcurrency)
 
 	var total int
 	for _, cfg := range groupsCfg {
 		ng := rule.NewGroup(cfg, qb, *evaluationInterval, labels)
-		total += ng.Replay(tFrom, tTo, rw, *replayMaxDatapoints, *replayRuleRetryAttempts, *replayRulesDelay, *disableProgressBar)
+		total += ng.Replay(tFrom, tTo, rw, *replayMaxDatapoints, *replayRuleRetryAttempts, *replayRulesDelay, *disableProgressBar,
+			*replayRuleEvaluationConcurrency, cp)
 	}
 	logger.Infof("replay evaluation finished, generated %d samples", total)
 	if err := rw.Close(); err != nil {
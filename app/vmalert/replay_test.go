@@ -3,6 +3,8 @@ package main
 import (
 	"context"
 	"fmt"
+	"path/filepath"
+	"sync"
 	"testing"
 	"time"
 
@@ -14,6 +16,8 @@ import (
 
 type fakeReplayQuerier struct {
 	datasource.FakeQuerier
+
+	mu       sync.Mutex
 	registry map[string]map[string]struct{}
 }
 
@@ -22,6 +26,9 @@ func (fr *fakeReplayQuerier) BuildWithParams(_ datasource.QuerierParams) datasou
 }
 
 func (fr *fakeReplayQuerier) QueryRange(_ context.Context, q string, from, to time.Time) (res datasource.Result, err error) {
+	fr.mu.Lock()
+	defer fr.mu.Unlock()
+
 	key := fmt.Sprintf("%s+%s", from.Format("15:04:05"), to.Format("15:04:05"))
 	dps, ok := fr.registry[q]
 	if !ok {
@@ -138,3 +145,105 @@ func TestReplay(t *testing.T) {
 		},
 	})
 }
+
+func TestReplayConcurrency(t *testing.T) {
+	fromOrig, toOrig, maxDatapointsOrig := *replayFrom, *replayTo, *replayMaxDatapoints
+	retriesOrig, delayOrig, concurrencyOrig := *replayRuleRetryAttempts, *replayRulesDelay, *replayRuleEvaluationConcurrency
+	defer func() {
+		*replayFrom, *replayTo = fromOrig, toOrig
+		*replayMaxDatapoints, *replayRuleRetryAttempts = maxDatapointsOrig, retriesOrig
+		*replayRulesDelay, *replayRuleEvaluationConcurrency = delayOrig, concurrencyOrig
+	}()
+
+	*replayRuleRetryAttempts = 1
+	*replayRulesDelay = time.Millisecond
+	*replayFrom = "2021-01-01T12:00:00.000Z"
+	*replayTo = "2021-01-01T12:04:30.000Z"
+	*replayMaxDatapoints = 1
+	*replayRuleEvaluationConcurrency = 3
+
+	qb := &fakeReplayQuerier{
+		registry: map[string]map[string]struct{}{
+			"sum(up)": {
+				"12:00:00+12:01:00": {},
+				"12:01:00+12:02:00": {},
+				"12:02:00+12:03:00": {},
+				"12:03:00+12:04:00": {},
+				"12:04:00+12:04:30": {},
+			},
+			"sum(up) > 1": {
+				"12:00:00+12:01:00": {},
+				"12:01:00+12:02:00": {},
+				"12:02:00+12:03:00": {},
+				"12:03:00+12:04:00": {},
+				"12:04:00+12:04:30": {},
+			},
+		},
+	}
+	cfg := []config.Group{
+		{Rules: []config.Rule{{Record: "foo", Expr: "sum(up)"}}},
+		{Rules: []config.Rule{{Alert: "foo", Expr: "sum(up) > 1"}}},
+	}
+	if err := replay(cfg, qb, &remotewrite.DebugClient{}); err != nil {
+		t.Fatalf("replay failed: %s", err)
+	}
+	if len(qb.registry) > 0 {
+		t.Fatalf("not all requests were sent: %#v", qb.registry)
+	}
+}
+
+func TestReplayCheckpoint(t *testing.T) {
+	fromOrig, toOrig, maxDatapointsOrig := *replayFrom, *replayTo, *replayMaxDatapoints
+	retriesOrig, delayOrig, checkpointOrig := *replayRuleRetryAttempts, *replayRulesDelay, *replayCheckpointFile
+	defer func() {
+		*replayFrom, *replayTo = fromOrig, toOrig
+		*replayMaxDatapoints, *replayRuleRetryAttempts = maxDatapointsOrig, retriesOrig
+		*replayRulesDelay, *replayCheckpointFile = delayOrig, checkpointOrig
+	}()
+
+	*replayRuleRetryAttempts = 1
+	*replayRulesDelay = time.Millisecond
+	*replayMaxDatapoints = 1
+	*replayFrom = "2021-01-01T12:00:00.000Z"
+	*replayCheckpointFile = filepath.Join(t.TempDir(), "checkpoint.json")
+	cfg := []config.Group{
+		{Rules: []config.Rule{{Record: "foo", Expr: "sum(up)"}}},
+	}
+
+	f := func(to string, qb *fakeReplayQuerier) {
+		t.Helper()
+
+		*replayTo = to
+		if err := replay(cfg, qb, &remotewrite.DebugClient{}); err != nil {
+			t.Fatalf("replay failed: %s", err)
+		}
+		if len(qb.registry) > 0 {
+			t.Fatalf("not all requests were sent: %#v", qb.registry)
+		}
+	}
+
+	// initial replay
+	f("2021-01-01T12:02:00.000Z", &fakeReplayQuerier{
+		registry: map[string]map[string]struct{}{
+			"sum(up)": {
+				"12:00:00+12:01:00": {},
+				"12:01:00+12:02:00": {},
+			},
+		},
+	})
+
+	// the replay must be resumed from the last replayed time range
+	f("2021-01-01T12:03:30.000Z", &fakeReplayQuerier{
+		registry: map[string]map[string]struct{}{
+			"sum(up)": {
+				"12:02:00+12:03:00": {},
+				"12:03:00+12:03:30": {},
+			},
+		},
+	})
+
+	// the rule was already replayed, so no requests are expected
+	f("2021-01-01T12:03:30.000Z", &fakeReplayQuerier{
+		registry: map[string]map[string]struct{}{},
+	})
+}
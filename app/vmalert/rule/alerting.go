@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"hash/fnv"
+	"net/url"
 	"sort"
 	"strings"
 	"sync"
@@ -35,6 +36,10 @@ type AlertingRule struct {
 	Debug         bool
 
 	q datasource.Querier
+	// queryParams and headers are used for building
+	// the querier for alerts state restore
+	queryParams url.Values
+	headers     map[string]string
 
 	alertsMu sync.RWMutex
 	// stores list of active alerts
@@ -57,6 +62,7 @@ type alertingRuleMetrics struct {
 
 // NewAlertingRule creates a new AlertingRule
 func NewAlertingRule(qb datasource.QuerierBuilder, group *Group, cfg config.Rule) *AlertingRule {
+	queryParams, headers := group.getQueryParams(cfg), group.getHeaders(cfg)
 	ar := &AlertingRule{
 		Type:          group.Type,
 		RuleID:        cfg.ID,
@@ -74,12 +80,14 @@ func NewAlertingRule(qb datasource.QuerierBuilder, group *Group, cfg config.Rule
 		q: qb.BuildWithParams(datasource.QuerierParams{
 			DataSourceType:     group.Type.String(),
 			EvaluationInterval: group.Interval,
-			QueryParams:        group.Params,
-			Headers:            group.Headers,
+			QueryParams:        queryParams,
+			Headers:            headers,
 			Debug:              cfg.Debug,
 		}),
-		alerts:  make(map[uint64]*notifier.Alert),
-		metrics: &alertingRuleMetrics{},
+		queryParams: queryParams,
+		headers:     headers,
+		alerts:      make(map[uint64]*notifier.Alert),
+		metrics:     &alertingRuleMetrics{},
 	}
 
 	entrySize := *ruleUpdateEntriesLimit
@@ -225,6 +233,8 @@ func (ar *AlertingRule) updateWith(r Rule) error {
 	ar.EvalInterval = nr.EvalInterval
 	ar.Debug = nr.Debug
 	ar.q = nr.q
+	ar.queryParams = nr.queryParams
+	ar.headers = nr.headers
 	ar.state = nr.state
 	return nil
 }
@@ -215,8 +215,8 @@ func (g *Group) restore(ctx context.Context, qb datasource.QuerierBuilder, ts ti
 		q := qb.BuildWithParams(datasource.QuerierParams{
 			DataSourceType:     g.Type.String(),
 			EvaluationInterval: g.Interval,
-			QueryParams:        g.Params,
-			Headers:            g.Headers,
+			QueryParams:        ar.queryParams,
+			Headers:            ar.headers,
 			Debug:              ar.Debug,
 		})
 		if err := ar.restore(ctx, q, ts, lookback); err != nil {
@@ -300,6 +300,10 @@ func (g *Group) Close() {
 	g.InterruptEval()
 	<-g.finishedCh
 
+	g.unregisterMetrics()
+}
+
+func (g *Group) unregisterMetrics() {
 	g.metrics.iterationDuration.Unregister()
 	g.metrics.iterationTotal.Unregister()
 	g.metrics.iterationMissed.Unregister()
@@ -482,10 +486,15 @@ func (g *Group) infof(format string, args ...any) {
 }
 
 // Replay performs group replay
-func (g *Group) Replay(start, end time.Time, rw remotewrite.RWClient, maxDataPoint, replayRuleRetryAttempts int, replayDelay time.Duration, disableProgressBar bool) int {
+//
+// Time ranges of recording rules are evaluated with the given concurrency,
+// while alerting rules are always evaluated sequentially, since their state depends on the previous time ranges.
+// If cp isn't nil, then the replay progress is stored in cp after every replayed time range,
+// and the rules are replayed starting from the last replayed time range stored in cp.
+func (g *Group) Replay(start, end time.Time, rw remotewrite.RWClient, maxDataPoint, replayRuleRetryAttempts int, replayDelay time.Duration,
+	disableProgressBar bool, concurrency int, cp *ReplayCheckpoint) int {
 	var total int
 	step := g.Interval * time.Duration(maxDataPoint)
-	ri := rangeIterator{start: start, end: end, step: step}
 	iterations := int(end.Sub(start)/step) + 1
 	fmt.Printf("\nGroup %q"+
 		"\ninterval: \t%v"+
@@ -498,20 +507,40 @@ func (g *Group) Replay(start, end time.Time, rw remotewrite.RWClient, maxDataPoi
 	}
 	for _, rule := range g.Rules {
 		fmt.Printf("> Rule %q (ID: %d)\n", rule, rule.ID())
+		key := replayCheckpointKey(g, rule)
+		ruleStart := start
+		if t, ok := cp.get(key); ok && t.After(ruleStart) {
+			if !end.After(t) {
+				fmt.Printf("rule was already replayed till %v according to checkpoint; skipping it\n", t)
+				continue
+			}
+			fmt.Printf("resuming replay from %v according to checkpoint\n", t)
+			ruleStart = t
+		}
+		var ranges []replayRange
+		ri := rangeIterator{start: ruleStart, end: end, step: step}
+		for ri.next() {
+			ranges = append(ranges, replayRange{start: ri.s, end: ri.e})
+		}
 		var bar *pb.ProgressBar
 		if !disableProgressBar {
-			bar = pb.StartNew(iterations)
+			bar = pb.StartNew(len(ranges))
 		}
-		ri.reset()
-		for ri.next() {
-			n, err := replayRule(rule, ri.s, ri.e, rw, replayRuleRetryAttempts)
-			if err != nil {
-				logger.Fatalf("rule %q: %s", rule, err)
-			}
-			total += n
+		ruleConcurrency := concurrency
+		if _, ok := rule.(*AlertingRule); ok {
+			ruleConcurrency = 1
+		}
+		n, err := replayRanges(rule, ranges, rw, replayRuleRetryAttempts, ruleConcurrency, func(rangesDone int) {
 			if bar != nil {
 				bar.Increment()
 			}
+			if rangesDone > 0 {
+				cp.set(key, ranges[rangesDone-1].end)
+			}
+		})
+		total += n
+		if err != nil {
+			logger.Fatalf("rule %q: %s", rule, err)
 		}
 		if bar != nil {
 			bar.Finish()
@@ -538,6 +567,26 @@ func (g *Group) ExecOnce(ctx context.Context, nts func() []notifier.Notifier, rw
 	return e.execConcurrently(ctx, g.Rules, evalTS, g.Concurrency, resolveDuration, g.Limit)
 }
 
+// DryRun evaluates all the rules under group for once with given timestamp without sending alerts to notifiers.
+// Series produced by rules are pushed to rw if it isn't nil.
+//
+// Group metrics are unregistered after the evaluation, so DryRun must be called only for groups, which aren't started.
+func (g *Group) DryRun(ctx context.Context, rw remotewrite.RWClient, evalTS time.Time) error {
+	defer g.unregisterMetrics()
+
+	errs := g.ExecOnce(ctx, func() []notifier.Notifier { return nil }, rw, evalTS)
+	if errs == nil {
+		return nil
+	}
+	errGr := new(utils.ErrGroup)
+	for err := range errs {
+		if err != nil {
+			errGr.Add(err)
+		}
+	}
+	return errGr.Err()
+}
+
 type rangeIterator struct {
 	step       time.Duration
 	start, end time.Time
@@ -546,11 +595,6 @@ type rangeIterator struct {
 	s, e time.Time
 }
 
-func (ri *rangeIterator) reset() {
-	ri.iter = 0
-	ri.s, ri.e = time.Time{}, time.Time{}
-}
-
 func (ri *rangeIterator) next() bool {
 	ri.s = ri.start.Add(ri.step * time.Duration(ri.iter))
 	if !ri.end.After(ri.s) {
@@ -614,6 +658,38 @@ func (g *Group) getEvalDelay() time.Duration {
 	return *evalDelay
 }
 
+// getQueryParams returns HTTP URL params for requests of the rule with the given cfg.
+// Rule params have priority over the group params with the same name.
+func (g *Group) getQueryParams(cfg config.Rule) url.Values {
+	if len(cfg.Params) == 0 {
+		return g.Params
+	}
+	params := url.Values{}
+	for k, vl := range g.Params {
+		params[k] = vl
+	}
+	for k, vl := range cfg.Params {
+		params[k] = vl
+	}
+	return params
+}
+
+// getHeaders returns HTTP headers for requests of the rule with the given cfg.
+// Rule headers have priority over the group headers with the same key.
+func (g *Group) getHeaders(cfg config.Rule) map[string]string {
+	if len(cfg.Headers) == 0 {
+		return g.Headers
+	}
+	headers := make(map[string]string, len(g.Headers)+len(cfg.Headers))
+	for k, v := range g.Headers {
+		headers[k] = v
+	}
+	for _, h := range cfg.Headers {
+		headers[h.Key] = h.Value
+	}
+	return headers
+}
+
 // executor contains group's notify and rw configs
 type executor struct {
 	Notifiers       func() []notifier.Notifier
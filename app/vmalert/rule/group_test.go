@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"net/url"
 	"os"
 	"reflect"
 	"sort"
@@ -683,6 +684,51 @@ func TestGetPrometheusReqTimestamp(t *testing.T) {
 	}, "2023-08-28T11:41:00+00:00", "2023-08-28T11:40:00+00:00")
 }
 
+func TestGroupRuleQueryParamsAndHeaders(t *testing.T) {
+	f := func(g *Group, cfg config.Rule, paramsExpected url.Values, headersExpected map[string]string) {
+		t.Helper()
+
+		params := g.getQueryParams(cfg)
+		if params.Encode() != paramsExpected.Encode() {
+			t.Fatalf("unexpected params; got %q; want %q", params.Encode(), paramsExpected.Encode())
+		}
+		headers := g.getHeaders(cfg)
+		if len(headers) != len(headersExpected) {
+			t.Fatalf("unexpected headers; got %v; want %v", headers, headersExpected)
+		}
+		for k, v := range headersExpected {
+			if headers[k] != v {
+				t.Fatalf("unexpected headers; got %v; want %v", headers, headersExpected)
+			}
+		}
+	}
+
+	g := &Group{
+		Params:  url.Values{"nocache": {"1"}, "extra_label": {"env=prod"}},
+		Headers: map[string]string{"AccountID": "1", "TenantID": "foo"},
+	}
+
+	// rule without params and headers inherits group ones
+	f(g, config.Rule{}, g.Params, g.Headers)
+
+	// rule params and headers override group ones
+	f(g, config.Rule{
+		Params:  url.Values{"extra_label": {"env=dev", "job=bar"}, "latency_offset": {"1m"}},
+		Headers: []config.Header{{Key: "TenantID", Value: "bar"}, {Key: "X-Foo", Value: "baz"}},
+	}, url.Values{
+		"nocache":        {"1"},
+		"extra_label":    {"env=dev", "job=bar"},
+		"latency_offset": {"1m"},
+	}, map[string]string{
+		"AccountID": "1",
+		"TenantID":  "bar",
+		"X-Foo":     "baz",
+	})
+
+	// group params and headers must remain unchanged
+	f(g, config.Rule{}, url.Values{"nocache": {"1"}, "extra_label": {"env=prod"}}, map[string]string{"AccountID": "1", "TenantID": "foo"})
+}
+
 func TestRangeIterator(t *testing.T) {
 	f := func(ri rangeIterator, resultExpected [][2]time.Time) {
 		t.Helper()
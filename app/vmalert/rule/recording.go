@@ -66,8 +66,8 @@ func NewRecordingRule(qb datasource.QuerierBuilder, group *Group, cfg config.Rul
 		q: qb.BuildWithParams(datasource.QuerierParams{
 			DataSourceType:     group.Type.String(),
 			EvaluationInterval: group.Interval,
-			QueryParams:        group.Params,
-			Headers:            group.Headers,
+			QueryParams:        group.getQueryParams(cfg),
+			Headers:            group.getHeaders(cfg),
 		}),
 	}
 
@@ -0,0 +1,137 @@
+package rule
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmalert/remotewrite"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fs"
+)
+
+// ReplayCheckpoint tracks replay progress for every rule,
+// so the interrupted replay could be resumed from the last replayed time range.
+//
+// All the methods are safe to call on nil ReplayCheckpoint.
+type ReplayCheckpoint struct {
+	path string
+
+	mu sync.Mutex
+	// rules contains the end of the last successfully replayed time range per every rule
+	rules map[string]time.Time
+}
+
+// LoadReplayCheckpoint loads replay checkpoint from the given path.
+//
+// Empty checkpoint is returned if the file at path doesn't exist yet.
+func LoadReplayCheckpoint(path string) (*ReplayCheckpoint, error) {
+	c := &ReplayCheckpoint{
+		path:  path,
+		rules: make(map[string]time.Time),
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return c, nil
+		}
+		return nil, fmt.Errorf("cannot read replay checkpoint: %w", err)
+	}
+	if err := json.Unmarshal(data, &c.rules); err != nil {
+		return nil, fmt.Errorf("cannot parse replay checkpoint from %q: %w", path, err)
+	}
+	return c, nil
+}
+
+func (c *ReplayCheckpoint) get(key string) (time.Time, bool) {
+	if c == nil {
+		return time.Time{}, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t, ok := c.rules[key]
+	return t, ok
+}
+
+// set stores t as the end of the last replayed time range for the rule with the given key
+// and persists the checkpoint to disk.
+func (c *ReplayCheckpoint) set(key string, t time.Time) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.rules[key] = t
+	data, err := json.Marshal(c.rules)
+	if err != nil {
+		// This shouldn't happen, since c.rules contains only strings and timestamps
+		panic(fmt.Errorf("BUG: cannot marshal replay checkpoint: %w", err))
+	}
+	fs.MustWriteAtomic(c.path, data, true)
+}
+
+func replayCheckpointKey(g *Group, r Rule) string {
+	return fmt.Sprintf("%d/%d", g.ID(), r.ID())
+}
+
+// replayRange is a time range, which must be replayed for a rule.
+type replayRange struct {
+	start, end time.Time
+}
+
+// replayRanges evaluates r over the given ranges with the given concurrency and pushes the results to rw.
+//
+// onRangeDone is called after the evaluation of every range with the number of the leading ranges,
+// which were successfully evaluated.
+func replayRanges(r Rule, ranges []replayRange, rw remotewrite.RWClient, retryAttempts, concurrency int, onRangeDone func(rangesDone int)) (int, error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		total    int
+		firstErr error
+		done     = make([]bool, len(ranges))
+		leading  int
+	)
+	workCh := make(chan int)
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range workCh {
+				rr := ranges[idx]
+				n, err := replayRule(r, rr.start, rr.end, rw, retryAttempts)
+				mu.Lock()
+				total += n
+				if err != nil {
+					if firstErr == nil {
+						firstErr = err
+					}
+					mu.Unlock()
+					continue
+				}
+				done[idx] = true
+				for leading < len(done) && done[leading] {
+					leading++
+				}
+				onRangeDone(leading)
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range ranges {
+		mu.Lock()
+		stop := firstErr != nil
+		mu.Unlock()
+		if stop {
+			break
+		}
+		workCh <- i
+	}
+	close(workCh)
+	wg.Wait()
+	return total, firstErr
+}
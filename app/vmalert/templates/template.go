@@ -426,6 +426,23 @@ func templateFuncs() textTpl.FuncMap {
 			return fmt.Sprint(t), nil
 		},
 
+		// toDuration converts given seconds to a time.Duration.
+		"toDuration": func(i any) (time.Duration, error) {
+			v, err := toFloat64(i)
+			if err != nil {
+				return 0, err
+			}
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				return 0, fmt.Errorf("cannot convert %v to time.Duration", v)
+			}
+			return time.Duration(v * float64(time.Second)), nil
+		},
+
+		// now returns the current unix timestamp in seconds.
+		"now": func() float64 {
+			return float64(time.Now().UnixNano()) / 1e9
+		},
+
 		// toTime converts given timestamp to a time.Time.
 		"toTime": func(i any) (time.Time, error) {
 			v, err := toFloat64(i)
@@ -461,6 +478,18 @@ func templateFuncs() textTpl.FuncMap {
 			return ""
 		},
 
+		// graphLink returns the relative path to the graph view of the given expression.
+		// The returned path is compatible with Prometheus and with VictoriaMetrics, which redirects it to vmui.
+		"graphLink": func(expr string) string {
+			return "/graph?g0.expr=" + url.QueryEscape(expr) + "&g0.tab=0"
+		},
+
+		// tableLink returns the relative path to the table view of the given expression.
+		// The returned path is compatible with Prometheus and with VictoriaMetrics, which redirects it to vmui.
+		"tableLink": func(expr string) string {
+			return "/graph?g0.expr=" + url.QueryEscape(expr) + "&g0.tab=1"
+		},
+
 		// pathEscape escapes the string so it can be safely placed inside a URL path segment.
 		//
 		// See also queryEscape.
@@ -585,6 +614,8 @@ func toFloat64(v any) (float64, error) {
 		return float64(i), nil
 	case string:
 		return strconv.ParseFloat(i, 64)
+	case time.Duration:
+		return i.Seconds(), nil
 	default:
 		return 0, fmt.Errorf("unexpected value type %v", i)
 	}
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 	textTpl "text/template"
+	"time"
 )
 
 func TestTemplateFuncs_StringConversion(t *testing.T) {
@@ -58,6 +59,49 @@ func TestTemplateFuncs_Match(t *testing.T) {
 	}
 }
 
+func TestTemplateFuncs_Links(t *testing.T) {
+	f := func(funcName, expr, resultExpected string) {
+		t.Helper()
+
+		funcs := templateFuncs()
+		fLocal := funcs[funcName].(func(expr string) string)
+		result := fLocal(expr)
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %s(%q); got\n%s\nwant\n%s", funcName, expr, result, resultExpected)
+		}
+	}
+
+	f("graphLink", `up{job="foo"} == 0`, "/graph?g0.expr=up%7Bjob%3D%22foo%22%7D+%3D%3D+0&g0.tab=0")
+	f("tableLink", `up{job="foo"} == 0`, "/graph?g0.expr=up%7Bjob%3D%22foo%22%7D+%3D%3D+0&g0.tab=1")
+}
+
+func TestTemplateFuncs_ToDuration(t *testing.T) {
+	toDuration := templateFuncs()["toDuration"].(func(i any) (time.Duration, error))
+	f := func(p any, resultExpected time.Duration) {
+		t.Helper()
+
+		result, err := toDuration(p)
+		if err != nil {
+			t.Fatalf("unexpected error for toDuration(%v): %s", p, err)
+		}
+		if result != resultExpected {
+			t.Fatalf("unexpected result for toDuration(%v); got %s; want %s", p, result, resultExpected)
+		}
+	}
+
+	f(float64(90), 90*time.Second)
+	f(0.25, 250*time.Millisecond)
+	f("60", time.Minute)
+	f(5*time.Second, 5*time.Second)
+
+	if _, err := toDuration(math.NaN()); err == nil {
+		t.Fatalf("expecting non-nil error for toDuration(NaN)")
+	}
+	if _, err := toDuration("foo"); err == nil {
+		t.Fatalf("expecting non-nil error for toDuration(\"foo\")")
+	}
+}
+
 func TestTemplateFuncs_Formatting(t *testing.T) {
 	f := func(funcName string, p any, resultExpected string) {
 		t.Helper()
@@ -94,6 +138,7 @@ func TestTemplateFuncs_Formatting(t *testing.T) {
 	f("humanizeDuration", 0.2, "200ms")
 	f("humanizeDuration", 42000, "11h 40m 0s")
 	f("humanizeDuration", 16790555, "194d 8h 2m 35s")
+	f("humanizeDuration", 90*time.Second, "1m 30s")
 
 	f("humanizePercentage", 1, "100%")
 	f("humanizePercentage", 0.8, "80%")
@@ -166,6 +166,19 @@ func (rh *requestHandler) handler(w http.ResponseWriter, r *http.Request) bool {
 		w.Header().Set("Content-Type", "application/json")
 		w.Write(data)
 		return true
+	case "/vmalert/api/v1/rules/dry_run", "/api/v1/rules/dry_run":
+		if r.Method != http.MethodPost {
+			httpserver.Errorf(w, r, "path %q supports only POST method", r.URL.Path)
+			return true
+		}
+		data, err := rh.dryRun(r)
+		if err != nil {
+			httpserver.Errorf(w, r, "%s", err)
+			return true
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(data)
+		return true
 	case "/-/reload":
 		if !httpserver.CheckAuthFlag(w, r, reloadAuthKey) {
 			return true
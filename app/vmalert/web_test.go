@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -312,3 +313,102 @@ func TestEmptyResponse(t *testing.T) {
 		}
 	})
 }
+
+func TestHandlerDryRun(t *testing.T) {
+	fq := &datasource.FakeQuerier{}
+	fq.Add(datasource.Metric{
+		Labels: []datasource.Label{{Name: "instance", Value: "foo"}},
+		Values: []float64{1}, Timestamps: []int64{0},
+	})
+	rh := &requestHandler{m: &manager{
+		querierBuilder: fq,
+		groups:         make(map[uint64]*rule.Group),
+	}}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { rh.handler(w, r) }))
+	defer ts.Close()
+
+	postResp := func(t *testing.T, url, body string, to any, code int) {
+		t.Helper()
+		resp, err := http.Post(url, "application/yaml", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("unexpected err %s", err)
+		}
+		if code != resp.StatusCode {
+			t.Fatalf("unexpected status code %d want %d", resp.StatusCode, code)
+		}
+		defer func() {
+			if err := resp.Body.Close(); err != nil {
+				t.Fatalf("err closing body %s", err)
+			}
+		}()
+		if to != nil {
+			if err = json.NewDecoder(resp.Body).Decode(to); err != nil {
+				t.Fatalf("unexpected err %s", err)
+			}
+		}
+	}
+
+	t.Run("valid group", func(t *testing.T) {
+		cfg := `
+groups:
+  - name: candidate
+    rules:
+      - alert: InstanceDown
+        expr: up == 0
+        labels:
+          severity: critical
+      - record: job:up:sum
+        expr: sum(up)
+`
+		for _, path := range []string{"/api/v1/rules/dry_run", "/vmalert/api/v1/rules/dry_run"} {
+			var resp dryRunResponse
+			postResp(t, ts.URL+path, cfg, &resp, 200)
+			if len(resp.Data.Groups) != 1 {
+				t.Fatalf("expected 1 group; got %d", len(resp.Data.Groups))
+			}
+			g := resp.Data.Groups[0]
+			if g.Name != "candidate" || g.File != dryRunFile {
+				t.Fatalf("unexpected group name=%q, file=%q", g.Name, g.File)
+			}
+			if len(g.Rules) != 2 {
+				t.Fatalf("expected 2 rules; got %d", len(g.Rules))
+			}
+			var alerts int
+			for _, r := range g.Rules {
+				if r.LastError != "" {
+					t.Fatalf("unexpected error for rule %q: %s", r.Name, r.LastError)
+				}
+				alerts += len(r.Alerts)
+			}
+			if alerts != 1 {
+				t.Fatalf("expected 1 alert; got %d", alerts)
+			}
+			// ALERTS and ALERTS_FOR_STATE series for the alerting rule and a single series for the recording rule
+			if len(g.Series) != 3 {
+				t.Fatalf("expected 3 series; got %d: %v", len(g.Series), g.Series)
+			}
+		}
+	})
+
+	t.Run("invalid group", func(t *testing.T) {
+		postResp(t, ts.URL+"/api/v1/rules/dry_run", "groups: foo", nil, 400)
+		postResp(t, ts.URL+"/api/v1/rules/dry_run", `
+groups:
+  - name: candidate
+    rules:
+      - alert: InstanceDown
+        expr: up ==
+`, nil, 400)
+	})
+
+	t.Run("unsupported method", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/api/v1/rules/dry_run")
+		if err != nil {
+			t.Fatalf("unexpected err %s", err)
+		}
+		_ = resp.Body.Close()
+		if resp.StatusCode != 400 {
+			t.Fatalf("unexpected status code %d want %d", resp.StatusCode, 400)
+		}
+	})
+}
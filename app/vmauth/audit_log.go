@@ -0,0 +1,379 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/stringsutil"
+)
+
+var (
+	auditLog = flag.Bool("auditLog", false, "Whether to write audit log entries for requests proxied by vmauth. "+
+		"See https://docs.victoriametrics.com/vmauth/#audit-log")
+	auditLogSampleRatio = flag.Float64("auditLog.sampleRatio", 1, "The ratio of successfully proxied requests to write to the audit log. "+
+		"For example, 0.1 means that every 10th successful request is written to the audit log on average. Unsuccessful requests are always written to the audit log. "+
+		"See https://docs.victoriametrics.com/vmauth/#audit-log")
+	auditLogURL = flag.String("auditLog.url", "", "Optional URL for sending audit log entries to VictoriaLogs via JSON stream ingestion API, "+
+		"e.g. http://victorialogs:9428/insert/jsonline . If empty, then audit log entries are written to stdout. See https://docs.victoriametrics.com/vmauth/#audit-log")
+	auditLogFlushInterval = flag.Duration("auditLog.flushInterval", time.Second, "The interval for sending the collected audit log entries to -auditLog.url")
+)
+
+var (
+	auditLogEntriesTotal   = metrics.NewCounter(`vmauth_audit_log_entries_total`)
+	auditLogEntriesDropped = metrics.NewCounter(`vmauth_audit_log_entries_dropped_total`)
+	auditLogSendErrors     = metrics.NewCounter(`vmauth_audit_log_send_errors_total`)
+)
+
+// auditLogMaxPendingEntries is the maximum number of audit log entries waiting to be sent to -auditLog.url.
+//
+// Entries are dropped if the queue is full, so the unavailability of -auditLog.url doesn't block request proxying.
+const auditLogMaxPendingEntries = 100_000
+
+var (
+	auditLogCh     chan []byte
+	auditLogStopCh chan struct{}
+	auditLogWG     sync.WaitGroup
+)
+
+// auditLogStdout writes audit log entries to stdout if -auditLog.url isn't set.
+//
+// Entries are written directly instead of using logger, so they aren't suppressed by -loglevel
+// and aren't mixed with the log prefix.
+var auditLogStdout = &auditLogWriter{
+	w: os.Stdout,
+}
+
+// auditLogWriter writes audit log entries to w line by line.
+type auditLogWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (aw *auditLogWriter) writeLine(line []byte) {
+	aw.mu.Lock()
+	defer aw.mu.Unlock()
+
+	line = append(line, '\n')
+	if _, err := aw.w.Write(line); err != nil {
+		auditLogEntriesDropped.Inc()
+	}
+}
+
+func initAuditLog() {
+	if !*auditLog || *auditLogURL == "" {
+		return
+	}
+	auditLogCh = make(chan []byte, auditLogMaxPendingEntries)
+	auditLogStopCh = make(chan struct{})
+	auditLogWG.Add(1)
+	go func() {
+		defer auditLogWG.Done()
+		runAuditLogSender(*auditLogURL, auditLogCh, auditLogStopCh)
+	}()
+}
+
+func stopAuditLog() {
+	if auditLogStopCh == nil {
+		return
+	}
+	close(auditLogStopCh)
+	auditLogWG.Wait()
+}
+
+// auditLogEntry contains information about the proxied request.
+type auditLogEntry struct {
+	timestamp     time.Time
+	user          string
+	remoteAddr    string
+	forwardedFor  string
+	method        string
+	path          string
+	tenant        string
+	statusCode    int
+	duration      time.Duration
+	requestBytes  int64
+	responseBytes int64
+}
+
+// marshalJSON appends JSON representation of e to dst, which is compatible with VictoriaLogs JSON stream ingestion API.
+func (e *auditLogEntry) marshalJSON(dst []byte) []byte {
+	dst = append(dst, `{"_time":`...)
+	dst = strconv.AppendQuote(dst, e.timestamp.UTC().Format(time.RFC3339Nano))
+	dst = append(dst, `,"_msg":"proxied request","user":`...)
+	dst = append(dst, stringsutil.JSONString(e.user)...)
+	dst = append(dst, `,"remote_addr":`...)
+	dst = append(dst, stringsutil.JSONString(e.remoteAddr)...)
+	if e.forwardedFor != "" {
+		dst = append(dst, `,"forwarded_for":`...)
+		dst = append(dst, stringsutil.JSONString(e.forwardedFor)...)
+	}
+	dst = append(dst, `,"method":`...)
+	dst = append(dst, stringsutil.JSONString(e.method)...)
+	dst = append(dst, `,"path":`...)
+	dst = append(dst, stringsutil.JSONString(e.path)...)
+	if e.tenant != "" {
+		dst = append(dst, `,"tenant":`...)
+		dst = append(dst, stringsutil.JSONString(e.tenant)...)
+	}
+	dst = append(dst, `,"status_code":`...)
+	dst = strconv.AppendInt(dst, int64(e.statusCode), 10)
+	dst = append(dst, `,"duration_seconds":`...)
+	dst = strconv.AppendFloat(dst, e.duration.Seconds(), 'f', -1, 64)
+	dst = append(dst, `,"request_bytes":`...)
+	dst = strconv.AppendInt(dst, e.requestBytes, 10)
+	dst = append(dst, `,"response_bytes":`...)
+	dst = strconv.AppendInt(dst, e.responseBytes, 10)
+	dst = append(dst, '}')
+	return dst
+}
+
+// getTenantFromPath returns tenant from the given request path in VictoriaMetrics cluster format.
+//
+// For example, `1:2` tenant is returned for `/select/1:2/prometheus/api/v1/query` path.
+// Empty string is returned if the path doesn't contain tenant.
+func getTenantFromPath(path string) string {
+	for _, prefix := range []string{"/insert/", "/select/", "/delete/"} {
+		n := strings.Index(path, prefix)
+		if n < 0 {
+			continue
+		}
+		tail := path[n+len(prefix):]
+		if n := strings.IndexByte(tail, '/'); n >= 0 {
+			tail = tail[:n]
+		}
+		if isTenant(tail) {
+			return tail
+		}
+	}
+	return ""
+}
+
+func isTenant(s string) bool {
+	if s == "multitenant" {
+		return true
+	}
+	accountID, projectID, ok := strings.Cut(s, ":")
+	if _, err := strconv.ParseUint(accountID, 10, 32); err != nil {
+		return false
+	}
+	if !ok {
+		return true
+	}
+	_, err := strconv.ParseUint(projectID, 10, 32)
+	return err == nil
+}
+
+// shouldWriteAuditLog returns true if the request with the given statusCode must be written to the audit log.
+func shouldWriteAuditLog(statusCode int) bool {
+	if !*auditLog {
+		return false
+	}
+	if statusCode < 200 || statusCode >= 300 {
+		// Always log unsuccessful requests
+		return true
+	}
+	ratio := *auditLogSampleRatio
+	return ratio >= 1 || rand.Float64() < ratio
+}
+
+func writeAuditLog(e *auditLogEntry) {
+	if !shouldWriteAuditLog(e.statusCode) {
+		return
+	}
+	auditLogEntriesTotal.Inc()
+	line := e.marshalJSON(nil)
+	if auditLogCh == nil {
+		auditLogStdout.writeLine(line)
+		return
+	}
+	select {
+	case auditLogCh <- line:
+	default:
+		auditLogEntriesDropped.Inc()
+	}
+}
+
+func runAuditLogSender(u string, ch <-chan []byte, stopCh <-chan struct{}) {
+	c := &http.Client{
+		Timeout: time.Minute,
+	}
+	ticker := time.NewTicker(*auditLogFlushInterval)
+	defer ticker.Stop()
+
+	var bb bytes.Buffer
+	pending := 0
+	flush := func() {
+		if pending == 0 {
+			return
+		}
+		if err := sendAuditLog(c, u, bb.Bytes()); err != nil {
+			auditLogSendErrors.Inc()
+			auditLogEntriesDropped.Add(pending)
+			logger.Errorf("cannot send %d audit log entries to -auditLog.url=%q: %s", pending, u, err)
+		}
+		bb.Reset()
+		pending = 0
+	}
+	for {
+		select {
+		case line := <-ch:
+			bb.Write(line)
+			bb.WriteByte('\n')
+			pending++
+			if pending >= 10_000 {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-stopCh:
+			for {
+				select {
+				case line := <-ch:
+					bb.Write(line)
+					bb.WriteByte('\n')
+					pending++
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+func sendAuditLog(c *http.Client, u string, data []byte) error {
+	req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("cannot create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/stream+json")
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected response status code %d; response body: %q", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// auditResponseWriter tracks the status code and the size of the response written to it.
+type auditResponseWriter struct {
+	http.ResponseWriter
+
+	statusCode int
+	bytes      int64
+}
+
+// WriteHeader implements http.ResponseWriter interface.
+func (arw *auditResponseWriter) WriteHeader(statusCode int) {
+	if arw.statusCode == 0 {
+		arw.statusCode = statusCode
+	}
+	arw.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write implements http.ResponseWriter interface.
+func (arw *auditResponseWriter) Write(p []byte) (int, error) {
+	if arw.statusCode == 0 {
+		arw.statusCode = http.StatusOK
+	}
+	n, err := arw.ResponseWriter.Write(p)
+	arw.bytes += int64(n)
+	return n, err
+}
+
+// getStatusCode returns the status code of the response written to arw.
+func (arw *auditResponseWriter) getStatusCode() int {
+	if arw.statusCode == 0 {
+		// net/http sends 200 OK if neither WriteHeader nor Write is called.
+		return http.StatusOK
+	}
+	return arw.statusCode
+}
+
+// Flush implements http.Flusher interface.
+func (arw *auditResponseWriter) Flush() {
+	if f, ok := arw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying http.ResponseWriter.
+//
+// It is used by http.ResponseController.
+func (arw *auditResponseWriter) Unwrap() http.ResponseWriter {
+	return arw.ResponseWriter
+}
+
+// auditRequestBody tracks the number of bytes read from the request body.
+type auditRequestBody struct {
+	r     io.ReadCloser
+	bytes int64
+}
+
+// Read implements io.Reader interface.
+func (arb *auditRequestBody) Read(p []byte) (int, error) {
+	n, err := arb.r.Read(p)
+	arb.bytes += int64(n)
+	return n, err
+}
+
+// Close implements io.Closer interface.
+func (arb *auditRequestBody) Close() error {
+	return arb.r.Close()
+}
+
+// processUserRequestWithAuditLog processes r via processUserRequest and writes the audit log entry for it if -auditLog is set.
+func processUserRequestWithAuditLog(w http.ResponseWriter, r *http.Request, ui *UserInfo) {
+	if !*auditLog {
+		processUserRequest(w, r, ui)
+		return
+	}
+
+	startTime := time.Now()
+	arw := &auditResponseWriter{
+		ResponseWriter: w,
+	}
+	var arb *auditRequestBody
+	if r.Body != nil {
+		arb = &auditRequestBody{
+			r: r.Body,
+		}
+		r.Body = arb
+	}
+	path := r.URL.Path
+
+	processUserRequest(arw, r, ui)
+
+	e := &auditLogEntry{
+		timestamp:     startTime,
+		user:          ui.name(),
+		remoteAddr:    r.RemoteAddr,
+		forwardedFor:  r.Header.Get("X-Forwarded-For"),
+		method:        r.Method,
+		path:          path,
+		tenant:        getTenantFromPath(path),
+		statusCode:    arw.getStatusCode(),
+		duration:      time.Since(startTime),
+		responseBytes: arw.bytes,
+	}
+	if arb != nil {
+		e.requestBytes = arb.bytes
+	}
+	writeAuditLog(e)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGetTenantFromPath(t *testing.T) {
+	f := func(path, tenantExpected string) {
+		t.Helper()
+
+		tenant := getTenantFromPath(path)
+		if tenant != tenantExpected {
+			t.Fatalf("unexpected tenant for path %q; got %q; want %q", path, tenant, tenantExpected)
+		}
+	}
+
+	f("", "")
+	f("/api/v1/query", "")
+	f("/select/0/prometheus/api/v1/query", "0")
+	f("/select/12:34/prometheus/api/v1/query", "12:34")
+	f("/insert/multitenant/prometheus/api/v1/write", "multitenant")
+	f("/delete/5/prometheus/api/v1/admin/tsdb/delete_series", "5")
+	f("/foo/insert/1:2/influx/write", "1:2")
+
+	// invalid tenants
+	f("/select/foo/prometheus/api/v1/query", "")
+	f("/select/1:bar/prometheus/api/v1/query", "")
+	f("/select/-1/prometheus/api/v1/query", "")
+}
+
+func TestAuditLogEntryMarshalJSON(t *testing.T) {
+	f := func(e *auditLogEntry, resultExpected string) {
+		t.Helper()
+
+		result := string(e.marshalJSON(nil))
+		if result != resultExpected {
+			t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, resultExpected)
+		}
+	}
+
+	ts := time.Date(2024, 9, 1, 10, 20, 30, 123000000, time.UTC)
+	f(&auditLogEntry{
+		timestamp:  ts,
+		remoteAddr: "1.2.3.4:5678",
+		method:     "GET",
+		path:       "/api/v1/query",
+		statusCode: 401,
+	}, `{"_time":"2024-09-01T10:20:30.123Z","_msg":"proxied request","user":"","remote_addr":"1.2.3.4:5678","method":"GET","path":"/api/v1/query",`+
+		`"status_code":401,"duration_seconds":0,"request_bytes":0,"response_bytes":0}`)
+	f(&auditLogEntry{
+		timestamp:     ts,
+		user:          `foo"bar`,
+		remoteAddr:    "1.2.3.4:5678",
+		forwardedFor:  "5.6.7.8",
+		method:        "POST",
+		path:          "/insert/1:2/prometheus/api/v1/write",
+		tenant:        "1:2",
+		statusCode:    204,
+		duration:      1500 * time.Millisecond,
+		requestBytes:  1234,
+		responseBytes: 0,
+	}, `{"_time":"2024-09-01T10:20:30.123Z","_msg":"proxied request","user":"foo\"bar","remote_addr":"1.2.3.4:5678","forwarded_for":"5.6.7.8",`+
+		`"method":"POST","path":"/insert/1:2/prometheus/api/v1/write","tenant":"1:2","status_code":204,"duration_seconds":1.5,"request_bytes":1234,"response_bytes":0}`)
+}
+
+func TestRunAuditLogSender(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/stream+json" {
+			t.Errorf("unexpected Content-Type header: %q", ct)
+		}
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("cannot read request body: %s", err)
+		}
+		mu.Lock()
+		received = append(received, strings.Split(strings.TrimSpace(string(data)), "\n")...)
+		mu.Unlock()
+	}))
+	defer ts.Close()
+
+	ch := make(chan []byte, 10)
+	stopCh := make(chan struct{})
+	doneCh := make(chan struct{})
+	go func() {
+		runAuditLogSender(ts.URL, ch, stopCh)
+		close(doneCh)
+	}()
+
+	ch <- []byte(`{"_msg":"foo"}`)
+	ch <- []byte(`{"_msg":"bar"}`)
+	close(stopCh)
+	<-doneCh
+
+	// All the pending entries must be sent on stop
+	resultExpected := []string{`{"_msg":"foo"}`, `{"_msg":"bar"}`}
+	if strings.Join(received, "\n") != strings.Join(resultExpected, "\n") {
+		t.Fatalf("unexpected entries received\ngot\n%s\nwant\n%s", received, resultExpected)
+	}
+}
+
+func TestAuditResponseWriterStatusCode(t *testing.T) {
+	f := func(writeResponse func(w http.ResponseWriter), statusCodeExpected int) {
+		t.Helper()
+
+		arw := &auditResponseWriter{
+			ResponseWriter: httptest.NewRecorder(),
+		}
+		writeResponse(arw)
+		if statusCode := arw.getStatusCode(); statusCode != statusCodeExpected {
+			t.Fatalf("unexpected status code; got %d; want %d", statusCode, statusCodeExpected)
+		}
+	}
+
+	// the handler doesn't write anything
+	f(func(_ http.ResponseWriter) {}, http.StatusOK)
+
+	// the handler writes only the body
+	f(func(w http.ResponseWriter) {
+		_, _ = w.Write([]byte("foo"))
+	}, http.StatusOK)
+
+	// the handler writes the status code
+	f(func(w http.ResponseWriter) {
+		w.WriteHeader(http.StatusBadGateway)
+		w.WriteHeader(http.StatusOK)
+	}, http.StatusBadGateway)
+}
+
+func TestAuditLogWriter(t *testing.T) {
+	var bb bytes.Buffer
+	aw := &auditLogWriter{
+		w: &bb,
+	}
+	aw.writeLine([]byte(`{"_msg":"foo"}`))
+	aw.writeLine([]byte(`{"_msg":"bar"}`))
+
+	resultExpected := "{\"_msg\":\"foo\"}\n{\"_msg\":\"bar\"}\n"
+	if result := bb.String(); result != resultExpected {
+		t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, resultExpected)
+	}
+}
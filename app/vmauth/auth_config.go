@@ -28,6 +28,7 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/netutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/procutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promutils"
 )
 
 var (
@@ -81,6 +82,13 @@ type UserInfo struct {
 
 	MetricLabels map[string]string `yaml:"metric_labels,omitempty"`
 
+	MaxRequestBodySize     string              `yaml:"max_request_body_size,omitempty"`
+	RequestBodyReadTimeout *promutils.Duration `yaml:"request_body_read_timeout,omitempty"`
+	ResponseWriteTimeout   *promutils.Duration `yaml:"response_write_timeout,omitempty"`
+
+	// maxRequestBodySize is parsed from MaxRequestBodySize
+	maxRequestBodySize int64
+
 	concurrencyLimitCh      chan struct{}
 	concurrencyLimitReached *metrics.Counter
 
@@ -120,6 +128,42 @@ func (ui *UserInfo) getMaxConcurrentRequests() int {
 	return mcr
 }
 
+func (ui *UserInfo) initMaxRequestBodySize() error {
+	if ui.MaxRequestBodySize == "" {
+		return nil
+	}
+	n, err := flagutil.ParseBytes(ui.MaxRequestBodySize)
+	if err != nil {
+		return fmt.Errorf("cannot parse max_request_body_size=%q: %w", ui.MaxRequestBodySize, err)
+	}
+	if n < 0 {
+		return fmt.Errorf("max_request_body_size cannot be negative; got %q", ui.MaxRequestBodySize)
+	}
+	ui.maxRequestBodySize = n
+	return nil
+}
+
+func (ui *UserInfo) getMaxRequestBodySize() int64 {
+	if ui.maxRequestBodySize > 0 {
+		return ui.maxRequestBodySize
+	}
+	return maxRequestBodySize.N
+}
+
+func (ui *UserInfo) getRequestBodyReadTimeout() time.Duration {
+	if d := ui.RequestBodyReadTimeout.Duration(); d > 0 {
+		return d
+	}
+	return *requestBodyReadTimeout
+}
+
+func (ui *UserInfo) getResponseWriteTimeout() time.Duration {
+	if d := ui.ResponseWriteTimeout.Duration(); d > 0 {
+		return d
+	}
+	return *responseWriteTimeout
+}
+
 // Header is `Name: Value` http header, which must be added to the proxied request.
 type Header struct {
 	Name  string
@@ -755,6 +799,9 @@ func parseAuthConfig(data []byte) (*AuthConfig, error) {
 		if err := ui.initURLs(); err != nil {
 			return nil, err
 		}
+		if err := ui.initMaxRequestBodySize(); err != nil {
+			return nil, fmt.Errorf("cannot initialize unauthorized_user: %w", err)
+		}
 
 		metricLabels, err := ui.getMetricLabels()
 		if err != nil {
@@ -802,6 +849,9 @@ func parseAuthConfigUsers(ac *AuthConfig) (map[string]*UserInfo, error) {
 		if err := ui.initURLs(); err != nil {
 			return nil, err
 		}
+		if err := ui.initMaxRequestBodySize(); err != nil {
+			return nil, fmt.Errorf("cannot initialize username=%q, name=%q: %w", ui.Username, ui.Name, err)
+		}
 
 		metricLabels, err := ui.getMetricLabels()
 		if err != nil {
@@ -281,6 +281,27 @@ users:
   metric_labels:
     not-prometheus-compatible: value
 `)
+
+	// Invalid max_request_body_size
+	f(`
+users:
+- username: foo
+  url_prefix: http://foo.bar
+  max_request_body_size: foobar
+`)
+	f(`
+unauthorized_user:
+  url_prefix: http://foo.bar
+  max_request_body_size: -1KiB
+`)
+
+	// Invalid request_body_read_timeout
+	f(`
+users:
+- username: foo
+  url_prefix: http://foo.bar
+  request_body_read_timeout: foobar
+`)
 }
 
 func TestParseAuthConfigSuccess(t *testing.T) {
@@ -14,6 +14,7 @@ import (
 	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/VictoriaMetrics/metrics"
@@ -28,6 +29,7 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/procutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promauth"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/pushmetrics"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/pushprofiles"
 )
 
 var (
@@ -51,6 +53,19 @@ var (
 	failTimeout               = flag.Duration("failTimeout", 3*time.Second, "Sets a delay period for load balancing to skip a malfunctioning backend")
 	maxRequestBodySizeToRetry = flagutil.NewBytes("maxRequestBodySizeToRetry", 16*1024, "The maximum request body size, which can be cached and re-tried at other backends. "+
 		"Bigger values may require more memory. Zero or negative value disables caching of request body. This may be useful when proxying data ingestion requests")
+	maxRequestBodySize = flagutil.NewBytes("maxRequestBodySize", 0, "The maximum request body size vmauth accepts from clients. Requests with bigger bodies are rejected "+
+		"with '413 Request Entity Too Large' http status code. Zero value means no limit. The limit can be overridden with max_request_body_size option in per-user config. "+
+		"See https://docs.victoriametrics.com/vmauth/#request-size-limits-and-timeouts")
+	requestBodyReadTimeout = flag.Duration("requestBodyReadTimeout", 0, "The maximum duration for reading the request body from clients. Requests, which cannot be read in time, "+
+		"are rejected with '408 Request Timeout' http status code. Zero value means no timeout. The timeout can be overridden with request_body_read_timeout option "+
+		"in per-user config. See https://docs.victoriametrics.com/vmauth/#request-size-limits-and-timeouts")
+	maxInflightRequestBodyBytes = flagutil.NewBytes("maxInflightRequestBodyBytes", 0, "The maximum total size of request bodies, which can be processed "+
+		"by vmauth concurrently. Requests exceeding the limit are rejected with '429 Too Many Requests' http status code. "+
+		"The size of requests with Content-Length header is reserved before proxying them to backends. Zero value means no limit. "+
+		"See https://docs.victoriametrics.com/vmauth/#request-size-limits-and-timeouts")
+	responseWriteTimeout = flag.Duration("responseWriteTimeout", 0, "The maximum duration for proxying the response to clients since the start of request processing. "+
+		"The connection to clients, which read the response too slowly, is closed after the timeout. Zero value means no timeout. "+
+		"The timeout can be overridden with response_write_timeout option in per-user config. See https://docs.victoriametrics.com/vmauth/#request-size-limits-and-timeouts")
 	backendTLSInsecureSkipVerify = flag.Bool("backend.tlsInsecureSkipVerify", false, "Whether to skip TLS verification when connecting to backends over HTTPS. "+
 		"See https://docs.victoriametrics.com/vmauth/#backend-tls-setup")
 	backendTLSCAFile = flag.String("backend.TLSCAFile", "", "Optional path to TLS root CA file, which is used for TLS verification when connecting to backends over HTTPS. "+
@@ -78,12 +93,15 @@ func main() {
 	logger.Infof("starting vmauth at %q...", listenAddrs)
 	startTime := time.Now()
 	initAuthConfig()
+	initAuditLog()
 	go httpserver.Serve(listenAddrs, useProxyProtocol, requestHandler)
 	logger.Infof("started vmauth in %.3f seconds", time.Since(startTime).Seconds())
 
 	pushmetrics.Init()
+	pushprofiles.Init()
 	sig := procutil.WaitForSigterm()
 	logger.Infof("received signal %s", sig)
+	pushprofiles.Stop()
 	pushmetrics.Stop()
 
 	startTime = time.Now()
@@ -93,6 +111,7 @@ func main() {
 	}
 	logger.Infof("successfully shut down the webservice in %.3f seconds", time.Since(startTime).Seconds())
 	stopAuthConfig()
+	stopAuditLog()
 	logger.Infof("successfully stopped vmauth in %.3f seconds", time.Since(startTime).Seconds())
 }
 
@@ -113,7 +132,7 @@ func requestHandler(w http.ResponseWriter, r *http.Request) bool {
 		// Process requests for unauthorized users
 		ui := authConfig.Load().UnauthorizedUser
 		if ui != nil {
-			processUserRequest(w, r, ui)
+			processUserRequestWithAuditLog(w, r, ui)
 			return true
 		}
 
@@ -137,7 +156,7 @@ func requestHandler(w http.ResponseWriter, r *http.Request) bool {
 		return true
 	}
 
-	processUserRequest(w, r, ui)
+	processUserRequestWithAuditLog(w, r, ui)
 	return true
 }
 
@@ -199,7 +218,52 @@ func processRequest(w http.ResponseWriter, r *http.Request, ui *UserInfo) {
 		isDefault = true
 	}
 
-	rtb := getReadTrackingBody(r.Body, maxRequestBodySizeToRetry.IntN())
+	var rcw *responseCacheWriter
+	if c := getResponseCache(); c != nil && isCacheableRequest(r, u) {
+		key := getResponseCacheKey(nil, r, u, up, hc, ui)
+		if !strings.Contains(r.Header.Get("Cache-Control"), "no-cache") && writeCachedResponse(w, c, key) {
+			return
+		}
+		rcw = newResponseCacheWriter(w, c, key)
+		w = rcw
+	}
+
+	maxBodySize := ui.getMaxRequestBodySize()
+	if maxBodySize > 0 && r.ContentLength > maxBodySize {
+		err := fmt.Errorf("%w: Content-Length=%d exceeds the limit of %d bytes", errRequestBodyTooLarge, r.ContentLength, maxBodySize)
+		handleRequestBodyError(w, r, err)
+		return
+	}
+	rc := http.NewResponseController(w)
+	if d := ui.getRequestBodyReadTimeout(); d > 0 && r.Body != nil {
+		// Ignore the error, since it is returned only if the underlying connection doesn't support deadlines.
+		_ = rc.SetReadDeadline(time.Now().Add(d))
+	}
+	if d := ui.getResponseWriteTimeout(); d > 0 {
+		_ = rc.SetWriteDeadline(time.Now().Add(d))
+	}
+
+	body := r.Body
+	if maxBodySize > 0 && body != nil {
+		body = &limitedRequestBody{
+			r:       body,
+			maxSize: maxBodySize,
+		}
+	}
+	if maxInflightRequestBodyBytes.N > 0 && body != nil && body != http.NoBody {
+		ib := &inflightRequestBody{
+			r: body,
+		}
+		defer ib.release()
+		if r.ContentLength > 0 && !ib.reserve(r.ContentLength) {
+			// Fast path - reject the request before reading its body.
+			err := fmt.Errorf("%w: cannot reserve %d bytes for the request body", errTooManyInflightRequestBodyBytes, r.ContentLength)
+			handleRequestBodyError(w, r, err)
+			return
+		}
+		body = ib
+	}
+	rtb := getReadTrackingBody(body, maxRequestBodySizeToRetry.IntN())
 	defer putReadTrackingBody(rtb)
 	r.Body = rtb
 
@@ -229,6 +293,9 @@ func processRequest(w http.ResponseWriter, r *http.Request, ui *UserInfo) {
 
 		bu.put()
 		if ok {
+			if rcw != nil {
+				rcw.store()
+			}
 			return
 		}
 		bu.setBroken()
@@ -258,6 +325,11 @@ func tryProcessingRequest(w http.ResponseWriter, r *http.Request, targetURL *url
 	rtb, rtbOK := req.Body.(*readTrackingBody)
 	res, err := ui.rt.RoundTrip(req)
 	if err != nil {
+		if rtbOK && rtb.readErr != nil && isRequestBodyLimitError(rtb.readErr) {
+			// Do not retry requests, which exceed the request body limits
+			handleRequestBodyError(w, r, rtb.readErr)
+			return true, false
+		}
 		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 			// Do not retry canceled or timed out requests
 			remoteAddr := httpserver.GetQuotedRemoteAddr(r)
@@ -324,6 +396,12 @@ func tryProcessingRequest(w http.ResponseWriter, r *http.Request, targetURL *url
 	_, err = io.CopyBuffer(w, res.Body, copyBuf.B)
 	copyBufPool.Put(copyBuf)
 	_ = res.Body.Close()
+	if err != nil {
+		if rcw, ok := w.(*responseCacheWriter); ok {
+			// Do not cache partially proxied response
+			rcw.discard()
+		}
+	}
 	if err != nil && !netutil.IsTrivialNetworkError(err) {
 		remoteAddr := httpserver.GetQuotedRemoteAddr(r)
 		requestURI := httpserver.GetRequestURI(r)
@@ -416,9 +494,13 @@ var hopHeaders = []string{
 }
 
 var (
-	configReloadRequests     = metrics.NewCounter(`vmauth_http_requests_total{path="/-/reload"}`)
-	invalidAuthTokenRequests = metrics.NewCounter(`vmauth_http_request_errors_total{reason="invalid_auth_token"}`)
-	missingRouteRequests     = metrics.NewCounter(`vmauth_http_request_errors_total{reason="missing_route"}`)
+	configReloadRequests        = metrics.NewCounter(`vmauth_http_requests_total{path="/-/reload"}`)
+	invalidAuthTokenRequests    = metrics.NewCounter(`vmauth_http_request_errors_total{reason="invalid_auth_token"}`)
+	missingRouteRequests        = metrics.NewCounter(`vmauth_http_request_errors_total{reason="missing_route"}`)
+	requestBodyTooLargeRequests = metrics.NewCounter(`vmauth_http_request_errors_total{reason="request_body_too_large"}`)
+	requestBodyTimeoutRequests  = metrics.NewCounter(`vmauth_http_request_errors_total{reason="request_body_read_timeout"}`)
+
+	inflightRequestBodyBytesLimitRequests = metrics.NewCounter(`vmauth_http_request_errors_total{reason="inflight_request_body_bytes_limit"}`)
 )
 
 func newRoundTripper(caFileOpt, certFileOpt, keyFileOpt, serverNameOpt string, insecureSkipVerifyP *bool) (http.RoundTripper, error) {
@@ -511,6 +593,113 @@ func handleConcurrencyLimitError(w http.ResponseWriter, r *http.Request, err err
 	httpserver.Errorf(w, r, "%s", err)
 }
 
+var errRequestBodyTooLarge = errors.New("request body is too large")
+
+// limitedRequestBody returns errRequestBodyTooLarge error when more than maxSize bytes are read from r.
+type limitedRequestBody struct {
+	r       io.ReadCloser
+	maxSize int64
+	n       int64
+}
+
+// Read implements io.Reader interface.
+func (lrb *limitedRequestBody) Read(p []byte) (int, error) {
+	n, err := lrb.r.Read(p)
+	lrb.n += int64(n)
+	if lrb.n > lrb.maxSize {
+		return n, fmt.Errorf("%w: it exceeds the limit of %d bytes", errRequestBodyTooLarge, lrb.maxSize)
+	}
+	return n, err
+}
+
+// Close implements io.Closer interface.
+func (lrb *limitedRequestBody) Close() error {
+	return lrb.r.Close()
+}
+
+var errTooManyInflightRequestBodyBytes = errors.New("too many request body bytes are processed concurrently")
+
+var inflightRequestBodyBytes atomic.Int64
+
+var _ = metrics.NewGauge(`vmauth_inflight_request_body_bytes`, func() float64 {
+	return float64(inflightRequestBodyBytes.Load())
+})
+
+// inflightRequestBody accounts the request body size in the global budget set via -maxInflightRequestBodyBytes.
+//
+// The size is reserved before the request processing if it is known from Content-Length header.
+// Otherwise the size is reserved while the request body is read.
+// release must be called when the request processing is finished.
+type inflightRequestBody struct {
+	r io.ReadCloser
+
+	// reserved is the number of bytes reserved in inflightRequestBodyBytes
+	reserved int64
+
+	// n is the number of bytes read from r
+	n int64
+}
+
+// reserve reserves n bytes in the global budget.
+//
+// It returns false if the budget is exceeded.
+func (ib *inflightRequestBody) reserve(n int64) bool {
+	if inflightRequestBodyBytes.Add(n) > maxInflightRequestBodyBytes.N {
+		inflightRequestBodyBytes.Add(-n)
+		return false
+	}
+	ib.reserved += n
+	return true
+}
+
+func (ib *inflightRequestBody) release() {
+	inflightRequestBodyBytes.Add(-ib.reserved)
+	ib.reserved = 0
+}
+
+// Read implements io.Reader interface.
+func (ib *inflightRequestBody) Read(p []byte) (int, error) {
+	n, err := ib.r.Read(p)
+	ib.n += int64(n)
+	if ib.n > ib.reserved && !ib.reserve(ib.n-ib.reserved) {
+		return n, fmt.Errorf("%w: cannot reserve %d bytes for the request body", errTooManyInflightRequestBodyBytes, ib.n)
+	}
+	return n, err
+}
+
+// Close implements io.Closer interface.
+func (ib *inflightRequestBody) Close() error {
+	return ib.r.Close()
+}
+
+// isRequestBodyLimitError returns true if err is caused by request body limits or by request body read timeout.
+func isRequestBodyLimitError(err error) bool {
+	return errors.Is(err, errRequestBodyTooLarge) || errors.Is(err, errTooManyInflightRequestBodyBytes) || errors.Is(err, os.ErrDeadlineExceeded)
+}
+
+func handleRequestBodyError(w http.ResponseWriter, r *http.Request, err error) {
+	statusCode := http.StatusBadRequest
+	switch {
+	case errors.Is(err, errRequestBodyTooLarge):
+		requestBodyTooLargeRequests.Inc()
+		statusCode = http.StatusRequestEntityTooLarge
+		err = fmt.Errorf("%w; see -maxRequestBodySize command-line flag and max_request_body_size option in -auth.config", err)
+	case errors.Is(err, errTooManyInflightRequestBodyBytes):
+		inflightRequestBodyBytesLimitRequests.Inc()
+		statusCode = http.StatusTooManyRequests
+		err = fmt.Errorf("%w; see -maxInflightRequestBodyBytes command-line flag", err)
+	case errors.Is(err, os.ErrDeadlineExceeded):
+		requestBodyTimeoutRequests.Inc()
+		statusCode = http.StatusRequestTimeout
+		err = fmt.Errorf("cannot read request body in time: %w; see -requestBodyReadTimeout command-line flag and request_body_read_timeout option in -auth.config", err)
+	}
+	err = &httpserver.ErrorWithStatusCode{
+		Err:        err,
+		StatusCode: statusCode,
+	}
+	httpserver.Errorf(w, r, "%s", err)
+}
+
 // readTrackingBody must be obtained via getReadTrackingBody()
 type readTrackingBody struct {
 	// maxBodySize is the maximum body size to cache in buf.
@@ -534,6 +723,9 @@ type readTrackingBody struct {
 
 	// bufComplete is set to true when buf contains complete request body read from r.
 	bufComplete bool
+
+	// readErr contains the first error other than io.EOF returned from r.
+	readErr error
 }
 
 func (rtb *readTrackingBody) reset() {
@@ -543,6 +735,7 @@ func (rtb *readTrackingBody) reset() {
 	rtb.readBuf = nil
 	rtb.cannotRetry = false
 	rtb.bufComplete = false
+	rtb.readErr = nil
 }
 
 func getReadTrackingBody(r io.ReadCloser, maxBodySize int) *readTrackingBody {
@@ -597,6 +790,9 @@ func (rtb *readTrackingBody) Read(p []byte) (int, error) {
 	}
 
 	n, err := rtb.r.Read(p)
+	if err != nil && err != io.EOF && rtb.readErr == nil {
+		rtb.readErr = err
+	}
 	if rtb.cannotRetry {
 		return n, err
 	}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"bufio"
 	"bytes"
 	"fmt"
 	"io"
@@ -470,6 +471,219 @@ requested_url={BACKEND}/path2/foo/?de=fg`
 	}
 }
 
+func TestRequestBodyLimits(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, "received %q", data)
+	}))
+	defer ts.Close()
+
+	cfgOrigP := authConfigData.Load()
+	cfgStr := fmt.Sprintf(`
+users:
+- username: foo
+  url_prefix: %s
+  max_request_body_size: 10
+- username: bar
+  url_prefix: %s`, ts.URL, ts.URL)
+	if _, err := reloadAuthConfigData([]byte(cfgStr)); err != nil {
+		t.Fatalf("cannot load config data: %s", err)
+	}
+	defer func() {
+		cfgOrig := []byte("unauthorized_user:\n  url_prefix: http://foo/bar")
+		if cfgOrigP != nil {
+			cfgOrig = *cfgOrigP
+		}
+		if _, err := reloadAuthConfigData(cfgOrig); err != nil {
+			t.Fatalf("cannot load the original config: %s", err)
+		}
+	}()
+
+	f := func(user, body string, knownContentLength bool, responseExpected string) {
+		t.Helper()
+
+		var r *http.Request
+		var err error
+		if knownContentLength {
+			r, err = http.NewRequest(http.MethodPost, "http://vmauth/api/v1/import", strings.NewReader(body))
+		} else {
+			r, err = http.NewRequest(http.MethodPost, "http://vmauth/api/v1/import", io.NopCloser(strings.NewReader(body)))
+			r.ContentLength = -1
+		}
+		if err != nil {
+			t.Fatalf("cannot initialize http request: %s", err)
+		}
+		r.RequestURI = r.URL.RequestURI()
+		r.RemoteAddr = "42.2.3.84:6789"
+		r.SetBasicAuth(user, "")
+
+		w := &fakeResponseWriter{}
+		if !requestHandler(w, r) {
+			t.Fatalf("unexpected false is returned from requestHandler")
+		}
+		response := strings.ReplaceAll(w.getResponse(), "\r\n", "\n")
+		response = strings.TrimSpace(response)
+		if !strings.HasPrefix(response, responseExpected) {
+			t.Fatalf("unexpected response\ngot\n%s\nwant prefix\n%s", response, responseExpected)
+		}
+	}
+
+	// the request body fits the limit
+	f("foo", "0123456789", true, "statusCode=200\nreceived \"0123456789\"")
+	f("foo", "0123456789", false, "statusCode=200\nreceived \"0123456789\"")
+
+	// the request body exceeds the limit
+	f("foo", "0123456789a", true, "statusCode=413")
+	f("foo", "0123456789a", false, "statusCode=413")
+
+	// the limit isn't set for the user
+	f("bar", "0123456789a", true, "statusCode=200\nreceived \"0123456789a\"")
+}
+
+func TestInflightRequestBodyBytesLimit(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, "received %q", data)
+	}))
+	defer ts.Close()
+
+	cfgOrigP := authConfigData.Load()
+	cfgStr := fmt.Sprintf(`
+users:
+- username: foo
+  url_prefix: %s`, ts.URL)
+	if _, err := reloadAuthConfigData([]byte(cfgStr)); err != nil {
+		t.Fatalf("cannot load config data: %s", err)
+	}
+	limitOrig := maxInflightRequestBodyBytes.N
+	maxInflightRequestBodyBytes.N = 10
+
+	// Simulate concurrently processed requests with 5 bytes bodies
+	inflightRequestBodyBytes.Add(5)
+	defer func() {
+		inflightRequestBodyBytes.Add(-5)
+		maxInflightRequestBodyBytes.N = limitOrig
+		cfgOrig := []byte("unauthorized_user:\n  url_prefix: http://foo/bar")
+		if cfgOrigP != nil {
+			cfgOrig = *cfgOrigP
+		}
+		if _, err := reloadAuthConfigData(cfgOrig); err != nil {
+			t.Fatalf("cannot load the original config: %s", err)
+		}
+	}()
+
+	f := func(body string, knownContentLength bool, responseExpected string) {
+		t.Helper()
+
+		var r *http.Request
+		var err error
+		if knownContentLength {
+			r, err = http.NewRequest(http.MethodPost, "http://vmauth/api/v1/import", strings.NewReader(body))
+		} else {
+			r, err = http.NewRequest(http.MethodPost, "http://vmauth/api/v1/import", io.NopCloser(strings.NewReader(body)))
+			r.ContentLength = -1
+		}
+		if err != nil {
+			t.Fatalf("cannot initialize http request: %s", err)
+		}
+		r.RequestURI = r.URL.RequestURI()
+		r.RemoteAddr = "42.2.3.84:6789"
+		r.SetBasicAuth("foo", "")
+
+		w := &fakeResponseWriter{}
+		if !requestHandler(w, r) {
+			t.Fatalf("unexpected false is returned from requestHandler")
+		}
+		response := strings.ReplaceAll(w.getResponse(), "\r\n", "\n")
+		response = strings.TrimSpace(response)
+		if !strings.HasPrefix(response, responseExpected) {
+			t.Fatalf("unexpected response\ngot\n%s\nwant prefix\n%s", response, responseExpected)
+		}
+		if n := inflightRequestBodyBytes.Load(); n != 5 {
+			t.Fatalf("unexpected inflight request body bytes after the request; got %d; want 5", n)
+		}
+	}
+
+	// the request body fits the remaining budget
+	f("01234", true, "statusCode=200\nreceived \"01234\"")
+	f("01234", false, "statusCode=200\nreceived \"01234\"")
+
+	// the request body exceeds the remaining budget
+	f("012345", true, "statusCode=429")
+	f("012345", false, "statusCode=429")
+}
+
+func TestRequestBodyReadTimeout(t *testing.T) {
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer backend.Close()
+
+	cfgOrigP := authConfigData.Load()
+	cfgStr := fmt.Sprintf(`
+users:
+- username: foo
+  url_prefix: %s
+  request_body_read_timeout: 100ms`, backend.URL)
+	if _, err := reloadAuthConfigData([]byte(cfgStr)); err != nil {
+		t.Fatalf("cannot load config data: %s", err)
+	}
+	defer func() {
+		cfgOrig := []byte("unauthorized_user:\n  url_prefix: http://foo/bar")
+		if cfgOrigP != nil {
+			cfgOrig = *cfgOrigP
+		}
+		if _, err := reloadAuthConfigData(cfgOrig); err != nil {
+			t.Fatalf("cannot load the original config: %s", err)
+		}
+	}()
+
+	// The deadlines can be set only for real connections, so run vmauth handler at http server.
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestHandler(w, r)
+	}))
+	defer ts.Close()
+
+	conn, err := net.Dial("tcp", ts.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("cannot connect to vmauth: %s", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	// Send only a part of the request body, so vmauth cannot read it in time.
+	req := "POST /api/v1/import HTTP/1.1\r\n" +
+		"Host: vmauth\r\n" +
+		"Authorization: Basic Zm9vOg==\r\n" +
+		"Content-Length: 100\r\n" +
+		"\r\n" +
+		"partial body"
+	if _, err := io.WriteString(conn, req); err != nil {
+		t.Fatalf("cannot send request: %s", err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatalf("cannot read response: %s", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestTimeout {
+		t.Fatalf("unexpected status code; got %d; want %d", resp.StatusCode, http.StatusRequestTimeout)
+	}
+}
+
 type fakeResponseWriter struct {
 	h http.Header
 
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"flag"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/fastcache"
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fasttime"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+)
+
+var (
+	responseCacheMaxSize = flagutil.NewBytes("responseCache.maxSize", 0, "The maximum size of in-memory cache for responses to GET requests to read APIs "+
+		"such as /api/v1/query and /api/v1/query_range. Responses are cached per each user, so users never see responses obtained for other users. "+
+		"Zero value disables the cache. See https://docs.victoriametrics.com/vmauth/#response-caching")
+	responseCacheTTL = flag.Duration("responseCache.ttl", 30*time.Second, "The duration for keeping the cached response in the cache. "+
+		"See https://docs.victoriametrics.com/vmauth/#response-caching")
+	responseCacheMaxEntrySize = flagutil.NewBytes("responseCache.maxEntrySize", 1024*1024, "The maximum size of a single response, which can be cached. "+
+		"Bigger responses aren't cached. See https://docs.victoriametrics.com/vmauth/#response-caching")
+)
+
+var (
+	responseCache     *fastcache.Cache
+	responseCacheOnce sync.Once
+
+	responseCacheRequests = metrics.NewCounter(`vmauth_response_cache_requests_total`)
+	responseCacheMisses   = metrics.NewCounter(`vmauth_response_cache_misses_total`)
+)
+
+func initResponseCache() {
+	if responseCacheMaxSize.N <= 0 {
+		return
+	}
+	responseCache = fastcache.New(responseCacheMaxSize.IntN())
+
+	var cs fastcache.Stats
+	var csLock sync.Mutex
+	updateStats := func() *fastcache.Stats {
+		csLock.Lock()
+		defer csLock.Unlock()
+		cs.Reset()
+		responseCache.UpdateStats(&cs)
+		return &cs
+	}
+	_ = metrics.NewGauge(`vmauth_response_cache_size_bytes`, func() float64 {
+		return float64(updateStats().BytesSize)
+	})
+	_ = metrics.NewGauge(`vmauth_response_cache_size_max_bytes`, func() float64 {
+		return float64(updateStats().MaxBytesSize)
+	})
+	_ = metrics.NewGauge(`vmauth_response_cache_entries`, func() float64 {
+		return float64(updateStats().EntriesCount)
+	})
+}
+
+func getResponseCache() *fastcache.Cache {
+	responseCacheOnce.Do(initResponseCache)
+	return responseCache
+}
+
+// cacheableReadPathSuffixes contains path suffixes for idempotent read APIs, which responses can be cached.
+var cacheableReadPathSuffixes = []string{
+	"/api/v1/query",
+	"/api/v1/query_range",
+	"/api/v1/series",
+	"/api/v1/labels",
+	"/api/v1/query_exemplars",
+}
+
+// isCacheableRequest returns true if the response for r can be cached.
+func isCacheableRequest(r *http.Request, u *url.URL) bool {
+	if r.Method != http.MethodGet {
+		return false
+	}
+	if u.Query().Get("nocache") == "1" {
+		return false
+	}
+	p := strings.TrimSuffix(u.Path, "/")
+	for _, suffix := range cacheableReadPathSuffixes {
+		if strings.HasSuffix(p, suffix) {
+			return true
+		}
+	}
+	// Handle /api/v1/label/<labelName>/values
+	if strings.HasSuffix(p, "/values") {
+		n := strings.LastIndex(p[:len(p)-len("/values")], "/api/v1/label/")
+		return n >= 0 && !strings.Contains(p[n+len("/api/v1/label/"):len(p)-len("/values")], "/")
+	}
+	return false
+}
+
+// responseCacheAuthKey is a random per-process secret used for hashing user credentials in response cache keys.
+var responseCacheAuthKey = func() []byte {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		logger.Panicf("FATAL: cannot generate random key for response cache: %s", err)
+	}
+	return key
+}()
+
+// getResponseCacheKey returns the cache key for the request to u proxied to up on behalf of ui.
+//
+// The key contains a keyed hash of the user credentials, so they aren't stored in the cache in plaintext, the backends and the request headers the request is proxied with,
+// and the normalized request url with sorted query args.
+func getResponseCacheKey(dst []byte, r *http.Request, u *url.URL, up *URLPrefix, hc HeadersConf, ui *UserInfo) []byte {
+	dst = appendUserAuthHash(dst, ui)
+	for _, bu := range up.busOriginal {
+		dst = encoding.MarshalBytes(dst, bytesutil.ToUnsafeBytes(bu.String()))
+	}
+	dst = append(dst, 0)
+	for _, h := range hc.RequestHeaders {
+		dst = encoding.MarshalBytes(dst, bytesutil.ToUnsafeBytes(h.Name))
+		dst = encoding.MarshalBytes(dst, bytesutil.ToUnsafeBytes(h.Value))
+	}
+	dst = append(dst, 0)
+	// The response encoding depends on Accept-Encoding request header
+	dst = encoding.MarshalBytes(dst, bytesutil.ToUnsafeBytes(r.Header.Get("Accept-Encoding")))
+	dst = encoding.MarshalBytes(dst, bytesutil.ToUnsafeBytes(u.Path))
+	// url.Values.Encode() sorts query args by key
+	dst = encoding.MarshalBytes(dst, bytesutil.ToUnsafeBytes(u.Query().Encode()))
+	return dst
+}
+
+// appendUserAuthHash appends HMAC-SHA256 of ui credentials to dst and returns the result.
+func appendUserAuthHash(dst []byte, ui *UserInfo) []byte {
+	bb := responseCacheBufPool.Get()
+	bb.B = encoding.MarshalBytes(bb.B[:0], bytesutil.ToUnsafeBytes(ui.AuthToken))
+	bb.B = encoding.MarshalBytes(bb.B, bytesutil.ToUnsafeBytes(ui.BearerToken))
+	bb.B = encoding.MarshalBytes(bb.B, bytesutil.ToUnsafeBytes(ui.Username))
+	bb.B = encoding.MarshalBytes(bb.B, bytesutil.ToUnsafeBytes(ui.Password))
+	h := hmac.New(sha256.New, responseCacheAuthKey)
+	_, _ = h.Write(bb.B)
+	responseCacheBufPool.Put(bb)
+	return h.Sum(dst)
+}
+
+// writeCachedResponse writes the cached response for the given key to w.
+//
+// It returns false if the response is missing in the cache or if it is expired.
+func writeCachedResponse(w http.ResponseWriter, c *fastcache.Cache, key []byte) bool {
+	responseCacheRequests.Inc()
+	bb := responseCacheBufPool.Get()
+	defer responseCacheBufPool.Put(bb)
+
+	bb.B = c.GetBig(bb.B[:0], key)
+	if len(bb.B) < 8 || fasttime.UnixTimestamp() > encoding.UnmarshalUint64(bb.B) {
+		responseCacheMisses.Inc()
+		return false
+	}
+	src := bb.B[8:]
+	h := w.Header()
+	for len(src) > 0 && src[0] != 0 {
+		name, nSize := encoding.UnmarshalBytes(src[1:])
+		if nSize <= 0 {
+			logger.Panicf("BUG: cannot unmarshal header name from cached response")
+		}
+		src = src[1+nSize:]
+		value, nSize := encoding.UnmarshalBytes(src)
+		if nSize <= 0 {
+			logger.Panicf("BUG: cannot unmarshal header value from cached response")
+		}
+		src = src[nSize:]
+		h.Add(string(name), string(value))
+	}
+	if len(src) == 0 {
+		logger.Panicf("BUG: missing body in cached response")
+	}
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(src[1:])
+	return true
+}
+
+var responseCacheBufPool bytesutil.ByteBufferPool
+
+// responseCacheWriter stores successful response written to it into the cache.
+type responseCacheWriter struct {
+	http.ResponseWriter
+
+	c   *fastcache.Cache
+	key []byte
+
+	statusCode  int
+	body        []byte
+	maxBodySize int
+	discarded   bool
+}
+
+func newResponseCacheWriter(w http.ResponseWriter, c *fastcache.Cache, key []byte) *responseCacheWriter {
+	return &responseCacheWriter{
+		ResponseWriter: w,
+		c:              c,
+		key:            key,
+		maxBodySize:    responseCacheMaxEntrySize.IntN(),
+	}
+}
+
+// WriteHeader implements http.ResponseWriter interface.
+func (rcw *responseCacheWriter) WriteHeader(statusCode int) {
+	rcw.statusCode = statusCode
+	if statusCode != http.StatusOK {
+		rcw.discard()
+	}
+	if cc := rcw.Header().Get("Cache-Control"); strings.Contains(cc, "no-store") || strings.Contains(cc, "private") {
+		rcw.discard()
+	}
+	rcw.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write implements http.ResponseWriter interface.
+func (rcw *responseCacheWriter) Write(p []byte) (int, error) {
+	if rcw.statusCode == 0 {
+		rcw.WriteHeader(http.StatusOK)
+	}
+	if !rcw.discarded {
+		if len(rcw.body)+len(p) > rcw.maxBodySize {
+			rcw.discard()
+		} else {
+			rcw.body = append(rcw.body, p...)
+		}
+	}
+	return rcw.ResponseWriter.Write(p)
+}
+
+// Flush implements http.Flusher interface.
+func (rcw *responseCacheWriter) Flush() {
+	if f, ok := rcw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying http.ResponseWriter.
+//
+// It is used by http.ResponseController.
+func (rcw *responseCacheWriter) Unwrap() http.ResponseWriter {
+	return rcw.ResponseWriter
+}
+
+// discard prevents from storing the response in the cache.
+//
+// It must be called if the response couldn't be fully proxied to the client.
+func (rcw *responseCacheWriter) discard() {
+	rcw.discarded = true
+	rcw.body = nil
+}
+
+// store stores the written response in the cache if it is cacheable.
+func (rcw *responseCacheWriter) store() {
+	if rcw.discarded || rcw.statusCode != http.StatusOK {
+		return
+	}
+	bb := responseCacheBufPool.Get()
+	defer responseCacheBufPool.Put(bb)
+
+	deadline := fasttime.UnixTimestamp() + uint64(responseCacheTTL.Seconds())
+	dst := encoding.MarshalUint64(bb.B[:0], deadline)
+	h := rcw.Header()
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range h[name] {
+			dst = append(dst, 1)
+			dst = encoding.MarshalBytes(dst, bytesutil.ToUnsafeBytes(name))
+			dst = encoding.MarshalBytes(dst, bytesutil.ToUnsafeBytes(value))
+		}
+	}
+	dst = append(dst, 0)
+	dst = append(dst, rcw.body...)
+	bb.B = dst
+	rcw.c.SetBig(rcw.key, bb.B)
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/VictoriaMetrics/fastcache"
+)
+
+func TestIsCacheableRequest(t *testing.T) {
+	f := func(method, requestURL string, resultExpected bool) {
+		t.Helper()
+
+		r, err := http.NewRequest(method, requestURL, nil)
+		if err != nil {
+			t.Fatalf("cannot initialize http request: %s", err)
+		}
+		u := normalizeURL(r.URL)
+		result := isCacheableRequest(r, u)
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %s %s; got %v; want %v", method, requestURL, result, resultExpected)
+		}
+	}
+
+	f(http.MethodGet, "http://vmauth/api/v1/query?query=up", true)
+	f(http.MethodGet, "http://vmauth/select/0/prometheus/api/v1/query_range?query=up", true)
+	f(http.MethodGet, "http://vmauth/api/v1/series?match[]=up", true)
+	f(http.MethodGet, "http://vmauth/api/v1/labels", true)
+	f(http.MethodGet, "http://vmauth/api/v1/label/job/values", true)
+	f(http.MethodGet, "http://vmauth/api/v1/query/", true)
+
+	// non-GET requests
+	f(http.MethodPost, "http://vmauth/api/v1/query?query=up", false)
+	f(http.MethodDelete, "http://vmauth/api/v1/admin/tsdb/delete_series?match[]=up", false)
+
+	// explicitly disabled caching
+	f(http.MethodGet, "http://vmauth/api/v1/query?query=up&nocache=1", false)
+
+	// non-read APIs
+	f(http.MethodGet, "http://vmauth/api/v1/export?match[]=up", false)
+	f(http.MethodGet, "http://vmauth/api/v1/label/job/foo/values", false)
+	f(http.MethodGet, "http://vmauth/values", false)
+	f(http.MethodGet, "http://vmauth/api/v1/write", false)
+}
+
+func TestResponseCache(t *testing.T) {
+	_ = getResponseCache()
+	responseCacheOrig := responseCache
+	responseCache = fastcache.New(32 * 1024 * 1024)
+	defer func() {
+		responseCache.Reset()
+		responseCache = responseCacheOrig
+	}()
+
+	var backendRequests atomic.Int64
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := backendRequests.Add(1)
+		if r.URL.Query().Get("fail") == "1" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, "error %d", n)
+			return
+		}
+		w.Header().Set("Foo", "bar")
+		fmt.Fprintf(w, "response %d for %s", n, r.URL.RequestURI())
+	}))
+	defer ts.Close()
+
+	cfgOrigP := authConfigData.Load()
+	cfgStr := fmt.Sprintf(`
+users:
+- username: foo
+  url_prefix: %s
+- username: bar
+  url_prefix: %s`, ts.URL, ts.URL)
+	if _, err := reloadAuthConfigData([]byte(cfgStr)); err != nil {
+		t.Fatalf("cannot load config data: %s", err)
+	}
+	defer func() {
+		cfgOrig := []byte("unauthorized_user:\n  url_prefix: http://foo/bar")
+		if cfgOrigP != nil {
+			cfgOrig = *cfgOrigP
+		}
+		if _, err := reloadAuthConfigData(cfgOrig); err != nil {
+			t.Fatalf("cannot load the original config: %s", err)
+		}
+	}()
+
+	f := func(method, user, requestURL, responseExpected string, backendRequestsExpected int64) {
+		t.Helper()
+
+		r, err := http.NewRequest(method, requestURL, nil)
+		if err != nil {
+			t.Fatalf("cannot initialize http request: %s", err)
+		}
+		r.RequestURI = r.URL.RequestURI()
+		r.RemoteAddr = "42.2.3.84:6789"
+		r.SetBasicAuth(user, "")
+
+		w := &fakeResponseWriter{}
+		if !requestHandler(w, r) {
+			t.Fatalf("unexpected false is returned from requestHandler")
+		}
+		response := strings.ReplaceAll(w.getResponse(), "\r\n", "\n")
+		response = strings.TrimSpace(response)
+		responseExpected = strings.TrimSpace(responseExpected)
+		if response != responseExpected {
+			t.Fatalf("unexpected response\ngot\n%s\nwant\n%s", response, responseExpected)
+		}
+		if n := backendRequests.Load(); n != backendRequestsExpected {
+			t.Fatalf("unexpected number of backend requests; got %d; want %d", n, backendRequestsExpected)
+		}
+	}
+
+	// the first request must be proxied to the backend
+	f(http.MethodGet, "foo", "http://vmauth/api/v1/query?query=up&time=123", `
+statusCode=200
+Foo: bar
+response 1 for /api/v1/query?query=up&time=123`, 1)
+
+	// the same request with re-ordered query args must be served from the cache
+	f(http.MethodGet, "foo", "http://vmauth/api/v1/query?time=123&query=up", `
+statusCode=200
+Foo: bar
+response 1 for /api/v1/query?query=up&time=123`, 1)
+
+	// the same request from another user mustn't be served from the cache
+	f(http.MethodGet, "bar", "http://vmauth/api/v1/query?query=up&time=123", `
+statusCode=200
+Foo: bar
+response 2 for /api/v1/query?query=up&time=123`, 2)
+
+	// POST requests mustn't be cached
+	f(http.MethodPost, "foo", "http://vmauth/api/v1/query?query=up&time=123", `
+statusCode=200
+Foo: bar
+response 3 for /api/v1/query?query=up&time=123`, 3)
+
+	// nocache=1 disables the cache
+	f(http.MethodGet, "foo", "http://vmauth/api/v1/query?query=up&time=123&nocache=1", `
+statusCode=200
+Foo: bar
+response 4 for /api/v1/query?nocache=1&query=up&time=123`, 4)
+
+	// unsuccessful responses mustn't be cached
+	f(http.MethodGet, "foo", "http://vmauth/api/v1/query?query=up&fail=1", `
+statusCode=400
+error 5`, 5)
+	f(http.MethodGet, "foo", "http://vmauth/api/v1/query?query=up&fail=1", `
+statusCode=400
+error 6`, 6)
+}
+
+func TestGetResponseCacheKey(t *testing.T) {
+	newKey := func(requestURL, acceptEncoding string, ui *UserInfo) string {
+		t.Helper()
+
+		r, err := http.NewRequest(http.MethodGet, requestURL, nil)
+		if err != nil {
+			t.Fatalf("cannot initialize http request: %s", err)
+		}
+		if acceptEncoding != "" {
+			r.Header.Set("Accept-Encoding", acceptEncoding)
+		}
+		u := normalizeURL(r.URL)
+		up := &URLPrefix{
+			busOriginal: []*url.URL{{Scheme: "http", Host: "backend"}},
+		}
+		return string(getResponseCacheKey(nil, r, u, up, ui.HeadersConf, ui))
+	}
+
+	foo := &UserInfo{Username: "foo", Password: "secret"}
+	fooOtherPassword := &UserInfo{Username: "foo", Password: "other"}
+	bar := &UserInfo{Username: "bar", Password: "secret"}
+
+	k := newKey("http://vmauth/api/v1/query?query=up&time=1", "", foo)
+	if k != newKey("http://vmauth/api/v1/query?time=1&query=up", "", foo) {
+		t.Fatalf("expecting the same key for the request with re-ordered query args")
+	}
+	if k == newKey("http://vmauth/api/v1/query?query=up&time=1", "", bar) {
+		t.Fatalf("expecting distinct keys for distinct users")
+	}
+	if strings.Contains(k, "secret") {
+		t.Fatalf("the key mustn't contain plaintext password; got %q", k)
+	}
+	if k == newKey("http://vmauth/api/v1/query?query=up&time=1", "", fooOtherPassword) {
+		t.Fatalf("expecting distinct keys for distinct passwords")
+	}
+	if k == newKey("http://vmauth/api/v1/query?query=up&time=1", "gzip", foo) {
+		t.Fatalf("expecting distinct keys for distinct Accept-Encoding")
+	}
+	if k == newKey("http://vmauth/api/v1/query?query=up&time=2", "", foo) {
+		t.Fatalf("expecting distinct keys for distinct query args")
+	}
+}
@@ -37,6 +37,19 @@ var (
 	origin            = flag.String("origin", "", "Optional origin directory on the remote storage with old backup for server-side copying when performing full backup. This speeds up full backups")
 	concurrency       = flag.Int("concurrency", 10, "The number of concurrent workers. Higher concurrency may reduce backup duration")
 	maxBytesPerSecond = flagutil.NewBytes("maxBytesPerSecond", 0, "The maximum upload speed. There is no limit if it is set to 0")
+
+	verifySamplePercent = flag.Float64("verify.samplePercent", 1, "The percentage of randomly selected parts to download from -dst when running vmbackup verify command. "+
+		"Only the size of the downloaded parts is checked, since backups do not contain checksums. "+
+		"See https://docs.victoriametrics.com/vmbackup/#backup-verification")
+
+	dstCredsFilePath = flag.String("dst.credsFilePath", "", "Path to file with GCS or S3 credentials for -dst when running vmbackup replicate command. "+
+		"-credsFilePath is used if not set. See https://docs.victoriametrics.com/vmbackup/#backup-replication")
+	dstConfigFilePath = flag.String("dst.configFilePath", "", "Path to file with S3 configs for -dst when running vmbackup replicate command. "+
+		"-configFilePath is used if not set. See https://docs.victoriametrics.com/vmbackup/#backup-replication")
+	dstConfigProfile = flag.String("dst.configProfile", "", "Profile name for S3 configs for -dst when running vmbackup replicate command. "+
+		"-configProfile is used if not set. See https://docs.victoriametrics.com/vmbackup/#backup-replication")
+	dstCustomS3Endpoint = flag.String("dst.customS3Endpoint", "", "Custom S3 endpoint for -dst when running vmbackup replicate command. "+
+		"-customS3Endpoint is used if not set. See https://docs.victoriametrics.com/vmbackup/#backup-replication")
 )
 
 func main() {
@@ -45,10 +58,39 @@ func main() {
 	flag.Usage = usage
 	flagutil.RegisterSecretFlag("snapshot.createURL")
 	flagutil.RegisterSecretFlag("snapshot.deleteURL")
+
+	// `vmbackup verify` runs the verification for the backup at -dst instead of making a backup.
+	// `vmbackup replicate` replicates the backup from -origin to -dst instead of making a backup.
+	command := ""
+	if len(os.Args) > 1 && (os.Args[1] == "verify" || os.Args[1] == "replicate") {
+		command = os.Args[1]
+		os.Args = append(os.Args[:1], os.Args[2:]...)
+	}
 	envflag.Parse()
 	buildinfo.Init()
 	logger.Init()
 
+	if command != "" {
+		listenAddrs := []string{*httpListenAddr}
+		go httpserver.Serve(listenAddrs, nil, nil)
+		pushmetrics.Init()
+		switch command {
+		case "verify":
+			if err := verifyBackup(); err != nil {
+				logger.Fatalf("cannot verify backup: %s", err)
+			}
+		case "replicate":
+			if err := replicateBackup(); err != nil {
+				logger.Fatalf("cannot replicate backup: %s", err)
+			}
+		}
+		pushmetrics.Stop()
+		if err := httpserver.Stop(listenAddrs); err != nil {
+			logger.Fatalf("cannot stop http server for metrics: %s", err)
+		}
+		return
+	}
+
 	// Storing snapshot delete function to be able to call it in case
 	// of error since logger.Fatal will exit the program without
 	// calling deferred functions.
@@ -159,11 +201,65 @@ func makeBackup() error {
 	return nil
 }
 
+func verifyBackup() error {
+	if *verifySamplePercent < 0 || *verifySamplePercent > 100 {
+		return fmt.Errorf("-verify.samplePercent must be in the range [0..100]; got %v", *verifySamplePercent)
+	}
+	srcFS, err := actions.NewRemoteFS(*dst)
+	if err != nil {
+		return fmt.Errorf("cannot parse `-dst`=%q: %w", *dst, err)
+	}
+	a := &actions.Verify{
+		Concurrency:   *concurrency,
+		Src:           srcFS,
+		SamplePercent: *verifySamplePercent,
+	}
+	if err := a.Run(); err != nil {
+		return err
+	}
+	srcFS.MustStop()
+	return nil
+}
+
+func replicateBackup() error {
+	if len(*origin) == 0 {
+		return fmt.Errorf("-origin must be set to the backup to replicate")
+	}
+	srcFS, err := actions.NewRemoteFS(*origin)
+	if err != nil {
+		return fmt.Errorf("cannot parse `-origin`=%q: %w", *origin, err)
+	}
+	dstCfg := &actions.RemoteFSConfig{
+		CredsFilePath:    *dstCredsFilePath,
+		ConfigFilePath:   *dstConfigFilePath,
+		ConfigProfile:    *dstConfigProfile,
+		CustomS3Endpoint: *dstCustomS3Endpoint,
+	}
+	dstFS, err := actions.NewRemoteFSWithConfig(*dst, dstCfg)
+	if err != nil {
+		return fmt.Errorf("cannot parse `-dst`=%q: %w", *dst, err)
+	}
+	a := &actions.Replicate{
+		Concurrency: *concurrency,
+		Src:         srcFS,
+		Dst:         dstFS,
+	}
+	if err := a.Run(); err != nil {
+		return err
+	}
+	srcFS.MustStop()
+	dstFS.MustStop()
+	return nil
+}
+
 func usage() {
 	const s = `
 vmbackup performs backups for VictoriaMetrics data from instant snapshots to gcs, s3, azblob
 or local filesystem. Backed up data can be restored with vmrestore.
 
+Run 'vmbackup verify -dst=...' in order to verify the backup at -dst.
+Run 'vmbackup replicate -origin=... -dst=...' in order to replicate the backup from -origin to -dst.
+
 See the docs at https://docs.victoriametrics.com/vmbackup/ .
 `
 	flagutil.Usage(s)
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/barpool"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/elasticsearch"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/vlogs"
+)
+
+type esProcessor struct {
+	src *elasticsearch.Client
+	dst *vlogs.Importer
+
+	index        string
+	timeField    string
+	msgField     string
+	streamFields []string
+	resumeFile   string
+}
+
+func (ep *esProcessor) run(ctx context.Context) error {
+	ep.dst.ResetStats()
+
+	rs, err := vlogs.ReadResumeState(ep.resumeFile)
+	if err != nil {
+		return err
+	}
+	var after *elasticsearch.Hit
+	if !rs.Time.IsZero() {
+		log.Printf("resuming the migration after the document %q with timestamp %q stored at %q", rs.ID, rs.Time.Format(time.RFC3339Nano), ep.resumeFile)
+		after = &elasticsearch.Hit{
+			Timestamp: rs.Time.Format(time.RFC3339Nano),
+			ID:        rs.ID,
+		}
+	}
+
+	question := fmt.Sprintf("Logs from Elasticsearch index %q will be migrated to VictoriaLogs. Continue?", ep.index)
+	if !prompt(question) {
+		return nil
+	}
+
+	bar := barpool.AddWithTemplate(fmt.Sprintf(barTpl, "Processing documents"), 0)
+	if err := barpool.Start(); err != nil {
+		return err
+	}
+	defer barpool.Stop()
+
+	var ignoreFields []string
+	if ep.timeField != "_time" {
+		// The timestamp is passed in _time field, so drop the original time field.
+		ignoreFields = []string{ep.timeField}
+	}
+	err = ep.src.Read(ctx, after, func(hits []elasticsearch.Hit) error {
+		b := &vlogs.Batch{
+			Lines:        make([][]byte, 0, len(hits)),
+			TimeField:    "_time",
+			MsgField:     ep.msgField,
+			StreamFields: ep.streamFields,
+			IgnoreFields: ignoreFields,
+		}
+		for _, h := range hits {
+			line, err := newESLine(h)
+			if err != nil {
+				return err
+			}
+			b.Lines = append(b.Lines, line)
+		}
+		if err := ep.dst.Import(ctx, b); err != nil {
+			return fmt.Errorf("import process failed: %s", err)
+		}
+		lastHit := hits[len(hits)-1]
+		t, err := time.Parse(time.RFC3339Nano, lastHit.Timestamp)
+		if err != nil {
+			return fmt.Errorf("cannot parse document timestamp %q: %s", lastHit.Timestamp, err)
+		}
+		if err := vlogs.WriteResumeState(ep.resumeFile, vlogs.ResumeState{Time: t, ID: lastHit.ID}); err != nil {
+			return err
+		}
+		bar.Add(len(hits))
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to migrate logs from index %q: %s", ep.index, err)
+	}
+
+	barpool.Stop()
+	log.Println("Import finished!")
+	log.Print(ep.dst.Stats())
+	return nil
+}
+
+// newESLine returns JSON line for the document h with the document timestamp in _time field.
+func newESLine(h elasticsearch.Hit) ([]byte, error) {
+	src := bytes.TrimSpace(h.Source)
+	if len(src) < 2 || src[0] != '{' || src[len(src)-1] != '}' {
+		return nil, fmt.Errorf("unexpected document source %q; want JSON object", h.Source)
+	}
+	line := make([]byte, 0, len(src)+64)
+	line = append(line, `{"_time":`...)
+	line = appendJSONString(line, h.Timestamp)
+	if len(bytes.TrimSpace(src[1:len(src)-1])) > 0 {
+		line = append(line, ',')
+	}
+	line = append(line, src[1:]...)
+	return line, nil
+}
@@ -0,0 +1,210 @@
+package elasticsearch
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/auth"
+)
+
+// Config contains a list of params needed
+// for reading logs from Elasticsearch
+type Config struct {
+	// Addr of the Elasticsearch server
+	Addr string
+	// Index is the index name or pattern to read logs from
+	Index string
+	// Query is an optional Elasticsearch query in JSON format for filtering logs.
+	// All the logs are read if it is empty.
+	Query string
+	// TimeField is the name of the field with log timestamp
+	TimeField string
+	// PageSize is the number of documents to fetch per request
+	PageSize int
+	// AuthCfg contains auth config for requests to Elasticsearch
+	AuthCfg *auth.Config
+	// Transport allows specifying custom http.Transport
+	Transport *http.Transport
+}
+
+// Client is an HTTP client for reading logs
+// from Elasticsearch via search API with search_after pagination
+type Client struct {
+	addr      string
+	index     string
+	query     json.RawMessage
+	timeField string
+	pageSize  int
+	authCfg   *auth.Config
+	c         *http.Client
+}
+
+// Hit represents a single document returned by Elasticsearch
+type Hit struct {
+	// Source contains the original document
+	Source json.RawMessage
+	// Timestamp is the document timestamp in RFC3339 format with nanoseconds precision
+	Timestamp string
+	// ID is the document _id
+	ID string
+}
+
+// NewClient creates and returns Elasticsearch client
+// configured with passed Config
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.Addr == "" {
+		return nil, fmt.Errorf("addr cannot be empty")
+	}
+	if cfg.Index == "" {
+		return nil, fmt.Errorf("index cannot be empty")
+	}
+	if cfg.TimeField == "" {
+		return nil, fmt.Errorf("time field cannot be empty")
+	}
+	if cfg.PageSize < 1 {
+		return nil, fmt.Errorf("page size must be greater than 0; got %d", cfg.PageSize)
+	}
+	var query json.RawMessage
+	if cfg.Query != "" {
+		if !json.Valid([]byte(cfg.Query)) {
+			return nil, fmt.Errorf("query must be a valid JSON; got %q", cfg.Query)
+		}
+		query = json.RawMessage(cfg.Query)
+	}
+	c := &http.Client{}
+	if cfg.Transport != nil {
+		c.Transport = cfg.Transport
+	}
+	return &Client{
+		addr:      strings.TrimSuffix(cfg.Addr, "/"),
+		index:     cfg.Index,
+		query:     query,
+		timeField: cfg.TimeField,
+		pageSize:  cfg.PageSize,
+		authCfg:   cfg.AuthCfg,
+		c:         c,
+	}, nil
+}
+
+// Read reads all the documents in ascending order of (timestamp, _id) and passes them to cb.
+//
+// Documents are read with pages of up to Config.PageSize documents. cb is called for every page.
+// If after isn't nil, then only the documents following after are read. All the documents are read otherwise.
+func (c *Client) Read(ctx context.Context, after *Hit, cb func(hits []Hit) error) error {
+	u := fmt.Sprintf("%s/%s/_search", c.addr, c.index)
+	for {
+		body, err := c.newSearchRequestBody(after)
+		if err != nil {
+			return err
+		}
+		sr, err := c.do(ctx, u, body)
+		if err != nil {
+			return fmt.Errorf("search request failed: %w", err)
+		}
+		if len(sr.Hits.Hits) == 0 {
+			return nil
+		}
+		hits := make([]Hit, 0, len(sr.Hits.Hits))
+		for _, h := range sr.Hits.Hits {
+			if len(h.Sort) == 0 {
+				return fmt.Errorf("missing sort value for the document %q", h.ID)
+			}
+			var ts string
+			if err := json.Unmarshal(h.Sort[0], &ts); err != nil {
+				return fmt.Errorf("cannot parse timestamp for the document %q: %w", h.ID, err)
+			}
+			hits = append(hits, Hit{
+				Source:    h.Source,
+				Timestamp: ts,
+				ID:        h.ID,
+			})
+		}
+		if err := cb(hits); err != nil {
+			return err
+		}
+		if len(hits) < c.pageSize {
+			return nil
+		}
+		after = &hits[len(hits)-1]
+	}
+}
+
+func (c *Client) newSearchRequestBody(after *Hit) ([]byte, error) {
+	req := map[string]any{
+		"size": c.pageSize,
+		"sort": []any{
+			map[string]any{
+				c.timeField: map[string]any{
+					"order": "asc",
+					// Return sort values in RFC3339 format with nanoseconds precision instead of milliseconds since epoch.
+					"format": "strict_date_optional_time_nanos",
+				},
+			},
+			// Documents with identical timestamps are ordered by _id, so the reading can be continued
+			// after any document without skipping or duplicating documents.
+			map[string]any{
+				"_id": "asc",
+			},
+		},
+	}
+	if len(c.query) > 0 {
+		req["query"] = map[string]any{
+			"bool": map[string]any{
+				"filter": []any{c.query},
+			},
+		}
+	}
+	if after != nil {
+		req["search_after"] = []string{after.Timestamp, after.ID}
+	}
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal search request: %w", err)
+	}
+	return data, nil
+}
+
+type searchResponse struct {
+	Hits struct {
+		Hits []struct {
+			ID     string            `json:"_id"`
+			Source json.RawMessage   `json:"_source"`
+			Sort   []json.RawMessage `json:"sort"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+func (c *Client) do(ctx context.Context, u string, body []byte) (*searchResponse, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create request to %q: %s", u, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.authCfg != nil {
+		c.authCfg.SetHeaders(req, true)
+	}
+	resp, err := c.c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected error when performing request to %q: %w", u, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read response from %q: %w", u, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response code %d for %q: %s", resp.StatusCode, u, data)
+	}
+	var sr searchResponse
+	if err := json.Unmarshal(data, &sr); err != nil {
+		return nil, fmt.Errorf("cannot parse response from %q: %w", u, err)
+	}
+	return &sr, nil
+}
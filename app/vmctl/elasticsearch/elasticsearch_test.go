@@ -0,0 +1,127 @@
+package elasticsearch
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestClientRead(t *testing.T) {
+	type doc struct {
+		id     string
+		ts     string
+		source string
+	}
+	// Documents are sorted by (timestamp, _id) like Elasticsearch does.
+	docs := []doc{
+		{"a", "2024-01-01T00:00:00Z", `{"message":"msg 0"}`},
+		{"b", "2024-01-01T00:00:01Z", `{"message":"msg 1"}`},
+		{"c", "2024-01-01T00:00:01Z", `{"message":"msg 2"}`},
+		{"d", "2024-01-01T00:00:01Z", `{"message":"msg 3"}`},
+		{"e", "2024-01-01T00:00:02Z", `{"message":"msg 4"}`},
+	}
+
+	var searchRequests []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/logs-*/_search" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			Size        int      `json:"size"`
+			SearchAfter []string `json:"search_after"`
+		}
+		if err := json.Unmarshal(body, &req); err != nil {
+			t.Errorf("cannot parse search request: %s", err)
+		}
+		searchRequests = append(searchRequests, string(body))
+
+		pos := 0
+		if len(req.SearchAfter) == 2 {
+			for pos < len(docs) && (docs[pos].ts < req.SearchAfter[0] || docs[pos].ts == req.SearchAfter[0] && docs[pos].id <= req.SearchAfter[1]) {
+				pos++
+			}
+		}
+		hits := []map[string]any{}
+		for i := 0; i < req.Size && pos < len(docs); i++ {
+			d := docs[pos]
+			hits = append(hits, map[string]any{
+				"_id":     d.id,
+				"_source": json.RawMessage(d.source),
+				"sort":    []string{d.ts, d.id},
+			})
+			pos++
+		}
+		resp := map[string]any{
+			"hits": map[string]any{
+				"hits": hits,
+			},
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	c, err := NewClient(Config{
+		Addr:      srv.URL,
+		Index:     "logs-*",
+		Query:     `{"term":{"service":"nginx"}}`,
+		TimeField: "@timestamp",
+		PageSize:  2,
+	})
+	if err != nil {
+		t.Fatalf("cannot create client: %s", err)
+	}
+
+	f := func(after *Hit, idsExpected []string) {
+		t.Helper()
+
+		searchRequests = searchRequests[:0]
+		var ids []string
+		err := c.Read(context.Background(), after, func(hits []Hit) error {
+			for _, h := range hits {
+				ids = append(ids, fmt.Sprintf("%s@%s:%s", h.ID, h.Timestamp, h.Source))
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		var expected []string
+		for _, id := range idsExpected {
+			for _, d := range docs {
+				if d.id == id {
+					expected = append(expected, fmt.Sprintf("%s@%s:%s", d.id, d.ts, d.source))
+				}
+			}
+		}
+		if !reflect.DeepEqual(ids, expected) {
+			t.Fatalf("unexpected documents; got %q; want %q", ids, expected)
+		}
+	}
+
+	// read all the documents
+	f(nil, []string{"a", "b", "c", "d", "e"})
+
+	requestExpected := `{"query":{"bool":{"filter":[{"term":{"service":"nginx"}}]}},` +
+		`"size":2,"sort":[{"@timestamp":{"format":"strict_date_optional_time_nanos","order":"asc"}},{"_id":"asc"}]}`
+	if searchRequests[0] != requestExpected {
+		t.Fatalf("unexpected search request\ngot\n%s\nwant\n%s", searchRequests[0], requestExpected)
+	}
+	requestExpected = `{"query":{"bool":{"filter":[{"term":{"service":"nginx"}}]}},"search_after":["2024-01-01T00:00:01Z","b"],` +
+		`"size":2,"sort":[{"@timestamp":{"format":"strict_date_optional_time_nanos","order":"asc"}},{"_id":"asc"}]}`
+	if searchRequests[1] != requestExpected {
+		t.Fatalf("unexpected search request\ngot\n%s\nwant\n%s", searchRequests[1], requestExpected)
+	}
+
+	// resume in the middle of documents with identical timestamps
+	f(&Hit{Timestamp: "2024-01-01T00:00:01Z", ID: "c"}, []string{"d", "e"})
+
+	// resume after the last document
+	f(&Hit{Timestamp: "2024-01-01T00:00:02Z", ID: "e"}, nil)
+}
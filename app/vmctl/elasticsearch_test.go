@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/elasticsearch"
+)
+
+func TestNewESLine_Success(t *testing.T) {
+	f := func(source, timestamp, resultExpected string) {
+		t.Helper()
+
+		result, err := newESLine(elasticsearch.Hit{
+			Source:    []byte(source),
+			Timestamp: timestamp,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if string(result) != resultExpected {
+			t.Fatalf("unexpected result; got %s; want %s", result, resultExpected)
+		}
+	}
+
+	f(`{"message":"foo","@timestamp":"2024-01-01T00:00:00Z"}`, "2024-01-01T00:00:00.000000000Z",
+		`{"_time":"2024-01-01T00:00:00.000000000Z","message":"foo","@timestamp":"2024-01-01T00:00:00Z"}`)
+	f(`{}`, "2024-01-01T00:00:00Z", `{"_time":"2024-01-01T00:00:00Z"}`)
+}
+
+func TestNewESLine_Failure(t *testing.T) {
+	f := func(source string) {
+		t.Helper()
+
+		if _, err := newESLine(elasticsearch.Hit{Source: []byte(source)}); err == nil {
+			t.Fatalf("expecting non-nil error for %s", source)
+		}
+	}
+
+	f(``)
+	f(`[1,2]`)
+	f(`"foo"`)
+}
@@ -453,6 +453,9 @@ const (
 	vmNativeBackoffRetries     = "vm-native-backoff-retries"
 	vmNativeBackoffFactor      = "vm-native-backoff-factor"
 	vmNativeBackoffMinDuration = "vm-native-backoff-min-duration"
+
+	vmNativeTenantMap     = "vm-native-tenant-map"
+	vmNativeRelabelConfig = "vm-native-relabel-config"
 )
 
 var (
@@ -604,6 +607,18 @@ var (
 				fmt.Sprintf(" In this mode --%s flag format is: 'http://vmselect:8481/'. --%s flag format is: http://vminsert:8480/. \n", vmNativeSrcAddr, vmNativeDstAddr) +
 				" TenantID will be appended automatically after discovering tenants from src.",
 		},
+		&cli.StringSliceFlag{
+			Name: vmNativeTenantMap,
+			Usage: fmt.Sprintf("Optional mapping of source tenants to destination tenants in cluster-to-cluster migration mode enabled via --%s. ", vmInterCluster) +
+				"The mapping must be in the format 'srcTenant=dstTenant', for example, '1:0=2:5' or '3=4'. " +
+				"Flag can be set multiple times. Tenants without the mapping are migrated to the same tenants at destination.",
+		},
+		&cli.StringFlag{
+			Name: vmNativeRelabelConfig,
+			Usage: "Optional path to a file with relabeling rules, which are applied to the migrated time series. " +
+				"See https://docs.victoriametrics.com/vmagent/#relabeling . " +
+				fmt.Sprintf("Relabeling requires --%s, since data blocks in native binary format cannot be modified on the fly.", vmNativeDisableBinaryProtocol),
+		},
 		&cli.UintFlag{
 			Name:  vmConcurrency,
 			Usage: "Number of workers concurrently performing import requests to VM",
@@ -766,6 +781,283 @@ var (
 	}
 )
 
+const (
+	vlogsAddr               = "vlogs-addr"
+	vlogsUser               = "vlogs-user"
+	vlogsPassword           = "vlogs-password"
+	vlogsCompress           = "vlogs-compress"
+	vlogsBatchSize          = "vlogs-batch-size"
+	vlogsExtraField         = "vlogs-extra-field"
+	vlogsRateLimit          = "vlogs-rate-limit"
+	vlogsResumeFile         = "vlogs-resume-file"
+	vlogsCertFile           = "vlogs-cert-file"
+	vlogsKeyFile            = "vlogs-key-file"
+	vlogsCAFile             = "vlogs-CA-file"
+	vlogsServerName         = "vlogs-server-name"
+	vlogsInsecureSkipVerify = "vlogs-insecure-skip-verify"
+	vlogsBackoffRetries     = "vlogs-backoff-retries"
+	vlogsBackoffFactor      = "vlogs-backoff-factor"
+	vlogsBackoffMinDuration = "vlogs-backoff-min-duration"
+)
+
+var (
+	vlogsFlags = []cli.Flag{
+		&cli.StringFlag{
+			Name:  vlogsAddr,
+			Value: "http://localhost:9428",
+			Usage: "VictoriaLogs address to perform import requests. \n" +
+				"Please note, that `vmctl` performs initial readiness check for the given address by checking `/health` endpoint.",
+		},
+		&cli.StringFlag{
+			Name:    vlogsUser,
+			Usage:   "VictoriaLogs username for basic auth",
+			EnvVars: []string{"VLOGS_USERNAME"},
+		},
+		&cli.StringFlag{
+			Name:    vlogsPassword,
+			Usage:   "VictoriaLogs password for basic auth",
+			EnvVars: []string{"VLOGS_PASSWORD"},
+		},
+		&cli.BoolFlag{
+			Name:  vlogsCompress,
+			Value: true,
+			Usage: "Whether to apply gzip compression to import requests",
+		},
+		&cli.IntFlag{
+			Name:  vlogsBatchSize,
+			Value: 10e3,
+			Usage: "How many log entries importer sends in a single import request to VictoriaLogs",
+		},
+		&cli.StringSliceFlag{
+			Name:  vlogsExtraField,
+			Value: nil,
+			Usage: "Extra fields in the form 'field=value', which will be added to all the imported log entries. " +
+				"Flag can be set multiple times, to add few additional fields.",
+		},
+		&cli.Int64Flag{
+			Name: vlogsRateLimit,
+			Usage: "Optional data transfer rate limit in bytes per second.\n" +
+				"By default, the rate limit is disabled. It can be useful for limiting load on configured via '--vlogs-addr' destination.",
+		},
+		&cli.StringFlag{
+			Name: vlogsResumeFile,
+			Usage: "Optional path to file for storing the migration progress. If the file exists, then the migration is resumed " +
+				"from the position stored in it. This allows continuing the interrupted migration without importing already migrated logs again",
+		},
+		&cli.StringFlag{
+			Name:  vlogsCertFile,
+			Usage: "Optional path to client-side TLS certificate file to use when connecting to '--vlogs-addr'",
+		},
+		&cli.StringFlag{
+			Name:  vlogsKeyFile,
+			Usage: "Optional path to client-side TLS key to use when connecting to '--vlogs-addr'",
+		},
+		&cli.StringFlag{
+			Name:  vlogsCAFile,
+			Usage: "Optional path to TLS CA file to use for verifying connections to '--vlogs-addr'. By default, system CA is used",
+		},
+		&cli.StringFlag{
+			Name:  vlogsServerName,
+			Usage: "Optional TLS server name to use for connections to '--vlogs-addr'. By default, the server name from '--vlogs-addr' is used",
+		},
+		&cli.BoolFlag{
+			Name:  vlogsInsecureSkipVerify,
+			Usage: "Whether to skip tls verification when connecting to '--vlogs-addr'",
+			Value: false,
+		},
+		&cli.IntFlag{
+			Name:  vlogsBackoffRetries,
+			Value: 10,
+			Usage: "How many import retries to perform before giving up.",
+		},
+		&cli.Float64Flag{
+			Name:  vlogsBackoffFactor,
+			Value: 1.8,
+			Usage: "Factor to multiply the base duration after each failed import retry. Must be greater than 1.0",
+		},
+		&cli.DurationFlag{
+			Name:  vlogsBackoffMinDuration,
+			Value: time.Second * 2,
+			Usage: "Minimum duration to wait before the first import retry. Each subsequent import retry will be multiplied by the '--vlogs-backoff-factor'.",
+		},
+	}
+)
+
+const (
+	lokiAddr               = "loki-addr"
+	lokiUser               = "loki-user"
+	lokiPassword           = "loki-password"
+	lokiOrgID              = "loki-org-id"
+	lokiQuery              = "loki-query"
+	lokiLimit              = "loki-limit"
+	lokiFilterTimeStart    = "loki-filter-time-start"
+	lokiFilterTimeEnd      = "loki-filter-time-end"
+	lokiStepInterval       = "loki-step-interval"
+	lokiCertFile           = "loki-cert-file"
+	lokiKeyFile            = "loki-key-file"
+	lokiCAFile             = "loki-CA-file"
+	lokiServerName         = "loki-server-name"
+	lokiInsecureSkipVerify = "loki-insecure-skip-verify"
+)
+
+var (
+	lokiFlags = []cli.Flag{
+		&cli.StringFlag{
+			Name:     lokiAddr,
+			Usage:    "Loki address to read logs from. E.g. http://localhost:3100",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:    lokiUser,
+			Usage:   "Loki username for basic auth",
+			EnvVars: []string{"LOKI_USERNAME"},
+		},
+		&cli.StringFlag{
+			Name:    lokiPassword,
+			Usage:   "Loki password for basic auth",
+			EnvVars: []string{"LOKI_PASSWORD"},
+		},
+		&cli.StringFlag{
+			Name:  lokiOrgID,
+			Usage: "Optional Loki tenant to read logs from. It is sent via 'X-Scope-OrgID' header",
+		},
+		&cli.StringFlag{
+			Name:     lokiQuery,
+			Usage:    "LogQL stream selector for logs to migrate. E.g. '{job=~\".+\"}'",
+			Required: true,
+		},
+		&cli.IntFlag{
+			Name:  lokiLimit,
+			Usage: "The maximum number of log entries to read from Loki per request. It must not exceed 'max_entries_limit_per_query' limit at Loki",
+			Value: 5000,
+		},
+		&cli.TimestampFlag{
+			Name:     lokiFilterTimeStart,
+			Usage:    "The time filter in RFC3339 format to select logs with timestamp equal or higher than provided value. E.g. '2020-01-01T20:07:00Z'",
+			Layout:   time.RFC3339,
+			Required: true,
+		},
+		&cli.TimestampFlag{
+			Name:   lokiFilterTimeEnd,
+			Usage:  "The time filter in RFC3339 format to select logs with timestamp lower than provided value. E.g. '2020-01-01T20:07:00Z'. By default, the current time is used",
+			Layout: time.RFC3339,
+		},
+		&cli.StringFlag{
+			Name: lokiStepInterval,
+			Usage: fmt.Sprintf("The time interval to split the migration into steps. Valid values are '%s','%s','%s','%s','%s'.",
+				stepper.StepMonth, stepper.StepWeek, stepper.StepDay, stepper.StepHour, stepper.StepMinute),
+			Value: stepper.StepDay,
+		},
+		&cli.StringFlag{
+			Name:  lokiCertFile,
+			Usage: "Optional path to client-side TLS certificate file to use when connecting to '--loki-addr'",
+		},
+		&cli.StringFlag{
+			Name:  lokiKeyFile,
+			Usage: "Optional path to client-side TLS key to use when connecting to '--loki-addr'",
+		},
+		&cli.StringFlag{
+			Name:  lokiCAFile,
+			Usage: "Optional path to TLS CA file to use for verifying connections to '--loki-addr'. By default, system CA is used",
+		},
+		&cli.StringFlag{
+			Name:  lokiServerName,
+			Usage: "Optional TLS server name to use for connections to '--loki-addr'. By default, the server name from '--loki-addr' is used",
+		},
+		&cli.BoolFlag{
+			Name:  lokiInsecureSkipVerify,
+			Usage: "Whether to skip tls verification when connecting to '--loki-addr'",
+			Value: false,
+		},
+	}
+)
+
+const (
+	esAddr               = "es-addr"
+	esUser               = "es-user"
+	esPassword           = "es-password"
+	esIndex              = "es-index"
+	esQuery              = "es-query"
+	esTimeField          = "es-time-field"
+	esMsgField           = "es-msg-field"
+	esStreamFields       = "es-stream-fields"
+	esPageSize           = "es-page-size"
+	esCertFile           = "es-cert-file"
+	esKeyFile            = "es-key-file"
+	esCAFile             = "es-CA-file"
+	esServerName         = "es-server-name"
+	esInsecureSkipVerify = "es-insecure-skip-verify"
+)
+
+var (
+	esFlags = []cli.Flag{
+		&cli.StringFlag{
+			Name:     esAddr,
+			Usage:    "Elasticsearch address to read logs from. E.g. http://localhost:9200",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:    esUser,
+			Usage:   "Elasticsearch username for basic auth",
+			EnvVars: []string{"ES_USERNAME"},
+		},
+		&cli.StringFlag{
+			Name:    esPassword,
+			Usage:   "Elasticsearch password for basic auth",
+			EnvVars: []string{"ES_PASSWORD"},
+		},
+		&cli.StringFlag{
+			Name:     esIndex,
+			Usage:    "Elasticsearch index name or pattern to read logs from. E.g. 'logs-*'",
+			Required: true,
+		},
+		&cli.StringFlag{
+			Name:  esQuery,
+			Usage: "Optional Elasticsearch query in JSON format for selecting logs to migrate. E.g. '{\"term\":{\"service\":\"nginx\"}}'. By default, all the logs are migrated",
+		},
+		&cli.StringFlag{
+			Name:  esTimeField,
+			Usage: "The name of the field with log timestamp",
+			Value: "@timestamp",
+		},
+		&cli.StringFlag{
+			Name:  esMsgField,
+			Usage: "The name of the field with log message",
+			Value: "message",
+		},
+		&cli.StringSliceFlag{
+			Name:  esStreamFields,
+			Usage: "The names of fields to use as log stream fields in VictoriaLogs. See https://docs.victoriametrics.com/victorialogs/keyconcepts/#stream-fields",
+		},
+		&cli.IntFlag{
+			Name:  esPageSize,
+			Usage: "The number of documents to read from Elasticsearch per request",
+			Value: 1000,
+		},
+		&cli.StringFlag{
+			Name:  esCertFile,
+			Usage: "Optional path to client-side TLS certificate file to use when connecting to '--es-addr'",
+		},
+		&cli.StringFlag{
+			Name:  esKeyFile,
+			Usage: "Optional path to client-side TLS key to use when connecting to '--es-addr'",
+		},
+		&cli.StringFlag{
+			Name:  esCAFile,
+			Usage: "Optional path to TLS CA file to use for verifying connections to '--es-addr'. By default, system CA is used",
+		},
+		&cli.StringFlag{
+			Name:  esServerName,
+			Usage: "Optional TLS server name to use for connections to '--es-addr'. By default, the server name from '--es-addr' is used",
+		},
+		&cli.BoolFlag{
+			Name:  esInsecureSkipVerify,
+			Usage: "Whether to skip tls verification when connecting to '--es-addr'",
+			Value: false,
+		},
+	}
+)
+
 func mergeFlags(flags ...[]cli.Flag) []cli.Flag {
 	var result []cli.Flag
 	for _, f := range flags {
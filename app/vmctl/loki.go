@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/barpool"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/loki"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/stepper"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/vlogs"
+)
+
+type lokiProcessor struct {
+	filter lokiFilter
+
+	src *loki.Client
+	dst *vlogs.Importer
+
+	resumeFile string
+}
+
+type lokiFilter struct {
+	timeStart *time.Time
+	timeEnd   *time.Time
+	chunk     string
+}
+
+func (lp *lokiProcessor) run(ctx context.Context) error {
+	lp.dst.ResetStats()
+	start := *lp.filter.timeStart
+	end := time.Now().In(start.Location())
+	if lp.filter.timeEnd != nil {
+		end = *lp.filter.timeEnd
+	}
+
+	rs, err := vlogs.ReadResumeState(lp.resumeFile)
+	if err != nil {
+		return err
+	}
+	if rs.Time.After(start) {
+		log.Printf("resuming the migration from %q stored at %q", rs.Time.Format(time.RFC3339Nano), lp.resumeFile)
+		start = rs.Time.In(start.Location())
+	}
+	if !start.Before(end) {
+		log.Printf("nothing to migrate on the time range %q - %q", start.Format(time.RFC3339Nano), end.Format(time.RFC3339Nano))
+		return nil
+	}
+
+	ranges, err := stepper.SplitDateRange(start, end, lp.filter.chunk, false)
+	if err != nil {
+		return fmt.Errorf("failed to create date ranges for the given time filters: %v", err)
+	}
+
+	question := fmt.Sprintf("Selected time range %q - %q will be split into %d ranges according to %q step. Continue?",
+		start.String(), end.String(), len(ranges), lp.filter.chunk)
+	if !prompt(question) {
+		return nil
+	}
+
+	bar := barpool.AddWithTemplate(fmt.Sprintf(barTpl, "Processing ranges"), len(ranges))
+	if err := barpool.Start(); err != nil {
+		return err
+	}
+	defer barpool.Stop()
+
+	for _, r := range ranges {
+		err := lp.src.Read(ctx, r[0], r[1], func(streams []loki.Stream) error {
+			for _, s := range streams {
+				b := newLokiBatch(s)
+				if err := lp.dst.Import(ctx, b); err != nil {
+					return fmt.Errorf("import process failed: %s", err)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to migrate logs for time range %q - %q: %s", r[0].Format(time.RFC3339), r[1].Format(time.RFC3339), err)
+		}
+		if err := vlogs.WriteResumeState(lp.resumeFile, vlogs.ResumeState{Time: r[1]}); err != nil {
+			return err
+		}
+		bar.Increment()
+	}
+
+	barpool.Stop()
+	log.Println("Import finished!")
+	log.Print(lp.dst.Stats())
+	return nil
+}
+
+// newLokiBatch converts Loki stream s into a batch for importing into VictoriaLogs.
+//
+// Stream labels are converted into log stream fields.
+func newLokiBatch(s loki.Stream) *vlogs.Batch {
+	streamFields := make([]string, 0, len(s.Labels))
+	for name := range s.Labels {
+		streamFields = append(streamFields, name)
+	}
+	sort.Strings(streamFields)
+
+	var labels []byte
+	for _, name := range streamFields {
+		labels = appendJSONString(labels, name)
+		labels = append(labels, ':')
+		labels = appendJSONString(labels, s.Labels[name])
+		labels = append(labels, ',')
+	}
+
+	lines := make([][]byte, 0, len(s.Entries))
+	for _, e := range s.Entries {
+		line := make([]byte, 0, len(labels)+len(e.Line)+64)
+		line = append(line, '{')
+		line = append(line, labels...)
+		line = append(line, `"_time":`...)
+		line = appendJSONString(line, time.Unix(0, e.Timestamp).UTC().Format(time.RFC3339Nano))
+		line = append(line, `,"_msg":`...)
+		line = appendJSONString(line, e.Line)
+		line = append(line, '}')
+		lines = append(lines, line)
+	}
+	return &vlogs.Batch{
+		Lines:        lines,
+		TimeField:    "_time",
+		MsgField:     "_msg",
+		StreamFields: streamFields,
+	}
+}
+
+func appendJSONString(dst []byte, s string) []byte {
+	// json.Marshal cannot fail for strings
+	b, _ := json.Marshal(s)
+	return append(dst, b...)
+}
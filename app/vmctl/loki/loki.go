@@ -0,0 +1,277 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/auth"
+)
+
+// Config contains a list of params needed
+// for reading logs from Loki
+type Config struct {
+	// Addr of the Loki server
+	Addr string
+	// Query is LogQL stream selector for logs to migrate. For example, {job=~".+"}
+	Query string
+	// Limit is the maximum number of log entries to fetch per request
+	Limit int
+	// OrgID is an optional tenant ID to send via X-Scope-OrgID header
+	OrgID string
+	// AuthCfg contains auth config for requests to Loki
+	AuthCfg *auth.Config
+	// Transport allows specifying custom http.Transport
+	Transport *http.Transport
+}
+
+// Client is an HTTP client for reading logs
+// from Loki via query_range API
+type Client struct {
+	addr    string
+	query   string
+	limit   int
+	orgID   string
+	authCfg *auth.Config
+	c       *http.Client
+}
+
+// Stream represents a log stream returned by Loki
+type Stream struct {
+	// Labels contains stream labels
+	Labels map[string]string
+	// Entries contains log entries for the stream sorted by timestamp
+	Entries []Entry
+}
+
+// Entry represents a single log entry
+type Entry struct {
+	// Timestamp in nanoseconds
+	Timestamp int64
+	// Line is the log line
+	Line string
+}
+
+// NewClient creates and returns Loki client
+// configured with passed Config
+func NewClient(cfg Config) (*Client, error) {
+	if cfg.Addr == "" {
+		return nil, fmt.Errorf("addr cannot be empty")
+	}
+	if cfg.Query == "" {
+		return nil, fmt.Errorf("query cannot be empty")
+	}
+	if cfg.Limit < 1 {
+		return nil, fmt.Errorf("limit must be greater than 0; got %d", cfg.Limit)
+	}
+	c := &http.Client{}
+	if cfg.Transport != nil {
+		c.Transport = cfg.Transport
+	}
+	return &Client{
+		addr:    strings.TrimSuffix(cfg.Addr, "/"),
+		query:   cfg.Query,
+		limit:   cfg.Limit,
+		orgID:   cfg.OrgID,
+		authCfg: cfg.AuthCfg,
+		c:       c,
+	}, nil
+}
+
+// Read reads all the log entries on the time range [start, end) and passes them to cb.
+//
+// Entries are read in ascending order of timestamps with pages of up to Config.Limit entries.
+// cb is called for every page.
+func (c *Client) Read(ctx context.Context, start, end time.Time, cb func(streams []Stream) error) error {
+	startNs := start.UnixNano()
+	endNs := end.UnixNano()
+
+	// seen contains keys for already read entries with the timestamp equal to startNs.
+	// It is used for skipping duplicate entries, since the next page starts at the maximum timestamp from the previous page.
+	seen := make(map[string]struct{})
+	for startNs < endNs {
+		streams, err := c.queryRange(ctx, startNs, endNs, c.limit)
+		if err != nil {
+			return err
+		}
+		entries, maxTs := getEntriesStats(streams, startNs)
+		isLastPage := entries < c.limit
+		if !isLastPage && maxTs == startNs {
+			// All the entries on the page have the same timestamp, so the remaining entries with this timestamp
+			// cannot be read by moving the start of the time range. Read all the entries with this timestamp at once
+			// by increasing the limit and then move to the next timestamp.
+			limit := c.limit
+			for entries >= limit {
+				limit *= 2
+				streams, err = c.queryRange(ctx, startNs, startNs+1, limit)
+				if err != nil {
+					return fmt.Errorf("cannot read %d+ log entries with the timestamp %d: %w", entries, startNs, err)
+				}
+				entries, _ = getEntriesStats(streams, startNs)
+			}
+			maxTs = startNs + 1
+		}
+
+		nextSeen := make(map[string]struct{})
+		newStreams := streams[:0]
+		for _, s := range streams {
+			labelsKey := streamKey(s.Labels)
+			newEntries := s.Entries[:0]
+			for _, e := range s.Entries {
+				k := entryKey(labelsKey, e)
+				if e.Timestamp == startNs {
+					if _, ok := seen[k]; ok {
+						continue
+					}
+				}
+				if e.Timestamp == maxTs {
+					nextSeen[k] = struct{}{}
+				}
+				newEntries = append(newEntries, e)
+			}
+			if len(newEntries) > 0 {
+				s.Entries = newEntries
+				newStreams = append(newStreams, s)
+			}
+		}
+		if len(newStreams) > 0 {
+			if err := cb(newStreams); err != nil {
+				return err
+			}
+		}
+
+		if isLastPage {
+			return nil
+		}
+		seen = nextSeen
+		startNs = maxTs
+	}
+	return nil
+}
+
+// getEntriesStats returns the number of entries in streams and the maximum timestamp across these entries.
+//
+// minTs is returned as the maximum timestamp if streams have no entries.
+func getEntriesStats(streams []Stream, minTs int64) (int, int64) {
+	entries := 0
+	maxTs := minTs
+	for _, s := range streams {
+		entries += len(s.Entries)
+		for _, e := range s.Entries {
+			if e.Timestamp > maxTs {
+				maxTs = e.Timestamp
+			}
+		}
+	}
+	return entries, maxTs
+}
+
+type queryRangeResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string `json:"resultType"`
+		Result     []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][]string        `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+func (c *Client) queryRange(ctx context.Context, startNs, endNs int64, limit int) ([]Stream, error) {
+	u := c.addr + "/loki/api/v1/query_range"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create request to %q: %s", u, err)
+	}
+	params := req.URL.Query()
+	params.Set("query", c.query)
+	params.Set("start", strconv.FormatInt(startNs, 10))
+	params.Set("end", strconv.FormatInt(endNs, 10))
+	params.Set("limit", strconv.Itoa(limit))
+	params.Set("direction", "forward")
+	req.URL.RawQuery = params.Encode()
+	if c.orgID != "" {
+		req.Header.Set("X-Scope-OrgID", c.orgID)
+	}
+	if c.authCfg != nil {
+		c.authCfg.SetHeaders(req, true)
+	}
+
+	resp, err := c.c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected error when performing request to %q: %w", u, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read response from %q: %w", u, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected response code %d for %q: %s", resp.StatusCode, u, body)
+	}
+	return parseQueryRangeResponse(body)
+}
+
+func parseQueryRangeResponse(data []byte) ([]Stream, error) {
+	var qrr queryRangeResponse
+	if err := json.Unmarshal(data, &qrr); err != nil {
+		return nil, fmt.Errorf("cannot parse query_range response: %w", err)
+	}
+	if qrr.Status != "success" {
+		return nil, fmt.Errorf("unexpected status %q in query_range response: %s", qrr.Status, qrr.Error)
+	}
+	if qrr.Data.ResultType != "streams" {
+		return nil, fmt.Errorf("unexpected resultType %q in query_range response; want %q; make sure the query is a log query", qrr.Data.ResultType, "streams")
+	}
+	streams := make([]Stream, 0, len(qrr.Data.Result))
+	for _, r := range qrr.Data.Result {
+		s := Stream{
+			Labels:  r.Stream,
+			Entries: make([]Entry, 0, len(r.Values)),
+		}
+		for _, v := range r.Values {
+			if len(v) < 2 {
+				return nil, fmt.Errorf("unexpected number of items in log entry %q; want at least 2", v)
+			}
+			ts, err := strconv.ParseInt(v[0], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse timestamp %q: %w", v[0], err)
+			}
+			s.Entries = append(s.Entries, Entry{
+				Timestamp: ts,
+				Line:      v[1],
+			})
+		}
+		streams = append(streams, s)
+	}
+	return streams, nil
+}
+
+func streamKey(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var sb strings.Builder
+	for _, name := range names {
+		sb.WriteString(strconv.Quote(name))
+		sb.WriteByte('=')
+		sb.WriteString(strconv.Quote(labels[name]))
+		sb.WriteByte(',')
+	}
+	return sb.String()
+}
+
+func entryKey(labelsKey string, e Entry) string {
+	return labelsKey + strconv.FormatInt(e.Timestamp, 10) + "\x00" + e.Line
+}
@@ -0,0 +1,128 @@
+package loki
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestClientRead(t *testing.T) {
+	type entry struct {
+		labels map[string]string
+		ts     int64
+		line   string
+	}
+	entries := []entry{
+		{map[string]string{"job": "a"}, 1, "a1"},
+		{map[string]string{"job": "a"}, 2, "a2"},
+		{map[string]string{"job": "b"}, 2, "b2"},
+		{map[string]string{"job": "a"}, 3, "a3"},
+		{map[string]string{"job": "b"}, 4, "b4"},
+		{map[string]string{"job": "b"}, 10, "b10"},
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/loki/api/v1/query_range" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		start, _ := strconv.ParseInt(r.FormValue("start"), 10, 64)
+		end, _ := strconv.ParseInt(r.FormValue("end"), 10, 64)
+		limit, _ := strconv.Atoi(r.FormValue("limit"))
+
+		var result []map[string]any
+		streams := make(map[string]int)
+		n := 0
+		for _, e := range entries {
+			if e.ts < start || e.ts >= end || n >= limit {
+				continue
+			}
+			n++
+			job := e.labels["job"]
+			idx, ok := streams[job]
+			if !ok {
+				idx = len(result)
+				streams[job] = idx
+				result = append(result, map[string]any{
+					"stream": e.labels,
+					"values": [][]string{},
+				})
+			}
+			result[idx]["values"] = append(result[idx]["values"].([][]string), []string{strconv.FormatInt(e.ts, 10), e.line})
+		}
+		resp := map[string]any{
+			"status": "success",
+			"data": map[string]any{
+				"resultType": "streams",
+				"result":     result,
+			},
+		}
+		_ = json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	f := func(limit int, start, end int64, linesExpected []string) {
+		t.Helper()
+
+		c, err := NewClient(Config{
+			Addr:  srv.URL,
+			Query: `{job=~".+"}`,
+			Limit: limit,
+		})
+		if err != nil {
+			t.Fatalf("cannot create client: %s", err)
+		}
+		var lines []string
+		err = c.Read(context.Background(), time.Unix(0, start), time.Unix(0, end), func(streams []Stream) error {
+			for _, s := range streams {
+				for _, e := range s.Entries {
+					lines = append(lines, e.Line)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		sort.Strings(lines)
+		sort.Strings(linesExpected)
+		if !reflect.DeepEqual(lines, linesExpected) {
+			t.Fatalf("unexpected lines; got %q; want %q", lines, linesExpected)
+		}
+	}
+
+	// all the entries fit a single page
+	f(100, 0, 100, []string{"a1", "a2", "b2", "a3", "b4", "b10"})
+
+	// multiple pages with duplicate timestamps at page boundaries
+	f(2, 0, 100, []string{"a1", "a2", "b2", "a3", "b4", "b10"})
+	f(1, 0, 100, []string{"a1", "a2", "b2", "a3", "b4", "b10"})
+
+	// more entries with the same timestamp than the limit
+	f(1, 2, 3, []string{"a2", "b2"})
+
+	// the end of time range is excluded
+	f(2, 2, 10, []string{"a2", "b2", "a3", "b4"})
+}
+
+func TestParseQueryRangeResponseFailure(t *testing.T) {
+	f := func(data string) {
+		t.Helper()
+
+		if _, err := parseQueryRangeResponse([]byte(data)); err == nil {
+			t.Fatalf("expecting non-nil error for %s", data)
+		}
+	}
+
+	f(`foo`)
+	f(`{"status":"error","error":"bad query"}`)
+	f(`{"status":"success","data":{"resultType":"matrix","result":[]}}`)
+	f(`{"status":"success","data":{"resultType":"streams","result":[{"stream":{},"values":[["foo","bar"]]}]}}`)
+	f(`{"status":"success","data":{"resultType":"streams","result":[{"stream":{},"values":[["123"]]}]}}`)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/loki"
+)
+
+func TestNewLokiBatch(t *testing.T) {
+	s := loki.Stream{
+		Labels: map[string]string{
+			"job":      "nginx",
+			"instance": "host-1",
+		},
+		Entries: []loki.Entry{
+			{Timestamp: 1704067200000000001, Line: "foo"},
+			{Timestamp: 1704067200123000000, Line: `bar "baz"`},
+		},
+	}
+	b := newLokiBatch(s)
+
+	streamFieldsExpected := []string{"instance", "job"}
+	if !reflect.DeepEqual(b.StreamFields, streamFieldsExpected) {
+		t.Fatalf("unexpected stream fields; got %q; want %q", b.StreamFields, streamFieldsExpected)
+	}
+	if b.TimeField != "_time" || b.MsgField != "_msg" {
+		t.Fatalf("unexpected time and msg fields; got %q and %q", b.TimeField, b.MsgField)
+	}
+	var lines []string
+	for _, line := range b.Lines {
+		lines = append(lines, string(line))
+	}
+	resultExpected := `{"instance":"host-1","job":"nginx","_time":"2024-01-01T00:00:00.000000001Z","_msg":"foo"}
+{"instance":"host-1","job":"nginx","_time":"2024-01-01T00:00:00.123Z","_msg":"bar \"baz\""}`
+	if result := strings.Join(lines, "\n"); result != resultExpected {
+		t.Fatalf("unexpected lines\ngot\n%s\nwant\n%s", result, resultExpected)
+	}
+}
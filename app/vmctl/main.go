@@ -20,12 +20,16 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/native"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/remoteread"
 
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/elasticsearch"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/influx"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/loki"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/opentsdb"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/prometheus"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/vlogs"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/vm"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/buildinfo"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/httputils"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promrelabel"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/common"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/native/stream"
 )
@@ -276,6 +280,24 @@ func main() {
 						return fmt.Errorf("flag %q can't be empty", vmNativeFilterMatch)
 					}
 
+					tenantMap, err := parseTenantMap(c.StringSlice(vmNativeTenantMap))
+					if err != nil {
+						return fmt.Errorf("failed to parse %q: %s", vmNativeTenantMap, err)
+					}
+					if len(tenantMap) > 0 && !c.Bool(vmInterCluster) {
+						return fmt.Errorf("flag %q can be set only in cluster-to-cluster migration mode enabled via %q", vmNativeTenantMap, vmInterCluster)
+					}
+					var relabelConfigs *promrelabel.ParsedConfigs
+					if path := c.String(vmNativeRelabelConfig); path != "" {
+						if !c.Bool(vmNativeDisableBinaryProtocol) {
+							return fmt.Errorf("flag %q requires %q to be set", vmNativeRelabelConfig, vmNativeDisableBinaryProtocol)
+						}
+						relabelConfigs, err = promrelabel.LoadRelabelConfigs(path)
+						if err != nil {
+							return fmt.Errorf("failed to load relabel configs from %q: %s", path, err)
+						}
+					}
+
 					bfRetries := c.Int(vmNativeBackoffRetries)
 					bfFactor := c.Float64(vmNativeBackoffFactor)
 					bfMinDuration := c.Duration(vmNativeBackoffMinDuration)
@@ -366,10 +388,130 @@ func main() {
 						cc:                       c.Int(vmConcurrency),
 						disablePerMetricRequests: c.Bool(vmNativeDisablePerMetricMigration),
 						isNative:                 !c.Bool(vmNativeDisableBinaryProtocol),
+						tenantMap:                tenantMap,
+						relabelConfigs:           relabelConfigs,
 					}
 					return p.run(ctx)
 				},
 			},
+			{
+				Name:   "loki",
+				Usage:  "Migrate logs from Loki to VictoriaLogs",
+				Flags:  mergeFlags(globalFlags, lokiFlags, vlogsFlags),
+				Before: beforeFn,
+				Action: func(c *cli.Context) error {
+					fmt.Println("Loki import mode")
+
+					addr := c.String(lokiAddr)
+
+					// create Transport with given TLS config
+					certFile := c.String(lokiCertFile)
+					keyFile := c.String(lokiKeyFile)
+					caFile := c.String(lokiCAFile)
+					serverName := c.String(lokiServerName)
+					insecureSkipVerify := c.Bool(lokiInsecureSkipVerify)
+
+					tr, err := httputils.Transport(addr, certFile, keyFile, caFile, serverName, insecureSkipVerify)
+					if err != nil {
+						return fmt.Errorf("failed to create transport for -%s=%q: %s", lokiAddr, addr, err)
+					}
+					authCfg, err := auth.Generate(auth.WithBasicAuth(c.String(lokiUser), c.String(lokiPassword)))
+					if err != nil {
+						return fmt.Errorf("error initilize auth config for source: %s", addr)
+					}
+					lc, err := loki.NewClient(loki.Config{
+						Addr:      addr,
+						Query:     c.String(lokiQuery),
+						Limit:     c.Int(lokiLimit),
+						OrgID:     c.String(lokiOrgID),
+						AuthCfg:   authCfg,
+						Transport: tr,
+					})
+					if err != nil {
+						return fmt.Errorf("failed to create loki client: %s", err)
+					}
+
+					vlogsCfg, err := initConfigVLogs(c)
+					if err != nil {
+						return fmt.Errorf("failed to init VictoriaLogs configuration: %s", err)
+					}
+					vlogsImporter, err := vlogs.NewImporter(vlogsCfg)
+					if err != nil {
+						return fmt.Errorf("failed to create VictoriaLogs importer: %s", err)
+					}
+
+					lp := lokiProcessor{
+						src: lc,
+						dst: vlogsImporter,
+						filter: lokiFilter{
+							timeStart: c.Timestamp(lokiFilterTimeStart),
+							timeEnd:   c.Timestamp(lokiFilterTimeEnd),
+							chunk:     c.String(lokiStepInterval),
+						},
+						resumeFile: c.String(vlogsResumeFile),
+					}
+					return lp.run(ctx)
+				},
+			},
+			{
+				Name:   "elasticsearch",
+				Usage:  "Migrate logs from Elasticsearch to VictoriaLogs",
+				Flags:  mergeFlags(globalFlags, esFlags, vlogsFlags),
+				Before: beforeFn,
+				Action: func(c *cli.Context) error {
+					fmt.Println("Elasticsearch import mode")
+
+					addr := c.String(esAddr)
+
+					// create Transport with given TLS config
+					certFile := c.String(esCertFile)
+					keyFile := c.String(esKeyFile)
+					caFile := c.String(esCAFile)
+					serverName := c.String(esServerName)
+					insecureSkipVerify := c.Bool(esInsecureSkipVerify)
+
+					tr, err := httputils.Transport(addr, certFile, keyFile, caFile, serverName, insecureSkipVerify)
+					if err != nil {
+						return fmt.Errorf("failed to create transport for -%s=%q: %s", esAddr, addr, err)
+					}
+					authCfg, err := auth.Generate(auth.WithBasicAuth(c.String(esUser), c.String(esPassword)))
+					if err != nil {
+						return fmt.Errorf("error initilize auth config for source: %s", addr)
+					}
+					esc, err := elasticsearch.NewClient(elasticsearch.Config{
+						Addr:      addr,
+						Index:     c.String(esIndex),
+						Query:     c.String(esQuery),
+						TimeField: c.String(esTimeField),
+						PageSize:  c.Int(esPageSize),
+						AuthCfg:   authCfg,
+						Transport: tr,
+					})
+					if err != nil {
+						return fmt.Errorf("failed to create elasticsearch client: %s", err)
+					}
+
+					vlogsCfg, err := initConfigVLogs(c)
+					if err != nil {
+						return fmt.Errorf("failed to init VictoriaLogs configuration: %s", err)
+					}
+					vlogsImporter, err := vlogs.NewImporter(vlogsCfg)
+					if err != nil {
+						return fmt.Errorf("failed to create VictoriaLogs importer: %s", err)
+					}
+
+					ep := esProcessor{
+						src:          esc,
+						dst:          vlogsImporter,
+						index:        c.String(esIndex),
+						timeField:    c.String(esTimeField),
+						msgField:     c.String(esMsgField),
+						streamFields: c.StringSlice(esStreamFields),
+						resumeFile:   c.String(vlogsResumeFile),
+					}
+					return ep.run(ctx)
+				},
+			},
 			{
 				Name:  "verify-block",
 				Usage: "Verifies exported block with VictoriaMetrics Native format",
@@ -465,3 +607,42 @@ func initConfigVM(c *cli.Context) (vm.Config, error) {
 		Backoff:            bf,
 	}, nil
 }
+
+func initConfigVLogs(c *cli.Context) (vlogs.Config, error) {
+	addr := c.String(vlogsAddr)
+
+	// create Transport with given TLS config
+	certFile := c.String(vlogsCertFile)
+	keyFile := c.String(vlogsKeyFile)
+	caFile := c.String(vlogsCAFile)
+	serverName := c.String(vlogsServerName)
+	insecureSkipVerify := c.Bool(vlogsInsecureSkipVerify)
+
+	tr, err := httputils.Transport(addr, certFile, keyFile, caFile, serverName, insecureSkipVerify)
+	if err != nil {
+		return vlogs.Config{}, fmt.Errorf("failed to create transport for -%s=%q: %s", vlogsAddr, addr, err)
+	}
+	authCfg, err := auth.Generate(auth.WithBasicAuth(c.String(vlogsUser), c.String(vlogsPassword)))
+	if err != nil {
+		return vlogs.Config{}, fmt.Errorf("error initilize auth config for destination: %s", addr)
+	}
+
+	bfRetries := c.Int(vlogsBackoffRetries)
+	bfFactor := c.Float64(vlogsBackoffFactor)
+	bfMinDuration := c.Duration(vlogsBackoffMinDuration)
+	bf, err := backoff.New(bfRetries, bfFactor, bfMinDuration)
+	if err != nil {
+		return vlogs.Config{}, fmt.Errorf("failed to create backoff object: %s", err)
+	}
+
+	return vlogs.Config{
+		Addr:        addr,
+		Transport:   tr,
+		AuthCfg:     authCfg,
+		Compress:    c.Bool(vlogsCompress),
+		BatchSize:   c.Int(vlogsBatchSize),
+		ExtraFields: c.StringSlice(vlogsExtraField),
+		RateLimit:   c.Int64(vlogsRateLimit),
+		Backoff:     bf,
+	}, nil
+}
@@ -0,0 +1,61 @@
+package vlogs
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ResumeState contains the state for resuming the migration.
+type ResumeState struct {
+	// Time is the timestamp of the last migrated log entry
+	Time time.Time `json:"time"`
+	// ID is an optional ID of the last migrated log entry.
+	// It is used for distinguishing log entries with identical timestamps.
+	ID string `json:"id,omitempty"`
+}
+
+// ReadResumeState reads the state for resuming the migration from the file at path.
+//
+// Zero state is returned if path is empty or if the file at path doesn't exist.
+func ReadResumeState(path string) (ResumeState, error) {
+	var rs ResumeState
+	if path == "" {
+		return rs, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return rs, nil
+		}
+		return rs, fmt.Errorf("cannot read resume file: %w", err)
+	}
+	if err := json.Unmarshal(data, &rs); err != nil {
+		return rs, fmt.Errorf("cannot parse resume file %q: %w", path, err)
+	}
+	return rs, nil
+}
+
+// WriteResumeState atomically writes the state for resuming the migration to the file at path.
+//
+// It is no-op if path is empty.
+func WriteResumeState(path string, rs ResumeState) error {
+	if path == "" {
+		return nil
+	}
+	rs.Time = rs.Time.UTC()
+	data, err := json.Marshal(&rs)
+	if err != nil {
+		return fmt.Errorf("cannot marshal resume state: %w", err)
+	}
+	tmpPath := filepath.Join(filepath.Dir(path), "."+filepath.Base(path)+".tmp")
+	if err := os.WriteFile(tmpPath, append(data, '\n'), 0600); err != nil {
+		return fmt.Errorf("cannot write resume file: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("cannot update resume file: %w", err)
+	}
+	return nil
+}
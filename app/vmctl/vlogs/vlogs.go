@@ -0,0 +1,311 @@
+package vlogs
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/auth"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/backoff"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/limiter"
+)
+
+// Config contains list of params to configure the Importer
+type Config struct {
+	// VictoriaLogs address to perform import requests
+	Addr string
+	// Transport allows specifying custom http.Transport
+	Transport *http.Transport
+	// AuthCfg contains auth config for import requests
+	AuthCfg *auth.Config
+	// Whether to apply gzip compression
+	Compress bool
+	// BatchSize defines how many log entries
+	// importer sends in a single import request
+	BatchSize int
+	// ExtraFields that will be added to all imported log entries. Must be in field=value format.
+	ExtraFields []string
+	// RateLimit defines a data transfer speed in bytes per second.
+	RateLimit int64
+	// Backoff defines backoff policy for retries
+	Backoff *backoff.Backoff
+}
+
+// Batch contains log entries for importing into VictoriaLogs
+// via JSON lines protocol.
+//
+// See https://docs.victoriametrics.com/victorialogs/data-ingestion/#json-stream-api
+type Batch struct {
+	// Lines contains log entries as JSON objects
+	Lines [][]byte
+	// TimeField is the name of the field with log entry timestamp
+	TimeField string
+	// MsgField is the name of the field with log message
+	MsgField string
+	// StreamFields contains the names of log stream fields
+	StreamFields []string
+	// IgnoreFields contains the names of fields to drop during ingestion
+	IgnoreFields []string
+}
+
+// Importer performs insertion of log entries
+// via VictoriaLogs JSON lines protocol
+type Importer struct {
+	addr        string
+	client      *http.Client
+	authCfg     *auth.Config
+	compress    bool
+	batchSize   int
+	extraFields [][]byte
+
+	rl      *limiter.Limiter
+	backoff *backoff.Backoff
+
+	s *stats
+}
+
+// NewImporter creates new Importer for the given cfg.
+func NewImporter(cfg Config) (*Importer, error) {
+	if cfg.Backoff == nil {
+		return nil, fmt.Errorf("backoff must be set")
+	}
+	extraFields, err := parseExtraFields(cfg.ExtraFields)
+	if err != nil {
+		return nil, err
+	}
+	client := &http.Client{}
+	if cfg.Transport != nil {
+		client.Transport = cfg.Transport
+	}
+	if cfg.BatchSize < 1 {
+		cfg.BatchSize = 1e4
+	}
+	im := &Importer{
+		addr:        strings.TrimRight(cfg.Addr, "/"),
+		client:      client,
+		authCfg:     cfg.AuthCfg,
+		compress:    cfg.Compress,
+		batchSize:   cfg.BatchSize,
+		extraFields: extraFields,
+		rl:          limiter.NewLimiter(cfg.RateLimit),
+		backoff:     cfg.Backoff,
+	}
+	if err := im.Ping(); err != nil {
+		return nil, fmt.Errorf("ping to %q failed: %s", im.addr, err)
+	}
+	im.ResetStats()
+	return im, nil
+}
+
+// parseExtraFields parses field=value pairs into JSON object members.
+func parseExtraFields(extraFields []string) ([][]byte, error) {
+	var result [][]byte
+	for _, f := range extraFields {
+		n := strings.IndexByte(f, '=')
+		if n <= 0 {
+			return nil, fmt.Errorf("bad format for extra field %q; it must be `field=value`", f)
+		}
+		result = append(result, []byte(fmt.Sprintf("%q:%q", f[:n], f[n+1:])))
+	}
+	return result, nil
+}
+
+// ResetStats resets im stats.
+func (im *Importer) ResetStats() {
+	im.s = &stats{
+		startTime: time.Now(),
+	}
+}
+
+// Stats returns im stats.
+func (im *Importer) Stats() string {
+	return im.s.String()
+}
+
+// Ping sends a ping to im.addr.
+func (im *Importer) Ping() error {
+	req, err := http.NewRequest(http.MethodGet, im.addr+"/health", nil)
+	if err != nil {
+		return fmt.Errorf("cannot create request to %q: %s", im.addr, err)
+	}
+	if im.authCfg != nil {
+		im.authCfg.SetHeaders(req, true)
+	}
+	resp, err := im.client.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("bad status code: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Import imports log entries from b into VictoriaLogs.
+//
+// Entries are split into requests with up to Config.BatchSize entries.
+// Every request is retried according to Config.Backoff on errors.
+func (im *Importer) Import(ctx context.Context, b *Batch) error {
+	lines := b.Lines
+	for len(lines) > 0 {
+		n := im.batchSize
+		if n > len(lines) {
+			n = len(lines)
+		}
+		body, err := im.marshalLines(lines[:n])
+		if err != nil {
+			return err
+		}
+		im.rl.Register(len(body))
+		attempts, err := im.backoff.Retry(ctx, func() error {
+			return im.send(ctx, b, body)
+		})
+		if err != nil {
+			return fmt.Errorf("import failed with %d retries: %w", attempts, err)
+		}
+
+		im.s.Lock()
+		im.s.entries += uint64(n)
+		im.s.bytes += uint64(len(body))
+		im.s.requests++
+		im.s.retries += attempts
+		im.s.Unlock()
+
+		lines = lines[n:]
+	}
+	return nil
+}
+
+func (im *Importer) marshalLines(lines [][]byte) ([]byte, error) {
+	var bb bytes.Buffer
+	w := io.Writer(&bb)
+	var zw *gzip.Writer
+	if im.compress {
+		var err error
+		zw, err = gzip.NewWriterLevel(&bb, 1)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected error when creating gzip writer: %s", err)
+		}
+		w = zw
+	}
+	for _, line := range lines {
+		line = addExtraFields(line, im.extraFields)
+		if _, err := w.Write(line); err != nil {
+			return nil, err
+		}
+		if _, err := w.Write([]byte("\n")); err != nil {
+			return nil, err
+		}
+	}
+	if zw != nil {
+		if err := zw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	return bb.Bytes(), nil
+}
+
+// addExtraFields adds extraFields to the JSON object at line.
+func addExtraFields(line []byte, extraFields [][]byte) []byte {
+	line = bytes.TrimSpace(line)
+	if len(extraFields) == 0 || len(line) < 2 || line[0] != '{' {
+		return line
+	}
+	isEmpty := len(bytes.TrimSpace(line[1:len(line)-1])) == 0
+	dst := make([]byte, 0, len(line)+64)
+	dst = append(dst, '{')
+	for i, f := range extraFields {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = append(dst, f...)
+	}
+	if isEmpty {
+		return append(dst, '}')
+	}
+	dst = append(dst, ',')
+	return append(dst, line[1:]...)
+}
+
+func (im *Importer) send(ctx context.Context, b *Batch, body []byte) error {
+	qs := url.Values{}
+	if b.TimeField != "" {
+		qs.Set("_time_field", b.TimeField)
+	}
+	if b.MsgField != "" {
+		qs.Set("_msg_field", b.MsgField)
+	}
+	if len(b.StreamFields) > 0 {
+		qs.Set("_stream_fields", strings.Join(b.StreamFields, ","))
+	}
+	if len(b.IgnoreFields) > 0 {
+		qs.Set("ignore_fields", strings.Join(b.IgnoreFields, ","))
+	}
+	u := im.addr + "/insert/jsonline?" + qs.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("cannot create request to %q: %s", im.addr, err)
+	}
+	if im.authCfg != nil {
+		im.authCfg.SetHeaders(req, true)
+	}
+	req.Header.Set("Content-Type", "application/stream+json")
+	if im.compress {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	resp, err := im.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("unexpected error when performing request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode/100 != 2 {
+		respBody, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return fmt.Errorf("failed to read response body for status code %d: %s", resp.StatusCode, err)
+		}
+		if resp.StatusCode == http.StatusBadRequest {
+			return fmt.Errorf("%w: unexpected response code %d: %s", backoff.ErrBadRequest, resp.StatusCode, respBody)
+		}
+		return fmt.Errorf("unexpected response code %d: %s", resp.StatusCode, respBody)
+	}
+	return nil
+}
+
+type stats struct {
+	sync.Mutex
+	entries   uint64
+	bytes     uint64
+	requests  uint64
+	retries   uint64
+	startTime time.Time
+}
+
+func (s *stats) String() string {
+	s.Lock()
+	defer s.Unlock()
+
+	totalImportDuration := time.Since(s.startTime)
+	totalImportDurationS := totalImportDuration.Seconds()
+	var entriesPerS float64
+	if s.entries > 0 && totalImportDurationS > 0 {
+		entriesPerS = float64(s.entries) / totalImportDurationS
+	}
+	return fmt.Sprintf("VictoriaLogs importer stats:\n"+
+		"  time spent while importing: %v;\n"+
+		"  total log entries: %d;\n"+
+		"  log entries/s: %.2f;\n"+
+		"  total bytes: %d;\n"+
+		"  import requests: %d;\n"+
+		"  import requests retries: %d;",
+		totalImportDuration, s.entries, entriesPerS, s.bytes, s.requests, s.retries)
+}
@@ -0,0 +1,83 @@
+package vlogs
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAddExtraFields(t *testing.T) {
+	f := func(line string, extraFields []string, resultExpected string) {
+		t.Helper()
+
+		efs, err := parseExtraFields(extraFields)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		result := addExtraFields([]byte(line), efs)
+		if string(result) != resultExpected {
+			t.Fatalf("unexpected result; got %s; want %s", result, resultExpected)
+		}
+	}
+
+	// no extra fields
+	f(`{"_msg":"foo"}`, nil, `{"_msg":"foo"}`)
+
+	// single extra field
+	f(`{"_msg":"foo"}`, []string{"env=prod"}, `{"env":"prod","_msg":"foo"}`)
+
+	// multiple extra fields
+	f(` {"_msg":"foo"} `, []string{"env=prod", "dc=a=b"}, `{"env":"prod","dc":"a=b","_msg":"foo"}`)
+
+	// empty object
+	f(`{ }`, []string{"env=prod"}, `{"env":"prod"}`)
+}
+
+func TestParseExtraFieldsFailure(t *testing.T) {
+	f := func(extraFields []string) {
+		t.Helper()
+
+		if _, err := parseExtraFields(extraFields); err == nil {
+			t.Fatalf("expecting non-nil error for %q", extraFields)
+		}
+	}
+
+	f([]string{"foo"})
+	f([]string{"=bar"})
+}
+
+func TestResumeState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "resume")
+
+	// missing file
+	rs, err := ReadResumeState(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !rs.Time.IsZero() || rs.ID != "" {
+		t.Fatalf("expecting zero state for missing file; got %+v", rs)
+	}
+
+	// empty path
+	if err := WriteResumeState("", ResumeState{Time: time.Now()}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	f := func(rsExpected ResumeState) {
+		t.Helper()
+
+		if err := WriteResumeState(path, rsExpected); err != nil {
+			t.Fatalf("cannot write resume state: %s", err)
+		}
+		rs, err := ReadResumeState(path)
+		if err != nil {
+			t.Fatalf("cannot read resume state: %s", err)
+		}
+		if !rs.Time.Equal(rsExpected.Time) || rs.ID != rsExpected.ID {
+			t.Fatalf("unexpected resume state; got %+v; want %+v", rs, rsExpected)
+		}
+	}
+
+	f(ResumeState{Time: time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)})
+	f(ResumeState{Time: time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC), ID: "doc 1\n"})
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmctl/vm"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmselect/searchutils"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promrelabel"
 )
 
 type vmNativeProcessor struct {
@@ -34,6 +35,11 @@ type vmNativeProcessor struct {
 	isNative     bool
 
 	disablePerMetricRequests bool
+
+	// tenantMap contains the mapping of source tenants to destination tenants in cluster-to-cluster migration mode
+	tenantMap map[string]string
+	// relabelConfigs contains optional relabeling rules for the migrated time series
+	relabelConfigs *promrelabel.ParsedConfigs
 }
 
 const (
@@ -138,7 +144,12 @@ func (p *vmNativeProcessor) runSingle(ctx context.Context, f native.Filter, srcU
 		w = limiter.NewWriteLimiter(pw, rl)
 	}
 
-	written, err := io.Copy(w, reader)
+	var written int64
+	if p.relabelConfigs != nil {
+		written, err = relabelJSONLines(w, reader, p.relabelConfigs)
+	} else {
+		written, err = io.Copy(w, reader)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to write into %q: %s", p.dst.Addr, err)
 	}
@@ -172,7 +183,7 @@ func (p *vmNativeProcessor) runBackfilling(ctx context.Context, tenantID string,
 
 	if p.interCluster {
 		srcURL = fmt.Sprintf("%s/select/%s/prometheus/%s", p.src.Addr, tenantID, exportAddr)
-		dstURL = fmt.Sprintf("%s/insert/%s/prometheus/%s", p.dst.Addr, tenantID, importAddr)
+		dstURL = fmt.Sprintf("%s/insert/%s/prometheus/%s", p.dst.Addr, p.getDstTenant(tenantID), importAddr)
 	}
 
 	initMessage := "Initing import process from %q to %q with filter %s"
@@ -294,6 +305,21 @@ func (p *vmNativeProcessor) runBackfilling(ctx context.Context, tenantID string,
 	return nil
 }
 
+// getDstTenant returns destination tenant for the given srcTenant according to p.tenantMap.
+func (p *vmNativeProcessor) getDstTenant(srcTenant string) string {
+	if len(p.tenantMap) == 0 {
+		return srcTenant
+	}
+	tenant, err := normalizeTenant(srcTenant)
+	if err != nil {
+		return srcTenant
+	}
+	if dstTenant, ok := p.tenantMap[tenant]; ok {
+		return dstTenant
+	}
+	return srcTenant
+}
+
 func (p *vmNativeProcessor) explore(ctx context.Context, src *native.Client, tenantID string, ranges [][]time.Time) (map[string][][]time.Time, error) {
 	log.Printf("Exploring metrics...")
 
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fastjson"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promrelabel"
+)
+
+// parseTenantMap parses srcTenant=dstTenant pairs into a map of normalized src tenants to normalized dst tenants.
+func parseTenantMap(a []string) (map[string]string, error) {
+	if len(a) == 0 {
+		return nil, nil
+	}
+	m := make(map[string]string, len(a))
+	for _, s := range a {
+		n := strings.IndexByte(s, '=')
+		if n < 0 {
+			return nil, fmt.Errorf("missing '=' in tenant mapping %q; it must be in the format 'srcTenant=dstTenant'", s)
+		}
+		src, err := normalizeTenant(s[:n])
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse source tenant in tenant mapping %q: %w", s, err)
+		}
+		dst, err := normalizeTenant(s[n+1:])
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse destination tenant in tenant mapping %q: %w", s, err)
+		}
+		if _, ok := m[src]; ok {
+			return nil, fmt.Errorf("duplicate mapping for source tenant %q", src)
+		}
+		m[src] = dst
+	}
+	return m, nil
+}
+
+// normalizeTenant converts tenant in the format 'accountID[:projectID]' into 'accountID:projectID'.
+func normalizeTenant(s string) (string, error) {
+	accountID, projectID, ok := strings.Cut(s, ":")
+	if !ok {
+		projectID = "0"
+	}
+	if _, err := strconv.ParseUint(accountID, 10, 32); err != nil {
+		return "", fmt.Errorf("cannot parse accountID %q: %w", accountID, err)
+	}
+	if _, err := strconv.ParseUint(projectID, 10, 32); err != nil {
+		return "", fmt.Errorf("cannot parse projectID %q: %w", projectID, err)
+	}
+	return accountID + ":" + projectID, nil
+}
+
+// relabelJSONLines reads time series in JSON line format from src, applies pcs to them and writes the result to dst.
+//
+// Time series with all the labels removed by pcs are dropped.
+// See https://docs.victoriametrics.com/#how-to-export-data-in-json-line-format
+func relabelJSONLines(dst io.Writer, src io.Reader, pcs *promrelabel.ParsedConfigs) (int64, error) {
+	br := bufio.NewReaderSize(src, 64*1024)
+	var p fastjson.Parser
+	var a fastjson.Arena
+	var labels []prompbmarshal.Label
+	var buf []byte
+	var written int64
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			buf, labels, err = relabelJSONLine(buf[:0], labels[:0], line, &p, &a, pcs)
+			if err != nil {
+				return written, err
+			}
+			n, err := dst.Write(buf)
+			written += int64(n)
+			if err != nil {
+				return written, err
+			}
+		}
+		if err != nil {
+			if err == io.EOF {
+				return written, nil
+			}
+			return written, fmt.Errorf("cannot read time series: %w", err)
+		}
+	}
+}
+
+func relabelJSONLine(dst []byte, labels []prompbmarshal.Label, line []byte, p *fastjson.Parser, a *fastjson.Arena,
+	pcs *promrelabel.ParsedConfigs) ([]byte, []prompbmarshal.Label, error) {
+	s := strings.TrimSpace(string(line))
+	if s == "" {
+		return dst, labels, nil
+	}
+	v, err := p.Parse(s)
+	if err != nil {
+		return dst, labels, fmt.Errorf("cannot parse time series %q: %w", s, err)
+	}
+	metric := v.GetObject("metric")
+	if metric == nil {
+		return dst, labels, fmt.Errorf("missing `metric` object in time series %q", s)
+	}
+	metric.Visit(func(k []byte, v *fastjson.Value) {
+		if err != nil {
+			return
+		}
+		var value []byte
+		value, err = v.StringBytes()
+		if err != nil {
+			err = fmt.Errorf("unexpected value for label %q: %w", k, err)
+			return
+		}
+		labels = append(labels, prompbmarshal.Label{
+			Name:  string(k),
+			Value: string(value),
+		})
+	})
+	if err != nil {
+		return dst, labels, fmt.Errorf("cannot parse `metric` object in time series %q: %w", s, err)
+	}
+
+	labels = pcs.Apply(labels, 0)
+	labels = promrelabel.FinalizeLabels(labels[:0], labels)
+	if len(labels) == 0 {
+		// Drop the time series with all the labels removed during relabeling.
+		return dst, labels, nil
+	}
+
+	a.Reset()
+	newMetric := a.NewObject()
+	for _, label := range labels {
+		newMetric.Set(label.Name, a.NewString(label.Value))
+	}
+	v.Set("metric", newMetric)
+	dst = v.MarshalTo(dst)
+	dst = append(dst, '\n')
+	return dst, labels, nil
+}
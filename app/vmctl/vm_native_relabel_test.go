@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promrelabel"
+)
+
+func TestParseTenantMap_Success(t *testing.T) {
+	f := func(a []string, resultExpected map[string]string) {
+		t.Helper()
+
+		result, err := parseTenantMap(a)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(result, resultExpected) {
+			t.Fatalf("unexpected result; got %v; want %v", result, resultExpected)
+		}
+	}
+
+	f(nil, nil)
+	f([]string{"1=2"}, map[string]string{
+		"1:0": "2:0",
+	})
+	f([]string{"1:5=2:0", "3=0:7"}, map[string]string{
+		"1:5": "2:0",
+		"3:0": "0:7",
+	})
+}
+
+func TestParseTenantMap_Failure(t *testing.T) {
+	f := func(a []string) {
+		t.Helper()
+
+		if _, err := parseTenantMap(a); err == nil {
+			t.Fatalf("expecting non-nil error for %q", a)
+		}
+	}
+
+	// missing '='
+	f([]string{"1:2"})
+
+	// invalid tenants
+	f([]string{"=2"})
+	f([]string{"1="})
+	f([]string{"foo=2"})
+	f([]string{"1:bar=2"})
+	f([]string{"1=-2"})
+	f([]string{"1=4294967296"})
+
+	// duplicate source tenant
+	f([]string{"1=2", "1:0=3"})
+}
+
+func TestVMNativeProcessorGetDstTenant(t *testing.T) {
+	p := &vmNativeProcessor{
+		tenantMap: map[string]string{
+			"1:0": "2:3",
+		},
+	}
+	f := func(srcTenant, dstTenantExpected string) {
+		t.Helper()
+
+		dstTenant := p.getDstTenant(srcTenant)
+		if dstTenant != dstTenantExpected {
+			t.Fatalf("unexpected destination tenant for %q; got %q; want %q", srcTenant, dstTenant, dstTenantExpected)
+		}
+	}
+
+	f("1:0", "2:3")
+	f("1", "2:3")
+	f("1:1", "1:1")
+	f("0:0", "0:0")
+}
+
+func TestRelabelJSONLines(t *testing.T) {
+	f := func(config, data, resultExpected string) {
+		t.Helper()
+
+		pcs, err := promrelabel.ParseRelabelConfigsData([]byte(config))
+		if err != nil {
+			t.Fatalf("cannot parse relabel config: %s", err)
+		}
+		var bb bytes.Buffer
+		n, err := relabelJSONLines(&bb, strings.NewReader(data), pcs)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if n != int64(bb.Len()) {
+			t.Fatalf("unexpected number of written bytes; got %d; want %d", n, bb.Len())
+		}
+		if result := bb.String(); result != resultExpected {
+			t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, resultExpected)
+		}
+	}
+
+	// empty data
+	f(`
+- action: drop
+  source_labels: [job]
+`, "", "")
+
+	// rename label and drop series
+	f(`
+- action: drop
+  source_labels: [job]
+  regex: bar
+- action: labelmap
+  regex: instance
+  replacement: host
+- action: labeldrop
+  regex: instance
+`, `{"metric":{"__name__":"up","job":"foo","instance":"a:1"},"values":[1,0],"timestamps":[1000,2000]}
+{"metric":{"__name__":"up","job":"bar","instance":"b:1"},"values":[1],"timestamps":[1000]}
+
+{"metric":{"__name__":"down","job":"baz"},"values":[5.5],"timestamps":[3000]}`,
+		`{"metric":{"__name__":"up","job":"foo","host":"a:1"},"values":[1,0],"timestamps":[1000,2000]}
+{"metric":{"__name__":"down","job":"baz"},"values":[5.5],"timestamps":[3000]}
+`)
+
+	// temporary labels are removed
+	f(`
+- target_label: __tmp
+  replacement: x
+- source_labels: [__tmp, job]
+  separator: "-"
+  target_label: job
+`, `{"metric":{"__name__":"up","job":"foo"},"values":[1],"timestamps":[1000]}
+`, `{"metric":{"__name__":"up","job":"x-foo"},"values":[1],"timestamps":[1000]}
+`)
+}
+
+func TestRelabelJSONLinesFailure(t *testing.T) {
+	f := func(data string) {
+		t.Helper()
+
+		var bb bytes.Buffer
+		if _, err := relabelJSONLines(&bb, strings.NewReader(data), nil); err == nil {
+			t.Fatalf("expecting non-nil error for %q", data)
+		}
+	}
+
+	f("foo")
+	f(`{"values":[1],"timestamps":[1000]}`)
+	f(`{"metric":{"job":1},"values":[1],"timestamps":[1000]}`)
+}
@@ -3,6 +3,7 @@ package common
 import (
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vminsert/relabel"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmstorage"
@@ -11,6 +12,7 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompb"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/slicesutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/storage"
+	"github.com/VictoriaMetrics/metrics"
 )
 
 // InsertCtx contains common bits for data points insertion.
@@ -24,6 +26,12 @@ type InsertCtx struct {
 	streamAggrCtx streamAggrCtx
 
 	skipStreamAggr bool
+
+	// rowsRejected is the number of rows rejected by the storage since the last TakeRejectedRows call.
+	rowsRejected int
+
+	// rejectReason is the reason for the first rejection since the last TakeRejectedRows call.
+	rejectReason string
 }
 
 // Reset resets ctx for future fill with rowsLen rows.
@@ -137,8 +145,38 @@ func (ctx *InsertCtx) ApplyRelabeling() {
 	ctx.Labels = ctx.relabelCtx.ApplyRelabeling(ctx.Labels)
 }
 
+// UpdateIngestionLag updates h with the lag in seconds between the current time and the newest buffered row.
+//
+// This allows detecting clients, which fall behind. It must be called before FlushBufs.
+func (ctx *InsertCtx) UpdateIngestionLag(h *metrics.Histogram) {
+	if lag, ok := getIngestionLag(ctx.mrs, time.Now().UnixMilli()); ok {
+		h.Update(lag)
+	}
+}
+
+// getIngestionLag returns the lag in seconds between currentTimestamp and the newest row in mrs.
+//
+// False is returned if mrs is empty.
+func getIngestionLag(mrs []storage.MetricRow, currentTimestamp int64) (float64, bool) {
+	if len(mrs) == 0 {
+		return 0, false
+	}
+	maxTimestamp := mrs[0].Timestamp
+	for i := range mrs[1:] {
+		maxTimestamp = max(maxTimestamp, mrs[i+1].Timestamp)
+	}
+	// Rows with timestamps in the future have zero lag.
+	lag := float64(currentTimestamp-maxTimestamp) / 1e3
+	return max(lag, 0), true
+}
+
 // FlushBufs flushes buffered rows to the underlying storage.
 func (ctx *InsertCtx) FlushBufs() error {
+	if isMirrorEnabled() && !ctx.skipStreamAggr {
+		// Mirror the input samples before the aggregation, since the secondary storage may have its own aggregation config.
+		// Aggregated samples are pushed with skipStreamAggr=true, so they aren't mirrored.
+		mirrorRows(ctx.mrs)
+	}
 	sas := sasGlobal.Load()
 	if (sas.IsEnabled() || deduplicator != nil) && !ctx.skipStreamAggr {
 		matchIdxs := matchIdxsPool.Get()
@@ -152,8 +190,14 @@ func (ctx *InsertCtx) FlushBufs() error {
 	// There is no need in limiting the number of concurrent calls to vmstorage.AddRows() here,
 	// since the number of concurrent FlushBufs() calls should be already limited via writeconcurrencylimiter
 	// used at every stream.Parse() call under lib/protoparser/*
-	err := vmstorage.AddRows(ctx.mrs)
+	rowsRejected, rejectReason, err := vmstorage.AddRowsExt(ctx.mrs)
 	ctx.Reset(0)
+	if rowsRejected > 0 {
+		if ctx.rowsRejected == 0 {
+			ctx.rejectReason = rejectReason
+		}
+		ctx.rowsRejected += rowsRejected
+	}
 	if err == nil {
 		return nil
 	}
@@ -163,6 +207,17 @@ func (ctx *InsertCtx) FlushBufs() error {
 	}
 }
 
+// TakeRejectedRows returns the number of rows rejected by the storage since the previous TakeRejectedRows call
+// together with the reason for the first rejection.
+//
+// Rows may be rejected because of timestamps outside the retention or because of cardinality limits.
+func (ctx *InsertCtx) TakeRejectedRows() (int, string) {
+	rowsRejected, rejectReason := ctx.rowsRejected, ctx.rejectReason
+	ctx.rowsRejected = 0
+	ctx.rejectReason = ""
+	return rowsRejected, rejectReason
+}
+
 func (ctx *InsertCtx) dropAggregatedRows(matchIdxs []byte) {
 	dst := ctx.mrs[:0]
 	src := ctx.mrs
@@ -0,0 +1,35 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/storage"
+)
+
+func TestGetIngestionLag(t *testing.T) {
+	f := func(timestamps []int64, currentTimestamp int64, lagExpected float64, okExpected bool) {
+		t.Helper()
+
+		mrs := make([]storage.MetricRow, len(timestamps))
+		for i, timestamp := range timestamps {
+			mrs[i].Timestamp = timestamp
+		}
+		lag, ok := getIngestionLag(mrs, currentTimestamp)
+		if ok != okExpected {
+			t.Fatalf("unexpected ok; got %v; want %v", ok, okExpected)
+		}
+		if lag != lagExpected {
+			t.Fatalf("unexpected lag; got %v; want %v", lag, lagExpected)
+		}
+	}
+
+	// empty rows
+	f(nil, 1000, 0, false)
+
+	// the lag is measured for the newest row
+	f([]int64{1000}, 3500, 2.5, true)
+	f([]int64{1000, 3000, 2000}, 3500, 0.5, true)
+
+	// rows from the future have zero lag
+	f([]int64{1000, 5000}, 3500, 0, true)
+}
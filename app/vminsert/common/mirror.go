@@ -0,0 +1,344 @@
+package common
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+	"github.com/cespare/xxhash/v2"
+	"github.com/golang/snappy"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/netutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promauth"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/storage"
+)
+
+var (
+	mirrorURL = flag.String("mirror.url", "", "Optional Prometheus remote write URL for mirroring the ingested samples to a secondary storage, "+
+		"e.g. http://victoriametrics-canary:8428/api/v1/write . This is useful for testing new releases against production traffic. "+
+		"Mirroring is performed on a best-effort basis, e.g. samples are dropped if the secondary storage cannot keep up with the ingestion rate. "+
+		"See https://docs.victoriametrics.com/#mirroring-ingested-samples")
+	mirrorSamplePercent = flag.Float64("mirror.samplePercent", 100, "The percentage of time series to mirror to -mirror.url. "+
+		"Time series are selected by the hash of their labels, so all the samples for the selected series are mirrored. "+
+		"See https://docs.victoriametrics.com/#mirroring-ingested-samples")
+	mirrorConcurrency = flag.Int("mirror.concurrency", 2, "The number of concurrent requests to -mirror.url")
+
+	mirrorHeaders = flag.String("mirror.headers", "", "Optional HTTP headers to send with each request to -mirror.url. "+
+		"For example, -mirror.headers='My-Auth:foobar' would send 'My-Auth: foobar' HTTP header with every request to -mirror.url. "+
+		"Multiple headers must be delimited by '^^': -mirror.headers='header1:value1^^header2:value2'")
+
+	mirrorBasicAuthUsername     = flag.String("mirror.basicAuth.username", "", "Optional basic auth username to use for -mirror.url")
+	mirrorBasicAuthPassword     = flag.String("mirror.basicAuth.password", "", "Optional basic auth password to use for -mirror.url")
+	mirrorBasicAuthPasswordFile = flag.String("mirror.basicAuth.passwordFile", "", "Optional path to basic auth password to use for -mirror.url. "+
+		"The file is re-read every second")
+	mirrorBearerToken     = flag.String("mirror.bearerToken", "", "Optional bearer auth token to use for -mirror.url")
+	mirrorBearerTokenFile = flag.String("mirror.bearerTokenFile", "", "Optional path to bearer token file to use for -mirror.url. "+
+		"The token is re-read from the file every second")
+
+	mirrorTLSInsecureSkipVerify = flag.Bool("mirror.tlsInsecureSkipVerify", false, "Whether to skip tls verification when connecting to -mirror.url")
+	mirrorTLSCertFile           = flag.String("mirror.tlsCertFile", "", "Optional path to client-side TLS certificate file to use when connecting to -mirror.url")
+	mirrorTLSKeyFile            = flag.String("mirror.tlsKeyFile", "", "Optional path to client-side TLS certificate key to use when connecting to -mirror.url")
+	mirrorTLSCAFile             = flag.String("mirror.tlsCAFile", "", "Optional path to TLS CA file to use for verifying connections to -mirror.url. "+
+		"By default, system CA is used")
+	mirrorTLSServerName = flag.String("mirror.tlsServerName", "", "Optional TLS server name to use for connections to -mirror.url. "+
+		"By default, the server name from -mirror.url is used")
+)
+
+var (
+	mirrorSamplesTotal        = metrics.NewCounter(`vminsert_mirror_samples_total`)
+	mirrorSamplesDropped      = metrics.NewCounter(`vminsert_mirror_samples_dropped_total`)
+	mirrorSendErrors          = metrics.NewCounter(`vminsert_mirror_send_errors_total`)
+	mirrorRequestDurationHist = metrics.NewHistogram(`vminsert_mirror_request_duration_seconds`)
+
+	_ = metrics.NewGauge(`vminsert_mirror_pending_blocks`, func() float64 {
+		return float64(len(mirrorCh))
+	})
+)
+
+// mirrorMaxPendingBlocks is the maximum number of blocks waiting to be sent to -mirror.url.
+//
+// Blocks are dropped if the queue is full, so the slowness or unavailability of -mirror.url doesn't affect data ingestion.
+const mirrorMaxPendingBlocks = 1000
+
+// mirrorBlock is a snappy-compressed Prometheus remote write request with the given number of samples.
+type mirrorBlock struct {
+	data    []byte
+	samples int
+}
+
+var (
+	mirrorCh     chan *mirrorBlock
+	mirrorStopCh chan struct{}
+	mirrorWG     sync.WaitGroup
+)
+
+// InitMirror starts mirroring ingested samples to -mirror.url if it is set.
+func InitMirror() {
+	if *mirrorURL == "" {
+		return
+	}
+	if *mirrorSamplePercent <= 0 || *mirrorSamplePercent > 100 {
+		logger.Fatalf("-mirror.samplePercent must be in the range (0..100]; got %v", *mirrorSamplePercent)
+	}
+	concurrency := *mirrorConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	authCfg, err := getMirrorAuthConfig()
+	if err != nil {
+		logger.Fatalf("cannot initialize auth config for -mirror.url: %s", err)
+	}
+	tr := &http.Transport{
+		DialContext:         netutil.NewStatDialFunc("vminsert_mirror"),
+		MaxIdleConnsPerHost: concurrency,
+		IdleConnTimeout:     time.Minute,
+	}
+	c := &mirrorClient{
+		url: *mirrorURL,
+		hc: &http.Client{
+			Transport: authCfg.NewRoundTripper(tr),
+			Timeout:   time.Minute,
+		},
+		authCfg: authCfg,
+	}
+	mirrorCh = make(chan *mirrorBlock, mirrorMaxPendingBlocks)
+	mirrorStopCh = make(chan struct{})
+	for i := 0; i < concurrency; i++ {
+		mirrorWG.Add(1)
+		go func() {
+			defer mirrorWG.Done()
+			runMirrorSender(c, mirrorCh, mirrorStopCh)
+		}()
+	}
+	logger.Infof("mirroring %v%% of ingested time series to -mirror.url=%q", *mirrorSamplePercent, *mirrorURL)
+}
+
+func getMirrorAuthConfig() (*promauth.Config, error) {
+	var hdrs []string
+	if *mirrorHeaders != "" {
+		hdrs = strings.Split(*mirrorHeaders, "^^")
+	}
+	var basicAuthCfg *promauth.BasicAuthConfig
+	if *mirrorBasicAuthUsername != "" || *mirrorBasicAuthPassword != "" || *mirrorBasicAuthPasswordFile != "" {
+		basicAuthCfg = &promauth.BasicAuthConfig{
+			Username:     *mirrorBasicAuthUsername,
+			Password:     promauth.NewSecret(*mirrorBasicAuthPassword),
+			PasswordFile: *mirrorBasicAuthPasswordFile,
+		}
+	}
+	opts := &promauth.Options{
+		BasicAuth:       basicAuthCfg,
+		BearerToken:     *mirrorBearerToken,
+		BearerTokenFile: *mirrorBearerTokenFile,
+		TLSConfig: &promauth.TLSConfig{
+			CAFile:             *mirrorTLSCAFile,
+			CertFile:           *mirrorTLSCertFile,
+			KeyFile:            *mirrorTLSKeyFile,
+			ServerName:         *mirrorTLSServerName,
+			InsecureSkipVerify: *mirrorTLSInsecureSkipVerify,
+		},
+		Headers: hdrs,
+	}
+	return opts.NewConfig()
+}
+
+// MustStopMirror stops mirroring ingested samples.
+//
+// It sends the pending samples to -mirror.url before returning.
+func MustStopMirror() {
+	if mirrorStopCh == nil {
+		return
+	}
+	close(mirrorStopCh)
+	mirrorWG.Wait()
+}
+
+func isMirrorEnabled() bool {
+	return mirrorCh != nil
+}
+
+// shouldMirror returns true if the series for mc.mn must be mirrored according to the given samplePercent.
+//
+// The decision is made by the hash of the canonical label set, so it doesn't depend on the order of labels
+// in the ingested samples. mc.mn tags are sorted in place.
+func (mc *mirrorCtx) shouldMirror(samplePercent float64) bool {
+	if samplePercent >= 100 {
+		return true
+	}
+	mc.buf = mc.mn.SortAndMarshal(mc.buf[:0])
+	h := xxhash.Sum64(mc.buf)
+	return float64(h%10000) < samplePercent*100
+}
+
+// mirrorRows sends a sample of mrs to -mirror.url.
+//
+// It never blocks: the rows are dropped if the queue to -mirror.url is full.
+func mirrorRows(mrs []storage.MetricRow) {
+	mc := getMirrorCtx()
+	defer putMirrorCtx(mc)
+
+	samplePercent := *mirrorSamplePercent
+	for i := range mrs {
+		mr := &mrs[i]
+		if err := mc.mn.UnmarshalRaw(mr.MetricNameRaw); err != nil {
+			logger.Panicf("BUG: cannot unmarshal recently marshaled MetricName: %s", err)
+		}
+		if mc.shouldMirror(samplePercent) {
+			mc.addRow(mr)
+		}
+	}
+	if len(mc.wr.Timeseries) == 0 {
+		return
+	}
+	samples := len(mc.wr.Timeseries)
+	mirrorSamplesTotal.Add(samples)
+
+	mc.buf = mc.wr.MarshalProtobuf(mc.buf[:0])
+	mb := &mirrorBlock{
+		data:    snappy.Encode(nil, mc.buf),
+		samples: samples,
+	}
+	select {
+	case mirrorCh <- mb:
+	default:
+		mirrorSamplesDropped.Add(samples)
+	}
+}
+
+type mirrorCtx struct {
+	wr      prompbmarshal.WriteRequest
+	labels  []prompbmarshal.Label
+	samples []prompbmarshal.Sample
+	mn      storage.MetricName
+
+	labelsBuf []byte
+	buf       []byte
+}
+
+func (mc *mirrorCtx) reset() {
+	mc.wr.Reset()
+	clear(mc.labels)
+	mc.labels = mc.labels[:0]
+	mc.samples = mc.samples[:0]
+	mc.mn.Reset()
+	mc.labelsBuf = mc.labelsBuf[:0]
+	mc.buf = mc.buf[:0]
+}
+
+// addRow adds mr with the metric name from mc.mn to mc.wr.
+func (mc *mirrorCtx) addRow(mr *storage.MetricRow) {
+	mn := &mc.mn
+	labelsLen := len(mc.labels)
+	mc.labels = append(mc.labels, prompbmarshal.Label{
+		Name:  "__name__",
+		Value: mc.appendString(mn.MetricGroup),
+	})
+	for _, tag := range mn.Tags {
+		mc.labels = append(mc.labels, prompbmarshal.Label{
+			Name:  mc.appendString(tag.Key),
+			Value: mc.appendString(tag.Value),
+		})
+	}
+
+	samplesLen := len(mc.samples)
+	mc.samples = append(mc.samples, prompbmarshal.Sample{
+		Timestamp: mr.Timestamp,
+		Value:     mr.Value,
+	})
+
+	mc.wr.Timeseries = append(mc.wr.Timeseries, prompbmarshal.TimeSeries{
+		Labels:  mc.labels[labelsLen:],
+		Samples: mc.samples[samplesLen:],
+	})
+}
+
+// appendString appends b to mc.labelsBuf and returns the appended string.
+//
+// This is needed because mc.mn is re-used for every added row.
+func (mc *mirrorCtx) appendString(b []byte) string {
+	n := len(mc.labelsBuf)
+	mc.labelsBuf = append(mc.labelsBuf, b...)
+	return bytesutil.ToUnsafeString(mc.labelsBuf[n:])
+}
+
+func getMirrorCtx() *mirrorCtx {
+	v := mirrorCtxPool.Get()
+	if v == nil {
+		return &mirrorCtx{}
+	}
+	return v.(*mirrorCtx)
+}
+
+func putMirrorCtx(mc *mirrorCtx) {
+	mc.reset()
+	mirrorCtxPool.Put(mc)
+}
+
+var mirrorCtxPool sync.Pool
+
+// mirrorClient sends blocks to -mirror.url.
+type mirrorClient struct {
+	url     string
+	hc      *http.Client
+	authCfg *promauth.Config
+}
+
+func runMirrorSender(c *mirrorClient, ch <-chan *mirrorBlock, stopCh <-chan struct{}) {
+	send := func(mb *mirrorBlock) {
+		startTime := time.Now()
+		err := c.send(mb.data)
+		mirrorRequestDurationHist.UpdateDuration(startTime)
+		if err != nil {
+			mirrorSendErrors.Inc()
+			mirrorSamplesDropped.Add(mb.samples)
+			logger.Errorf("cannot send %d samples to -mirror.url=%q: %s", mb.samples, c.url, err)
+		}
+	}
+	for {
+		select {
+		case mb := <-ch:
+			send(mb)
+		case <-stopCh:
+			for {
+				select {
+				case mb := <-ch:
+					send(mb)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (c *mirrorClient) send(data []byte) error {
+	req, err := http.NewRequest(http.MethodPost, c.url, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("cannot create request: %w", err)
+	}
+	if err := c.authCfg.SetHeaders(req, true); err != nil {
+		return fmt.Errorf("cannot set auth headers: %w", err)
+	}
+	req.Header.Set("Content-Encoding", "snappy")
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	req.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+	resp, err := c.hc.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected response status code %d; response body: %q", resp.StatusCode, body)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package common
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/golang/snappy"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promauth"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompb"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/storage"
+)
+
+func TestShouldMirror(t *testing.T) {
+	mc := getMirrorCtx()
+	defer putMirrorCtx(mc)
+
+	shouldMirror := func(labels []prompb.Label, samplePercent float64) bool {
+		t.Helper()
+
+		metricNameRaw := storage.MarshalMetricNameRaw(nil, labels)
+		if err := mc.mn.UnmarshalRaw(metricNameRaw); err != nil {
+			t.Fatalf("cannot unmarshal metric name: %s", err)
+		}
+		return mc.shouldMirror(samplePercent)
+	}
+
+	f := func(samplePercent float64, mirroredMin, mirroredMax int) {
+		t.Helper()
+
+		mirrored := 0
+		for i := 0; i < 10000; i++ {
+			labels := []prompb.Label{
+				{Name: "__name__", Value: "foo"},
+				{Name: "instance", Value: fmt.Sprintf("host-%d", i)},
+				{Name: "job", Value: "bar"},
+			}
+			ok := shouldMirror(labels, samplePercent)
+			if ok {
+				mirrored++
+			}
+			// The decision must be stable for the same series regardless of the order of labels
+			labelsReordered := []prompb.Label{labels[2], labels[0], labels[1]}
+			if shouldMirror(labelsReordered, samplePercent) != ok {
+				t.Fatalf("the mirroring decision depends on the order of labels for %s", labels)
+			}
+		}
+		if mirrored < mirroredMin || mirrored > mirroredMax {
+			t.Fatalf("unexpected number of mirrored series for samplePercent=%v; got %d; want [%d..%d]", samplePercent, mirrored, mirroredMin, mirroredMax)
+		}
+	}
+
+	f(100, 10000, 10000)
+	f(50, 4700, 5300)
+	f(10, 800, 1200)
+	f(0.01, 0, 10)
+}
+
+func TestMirrorRows(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	ts := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "Bearer secret-token" {
+			t.Errorf("unexpected Authorization header: %q", auth)
+		}
+		if ce := r.Header.Get("Content-Encoding"); ce != "snappy" {
+			t.Errorf("unexpected Content-Encoding header: %q", ce)
+		}
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("cannot read request body: %s", err)
+			return
+		}
+		data, err = snappy.Decode(nil, data)
+		if err != nil {
+			t.Errorf("cannot decode request body: %s", err)
+			return
+		}
+		var wr prompb.WriteRequest
+		if err := wr.UnmarshalProtobuf(data); err != nil {
+			t.Errorf("cannot unmarshal request body: %s", err)
+			return
+		}
+		mu.Lock()
+		for _, ts := range wr.Timeseries {
+			var labels []string
+			for _, label := range ts.Labels {
+				labels = append(labels, fmt.Sprintf("%s=%q", label.Name, label.Value))
+			}
+			for _, s := range ts.Samples {
+				received = append(received, fmt.Sprintf("{%s} %v %d", strings.Join(labels, ","), s.Value, s.Timestamp))
+			}
+		}
+		mu.Unlock()
+	}))
+	defer ts.Close()
+
+	authCfg, err := (&promauth.Options{
+		BearerToken: "secret-token",
+	}).NewConfig()
+	if err != nil {
+		t.Fatalf("cannot create auth config: %s", err)
+	}
+	c := &mirrorClient{
+		url:     ts.URL,
+		hc:      ts.Client(),
+		authCfg: authCfg,
+	}
+
+	mirrorCh = make(chan *mirrorBlock, mirrorMaxPendingBlocks)
+	stopCh := make(chan struct{})
+	doneCh := make(chan struct{})
+	defer func() {
+		mirrorCh = nil
+	}()
+
+	var mrs []storage.MetricRow
+	for i := 0; i < 3; i++ {
+		labels := []prompb.Label{
+			{Name: "__name__", Value: "foo"},
+			{Name: "job", Value: fmt.Sprintf("bar-%d", i)},
+		}
+		mrs = append(mrs, storage.MetricRow{
+			MetricNameRaw: storage.MarshalMetricNameRaw(nil, labels),
+			Timestamp:     int64(1000 + i),
+			Value:         float64(i),
+		})
+	}
+	mirrorRows(mrs)
+
+	go func() {
+		runMirrorSender(c, mirrorCh, stopCh)
+		close(doneCh)
+	}()
+	close(stopCh)
+	<-doneCh
+
+	// All the pending samples must be sent on stop
+	resultExpected := []string{
+		`{__name__="foo",job="bar-0"} 0 1000`,
+		`{__name__="foo",job="bar-1"} 1 1001`,
+		`{__name__="foo",job="bar-2"} 2 1002`,
+	}
+	if strings.Join(received, "\n") != strings.Join(resultExpected, "\n") {
+		t.Fatalf("unexpected samples received\ngot\n%s\nwant\n%s", received, resultExpected)
+	}
+}
@@ -15,6 +15,7 @@ import (
 var (
 	rowsInserted  = metrics.NewCounter(`vm_rows_inserted_total{type="csvimport"}`)
 	rowsPerInsert = metrics.NewHistogram(`vm_rows_per_insert{type="csvimport"}`)
+	ingestionLag  = metrics.NewHistogram(`vm_ingestion_lag_seconds{type="csvimport"}`)
 )
 
 // InsertHandler processes /api/v1/import/csv requests.
@@ -60,5 +61,6 @@ func insertRows(rows []parser.Row, extraLabels []prompbmarshal.Label) error {
 	}
 	rowsInserted.Add(len(rows))
 	rowsPerInsert.Update(float64(len(rows)))
+	ctx.UpdateIngestionLag(ingestionLag)
 	return ctx.FlushBufs()
 }
@@ -16,6 +16,7 @@ import (
 var (
 	rowsInserted  = metrics.NewCounter(`vm_rows_inserted_total{type="datadogsketches"}`)
 	rowsPerInsert = metrics.NewHistogram(`vm_rows_per_insert{type="datadogsketches"}`)
+	ingestionLag  = metrics.NewHistogram(`vm_ingestion_lag_seconds{type="datadogsketches"}`)
 )
 
 // InsertHandlerForHTTP processes remote write for DataDog POST /api/beta/sketches request.
@@ -42,7 +43,7 @@ func insertRows(sketches []*datadogsketches.Sketch, extraLabels []prompbmarshal.
 	rowsTotal := 0
 	hasRelabeling := relabel.HasRelabeling()
 	for _, sketch := range sketches {
-		ms := sketch.ToSummary()
+		ms := sketch.ToMetrics()
 		for _, m := range ms {
 			ctx.Labels = ctx.Labels[:0]
 			ctx.AddLabel("", m.Name)
@@ -81,5 +82,6 @@ func insertRows(sketches []*datadogsketches.Sketch, extraLabels []prompbmarshal.
 	}
 	rowsInserted.Add(rowsTotal)
 	rowsPerInsert.Update(float64(rowsTotal))
+	ctx.UpdateIngestionLag(ingestionLag)
 	return ctx.FlushBufs()
 }
@@ -16,6 +16,7 @@ import (
 var (
 	rowsInserted  = metrics.NewCounter(`vm_rows_inserted_total{type="datadogv1"}`)
 	rowsPerInsert = metrics.NewHistogram(`vm_rows_per_insert{type="datadogv1"}`)
+	ingestionLag  = metrics.NewHistogram(`vm_ingestion_lag_seconds{type="datadogv1"}`)
 )
 
 // InsertHandlerForHTTP processes remote write for DataDog POST /api/v1/series request.
@@ -84,5 +85,6 @@ func insertRows(series []datadogv1.Series, extraLabels []prompbmarshal.Label) er
 	}
 	rowsInserted.Add(rowsTotal)
 	rowsPerInsert.Update(float64(rowsTotal))
+	ctx.UpdateIngestionLag(ingestionLag)
 	return ctx.FlushBufs()
 }
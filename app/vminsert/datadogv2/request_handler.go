@@ -16,6 +16,7 @@ import (
 var (
 	rowsInserted  = metrics.NewCounter(`vm_rows_inserted_total{type="datadogv2"}`)
 	rowsPerInsert = metrics.NewHistogram(`vm_rows_per_insert{type="datadogv2"}`)
+	ingestionLag  = metrics.NewHistogram(`vm_ingestion_lag_seconds{type="datadogv2"}`)
 )
 
 // InsertHandlerForHTTP processes remote write for DataDog POST /api/v2/series request.
@@ -87,5 +88,6 @@ func insertRows(series []datadogv2.Series, extraLabels []prompbmarshal.Label) er
 	}
 	rowsInserted.Add(rowsTotal)
 	rowsPerInsert.Update(float64(rowsTotal))
+	ctx.UpdateIngestionLag(ingestionLag)
 	return ctx.FlushBufs()
 }
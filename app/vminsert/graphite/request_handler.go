@@ -13,6 +13,7 @@ import (
 var (
 	rowsInserted  = metrics.NewCounter(`vm_rows_inserted_total{type="graphite"}`)
 	rowsPerInsert = metrics.NewHistogram(`vm_rows_per_insert{type="graphite"}`)
+	ingestionLag  = metrics.NewHistogram(`vm_ingestion_lag_seconds{type="graphite"}`)
 )
 
 // InsertHandler processes remote write for graphite plaintext protocol.
@@ -22,6 +23,13 @@ func InsertHandler(r io.Reader) error {
 	return stream.Parse(r, false, insertRows)
 }
 
+// InsertPickleHandler processes remote write for graphite pickle protocol.
+//
+// See https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-pickle-protocol
+func InsertPickleHandler(r io.Reader) error {
+	return stream.ParsePickle(r, insertRows)
+}
+
 func insertRows(rows []parser.Row) error {
 	ctx := common.GetInsertCtx()
 	defer common.PutInsertCtx(ctx)
@@ -50,5 +58,6 @@ func insertRows(rows []parser.Row) error {
 	}
 	rowsInserted.Add(len(rows))
 	rowsPerInsert.Update(float64(len(rows)))
+	ctx.UpdateIngestionLag(ingestionLag)
 	return ctx.FlushBufs()
 }
@@ -9,6 +9,7 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vminsert/common"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vminsert/relabel"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/influxutils"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompb"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
 	parserCommon "github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/common"
@@ -28,6 +29,7 @@ var (
 var (
 	rowsInserted  = metrics.NewCounter(`vm_rows_inserted_total{type="influx"}`)
 	rowsPerInsert = metrics.NewHistogram(`vm_rows_per_insert{type="influx"}`)
+	ingestionLag  = metrics.NewHistogram(`vm_ingestion_lag_seconds{type="influx"}`)
 )
 
 // InsertHandlerForReader processes remote write for influx line protocol.
@@ -50,8 +52,8 @@ func InsertHandlerForHTTP(req *http.Request) error {
 	isGzipped := req.Header.Get("Content-Encoding") == "gzip"
 	q := req.URL.Query()
 	precision := q.Get("precision")
-	// Read db tag from https://docs.influxdata.com/influxdb/v1.7/tools/api/#write-http-endpoint
-	db := q.Get("db")
+	db := influxutils.GetDatabase(q)
+	extraLabels = influxutils.AppendOrgLabel(extraLabels, q)
 	return stream.Parse(req.Body, isGzipped, precision, db, func(db string, rows []parser.Row) error {
 		return insertRows(db, rows, extraLabels)
 	})
@@ -142,6 +144,7 @@ func insertRows(db string, rows []parser.Row, extraLabels []prompbmarshal.Label)
 	}
 	rowsInserted.Add(rowsTotal)
 	rowsPerInsert.Update(float64(rowsTotal))
+	ic.UpdateIngestionLag(ingestionLag)
 	return ic.FlushBufs()
 }
 
@@ -17,6 +17,7 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vminsert/datadogv2"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vminsert/graphite"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vminsert/influx"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vminsert/jsonlimport"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vminsert/native"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vminsert/newrelic"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vminsert/opentelemetry"
@@ -26,6 +27,7 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vminsert/prompush"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vminsert/promremotewrite"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vminsert/relabel"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vminsert/statsd"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vminsert/vmimport"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/auth"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
@@ -34,13 +36,17 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/influxutils"
 	graphiteserver "github.com/VictoriaMetrics/VictoriaMetrics/lib/ingestserver/graphite"
 	influxserver "github.com/VictoriaMetrics/VictoriaMetrics/lib/ingestserver/influx"
+	otelserver "github.com/VictoriaMetrics/VictoriaMetrics/lib/ingestserver/opentelemetry"
 	opentsdbserver "github.com/VictoriaMetrics/VictoriaMetrics/lib/ingestserver/opentsdb"
 	opentsdbhttpserver "github.com/VictoriaMetrics/VictoriaMetrics/lib/ingestserver/opentsdbhttp"
+	statsdserver "github.com/VictoriaMetrics/VictoriaMetrics/lib/ingestserver/statsd"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/procutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promscrape"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/common"
+	graphiteparser "github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/graphite"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/firehose"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/stream"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/storage"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/stringsutil"
 )
@@ -50,6 +56,10 @@ var (
 		"See also -graphiteListenAddr.useProxyProtocol")
 	graphiteUseProxyProtocol = flag.Bool("graphiteListenAddr.useProxyProtocol", false, "Whether to use proxy protocol for connections accepted at -graphiteListenAddr . "+
 		"See https://www.haproxy.org/download/1.8/doc/proxy-protocol.txt")
+	graphitePickleListenAddr = flag.String("graphitePickleListenAddr", "", "TCP address to listen for Graphite data in pickle protocol, which is used by carbon-relay. "+
+		"Usually :2004 must be set. Doesn't work if empty. See also -graphitePickleListenAddr.useProxyProtocol")
+	graphitePickleUseProxyProtocol = flag.Bool("graphitePickleListenAddr.useProxyProtocol", false, "Whether to use proxy protocol for connections accepted at -graphitePickleListenAddr . "+
+		"See https://www.haproxy.org/download/1.8/doc/proxy-protocol.txt")
 	influxListenAddr = flag.String("influxListenAddr", "", "TCP and UDP address to listen for InfluxDB line protocol data. Usually :8089 must be set. Doesn't work if empty. "+
 		"This flag isn't needed when ingesting data over HTTP - just send it to http://<victoriametrics>:8428/write . "+
 		"See also -influxListenAddr.useProxyProtocol")
@@ -65,6 +75,14 @@ var (
 		"See also -opentsdbHTTPListenAddr.useProxyProtocol")
 	opentsdbHTTPUseProxyProtocol = flag.Bool("opentsdbHTTPListenAddr.useProxyProtocol", false, "Whether to use proxy protocol for connections accepted "+
 		"at -opentsdbHTTPListenAddr . See https://www.haproxy.org/download/1.8/doc/proxy-protocol.txt")
+	opentelemetryGRPCListenAddr = flag.String("opentelemetryGRPCListenAddr", "", "TCP address to listen for OpenTelemetry metrics sent via OTLP/gRPC protocol. Usually :4317 must be set. Doesn't work if empty. "+
+		"See also -opentelemetryGRPCListenAddr.useProxyProtocol")
+	opentelemetryGRPCUseProxyProtocol = flag.Bool("opentelemetryGRPCListenAddr.useProxyProtocol", false, "Whether to use proxy protocol for connections accepted "+
+		"at -opentelemetryGRPCListenAddr . See https://www.haproxy.org/download/1.8/doc/proxy-protocol.txt")
+	statsdListenAddr = flag.String("statsdListenAddr", "", "TCP and UDP address to listen for StatsD data. Usually :8125 must be set. Doesn't work if empty. "+
+		"The received data is aggregated on the client side every -statsd.flushInterval. See also -statsdListenAddr.useProxyProtocol")
+	statsdUseProxyProtocol = flag.Bool("statsdListenAddr.useProxyProtocol", false, "Whether to use proxy protocol for connections accepted at -statsdListenAddr . "+
+		"See https://www.haproxy.org/download/1.8/doc/proxy-protocol.txt")
 	configAuthKey          = flagutil.NewPassword("configAuthKey", "Authorization key for accessing /config page. It must be passed via authKey query arg. It overrides -httpAuth.*")
 	reloadAuthKey          = flagutil.NewPassword("reloadAuthKey", "Auth key for /-/reload http endpoint. It must be passed via authKey query arg. It overrides httpAuth.* settings.")
 	maxLabelsPerTimeseries = flag.Int("maxLabelsPerTimeseries", 30, "The maximum number of labels accepted per time series. Superfluous labels are dropped. In this case the vm_metrics_with_dropped_labels_total metric at /metrics page is incremented")
@@ -72,10 +90,13 @@ var (
 )
 
 var (
-	graphiteServer     *graphiteserver.Server
-	influxServer       *influxserver.Server
-	opentsdbServer     *opentsdbserver.Server
-	opentsdbhttpServer *opentsdbhttpserver.Server
+	graphiteServer       *graphiteserver.Server
+	graphitePickleServer *graphiteserver.Server
+	influxServer         *influxserver.Server
+	opentsdbServer       *opentsdbserver.Server
+	opentsdbhttpServer   *opentsdbhttpserver.Server
+	otelGRPCServer       *otelserver.Server
+	statsdServer         *statsdserver.Server
 )
 
 //go:embed static
@@ -87,12 +108,20 @@ var staticServer = http.FileServer(http.FS(staticFiles))
 func Init() {
 	relabel.Init()
 	vminsertCommon.InitStreamAggr()
+	vminsertCommon.InitMirror()
 	storage.SetMaxLabelsPerTimeseries(*maxLabelsPerTimeseries)
 	storage.SetMaxLabelValueLen(*maxLabelValueLen)
 	common.StartUnmarshalWorkers()
+	stream.InitAttributesMapping()
+	if len(*graphiteListenAddr) > 0 || len(*graphitePickleListenAddr) > 0 {
+		graphiteparser.InitMapping()
+	}
 	if len(*graphiteListenAddr) > 0 {
 		graphiteServer = graphiteserver.MustStart(*graphiteListenAddr, *graphiteUseProxyProtocol, graphite.InsertHandler)
 	}
+	if len(*graphitePickleListenAddr) > 0 {
+		graphitePickleServer = graphiteserver.MustStartPickle(*graphitePickleListenAddr, *graphitePickleUseProxyProtocol, graphite.InsertPickleHandler)
+	}
 	if len(*influxListenAddr) > 0 {
 		influxServer = influxserver.MustStart(*influxListenAddr, *influxUseProxyProtocol, influx.InsertHandlerForReader)
 	}
@@ -102,6 +131,13 @@ func Init() {
 	if len(*opentsdbHTTPListenAddr) > 0 {
 		opentsdbhttpServer = opentsdbhttpserver.MustStart(*opentsdbHTTPListenAddr, *opentsdbHTTPUseProxyProtocol, opentsdbhttp.InsertHandler)
 	}
+	if len(*opentelemetryGRPCListenAddr) > 0 {
+		otelGRPCServer = otelserver.MustStart(*opentelemetryGRPCListenAddr, *opentelemetryGRPCUseProxyProtocol, opentelemetry.InsertHandlerForReader, opentelemetry.InsertHandlerForRequest)
+	}
+	if len(*statsdListenAddr) > 0 {
+		statsd.Init()
+		statsdServer = statsdserver.MustStart(*statsdListenAddr, *statsdUseProxyProtocol, statsd.InsertHandler)
+	}
 	promscrape.Init(func(_ *auth.Token, wr *prompbmarshal.WriteRequest) {
 		prompush.Push(wr)
 	})
@@ -113,6 +149,9 @@ func Stop() {
 	if len(*graphiteListenAddr) > 0 {
 		graphiteServer.MustStop()
 	}
+	if len(*graphitePickleListenAddr) > 0 {
+		graphitePickleServer.MustStop()
+	}
 	if len(*influxListenAddr) > 0 {
 		influxServer.MustStop()
 	}
@@ -122,8 +161,16 @@ func Stop() {
 	if len(*opentsdbHTTPListenAddr) > 0 {
 		opentsdbhttpServer.MustStop()
 	}
+	if len(*opentelemetryGRPCListenAddr) > 0 {
+		otelGRPCServer.MustStop()
+	}
+	if len(*statsdListenAddr) > 0 {
+		statsdServer.MustStop()
+		statsd.MustStop()
+	}
 	common.StopUnmarshalWorkers()
 	vminsertCommon.MustStopStreamAggr()
+	vminsertCommon.MustStopMirror()
 }
 
 // RequestHandler is a handler for Prometheus remote storage write API
@@ -194,6 +241,15 @@ func RequestHandler(w http.ResponseWriter, r *http.Request) bool {
 		}
 		w.WriteHeader(http.StatusNoContent)
 		return true
+	case "/prometheus/api/v1/import/jsonl", "/api/v1/import/jsonl":
+		jsonlimportRequests.Inc()
+		if err := jsonlimport.InsertHandler(r); err != nil {
+			jsonlimportErrors.Inc()
+			httpserver.Errorf(w, r, "%s", err)
+			return true
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return true
 	case "/prometheus/api/v1/import/native", "/api/v1/import/native":
 		nativeimportRequests.Inc()
 		if err := native.InsertHandler(r); err != nil {
@@ -203,7 +259,7 @@ func RequestHandler(w http.ResponseWriter, r *http.Request) bool {
 		}
 		w.WriteHeader(http.StatusNoContent)
 		return true
-	case "/influx/write", "/influx/api/v2/write", "/write", "/api/v2/write":
+	case "/influx/write", "/write":
 		influxWriteRequests.Inc()
 		addInfluxResponseHeaders(w)
 		if err := influx.InsertHandlerForHTTP(r); err != nil {
@@ -213,6 +269,16 @@ func RequestHandler(w http.ResponseWriter, r *http.Request) bool {
 		}
 		w.WriteHeader(http.StatusNoContent)
 		return true
+	case "/influx/api/v2/write", "/api/v2/write":
+		influxWriteRequests.Inc()
+		addInfluxResponseHeaders(w)
+		if err := influx.InsertHandlerForHTTP(r); err != nil {
+			influxWriteErrors.Inc()
+			influxutils.WriteV2ErrorResponse(w, r, err)
+			return true
+		}
+		w.WriteHeader(http.StatusNoContent)
+		return true
 	case "/influx/query", "/query":
 		influxQueryRequests.Inc()
 		addInfluxResponseHeaders(w)
@@ -224,11 +290,16 @@ func RequestHandler(w http.ResponseWriter, r *http.Request) bool {
 		return true
 	case "/opentelemetry/api/v1/push", "/opentelemetry/v1/metrics":
 		opentelemetryPushRequests.Inc()
-		if err := opentelemetry.InsertHandler(r); err != nil {
+		ps, err := opentelemetry.InsertHandler(r)
+		if err != nil {
 			opentelemetryPushErrors.Inc()
 			httpserver.Errorf(w, r, "%s", err)
 			return true
 		}
+		if ps != nil {
+			stream.WritePartialSuccessResponse(w, r, ps)
+			return true
+		}
 		firehose.WriteSuccessResponse(w, r)
 		return true
 	case "/newrelic":
@@ -254,6 +325,17 @@ func RequestHandler(w http.ResponseWriter, r *http.Request) bool {
 		w.WriteHeader(202)
 		fmt.Fprintf(w, `{"status":"ok"}`)
 		return true
+	case "/newrelic/metric/v1":
+		newrelicMetricAPIWriteRequests.Inc()
+		if err := newrelic.InsertMetricAPIHandlerForHTTP(r); err != nil {
+			newrelicMetricAPIWriteErrors.Inc()
+			httpserver.Errorf(w, r, "%s", err)
+			return true
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(202)
+		fmt.Fprintf(w, `{"status":"ok"}`)
+		return true
 	case "/datadog/api/v1/series":
 		datadogv1WriteRequests.Inc()
 		if err := datadogv1.InsertHandlerForHTTP(r); err != nil {
@@ -317,6 +399,10 @@ func RequestHandler(w http.ResponseWriter, r *http.Request) bool {
 		promscrapeServiceDiscoveryRequests.Inc()
 		promscrape.WriteServiceDiscovery(w, r)
 		return true
+	case "/prometheus/api/v1/targets/cardinality", "/api/v1/targets/cardinality":
+		promscrapeTargetsCardinalityRequests.Inc()
+		promscrape.WriteTargetsCardinalityReport(w, r)
+		return true
 	case "/prometheus/api/v1/targets", "/api/v1/targets":
 		promscrapeAPIV1TargetsRequests.Inc()
 		w.Header().Set("Content-Type", "application/json")
@@ -392,6 +478,9 @@ var (
 	csvimportRequests = metrics.NewCounter(`vm_http_requests_total{path="/api/v1/import/csv", protocol="csvimport"}`)
 	csvimportErrors   = metrics.NewCounter(`vm_http_request_errors_total{path="/api/v1/import/csv", protocol="csvimport"}`)
 
+	jsonlimportRequests = metrics.NewCounter(`vm_http_requests_total{path="/api/v1/import/jsonl", protocol="jsonlimport"}`)
+	jsonlimportErrors   = metrics.NewCounter(`vm_http_request_errors_total{path="/api/v1/import/jsonl", protocol="jsonlimport"}`)
+
 	prometheusimportRequests = metrics.NewCounter(`vm_http_requests_total{path="/api/v1/import/prometheus", protocol="prometheusimport"}`)
 	prometheusimportErrors   = metrics.NewCounter(`vm_http_request_errors_total{path="/api/v1/import/prometheus", protocol="prometheusimport"}`)
 
@@ -424,13 +513,17 @@ var (
 	newrelicWriteRequests = metrics.NewCounter(`vm_http_requests_total{path="/newrelic/infra/v2/metrics/events/bulk", protocol="newrelic"}`)
 	newrelicWriteErrors   = metrics.NewCounter(`vm_http_request_errors_total{path="/newrelic/infra/v2/metrics/events/bulk", protocol="newrelic"}`)
 
+	newrelicMetricAPIWriteRequests = metrics.NewCounter(`vm_http_requests_total{path="/newrelic/metric/v1", protocol="newrelic"}`)
+	newrelicMetricAPIWriteErrors   = metrics.NewCounter(`vm_http_request_errors_total{path="/newrelic/metric/v1", protocol="newrelic"}`)
+
 	newrelicInventoryRequests = metrics.NewCounter(`vm_http_requests_total{path="/newrelic/inventory/deltas", protocol="newrelic"}`)
 	newrelicCheckRequest      = metrics.NewCounter(`vm_http_requests_total{path="/newrelic", protocol="newrelic"}`)
 
 	promscrapeTargetsRequests          = metrics.NewCounter(`vm_http_requests_total{path="/targets"}`)
 	promscrapeServiceDiscoveryRequests = metrics.NewCounter(`vm_http_requests_total{path="/service-discovery"}`)
 
-	promscrapeAPIV1TargetsRequests = metrics.NewCounter(`vm_http_requests_total{path="/api/v1/targets"}`)
+	promscrapeAPIV1TargetsRequests       = metrics.NewCounter(`vm_http_requests_total{path="/api/v1/targets"}`)
+	promscrapeTargetsCardinalityRequests = metrics.NewCounter(`vm_http_requests_total{path="/api/v1/targets/cardinality"}`)
 
 	promscrapeTargetResponseRequests = metrics.NewCounter(`vm_http_requests_total{path="/target_response"}`)
 	promscrapeTargetResponseErrors   = metrics.NewCounter(`vm_http_request_errors_total{path="/target_response"}`)
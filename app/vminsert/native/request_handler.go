@@ -17,6 +17,7 @@ import (
 var (
 	rowsInserted  = metrics.NewCounter(`vm_rows_inserted_total{type="native"}`)
 	rowsPerInsert = metrics.NewHistogram(`vm_rows_per_insert{type="native"}`)
+	ingestionLag  = metrics.NewHistogram(`vm_ingestion_lag_seconds{type="native"}`)
 )
 
 // InsertHandler processes `/api/v1/import/native` request.
@@ -75,6 +76,7 @@ func insertRows(block *stream.Block, extraLabels []prompbmarshal.Label) error {
 			return err
 		}
 	}
+	ic.UpdateIngestionLag(ingestionLag)
 	return ic.FlushBufs()
 }
 
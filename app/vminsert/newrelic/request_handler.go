@@ -16,6 +16,7 @@ import (
 var (
 	rowsInserted  = metrics.NewCounter(`vm_rows_inserted_total{type="newrelic"}`)
 	rowsPerInsert = metrics.NewHistogram(`vm_rows_per_insert{type="newrelic"}`)
+	ingestionLag  = metrics.NewHistogram(`vm_ingestion_lag_seconds{type="newrelic"}`)
 )
 
 // InsertHandlerForHTTP processes remote write for request to /newrelic/infra/v2/metrics/events/bulk request.
@@ -31,6 +32,19 @@ func InsertHandlerForHTTP(req *http.Request) error {
 	})
 }
 
+// InsertMetricAPIHandlerForHTTP processes remote write for request to /newrelic/metric/v1 request.
+func InsertMetricAPIHandlerForHTTP(req *http.Request) error {
+	extraLabels, err := parserCommon.GetExtraLabels(req)
+	if err != nil {
+		return err
+	}
+	ce := req.Header.Get("Content-Encoding")
+	isGzip := ce == "gzip"
+	return stream.ParseMetricAPI(req.Body, isGzip, func(rows []newrelic.Row) error {
+		return insertRows(rows, extraLabels)
+	})
+}
+
 func insertRows(rows []newrelic.Row, extraLabels []prompbmarshal.Label) error {
 	ctx := common.GetInsertCtx()
 	defer common.PutInsertCtx(ctx)
@@ -73,5 +87,6 @@ func insertRows(rows []newrelic.Row, extraLabels []prompbmarshal.Label) error {
 	}
 	rowsInserted.Add(samplesCount)
 	rowsPerInsert.Update(float64(samplesCount))
+	ctx.UpdateIngestionLag(ingestionLag)
 	return ctx.FlushBufs()
 }
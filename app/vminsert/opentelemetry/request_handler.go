@@ -1,7 +1,7 @@
 package opentelemetry
 
 import (
-	"fmt"
+	"io"
 	"net/http"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vminsert/common"
@@ -9,6 +9,7 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
 	parserCommon "github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/common"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/firehose"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/stream"
 	"github.com/VictoriaMetrics/metrics"
 )
@@ -16,26 +17,56 @@ import (
 var (
 	rowsInserted  = metrics.NewCounter(`vm_rows_inserted_total{type="opentelemetry"}`)
 	rowsPerInsert = metrics.NewHistogram(`vm_rows_per_insert{type="opentelemetry"}`)
+	ingestionLag  = metrics.NewHistogram(`vm_ingestion_lag_seconds{type="opentelemetry"}`)
 )
 
 // InsertHandler processes opentelemetry metrics.
-func InsertHandler(req *http.Request) error {
+//
+// It returns non-nil partial success if some data points from req were rejected.
+// Partial success isn't returned for AWS Firehose requests, since AWS Firehose doesn't support it.
+func InsertHandler(req *http.Request) (*pb.ExportMetricsPartialSuccess, error) {
 	extraLabels, err := parserCommon.GetExtraLabels(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	isGzipped := req.Header.Get("Content-Encoding") == "gzip"
+	ce := req.Header.Get("Content-Encoding")
 	var processBody func([]byte) ([]byte, error)
-	if req.Header.Get("Content-Type") == "application/json" {
+	isFirehose := false
+	if stream.IsJSONRequest(req) {
 		if req.Header.Get("X-Amz-Firehose-Protocol-Version") != "" {
 			processBody = firehose.ProcessRequestBody
+			isFirehose = true
 		} else {
-			return fmt.Errorf("json encoding isn't supported for opentelemetry format. Use protobuf encoding")
+			processBody = stream.ProcessJSONRequestBody
 		}
 	}
-	return stream.ParseStream(req.Body, isGzipped, processBody, func(tss []prompbmarshal.TimeSeries) error {
+	ps, err := stream.ParseStreamExt(req.Body, ce, processBody, func(tss []prompbmarshal.TimeSeries) error {
 		return insertRows(tss, extraLabels)
 	})
+	if err != nil || isFirehose {
+		return nil, err
+	}
+	return ps, nil
+}
+
+// InsertHandlerForReader processes protobuf-encoded opentelemetry metrics from r.
+//
+// It is used for processing requests to OTLP/gRPC server.
+// It returns non-nil partial success if some data points from r were rejected.
+func InsertHandlerForReader(r io.Reader) (*pb.ExportMetricsPartialSuccess, error) {
+	return stream.ParseStreamExt(r, "", nil, func(tss []prompbmarshal.TimeSeries) error {
+		return insertRows(tss, nil)
+	})
+}
+
+// InsertHandlerForRequest processes opentelemetry metrics from the already decoded req.
+//
+// It is used for processing OpenTelemetry Arrow batches at OTLP/gRPC server.
+// It returns non-nil partial success if some data points from req were rejected.
+func InsertHandlerForRequest(req *pb.ExportMetricsServiceRequest) (*pb.ExportMetricsPartialSuccess, error) {
+	return stream.ParseRequest(req, func(tss []prompbmarshal.TimeSeries) error {
+		return insertRows(tss, nil)
+	})
 }
 
 func insertRows(tss []prompbmarshal.TimeSeries, extraLabels []prompbmarshal.Label) error {
@@ -80,5 +111,15 @@ func insertRows(tss []prompbmarshal.TimeSeries, extraLabels []prompbmarshal.Labe
 	}
 	rowsInserted.Add(rowsTotal)
 	rowsPerInsert.Update(float64(rowsTotal))
-	return ctx.FlushBufs()
+	ctx.UpdateIngestionLag(ingestionLag)
+	if err := ctx.FlushBufs(); err != nil {
+		return err
+	}
+	if rowsRejected, rejectReason := ctx.TakeRejectedRows(); rowsRejected > 0 {
+		return &stream.RejectedSamplesError{
+			Count:  rowsRejected,
+			Reason: rejectReason,
+		}
+	}
+	return nil
 }
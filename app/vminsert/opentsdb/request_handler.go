@@ -13,6 +13,7 @@ import (
 var (
 	rowsInserted  = metrics.NewCounter(`vm_rows_inserted_total{type="opentsdb"}`)
 	rowsPerInsert = metrics.NewHistogram(`vm_rows_per_insert{type="opentsdb"}`)
+	ingestionLag  = metrics.NewHistogram(`vm_ingestion_lag_seconds{type="opentsdb"}`)
 )
 
 // InsertHandler processes remote write for OpenTSDB put protocol.
@@ -50,5 +51,6 @@ func insertRows(rows []parser.Row) error {
 	}
 	rowsInserted.Add(len(rows))
 	rowsPerInsert.Update(float64(len(rows)))
+	ctx.UpdateIngestionLag(ingestionLag)
 	return ctx.FlushBufs()
 }
@@ -16,6 +16,7 @@ import (
 var (
 	rowsInserted  = metrics.NewCounter(`vm_rows_inserted_total{type="prometheus"}`)
 	rowsPerInsert = metrics.NewHistogram(`vm_rows_per_insert{type="prometheus"}`)
+	ingestionLag  = metrics.NewHistogram(`vm_ingestion_lag_seconds{type="prometheus"}`)
 )
 
 // InsertHandler processes `/api/v1/import/prometheus` request.
@@ -68,5 +69,6 @@ func insertRows(rows []parser.Row, extraLabels []prompbmarshal.Label) error {
 	}
 	rowsInserted.Add(len(rows))
 	rowsPerInsert.Update(float64(len(rows)))
+	ctx.UpdateIngestionLag(ingestionLag)
 	return ctx.FlushBufs()
 }
@@ -15,6 +15,7 @@ import (
 var (
 	rowsInserted  = metrics.NewCounter(`vm_rows_inserted_total{type="promremotewrite"}`)
 	rowsPerInsert = metrics.NewHistogram(`vm_rows_per_insert{type="promremotewrite"}`)
+	ingestionLag  = metrics.NewHistogram(`vm_ingestion_lag_seconds{type="promremotewrite"}`)
 )
 
 // InsertHandler processes remote write for prometheus.
@@ -73,5 +74,6 @@ func insertRows(timeseries []prompb.TimeSeries, extraLabels []prompbmarshal.Labe
 	}
 	rowsInserted.Add(rowsTotal)
 	rowsPerInsert.Update(float64(rowsTotal))
+	ctx.UpdateIngestionLag(ingestionLag)
 	return ctx.FlushBufs()
 }
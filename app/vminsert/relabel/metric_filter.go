@@ -0,0 +1,172 @@
+package relabel
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fasttime"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fs/fscore"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/procutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompb"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/regexutil"
+)
+
+var (
+	allowMetricsFile = flag.String("ingest.allowMetrics", "", "Optional path to a file with metric names, which are allowed to be ingested. "+
+		"Samples for other metrics are dropped before the relabeling. The file must contain a metric name or a regular expression per line. "+
+		"The path can point either to local file or to http url. The file is reloaded on SIGHUP signal. "+
+		"See https://docs.victoriametrics.com/#metric-name-filters")
+	denyMetricsFile = flag.String("ingest.denyMetrics", "", "Optional path to a file with metric names, which must be dropped during data ingestion. "+
+		"Samples for these metrics are dropped before the relabeling. The file must contain a metric name or a regular expression per line. "+
+		"The path can point either to local file or to http url. The file is reloaded on SIGHUP signal. "+
+		"See https://docs.victoriametrics.com/#metric-name-filters")
+)
+
+var (
+	metricFiltersReloads      = metrics.NewCounter(`vm_ingest_metric_filters_reloads_total`)
+	metricFiltersReloadErrors = metrics.NewCounter(`vm_ingest_metric_filters_reloads_errors_total`)
+	metricFiltersSuccess      = metrics.NewGauge(`vm_ingest_metric_filters_last_reload_successful`, nil)
+	metricFiltersTimestamp    = metrics.NewCounter(`vm_ingest_metric_filters_last_reload_success_timestamp_seconds`)
+
+	metricsDroppedByAllowList = metrics.NewCounter(`vm_ingest_metrics_dropped_total{reason="not_allowed"}`)
+	metricsDroppedByDenyList  = metrics.NewCounter(`vm_ingest_metrics_dropped_total{reason="denied"}`)
+)
+
+// metricFilters contains metric name filters loaded from -ingest.allowMetrics and -ingest.denyMetrics.
+type metricFilters struct {
+	// hasAllowList is set to true if -ingest.allowMetrics is set.
+	// In this case only metrics matching allow are accepted.
+	hasAllowList bool
+	allow        *regexutil.PromRegex
+
+	deny *regexutil.PromRegex
+}
+
+var metricFiltersGlobal atomic.Pointer[metricFilters]
+
+func initMetricFilters() {
+	// Register SIGHUP handler before loading the filters, so the filters are re-read if the signal arrives during loadMetricFilters call.
+	sighupCh := procutil.NewSighupChan()
+
+	mfs, err := loadMetricFilters()
+	if err != nil {
+		logger.Fatalf("cannot load metric name filters: %s", err)
+	}
+	metricFiltersGlobal.Store(mfs)
+	metricFiltersSuccess.Set(1)
+	metricFiltersTimestamp.Set(fasttime.UnixTimestamp())
+
+	if mfs == nil {
+		return
+	}
+	go func() {
+		for range sighupCh {
+			metricFiltersReloads.Inc()
+			logger.Infof("received SIGHUP; reloading -ingest.allowMetrics=%q and -ingest.denyMetrics=%q...", *allowMetricsFile, *denyMetricsFile)
+			mfs, err := loadMetricFilters()
+			if err != nil {
+				metricFiltersReloadErrors.Inc()
+				metricFiltersSuccess.Set(0)
+				logger.Errorf("cannot load the updated metric name filters: %s; preserving the previous filters", err)
+				continue
+			}
+			metricFiltersGlobal.Store(mfs)
+			metricFiltersSuccess.Set(1)
+			metricFiltersTimestamp.Set(fasttime.UnixTimestamp())
+			logger.Infof("successfully reloaded metric name filters")
+		}
+	}()
+}
+
+// CheckMetricFilters checks files pointed by -ingest.allowMetrics and -ingest.denyMetrics
+func CheckMetricFilters() error {
+	_, err := loadMetricFilters()
+	return err
+}
+
+func loadMetricFilters() (*metricFilters, error) {
+	if *allowMetricsFile == "" && *denyMetricsFile == "" {
+		return nil, nil
+	}
+	allow, err := loadMetricNamesRegex(*allowMetricsFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load -ingest.allowMetrics=%q: %w", *allowMetricsFile, err)
+	}
+	deny, err := loadMetricNamesRegex(*denyMetricsFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot load -ingest.denyMetrics=%q: %w", *denyMetricsFile, err)
+	}
+	mfs := &metricFilters{
+		hasAllowList: *allowMetricsFile != "",
+		allow:        allow,
+		deny:         deny,
+	}
+	return mfs, nil
+}
+
+func loadMetricNamesRegex(path string) (*regexutil.PromRegex, error) {
+	if path == "" {
+		return nil, nil
+	}
+	data, err := fscore.ReadFileOrHTTP(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseMetricNamesRegex(string(data))
+}
+
+// parseMetricNamesRegex parses metric names from s and returns a regex matching any of them.
+//
+// s must contain a metric name or a regular expression per line. Empty lines and lines starting with # are ignored.
+func parseMetricNamesRegex(s string) (*regexutil.PromRegex, error) {
+	var exprs []string
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if _, err := regexutil.NewPromRegex(line); err != nil {
+			return nil, fmt.Errorf("cannot parse %q: %w", line, err)
+		}
+		exprs = append(exprs, line)
+	}
+	if len(exprs) == 0 {
+		// An empty list matches nothing
+		return nil, nil
+	}
+	pr, err := regexutil.NewPromRegex(strings.Join(exprs, "|"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot build regex from metric names: %w", err)
+	}
+	return pr, nil
+}
+
+// hasMetricFilters returns true if -ingest.allowMetrics or -ingest.denyMetrics is set.
+func hasMetricFilters() bool {
+	return metricFiltersGlobal.Load() != nil
+}
+
+// isAllowedMetric returns true if the metric with the given labels passes -ingest.allowMetrics and -ingest.denyMetrics filters.
+func (mfs *metricFilters) isAllowedMetric(labels []prompb.Label) bool {
+	metricName := ""
+	for _, label := range labels {
+		if label.Name == "" || label.Name == "__name__" {
+			metricName = label.Value
+			break
+		}
+	}
+	if mfs.hasAllowList && (mfs.allow == nil || !mfs.allow.MatchString(metricName)) {
+		metricsDroppedByAllowList.Inc()
+		return false
+	}
+	if mfs.deny != nil && mfs.deny.MatchString(metricName) {
+		metricsDroppedByDenyList.Inc()
+		return false
+	}
+	return true
+}
@@ -0,0 +1,72 @@
+package relabel
+
+import (
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompb"
+)
+
+func TestParseMetricNamesRegex_Failure(t *testing.T) {
+	f := func(s string) {
+		t.Helper()
+
+		if _, err := parseMetricNamesRegex(s); err == nil {
+			t.Fatalf("expecting non-nil error for %q", s)
+		}
+	}
+
+	f("foo(")
+	f("foo\nbar[")
+}
+
+func TestMetricFiltersIsAllowedMetric(t *testing.T) {
+	f := func(allowList, denyList string, metricName string, resultExpected bool) {
+		t.Helper()
+
+		mfs := &metricFilters{
+			hasAllowList: allowList != "",
+		}
+		var err error
+		if allowList != "" {
+			mfs.allow, err = parseMetricNamesRegex(allowList)
+			if err != nil {
+				t.Fatalf("cannot parse allow list: %s", err)
+			}
+		}
+		if denyList != "" {
+			mfs.deny, err = parseMetricNamesRegex(denyList)
+			if err != nil {
+				t.Fatalf("cannot parse deny list: %s", err)
+			}
+		}
+		labels := []prompb.Label{
+			{Name: "job", Value: "bar"},
+			{Name: "", Value: metricName},
+		}
+		result := mfs.isAllowedMetric(labels)
+		if result != resultExpected {
+			t.Fatalf("unexpected result for metric %q; got %v; want %v", metricName, result, resultExpected)
+		}
+	}
+
+	// allow list
+	f("foo\nbar", "", "foo", true)
+	f("foo\nbar", "", "bar", true)
+	f("foo\nbar", "", "foobar", false)
+	f("# comment\n\n  node_.+  \n", "", "node_cpu_seconds_total", true)
+	f("# comment\n\n  node_.+  \n", "", "process_cpu_seconds_total", false)
+
+	// allow list without metric names drops all the metrics
+	f("# comment only", "", "foo", false)
+
+	// deny list
+	f("", "foo\ngo_.*", "foo", false)
+	f("", "foo\ngo_.*", "go_goroutines", false)
+	f("", "foo\ngo_.*", "foo_bar", true)
+	f("", "foo\ngo_.*", "", true)
+
+	// both lists
+	f("node_.+", "node_scrape_.*", "node_cpu_seconds_total", true)
+	f("node_.+", "node_scrape_.*", "node_scrape_collector_success", false)
+	f("node_.+", "node_scrape_.*", "up", false)
+}
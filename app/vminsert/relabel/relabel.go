@@ -31,6 +31,8 @@ func Init() {
 	// See https://github.com/VictoriaMetrics/VictoriaMetrics/issues/1240
 	sighupCh := procutil.NewSighupChan()
 
+	initMetricFilters()
+
 	pcs, err := loadRelabelConfig()
 	if err != nil {
 		logger.Fatalf("cannot load relabelConfig: %s", err)
@@ -87,10 +89,10 @@ func loadRelabelConfig() (*promrelabel.ParsedConfigs, error) {
 	return pcs, nil
 }
 
-// HasRelabeling returns true if there is global relabeling.
+// HasRelabeling returns true if there is global relabeling or metric name filtering via -ingest.allowMetrics or -ingest.denyMetrics.
 func HasRelabeling() bool {
 	pcs := pcsGlobal.Load()
-	return pcs.Len() > 0 || *usePromCompatibleNaming
+	return pcs.Len() > 0 || *usePromCompatibleNaming || hasMetricFilters()
 }
 
 // Ctx holds relabeling context.
@@ -109,6 +111,10 @@ func (ctx *Ctx) Reset() {
 //
 // The returned labels are valid until the next call to ApplyRelabeling.
 func (ctx *Ctx) ApplyRelabeling(labels []prompb.Label) []prompb.Label {
+	if mfs := metricFiltersGlobal.Load(); mfs != nil && !mfs.isAllowedMetric(labels) {
+		// Drop the metric before the relabeling.
+		return labels[:0]
+	}
 	pcs := pcsGlobal.Load()
 	if pcs.Len() == 0 && !*usePromCompatibleNaming {
 		// There are no relabeling rules.
@@ -0,0 +1,86 @@
+package statsd
+
+import (
+	"io"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vminsert/common"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vminsert/relabel"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+	parser "github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/statsd"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/statsd/stream"
+	"github.com/VictoriaMetrics/metrics"
+)
+
+var (
+	rowsInserted  = metrics.NewCounter(`vm_rows_inserted_total{type="statsd"}`)
+	rowsPerInsert = metrics.NewHistogram(`vm_rows_per_insert{type="statsd"}`)
+)
+
+var aggregator *parser.Aggregator
+
+// Init initializes the aggregation of StatsD data.
+//
+// MustStop must be called when the StatsD data is no longer received.
+func Init() {
+	aggregator = stream.NewAggregator(insertTimeSeries)
+}
+
+// MustStop stops the aggregation of StatsD data and writes the remaining aggregated samples to the storage.
+func MustStop() {
+	aggregator.MustStop()
+	aggregator = nil
+}
+
+// InsertHandler processes StatsD data.
+//
+// See https://github.com/statsd/statsd/blob/master/docs/metric_types.md
+func InsertHandler(r io.Reader) error {
+	return stream.Parse(r, func(rows []parser.Row) error {
+		aggregator.Push(rows)
+		return nil
+	})
+}
+
+func insertTimeSeries(tss []prompbmarshal.TimeSeries) {
+	ctx := common.GetInsertCtx()
+	defer common.PutInsertCtx(ctx)
+
+	ctx.Reset(len(tss))
+	hasRelabeling := relabel.HasRelabeling()
+	rowsTotal := 0
+	for i := range tss {
+		ts := &tss[i]
+		ctx.Labels = ctx.Labels[:0]
+		for _, label := range ts.Labels {
+			name := label.Name
+			if name == "__name__" {
+				name = ""
+			}
+			ctx.AddLabel(name, label.Value)
+		}
+		if hasRelabeling {
+			ctx.ApplyRelabeling()
+		}
+		if len(ctx.Labels) == 0 {
+			// Skip metric without labels.
+			continue
+		}
+		ctx.SortLabelsIfNeeded()
+		var metricNameRaw []byte
+		var err error
+		for _, s := range ts.Samples {
+			metricNameRaw, err = ctx.WriteDataPointExt(metricNameRaw, ctx.Labels, s.Timestamp, s.Value)
+			if err != nil {
+				logger.Errorf("cannot write aggregated StatsD data to storage: %s", err)
+				return
+			}
+		}
+		rowsTotal += len(ts.Samples)
+	}
+	rowsInserted.Add(rowsTotal)
+	rowsPerInsert.Update(float64(rowsTotal))
+	if err := ctx.FlushBufs(); err != nil {
+		logger.Errorf("cannot flush aggregated StatsD data to storage: %s", err)
+	}
+}
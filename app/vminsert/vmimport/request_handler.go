@@ -18,6 +18,7 @@ import (
 var (
 	rowsInserted  = metrics.NewCounter(`vm_rows_inserted_total{type="vmimport"}`)
 	rowsPerInsert = metrics.NewHistogram(`vm_rows_per_insert{type="vmimport"}`)
+	ingestionLag  = metrics.NewHistogram(`vm_ingestion_lag_seconds{type="vmimport"}`)
 )
 
 // InsertHandler processes `/api/v1/import` request.
@@ -81,6 +82,7 @@ func insertRows(rows []parser.Row, extraLabels []prompbmarshal.Label) error {
 	}
 	rowsInserted.Add(rowsTotal)
 	rowsPerInsert.Update(float64(rowsTotal))
+	ic.UpdateIngestionLag(ingestionLag)
 	return ic.FlushBufs()
 }
 
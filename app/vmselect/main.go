@@ -64,6 +64,7 @@ func Init() {
 	fs.RemoveDirContents(tmpDirPath)
 	netstorage.InitTmpBlocksDir(tmpDirPath)
 	promql.InitRollupResultCache(*vmstorage.DataPath + "/cache/rollupResult")
+	searchutils.InitLabelFilterMacros()
 
 	concurrencyLimitCh = make(chan struct{}, *maxConcurrentRequests)
 	initVMAlertProxy()
@@ -223,6 +224,15 @@ func RequestHandler(w http.ResponseWriter, r *http.Request) bool {
 			return true
 		}
 		return true
+	case "/api/v1/query_exemplars":
+		queryExemplarsRequests.Inc()
+		httpserver.EnableCORS(w, r)
+		if err := prometheus.QueryExemplarsHandler(qt, startTime, w, r); err != nil {
+			queryExemplarsErrors.Inc()
+			sendPrometheusError(w, r, err)
+			return true
+		}
+		return true
 	case "/api/v1/series":
 		seriesRequests.Inc()
 		httpserver.EnableCORS(w, r)
@@ -259,6 +269,15 @@ func RequestHandler(w http.ResponseWriter, r *http.Request) bool {
 			return true
 		}
 		return true
+	case "/api/v1/status/scrape_intervals":
+		statusScrapeIntervalsRequests.Inc()
+		httpserver.EnableCORS(w, r)
+		if err := prometheus.ScrapeIntervalsHandler(qt, startTime, w, r); err != nil {
+			statusScrapeIntervalsErrors.Inc()
+			sendPrometheusError(w, r, err)
+			return true
+		}
+		return true
 	case "/api/v1/export":
 		exportRequests.Inc()
 		if err := prometheus.ExportHandler(startTime, w, r); err != nil {
@@ -546,17 +565,11 @@ func handleStaticAndSimpleRequests(w http.ResponseWriter, r *http.Request, path
 		return true
 	case "/api/v1/status/buildinfo":
 		buildInfoRequests.Inc()
-		w.Header().Set("Content-Type", "application/json")
-		// prometheus version is used here, which affects what API Grafana uses when retrieving label values.
-		// as new Grafana features are added that are customized for the Prometheus version, maybe the version will need to be increased.
-		// see this issue for more info: https://github.com/VictoriaMetrics/VictoriaMetrics/issues/5370
-		fmt.Fprintf(w, "%s", `{"status":"success","data":{"version":"2.24.0"}}`)
+		prometheus.BuildInfoHandler(w, r)
 		return true
-	case "/api/v1/query_exemplars":
-		// Return dumb placeholder for https://prometheus.io/docs/prometheus/latest/querying/api/#querying-exemplars
-		queryExemplarsRequests.Inc()
-		w.Header().Set("Content-Type", "application/json")
-		fmt.Fprintf(w, "%s", `{"status":"success","data":[]}`)
+	case "/api/v1/status/flags":
+		statusFlagsRequests.Inc()
+		prometheus.StatusFlagsHandler(w, r)
 		return true
 	default:
 		return false
@@ -617,6 +630,9 @@ var (
 	statusTSDBRequests = metrics.NewCounter(`vm_http_requests_total{path="/api/v1/status/tsdb"}`)
 	statusTSDBErrors   = metrics.NewCounter(`vm_http_request_errors_total{path="/api/v1/status/tsdb"}`)
 
+	statusScrapeIntervalsRequests = metrics.NewCounter(`vm_http_requests_total{path="/api/v1/status/scrape_intervals"}`)
+	statusScrapeIntervalsErrors   = metrics.NewCounter(`vm_http_request_errors_total{path="/api/v1/status/scrape_intervals"}`)
+
 	statusActiveQueriesRequests = metrics.NewCounter(`vm_http_requests_total{path="/api/v1/status/active_queries"}`)
 
 	topQueriesRequests = metrics.NewCounter(`vm_http_requests_total{path="/api/v1/status/top_queries"}`)
@@ -689,9 +705,12 @@ var (
 	rulesRequests   = metrics.NewCounter(`vm_http_requests_total{path="/api/v1/rules"}`)
 	alertsRequests  = metrics.NewCounter(`vm_http_requests_total{path="/api/v1/alerts"}`)
 
-	metadataRequests       = metrics.NewCounter(`vm_http_requests_total{path="/api/v1/metadata"}`)
-	buildInfoRequests      = metrics.NewCounter(`vm_http_requests_total{path="/api/v1/buildinfo"}`)
+	metadataRequests    = metrics.NewCounter(`vm_http_requests_total{path="/api/v1/metadata"}`)
+	buildInfoRequests   = metrics.NewCounter(`vm_http_requests_total{path="/api/v1/buildinfo"}`)
+	statusFlagsRequests = metrics.NewCounter(`vm_http_requests_total{path="/api/v1/status/flags"}`)
+
 	queryExemplarsRequests = metrics.NewCounter(`vm_http_requests_total{path="/api/v1/query_exemplars"}`)
+	queryExemplarsErrors   = metrics.NewCounter(`vm_http_request_errors_total{path="/api/v1/query_exemplars"}`)
 )
 
 func proxyVMAlertRequests(w http.ResponseWriter, r *http.Request) {
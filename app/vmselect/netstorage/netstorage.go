@@ -6,6 +6,7 @@ import (
 	"flag"
 	"fmt"
 	"sort"
+	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -19,6 +20,7 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/cgroup"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fasttime"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/querytracer"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/storage"
 )
@@ -38,6 +40,18 @@ var (
 		"There is no sense in setting this flag to values bigger than the number of CPU cores available on the system")
 )
 
+// maxSamplesPerQueryRuntime allows changing -search.maxSamplesPerQuery at runtime.
+var maxSamplesPerQueryRuntime = flagutil.NewRuntimeValue("search.maxSamplesPerQuery", maxSamplesPerQuery, func(s string) (int, error) {
+	n, err := strconv.ParseInt(s, 0, strconv.IntSize)
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, fmt.Errorf("the value cannot be negative; got %d", n)
+	}
+	return int(n), nil
+})
+
 // Result is a single timeseries result.
 //
 // ProcessSearchQuery returns Result slice.
@@ -48,10 +62,16 @@ type Result struct {
 	// Values are sorted by Timestamps.
 	Values     []float64
 	Timestamps []int64
+
+	// ScrapeInterval is the observed interval between the ingested samples for the time series.
+	//
+	// It is zero if the interval is unknown. See -storage.trackScrapeIntervals.
+	ScrapeInterval storage.ScrapeInterval
 }
 
 func (r *Result) reset() {
 	r.MetricName.Reset()
+	r.ScrapeInterval = storage.ScrapeInterval{}
 	r.Values = r.Values[:0]
 	r.Timestamps = r.Timestamps[:0]
 }
@@ -336,7 +356,12 @@ var (
 
 type packedTimeseries struct {
 	metricName string
+	metricID   uint64
 	brs        []blockRef
+
+	// remoteTimestamps and remoteValues contain samples fetched from -remoteCluster.url.
+	remoteTimestamps []int64
+	remoteValues     []float64
 }
 
 type unpackWork struct {
@@ -432,6 +457,7 @@ func (pts *packedTimeseries) Unpack(dst *Result, tbf *tmpBlocksFile, tr storage.
 	if err := dst.MetricName.Unmarshal(bytesutil.ToUnsafeBytes(pts.metricName)); err != nil {
 		return fmt.Errorf("cannot unmarshal metricName %q: %w", pts.metricName, err)
 	}
+	dst.ScrapeInterval, _ = vmstorage.Storage.GetScrapeInterval(pts.metricID)
 	sbh := getSortBlocksHeap()
 	var err error
 	sbh.sbs, err = pts.unpackTo(sbh.sbs[:0], tbf, tr)
@@ -443,6 +469,11 @@ func (pts *packedTimeseries) Unpack(dst *Result, tbf *tmpBlocksFile, tr storage.
 	dedupInterval := storage.GetDedupInterval()
 	mergeSortBlocks(dst, sbh, dedupInterval)
 	putSortBlocksHeap(sbh)
+	if len(pts.remoteTimestamps) > 0 {
+		// Local samples take precedence over remote samples with identical timestamps.
+		dst.Timestamps, dst.Values = mergeSamples(dst.Timestamps, dst.Values, pts.remoteTimestamps, pts.remoteValues)
+		dst.Timestamps, dst.Values = storage.DeduplicateSamples(dst.Timestamps, dst.Values, dedupInterval)
+	}
 	return nil
 }
 
@@ -1118,6 +1149,118 @@ func SearchMetricNames(qt *querytracer.Tracer, sq *storage.SearchQuery, deadline
 	return metricNames, nil
 }
 
+// SearchMetricNamesWithLimit returns up to limit metric names matching sq until the given deadline.
+//
+// The returned metric names are sorted. If limit > 0, then only the first limit metric names in sorted order are returned,
+// while the memory usage is proportional to limit instead of the number of matching metric names.
+//
+// The returned metric names must be unmarshaled via storage.MetricName.UnmarshalString().
+func SearchMetricNamesWithLimit(qt *querytracer.Tracer, sq *storage.SearchQuery, limit int, deadline searchutils.Deadline) ([]string, error) {
+	if limit <= 0 {
+		return SearchMetricNames(qt, sq, deadline)
+	}
+	qt = qt.NewChild("fetch metric names: %s, limit=%d", sq, limit)
+	defer qt.Done()
+	if deadline.Exceeded() {
+		return nil, fmt.Errorf("timeout exceeded before starting to search metric names: %s", deadline.String())
+	}
+
+	// Setup search.
+	tr := sq.GetTimeRange()
+	if err := vmstorage.CheckTimeRange(tr); err != nil {
+		return nil, err
+	}
+	tfss, err := setupTfss(qt, tr, sq.TagFilterss, sq.MaxMetrics, deadline)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep the smallest limit metric names in a max-heap, so the biggest of them can be quickly replaced.
+	var h metricNamesMaxHeap
+	err = vmstorage.ForEachMetricName(qt, tfss, tr, sq.MaxMetrics, deadline.Deadline(), func(metricName []byte) error {
+		if len(h) < limit {
+			heap.Push(&h, string(metricName))
+			return nil
+		}
+		if string(metricName) < h[0] {
+			h[0] = string(metricName)
+			heap.Fix(&h, 0)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot find metric names: %w", err)
+	}
+	metricNames := []string(h)
+	sort.Strings(metricNames)
+	qt.Printf("sort %d metric names", len(metricNames))
+	return metricNames, nil
+}
+
+type metricNamesMaxHeap []string
+
+func (h *metricNamesMaxHeap) Len() int {
+	return len(*h)
+}
+
+func (h *metricNamesMaxHeap) Less(i, j int) bool {
+	a := *h
+	return a[i] > a[j]
+}
+
+func (h *metricNamesMaxHeap) Swap(i, j int) {
+	a := *h
+	a[i], a[j] = a[j], a[i]
+}
+
+func (h *metricNamesMaxHeap) Push(x any) {
+	*h = append(*h, x.(string))
+}
+
+func (h *metricNamesMaxHeap) Pop() any {
+	a := *h
+	x := a[len(a)-1]
+	*h = a[:len(a)-1]
+	return x
+}
+
+// ForEachScrapeInterval calls f for every unique metric name matching the given sq with the observed interval between the ingested samples.
+//
+// Time series without the observed interval are skipped. See -storage.trackScrapeIntervals.
+// If limit > 0, then the search stops after f is called for limit metric names.
+//
+// f mustn't hold mn and si after returning.
+func ForEachScrapeInterval(qt *querytracer.Tracer, sq *storage.SearchQuery, limit int, deadline searchutils.Deadline,
+	f func(mn *storage.MetricName, si *storage.ScrapeInterval) error) error {
+	qt = qt.NewChild("fetch scrape intervals: %s, limit=%d", sq, limit)
+	defer qt.Done()
+	if deadline.Exceeded() {
+		return fmt.Errorf("timeout exceeded before starting to search scrape intervals: %s", deadline.String())
+	}
+
+	// Setup search.
+	tr := sq.GetTimeRange()
+	if err := vmstorage.CheckTimeRange(tr); err != nil {
+		return err
+	}
+	tfss, err := setupTfss(qt, tr, sq.TagFilterss, sq.MaxMetrics, deadline)
+	if err != nil {
+		return err
+	}
+
+	var mn storage.MetricName
+	err = vmstorage.ForEachScrapeInterval(qt, tfss, tr, sq.MaxMetrics, limit, deadline.Deadline(), func(metricName []byte, si *storage.ScrapeInterval) error {
+		if err := mn.Unmarshal(metricName); err != nil {
+			return fmt.Errorf("cannot unmarshal metric name: %w", err)
+		}
+		return f(&mn, si)
+	})
+	if err != nil {
+		return fmt.Errorf("cannot find scrape intervals: %w", err)
+	}
+	return nil
+}
+
 // ProcessSearchQuery performs sq until the given deadline.
 //
 // Results.RunParallel or Results.Cancel must be called on the returned Results.
@@ -1138,6 +1281,25 @@ func ProcessSearchQuery(qt *querytracer.Tracer, sq *storage.SearchQuery, deadlin
 		return nil, err
 	}
 
+	// Fetch series from -remoteCluster.url in parallel with the local search.
+	type remoteResult struct {
+		rss []remoteSeries
+		err error
+	}
+	var remoteCh chan remoteResult
+	if len(*remoteClusterURLs) > 0 {
+		remoteCh = make(chan remoteResult, 1)
+		qtRemote := qt.NewChild("fetch series from %d remote clusters", len(*remoteClusterURLs))
+		go func() {
+			rss, err := fetchRemoteSeries(qtRemote, sq, deadline)
+			qtRemote.Done()
+			remoteCh <- remoteResult{
+				rss: rss,
+				err: err,
+			}
+		}()
+	}
+
 	vmstorage.WG.Add(1)
 	defer vmstorage.WG.Done()
 
@@ -1147,6 +1309,10 @@ func ProcessSearchQuery(qt *querytracer.Tracer, sq *storage.SearchQuery, deadlin
 	indexSearchDuration.UpdateDuration(startTime)
 	type blockRefs struct {
 		brs []blockRef
+
+		// metricID is the MetricID for the most recently read block.
+		// The same metricName may have distinct MetricIDs after indexdb rotation.
+		metricID uint64
 	}
 
 	blocksRead := 0
@@ -1198,11 +1364,11 @@ func ProcessSearchQuery(qt *querytracer.Tracer, sq *storage.SearchQuery, deadlin
 		// are left then because of the given time range.
 		// This allows effectively limiting CPU resources used per query.
 		samples += br.RowsCount()
-		if *maxSamplesPerQuery > 0 && samples > *maxSamplesPerQuery {
+		if maxSamples := maxSamplesPerQueryRuntime.Get(); maxSamples > 0 && samples > maxSamples {
 			putTmpBlocksFile(tbf)
 			putStorageSearch(sr)
 			return nil, fmt.Errorf("cannot select more than -search.maxSamplesPerQuery=%d samples; possible solutions: increase the -search.maxSamplesPerQuery; "+
-				"reduce time range for the query; use more specific label filters in order to select fewer series", *maxSamplesPerQuery)
+				"reduce time range for the query; use more specific label filters in order to select fewer series", maxSamples)
 		}
 
 		buf = br.Marshal(buf[:0])
@@ -1229,6 +1395,7 @@ func ProcessSearchQuery(qt *querytracer.Tracer, sq *storage.SearchQuery, deadlin
 		}
 
 		brs := &brssPool[brsIdx]
+		brs.metricID = br.MetricID()
 		partRef := br.PartRef()
 		if uintptr(cap(brsPool)) >= maxFastAllocBlockSize/unsafe.Sizeof(blockRef{}) && len(brsPool) == cap(brsPool) {
 			// Allocate a new brsPool in order to avoid slow allocation of an object
@@ -1285,9 +1452,22 @@ func ProcessSearchQuery(qt *querytracer.Tracer, sq *storage.SearchQuery, deadlin
 	rss.deadline = deadline
 	pts := make([]packedTimeseries, len(orderedMetricNames))
 	for i, metricName := range orderedMetricNames {
+		brs := &brssPool[m[metricName]]
 		pts[i] = packedTimeseries{
 			metricName: metricName,
-			brs:        brssPool[m[metricName]].brs,
+			metricID:   brs.metricID,
+			brs:        brs.brs,
+		}
+	}
+	if remoteCh != nil {
+		remote := <-remoteCh
+		if remote.err == nil {
+			pts, remote.err = addRemoteSeries(pts, remote.rss, samples)
+		}
+		if remote.err != nil {
+			putTmpBlocksFile(tbf)
+			putStorageSearch(sr)
+			return nil, remote.err
 		}
 	}
 	rss.packedTimeseries = pts
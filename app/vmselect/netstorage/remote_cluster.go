@@ -0,0 +1,278 @@
+package netstorage
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmselect/searchutils"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/common"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/vmimport"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/querytracer"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/storage"
+)
+
+var (
+	remoteClusterURLs = flagutil.NewArrayString("remoteCluster.url", "Optional URL of Prometheus querying API at remote VictoriaMetrics cluster "+
+		"to fetch raw samples from during queries, e.g. http://victoria-metrics:8428 or http://vmselect:8481/select/0/prometheus . "+
+		"Samples from all the remote clusters are merged with local samples and are de-duplicated. "+
+		"The remote clusters mustn't refer back to the current instance. See https://docs.victoriametrics.com/#multi-cluster-querying")
+	remoteClusterDenyPartialResponse = flag.Bool("remoteCluster.denyPartialResponse", false, "Whether to return an error when at least a single "+
+		"-remoteCluster.url is unavailable. By default such clusters are skipped and the query returns partial results")
+	remoteClusterMaxLineLen = flagutil.NewBytes("remoteCluster.maxLineLen", 10*1024*1024, "The maximum length in bytes of a single line "+
+		"in responses from -remoteCluster.url")
+)
+
+var remoteClusterClient = &http.Client{}
+
+// remoteSeries contains raw samples for a single time series fetched from -remoteCluster.url.
+type remoteSeries struct {
+	// metricName is marshaled storage.MetricName with sorted tags.
+	metricName string
+
+	timestamps []int64
+	values     []float64
+}
+
+// fetchRemoteSeries fetches raw samples matching sq from all the -remoteCluster.url until the given deadline.
+//
+// The returned series are sorted by metricName, while samples in every series are sorted by timestamps.
+// Samples with identical timestamps from distinct clusters are de-duplicated.
+func fetchRemoteSeries(qt *querytracer.Tracer, sq *storage.SearchQuery, deadline searchutils.Deadline) ([]remoteSeries, error) {
+	urls := *remoteClusterURLs
+	if len(urls) == 0 {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithDeadline(context.Background(), time.Unix(int64(deadline.Deadline()), 0))
+	defer cancel()
+
+	rsss := make([][]remoteSeries, len(urls))
+	errs := make([]error, len(urls))
+	var wg sync.WaitGroup
+	for i, u := range urls {
+		wg.Add(1)
+		go func(i int, u string) {
+			defer wg.Done()
+			rsss[i], errs[i] = fetchRemoteClusterSeries(ctx, u, sq)
+		}(i, u)
+	}
+	wg.Wait()
+
+	var rss []remoteSeries
+	for i, rssLocal := range rsss {
+		if err := errs[i]; err != nil {
+			remoteClusterErrors.Inc()
+			err = fmt.Errorf("cannot fetch series from -remoteCluster.url=%q: %w", urls[i], err)
+			if *remoteClusterDenyPartialResponse {
+				return nil, err
+			}
+			logger.Warnf("skipping remote cluster: %s", err)
+			continue
+		}
+		rss = append(rss, rssLocal...)
+	}
+	rss = mergeRemoteSeries(rss)
+	qt.Printf("fetched %d unique series", len(rss))
+	return rss, nil
+}
+
+var remoteClusterErrors = metrics.NewCounter(`vm_remote_cluster_errors_total`)
+
+func fetchRemoteClusterSeries(ctx context.Context, remoteURL string, sq *storage.SearchQuery) ([]remoteSeries, error) {
+	args := url.Values{}
+	for _, tfs := range sq.TagFilterss {
+		args.Add("match[]", tagFiltersToSelector(tfs))
+	}
+	args.Set("start", formatTimestampSeconds(sq.MinTimestamp))
+	args.Set("end", formatTimestampSeconds(sq.MaxTimestamp))
+	exportURL := strings.TrimSuffix(remoteURL, "/") + "/api/v1/export?" + args.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, exportURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create request: %w", err)
+	}
+	resp, err := remoteClusterClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("cannot perform request: %w", err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("unexpected status code %d; response body: %q", resp.StatusCode, body)
+	}
+	return readRemoteSeries(resp.Body, sq.GetTimeRange())
+}
+
+// readRemoteSeries reads series in JSON line format from r.
+//
+// Only samples on the time range tr are returned.
+func readRemoteSeries(r io.Reader, tr storage.TimeRange) ([]remoteSeries, error) {
+	var rss []remoteSeries
+	var rows vmimport.Rows
+	var mn storage.MetricName
+	var buf, reqBuf, tailBuf []byte
+	for {
+		var err error
+		reqBuf, tailBuf, err = common.ReadLinesBlockExt(r, reqBuf, tailBuf, remoteClusterMaxLineLen.IntN())
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return rss, nil
+			}
+			return nil, err
+		}
+		rows.Unmarshal(string(reqBuf))
+		for i := range rows.Rows {
+			row := &rows.Rows[i]
+			mn.Reset()
+			for _, tag := range row.Tags {
+				if string(tag.Key) == "__name__" {
+					mn.MetricGroup = append(mn.MetricGroup[:0], tag.Value...)
+					continue
+				}
+				mn.AddTagBytes(tag.Key, tag.Value)
+			}
+			buf = mn.SortAndMarshal(buf[:0])
+			rs := remoteSeries{
+				metricName: string(buf),
+			}
+			for j, ts := range row.Timestamps {
+				if ts < tr.MinTimestamp || ts > tr.MaxTimestamp {
+					continue
+				}
+				rs.timestamps = append(rs.timestamps, ts)
+				rs.values = append(rs.values, row.Values[j])
+			}
+			if len(rs.timestamps) > 0 {
+				rss = append(rss, rs)
+			}
+		}
+	}
+}
+
+// mergeRemoteSeries merges series with identical metric names in rss.
+func mergeRemoteSeries(rss []remoteSeries) []remoteSeries {
+	sort.SliceStable(rss, func(i, j int) bool {
+		return rss[i].metricName < rss[j].metricName
+	})
+	dst := rss[:0]
+	for _, rs := range rss {
+		if len(dst) > 0 && dst[len(dst)-1].metricName == rs.metricName {
+			last := &dst[len(dst)-1]
+			last.timestamps, last.values = mergeSamples(last.timestamps, last.values, rs.timestamps, rs.values)
+			continue
+		}
+		rs.timestamps, rs.values = mergeSamples(nil, nil, rs.timestamps, rs.values)
+		dst = append(dst, rs)
+	}
+	return dst
+}
+
+// mergeSamples merges samples from (srcTimestamps, srcValues) into (dstTimestamps, dstValues) and returns the result.
+//
+// The returned samples are sorted by timestamps. Only the first sample is left for samples with identical timestamps,
+// so samples from dst take precedence over samples from src.
+func mergeSamples(dstTimestamps []int64, dstValues []float64, srcTimestamps []int64, srcValues []float64) ([]int64, []float64) {
+	if len(srcTimestamps) == 0 {
+		return dstTimestamps, dstValues
+	}
+	type sample struct {
+		timestamp int64
+		value     float64
+	}
+	samples := make([]sample, 0, len(dstTimestamps)+len(srcTimestamps))
+	for i, ts := range dstTimestamps {
+		samples = append(samples, sample{
+			timestamp: ts,
+			value:     dstValues[i],
+		})
+	}
+	for i, ts := range srcTimestamps {
+		samples = append(samples, sample{
+			timestamp: ts,
+			value:     srcValues[i],
+		})
+	}
+	sort.SliceStable(samples, func(i, j int) bool {
+		return samples[i].timestamp < samples[j].timestamp
+	})
+	dstTimestamps = dstTimestamps[:0]
+	dstValues = dstValues[:0]
+	for i, s := range samples {
+		if i > 0 && s.timestamp == samples[i-1].timestamp {
+			continue
+		}
+		dstTimestamps = append(dstTimestamps, s.timestamp)
+		dstValues = append(dstValues, s.value)
+	}
+	return dstTimestamps, dstValues
+}
+
+// tagFiltersToSelector returns series selector for the given tfs, which can be passed to match[] query arg.
+func tagFiltersToSelector(tfs []storage.TagFilter) string {
+	a := make([]string, len(tfs))
+	for i := range tfs {
+		tf := &tfs[i]
+		key := string(tf.Key)
+		if key == "" {
+			key = "__name__"
+		}
+		op := "="
+		switch {
+		case tf.IsNegative && tf.IsRegexp:
+			op = "!~"
+		case tf.IsNegative:
+			op = "!="
+		case tf.IsRegexp:
+			op = "=~"
+		}
+		a[i] = key + op + strconv.Quote(string(tf.Value))
+	}
+	return "{" + strings.Join(a, ",") + "}"
+}
+
+func formatTimestampSeconds(timestamp int64) string {
+	return strconv.FormatFloat(float64(timestamp)/1e3, 'f', 3, 64)
+}
+
+// addRemoteSeries attaches samples from rss to pts with identical metric names and returns the result.
+//
+// Series missing in pts are appended to it. samples must contain the number of local samples selected by the query.
+func addRemoteSeries(pts []packedTimeseries, rss []remoteSeries, samples int) ([]packedTimeseries, error) {
+	m := make(map[string]int, len(pts))
+	for i := range pts {
+		m[pts[i].metricName] = i
+	}
+	for _, rs := range rss {
+		samples += len(rs.timestamps)
+		if maxSamples := maxSamplesPerQueryRuntime.Get(); maxSamples > 0 && samples > maxSamples {
+			return nil, fmt.Errorf("cannot select more than -search.maxSamplesPerQuery=%d samples; possible solutions: increase the -search.maxSamplesPerQuery; "+
+				"reduce time range for the query; use more specific label filters in order to select fewer series", maxSamples)
+		}
+		idx, ok := m[rs.metricName]
+		if !ok {
+			pts = append(pts, packedTimeseries{
+				metricName: rs.metricName,
+			})
+			idx = len(pts) - 1
+		}
+		pts[idx].remoteTimestamps = rs.timestamps
+		pts[idx].remoteValues = rs.values
+	}
+	return pts, nil
+}
@@ -0,0 +1,177 @@
+package netstorage
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/storage"
+)
+
+func TestTagFiltersToSelector(t *testing.T) {
+	f := func(tfs []storage.TagFilter, resultExpected string) {
+		t.Helper()
+		result := tagFiltersToSelector(tfs)
+		if result != resultExpected {
+			t.Fatalf("unexpected result;\ngot\n%s\nwant\n%s", result, resultExpected)
+		}
+	}
+
+	f(nil, `{}`)
+	f([]storage.TagFilter{
+		{
+			Value: []byte("foo"),
+		},
+	}, `{__name__="foo"}`)
+	f([]storage.TagFilter{
+		{
+			Value:    []byte("foo|bar"),
+			IsRegexp: true,
+		},
+		{
+			Key:        []byte("job"),
+			Value:      []byte(`a"b`),
+			IsNegative: true,
+		},
+		{
+			Key:        []byte("instance"),
+			Value:      []byte(`x.+`),
+			IsNegative: true,
+			IsRegexp:   true,
+		},
+	}, `{__name__=~"foo|bar",job!="a\"b",instance!~"x.+"}`)
+}
+
+func TestMergeSamples(t *testing.T) {
+	f := func(dstTimestamps []int64, dstValues []float64, srcTimestamps []int64, srcValues []float64, timestampsExpected []int64, valuesExpected []float64) {
+		t.Helper()
+		timestamps, values := mergeSamples(dstTimestamps, dstValues, srcTimestamps, srcValues)
+		if !reflect.DeepEqual(timestamps, timestampsExpected) {
+			t.Fatalf("unexpected timestamps;\ngot\n%v\nwant\n%v", timestamps, timestampsExpected)
+		}
+		if !reflect.DeepEqual(values, valuesExpected) {
+			t.Fatalf("unexpected values;\ngot\n%v\nwant\n%v", values, valuesExpected)
+		}
+	}
+
+	// empty src
+	f([]int64{1, 2}, []float64{3, 4}, nil, nil, []int64{1, 2}, []float64{3, 4})
+
+	// empty dst
+	f(nil, nil, []int64{3, 1, 2}, []float64{30, 10, 20}, []int64{1, 2, 3}, []float64{10, 20, 30})
+
+	// interleaved samples; dst takes precedence on duplicate timestamps
+	f([]int64{1, 3, 5}, []float64{1, 3, 5}, []int64{2, 3, 6}, []float64{20, 30, 60}, []int64{1, 2, 3, 5, 6}, []float64{1, 20, 3, 5, 60})
+}
+
+func TestReadRemoteSeries(t *testing.T) {
+	data := `{"metric":{"__name__":"foo","job":"x","instance":"a"},"values":[1,2,3],"timestamps":[1000,2000,3000]}
+{"metric":{"__name__":"bar"},"values":[4],"timestamps":[5000]}
+{"metric":{"instance":"a","__name__":"foo","job":"x"},"values":[5,6],"timestamps":[2000,2500]}
+`
+	tr := storage.TimeRange{
+		MinTimestamp: 2000,
+		MaxTimestamp: 4000,
+	}
+	rss, err := readRemoteSeries(strings.NewReader(data), tr)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	rss = mergeRemoteSeries(rss)
+	if len(rss) != 1 {
+		t.Fatalf("unexpected number of series; got %d; want 1", len(rss))
+	}
+	var mn storage.MetricName
+	if err := mn.UnmarshalString(rss[0].metricName); err != nil {
+		t.Fatalf("cannot unmarshal metric name: %s", err)
+	}
+	if s := mn.String(); s != `foo{job="x",instance="a"}` {
+		t.Fatalf("unexpected metric name; got %s; want %s", s, `foo{job="x",instance="a"}`)
+	}
+	timestampsExpected := []int64{2000, 2500, 3000}
+	if !reflect.DeepEqual(rss[0].timestamps, timestampsExpected) {
+		t.Fatalf("unexpected timestamps;\ngot\n%v\nwant\n%v", rss[0].timestamps, timestampsExpected)
+	}
+	valuesExpected := []float64{2, 6, 3}
+	if !reflect.DeepEqual(rss[0].values, valuesExpected) {
+		t.Fatalf("unexpected values;\ngot\n%v\nwant\n%v", rss[0].values, valuesExpected)
+	}
+}
+
+func TestFetchRemoteClusterSeries(t *testing.T) {
+	var requestURI string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestURI = r.URL.Path + "?" + r.URL.RawQuery
+		fmt.Fprintf(w, `{"metric":{"__name__":"foo"},"values":[1],"timestamps":[1500]}`)
+	}))
+	defer srv.Close()
+
+	sq := storage.NewSearchQuery(1000, 2000, [][]storage.TagFilter{{
+		{
+			Value: []byte("foo"),
+		},
+	}}, 0)
+	rss, err := fetchRemoteClusterSeries(context.Background(), srv.URL+"/select/0/prometheus/", sq)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	requestURIExpected := `/select/0/prometheus/api/v1/export?end=2.000&match%5B%5D=%7B__name__%3D%22foo%22%7D&start=1.000`
+	if requestURI != requestURIExpected {
+		t.Fatalf("unexpected request uri;\ngot\n%s\nwant\n%s", requestURI, requestURIExpected)
+	}
+	if len(rss) != 1 || !reflect.DeepEqual(rss[0].timestamps, []int64{1500}) || !reflect.DeepEqual(rss[0].values, []float64{1}) {
+		t.Fatalf("unexpected series fetched: %+v", rss)
+	}
+
+	// remote error
+	srvErr := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srvErr.Close()
+	if _, err := fetchRemoteClusterSeries(context.Background(), srvErr.URL, sq); err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+}
+
+func TestAddRemoteSeries(t *testing.T) {
+	pts := []packedTimeseries{
+		{
+			metricName: "foo",
+		},
+		{
+			metricName: "bar",
+		},
+	}
+	rss := []remoteSeries{
+		{
+			metricName: "bar",
+			timestamps: []int64{1},
+			values:     []float64{2},
+		},
+		{
+			metricName: "baz",
+			timestamps: []int64{3, 4},
+			values:     []float64{5, 6},
+		},
+	}
+	pts, err := addRemoteSeries(pts, rss, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(pts) != 3 {
+		t.Fatalf("unexpected number of series; got %d; want 3", len(pts))
+	}
+	if pts[0].remoteTimestamps != nil {
+		t.Fatalf("unexpected remote samples for %q", pts[0].metricName)
+	}
+	if !reflect.DeepEqual(pts[1].remoteTimestamps, []int64{1}) {
+		t.Fatalf("unexpected remote timestamps for %q: %v", pts[1].metricName, pts[1].remoteTimestamps)
+	}
+	if pts[2].metricName != "baz" || !reflect.DeepEqual(pts[2].remoteValues, []float64{5, 6}) {
+		t.Fatalf("unexpected remote series: %+v", pts[2])
+	}
+}
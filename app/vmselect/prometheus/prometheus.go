@@ -1,6 +1,7 @@
 package prometheus
 
 import (
+	"encoding/base64"
 	"flag"
 	"fmt"
 	"github.com/VictoriaMetrics/metricsql"
@@ -65,6 +66,18 @@ var (
 		"See also -search.maxLabelsAPISeries and -search.maxLabelsAPIDuration")
 )
 
+// maxPointsPerTimeseriesRuntime allows changing -search.maxPointsPerTimeseries at runtime.
+var maxPointsPerTimeseriesRuntime = flagutil.NewRuntimeValue("search.maxPointsPerTimeseries", maxPointsPerTimeseries, func(s string) (int, error) {
+	n, err := strconv.ParseInt(s, 0, strconv.IntSize)
+	if err != nil {
+		return 0, err
+	}
+	if n <= 0 {
+		return 0, fmt.Errorf("the value must be positive; got %d", n)
+	}
+	return int(n), nil
+})
+
 // Default step used if not set.
 const defaultStep = 5 * 60 * 1000
 
@@ -294,7 +307,18 @@ func ExportHandler(startTime time.Time, w http.ResponseWriter, r *http.Request)
 	if err != nil {
 		return err
 	}
+	hasNewSamples := true
+	if _, ok := r.Form["since_token"]; ok {
+		hasNewSamples, err = adjustExportParamsForSinceToken(w, r, cp)
+		if err != nil {
+			return err
+		}
+	}
 	format := r.FormValue("format")
+	if !hasNewSamples {
+		// There are no new samples since the previous incremental export request.
+		return writeEmptyExportResponse(w, format)
+	}
 	maxRowsPerLine := int(fastfloat.ParseInt64BestEffort(r.FormValue("max_rows_per_line")))
 	reduceMemUsage := httputils.GetBool(r, "reduce_mem_usage")
 	if err := exportHandler(nil, w, cp, format, maxRowsPerLine, reduceMemUsage); err != nil {
@@ -303,6 +327,75 @@ func ExportHandler(startTime time.Time, w http.ResponseWriter, r *http.Request)
 	return nil
 }
 
+// adjustExportParamsForSinceToken limits the time range for cp to samples, which weren't exported
+// by the previous incremental export request, according to `since_token` query arg.
+//
+// The token contains the maximum timestamp of the exported samples, so samples with smaller timestamps,
+// which are ingested after the previous request, aren't exported. This isn't change data capture.
+//
+// The token for the next incremental export request is returned in `X-Next-Since-Token` response header.
+// The end of the time range is limited by -search.latencyOffset, since the most recent samples may be still in flight.
+//
+// false is returned if there are no new samples to export since the previous request.
+func adjustExportParamsForSinceToken(w http.ResponseWriter, r *http.Request, cp *commonParams) (bool, error) {
+	start := cp.start
+	sinceToken := r.FormValue("since_token")
+	if sinceToken != "" {
+		ts, err := unmarshalExportSinceToken(sinceToken)
+		if err != nil {
+			return false, fmt.Errorf("cannot parse `since_token` arg %q: %w", sinceToken, err)
+		}
+		start = max(start, ts+1)
+	}
+	latencyOffset, err := getLatencyOffsetMilliseconds(r)
+	if err != nil {
+		return false, err
+	}
+	end := min(cp.end, cp.currentTimestamp-latencyOffset)
+	if end < start {
+		// Return the token pointing to the same time, so the next request starts from it.
+		w.Header().Set("X-Next-Since-Token", marshalExportSinceToken(start-1))
+		return false, nil
+	}
+	cp.start = start
+	cp.end = end
+	w.Header().Set("X-Next-Since-Token", marshalExportSinceToken(end))
+	return true, nil
+}
+
+// writeEmptyExportResponse writes an empty response in the given format for /api/v1/export.
+func writeEmptyExportResponse(w http.ResponseWriter, format string) error {
+	contentType := "application/stream+json; charset=utf-8"
+	if format == "prometheus" {
+		contentType = "text/plain; charset=utf-8"
+	}
+	w.Header().Set("Content-Type", contentType)
+	if format != "promapi" {
+		return nil
+	}
+	bw := bufferedwriter.Get(w)
+	defer bufferedwriter.Put(bw)
+	WriteExportPromAPIHeader(bw)
+	WriteExportPromAPIFooter(bw, nil)
+	return bw.Flush()
+}
+
+func marshalExportSinceToken(timestamp int64) string {
+	b := encoding.MarshalInt64(nil, timestamp)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func unmarshalExportSinceToken(s string) (int64, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return 0, err
+	}
+	if len(b) != 8 {
+		return 0, fmt.Errorf("unexpected token length; got %d bytes; want 8 bytes", len(b))
+	}
+	return encoding.UnmarshalInt64(b), nil
+}
+
 var exportDuration = metrics.NewSummary(`vm_request_duration_seconds{path="/api/v1/export"}`)
 
 func exportHandler(qt *querytracer.Tracer, w http.ResponseWriter, cp *commonParams, format string, maxRowsPerLine int, reduceMemUsage bool) error {
@@ -663,16 +756,13 @@ func SeriesHandler(qt *querytracer.Tracer, startTime time.Time, w http.ResponseW
 	}
 
 	sq := storage.NewSearchQuery(cp.start, cp.end, cp.filterss, *maxSeriesLimit)
-	metricNames, err := netstorage.SearchMetricNames(qt, sq, cp.deadline)
+	metricNames, err := netstorage.SearchMetricNamesWithLimit(qt, sq, limit, cp.deadline)
 	if err != nil {
 		return fmt.Errorf("cannot fetch time series for %q: %w", sq, err)
 	}
 	w.Header().Set("Content-Type", "application/json")
 	bw := bufferedwriter.Get(w)
 	defer bufferedwriter.Put(bw)
-	if limit > 0 && limit < len(metricNames) {
-		metricNames = metricNames[:limit]
-	}
 	qtDone := func() {
 		qt.Donef("start=%d, end=%d", cp.start, cp.end)
 	}
@@ -790,7 +880,7 @@ func QueryHandler(qt *querytracer.Tracer, startTime time.Time, w http.ResponseWr
 		Start:               start,
 		End:                 start,
 		Step:                step,
-		MaxPointsPerSeries:  *maxPointsPerTimeseries,
+		MaxPointsPerSeries:  maxPointsPerTimeseriesRuntime.Get(),
 		MaxSeries:           *maxUniqueTimeseries,
 		QuotedRemoteAddr:    httpserver.GetQuotedRemoteAddr(r),
 		Deadline:            deadline,
@@ -886,7 +976,7 @@ func queryRangeHandler(qt *querytracer.Tracer, startTime time.Time, w http.Respo
 	if start > end {
 		end = start + defaultStep
 	}
-	if err := promql.ValidateMaxPointsPerSeries(start, end, step, *maxPointsPerTimeseries); err != nil {
+	if err := promql.ValidateMaxPointsPerSeries(start, end, step, maxPointsPerTimeseriesRuntime.Get()); err != nil {
 		return fmt.Errorf("%w; (see -search.maxPointsPerTimeseries command-line flag)", err)
 	}
 	if mayCache {
@@ -898,7 +988,7 @@ func queryRangeHandler(qt *querytracer.Tracer, startTime time.Time, w http.Respo
 		Start:               start,
 		End:                 end,
 		Step:                step,
-		MaxPointsPerSeries:  *maxPointsPerTimeseries,
+		MaxPointsPerSeries:  maxPointsPerTimeseriesRuntime.Get(),
 		MaxSeries:           *maxUniqueTimeseries,
 		QuotedRemoteAddr:    httpserver.GetQuotedRemoteAddr(r),
 		Deadline:            deadline,
@@ -936,7 +1026,9 @@ func queryRangeHandler(qt *querytracer.Tracer, startTime time.Time, w http.Respo
 	qtDone := func() {
 		qt.Donef("start=%d, end=%d, step=%d, query=%q: series=%d", start, end, step, query, len(result))
 	}
-	WriteQueryRangeResponse(bw, result, qt, qtDone, qs)
+	seriesMap := httputils.GetBool(r, "series_map")
+	nameOnly := httputils.GetBool(r, "name_only")
+	WriteQueryRangeResponse(bw, result, qt, qtDone, qs, seriesMap, nameOnly)
 	if err := bw.Flush(); err != nil {
 		return fmt.Errorf("cannot send query range response to remote client: %w", err)
 	}
@@ -1,12 +1,18 @@
 package prometheus
 
 import (
+	"fmt"
 	"math"
 	"net/http"
+	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmselect/netstorage"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmstorage"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompb"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/storage"
 )
 
 func TestRemoveEmptyValuesAndTimeseries(t *testing.T) {
@@ -229,3 +235,183 @@ func TestGetLatencyOffsetMillisecondsFailure(t *testing.T) {
 	}
 	f("http://localhost?latency_offset=foobar")
 }
+
+func TestAdjustExportParamsForSinceTokenSuccess(t *testing.T) {
+	f := func(url string, start, end int64, hasNewSamplesExpected bool, startExpected, endExpected, nextTimestampExpected int64) {
+		t.Helper()
+		r, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatalf("unexpected error in NewRequest(%q): %s", url, err)
+		}
+		w := httptest.NewRecorder()
+		cp := &commonParams{
+			start:            start,
+			end:              end,
+			currentTimestamp: 100000,
+			filterss:         [][]storage.TagFilter{{}},
+		}
+		hasNewSamples, err := adjustExportParamsForSinceToken(w, r, cp)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if hasNewSamples != hasNewSamplesExpected {
+			t.Fatalf("unexpected hasNewSamples; got %v; want %v", hasNewSamples, hasNewSamplesExpected)
+		}
+		if cp.start != startExpected || cp.end != endExpected {
+			t.Fatalf("unexpected time range; got [%d, %d]; want [%d, %d]", cp.start, cp.end, startExpected, endExpected)
+		}
+		token := w.Header().Get("X-Next-Since-Token")
+		nextTimestamp, err := unmarshalExportSinceToken(token)
+		if err != nil {
+			t.Fatalf("cannot unmarshal token %q: %s", token, err)
+		}
+		if nextTimestamp != nextTimestampExpected {
+			t.Fatalf("unexpected timestamp in the next token; got %d; want %d", nextTimestamp, nextTimestampExpected)
+		}
+	}
+
+	// the first request
+	f("http://localhost?since_token=&latency_offset=10s", 0, 100000, true, 0, 90000, 90000)
+
+	// the next request
+	f("http://localhost?since_token="+marshalExportSinceToken(50000)+"&latency_offset=10s", 0, 100000, true, 50001, 90000, 90000)
+
+	// the start arg is bigger than the token
+	f("http://localhost?since_token="+marshalExportSinceToken(50000)+"&latency_offset=10s", 60000, 100000, true, 60000, 90000, 90000)
+
+	// the end arg is smaller than the current time minus latency offset
+	f("http://localhost?since_token="+marshalExportSinceToken(50000)+"&latency_offset=10s", 0, 70000, true, 50001, 70000, 70000)
+
+	// no new samples - the time range must remain unchanged
+	f("http://localhost?since_token="+marshalExportSinceToken(95000)+"&latency_offset=10s", 0, 100000, false, 0, 100000, 95000)
+}
+
+func TestAdjustExportParamsForSinceTokenFailure(t *testing.T) {
+	f := func(url string) {
+		t.Helper()
+		r, err := http.NewRequest(http.MethodGet, url, nil)
+		if err != nil {
+			t.Fatalf("unexpected error in NewRequest(%q): %s", url, err)
+		}
+		w := httptest.NewRecorder()
+		cp := &commonParams{
+			end:              100000,
+			currentTimestamp: 100000,
+		}
+		if _, err := adjustExportParamsForSinceToken(w, r, cp); err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+	f("http://localhost?since_token=foobar")
+	f("http://localhost?since_token=!!!")
+	f("http://localhost?since_token=&latency_offset=foobar")
+}
+
+func TestSeriesHandler(t *testing.T) {
+	vmstorage.Storage = storage.MustOpenStorage(t.TempDir(), 0, 0, 0)
+	defer func() {
+		vmstorage.Storage.MustClose()
+		vmstorage.Storage = nil
+	}()
+
+	// Register series in the reverse order, so their metricIDs do not match the sorted order.
+	now := time.Now()
+	var mrs []storage.MetricRow
+	for i := 9; i >= 0; i-- {
+		labels := []prompb.Label{
+			{
+				Name:  "__name__",
+				Value: "foo",
+			},
+			{
+				Name:  "instance",
+				Value: fmt.Sprintf("host-%d", i),
+			},
+		}
+		mrs = append(mrs, storage.MetricRow{
+			MetricNameRaw: storage.MarshalMetricNameRaw(nil, labels),
+			Timestamp:     now.UnixMilli(),
+			Value:         float64(i),
+		})
+	}
+	vmstorage.Storage.AddRows(mrs, 64)
+	vmstorage.Storage.DebugFlush()
+
+	f := func(args, responseExpected string) {
+		t.Helper()
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/series?match[]=foo&start=-1h&"+args, nil)
+		w := httptest.NewRecorder()
+		if err := SeriesHandler(nil, now, w, r); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if response := w.Body.String(); response != responseExpected {
+			t.Fatalf("unexpected response\ngot\n%s\nwant\n%s", response, responseExpected)
+		}
+	}
+
+	// all the series in sorted order
+	f("", `{"status":"success","data":[{"__name__":"foo","instance":"host-0"},{"__name__":"foo","instance":"host-1"},{"__name__":"foo","instance":"host-2"},`+
+		`{"__name__":"foo","instance":"host-3"},{"__name__":"foo","instance":"host-4"},{"__name__":"foo","instance":"host-5"},{"__name__":"foo","instance":"host-6"},`+
+		`{"__name__":"foo","instance":"host-7"},{"__name__":"foo","instance":"host-8"},{"__name__":"foo","instance":"host-9"}]}`)
+
+	// the first series in sorted order must be returned for the limit
+	f("limit=1", `{"status":"success","data":[{"__name__":"foo","instance":"host-0"}]}`)
+	f("limit=3", `{"status":"success","data":[{"__name__":"foo","instance":"host-0"},{"__name__":"foo","instance":"host-1"},{"__name__":"foo","instance":"host-2"}]}`)
+
+	// limit exceeding the number of series
+	f("limit=100", `{"status":"success","data":[{"__name__":"foo","instance":"host-0"},{"__name__":"foo","instance":"host-1"},{"__name__":"foo","instance":"host-2"},`+
+		`{"__name__":"foo","instance":"host-3"},{"__name__":"foo","instance":"host-4"},{"__name__":"foo","instance":"host-5"},{"__name__":"foo","instance":"host-6"},`+
+		`{"__name__":"foo","instance":"host-7"},{"__name__":"foo","instance":"host-8"},{"__name__":"foo","instance":"host-9"}]}`)
+
+	// no matching series
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/series?match[]=bar&start=-1h", nil)
+	w := httptest.NewRecorder()
+	if err := SeriesHandler(nil, now, w, r); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if response := w.Body.String(); response != `{"status":"success","data":[]}` {
+		t.Fatalf("unexpected response for missing series: %s", response)
+	}
+
+	// search errors must be returned before writing the response.
+	// Use distinct filters for every call in order to avoid hitting the cache for the found series.
+	fError := func(startTime time.Time, args string) {
+		t.Helper()
+		r := httptest.NewRequest(http.MethodGet, "/api/v1/series?start=-1h&"+args, nil)
+		w := httptest.NewRecorder()
+		if err := SeriesHandler(nil, startTime, w, r); err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+		if w.Body.Len() > 0 {
+			t.Fatalf("unexpected response written on error: %s", w.Body.String())
+		}
+	}
+	maxSeriesLimitOrig := *maxSeriesLimit
+	*maxSeriesLimit = 5
+	fError(now, "match[]={instance=~\"host-.%2B\"}")
+	fError(now, "match[]={instance=~\"host-[0-9]\"}&limit=1")
+	*maxSeriesLimit = maxSeriesLimitOrig
+
+	// timeout
+	fError(now.Add(-time.Minute), "match[]=foo&timeout=1s")
+}
+
+func TestWriteEmptyExportResponse(t *testing.T) {
+	f := func(format, contentTypeExpected, responseExpected string) {
+		t.Helper()
+		w := httptest.NewRecorder()
+		if err := writeEmptyExportResponse(w, format); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if contentType := w.Header().Get("Content-Type"); contentType != contentTypeExpected {
+			t.Fatalf("unexpected Content-Type; got %q; want %q", contentType, contentTypeExpected)
+		}
+		if response := w.Body.String(); response != responseExpected {
+			t.Fatalf("unexpected response; got %q; want %q", response, responseExpected)
+		}
+	}
+
+	f("", "application/stream+json; charset=utf-8", "")
+	f("prometheus", "text/plain; charset=utf-8", "")
+	f("promapi", "application/stream+json; charset=utf-8", `{"status":"success","data":{"resultType":"matrix","result":[]}}`)
+}
@@ -0,0 +1,130 @@
+package prometheus
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+	"github.com/VictoriaMetrics/metricsql"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmselect/promql"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bufferedwriter"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/exemplars"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/httputils"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/querytracer"
+)
+
+// QueryExemplarsHandler processes /api/v1/query_exemplars request.
+//
+// It returns exemplars for the time series matching series selectors from `query` arg.
+//
+// See https://prometheus.io/docs/prometheus/latest/querying/api/#querying-exemplars
+func QueryExemplarsHandler(qt *querytracer.Tracer, startTime time.Time, w http.ResponseWriter, r *http.Request) error {
+	defer queryExemplarsDuration.UpdateDuration(startTime)
+
+	ct := startTime.UnixNano() / 1e6
+	query := r.FormValue("query")
+	if len(query) == 0 {
+		return fmt.Errorf("missing `query` arg")
+	}
+	start, err := httputils.GetTime(r, "start", ct-defaultStep)
+	if err != nil {
+		return err
+	}
+	end, err := httputils.GetTime(r, "end", ct)
+	if err != nil {
+		return err
+	}
+	lfss, err := promql.GetLabelFilterss(query)
+	if err != nil {
+		return fmt.Errorf("cannot parse query %q: %w", query, err)
+	}
+	sm, err := newSeriesMatcher(lfss)
+	if err != nil {
+		return fmt.Errorf("cannot parse series selectors in query %q: %w", query, err)
+	}
+	ses := exemplars.Search(start, end, sm.match)
+
+	w.Header().Set("Content-Type", "application/json")
+	bw := bufferedwriter.Get(w)
+	defer bufferedwriter.Put(bw)
+	WriteQueryExemplarsResponse(bw, ses, qt)
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("cannot send query exemplars response to remote client: %w", err)
+	}
+	return nil
+}
+
+var queryExemplarsDuration = metrics.NewSummary(`vm_request_duration_seconds{path="/api/v1/query_exemplars"}`)
+
+// seriesMatcher matches series labels against series selectors.
+type seriesMatcher struct {
+	// lfss contains label filters per each series selector.
+	lfss [][]labelFilter
+}
+
+type labelFilter struct {
+	metricsql.LabelFilter
+
+	// re is set for regexp filters.
+	re *regexp.Regexp
+}
+
+func newSeriesMatcher(lfss [][]metricsql.LabelFilter) (*seriesMatcher, error) {
+	var sm seriesMatcher
+	for _, lfs := range lfss {
+		filters := make([]labelFilter, 0, len(lfs))
+		for _, lf := range lfs {
+			f := labelFilter{
+				LabelFilter: lf,
+			}
+			if lf.IsRegexp {
+				re, err := metricsql.CompileRegexpAnchored(lf.Value)
+				if err != nil {
+					return nil, fmt.Errorf("cannot parse regexp for %s: %w", lf.AppendString(nil), err)
+				}
+				f.re = re
+			}
+			filters = append(filters, f)
+		}
+		sm.lfss = append(sm.lfss, filters)
+	}
+	return &sm, nil
+}
+
+// match returns true if labels match at least a single series selector from sm.
+func (sm *seriesMatcher) match(labels []prompbmarshal.Label) bool {
+	for _, lfs := range sm.lfss {
+		if matchLabelFilters(labels, lfs) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchLabelFilters(labels []prompbmarshal.Label, lfs []labelFilter) bool {
+	for i := range lfs {
+		lf := &lfs[i]
+		// Missing label is equivalent to the label with empty value.
+		value := ""
+		for _, label := range labels {
+			if label.Name == lf.Label {
+				value = label.Value
+				break
+			}
+		}
+		var ok bool
+		if lf.re != nil {
+			ok = lf.re.MatchString(value)
+		} else {
+			ok = value == lf.Value
+		}
+		if ok == lf.IsNegative {
+			return false
+		}
+	}
+	return true
+}
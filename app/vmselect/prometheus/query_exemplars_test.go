@@ -0,0 +1,54 @@
+package prometheus
+
+import (
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmselect/promql"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+)
+
+func TestSeriesMatcher(t *testing.T) {
+	f := func(query string, labels []prompbmarshal.Label, resultExpected bool) {
+		t.Helper()
+
+		lfss, err := promql.GetLabelFilterss(query)
+		if err != nil {
+			t.Fatalf("cannot obtain label filters from %q: %s", query, err)
+		}
+		sm, err := newSeriesMatcher(lfss)
+		if err != nil {
+			t.Fatalf("cannot create series matcher for %q: %s", query, err)
+		}
+		result := sm.match(labels)
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %q; got %v; want %v", query, result, resultExpected)
+		}
+	}
+
+	labels := []prompbmarshal.Label{
+		{
+			Name:  "__name__",
+			Value: "http_requests_total",
+		},
+		{
+			Name:  "job",
+			Value: "api",
+		},
+	}
+
+	f(`http_requests_total`, labels, true)
+	f(`http_requests_total{job="api"}`, labels, true)
+	f(`http_requests_total{job=~"ap.+"}`, labels, true)
+	f(`http_requests_total{job!="foo"}`, labels, true)
+	f(`rate(http_requests_total[5m])`, labels, true)
+	f(`foo or http_requests_total{job="api"}`, labels, true)
+	f(`{job=~"api|web",instance=""}`, labels, true)
+	f(`{job="api" or job="web",instance="foo"}`, labels, true)
+
+	f(`foo`, labels, false)
+	f(`http_requests_total{job="web"}`, labels, false)
+	f(`http_requests_total{job=~"a"}`, labels, false)
+	f(`http_requests_total{job!~"api"}`, labels, false)
+	f(`http_requests_total{instance!=""}`, labels, false)
+	f(`{job="web" or job="api",instance="foo"}`, labels, false)
+}
@@ -9,146 +9,228 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmselect/netstorage"
 	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmselect/promql"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/querytracer"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/storage"
 )
 
-// QueryRangeResponse generates response for /api/v1/query_range.See https://prometheus.io/docs/prometheus/latest/querying/api/#range-queries
+// QueryRangeResponse generates response for /api/v1/query_range.See https://prometheus.io/docs/prometheus/latest/querying/api/#range-queriesIf seriesMap is set, then labels for every series are returned once in "series" list, while "result" items refer to them via "id".If nameOnly is set, then only metric names are returned instead of the full label sets.
 
-//line app/vmselect/prometheus/query_range_response.qtpl:10
+//line app/vmselect/prometheus/query_range_response.qtpl:14
 import (
 	qtio422016 "io"
 
 	qt422016 "github.com/valyala/quicktemplate"
 )
 
-//line app/vmselect/prometheus/query_range_response.qtpl:10
+//line app/vmselect/prometheus/query_range_response.qtpl:14
 var (
 	_ = qtio422016.Copy
 	_ = qt422016.AcquireByteBuffer
 )
 
-//line app/vmselect/prometheus/query_range_response.qtpl:10
-func StreamQueryRangeResponse(qw422016 *qt422016.Writer, rs []netstorage.Result, qt *querytracer.Tracer, qtDone func(), qs *promql.QueryStats) {
-//line app/vmselect/prometheus/query_range_response.qtpl:10
+//line app/vmselect/prometheus/query_range_response.qtpl:14
+func StreamQueryRangeResponse(qw422016 *qt422016.Writer, rs []netstorage.Result, qt *querytracer.Tracer, qtDone func(), qs *promql.QueryStats, seriesMap, nameOnly bool) {
+//line app/vmselect/prometheus/query_range_response.qtpl:14
 	qw422016.N().S(`{`)
-//line app/vmselect/prometheus/query_range_response.qtpl:13
+//line app/vmselect/prometheus/query_range_response.qtpl:17
 	seriesCount := len(rs)
 	pointsCount := 0
 
-//line app/vmselect/prometheus/query_range_response.qtpl:15
-	qw422016.N().S(`"status":"success","data":{"resultType":"matrix","result":[`)
-//line app/vmselect/prometheus/query_range_response.qtpl:20
-	if len(rs) > 0 {
-//line app/vmselect/prometheus/query_range_response.qtpl:21
-		streamqueryRangeLine(qw422016, &rs[0])
-//line app/vmselect/prometheus/query_range_response.qtpl:22
-		pointsCount += len(rs[0].Values)
-
+//line app/vmselect/prometheus/query_range_response.qtpl:19
+	qw422016.N().S(`"status":"success","data":{"resultType":"matrix",`)
 //line app/vmselect/prometheus/query_range_response.qtpl:23
-		rs = rs[1:]
-
-//line app/vmselect/prometheus/query_range_response.qtpl:24
-		for i := range rs {
-//line app/vmselect/prometheus/query_range_response.qtpl:24
-			qw422016.N().S(`,`)
+	if seriesMap {
+//line app/vmselect/prometheus/query_range_response.qtpl:23
+		qw422016.N().S(`"series":[`)
 //line app/vmselect/prometheus/query_range_response.qtpl:25
-			streamqueryRangeLine(qw422016, &rs[i])
+		for i := range rs {
 //line app/vmselect/prometheus/query_range_response.qtpl:26
-			pointsCount += len(rs[i].Values)
-
+			streamqueryRangeMetricName(qw422016, &rs[i].MetricName, nameOnly)
+//line app/vmselect/prometheus/query_range_response.qtpl:27
+			if i+1 < len(rs) {
 //line app/vmselect/prometheus/query_range_response.qtpl:27
+				qw422016.N().S(`,`)
+//line app/vmselect/prometheus/query_range_response.qtpl:27
+			}
+//line app/vmselect/prometheus/query_range_response.qtpl:28
 		}
 //line app/vmselect/prometheus/query_range_response.qtpl:28
+		qw422016.N().S(`],`)
+//line app/vmselect/prometheus/query_range_response.qtpl:30
 	}
-//line app/vmselect/prometheus/query_range_response.qtpl:28
-	qw422016.N().S(`]},"stats":{`)
+//line app/vmselect/prometheus/query_range_response.qtpl:30
+	qw422016.N().S(`"result":[`)
+//line app/vmselect/prometheus/query_range_response.qtpl:32
+	for i := range rs {
 //line app/vmselect/prometheus/query_range_response.qtpl:33
+		streamqueryRangeLine(qw422016, &rs[i], i, seriesMap, nameOnly)
+//line app/vmselect/prometheus/query_range_response.qtpl:34
+		if i+1 < len(rs) {
+//line app/vmselect/prometheus/query_range_response.qtpl:34
+			qw422016.N().S(`,`)
+//line app/vmselect/prometheus/query_range_response.qtpl:34
+		}
+//line app/vmselect/prometheus/query_range_response.qtpl:35
+		pointsCount += len(rs[i].Values)
+
+//line app/vmselect/prometheus/query_range_response.qtpl:36
+	}
+//line app/vmselect/prometheus/query_range_response.qtpl:36
+	qw422016.N().S(`]},"stats":{`)
+//line app/vmselect/prometheus/query_range_response.qtpl:41
 	// seriesFetched is string instead of int because of historical reasons.
 	// It cannot be converted to int without breaking backwards compatibility at vmalert :(
 
-//line app/vmselect/prometheus/query_range_response.qtpl:35
+//line app/vmselect/prometheus/query_range_response.qtpl:43
 	qw422016.N().S(`"seriesFetched": "`)
-//line app/vmselect/prometheus/query_range_response.qtpl:36
+//line app/vmselect/prometheus/query_range_response.qtpl:44
 	qw422016.N().DL(qs.SeriesFetched.Load())
-//line app/vmselect/prometheus/query_range_response.qtpl:36
+//line app/vmselect/prometheus/query_range_response.qtpl:44
 	qw422016.N().S(`","executionTimeMsec":`)
-//line app/vmselect/prometheus/query_range_response.qtpl:37
+//line app/vmselect/prometheus/query_range_response.qtpl:45
 	qw422016.N().DL(qs.ExecutionTimeMsec.Load())
-//line app/vmselect/prometheus/query_range_response.qtpl:37
+//line app/vmselect/prometheus/query_range_response.qtpl:45
 	qw422016.N().S(`}`)
-//line app/vmselect/prometheus/query_range_response.qtpl:40
+//line app/vmselect/prometheus/query_range_response.qtpl:48
 	qt.Printf("generate /api/v1/query_range response for series=%d, points=%d", seriesCount, pointsCount)
 	qtDone()
 
-//line app/vmselect/prometheus/query_range_response.qtpl:43
+//line app/vmselect/prometheus/query_range_response.qtpl:51
 	streamdumpQueryTrace(qw422016, qt)
-//line app/vmselect/prometheus/query_range_response.qtpl:43
+//line app/vmselect/prometheus/query_range_response.qtpl:51
 	qw422016.N().S(`}`)
-//line app/vmselect/prometheus/query_range_response.qtpl:45
+//line app/vmselect/prometheus/query_range_response.qtpl:53
 }
 
-//line app/vmselect/prometheus/query_range_response.qtpl:45
-func WriteQueryRangeResponse(qq422016 qtio422016.Writer, rs []netstorage.Result, qt *querytracer.Tracer, qtDone func(), qs *promql.QueryStats) {
-//line app/vmselect/prometheus/query_range_response.qtpl:45
+//line app/vmselect/prometheus/query_range_response.qtpl:53
+func WriteQueryRangeResponse(qq422016 qtio422016.Writer, rs []netstorage.Result, qt *querytracer.Tracer, qtDone func(), qs *promql.QueryStats, seriesMap, nameOnly bool) {
+//line app/vmselect/prometheus/query_range_response.qtpl:53
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line app/vmselect/prometheus/query_range_response.qtpl:45
-	StreamQueryRangeResponse(qw422016, rs, qt, qtDone, qs)
-//line app/vmselect/prometheus/query_range_response.qtpl:45
+//line app/vmselect/prometheus/query_range_response.qtpl:53
+	StreamQueryRangeResponse(qw422016, rs, qt, qtDone, qs, seriesMap, nameOnly)
+//line app/vmselect/prometheus/query_range_response.qtpl:53
 	qt422016.ReleaseWriter(qw422016)
-//line app/vmselect/prometheus/query_range_response.qtpl:45
+//line app/vmselect/prometheus/query_range_response.qtpl:53
 }
 
-//line app/vmselect/prometheus/query_range_response.qtpl:45
-func QueryRangeResponse(rs []netstorage.Result, qt *querytracer.Tracer, qtDone func(), qs *promql.QueryStats) string {
-//line app/vmselect/prometheus/query_range_response.qtpl:45
+//line app/vmselect/prometheus/query_range_response.qtpl:53
+func QueryRangeResponse(rs []netstorage.Result, qt *querytracer.Tracer, qtDone func(), qs *promql.QueryStats, seriesMap, nameOnly bool) string {
+//line app/vmselect/prometheus/query_range_response.qtpl:53
 	qb422016 := qt422016.AcquireByteBuffer()
-//line app/vmselect/prometheus/query_range_response.qtpl:45
-	WriteQueryRangeResponse(qb422016, rs, qt, qtDone, qs)
-//line app/vmselect/prometheus/query_range_response.qtpl:45
+//line app/vmselect/prometheus/query_range_response.qtpl:53
+	WriteQueryRangeResponse(qb422016, rs, qt, qtDone, qs, seriesMap, nameOnly)
+//line app/vmselect/prometheus/query_range_response.qtpl:53
 	qs422016 := string(qb422016.B)
-//line app/vmselect/prometheus/query_range_response.qtpl:45
+//line app/vmselect/prometheus/query_range_response.qtpl:53
 	qt422016.ReleaseByteBuffer(qb422016)
-//line app/vmselect/prometheus/query_range_response.qtpl:45
+//line app/vmselect/prometheus/query_range_response.qtpl:53
 	return qs422016
-//line app/vmselect/prometheus/query_range_response.qtpl:45
+//line app/vmselect/prometheus/query_range_response.qtpl:53
 }
 
-//line app/vmselect/prometheus/query_range_response.qtpl:47
-func streamqueryRangeLine(qw422016 *qt422016.Writer, r *netstorage.Result) {
-//line app/vmselect/prometheus/query_range_response.qtpl:47
-	qw422016.N().S(`{"metric":`)
-//line app/vmselect/prometheus/query_range_response.qtpl:49
-	streammetricNameObject(qw422016, &r.MetricName)
-//line app/vmselect/prometheus/query_range_response.qtpl:49
-	qw422016.N().S(`,"values":`)
-//line app/vmselect/prometheus/query_range_response.qtpl:50
+//line app/vmselect/prometheus/query_range_response.qtpl:55
+func streamqueryRangeLine(qw422016 *qt422016.Writer, r *netstorage.Result, id int, seriesMap, nameOnly bool) {
+//line app/vmselect/prometheus/query_range_response.qtpl:55
+	qw422016.N().S(`{`)
+//line app/vmselect/prometheus/query_range_response.qtpl:57
+	if seriesMap {
+//line app/vmselect/prometheus/query_range_response.qtpl:57
+		qw422016.N().S(`"id":`)
+//line app/vmselect/prometheus/query_range_response.qtpl:58
+		qw422016.N().D(id)
+//line app/vmselect/prometheus/query_range_response.qtpl:58
+		qw422016.N().S(`,`)
+//line app/vmselect/prometheus/query_range_response.qtpl:59
+	} else {
+//line app/vmselect/prometheus/query_range_response.qtpl:59
+		qw422016.N().S(`"metric":`)
+//line app/vmselect/prometheus/query_range_response.qtpl:60
+		streamqueryRangeMetricName(qw422016, &r.MetricName, nameOnly)
+//line app/vmselect/prometheus/query_range_response.qtpl:60
+		qw422016.N().S(`,`)
+//line app/vmselect/prometheus/query_range_response.qtpl:61
+	}
+//line app/vmselect/prometheus/query_range_response.qtpl:61
+	qw422016.N().S(`"values":`)
+//line app/vmselect/prometheus/query_range_response.qtpl:62
 	streamvaluesWithTimestamps(qw422016, r.Values, r.Timestamps)
-//line app/vmselect/prometheus/query_range_response.qtpl:50
+//line app/vmselect/prometheus/query_range_response.qtpl:62
 	qw422016.N().S(`}`)
-//line app/vmselect/prometheus/query_range_response.qtpl:52
+//line app/vmselect/prometheus/query_range_response.qtpl:64
+}
+
+//line app/vmselect/prometheus/query_range_response.qtpl:64
+func writequeryRangeLine(qq422016 qtio422016.Writer, r *netstorage.Result, id int, seriesMap, nameOnly bool) {
+//line app/vmselect/prometheus/query_range_response.qtpl:64
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line app/vmselect/prometheus/query_range_response.qtpl:64
+	streamqueryRangeLine(qw422016, r, id, seriesMap, nameOnly)
+//line app/vmselect/prometheus/query_range_response.qtpl:64
+	qt422016.ReleaseWriter(qw422016)
+//line app/vmselect/prometheus/query_range_response.qtpl:64
+}
+
+//line app/vmselect/prometheus/query_range_response.qtpl:64
+func queryRangeLine(r *netstorage.Result, id int, seriesMap, nameOnly bool) string {
+//line app/vmselect/prometheus/query_range_response.qtpl:64
+	qb422016 := qt422016.AcquireByteBuffer()
+//line app/vmselect/prometheus/query_range_response.qtpl:64
+	writequeryRangeLine(qb422016, r, id, seriesMap, nameOnly)
+//line app/vmselect/prometheus/query_range_response.qtpl:64
+	qs422016 := string(qb422016.B)
+//line app/vmselect/prometheus/query_range_response.qtpl:64
+	qt422016.ReleaseByteBuffer(qb422016)
+//line app/vmselect/prometheus/query_range_response.qtpl:64
+	return qs422016
+//line app/vmselect/prometheus/query_range_response.qtpl:64
+}
+
+//line app/vmselect/prometheus/query_range_response.qtpl:66
+func streamqueryRangeMetricName(qw422016 *qt422016.Writer, mn *storage.MetricName, nameOnly bool) {
+//line app/vmselect/prometheus/query_range_response.qtpl:67
+	if nameOnly {
+//line app/vmselect/prometheus/query_range_response.qtpl:67
+		qw422016.N().S(`{`)
+//line app/vmselect/prometheus/query_range_response.qtpl:69
+		if len(mn.MetricGroup) > 0 {
+//line app/vmselect/prometheus/query_range_response.qtpl:69
+			qw422016.N().S(`"__name__":`)
+//line app/vmselect/prometheus/query_range_response.qtpl:70
+			qw422016.N().QZ(mn.MetricGroup)
+//line app/vmselect/prometheus/query_range_response.qtpl:71
+		}
+//line app/vmselect/prometheus/query_range_response.qtpl:71
+		qw422016.N().S(`}`)
+//line app/vmselect/prometheus/query_range_response.qtpl:73
+	} else {
+//line app/vmselect/prometheus/query_range_response.qtpl:74
+		streammetricNameObject(qw422016, mn)
+//line app/vmselect/prometheus/query_range_response.qtpl:75
+	}
+//line app/vmselect/prometheus/query_range_response.qtpl:76
 }
 
-//line app/vmselect/prometheus/query_range_response.qtpl:52
-func writequeryRangeLine(qq422016 qtio422016.Writer, r *netstorage.Result) {
-//line app/vmselect/prometheus/query_range_response.qtpl:52
+//line app/vmselect/prometheus/query_range_response.qtpl:76
+func writequeryRangeMetricName(qq422016 qtio422016.Writer, mn *storage.MetricName, nameOnly bool) {
+//line app/vmselect/prometheus/query_range_response.qtpl:76
 	qw422016 := qt422016.AcquireWriter(qq422016)
-//line app/vmselect/prometheus/query_range_response.qtpl:52
-	streamqueryRangeLine(qw422016, r)
-//line app/vmselect/prometheus/query_range_response.qtpl:52
+//line app/vmselect/prometheus/query_range_response.qtpl:76
+	streamqueryRangeMetricName(qw422016, mn, nameOnly)
+//line app/vmselect/prometheus/query_range_response.qtpl:76
 	qt422016.ReleaseWriter(qw422016)
-//line app/vmselect/prometheus/query_range_response.qtpl:52
+//line app/vmselect/prometheus/query_range_response.qtpl:76
 }
 
-//line app/vmselect/prometheus/query_range_response.qtpl:52
-func queryRangeLine(r *netstorage.Result) string {
-//line app/vmselect/prometheus/query_range_response.qtpl:52
+//line app/vmselect/prometheus/query_range_response.qtpl:76
+func queryRangeMetricName(mn *storage.MetricName, nameOnly bool) string {
+//line app/vmselect/prometheus/query_range_response.qtpl:76
 	qb422016 := qt422016.AcquireByteBuffer()
-//line app/vmselect/prometheus/query_range_response.qtpl:52
-	writequeryRangeLine(qb422016, r)
-//line app/vmselect/prometheus/query_range_response.qtpl:52
+//line app/vmselect/prometheus/query_range_response.qtpl:76
+	writequeryRangeMetricName(qb422016, mn, nameOnly)
+//line app/vmselect/prometheus/query_range_response.qtpl:76
 	qs422016 := string(qb422016.B)
-//line app/vmselect/prometheus/query_range_response.qtpl:52
+//line app/vmselect/prometheus/query_range_response.qtpl:76
 	qt422016.ReleaseByteBuffer(qb422016)
-//line app/vmselect/prometheus/query_range_response.qtpl:52
+//line app/vmselect/prometheus/query_range_response.qtpl:76
 	return qs422016
-//line app/vmselect/prometheus/query_range_response.qtpl:52
+//line app/vmselect/prometheus/query_range_response.qtpl:76
 }
@@ -0,0 +1,59 @@
+package prometheus
+
+import (
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmselect/netstorage"
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmselect/promql"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/storage"
+)
+
+func TestQueryRangeResponse(t *testing.T) {
+	rs := []netstorage.Result{
+		{
+			MetricName: storage.MetricName{
+				MetricGroup: []byte("foo"),
+				Tags: []storage.Tag{{
+					Key:   []byte("job"),
+					Value: []byte("a"),
+				}},
+			},
+			Values:     []float64{1, 2},
+			Timestamps: []int64{1000, 2000},
+		},
+		{
+			MetricName: storage.MetricName{
+				Tags: []storage.Tag{{
+					Key:   []byte("job"),
+					Value: []byte("b"),
+				}},
+			},
+			Values:     []float64{3},
+			Timestamps: []int64{1000},
+		},
+	}
+	f := func(seriesMap, nameOnly bool, resultExpected string) {
+		t.Helper()
+		qs := &promql.QueryStats{}
+		result := QueryRangeResponse(rs, nil, func() {}, qs, seriesMap, nameOnly)
+		if result != resultExpected {
+			t.Fatalf("unexpected response\ngot\n%s\nwant\n%s", result, resultExpected)
+		}
+	}
+	stats := `"stats":{"seriesFetched": "0","executionTimeMsec":0}}`
+
+	f(false, false, `{"status":"success","data":{"resultType":"matrix","result":[`+
+		`{"metric":{"__name__":"foo","job":"a"},"values":[[1,"1"],[2,"2"]]},`+
+		`{"metric":{"job":"b"},"values":[[1,"3"]]}]},`+stats)
+	f(false, true, `{"status":"success","data":{"resultType":"matrix","result":[`+
+		`{"metric":{"__name__":"foo"},"values":[[1,"1"],[2,"2"]]},`+
+		`{"metric":{},"values":[[1,"3"]]}]},`+stats)
+	f(true, false, `{"status":"success","data":{"resultType":"matrix",`+
+		`"series":[{"__name__":"foo","job":"a"},{"job":"b"}],"result":[`+
+		`{"id":0,"values":[[1,"1"],[2,"2"]]},`+
+		`{"id":1,"values":[[1,"3"]]}]},`+stats)
+	f(true, true, `{"status":"success","data":{"resultType":"matrix",`+
+		`"series":[{"__name__":"foo"},{}],"result":[`+
+		`{"id":0,"values":[[1,"1"],[2,"2"]]},`+
+		`{"id":1,"values":[[1,"3"]]}]},`+stats)
+}
@@ -0,0 +1,62 @@
+package prometheus
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmselect/netstorage"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bufferedwriter"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/httputils"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/querytracer"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/storage"
+)
+
+// ScrapeIntervalsHandler processes /api/v1/status/scrape_intervals request.
+//
+// It returns the observed intervals between the ingested samples for time series matching `match[]` args.
+//
+// See https://docs.victoriametrics.com/#scrape-intervals-tracking
+func ScrapeIntervalsHandler(qt *querytracer.Tracer, startTime time.Time, w http.ResponseWriter, r *http.Request) error {
+	defer scrapeIntervalsDuration.UpdateDuration(startTime)
+
+	cp, err := getCommonParamsForLabelsAPI(r, startTime, true)
+	if err != nil {
+		return err
+	}
+	limit, err := httputils.GetInt(r, "limit")
+	if err != nil {
+		return err
+	}
+
+	sq := storage.NewSearchQuery(cp.start, cp.end, cp.filterss, *maxSeriesLimit)
+	w.Header().Set("Content-Type", "application/json")
+	bw := bufferedwriter.Get(w)
+	defer bufferedwriter.Put(bw)
+
+	WriteScrapeIntervalsResponseHeader(bw)
+	seriesCount := 0
+	err = netstorage.ForEachScrapeInterval(qt, sq, limit, cp.deadline, func(mn *storage.MetricName, si *storage.ScrapeInterval) error {
+		if err := bw.Error(); err != nil {
+			return err
+		}
+		if seriesCount > 0 {
+			_, _ = bw.Write([]byte(","))
+		}
+		WriteScrapeIntervalsResponseLine(bw, mn, si)
+		seriesCount++
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("cannot fetch scrape intervals for %q: %w", sq, err)
+	}
+	qtDone := func() {
+		qt.Donef("start=%d, end=%d", cp.start, cp.end)
+	}
+	WriteScrapeIntervalsResponseFooter(bw, seriesCount, qt, qtDone)
+	return bw.Flush()
+}
+
+var scrapeIntervalsDuration = metrics.NewSummary(`vm_request_duration_seconds{path="/api/v1/status/scrape_intervals"}`)
@@ -0,0 +1,79 @@
+package prometheus
+
+import (
+	"flag"
+	"net/http"
+	"runtime"
+	"strings"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/buildinfo"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/exemplars"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
+)
+
+// prometheusCompatibleVersion is the Prometheus version returned from /api/v1/status/buildinfo.
+//
+// Grafana uses this version for determining the API to use when retrieving label values.
+// As new Grafana features are added that are customized for the Prometheus version, maybe the version will need to be increased.
+// See https://github.com/VictoriaMetrics/VictoriaMetrics/issues/5370
+const prometheusCompatibleVersion = "2.24.0"
+
+// BuildInfoHandler processes /api/v1/status/buildinfo request.
+//
+// See https://prometheus.io/docs/prometheus/latest/querying/api/#build-information
+func BuildInfoHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	WriteBuildInfoResponse(w, prometheusCompatibleVersion, buildinfo.Version, runtime.Version())
+}
+
+// StatusFlagsHandler processes /api/v1/status/flags request.
+//
+// It returns all the command-line flags with their values in the format compatible with Prometheus.
+// Values for flags with secrets are hidden. Additionally, `enable-feature` entry contains the list of Prometheus features
+// supported by VictoriaMetrics, so Prometheus-compatible clients could detect them.
+//
+// See https://prometheus.io/docs/prometheus/latest/querying/api/#flags
+func StatusFlagsHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	WriteStatusFlagsResponse(w, getStatusFlags())
+}
+
+type statusFlag struct {
+	name  string
+	value string
+}
+
+func getStatusFlags() []statusFlag {
+	flags := []statusFlag{
+		{
+			name:  "enable-feature",
+			value: strings.Join(getPrometheusFeatures(), ","),
+		},
+	}
+	// flag.VisitAll visits flags in lexicographical order.
+	flag.VisitAll(func(f *flag.Flag) {
+		value := flagutil.GetFlagValue(f)
+		if flagutil.IsSecretFlag(strings.ToLower(f.Name)) {
+			value = "secret"
+		}
+		flags = append(flags, statusFlag{
+			name:  f.Name,
+			value: value,
+		})
+	})
+	return flags
+}
+
+// getPrometheusFeatures returns the names of Prometheus feature flags, which are supported by VictoriaMetrics.
+//
+// See https://prometheus.io/docs/prometheus/latest/feature_flags/
+func getPrometheusFeatures() []string {
+	features := []string{
+		"promql-at-modifier",
+		"promql-negative-offset",
+	}
+	if exemplars.Enabled() {
+		features = append(features, "exemplar-storage")
+	}
+	return features
+}
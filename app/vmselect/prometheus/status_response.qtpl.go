@@ -0,0 +1,118 @@
+// Code generated by qtc from "status_response.qtpl". DO NOT EDIT.
+// See https://github.com/valyala/quicktemplate for details.
+
+// BuildInfoResponse generates response for /api/v1/status/buildinfo .See https://prometheus.io/docs/prometheus/latest/querying/api/#build-information
+
+//line app/vmselect/prometheus/status_response.qtpl:5
+package prometheus
+
+//line app/vmselect/prometheus/status_response.qtpl:5
+import (
+	qtio422016 "io"
+
+	qt422016 "github.com/valyala/quicktemplate"
+)
+
+//line app/vmselect/prometheus/status_response.qtpl:5
+var (
+	_ = qtio422016.Copy
+	_ = qt422016.AcquireByteBuffer
+)
+
+//line app/vmselect/prometheus/status_response.qtpl:5
+func StreamBuildInfoResponse(qw422016 *qt422016.Writer, version, revision, goVersion string) {
+//line app/vmselect/prometheus/status_response.qtpl:5
+	qw422016.N().S(`{"status":"success","data":{"version":`)
+//line app/vmselect/prometheus/status_response.qtpl:9
+	qw422016.N().Q(version)
+//line app/vmselect/prometheus/status_response.qtpl:9
+	qw422016.N().S(`,"revision":`)
+//line app/vmselect/prometheus/status_response.qtpl:10
+	qw422016.N().Q(revision)
+//line app/vmselect/prometheus/status_response.qtpl:10
+	qw422016.N().S(`,"branch":"","buildUser":"","buildDate":"","goVersion":`)
+//line app/vmselect/prometheus/status_response.qtpl:14
+	qw422016.N().Q(goVersion)
+//line app/vmselect/prometheus/status_response.qtpl:14
+	qw422016.N().S(`}}`)
+//line app/vmselect/prometheus/status_response.qtpl:17
+}
+
+//line app/vmselect/prometheus/status_response.qtpl:17
+func WriteBuildInfoResponse(qq422016 qtio422016.Writer, version, revision, goVersion string) {
+//line app/vmselect/prometheus/status_response.qtpl:17
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line app/vmselect/prometheus/status_response.qtpl:17
+	StreamBuildInfoResponse(qw422016, version, revision, goVersion)
+//line app/vmselect/prometheus/status_response.qtpl:17
+	qt422016.ReleaseWriter(qw422016)
+//line app/vmselect/prometheus/status_response.qtpl:17
+}
+
+//line app/vmselect/prometheus/status_response.qtpl:17
+func BuildInfoResponse(version, revision, goVersion string) string {
+//line app/vmselect/prometheus/status_response.qtpl:17
+	qb422016 := qt422016.AcquireByteBuffer()
+//line app/vmselect/prometheus/status_response.qtpl:17
+	WriteBuildInfoResponse(qb422016, version, revision, goVersion)
+//line app/vmselect/prometheus/status_response.qtpl:17
+	qs422016 := string(qb422016.B)
+//line app/vmselect/prometheus/status_response.qtpl:17
+	qt422016.ReleaseByteBuffer(qb422016)
+//line app/vmselect/prometheus/status_response.qtpl:17
+	return qs422016
+//line app/vmselect/prometheus/status_response.qtpl:17
+}
+
+// StatusFlagsResponse generates response for /api/v1/status/flags .See https://prometheus.io/docs/prometheus/latest/querying/api/#flags
+
+//line app/vmselect/prometheus/status_response.qtpl:21
+func StreamStatusFlagsResponse(qw422016 *qt422016.Writer, flags []statusFlag) {
+//line app/vmselect/prometheus/status_response.qtpl:21
+	qw422016.N().S(`{"status":"success","data":{`)
+//line app/vmselect/prometheus/status_response.qtpl:25
+	for i, f := range flags {
+//line app/vmselect/prometheus/status_response.qtpl:26
+		qw422016.N().Q(f.name)
+//line app/vmselect/prometheus/status_response.qtpl:26
+		qw422016.N().S(`:`)
+//line app/vmselect/prometheus/status_response.qtpl:26
+		qw422016.N().Q(f.value)
+//line app/vmselect/prometheus/status_response.qtpl:27
+		if i+1 < len(flags) {
+//line app/vmselect/prometheus/status_response.qtpl:27
+			qw422016.N().S(`,`)
+//line app/vmselect/prometheus/status_response.qtpl:27
+		}
+//line app/vmselect/prometheus/status_response.qtpl:28
+	}
+//line app/vmselect/prometheus/status_response.qtpl:28
+	qw422016.N().S(`}}`)
+//line app/vmselect/prometheus/status_response.qtpl:31
+}
+
+//line app/vmselect/prometheus/status_response.qtpl:31
+func WriteStatusFlagsResponse(qq422016 qtio422016.Writer, flags []statusFlag) {
+//line app/vmselect/prometheus/status_response.qtpl:31
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line app/vmselect/prometheus/status_response.qtpl:31
+	StreamStatusFlagsResponse(qw422016, flags)
+//line app/vmselect/prometheus/status_response.qtpl:31
+	qt422016.ReleaseWriter(qw422016)
+//line app/vmselect/prometheus/status_response.qtpl:31
+}
+
+//line app/vmselect/prometheus/status_response.qtpl:31
+func StatusFlagsResponse(flags []statusFlag) string {
+//line app/vmselect/prometheus/status_response.qtpl:31
+	qb422016 := qt422016.AcquireByteBuffer()
+//line app/vmselect/prometheus/status_response.qtpl:31
+	WriteStatusFlagsResponse(qb422016, flags)
+//line app/vmselect/prometheus/status_response.qtpl:31
+	qs422016 := string(qb422016.B)
+//line app/vmselect/prometheus/status_response.qtpl:31
+	qt422016.ReleaseByteBuffer(qb422016)
+//line app/vmselect/prometheus/status_response.qtpl:31
+	return qs422016
+//line app/vmselect/prometheus/status_response.qtpl:31
+}
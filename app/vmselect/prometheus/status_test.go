@@ -0,0 +1,48 @@
+package prometheus
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestStatusFlagsHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	StatusFlagsHandler(w, httptest.NewRequest("GET", "/api/v1/status/flags", nil))
+
+	var resp struct {
+		Status string            `json:"status"`
+		Data   map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("cannot parse response %q: %s", w.Body.String(), err)
+	}
+	if resp.Status != "success" {
+		t.Fatalf("unexpected status; got %q; want %q", resp.Status, "success")
+	}
+	if v := resp.Data["enable-feature"]; v != "promql-at-modifier,promql-negative-offset" {
+		t.Fatalf("unexpected enable-feature value; got %q", v)
+	}
+	if v, ok := resp.Data["search.maxSeries"]; !ok || v != "30000" {
+		t.Fatalf("unexpected search.maxSeries value; got %q", v)
+	}
+}
+
+func TestBuildInfoHandler(t *testing.T) {
+	w := httptest.NewRecorder()
+	BuildInfoHandler(w, httptest.NewRequest("GET", "/api/v1/status/buildinfo", nil))
+
+	var resp struct {
+		Status string            `json:"status"`
+		Data   map[string]string `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("cannot parse response %q: %s", w.Body.String(), err)
+	}
+	if resp.Status != "success" {
+		t.Fatalf("unexpected status; got %q; want %q", resp.Status, "success")
+	}
+	if v := resp.Data["version"]; v != prometheusCompatibleVersion {
+		t.Fatalf("unexpected version; got %q; want %q", v, prometheusCompatibleVersion)
+	}
+}
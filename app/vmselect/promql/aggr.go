@@ -1124,6 +1124,12 @@ func aggrFuncLimitK(afa *aggrFuncArg) ([]*timeseries, error) {
 		limit = 0
 	}
 	afe := func(tss []*timeseries, _ *metricsql.ModifierExpr) []*timeseries {
+		// Drop series without values on the selected time range before applying the limit.
+		// Such series are removed from the response anyway, so they mustn't occupy the limited slots.
+		// Otherwise the number of returned series may vary between consecutive calls
+		// depending on whether the series with the smallest hashes have values on the selected time range.
+		tss = removeEmptySeries(tss)
+
 		// Sort series by metricName hash in order to get consistent set of output series
 		// across multiple calls to limitk() function.
 		// Sort series by hash in order to guarantee uniform selection across series.
@@ -1154,7 +1160,12 @@ func aggrFuncLimitK(afa *aggrFuncArg) ([]*timeseries, error) {
 	return aggrFuncExt(afe, args[1], &afa.ae.Modifier, afa.ae.Limit, true)
 }
 
+// getHash returns hash for mn.
+//
+// The hash doesn't depend on the order of mn tags, so it remains the same for the same series across calls
+// even if the tags were re-ordered by the preceding label manipulation functions.
 func getHash(d *xxhash.Digest, mn *storage.MetricName) uint64 {
+	sortMetricTags(mn)
 	d.Reset()
 	_, _ = d.Write(mn.MetricGroup)
 	for _, tag := range mn.Tags {
@@ -1162,7 +1173,6 @@ func getHash(d *xxhash.Digest, mn *storage.MetricName) uint64 {
 		_, _ = d.Write(tag.Value)
 	}
 	return d.Sum64()
-
 }
 
 func aggrFuncQuantiles(afa *aggrFuncArg) ([]*timeseries, error) {
@@ -0,0 +1,39 @@
+package promql
+
+import (
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/querytracer"
+)
+
+// trackAutoWindows enables tracking of lookbehind windows automatically chosen for the series processed by rcs if qt is enabled.
+//
+// The tracking isn't free, since it is performed for every processed series, so it is enabled only for traced queries.
+func trackAutoWindows(qt *querytracer.Tracer, rcs []*rollupConfig) {
+	if !qt.Enabled() {
+		return
+	}
+	for _, rc := range rcs {
+		if rc.Window <= 0 {
+			rc.trackAutoWindow = true
+		}
+	}
+}
+
+// traceAutoWindows adds lookbehind windows automatically chosen for the series processed by rcs to qt.
+//
+// trackAutoWindows must be called for rcs before processing the series.
+func traceAutoWindows(qt *querytracer.Tracer, rcs []*rollupConfig) {
+	if !qt.Enabled() {
+		return
+	}
+	for _, rc := range rcs {
+		if !rc.trackAutoWindow {
+			continue
+		}
+		minWindow := rc.autoWindowMin.Load()
+		if minWindow == 0 {
+			// No series were processed
+			continue
+		}
+		qt.Printf("automatically chosen lookbehind window for step=%dms: min=%dms, max=%dms", rc.Step, minWindow, rc.autoWindowMax.Load())
+	}
+}
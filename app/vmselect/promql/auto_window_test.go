@@ -0,0 +1,54 @@
+package promql
+
+import (
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/querytracer"
+)
+
+func TestRollupConfigUpdateAutoWindow(t *testing.T) {
+	f := func(windows []int64, minExpected, maxExpected int64) {
+		t.Helper()
+
+		var rc rollupConfig
+		for _, w := range windows {
+			rc.updateAutoWindow(w)
+		}
+		if n := rc.autoWindowMin.Load(); n != minExpected {
+			t.Fatalf("unexpected min window; got %d; want %d", n, minExpected)
+		}
+		if n := rc.autoWindowMax.Load(); n != maxExpected {
+			t.Fatalf("unexpected max window; got %d; want %d", n, maxExpected)
+		}
+	}
+
+	f(nil, 0, 0)
+	f([]int64{30e3}, 30e3, 30e3)
+	f([]int64{30e3, 15e3, 60e3, 20e3}, 15e3, 60e3)
+}
+
+func TestTrackAutoWindows(t *testing.T) {
+	f := func(qt *querytracer.Tracer, windows []int64, resultExpected []bool) {
+		t.Helper()
+
+		rcs := make([]*rollupConfig, len(windows))
+		for i, window := range windows {
+			rcs[i] = &rollupConfig{
+				Window: window,
+			}
+		}
+		trackAutoWindows(qt, rcs)
+		for i, rc := range rcs {
+			if rc.trackAutoWindow != resultExpected[i] {
+				t.Fatalf("unexpected trackAutoWindow for window=%d; got %v; want %v", rc.Window, rc.trackAutoWindow, resultExpected[i])
+			}
+		}
+	}
+
+	// tracing is disabled
+	f(nil, []int64{0, 60e3}, []bool{false, false})
+
+	// tracing is enabled
+	qt := querytracer.New(true, "test")
+	f(qt, []int64{0, 60e3}, []bool{true, false})
+}
@@ -144,6 +144,15 @@ type EvalConfig struct {
 	// The caller must initialize QueryStats, otherwise it isn't collected.
 	QueryStats *QueryStats
 
+	// queryStart and queryEnd contain the time range of the original query.
+	//
+	// They are used for evaluating start() and end() inside `@` modifier,
+	// since they must refer to the original query time range even inside subqueries and offsets.
+	// See https://prometheus.io/docs/prometheus/latest/querying/basics/#modifier
+	queryStart        int64
+	queryEnd          int64
+	hasQueryTimeRange bool
+
 	timestamps     []int64
 	timestampsOnce sync.Once
 }
@@ -163,6 +172,8 @@ func copyEvalConfig(src *EvalConfig) *EvalConfig {
 	ec.EnforcedTagFilterss = src.EnforcedTagFilterss
 	ec.GetRequestURI = src.GetRequestURI
 	ec.QueryStats = src.QueryStats
+	ec.queryStart, ec.queryEnd = src.getQueryTimeRange()
+	ec.hasQueryTimeRange = true
 
 	// do not copy src.timestamps - they must be generated again.
 	return &ec
@@ -222,6 +233,14 @@ func (ec *EvalConfig) mayCache() bool {
 	return true
 }
 
+// getQueryTimeRange returns the time range of the original query.
+func (ec *EvalConfig) getQueryTimeRange() (int64, int64) {
+	if ec.hasQueryTimeRange {
+		return ec.queryStart, ec.queryEnd
+	}
+	return ec.Start, ec.End
+}
+
 func (ec *EvalConfig) timeRangeString() string {
 	start := storage.TimestampToHumanReadableFormat(ec.Start)
 	end := storage.TimestampToHumanReadableFormat(ec.End)
@@ -800,7 +819,12 @@ func evalRollupFunc(qt *querytracer.Tracer, ec *EvalConfig, funcName string, rf
 	if re.At == nil {
 		return evalRollupFuncWithoutAt(qt, ec, funcName, rf, expr, re, iafc)
 	}
-	tssAt, err := evalExpr(qt, ec, re.At)
+	// start() and end() inside `@` modifier must refer to the original query time range
+	// even if the modifier is located inside subquery or it is applied together with offset.
+	// See https://prometheus.io/docs/prometheus/latest/querying/basics/#modifier
+	ecAt := copyEvalConfig(ec)
+	ecAt.Start, ecAt.End = ec.getQueryTimeRange()
+	tssAt, err := evalExpr(qt, ecAt, re.At)
 	if err != nil {
 		return nil, &UserReadableError{
 			Err: fmt.Errorf("cannot evaluate `@` modifier: %w", err),
@@ -811,7 +835,13 @@ func evalRollupFunc(qt *querytracer.Tracer, ec *EvalConfig, funcName string, rf
 			Err: fmt.Errorf("`@` modifier must return a single series; it returns %d series instead", len(tssAt)),
 		}
 	}
-	atTimestamp := int64(tssAt[0].Values[0] * 1000)
+	atValue := tssAt[0].Values[0]
+	if math.IsNaN(atValue) || math.IsInf(atValue, 0) {
+		return nil, &UserReadableError{
+			Err: fmt.Errorf("`@` modifier must return a finite timestamp; it returns %v instead", atValue),
+		}
+	}
+	atTimestamp := int64(atValue * 1000)
 	ecNew := copyEvalConfig(ec)
 	ecNew.Start = atTimestamp
 	ecNew.End = atTimestamp
@@ -949,6 +979,7 @@ func evalRollupFuncWithSubquery(qt *querytracer.Tracer, ec *EvalConfig, funcName
 	if err != nil {
 		return nil, err
 	}
+	trackAutoWindows(qt, rcs)
 
 	var samplesScannedTotal atomic.Uint64
 	keepMetricNames := getKeepMetricNames(expr)
@@ -959,13 +990,13 @@ func evalRollupFuncWithSubquery(qt *querytracer.Tracer, ec *EvalConfig, funcName
 		preFunc(values, timestamps)
 		for _, rc := range rcs {
 			if tsm := newTimeseriesMap(funcName, keepMetricNames, sharedTimestamps, &tsSQ.MetricName); tsm != nil {
-				samplesScanned := rc.DoTimeseriesMap(tsm, values, timestamps)
+				samplesScanned := rc.DoTimeseriesMap(tsm, values, timestamps, 0)
 				samplesScannedTotal.Add(samplesScanned)
 				seriesByWorkerID[workerID].tss = tsm.AppendTimeseriesTo(seriesByWorkerID[workerID].tss)
 				continue
 			}
 			var ts timeseries
-			samplesScanned := doRollupForTimeseries(funcName, keepMetricNames, rc, &ts, &tsSQ.MetricName, values, timestamps, sharedTimestamps)
+			samplesScanned := doRollupForTimeseries(funcName, keepMetricNames, rc, &ts, &tsSQ.MetricName, values, timestamps, 0, sharedTimestamps)
 			samplesScannedTotal.Add(samplesScanned)
 			seriesByWorkerID[workerID].tss = append(seriesByWorkerID[workerID].tss, &ts)
 		}
@@ -979,6 +1010,7 @@ func evalRollupFuncWithSubquery(qt *querytracer.Tracer, ec *EvalConfig, funcName
 
 	rowsScannedPerQuery.Update(float64(samplesScannedTotal.Load()))
 	qt.Printf("rollup %s() over %d series returned by subquery: series=%d, samplesScanned=%d", funcName, len(tssSQ), len(tss), samplesScannedTotal.Load())
+	traceAutoWindows(qt, rcs)
 	return tss, nil
 }
 
@@ -1070,13 +1102,17 @@ func evalInstantRollup(qt *querytracer.Tracer, ec *EvalConfig, funcName string,
 		defer qt.Done()
 	}
 
-	evalAt := func(qt *querytracer.Tracer, timestamp, window int64) ([]*timeseries, error) {
+	evalAtWithHints := func(qt *querytracer.Tracer, timestamp, window int64) ([]*timeseries, bool, error) {
 		ecCopy := copyEvalConfig(ec)
 		ecCopy.Start = timestamp
 		ecCopy.End = timestamp
 		pointsPerSeries := int64(1)
 		return evalRollupFuncNoCache(qt, ecCopy, funcName, rf, expr, me, iafc, window, pointsPerSeries)
 	}
+	evalAt := func(qt *querytracer.Tracer, timestamp, window int64) ([]*timeseries, error) {
+		tss, _, err := evalAtWithHints(qt, timestamp, window)
+		return tss, err
+	}
 	tooBigOffset := func(offset int64) bool {
 		maxOffset := window / 2
 		if maxOffset > 1800*1000 {
@@ -1107,7 +1143,7 @@ func evalInstantRollup(qt *querytracer.Tracer, ec *EvalConfig, funcName string,
 				return tss, 0, err
 			}
 			qt.Printf("calculating the rollup at time=%s, because it is missing in the cache", storage.TimestampToHumanReadableFormat(start))
-			tss, err := evalAt(qt, start, window)
+			tss, scrapeIntervalHintsUsed, err := evalAtWithHints(qt, start, window)
 			if err != nil {
 				return nil, 0, err
 			}
@@ -1116,6 +1152,10 @@ func evalInstantRollup(qt *querytracer.Tracer, ec *EvalConfig, funcName string,
 				tss, err := evalAt(qt, timestamp, window)
 				return tss, 0, err
 			}
+			if scrapeIntervalHintsUsed {
+				qt.Printf("do not cache the calculated rollup, since it depends on scrape intervals tracked in memory")
+				return tss, offset, nil
+			}
 			rollupResultCacheV.PutInstantValues(qt, expr, window, ec.Step, ec.EnforcedTagFilterss, tss)
 			return tss, offset, nil
 		}
@@ -1609,19 +1649,20 @@ func evalRollupFuncWithMetricExpr(qt *querytracer.Tracer, ec *EvalConfig, funcNa
 		return rvs, nil
 	}
 	pointsPerSeries := 1 + (ec.End-ec.Start)/ec.Step
-	evalWithConfig := func(ec *EvalConfig) ([]*timeseries, error) {
-		tss, err := evalRollupFuncNoCache(qt, ec, funcName, rf, expr, me, iafc, window, pointsPerSeries)
+	evalWithConfig := func(ec *EvalConfig) ([]*timeseries, bool, error) {
+		tss, scrapeIntervalHintsUsed, err := evalRollupFuncNoCache(qt, ec, funcName, rf, expr, me, iafc, window, pointsPerSeries)
 		if err != nil {
 			err = &UserReadableError{
 				Err: err,
 			}
-			return nil, err
+			return nil, false, err
 		}
-		return tss, nil
+		return tss, scrapeIntervalHintsUsed, nil
 	}
 	if !ec.mayCache() {
 		qt.Printf("do not fetch series from cache, since it is disabled in the current context")
-		return evalWithConfig(ec)
+		tss, _, err := evalWithConfig(ec)
+		return tss, err
 	}
 
 	// Search for cached results.
@@ -1646,20 +1687,33 @@ func evalRollupFuncWithMetricExpr(qt *querytracer.Tracer, ec *EvalConfig, funcNa
 		ecNew = copyEvalConfig(ec)
 		ecNew.Start = start
 	}
-	tss, err := evalWithConfig(ecNew)
+	tss, scrapeIntervalHintsUsed, err := evalWithConfig(ecNew)
 	if err != nil {
 		return nil, err
 	}
+	if scrapeIntervalHintsUsed {
+		// Scrape intervals tracked in memory may differ from the intervals used for calculating the cached results,
+		// so do not mix them in a single response and do not cache the calculated results.
+		qt.Printf("do not cache the calculated rollup, since it depends on scrape intervals tracked in memory")
+		if ecNew == ec {
+			return tss, nil
+		}
+		tss, _, err = evalWithConfig(ec)
+		return tss, err
+	}
 
 	// Merge cached results with the fetched additional results.
 	rvs, ok := mergeSeries(qt, tssCached, tss, start, ec)
 	if !ok {
 		// Cannot merge series - fall back to non-cached querying.
 		qt.Printf("fall back to non-caching querying")
-		rvs, err = evalWithConfig(ec)
+		rvs, scrapeIntervalHintsUsed, err = evalWithConfig(ec)
 		if err != nil {
 			return nil, err
 		}
+		if scrapeIntervalHintsUsed {
+			return rvs, nil
+		}
 	}
 	rollupResultCacheV.PutSeries(qt, ec, expr, window, rvs)
 	return rvs, nil
@@ -1668,21 +1722,26 @@ func evalRollupFuncWithMetricExpr(qt *querytracer.Tracer, ec *EvalConfig, funcNa
 // evalRollupFuncNoCache calculates the given rf with the given lookbehind window.
 //
 // pointsPerSeries is used only for estimating the needed memory for query processing
+//
+// The returned bool is set to true if the scrape intervals tracked during data ingestion were used for the calculations.
+// Such results mustn't be cached, since the tracked intervals are kept in memory and they change over time.
+// See -storage.trackScrapeIntervals.
 func evalRollupFuncNoCache(qt *querytracer.Tracer, ec *EvalConfig, funcName string, rf rollupFunc,
-	expr metricsql.Expr, me *metricsql.MetricExpr, iafc *incrementalAggrFuncContext, window, pointsPerSeries int64) ([]*timeseries, error) {
+	expr metricsql.Expr, me *metricsql.MetricExpr, iafc *incrementalAggrFuncContext, window, pointsPerSeries int64) ([]*timeseries, bool, error) {
 	if qt.Enabled() {
 		qt = qt.NewChild("rollup %s: timeRange=%s, step=%d, window=%d", expr.AppendString(nil), ec.timeRangeString(), ec.Step, window)
 		defer qt.Done()
 	}
 	if window < 0 {
-		return nil, nil
+		return nil, false, nil
 	}
 	// Obtain rollup configs before fetching data from db, so type errors could be caught earlier.
 	sharedTimestamps := getTimestamps(ec.Start, ec.End, ec.Step, ec.MaxPointsPerSeries)
 	preFunc, rcs, err := getRollupConfigs(funcName, rf, expr, ec.Start, ec.End, ec.Step, ec.MaxPointsPerSeries, window, ec.LookbackDelta, sharedTimestamps)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
+	trackAutoWindows(qt, rcs)
 
 	// Fetch the result.
 	tfss := searchutils.ToTagFilterss(me.LabelFilterss)
@@ -1699,12 +1758,12 @@ func evalRollupFuncNoCache(qt *querytracer.Tracer, ec *EvalConfig, funcName stri
 	sq := storage.NewSearchQuery(minTimestamp, ec.End, tfss, ec.MaxSeries)
 	rss, err := netstorage.ProcessSearchQuery(qt, sq, ec.Deadline)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 	rssLen := rss.Len()
 	if rssLen == 0 {
 		rss.Cancel()
-		return nil, nil
+		return nil, false, nil
 	}
 	ec.QueryStats.addSeriesFetched(rssLen)
 
@@ -1745,7 +1804,7 @@ func evalRollupFuncNoCache(qt *querytracer.Tracer, ec *EvalConfig, funcName stri
 			"possible solutions are: reducing the number of matching time series; increasing `step` query arg (step=%gs); "+
 			"increasing -search.maxMemoryPerQuery",
 			expr.AppendString(nil), rollupPoints, timeseriesLen*len(rcs), pointsPerSeries, maxMemory, rollupMemorySize, float64(ec.Step)/1e3)
-		return nil, err
+		return nil, false, err
 	}
 	rml := getRollupMemoryLimiter()
 	if !rml.Get(uint64(rollupMemorySize)) {
@@ -1755,7 +1814,7 @@ func evalRollupFuncNoCache(qt *querytracer.Tracer, ec *EvalConfig, funcName stri
 			"possible solutions are: reducing the number of matching time series; increasing `step` query arg (step=%gs); "+
 			"switching to node with more RAM; increasing -memory.allowedPercent",
 			expr.AppendString(nil), rollupPoints, timeseriesLen*len(rcs), pointsPerSeries, rml.MaxSize, uint64(rollupMemorySize), float64(ec.Step)/1e3)
-		return nil, err
+		return nil, false, err
 	}
 	defer rml.Put(uint64(rollupMemorySize))
 	qt.Printf("the rollup evaluation needs an estimated %d bytes of RAM for %d series and %d points per series (summary %d points)",
@@ -1763,10 +1822,14 @@ func evalRollupFuncNoCache(qt *querytracer.Tracer, ec *EvalConfig, funcName stri
 
 	// Evaluate rollup
 	keepMetricNames := getKeepMetricNames(expr)
+	var scrapeIntervalHintsUsed atomic.Bool
+	var tss []*timeseries
 	if iafc != nil {
-		return evalRollupWithIncrementalAggregate(qt, funcName, keepMetricNames, iafc, rss, rcs, preFunc, sharedTimestamps)
+		tss, err = evalRollupWithIncrementalAggregate(qt, funcName, keepMetricNames, iafc, rss, rcs, preFunc, sharedTimestamps, &scrapeIntervalHintsUsed)
+	} else {
+		tss, err = evalRollupNoIncrementalAggregate(qt, funcName, keepMetricNames, rss, rcs, preFunc, sharedTimestamps, &scrapeIntervalHintsUsed)
 	}
-	return evalRollupNoIncrementalAggregate(qt, funcName, keepMetricNames, rss, rcs, preFunc, sharedTimestamps)
+	return tss, scrapeIntervalHintsUsed.Load(), err
 }
 
 var (
@@ -1791,18 +1854,19 @@ func maxSilenceInterval() int64 {
 
 func evalRollupWithIncrementalAggregate(qt *querytracer.Tracer, funcName string, keepMetricNames bool,
 	iafc *incrementalAggrFuncContext, rss *netstorage.Results, rcs []*rollupConfig,
-	preFunc func(values []float64, timestamps []int64), sharedTimestamps []int64) ([]*timeseries, error) {
+	preFunc func(values []float64, timestamps []int64), sharedTimestamps []int64, scrapeIntervalHintsUsed *atomic.Bool) ([]*timeseries, error) {
 	qt = qt.NewChild("rollup %s() with incremental aggregation %s() over %d series; rollupConfigs=%s", funcName, iafc.ae.Name, rss.Len(), rcs)
 	defer qt.Done()
 	var samplesScannedTotal atomic.Uint64
 	err := rss.RunParallel(qt, func(rs *netstorage.Result, workerID uint) error {
 		rs.Values, rs.Timestamps = dropStaleNaNs(funcName, rs.Values, rs.Timestamps)
 		preFunc(rs.Values, rs.Timestamps)
+		scrapeIntervalHint := getScrapeIntervalHint(rs, scrapeIntervalHintsUsed)
 		ts := getTimeseries()
 		defer putTimeseries(ts)
 		for _, rc := range rcs {
 			if tsm := newTimeseriesMap(funcName, keepMetricNames, sharedTimestamps, &rs.MetricName); tsm != nil {
-				samplesScanned := rc.DoTimeseriesMap(tsm, rs.Values, rs.Timestamps)
+				samplesScanned := rc.DoTimeseriesMap(tsm, rs.Values, rs.Timestamps, scrapeIntervalHint)
 				for _, ts := range tsm.m {
 					iafc.updateTimeseries(ts, workerID)
 				}
@@ -1810,7 +1874,7 @@ func evalRollupWithIncrementalAggregate(qt *querytracer.Tracer, funcName string,
 				continue
 			}
 			ts.Reset()
-			samplesScanned := doRollupForTimeseries(funcName, keepMetricNames, rc, ts, &rs.MetricName, rs.Values, rs.Timestamps, sharedTimestamps)
+			samplesScanned := doRollupForTimeseries(funcName, keepMetricNames, rc, ts, &rs.MetricName, rs.Values, rs.Timestamps, scrapeIntervalHint, sharedTimestamps)
 			samplesScannedTotal.Add(samplesScanned)
 			iafc.updateTimeseries(ts, workerID)
 
@@ -1826,11 +1890,12 @@ func evalRollupWithIncrementalAggregate(qt *querytracer.Tracer, funcName string,
 	tss := iafc.finalizeTimeseries()
 	rowsScannedPerQuery.Update(float64(samplesScannedTotal.Load()))
 	qt.Printf("series after aggregation with %s(): %d; samplesScanned=%d", iafc.ae.Name, len(tss), samplesScannedTotal.Load())
+	traceAutoWindows(qt, rcs)
 	return tss, nil
 }
 
 func evalRollupNoIncrementalAggregate(qt *querytracer.Tracer, funcName string, keepMetricNames bool, rss *netstorage.Results, rcs []*rollupConfig,
-	preFunc func(values []float64, timestamps []int64), sharedTimestamps []int64) ([]*timeseries, error) {
+	preFunc func(values []float64, timestamps []int64), sharedTimestamps []int64, scrapeIntervalHintsUsed *atomic.Bool) ([]*timeseries, error) {
 	qt = qt.NewChild("rollup %s() over %d series; rollupConfigs=%s", funcName, rss.Len(), rcs)
 	defer qt.Done()
 
@@ -1841,15 +1906,16 @@ func evalRollupNoIncrementalAggregate(qt *querytracer.Tracer, funcName string, k
 	err := rss.RunParallel(qt, func(rs *netstorage.Result, workerID uint) error {
 		rs.Values, rs.Timestamps = dropStaleNaNs(funcName, rs.Values, rs.Timestamps)
 		preFunc(rs.Values, rs.Timestamps)
+		scrapeIntervalHint := getScrapeIntervalHint(rs, scrapeIntervalHintsUsed)
 		for _, rc := range rcs {
 			if tsm := newTimeseriesMap(funcName, keepMetricNames, sharedTimestamps, &rs.MetricName); tsm != nil {
-				samplesScanned := rc.DoTimeseriesMap(tsm, rs.Values, rs.Timestamps)
+				samplesScanned := rc.DoTimeseriesMap(tsm, rs.Values, rs.Timestamps, scrapeIntervalHint)
 				samplesScannedTotal.Add(samplesScanned)
 				seriesByWorkerID[workerID].tss = tsm.AppendTimeseriesTo(seriesByWorkerID[workerID].tss)
 				continue
 			}
 			var ts timeseries
-			samplesScanned := doRollupForTimeseries(funcName, keepMetricNames, rc, &ts, &rs.MetricName, rs.Values, rs.Timestamps, sharedTimestamps)
+			samplesScanned := doRollupForTimeseries(funcName, keepMetricNames, rc, &ts, &rs.MetricName, rs.Values, rs.Timestamps, scrapeIntervalHint, sharedTimestamps)
 			samplesScannedTotal.Add(samplesScanned)
 			seriesByWorkerID[workerID].tss = append(seriesByWorkerID[workerID].tss, &ts)
 		}
@@ -1866,11 +1932,28 @@ func evalRollupNoIncrementalAggregate(qt *querytracer.Tracer, funcName string, k
 
 	rowsScannedPerQuery.Update(float64(samplesScannedTotal.Load()))
 	qt.Printf("samplesScanned=%d", samplesScannedTotal.Load())
+	traceAutoWindows(qt, rcs)
 	return tss, nil
 }
 
+// getScrapeIntervalHint returns the interval between samples observed during data ingestion for rs.
+//
+// Zero is returned if the interval wasn't observed on the time range covering the selected samples,
+// since the interval may differ on other time ranges. scrapeIntervalHintsUsed is set to true if non-zero interval is returned.
+// See -storage.trackScrapeIntervals.
+func getScrapeIntervalHint(rs *netstorage.Result, scrapeIntervalHintsUsed *atomic.Bool) int64 {
+	if len(rs.Timestamps) == 0 {
+		return 0
+	}
+	scrapeIntervalHint := rs.ScrapeInterval.IntervalForTimeRange(rs.Timestamps[0], rs.Timestamps[len(rs.Timestamps)-1])
+	if scrapeIntervalHint > 0 {
+		scrapeIntervalHintsUsed.Store(true)
+	}
+	return scrapeIntervalHint
+}
+
 func doRollupForTimeseries(funcName string, keepMetricNames bool, rc *rollupConfig, tsDst *timeseries, mnSrc *storage.MetricName,
-	valuesSrc []float64, timestampsSrc []int64, sharedTimestamps []int64) uint64 {
+	valuesSrc []float64, timestampsSrc []int64, scrapeInterval int64, sharedTimestamps []int64) uint64 {
 	tsDst.MetricName.CopyFrom(mnSrc)
 	if len(rc.TagValue) > 0 {
 		tsDst.MetricName.AddTag("rollup", rc.TagValue)
@@ -1879,7 +1962,7 @@ func doRollupForTimeseries(funcName string, keepMetricNames bool, rc *rollupConf
 		tsDst.MetricName.ResetMetricGroup()
 	}
 	var samplesScanned uint64
-	tsDst.Values, samplesScanned = rc.Do(tsDst.Values[:0], valuesSrc, timestampsSrc)
+	tsDst.Values, samplesScanned = rc.DoWithScrapeInterval(tsDst.Values[:0], valuesSrc, timestampsSrc, scrapeInterval)
 	tsDst.Timestamps = sharedTimestamps
 	tsDst.denyReuse = true
 	return samplesScanned
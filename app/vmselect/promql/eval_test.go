@@ -2,8 +2,10 @@ package promql
 
 import (
 	"reflect"
+	"sync/atomic"
 	"testing"
 
+	"github.com/VictoriaMetrics/VictoriaMetrics/app/vmselect/netstorage"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/prometheus"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/storage"
 	"github.com/VictoriaMetrics/metricsql"
@@ -170,3 +172,41 @@ func TestGetSumInstantValues(t *testing.T) {
 		[]*timeseries{ts("foo", 100, 1)},
 	)
 }
+
+func TestGetScrapeIntervalHint(t *testing.T) {
+	f := func(timestamps []int64, si storage.ScrapeInterval, hintExpected int64) {
+		t.Helper()
+
+		rs := &netstorage.Result{
+			Timestamps:     timestamps,
+			ScrapeInterval: si,
+		}
+		var hintsUsed atomic.Bool
+		hint := getScrapeIntervalHint(rs, &hintsUsed)
+		if hint != hintExpected {
+			t.Fatalf("unexpected scrape interval hint; got %d; want %d", hint, hintExpected)
+		}
+		if hintsUsed.Load() != (hintExpected > 0) {
+			t.Fatalf("unexpected hintsUsed; got %v; want %v", hintsUsed.Load(), hintExpected > 0)
+		}
+	}
+
+	si := storage.ScrapeInterval{
+		Interval:       10000,
+		FirstTimestamp: 100000,
+		LastTimestamp:  200000,
+		Samples:        11,
+	}
+
+	// no samples
+	f(nil, si, 0)
+
+	// the samples are located on the time range where the interval was observed
+	f([]int64{100000, 110000, 120000}, si, 10000)
+
+	// historical samples
+	f([]int64{0, 60000, 120000}, si, 0)
+
+	// the interval isn't tracked
+	f([]int64{100000, 110000, 120000}, storage.ScrapeInterval{}, 0)
+}
@@ -1254,6 +1254,50 @@ func TestExecSuccess(t *testing.T) {
 		resultExpected := []netstorage.Result{r}
 		f(q, resultExpected)
 	})
+	t.Run("time() @ end() offset -10m", func(t *testing.T) {
+		t.Parallel()
+		q := `time() @ end() offset -10m`
+		r := netstorage.Result{
+			MetricName: metricNameExpected,
+			Values:     []float64{2600, 2600, 2600, 2600, 2600, 2600},
+			Timestamps: timestampsExpected,
+		}
+		resultExpected := []netstorage.Result{r}
+		f(q, resultExpected)
+	})
+	t.Run("min_over_time((time() @ start())[10m:100s])", func(t *testing.T) {
+		t.Parallel()
+		q := `min_over_time((time() @ start())[10m:100s])`
+		r := netstorage.Result{
+			MetricName: metricNameExpected,
+			Values:     []float64{1000, 1000, 1000, 1000, 1000, 1000},
+			Timestamps: timestampsExpected,
+		}
+		resultExpected := []netstorage.Result{r}
+		f(q, resultExpected)
+	})
+	t.Run("max_over_time((time() @ end())[10m:100s] offset 5m)", func(t *testing.T) {
+		t.Parallel()
+		q := `max_over_time((time() @ end())[10m:100s] offset 5m)`
+		r := netstorage.Result{
+			MetricName: metricNameExpected,
+			Values:     []float64{2000, 2000, 2000, 2000, 2000, 2000},
+			Timestamps: timestampsExpected,
+		}
+		resultExpected := []netstorage.Result{r}
+		f(q, resultExpected)
+	})
+	t.Run("min_over_time(time()[5m:1m] @ 1h offset -10m)", func(t *testing.T) {
+		t.Parallel()
+		q := `min_over_time(time()[5m:1m] @ 1h offset -10m)`
+		r := netstorage.Result{
+			MetricName: metricNameExpected,
+			Values:     []float64{3960, 3960, 3960, 3960, 3960, 3960},
+			Timestamps: timestampsExpected,
+		}
+		resultExpected := []netstorage.Result{r}
+		f(q, resultExpected)
+	})
 	t.Run("rand()", func(t *testing.T) {
 		t.Parallel()
 		q := `round(rand()/2)`
@@ -4067,6 +4111,22 @@ func TestExecSuccess(t *testing.T) {
 		resultExpected := []netstorage.Result{r}
 		f(q, resultExpected)
 	})
+	t.Run(`histogram_quantile(mixed-le-vmrange)`, func(t *testing.T) {
+		t.Parallel()
+		q := `histogram_quantile(0.5, (
+			label_set(10, "le", "1"),
+			label_set(20, "le", "2"),
+			label_set(20, "le", "+Inf"),
+			label_set(20, "vmrange", "1...2"),
+		))`
+		r := netstorage.Result{
+			MetricName: metricNameExpected,
+			Values:     []float64{1.3333333333333333, 1.3333333333333333, 1.3333333333333333, 1.3333333333333333, 1.3333333333333333, 1.3333333333333333},
+			Timestamps: timestampsExpected,
+		}
+		resultExpected := []netstorage.Result{r}
+		f(q, resultExpected)
+	})
 	t.Run(`stdvar_over_time()`, func(t *testing.T) {
 		t.Parallel()
 		q := `round(stdvar_over_time(rand(0)[200s:5s]), 0.001)`
@@ -6315,6 +6375,46 @@ func TestExecSuccess(t *testing.T) {
 		resultExpected := []netstorage.Result{r1}
 		f(q, resultExpected)
 	})
+	t.Run(`limitk(1, empty-series)`, func(t *testing.T) {
+		t.Parallel()
+		// The series without values mustn't occupy the limited slots.
+		q := `limitk(1, label_set(time() > 1e6, "foo", "bar") or label_set(time()/150, "xbaz", "sss"))`
+		r1 := netstorage.Result{
+			MetricName: metricNameExpected,
+			Values:     []float64{6.666666666666667, 8, 9.333333333333334, 10.666666666666666, 12, 13.333333333333334},
+			Timestamps: timestampsExpected,
+		}
+		r1.MetricName.Tags = []storage.Tag{{
+			Key:   []byte("xbaz"),
+			Value: []byte("sss"),
+		}}
+		resultExpected := []netstorage.Result{r1}
+		f(q, resultExpected)
+	})
+	t.Run(`limitk(1, tags-order)`, func(t *testing.T) {
+		t.Parallel()
+		// The selected series mustn't depend on the order of labels.
+		q := `limitk(1, label_set(10, "foo", "bar", "a", "b") or label_set(time()/150, "xbaz", "sss", "a", "b"))`
+		r1 := netstorage.Result{
+			MetricName: metricNameExpected,
+			Values:     []float64{10, 10, 10, 10, 10, 10},
+			Timestamps: timestampsExpected,
+		}
+		r1.MetricName.Tags = []storage.Tag{
+			{
+				Key:   []byte("a"),
+				Value: []byte("b"),
+			},
+			{
+				Key:   []byte("foo"),
+				Value: []byte("bar"),
+			},
+		}
+		resultExpected := []netstorage.Result{r1}
+		f(q, resultExpected)
+		q = `limitk(1, label_set(10, "a", "b", "foo", "bar") or label_set(time()/150, "a", "b", "xbaz", "sss"))`
+		f(q, resultExpected)
+	})
 	t.Run(`limitk(10)`, func(t *testing.T) {
 		t.Parallel()
 		q := `sort(limitk(10, label_set(10, "foo", "bar") or label_set(time()/150, "baz", "sss")))`
@@ -9365,6 +9465,10 @@ func TestExecError(t *testing.T) {
 		label_set(time()+200, "__name__", "bar", "a", "x"),
 	) + 10`)
 
+	// Invalid `@` modifier
+	f(`time() @ NaN`)
+	f(`time() @ (1, 2)`)
+
 	// Invalid aggregates
 	f(`sum(1) foo (bar)`)
 	f(`sum foo () (bar)`)
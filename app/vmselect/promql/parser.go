@@ -40,3 +40,22 @@ func IsMetricSelectorWithRollup(s string) (childQuery string, window, offset *me
 	wrappedQuery := me.AppendString(nil)
 	return string(wrappedQuery), re.Window, re.Offset
 }
+
+// GetLabelFilterss returns label filters for all the series selectors in the query q.
+//
+// Every item in the returned slice contains label filters for a single series selector.
+func GetLabelFilterss(q string) ([][]metricsql.LabelFilter, error) {
+	expr, err := parsePromQLWithCache(q)
+	if err != nil {
+		return nil, err
+	}
+	var lfss [][]metricsql.LabelFilter
+	metricsql.VisitAll(expr, func(e metricsql.Expr) {
+		me, ok := e.(*metricsql.MetricExpr)
+		if !ok {
+			return
+		}
+		lfss = append(lfss, me.LabelFilterss...)
+	})
+	return lfss, nil
+}
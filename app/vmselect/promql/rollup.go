@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/VictoriaMetrics/metrics"
 	"github.com/VictoriaMetrics/metricsql"
@@ -586,6 +587,32 @@ type rollupConfig struct {
 	//
 	// If zero, then it is considered that Func scans all the samples passed to it.
 	samplesScannedPerCall int
+
+	// trackAutoWindow is set to true if lookbehind windows automatically chosen for the processed series
+	// must be tracked in autoWindowMin and autoWindowMax. See trackAutoWindows.
+	trackAutoWindow bool
+
+	// autoWindowMin and autoWindowMax contain the minimum and the maximum lookbehind windows
+	// automatically chosen for the processed series if Window isn't set.
+	//
+	// They are exposed in query trace.
+	autoWindowMin atomic.Int64
+	autoWindowMax atomic.Int64
+}
+
+func (rc *rollupConfig) updateAutoWindow(window int64) {
+	for {
+		n := rc.autoWindowMin.Load()
+		if (n > 0 && n <= window) || rc.autoWindowMin.CompareAndSwap(n, window) {
+			break
+		}
+	}
+	for {
+		n := rc.autoWindowMax.Load()
+		if n >= window || rc.autoWindowMax.CompareAndSwap(n, window) {
+			break
+		}
+	}
 }
 
 func (rc *rollupConfig) getTimestamps() []int64 {
@@ -669,19 +696,28 @@ func (tsm *timeseriesMap) GetOrCreateTimeseries(labelName, labelValue string) *t
 //
 // Do cannot be called from concurrent goroutines.
 func (rc *rollupConfig) Do(dstValues []float64, values []float64, timestamps []int64) ([]float64, uint64) {
-	return rc.doInternal(dstValues, nil, values, timestamps)
+	return rc.doInternal(dstValues, nil, values, timestamps, 0)
+}
+
+// DoWithScrapeInterval is like Do, but uses the given scrapeInterval hint for the time series.
+//
+// scrapeInterval is the interval in milliseconds between samples observed during data ingestion. It is ignored if it is zero.
+func (rc *rollupConfig) DoWithScrapeInterval(dstValues []float64, values []float64, timestamps []int64, scrapeInterval int64) ([]float64, uint64) {
+	return rc.doInternal(dstValues, nil, values, timestamps, scrapeInterval)
 }
 
 // DoTimeseriesMap calculates rollups for the given timestamps and values and puts them to tsm.
-func (rc *rollupConfig) DoTimeseriesMap(tsm *timeseriesMap, values []float64, timestamps []int64) uint64 {
+//
+// scrapeInterval is the interval in milliseconds between samples observed during data ingestion. It is ignored if it is zero.
+func (rc *rollupConfig) DoTimeseriesMap(tsm *timeseriesMap, values []float64, timestamps []int64, scrapeInterval int64) uint64 {
 	ts := getTimeseries()
 	var samplesScanned uint64
-	ts.Values, samplesScanned = rc.doInternal(ts.Values[:0], tsm, values, timestamps)
+	ts.Values, samplesScanned = rc.doInternal(ts.Values[:0], tsm, values, timestamps, scrapeInterval)
 	putTimeseries(ts)
 	return samplesScanned
 }
 
-func (rc *rollupConfig) doInternal(dstValues []float64, tsm *timeseriesMap, values []float64, timestamps []int64) ([]float64, uint64) {
+func (rc *rollupConfig) doInternal(dstValues []float64, tsm *timeseriesMap, values []float64, timestamps []int64, scrapeIntervalHint int64) ([]float64, uint64) {
 	// Sanity checks.
 	if rc.Step <= 0 {
 		logger.Panicf("BUG: Step must be bigger than 0; got %d", rc.Step)
@@ -699,7 +735,7 @@ func (rc *rollupConfig) doInternal(dstValues []float64, tsm *timeseriesMap, valu
 	// Extend dstValues in order to remove mallocs below.
 	dstValues = decimal.ExtendFloat64sCapacity(dstValues, len(rc.Timestamps))
 
-	scrapeInterval := getScrapeInterval(timestamps, rc.Step)
+	scrapeInterval := getScrapeInterval(timestamps, rc.Step, scrapeIntervalHint)
 	maxPrevInterval := getMaxPrevInterval(scrapeInterval)
 	if rc.LookbackDelta > 0 && maxPrevInterval > rc.LookbackDelta {
 		maxPrevInterval = rc.LookbackDelta
@@ -728,6 +764,9 @@ func (rc *rollupConfig) doInternal(dstValues []float64, tsm *timeseriesMap, valu
 			// according to https://github.com/VictoriaMetrics/VictoriaMetrics/issues/784
 			window = rc.LookbackDelta
 		}
+		if rc.trackAutoWindow {
+			rc.updateAutoWindow(window)
+		}
 	}
 	rfa := getRollupFuncArg()
 	rfa.idx = 0
@@ -836,10 +875,21 @@ func binarySearchInt64(a []int64, v int64) uint {
 	return i
 }
 
-func getScrapeInterval(timestamps []int64, defaultInterval int64) int64 {
+// getScrapeInterval returns the estimated interval between timestamps.
+//
+// scrapeIntervalHint is the interval between samples observed during data ingestion. It is ignored if it is zero.
+// See -storage.trackScrapeIntervals.
+func getScrapeInterval(timestamps []int64, defaultInterval, scrapeIntervalHint int64) int64 {
+	if scrapeIntervalHint > 0 {
+		// Stored samples cannot be denser than the deduplicated ingested samples.
+		scrapeIntervalHint = max(scrapeIntervalHint, storage.GetDedupInterval())
+	}
 	if len(timestamps) < 2 {
 		// can't calculate scrape interval with less than 2 timestamps
-		// return defaultInterval
+		// return scrapeIntervalHint or defaultInterval
+		if scrapeIntervalHint > 0 {
+			return scrapeIntervalHint
+		}
 		return defaultInterval
 	}
 
@@ -851,13 +901,21 @@ func getScrapeInterval(timestamps []int64, defaultInterval int64) int64 {
 	}
 	a := getFloat64s()
 	intervals := a.A[:0]
+	minInterval := int64(math.MaxInt64)
 	for _, ts := range timestamps {
 		intervals = append(intervals, float64(ts-tsPrev))
+		minInterval = min(minInterval, ts-tsPrev)
 		tsPrev = ts
 	}
 	scrapeInterval := int64(quantile(0.6, intervals))
 	a.A = intervals
 	putFloat64s(a)
+	if scrapeIntervalHint > 0 && minInterval < 2*scrapeIntervalHint {
+		// The selected samples have the same density as the ingested samples.
+		// Prefer the interval observed during data ingestion over the estimation from the first 20 intervals,
+		// since the latter may be inaccurate because of gaps in the selected samples.
+		return scrapeIntervalHint
+	}
 	if scrapeInterval <= 0 {
 		return defaultInterval
 	}
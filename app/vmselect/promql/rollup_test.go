@@ -1587,3 +1587,30 @@ func TestRollupDelta(t *testing.T) {
 	f(1, nan, nan, nil, 0)
 	f(100, nan, nan, nil, 0)
 }
+
+func TestGetScrapeInterval(t *testing.T) {
+	f := func(timestamps []int64, defaultInterval, scrapeIntervalHint, resultExpected int64) {
+		t.Helper()
+
+		result := getScrapeInterval(timestamps, defaultInterval, scrapeIntervalHint)
+		if result != resultExpected {
+			t.Fatalf("unexpected scrape interval for timestamps=%v, scrapeIntervalHint=%d; got %d; want %d", timestamps, scrapeIntervalHint, result, resultExpected)
+		}
+	}
+
+	// not enough timestamps
+	f(nil, 5000, 0, 5000)
+	f([]int64{1000}, 5000, 0, 5000)
+	f([]int64{1000}, 5000, 10000, 10000)
+
+	// regular intervals
+	f([]int64{0, 10000, 20000, 30000}, 5000, 0, 10000)
+	f([]int64{0, 10000, 20000, 30000}, 5000, 10000, 10000)
+
+	// gaps in the selected samples
+	f([]int64{0, 60000, 120000, 130000}, 5000, 0, 60000)
+	f([]int64{0, 60000, 120000, 130000}, 5000, 10000, 10000)
+
+	// the selected samples are sparser than the ingested samples, e.g. because of downsampling
+	f([]int64{0, 300000, 600000, 900000}, 5000, 10000, 300000)
+}
@@ -491,6 +491,7 @@ func transformPrometheusBuckets(tfa *transformFuncArg) ([]*timeseries, error) {
 }
 
 func vmrangeBucketsToLE(tss []*timeseries) []*timeseries {
+	var leTss []*timeseries
 	rvs := make([]*timeseries, 0, len(tss))
 
 	// Group timeseries by MetricGroup+tags excluding `vmrange` tag.
@@ -509,7 +510,7 @@ func vmrangeBucketsToLE(tss []*timeseries) []*timeseries {
 		if len(vmrange) == 0 {
 			if le := ts.MetricName.GetTagValue("le"); len(le) > 0 {
 				// Keep Prometheus-compatible buckets.
-				rvs = append(rvs, ts)
+				leTss = append(leTss, ts)
 			}
 			continue
 		}
@@ -627,9 +628,181 @@ func vmrangeBucketsToLE(tss []*timeseries) []*timeseries {
 			rvs = append(rvs, xs.ts)
 		}
 	}
+	if len(leTss) == 0 {
+		return rvs
+	}
+	if len(rvs) == 0 {
+		return leTss
+	}
+	return mergeLEAndVMRangeBuckets(leTss, rvs)
+}
+
+// mergeLEAndVMRangeBuckets merges Prometheus-compatible buckets from leTss with buckets converted from `vmrange` buckets in vmrangeTss.
+//
+// Histograms with the same labels may be exposed with `le` buckets by some sources and with `vmrange` buckets by other sources,
+// e.g. during migration from Prometheus histograms to VictoriaMetrics histograms.
+// Such histograms are merged into a single histogram with the union of bucket bounds.
+// The cumulative counters for bounds missing in some source are linearly interpolated from the adjacent buckets of this source.
+func mergeLEAndVMRangeBuckets(leTss, vmrangeTss []*timeseries) []*timeseries {
+	type group struct {
+		leTss      []*timeseries
+		vmrangeTss []*timeseries
+	}
+	m := make(map[string]*group)
+	var keys []string
+	bb := bbPool.Get()
+	defer bbPool.Put(bb)
+	var mn storage.MetricName
+	getGroup := func(ts *timeseries) *group {
+		mn.CopyFrom(&ts.MetricName)
+		mn.RemoveTag("le")
+		bb.B = marshalMetricNameSorted(bb.B[:0], &mn)
+		g := m[string(bb.B)]
+		if g == nil {
+			g = &group{}
+			k := string(bb.B)
+			m[k] = g
+			keys = append(keys, k)
+		}
+		return g
+	}
+	for _, ts := range leTss {
+		g := getGroup(ts)
+		g.leTss = append(g.leTss, ts)
+	}
+	for _, ts := range vmrangeTss {
+		g := getGroup(ts)
+		g.vmrangeTss = append(g.vmrangeTss, ts)
+	}
+
+	rvs := make([]*timeseries, 0, len(leTss)+len(vmrangeTss))
+	for _, ts := range leTss {
+		if g := getGroup(ts); len(g.vmrangeTss) == 0 {
+			rvs = append(rvs, ts)
+		}
+	}
+	for _, ts := range vmrangeTss {
+		if g := getGroup(ts); len(g.leTss) == 0 {
+			rvs = append(rvs, ts)
+		}
+	}
+	for _, k := range keys {
+		g := m[k]
+		if len(g.leTss) == 0 || len(g.vmrangeTss) == 0 {
+			continue
+		}
+		rvs = mergeCumulativeBuckets(rvs, [][]*timeseries{g.leTss, g.vmrangeTss})
+	}
 	return rvs
 }
 
+type leBucket struct {
+	le    float64
+	leStr string
+	ts    *timeseries
+}
+
+func getSortedLEBuckets(tss []*timeseries) []leBucket {
+	bs := make([]leBucket, 0, len(tss))
+	for _, ts := range tss {
+		leStr := string(ts.MetricName.GetTagValue("le"))
+		le, err := strconv.ParseFloat(leStr, 64)
+		if err != nil {
+			continue
+		}
+		bs = append(bs, leBucket{
+			le:    le,
+			leStr: leStr,
+			ts:    ts,
+		})
+	}
+	sort.SliceStable(bs, func(i, j int) bool { return bs[i].le < bs[j].le })
+	return bs
+}
+
+// mergeCumulativeBuckets appends to dst `le` buckets for the sum of histograms from sources.
+//
+// Every source must contain `le` buckets for a single histogram.
+func mergeCumulativeBuckets(dst []*timeseries, sources [][]*timeseries) []*timeseries {
+	sourceBuckets := make([][]leBucket, 0, len(sources))
+	var bounds []leBucket
+	for _, tss := range sources {
+		bs := getSortedLEBuckets(tss)
+		if len(bs) == 0 {
+			continue
+		}
+		sourceBuckets = append(sourceBuckets, bs)
+		bounds = append(bounds, bs...)
+	}
+	if len(bounds) == 0 {
+		return dst
+	}
+	sort.SliceStable(bounds, func(i, j int) bool { return bounds[i].le < bounds[j].le })
+
+	leSeen := make(map[float64]bool, len(bounds))
+	for _, b := range bounds {
+		if leSeen[b.le] {
+			continue
+		}
+		leSeen[b.le] = true
+
+		var ts timeseries
+		ts.CopyFromShallowTimestamps(b.ts)
+		ts.MetricName.RemoveTag("le")
+		ts.MetricName.AddTag("le", b.leStr)
+		values := ts.Values
+		for i := range values {
+			sum := float64(0)
+			hasValue := false
+			for _, bs := range sourceBuckets {
+				v, ok := getCumulativeBucketValue(bs, i, b.le)
+				if ok {
+					sum += v
+					hasValue = true
+				}
+			}
+			if !hasValue {
+				sum = nan
+			}
+			values[i] = sum
+		}
+		dst = append(dst, &ts)
+	}
+	return dst
+}
+
+// getCumulativeBucketValue returns the cumulative counter at the point with index i for the given le from sorted bs.
+//
+// The counter is linearly interpolated if bs has no bucket with the given le.
+// It is assumed that the lower bound of the first bucket is 0 like Prometheus does.
+// false is returned if bs has no values at the point with index i.
+func getCumulativeBucketValue(bs []leBucket, i int, le float64) (float64, bool) {
+	vPrev := float64(0)
+	lePrev := float64(0)
+	hasValue := false
+	for _, b := range bs {
+		v := b.ts.Values[i]
+		if math.IsNaN(v) {
+			continue
+		}
+		hasValue = true
+		if b.le == le {
+			return v, true
+		}
+		if b.le > le {
+			if math.IsInf(b.le, 1) || math.IsInf(lePrev, -1) || le <= lePrev {
+				// It is impossible to interpolate the counter. Assume that all the observations
+				// from the current bucket are bigger than le.
+				return vPrev, true
+			}
+			return vPrev + (v-vPrev)*(le-lePrev)/(b.le-lePrev), true
+		}
+		vPrev = v
+		lePrev = b.le
+	}
+	return vPrev, hasValue
+}
+
 func transformHistogramShare(tfa *transformFuncArg) ([]*timeseries, error) {
 	args := tfa.args
 	if len(args) < 2 || len(args) > 3 {
@@ -1863,7 +2036,6 @@ func transformDropEmptySeries(tfa *transformFuncArg) ([]*timeseries, error) {
 	rvs := removeEmptySeries(args[0])
 	return rvs, nil
 }
-
 func transformLabelCopy(tfa *transformFuncArg) ([]*timeseries, error) {
 	return transformLabelCopyExt(tfa, false)
 }
@@ -238,6 +238,40 @@ foo{vmrange="1...2"} 0 123`,
 		`foo{vmrange="4.084e+02...foo"} 1 1`,
 		``,
 	)
+
+	// Mixed le and vmrange buckets for distinct histograms
+	f(
+		`foo{le="1"} 3 6
+bar{vmrange="1...2"} 4 6`,
+		`foo{le="1"} 3 6
+bar{le="1"} 0 6
+bar{le="2"} 4 6
+bar{le="+Inf"} 4 6`,
+	)
+
+	// Mixed le and vmrange buckets with the same bounds for the same histogram
+	f(
+		`foo{le="1"} 10 6
+foo{le="2"} 20 6
+foo{le="+Inf"} 20 6
+foo{vmrange="1...2"} 20 6`,
+		`foo{le="1"} 10 6
+foo{le="2"} 40 6
+foo{le="+Inf"} 40 6`,
+	)
+
+	// Mixed le and vmrange buckets with distinct bounds for the same histogram
+	f(
+		`foo{le="1"} 10 6
+foo{le="2"} 20 6
+foo{le="+Inf"} 20 6
+foo{vmrange="1.5...3"} 8 6`,
+		`foo{le="1"} 10 6
+foo{le="1.5"} 15 6
+foo{le="2"} 22.666666666666668 6
+foo{le="3"} 28 6
+foo{le="+Inf"} 28 6`,
+	)
 }
 
 func promMetricsToTimeseries(s string) []*timeseries {
@@ -0,0 +1,244 @@
+package searchutils
+
+import (
+	"flag"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync/atomic"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/envtemplate"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fasttime"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fs/fscore"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/procutil"
+	"github.com/VictoriaMetrics/metrics"
+	"github.com/VictoriaMetrics/metricsql"
+)
+
+var labelFilterMacrosFile = flag.String("search.labelFilterMacrosFile", "", "Optional path to a file with label filter macros, which can be referenced in queries via $name. "+
+	`For example, foo{$prod} is expanded into foo{env="prod",cluster=~"a|b"} if the file contains 'prod: {env="prod",cluster=~"a|b"}' line. `+
+	"The path can point either to local file or to http url. The file is reloaded on SIGHUP signal. "+
+	"See https://docs.victoriametrics.com/#label-filter-macros")
+
+// InitLabelFilterMacros loads label filter macros from -search.labelFilterMacrosFile.
+//
+// It must be called after flag.Parse and before ExpandLabelFilterMacros calls.
+func InitLabelFilterMacros() {
+	// Register SIGHUP handler for config re-read just before loadLabelFilterMacros call.
+	// This guarantees that the config will be re-read if the signal arrives during loadLabelFilterMacros call.
+	sighupCh := procutil.NewSighupChan()
+
+	lfm, err := loadLabelFilterMacros()
+	if err != nil {
+		logger.Fatalf("cannot load -search.labelFilterMacrosFile: %s", err)
+	}
+	labelFilterMacrosGlobal.Store(lfm)
+	if len(*labelFilterMacrosFile) == 0 {
+		return
+	}
+	macrosConfigSuccess.Set(1)
+	macrosConfigTimestamp.Set(fasttime.UnixTimestamp())
+	go func() {
+		for range sighupCh {
+			macrosConfigReloads.Inc()
+			logger.Infof("received SIGHUP; reloading -search.labelFilterMacrosFile=%q...", *labelFilterMacrosFile)
+			lfm, err := loadLabelFilterMacros()
+			if err != nil {
+				macrosConfigReloadErrors.Inc()
+				macrosConfigSuccess.Set(0)
+				logger.Errorf("cannot load the updated -search.labelFilterMacrosFile: %s; preserving the previous macros", err)
+				continue
+			}
+			labelFilterMacrosGlobal.Store(lfm)
+			macrosConfigSuccess.Set(1)
+			macrosConfigTimestamp.Set(fasttime.UnixTimestamp())
+			logger.Infof("successfully reloaded -search.labelFilterMacrosFile=%q", *labelFilterMacrosFile)
+		}
+	}()
+}
+
+var (
+	macrosConfigReloads      = metrics.NewCounter(`vm_label_filter_macros_config_reloads_total`)
+	macrosConfigReloadErrors = metrics.NewCounter(`vm_label_filter_macros_config_reloads_errors_total`)
+	macrosConfigSuccess      = metrics.NewGauge(`vm_label_filter_macros_config_last_reload_successful`, nil)
+	macrosConfigTimestamp    = metrics.NewCounter(`vm_label_filter_macros_config_last_reload_success_timestamp_seconds`)
+)
+
+var labelFilterMacrosGlobal atomic.Pointer[labelFilterMacros]
+
+// labelFilterMacros contains label filter macros keyed by macro name.
+//
+// Every macro value is a series selector without metric name and without `or` filters such as {env="prod",cluster=~"a|b"}.
+type labelFilterMacros struct {
+	m map[string]string
+}
+
+func loadLabelFilterMacros() (*labelFilterMacros, error) {
+	if len(*labelFilterMacrosFile) == 0 {
+		return nil, nil
+	}
+	data, err := fscore.ReadFileOrHTTP(*labelFilterMacrosFile)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %q: %w", *labelFilterMacrosFile, err)
+	}
+	data, err = envtemplate.ReplaceBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot expand environment vars at %q: %w", *labelFilterMacrosFile, err)
+	}
+	lfm, err := parseLabelFilterMacros(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %q: %w", *labelFilterMacrosFile, err)
+	}
+	return lfm, nil
+}
+
+var macroNameRegexp = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+func parseLabelFilterMacros(data []byte) (*labelFilterMacros, error) {
+	var src map[string]string
+	if err := yaml.UnmarshalStrict(data, &src); err != nil {
+		return nil, err
+	}
+	m := make(map[string]string, len(src))
+	for name, filters := range src {
+		if !macroNameRegexp.MatchString(name) {
+			return nil, fmt.Errorf("invalid macro name %q; it must match %q", name, macroNameRegexp)
+		}
+		expr, err := metricsql.Parse(filters)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse macro %q: %w", name, err)
+		}
+		me, ok := expr.(*metricsql.MetricExpr)
+		if !ok || len(me.LabelFilterss) != 1 {
+			return nil, fmt.Errorf("macro %q must contain label filters without `or` such as {env=\"prod\"}; got %q", name, filters)
+		}
+		for _, lf := range me.LabelFilterss[0] {
+			if lf.Label == "__name__" {
+				return nil, fmt.Errorf("macro %q mustn't contain metric name filter; got %q", name, filters)
+			}
+		}
+		m[name] = string(me.AppendString(nil))
+	}
+	return &labelFilterMacros{
+		m: m,
+	}, nil
+}
+
+// ExpandLabelFilterMacros expands $name references to label filter macros from -search.labelFilterMacrosFile in q.
+//
+// For example, foo{$prod,job="bar"} is expanded into foo{env="prod",job="bar"} if the `prod` macro is set to {env="prod"}.
+// Standalone $prod is expanded into {env="prod"}.
+func ExpandLabelFilterMacros(q string) (string, error) {
+	return labelFilterMacrosGlobal.Load().expand(q)
+}
+
+// macroIdentPrefix is the prefix for WITH template names used for macros expansion.
+const macroIdentPrefix = "__label_filter_macro_"
+
+func (lfm *labelFilterMacros) expand(q string) (string, error) {
+	if lfm == nil || !strings.Contains(q, "$") {
+		// Fast path - nothing to expand.
+		return q, nil
+	}
+
+	// Substitute $name with WITH template references and then put the referenced macros into WITH expression in front of q,
+	// so MetricsQL parser merges macros' label filters with the rest of label filters.
+	// See https://docs.victoriametrics.com/metricsql/#with-templates
+	var b strings.Builder
+	usedNames := make(map[string]struct{})
+	s := q
+	for len(s) > 0 {
+		n := strings.IndexAny(s, "$\"'`#")
+		if n < 0 {
+			b.WriteString(s)
+			break
+		}
+		b.WriteString(s[:n])
+		s = s[n:]
+		switch s[0] {
+		case '$':
+			name := getMacroName(s[1:])
+			if name == "" {
+				b.WriteByte('$')
+				s = s[1:]
+				continue
+			}
+			if _, ok := lfm.m[name]; !ok {
+				return "", fmt.Errorf("unknown label filter macro $%s; see -search.labelFilterMacrosFile", name)
+			}
+			usedNames[name] = struct{}{}
+			b.WriteString(macroIdentPrefix)
+			b.WriteString(name)
+			s = s[1+len(name):]
+		case '#':
+			// Skip comment till the end of line
+			n = strings.IndexByte(s, '\n')
+			if n < 0 {
+				n = len(s) - 1
+			}
+			b.WriteString(s[:n+1])
+			s = s[n+1:]
+		default:
+			// Skip string literal
+			n = getStringLiteralLen(s)
+			b.WriteString(s[:n])
+			s = s[n:]
+		}
+	}
+	if len(usedNames) == 0 {
+		return q, nil
+	}
+
+	names := make([]string, 0, len(usedNames))
+	for name := range usedNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var with strings.Builder
+	with.WriteString("WITH (")
+	for i, name := range names {
+		if i > 0 {
+			with.WriteString(", ")
+		}
+		fmt.Fprintf(&with, "%s%s = %s", macroIdentPrefix, name, lfm.m[name])
+	}
+	with.WriteString(") ")
+	with.WriteString(b.String())
+	return with.String(), nil
+}
+
+func getMacroName(s string) string {
+	n := 0
+	for n < len(s) {
+		c := s[n]
+		if c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (n > 0 && c >= '0' && c <= '9') {
+			n++
+			continue
+		}
+		break
+	}
+	return s[:n]
+}
+
+// getStringLiteralLen returns the length of string literal at the start of s including quotes.
+//
+// It returns len(s) if the string literal isn't closed.
+func getStringLiteralLen(s string) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quote != '`' {
+				// Skip escaped char
+				i++
+			}
+		case quote:
+			return i + 1
+		}
+	}
+	return len(s)
+}
@@ -0,0 +1,99 @@
+package searchutils
+
+import (
+	"testing"
+
+	"github.com/VictoriaMetrics/metricsql"
+)
+
+func TestParseLabelFilterMacrosFailure(t *testing.T) {
+	f := func(data string) {
+		t.Helper()
+		if _, err := parseLabelFilterMacros([]byte(data)); err == nil {
+			t.Fatalf("expecting non-nil error for %q", data)
+		}
+	}
+
+	// invalid yaml
+	f(`foo`)
+	f(`prod: [1, 2]`)
+
+	// invalid macro name
+	f(`foo-bar: '{env="prod"}'`)
+	f(`1foo: '{env="prod"}'`)
+
+	// invalid label filters
+	f(`prod: '{env="prod"'`)
+	f(`prod: 'rate(foo)'`)
+	f(`prod: '{env="prod" or env="dev"}'`)
+	f(`prod: 'foo{env="prod"}'`)
+}
+
+func TestLabelFilterMacrosExpandSuccess(t *testing.T) {
+	lfm, err := parseLabelFilterMacros([]byte(`
+prod: '{env="prod",cluster=~"a|b"}'
+dev: '{env="dev"}'
+`))
+	if err != nil {
+		t.Fatalf("cannot parse macros: %s", err)
+	}
+
+	f := func(q, resultExpected string) {
+		t.Helper()
+		qExpanded, err := lfm.expand(q)
+		if err != nil {
+			t.Fatalf("unexpected error when expanding %q: %s", q, err)
+		}
+		expr, err := metricsql.Parse(qExpanded)
+		if err != nil {
+			t.Fatalf("cannot parse expanded query %q: %s", qExpanded, err)
+		}
+		result := string(expr.AppendString(nil))
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %q\ngot\n%s\nwant\n%s", q, result, resultExpected)
+		}
+	}
+
+	// no macros
+	f(`foo`, `foo`)
+	f(`label_replace(foo, "a", "$1", "b", "(.+)$")`, `label_replace(foo, "a", "$1", "b", "(.+)$")`)
+
+	// macros inside label filters
+	f(`foo{$prod}`, `foo{env="prod",cluster=~"a|b"}`)
+	f(`foo{job="bar", $prod}`, `foo{job="bar",env="prod",cluster=~"a|b"}`)
+	f(`sum(rate(foo{$prod}[5m])) / sum(rate(bar{$dev}[5m]))`, `sum(rate(foo{env="prod",cluster=~"a|b"}[5m])) / sum(rate(bar{env="dev"}[5m]))`)
+
+	// standalone macro
+	f(`rate($dev[5m])`, `rate({env="dev"}[5m])`)
+
+	// macros inside string literals and comments are left as is
+	f(`label_set(foo{$dev}, "a", "$prod")`, `label_set(foo{env="dev"}, "a", "$prod")`)
+	f("foo{a=`$prod`, $dev}", `foo{a="$prod",env="dev"}`)
+	f(`foo{a="\"$prod", $dev}`, `foo{a="\"$prod",env="dev"}`)
+	f("foo{$dev} # $unknown\n", `foo{env="dev"}`)
+
+	// macros inside WITH templates
+	f(`WITH (f(x) = sum(rate(x{$prod}[5m]))) f(foo)`, `sum(rate(foo{env="prod",cluster=~"a|b"}[5m]))`)
+}
+
+func TestLabelFilterMacrosExpandFailure(t *testing.T) {
+	lfm, err := parseLabelFilterMacros([]byte(`prod: '{env="prod"}'`))
+	if err != nil {
+		t.Fatalf("cannot parse macros: %s", err)
+	}
+	if _, err := lfm.expand(`foo{$unknown}`); err == nil {
+		t.Fatalf("expecting non-nil error for unknown macro")
+	}
+}
+
+func TestLabelFilterMacrosExpandNil(t *testing.T) {
+	var lfm *labelFilterMacros
+	q := `foo{$prod}`
+	result, err := lfm.expand(q)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if result != q {
+		t.Fatalf("unexpected result; got %q; want %q", result, q)
+	}
+}
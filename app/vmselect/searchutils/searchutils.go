@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fasttime"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/httputils"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/storage"
 	"github.com/VictoriaMetrics/metricsql"
@@ -21,6 +22,18 @@ var (
 		"See also -search.maxLabelsAPISeries and -search.ignoreExtraFiltersAtLabelsAPI")
 )
 
+// maxQueryDurationRuntime allows changing -search.maxQueryDuration at runtime.
+var maxQueryDurationRuntime = flagutil.NewRuntimeValue("search.maxQueryDuration", maxQueryDuration, func(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("the duration must be positive; got %s", d)
+	}
+	return d, nil
+})
+
 // GetMaxQueryDuration returns the maximum duration for query from r.
 func GetMaxQueryDuration(r *http.Request) time.Duration {
 	dms, err := httputils.GetDuration(r, "timeout", 0)
@@ -28,15 +41,16 @@ func GetMaxQueryDuration(r *http.Request) time.Duration {
 		dms = 0
 	}
 	d := time.Duration(dms) * time.Millisecond
-	if d <= 0 || d > *maxQueryDuration {
-		d = *maxQueryDuration
+	maxDuration := maxQueryDurationRuntime.Get()
+	if d <= 0 || d > maxDuration {
+		d = maxDuration
 	}
 	return d
 }
 
 // GetDeadlineForQuery returns deadline for the given query r.
 func GetDeadlineForQuery(r *http.Request, startTime time.Time) Deadline {
-	dMax := maxQueryDuration.Milliseconds()
+	dMax := maxQueryDurationRuntime.Get().Milliseconds()
 	return getDeadlineWithMaxDuration(r, startTime, dMax, "-search.maxQueryDuration")
 }
 
@@ -180,7 +194,13 @@ func JoinTagFilterss(src, etfs [][]storage.TagFilter) [][]storage.TagFilter {
 }
 
 // ParseMetricSelector parses s containing PromQL metric selector and returns the corresponding LabelFilters.
+//
+// s may contain references to label filter macros. See ExpandLabelFilterMacros.
 func ParseMetricSelector(s string) ([][]storage.TagFilter, error) {
+	s, err := ExpandLabelFilterMacros(s)
+	if err != nil {
+		return nil, err
+	}
 	expr, err := metricsql.Parse(s)
 	if err != nil {
 		return nil, err
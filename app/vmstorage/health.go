@@ -0,0 +1,145 @@
+package vmstorage
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/storage"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/timeutil"
+)
+
+// healthSampleInterval is the interval between ingestion stats samples used for calculating rates at /api/v1/status/health
+const healthSampleInterval = 10 * time.Second
+
+// healthSample contains ingestion stats collected at the given timestamp.
+type healthSample struct {
+	timestamp      time.Time
+	rowsAdded      uint64
+	slowRowInserts uint64
+}
+
+var (
+	healthSamplesLock sync.Mutex
+	healthSamplePrev  healthSample
+	healthSampleCurr  healthSample
+)
+
+func initHealthSampler(strg *storage.Storage) {
+	healthSamplerCh = make(chan struct{})
+	updateHealthSamples(strg)
+	healthSamplerWG.Add(1)
+	go func() {
+		defer healthSamplerWG.Done()
+		d := timeutil.AddJitterToDuration(healthSampleInterval)
+		t := time.NewTicker(d)
+		defer t.Stop()
+		for {
+			select {
+			case <-healthSamplerCh:
+				return
+			case <-t.C:
+			}
+			updateHealthSamples(strg)
+		}
+	}()
+}
+
+func stopHealthSampler() {
+	close(healthSamplerCh)
+	healthSamplerWG.Wait()
+}
+
+var (
+	healthSamplerCh chan struct{}
+	healthSamplerWG sync.WaitGroup
+)
+
+func updateHealthSamples(strg *storage.Storage) {
+	var m storage.Metrics
+	strg.UpdateMetrics(&m)
+	hs := healthSample{
+		timestamp:      time.Now(),
+		rowsAdded:      m.RowsAddedTotal,
+		slowRowInserts: m.SlowRowInserts,
+	}
+
+	healthSamplesLock.Lock()
+	healthSamplePrev = healthSampleCurr
+	healthSampleCurr = hs
+	healthSamplesLock.Unlock()
+}
+
+// getIngestionStats returns ingestion rate in rows per second and the share of slow inserts
+// between the last two samples collected by the health sampler.
+func getIngestionStats() (float64, float64) {
+	healthSamplesLock.Lock()
+	prev := healthSamplePrev
+	curr := healthSampleCurr
+	healthSamplesLock.Unlock()
+
+	if prev.timestamp.IsZero() {
+		return 0, 0
+	}
+	secs := curr.timestamp.Sub(prev.timestamp).Seconds()
+	if secs <= 0 || curr.rowsAdded < prev.rowsAdded {
+		return 0, 0
+	}
+	rowsAdded := curr.rowsAdded - prev.rowsAdded
+	ingestRate := float64(rowsAdded) / secs
+	return ingestRate, getRatio(curr.slowRowInserts-prev.slowRowInserts, rowsAdded)
+}
+
+// getRatio returns n/total or 0 if total is 0.
+func getRatio(n, total uint64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
+
+// getCacheHitRatio returns cache hit ratio for the given number of requests and misses.
+//
+// 1 is returned if there were no requests to the cache.
+func getCacheHitRatio(requests, misses uint64) float64 {
+	if requests == 0 || misses > requests {
+		return 1
+	}
+	return 1 - float64(misses)/float64(requests)
+}
+
+func handleStatusHealth(w http.ResponseWriter, strg *storage.Storage) {
+	statusHealthRequests.Inc()
+
+	var m storage.Metrics
+	strg.UpdateMetrics(&m)
+	ingestRate, slowInsertsRatio := getIngestionStats()
+
+	w.Header().Set("Content-Type", "application/json")
+	WriteStatusHealthResponse(w, &m, strg.IsReadOnly(), ingestRate, slowInsertsRatio, logger.GetLastErrors())
+}
+
+type cacheHitRatio struct {
+	name  string
+	ratio float64
+}
+
+// getCacheHitRatios returns hit ratios for the main storage caches from m.
+func getCacheHitRatios(m *storage.Metrics) []cacheHitRatio {
+	tm := &m.TableMetrics
+	idbm := &m.IndexDBMetrics
+	return []cacheHitRatio{
+		{"storage/tsid", getCacheHitRatio(m.TSIDCacheRequests, m.TSIDCacheMisses)},
+		{"storage/metricIDs", getCacheHitRatio(m.MetricIDCacheRequests, m.MetricIDCacheMisses)},
+		{"storage/metricName", getCacheHitRatio(m.MetricNameCacheRequests, m.MetricNameCacheMisses)},
+		{"storage/indexBlocks", getCacheHitRatio(tm.IndexBlocksCacheRequests, tm.IndexBlocksCacheMisses)},
+		{"indexdb/dataBlocks", getCacheHitRatio(idbm.DataBlocksCacheRequests, idbm.DataBlocksCacheMisses)},
+		{"indexdb/indexBlocks", getCacheHitRatio(idbm.IndexBlocksCacheRequests, idbm.IndexBlocksCacheMisses)},
+		{"indexdb/tagFiltersToMetricIDs", getCacheHitRatio(idbm.TagFiltersToMetricIDsCacheRequests, idbm.TagFiltersToMetricIDsCacheMisses)},
+	}
+}
+
+var statusHealthRequests = metrics.NewCounter(`vm_http_requests_total{path="/api/v1/status/health"}`)
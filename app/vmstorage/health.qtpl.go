@@ -0,0 +1,146 @@
+// Code generated by qtc from "health.qtpl". DO NOT EDIT.
+// See https://github.com/valyala/quicktemplate for details.
+
+//line app/vmstorage/health.qtpl:1
+package vmstorage
+
+//line app/vmstorage/health.qtpl:1
+import (
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/storage"
+)
+
+// StatusHealthResponse generates response for /api/v1/status/health .
+
+//line app/vmstorage/health.qtpl:10
+import (
+	qtio422016 "io"
+
+	qt422016 "github.com/valyala/quicktemplate"
+)
+
+//line app/vmstorage/health.qtpl:10
+var (
+	_ = qtio422016.Copy
+	_ = qt422016.AcquireByteBuffer
+)
+
+//line app/vmstorage/health.qtpl:10
+func StreamStatusHealthResponse(qw422016 *qt422016.Writer, m *storage.Metrics, isReadOnly bool, ingestRate, slowInsertsRatio float64, lastErrors []logger.LastError) {
+//line app/vmstorage/health.qtpl:12
+	tm := &m.TableMetrics
+	idbm := &m.IndexDBMetrics
+	cacheHitRatios := getCacheHitRatios(m)
+
+//line app/vmstorage/health.qtpl:15
+	qw422016.N().S(`{"status":"success","data":{"readOnly":`)
+//line app/vmstorage/health.qtpl:19
+	if isReadOnly {
+//line app/vmstorage/health.qtpl:19
+		qw422016.N().S(`true`)
+//line app/vmstorage/health.qtpl:19
+	} else {
+//line app/vmstorage/health.qtpl:19
+		qw422016.N().S(`false`)
+//line app/vmstorage/health.qtpl:19
+	}
+//line app/vmstorage/health.qtpl:19
+	qw422016.N().S(`,"ingestRate":`)
+//line app/vmstorage/health.qtpl:20
+	qw422016.N().FPrec(ingestRate, 3)
+//line app/vmstorage/health.qtpl:20
+	qw422016.N().S(`,"slowInsertsRatio":`)
+//line app/vmstorage/health.qtpl:21
+	qw422016.N().FPrec(slowInsertsRatio, 3)
+//line app/vmstorage/health.qtpl:21
+	qw422016.N().S(`,"pendingRows":`)
+//line app/vmstorage/health.qtpl:22
+	qw422016.N().DUL(tm.PendingRows)
+//line app/vmstorage/health.qtpl:22
+	qw422016.N().S(`,"pendingIndexItems":`)
+//line app/vmstorage/health.qtpl:23
+	qw422016.N().DUL(idbm.PendingItems)
+//line app/vmstorage/health.qtpl:23
+	qw422016.N().S(`,"activeMerges":`)
+//line app/vmstorage/health.qtpl:24
+	qw422016.N().DUL(tm.ActiveInmemoryMerges + tm.ActiveSmallMerges + tm.ActiveBigMerges + idbm.ActiveInmemoryMerges + idbm.ActiveFileMerges)
+//line app/vmstorage/health.qtpl:24
+	qw422016.N().S(`,"partsCount":`)
+//line app/vmstorage/health.qtpl:25
+	qw422016.N().DUL(tm.InmemoryPartsCount + tm.SmallPartsCount + tm.BigPartsCount)
+//line app/vmstorage/health.qtpl:25
+	qw422016.N().S(`,"cacheHitRatios":{`)
+//line app/vmstorage/health.qtpl:27
+	for i, c := range cacheHitRatios {
+//line app/vmstorage/health.qtpl:28
+		qw422016.N().Q(c.name)
+//line app/vmstorage/health.qtpl:28
+		qw422016.N().S(`:`)
+//line app/vmstorage/health.qtpl:28
+		qw422016.N().FPrec(c.ratio, 3)
+//line app/vmstorage/health.qtpl:29
+		if i+1 < len(cacheHitRatios) {
+//line app/vmstorage/health.qtpl:29
+			qw422016.N().S(`,`)
+//line app/vmstorage/health.qtpl:29
+		}
+//line app/vmstorage/health.qtpl:30
+	}
+//line app/vmstorage/health.qtpl:30
+	qw422016.N().S(`},"lastErrors":[`)
+//line app/vmstorage/health.qtpl:33
+	for i, e := range lastErrors {
+//line app/vmstorage/health.qtpl:33
+		qw422016.N().S(`{"timestamp":`)
+//line app/vmstorage/health.qtpl:35
+		qw422016.N().Q(e.Timestamp.UTC().Format(time.RFC3339))
+//line app/vmstorage/health.qtpl:35
+		qw422016.N().S(`,"location":`)
+//line app/vmstorage/health.qtpl:36
+		qw422016.N().Q(e.Location)
+//line app/vmstorage/health.qtpl:36
+		qw422016.N().S(`,"msg":`)
+//line app/vmstorage/health.qtpl:37
+		qw422016.N().Q(e.Msg)
+//line app/vmstorage/health.qtpl:37
+		qw422016.N().S(`}`)
+//line app/vmstorage/health.qtpl:39
+		if i+1 < len(lastErrors) {
+//line app/vmstorage/health.qtpl:39
+			qw422016.N().S(`,`)
+//line app/vmstorage/health.qtpl:39
+		}
+//line app/vmstorage/health.qtpl:40
+	}
+//line app/vmstorage/health.qtpl:40
+	qw422016.N().S(`]}}`)
+//line app/vmstorage/health.qtpl:44
+}
+
+//line app/vmstorage/health.qtpl:44
+func WriteStatusHealthResponse(qq422016 qtio422016.Writer, m *storage.Metrics, isReadOnly bool, ingestRate, slowInsertsRatio float64, lastErrors []logger.LastError) {
+//line app/vmstorage/health.qtpl:44
+	qw422016 := qt422016.AcquireWriter(qq422016)
+//line app/vmstorage/health.qtpl:44
+	StreamStatusHealthResponse(qw422016, m, isReadOnly, ingestRate, slowInsertsRatio, lastErrors)
+//line app/vmstorage/health.qtpl:44
+	qt422016.ReleaseWriter(qw422016)
+//line app/vmstorage/health.qtpl:44
+}
+
+//line app/vmstorage/health.qtpl:44
+func StatusHealthResponse(m *storage.Metrics, isReadOnly bool, ingestRate, slowInsertsRatio float64, lastErrors []logger.LastError) string {
+//line app/vmstorage/health.qtpl:44
+	qb422016 := qt422016.AcquireByteBuffer()
+//line app/vmstorage/health.qtpl:44
+	WriteStatusHealthResponse(qb422016, m, isReadOnly, ingestRate, slowInsertsRatio, lastErrors)
+//line app/vmstorage/health.qtpl:44
+	qs422016 := string(qb422016.B)
+//line app/vmstorage/health.qtpl:44
+	qt422016.ReleaseByteBuffer(qb422016)
+//line app/vmstorage/health.qtpl:44
+	return qs422016
+//line app/vmstorage/health.qtpl:44
+}
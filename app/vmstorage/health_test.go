@@ -0,0 +1,83 @@
+package vmstorage
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/storage"
+)
+
+func TestGetCacheHitRatio(t *testing.T) {
+	f := func(requests, misses uint64, resultExpected float64) {
+		t.Helper()
+		result := getCacheHitRatio(requests, misses)
+		if result != resultExpected {
+			t.Fatalf("unexpected result for requests=%d, misses=%d; got %v; want %v", requests, misses, result, resultExpected)
+		}
+	}
+
+	f(0, 0, 1)
+	f(10, 0, 1)
+	f(10, 10, 0)
+	f(4, 1, 0.75)
+}
+
+func TestWriteStatusHealth(t *testing.T) {
+	var m storage.Metrics
+	m.TSIDCacheRequests = 4
+	m.TSIDCacheMisses = 1
+	m.TableMetrics.PendingRows = 123
+	m.TableMetrics.SmallPartsCount = 5
+	m.TableMetrics.BigPartsCount = 2
+	m.TableMetrics.ActiveSmallMerges = 1
+	m.IndexDBMetrics.ActiveFileMerges = 2
+	m.IndexDBMetrics.PendingItems = 45
+	lastErrors := []logger.LastError{
+		{
+			Timestamp: time.Unix(1700000000, 0),
+			Location:  "foo.go:12",
+			Msg:       `cannot "open" file`,
+		},
+	}
+
+	var bb bytes.Buffer
+	WriteStatusHealthResponse(&bb, &m, true, 1234.5, 0.01, lastErrors)
+
+	var resp struct {
+		Status string
+		Data   struct {
+			ReadOnly          bool
+			IngestRate        float64
+			SlowInsertsRatio  float64
+			PendingRows       uint64
+			PendingIndexItems uint64
+			ActiveMerges      uint64
+			PartsCount        uint64
+			CacheHitRatios    map[string]float64
+			LastErrors        []struct {
+				Timestamp string
+				Location  string
+				Msg       string
+			}
+		}
+	}
+	if err := json.Unmarshal(bb.Bytes(), &resp); err != nil {
+		t.Fatalf("cannot parse response %s: %s", bb.String(), err)
+	}
+	d := &resp.Data
+	if resp.Status != "success" || !d.ReadOnly || d.IngestRate != 1234.5 || d.SlowInsertsRatio != 0.01 {
+		t.Fatalf("unexpected response: %s", bb.String())
+	}
+	if d.PendingRows != 123 || d.PendingIndexItems != 45 || d.ActiveMerges != 3 || d.PartsCount != 7 {
+		t.Fatalf("unexpected response: %s", bb.String())
+	}
+	if len(d.CacheHitRatios) != 7 || d.CacheHitRatios["storage/tsid"] != 0.75 || d.CacheHitRatios["indexdb/dataBlocks"] != 1 {
+		t.Fatalf("unexpected cache hit ratios in response: %s", bb.String())
+	}
+	if len(d.LastErrors) != 1 || d.LastErrors[0].Msg != `cannot "open" file` || d.LastErrors[0].Timestamp != "2023-11-14T22:13:20Z" {
+		t.Fatalf("unexpected last errors in response: %s", bb.String())
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/VictoriaMetrics/metrics"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/exemplars"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fasttime"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fs"
@@ -52,6 +53,22 @@ var (
 	denyQueriesOutsideRetention = flag.Bool("denyQueriesOutsideRetention", false, "Whether to deny queries outside the configured -retentionPeriod. "+
 		"When set, then /api/v1/query_range would return '503 Service Unavailable' error for queries with 'from' value outside -retentionPeriod. "+
 		"This may be useful when multiple data sources with distinct retentions are hidden behind query-tee")
+	trackScrapeIntervals = flag.Bool("storage.trackScrapeIntervals", false, "Whether to track the observed intervals between the ingested samples per each active time series. "+
+		"The tracked intervals improve the accuracy of automatically chosen lookbehind windows and gap detection in rollup functions "+
+		"and are exposed via /api/v1/status/scrape_intervals. This requires additional memory per each active time series. "+
+		"See https://docs.victoriametrics.com/#scrape-intervals-tracking")
+	valuesXOREncoding = flag.Bool("storage.valuesXOREncoding", false, "Whether to use Gorilla XOR encoding for gauge values when it gives smaller blocks "+
+		"than the default encoding. This may reduce disk space usage for gauges with jittery float values. This option has effect only with -precisionBits=64. "+
+		"Data written with this option cannot be read by VictoriaMetrics versions without XOR encoding support, so it complicates downgrades. "+
+		"See also -storage.timestampsDeltaOfDeltaEncoding")
+	timestampsDeltaOfDeltaEncoding = flag.Bool("storage.timestampsDeltaOfDeltaEncoding", false, "Whether to use Gorilla delta-of-delta encoding for timestamps "+
+		"when it gives smaller blocks than the default encoding. This may reduce disk space usage for samples with jittery scrape intervals. "+
+		"This option has effect only with -precisionBits=64. Data written with this option cannot be read by VictoriaMetrics versions "+
+		"without delta-of-delta encoding support, so it complicates downgrades. See also -storage.valuesXOREncoding")
+	maxDaysForPerDayLabelsSearch = flag.Int("search.maxDaysForPerDayLabelsSearch", 40, "The maximum number of days on the requested time range, "+
+		"which can be searched in the per-day index at /api/v1/labels and /api/v1/label/.../values. The global index is used for longer time ranges. "+
+		"Bigger values may reduce latency of label lookups over long time ranges on installations with big retention and high churn rate "+
+		"at the cost of higher CPU usage. See https://docs.victoriametrics.com/#index-tuning-for-label-lookups")
 	maxHourlySeries = flag.Int("storage.maxHourlySeries", 0, "The maximum number of unique series can be added to the storage during the last hour. "+
 		"Excess series are logged and dropped. This can be useful for limiting series cardinality. See https://docs.victoriametrics.com/#cardinality-limiter . "+
 		"See also -storage.maxDailySeries")
@@ -94,12 +111,17 @@ func Init(resetCacheIfNeeded func(mrs []storage.MetricRow)) {
 
 	resetResponseCacheIfNeeded = resetCacheIfNeeded
 	storage.SetLogNewSeries(*logNewSeries)
+	storage.SetTrackScrapeIntervals(*trackScrapeIntervals)
+	storage.SetValuesXOREncoding(*valuesXOREncoding)
+	storage.SetTimestampsDeltaOfDeltaEncoding(*timestampsDeltaOfDeltaEncoding)
+	storage.SetMaxDaysForPerDayLabelsSearch(*maxDaysForPerDayLabelsSearch)
 	storage.SetRetentionTimezoneOffset(*retentionTimezoneOffset)
 	storage.SetFreeDiskSpaceLimit(minFreeDiskSpaceBytes.N)
 	storage.SetTSIDCacheSize(cacheSizeStorageTSID.IntN())
 	storage.SetTagFiltersCacheSize(cacheSizeIndexDBTagFilters.IntN())
 	mergeset.SetIndexBlocksCacheSize(cacheSizeIndexDBIndexBlocks.IntN())
 	mergeset.SetDataBlocksCacheSize(cacheSizeIndexDBDataBlocks.IntN())
+	exemplars.Init()
 
 	if retentionPeriod.Duration() < 24*time.Hour {
 		logger.Fatalf("-retentionPeriod cannot be smaller than a day; got %s", retentionPeriod)
@@ -110,6 +132,7 @@ func Init(resetCacheIfNeeded func(mrs []storage.MetricRow)) {
 	strg := storage.MustOpenStorage(*DataPath, retentionPeriod.Duration(), *maxHourlySeries, *maxDailySeries)
 	Storage = strg
 	initStaleSnapshotsRemover(strg)
+	initHealthSampler(strg)
 
 	var m storage.Metrics
 	strg.UpdateMetrics(&m)
@@ -149,14 +172,21 @@ var resetResponseCacheIfNeeded func(mrs []storage.MetricRow)
 //
 // The caller should limit the number of concurrent calls to AddRows() in order to limit memory usage.
 func AddRows(mrs []storage.MetricRow) error {
+	_, _, err := AddRowsExt(mrs)
+	return err
+}
+
+// AddRowsExt works the same as AddRows, but also returns the number of rows rejected by the storage
+// together with the reason for the first rejection.
+func AddRowsExt(mrs []storage.MetricRow) (int, string, error) {
 	if Storage.IsReadOnly() {
-		return errReadOnly
+		return 0, "", errReadOnly
 	}
 	resetResponseCacheIfNeeded(mrs)
 	WG.Add(1)
-	Storage.AddRows(mrs, uint8(*precisionBits))
+	rowsRejected, rejectReason := Storage.AddRowsExt(mrs, uint8(*precisionBits))
 	WG.Done()
-	return nil
+	return rowsRejected, rejectReason, nil
 }
 
 var errReadOnly = errors.New("the storage is in read-only mode; check -storage.minFreeDiskSpaceBytes command-line flag value")
@@ -186,6 +216,23 @@ func SearchMetricNames(qt *querytracer.Tracer, tfss []*storage.TagFilters, tr st
 	return metricNames, err
 }
 
+// ForEachMetricName calls f for every unique metric name matching the given tfss on the given tr.
+func ForEachMetricName(qt *querytracer.Tracer, tfss []*storage.TagFilters, tr storage.TimeRange, maxMetrics int, deadline uint64, f func(metricName []byte) error) error {
+	WG.Add(1)
+	err := Storage.ForEachMetricName(qt, tfss, tr, maxMetrics, deadline, f)
+	WG.Done()
+	return err
+}
+
+// ForEachScrapeInterval calls f for each metric name matching tfss on tr with the observed interval between the ingested samples.
+func ForEachScrapeInterval(qt *querytracer.Tracer, tfss []*storage.TagFilters, tr storage.TimeRange, maxMetrics, limit int, deadline uint64,
+	f func(metricName []byte, si *storage.ScrapeInterval) error) error {
+	WG.Add(1)
+	err := Storage.ForEachScrapeInterval(qt, tfss, tr, maxMetrics, limit, deadline, f)
+	WG.Done()
+	return err
+}
+
 // SearchLabelNamesWithFiltersOnTimeRange searches for tag keys matching the given tfss on tr.
 func SearchLabelNamesWithFiltersOnTimeRange(qt *querytracer.Tracer, tfss []*storage.TagFilters, tr storage.TimeRange, maxTagKeys, maxMetrics int, deadline uint64) ([]string, error) {
 	WG.Add(1)
@@ -248,6 +295,7 @@ func Stop() {
 	startTime := time.Now()
 	WG.WaitAndBlock()
 	stopStaleSnapshotsRemover()
+	stopHealthSampler()
 	Storage.MustClose()
 	logger.Infof("successfully closed the storage in %.3f seconds", time.Since(startTime).Seconds())
 
@@ -283,6 +331,10 @@ func RequestHandler(w http.ResponseWriter, r *http.Request) bool {
 		Storage.DebugFlush()
 		return true
 	}
+	if path == "/api/v1/status/health" {
+		handleStatusHealth(w, Storage)
+		return true
+	}
 	prometheusCompatibleResponse := false
 	if path == "/api/v1/admin/tsdb/snapshot" {
 		// Handle Prometheus API - https://prometheus.io/docs/prometheus/latest/querying/api/#snapshot .
@@ -571,6 +623,7 @@ func writeStorageMetrics(w io.Writer, strg *storage.Storage) {
 	metrics.WriteGaugeUint64(w, `vm_cache_entries{type="storage/date_metricID"}`, m.DateMetricIDCacheSize)
 	metrics.WriteGaugeUint64(w, `vm_cache_entries{type="storage/hour_metric_ids"}`, m.HourMetricIDCacheSize)
 	metrics.WriteGaugeUint64(w, `vm_cache_entries{type="storage/next_day_metric_ids"}`, m.NextDayMetricIDCacheSize)
+	metrics.WriteGaugeUint64(w, `vm_cache_entries{type="storage/scrape_intervals"}`, m.ScrapeIntervalCacheSize)
 	metrics.WriteGaugeUint64(w, `vm_cache_entries{type="storage/indexBlocks"}`, tm.IndexBlocksCacheSize)
 	metrics.WriteGaugeUint64(w, `vm_cache_entries{type="indexdb/dataBlocks"}`, idbm.DataBlocksCacheSize)
 	metrics.WriteGaugeUint64(w, `vm_cache_entries{type="indexdb/indexBlocks"}`, idbm.IndexBlocksCacheSize)
@@ -588,6 +641,7 @@ func writeStorageMetrics(w io.Writer, strg *storage.Storage) {
 	metrics.WriteGaugeUint64(w, `vm_cache_size_bytes{type="storage/date_metricID"}`, m.DateMetricIDCacheSizeBytes)
 	metrics.WriteGaugeUint64(w, `vm_cache_size_bytes{type="storage/hour_metric_ids"}`, m.HourMetricIDCacheSizeBytes)
 	metrics.WriteGaugeUint64(w, `vm_cache_size_bytes{type="storage/next_day_metric_ids"}`, m.NextDayMetricIDCacheSizeBytes)
+	metrics.WriteGaugeUint64(w, `vm_cache_size_bytes{type="storage/scrape_intervals"}`, m.ScrapeIntervalCacheSizeBytes)
 	metrics.WriteGaugeUint64(w, `vm_cache_size_bytes{type="indexdb/tagFiltersToMetricIDs"}`, idbm.TagFiltersToMetricIDsCacheSizeBytes)
 	metrics.WriteGaugeUint64(w, `vm_cache_size_bytes{type="storage/regexps"}`, uint64(storage.RegexpCacheSizeBytes()))
 	metrics.WriteGaugeUint64(w, `vm_cache_size_bytes{type="storage/regexpPrefixes"}`, uint64(storage.RegexpPrefixesCacheSizeBytes()))
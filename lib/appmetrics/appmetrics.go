@@ -76,7 +76,7 @@ func writePrometheusMetrics(w io.Writer) {
 	metrics.WriteMetadataIfNeeded(w, "flag", "gauge")
 	flag.VisitAll(func(f *flag.Flag) {
 		lname := strings.ToLower(f.Name)
-		value := f.Value.String()
+		value := flagutil.GetFlagValue(f)
 		if flagutil.IsSecretFlag(lname) {
 			// Do not expose passwords and keys to prometheus.
 			value = "secret"
@@ -90,3 +90,8 @@ func writePrometheusMetrics(w io.Writer) {
 }
 
 var startTime = time.Now()
+
+// GetStartTime returns the process start time.
+func GetStartTime() time.Time {
+	return startTime
+}
@@ -0,0 +1,269 @@
+package actions
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/azremote"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/backupnames"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/common"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/fsremote"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/gcsremote"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/s3remote"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+)
+
+// Replicate replicates the backup from Src to Dst, which may be located at distinct remote storages.
+//
+// Parts are copied server-side if Src and Dst are located at the same remote storage and are accessed with the same credentials.
+// Otherwise parts are streamed from Src to Dst.
+type Replicate struct {
+	// Concurrency is the number of concurrent workers during the replication.
+	Concurrency int
+
+	// Src is the replication source. It must contain complete backup.
+	Src common.RemoteFS
+
+	// Dst is the replication destination.
+	//
+	// If dst contains the manifest from the previous replication, then dst is updated incrementally,
+	// i.e. only the changed parts are transferred.
+	Dst common.RemoteFS
+}
+
+// Run runs the replication with the provided settings.
+func (r *Replicate) Run() error {
+	concurrency := r.Concurrency
+	src := r.Src
+	dst := r.Dst
+
+	if src.String() == dst.String() {
+		return fmt.Errorf("src and dst cannot point to the same backup %s", src)
+	}
+	ok, err := src.HasFile(backupnames.BackupCompleteFilename)
+	if err != nil {
+		return fmt.Errorf("cannot check for `backup complete` file at %s: %w", src, err)
+	}
+	if !ok {
+		return fmt.Errorf("cannot replicate incomplete backup from %s; missing %q file", src, backupnames.BackupCompleteFilename)
+	}
+
+	dstParts, err := readManifest(dst)
+	if err != nil {
+		return err
+	}
+	if err := dst.DeleteFile(backupnames.BackupCompleteFilename); err != nil {
+		return fmt.Errorf("cannot delete `backup complete` file at %s: %w", dst, err)
+	}
+	// Delete the manifest before modifying dst, so the interrupted replication falls back to listing dst parts on the next run.
+	if err := dst.DeleteFile(backupnames.BackupManifestFilename); err != nil {
+		return fmt.Errorf("cannot delete manifest file at %s: %w", dst, err)
+	}
+	srcParts, err := runReplicate(src, dst, dstParts, concurrency)
+	if err != nil {
+		return err
+	}
+	if err := copyMetadata(src, dst); err != nil {
+		return fmt.Errorf("cannot store backup metadata: %w", err)
+	}
+	if err := writeManifest(dst, srcParts); err != nil {
+		return err
+	}
+	if err := dst.CreateFile(backupnames.BackupCompleteFilename, nil); err != nil {
+		return fmt.Errorf("cannot create `backup complete` file at %s: %w", dst, err)
+	}
+	return nil
+}
+
+// readManifest returns parts from the manifest at fs.
+//
+// nil is returned if fs doesn't contain the manifest.
+func readManifest(fs common.RemoteFS) ([]common.Part, error) {
+	ok, err := fs.HasFile(backupnames.BackupManifestFilename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot check for manifest file at %s: %w", fs, err)
+	}
+	if !ok {
+		return nil, nil
+	}
+	data, err := fs.ReadFile(backupnames.BackupManifestFilename)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read manifest file at %s: %w", fs, err)
+	}
+	parts, err := unmarshalManifest(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse manifest file at %s: %w", fs, err)
+	}
+	return parts, nil
+}
+
+func writeManifest(fs common.RemoteFS, parts []common.Part) error {
+	data, err := marshalManifest(parts)
+	if err != nil {
+		return fmt.Errorf("cannot marshal manifest: %w", err)
+	}
+	if err := fs.CreateFile(backupnames.BackupManifestFilename, data); err != nil {
+		return fmt.Errorf("cannot create manifest file at %s: %w", fs, err)
+	}
+	return nil
+}
+
+// manifestPart is a part entry in the manifest file.
+type manifestPart struct {
+	Path     string `json:"path"`
+	FileSize uint64 `json:"file_size"`
+	Offset   uint64 `json:"offset"`
+	Size     uint64 `json:"size"`
+}
+
+func marshalManifest(parts []common.Part) ([]byte, error) {
+	mps := make([]manifestPart, len(parts))
+	for i, p := range parts {
+		mps[i] = manifestPart{
+			Path:     p.Path,
+			FileSize: p.FileSize,
+			Offset:   p.Offset,
+			Size:     p.Size,
+		}
+	}
+	return json.Marshal(mps)
+}
+
+func unmarshalManifest(data []byte) ([]common.Part, error) {
+	var mps []manifestPart
+	if err := json.Unmarshal(data, &mps); err != nil {
+		return nil, err
+	}
+	parts := make([]common.Part, len(mps))
+	for i, mp := range mps {
+		if mp.Path == "" {
+			return nil, fmt.Errorf("missing path for the part #%d", i)
+		}
+		parts[i] = common.Part{
+			Path:     mp.Path,
+			FileSize: mp.FileSize,
+			Offset:   mp.Offset,
+			Size:     mp.Size,
+			// Parts are stored in the manifest only after successful transfer, so their actual size matches the size.
+			ActualSize: mp.Size,
+		}
+	}
+	return parts, nil
+}
+
+// runReplicate replicates parts from src to dst and returns the replicated parts.
+//
+// dstParts must contain parts from dst manifest. Parts are listed at dst if dstParts is nil.
+func runReplicate(src, dst common.RemoteFS, dstParts []common.Part, concurrency int) ([]common.Part, error) {
+	startTime := time.Now()
+
+	logger.Infof("starting backup replication from %s to %s", src, dst)
+
+	srcParts, err := src.ListParts()
+	if err != nil {
+		return nil, fmt.Errorf("cannot list src parts: %w", err)
+	}
+	logger.Infof("obtained %d parts from src %s", len(srcParts), src)
+
+	if dstParts == nil {
+		dstParts, err = dst.ListParts()
+		if err != nil {
+			return nil, fmt.Errorf("cannot list dst parts: %w", err)
+		}
+		logger.Infof("obtained %d parts from dst %s", len(dstParts), dst)
+	} else {
+		logger.Infof("obtained %d parts from the manifest at dst %s", len(dstParts), dst)
+	}
+
+	backupSize := getPartsSize(srcParts)
+	partsToDelete := common.PartsDifference(dstParts, srcParts)
+	deleteSize := getPartsSize(partsToDelete)
+	if err := deleteDstParts(dst, partsToDelete, concurrency); err != nil {
+		return nil, fmt.Errorf("cannot delete unneeded parts at dst: %w", err)
+	}
+
+	partsToCopy := common.PartsDifference(srcParts, dstParts)
+	copySize := getPartsSize(partsToCopy)
+	if canCopyServerSide(src, dst) {
+		if err := copySrcParts(src, dst, partsToCopy, concurrency); err != nil {
+			return nil, fmt.Errorf("cannot server-side copy parts from src to dst: %w", err)
+		}
+	} else {
+		if err := transferSrcParts(src, dst, partsToCopy, concurrency); err != nil {
+			return nil, fmt.Errorf("cannot transfer parts from src to dst: %w", err)
+		}
+	}
+
+	logger.Infof("backup replication from %s to %s is complete; replicated %d bytes in %.3f seconds; deleted %d bytes; transferred %d bytes",
+		src, dst, backupSize, time.Since(startTime).Seconds(), deleteSize, copySize)
+
+	return srcParts, nil
+}
+
+// canCopyServerSide returns true if parts can be copied server-side from src to dst.
+//
+// Server-side copying is performed by dst client, so it is allowed only if src and dst are located
+// at the same bucket of the same remote storage and are accessed with the same credentials.
+func canCopyServerSide(src, dst common.RemoteFS) bool {
+	switch s := src.(type) {
+	case *fsremote.FS:
+		_, ok := dst.(*fsremote.FS)
+		return ok
+	case *gcsremote.FS:
+		d, ok := dst.(*gcsremote.FS)
+		return ok && s.Bucket == d.Bucket && s.CredsFilePath == d.CredsFilePath
+	case *azremote.FS:
+		// Azure credentials are always read from environment variables, so they are the same for src and dst.
+		d, ok := dst.(*azremote.FS)
+		return ok && s.Container == d.Container
+	case *s3remote.FS:
+		d, ok := dst.(*s3remote.FS)
+		return ok && s.Bucket == d.Bucket && s.CustomEndpoint == d.CustomEndpoint && s.CredsFilePath == d.CredsFilePath &&
+			s.ConfigFilePath == d.ConfigFilePath && s.ProfileName == d.ProfileName
+	default:
+		return false
+	}
+}
+
+// transferSrcParts streams partsToTransfer from src to dst.
+func transferSrcParts(src, dst common.RemoteFS, partsToTransfer []common.Part, concurrency int) error {
+	if len(partsToTransfer) == 0 {
+		return nil
+	}
+	transferSize := getPartsSize(partsToTransfer)
+	logger.Infof("transferring %d parts from %s to %s", len(partsToTransfer), src, dst)
+	var bytesTransferred atomic.Uint64
+	return runParallel(concurrency, partsToTransfer, func(p common.Part) error {
+		logger.Infof("transferring %s from %s to %s", &p, src, dst)
+		pr, pw := io.Pipe()
+		downloadErrCh := make(chan error, 1)
+		go func() {
+			err := src.DownloadPart(p, pw)
+			// Close the writer with the download error, so the upload below is interrupted on download errors.
+			_ = pw.CloseWithError(err)
+			downloadErrCh <- err
+		}()
+		sr := &statReader{
+			r:         pr,
+			bytesRead: &bytesTransferred,
+		}
+		uploadErr := dst.UploadPart(p, sr)
+		// Unblock the download if the upload has been stopped before reading all the data.
+		_ = pr.CloseWithError(io.ErrClosedPipe)
+		downloadErr := <-downloadErrCh
+		if uploadErr != nil {
+			return fmt.Errorf("cannot upload %s to %s: %w", &p, dst, uploadErr)
+		}
+		if downloadErr != nil {
+			return fmt.Errorf("cannot download %s from %s: %w", &p, src, downloadErr)
+		}
+		return nil
+	}, func(elapsed time.Duration) {
+		n := bytesTransferred.Load()
+		prc := 100 * float64(n) / float64(transferSize)
+		logger.Infof("transferred %d out of %d bytes (%.2f%%) from %s to %s in %s", n, transferSize, prc, src, dst, elapsed)
+	})
+}
@@ -0,0 +1,129 @@
+package actions
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/azremote"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/common"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/fsremote"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/gcsremote"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/s3remote"
+)
+
+func TestMarshalUnmarshalManifest(t *testing.T) {
+	f := func(parts []common.Part) {
+		t.Helper()
+
+		data, err := marshalManifest(parts)
+		if err != nil {
+			t.Fatalf("cannot marshal manifest: %s", err)
+		}
+		result, err := unmarshalManifest(data)
+		if err != nil {
+			t.Fatalf("cannot unmarshal manifest: %s", err)
+		}
+		if !reflect.DeepEqual(result, parts) {
+			t.Fatalf("unexpected parts after unmarshaling manifest\ngot\n%v\nwant\n%v", result, parts)
+		}
+	}
+
+	f([]common.Part{})
+	f([]common.Part{
+		{Path: "data/small/2024_01/parts.json", FileSize: 10, Offset: 0, Size: 10, ActualSize: 10},
+		{Path: "indexdb/foo/index.bin", FileSize: 30, Offset: 0, Size: 20, ActualSize: 20},
+		{Path: "indexdb/foo/index.bin", FileSize: 30, Offset: 20, Size: 10, ActualSize: 10},
+	})
+}
+
+func TestUnmarshalManifestFailure(t *testing.T) {
+	f := func(data string) {
+		t.Helper()
+
+		if _, err := unmarshalManifest([]byte(data)); err == nil {
+			t.Fatalf("expecting non-nil error for manifest %q", data)
+		}
+	}
+
+	f("")
+	f("{}")
+	f(`[{"file_size":10}]`)
+}
+
+func TestTransferSrcParts(t *testing.T) {
+	src := &fsremote.FS{Dir: t.TempDir()}
+	dst := &fsremote.FS{Dir: t.TempDir()}
+
+	files := map[string]string{
+		"data/foo/index.bin": "foobar",
+		"indexdb/bar.bin":    "",
+		"metadata.json":      `{"a":"b"}`,
+	}
+	var parts []common.Part
+	for path, data := range files {
+		p := common.Part{
+			Path:       path,
+			FileSize:   uint64(len(data)),
+			Size:       uint64(len(data)),
+			ActualSize: uint64(len(data)),
+		}
+		if err := src.UploadPart(p, bytes.NewBufferString(data)); err != nil {
+			t.Fatalf("cannot upload %s: %s", &p, err)
+		}
+		parts = append(parts, p)
+	}
+
+	if err := transferSrcParts(src, dst, parts, 2); err != nil {
+		t.Fatalf("cannot transfer parts: %s", err)
+	}
+
+	dstParts, err := dst.ListParts()
+	if err != nil {
+		t.Fatalf("cannot list dst parts: %s", err)
+	}
+	if len(dstParts) != len(parts) {
+		t.Fatalf("unexpected number of parts at dst; got %d; want %d", len(dstParts), len(parts))
+	}
+	for _, p := range dstParts {
+		var bb bytes.Buffer
+		if err := dst.DownloadPart(p, &bb); err != nil {
+			t.Fatalf("cannot download %s: %s", &p, err)
+		}
+		if bb.String() != files[p.Path] {
+			t.Fatalf("unexpected contents for %s; got %q; want %q", &p, bb.String(), files[p.Path])
+		}
+	}
+}
+
+func TestCanCopyServerSide(t *testing.T) {
+	f := func(src, dst common.RemoteFS, resultExpected bool) {
+		t.Helper()
+
+		result := canCopyServerSide(src, dst)
+		if result != resultExpected {
+			t.Fatalf("unexpected result for canCopyServerSide(%s, %s); got %v; want %v", src, dst, result, resultExpected)
+		}
+	}
+
+	f(&fsremote.FS{Dir: "/foo"}, &fsremote.FS{Dir: "/bar"}, true)
+	f(&fsremote.FS{Dir: "/foo"}, &s3remote.FS{Bucket: "bucket", Dir: "bar"}, false)
+
+	// gcs
+	f(&gcsremote.FS{Bucket: "bucket", Dir: "foo"}, &gcsremote.FS{Bucket: "bucket", Dir: "bar"}, true)
+	f(&gcsremote.FS{Bucket: "bucket", Dir: "foo"}, &gcsremote.FS{Bucket: "other", Dir: "bar"}, false)
+	f(&gcsremote.FS{Bucket: "bucket", Dir: "foo"}, &gcsremote.FS{CredsFilePath: "/creds", Bucket: "bucket", Dir: "bar"}, false)
+
+	// azblob
+	f(&azremote.FS{Container: "c", Dir: "foo"}, &azremote.FS{Container: "c", Dir: "bar"}, true)
+	f(&azremote.FS{Container: "c", Dir: "foo"}, &azremote.FS{Container: "other", Dir: "bar"}, false)
+
+	// s3
+	f(&s3remote.FS{Bucket: "bucket", Dir: "foo"}, &s3remote.FS{Bucket: "bucket", Dir: "bar"}, true)
+	f(&s3remote.FS{Bucket: "bucket", Dir: "foo"}, &s3remote.FS{Bucket: "other", Dir: "bar"}, false)
+	f(&s3remote.FS{Bucket: "bucket", Dir: "foo"}, &s3remote.FS{CustomEndpoint: "http://minio:9000", Bucket: "bucket", Dir: "bar"}, false)
+	f(&s3remote.FS{Bucket: "bucket", Dir: "foo"}, &s3remote.FS{CredsFilePath: "/creds", Bucket: "bucket", Dir: "bar"}, false)
+	f(&s3remote.FS{Bucket: "bucket", Dir: "foo"}, &s3remote.FS{ConfigFilePath: "/config", Bucket: "bucket", Dir: "bar"}, false)
+	f(&s3remote.FS{Bucket: "bucket", Dir: "foo"}, &s3remote.FS{ProfileName: "dst", Bucket: "bucket", Dir: "bar"}, false)
+	f(&s3remote.FS{Bucket: "bucket", Dir: "foo"}, &gcsremote.FS{Bucket: "bucket", Dir: "bar"}, false)
+}
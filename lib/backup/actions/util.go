@@ -184,6 +184,46 @@ func getPartsSize(parts []common.Part) uint64 {
 
 // NewRemoteFS returns new remote fs from the given path.
 func NewRemoteFS(path string) (common.RemoteFS, error) {
+	return NewRemoteFSWithConfig(path, &RemoteFSConfig{})
+}
+
+// RemoteFSConfig contains credentials and endpoint settings for NewRemoteFSWithConfig.
+//
+// Empty fields are set to the values of the corresponding command-line flags.
+type RemoteFSConfig struct {
+	// CredsFilePath overrides -credsFilePath.
+	CredsFilePath string
+
+	// ConfigFilePath overrides -configFilePath.
+	ConfigFilePath string
+
+	// ConfigProfile overrides -configProfile.
+	ConfigProfile string
+
+	// CustomS3Endpoint overrides -customS3Endpoint.
+	CustomS3Endpoint string
+}
+
+func (cfg *RemoteFSConfig) withDefaults() *RemoteFSConfig {
+	c := *cfg
+	if c.CredsFilePath == "" {
+		c.CredsFilePath = *credsFilePath
+	}
+	if c.ConfigFilePath == "" {
+		c.ConfigFilePath = *configFilePath
+	}
+	if c.ConfigProfile == "" {
+		c.ConfigProfile = *configProfile
+	}
+	if c.CustomS3Endpoint == "" {
+		c.CustomS3Endpoint = *customS3Endpoint
+	}
+	return &c
+}
+
+// NewRemoteFSWithConfig returns new remote fs from the given path with the given cfg.
+func NewRemoteFSWithConfig(path string, cfg *RemoteFSConfig) (common.RemoteFS, error) {
+	cfg = cfg.withDefaults()
 	if len(path) == 0 {
 		return nil, fmt.Errorf("path cannot be empty")
 	}
@@ -210,7 +250,7 @@ func NewRemoteFS(path string) (common.RemoteFS, error) {
 		bucket := dir[:n]
 		dir = dir[n:]
 		fs := &gcsremote.FS{
-			CredsFilePath: *credsFilePath,
+			CredsFilePath: cfg.CredsFilePath,
 			Bucket:        bucket,
 			Dir:           dir,
 		}
@@ -241,13 +281,13 @@ func NewRemoteFS(path string) (common.RemoteFS, error) {
 		bucket := dir[:n]
 		dir = dir[n:]
 		fs := &s3remote.FS{
-			CredsFilePath:         *credsFilePath,
-			ConfigFilePath:        *configFilePath,
-			CustomEndpoint:        *customS3Endpoint,
+			CredsFilePath:         cfg.CredsFilePath,
+			ConfigFilePath:        cfg.ConfigFilePath,
+			CustomEndpoint:        cfg.CustomS3Endpoint,
 			TLSInsecureSkipVerify: *s3TLSInsecureSkipVerify,
 			StorageClass:          s3remote.StringToS3StorageClass(*s3StorageClass),
 			S3ForcePathStyle:      *s3ForcePathStyle,
-			ProfileName:           *configProfile,
+			ProfileName:           cfg.ConfigProfile,
 			Bucket:                bucket,
 			Dir:                   dir,
 		}
@@ -0,0 +1,271 @@
+package actions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/backupnames"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/common"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+)
+
+// Verify verifies the backup at Src.
+type Verify struct {
+	// Concurrency is the number of concurrent workers during the verification.
+	Concurrency int
+
+	// Src is the backup to verify.
+	Src common.RemoteFS
+
+	// SamplePercent is the percentage of randomly selected parts to download during the verification.
+	//
+	// parts.json and metadata.json files are always downloaded.
+	// Only the size is verified for the downloaded data parts, since backups do not contain checksums for them.
+	SamplePercent float64
+}
+
+// Run runs the verification with the provided settings.
+//
+// It logs the verification report and returns an error if the backup is broken.
+func (v *Verify) Run() error {
+	startTime := time.Now()
+	src := v.Src
+
+	logger.Infof("starting verification of the backup at %s", src)
+	var problems []string
+
+	ok, err := src.HasFile(backupnames.BackupCompleteFilename)
+	if err != nil {
+		return fmt.Errorf("cannot check for `backup complete` file at %s: %w", src, err)
+	}
+	if !ok {
+		problems = append(problems, fmt.Sprintf("missing `backup complete` file %q; the backup is incomplete", backupnames.BackupCompleteFilename))
+	}
+
+	parts, err := src.ListParts()
+	if err != nil {
+		return fmt.Errorf("cannot list parts at %s: %w", src, err)
+	}
+	if len(parts) == 0 {
+		return fmt.Errorf("cannot find any parts at %s", src)
+	}
+	common.SortParts(parts)
+	logger.Infof("obtained %d parts with %d bytes from %s", len(parts), getPartsSize(parts), src)
+	problems = append(problems, verifyPartsLayout(parts)...)
+
+	// Download parts.json and metadata.json files, plus a random sample of the remaining parts.
+	partsToDownload := selectPartsForVerification(parts, v.SamplePercent)
+	downloadSize := getPartsSize(partsToDownload)
+	logger.Infof("downloading %d parts with %d bytes from %s for verification", len(partsToDownload), downloadSize, src)
+	var bytesDownloaded atomic.Uint64
+	var problemsLock sync.Mutex
+	partsJSON := make(map[string][]byte)
+	err = runParallel(v.Concurrency, partsToDownload, func(p common.Part) error {
+		// Only parts.json and metadata.json files are kept in memory for the content verification.
+		// Other parts may have up to common.MaxPartSize bytes, so they are streamed and only their size is verified.
+		isJSON := isJSONPart(p)
+		var bb bytes.Buffer
+		var w io.Writer = io.Discard
+		if isJSON {
+			w = &bb
+		}
+		var n atomic.Uint64
+		sw := &statWriter{
+			w: &countingWriter{
+				w: w,
+				n: &n,
+			},
+			bytesWritten: &bytesDownloaded,
+		}
+		if err := src.DownloadPart(p, sw); err != nil {
+			return fmt.Errorf("cannot download %s from %s: %w", &p, src, err)
+		}
+		problemsLock.Lock()
+		defer problemsLock.Unlock()
+		if n := n.Load(); n != p.Size {
+			problems = append(problems, fmt.Sprintf("unexpected size for downloaded %s; got %d bytes; want %d bytes", &p, n, p.Size))
+			return nil
+		}
+		if !isJSON {
+			return nil
+		}
+		switch path.Base(p.Path) {
+		case "parts.json":
+			partsJSON[p.Path] = bb.Bytes()
+		case "metadata.json":
+			if !json.Valid(bb.Bytes()) {
+				problems = append(problems, fmt.Sprintf("invalid JSON in %s", &p))
+			}
+		}
+		return nil
+	}, func(elapsed time.Duration) {
+		n := bytesDownloaded.Load()
+		prc := 100 * float64(n) / float64(downloadSize)
+		logger.Infof("downloaded %d out of %d bytes (%.2f%%) from %s in %s", n, downloadSize, prc, src, elapsed)
+	})
+	if err != nil {
+		return err
+	}
+	problems = append(problems, verifyPartsIndex(parts, partsJSON)...)
+
+	logger.Infof("verification report for the backup at %s: files: %d, parts: %d, size: %d bytes, verified parts: %d, downloaded: %d bytes, problems found: %d, duration: %.3f seconds",
+		src, getFilesCount(parts), len(parts), getPartsSize(parts), len(partsToDownload), bytesDownloaded.Load(), len(problems), time.Since(startTime).Seconds())
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			logger.Errorf("problem in the backup at %s: %s", src, problem)
+		}
+		return fmt.Errorf("found %d problems in the backup at %s; see the log above for details", len(problems), src)
+	}
+	logger.Infof("the backup at %s has been successfully verified", src)
+	return nil
+}
+
+// verifyPartsLayout verifies that parts have expected sizes and cover the whole files without gaps and overlaps.
+//
+// parts must be sorted with common.SortParts.
+func verifyPartsLayout(parts []common.Part) []string {
+	var problems []string
+	for len(parts) > 0 {
+		filePath := parts[0].Path
+		n := 1
+		for n < len(parts) && parts[n].Path == filePath {
+			n++
+		}
+		fileParts := parts[:n]
+		parts = parts[n:]
+
+		fileSize := fileParts[0].FileSize
+		offset := uint64(0)
+		for _, p := range fileParts {
+			if p.ActualSize != p.Size {
+				problems = append(problems, fmt.Sprintf("unexpected size for %s; got %d bytes; want %d bytes", &p, p.ActualSize, p.Size))
+			}
+			if p.FileSize != fileSize {
+				problems = append(problems, fmt.Sprintf("unexpected file size for %s; got %d bytes; want %d bytes", &p, p.FileSize, fileSize))
+			}
+			if p.Offset != offset {
+				problems = append(problems, fmt.Sprintf("unexpected offset for %s; got %d; want %d", &p, p.Offset, offset))
+			}
+			offset = p.Offset + p.Size
+		}
+		if offset != fileSize {
+			problems = append(problems, fmt.Sprintf("parts for the file %q cover %d bytes; want %d bytes", filePath, offset, fileSize))
+		}
+	}
+	return problems
+}
+
+// verifyPartsIndex verifies that all the data parts referred by parts.json files exist in the backup.
+//
+// partsJSON must contain parts.json contents per each parts.json path.
+func verifyPartsIndex(parts []common.Part, partsJSON map[string][]byte) []string {
+	dirs := make(map[string]struct{})
+	for _, p := range parts {
+		dirs[path.Dir(p.Path)] = struct{}{}
+	}
+
+	partsJSONPaths := make([]string, 0, len(partsJSON))
+	for partsJSONPath := range partsJSON {
+		partsJSONPaths = append(partsJSONPaths, partsJSONPath)
+	}
+	sort.Strings(partsJSONPaths)
+
+	var problems []string
+	checkPartDirs := func(partsJSONPath, dir string, partNames []string) {
+		for _, partName := range partNames {
+			partDir := path.Join(dir, partName)
+			if _, ok := dirs[partDir]; !ok {
+				problems = append(problems, fmt.Sprintf("missing part directory %q referred by %q", partDir, partsJSONPath))
+			}
+		}
+	}
+	for _, partsJSONPath := range partsJSONPaths {
+		data := partsJSON[partsJSONPath]
+		dir := path.Dir(partsJSONPath)
+		if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+			// parts.json for indexdb contains the list of part names in the same directory.
+			var partNames []string
+			if err := json.Unmarshal(data, &partNames); err != nil {
+				problems = append(problems, fmt.Sprintf("cannot parse %q: %s", partsJSONPath, err))
+				continue
+			}
+			checkPartDirs(partsJSONPath, dir, partNames)
+			continue
+		}
+
+		// parts.json for data partition contains small and big part names.
+		// It is located in the directory for small parts, while big parts are located in the directory with the same name under `big` dir.
+		var pn struct {
+			Small []string
+			Big   []string
+		}
+		if err := json.Unmarshal(data, &pn); err != nil {
+			problems = append(problems, fmt.Sprintf("cannot parse %q: %s", partsJSONPath, err))
+			continue
+		}
+		checkPartDirs(partsJSONPath, dir, pn.Small)
+		if len(pn.Big) > 0 {
+			parentDir, partitionName := path.Split(dir)
+			if !strings.HasSuffix(parentDir, "small/") {
+				problems = append(problems, fmt.Sprintf("unexpected location for %q with big parts; it must be located in `small` directory", partsJSONPath))
+				continue
+			}
+			bigDir := path.Join(strings.TrimSuffix(parentDir, "small/")+"big", partitionName)
+			checkPartDirs(partsJSONPath, bigDir, pn.Big)
+		}
+	}
+	return problems
+}
+
+func isJSONPart(p common.Part) bool {
+	switch path.Base(p.Path) {
+	case "parts.json", "metadata.json":
+		return true
+	default:
+		return false
+	}
+}
+
+// countingWriter counts the number of bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n *atomic.Uint64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n.Add(uint64(n))
+	return n, err
+}
+
+// selectPartsForVerification returns parts.json and metadata.json parts plus samplePercent of randomly selected remaining parts.
+func selectPartsForVerification(parts []common.Part, samplePercent float64) []common.Part {
+	var result []common.Part
+	for _, p := range parts {
+		if isJSONPart(p) || rand.Float64()*100 < samplePercent {
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+func getFilesCount(parts []common.Part) int {
+	n := 0
+	prevPath := ""
+	for _, p := range parts {
+		if p.Path != prevPath {
+			n++
+			prevPath = p.Path
+		}
+	}
+	return n
+}
@@ -0,0 +1,154 @@
+package actions
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/backupnames"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/common"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/fsremote"
+)
+
+func TestVerifyPartsLayout(t *testing.T) {
+	f := func(parts []common.Part, problemsExpected []string) {
+		t.Helper()
+		common.SortParts(parts)
+		problems := verifyPartsLayout(parts)
+		if !reflect.DeepEqual(problems, problemsExpected) {
+			t.Fatalf("unexpected problems;\ngot\n%q\nwant\n%q", problems, problemsExpected)
+		}
+	}
+
+	// valid parts
+	f([]common.Part{
+		{Path: "data/foo", FileSize: 30, Offset: 0, Size: 10, ActualSize: 10},
+		{Path: "data/foo", FileSize: 30, Offset: 10, Size: 20, ActualSize: 20},
+		{Path: "data/bar", FileSize: 0, Offset: 0, Size: 0, ActualSize: 0},
+	}, nil)
+
+	// broken part
+	f([]common.Part{
+		{Path: "data/foo", FileSize: 10, Offset: 0, Size: 10, ActualSize: 5},
+	}, []string{
+		`unexpected size for part{path: "data/foo", file_size: 10, offset: 0, size: 10}; got 5 bytes; want 10 bytes`,
+	})
+
+	// missing part in the middle of the file
+	f([]common.Part{
+		{Path: "data/foo", FileSize: 30, Offset: 0, Size: 10, ActualSize: 10},
+		{Path: "data/foo", FileSize: 30, Offset: 20, Size: 10, ActualSize: 10},
+	}, []string{
+		`unexpected offset for part{path: "data/foo", file_size: 30, offset: 20, size: 10}; got 20; want 10`,
+	})
+
+	// missing part at the end of the file
+	f([]common.Part{
+		{Path: "data/foo", FileSize: 30, Offset: 0, Size: 10, ActualSize: 10},
+	}, []string{
+		`parts for the file "data/foo" cover 10 bytes; want 30 bytes`,
+	})
+}
+
+func TestVerifyPartsIndex(t *testing.T) {
+	f := func(partsJSON map[string]string, problemsExpected []string) {
+		t.Helper()
+		parts := []common.Part{
+			{Path: "data/small/2024_01/parts.json"},
+			{Path: "data/small/2024_01/17A0000000000001/metadata.json"},
+			{Path: "data/big/2024_01/17A0000000000002/metadata.json"},
+			{Path: "indexdb/17A0000000000003/parts.json"},
+			{Path: "indexdb/17A0000000000003/17A0000000000004/metadata.json"},
+		}
+		m := make(map[string][]byte)
+		for k, v := range partsJSON {
+			m[k] = []byte(v)
+		}
+		problems := verifyPartsIndex(parts, m)
+		if !reflect.DeepEqual(problems, problemsExpected) {
+			t.Fatalf("unexpected problems;\ngot\n%q\nwant\n%q", problems, problemsExpected)
+		}
+	}
+
+	// consistent index
+	f(map[string]string{
+		"data/small/2024_01/parts.json":       `{"Small":["17A0000000000001"],"Big":["17A0000000000002"]}`,
+		"indexdb/17A0000000000003/parts.json": `["17A0000000000004"]`,
+	}, nil)
+
+	// missing parts
+	f(map[string]string{
+		"data/small/2024_01/parts.json":       `{"Small":["17A0000000000001","17A0000000000005"],"Big":["17A0000000000006"]}`,
+		"indexdb/17A0000000000003/parts.json": `["17A0000000000004","17A0000000000007"]`,
+	}, []string{
+		`missing part directory "data/small/2024_01/17A0000000000005" referred by "data/small/2024_01/parts.json"`,
+		`missing part directory "data/big/2024_01/17A0000000000006" referred by "data/small/2024_01/parts.json"`,
+		`missing part directory "indexdb/17A0000000000003/17A0000000000007" referred by "indexdb/17A0000000000003/parts.json"`,
+	})
+
+	// invalid parts.json
+	f(map[string]string{
+		"indexdb/17A0000000000003/parts.json": `["17A0000000000004"`,
+	}, []string{
+		`cannot parse "indexdb/17A0000000000003/parts.json": unexpected end of JSON input`,
+	})
+}
+
+func TestVerifyRun(t *testing.T) {
+	f := func(files map[string]string, backupComplete, resultExpected bool) {
+		t.Helper()
+
+		src := &fsremote.FS{Dir: t.TempDir()}
+		for path, data := range files {
+			p := common.Part{
+				Path:       path,
+				FileSize:   uint64(len(data)),
+				Size:       uint64(len(data)),
+				ActualSize: uint64(len(data)),
+			}
+			if err := src.UploadPart(p, bytes.NewBufferString(data)); err != nil {
+				t.Fatalf("cannot upload %s: %s", &p, err)
+			}
+		}
+		if backupComplete {
+			if err := src.CreateFile(backupnames.BackupCompleteFilename, nil); err != nil {
+				t.Fatalf("cannot create %s: %s", backupnames.BackupCompleteFilename, err)
+			}
+		}
+
+		v := &Verify{
+			Concurrency:   2,
+			Src:           src,
+			SamplePercent: 100,
+		}
+		err := v.Run()
+		if result := err == nil; result != resultExpected {
+			t.Fatalf("unexpected result; got %v; want %v; err: %v", result, resultExpected, err)
+		}
+	}
+
+	files := map[string]string{
+		"data/small/2024_01/parts.json":      `{"Small":["part1"],"Big":[]}`,
+		"data/small/2024_01/part1/index.bin": "foobar",
+		"metadata.json":                      `{"a":"b"}`,
+	}
+
+	// valid backup
+	f(files, true, true)
+
+	// incomplete backup
+	f(files, false, false)
+
+	// missing part referred by parts.json
+	f(map[string]string{
+		"data/small/2024_01/parts.json": `{"Small":["part1"],"Big":[]}`,
+		"metadata.json":                 `{"a":"b"}`,
+	}, true, false)
+
+	// invalid metadata.json
+	f(map[string]string{
+		"data/small/2024_01/parts.json":      `{"Small":["part1"],"Big":[]}`,
+		"data/small/2024_01/part1/index.bin": "foobar",
+		"metadata.json":                      `{"a":`,
+	}, true, false)
+}
@@ -21,4 +21,8 @@ const (
 
 	// BackupMetadataFilename is a filename, which contains metadata for the backup.
 	BackupMetadataFilename = "backup_metadata.ignore"
+
+	// BackupManifestFilename is a filename, which contains the list of parts for the replicated backup.
+	// It is used for incremental replication of backups between remote storages.
+	BackupManifestFilename = "backup_manifest.ignore"
 )
@@ -10,6 +10,7 @@ import (
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/cgroup"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fasttime"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/memory"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/timeutil"
 	"github.com/cespare/xxhash/v2"
 )
@@ -25,6 +26,9 @@ type Cache struct {
 
 	cleanerMustStopCh chan struct{}
 	cleanerStoppedCh  chan struct{}
+
+	// unregisterPressureCallback unregisters shrinkOnMemoryPressure callback.
+	unregisterPressureCallback func()
 }
 
 // NewCache creates new cache.
@@ -54,12 +58,14 @@ func NewCache(getMaxSizeBytes func() int) *Cache {
 		cleanerMustStopCh: make(chan struct{}),
 		cleanerStoppedCh:  make(chan struct{}),
 	}
+	c.unregisterPressureCallback = memory.RegisterPressureCallback(c.shrinkOnMemoryPressure)
 	go c.cleaner()
 	return c
 }
 
 // MustStop frees up resources occupied by c.
 func (c *Cache) MustStop() {
+	c.unregisterPressureCallback()
 	close(c.cleanerMustStopCh)
 	<-c.cleanerStoppedCh
 }
@@ -165,6 +171,14 @@ func (c *Cache) cleanByTimeout() {
 	}
 }
 
+// shrinkOnMemoryPressure removes the least recently accessed entries from c
+// until its size drops below the half of the maximum size when the memory pressure exceeds -memory.pressureThreshold.
+func (c *Cache) shrinkOnMemoryPressure() {
+	for _, shard := range c.shards {
+		shard.shrink()
+	}
+}
+
 func (c *Cache) cleanPerKeyMisses() {
 	for _, shard := range c.shards {
 		shard.cleanPerKeyMisses()
@@ -277,6 +291,16 @@ func (c *cache) cleanByTimeout() {
 	}
 }
 
+func (c *cache) shrink() {
+	maxSizeBytes := c.getMaxSizeBytes() / 2
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.SizeBytes() > maxSizeBytes && len(c.lah) > 0 {
+		c.removeLeastRecentlyAccessedItem()
+	}
+}
+
 func (c *cache) GetBlock(k Key) Block {
 	c.requests.Add(1)
 	var e *cacheEntry
@@ -123,6 +123,39 @@ func TestCache(t *testing.T) {
 	}
 }
 
+func TestCacheShrinkOnMemoryPressure(t *testing.T) {
+	const sizeMaxBytes = 16 * 1024 * 1024
+	getMaxSize := func() int {
+		return sizeMaxBytes
+	}
+	c := NewCache(getMaxSize)
+	defer c.MustStop()
+
+	testCacheSetGet(c, 0)
+	sizeBytes := c.SizeBytes()
+	if sizeBytes == 0 {
+		t.Fatalf("expecting non-zero SizeBytes()")
+	}
+
+	// The cache is smaller than the half of its maximum size, so nothing must be removed.
+	c.shrinkOnMemoryPressure()
+	if n := c.SizeBytes(); n != sizeBytes {
+		t.Fatalf("unexpected SizeBytes(); got %d; want %d", n, sizeBytes)
+	}
+
+	// Shrink the cache, which exceeds the half of its maximum size.
+	sizeMaxBytes1 := 2*sizeBytes - 1
+	c1 := NewCache(func() int {
+		return sizeMaxBytes1
+	})
+	defer c1.MustStop()
+	testCacheSetGet(c1, 0)
+	c1.shrinkOnMemoryPressure()
+	if n := c1.SizeBytes(); n > sizeMaxBytes1/2 {
+		t.Fatalf("too big SizeBytes() after shrinking; got %d; want up to %d", n, sizeMaxBytes1/2)
+	}
+}
+
 func TestCacheConcurrentAccess(_ *testing.T) {
 	const sizeMaxBytes = 16 * 1024 * 1024
 	getMaxSize := func() int {
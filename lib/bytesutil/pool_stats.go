@@ -0,0 +1,69 @@
+package bytesutil
+
+import (
+	"flag"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/VictoriaMetrics/metrics"
+)
+
+var poolStatsSampleRate = flag.Int("poolStatsSampleRate", 0, "Account every N-th object obtained from internal buffer pools such as scrape buffers and ingest row buffers. "+
+	"The estimated number of pool gets, allocations and allocated bytes is exposed via vm_pool_gets_total, vm_pool_allocations_total "+
+	"and vm_pool_allocated_bytes_total metrics, so pooling regressions become visible at self-monitoring. "+
+	"Lower values improve accuracy at the cost of slightly higher CPU usage. Zero value disables the accounting")
+
+// PoolStats accounts allocations for the internal pool of objects.
+//
+// PoolStats is a no-op unless -poolStatsSampleRate is set to a positive value.
+//
+// fastcache-based caches aren't accounted, since they allocate their chunks outside Go heap and re-use them as a ring buffer.
+// Their memory usage is exposed via vm_cache_size_bytes and vm_cache_size_max_bytes metrics.
+type PoolStats struct {
+	name string
+
+	calls atomic.Uint64
+
+	metricsOnce    sync.Once
+	gets           *metrics.Counter
+	allocations    *metrics.Counter
+	allocatedBytes *metrics.Counter
+}
+
+// NewPoolStats returns PoolStats for the pool with the given name.
+//
+// The name is exposed in the `pool` label of vm_pool_* metrics.
+func NewPoolStats(name string) *PoolStats {
+	return &PoolStats{
+		name: name,
+	}
+}
+
+// Update registers a single object obtained from the pool.
+//
+// allocatedBytes must contain the number of bytes allocated for the object. Zero value means the object was re-used from the pool.
+func (ps *PoolStats) Update(allocatedBytes int) {
+	sampleRate := *poolStatsSampleRate
+	if sampleRate <= 0 {
+		return
+	}
+	n := ps.calls.Add(1)
+	if n%uint64(sampleRate) != 0 {
+		return
+	}
+	ps.metricsOnce.Do(ps.initMetrics)
+
+	// Scale the sampled values, so the exposed counters estimate the real number of calls.
+	ps.gets.Add(sampleRate)
+	if allocatedBytes > 0 {
+		ps.allocations.Add(sampleRate)
+		ps.allocatedBytes.AddInt64(int64(allocatedBytes) * int64(sampleRate))
+	}
+}
+
+func (ps *PoolStats) initMetrics() {
+	ps.gets = metrics.GetOrCreateCounter(fmt.Sprintf(`vm_pool_gets_total{pool=%q}`, ps.name))
+	ps.allocations = metrics.GetOrCreateCounter(fmt.Sprintf(`vm_pool_allocations_total{pool=%q}`, ps.name))
+	ps.allocatedBytes = metrics.GetOrCreateCounter(fmt.Sprintf(`vm_pool_allocated_bytes_total{pool=%q}`, ps.name))
+}
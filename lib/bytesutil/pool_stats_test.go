@@ -0,0 +1,47 @@
+package bytesutil
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPoolStats(t *testing.T) {
+	f := func(sampleRate int, allocations []int, getsExpected, allocationsExpected, allocatedBytesExpected uint64) {
+		t.Helper()
+
+		origSampleRate := *poolStatsSampleRate
+		*poolStatsSampleRate = sampleRate
+		defer func() {
+			*poolStatsSampleRate = origSampleRate
+		}()
+
+		ps := NewPoolStats(fmt.Sprintf("%s_%d", t.Name(), sampleRate))
+		for _, n := range allocations {
+			ps.Update(n)
+		}
+		if sampleRate <= 0 {
+			if ps.gets != nil {
+				t.Fatalf("unexpected metrics registered with disabled accounting")
+			}
+			return
+		}
+		if n := ps.gets.Get(); n != getsExpected {
+			t.Fatalf("unexpected gets; got %d; want %d", n, getsExpected)
+		}
+		if n := ps.allocations.Get(); n != allocationsExpected {
+			t.Fatalf("unexpected allocations; got %d; want %d", n, allocationsExpected)
+		}
+		if n := ps.allocatedBytes.Get(); n != allocatedBytesExpected {
+			t.Fatalf("unexpected allocated bytes; got %d; want %d", n, allocatedBytesExpected)
+		}
+	}
+
+	// disabled accounting
+	f(0, []int{10, 0, 20}, 0, 0, 0)
+
+	// account every call
+	f(1, []int{10, 0, 20}, 3, 2, 30)
+
+	// account every second call
+	f(2, []int{10, 0, 20, 5}, 4, 2, 10)
+}
@@ -1,9 +1,11 @@
 package cgroup
 
 import (
+	"fmt"
 	"os"
 	"runtime/debug"
 	"strconv"
+	"strings"
 )
 
 // GetGOGC returns GOGC value for the currently running process.
@@ -48,16 +50,62 @@ func GetMemoryLimit() int64 {
 	if err == nil {
 		return n
 	}
-	n, err = getMemStatV2("memory.max")
+	n, err = getMemoryLimitV2("/sys/fs/cgroup", "/proc/self/cgroup")
 	if err != nil {
 		return 0
 	}
 	return n
 }
 
-func getMemStatV2(statName string) (int64, error) {
-	// See https: //www.kernel.org/doc/html/latest/admin-guide/cgroup-v2.html#memory-interface-files
-	return getStatGeneric(statName, "/sys/fs/cgroup", "/proc/self/cgroup", "")
+func getMemoryLimitV2(sysfsPrefix, cgroupPath string) (int64, error) {
+	// See https://www.kernel.org/doc/html/latest/admin-guide/cgroup-v2.html#memory-interface-files
+	//
+	// memory.max and memory.high contain "max" string if the corresponding limit isn't set.
+	// In this case getStatGeneric returns an error.
+	n, err := getStatGeneric("memory.max", sysfsPrefix, cgroupPath, "")
+
+	// The kernel throttles the process and aggressively reclaims its memory after exceeding memory.high,
+	// so it must be respected if it is lower than memory.max.
+	high, errHigh := getStatGeneric("memory.high", sysfsPrefix, cgroupPath, "")
+	if errHigh == nil && high > 0 && (err != nil || high < n) {
+		return high, nil
+	}
+	return n, err
+}
+
+// GetMemoryPressure returns the percentage of time over the last 10 seconds when some tasks in the current cgroup were stalled on memory.
+//
+// It is read from cgroup v2 memory.pressure file. See https://docs.kernel.org/accounting/psi.html
+// An error is returned if the memory pressure cannot be determined, e.g. on cgroup v1 or on kernels without PSI support.
+func GetMemoryPressure() (float64, error) {
+	return getMemoryPressure("/sys/fs/cgroup", "/proc/self/cgroup")
+}
+
+func getMemoryPressure(sysfsPrefix, cgroupPath string) (float64, error) {
+	data, err := getFileContents("memory.pressure", sysfsPrefix, cgroupPath, "")
+	if err != nil {
+		return 0, err
+	}
+	return parseMemoryPressure(data)
+}
+
+// parseMemoryPressure parses avg10 value from the `some` line at data in the following format:
+//
+//	some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func parseMemoryPressure(data string) (float64, error) {
+	for _, line := range strings.Split(data, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "some" {
+			continue
+		}
+		s, ok := strings.CutPrefix(fields[1], "avg10=")
+		if !ok {
+			return 0, fmt.Errorf("missing avg10 in %q", line)
+		}
+		return strconv.ParseFloat(s, 64)
+	}
+	return 0, fmt.Errorf("cannot find `some` line in %q", data)
 }
 
 func getMemStat(statName string) (int64, error) {
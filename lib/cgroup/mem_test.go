@@ -32,3 +32,62 @@ func TestGetHierarchicalMemoryLimitFailure(t *testing.T) {
 	}
 	f("testdata/", "testdata/none_existing_folder")
 }
+
+func TestGetMemoryLimitV2Success(t *testing.T) {
+	f := func(sysPath, cgroupPath string, want int64) {
+		t.Helper()
+		got, err := getMemoryLimitV2(sysPath, cgroupPath)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if got != want {
+			t.Fatalf("unexpected result, got: %d, want %d", got, want)
+		}
+	}
+
+	// memory.max without memory.high
+	f("testdata/cgroup", "testdata/self/cgroupv2", 523372036854771712)
+
+	// memory.high with memory.max=max
+	f("testdata/cgroupv2", "testdata/self/cgroupv2", 1073741824)
+}
+
+func TestGetMemoryLimitV2Failure(t *testing.T) {
+	f := func(sysPath, cgroupPath string) {
+		t.Helper()
+		_, err := getMemoryLimitV2(sysPath, cgroupPath)
+		if err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+	f("testdata/none_existing_folder", "testdata/self/cgroupv2")
+}
+
+func TestGetMemoryPressure(t *testing.T) {
+	got, err := getMemoryPressure("testdata/cgroupv2", "testdata/self/cgroupv2")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if got != 12.34 {
+		t.Fatalf("unexpected result, got: %v, want 12.34", got)
+	}
+
+	_, err = getMemoryPressure("testdata/cgroup", "testdata/self/cgroupv2")
+	if err == nil {
+		t.Fatalf("expecting non-nil error for missing memory.pressure")
+	}
+}
+
+func TestParseMemoryPressureFailure(t *testing.T) {
+	f := func(data string) {
+		t.Helper()
+		_, err := parseMemoryPressure(data)
+		if err == nil {
+			t.Fatalf("expecting non-nil error for %q", data)
+		}
+	}
+	f("")
+	f("full avg10=0.00 avg60=0.00 avg300=0.00 total=0")
+	f("some avg60=0.00")
+	f("some avg10=foo avg60=0.00 avg300=0.00 total=0")
+}
@@ -0,0 +1,104 @@
+package encoding
+
+import (
+	"fmt"
+)
+
+// deltaOfDeltaBuckets contains the number of bits for zigzag-encoded delta-of-delta values per each bucket.
+//
+// The bucket i is prefixed with i one bits followed by zero bit, while the last bucket is prefixed with len(deltaOfDeltaBuckets) one bits.
+// The zero delta-of-delta is encoded with a single zero bit.
+var deltaOfDeltaBuckets = [...]int{7, 9, 12, 32, 64}
+
+// marshalInt64DeltaOfDelta appends marshaled a to dst and returns the result.
+//
+// The values are encoded with delta-of-delta encoding from Facebook Gorilla paper - see https://www.vldb.org/pvldb/vol8/p1816-teller.pdf .
+// Every value is stored as the difference between the current delta and the previous delta, so timestamps
+// with regular intervals and small jitter need only a few bits per value.
+// This encoding is lossless.
+//
+// The first value is returned as firstValue and it isn't stored in dst.
+func marshalInt64DeltaOfDelta(dst []byte, a []int64) (result []byte, firstValue int64) {
+	if len(a) == 0 {
+		return dst, 0
+	}
+	firstValue = a[0]
+	bw := bitWriter{
+		b: dst,
+	}
+	prev := firstValue
+	prevDelta := int64(0)
+	for _, v := range a[1:] {
+		delta := v - prev
+		dod := delta - prevDelta
+		prev = v
+		prevDelta = delta
+		if dod == 0 {
+			bw.writeBit(0)
+			continue
+		}
+		// Zigzag encoding moves the sign bit to the lowest bit, so small negative values have many leading zeros.
+		zz := uint64((dod << 1) ^ (dod >> 63))
+		for i, n := range deltaOfDeltaBuckets {
+			if n < 64 && zz >= 1<<uint(n) {
+				continue
+			}
+			// Write i+1 one bits followed by zero bit for all the buckets except the last one.
+			prefixLen := i + 2
+			prefix := uint64(1<<uint(i+1)-1) << 1
+			if i == len(deltaOfDeltaBuckets)-1 {
+				prefixLen = i + 1
+				prefix >>= 1
+			}
+			bw.writeBits(prefix, prefixLen)
+			bw.writeBits(zz, n)
+			break
+		}
+	}
+	return bw.flush(), firstValue
+}
+
+// unmarshalInt64DeltaOfDelta appends itemsCount values unmarshaled from src to dst and returns the result.
+//
+// firstValue must be the value returned from marshalInt64DeltaOfDelta.
+func unmarshalInt64DeltaOfDelta(dst []int64, src []byte, firstValue int64, itemsCount int) ([]int64, error) {
+	if itemsCount < 1 {
+		return nil, fmt.Errorf("itemsCount must be greater than 0; got %d", itemsCount)
+	}
+	br := bitReader{
+		b: src,
+	}
+	dst = append(dst, firstValue)
+	prev := firstValue
+	prevDelta := int64(0)
+	for i := 1; i < itemsCount; i++ {
+		bucket := 0
+		for bucket < len(deltaOfDeltaBuckets) {
+			bit, ok := br.readBits(1)
+			if !ok {
+				return nil, fmt.Errorf("unexpected end of data at value #%d out of %d values", i, itemsCount)
+			}
+			if bit == 0 {
+				break
+			}
+			bucket++
+		}
+		var dod int64
+		if bucket > 0 {
+			n := deltaOfDeltaBuckets[bucket-1]
+			zz, ok := br.readBits(n)
+			if !ok {
+				return nil, fmt.Errorf("cannot read %d bits of delta-of-delta for value #%d out of %d values", n, i, itemsCount)
+			}
+			dod = int64(zz>>1) ^ -int64(zz&1)
+		}
+		delta := prevDelta + dod
+		prev += delta
+		prevDelta = delta
+		dst = append(dst, prev)
+	}
+	if len(br.b) > 1 || (len(br.b) == 1 && br.n == 0) {
+		return nil, fmt.Errorf("unexpected trailing data after %d values: %d bytes", itemsCount, len(br.b))
+	}
+	return dst, nil
+}
@@ -0,0 +1,174 @@
+package encoding
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestMarshalInt64DeltaOfDelta(t *testing.T) {
+	f := func(va []int64, firstValueExpected int64, bExpected string) {
+		t.Helper()
+
+		prefix := []byte("foobar")
+		b, firstValue := marshalInt64DeltaOfDelta(prefix, va)
+		if firstValue != firstValueExpected {
+			t.Fatalf("unexpected firstValue for va=%d; got %d; want %d", va, firstValue, firstValueExpected)
+		}
+		if string(b[:len(prefix)]) != string(prefix) {
+			t.Fatalf("invalid prefix for va=%d; got\n%x; expecting\n%x", va, b[:len(prefix)], prefix)
+		}
+		if fmt.Sprintf("%x", b[len(prefix):]) != bExpected {
+			t.Fatalf("invalid marshaled data for va=%d; got\n%x; expecting\n%s", va, b[len(prefix):], bExpected)
+		}
+	}
+
+	f([]int64{0}, 0, "")
+	f([]int64{5, 5}, 5, "00")
+	f([]int64{5, 5, 5, 5, 5, 5, 5, 5, 5}, 5, "00")
+
+	// dod=-1: control bits 10 followed by zigzag-encoded value 1 in 7 bits
+	f([]int64{5, 4}, 5, "8080")
+
+	// The first delta 1000 is stored in the 12-bit bucket: control bits 1110 followed by zigzag-encoded value 2000.
+	// The second delta equals to the first delta, so it is stored as a single zero bit.
+	f([]int64{1000, 2000, 3000}, 1000, "e7d000")
+
+	// The biggest bucket: control bits 11111 followed by 64 bits
+	f([]int64{0, math.MinInt64}, 0, "fffffffffffffffff8")
+}
+
+func TestMarshalUnmarshalInt64DeltaOfDelta(t *testing.T) {
+	f := func(va []int64) {
+		t.Helper()
+
+		b, firstValue := marshalInt64DeltaOfDelta(nil, va)
+		vaNew, err := unmarshalInt64DeltaOfDelta(nil, b, firstValue, len(va))
+		if err != nil {
+			t.Fatalf("cannot unmarshal data for va=%d, b=%x: %s", va, b, err)
+		}
+		if !reflect.DeepEqual(va, vaNew) {
+			t.Fatalf("invalid unmarshaled data; got\n%d; expecting\n%d", vaNew, va)
+		}
+
+		vaPrefix := []int64{1, 2, 3, 4}
+		vaNew, err = unmarshalInt64DeltaOfDelta(vaPrefix, b, firstValue, len(va))
+		if err != nil {
+			t.Fatalf("cannot unmarshal prefixed data for va=%d, b=%x: %s", va, b, err)
+		}
+		if !reflect.DeepEqual(vaNew[:len(vaPrefix)], vaPrefix) {
+			t.Fatalf("unexpected prefix for va=%d; got\n%d; expecting\n%d", va, vaNew[:len(vaPrefix)], vaPrefix)
+		}
+		if !reflect.DeepEqual(vaNew[len(vaPrefix):], va) {
+			t.Fatalf("invalid unmarshaled prefixed data; got\n%d; expecting\n%d", vaNew[len(vaPrefix):], va)
+		}
+	}
+
+	f([]int64{0})
+	f([]int64{0, 0})
+	f([]int64{1, -3})
+	f([]int64{-5e12, -6e12, -7e12, -8e12, -8.9e12})
+	f([]int64{math.MinInt64, math.MaxInt64, 0, -1, 1, math.MinInt64, math.MinInt64})
+	f([]int64{1 << 63 >> 1, 1, 1 << 62, 2, 3})
+
+	r := rand.New(rand.NewSource(1))
+
+	// Timestamps with jittery scrape interval
+	var va []int64
+	v := int64(1.7e12)
+	for i := 0; i < 8*1024; i++ {
+		v += 15000 + int64(r.NormFloat64()*50)
+		va = append(va, v)
+	}
+	f(va)
+
+	// Timestamps with gaps
+	va = va[:0]
+	for i := 0; i < 8*1024; i++ {
+		v += 15000
+		if r.Intn(100) == 0 {
+			v += int64(r.Intn(1e6))
+		}
+		va = append(va, v)
+	}
+	f(va)
+
+	// Random values
+	va = va[:0]
+	for i := 0; i < 8*1024; i++ {
+		va = append(va, int64(r.Uint64()))
+	}
+	f(va)
+}
+
+func TestUnmarshalInt64DeltaOfDeltaFailure(t *testing.T) {
+	f := func(b []byte, firstValue int64, itemsCount int) {
+		t.Helper()
+
+		_, err := unmarshalInt64DeltaOfDelta(nil, b, firstValue, itemsCount)
+		if err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	b, firstValue := marshalInt64DeltaOfDelta(nil, []int64{0, 1, 0, 123456, -5})
+
+	// zero itemsCount
+	f(b, firstValue, 0)
+
+	// missing data
+	f(b[:len(b)-1], firstValue, 5)
+	f(nil, firstValue, 2)
+
+	// trailing data
+	f(append(b, 0), firstValue, 5)
+	f(b, firstValue, 1)
+
+	// missing delta-of-delta bits
+	f([]byte{0x80}, 0, 2)
+	f([]byte{0xff, 0xff}, 0, 2)
+}
+
+func TestMarshalTimestampsWithDeltaOfDelta(t *testing.T) {
+	f := func(timestamps []int64, precisionBits uint8, mtExpected MarshalType) {
+		t.Helper()
+
+		prefix := []byte("foobar")
+		result, mt, firstTimestamp := MarshalTimestampsWithDeltaOfDelta(prefix, timestamps, precisionBits)
+		if mt != mtExpected {
+			t.Fatalf("unexpected MarshalType for timestamps=%d, precisionBits=%d; got %d; want %d", timestamps, precisionBits, mt, mtExpected)
+		}
+		if string(result[:len(prefix)]) != string(prefix) {
+			t.Fatalf("invalid prefix for timestamps=%d; got\n%x; expecting\n%x", timestamps, result[:len(prefix)], prefix)
+		}
+		timestampsNew, err := UnmarshalTimestamps(nil, result[len(prefix):], mt, firstTimestamp, len(timestamps))
+		if err != nil {
+			t.Fatalf("cannot unmarshal timestamps: %s", err)
+		}
+		if precisionBits == 64 && !reflect.DeepEqual(timestampsNew, timestamps) {
+			t.Fatalf("invalid unmarshaled timestamps; got\n%d; expecting\n%d", timestampsNew, timestamps)
+		}
+
+		// The result mustn't exceed the result of MarshalTimestamps
+		resultDefault, _, _ := MarshalTimestamps(nil, timestamps, precisionBits)
+		if len(result)-len(prefix) > len(resultDefault) {
+			t.Fatalf("too big result for timestamps=%d; got %d bytes; MarshalTimestamps returns %d bytes", timestamps, len(result)-len(prefix), len(resultDefault))
+		}
+	}
+
+	// const and delta const timestamps
+	f([]int64{5, 5, 5, 5}, 64, MarshalTypeConst)
+	f([]int64{1000, 2000, 3000, 4000}, 64, MarshalTypeDeltaConst)
+
+	// timestamps with irregular intervals are better compressed with nearest delta2 encoding
+	f([]int64{1, 20, 2345, 6789, 12342}, 64, MarshalTypeNearestDelta2)
+
+	// timestamps with occasional small jitter around the scrape interval are better compressed with delta-of-delta encoding
+	jittery := []int64{0, 15000, 30000, 45000, 60001, 75000, 90000, 105000, 120000, 135000, 149998, 165000, 180000, 195000, 210000, 225000}
+	f(jittery, 64, MarshalTypeDeltaOfDelta)
+
+	// delta-of-delta encoding isn't used for lossy encoding
+	f(jittery, 32, MarshalTypeNearestDelta2)
+}
@@ -40,13 +40,29 @@ const (
 	// MarshalTypeNearestDelta is used instead of MarshalTypeZSTDNearestDelta
 	// if compression doesn't help.
 	MarshalTypeNearestDelta = MarshalType(6)
+
+	// MarshalTypeXOR is used instead of MarshalTypeZSTDNearestDelta
+	// and MarshalTypeNearestDelta for lossless gauge values
+	// if Gorilla XOR encoding gives smaller result.
+	//
+	// See MarshalValuesWithXOR.
+	MarshalTypeXOR = MarshalType(7)
+
+	// MarshalTypeDeltaOfDelta is used instead of MarshalTypeZSTDNearestDelta2
+	// and MarshalTypeNearestDelta2 for lossless timestamps
+	// if Gorilla delta-of-delta encoding gives smaller result.
+	//
+	// See MarshalTimestampsWithDeltaOfDelta.
+	MarshalTypeDeltaOfDelta = MarshalType(8)
 )
 
 // NeedsValidation returns true if mt may need additional validation for silent data corruption.
 func (mt MarshalType) NeedsValidation() bool {
 	switch mt {
 	case MarshalTypeNearestDelta2,
-		MarshalTypeNearestDelta:
+		MarshalTypeNearestDelta,
+		MarshalTypeXOR,
+		MarshalTypeDeltaOfDelta:
 		return true
 	default:
 		// Other types do not need additional validation,
@@ -58,8 +74,8 @@ func (mt MarshalType) NeedsValidation() bool {
 
 // CheckMarshalType verifies whether the mt is valid.
 func CheckMarshalType(mt MarshalType) error {
-	if mt < 0 || mt > 6 {
-		return fmt.Errorf("MarshalType should be in range [0..6]; got %d", mt)
+	if mt < 0 || mt > 8 {
+		return fmt.Errorf("MarshalType should be in range [0..8]; got %d", mt)
 	}
 	return nil
 }
@@ -83,6 +99,29 @@ func MarshalTimestamps(dst []byte, timestamps []int64, precisionBits uint8) (res
 	return marshalInt64Array(dst, timestamps, precisionBits)
 }
 
+// MarshalTimestampsWithDeltaOfDelta works like MarshalTimestamps, but additionally tries Gorilla delta-of-delta encoding
+// for lossless timestamps and uses it if it gives smaller result.
+//
+// Timestamps with regular intervals and small jitter are usually better compressed with delta-of-delta encoding.
+// The resulting data cannot be unmarshaled by the code, which doesn't support MarshalTypeDeltaOfDelta.
+func MarshalTimestampsWithDeltaOfDelta(dst []byte, timestamps []int64, precisionBits uint8) (result []byte, mt MarshalType, firstTimestamp int64) {
+	dstLen := len(dst)
+	dst, mt, firstTimestamp = marshalInt64Array(dst, timestamps, precisionBits)
+	if precisionBits != 64 || (mt != MarshalTypeZSTDNearestDelta2 && mt != MarshalTypeNearestDelta2) {
+		// Delta-of-delta encoding is lossless, so it cannot be used for lossy encoding.
+		// Constant timestamps and timestamps with constant intervals are compressed better with other encodings.
+		return dst, mt, firstTimestamp
+	}
+	bb := bbPool.Get()
+	bb.B, _ = marshalInt64DeltaOfDelta(bb.B[:0], timestamps)
+	if len(bb.B) < len(dst)-dstLen {
+		dst = append(dst[:dstLen], bb.B...)
+		mt = MarshalTypeDeltaOfDelta
+	}
+	bbPool.Put(bb)
+	return dst, mt, firstTimestamp
+}
+
 // UnmarshalTimestamps unmarshals timestamps from src, appends them to dst
 // and returns the resulting dst.
 //
@@ -104,6 +143,29 @@ func MarshalValues(dst []byte, values []int64, precisionBits uint8) (result []by
 	return marshalInt64Array(dst, values, precisionBits)
 }
 
+// MarshalValuesWithXOR works like MarshalValues, but additionally tries Gorilla XOR encoding
+// for lossless gauge values and uses it if it gives smaller result.
+//
+// Jittery gauges with float values are usually better compressed with XOR encoding.
+// The resulting data cannot be unmarshaled by the code, which doesn't support MarshalTypeXOR.
+func MarshalValuesWithXOR(dst []byte, values []int64, precisionBits uint8) (result []byte, mt MarshalType, firstValue int64) {
+	dstLen := len(dst)
+	dst, mt, firstValue = marshalInt64Array(dst, values, precisionBits)
+	if precisionBits != 64 || (mt != MarshalTypeZSTDNearestDelta && mt != MarshalTypeNearestDelta) {
+		// XOR encoding is lossless, so it cannot be used for lossy encoding.
+		// Counters, constants and delta constants are compressed better with other encodings.
+		return dst, mt, firstValue
+	}
+	bb := bbPool.Get()
+	bb.B, _ = marshalInt64XOR(bb.B[:0], values)
+	if len(bb.B) < len(dst)-dstLen {
+		dst = append(dst[:dstLen], bb.B...)
+		mt = MarshalTypeXOR
+	}
+	bbPool.Put(bb)
+	return dst, mt, firstValue
+}
+
 // UnmarshalValues unmarshals values from src, appends them to dst and returns
 // the resulting dst.
 //
@@ -212,6 +274,18 @@ func unmarshalInt64Array(dst []int64, src []byte, mt MarshalType, firstValue int
 			return nil, fmt.Errorf("cannot unmarshal nearest delta2 data: %w", err)
 		}
 		return dst, nil
+	case MarshalTypeXOR:
+		dst, err = unmarshalInt64XOR(dst, src, firstValue, itemsCount)
+		if err != nil {
+			return nil, fmt.Errorf("cannot unmarshal xor data: %w", err)
+		}
+		return dst, nil
+	case MarshalTypeDeltaOfDelta:
+		dst, err = unmarshalInt64DeltaOfDelta(dst, src, firstValue, itemsCount)
+		if err != nil {
+			return nil, fmt.Errorf("cannot unmarshal delta-of-delta data: %w", err)
+		}
+		return dst, nil
 	case MarshalTypeConst:
 		if len(src) > 0 {
 			return nil, fmt.Errorf("unexpected data left in const encoding: %d bytes", len(src))
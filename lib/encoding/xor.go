@@ -0,0 +1,189 @@
+package encoding
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// marshalInt64XOR appends marshaled a to dst and returns the result.
+//
+// The values are encoded with XOR encoding from Facebook Gorilla paper - see https://www.vldb.org/pvldb/vol8/p1816-teller.pdf .
+// Every value is XOR'ed with the previous value and only the meaningful bits of the result are stored.
+// This encoding is lossless.
+//
+// The first value is returned as firstValue and it isn't stored in dst.
+func marshalInt64XOR(dst []byte, a []int64) (result []byte, firstValue int64) {
+	if len(a) == 0 {
+		return dst, 0
+	}
+	firstValue = a[0]
+	bw := bitWriter{
+		b: dst,
+	}
+	prev := uint64(firstValue)
+	prevLeading := -1
+	prevTrailing := 0
+	for _, v := range a[1:] {
+		x := uint64(v) ^ prev
+		prev = uint64(v)
+		if x == 0 {
+			bw.writeBit(0)
+			continue
+		}
+		bw.writeBit(1)
+		leading := bits.LeadingZeros64(x)
+		trailing := bits.TrailingZeros64(x)
+		if prevLeading >= 0 && leading >= prevLeading && trailing >= prevTrailing {
+			// The meaningful bits fit the previous window.
+			bw.writeBit(0)
+			bw.writeBits(x>>uint(prevTrailing), 64-prevLeading-prevTrailing)
+			continue
+		}
+		meaningful := 64 - leading - trailing
+		bw.writeBit(1)
+		// Values are usually stored as integer mantissas with many leading zeros,
+		// so the number of leading zeros is stored in 6 bits instead of 5 bits from the original paper.
+		bw.writeBits(uint64(leading), 6)
+		// The number of meaningful bits is in the range [1..64], so store it as meaningful-1 in 6 bits.
+		bw.writeBits(uint64(meaningful-1), 6)
+		bw.writeBits(x>>uint(trailing), meaningful)
+		prevLeading = leading
+		prevTrailing = trailing
+	}
+	return bw.flush(), firstValue
+}
+
+// unmarshalInt64XOR appends itemsCount values unmarshaled from src to dst and returns the result.
+//
+// firstValue must be the value returned from marshalInt64XOR.
+func unmarshalInt64XOR(dst []int64, src []byte, firstValue int64, itemsCount int) ([]int64, error) {
+	if itemsCount < 1 {
+		return nil, fmt.Errorf("itemsCount must be greater than 0; got %d", itemsCount)
+	}
+	br := bitReader{
+		b: src,
+	}
+	dst = append(dst, firstValue)
+	prev := uint64(firstValue)
+	prevLeading := -1
+	prevTrailing := 0
+	for i := 1; i < itemsCount; i++ {
+		bit, ok := br.readBits(1)
+		if !ok {
+			return nil, fmt.Errorf("unexpected end of data at value #%d out of %d values", i, itemsCount)
+		}
+		if bit == 0 {
+			dst = append(dst, int64(prev))
+			continue
+		}
+		bit, ok = br.readBits(1)
+		if !ok {
+			return nil, fmt.Errorf("unexpected end of data at value #%d out of %d values", i, itemsCount)
+		}
+		if bit == 1 {
+			leading, ok := br.readBits(6)
+			if !ok {
+				return nil, fmt.Errorf("cannot read the number of leading zeros for value #%d out of %d values", i, itemsCount)
+			}
+			meaningful, ok := br.readBits(6)
+			if !ok {
+				return nil, fmt.Errorf("cannot read the number of meaningful bits for value #%d out of %d values", i, itemsCount)
+			}
+			meaningful++
+			if leading+meaningful > 64 {
+				return nil, fmt.Errorf("invalid number of leading zeros=%d and meaningful bits=%d for value #%d out of %d values", leading, meaningful, i, itemsCount)
+			}
+			prevLeading = int(leading)
+			prevTrailing = 64 - int(leading) - int(meaningful)
+		} else if prevLeading < 0 {
+			return nil, fmt.Errorf("missing the number of meaningful bits for value #%d out of %d values", i, itemsCount)
+		}
+		x, ok := br.readBits(64 - prevLeading - prevTrailing)
+		if !ok {
+			return nil, fmt.Errorf("cannot read meaningful bits for value #%d out of %d values", i, itemsCount)
+		}
+		prev ^= x << uint(prevTrailing)
+		dst = append(dst, int64(prev))
+	}
+	if len(br.b) > 1 || (len(br.b) == 1 && br.n == 0) {
+		return nil, fmt.Errorf("unexpected trailing data after %d values: %d bytes", itemsCount, len(br.b))
+	}
+	return dst, nil
+}
+
+// bitWriter appends bits to b in big-endian order.
+type bitWriter struct {
+	b []byte
+
+	// acc holds n pending bits, which aren't appended to b yet.
+	acc uint64
+	n   int
+}
+
+func (bw *bitWriter) writeBit(bit uint64) {
+	bw.writeBits(bit, 1)
+}
+
+// writeBits writes the lower n bits of v, where n must be in the range [1..64].
+func (bw *bitWriter) writeBits(v uint64, n int) {
+	if n < 64 {
+		v &= (1 << uint(n)) - 1
+	}
+	for n > 0 {
+		free := 64 - bw.n
+		if free > n {
+			free = n
+		}
+		bw.acc = bw.acc<<uint(free) | v>>uint(n-free)
+		bw.n += free
+		n -= free
+		if n < 64 {
+			v &= (1 << uint(n)) - 1
+		}
+		for bw.n >= 8 {
+			bw.n -= 8
+			bw.b = append(bw.b, byte(bw.acc>>uint(bw.n)))
+		}
+	}
+}
+
+// flush appends the pending bits to bw.b padded with zeros and returns the result.
+func (bw *bitWriter) flush() []byte {
+	if bw.n > 0 {
+		bw.b = append(bw.b, byte(bw.acc<<uint(8-bw.n)))
+		bw.n = 0
+	}
+	return bw.b
+}
+
+// bitReader reads bits from b in big-endian order.
+type bitReader struct {
+	b []byte
+
+	// n is the number of already read bits from b[0].
+	n int
+}
+
+// readBits reads n bits, where n must be in the range [0..64].
+func (br *bitReader) readBits(n int) (uint64, bool) {
+	var v uint64
+	for n > 0 {
+		if len(br.b) == 0 {
+			return 0, false
+		}
+		avail := 8 - br.n
+		take := avail
+		if take > n {
+			take = n
+		}
+		chunk := (br.b[0] >> uint(avail-take)) & byte((1<<uint(take))-1)
+		v = v<<uint(take) | uint64(chunk)
+		n -= take
+		br.n += take
+		if br.n == 8 {
+			br.b = br.b[1:]
+			br.n = 0
+		}
+	}
+	return v, true
+}
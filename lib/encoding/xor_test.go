@@ -0,0 +1,174 @@
+package encoding
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestMarshalInt64XOR(t *testing.T) {
+	f := func(va []int64, firstValueExpected int64, bExpected string) {
+		t.Helper()
+
+		prefix := []byte("foobar")
+		b, firstValue := marshalInt64XOR(prefix, va)
+		if firstValue != firstValueExpected {
+			t.Fatalf("unexpected firstValue for va=%d; got %d; want %d", va, firstValue, firstValueExpected)
+		}
+		if string(b[:len(prefix)]) != string(prefix) {
+			t.Fatalf("invalid prefix for va=%d; got\n%x; expecting\n%x", va, b[:len(prefix)], prefix)
+		}
+		if fmt.Sprintf("%x", b[len(prefix):]) != bExpected {
+			t.Fatalf("invalid marshaled data for va=%d; got\n%x; expecting\n%s", va, b[len(prefix):], bExpected)
+		}
+	}
+
+	f([]int64{0}, 0, "")
+	f([]int64{5, 5}, 5, "00")
+	f([]int64{5, 5, 5, 5, 5, 5, 5, 5, 5}, 5, "00")
+
+	// xor=1: control bits 11, leading zeros=63, meaningful bits=1
+	f([]int64{0, 1}, 0, "ff02")
+
+	// The second value re-uses the window of the first value: control bits 10 and a single meaningful bit
+	f([]int64{0, 1, 0}, 0, "ff0340")
+}
+
+func TestMarshalUnmarshalInt64XOR(t *testing.T) {
+	f := func(va []int64) {
+		t.Helper()
+
+		b, firstValue := marshalInt64XOR(nil, va)
+		vaNew, err := unmarshalInt64XOR(nil, b, firstValue, len(va))
+		if err != nil {
+			t.Fatalf("cannot unmarshal data for va=%d, b=%x: %s", va, b, err)
+		}
+		if !reflect.DeepEqual(va, vaNew) {
+			t.Fatalf("invalid unmarshaled data; got\n%d; expecting\n%d", vaNew, va)
+		}
+
+		vaPrefix := []int64{1, 2, 3, 4}
+		vaNew, err = unmarshalInt64XOR(vaPrefix, b, firstValue, len(va))
+		if err != nil {
+			t.Fatalf("cannot unmarshal prefixed data for va=%d, b=%x: %s", va, b, err)
+		}
+		if !reflect.DeepEqual(vaNew[:len(vaPrefix)], vaPrefix) {
+			t.Fatalf("unexpected prefix for va=%d; got\n%d; expecting\n%d", va, vaNew[:len(vaPrefix)], vaPrefix)
+		}
+		if !reflect.DeepEqual(vaNew[len(vaPrefix):], va) {
+			t.Fatalf("invalid unmarshaled prefixed data; got\n%d; expecting\n%d", vaNew[len(vaPrefix):], va)
+		}
+	}
+
+	f([]int64{0})
+	f([]int64{0, 0})
+	f([]int64{1, -3})
+	f([]int64{-5e12, -6e12, -7e12, -8e12, -8.9e12})
+	f([]int64{math.MinInt64, math.MaxInt64, 0, -1, 1, math.MinInt64, math.MinInt64})
+	f([]int64{1 << 63 >> 1, 1, 1 << 62, 2, 3})
+
+	r := rand.New(rand.NewSource(1))
+
+	// Jittery gauge
+	var va []int64
+	v := int64(1e9)
+	for i := 0; i < 8*1024; i++ {
+		v += int64(r.NormFloat64() * 1e3)
+		va = append(va, v)
+	}
+	f(va)
+
+	// Gauge with frequently repeated values
+	va = va[:0]
+	for i := 0; i < 8*1024; i++ {
+		if r.Intn(4) == 0 {
+			v = int64(r.Intn(100))
+		}
+		va = append(va, v)
+	}
+	f(va)
+
+	// Random values
+	va = va[:0]
+	for i := 0; i < 8*1024; i++ {
+		va = append(va, int64(r.Uint64()))
+	}
+	f(va)
+}
+
+func TestUnmarshalInt64XORFailure(t *testing.T) {
+	f := func(b []byte, firstValue int64, itemsCount int) {
+		t.Helper()
+
+		_, err := unmarshalInt64XOR(nil, b, firstValue, itemsCount)
+		if err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	b, firstValue := marshalInt64XOR(nil, []int64{0, 1, 0, 123456, -5})
+
+	// zero itemsCount
+	f(b, firstValue, 0)
+
+	// missing data
+	f(b[:len(b)-1], firstValue, 5)
+	f(nil, firstValue, 2)
+
+	// trailing data
+	f(append(b, 0), firstValue, 5)
+	f(b, firstValue, 1)
+
+	// missing window for the value
+	f([]byte{0x80}, 0, 2)
+
+	// invalid window: leading zeros=63, meaningful bits=64
+	f([]byte{0xff, 0xff, 0, 0, 0, 0, 0, 0, 0, 0}, 0, 2)
+}
+
+func TestMarshalValuesWithXOR(t *testing.T) {
+	f := func(values []int64, precisionBits uint8, mtExpected MarshalType) {
+		t.Helper()
+
+		prefix := []byte("foobar")
+		result, mt, firstValue := MarshalValuesWithXOR(prefix, values, precisionBits)
+		if mt != mtExpected {
+			t.Fatalf("unexpected MarshalType for values=%d, precisionBits=%d; got %d; want %d", values, precisionBits, mt, mtExpected)
+		}
+		if string(result[:len(prefix)]) != string(prefix) {
+			t.Fatalf("invalid prefix for values=%d; got\n%x; expecting\n%x", values, result[:len(prefix)], prefix)
+		}
+		valuesNew, err := UnmarshalValues(nil, result[len(prefix):], mt, firstValue, len(values))
+		if err != nil {
+			t.Fatalf("cannot unmarshal values: %s", err)
+		}
+		if precisionBits == 64 && !reflect.DeepEqual(valuesNew, values) {
+			t.Fatalf("invalid unmarshaled values; got\n%d; expecting\n%d", valuesNew, values)
+		}
+
+		// The result mustn't exceed the result of MarshalValues
+		resultDefault, _, _ := MarshalValues(nil, values, precisionBits)
+		if len(result)-len(prefix) > len(resultDefault) {
+			t.Fatalf("too big result for values=%d; got %d bytes; MarshalValues returns %d bytes", values, len(result)-len(prefix), len(resultDefault))
+		}
+	}
+
+	// const and delta const values
+	f([]int64{5, 5, 5, 5}, 64, MarshalTypeConst)
+	f([]int64{5, 6, 7, 8}, 64, MarshalTypeDeltaConst)
+
+	// counter values
+	f([]int64{1, 20, 2345, 6789, 12342}, 64, MarshalTypeNearestDelta2)
+
+	// gauge with frequently repeated values is better compressed with xor encoding
+	repeated := []int64{5, 5, 5, 5, 6, 6, 6, 6, 5, 5, 5, 5, 5, 6, 6, 6, 5, 5, 5, 5, 5, 5, 6, 6, 6, 6, 5, 5, 5, 5, 5}
+	f(repeated, 64, MarshalTypeXOR)
+
+	// xor encoding isn't used for lossy encoding
+	f(repeated, 32, MarshalTypeNearestDelta)
+
+	// gauge with small deltas is better compressed with nearest delta encoding
+	f([]int64{1, 20, -2345, 678934, 342}, 64, MarshalTypeNearestDelta)
+}
@@ -2,11 +2,19 @@ package envflag
 
 import (
 	"flag"
+	"fmt"
 	"log"
 	"os"
+	"slices"
 	"strings"
 
+	"github.com/VictoriaMetrics/metrics"
+
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/envtemplate"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fs/fscore"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/procutil"
 )
 
 var (
@@ -14,6 +22,11 @@ var (
 		"Command line flag values have priority over values from environment vars. "+
 		"Flags are read only from the command line if this flag isn't set. See https://docs.victoriametrics.com/#environment-variables for more details")
 	prefix = flag.String("envflag.prefix", "", "Prefix for environment variables if -envflag.enable is set")
+
+	configFile = flag.String("configFile", "", "Optional path to YAML file with flag values. The file must contain a mapping from flag names to flag values. "+
+		"Command line flag values have priority over values from the file. The file can contain %{ENV_VAR} placeholders, which are substituted by the corresponding env vars. "+
+		"The file is re-read on SIGHUP signal and the updated values are applied to flags, which can be changed at runtime. "+
+		"See https://docs.victoriametrics.com/#config-file-for-flags")
 )
 
 // Parse parses environment vars and command-line flags.
@@ -37,7 +50,7 @@ func ParseFlagSet(fs *flag.FlagSet, args []string) {
 		log.Fatalf("unprocessed command-line args left: %s; the most likely reason is missing `=` between boolean flag name and value; "+
 			"see https://pkg.go.dev/flag#hdr-Command_line_flag_syntax", fs.Args())
 	}
-	if !*enable {
+	if *configFile == "" && !*enable {
 		return
 	}
 	// Remember explicitly set command-line flags.
@@ -46,12 +59,39 @@ func ParseFlagSet(fs *flag.FlagSet, args []string) {
 		flagsSet[f.Name] = true
 	})
 
+	var configFlags map[string][]string
+	if *configFile != "" {
+		// Register SIGHUP handler before reading the config file, so it is re-read if the signal arrives during the reading.
+		sighupCh := procutil.NewSighupChan()
+
+		m, err := readConfigFile(*configFile)
+		if err != nil {
+			// Do not use lib/logger here, since it is uninitialized yet.
+			log.Fatalf("cannot read -configFile=%q: %s", *configFile, err)
+		}
+		if err := applyConfigFile(fs, m, flagsSet); err != nil {
+			// Do not use lib/logger here, since it is uninitialized yet.
+			log.Fatalf("cannot apply -configFile=%q: %s", *configFile, err)
+		}
+		configFileSuccess.Set(1)
+		go configFileReloader(fs, sighupCh, *configFile, m, flagsSet)
+		configFlags = m
+	}
+
+	if !*enable {
+		return
+	}
+
 	// Obtain the remaining flag values from environment vars.
 	fs.VisitAll(func(f *flag.Flag) {
 		if flagsSet[f.Name] {
 			// The flag is explicitly set via command-line.
 			return
 		}
+		if _, ok := configFlags[f.Name]; ok {
+			// The flag is set via -configFile. It has priority over environment vars.
+			return
+		}
 		// Get flag value from environment var.
 		fname := getEnvFlagName(f.Name)
 		if v, ok := envtemplate.LookupEnv(fname); ok {
@@ -63,6 +103,118 @@ func ParseFlagSet(fs *flag.FlagSet, args []string) {
 	})
 }
 
+var (
+	configFileReloads      = metrics.NewCounter(`vm_flags_config_file_reloads_total`)
+	configFileReloadErrors = metrics.NewCounter(`vm_flags_config_file_reloads_errors_total`)
+	configFileSuccess      = metrics.NewGauge(`vm_flags_config_file_last_reload_successful`, nil)
+)
+
+func readConfigFile(path string) (map[string][]string, error) {
+	data, err := fscore.ReadFileOrHTTP(path)
+	if err != nil {
+		return nil, err
+	}
+	return flagutil.ParseConfigFile(data)
+}
+
+// applyConfigFile sets flag values from m at fs, except of the flags from flagsSet.
+func applyConfigFile(fs *flag.FlagSet, m map[string][]string, flagsSet map[string]bool) error {
+	for name := range m {
+		if fs.Lookup(name) == nil {
+			return fmt.Errorf("unknown flag -%s", name)
+		}
+	}
+	for name, values := range m {
+		if flagsSet[name] {
+			// The flag is explicitly set via command-line.
+			continue
+		}
+		for _, v := range values {
+			if err := fs.Set(name, v); err != nil {
+				return fmt.Errorf("cannot set -%s=%q: %w", name, v, err)
+			}
+		}
+	}
+	return nil
+}
+
+func configFileReloader(fs *flag.FlagSet, sighupCh <-chan os.Signal, path string, m map[string][]string, flagsSet map[string]bool) {
+	for range sighupCh {
+		configFileReloads.Inc()
+		logger.Infof("received SIGHUP; reloading -configFile=%q...", path)
+		mNew, err := readConfigFile(path)
+		if err == nil {
+			err = reloadConfigFile(fs, m, mNew, flagsSet)
+		}
+		if err != nil {
+			configFileReloadErrors.Inc()
+			configFileSuccess.Set(0)
+			logger.Errorf("cannot reload -configFile=%q: %s; preserving the previous flag values", path, err)
+			continue
+		}
+		m = mNew
+		configFileSuccess.Set(1)
+		logger.Infof("successfully reloaded -configFile=%q", path)
+	}
+}
+
+// reloadConfigFile applies flag values from mNew, which differ from the previously applied values at mPrev.
+//
+// Flags removed from mNew are reset to their default values. Only flags registered via flagutil.RegisterReloadableFlag are updated.
+// All the values are validated before applying them, so either all the changed values are applied or none of them.
+func reloadConfigFile(fs *flag.FlagSet, mPrev, mNew map[string][]string, flagsSet map[string]bool) error {
+	for name := range mNew {
+		if fs.Lookup(name) == nil {
+			return fmt.Errorf("unknown flag -%s", name)
+		}
+	}
+	values := make(map[string]string)
+	for name, vs := range mNew {
+		if flagsSet[name] || slices.Equal(mPrev[name], vs) {
+			continue
+		}
+		if !flagutil.IsReloadableFlag(name) || len(vs) != 1 {
+			logger.Warnf("-%s cannot be changed at runtime; restart the service in order to apply the new value from -configFile", name)
+			continue
+		}
+		values[name] = vs[0]
+	}
+	for name := range mPrev {
+		if _, ok := mNew[name]; ok || flagsSet[name] {
+			continue
+		}
+		if !flagutil.IsReloadableFlag(name) {
+			logger.Warnf("-%s cannot be changed at runtime; restart the service in order to reset it to the default value after removing it from -configFile", name)
+			continue
+		}
+		values[name] = getDefaultFlagValue(fs.Lookup(name))
+	}
+	if err := flagutil.SetReloadableFlags(fs, values); err != nil {
+		return err
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		logger.Infof("-%s is set to %q via -configFile", name, values[name])
+	}
+	return nil
+}
+
+// getDefaultFlagValue returns the value for f, which must be used when f is missing in -configFile.
+//
+// This is the value from the corresponding environment var if -envflag.enable is set, or the default value otherwise.
+func getDefaultFlagValue(f *flag.Flag) string {
+	if *enable {
+		if v, ok := envtemplate.LookupEnv(getEnvFlagName(f.Name)); ok {
+			return v
+		}
+	}
+	return f.DefValue
+}
+
 // expandArgs substitutes %{ENV_VAR} placeholders inside args
 // with the corresponding environment variable values.
 func expandArgs(args []string) []string {
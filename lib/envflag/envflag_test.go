@@ -0,0 +1,79 @@
+package envflag
+
+import (
+	"flag"
+	"strconv"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
+)
+
+func TestReloadConfigFile(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	a := fs.Int("envflagTestA", 1, "test flag")
+	b := fs.Int("envflagTestB", 1, "test flag")
+	_ = fs.Int("envflagTestNonReloadable", 1, "test flag")
+
+	parse := func(s string) (int, error) {
+		return strconv.Atoi(s)
+	}
+	rvA := flagutil.NewRuntimeValue("envflagTestA", a, parse)
+	rvB := flagutil.NewRuntimeValue("envflagTestB", b, parse)
+
+	f := func(mPrev, mNew map[string][]string, aExpected, bExpected int, errExpected bool) {
+		t.Helper()
+
+		err := reloadConfigFile(fs, mPrev, mNew, nil)
+		if errExpected != (err != nil) {
+			t.Fatalf("unexpected error: %v; errExpected=%v", err, errExpected)
+		}
+		if n := rvA.Get(); n != aExpected {
+			t.Fatalf("unexpected -envflagTestA value; got %d; want %d", n, aExpected)
+		}
+		if n := rvB.Get(); n != bExpected {
+			t.Fatalf("unexpected -envflagTestB value; got %d; want %d", n, bExpected)
+		}
+	}
+
+	// change both flags
+	f(nil, map[string][]string{
+		"envflagTestA": {"2"},
+		"envflagTestB": {"3"},
+	}, 2, 3, false)
+
+	// invalid value for a single flag mustn't change other flags
+	f(map[string][]string{
+		"envflagTestA": {"2"},
+		"envflagTestB": {"3"},
+	}, map[string][]string{
+		"envflagTestA": {"4"},
+		"envflagTestB": {"foo"},
+	}, 2, 3, true)
+
+	// unknown flag mustn't change other flags
+	f(map[string][]string{
+		"envflagTestA": {"2"},
+		"envflagTestB": {"3"},
+	}, map[string][]string{
+		"envflagTestA":       {"4"},
+		"envflagTestUnknown": {"1"},
+	}, 2, 3, true)
+
+	// non-reloadable flags are ignored
+	f(map[string][]string{
+		"envflagTestA": {"2"},
+		"envflagTestB": {"3"},
+	}, map[string][]string{
+		"envflagTestA":             {"4"},
+		"envflagTestB":             {"3"},
+		"envflagTestNonReloadable": {"5"},
+	}, 4, 3, false)
+
+	// flags removed from the file are reset to default values
+	f(map[string][]string{
+		"envflagTestA": {"4"},
+		"envflagTestB": {"3"},
+	}, map[string][]string{
+		"envflagTestB": {"3"},
+	}, 1, 3, false)
+}
@@ -0,0 +1,219 @@
+package exemplars
+
+import (
+	"flag"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+)
+
+var maxExemplars = flag.Int("storage.maxExemplars", 10000, "The maximum number of the most recently ingested exemplars to keep in memory. "+
+	"Exemplars are ingested via OpenTelemetry protocol and are returned from /api/v1/query_exemplars. Exemplars aren't persisted to disk. "+
+	"Set to zero for disabling exemplars storage. See https://docs.victoriametrics.com/#exemplars")
+
+// Exemplar is an exemplar for a time series.
+//
+// See https://github.com/OpenObservability/OpenMetrics/blob/main/specification/OpenMetrics.md#exemplars
+type Exemplar struct {
+	// Labels contains exemplar labels such as trace_id and span_id.
+	Labels []prompbmarshal.Label
+
+	// Value is the exemplar value.
+	Value float64
+
+	// Timestamp is the exemplar timestamp in milliseconds.
+	Timestamp int64
+}
+
+// SeriesExemplars contains exemplars for a single time series.
+type SeriesExemplars struct {
+	// SeriesLabels contains labels for the time series including __name__.
+	SeriesLabels []prompbmarshal.Label
+
+	// Exemplars contains exemplars for the time series sorted by timestamp.
+	Exemplars []Exemplar
+}
+
+// Init initializes exemplars storage according to -storage.maxExemplars.
+//
+// Exemplars aren't stored until Init is called.
+func Init() {
+	if *maxExemplars <= 0 {
+		return
+	}
+	s := newStorage(*maxExemplars)
+	storageGlobal.Store(s)
+}
+
+// Enabled returns true if exemplars storage is initialized.
+//
+// It can be used for skipping exemplars parsing if they cannot be stored.
+func Enabled() bool {
+	return storageGlobal.Load() != nil
+}
+
+// Add adds exemplar e for the time series with the given seriesLabels.
+//
+// It is safe to modify seriesLabels and e after returning from Add.
+func Add(seriesLabels []prompbmarshal.Label, e *Exemplar) {
+	s := storageGlobal.Load()
+	if s == nil {
+		return
+	}
+	s.add(seriesLabels, e)
+}
+
+// Search returns exemplars on the time range [start..end] for time series, which match the given match func.
+//
+// start and end are in milliseconds. The returned series are sorted by labels.
+func Search(start, end int64, match func(seriesLabels []prompbmarshal.Label) bool) []SeriesExemplars {
+	s := storageGlobal.Load()
+	if s == nil {
+		return nil
+	}
+	return s.search(start, end, match)
+}
+
+var storageGlobal atomic.Pointer[storage]
+
+var (
+	exemplarsAdded = metrics.NewCounter(`vm_exemplars_added_total`)
+	_              = metrics.NewGauge(`vm_exemplars_in_memory`, func() float64 {
+		s := storageGlobal.Load()
+		if s == nil {
+			return 0
+		}
+		return float64(s.len())
+	})
+)
+
+// storage is a ring buffer for the most recently added exemplars.
+type storage struct {
+	mu sync.Mutex
+
+	// items contains up to maxItems exemplars.
+	items []item
+
+	// next is the index in items for the next exemplar to add when items are full.
+	next int
+
+	maxItems int
+}
+
+type item struct {
+	// seriesKey is the canonical representation of seriesLabels.
+	seriesKey    string
+	seriesLabels []prompbmarshal.Label
+	e            Exemplar
+}
+
+func newStorage(maxItems int) *storage {
+	return &storage{
+		maxItems: maxItems,
+	}
+}
+
+func (s *storage) len() int {
+	s.mu.Lock()
+	n := len(s.items)
+	s.mu.Unlock()
+	return n
+}
+
+func (s *storage) add(seriesLabels []prompbmarshal.Label, e *Exemplar) {
+	it := item{
+		seriesLabels: cloneLabels(seriesLabels),
+		e: Exemplar{
+			Labels:    cloneLabels(e.Labels),
+			Value:     e.Value,
+			Timestamp: e.Timestamp,
+		},
+	}
+	it.seriesKey = marshalSeriesKey(it.seriesLabels)
+
+	s.mu.Lock()
+	if len(s.items) < s.maxItems {
+		s.items = append(s.items, it)
+	} else {
+		s.items[s.next] = it
+		s.next++
+		if s.next >= len(s.items) {
+			s.next = 0
+		}
+	}
+	s.mu.Unlock()
+
+	exemplarsAdded.Inc()
+}
+
+func (s *storage) search(start, end int64, match func(seriesLabels []prompbmarshal.Label) bool) []SeriesExemplars {
+	m := make(map[string]*SeriesExemplars)
+	s.mu.Lock()
+	for i := range s.items {
+		it := &s.items[i]
+		if it.e.Timestamp < start || it.e.Timestamp > end {
+			continue
+		}
+		se := m[it.seriesKey]
+		if se == nil {
+			if !match(it.seriesLabels) {
+				continue
+			}
+			se = &SeriesExemplars{
+				SeriesLabels: it.seriesLabels,
+			}
+			m[it.seriesKey] = se
+		}
+		se.Exemplars = append(se.Exemplars, it.e)
+	}
+	s.mu.Unlock()
+
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	result := make([]SeriesExemplars, 0, len(keys))
+	for _, k := range keys {
+		se := m[k]
+		sort.SliceStable(se.Exemplars, func(i, j int) bool {
+			return se.Exemplars[i].Timestamp < se.Exemplars[j].Timestamp
+		})
+		result = append(result, *se)
+	}
+	return result
+}
+
+// cloneLabels returns a copy of labels sorted by name.
+func cloneLabels(labels []prompbmarshal.Label) []prompbmarshal.Label {
+	if len(labels) == 0 {
+		return nil
+	}
+	dst := make([]prompbmarshal.Label, len(labels))
+	for i, label := range labels {
+		dst[i] = prompbmarshal.Label{
+			Name:  strings.Clone(label.Name),
+			Value: strings.Clone(label.Value),
+		}
+	}
+	sort.Slice(dst, func(i, j int) bool {
+		return dst[i].Name < dst[j].Name
+	})
+	return dst
+}
+
+func marshalSeriesKey(labels []prompbmarshal.Label) string {
+	var b strings.Builder
+	for _, label := range labels {
+		b.WriteString(label.Name)
+		b.WriteByte(0)
+		b.WriteString(label.Value)
+		b.WriteByte(0)
+	}
+	return b.String()
+}
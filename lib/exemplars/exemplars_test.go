@@ -0,0 +1,110 @@
+package exemplars
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+)
+
+func TestStorageAddSearch(t *testing.T) {
+	s := newStorage(3)
+
+	series := func(name, job string) []prompbmarshal.Label {
+		return []prompbmarshal.Label{
+			{
+				Name:  "job",
+				Value: job,
+			},
+			{
+				Name:  "__name__",
+				Value: name,
+			},
+		}
+	}
+	exemplar := func(traceID string, value float64, timestamp int64) *Exemplar {
+		return &Exemplar{
+			Labels: []prompbmarshal.Label{
+				{
+					Name:  "trace_id",
+					Value: traceID,
+				},
+			},
+			Value:     value,
+			Timestamp: timestamp,
+		}
+	}
+	matchAll := func(_ []prompbmarshal.Label) bool {
+		return true
+	}
+
+	f := func(start, end int64, match func(seriesLabels []prompbmarshal.Label) bool, resultExpected []SeriesExemplars) {
+		t.Helper()
+
+		result := s.search(start, end, match)
+		if !reflect.DeepEqual(result, resultExpected) {
+			t.Fatalf("unexpected result\ngot\n%v\nwant\n%v", result, resultExpected)
+		}
+	}
+
+	// empty storage
+	f(0, 100, matchAll, []SeriesExemplars{})
+
+	s.add(series("foo", "a"), exemplar("t2", 2, 20))
+	s.add(series("foo", "a"), exemplar("t1", 1, 10))
+	s.add(series("bar", "b"), exemplar("t3", 3, 30))
+	if n := s.len(); n != 3 {
+		t.Fatalf("unexpected number of exemplars; got %d; want 3", n)
+	}
+
+	// series are sorted by labels and exemplars are sorted by timestamps
+	f(0, 100, matchAll, []SeriesExemplars{
+		{
+			SeriesLabels: cloneLabels(series("bar", "b")),
+			Exemplars:    []Exemplar{*exemplar("t3", 3, 30)},
+		},
+		{
+			SeriesLabels: cloneLabels(series("foo", "a")),
+			Exemplars:    []Exemplar{*exemplar("t1", 1, 10), *exemplar("t2", 2, 20)},
+		},
+	})
+
+	// time range filter
+	f(15, 25, matchAll, []SeriesExemplars{
+		{
+			SeriesLabels: cloneLabels(series("foo", "a")),
+			Exemplars:    []Exemplar{*exemplar("t2", 2, 20)},
+		},
+	})
+
+	// series filter
+	f(0, 100, func(seriesLabels []prompbmarshal.Label) bool {
+		for _, label := range seriesLabels {
+			if label.Name == "job" && label.Value == "b" {
+				return true
+			}
+		}
+		return false
+	}, []SeriesExemplars{
+		{
+			SeriesLabels: cloneLabels(series("bar", "b")),
+			Exemplars:    []Exemplar{*exemplar("t3", 3, 30)},
+		},
+	})
+
+	// the oldest exemplar is evicted when the storage is full
+	s.add(series("bar", "b"), exemplar("t4", 4, 40))
+	if n := s.len(); n != 3 {
+		t.Fatalf("unexpected number of exemplars; got %d; want 3", n)
+	}
+	f(0, 100, matchAll, []SeriesExemplars{
+		{
+			SeriesLabels: cloneLabels(series("bar", "b")),
+			Exemplars:    []Exemplar{*exemplar("t3", 3, 30), *exemplar("t4", 4, 40)},
+		},
+		{
+			SeriesLabels: cloneLabels(series("foo", "a")),
+			Exemplars:    []Exemplar{*exemplar("t1", 1, 10)},
+		},
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/memory"
 	"github.com/VictoriaMetrics/metrics"
@@ -18,6 +19,14 @@ var disableFadvise = flag.Bool("filestream.disableFadvise", false, "Whether to d
 	"The fadvise() syscall prevents from eviction of recently accessed data from OS page cache during background merges and backups. "+
 	"In some rare cases it is better to disable the syscall if it uses too much CPU")
 
+var disableWriteFadvise = flag.Bool("filestream.disableWriteFadvise", false, "Whether to disable fadvise(POSIX_FADV_DONTNEED) syscall for data files written by background merges. "+
+	"By default the written data is dropped from OS page cache, so merges do not evict the data used by queries. "+
+	"It may be better to disable the syscall if the freshly merged data is queried frequently and there is enough RAM for OS page cache")
+
+var preallocateSize = flagutil.NewBytes("filestream.preallocateSize", 0, "The size of chunks for pre-allocating disk space via fallocate() syscall for data files written by background merges. "+
+	"Pre-allocation reduces file fragmentation on some filesystems. The unused pre-allocated space is released when the file is closed. "+
+	"Zero value disables pre-allocation. Pre-allocation is supported only on Linux")
+
 const dontNeedBlockSize = 16 * 1024 * 1024
 
 // ReadCloser is a standard interface for filestream Reader.
@@ -181,6 +190,14 @@ type Writer struct {
 	f  *os.File
 	bw *bufio.Writer
 	st streamTracker
+
+	// size is the number of bytes written to f.
+	size int64
+
+	// preallocatedSize is the size of f pre-allocated via -filestream.preallocateSize.
+	//
+	// It is negative if pre-allocation is disabled for f.
+	preallocatedSize int64
 }
 
 // Path returns the path to r
@@ -218,13 +235,23 @@ func MustCreate(path string, nocache bool) *Writer {
 	if err != nil {
 		logger.Panicf("FATAL: cannot create file %q: %s", path, err)
 	}
-	return newWriter(f, nocache)
+	w := newWriter(f, nocache)
+	if nocache && preallocateSize.N > 0 {
+		// Pre-allocate disk space only for newly created files written by background merges,
+		// since their size is truncated to the written size on close.
+		w.preallocatedSize = 0
+	}
+	return w
 }
 
 func newWriter(f *os.File, nocache bool) *Writer {
 	w := &Writer{
-		f:  f,
-		bw: getBufioWriter(f),
+		f:                f,
+		bw:               getBufioWriter(f),
+		preallocatedSize: -1,
+	}
+	if *disableWriteFadvise {
+		nocache = false
 	}
 	if nocache {
 		w.st.fd = f.Fd()
@@ -241,6 +268,12 @@ func (w *Writer) MustClose() {
 	putBufioWriter(w.bw)
 	w.bw = nil
 
+	if w.preallocatedSize > w.size {
+		// Release the unused pre-allocated space.
+		if err := w.f.Truncate(w.size); err != nil {
+			logger.Panicf("FATAL: cannot truncate file %q to %d bytes: %s", w.f.Name(), w.size, err)
+		}
+	}
 	if err := w.f.Sync(); err != nil {
 		logger.Panicf("FATAL: cannot sync file %q: %d", w.f.Name(), err)
 	}
@@ -262,12 +295,15 @@ var (
 	writtenBytesBuffered = metrics.NewCounter(`vm_filestream_buffered_written_bytes_total`)
 	writtenBytesReal     = metrics.NewCounter(`vm_filestream_real_written_bytes_total`)
 	writersCount         = metrics.NewCounter(`vm_filestream_writers`)
+	preallocatedBytes    = metrics.NewCounter(`vm_filestream_preallocated_bytes_total`)
 )
 
 // Write writes p to the underlying file.
 func (w *Writer) Write(p []byte) (int, error) {
 	writeCallsBuffered.Inc()
+	w.preallocate(len(p))
 	n, err := w.bw.Write(p)
+	w.size += int64(n)
 	writtenBytesBuffered.Add(n)
 	if err != nil {
 		return n, err
@@ -278,6 +314,25 @@ func (w *Writer) Write(p []byte) (int, error) {
 	return n, nil
 }
 
+func (w *Writer) preallocate(n int) {
+	if w.preallocatedSize < 0 || w.size+int64(n) <= w.preallocatedSize {
+		return
+	}
+	chunkSize := preallocateSize.N
+	size := w.size + int64(n) - w.preallocatedSize
+	size += chunkSize - 1
+	size -= size % chunkSize
+	if err := fallocate(w.f.Fd(), w.preallocatedSize, size); err != nil {
+		preallocateLogger.Warnf("disabling disk space pre-allocation for %q: %s", w.f.Name(), err)
+		w.preallocatedSize = -1
+		return
+	}
+	w.preallocatedSize += size
+	preallocatedBytes.Add(int(size))
+}
+
+var preallocateLogger = logger.WithThrottler("filestream_preallocate", 5*time.Second)
+
 // MustFlush flushes all the buffered data to file.
 //
 // if isSync is true, then the flushed data is fsynced to the underlying storage.
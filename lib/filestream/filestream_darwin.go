@@ -7,3 +7,8 @@ func (st *streamTracker) adviseDontNeed(n int, fdatasync bool) error {
 func (st *streamTracker) close() error {
 	return nil
 }
+
+func fallocate(_ uintptr, _, _ int64) error {
+	// Disk space pre-allocation isn't supported on this OS.
+	return nil
+}
@@ -62,3 +62,8 @@ func errnoErr(e syscall.Errno) error {
 	}
 	return e
 }
+
+func fallocate(_ uintptr, _, _ int64) error {
+	// Disk space pre-allocation isn't supported on this OS.
+	return nil
+}
@@ -37,3 +37,10 @@ func (st *streamTracker) close() error {
 	}
 	return nil
 }
+
+func fallocate(fd uintptr, offset, size int64) error {
+	if err := unix.Fallocate(int(fd), 0, offset, size); err != nil {
+		return fmt.Errorf("unix.Fallocate(%d, %d) error: %w", offset, size, err)
+	}
+	return nil
+}
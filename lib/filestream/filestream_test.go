@@ -49,3 +49,46 @@ func testWriteRead(t *testing.T, nocache bool, testStr string) {
 	}
 	r.MustClose()
 }
+
+func TestWriterPreallocate(t *testing.T) {
+	f := func(chunkSize int64, nocache bool, testStr string) {
+		t.Helper()
+
+		origPreallocateSize := preallocateSize.N
+		preallocateSize.N = chunkSize
+		defer func() {
+			preallocateSize.N = origPreallocateSize
+		}()
+
+		path := "./preallocate_test.txt"
+		w := MustCreate(path, nocache)
+		defer func() {
+			_ = os.Remove(path)
+		}()
+		for i := 0; i < len(testStr); i += 7 {
+			n := i + 7
+			if n > len(testStr) {
+				n = len(testStr)
+			}
+			if _, err := fmt.Fprintf(w, "%s", testStr[i:n]); err != nil {
+				t.Fatalf("unexpected error when writing testStr: %s", err)
+			}
+		}
+		w.MustClose()
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("cannot read %q: %s", path, err)
+		}
+		if string(data) != testStr {
+			t.Fatalf("unexpected data read: got\n%x; want\n%x", data, testStr)
+		}
+	}
+
+	f(0, true, "foobar")
+	f(10, false, "foobar")
+	f(10, true, "")
+	f(10, true, "foobar")
+	f(10, true, "foobar baz 1234567890 qwerty")
+	f(4096, true, "foobar baz 1234567890 qwerty")
+}
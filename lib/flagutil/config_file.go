@@ -0,0 +1,57 @@
+package flagutil
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/envtemplate"
+)
+
+// ParseConfigFile parses flag values from YAML data.
+//
+// data must contain a mapping from flag names without leading dashes to flag values.
+// Values for array flags may be specified as YAML lists.
+// %{ENV_VAR} placeholders in data are substituted with the corresponding environment variable values.
+//
+// The returned map contains flag values per each flag name.
+func ParseConfigFile(data []byte) (map[string][]string, error) {
+	data, err := envtemplate.ReplaceBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot expand environment vars: %w", err)
+	}
+	var m map[string]configFileValue
+	if err := yaml.UnmarshalStrict(data, &m); err != nil {
+		return nil, fmt.Errorf("cannot parse flags: %w", err)
+	}
+	result := make(map[string][]string, len(m))
+	for name, v := range m {
+		if name == "" || name[0] == '-' {
+			return nil, fmt.Errorf("invalid flag name %q; it must be non-empty and it mustn't start with `-`", name)
+		}
+		result[name] = v.values
+	}
+	return result, nil
+}
+
+// configFileValue is a flag value in the config file.
+//
+// It may contain either a single value or a list of values.
+type configFileValue struct {
+	values []string
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler interface.
+func (v *configFileValue) UnmarshalYAML(unmarshal func(any) error) error {
+	var s string
+	if err := unmarshal(&s); err == nil {
+		v.values = []string{s}
+		return nil
+	}
+	var a []string
+	if err := unmarshal(&a); err != nil {
+		return fmt.Errorf("flag value must be either a scalar or a list of scalars: %w", err)
+	}
+	v.values = a
+	return nil
+}
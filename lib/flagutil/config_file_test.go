@@ -0,0 +1,59 @@
+package flagutil
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseConfigFileFailure(t *testing.T) {
+	f := func(data string) {
+		t.Helper()
+		if _, err := ParseConfigFile([]byte(data)); err == nil {
+			t.Fatalf("expecting non-nil error when parsing %q", data)
+		}
+	}
+
+	// invalid yaml
+	f("foo")
+	f("foo: [bar")
+
+	// invalid values
+	f("foo: {bar: baz}")
+	f("foo: [[bar]]")
+
+	// invalid flag names
+	f("-foo: bar")
+	f(`"": bar`)
+
+	// missing env var
+	f("foo: '%{CONFIG_FILE_TEST_MISSING_ENV}'")
+}
+
+func TestParseConfigFileSuccess(t *testing.T) {
+	f := func(data string, resultExpected map[string][]string) {
+		t.Helper()
+		result, err := ParseConfigFile([]byte(data))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(result, resultExpected) {
+			t.Fatalf("unexpected result\ngot\n%v\nwant\n%v", result, resultExpected)
+		}
+	}
+
+	f("", map[string][]string{})
+	f(`
+retentionPeriod: 1y
+search.maxUniqueTimeseries: 100000
+selfScrapeInterval: 10s
+envflag.enable: true
+httpListenAddr: [":8428", ":8429"]
+`, map[string][]string{
+		"retentionPeriod":            {"1y"},
+		"search.maxUniqueTimeseries": {"100000"},
+		"selfScrapeInterval":         {"10s"},
+		"envflag.enable":             {"true"},
+		"httpListenAddr":             {":8428", ":8429"},
+	})
+
+}
@@ -11,7 +11,7 @@ import (
 func WriteFlags(w io.Writer) {
 	flag.Visit(func(f *flag.Flag) {
 		lname := strings.ToLower(f.Name)
-		value := f.Value.String()
+		value := GetFlagValue(f)
 		if IsSecretFlag(lname) {
 			value = "secret"
 		}
@@ -0,0 +1,157 @@
+package flagutil
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// RegisterReloadableFlag registers flagName as reloadable, e.g. its value can be changed at runtime via SetReloadableFlag.
+//
+// parse is called with the new flag value. It must validate the value and return the function for applying it.
+// The returned function must apply the value in a thread-safe manner, since the value may be read concurrently by other goroutines.
+// The flag variable itself is never changed at runtime, so it can be safely read only before the first value is applied.
+// See NewRuntimeValue. The new value is ignored if parse returns an error.
+//
+// This function must be called before flag parsing.
+func RegisterReloadableFlag(flagName string, parse func(value string) (func(), error)) {
+	reloadableFlagsLock.Lock()
+	defer reloadableFlagsLock.Unlock()
+
+	reloadableFlags[flagName] = parse
+}
+
+// IsReloadableFlag returns true if flagName is registered via RegisterReloadableFlag.
+func IsReloadableFlag(flagName string) bool {
+	reloadableFlagsLock.Lock()
+	defer reloadableFlagsLock.Unlock()
+
+	_, ok := reloadableFlags[flagName]
+	return ok
+}
+
+// GetReloadableFlags returns sorted names of flags registered via RegisterReloadableFlag.
+func GetReloadableFlags() []string {
+	reloadableFlagsLock.Lock()
+	defer reloadableFlagsLock.Unlock()
+
+	names := make([]string, 0, len(reloadableFlags))
+	for name := range reloadableFlags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SetReloadableFlag sets the value for the flag with the given flagName at fs.
+//
+// The flag must be registered via RegisterReloadableFlag. The value is passed to parse callback
+// registered for the flag, while the flag variable at fs isn't changed, since it may be read concurrently.
+// Use GetFlagValue for obtaining the current string value for the flag.
+func SetReloadableFlag(fs *flag.FlagSet, flagName, value string) error {
+	return SetReloadableFlags(fs, map[string]string{
+		flagName: value,
+	})
+}
+
+// SetReloadableFlags sets the given values for the flags at fs.
+//
+// values must contain a mapping from flag names to flag values. All the values are validated before applying them,
+// so either all the values are applied or none of them are applied if some value is invalid.
+// See SetReloadableFlag for details.
+func SetReloadableFlags(fs *flag.FlagSet, values map[string]string) error {
+	reloadableFlagsLock.Lock()
+	defer reloadableFlagsLock.Unlock()
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	applies := make([]func(), 0, len(names))
+	for _, name := range names {
+		parse, ok := reloadableFlags[name]
+		if !ok {
+			return fmt.Errorf("the flag -%s cannot be changed at runtime", name)
+		}
+		if fs.Lookup(name) == nil {
+			return fmt.Errorf("unknown flag -%s", name)
+		}
+		value := values[name]
+		apply, err := parse(value)
+		if err != nil {
+			return fmt.Errorf("cannot set -%s=%q: %w", name, value, err)
+		}
+		applies = append(applies, apply)
+	}
+	for i, name := range names {
+		applies[i]()
+		reloadedValues[name] = values[name]
+	}
+	return nil
+}
+
+// GetFlagValue returns the current string value for f.
+//
+// It returns the value set via SetReloadableFlag if f is reloadable and it has been changed at runtime.
+func GetFlagValue(f *flag.Flag) string {
+	reloadableFlagsLock.Lock()
+	value, ok := reloadedValues[f.Name]
+	reloadableFlagsLock.Unlock()
+
+	if ok {
+		return value
+	}
+	return f.Value.String()
+}
+
+var (
+	reloadableFlagsLock sync.Mutex
+	reloadableFlags     = make(map[string]func(value string) (func(), error))
+
+	// reloadedValues contains values for reloadable flags changed via SetReloadableFlag.
+	reloadedValues = make(map[string]string)
+)
+
+// RuntimeValue holds the value for reloadable flag.
+//
+// The value can be read via Get concurrently with its update via SetReloadableFlag.
+type RuntimeValue[T any] struct {
+	// flagValue points to the flag variable. It is read only until the value is changed at runtime.
+	// The flag variable is never written after flags parsing, so it is safe to read it concurrently.
+	flagValue *T
+
+	// p holds the value changed at runtime.
+	p atomic.Pointer[T]
+}
+
+// NewRuntimeValue registers flagName with the given flagValue as reloadable flag and returns RuntimeValue for it.
+//
+// parse must parse and validate the new flag value.
+// RuntimeValue.Get must be used instead of reading flagValue directly.
+func NewRuntimeValue[T any](flagName string, flagValue *T, parse func(s string) (T, error)) *RuntimeValue[T] {
+	rv := &RuntimeValue[T]{
+		flagValue: flagValue,
+	}
+	RegisterReloadableFlag(flagName, func(s string) (func(), error) {
+		v, err := parse(s)
+		if err != nil {
+			return nil, err
+		}
+		return func() {
+			rv.p.Store(&v)
+		}, nil
+	})
+	return rv
+}
+
+// Get returns the current value for rv.
+func (rv *RuntimeValue[T]) Get() T {
+	if p := rv.p.Load(); p != nil {
+		return *p
+	}
+	return *rv.flagValue
+}
@@ -0,0 +1,166 @@
+package flagutil
+
+import (
+	"flag"
+	"fmt"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestSetReloadableFlag(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flagValue := fs.String("reloadableFlagTest", "foo", "test flag")
+	_ = fs.String("nonReloadableFlagTest", "foo", "test flag")
+
+	var lastValue string
+	RegisterReloadableFlag("reloadableFlagTest", func(value string) (func(), error) {
+		if value == "invalid" {
+			return nil, fmt.Errorf("invalid value")
+		}
+		return func() {
+			lastValue = value
+		}, nil
+	})
+
+	if !IsReloadableFlag("reloadableFlagTest") {
+		t.Fatalf("expecting reloadableFlagTest to be reloadable")
+	}
+	if IsReloadableFlag("nonReloadableFlagTest") {
+		t.Fatalf("expecting nonReloadableFlagTest to be non-reloadable")
+	}
+
+	if err := SetReloadableFlag(fs, "reloadableFlagTest", "bar"); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if v := GetFlagValue(fs.Lookup("reloadableFlagTest")); v != "bar" {
+		t.Fatalf("unexpected flag value; got %q; want %q", v, "bar")
+	}
+	// The flag variable mustn't be changed, since it may be read concurrently
+	if *flagValue != "foo" {
+		t.Fatalf("unexpected flag variable value; got %q; want %q", *flagValue, "foo")
+	}
+	if lastValue != "bar" {
+		t.Fatalf("unexpected value passed to onChange; got %q; want %q", lastValue, "bar")
+	}
+
+	// invalid value mustn't change the flag
+	if err := SetReloadableFlag(fs, "reloadableFlagTest", "invalid"); err == nil {
+		t.Fatalf("expecting non-nil error for invalid value")
+	}
+	if v := GetFlagValue(fs.Lookup("reloadableFlagTest")); v != "bar" {
+		t.Fatalf("unexpected flag value; got %q; want %q", v, "bar")
+	}
+
+	// non-reloadable flag
+	if err := SetReloadableFlag(fs, "nonReloadableFlagTest", "bar"); err == nil {
+		t.Fatalf("expecting non-nil error for non-reloadable flag")
+	}
+}
+
+func TestSetReloadableFlags(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	_ = fs.Int("reloadableFlagsTestA", 1, "test flag")
+	_ = fs.Int("reloadableFlagsTestB", 1, "test flag")
+
+	parse := func(s string) (int, error) {
+		n, err := strconv.Atoi(s)
+		if err == nil && n < 0 {
+			err = fmt.Errorf("the value mustn't be negative")
+		}
+		return n, err
+	}
+	rvA := NewRuntimeValue("reloadableFlagsTestA", new(int), parse)
+	rvB := NewRuntimeValue("reloadableFlagsTestB", new(int), parse)
+
+	if err := SetReloadableFlags(fs, map[string]string{
+		"reloadableFlagsTestA": "2",
+		"reloadableFlagsTestB": "3",
+	}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if a, b := rvA.Get(), rvB.Get(); a != 2 || b != 3 {
+		t.Fatalf("unexpected values; got %d, %d; want 2, 3", a, b)
+	}
+
+	// None of the values must be applied if some of them are invalid
+	if err := SetReloadableFlags(fs, map[string]string{
+		"reloadableFlagsTestA": "4",
+		"reloadableFlagsTestB": "-1",
+	}); err == nil {
+		t.Fatalf("expecting non-nil error for invalid value")
+	}
+	if a, b := rvA.Get(), rvB.Get(); a != 2 || b != 3 {
+		t.Fatalf("unexpected values after invalid update; got %d, %d; want 2, 3", a, b)
+	}
+	if v := GetFlagValue(fs.Lookup("reloadableFlagsTestA")); v != "2" {
+		t.Fatalf("unexpected flag value after invalid update; got %q; want %q", v, "2")
+	}
+}
+
+func TestRuntimeValueConcurrentReload(t *testing.T) {
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	flagValue := fs.Int("runtimeValueConcurrentTest", 1, "test flag")
+	if err := fs.Parse([]string{"-runtimeValueConcurrentTest=2"}); err != nil {
+		t.Fatalf("cannot parse flags: %s", err)
+	}
+	rv := NewRuntimeValue("runtimeValueConcurrentTest", flagValue, strconv.Atoi)
+	if n := rv.Get(); n != 2 {
+		t.Fatalf("unexpected initial value; got %d; want 2", n)
+	}
+
+	// Read the value concurrently with its updates. This test is useful for running with -race flag.
+	const updates = 1000
+	const readers = 4
+	stopCh := make(chan struct{})
+	var started atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			f := fs.Lookup("runtimeValueConcurrentTest")
+			started.Add(1)
+			for {
+				select {
+				case <-stopCh:
+					return
+				default:
+				}
+				if n := rv.Get(); n < 2 || n > updates {
+					panic(fmt.Errorf("unexpected value: %d", n))
+				}
+				_ = GetFlagValue(f)
+				// The flag variable may be read directly by other code, so it mustn't be changed by SetReloadableFlag.
+				_ = f.Value.String()
+			}
+		}()
+	}
+	for started.Load() < readers {
+		runtime.Gosched()
+	}
+	for i := 2; i <= updates; i++ {
+		if err := SetReloadableFlag(fs, "runtimeValueConcurrentTest", strconv.Itoa(i)); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	close(stopCh)
+	wg.Wait()
+
+	if n := rv.Get(); n != updates {
+		t.Fatalf("unexpected value after updates; got %d; want %d", n, updates)
+	}
+	if v := GetFlagValue(fs.Lookup("runtimeValueConcurrentTest")); v != strconv.Itoa(updates) {
+		t.Fatalf("unexpected flag value after updates; got %q; want %q", v, strconv.Itoa(updates))
+	}
+
+	// invalid value mustn't change the value
+	if err := SetReloadableFlag(fs, "runtimeValueConcurrentTest", "foo"); err == nil {
+		t.Fatalf("expecting non-nil error for invalid value")
+	}
+	if n := rv.Get(); n != updates {
+		t.Fatalf("unexpected value after invalid update; got %d; want %d", n, updates)
+	}
+}
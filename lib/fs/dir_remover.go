@@ -49,10 +49,10 @@ func tryRemoveAll(path string) bool {
 		mustSyncParentDirIfExists(path)
 		return true
 	}
-	if !isTemporaryNFSError(err) {
+	if !isTemporaryNFSError(err) && !isTemporaryRemoveError(err) {
 		logger.Panicf("FATAL: cannot remove %q: %s", path, err)
 	}
-	// NFS prevents from removing directories with open files.
+	// NFS and Windows prevent from removing directories with open files.
 	// See https://github.com/VictoriaMetrics/VictoriaMetrics/issues/61 .
 	// Schedule for later directory removal.
 	nfsDirRemoveFailedAttempts.Inc()
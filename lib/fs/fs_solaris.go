@@ -68,3 +68,7 @@ func mustGetFreeSpace(path string) uint64 {
 func freeSpace(stat unix.Statvfs_t) uint64 {
 	return uint64(stat.Bavail) * uint64(stat.Bsize)
 }
+
+func isTemporaryRemoveError(_ error) bool {
+	return false
+}
@@ -50,3 +50,7 @@ func mustGetFreeSpace(path string) uint64 {
 	}
 	return freeSpace(stat)
 }
+
+func isTemporaryRemoveError(_ error) bool {
+	return false
+}
@@ -1,9 +1,11 @@
 package fs
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"sync"
+	"time"
 	"unsafe"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
@@ -25,7 +27,7 @@ func mustSyncPath(path string) {
 func mustRemoveDirAtomic(dir string) {
 	n := atomicDirRemoveCounter.Add(1)
 	tmpDir := fmt.Sprintf("%s.must-remove.%d", dir, n)
-	if err := os.Rename(dir, tmpDir); err != nil {
+	if err := renameWithRetries(dir, tmpDir); err != nil {
 		logger.Panicf("FATAL: cannot move %s to %s: %s", dir, tmpDir, err)
 	}
 	if err := os.RemoveAll(tmpDir); err != nil {
@@ -34,6 +36,34 @@ func mustRemoveDirAtomic(dir string) {
 	}
 }
 
+// renameWithRetries renames src to dst.
+//
+// Windows doesn't allow renaming directories with files opened by other processes such as antivirus or indexing service.
+// Such files are usually closed shortly, so the rename is retried a few times before giving up.
+func renameWithRetries(src, dst string) error {
+	delay := 10 * time.Millisecond
+	for i := 0; ; i++ {
+		err := os.Rename(src, dst)
+		if err == nil || i >= 10 || !isTemporaryRemoveError(err) {
+			return err
+		}
+		time.Sleep(delay)
+		if delay < time.Second {
+			delay *= 2
+		}
+	}
+}
+
+// isTemporaryRemoveError returns true if err is caused by files, which are temporarily opened by other processes.
+//
+// Windows doesn't allow removing opened files. Files removed while being opened stay in the directory
+// until the last handle is closed, so the directory cannot be removed until then.
+func isTemporaryRemoveError(err error) bool {
+	return errors.Is(err, windows.ERROR_SHARING_VIOLATION) ||
+		errors.Is(err, windows.ERROR_ACCESS_DENIED) ||
+		errors.Is(err, windows.ERROR_DIR_NOT_EMPTY)
+}
+
 const (
 	lockfileExclusiveLock = 2
 	fileFlagNormal        = 0x00000080
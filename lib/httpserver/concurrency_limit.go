@@ -0,0 +1,118 @@
+package httpserver
+
+import (
+	"flag"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/timerpool"
+)
+
+var (
+	maxConcurrentRequestsPerPath = flagutil.NewDictInt("http.maxConcurrentRequestsPerPath", 0, "The maximum number of concurrently executed requests per the given http path. "+
+		"For example, -http.maxConcurrentRequestsPerPath=/api/v1/export:2 allows executing up to 2 concurrent requests to /api/v1/export. "+
+		"Excess requests are queued for up to -http.maxQueueDurationPerPath. Zero value disables the limit for the given path. "+
+		"See https://docs.victoriametrics.com/#per-path-concurrency-limits")
+	maxQueueDurationPerPath = flag.Duration("http.maxQueueDurationPerPath", 10*time.Second, "The maximum duration the request waits for execution when "+
+		"-http.maxConcurrentRequestsPerPath limit is reached for the requested path. '503 Service Unavailable' response with 'Retry-After' header "+
+		"is returned after the timeout")
+)
+
+// pathConcurrencyLimiter limits the number of concurrently executed requests for a single http path.
+type pathConcurrencyLimiter struct {
+	path string
+	ch   chan struct{}
+
+	limitReached *metrics.Counter
+	limitTimeout *metrics.Counter
+}
+
+var (
+	pathConcurrencyLimitersLock sync.Mutex
+	pathConcurrencyLimiters     = make(map[string]*pathConcurrencyLimiter)
+)
+
+// getPathConcurrencyLimiter returns concurrency limiter for the given path.
+//
+// nil is returned if -http.maxConcurrentRequestsPerPath isn't set for the given path.
+func getPathConcurrencyLimiter(path string) *pathConcurrencyLimiter {
+	n := maxConcurrentRequestsPerPath.Get(path)
+	if n <= 0 {
+		return nil
+	}
+
+	pathConcurrencyLimitersLock.Lock()
+	defer pathConcurrencyLimitersLock.Unlock()
+
+	pcl := pathConcurrencyLimiters[path]
+	if pcl == nil {
+		pcl = &pathConcurrencyLimiter{
+			path:         path,
+			ch:           make(chan struct{}, n),
+			limitReached: metrics.NewCounter(fmt.Sprintf(`vm_http_concurrency_limit_reached_total{path=%q}`, path)),
+			limitTimeout: metrics.NewCounter(fmt.Sprintf(`vm_http_concurrency_limit_timeout_total{path=%q}`, path)),
+		}
+		_ = metrics.NewGauge(fmt.Sprintf(`vm_http_concurrent_requests{path=%q}`, path), func() float64 {
+			return float64(len(pcl.ch))
+		})
+		_ = metrics.NewGauge(fmt.Sprintf(`vm_http_concurrent_requests_capacity{path=%q}`, path), func() float64 {
+			return float64(cap(pcl.ch))
+		})
+		pathConcurrencyLimiters[path] = pcl
+	}
+	return pcl
+}
+
+// acquire waits until the request r can be executed according to pcl limit.
+//
+// It returns false if the request cannot be executed. In this case the error response is already sent to w.
+// pcl.release must be called after the request execution if true is returned.
+func (pcl *pathConcurrencyLimiter) acquire(w http.ResponseWriter, r *http.Request) bool {
+	select {
+	case pcl.ch <- struct{}{}:
+		return true
+	default:
+	}
+
+	// Wait for a while until giving up. This should resolve short bursts in requests.
+	pcl.limitReached.Inc()
+	t := timerpool.Get(*maxQueueDurationPerPath)
+	defer timerpool.Put(t)
+	select {
+	case pcl.ch <- struct{}{}:
+		return true
+	case <-r.Context().Done():
+		// The client has canceled the request, so there is no need in sending the response.
+		return false
+	case <-t.C:
+		pcl.limitTimeout.Inc()
+		w.Header().Set("Retry-After", getRetryAfterSeconds(*maxQueueDurationPerPath))
+		err := &ErrorWithStatusCode{
+			Err: fmt.Errorf("couldn't start executing the request in %.3f seconds, since -http.maxConcurrentRequestsPerPath=%s:%d concurrent requests "+
+				"are executed; try again later", maxQueueDurationPerPath.Seconds(), pcl.path, cap(pcl.ch)),
+			StatusCode: http.StatusServiceUnavailable,
+		}
+		Errorf(w, r, "%s", err)
+		return false
+	}
+}
+
+func (pcl *pathConcurrencyLimiter) release() {
+	<-pcl.ch
+}
+
+// getRetryAfterSeconds returns the value for Retry-After header for the given d.
+func getRetryAfterSeconds(d time.Duration) string {
+	secs := int64(math.Ceil(d.Seconds()))
+	if secs < 1 {
+		secs = 1
+	}
+	return strconv.FormatInt(secs, 10)
+}
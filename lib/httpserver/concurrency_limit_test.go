@@ -0,0 +1,79 @@
+package httpserver
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
+)
+
+func TestGetRetryAfterSeconds(t *testing.T) {
+	f := func(d time.Duration, resultExpected string) {
+		t.Helper()
+		result := getRetryAfterSeconds(d)
+		if result != resultExpected {
+			t.Fatalf("unexpected result for %s; got %q; want %q", d, result, resultExpected)
+		}
+	}
+
+	f(0, "1")
+	f(10*time.Millisecond, "1")
+	f(time.Second, "1")
+	f(1500*time.Millisecond, "2")
+	f(time.Minute, "60")
+}
+
+func TestPathConcurrencyLimiter(t *testing.T) {
+	if err := maxConcurrentRequestsPerPath.Set("/api/v1/export:1"); err != nil {
+		t.Fatalf("cannot set -http.maxConcurrentRequestsPerPath: %s", err)
+	}
+	origMaxQueueDuration := *maxQueueDurationPerPath
+	*maxQueueDurationPerPath = 10 * time.Millisecond
+	defer func() {
+		*maxConcurrentRequestsPerPath = flagutil.DictInt{}
+		*maxQueueDurationPerPath = origMaxQueueDuration
+	}()
+
+	if pcl := getPathConcurrencyLimiter("/api/v1/query"); pcl != nil {
+		t.Fatalf("expecting nil limiter for the path without limits")
+	}
+	pcl := getPathConcurrencyLimiter("/api/v1/export")
+	if pcl == nil {
+		t.Fatalf("expecting non-nil limiter for /api/v1/export")
+	}
+	if pcl != getPathConcurrencyLimiter("/api/v1/export") {
+		t.Fatalf("expecting the same limiter for the same path")
+	}
+
+	f := func(okExpected bool, statusCodeExpected int, retryAfterExpected string) {
+		t.Helper()
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/export", nil)
+		w := httptest.NewRecorder()
+		ok := pcl.acquire(w, req)
+		if ok != okExpected {
+			t.Fatalf("unexpected acquire result; got %v; want %v", ok, okExpected)
+		}
+		res := w.Result()
+		_ = res.Body.Close()
+		if res.StatusCode != statusCodeExpected {
+			t.Fatalf("unexpected status code; got %d; want %d", res.StatusCode, statusCodeExpected)
+		}
+		if retryAfter := res.Header.Get("Retry-After"); retryAfter != retryAfterExpected {
+			t.Fatalf("unexpected Retry-After header; got %q; want %q", retryAfter, retryAfterExpected)
+		}
+	}
+
+	// the first request must be executed
+	f(true, http.StatusOK, "")
+
+	// the second request must be rejected after the queue timeout
+	f(false, http.StatusServiceUnavailable, "1")
+
+	// the request must be executed after the slot is released
+	pcl.release()
+	f(true, http.StatusOK, "")
+	pcl.release()
+}
@@ -52,6 +52,8 @@ var (
 	metricsAuthKey   = flagutil.NewPassword("metricsAuthKey", "Auth key for /metrics endpoint. It must be passed via authKey query arg. It overrides -httpAuth.*")
 	flagsAuthKey     = flagutil.NewPassword("flagsAuthKey", "Auth key for /flags endpoint. It must be passed via authKey query arg. It overrides -httpAuth.*")
 	pprofAuthKey     = flagutil.NewPassword("pprofAuthKey", "Auth key for /debug/pprof/* endpoints. It must be passed via authKey query arg. It -httpAuth.*")
+	flagsSetAuthKey  = flagutil.NewPassword("flagsSetAuthKey", "Auth key for /-/flags/set endpoint, which allows changing a subset of flags at runtime. "+
+		"It must be passed via authKey query arg. The endpoint is disabled if the auth key isn't set. See https://docs.victoriametrics.com/#changing-flags-at-runtime")
 
 	disableResponseCompression  = flag.Bool("http.disableResponseCompression", false, "Disable compression of HTTP responses to save CPU resources. By default, compression is enabled to save network bandwidth")
 	maxGracefulShutdownDuration = flag.Duration("http.maxGracefulShutdownDuration", 7*time.Second, `The maximum duration for a graceful shutdown of the HTTP server. A highly loaded server may require increased value for a graceful shutdown`)
@@ -383,6 +385,10 @@ func handlerWrapper(s *server, w http.ResponseWriter, r *http.Request, rh Reques
 		h.Set("Content-Type", "text/plain; charset=utf-8")
 		flagutil.WriteFlags(w)
 		return
+	case "/-/flags/set":
+		flagsSetRequests.Inc()
+		handleFlagsSet(w, r)
+		return
 	case "/-/healthy":
 		// This is needed for Prometheus compatibility
 		// See https://github.com/VictoriaMetrics/VictoriaMetrics/issues/1833
@@ -412,6 +418,13 @@ func handlerWrapper(s *server, w http.ResponseWriter, r *http.Request, rh Reques
 			return
 		}
 
+		if pcl := getPathConcurrencyLimiter(r.URL.Path); pcl != nil {
+			if !pcl.acquire(w, r) {
+				return
+			}
+			defer pcl.release()
+		}
+
 		w = &responseWriterWithAbort{
 			ResponseWriter: w,
 		}
@@ -434,6 +447,37 @@ func isProtectedByAuthFlag(path string) bool {
 		strings.HasPrefix(path, "/snapshot/")
 }
 
+// handleFlagsSet changes the value for the flag registered via flagutil.RegisterReloadableFlag.
+//
+// The flag name and the new value must be passed via name and value query args.
+func handleFlagsSet(w http.ResponseWriter, r *http.Request) {
+	if flagsSetAuthKey.Get() == "" {
+		http.Error(w, "the endpoint is disabled; set -flagsSetAuthKey command-line flag in order to enable it", http.StatusForbidden)
+		return
+	}
+	if !CheckAuthFlag(w, r, flagsSetAuthKey) {
+		return
+	}
+	if r.Method != http.MethodPost {
+		http.Error(w, fmt.Sprintf("unsupported method %s; use POST", r.Method), http.StatusMethodNotAllowed)
+		return
+	}
+	name := strings.TrimPrefix(r.FormValue("name"), "-")
+	value := r.FormValue("value")
+	if !flagutil.IsReloadableFlag(name) {
+		http.Error(w, fmt.Sprintf("the flag -%s cannot be changed at runtime; supported flags: -%s", name, strings.Join(flagutil.GetReloadableFlags(), ", -")), http.StatusBadRequest)
+		return
+	}
+	if err := flagutil.SetReloadableFlag(flag.CommandLine, name, value); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	logger.Infof("-%s is set to %q via %s request from %s", name, value, r.URL.Path, r.RemoteAddr)
+	h := w.Header()
+	h.Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintf(w, "-%s=%q\n", name, value)
+}
+
 // CheckAuthFlag checks whether the given authKey is set and valid
 //
 // Falls back to checkBasicAuth if authKey is not set
@@ -510,6 +554,7 @@ func pprofHandler(profileName string, w http.ResponseWriter, r *http.Request) {
 var (
 	metricsRequests      = metrics.NewCounter(`vm_http_requests_total{path="/metrics"}`)
 	pprofRequests        = metrics.NewCounter(`vm_http_requests_total{path="/debug/pprof/"}`)
+	flagsSetRequests     = metrics.NewCounter(`vm_http_requests_total{path="/-/flags/set"}`)
 	pprofCmdlineRequests = metrics.NewCounter(`vm_http_requests_total{path="/debug/pprof/cmdline"}`)
 	pprofProfileRequests = metrics.NewCounter(`vm_http_requests_total{path="/debug/pprof/profile"}`)
 	pprofSymbolRequests  = metrics.NewCounter(`vm_http_requests_total{path="/debug/pprof/symbol"}`)
@@ -583,6 +628,13 @@ func (rwa *responseWriterWithAbort) Flush() {
 	flusher.Flush()
 }
 
+// Unwrap returns the underlying http.ResponseWriter.
+//
+// It is used by http.ResponseController for accessing the underlying connection.
+func (rwa *responseWriterWithAbort) Unwrap() http.ResponseWriter {
+	return rwa.ResponseWriter
+}
+
 // abort aborts the client connection associated with rwa.
 //
 // The last http chunk in the response stream is intentionally written incorrectly,
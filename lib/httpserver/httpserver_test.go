@@ -2,6 +2,7 @@ package httpserver
 
 import (
 	"encoding/json"
+	"flag"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -177,3 +178,52 @@ func TestHandlerWrapper(t *testing.T) {
 		t.Fatalf("unexpected CSP header; got %q; want %q", got, cspHeader)
 	}
 }
+
+func TestHandleFlagsSet(t *testing.T) {
+	_ = flag.String("httpserverTestReloadableFlag", "foo", "test flag")
+	flagutil.RegisterReloadableFlag("httpserverTestReloadableFlag", func(_ string) (func(), error) {
+		return func() {}, nil
+	})
+
+	f := func(method, authKey, query string, statusCodeExpected int, valueExpected string) {
+		t.Helper()
+
+		req := httptest.NewRequest(method, "/-/flags/set?"+query+"&authKey="+authKey, nil)
+		w := httptest.NewRecorder()
+		handleFlagsSet(w, req)
+		res := w.Result()
+		_ = res.Body.Close()
+		if res.StatusCode != statusCodeExpected {
+			t.Fatalf("unexpected status code; got %d; want %d", res.StatusCode, statusCodeExpected)
+		}
+		if v := flagutil.GetFlagValue(flag.Lookup("httpserverTestReloadableFlag")); v != valueExpected {
+			t.Fatalf("unexpected flag value; got %q; want %q", v, valueExpected)
+		}
+	}
+
+	// the endpoint is disabled by default
+	f(http.MethodPost, "", "name=httpserverTestReloadableFlag&value=bar", http.StatusForbidden, "foo")
+
+	if err := flagsSetAuthKey.Set("secret"); err != nil {
+		t.Fatalf("cannot set -flagsSetAuthKey: %s", err)
+	}
+	defer func() {
+		if err := flagsSetAuthKey.Set(""); err != nil {
+			t.Fatalf("cannot reset -flagsSetAuthKey: %s", err)
+		}
+	}()
+
+	// invalid auth key
+	f(http.MethodPost, "wrong", "name=httpserverTestReloadableFlag&value=bar", http.StatusUnauthorized, "foo")
+
+	// unsupported method
+	f(http.MethodGet, "secret", "name=httpserverTestReloadableFlag&value=bar", http.StatusMethodNotAllowed, "foo")
+
+	// non-reloadable flag
+	f(http.MethodPost, "secret", "name=httpListenAddr&value=:1234", http.StatusBadRequest, "foo")
+	f(http.MethodPost, "secret", "name=&value=bar", http.StatusBadRequest, "foo")
+
+	// successful update
+	f(http.MethodPost, "secret", "name=httpserverTestReloadableFlag&value=bar", http.StatusOK, "bar")
+	f(http.MethodPost, "secret", "name=-httpserverTestReloadableFlag&value=baz", http.StatusOK, "baz")
+}
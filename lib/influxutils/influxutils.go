@@ -1,15 +1,111 @@
 package influxutils
 
 import (
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/httpserver"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
 )
 
-var influxDatabaseNames = flagutil.NewArrayString("influx.databaseNames", "Comma-separated list of database names to return from /query and /influx/query API. "+
-	"This can be needed for accepting data from Telegraf plugins such as https://github.com/fangli/fluent-plugin-influxdb")
+var (
+	influxDatabaseNames = flagutil.NewArrayString("influx.databaseNames", "Comma-separated list of database names to return from /query and /influx/query API. "+
+		"This can be needed for accepting data from Telegraf plugins such as https://github.com/fangli/fluent-plugin-influxdb")
+	orgLabel = flag.String("influx.orgLabel", "", "Optional label name for storing the organization sent over 'org' query arg to InfluxDB v2 /api/v2/write endpoint. "+
+		"The organization isn't stored by default. See https://docs.victoriametrics.com/#how-to-send-data-in-influxdb-v2-format")
+)
+
+// GetDatabase returns the database name for InfluxDB write request with the given query args q.
+//
+// The database name is read from 'db' query arg for InfluxDB v1 write API.
+// See https://docs.influxdata.com/influxdb/v1.7/tools/api/#write-http-endpoint
+//
+// The bucket name from 'bucket' query arg is used as the database name for InfluxDB v2 write API.
+// See https://docs.influxdata.com/influxdb/v2/api/#operation/PostWrite
+func GetDatabase(q url.Values) string {
+	if db := q.Get("db"); db != "" {
+		return db
+	}
+	return q.Get("bucket")
+}
+
+// AppendOrgLabel appends the label with the organization from 'org' query arg at q to dst if -influx.orgLabel is set.
+//
+// See https://docs.influxdata.com/influxdb/v2/api/#operation/PostWrite
+func AppendOrgLabel(dst []prompbmarshal.Label, q url.Values) []prompbmarshal.Label {
+	if *orgLabel == "" {
+		return dst
+	}
+	org := q.Get("org")
+	if org == "" {
+		org = q.Get("orgID")
+	}
+	if org == "" {
+		return dst
+	}
+	return append(dst, prompbmarshal.Label{
+		Name:  *orgLabel,
+		Value: org,
+	})
+}
+
+// WriteV2ErrorResponse writes InfluxDB v2 compatible error response for the given err to w.
+//
+// See https://docs.influxdata.com/influxdb/v2/api/#operation/PostWrite
+func WriteV2ErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	logger.WarnfSkipframes(1, "remoteAddr: %s; requestURI: %s; %s", httpserver.GetQuotedRemoteAddr(r), httpserver.GetRequestURI(r), err)
+
+	statusCode := http.StatusBadRequest
+	var esc *httpserver.ErrorWithStatusCode
+	if errors.As(err, &esc) {
+		statusCode = esc.StatusCode
+	}
+	resp := struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}{
+		Code:    getV2ErrorCode(statusCode),
+		Message: err.Error(),
+	}
+	data, _ := json.Marshal(&resp)
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Platform-Error-Code", resp.Code)
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(data)
+}
+
+// getV2ErrorCode returns InfluxDB v2 error code for the given HTTP statusCode.
+//
+// See https://github.com/influxdata/influxdb/blob/v2.7.11/kit/platform/errors/errors.go
+func getV2ErrorCode(statusCode int) string {
+	switch statusCode {
+	case http.StatusUnauthorized:
+		return "unauthorized"
+	case http.StatusForbidden:
+		return "forbidden"
+	case http.StatusNotFound:
+		return "not found"
+	case http.StatusRequestEntityTooLarge:
+		return "request too large"
+	case http.StatusUnsupportedMediaType:
+		return "unsupported media type"
+	case http.StatusTooManyRequests:
+		return "too many requests"
+	case http.StatusServiceUnavailable:
+		return "unavailable"
+	}
+	if statusCode >= 500 {
+		return "internal error"
+	}
+	return "invalid"
+}
 
 // WriteDatabaseNames writes influxDatabaseNames to w.
 func WriteDatabaseNames(w http.ResponseWriter) {
@@ -0,0 +1,89 @@
+package influxutils
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/httpserver"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+)
+
+func TestGetDatabase(t *testing.T) {
+	f := func(query, dbExpected string) {
+		t.Helper()
+		q, err := url.ParseQuery(query)
+		if err != nil {
+			t.Fatalf("cannot parse query %q: %s", query, err)
+		}
+		db := GetDatabase(q)
+		if db != dbExpected {
+			t.Fatalf("unexpected db for query %q; got %q; want %q", query, db, dbExpected)
+		}
+	}
+	f("", "")
+	f("db=foo", "foo")
+	f("bucket=bar&org=baz", "bar")
+	f("db=foo&bucket=bar", "foo")
+}
+
+func TestAppendOrgLabel(t *testing.T) {
+	f := func(label, query string, labelsExpected []prompbmarshal.Label) {
+		t.Helper()
+		origLabel := *orgLabel
+		*orgLabel = label
+		defer func() {
+			*orgLabel = origLabel
+		}()
+		q, err := url.ParseQuery(query)
+		if err != nil {
+			t.Fatalf("cannot parse query %q: %s", query, err)
+		}
+		labels := AppendOrgLabel(nil, q)
+		if !reflect.DeepEqual(labels, labelsExpected) {
+			t.Fatalf("unexpected labels for query %q; got %v; want %v", query, labels, labelsExpected)
+		}
+	}
+
+	// -influx.orgLabel isn't set
+	f("", "org=foo", nil)
+
+	// missing org
+	f("org", "bucket=bar", nil)
+
+	f("org", "org=foo&bucket=bar", []prompbmarshal.Label{{
+		Name:  "org",
+		Value: "foo",
+	}})
+	f("tenant", "orgID=123", []prompbmarshal.Label{{
+		Name:  "tenant",
+		Value: "123",
+	}})
+}
+
+func TestWriteV2ErrorResponse(t *testing.T) {
+	f := func(err error, statusCodeExpected int, respExpected string) {
+		t.Helper()
+		r := httptest.NewRequest(http.MethodPost, "/api/v2/write", nil)
+		w := httptest.NewRecorder()
+		WriteV2ErrorResponse(w, r, err)
+		if w.Code != statusCodeExpected {
+			t.Fatalf("unexpected status code; got %d; want %d", w.Code, statusCodeExpected)
+		}
+		if resp := w.Body.String(); resp != respExpected {
+			t.Fatalf("unexpected response\ngot\n%s\nwant\n%s", resp, respExpected)
+		}
+	}
+	f(fmt.Errorf("cannot parse line"), http.StatusBadRequest, `{"code":"invalid","message":"cannot parse line"}`)
+	f(&httpserver.ErrorWithStatusCode{
+		Err:        fmt.Errorf("too big request"),
+		StatusCode: http.StatusRequestEntityTooLarge,
+	}, http.StatusRequestEntityTooLarge, `{"code":"request too large","message":"too big request"}`)
+	f(fmt.Errorf("cannot write data: %w", &httpserver.ErrorWithStatusCode{
+		Err:        fmt.Errorf("storage is unavailable"),
+		StatusCode: http.StatusServiceUnavailable,
+	}), http.StatusServiceUnavailable, `{"code":"unavailable","message":"cannot write data: storage is unavailable"}`)
+}
@@ -24,7 +24,7 @@ var (
 	writeErrorsUDP   = metrics.NewCounter(`vm_ingestserver_request_errors_total{type="graphite", name="write", net="udp"}`)
 )
 
-// Server accepts Graphite plaintext lines over TCP and UDP.
+// Server accepts Graphite plaintext lines over TCP and UDP or Graphite pickle messages over TCP.
 type Server struct {
 	addr  string
 	lnTCP net.Listener
@@ -75,19 +75,50 @@ func MustStart(addr string, useProxyProtocol bool, insertHandler func(r io.Reade
 	return s
 }
 
+// MustStartPickle starts graphite server for pickle protocol on the given TCP addr.
+//
+// The incoming connections are processed with insertHandler.
+//
+// If useProxyProtocol is set to true, then the incoming connections are accepted via proxy protocol.
+// See https://www.haproxy.org/download/1.8/doc/proxy-protocol.txt
+//
+// MustStop must be called on the returned server when it is no longer needed.
+func MustStartPickle(addr string, useProxyProtocol bool, insertHandler func(r io.Reader) error) *Server {
+	logger.Infof("starting TCP Graphite pickle server at %q", addr)
+	lnTCP, err := netutil.NewTCPListener("graphite_pickle", addr, useProxyProtocol, nil)
+	if err != nil {
+		logger.Fatalf("cannot start TCP Graphite pickle server at %q: %s", addr, err)
+	}
+
+	s := &Server{
+		addr:  addr,
+		lnTCP: lnTCP,
+	}
+	s.cm.Init("graphite_pickle")
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.serveTCP(insertHandler)
+		logger.Infof("stopped TCP Graphite pickle server at %q", addr)
+	}()
+	return s
+}
+
 // MustStop stops the server.
 func (s *Server) MustStop() {
 	logger.Infof("stopping TCP Graphite server at %q...", s.addr)
 	if err := s.lnTCP.Close(); err != nil {
 		logger.Errorf("cannot close TCP Graphite server: %s", err)
 	}
-	logger.Infof("stopping UDP Graphite server at %q...", s.addr)
-	if err := s.lnUDP.Close(); err != nil {
-		logger.Errorf("cannot close UDP Graphite server: %s", err)
+	if s.lnUDP != nil {
+		logger.Infof("stopping UDP Graphite server at %q...", s.addr)
+		if err := s.lnUDP.Close(); err != nil {
+			logger.Errorf("cannot close UDP Graphite server: %s", err)
+		}
 	}
 	s.cm.CloseAll(0)
 	s.wg.Wait()
-	logger.Infof("TCP and UDP Graphite servers at %q have been stopped", s.addr)
+	logger.Infof("Graphite servers at %q have been stopped", s.addr)
 }
 
 func (s *Server) serveTCP(insertHandler func(r io.Reader) error) {
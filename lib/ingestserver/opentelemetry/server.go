@@ -0,0 +1,320 @@
+package opentelemetry
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	// OpenTelemetry collector compresses OTLP/gRPC requests with gzip by default.
+	_ "google.golang.org/grpc/encoding/gzip"
+	"google.golang.org/grpc/status"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding/zstd"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/httpserver"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/netutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/arrow"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/writeconcurrencylimiter"
+	"github.com/VictoriaMetrics/metrics"
+)
+
+var (
+	writeRequests = metrics.NewCounter(`vm_ingestserver_requests_total{type="opentelemetry", name="write", net="grpc"}`)
+	writeErrors   = metrics.NewCounter(`vm_ingestserver_request_errors_total{type="opentelemetry", name="write", net="grpc"}`)
+
+	arrowWriteRequests = metrics.NewCounter(`vm_ingestserver_requests_total{type="opentelemetry", name="arrow_write", net="grpc"}`)
+	arrowWriteErrors   = metrics.NewCounter(`vm_ingestserver_request_errors_total{type="opentelemetry", name="arrow_write", net="grpc"}`)
+)
+
+// maxRequestSize is the maximum size of OTLP/gRPC request.
+const maxRequestSize = 64 * 1024 * 1024
+
+// Server represents OTLP/gRPC server for OpenTelemetry metrics.
+//
+// See https://opentelemetry.io/docs/specs/otlp/#otlpgrpc
+type Server struct {
+	s  *grpc.Server
+	ln net.Listener
+	wg sync.WaitGroup
+}
+
+// MustStart starts OTLP/gRPC server on the given addr.
+//
+// insertHandler is called with protobuf-encoded ExportMetricsServiceRequest for every incoming OTLP/gRPC request.
+// requestHandler is called with ExportMetricsServiceRequest converted from every incoming OpenTelemetry Arrow batch.
+// Both handlers may return non-nil partial success if some data points from the request were rejected.
+//
+// If useProxyProtocol is set to true, then the incoming connections are accepted via proxy protocol.
+// See https://www.haproxy.org/download/1.8/doc/proxy-protocol.txt
+//
+// MustStop must be called on the returned server when it is no longer needed.
+func MustStart(addr string, useProxyProtocol bool, insertHandler func(r io.Reader) (*pb.ExportMetricsPartialSuccess, error), requestHandler func(req *pb.ExportMetricsServiceRequest) (*pb.ExportMetricsPartialSuccess, error)) *Server {
+	logger.Infof("starting OpenTelemetry gRPC server at %q", addr)
+	lnTCP, err := netutil.NewTCPListener("opentelemetry", addr, useProxyProtocol, nil)
+	if err != nil {
+		logger.Fatalf("cannot start OpenTelemetry gRPC server at %q: %s", addr, err)
+	}
+	return MustServe(lnTCP, insertHandler, requestHandler)
+}
+
+// MustServe serves OTLP/gRPC requests from ln.
+//
+// MustStop must be called on the returned server when it is no longer needed.
+func MustServe(ln net.Listener, insertHandler func(r io.Reader) (*pb.ExportMetricsPartialSuccess, error), requestHandler func(req *pb.ExportMetricsServiceRequest) (*pb.ExportMetricsPartialSuccess, error)) *Server {
+	gs := grpc.NewServer(grpc.ForceServerCodec(rawCodec{}), grpc.MaxRecvMsgSize(maxRequestSize), grpc.RecvBufferPool(grpc.NewSharedBufferPool()))
+	gs.RegisterService(newMetricsServiceDesc(insertHandler), nil)
+	gs.RegisterService(newArrowMetricsServiceDesc(requestHandler), nil)
+	s := &Server{
+		s:  gs,
+		ln: ln,
+	}
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		err := s.s.Serve(s.ln)
+		if err == grpc.ErrServerStopped {
+			return
+		}
+		if err != nil {
+			logger.Fatalf("error serving OpenTelemetry gRPC at %q: %s", s.ln.Addr(), err)
+		}
+	}()
+	return s
+}
+
+// MustStop stops OTLP/gRPC server.
+func (s *Server) MustStop() {
+	logger.Infof("stopping OpenTelemetry gRPC server at %q...", s.ln.Addr())
+	stopped := make(chan struct{})
+	go func() {
+		s.s.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		s.s.Stop()
+	}
+	s.wg.Wait()
+	logger.Infof("OpenTelemetry gRPC server at %q has been stopped", s.ln.Addr())
+}
+
+// newMetricsServiceDesc returns the description for opentelemetry.proto.collector.metrics.v1.MetricsService
+//
+// See https://github.com/open-telemetry/opentelemetry-proto/blob/34d29fe5ad4689b5db0259d3750de2bfa195bc85/opentelemetry/proto/collector/metrics/v1/metrics_service.proto
+func newMetricsServiceDesc(insertHandler func(r io.Reader) (*pb.ExportMetricsPartialSuccess, error)) *grpc.ServiceDesc {
+	exportHandler := func(_ any, _ context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+		writeRequests.Inc()
+		bb := requestBufPool.Get()
+		defer requestBufPool.Put(bb)
+		if err := dec(bb); err != nil {
+			writeErrors.Inc()
+			return nil, status.Errorf(codes.InvalidArgument, "cannot read request: %s", err)
+		}
+		ps, err := insertHandler(bytes.NewReader(bb.B))
+		if err != nil {
+			writeErrors.Inc()
+			return nil, getStatusError(err)
+		}
+		if ps == nil {
+			// Return empty ExportMetricsServiceResponse
+			return []byte{}, nil
+		}
+		resp := &pb.ExportMetricsServiceResponse{
+			PartialSuccess: ps,
+		}
+		return resp.MarshalProtobuf(nil), nil
+	}
+	return &grpc.ServiceDesc{
+		ServiceName: "opentelemetry.proto.collector.metrics.v1.MetricsService",
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: "Export",
+				Handler:    exportHandler,
+			},
+		},
+		Metadata: "opentelemetry/proto/collector/metrics/v1/metrics_service.proto",
+	}
+}
+
+// getStatusError converts err returned from insertHandler to gRPC status error.
+//
+// Temporary ingestion errors are returned with codes.Unavailable or codes.ResourceExhausted codes, so clients could retry the request.
+// Other errors are caused by invalid request data, so they are returned with codes.InvalidArgument code.
+// See https://opentelemetry.io/docs/specs/otlp/#failures
+func getStatusError(err error) error {
+	code := codes.InvalidArgument
+	var esc *httpserver.ErrorWithStatusCode
+	if errors.As(err, &esc) {
+		switch {
+		case esc.StatusCode == http.StatusTooManyRequests:
+			code = codes.ResourceExhausted
+		case esc.StatusCode >= 500:
+			code = codes.Unavailable
+		}
+	}
+	return status.Error(code, err.Error())
+}
+
+var requestBufPool bytesutil.ByteBufferPool
+
+// newArrowMetricsServiceDesc returns the description for opentelemetry.proto.experimental.arrow.v1.ArrowMetricsService
+//
+// See https://github.com/open-telemetry/otel-arrow/blob/main/proto/opentelemetry/proto/experimental/arrow/v1/arrow_service.proto
+func newArrowMetricsServiceDesc(requestHandler func(req *pb.ExportMetricsServiceRequest) (*pb.ExportMetricsPartialSuccess, error)) *grpc.ServiceDesc {
+	arrowMetricsHandler := func(_ any, stream grpc.ServerStream) error {
+		// OTel Arrow streams are stateful, so every stream needs its own consumer.
+		mc := arrow.NewMetricsConsumer()
+		bb := requestBufPool.Get()
+		defer requestBufPool.Put(bb)
+		var bar arrow.BatchArrowRecords
+		for {
+			if err := stream.RecvMsg(bb); err != nil {
+				if err == io.EOF {
+					return nil
+				}
+				return err
+			}
+			arrowWriteRequests.Inc()
+			bs := &arrow.BatchStatus{
+				StatusCode: arrow.StatusCodeOK,
+			}
+			if err := processArrowBatch(mc, &bar, bb.B, requestHandler); err != nil {
+				arrowWriteErrors.Inc()
+				bs.StatusCode = getArrowStatusCode(err)
+				bs.StatusMessage = err.Error()
+			}
+			bs.BatchID = bar.BatchID
+			if err := stream.SendMsg(bs.MarshalProtobuf(nil)); err != nil {
+				return err
+			}
+		}
+	}
+	return &grpc.ServiceDesc{
+		ServiceName: "opentelemetry.proto.experimental.arrow.v1.ArrowMetricsService",
+		HandlerType: (*any)(nil),
+		Streams: []grpc.StreamDesc{
+			{
+				StreamName:    "ArrowMetrics",
+				Handler:       arrowMetricsHandler,
+				ServerStreams: true,
+				ClientStreams: true,
+			},
+		},
+		Metadata: "opentelemetry/proto/experimental/arrow/v1/arrow_service.proto",
+	}
+}
+
+// processArrowBatch converts the Arrow batch at data into ExportMetricsServiceRequest and passes it to requestHandler.
+//
+// The converted request is passed to requestHandler as is, so it isn't marshaled into protobuf and parsed again.
+func processArrowBatch(mc *arrow.MetricsConsumer, bar *arrow.BatchArrowRecords, data []byte, requestHandler func(req *pb.ExportMetricsServiceRequest) (*pb.ExportMetricsPartialSuccess, error)) error {
+	return writeconcurrencylimiter.Do(len(data), func() error {
+		if err := bar.UnmarshalProtobuf(data); err != nil {
+			return fmt.Errorf("cannot unmarshal BatchArrowRecords: %w", err)
+		}
+		req, err := mc.Convert(bar)
+		if err != nil {
+			return err
+		}
+		if _, err := requestHandler(req); err != nil {
+			return err
+		}
+		return nil
+	})
+}
+
+// getArrowStatusCode returns the status code for the Arrow batch, which couldn't be processed because of err.
+//
+// Temporary errors are reported with StatusCodeUnavailable, so the client could retry the batch later.
+func getArrowStatusCode(err error) arrow.StatusCode {
+	var esc *httpserver.ErrorWithStatusCode
+	if errors.As(err, &esc) && (esc.StatusCode == http.StatusTooManyRequests || esc.StatusCode >= 500) {
+		return arrow.StatusCodeUnavailable
+	}
+	return arrow.StatusCodeInvalidArgument
+}
+
+// rawCodec passes protobuf-encoded messages as is, so they could be parsed with lib/protoparser/opentelemetry/pb.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	b, ok := v.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("BUG: unexpected type %T; want []byte", v)
+	}
+	return b, nil
+}
+
+// Unmarshal copies data to v, which must be *bytesutil.ByteBuffer obtained from a pool.
+//
+// data cannot be used after returning from Unmarshal, since it is returned to gRPC receive buffer pool.
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	bb, ok := v.(*bytesutil.ByteBuffer)
+	if !ok {
+		return fmt.Errorf("BUG: unexpected type %T; want *bytesutil.ByteBuffer", v)
+	}
+	bb.B = append(bb.B[:0], data...)
+	return nil
+}
+
+func (rawCodec) Name() string {
+	return "proto"
+}
+
+func init() {
+	// OTel Arrow exporter compresses gRPC messages with zstd by default.
+	encoding.RegisterCompressor(zstdCompressor{})
+}
+
+type zstdCompressor struct{}
+
+func (zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	return &zstdWriter{
+		w: w,
+	}, nil
+}
+
+func (zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	compressed, err := io.ReadAll(io.LimitReader(r, maxRequestSize))
+	if err != nil {
+		return nil, err
+	}
+	data, err := zstd.Decompress(nil, compressed)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decompress zstd-encoded message: %w", err)
+	}
+	return bytes.NewReader(data), nil
+}
+
+func (zstdCompressor) Name() string {
+	return "zstd"
+}
+
+// zstdWriter buffers the written data and writes it zstd-compressed to w on Close.
+type zstdWriter struct {
+	w   io.Writer
+	buf []byte
+}
+
+func (zw *zstdWriter) Write(p []byte) (int, error) {
+	zw.buf = append(zw.buf, p...)
+	return len(p), nil
+}
+
+func (zw *zstdWriter) Close() error {
+	_, err := zw.w.Write(zstd.CompressLevel(nil, zw.buf, 1))
+	return err
+}
@@ -0,0 +1,251 @@
+package opentelemetry
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/httpserver"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/arrow"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/stream"
+)
+
+func TestServerExport(t *testing.T) {
+	var mu sync.Mutex
+	var rows []string
+	var insertErr error
+	callback := func(tss []prompbmarshal.TimeSeries) error {
+		mu.Lock()
+		defer mu.Unlock()
+		if insertErr != nil {
+			return insertErr
+		}
+		for _, ts := range tss {
+			for _, s := range ts.Samples {
+				rows = append(rows, fmt.Sprintf("%s %v %d", labelsString(ts.Labels), s.Value, s.Timestamp))
+			}
+		}
+		return nil
+	}
+	insertHandler := func(r io.Reader) (*pb.ExportMetricsPartialSuccess, error) {
+		return stream.ParseStreamExt(r, "", nil, callback)
+	}
+	requestHandler := func(req *pb.ExportMetricsServiceRequest) (*pb.ExportMetricsPartialSuccess, error) {
+		return stream.ParseRequest(req, callback)
+	}
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("cannot start listener: %s", err)
+	}
+	s := MustServe(ln, insertHandler, requestHandler)
+	defer s.MustStop()
+
+	conn, err := grpc.NewClient(ln.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("cannot create gRPC client: %s", err)
+	}
+	defer func() {
+		_ = conn.Close()
+	}()
+
+	export := func(data []byte, compressor string) error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		opts := []grpc.CallOption{
+			grpc.ForceCodec(rawCodec{}),
+		}
+		if compressor != "" {
+			opts = append(opts, grpc.UseCompressor(compressor))
+		}
+		var resp bytesutil.ByteBuffer
+		return conn.Invoke(ctx, "/opentelemetry.proto.collector.metrics.v1.MetricsService/Export", data, &resp, opts...)
+	}
+
+	f := func(compressor string, v float64, rowsExpected []string) {
+		t.Helper()
+
+		mu.Lock()
+		rows = rows[:0]
+		mu.Unlock()
+
+		job := "test"
+		req := &pb.ExportMetricsServiceRequest{
+			ResourceMetrics: []*pb.ResourceMetrics{
+				{
+					Resource: &pb.Resource{
+						Attributes: []*pb.KeyValue{
+							{
+								Key: "job",
+								Value: &pb.AnyValue{
+									StringValue: &job,
+								},
+							},
+						},
+					},
+					ScopeMetrics: []*pb.ScopeMetrics{
+						{
+							Metrics: []*pb.Metric{
+								{
+									Name: "foo",
+									Gauge: &pb.Gauge{
+										DataPoints: []*pb.NumberDataPoint{
+											{
+												DoubleValue:  &v,
+												TimeUnixNano: uint64(15 * time.Second),
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		if err := export(req.MarshalProtobuf(nil), compressor); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if !reflect.DeepEqual(rows, rowsExpected) {
+			t.Fatalf("unexpected rows inserted\ngot\n%q\nwant\n%q", rows, rowsExpected)
+		}
+	}
+
+	// uncompressed request
+	f("", 1.5, []string{`foo{job="test"} 1.5 15000`})
+
+	// gzip-compressed request
+	f("gzip", 2.5, []string{`foo{job="test"} 2.5 15000`})
+
+	// zstd-compressed request
+	f("zstd", 3.5, []string{`foo{job="test"} 3.5 15000`})
+
+	fError := func(data []byte, codeExpected codes.Code) {
+		t.Helper()
+
+		err := export(data, "")
+		if err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+		if code := status.Code(err); code != codeExpected {
+			t.Fatalf("unexpected gRPC status code; got %s; want %s; error: %s", code, codeExpected, err)
+		}
+	}
+
+	// malformed request
+	fError([]byte("invalid protobuf"), codes.InvalidArgument)
+
+	// temporary ingestion errors
+	req := &pb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*pb.ResourceMetrics{
+			{
+				ScopeMetrics: []*pb.ScopeMetrics{
+					{
+						Metrics: []*pb.Metric{
+							{
+								Name: "foo",
+								Gauge: &pb.Gauge{
+									DataPoints: []*pb.NumberDataPoint{
+										{
+											TimeUnixNano: uint64(15 * time.Second),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	data := req.MarshalProtobuf(nil)
+	setInsertErr := func(err error) {
+		mu.Lock()
+		insertErr = err
+		mu.Unlock()
+	}
+	setInsertErr(&httpserver.ErrorWithStatusCode{
+		Err:        fmt.Errorf("the storage is in read-only mode"),
+		StatusCode: http.StatusServiceUnavailable,
+	})
+	fError(data, codes.Unavailable)
+	setInsertErr(&httpserver.ErrorWithStatusCode{
+		Err:        fmt.Errorf("the queue is full"),
+		StatusCode: http.StatusTooManyRequests,
+	})
+	fError(data, codes.ResourceExhausted)
+	setInsertErr(nil)
+}
+
+func labelsString(labels []prompbmarshal.Label) string {
+	var metricName string
+	var b []byte
+	for _, label := range labels {
+		if label.Name == "__name__" {
+			metricName = label.Value
+			continue
+		}
+		if len(b) > 0 {
+			b = append(b, ',')
+		}
+		b = fmt.Appendf(b, "%s=%q", label.Name, label.Value)
+	}
+	return fmt.Sprintf("%s{%s}", metricName, b)
+}
+
+func TestProcessArrowBatch(t *testing.T) {
+	var bar arrow.BatchArrowRecords
+	requestHandler := func(_ *pb.ExportMetricsServiceRequest) (*pb.ExportMetricsPartialSuccess, error) {
+		t.Fatalf("unexpected call for malformed batch")
+		return nil, nil
+	}
+	err := processArrowBatch(arrow.NewMetricsConsumer(), &bar, []byte("invalid protobuf"), requestHandler)
+	if err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+	if code := getArrowStatusCode(err); code != arrow.StatusCodeInvalidArgument {
+		t.Fatalf("unexpected status code for malformed batch; got %d; want %d", code, arrow.StatusCodeInvalidArgument)
+	}
+}
+
+func TestGetArrowStatusCode(t *testing.T) {
+	f := func(err error, codeExpected arrow.StatusCode) {
+		t.Helper()
+
+		if code := getArrowStatusCode(err); code != codeExpected {
+			t.Fatalf("unexpected status code; got %d; want %d", code, codeExpected)
+		}
+	}
+
+	f(fmt.Errorf("cannot parse request"), arrow.StatusCodeInvalidArgument)
+	f(&httpserver.ErrorWithStatusCode{
+		Err:        fmt.Errorf("bad request"),
+		StatusCode: http.StatusBadRequest,
+	}, arrow.StatusCodeInvalidArgument)
+	f(fmt.Errorf("wrapped: %w", &httpserver.ErrorWithStatusCode{
+		Err:        fmt.Errorf("the storage is in read-only mode"),
+		StatusCode: http.StatusServiceUnavailable,
+	}), arrow.StatusCodeUnavailable)
+	f(&httpserver.ErrorWithStatusCode{
+		Err:        fmt.Errorf("the queue is full"),
+		StatusCode: http.StatusTooManyRequests,
+	}, arrow.StatusCodeUnavailable)
+}
@@ -27,15 +27,19 @@ func Get(capacity int) *bytesutil.ByteBuffer {
 			break
 		}
 		if v := pools[id].Get(); v != nil {
+			poolStats.Update(0)
 			return v.(*bytesutil.ByteBuffer)
 		}
 		id++
 	}
+	poolStats.Update(capacityNeeded)
 	return &bytesutil.ByteBuffer{
 		B: make([]byte, 0, capacityNeeded),
 	}
 }
 
+var poolStats = bytesutil.NewPoolStats("leveledbytebufferpool")
+
 // Put returns bb to the pool.
 func Put(bb *bytesutil.ByteBuffer) {
 	capacity := cap(bb.B)
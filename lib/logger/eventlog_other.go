@@ -0,0 +1,7 @@
+//go:build !windows
+
+package logger
+
+func mustOpenEventLog() func(level, msg string) {
+	panic("FATAL: `-loggerOutput=eventlog` is supported only on Windows")
+}
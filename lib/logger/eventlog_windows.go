@@ -0,0 +1,39 @@
+//go:build windows
+
+package logger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/windows/svc/eventlog"
+)
+
+// mustOpenEventLog opens Windows event log for writing log messages.
+//
+// The executable name without extension is used as event source name.
+// The source can be registered with `New-EventLog -LogName Application -Source <name>` PowerShell command.
+func mustOpenEventLog() func(level, msg string) {
+	exe, err := os.Executable()
+	if err != nil {
+		panic(fmt.Errorf("FATAL: cannot determine executable name for Windows event log source: %w", err))
+	}
+	source := strings.TrimSuffix(filepath.Base(exe), filepath.Ext(exe))
+	el, err := eventlog.Open(source)
+	if err != nil {
+		panic(fmt.Errorf("FATAL: cannot open Windows event log for source %q: %w", source, err))
+	}
+	return func(level, msg string) {
+		const eventID = 1
+		switch level {
+		case "INFO":
+			_ = el.Info(eventID, msg)
+		case "WARN":
+			_ = el.Warning(eventID, msg)
+		default:
+			_ = el.Error(eventID, msg)
+		}
+	}
+}
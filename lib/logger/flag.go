@@ -13,7 +13,7 @@ func logAllFlags() {
 	Infof("command-line flags")
 	flag.Visit(func(f *flag.Flag) {
 		lname := strings.ToLower(f.Name)
-		value := f.Value.String()
+		value := flagutil.GetFlagValue(f)
 		if flagutil.IsSecretFlag(lname) {
 			value = "secret"
 		}
@@ -0,0 +1,53 @@
+package logger
+
+import (
+	"sync"
+	"time"
+)
+
+// LastError contains information about the recently logged error message.
+type LastError struct {
+	// Timestamp is the time when the error has been logged.
+	Timestamp time.Time
+
+	// Location is the location in the source code where the error has been logged.
+	Location string
+
+	// Msg is the error message.
+	Msg string
+}
+
+// maxLastErrors is the maximum number of recently logged errors returned from GetLastErrors.
+const maxLastErrors = 10
+
+var (
+	lastErrors     []LastError
+	lastErrorsLock sync.Mutex
+)
+
+func rememberLastError(location, msg string) {
+	lastErrorsLock.Lock()
+	defer lastErrorsLock.Unlock()
+
+	if len(lastErrors) >= maxLastErrors {
+		copy(lastErrors, lastErrors[1:])
+		lastErrors = lastErrors[:maxLastErrors-1]
+	}
+	lastErrors = append(lastErrors, LastError{
+		Timestamp: time.Now(),
+		Location:  location,
+		Msg:       msg,
+	})
+}
+
+// GetLastErrors returns up to 10 recently logged error messages starting from the most recent one.
+func GetLastErrors() []LastError {
+	lastErrorsLock.Lock()
+	defer lastErrorsLock.Unlock()
+
+	result := make([]LastError, len(lastErrors))
+	for i := range lastErrors {
+		result[i] = lastErrors[len(lastErrors)-1-i]
+	}
+	return result
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/buildinfo"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/stringsutil"
 	"github.com/VictoriaMetrics/metrics"
 )
@@ -20,7 +21,7 @@ import (
 var (
 	loggerLevel    = flag.String("loggerLevel", "INFO", "Minimum level of errors to log. Possible values: INFO, WARN, ERROR, FATAL, PANIC")
 	loggerFormat   = flag.String("loggerFormat", "default", "Format for logs. Possible values: default, json")
-	loggerOutput   = flag.String("loggerOutput", "stderr", "Output for the logs. Supported values: stderr, stdout")
+	loggerOutput   = flag.String("loggerOutput", "stderr", "Output for the logs. Supported values: stderr, stdout, eventlog. The eventlog output writes logs to Windows event log and is supported only on Windows. See https://docs.victoriametrics.com/#running-as-windows-service")
 	loggerTimezone = flag.String("loggerTimezone", "UTC", "Timezone to use for timestamps in logs. Timezone must be a valid IANA Time Zone. "+
 		"For example: America/New_York, Europe/Berlin, Etc/GMT+3 or Local")
 	disableTimestamps = flag.Bool("loggerDisableTimestamps", false, "Whether to disable writing timestamps in logs")
@@ -62,22 +63,39 @@ func setLoggerOutput() {
 		output = os.Stderr
 	case "stdout":
 		output = os.Stdout
+	case "eventlog":
+		writeEventLog = mustOpenEventLog()
 	default:
-		panic(fmt.Errorf("FATAL: unsupported `loggerOutput` value: %q; supported values are: stderr, stdout", *loggerOutput))
+		panic(fmt.Errorf("FATAL: unsupported `loggerOutput` value: %q; supported values are: stderr, stdout, eventlog", *loggerOutput))
 	}
 }
 
 var output io.Writer = os.Stderr
 
+// writeEventLog writes log messages to Windows event log if it isn't nil.
+var writeEventLog func(level, msg string)
+
 func validateLoggerLevel() {
-	switch *loggerLevel {
+	if err := checkLoggerLevel(*loggerLevel); err != nil {
+		// We cannot use logger.Panicf here, since the logger isn't initialized yet.
+		panic(fmt.Errorf("FATAL: %w", err))
+	}
+}
+
+func checkLoggerLevel(level string) error {
+	switch level {
 	case "INFO", "WARN", "ERROR", "FATAL", "PANIC":
+		return nil
 	default:
-		// We cannot use logger.Panicf here, since the logger isn't initialized yet.
-		panic(fmt.Errorf("FATAL: unsupported `-loggerLevel` value: %q; supported values are: INFO, WARN, ERROR, FATAL, PANIC", *loggerLevel))
+		return fmt.Errorf("unsupported `-loggerLevel` value: %q; supported values are: INFO, WARN, ERROR, FATAL, PANIC", level)
 	}
 }
 
+// loggerLevelRuntime allows changing -loggerLevel at runtime.
+var loggerLevelRuntime = flagutil.NewRuntimeValue("loggerLevel", loggerLevel, func(s string) (string, error) {
+	return s, checkLoggerLevel(s)
+})
+
 func validateLoggerFormat() {
 	switch *loggerFormat {
 	case "default", "json":
@@ -285,9 +303,17 @@ func logMessage(level, msg string, skipframes int) {
 
 	// Serialize writes to log.
 	mu.Lock()
-	fmt.Fprint(output, logMsg)
+	if writeEventLog != nil {
+		writeEventLog(level, logMsg)
+	} else {
+		fmt.Fprint(output, logMsg)
+	}
 	mu.Unlock()
 
+	if level == "ERROR" {
+		rememberLastError(location, msg)
+	}
+
 	// Increment vm_log_messages_total
 	counterName := fmt.Sprintf(`vm_log_messages_total{app_version=%q, level=%q, location=%q}`, buildinfo.Version, levelLowercase, location)
 	metrics.GetOrCreateCounter(counterName).Inc()
@@ -307,7 +333,7 @@ func logMessage(level, msg string, skipframes int) {
 var mu sync.Mutex
 
 func shouldSkipLog(level string) bool {
-	switch *loggerLevel {
+	switch loggerLevelRuntime.Get() {
 	case "WARN":
 		switch level {
 		case "WARN", "ERROR", "FATAL", "PANIC":
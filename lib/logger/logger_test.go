@@ -23,3 +23,26 @@ func TestFormatLogMessage(t *testing.T) {
 	// Format args exceeding the maxArgLen
 	f("foo: %s, %q, %s", []any{"abcde", fmt.Errorf("foo bar baz"), "xx"}, 4, `foo: a..e, "f..z", xx`)
 }
+
+func TestGetLastErrors(t *testing.T) {
+	lastErrorsLock.Lock()
+	lastErrors = nil
+	lastErrorsLock.Unlock()
+
+	for i := 0; i < maxLastErrors+5; i++ {
+		rememberLastError("foo.go:123", fmt.Sprintf("error %d", i))
+	}
+	errs := GetLastErrors()
+	if len(errs) != maxLastErrors {
+		t.Fatalf("unexpected number of last errors; got %d; want %d", len(errs), maxLastErrors)
+	}
+	for i, e := range errs {
+		msgExpected := fmt.Sprintf("error %d", maxLastErrors+4-i)
+		if e.Msg != msgExpected {
+			t.Fatalf("unexpected message at position %d; got %q; want %q", i, e.Msg, msgExpected)
+		}
+		if e.Location != "foo.go:123" {
+			t.Fatalf("unexpected location at position %d; got %q; want %q", i, e.Location, "foo.go:123")
+		}
+	}
+}
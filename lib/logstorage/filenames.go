@@ -10,8 +10,9 @@ const (
 	messageValuesFilename = "message_values.bin"
 	messageBloomFilename  = "message_bloom.bin"
 
-	metadataFilename = "metadata.json"
-	partsFilename    = "parts.json"
+	metadataFilename      = "metadata.json"
+	partsFilename         = "parts.json"
+	indexedFieldsFilename = "indexed_fields.json"
 
 	streamIDCacheFilename = "stream_id.bin"
 
@@ -0,0 +1,266 @@
+package logstorage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+)
+
+// filterExactSuffix matches the exact suffix.
+//
+// Example LogsQL: `fieldName:ends_with("foo bar")`
+type filterExactSuffix struct {
+	fieldName string
+	suffix    string
+
+	tokensOnce sync.Once
+	tokens     []string
+}
+
+func (fes *filterExactSuffix) String() string {
+	return fmt.Sprintf("%sends_with(%s)", quoteFieldNameIfNeeded(fes.fieldName), quoteTokenIfNeeded(fes.suffix))
+}
+
+func (fes *filterExactSuffix) updateNeededFields(neededFields fieldsSet) {
+	neededFields.add(fes.fieldName)
+}
+
+func (fes *filterExactSuffix) getTokens() []string {
+	fes.tokensOnce.Do(fes.initTokens)
+	return fes.tokens
+}
+
+func (fes *filterExactSuffix) initTokens() {
+	fes.tokens = getTokensSkipFirst(fes.suffix)
+}
+
+func (fes *filterExactSuffix) applyToBlockResult(br *blockResult, bm *bitmap) {
+	if fes.suffix == "" {
+		// An empty suffix matches all the values
+		return
+	}
+	applyToBlockResultGeneric(br, bm, fes.fieldName, fes.suffix, matchExactSuffix)
+}
+
+func (fes *filterExactSuffix) applyToBlockSearch(bs *blockSearch, bm *bitmap) {
+	fieldName := fes.fieldName
+	suffix := fes.suffix
+
+	v := bs.csh.getConstColumnValue(fieldName)
+	if v != "" {
+		if !matchExactSuffix(v, suffix) {
+			bm.resetBits()
+		}
+		return
+	}
+
+	// Verify whether filter matches other columns
+	ch := bs.csh.getColumnHeader(fieldName)
+	if ch == nil {
+		// Fast path - there are no matching columns.
+		if !matchExactSuffix("", suffix) {
+			bm.resetBits()
+		}
+		return
+	}
+
+	tokens := fes.getTokens()
+
+	switch ch.valueType {
+	case valueTypeString:
+		matchStringByExactSuffix(bs, ch, bm, suffix, tokens)
+	case valueTypeDict:
+		matchValuesDictByExactSuffix(bs, ch, bm, suffix)
+	case valueTypeUint8:
+		matchUint8ByExactSuffix(bs, ch, bm, suffix)
+	case valueTypeUint16:
+		matchUint16ByExactSuffix(bs, ch, bm, suffix)
+	case valueTypeUint32:
+		matchUint32ByExactSuffix(bs, ch, bm, suffix)
+	case valueTypeUint64:
+		matchUint64ByExactSuffix(bs, ch, bm, suffix)
+	case valueTypeFloat64:
+		matchFloat64ByExactSuffix(bs, ch, bm, suffix, tokens)
+	case valueTypeIPv4:
+		matchIPv4ByExactSuffix(bs, ch, bm, suffix, tokens)
+	case valueTypeTimestampISO8601:
+		matchTimestampISO8601ByExactSuffix(bs, ch, bm, suffix, tokens)
+	default:
+		logger.Panicf("FATAL: %s: unknown valueType=%d", bs.partPath(), ch.valueType)
+	}
+}
+
+func matchTimestampISO8601ByExactSuffix(bs *blockSearch, ch *columnHeader, bm *bitmap, suffix string, tokens []string) {
+	if suffix == "" {
+		return
+	}
+	if len(suffix) > len(iso8601Timestamp) || !matchBloomFilterAllTokens(bs, ch, tokens) {
+		bm.resetBits()
+		return
+	}
+
+	bb := bbPool.Get()
+	visitValues(bs, ch, bm, func(v string) bool {
+		s := toTimestampISO8601String(bs, bb, v)
+		return matchExactSuffix(s, suffix)
+	})
+	bbPool.Put(bb)
+}
+
+func matchIPv4ByExactSuffix(bs *blockSearch, ch *columnHeader, bm *bitmap, suffix string, tokens []string) {
+	if suffix == "" {
+		return
+	}
+	if len(suffix) > len("255.255.255.255") || len(tokens) > 3 || !matchBloomFilterAllTokens(bs, ch, tokens) {
+		bm.resetBits()
+		return
+	}
+
+	bb := bbPool.Get()
+	visitValues(bs, ch, bm, func(v string) bool {
+		s := toIPv4String(bs, bb, v)
+		return matchExactSuffix(s, suffix)
+	})
+	bbPool.Put(bb)
+}
+
+func matchFloat64ByExactSuffix(bs *blockSearch, ch *columnHeader, bm *bitmap, suffix string, tokens []string) {
+	if suffix == "" {
+		// An empty suffix matches all the values
+		return
+	}
+	if len(tokens) > 2 || !matchBloomFilterAllTokens(bs, ch, tokens) {
+		bm.resetBits()
+		return
+	}
+
+	bb := bbPool.Get()
+	visitValues(bs, ch, bm, func(v string) bool {
+		s := toFloat64String(bs, bb, v)
+		return matchExactSuffix(s, suffix)
+	})
+	bbPool.Put(bb)
+}
+
+func matchValuesDictByExactSuffix(bs *blockSearch, ch *columnHeader, bm *bitmap, suffix string) {
+	bb := bbPool.Get()
+	for _, v := range ch.valuesDict.values {
+		c := byte(0)
+		if matchExactSuffix(v, suffix) {
+			c = 1
+		}
+		bb.B = append(bb.B, c)
+	}
+	matchEncodedValuesDict(bs, ch, bm, bb.B)
+	bbPool.Put(bb)
+}
+
+func matchStringByExactSuffix(bs *blockSearch, ch *columnHeader, bm *bitmap, suffix string, tokens []string) {
+	if !matchBloomFilterAllTokens(bs, ch, tokens) {
+		bm.resetBits()
+		return
+	}
+	visitValues(bs, ch, bm, func(v string) bool {
+		return matchExactSuffix(v, suffix)
+	})
+}
+
+func matchUint8ByExactSuffix(bs *blockSearch, ch *columnHeader, bm *bitmap, suffix string) {
+	if !matchMinMaxExactSuffix(ch, bm, suffix) {
+		return
+	}
+
+	bb := bbPool.Get()
+	visitValues(bs, ch, bm, func(v string) bool {
+		s := toUint8String(bs, bb, v)
+		return matchExactSuffix(s, suffix)
+	})
+	bbPool.Put(bb)
+}
+
+func matchUint16ByExactSuffix(bs *blockSearch, ch *columnHeader, bm *bitmap, suffix string) {
+	if !matchMinMaxExactSuffix(ch, bm, suffix) {
+		return
+	}
+
+	bb := bbPool.Get()
+	visitValues(bs, ch, bm, func(v string) bool {
+		s := toUint16String(bs, bb, v)
+		return matchExactSuffix(s, suffix)
+	})
+	bbPool.Put(bb)
+}
+
+func matchUint32ByExactSuffix(bs *blockSearch, ch *columnHeader, bm *bitmap, suffix string) {
+	if !matchMinMaxExactSuffix(ch, bm, suffix) {
+		return
+	}
+
+	bb := bbPool.Get()
+	visitValues(bs, ch, bm, func(v string) bool {
+		s := toUint32String(bs, bb, v)
+		return matchExactSuffix(s, suffix)
+	})
+	bbPool.Put(bb)
+}
+
+func matchUint64ByExactSuffix(bs *blockSearch, ch *columnHeader, bm *bitmap, suffix string) {
+	if !matchMinMaxExactSuffix(ch, bm, suffix) {
+		return
+	}
+
+	bb := bbPool.Get()
+	visitValues(bs, ch, bm, func(v string) bool {
+		s := toUint64String(bs, bb, v)
+		return matchExactSuffix(s, suffix)
+	})
+	bbPool.Put(bb)
+}
+
+func matchMinMaxExactSuffix(ch *columnHeader, bm *bitmap, suffix string) bool {
+	if suffix == "" {
+		// An empty suffix matches all the values
+		return false
+	}
+	if !isDecimalDigits(suffix) {
+		// Uint values may contain only decimal digits.
+		bm.resetBits()
+		return false
+	}
+	maxLen := len(strconv.FormatUint(ch.maxValue, 10))
+	if len(suffix) > maxLen {
+		// The suffix is longer than the longest value in the block.
+		bm.resetBits()
+		return false
+	}
+	return true
+}
+
+func isDecimalDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func matchExactSuffix(s, suffix string) bool {
+	return strings.HasSuffix(s, suffix)
+}
+
+// getTokensSkipFirst returns tokens from s except of the first token, which may be incomplete.
+func getTokensSkipFirst(s string) []string {
+	for {
+		r, runeSize := utf8.DecodeRuneInString(s)
+		if !isTokenRune(r) {
+			break
+		}
+		s = s[runeSize:]
+	}
+	return tokenizeStrings(nil, []string{s})
+}
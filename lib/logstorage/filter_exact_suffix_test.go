@@ -0,0 +1,655 @@
+package logstorage
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFilterExactSuffix(t *testing.T) {
+	t.Parallel()
+
+	t.Run("single-row", func(t *testing.T) {
+		t.Parallel()
+
+		columns := []column{
+			{
+				name: "foo",
+				values: []string{
+					"abc def",
+				},
+			},
+		}
+
+		// match
+		fes := &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "abc def",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{0})
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "c def",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{0})
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{0})
+
+		fes = &filterExactSuffix{
+			fieldName: "non-existing-column",
+			suffix:    "",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{0})
+
+		// mismatch
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "abcx",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+
+		fes = &filterExactSuffix{
+			fieldName: "non-existing column",
+			suffix:    "def",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+	})
+
+	t.Run("const-column", func(t *testing.T) {
+		t.Parallel()
+
+		columns := []column{
+			{
+				name: "foo",
+				values: []string{
+					"abc def",
+					"abc def",
+					"abc def",
+				},
+			},
+		}
+
+		// match
+		fes := &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "abc def",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{0, 1, 2})
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "ef",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{0, 1, 2})
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{0, 1, 2})
+
+		fes = &filterExactSuffix{
+			fieldName: "non-existing-column",
+			suffix:    "",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{0, 1, 2})
+
+		// mismatch
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "foobar",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+
+		fes = &filterExactSuffix{
+			fieldName: "non-existing column",
+			suffix:    "x",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+	})
+
+	t.Run("dict", func(t *testing.T) {
+		t.Parallel()
+
+		columns := []column{
+			{
+				name: "foo",
+				values: []string{
+					"",
+					"foobar",
+					"abc",
+					"afdf foobar",
+					"fddf foobarbaz",
+					"foobarbaz",
+					"foobar",
+				},
+			},
+		}
+
+		// match
+		fes := &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "foobar",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{1, 3, 6})
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{0, 1, 2, 3, 4, 5, 6})
+
+		// mismatch
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "foo",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+
+		fes = &filterExactSuffix{
+			fieldName: "non-existing column",
+			suffix:    "foobar",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+	})
+
+	t.Run("strings", func(t *testing.T) {
+		t.Parallel()
+
+		columns := []column{
+			{
+				name: "foo",
+				values: []string{
+					"a foo",
+					"a foobar",
+					"aa abc a",
+					"ca afdf a,foobar baz",
+					"aa fddf foobarbaz",
+					"a afoobarbaz",
+					"a foobar baz",
+					"a kjlkjf dfff",
+					"a ТЕСТЙЦУК НГКШ ",
+					"a !!,23.(!1)",
+				},
+			},
+		}
+
+		// match
+		fes := &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "bar baz",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{3, 6})
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "r baz",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{3, 6})
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "НГКШ ",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{8})
+
+		fes = &filterExactSuffix{
+			fieldName: "non-existing-column",
+			suffix:    "",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9})
+
+		// mismatch
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "ar ba",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "fobar",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+
+		fes = &filterExactSuffix{
+			fieldName: "non-existing-column",
+			suffix:    "az",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+	})
+
+	t.Run("uint8", func(t *testing.T) {
+		t.Parallel()
+
+		columns := []column{
+			{
+				name: "foo",
+				values: []string{
+					"123",
+					"12",
+					"32",
+					"0",
+					"0",
+					"12",
+					"1",
+					"2",
+					"3",
+					"4",
+					"5",
+				},
+			},
+		}
+
+		// match
+		fes := &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "2",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{1, 2, 5, 7})
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "23",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{0})
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+
+		// mismatch
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "bar",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "1234",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "7",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+	})
+
+	t.Run("uint16", func(t *testing.T) {
+		t.Parallel()
+
+		columns := []column{
+			{
+				name: "foo",
+				values: []string{
+					"123",
+					"12",
+					"32",
+					"0",
+					"0",
+					"12",
+					"1",
+					"2",
+					"3",
+					"467",
+					"5",
+				},
+			},
+		}
+
+		// match
+		fes := &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "2",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{1, 2, 5, 7})
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "67",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{9})
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+
+		// mismatch
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "bar",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "99999",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "8",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+	})
+
+	t.Run("uint32", func(t *testing.T) {
+		t.Parallel()
+
+		columns := []column{
+			{
+				name: "foo",
+				values: []string{
+					"123",
+					"12",
+					"32",
+					"0",
+					"0",
+					"12",
+					"1",
+					"2",
+					"3",
+					"65536",
+					"5",
+				},
+			},
+		}
+
+		// match
+		fes := &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "2",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{1, 2, 5, 7})
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "536",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{9})
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+
+		// mismatch
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "bar",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "999999",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "7",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+	})
+
+	t.Run("uint64", func(t *testing.T) {
+		t.Parallel()
+
+		columns := []column{
+			{
+				name: "foo",
+				values: []string{
+					"123",
+					"12",
+					"32",
+					"0",
+					"0",
+					"12",
+					"1",
+					"2",
+					"3",
+					"123456789012",
+					"5",
+				},
+			},
+		}
+
+		// match
+		fes := &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "12",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{1, 5, 9})
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10})
+
+		// mismatch
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "bar",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "1234567890123",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "7",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+	})
+
+	t.Run("float64", func(t *testing.T) {
+		t.Parallel()
+
+		columns := []column{
+			{
+				name: "foo",
+				values: []string{
+					"1234",
+					"0",
+					"3454",
+					"-65536",
+					"1234.5678901",
+					"1",
+					"2",
+					"3",
+					"4",
+				},
+			},
+		}
+
+		// match
+		fes := &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "34",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{0})
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "78901",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{4})
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "4",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{0, 2, 8})
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{0, 1, 2, 3, 4, 5, 6, 7, 8})
+
+		// mismatch
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "bar",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "6511",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+	})
+
+	t.Run("ipv4", func(t *testing.T) {
+		t.Parallel()
+
+		columns := []column{
+			{
+				name: "foo",
+				values: []string{
+					"1.2.3.4",
+					"0.0.0.0",
+					"127.0.0.1",
+					"254.255.255.255",
+					"127.0.0.2",
+					"127.0.0.1",
+					"127.0.4.2",
+					"127.0.0.1",
+					"12.0.127.6",
+					"55.55.55.55",
+					"66.66.66.66",
+					"7.7.7.7",
+				},
+			},
+		}
+
+		// match
+		fes := &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    ".0.1",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{2, 5, 7})
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "2",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{4, 6})
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11})
+
+		// mismatch
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "bar",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "0.0.0.0.0",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+
+		fes = &filterExactSuffix{
+			fieldName: "foo",
+			suffix:    "3",
+		}
+		testFilterMatchForColumns(t, columns, fes, "foo", nil)
+	})
+
+	t.Run("timestamp-iso8601", func(t *testing.T) {
+		t.Parallel()
+
+		columns := []column{
+			{
+				name: "_msg",
+				values: []string{
+					"2006-01-02T15:04:05.001Z",
+					"2006-01-02T15:04:05.002Z",
+					"2006-01-02T15:04:05.003Z",
+					"2006-01-02T15:04:06.004Z",
+					"2006-01-02T15:04:06.005Z",
+					"2006-01-02T15:04:07.006Z",
+					"2006-01-02T15:04:10.007Z",
+					"2006-01-02T15:04:12.008Z",
+					"2006-01-02T15:04:15.009Z",
+				},
+			},
+		}
+
+		// match
+		fes := &filterExactSuffix{
+			fieldName: "_msg",
+			suffix:    "05.002Z",
+		}
+		testFilterMatchForColumns(t, columns, fes, "_msg", []int{1})
+
+		fes = &filterExactSuffix{
+			fieldName: "_msg",
+			suffix:    "Z",
+		}
+		testFilterMatchForColumns(t, columns, fes, "_msg", []int{0, 1, 2, 3, 4, 5, 6, 7, 8})
+
+		fes = &filterExactSuffix{
+			fieldName: "_msg",
+			suffix:    "",
+		}
+		testFilterMatchForColumns(t, columns, fes, "_msg", []int{0, 1, 2, 3, 4, 5, 6, 7, 8})
+
+		// mismatch
+		fes = &filterExactSuffix{
+			fieldName: "_msg",
+			suffix:    "bar",
+		}
+		testFilterMatchForColumns(t, columns, fes, "_msg", nil)
+
+		fes = &filterExactSuffix{
+			fieldName: "_msg",
+			suffix:    "05.002",
+		}
+		testFilterMatchForColumns(t, columns, fes, "_msg", nil)
+	})
+}
+
+func TestGetTokensSkipFirst(t *testing.T) {
+	f := func(s string, tokensExpected []string) {
+		t.Helper()
+
+		tokens := getTokensSkipFirst(s)
+		if !reflect.DeepEqual(tokens, tokensExpected) {
+			t.Fatalf("unexpected tokens for %q; got %q; want %q", s, tokens, tokensExpected)
+		}
+	}
+
+	f("", nil)
+	f("foo", nil)
+	f("foo bar", []string{"bar"})
+	f(" foo bar", []string{"foo", "bar"})
+	f("o.bar-baz", []string{"bar", "baz"})
+	f("фу бар", []string{"бар"})
+}
@@ -1,6 +1,8 @@
 package logstorage
 
 import (
+	"math"
+	"time"
 	"unicode/utf8"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
@@ -8,7 +10,7 @@ import (
 
 // filterLenRange matches field values with the length in the given range [minLen, maxLen].
 //
-// Example LogsQL: `fieldName:len_range(10, 20)`
+// Example LogsQL: `fieldName:len_range(10, 20)` or `fieldName:len(10)`
 type filterLenRange struct {
 	fieldName string
 	minLen    uint64
@@ -18,7 +20,7 @@ type filterLenRange struct {
 }
 
 func (fr *filterLenRange) String() string {
-	return quoteFieldNameIfNeeded(fr.fieldName) + "len_range" + fr.stringRepr
+	return quoteFieldNameIfNeeded(fr.fieldName) + fr.stringRepr
 }
 
 func (fr *filterLenRange) updateNeededFields(neededFields fieldsSet) {
@@ -142,6 +144,11 @@ func (fr *filterLenRange) applyToBlockSearch(bs *blockSearch, bm *bitmap) {
 		}
 		return
 	}
+	if minLen > getMaxValueLen(&bs.bsw.bh, ch) {
+		// Fast path - the block cannot contain values with at least minLen bytes.
+		bm.resetBits()
+		return
+	}
 
 	switch ch.valueType {
 	case valueTypeString:
@@ -167,6 +174,23 @@ func (fr *filterLenRange) applyToBlockSearch(bs *blockSearch, bm *bitmap) {
 	}
 }
 
+// getMaxValueLen returns the upper bound for the length of values in the column ch of the block with the given bh.
+//
+// The bound is derived from bh.uncompressedSizeBytes (see block.uncompressedSizeBytes), so it doesn't require reading column values.
+// It is exact for blocks with a single non-const column, which has a single non-empty value.
+func getMaxValueLen(bh *blockHeader, ch *columnHeader) uint64 {
+	nameLen := uint64(len(ch.name))
+	if nameLen == 0 {
+		nameLen = uint64(len("_msg"))
+	}
+	n := bh.rowsCount*uint64(len(time.RFC3339Nano)) + nameLen + 2
+	if bh.uncompressedSizeBytes < n {
+		// This shouldn't happen, but do not skip the block just in case.
+		return math.MaxUint64
+	}
+	return bh.uncompressedSizeBytes - n
+}
+
 func matchTimestampISO8601ByLenRange(bm *bitmap, minLen, maxLen uint64) {
 	if minLen > uint64(len(iso8601Timestamp)) || maxLen < uint64(len(iso8601Timestamp)) {
 		bm.resetBits()
@@ -1,6 +1,7 @@
 package logstorage
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -32,6 +33,82 @@ func TestMatchLenRange(t *testing.T) {
 	f("ФЫВА", 0, 10, true)
 }
 
+func TestGetMaxValueLen(t *testing.T) {
+	t.Parallel()
+
+	f := func(rows [][]Field, maxLensExpected map[string]uint64) {
+		t.Helper()
+
+		timestamps := make([]int64, len(rows))
+		b := getBlock()
+		defer putBlock(b)
+		b.MustInitFromRows(timestamps, rows)
+
+		bh := &blockHeader{
+			uncompressedSizeBytes: b.uncompressedSizeBytes(),
+			rowsCount:             uint64(b.Len()),
+		}
+		maxLens := make(map[string]uint64)
+		for _, c := range b.columns {
+			ch := &columnHeader{
+				name: c.name,
+			}
+			maxLen := getMaxValueLen(bh, ch)
+			for _, v := range c.values {
+				if uint64(len(v)) > maxLen {
+					t.Fatalf("too small max value len for column %q; got %d; want at least %d", c.name, maxLen, len(v))
+				}
+			}
+			if _, ok := maxLensExpected[c.name]; ok {
+				maxLens[c.name] = maxLen
+			}
+		}
+		if !reflect.DeepEqual(maxLens, maxLensExpected) {
+			t.Fatalf("unexpected max value lens; got %v; want %v", maxLens, maxLensExpected)
+		}
+	}
+
+	// The only column with a single non-empty value - the max len is exact,
+	// so len() filters for longer values skip the block without reading column values.
+	f([][]Field{
+		{
+			{Name: "foo", Value: "abc"},
+		},
+		{
+			{Name: "foo", Value: ""},
+		},
+	}, map[string]uint64{
+		"foo": 3,
+	})
+	f([][]Field{
+		{
+			{Name: "", Value: "some message"},
+		},
+		{
+			{Name: "", Value: ""},
+		},
+	}, map[string]uint64{
+		"": 12,
+	})
+
+	// Multiple columns - the max len is an upper bound
+	f([][]Field{
+		{
+			{Name: "", Value: "some message"},
+			{Name: "foo", Value: "bar"},
+		},
+		{
+			{Name: "", Value: "other message"},
+			{Name: "foo", Value: ""},
+			{Name: "baz", Value: "ФЫВА"},
+		},
+		{
+			{Name: "", Value: "x"},
+			{Name: "foo", Value: "a long value for foo field"},
+		},
+	}, map[string]uint64{})
+}
+
 func TestFilterLenRange(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,400 @@
+package logstorage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/big"
+	"net/netip"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// geoIPDB is a read-only database in MaxMind DB format.
+//
+// See https://maxmind.github.io/MaxMind-DB/
+type geoIPDB struct {
+	// path is the path to the database file
+	path string
+
+	// tree is the binary search tree section of the database
+	tree []byte
+
+	// data is the data section of the database
+	data []byte
+
+	nodeCount  uint32
+	recordSize uint32
+	ipVersion  uint32
+
+	// ipv4Start is the node to start IPv4 lookups from in IPv6 database
+	ipv4Start uint32
+
+	// recordsLock protects records
+	recordsLock sync.RWMutex
+
+	// records caches decoded records by their offsets in data section.
+	//
+	// Many IP networks share the same record, so this avoids decoding it on every lookup.
+	records map[uint64]map[string]any
+}
+
+// maxGeoIPRecordsCacheSize is the maximum number of decoded records cached per geoIPDB.
+const maxGeoIPRecordsCacheSize = 100_000
+
+// maxGeoIPValueDepth is the maximum nesting depth for values in MaxMind DB data section.
+//
+// It protects from stack overflow on malformed databases with pointer loops.
+const maxGeoIPValueDepth = 512
+
+// geoIPMetadataMarker is the marker in front of the database metadata
+var geoIPMetadataMarker = []byte("\xab\xcd\xefMaxMind.com")
+
+func openGeoIPDB(path string) (*geoIPDB, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	db, err := newGeoIPDB(b)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %q: %w", path, err)
+	}
+	db.path = path
+	return db, nil
+}
+
+func newGeoIPDB(b []byte) (*geoIPDB, error) {
+	n := bytes.LastIndex(b, geoIPMetadataMarker)
+	if n < 0 {
+		return nil, fmt.Errorf("cannot find metadata marker; the file isn't in MaxMind DB format")
+	}
+	md := b[n+len(geoIPMetadataMarker):]
+	v, _, err := decodeGeoIPValue(md, 0)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode metadata: %w", err)
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected metadata type %T; want map", v)
+	}
+	getUint := func(name string) (uint32, error) {
+		v, ok := m[name].(uint64)
+		if !ok || v > math.MaxUint32 {
+			return 0, fmt.Errorf("missing or invalid %q in metadata", name)
+		}
+		return uint32(v), nil
+	}
+
+	var db geoIPDB
+	if db.nodeCount, err = getUint("node_count"); err != nil {
+		return nil, err
+	}
+	if db.recordSize, err = getUint("record_size"); err != nil {
+		return nil, err
+	}
+	if db.ipVersion, err = getUint("ip_version"); err != nil {
+		return nil, err
+	}
+	switch db.recordSize {
+	case 24, 28, 32:
+	default:
+		return nil, fmt.Errorf("unsupported record_size=%d; supported values: 24, 28, 32", db.recordSize)
+	}
+	switch db.ipVersion {
+	case 4, 6:
+	default:
+		return nil, fmt.Errorf("unsupported ip_version=%d; supported values: 4, 6", db.ipVersion)
+	}
+
+	treeSize := uint64(db.nodeCount) * uint64(db.recordSize) / 4
+	if treeSize+16 > uint64(n) {
+		return nil, fmt.Errorf("too small file size for the search tree with %d nodes", db.nodeCount)
+	}
+	db.tree = b[:treeSize]
+	db.data = b[treeSize+16 : n]
+
+	if db.ipVersion == 6 {
+		// IPv4 addresses are stored at ::/96 subtree of IPv6 database
+		node := uint32(0)
+		for i := 0; i < 96 && node < db.nodeCount; i++ {
+			node = db.readRecord(node, 0)
+		}
+		db.ipv4Start = node
+	}
+
+	return &db, nil
+}
+
+// readRecord returns the left (bit=0) or the right (bit=1) record for the given node.
+func (db *geoIPDB) readRecord(node uint32, bit byte) uint32 {
+	switch db.recordSize {
+	case 24:
+		b := db.tree[node*6+uint32(bit)*3:]
+		return uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2])
+	case 28:
+		b := db.tree[node*7:]
+		if bit == 0 {
+			return uint32(b[3]&0xf0)<<20 | uint32(b[0])<<16 | uint32(b[1])<<8 | uint32(b[2])
+		}
+		return uint32(b[3]&0x0f)<<24 | uint32(b[4])<<16 | uint32(b[5])<<8 | uint32(b[6])
+	default:
+		return binary.BigEndian.Uint32(db.tree[node*8+uint32(bit)*4:])
+	}
+}
+
+// lookup returns the record for the given ip.
+//
+// nil is returned if the ip isn't found in db. The returned record is shared among callers, so it mustn't be modified.
+func (db *geoIPDB) lookup(ip netip.Addr) (map[string]any, error) {
+	ip = ip.Unmap()
+
+	node := uint32(0)
+	var ipBytes []byte
+	if ip.Is4() {
+		a := ip.As4()
+		ipBytes = a[:]
+		node = db.ipv4Start
+	} else {
+		if db.ipVersion == 4 {
+			return nil, nil
+		}
+		a := ip.As16()
+		ipBytes = a[:]
+	}
+
+	bitsCount := len(ipBytes) * 8
+	for i := 0; i < bitsCount && node < db.nodeCount; i++ {
+		bit := (ipBytes[i/8] >> (7 - i%8)) & 1
+		node = db.readRecord(node, bit)
+	}
+	if node == db.nodeCount {
+		// The ip isn't found
+		return nil, nil
+	}
+	if node < db.nodeCount {
+		return nil, fmt.Errorf("invalid search tree in %q: the lookup for %s didn't end at the data record", db.path, ip)
+	}
+
+	offset := uint64(node) - uint64(db.nodeCount) - 16
+	if offset >= uint64(len(db.data)) {
+		return nil, fmt.Errorf("invalid data pointer in %q: %d exceeds data section size %d", db.path, offset, len(db.data))
+	}
+
+	db.recordsLock.RLock()
+	m, ok := db.records[offset]
+	db.recordsLock.RUnlock()
+	if ok {
+		return m, nil
+	}
+
+	v, _, err := decodeGeoIPValue(db.data, int(offset))
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode record for %s in %q: %w", ip, db.path, err)
+	}
+	m, ok = v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("unexpected record type for %s in %q: %T; want map", ip, db.path, v)
+	}
+
+	db.recordsLock.Lock()
+	if db.records == nil || len(db.records) >= maxGeoIPRecordsCacheSize {
+		db.records = make(map[uint64]map[string]any)
+	}
+	db.records[offset] = m
+	db.recordsLock.Unlock()
+
+	return m, nil
+}
+
+// decodeGeoIPValue decodes a value from the MaxMind DB data section b starting at the given offset.
+//
+// It returns the decoded value and the offset of the next value.
+func decodeGeoIPValue(b []byte, offset int) (any, int, error) {
+	return decodeGeoIPValueInternal(b, offset, 0)
+}
+
+func decodeGeoIPValueInternal(b []byte, offset, depth int) (any, int, error) {
+	if depth > maxGeoIPValueDepth {
+		return nil, offset, fmt.Errorf("too deep nesting of values at offset %d; it exceeds %d levels", offset, maxGeoIPValueDepth)
+	}
+	if offset >= len(b) {
+		return nil, offset, fmt.Errorf("unexpected end of data at offset %d", offset)
+	}
+	ctrl := b[offset]
+	offset++
+
+	typ := int(ctrl >> 5)
+	if typ == 1 {
+		// pointer
+		ss := int(ctrl>>3) & 3
+		vvv := uint64(ctrl & 7)
+		n := ss + 1
+		if offset+n > len(b) {
+			return nil, offset, fmt.Errorf("unexpected end of pointer at offset %d", offset)
+		}
+		var p uint64
+		switch ss {
+		case 0:
+			p = vvv<<8 | uint64(b[offset])
+		case 1:
+			p = 2048 + (vvv<<16 | uint64(b[offset])<<8 | uint64(b[offset+1]))
+		case 2:
+			p = 526336 + (vvv<<24 | uint64(b[offset])<<16 | uint64(b[offset+1])<<8 | uint64(b[offset+2]))
+		default:
+			p = uint64(binary.BigEndian.Uint32(b[offset:]))
+		}
+		offset += n
+		if p >= uint64(len(b)) {
+			return nil, offset, fmt.Errorf("too big pointer %d; it exceeds data size %d", p, len(b))
+		}
+		if b[p]>>5 == 1 {
+			return nil, offset, fmt.Errorf("pointer to pointer at offset %d isn't allowed", p)
+		}
+		v, _, err := decodeGeoIPValueInternal(b, int(p), depth+1)
+		return v, offset, err
+	}
+	if typ == 0 {
+		// extended type
+		if offset >= len(b) {
+			return nil, offset, fmt.Errorf("unexpected end of extended type at offset %d", offset)
+		}
+		typ = 7 + int(b[offset])
+		offset++
+	}
+
+	size := int(ctrl & 0x1f)
+	if size >= 29 {
+		n := size - 28
+		if offset+n > len(b) {
+			return nil, offset, fmt.Errorf("unexpected end of size at offset %d", offset)
+		}
+		switch n {
+		case 1:
+			size = 29 + int(b[offset])
+		case 2:
+			size = 285 + (int(b[offset])<<8 | int(b[offset+1]))
+		default:
+			size = 65821 + (int(b[offset])<<16 | int(b[offset+1])<<8 | int(b[offset+2]))
+		}
+		offset += n
+	}
+
+	switch typ {
+	case 7:
+		// map
+		m := make(map[string]any, size)
+		for i := 0; i < size; i++ {
+			k, nextOffset, err := decodeGeoIPValueInternal(b, offset, depth+1)
+			if err != nil {
+				return nil, offset, fmt.Errorf("cannot decode map key: %w", err)
+			}
+			key, ok := k.(string)
+			if !ok {
+				return nil, offset, fmt.Errorf("unexpected map key type %T at offset %d; want string", k, offset)
+			}
+			v, nextOffset, err := decodeGeoIPValueInternal(b, nextOffset, depth+1)
+			if err != nil {
+				return nil, offset, fmt.Errorf("cannot decode map value for key %q: %w", key, err)
+			}
+			m[key] = v
+			offset = nextOffset
+		}
+		return m, offset, nil
+	case 11:
+		// array
+		a := make([]any, 0, size)
+		for i := 0; i < size; i++ {
+			v, nextOffset, err := decodeGeoIPValueInternal(b, offset, depth+1)
+			if err != nil {
+				return nil, offset, fmt.Errorf("cannot decode array item #%d: %w", i, err)
+			}
+			a = append(a, v)
+			offset = nextOffset
+		}
+		return a, offset, nil
+	case 14:
+		// boolean
+		return size != 0, offset, nil
+	}
+
+	if offset+size > len(b) {
+		return nil, offset, fmt.Errorf("unexpected end of value with type %d and size %d at offset %d", typ, size, offset)
+	}
+	v := b[offset : offset+size]
+	offset += size
+
+	switch typ {
+	case 2:
+		// utf-8 string
+		return string(v), offset, nil
+	case 3:
+		// double
+		if size != 8 {
+			return nil, offset, fmt.Errorf("unexpected size for double: %d; want 8", size)
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(v)), offset, nil
+	case 4:
+		// bytes
+		return append([]byte{}, v...), offset, nil
+	case 5, 6, 9:
+		// uint16, uint32, uint64
+		if size > 8 {
+			return nil, offset, fmt.Errorf("too big size for unsigned integer: %d", size)
+		}
+		n := uint64(0)
+		for _, c := range v {
+			n = n<<8 | uint64(c)
+		}
+		return n, offset, nil
+	case 8:
+		// int32
+		if size > 4 {
+			return nil, offset, fmt.Errorf("too big size for int32: %d", size)
+		}
+		n := uint32(0)
+		for _, c := range v {
+			n = n<<8 | uint32(c)
+		}
+		return int64(int32(n)), offset, nil
+	case 10:
+		// uint128
+		if size > 16 {
+			return nil, offset, fmt.Errorf("too big size for uint128: %d", size)
+		}
+		return new(big.Int).SetBytes(v), offset, nil
+	case 15:
+		// float
+		if size != 4 {
+			return nil, offset, fmt.Errorf("unexpected size for float: %d; want 4", size)
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(v))), offset, nil
+	default:
+		return nil, offset, fmt.Errorf("unsupported data type %d at offset %d", typ, offset)
+	}
+}
+
+// getGeoIPString returns string value at the given path in m.
+func getGeoIPString(m map[string]any, path ...string) string {
+	var v any = m
+	for _, name := range path {
+		mm, ok := v.(map[string]any)
+		if !ok {
+			return ""
+		}
+		v = mm[name]
+	}
+	switch t := v.(type) {
+	case string:
+		return t
+	case uint64:
+		return strconv.FormatUint(t, 10)
+	case int64:
+		return strconv.FormatInt(t, 10)
+	case float64:
+		return strconv.FormatFloat(t, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,359 @@
+package logstorage
+
+import (
+	"encoding/binary"
+	"math"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestGeoIPDBLookup(t *testing.T) {
+	networks := map[string]map[string]any{
+		"1.2.3.0/24": {
+			"country": map[string]any{
+				"iso_code": "AU",
+				"names": map[string]any{
+					"en": "Australia",
+				},
+			},
+		},
+		"8.8.8.8/32": {
+			"autonomous_system_number":       uint64(15169),
+			"autonomous_system_organization": "GOOGLE",
+			"location": map[string]any{
+				"latitude":  37.751,
+				"longitude": -97.822,
+			},
+			"is_anycast": true,
+			"tags":       []any{"dns", "public"},
+		},
+		"2001:db8::/32": {
+			"country": map[string]any{
+				"iso_code": "DE",
+			},
+		},
+	}
+
+	f := func(ipVersion, recordSize int, ipStr string, resultExpected map[string]any) {
+		t.Helper()
+
+		data := newTestGeoIPDBData(t, ipVersion, recordSize, networks)
+		db, err := newGeoIPDB(data)
+		if err != nil {
+			t.Fatalf("cannot open geoip database: %s", err)
+		}
+		ip := netip.MustParseAddr(ipStr)
+		result, err := db.lookup(ip)
+		if err != nil {
+			t.Fatalf("unexpected error in lookup(%s): %s", ipStr, err)
+		}
+		if !reflect.DeepEqual(result, resultExpected) {
+			t.Fatalf("unexpected result for lookup(%s)\ngot\n%#v\nwant\n%#v", ipStr, result, resultExpected)
+		}
+
+		// the second lookup must return the cached record
+		result, err = db.lookup(ip)
+		if err != nil {
+			t.Fatalf("unexpected error in the second lookup(%s): %s", ipStr, err)
+		}
+		if !reflect.DeepEqual(result, resultExpected) {
+			t.Fatalf("unexpected result for the second lookup(%s)\ngot\n%#v\nwant\n%#v", ipStr, result, resultExpected)
+		}
+		if resultExpected != nil && len(db.records) != 1 {
+			t.Fatalf("unexpected number of cached records; got %d; want 1", len(db.records))
+		}
+	}
+
+	for _, recordSize := range []int{24, 28, 32} {
+		for _, ipVersion := range []int{4, 6} {
+			f(ipVersion, recordSize, "1.2.3.4", networks["1.2.3.0/24"])
+			f(ipVersion, recordSize, "1.2.3.255", networks["1.2.3.0/24"])
+			f(ipVersion, recordSize, "::ffff:1.2.3.4", networks["1.2.3.0/24"])
+			f(ipVersion, recordSize, "8.8.8.8", networks["8.8.8.8/32"])
+			f(ipVersion, recordSize, "1.2.4.1", nil)
+			f(ipVersion, recordSize, "8.8.8.9", nil)
+			f(ipVersion, recordSize, "127.0.0.1", nil)
+		}
+		f(6, recordSize, "2001:db8::1", networks["2001:db8::/32"])
+		f(6, recordSize, "2001:db9::1", nil)
+		f(4, recordSize, "2001:db8::1", nil)
+	}
+}
+
+func TestOpenGeoIPDBFailure(t *testing.T) {
+	f := func(data []byte) {
+		t.Helper()
+
+		path := filepath.Join(t.TempDir(), "test.mmdb")
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatalf("cannot write test database: %s", err)
+		}
+		if _, err := openGeoIPDB(path); err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	// missing metadata marker
+	f([]byte("foobar"))
+
+	// invalid metadata
+	f(append([]byte{}, geoIPMetadataMarker...))
+	f(append(append([]byte{}, geoIPMetadataMarker...), 0xff))
+
+	// missing search tree
+	md := appendTestGeoIPValue(nil, map[string]any{
+		"node_count":  uint64(100),
+		"record_size": uint64(24),
+		"ip_version":  uint64(4),
+	})
+	f(append(append([]byte{}, geoIPMetadataMarker...), md...))
+
+	// unsupported record_size
+	md = appendTestGeoIPValue(nil, map[string]any{
+		"node_count":  uint64(0),
+		"record_size": uint64(20),
+		"ip_version":  uint64(4),
+	})
+	f(append(make([]byte, 16), append(append([]byte{}, geoIPMetadataMarker...), md...)...))
+}
+
+func TestDecodeGeoIPValue(t *testing.T) {
+	f := func(b []byte, resultExpected any) {
+		t.Helper()
+
+		result, _, err := decodeGeoIPValue(b, 0)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(result, resultExpected) {
+			t.Fatalf("unexpected result\ngot\n%#v\nwant\n%#v", result, resultExpected)
+		}
+	}
+
+	f(appendTestGeoIPValue(nil, "foo"), "foo")
+	f(appendTestGeoIPValue(nil, string(make([]byte, 100))), string(make([]byte, 100)))
+	f(appendTestGeoIPValue(nil, string(make([]byte, 1000))), string(make([]byte, 1000)))
+	f(appendTestGeoIPValue(nil, string(make([]byte, 70000))), string(make([]byte, 70000)))
+	f(appendTestGeoIPValue(nil, uint64(0)), uint64(0))
+	f(appendTestGeoIPValue(nil, uint64(1234567890123)), uint64(1234567890123))
+	f(appendTestGeoIPValue(nil, 1.5), 1.5)
+	f(appendTestGeoIPValue(nil, false), false)
+	f(appendTestGeoIPValue(nil, []any{"a", uint64(1)}), []any{"a", uint64(1)})
+
+	// int32
+	f([]byte{0x04, 0x01, 0xff, 0xff, 0xff, 0xfe}, int64(-2))
+
+	// float
+	f([]byte{0x04, 0x08, 0x3f, 0xc0, 0x00, 0x00}, 1.5)
+
+	// pointer
+	b := appendTestGeoIPValue(nil, "foo")
+	b = append(b, 0x20, 0x00)
+	_, offset, err := decodeGeoIPValue(b, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	v, offset, err := decodeGeoIPValue(b, offset)
+	if err != nil {
+		t.Fatalf("unexpected error when decoding pointer: %s", err)
+	}
+	if v != "foo" {
+		t.Fatalf("unexpected value for pointer; got %#v; want %q", v, "foo")
+	}
+	if offset != len(b) {
+		t.Fatalf("unexpected offset after pointer; got %d; want %d", offset, len(b))
+	}
+}
+
+func TestDecodeGeoIPValueFailure(t *testing.T) {
+	f := func(b []byte) {
+		t.Helper()
+
+		if _, _, err := decodeGeoIPValue(b, 0); err == nil {
+			t.Fatalf("expecting non-nil error for %x", b)
+		}
+	}
+
+	// unexpected end of data
+	f(nil)
+	f([]byte{0x43, 'f', 'o'})
+
+	// pointer to pointer
+	f([]byte{0x20, 0x00})
+
+	// map, which contains a pointer to itself
+	f([]byte{0xe1, 0x41, 'a', 0x20, 0x00})
+}
+
+// newTestGeoIPDBData returns MaxMind DB with the given networks.
+func newTestGeoIPDBData(t *testing.T, ipVersion, recordSize int, networks map[string]map[string]any) []byte {
+	t.Helper()
+
+	// Records with values >= 0 point to nodes, -1 means an empty record, while values < -1 point to data records.
+	nodes := [][2]int{{-1, -1}}
+	var data []byte
+	var dataOffsets []int
+
+	prefixes := make([]string, 0, len(networks))
+	for prefix := range networks {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Strings(prefixes)
+
+	for _, prefixStr := range prefixes {
+		prefix := netip.MustParsePrefix(prefixStr)
+		var ipBytes []byte
+		bits := prefix.Bits()
+		if prefix.Addr().Is4() {
+			a := prefix.Addr().As4()
+			ipBytes = a[:]
+			if ipVersion == 6 {
+				ipBytes = append(make([]byte, 12), ipBytes...)
+				bits += 96
+			}
+		} else {
+			if ipVersion == 4 {
+				continue
+			}
+			a := prefix.Addr().As16()
+			ipBytes = a[:]
+		}
+
+		dataOffsets = append(dataOffsets, len(data))
+		data = appendTestGeoIPValue(data, networks[prefixStr])
+		dataRecord := -2 - (len(dataOffsets) - 1)
+
+		node := 0
+		for i := 0; i < bits; i++ {
+			bit := (ipBytes[i/8] >> (7 - i%8)) & 1
+			if i == bits-1 {
+				nodes[node][bit] = dataRecord
+				break
+			}
+			next := nodes[node][bit]
+			if next < 0 {
+				nodes = append(nodes, [2]int{-1, -1})
+				next = len(nodes) - 1
+				nodes[node][bit] = next
+			}
+			node = next
+		}
+	}
+
+	nodeCount := len(nodes)
+	getRecord := func(r int) uint32 {
+		switch {
+		case r >= 0:
+			return uint32(r)
+		case r == -1:
+			return uint32(nodeCount)
+		default:
+			return uint32(nodeCount + 16 + dataOffsets[-2-r])
+		}
+	}
+
+	var dst []byte
+	for _, node := range nodes {
+		left := getRecord(node[0])
+		right := getRecord(node[1])
+		switch recordSize {
+		case 24:
+			dst = append(dst, byte(left>>16), byte(left>>8), byte(left), byte(right>>16), byte(right>>8), byte(right))
+		case 28:
+			dst = append(dst, byte(left>>16), byte(left>>8), byte(left), byte((left>>20)&0xf0)|byte((right>>24)&0x0f), byte(right>>16), byte(right>>8), byte(right))
+		case 32:
+			dst = binary.BigEndian.AppendUint32(dst, left)
+			dst = binary.BigEndian.AppendUint32(dst, right)
+		default:
+			t.Fatalf("unexpected recordSize=%d", recordSize)
+		}
+	}
+	dst = append(dst, make([]byte, 16)...)
+	dst = append(dst, data...)
+	dst = append(dst, geoIPMetadataMarker...)
+	dst = appendTestGeoIPValue(dst, map[string]any{
+		"node_count":                  uint64(nodeCount),
+		"record_size":                 uint64(recordSize),
+		"ip_version":                  uint64(ipVersion),
+		"database_type":               "Test",
+		"binary_format_major_version": uint64(2),
+		"languages":                   []any{"en"},
+	})
+	return dst
+}
+
+func appendTestGeoIPValue(dst []byte, v any) []byte {
+	switch t := v.(type) {
+	case string:
+		dst = appendTestGeoIPControl(dst, 2, len(t))
+		return append(dst, t...)
+	case float64:
+		dst = appendTestGeoIPControl(dst, 3, 8)
+		return binary.BigEndian.AppendUint64(dst, math.Float64bits(t))
+	case uint64:
+		var b []byte
+		for n := t; n > 0; n >>= 8 {
+			b = append([]byte{byte(n)}, b...)
+		}
+		dst = appendTestGeoIPControl(dst, 9, len(b))
+		return append(dst, b...)
+	case map[string]any:
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		dst = appendTestGeoIPControl(dst, 7, len(t))
+		for _, k := range keys {
+			dst = appendTestGeoIPValue(dst, k)
+			dst = appendTestGeoIPValue(dst, t[k])
+		}
+		return dst
+	case []any:
+		dst = appendTestGeoIPControl(dst, 11, len(t))
+		for _, item := range t {
+			dst = appendTestGeoIPValue(dst, item)
+		}
+		return dst
+	case bool:
+		n := 0
+		if t {
+			n = 1
+		}
+		return appendTestGeoIPControl(dst, 14, n)
+	default:
+		panic("BUG: unsupported type")
+	}
+}
+
+func appendTestGeoIPControl(dst []byte, typ, size int) []byte {
+	ctrlType := typ
+	if typ > 7 {
+		ctrlType = 0
+	}
+	var sizeBytes []byte
+	ctrlSize := size
+	switch {
+	case size < 29:
+	case size < 285:
+		ctrlSize = 29
+		sizeBytes = []byte{byte(size - 29)}
+	case size < 65821:
+		ctrlSize = 30
+		n := size - 285
+		sizeBytes = []byte{byte(n >> 8), byte(n)}
+	default:
+		ctrlSize = 31
+		n := size - 65821
+		sizeBytes = []byte{byte(n >> 16), byte(n >> 8), byte(n)}
+	}
+	dst = append(dst, byte(ctrlType<<5|ctrlSize))
+	if typ > 7 {
+		dst = append(dst, byte(typ-7))
+	}
+	return append(dst, sizeBytes...)
+}
@@ -28,6 +28,11 @@ const (
 
 	// (tenantID:name:value => streamIDs) entries have this prefix
 	nsPrefixTagToStreamIDs = 2
+
+	// (tenantID:name:value => streamID) entries for indexed fields have this prefix.
+	//
+	// See https://docs.victoriametrics.com/victorialogs/#indexed-fields
+	nsPrefixIndexedFieldToStreamID = 3
 )
 
 // IndexdbStats contains indexdb stats
@@ -480,6 +485,77 @@ func (idb *indexdb) mustRegisterStream(streamID *streamID, streamTagsCanonical [
 	idb.streamsCreatedTotal.Add(1)
 }
 
+// mustRegisterIndexedFields registers the given fields for the given streamID in idb.
+//
+// This allows searching for streams with the given field values via searchStreamIDsByIndexedField.
+func (idb *indexdb) mustRegisterIndexedFields(streamID *streamID, fields []Field) {
+	tenantID := streamID.tenantID
+
+	bi := getBatchItems()
+	buf := bi.buf[:0]
+	items := bi.items[:0]
+
+	for i := range fields {
+		f := &fields[i]
+		bufLen := len(buf)
+		buf = marshalCommonPrefix(buf, nsPrefixIndexedFieldToStreamID, tenantID)
+		buf = marshalTagValue(buf, bytesutil.ToUnsafeBytes(f.Name))
+		buf = marshalTagValue(buf, bytesutil.ToUnsafeBytes(f.Value))
+		buf = streamID.id.marshal(buf)
+		items = append(items, buf[bufLen:])
+	}
+	idb.tb.AddItems(items)
+
+	bi.buf = buf
+	bi.items = items
+	putBatchItems(bi)
+}
+
+// searchStreamIDsByIndexedField returns sorted streamIDs for the given tenantIDs, which contain logs with the given fieldName=value.
+func (idb *indexdb) searchStreamIDsByIndexedField(tenantIDs []TenantID, fieldName, value string) []streamID {
+	is := idb.getIndexSearch()
+	var streamIDs []streamID
+	for _, tenantID := range tenantIDs {
+		ids := is.getStreamIDsForIndexedFieldValue(tenantID, fieldName, value)
+		for id := range ids {
+			streamIDs = append(streamIDs, streamID{
+				tenantID: tenantID,
+				id:       id,
+			})
+		}
+	}
+	idb.putIndexSearch(is)
+
+	sortStreamIDs(streamIDs)
+	return streamIDs
+}
+
+func (is *indexSearch) getStreamIDsForIndexedFieldValue(tenantID TenantID, fieldName, value string) map[u128]struct{} {
+	ids := make(map[u128]struct{})
+	var sp tagToStreamIDsRowParser
+
+	ts := &is.ts
+	kb := &is.kb
+	kb.B = marshalCommonPrefix(kb.B[:0], nsPrefixIndexedFieldToStreamID, tenantID)
+	kb.B = marshalTagValue(kb.B, bytesutil.ToUnsafeBytes(fieldName))
+	kb.B = marshalTagValue(kb.B, bytesutil.ToUnsafeBytes(value))
+	prefix := kb.B
+	ts.Seek(prefix)
+	for ts.NextItem() {
+		item := ts.Item
+		if !bytes.HasPrefix(item, prefix) {
+			break
+		}
+		tail := item[len(prefix):]
+		sp.UpdateStreamIDs(ids, tail)
+	}
+	if err := ts.Error(); err != nil {
+		logger.Panicf("FATAL: unexpected error: %s", err)
+	}
+
+	return ids
+}
+
 func (idb *indexdb) invalidateStreamFilterCache() {
 	// This function must be fast, since it is called each
 	// time new indexdb entry is added.
@@ -3,6 +3,7 @@ package logstorage
 import (
 	"sort"
 	"sync"
+	"unsafe"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
 )
@@ -230,7 +231,10 @@ func (lr *LogRows) GetRowString(idx int) string {
 func GetLogRows(streamFields, ignoreFields []string) *LogRows {
 	v := logRowsPool.Get()
 	if v == nil {
+		logRowsPoolStats.Update(int(unsafe.Sizeof(LogRows{})))
 		v = &LogRows{}
+	} else {
+		logRowsPoolStats.Update(0)
 	}
 	lr := v.(*LogRows)
 
@@ -267,6 +271,8 @@ func PutLogRows(lr *LogRows) {
 
 var logRowsPool sync.Pool
 
+var logRowsPoolStats = bytesutil.NewPoolStats("logstorage_log_rows")
+
 // Len returns the number of items in lr.
 func (lr *LogRows) Len() int {
 	return len(lr.streamIDs)
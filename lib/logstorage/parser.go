@@ -57,10 +57,17 @@ func (lex *lexer) restoreState(ls *lexerState) {
 //
 // The lex.token points to the first token in s.
 func newLexer(s string) *lexer {
+	return newLexerAtTimestamp(s, time.Now().UnixNano())
+}
+
+// newLexerAtTimestamp returns new lexer for the given s, which uses the given timestamp as the current time.
+//
+// The lex.token points to the first token in s.
+func newLexerAtTimestamp(s string, timestamp int64) *lexer {
 	lex := &lexer{
 		s:                s,
 		sOrig:            s,
-		currentTimestamp: time.Now().UnixNano(),
+		currentTimestamp: timestamp,
 	}
 	lex.nextToken()
 	return lex
@@ -221,11 +228,20 @@ type Query struct {
 	f filter
 
 	pipes []pipe
+
+	// opts contains optional per-query options set via `options(...)` prefix.
+	opts *queryOptions
+
+	// timestamp is the timestamp context used for parsing the query.
+	timestamp int64
 }
 
 // String returns string representation for q.
 func (q *Query) String() string {
 	s := q.f.String()
+	if q.opts != nil {
+		s = q.opts.String() + " " + s
+	}
 
 	for _, p := range q.pipes {
 		s += " | " + p.String()
@@ -329,7 +345,7 @@ func (q *Query) AddCountByTimePipe(step, off int64, fields []string) {
 // Clone returns a copy of q.
 func (q *Query) Clone() *Query {
 	qStr := q.String()
-	qCopy, err := ParseQuery(qStr)
+	qCopy, err := ParseQueryAtTimestamp(qStr, q.timestamp)
 	if err != nil {
 		logger.Panicf("BUG: cannot parse %q: %s", qStr, err)
 	}
@@ -409,6 +425,65 @@ func (q *Query) AddPipeLimit(n uint64) {
 	})
 }
 
+// GetStatsByFields returns `by (...)` fields from the last `stats` pipe at q.
+//
+// An error is returned if q doesn't end with `stats` pipe, optionally followed by pipes, which do not change the set of fields.
+func (q *Query) GetStatsByFields() ([]string, error) {
+	ps, err := q.getLastPipeStats()
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]string, len(ps.byFields))
+	for i, bf := range ps.byFields {
+		fields[i] = bf.name
+	}
+	return fields, nil
+}
+
+// GetStatsByFieldsAddGroupingByTime adds `_time:step offset off` grouping to the last `stats` pipe at q
+// and returns `by (...)` fields from this pipe without the added `_time` field.
+//
+// An error is returned if q doesn't end with `stats` pipe, optionally followed by pipes, which do not change the set of fields,
+// or if the `stats` pipe already contains grouping by `_time`.
+func (q *Query) GetStatsByFieldsAddGroupingByTime(step, off int64) ([]string, error) {
+	ps, err := q.getLastPipeStats()
+	if err != nil {
+		return nil, err
+	}
+	fields := make([]string, len(ps.byFields))
+	for i, bf := range ps.byFields {
+		if bf.name == "_time" {
+			return nil, fmt.Errorf("the `stats` pipe cannot contain grouping by _time field in the query [%s]", q)
+		}
+		fields[i] = bf.name
+	}
+	bf := &byStatsField{
+		name:          "_time",
+		bucketSizeStr: string(marshalDurationString(nil, step)),
+		bucketSize:    float64(step),
+	}
+	if off != 0 {
+		bf.bucketOffsetStr = string(marshalDurationString(nil, off))
+		bf.bucketOffset = float64(off)
+	}
+	ps.byFields = append([]*byStatsField{bf}, ps.byFields...)
+	return fields, nil
+}
+
+func (q *Query) getLastPipeStats() (*pipeStats, error) {
+	for i := len(q.pipes) - 1; i >= 0; i-- {
+		switch t := q.pipes[i].(type) {
+		case *pipeStats:
+			return t, nil
+		case *pipeSort, *pipeOffset, *pipeLimit, *pipeFilter:
+			// These pipes do not change the set of fields returned by the `stats` pipe.
+		default:
+			return nil, fmt.Errorf("the %q pipe cannot be put after the last `stats` pipe in the query [%s]", t, q)
+		}
+	}
+	return nil, fmt.Errorf("missing `stats` pipe at the end of the query [%s]", q)
+}
+
 // Optimize tries optimizing the query.
 func (q *Query) Optimize() {
 	q.pipes = optimizeSortOffsetPipes(q.pipes)
@@ -584,7 +659,30 @@ func (q *Query) getNeededColumns() ([]string, []string) {
 
 // ParseQuery parses s.
 func ParseQuery(s string) (*Query, error) {
-	lex := newLexer(s)
+	return ParseQueryAtTimestamp(s, time.Now().UnixNano())
+}
+
+// ParseQueryAtTimestamp parses s in the context of the given timestamp.
+//
+// E.g. _time:duration filters are adjusted according to the provided timestamp as _time:[timestamp-duration, timestamp].
+func ParseQueryAtTimestamp(s string, timestamp int64) (*Query, error) {
+	lex := newLexerAtTimestamp(s, timestamp)
+
+	// Parse optional per-query options.
+	var opts *queryOptions
+	if lex.isKeyword("options") {
+		ls := lex.backupState()
+		lex.nextToken()
+		isOptions := lex.isKeyword("(")
+		lex.restoreState(ls)
+		if isOptions {
+			qo, err := parseQueryOptions(lex)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse query options: %w; context: [%s]", err, lex.context())
+			}
+			opts = qo
+		}
+	}
 
 	// Verify the first token doesn't match pipe names.
 	firstToken := strings.ToLower(lex.rawToken)
@@ -600,9 +698,16 @@ func ParseQuery(s string) (*Query, error) {
 	if !lex.isEnd() {
 		return nil, fmt.Errorf("unexpected unparsed tail after [%s]; context: [%s]; tail: [%s]", q, lex.context(), lex.s)
 	}
+	q.opts = opts
+	q.timestamp = timestamp
 	return q, nil
 }
 
+// GetTimestamp returns timestamp context for the given q, which was passed to ParseQueryAtTimestamp().
+func (q *Query) GetTimestamp() int64 {
+	return q.timestamp
+}
+
 func parseQuery(lex *lexer) (*Query, error) {
 	f, err := parseFilter(lex)
 	if err != nil {
@@ -719,6 +824,8 @@ func parseGenericFilter(lex *lexer, fieldName string) (filter, error) {
 		return parseFilterNotTilda(lex, fieldName)
 	case lex.isKeyword("not", "!"):
 		return parseFilterNot(lex, fieldName)
+	case lex.isKeyword("ends_with"):
+		return parseFilterEndsWith(lex, fieldName)
 	case lex.isKeyword("exact"):
 		return parseFilterExact(lex, fieldName)
 	case lex.isKeyword("i"):
@@ -727,6 +834,8 @@ func parseGenericFilter(lex *lexer, fieldName string) (filter, error) {
 		return parseFilterIn(lex, fieldName)
 	case lex.isKeyword("ipv4_range"):
 		return parseFilterIPv4Range(lex, fieldName)
+	case lex.isKeyword("len"):
+		return parseFilterLen(lex, fieldName)
 	case lex.isKeyword("len_range"):
 		return parseFilterLenRange(lex, fieldName)
 	case lex.isKeyword("range"):
@@ -735,6 +844,8 @@ func parseGenericFilter(lex *lexer, fieldName string) (filter, error) {
 		return parseFilterRegexp(lex, fieldName)
 	case lex.isKeyword("seq"):
 		return parseFilterSequence(lex, fieldName)
+	case lex.isKeyword("starts_with"):
+		return parseFilterStartsWith(lex, fieldName)
 	case lex.isKeyword("string_range"):
 		return parseFilterStringRange(lex, fieldName)
 	case lex.isKeyword(`"`, "'", "`"):
@@ -946,7 +1057,7 @@ func parseFilterLenRange(lex *lexer, fieldName string) (filter, error) {
 			return nil, fmt.Errorf("cannot parse maxLen at %s(): %w", funcName, err)
 		}
 
-		stringRepr := "(" + args[0] + ", " + args[1] + ")"
+		stringRepr := "len_range(" + args[0] + ", " + args[1] + ")"
 		fr := &filterLenRange{
 			fieldName: fieldName,
 			minLen:    minLen,
@@ -958,6 +1069,24 @@ func parseFilterLenRange(lex *lexer, fieldName string) (filter, error) {
 	})
 }
 
+func parseFilterLen(lex *lexer, fieldName string) (filter, error) {
+	funcName := lex.token
+	return parseFuncArg(lex, fieldName, func(arg string) (filter, error) {
+		n, err := parseUint(arg)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse length at %s(): %w", funcName, err)
+		}
+		fr := &filterLenRange{
+			fieldName: fieldName,
+			minLen:    n,
+			maxLen:    n,
+
+			stringRepr: "len(" + arg + ")",
+		}
+		return fr, nil
+	})
+}
+
 func parseFilterStringRange(lex *lexer, fieldName string) (filter, error) {
 	funcName := lex.token
 	return parseFuncArgs(lex, fieldName, func(args []string) (filter, error) {
@@ -1126,6 +1255,26 @@ func parseFilterExact(lex *lexer, fieldName string) (filter, error) {
 	})
 }
 
+func parseFilterStartsWith(lex *lexer, fieldName string) (filter, error) {
+	return parseFuncArg(lex, fieldName, func(arg string) (filter, error) {
+		f := &filterExactPrefix{
+			fieldName: fieldName,
+			prefix:    arg,
+		}
+		return f, nil
+	})
+}
+
+func parseFilterEndsWith(lex *lexer, fieldName string) (filter, error) {
+	return parseFuncArg(lex, fieldName, func(arg string) (filter, error) {
+		f := &filterExactSuffix{
+			fieldName: fieldName,
+			suffix:    arg,
+		}
+		return f, nil
+	})
+}
+
 func parseFilterRegexp(lex *lexer, fieldName string) (filter, error) {
 	funcName := lex.token
 	return parseFuncArg(lex, fieldName, func(arg string) (filter, error) {
@@ -2058,15 +2207,21 @@ var reservedKeywords = func() map[string]struct{} {
 		"offset",
 		"-",
 
+		// query options: 'options(concurrency=2) foo'
+		"options",
+
 		// functions
+		"ends_with",
 		"exact",
 		"i",
 		"in",
 		"ipv4_range",
+		"len",
 		"len_range",
 		"range",
 		"re",
 		"seq",
+		"starts_with",
 		"string_range",
 	}
 	m := make(map[string]struct{}, len(kws))
@@ -792,12 +792,24 @@ func TestParseQuerySuccess(t *testing.T) {
 	f("a:in", `a:"in"`)
 	f("a:in-foo", `a:in-foo`)
 	f("in-foo:b", `in-foo:b`)
+	f("ends_with", `"ends_with"`)
+	f("ends_with:a", `"ends_with":a`)
+	f("ends_with-foo", `ends_with-foo`)
+	f("a:ends_with", `a:"ends_with"`)
+	f("a:ends_with-foo", `a:ends_with-foo`)
+	f("ends_with-foo:b", `ends_with-foo:b`)
 	f("ipv4_range", `"ipv4_range"`)
 	f("ipv4_range:a", `"ipv4_range":a`)
 	f("ipv4_range-foo", `ipv4_range-foo`)
 	f("a:ipv4_range", `a:"ipv4_range"`)
 	f("a:ipv4_range-foo", `a:ipv4_range-foo`)
 	f("ipv4_range-foo:b", `ipv4_range-foo:b`)
+	f("len", `"len"`)
+	f("len:a", `"len":a`)
+	f("len-foo", `len-foo`)
+	f("a:len", `a:"len"`)
+	f("a:len-foo", `a:len-foo`)
+	f("len-foo:b", `len-foo:b`)
 	f("len_range", `"len_range"`)
 	f("len_range:a", `"len_range":a`)
 	f("len_range-foo", `len_range-foo`)
@@ -818,6 +830,10 @@ func TestParseQuerySuccess(t *testing.T) {
 	f("seq-a", `seq-a`)
 	f("x:seq-a", `x:seq-a`)
 	f("seq-a:x", `seq-a:x`)
+	f("starts_with", `"starts_with"`)
+	f("starts_with-a", `starts_with-a`)
+	f("x:starts_with-a", `x:starts_with-a`)
+	f("starts_with-a:x", `starts_with-a:x`)
 	f("string_range", `"string_range"`)
 	f("string_range-a", `string_range-a`)
 	f("x:string_range-a", `x:string_range-a`)
@@ -836,6 +852,19 @@ func TestParseQuerySuccess(t *testing.T) {
 	f("=foo=bar !=b<=a>z foo:!='abc'*", `="foo=bar" !="b<=a>z" !foo:=abc*`)
 	f("==foo =>=bar x : ( = =a<b*='c*' >=20)", `="=foo" =">=bar" x:="=a<b"* x:="c*" x:>=20`)
 
+	// starts_with filter
+	f("starts_with(foo)", `=foo*`)
+	f("starts_with('foo bar),|baz')", `="foo bar),|baz"*`)
+	f(`foo:STARTS_WITH(foo/b:ar)`, `foo:="foo/b:ar"*`)
+	f(`starts_with("")`, `=""*`)
+
+	// ends_with filter
+	f("ends_with(foo)", `ends_with(foo)`)
+	f("ends_with('foo bar),|baz')", `ends_with("foo bar),|baz")`)
+	f(`foo:ENDS_WITH(foo/b:ar)`, `foo:ends_with("foo/b:ar")`)
+	f(`ends_with("")`, `ends_with("")`)
+	f(`!ends_with(foo)`, `!ends_with(foo)`)
+
 	// i filter
 	f("i(foo)", `i(foo)`)
 	f("i(foo*)", `i(foo*)`)
@@ -874,6 +903,12 @@ func TestParseQuerySuccess(t *testing.T) {
 	f(`len_range(0x10,0b100101)`, `len_range(0x10, 0b100101)`)
 	f(`len_range(1.5KB, 22MB100KB)`, `len_range(1.5KB, 22MB100KB)`)
 
+	// len filter
+	f(`len(10)`, `len(10)`)
+	f(`foo:len("10")`, `foo:len(10)`)
+	f(`LEN(0)`, `len(0)`)
+	f(`len(1KB)`, `len(1KB)`)
+
 	// range filter
 	f(`range(1.234, 5656.43454)`, `range(1.234, 5656.43454)`)
 	f(`foo:range(-2343.344, 2343.4343)`, `foo:range(-2343.344, 2343.4343)`)
@@ -1200,6 +1235,17 @@ func TestParseQuerySuccess(t *testing.T) {
 	// skip 'stats' and 'filter' prefixes
 	f(`* | by (host) count() rows | rows:>10`, `* | stats by (host) count(*) as rows | filter rows:>10`)
 	f(`* | (host) count() rows, count() if (error) errors | rows:>10`, `* | stats by (host) count(*) as rows, count(*) if (error) as errors | filter rows:>10`)
+
+	// query options
+	f(`options(concurrency=2) error`, `options(concurrency=2) error`)
+	f(`OPTIONS ( Concurrency = 2 , max_memory=512MiB,ignore_global_limits=true, ) error | stats count()`, `options(concurrency=2, max_memory=512MiB, ignore_global_limits=true) error | stats count(*) as "count(*)"`)
+	f(`options() error`, `options() error`)
+	f(`options(max_memory=1GB) *`, `options(max_memory=1GB) *`)
+
+	// options word without parens is a regular word filter
+	f(`options`, `"options"`)
+	f(`options foo`, `"options" foo`)
+	f(`foo:options`, `foo:"options"`)
 }
 
 func TestParseQueryFailure(t *testing.T) {
@@ -1225,6 +1271,24 @@ func TestParseQueryFailure(t *testing.T) {
 	f("not (abc")
 	f("!")
 
+	// invalid query options
+	f(`options(`)
+	f(`options(concurrency=2`)
+	f(`options(concurrency=2)`)
+	f(`options(concurrency=2) | stats count()`)
+	f(`options(concurrency) foo`)
+	f(`options(concurrency=) foo`)
+	f(`options(concurrency=0) foo`)
+	f(`options(concurrency=-1) foo`)
+	f(`options(concurrency=foo) foo`)
+	f(`options(max_memory=foo) foo`)
+	f(`options(max_memory=0) foo`)
+	f(`options(ignore_global_limits=foo) foo`)
+	f(`options(concurrency=1, concurrency=2) foo`)
+	f(`options(concurrency=1 max_memory=1MB) foo`)
+	f(`options(foo=bar) foo`)
+	f(`foo options(concurrency=2)`)
+
 	// pipe names without quoutes
 	f(`filter foo:bar`)
 	f(`stats count()`)
@@ -1390,6 +1454,26 @@ func TestParseQueryFailure(t *testing.T) {
 	f(`len_range(1, 2`)
 	f(`len_range(1.2, 3.4)`)
 
+	// invalid len
+	f(`len(`)
+	f(`len()`)
+	f(`len(foo)`)
+	f(`len(1, 2)`)
+	f(`len(1`)
+	f(`len(1.5)`)
+
+	// invalid starts_with
+	f(`starts_with(`)
+	f(`starts_with()`)
+	f(`starts_with(foo, bar)`)
+	f(`starts_with(foo`)
+
+	// invalid ends_with
+	f(`ends_with(`)
+	f(`ends_with()`)
+	f(`ends_with(foo, bar)`)
+	f(`ends_with(foo`)
+
 	// invalid range
 	f(`range(`)
 	f(`range(foo,bar)`)
@@ -1984,6 +2068,7 @@ func TestQueryClone(t *testing.T) {
 	f("error")
 	f("_time:5m error | fields foo, bar")
 	f("ip:in(foo | fields user_ip) bar | stats by (x:1h, y) count(*) if (user_id:in(q:w | fields abc)) as ccc")
+	f("options(concurrency=2, max_memory=1GiB) error | sort by (_time)")
 }
 
 func TestQueryGetFilterTimeRange(t *testing.T) {
@@ -2100,3 +2185,121 @@ func TestQueryDropAllPipes(t *testing.T) {
 	f(`foo or bar and baz | top 5 by (x)`, `foo or bar baz`)
 	f(`foo | filter bar:baz | stats by (x) min(y)`, `foo bar:baz`)
 }
+
+func TestParseQueryAtTimestamp(t *testing.T) {
+	f := func(qStr string, timestamp, startExpected, endExpected int64) {
+		t.Helper()
+
+		q, err := ParseQueryAtTimestamp(qStr, timestamp)
+		if err != nil {
+			t.Fatalf("cannot parse [%s]: %s", qStr, err)
+		}
+		if q.GetTimestamp() != timestamp {
+			t.Fatalf("unexpected timestamp; got %d; want %d", q.GetTimestamp(), timestamp)
+		}
+		start, end := q.GetFilterTimeRange()
+		if start != startExpected || end != endExpected {
+			t.Fatalf("unexpected filter time range; got [%d, %d]; want [%d, %d]", start, end, startExpected, endExpected)
+		}
+
+		// The cloned query must have the same time range
+		qCopy := q.Clone()
+		start, end = qCopy.GetFilterTimeRange()
+		if start != startExpected || end != endExpected {
+			t.Fatalf("unexpected filter time range for the cloned query; got [%d, %d]; want [%d, %d]", start, end, startExpected, endExpected)
+		}
+	}
+
+	f("*", 1717150830456789123, -9223372036854775808, 9223372036854775807)
+	f("_time:5m", 1717150830456789123, 1717150530456789123, 1717150830456789123)
+	f("_time:2024-05-31 error", 1717150830456789123, 1717113600000000000, 1717199999999999999)
+}
+
+func TestQueryGetStatsByFields_Success(t *testing.T) {
+	f := func(qStr string, fieldsExpected []string) {
+		t.Helper()
+
+		q, err := ParseQuery(qStr)
+		if err != nil {
+			t.Fatalf("cannot parse [%s]: %s", qStr, err)
+		}
+		fields, err := q.GetStatsByFields()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(fields, fieldsExpected) {
+			t.Fatalf("unexpected byFields;\ngot\n%q\nwant\n%q", fields, fieldsExpected)
+		}
+	}
+
+	f(`* | stats count()`, []string{})
+	f(`* | stats by (level) count()`, []string{"level"})
+	f(`* | stats by (level, host:/24) count() x, sum(y) | sort by (x) | limit 10`, []string{"level", "host"})
+	f(`* | fields a, b | stats by (a) count() x | filter x:>10 | offset 5`, []string{"a"})
+}
+
+func TestQueryGetStatsByFields_Failure(t *testing.T) {
+	f := func(qStr string) {
+		t.Helper()
+
+		q, err := ParseQuery(qStr)
+		if err != nil {
+			t.Fatalf("cannot parse [%s]: %s", qStr, err)
+		}
+		fields, err := q.GetStatsByFields()
+		if err == nil {
+			t.Fatalf("expecting non-nil error for [%s]", qStr)
+		}
+		if fields != nil {
+			t.Fatalf("expecting nil fields; got %q", fields)
+		}
+	}
+
+	f(`*`)
+	f(`foo | fields bar`)
+	f(`foo | stats count() x | fields x`)
+	f(`foo | stats by (a) count() x | copy x y`)
+}
+
+func TestQueryGetStatsByFieldsAddGroupingByTime_Success(t *testing.T) {
+	f := func(qStr string, step, off int64, fieldsExpected []string, qExpected string) {
+		t.Helper()
+
+		q, err := ParseQuery(qStr)
+		if err != nil {
+			t.Fatalf("cannot parse [%s]: %s", qStr, err)
+		}
+		fields, err := q.GetStatsByFieldsAddGroupingByTime(step, off)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(fields, fieldsExpected) {
+			t.Fatalf("unexpected byFields;\ngot\n%q\nwant\n%q", fields, fieldsExpected)
+		}
+		if s := q.String(); s != qExpected {
+			t.Fatalf("unexpected query\ngot\n%s\nwant\n%s", s, qExpected)
+		}
+	}
+
+	f(`* | stats count() x`, nsecsPerHour, 0, []string{}, `* | stats by (_time:1h) count(*) as x`)
+	f(`error | stats by (level) count() x | sort by (x)`, nsecsPerMinute, 30*nsecsPerSecond, []string{"level"},
+		`error | stats by (_time:1m offset 30s, level) count(*) as x | sort by (x)`)
+}
+
+func TestQueryGetStatsByFieldsAddGroupingByTime_Failure(t *testing.T) {
+	f := func(qStr string) {
+		t.Helper()
+
+		q, err := ParseQuery(qStr)
+		if err != nil {
+			t.Fatalf("cannot parse [%s]: %s", qStr, err)
+		}
+		if _, err := q.GetStatsByFieldsAddGroupingByTime(nsecsPerHour, 0); err == nil {
+			t.Fatalf("expecting non-nil error for [%s]", qStr)
+		}
+	}
+
+	f(`*`)
+	f(`* | stats by (_time:1m) count()`)
+	f(`foo | stats count() x | fields x`)
+}
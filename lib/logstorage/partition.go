@@ -2,10 +2,16 @@ package logstorage
 
 import (
 	"bytes"
+	"encoding/json"
+	"os"
 	"path/filepath"
+	"slices"
 	"sort"
+	"sync"
+	"unsafe"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/cgroup"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fs"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
@@ -33,6 +39,28 @@ type partition struct {
 
 	// ddb is the datadb used for the given partition
 	ddb *datadb
+
+	// indexedFields contains fields, which are indexed in idb for all the logs in the partition.
+	//
+	// See https://docs.victoriametrics.com/victorialogs/#indexed-fields
+	indexedFields map[string]struct{}
+
+	// streamRegistrationShards serialize registration of new streams in idb.
+	//
+	// Concurrent registrations of the same stream are serialized, so the stream is registered only once.
+	// The shard is selected by streamID, so registrations of distinct streams don't contend on a single lock.
+	streamRegistrationShards []streamRegistrationShard
+}
+
+type streamRegistrationShardNopad struct {
+	mu sync.Mutex
+}
+
+type streamRegistrationShard struct {
+	streamRegistrationShardNopad
+
+	// The padding prevents false sharing on widespread platforms with 128 mod (cache line size) = 0 .
+	_ [128 - unsafe.Sizeof(streamRegistrationShardNopad{})%128]byte
 }
 
 // mustCreatePartition creates a partition at the given path.
@@ -40,7 +68,9 @@ type partition struct {
 // The created partition can be opened with mustOpenPartition() after is has been created.
 //
 // The created partition can be deleted with mustDeletePartition() when it is no longer needed.
-func mustCreatePartition(path string) {
+//
+// indexedFields must contain fields to index for all the logs in the created partition.
+func mustCreatePartition(path string, indexedFields []string) {
 	fs.MustMkdirFailIfExist(path)
 
 	indexdbPath := filepath.Join(path, indexdbDirname)
@@ -48,6 +78,34 @@ func mustCreatePartition(path string) {
 
 	datadbPath := filepath.Join(path, datadbDirname)
 	mustCreateDatadb(datadbPath)
+
+	mustWriteIndexedFields(path, indexedFields)
+}
+
+func mustWriteIndexedFields(path string, indexedFields []string) {
+	data, err := json.Marshal(indexedFields)
+	if err != nil {
+		logger.Panicf("BUG: cannot marshal indexedFields to JSON: %s", err)
+	}
+	indexedFieldsPath := filepath.Join(path, indexedFieldsFilename)
+	fs.MustWriteAtomic(indexedFieldsPath, data, true)
+}
+
+func mustReadIndexedFields(path string) []string {
+	indexedFieldsPath := filepath.Join(path, indexedFieldsFilename)
+	if !fs.IsPathExist(indexedFieldsPath) {
+		// The partition has been created before indexed fields were introduced.
+		return nil
+	}
+	data, err := os.ReadFile(indexedFieldsPath)
+	if err != nil {
+		logger.Panicf("FATAL: cannot read %s: %s", indexedFieldsPath, err)
+	}
+	var indexedFields []string
+	if err := json.Unmarshal(data, &indexedFields); err != nil {
+		logger.Panicf("FATAL: cannot parse %s: %s", indexedFieldsPath, err)
+	}
+	return indexedFields
 }
 
 // mustDeletePartition deletes partition at the given path.
@@ -67,12 +125,31 @@ func mustOpenPartition(s *Storage, path string) *partition {
 	indexdbPath := filepath.Join(path, indexdbDirname)
 	idb := mustOpenIndexdb(indexdbPath, name, s)
 
+	// The index for the given field can be used only if all the logs in the partition are indexed by this field.
+	// So use only fields, which were indexed since the partition creation and are still enabled in s.
+	// Fields missing in s are dropped from the partition, since the logs ingested after that aren't indexed by these fields.
+	indexedFieldsPartition := mustReadIndexedFields(path)
+	var indexedFieldsList []string
+	indexedFields := make(map[string]struct{})
+	for _, f := range indexedFieldsPartition {
+		if slices.Contains(s.indexedFields, f) {
+			indexedFieldsList = append(indexedFieldsList, f)
+			indexedFields[f] = struct{}{}
+		}
+	}
+	if len(indexedFieldsList) != len(indexedFieldsPartition) {
+		mustWriteIndexedFields(path, indexedFieldsList)
+	}
+
 	// Start initializing the partition
 	pt := &partition{
-		s:    s,
-		path: path,
-		name: name,
-		idb:  idb,
+		s:             s,
+		path:          path,
+		name:          name,
+		idb:           idb,
+		indexedFields: indexedFields,
+
+		streamRegistrationShards: make([]streamRegistrationShard, cgroup.AvailableCPUs()),
 	}
 
 	// Open datadb
@@ -128,17 +205,15 @@ func (pt *partition) mustAddRows(lr *LogRows) {
 			if pt.hasStreamIDInCache(streamID) {
 				continue
 			}
-			if !pt.idb.hasStreamID(streamID) {
-				streamTagsCanonical := streamTagsCanonicals[rowIdx]
-				pt.idb.mustRegisterStream(streamID, streamTagsCanonical)
-				if logNewStreams {
-					pt.logNewStream(streamTagsCanonical, lr.rows[rowIdx])
-				}
-			}
-			pt.putStreamIDToCache(streamID)
+			pt.mustRegisterStreamIfMissing(streamID, streamTagsCanonicals[rowIdx], lr.rows[rowIdx], logNewStreams)
 		}
 	}
 
+	// Register indexed fields in indexdb
+	if len(pt.indexedFields) > 0 {
+		pt.mustRegisterIndexedFields(lr)
+	}
+
 	// Add rows to datadb
 	pt.ddb.mustAddRows(lr)
 	if pt.s.logIngestedRows {
@@ -146,6 +221,70 @@ func (pt *partition) mustAddRows(lr *LogRows) {
 	}
 }
 
+// mustRegisterStreamIfMissing registers the stream with the given streamID in pt.idb if it isn't registered yet.
+func (pt *partition) mustRegisterStreamIfMissing(streamID *streamID, streamTagsCanonical []byte, fields []Field, logNewStreams bool) {
+	shard := &pt.streamRegistrationShards[streamID.id.lo%uint64(len(pt.streamRegistrationShards))]
+	shard.mu.Lock()
+	defer shard.mu.Unlock()
+
+	// Re-check the cache, since the stream could be registered by concurrent goroutine while waiting for the lock.
+	// Newly registered streams may be missing in pt.idb search results for some time,
+	// so the cache is the only reliable source of truth for them.
+	if pt.hasStreamIDInCache(streamID) {
+		return
+	}
+	if !pt.idb.hasStreamID(streamID) {
+		pt.idb.mustRegisterStream(streamID, streamTagsCanonical)
+		if logNewStreams {
+			pt.logNewStream(streamTagsCanonical, fields)
+		}
+	}
+	pt.putStreamIDToCache(streamID)
+}
+
+// mustRegisterIndexedFields registers values for pt.indexedFields from lr in pt.idb.
+func (pt *partition) mustRegisterIndexedFields(lr *LogRows) {
+	var pendingFields []Field
+	bb := bbPool.Get()
+	streamIDs := lr.streamIDs
+	for i, fields := range lr.rows {
+		streamID := &streamIDs[i]
+		pendingFields = pendingFields[:0]
+		for _, f := range fields {
+			if f.Value == "" {
+				continue
+			}
+			f.Name = getCanonicalColumnName(f.Name)
+			if _, ok := pt.indexedFields[f.Name]; !ok {
+				continue
+			}
+			bb.B = pt.marshalIndexedFieldCacheKey(bb.B[:0], streamID, &f)
+			if pt.s.indexedFieldsCache.Has(bb.B) {
+				continue
+			}
+			pendingFields = append(pendingFields, f)
+		}
+		if len(pendingFields) == 0 {
+			continue
+		}
+
+		pt.idb.mustRegisterIndexedFields(streamID, pendingFields)
+		for j := range pendingFields {
+			bb.B = pt.marshalIndexedFieldCacheKey(bb.B[:0], streamID, &pendingFields[j])
+			pt.s.indexedFieldsCache.Set(bb.B, okValue)
+		}
+	}
+	bbPool.Put(bb)
+}
+
+func (pt *partition) marshalIndexedFieldCacheKey(dst []byte, sid *streamID, f *Field) []byte {
+	dst = encoding.MarshalBytes(dst, bytesutil.ToUnsafeBytes(pt.name))
+	dst = sid.marshal(dst)
+	dst = encoding.MarshalBytes(dst, bytesutil.ToUnsafeBytes(f.Name))
+	dst = encoding.MarshalBytes(dst, bytesutil.ToUnsafeBytes(f.Value))
+	return dst
+}
+
 func (pt *partition) logNewStream(streamTagsCanonical []byte, fields []Field) {
 	streamTags := getStreamTagsString(streamTagsCanonical)
 	rf := RowFormatter(fields)
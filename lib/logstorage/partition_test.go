@@ -17,7 +17,7 @@ func TestPartitionLifecycle(t *testing.T) {
 
 	s := newTestStorage()
 	for i := 0; i < 3; i++ {
-		mustCreatePartition(path)
+		mustCreatePartition(path, nil)
 		for j := 0; j < 2; j++ {
 			pt := mustOpenPartition(s, path)
 			ddbStats.reset()
@@ -58,7 +58,7 @@ func TestPartitionMustAddRowsSerial(t *testing.T) {
 	var ddbStats DatadbStats
 
 	s := newTestStorage()
-	mustCreatePartition(path)
+	mustCreatePartition(path, nil)
 	pt := mustOpenPartition(s, path)
 
 	// Try adding the same entry at a time.
@@ -141,7 +141,7 @@ func TestPartitionMustAddRowsConcurrent(t *testing.T) {
 	path := t.Name()
 	s := newTestStorage()
 
-	mustCreatePartition(path)
+	mustCreatePartition(path, nil)
 	pt := mustOpenPartition(s, path)
 
 	const workersCount = 3
@@ -173,6 +173,13 @@ func TestPartitionMustAddRowsConcurrent(t *testing.T) {
 		t.Fatalf("unexpected number of entries; got %d; want %d", n, totalRowsCount.Load())
 	}
 
+	// Every stream must be registered only once, even if it is ingested by concurrent workers
+	var idbStats IndexdbStats
+	pt.idb.updateStats(&idbStats)
+	if n := idbStats.StreamsCreatedTotal; n != 7*5 {
+		t.Fatalf("unexpected number of created streams; got %d; want %d", n, 7*5)
+	}
+
 	mustClosePartition(pt)
 	mustDeletePartition(path)
 
@@ -5,6 +5,7 @@ import (
 	"html"
 	"strconv"
 	"strings"
+	"unicode/utf8"
 )
 
 // pattern represents text pattern in the form 'some_text<some_field>other_text...'
@@ -152,9 +153,75 @@ func tryUnquoteString(s, opt string) (string, int) {
 	if len(s) == 0 {
 		return "", -1
 	}
-	if s[0] != '"' && s[0] != '`' {
+	switch s[0] {
+	case '"':
+		return tryUnquoteDoubleQuotedString(s)
+	case '`':
+		return tryUnquoteStringSlow(s)
+	default:
 		return "", -1
 	}
+}
+
+// tryUnquoteDoubleQuotedString unquotes double-quoted string at the beginning of s.
+//
+// It returns the same results as tryUnquoteStringSlow, but it is much faster on long strings,
+// since it skips chunks without escape sequences with strings.IndexByte instead of processing them rune by rune.
+func tryUnquoteDoubleQuotedString(s string) (string, int) {
+	tail := s[1:]
+	var buf []byte
+
+	// n is the position of the next double quote in tail.
+	// It is re-calculated only after the double quote is consumed by escape sequence, so long strings with many escape sequences are processed in linear time.
+	n := -1
+	for {
+		if n < 0 {
+			n = strings.IndexByte(tail, '"')
+			if n < 0 {
+				return "", -1
+			}
+		}
+		chunk := tail[:n]
+		escapeIdx := strings.IndexByte(chunk, '\\')
+		if escapeIdx >= 0 {
+			chunk = chunk[:escapeIdx]
+		}
+		if strings.IndexByte(chunk, '\n') >= 0 || !utf8.ValidString(chunk) {
+			// Fall back to the slow path for the rare cases with newlines and invalid utf-8 chars,
+			// since they must be handled in the same way as strconv.Unquote does.
+			return tryUnquoteStringSlow(s)
+		}
+
+		if escapeIdx < 0 {
+			// Found the closing quote
+			nOffset := len(s) - len(tail) + n + 1
+			if buf == nil {
+				return chunk, nOffset
+			}
+			buf = append(buf, chunk...)
+			return string(buf), nOffset
+		}
+
+		// Unescape the next char
+		if buf == nil {
+			buf = make([]byte, 0, len(tail))
+		}
+		buf = append(buf, chunk...)
+		r, multibyte, tailNew, err := strconv.UnquoteChar(tail[escapeIdx:], '"')
+		if err != nil {
+			return "", -1
+		}
+		if r < utf8.RuneSelf || !multibyte {
+			buf = append(buf, byte(r))
+		} else {
+			buf = utf8.AppendRune(buf, r)
+		}
+		n -= len(tail) - len(tailNew)
+		tail = tailNew
+	}
+}
+
+func tryUnquoteStringSlow(s string) (string, int) {
 	qp, err := strconv.QuotedPrefix(s)
 	if err != nil {
 		return "", -1
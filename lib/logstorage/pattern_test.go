@@ -2,6 +2,7 @@ package logstorage
 
 import (
 	"reflect"
+	"strconv"
 	"testing"
 )
 
@@ -240,3 +241,68 @@ func TestParsePatternStepsFailure(t *testing.T) {
 	f("<foo")
 	f("foo<bar")
 }
+
+func TestTryUnquoteString(t *testing.T) {
+	f := func(s string) {
+		t.Helper()
+
+		// The result must match strconv.Unquote(strconv.QuotedPrefix(s))
+		resultExpected, nOffsetExpected := "", -1
+		if qp, err := strconv.QuotedPrefix(s); err == nil {
+			if us, err := strconv.Unquote(qp); err == nil {
+				resultExpected, nOffsetExpected = us, len(qp)
+			}
+		}
+
+		result, nOffset := tryUnquoteString(s, "")
+		if result != resultExpected || nOffset != nOffsetExpected {
+			t.Fatalf("unexpected result for tryUnquoteString(%q); got (%q, %d); want (%q, %d)", s, result, nOffset, resultExpected, nOffsetExpected)
+		}
+
+		if s != "" && s[0] == '"' {
+			result, nOffset = tryUnquoteDoubleQuotedString(s)
+			if result != resultExpected || nOffset != nOffsetExpected {
+				t.Fatalf("unexpected result for tryUnquoteDoubleQuotedString(%q); got (%q, %d); want (%q, %d)", s, result, nOffset, resultExpected, nOffsetExpected)
+			}
+		}
+	}
+
+	// non-quoted strings
+	f(``)
+	f(`foo`)
+	f(`'foo'`)
+
+	// double-quoted strings
+	f(`"`)
+	f(`""`)
+	f(`"" tail`)
+	f(`"foo"`)
+	f(`"foo" "bar"`)
+	f(`"foo bar`)
+	f(`"foo\"bar"`)
+	f(`"foo\"`)
+	f(`"foo\\"bar"`)
+	f(`"a\nb\tc\\d\"e" tail"`)
+	f(`"\u0444\U0001F600\x41\101\a\b\f\r\v" x`)
+	f(`"\xff\xfe"`)
+	f(`"\'"`)
+	f(`"\q"`)
+	f(`"\u04"`)
+	f(`"\x4"`)
+	f(`"\`)
+	f(`"foo\nbar`)
+	f("\"foo\nbar\"")
+	f("\"foo\\\nbar\"")
+	f("\"фу\\tбар\" ")
+	f("\"\xff\"")
+	f("\"a\\n\xffb\"")
+	f(`"{\"level\":\"error\",\"stacktrace\":\"foo\\n\\tbar\"}" tail`)
+
+	// backtick-quoted strings
+	f("``")
+	f("`foo` bar")
+	f("`foo\\\"bar`")
+	f("`foo\nbar`")
+	f("`foo\r\nbar`")
+	f("`foo")
+}
@@ -1,6 +1,8 @@
 package logstorage
 
 import (
+	"strconv"
+	"strings"
 	"testing"
 )
 
@@ -78,3 +80,49 @@ func benchmarkPatternApply(b *testing.B, patternStr string, a []string) {
 		GlobalSink.Add(uint64(sink))
 	})
 }
+
+func BenchmarkPatternApplyLongMessages(b *testing.B) {
+	prefix := strings.Repeat("some long text without the needed separators, which must be skipped quickly; ", 30)
+	a := []string{
+		prefix + `user_id=12345 ip=1.2.3.4 duration=23ms status=200 path="/foo/bar?baz=1"`,
+		prefix + `user_id=23 ip=10.20.30.40 duration=1.5s status=503 path="/api/v1/query_range"`,
+		prefix + `user_id=4321 ip=127.0.0.1 duration=42ms status=404 path="/not/found/\"quoted\""`,
+	}
+
+	b.Run("single-field", func(b *testing.B) {
+		benchmarkPatternApply(b, `ip=<ip> `, a)
+	})
+	b.Run("single-field-unquote", func(b *testing.B) {
+		benchmarkPatternApply(b, `path=<path>`, a)
+	})
+	b.Run("many-fields", func(b *testing.B) {
+		benchmarkPatternApply(b, `user_id=<user_id> ip=<ip> duration=<duration> status=<status> path=<path>`, a)
+	})
+	b.Run("missing-field", func(b *testing.B) {
+		benchmarkPatternApply(b, `trace_id=<trace_id> `, a)
+	})
+}
+
+func BenchmarkTryUnquoteString(b *testing.B) {
+	f := func(name, s string) {
+		b.Run(name, func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(len(s)))
+			b.RunParallel(func(pb *testing.PB) {
+				sink := 0
+				for pb.Next() {
+					us, offset := tryUnquoteString(s, "")
+					sink += len(us) + offset
+				}
+				GlobalSink.Add(uint64(sink))
+			})
+		})
+	}
+
+	f("short", `"foo bar"`)
+	f("short-escaped", `"foo\tbar"`)
+	f("long", strconv.Quote(strings.Repeat("foo bar baz ", 100)))
+	f("long-escaped", strconv.Quote(strings.Repeat("foo\tbar \"baz\"\n", 100)))
+	f("long-unicode", strconv.Quote(strings.Repeat("фуу бар баз\n", 100)))
+	f("backtick", "`"+strings.Repeat("foo bar baz ", 100)+"`")
+}
@@ -159,6 +159,18 @@ func parsePipe(lex *lexer) (pipe, error) {
 			return nil, fmt.Errorf("cannot parse 'format' pipe: %w", err)
 		}
 		return pf, nil
+	case lex.isKeyword("geoip"):
+		pg, err := parsePipeGeoIP(lex)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse 'geoip' pipe: %w", err)
+		}
+		return pg, nil
+	case lex.isKeyword("join"):
+		pj, err := parsePipeJoin(lex)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse 'join' pipe: %w", err)
+		}
+		return pj, nil
 	case lex.isKeyword("limit", "head"):
 		pl, err := parsePipeLimit(lex)
 		if err != nil {
@@ -294,6 +306,8 @@ var pipeNames = func() map[string]struct{} {
 		"fields", "keep",
 		"filter", "where",
 		"format",
+		"geoip",
+		"join",
 		"limit", "head",
 		"math", "eval",
 		"offset", "skip",
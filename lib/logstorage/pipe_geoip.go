@@ -0,0 +1,165 @@
+package logstorage
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+// pipeGeoIP processes '| geoip ...' pipe.
+//
+// See https://docs.victoriametrics.com/victorialogs/logsql/#geoip-pipe
+type pipeGeoIP struct {
+	// fromField is the field with IP addresses to resolve
+	fromField string
+
+	// resultPrefix is the prefix to add to the resulting field names
+	resultPrefix string
+
+	// iff is an optional filter for skipping geoip lookups
+	iff *ifFilter
+
+	// dbs contains geoip databases for the lookups.
+	//
+	// It is initialized by Storage.initGeoIPPipes before the query execution.
+	dbs []*geoIPDB
+}
+
+// geoIPDefaultResultPrefix is the default prefix for the fields added by geoip pipe.
+const geoIPDefaultResultPrefix = "geoip."
+
+// geoIPFields contains the names of the fields added by geoip pipe without the result prefix.
+var geoIPFields = []string{
+	"country_code",
+	"country_name",
+	"city",
+	"asn",
+	"asn_org",
+}
+
+func (pg *pipeGeoIP) String() string {
+	s := "geoip"
+	if pg.iff != nil {
+		s += " " + pg.iff.String() + " "
+	}
+	s += "(" + quoteTokenIfNeeded(pg.fromField) + ")"
+	if pg.resultPrefix != geoIPDefaultResultPrefix {
+		s += " result_prefix " + quoteTokenIfNeeded(pg.resultPrefix)
+	}
+	return s
+}
+
+func (pg *pipeGeoIP) canLiveTail() bool {
+	return true
+}
+
+func (pg *pipeGeoIP) getOutFields() []string {
+	outFields := make([]string, len(geoIPFields))
+	for i, f := range geoIPFields {
+		outFields[i] = pg.resultPrefix + f
+	}
+	return outFields
+}
+
+func (pg *pipeGeoIP) updateNeededFields(neededFields, unneededFields fieldsSet) {
+	updateNeededFieldsForUnpackPipe(pg.fromField, pg.getOutFields(), false, false, pg.iff, neededFields, unneededFields)
+}
+
+func (pg *pipeGeoIP) optimize() {
+	pg.iff.optimizeFilterIn()
+}
+
+func (pg *pipeGeoIP) hasFilterInWithQuery() bool {
+	return pg.iff.hasFilterInWithQuery()
+}
+
+func (pg *pipeGeoIP) initFilterInValues(cache map[string][]string, getFieldValuesFunc getFieldValuesFunc) (pipe, error) {
+	iffNew, err := pg.iff.initFilterInValues(cache, getFieldValuesFunc)
+	if err != nil {
+		return nil, err
+	}
+	pgNew := *pg
+	pgNew.iff = iffNew
+	return &pgNew, nil
+}
+
+func (pg *pipeGeoIP) newPipeProcessor(workersCount int, _ <-chan struct{}, _ func(), ppNext pipeProcessor) pipeProcessor {
+	resolveGeoIP := func(uctx *fieldsUnpackerContext, s string) {
+		var values [5]string
+
+		ip, err := netip.ParseAddr(s)
+		if err == nil {
+			for _, db := range pg.dbs {
+				m, err := db.lookup(ip)
+				if err != nil || m == nil {
+					continue
+				}
+				setGeoIPValue(&values[0], m, "country", "iso_code")
+				setGeoIPValue(&values[1], m, "country", "names", "en")
+				setGeoIPValue(&values[2], m, "city", "names", "en")
+				setGeoIPValue(&values[3], m, "autonomous_system_number")
+				setGeoIPValue(&values[4], m, "autonomous_system_organization")
+			}
+		}
+
+		for i, f := range geoIPFields {
+			uctx.addField(f, values[i])
+		}
+	}
+
+	return newPipeUnpackProcessor(workersCount, resolveGeoIP, ppNext, pg.fromField, pg.resultPrefix, false, false, pg.iff)
+}
+
+// setGeoIPValue sets *dst to the value at the given path in m if *dst is empty.
+//
+// This allows obtaining the fields from multiple databases, e.g. GeoLite2-City and GeoLite2-ASN.
+func setGeoIPValue(dst *string, m map[string]any, path ...string) {
+	if *dst == "" {
+		*dst = getGeoIPString(m, path...)
+	}
+}
+
+func parsePipeGeoIP(lex *lexer) (*pipeGeoIP, error) {
+	if !lex.isKeyword("geoip") {
+		return nil, fmt.Errorf("unexpected token: %q; want %q", lex.token, "geoip")
+	}
+	lex.nextToken()
+
+	var iff *ifFilter
+	if lex.isKeyword("if") {
+		f, err := parseIfFilter(lex)
+		if err != nil {
+			return nil, err
+		}
+		iff = f
+	}
+
+	if !lex.isKeyword("(") {
+		return nil, fmt.Errorf("missing '(' after 'geoip'")
+	}
+	lex.nextToken()
+	fromField, err := parseFieldName(lex)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse field name with IP addresses at 'geoip': %w", err)
+	}
+	if !lex.isKeyword(")") {
+		return nil, fmt.Errorf("missing ')' after 'geoip(%s'", quoteTokenIfNeeded(fromField))
+	}
+	lex.nextToken()
+
+	resultPrefix := geoIPDefaultResultPrefix
+	if lex.isKeyword("result_prefix") {
+		lex.nextToken()
+		p, err := getCompoundToken(lex)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse 'result_prefix': %w", err)
+		}
+		resultPrefix = p
+	}
+
+	pg := &pipeGeoIP{
+		fromField:    fromField,
+		resultPrefix: resultPrefix,
+		iff:          iff,
+	}
+	return pg, nil
+}
@@ -0,0 +1,199 @@
+package logstorage
+
+import (
+	"testing"
+)
+
+func TestParsePipeGeoIPSuccess(t *testing.T) {
+	f := func(pipeStr string) {
+		t.Helper()
+		expectParsePipeSuccess(t, pipeStr)
+	}
+
+	f(`geoip(ip)`)
+	f(`geoip("client ip")`)
+	f(`geoip(ip) result_prefix client_`)
+	f(`geoip if (ip:ipv4_range(1.0.0.0, 1.255.255.255)) (ip) result_prefix client.`)
+}
+
+func TestParsePipeGeoIPFailure(t *testing.T) {
+	f := func(pipeStr string) {
+		t.Helper()
+		expectParsePipeFailure(t, pipeStr)
+	}
+
+	f(`geoip`)
+	f(`geoip ip`)
+	f(`geoip()`)
+	f(`geoip(ip`)
+	f(`geoip(ip, x)`)
+	f(`geoip(ip) result_prefix`)
+	f(`geoip(ip) if (x:y)`)
+	f(`geoip(ip) foo`)
+}
+
+func TestPipeGeoIP(t *testing.T) {
+	cityData := newTestGeoIPDBData(t, 6, 28, map[string]map[string]any{
+		"1.2.3.0/24": {
+			"city": map[string]any{
+				"names": map[string]any{
+					"en": "Sydney",
+				},
+			},
+			"country": map[string]any{
+				"iso_code": "AU",
+				"names": map[string]any{
+					"en": "Australia",
+				},
+			},
+		},
+	})
+	cityDB, err := newGeoIPDB(cityData)
+	if err != nil {
+		t.Fatalf("cannot open city database: %s", err)
+	}
+	asnData := newTestGeoIPDBData(t, 4, 24, map[string]map[string]any{
+		"1.2.0.0/16": {
+			"autonomous_system_number":       uint64(13335),
+			"autonomous_system_organization": "CLOUDFLARENET",
+		},
+	})
+	asnDB, err := newGeoIPDB(asnData)
+	if err != nil {
+		t.Fatalf("cannot open asn database: %s", err)
+	}
+
+	f := func(pipeStr string, rows, rowsExpected [][]Field) {
+		t.Helper()
+
+		lex := newLexer(pipeStr)
+		p, err := parsePipe(lex)
+		if err != nil {
+			t.Fatalf("unexpected error when parsing %q: %s", pipeStr, err)
+		}
+		pg := p.(*pipeGeoIP)
+		pg.dbs = []*geoIPDB{cityDB, asnDB}
+
+		workersCount := 5
+		stopCh := make(chan struct{})
+		cancel := func() {}
+		ppTest := newTestPipeProcessor()
+		pp := pg.newPipeProcessor(workersCount, stopCh, cancel, ppTest)
+
+		brw := newTestBlockResultWriter(workersCount, pp)
+		for _, row := range rows {
+			brw.writeRow(row)
+		}
+		brw.flush()
+		pp.flush()
+
+		ppTest.expectRows(t, rowsExpected)
+	}
+
+	// lookups from multiple databases
+	f("geoip(ip)", [][]Field{
+		{
+			{"ip", "1.2.3.4"},
+		},
+		{
+			{"ip", "1.2.200.1"},
+		},
+		{
+			{"ip", "127.0.0.1"},
+		},
+		{
+			{"ip", "foobar"},
+		},
+		{
+			{"_msg", "missing ip"},
+		},
+	}, [][]Field{
+		{
+			{"ip", "1.2.3.4"},
+			{"geoip.country_code", "AU"},
+			{"geoip.country_name", "Australia"},
+			{"geoip.city", "Sydney"},
+			{"geoip.asn", "13335"},
+			{"geoip.asn_org", "CLOUDFLARENET"},
+		},
+		{
+			{"ip", "1.2.200.1"},
+			{"geoip.country_code", ""},
+			{"geoip.country_name", ""},
+			{"geoip.city", ""},
+			{"geoip.asn", "13335"},
+			{"geoip.asn_org", "CLOUDFLARENET"},
+		},
+		{
+			{"ip", "127.0.0.1"},
+			{"geoip.country_code", ""},
+			{"geoip.country_name", ""},
+			{"geoip.city", ""},
+			{"geoip.asn", ""},
+			{"geoip.asn_org", ""},
+		},
+		{
+			{"ip", "foobar"},
+			{"geoip.country_code", ""},
+			{"geoip.country_name", ""},
+			{"geoip.city", ""},
+			{"geoip.asn", ""},
+			{"geoip.asn_org", ""},
+		},
+		{
+			{"_msg", "missing ip"},
+			{"geoip.country_code", ""},
+			{"geoip.country_name", ""},
+			{"geoip.city", ""},
+			{"geoip.asn", ""},
+			{"geoip.asn_org", ""},
+		},
+	})
+
+	// result_prefix and if filter
+	f("geoip if (x:y) (ip) result_prefix client_", [][]Field{
+		{
+			{"ip", "1.2.3.4"},
+			{"x", "y"},
+		},
+		{
+			{"ip", "1.2.3.4"},
+			{"x", "z"},
+		},
+	}, [][]Field{
+		{
+			{"ip", "1.2.3.4"},
+			{"x", "y"},
+			{"client_country_code", "AU"},
+			{"client_country_name", "Australia"},
+			{"client_city", "Sydney"},
+			{"client_asn", "13335"},
+			{"client_asn_org", "CLOUDFLARENET"},
+		},
+		{
+			{"ip", "1.2.3.4"},
+			{"x", "z"},
+		},
+	})
+}
+
+func TestPipeGeoIPUpdateNeededFields(t *testing.T) {
+	f := func(s string, neededFields, unneededFields, neededFieldsExpected, unneededFieldsExpected string) {
+		t.Helper()
+		expectPipeNeededFields(t, s, neededFields, unneededFields, neededFieldsExpected, unneededFieldsExpected)
+	}
+
+	// all the needed fields
+	f("geoip(ip)", "*", "", "*", "geoip.asn,geoip.asn_org,geoip.city,geoip.country_code,geoip.country_name")
+	f("geoip if (x:y) (ip)", "*", "", "*", "geoip.asn,geoip.asn_org,geoip.city,geoip.country_code,geoip.country_name")
+
+	// all the needed fields, unneeded fields contain all the output fields
+	f("geoip(ip)", "*", "geoip.asn,geoip.asn_org,geoip.city,geoip.country_code,geoip.country_name", "*", "geoip.asn,geoip.asn_org,geoip.city,geoip.country_code,geoip.country_name")
+
+	// needed fields do not intersect with output fields
+	f("geoip(ip)", "f1,f2", "", "f1,f2", "")
+
+	// needed fields intersect with output fields
+	f("geoip(ip)", "f1,geoip.city", "", "f1,ip", "")
+	f("geoip if (x:y) (ip) result_prefix a_", "f1,a_asn", "", "f1,ip,x", "")
+}
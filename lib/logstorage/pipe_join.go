@@ -0,0 +1,268 @@
+package logstorage
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"unsafe"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/slicesutil"
+)
+
+// pipeJoin processes '| join ...' pipe.
+//
+// See https://docs.victoriametrics.com/victorialogs/logsql/#join-pipe
+type pipeJoin struct {
+	// byFields contains fields to join by
+	byFields []string
+
+	// q is the query for obtaining the rows to join with
+	q *Query
+
+	// m maps byFields values to the rows obtained from q.
+	//
+	// It is initialized by Storage.initJoinMaps before the query execution.
+	m map[string][][]Field
+}
+
+func (pj *pipeJoin) String() string {
+	return fmt.Sprintf("join by (%s) from (%s)", fieldNamesString(pj.byFields), pj.q.String())
+}
+
+func (pj *pipeJoin) canLiveTail() bool {
+	return false
+}
+
+func (pj *pipeJoin) optimize() {
+	pj.q.Optimize()
+}
+
+func (pj *pipeJoin) hasFilterInWithQuery() bool {
+	return false
+}
+
+func (pj *pipeJoin) initFilterInValues(_ map[string][]string, _ getFieldValuesFunc) (pipe, error) {
+	return pj, nil
+}
+
+func (pj *pipeJoin) updateNeededFields(neededFields, unneededFields fieldsSet) {
+	if neededFields.contains("*") {
+		unneededFields.removeFields(pj.byFields)
+	} else {
+		neededFields.addFields(pj.byFields)
+	}
+}
+
+// initJoinMap returns a copy of pj with the join map initialized from the rows returned by runQuery for pj.q.
+//
+// maxStateSize is the maximum size in bytes of the join map.
+func (pj *pipeJoin) initJoinMap(runQuery func(q *Query, writeBlock func(workerID uint, br *blockResult)) error, maxStateSize int64) (*pipeJoin, error) {
+	m := make(map[string][][]Field)
+	stateSize := int64(0)
+	stateSizeExceeded := false
+
+	var mLock sync.Mutex
+	var keyBuf []byte
+	writeBlock := func(_ uint, br *blockResult) {
+		if len(br.timestamps) == 0 {
+			return
+		}
+
+		cs := br.getColumns()
+		byValues := make([][]string, len(pj.byFields))
+		for i, f := range pj.byFields {
+			c := br.getColumnByName(f)
+			byValues[i] = c.getValues(br)
+		}
+
+		mLock.Lock()
+		defer mLock.Unlock()
+
+		for rowIdx := range br.timestamps {
+			if stateSizeExceeded {
+				return
+			}
+
+			keyBuf = marshalJoinKey(keyBuf[:0], byValues, rowIdx)
+			if keyBuf == nil {
+				// Skip rows without byFields, since they cannot be joined with anything.
+				continue
+			}
+
+			fields := make([]Field, 0, len(cs))
+			for _, c := range cs {
+				if slices.Contains(pj.byFields, c.name) {
+					continue
+				}
+				v := c.getValueAtRow(br, rowIdx)
+				fields = append(fields, Field{
+					Name:  strings.Clone(c.name),
+					Value: strings.Clone(v),
+				})
+				stateSize += int64(len(c.name) + len(v))
+			}
+			stateSize += int64(unsafe.Sizeof(Field{})) * int64(len(fields))
+
+			rows, ok := m[string(keyBuf)]
+			if !ok {
+				stateSize += int64(len(keyBuf))
+			}
+			m[string(keyBuf)] = append(rows, fields)
+
+			if stateSize > maxStateSize {
+				stateSizeExceeded = true
+			}
+		}
+	}
+
+	if err := runQuery(pj.q, writeBlock); err != nil {
+		return nil, fmt.Errorf("cannot execute query at [%s]: %w", pj, err)
+	}
+	if stateSizeExceeded {
+		return nil, fmt.Errorf("cannot execute [%s], since the query results require more than %dMB of memory; "+
+			"reduce the number of rows returned by the query or select only the needed fields with the 'fields' pipe", pj, maxStateSize/(1<<20))
+	}
+
+	pjNew := *pj
+	pjNew.m = m
+	return &pjNew, nil
+}
+
+// marshalJoinKey appends the key for byValues at the given rowIdx to dst and returns the result.
+//
+// nil is returned if all the byValues at rowIdx are empty.
+func marshalJoinKey(dst []byte, byValues [][]string, rowIdx int) []byte {
+	hasNonEmptyValues := false
+	for _, values := range byValues {
+		v := values[rowIdx]
+		if v != "" {
+			hasNonEmptyValues = true
+		}
+		dst = encoding.MarshalBytes(dst, []byte(v))
+	}
+	if !hasNonEmptyValues {
+		return nil
+	}
+	return dst
+}
+
+func (pj *pipeJoin) newPipeProcessor(workersCount int, stopCh <-chan struct{}, _ func(), ppNext pipeProcessor) pipeProcessor {
+	return &pipeJoinProcessor{
+		pj:     pj,
+		stopCh: stopCh,
+		ppNext: ppNext,
+
+		shards: make([]pipeJoinProcessorShard, workersCount),
+	}
+}
+
+type pipeJoinProcessor struct {
+	pj     *pipeJoin
+	stopCh <-chan struct{}
+	ppNext pipeProcessor
+
+	shards []pipeJoinProcessorShard
+}
+
+type pipeJoinProcessorShard struct {
+	pipeJoinProcessorShardNopad
+
+	// The padding prevents false sharing on widespread platforms with 128 mod (cache line size) = 0 .
+	_ [128 - unsafe.Sizeof(pipeJoinProcessorShardNopad{})%128]byte
+}
+
+type pipeJoinProcessorShardNopad struct {
+	wctx pipeUnpackWriteContext
+
+	byValues [][]string
+	keyBuf   []byte
+}
+
+func (pjp *pipeJoinProcessor) writeBlock(workerID uint, br *blockResult) {
+	if len(br.timestamps) == 0 {
+		return
+	}
+
+	pj := pjp.pj
+	shard := &pjp.shards[workerID]
+	shard.wctx.init(workerID, pjp.ppNext, false, false, br)
+
+	shard.byValues = slicesutil.SetLength(shard.byValues, len(pj.byFields))
+	byValues := shard.byValues
+	for i, f := range pj.byFields {
+		c := br.getColumnByName(f)
+		byValues[i] = c.getValues(br)
+	}
+
+	for rowIdx := range br.timestamps {
+		shard.keyBuf = marshalJoinKey(shard.keyBuf[:0], byValues, rowIdx)
+		rows := pj.m[string(shard.keyBuf)]
+		if len(rows) == 0 {
+			// Pass the row as is to the next pipe if there are no rows to join with.
+			shard.wctx.writeRow(rowIdx, nil)
+			continue
+		}
+		if needStop(pjp.stopCh) {
+			return
+		}
+		for _, fields := range rows {
+			shard.wctx.writeRow(rowIdx, fields)
+		}
+	}
+
+	shard.wctx.flush()
+	shard.wctx.reset()
+}
+
+func (pjp *pipeJoinProcessor) flush() error {
+	return nil
+}
+
+func parsePipeJoin(lex *lexer) (*pipeJoin, error) {
+	if !lex.isKeyword("join") {
+		return nil, fmt.Errorf("unexpected token: %q; want %q", lex.token, "join")
+	}
+	lex.nextToken()
+
+	// parse by (...)
+	if lex.isKeyword("by") {
+		lex.nextToken()
+	}
+	byFields, err := parseFieldNamesInParens(lex)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse 'by(...)' at 'join': %w", err)
+	}
+	if len(byFields) == 0 {
+		return nil, fmt.Errorf("'by(...)' at 'join' must contain at least a single field")
+	}
+	if slices.Contains(byFields, "*") {
+		return nil, fmt.Errorf("join by '*' isn't supported")
+	}
+
+	// parse optional 'from'
+	if lex.isKeyword("from") {
+		lex.nextToken()
+	}
+
+	// parse (query)
+	if !lex.isKeyword("(") {
+		return nil, fmt.Errorf("missing '(' in front of the query at 'join'")
+	}
+	lex.nextToken()
+	q, err := parseQuery(lex)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse query at 'join': %w", err)
+	}
+	if !lex.isKeyword(")") {
+		return nil, fmt.Errorf("missing ')' after the query [%s] at 'join'", q)
+	}
+	lex.nextToken()
+
+	pj := &pipeJoin{
+		byFields: byFields,
+		q:        q,
+	}
+	return pj, nil
+}
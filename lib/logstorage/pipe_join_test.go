@@ -0,0 +1,248 @@
+package logstorage
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsePipeJoinSuccess(t *testing.T) {
+	f := func(pipeStr string) {
+		t.Helper()
+		expectParsePipeSuccess(t, pipeStr)
+	}
+
+	f(`join by (foo) from (*)`)
+	f(`join by (foo, bar) from (type:user | fields foo, bar, baz)`)
+	f(`join by (foo) from (_time:1h x:in(y | fields z) | stats by (foo) count(*) as hits)`)
+}
+
+func TestParsePipeJoinFailure(t *testing.T) {
+	f := func(pipeStr string) {
+		t.Helper()
+		expectParsePipeFailure(t, pipeStr)
+	}
+
+	f(`join`)
+	f(`join by`)
+	f(`join by ()`)
+	f(`join by (*)`)
+	f(`join by (foo, *)`)
+	f(`join by (foo)`)
+	f(`join by (foo) from`)
+	f(`join by (foo) from ()`)
+	f(`join by (foo) from (bar`)
+	f(`join by (foo) from (bar) baz`)
+}
+
+func TestPipeJoin(t *testing.T) {
+	f := func(pipeStr string, joinRows, rows, rowsExpected [][]Field) {
+		t.Helper()
+
+		lex := newLexer(pipeStr)
+		p, err := parsePipe(lex)
+		if err != nil {
+			t.Fatalf("unexpected error when parsing %q: %s", pipeStr, err)
+		}
+		pj := p.(*pipeJoin)
+
+		runQuery := func(_ *Query, writeBlock func(workerID uint, br *blockResult)) error {
+			brw := newTestBlockResultWriter(5, &testPipeJoinWriter{
+				writeBlockFunc: writeBlock,
+			})
+			for _, row := range joinRows {
+				brw.writeRow(row)
+			}
+			brw.flush()
+			return nil
+		}
+		pjNew, err := pj.initJoinMap(runQuery, 1<<20)
+		if err != nil {
+			t.Fatalf("unexpected error when initializing join map for %q: %s", pipeStr, err)
+		}
+
+		workersCount := 5
+		stopCh := make(chan struct{})
+		cancel := func() {}
+		ppTest := newTestPipeProcessor()
+		pp := pjNew.newPipeProcessor(workersCount, stopCh, cancel, ppTest)
+
+		brw := newTestBlockResultWriter(workersCount, pp)
+		for _, row := range rows {
+			brw.writeRow(row)
+		}
+		brw.flush()
+		pp.flush()
+
+		ppTest.expectRows(t, rowsExpected)
+	}
+
+	joinRows := [][]Field{
+		{
+			{"user_id", "1"},
+			{"user_name", "alice"},
+		},
+		{
+			{"user_id", "2"},
+			{"user_name", "bob"},
+		},
+		{
+			{"user_id", "2"},
+			{"user_name", "robert"},
+		},
+		{
+			{"user_name", "nobody"},
+		},
+	}
+
+	// join by a single field
+	f("join by (user_id) from (*)", joinRows, [][]Field{
+		{
+			{"_msg", "login"},
+			{"user_id", "1"},
+		},
+		{
+			{"_msg", "logout"},
+			{"user_id", "2"},
+		},
+		{
+			{"_msg", "unknown user"},
+			{"user_id", "3"},
+		},
+		{
+			{"_msg", "missing user"},
+		},
+	}, [][]Field{
+		{
+			{"_msg", "login"},
+			{"user_id", "1"},
+			{"user_name", "alice"},
+		},
+		{
+			{"_msg", "logout"},
+			{"user_id", "2"},
+			{"user_name", "bob"},
+		},
+		{
+			{"_msg", "logout"},
+			{"user_id", "2"},
+			{"user_name", "robert"},
+		},
+		{
+			{"_msg", "unknown user"},
+			{"user_id", "3"},
+		},
+		{
+			{"_msg", "missing user"},
+		},
+	})
+
+	// fields from the joined rows override the original fields
+	f("join by (user_id) from (*)", joinRows, [][]Field{
+		{
+			{"user_name", "foo"},
+			{"user_id", "1"},
+		},
+	}, [][]Field{
+		{
+			{"user_name", "alice"},
+			{"user_id", "1"},
+		},
+	})
+
+	// join by multiple fields
+	f("join by (host, app) from (*)", [][]Field{
+		{
+			{"host", "h1"},
+			{"app", "nginx"},
+			{"team", "web"},
+		},
+		{
+			{"host", "h1"},
+			{"app", "postgres"},
+			{"team", "db"},
+		},
+	}, [][]Field{
+		{
+			{"host", "h1"},
+			{"app", "postgres"},
+			{"_msg", "slow query"},
+		},
+		{
+			{"host", "h2"},
+			{"app", "nginx"},
+			{"_msg", "404"},
+		},
+	}, [][]Field{
+		{
+			{"host", "h1"},
+			{"app", "postgres"},
+			{"_msg", "slow query"},
+			{"team", "db"},
+		},
+		{
+			{"host", "h2"},
+			{"app", "nginx"},
+			{"_msg", "404"},
+		},
+	})
+}
+
+func TestPipeJoinStateSizeExceeded(t *testing.T) {
+	lex := newLexer("join by (a) from (*)")
+	p, err := parsePipe(lex)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	pj := p.(*pipeJoin)
+
+	runQuery := func(_ *Query, writeBlock func(workerID uint, br *blockResult)) error {
+		brw := newTestBlockResultWriter(1, &testPipeJoinWriter{
+			writeBlockFunc: writeBlock,
+		})
+		for i := 0; i < 100; i++ {
+			brw.writeRow([]Field{
+				{"a", "foo"},
+				{"b", strings.Repeat("x", 100)},
+			})
+		}
+		brw.flush()
+		return nil
+	}
+	if _, err := pj.initJoinMap(runQuery, 1000); err == nil {
+		t.Fatalf("expecting non-nil error when the join map exceeds the state size limit")
+	}
+}
+
+func TestPipeJoinUpdateNeededFields(t *testing.T) {
+	f := func(s string, neededFields, unneededFields, neededFieldsExpected, unneededFieldsExpected string) {
+		t.Helper()
+		expectPipeNeededFields(t, s, neededFields, unneededFields, neededFieldsExpected, unneededFieldsExpected)
+	}
+
+	// all the needed fields
+	f("join by (x) from (*)", "*", "", "*", "")
+
+	// all the needed fields, unneeded fields do not intersect with by fields
+	f("join by (x) from (*)", "*", "f1,f2", "*", "f1,f2")
+
+	// all the needed fields, unneeded fields intersect with by fields
+	f("join by (x) from (*)", "*", "f1,x", "*", "f1")
+
+	// needed fields do not intersect with by fields
+	f("join by (x) from (*)", "f1,f2", "", "f1,f2,x", "")
+
+	// needed fields intersect with by fields
+	f("join by (x, y) from (*)", "f1,x", "", "f1,x,y", "")
+}
+
+type testPipeJoinWriter struct {
+	writeBlockFunc func(workerID uint, br *blockResult)
+}
+
+func (w *testPipeJoinWriter) writeBlock(workerID uint, br *blockResult) {
+	w.writeBlockFunc(workerID, br)
+}
+
+func (w *testPipeJoinWriter) flush() error {
+	return nil
+}
@@ -13,7 +13,6 @@ import (
 	"github.com/valyala/quicktemplate"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
-	"github.com/VictoriaMetrics/VictoriaMetrics/lib/memory"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/stringsutil"
 )
 
@@ -103,14 +102,18 @@ func (ps *pipeSort) initFilterInValues(_ map[string][]string, _ getFieldValuesFu
 }
 
 func (ps *pipeSort) newPipeProcessor(workersCount int, stopCh <-chan struct{}, cancel func(), ppNext pipeProcessor) pipeProcessor {
+	return ps.newPipeProcessorWithOptions(nil, workersCount, stopCh, cancel, ppNext)
+}
+
+func (ps *pipeSort) newPipeProcessorWithOptions(qo *queryOptions, workersCount int, stopCh <-chan struct{}, cancel func(), ppNext pipeProcessor) pipeProcessor {
 	if ps.limit > 0 {
-		return newPipeTopkProcessor(ps, workersCount, stopCh, cancel, ppNext)
+		return newPipeTopkProcessor(ps, qo, workersCount, stopCh, cancel, ppNext)
 	}
-	return newPipeSortProcessor(ps, workersCount, stopCh, cancel, ppNext)
+	return newPipeSortProcessor(ps, qo, workersCount, stopCh, cancel, ppNext)
 }
 
-func newPipeSortProcessor(ps *pipeSort, workersCount int, stopCh <-chan struct{}, cancel func(), ppNext pipeProcessor) pipeProcessor {
-	maxStateSize := int64(float64(memory.Allowed()) * 0.2)
+func newPipeSortProcessor(ps *pipeSort, qo *queryOptions, workersCount int, stopCh <-chan struct{}, cancel func(), ppNext pipeProcessor) pipeProcessor {
+	maxStateSize := qo.getMaxStateSize(0.2)
 
 	shards := make([]pipeSortProcessorShard, workersCount)
 	for i := range shards {
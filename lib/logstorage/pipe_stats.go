@@ -9,7 +9,6 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
-	"github.com/VictoriaMetrics/VictoriaMetrics/lib/memory"
 )
 
 // pipeStats processes '| stats ...' queries.
@@ -155,7 +154,11 @@ func (ps *pipeStats) initFilterInValues(cache map[string][]string, getFieldValue
 const stateSizeBudgetChunk = 1 << 20
 
 func (ps *pipeStats) newPipeProcessor(workersCount int, stopCh <-chan struct{}, cancel func(), ppNext pipeProcessor) pipeProcessor {
-	maxStateSize := int64(float64(memory.Allowed()) * 0.3)
+	return ps.newPipeProcessorWithOptions(nil, workersCount, stopCh, cancel, ppNext)
+}
+
+func (ps *pipeStats) newPipeProcessorWithOptions(qo *queryOptions, workersCount int, stopCh <-chan struct{}, cancel func(), ppNext pipeProcessor) pipeProcessor {
+	maxStateSize := qo.getMaxStateSize(0.3)
 
 	shards := make([]pipeStatsProcessorShard, workersCount)
 	for i := range shards {
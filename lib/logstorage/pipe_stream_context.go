@@ -11,7 +11,6 @@ import (
 	"unsafe"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
-	"github.com/VictoriaMetrics/VictoriaMetrics/lib/memory"
 )
 
 // pipeStreamContext processes '| stream_context ...' queries.
@@ -63,7 +62,11 @@ func (pc *pipeStreamContext) initFilterInValues(_ map[string][]string, _ getFiel
 }
 
 func (pc *pipeStreamContext) newPipeProcessor(workersCount int, stopCh <-chan struct{}, cancel func(), ppNext pipeProcessor) pipeProcessor {
-	maxStateSize := int64(float64(memory.Allowed()) * 0.2)
+	return pc.newPipeProcessorWithOptions(nil, workersCount, stopCh, cancel, ppNext)
+}
+
+func (pc *pipeStreamContext) newPipeProcessorWithOptions(qo *queryOptions, workersCount int, stopCh <-chan struct{}, cancel func(), ppNext pipeProcessor) pipeProcessor {
+	maxStateSize := qo.getMaxStateSize(0.2)
 
 	shards := make([]pipeStreamContextProcessorShard, workersCount)
 	for i := range shards {
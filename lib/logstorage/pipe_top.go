@@ -11,7 +11,6 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
-	"github.com/VictoriaMetrics/VictoriaMetrics/lib/memory"
 )
 
 // pipeTopDefaultLimit is the default number of entries pipeTop returns.
@@ -73,7 +72,11 @@ func (pt *pipeTop) initFilterInValues(_ map[string][]string, _ getFieldValuesFun
 }
 
 func (pt *pipeTop) newPipeProcessor(workersCount int, stopCh <-chan struct{}, cancel func(), ppNext pipeProcessor) pipeProcessor {
-	maxStateSize := int64(float64(memory.Allowed()) * 0.2)
+	return pt.newPipeProcessorWithOptions(nil, workersCount, stopCh, cancel, ppNext)
+}
+
+func (pt *pipeTop) newPipeProcessorWithOptions(qo *queryOptions, workersCount int, stopCh <-chan struct{}, cancel func(), ppNext pipeProcessor) pipeProcessor {
+	maxStateSize := qo.getMaxStateSize(0.2)
 
 	shards := make([]pipeTopProcessorShard, workersCount)
 	for i := range shards {
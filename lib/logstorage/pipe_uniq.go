@@ -10,7 +10,6 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
-	"github.com/VictoriaMetrics/VictoriaMetrics/lib/memory"
 )
 
 // pipeUniq processes '| uniq ...' queries.
@@ -68,7 +67,11 @@ func (pu *pipeUniq) initFilterInValues(_ map[string][]string, _ getFieldValuesFu
 }
 
 func (pu *pipeUniq) newPipeProcessor(workersCount int, stopCh <-chan struct{}, cancel func(), ppNext pipeProcessor) pipeProcessor {
-	maxStateSize := int64(float64(memory.Allowed()) * 0.2)
+	return pu.newPipeProcessorWithOptions(nil, workersCount, stopCh, cancel, ppNext)
+}
+
+func (pu *pipeUniq) newPipeProcessorWithOptions(qo *queryOptions, workersCount int, stopCh <-chan struct{}, cancel func(), ppNext pipeProcessor) pipeProcessor {
+	maxStateSize := qo.getMaxStateSize(0.2)
 
 	shards := make([]pipeUniqProcessorShard, workersCount)
 	for i := range shards {
@@ -0,0 +1,162 @@
+package logstorage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/cgroup"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/memory"
+)
+
+// queryOptions contains per-query options set via `options(...)` prefix in the query.
+//
+// See https://docs.victoriametrics.com/victorialogs/logsql/#query-options
+type queryOptions struct {
+	// concurrency is the number of concurrent workers used for query execution.
+	//
+	// The default number of workers is used if concurrency is zero.
+	concurrency uint64
+
+	// maxMemory is the maximum memory in bytes, which can be used by the state of every pipe in the query.
+	//
+	// The default memory limits are used if maxMemory is zero.
+	maxMemory uint64
+
+	// ignoreGlobalLimits allows concurrency and maxMemory to exceed the default limits.
+	//
+	// The values are still clamped to hard limits - see getConcurrency and getMaxStateSize.
+	ignoreGlobalLimits bool
+
+	// args contains string representations of the options in the order they were specified in the query.
+	args []string
+}
+
+func (qo *queryOptions) String() string {
+	return "options(" + strings.Join(qo.args, ", ") + ")"
+}
+
+// maxConcurrencyPerCPU is the maximum number of concurrent workers per available CPU core, which can be set via ignore_global_limits option.
+//
+// Bigger number of workers doesn't improve query performance, while it may exhaust server resources.
+const maxConcurrencyPerCPU = 4
+
+// getConcurrency returns the number of concurrent workers to use for the query execution.
+func (qo *queryOptions) getConcurrency() int {
+	n := cgroup.AvailableCPUs()
+	if qo == nil || qo.concurrency == 0 {
+		return n
+	}
+	if qo.concurrency < uint64(n) {
+		return int(qo.concurrency)
+	}
+	if qo.ignoreGlobalLimits {
+		// Clamp the concurrency to the hard limit, so a single query cannot overload the server.
+		return int(min(qo.concurrency, uint64(n*maxConcurrencyPerCPU)))
+	}
+	return n
+}
+
+// getMaxStateSize returns the maximum state size in bytes for the pipe.
+//
+// defaultFraction is the default fraction of the allowed memory, which can be used by the pipe state.
+func (qo *queryOptions) getMaxStateSize(defaultFraction float64) int64 {
+	allowed := int64(memory.Allowed())
+	maxStateSize := int64(float64(allowed) * defaultFraction)
+	if qo == nil || qo.maxMemory == 0 {
+		return maxStateSize
+	}
+	if qo.maxMemory < uint64(maxStateSize) {
+		return int64(qo.maxMemory)
+	}
+	if qo.ignoreGlobalLimits {
+		// Clamp the state size to the allowed memory, so a single query cannot exceed it.
+		return int64(min(qo.maxMemory, uint64(allowed)))
+	}
+	return maxStateSize
+}
+
+// pipeWithQueryOptions must be implemented by pipes, which depend on queryOptions.
+type pipeWithQueryOptions interface {
+	// newPipeProcessorWithOptions must return new pipeProcessor, which respects the given qo.
+	//
+	// qo may be nil. In this case the default options must be used.
+	// See pipe.newPipeProcessor for the description of other args.
+	newPipeProcessorWithOptions(qo *queryOptions, workersCount int, stopCh <-chan struct{}, cancel func(), ppNext pipeProcessor) pipeProcessor
+}
+
+func newPipeProcessorWithOptions(p pipe, qo *queryOptions, workersCount int, stopCh <-chan struct{}, cancel func(), ppNext pipeProcessor) pipeProcessor {
+	if pq, ok := p.(pipeWithQueryOptions); ok {
+		return pq.newPipeProcessorWithOptions(qo, workersCount, stopCh, cancel, ppNext)
+	}
+	return p.newPipeProcessor(workersCount, stopCh, cancel, ppNext)
+}
+
+func parseQueryOptions(lex *lexer) (*queryOptions, error) {
+	if !lex.isKeyword("options") {
+		return nil, fmt.Errorf("unexpected token: %q; want %q", lex.token, "options")
+	}
+	lex.nextToken()
+	if !lex.isKeyword("(") {
+		return nil, fmt.Errorf("missing '(' after 'options'")
+	}
+
+	var qo queryOptions
+	seen := make(map[string]struct{})
+	for {
+		lex.nextToken()
+		if lex.isKeyword(")") {
+			lex.nextToken()
+			return &qo, nil
+		}
+
+		name := strings.ToLower(lex.token)
+		if _, ok := seen[name]; ok {
+			return nil, fmt.Errorf("duplicate option %q", name)
+		}
+		seen[name] = struct{}{}
+
+		lex.nextToken()
+		if !lex.isKeyword("=") {
+			return nil, fmt.Errorf("missing '=' after option %q", name)
+		}
+		lex.nextToken()
+		value, err := getCompoundToken(lex)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read value for option %q: %w", name, err)
+		}
+
+		switch name {
+		case "concurrency":
+			n, err := strconv.ParseUint(value, 10, 64)
+			if err != nil || n == 0 {
+				return nil, fmt.Errorf("cannot parse concurrency=%q; it must be positive integer", value)
+			}
+			qo.concurrency = n
+		case "max_memory":
+			n, ok := tryParseBytes(value)
+			if !ok || n <= 0 {
+				return nil, fmt.Errorf("cannot parse max_memory=%q; it must be positive size in bytes, such as 512MiB", value)
+			}
+			qo.maxMemory = uint64(n)
+		case "ignore_global_limits":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse ignore_global_limits=%q; it must be true or false", value)
+			}
+			qo.ignoreGlobalLimits = b
+		default:
+			return nil, fmt.Errorf("unsupported option %q; supported options: concurrency, max_memory, ignore_global_limits", name)
+		}
+		qo.args = append(qo.args, name+"="+value)
+
+		switch {
+		case lex.isKeyword(")"):
+			lex.nextToken()
+			return &qo, nil
+		case lex.isKeyword(","):
+		default:
+			return nil, fmt.Errorf("unexpected token after option %q: %q; want ',' or ')'", name, lex.token)
+		}
+	}
+}
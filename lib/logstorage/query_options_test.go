@@ -0,0 +1,66 @@
+package logstorage
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/cgroup"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/memory"
+)
+
+func TestQueryOptionsGetConcurrency(t *testing.T) {
+	f := func(qStr string, concurrencyExpected int) {
+		t.Helper()
+
+		q, err := ParseQuery(qStr)
+		if err != nil {
+			t.Fatalf("cannot parse [%s]: %s", qStr, err)
+		}
+		concurrency := q.opts.getConcurrency()
+		if concurrency != concurrencyExpected {
+			t.Fatalf("unexpected concurrency for [%s]; got %d; want %d", qStr, concurrency, concurrencyExpected)
+		}
+	}
+
+	cpus := cgroup.AvailableCPUs()
+
+	f(`*`, cpus)
+	f(`options() *`, cpus)
+	f(`options(max_memory=1MB) *`, cpus)
+	f(`options(concurrency=1) *`, 1)
+	f(`options(concurrency=100000) *`, cpus)
+	f(`options(concurrency=100000, ignore_global_limits=false) *`, cpus)
+	f(`options(concurrency=100000, ignore_global_limits=true) *`, cpus*maxConcurrencyPerCPU)
+
+	// the concurrency above the default limit is allowed up to the hard limit
+	qStr := fmt.Sprintf(`options(concurrency=%d, ignore_global_limits=true) *`, cpus+1)
+	f(qStr, cpus+1)
+}
+
+func TestQueryOptionsGetMaxStateSize(t *testing.T) {
+	f := func(qStr string, maxStateSizeExpected int64) {
+		t.Helper()
+
+		q, err := ParseQuery(qStr)
+		if err != nil {
+			t.Fatalf("cannot parse [%s]: %s", qStr, err)
+		}
+		maxStateSize := q.opts.getMaxStateSize(0.2)
+		if maxStateSize != maxStateSizeExpected {
+			t.Fatalf("unexpected maxStateSize for [%s]; got %d; want %d", qStr, maxStateSize, maxStateSizeExpected)
+		}
+	}
+
+	maxStateSizeDefault := int64(float64(memory.Allowed()) * 0.2)
+
+	f(`*`, maxStateSizeDefault)
+	f(`options() *`, maxStateSizeDefault)
+	f(`options(concurrency=2) *`, maxStateSizeDefault)
+	f(`options(max_memory=1MiB) *`, 1024*1024)
+	f(`options(max_memory=1000TiB) *`, maxStateSizeDefault)
+	f(`options(max_memory=1000TiB, ignore_global_limits=true) *`, int64(memory.Allowed()))
+
+	// max_memory above the default limit is allowed up to the allowed memory
+	qStr := fmt.Sprintf(`options(max_memory=%d, ignore_global_limits=true) *`, maxStateSizeDefault+1)
+	f(qStr, maxStateSizeDefault+1)
+}
@@ -0,0 +1,202 @@
+package logstorage
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+	"gopkg.in/yaml.v2"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding"
+)
+
+// SampleRateFieldName is the name of the field, which is added to log entries kept by Sampler.
+//
+// It contains the sampling rate, i.e. the number of ingested log entries represented by the kept log entry.
+// Use `stats sum(_sample_rate)` instead of `stats count()` for estimating the number of ingested log entries.
+const SampleRateFieldName = "_sample_rate"
+
+// SamplingRule is a rule for sampling logs at ingestion.
+//
+// Exactly one of KeepEvery or Probability must be set.
+type SamplingRule struct {
+	// Stream is an optional stream filter for log entries to sample. For example, {app="nginx"}.
+	//
+	// All the log entries match the rule if Stream is empty.
+	Stream string `yaml:"stream,omitempty"`
+
+	// KeepEvery instructs keeping 1 of every KeepEvery log entries matching the rule per each log stream.
+	KeepEvery int `yaml:"keep_every,omitempty"`
+
+	// Probability is the probability for keeping the log entry matching the rule. It must be in the range (0..1].
+	Probability float64 `yaml:"probability,omitempty"`
+}
+
+// Sampler samples the ingested log entries according to the sampling rules.
+//
+// Sampler must be created via ParseSamplingRules.
+type Sampler struct {
+	rules []*samplingRule
+}
+
+type samplingRule struct {
+	sf *StreamFilter
+
+	keepEvery   uint64
+	probability float64
+
+	// sampleRate is the value for SampleRateFieldName field.
+	sampleRate string
+
+	// countersLock protects counters.
+	countersLock sync.Mutex
+
+	// counters contains the number of log entries matching the rule with non-zero keepEvery per each log stream.
+	//
+	// The key is the hash of the log stream fields.
+	counters map[uint64]uint64
+}
+
+// maxSamplingRuleStreams is the maximum number of log streams tracked per each sampling rule with keep_every.
+//
+// The counters are reset when this limit is reached in order to limit memory usage for high number of log streams.
+const maxSamplingRuleStreams = 100_000
+
+// ParseSamplingRules parses sampling rules in YAML format from data.
+//
+// The first matching rule is applied to every log entry. Log entries without matching rules are kept as is.
+func ParseSamplingRules(data []byte) (*Sampler, error) {
+	var srs []SamplingRule
+	if err := yaml.UnmarshalStrict(data, &srs); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal sampling rules: %w", err)
+	}
+	rules := make([]*samplingRule, 0, len(srs))
+	for i := range srs {
+		r, err := newSamplingRule(&srs[i])
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse sampling rule #%d: %w", i+1, err)
+		}
+		rules = append(rules, r)
+	}
+	s := &Sampler{
+		rules: rules,
+	}
+	return s, nil
+}
+
+func newSamplingRule(sr *SamplingRule) (*samplingRule, error) {
+	var sf *StreamFilter
+	if sr.Stream != "" {
+		lex := newLexer(sr.Stream)
+		f, err := parseStreamFilter(lex)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse stream filter %q: %w", sr.Stream, err)
+		}
+		if !lex.isEnd() {
+			return nil, fmt.Errorf("unexpected tail %q after stream filter %q", lex.s, sr.Stream)
+		}
+		sf = f
+	}
+
+	switch {
+	case sr.KeepEvery != 0 && sr.Probability != 0:
+		return nil, fmt.Errorf("keep_every and probability cannot be set simultaneously")
+	case sr.KeepEvery != 0:
+		if sr.KeepEvery < 1 {
+			return nil, fmt.Errorf("keep_every must be positive; got %d", sr.KeepEvery)
+		}
+		r := &samplingRule{
+			sf:         sf,
+			keepEvery:  uint64(sr.KeepEvery),
+			sampleRate: strconv.Itoa(sr.KeepEvery),
+			counters:   make(map[uint64]uint64),
+		}
+		return r, nil
+	case sr.Probability != 0:
+		if sr.Probability < 0 || sr.Probability > 1 {
+			return nil, fmt.Errorf("probability must be in the range (0..1]; got %v", sr.Probability)
+		}
+		r := &samplingRule{
+			sf:          sf,
+			probability: sr.Probability,
+			sampleRate:  strconv.FormatFloat(1/sr.Probability, 'g', -1, 64),
+		}
+		return r, nil
+	default:
+		return nil, fmt.Errorf("missing keep_every or probability")
+	}
+}
+
+// Sample applies sampling rules from s to the log entry with the given fields.
+//
+// streamFields must contain names of log stream fields for the log entry.
+//
+// It returns false if the log entry must be dropped. Otherwise it returns the value for SampleRateFieldName field,
+// which must be added to the log entry. The returned value is empty if the log entry doesn't match sampling rules.
+func (s *Sampler) Sample(fields []Field, streamFields []string) (string, bool) {
+	if len(s.rules) == 0 {
+		return "", true
+	}
+
+	sn := getStreamName()
+	defer putStreamName(sn)
+
+	for _, name := range streamFields {
+		for i := range fields {
+			if fields[i].Name == name {
+				sn.tags = append(sn.tags, fields[i])
+				break
+			}
+		}
+	}
+
+	for _, r := range s.rules {
+		if r.sf != nil && !sn.matchStreamFilter(r.sf) {
+			continue
+		}
+		if !r.keep(sn) {
+			return "", false
+		}
+		return r.sampleRate, true
+	}
+	return "", true
+}
+
+func (r *samplingRule) keep(sn *streamName) bool {
+	if r.keepEvery > 0 {
+		h := sn.hash()
+
+		r.countersLock.Lock()
+		n, ok := r.counters[h]
+		if !ok && len(r.counters) >= maxSamplingRuleStreams {
+			clear(r.counters)
+		}
+		r.counters[h] = n + 1
+		r.countersLock.Unlock()
+
+		return n%r.keepEvery == 0
+	}
+	return r.probability >= 1 || rand.Float64() < r.probability
+}
+
+// hash returns the hash of sn tags, which doesn't depend on the order of tags.
+//
+// sn tags are sorted by name after the call.
+func (sn *streamName) hash() uint64 {
+	sort.Slice(sn.tags, func(i, j int) bool {
+		return sn.tags[i].Name < sn.tags[j].Name
+	})
+
+	bb := bbPool.Get()
+	for _, tag := range sn.tags {
+		bb.B = encoding.MarshalBytes(bb.B, bytesutil.ToUnsafeBytes(tag.Name))
+		bb.B = encoding.MarshalBytes(bb.B, bytesutil.ToUnsafeBytes(tag.Value))
+	}
+	h := xxhash.Sum64(bb.B)
+	bbPool.Put(bb)
+	return h
+}
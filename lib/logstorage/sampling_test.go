@@ -0,0 +1,177 @@
+package logstorage
+
+import (
+	"testing"
+)
+
+func TestParseSamplingRulesSuccess(t *testing.T) {
+	f := func(data string, rulesExpected int) {
+		t.Helper()
+
+		s, err := ParseSamplingRules([]byte(data))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if len(s.rules) != rulesExpected {
+			t.Fatalf("unexpected number of rules; got %d; want %d", len(s.rules), rulesExpected)
+		}
+	}
+
+	f(``, 0)
+	f(`
+- keep_every: 10
+`, 1)
+	f(`
+- stream: '{app="nginx"}'
+  keep_every: 10
+- stream: '{app=~"debug.+" or env="dev"}'
+  probability: 0.01
+- probability: 1
+`, 3)
+}
+
+func TestParseSamplingRulesFailure(t *testing.T) {
+	f := func(data string) {
+		t.Helper()
+
+		if _, err := ParseSamplingRules([]byte(data)); err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	// invalid yaml
+	f(`foo`)
+
+	// unknown field
+	f(`
+- keep_every: 10
+  foo: bar
+`)
+
+	// missing keep_every and probability
+	f(`
+- stream: '{app="nginx"}'
+`)
+
+	// both keep_every and probability
+	f(`
+- keep_every: 10
+  probability: 0.5
+`)
+
+	// invalid keep_every
+	f(`
+- keep_every: -1
+`)
+
+	// invalid probability
+	f(`
+- probability: 1.5
+`)
+	f(`
+- probability: -0.5
+`)
+
+	// invalid stream filter
+	f(`
+- stream: 'app="nginx"'
+  keep_every: 2
+`)
+	f(`
+- stream: '{app="nginx"} foo'
+  keep_every: 2
+`)
+	f(`
+- stream: '{app=~"("}'
+  keep_every: 2
+`)
+}
+
+func TestSamplerSample(t *testing.T) {
+	s, err := ParseSamplingRules([]byte(`
+- stream: '{app="nginx"}'
+  keep_every: 3
+- stream: '{app="debug"}'
+  probability: 0.25
+- stream: '{app="api"}'
+  probability: 1
+`))
+	if err != nil {
+		t.Fatalf("cannot parse sampling rules: %s", err)
+	}
+
+	f := func(fields []Field, streamFields []string, n, keptExpected int, sampleRateExpected string) {
+		t.Helper()
+
+		kept := 0
+		for i := 0; i < n; i++ {
+			sampleRate, keep := s.Sample(fields, streamFields)
+			if !keep {
+				continue
+			}
+			kept++
+			if sampleRate != sampleRateExpected {
+				t.Fatalf("unexpected sample rate; got %q; want %q", sampleRate, sampleRateExpected)
+			}
+		}
+		if keptExpected >= 0 && kept != keptExpected {
+			t.Fatalf("unexpected number of kept log entries; got %d; want %d", kept, keptExpected)
+		}
+	}
+
+	nginxFields := []Field{
+		{Name: "_msg", Value: "foo"},
+		{Name: "app", Value: "nginx"},
+	}
+
+	// keep every 3rd log entry
+	f(nginxFields, []string{"app"}, 9, 3, "3")
+
+	// app isn't a stream field, so the log entry doesn't match any rule
+	f(nginxFields, nil, 9, 9, "")
+	f(nginxFields, []string{"host"}, 9, 9, "")
+
+	// probability sampling
+	f([]Field{{Name: "app", Value: "debug"}}, []string{"app"}, 1000, -1, "4")
+
+	// probability 1 keeps all the log entries
+	f([]Field{{Name: "app", Value: "api"}, {Name: "host", Value: "h1"}}, []string{"host", "app"}, 10, 10, "1")
+
+	// non-matching stream
+	f([]Field{{Name: "app", Value: "other"}}, []string{"app"}, 10, 10, "")
+}
+
+func TestSamplerSampleKeepEveryPerStream(t *testing.T) {
+	s, err := ParseSamplingRules([]byte(`
+- stream: '{app="nginx"}'
+  keep_every: 2
+`))
+	if err != nil {
+		t.Fatalf("cannot parse sampling rules: %s", err)
+	}
+
+	f := func(fields []Field, streamFields []string, keepExpected bool) {
+		t.Helper()
+
+		_, keep := s.Sample(fields, streamFields)
+		if keep != keepExpected {
+			t.Fatalf("unexpected keep for %v; got %v; want %v", fields, keep, keepExpected)
+		}
+	}
+
+	h1 := []Field{{Name: "app", Value: "nginx"}, {Name: "host", Value: "h1"}}
+	h2 := []Field{{Name: "app", Value: "nginx"}, {Name: "host", Value: "h2"}}
+
+	// Interleaved log entries from distinct streams must be sampled independently,
+	// so the stream with every other entry isn't dropped entirely.
+	f(h1, []string{"app", "host"}, true)
+	f(h2, []string{"app", "host"}, true)
+	f(h1, []string{"app", "host"}, false)
+	f(h2, []string{"app", "host"}, false)
+	f(h1, []string{"app", "host"}, true)
+	f(h2, []string{"app", "host"}, true)
+
+	// The order of stream fields doesn't change the stream
+	f(h1, []string{"host", "app"}, false)
+	f(h2, []string{"host", "app"}, false)
+}
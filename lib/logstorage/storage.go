@@ -3,6 +3,7 @@ package logstorage
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -62,6 +63,11 @@ type StorageConfig struct {
 	// and enters read-only mode.
 	MinFreeDiskSpaceBytes int64
 
+	// RetentionMinFreeDiskSpaceBytes is an optional minimum free disk space at storage path.
+	//
+	// The oldest per-day partitions are automatically dropped if the free disk space falls below this limit.
+	RetentionMinFreeDiskSpaceBytes int64
+
 	// LogNewStreams indicates whether to log newly created log streams.
 	//
 	// This can be useful for debugging of high cardinality issues.
@@ -72,6 +78,16 @@ type StorageConfig struct {
 	//
 	// This can be useful for debugging of data ingestion.
 	LogIngestedRows bool
+
+	// GeoIPDatabasePaths is an optional list of paths to databases in MaxMind DB format.
+	//
+	// The databases are used by geoip pipe. See https://docs.victoriametrics.com/victorialogs/logsql/#geoip-pipe
+	GeoIPDatabasePaths []string
+
+	// IndexedFields is an optional list of fields to index for faster `field:=value` lookups.
+	//
+	// See https://docs.victoriametrics.com/victorialogs/#indexed-fields
+	IndexedFields []string
 }
 
 // Storage is the storage for log entries.
@@ -101,12 +117,29 @@ type Storage struct {
 	// minFreeDiskSpaceBytes is the minimum free disk space at path after which the storage stops accepting new data
 	minFreeDiskSpaceBytes uint64
 
+	// retentionMinFreeDiskSpaceBytes is an optional minimum free disk space at path.
+	//
+	// The oldest per-day partitions are automatically dropped if the free disk space falls below this limit.
+	retentionMinFreeDiskSpaceBytes uint64
+
+	// pendingDropPartitions contains partitions dropped by dropOldestPartition, which aren't deleted from disk yet,
+	// since they are in use by concurrently executed queries.
+	//
+	// It is accessed only by dropOldestPartition.
+	pendingDropPartitions []*partitionWrapper
+
 	// logNewStreams instructs to log new streams if it is set to true
 	logNewStreams bool
 
 	// logIngestedRows instructs to log all the ingested log entries if it is set to true
 	logIngestedRows bool
 
+	// geoIPDBs contains databases for geoip pipe
+	geoIPDBs []*geoIPDB
+
+	// indexedFields contains sorted list of fields to index in newly created partitions.
+	indexedFields []string
+
 	// flockF is a file, which makes sure that the Storage is opened by a single process
 	flockF *os.File
 
@@ -148,6 +181,12 @@ type Storage struct {
 	//
 	// It reduces the load on persistent storage during querying by _stream:{...} filter.
 	filterStreamCache *workingsetcache.Cache
+
+	// indexedFieldsCache caches (partition, streamID, field) entries seen during data ingestion for indexedFields.
+	//
+	// It reduces the load on persistent storage during data ingestion by skipping
+	// registration of already registered indexed fields.
+	indexedFieldsCache *workingsetcache.Cache
 }
 
 type partitionWrapper struct {
@@ -158,6 +197,12 @@ type partitionWrapper struct {
 	// The flag, which is set when the partition must be deleted after refCount reaches zero.
 	mustDrop atomic.Bool
 
+	// The flag, which is set after the partition is deleted from disk.
+	isDeleted atomic.Bool
+
+	// dropSizeBytes is the on-disk size of the partition at the time it was dropped by dropOldestPartition.
+	dropSizeBytes uint64
+
 	// day is the day for the partition in the unix timestamp divided by the number of seconds in the day.
 	day int64
 
@@ -196,6 +241,7 @@ func (ptw *partitionWrapper) decRef() {
 	// Delete partition if needed.
 	if deletePath != "" {
 		mustDeletePartition(deletePath)
+		ptw.isDeleted.Store(true)
 	}
 }
 
@@ -242,6 +288,20 @@ func MustOpenStorage(path string, cfg *StorageConfig) *Storage {
 		minFreeDiskSpaceBytes = uint64(cfg.MinFreeDiskSpaceBytes)
 	}
 
+	var retentionMinFreeDiskSpaceBytes uint64
+	if cfg.RetentionMinFreeDiskSpaceBytes >= 0 {
+		retentionMinFreeDiskSpaceBytes = uint64(cfg.RetentionMinFreeDiskSpaceBytes)
+	}
+
+	var geoIPDBs []*geoIPDB
+	for _, geoIPPath := range cfg.GeoIPDatabasePaths {
+		db, err := openGeoIPDB(geoIPPath)
+		if err != nil {
+			logger.Panicf("FATAL: cannot open geoip database: %s", err)
+		}
+		geoIPDBs = append(geoIPDBs, db)
+	}
+
 	if !fs.IsPathExist(path) {
 		mustCreateStorage(path)
 	}
@@ -257,21 +317,31 @@ func MustOpenStorage(path string, cfg *StorageConfig) *Storage {
 
 	filterStreamCache := workingsetcache.New(mem / 10)
 
+	indexedFieldsCache := workingsetcache.New(mem / 32)
+
+	indexedFields := append([]string{}, cfg.IndexedFields...)
+	sort.Strings(indexedFields)
+	indexedFields = slices.Compact(indexedFields)
+
 	s := &Storage{
-		path:                   path,
-		retention:              retention,
-		maxDiskSpaceUsageBytes: cfg.MaxDiskSpaceUsageBytes,
-		flushInterval:          flushInterval,
-		futureRetention:        futureRetention,
-		minFreeDiskSpaceBytes:  minFreeDiskSpaceBytes,
-		logNewStreams:          cfg.LogNewStreams,
-		logIngestedRows:        cfg.LogIngestedRows,
-		flockF:                 flockF,
-		stopCh:                 make(chan struct{}),
-
-		streamIDCache:     streamIDCache,
-		streamTagsCache:   streamTagsCache,
-		filterStreamCache: filterStreamCache,
+		path:                           path,
+		retention:                      retention,
+		maxDiskSpaceUsageBytes:         cfg.MaxDiskSpaceUsageBytes,
+		flushInterval:                  flushInterval,
+		futureRetention:                futureRetention,
+		minFreeDiskSpaceBytes:          minFreeDiskSpaceBytes,
+		retentionMinFreeDiskSpaceBytes: retentionMinFreeDiskSpaceBytes,
+		logNewStreams:                  cfg.LogNewStreams,
+		logIngestedRows:                cfg.LogIngestedRows,
+		geoIPDBs:                       geoIPDBs,
+		indexedFields:                  indexedFields,
+		flockF:                         flockF,
+		stopCh:                         make(chan struct{}),
+
+		streamIDCache:      streamIDCache,
+		streamTagsCache:    streamTagsCache,
+		filterStreamCache:  filterStreamCache,
+		indexedFieldsCache: indexedFieldsCache,
 	}
 
 	partitionsPath := filepath.Join(path, partitionsDirname)
@@ -318,6 +388,7 @@ func MustOpenStorage(path string, cfg *StorageConfig) *Storage {
 	s.partitions = ptws
 	s.runRetentionWatcher()
 	s.runMaxDiskSpaceUsageWatcher()
+	s.runMinFreeDiskSpaceWatcher()
 	return s
 }
 
@@ -342,6 +413,17 @@ func (s *Storage) runMaxDiskSpaceUsageWatcher() {
 	}()
 }
 
+func (s *Storage) runMinFreeDiskSpaceWatcher() {
+	if s.retentionMinFreeDiskSpaceBytes == 0 {
+		return
+	}
+	s.wg.Add(1)
+	go func() {
+		s.watchMinFreeDiskSpace()
+		s.wg.Done()
+	}()
+}
+
 func (s *Storage) watchRetention() {
 	d := timeutil.AddJitterToDuration(time.Hour)
 	ticker := time.NewTicker(d)
@@ -440,6 +522,73 @@ func (s *Storage) watchMaxDiskSpaceUsage() {
 	}
 }
 
+func (s *Storage) watchMinFreeDiskSpace() {
+	d := timeutil.AddJitterToDuration(10 * time.Second)
+	ticker := time.NewTicker(d)
+	defer ticker.Stop()
+	for {
+		freeSpace := fs.MustGetFreeSpace(s.path)
+		if freeSpace < s.retentionMinFreeDiskSpaceBytes {
+			s.dropOldestPartition(s.retentionMinFreeDiskSpaceBytes - freeSpace)
+		}
+
+		select {
+		case <-s.stopCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dropOldestPartition drops the oldest per-day partition if bytesToFree exceeds the size of previously dropped partitions,
+// which aren't deleted from disk yet.
+//
+// At most one partition is dropped per call, since the free disk space is updated only after the dropped partition
+// is deleted from disk, and this may be delayed by concurrently executed queries.
+//
+// The last two per-day partitions are never dropped, so logs could be queried for one day time range.
+func (s *Storage) dropOldestPartition(bytesToFree uint64) {
+	var pendingBytes uint64
+	pending := s.pendingDropPartitions[:0]
+	for _, ptw := range s.pendingDropPartitions {
+		if ptw.isDeleted.Load() {
+			continue
+		}
+		pendingBytes += ptw.dropSizeBytes
+		pending = append(pending, ptw)
+	}
+	clear(s.pendingDropPartitions[len(pending):])
+	s.pendingDropPartitions = pending
+
+	if bytesToFree <= pendingBytes {
+		// Wait until the previously dropped partitions are deleted from disk.
+		return
+	}
+
+	s.partitionsLock.Lock()
+	ptws := s.partitions
+	if len(ptws) <= 2 {
+		s.partitionsLock.Unlock()
+		return
+	}
+	ptw := ptws[0]
+	ptws[0] = nil
+	s.partitions = ptws[1:]
+	if ptw == s.ptwHot {
+		s.ptwHot = nil
+	}
+	var ps PartitionStats
+	ptw.pt.updateStats(&ps)
+	ptw.dropSizeBytes = ps.IndexdbSizeBytes + ps.CompressedSmallPartSize + ps.CompressedBigPartSize
+	s.partitionsLock.Unlock()
+
+	logger.Infof("the partition %s is scheduled to be deleted because the free disk space at %s is below -retention.minFreeDiskSpaceBytes=%d",
+		ptw.pt.path, s.path, s.retentionMinFreeDiskSpaceBytes)
+	s.pendingDropPartitions = append(s.pendingDropPartitions, ptw)
+	ptw.mustDrop.Store(true)
+	ptw.decRef()
+}
+
 func (s *Storage) getMinAllowedDay() int64 {
 	return time.Now().UTC().Add(-s.retention).UnixNano() / nsecPerDay
 }
@@ -480,6 +629,9 @@ func (s *Storage) MustClose() {
 	s.filterStreamCache.Stop()
 	s.filterStreamCache = nil
 
+	s.indexedFieldsCache.Stop()
+	s.indexedFieldsCache = nil
+
 	// release lock file
 	fs.MustClose(s.flockF)
 	s.flockF = nil
@@ -584,7 +736,7 @@ func (s *Storage) getPartitionForDay(day int64) *partitionWrapper {
 		// Missing partition for the given day. Create it.
 		fname := time.Unix(0, day*nsecPerDay).UTC().Format(partitionNameFormat)
 		partitionPath := filepath.Join(s.path, partitionsDirname, fname)
-		mustCreatePartition(partitionPath)
+		mustCreatePartition(partitionPath, s.indexedFields)
 
 		pt := mustOpenPartition(s, partitionPath)
 		ptw = newPartitionWrapper(pt, day)
@@ -111,6 +111,15 @@ func (s *Storage) RunQuery(ctx context.Context, tenantIDs []TenantID, q *Query,
 }
 
 func (s *Storage) runQuery(ctx context.Context, tenantIDs []TenantID, q *Query, writeBlockResultFunc func(workerID uint, br *blockResult)) error {
+	q, err := s.initJoinMaps(ctx, tenantIDs, q)
+	if err != nil {
+		return err
+	}
+	q, err = s.initGeoIPPipes(q)
+	if err != nil {
+		return err
+	}
+
 	streamIDs := q.getStreamIDs()
 	sort.Slice(streamIDs, func(i, j int) bool {
 		return streamIDs[i].less(&streamIDs[j])
@@ -130,7 +139,7 @@ func (s *Storage) runQuery(ctx context.Context, tenantIDs []TenantID, q *Query,
 		needAllColumns:      slices.Contains(neededColumnNames, "*"),
 	}
 
-	workersCount := cgroup.AvailableCPUs()
+	workersCount := q.opts.getConcurrency()
 
 	ppMain := newDefaultPipeProcessor(writeBlockResultFunc)
 	pp := ppMain
@@ -142,7 +151,7 @@ func (s *Storage) runQuery(ctx context.Context, tenantIDs []TenantID, q *Query,
 	for i := len(q.pipes) - 1; i >= 0; i-- {
 		p := q.pipes[i]
 		ctxChild, cancel := context.WithCancel(ctx)
-		pp = p.newPipeProcessor(workersCount, stopCh, cancel, pp)
+		pp = newPipeProcessorWithOptions(p, q.opts, workersCount, stopCh, cancel, pp)
 
 		pcp, ok := pp.(*pipeStreamContextProcessor)
 		if ok {
@@ -203,6 +212,7 @@ func (s *Storage) GetFieldNames(ctx context.Context, tenantIDs []TenantID, q *Qu
 	q = &Query{
 		f:     q.f,
 		pipes: pipes,
+		opts:  q.opts,
 	}
 
 	return s.runValuesWithHitsQuery(ctx, tenantIDs, q)
@@ -228,6 +238,7 @@ func (s *Storage) getFieldValuesNoHits(ctx context.Context, tenantIDs []TenantID
 	q = &Query{
 		f:     q.f,
 		pipes: pipes,
+		opts:  q.opts,
 	}
 
 	var values []string
@@ -284,6 +295,7 @@ func (s *Storage) GetFieldValues(ctx context.Context, tenantIDs []TenantID, q *Q
 	q = &Query{
 		f:     q.f,
 		pipes: pipes,
+		opts:  q.opts,
 	}
 
 	return s.runValuesWithHitsQuery(ctx, tenantIDs, q)
@@ -450,10 +462,88 @@ func (s *Storage) initFilterInValues(ctx context.Context, tenantIDs []TenantID,
 	qNew := &Query{
 		f:     fNew,
 		pipes: pipesNew,
+		opts:  q.opts,
+	}
+	return qNew, nil
+}
+
+// initJoinMaps executes the queries for the 'join' pipes in q and returns a copy of q with the initialized join maps.
+//
+// See https://docs.victoriametrics.com/victorialogs/logsql/#join-pipe
+func (s *Storage) initJoinMaps(ctx context.Context, tenantIDs []TenantID, q *Query) (*Query, error) {
+	if !slices.ContainsFunc(q.pipes, isPipeJoin) {
+		return q, nil
+	}
+
+	runQuery := func(qJoin *Query, writeBlock func(workerID uint, br *blockResult)) error {
+		qJoinNew, err := s.initFilterInValues(ctx, tenantIDs, qJoin)
+		if err != nil {
+			return err
+		}
+		return s.runQuery(ctx, tenantIDs, qJoinNew, writeBlock)
+	}
+	maxStateSize := q.opts.getMaxStateSize(0.2)
+
+	pipesNew := append([]pipe{}, q.pipes...)
+	for i, p := range pipesNew {
+		pj, ok := p.(*pipeJoin)
+		if !ok {
+			continue
+		}
+		pjNew, err := pj.initJoinMap(runQuery, maxStateSize)
+		if err != nil {
+			return nil, err
+		}
+		pipesNew[i] = pjNew
+	}
+	qNew := &Query{
+		f:     q.f,
+		pipes: pipesNew,
+		opts:  q.opts,
+	}
+	return qNew, nil
+}
+
+func isPipeJoin(p pipe) bool {
+	_, ok := p.(*pipeJoin)
+	return ok
+}
+
+// initGeoIPPipes returns a copy of q with the geoip databases set to the 'geoip' pipes.
+//
+// See https://docs.victoriametrics.com/victorialogs/logsql/#geoip-pipe
+func (s *Storage) initGeoIPPipes(q *Query) (*Query, error) {
+	if !slices.ContainsFunc(q.pipes, isPipeGeoIP) {
+		return q, nil
+	}
+	if len(s.geoIPDBs) == 0 {
+		return nil, fmt.Errorf("cannot execute 'geoip' pipe, since geoip databases aren't configured; " +
+			"see https://docs.victoriametrics.com/victorialogs/logsql/#geoip-pipe")
+	}
+
+	pipesNew := append([]pipe{}, q.pipes...)
+	for i, p := range pipesNew {
+		pg, ok := p.(*pipeGeoIP)
+		if !ok {
+			continue
+		}
+		pgNew := *pg
+		pgNew.dbs = s.geoIPDBs
+		pipesNew[i] = &pgNew
+	}
+	qNew := &Query{
+		f:     q.f,
+		pipes: pipesNew,
+		opts:  q.opts,
 	}
 	return qNew, nil
 }
 
+func isPipeGeoIP(p pipe) bool {
+	_, ok := p.(*pipeGeoIP)
+	return ok
+}
+
 func (iff *ifFilter) hasFilterInWithQuery() bool {
 	if iff == nil {
 		return false
@@ -745,6 +835,9 @@ func (pt *partition) search(sf *StreamFilter, f filter, so *genericSearchOptions
 		streamIDs = getStreamIDsForTenantIDs(so.streamIDs, tenantIDs)
 		tenantIDs = nil
 	}
+	if len(pt.indexedFields) > 0 {
+		tenantIDs, streamIDs = pt.searchStreamIDsByIndexedFields(f, so.tenantIDs, tenantIDs, streamIDs)
+	}
 	if hasStreamFilters(f) {
 		f = initStreamFilters(tenantIDs, pt.idb, f)
 	}
@@ -761,6 +854,50 @@ func (pt *partition) search(sf *StreamFilter, f filter, so *genericSearchOptions
 	return pt.ddb.search(soInternal, workCh, stopCh)
 }
 
+// searchStreamIDsByIndexedFields narrows down the search to streams containing logs, which match `field:=value` filters for pt.indexedFields in f.
+//
+// allTenantIDs must contain all the tenantIDs for the search. tenantIDs and streamIDs must contain the current search scope.
+// The updated search scope is returned.
+func (pt *partition) searchStreamIDsByIndexedFields(f filter, allTenantIDs, tenantIDs []TenantID, streamIDs []streamID) ([]TenantID, []streamID) {
+	for _, fe := range getIndexedFieldFilters(f, pt.indexedFields) {
+		fieldName := getCanonicalColumnName(fe.fieldName)
+		ids := pt.idb.searchStreamIDsByIndexedField(allTenantIDs, fieldName, fe.value)
+		if tenantIDs != nil {
+			// The search scope contains all the streams for tenantIDs
+			ids = getStreamIDsForTenantIDs(ids, tenantIDs)
+			tenantIDs = nil
+			streamIDs = ids
+		} else {
+			streamIDs = intersectStreamIDs(streamIDs, ids)
+		}
+	}
+	return tenantIDs, streamIDs
+}
+
+// getIndexedFieldFilters returns top-level `field:=value` filters from f for the given indexedFields.
+func getIndexedFieldFilters(f filter, indexedFields map[string]struct{}) []*filterExact {
+	var filters []filter
+	switch t := f.(type) {
+	case *filterAnd:
+		filters = t.filters
+	default:
+		filters = []filter{f}
+	}
+
+	var result []*filterExact
+	for _, f := range filters {
+		fe, ok := f.(*filterExact)
+		if !ok || fe.value == "" {
+			// Empty value matches logs without the given field, which are missing in the index.
+			continue
+		}
+		if _, ok := indexedFields[getCanonicalColumnName(fe.fieldName)]; ok {
+			result = append(result, fe)
+		}
+	}
+	return result
+}
+
 func intersectStreamIDs(a, b []streamID) []streamID {
 	m := make(map[streamID]struct{}, len(b))
 	for _, streamID := range b {
@@ -1122,7 +1259,7 @@ func forEachStreamField(streams []ValueWithHits, f func(f Field, hits uint64)) {
 	var fields []Field
 	for i := range streams {
 		var err error
-		fields, err = parseStreamFields(fields[:0], streams[i].Value)
+		fields, err = ParseStreamFields(fields[:0], streams[i].Value)
 		if err != nil {
 			continue
 		}
@@ -1133,7 +1270,8 @@ func forEachStreamField(streams []ValueWithHits, f func(f Field, hits uint64)) {
 	}
 }
 
-func parseStreamFields(dst []Field, s string) ([]Field, error) {
+// ParseStreamFields parses _stream value s in the form {name1="value1",...,nameN="valueN"} and appends the parsed fields to dst.
+func ParseStreamFields(dst []Field, s string) ([]Field, error) {
 	if len(s) == 0 || s[0] != '{' {
 		return dst, fmt.Errorf("missing '{' at the beginning of stream name")
 	}
@@ -1028,13 +1028,133 @@ func TestStorageSearch(t *testing.T) {
 	fs.MustRemoveAll(path)
 }
 
+func TestStorageSearchIndexedFields(t *testing.T) {
+	t.Parallel()
+
+	path := t.Name()
+
+	const streamsCount = 10
+	const rowsPerStream = 20
+
+	sc := &StorageConfig{
+		Retention:     24 * time.Hour,
+		IndexedFields: []string{"user_id", "_msg", "user_id"},
+	}
+	s := MustOpenStorage(path, sc)
+
+	// fill the storage with data. Every stream contains a single user_id value.
+	tenantID := TenantID{
+		AccountID: 1,
+		ProjectID: 2,
+	}
+	baseTimestamp := time.Now().UnixNano() - 3600*1e9
+	streamTags := []string{
+		"instance",
+	}
+	lr := GetLogRows(streamTags, nil)
+	for i := 0; i < streamsCount; i++ {
+		for j := 0; j < rowsPerStream; j++ {
+			fields := []Field{
+				{
+					Name:  "instance",
+					Value: fmt.Sprintf("host-%d", i),
+				},
+				{
+					Name:  "_msg",
+					Value: fmt.Sprintf("message %d", j),
+				},
+				{
+					Name:  "user_id",
+					Value: fmt.Sprintf("user-%d", i%5),
+				},
+			}
+			lr.MustAdd(tenantID, baseTimestamp+int64(j)*1e9, fields)
+		}
+	}
+	s.MustAddRows(lr)
+	PutLogRows(lr)
+	s.debugFlush()
+
+	getRowsCount := func(t *testing.T, qStr string) int {
+		t.Helper()
+
+		q := mustParseQuery(qStr)
+		var rowsCount atomic.Uint32
+		writeBlock := func(_ uint, timestamps []int64, _ []BlockColumn) {
+			rowsCount.Add(uint32(len(timestamps)))
+		}
+		if err := s.RunQuery(context.Background(), []TenantID{tenantID}, q, writeBlock); err != nil {
+			t.Fatalf("unexpected error returned from the query [%s]: %s", q, err)
+		}
+		return int(rowsCount.Load())
+	}
+
+	getStreamsCount := func(t *testing.T, qStr string) int {
+		t.Helper()
+
+		q := mustParseQuery(qStr)
+		ptw := s.partitions[0]
+		tenantIDs := []TenantID{tenantID}
+		tenantIDsResult, streamIDs := ptw.pt.searchStreamIDsByIndexedFields(q.f, tenantIDs, tenantIDs, nil)
+		if tenantIDsResult != nil {
+			return -1
+		}
+		return len(streamIDs)
+	}
+
+	f := func(qStr string, rowsCountExpected, streamsCountExpected int) {
+		t.Helper()
+
+		if n := getRowsCount(t, qStr); n != rowsCountExpected {
+			t.Fatalf("unexpected number of rows for [%s]; got %d; want %d", qStr, n, rowsCountExpected)
+		}
+		if n := getStreamsCount(t, qStr); n != streamsCountExpected {
+			t.Fatalf("unexpected number of streams for [%s]; got %d; want %d", qStr, n, streamsCountExpected)
+		}
+	}
+
+	// The index is used for `field:=value` filters on indexed fields.
+	f(`user_id:=user-1`, 2*rowsPerStream, 2)
+	f(`user_id:="user-3" message`, 2*rowsPerStream, 2)
+	f(`user_id:=user-1 user_id:=user-2`, 0, 0)
+	f(`user_id:=missing`, 0, 0)
+	f(`="message 3"`, streamsCount, streamsCount)
+	f(`"message 3" user_id:=user-4`, 2, 2)
+
+	// The index isn't used for other filters.
+	f(`user_id:user-1`, 2*rowsPerStream, -1)
+	f(`user_id:=user-1 or user_id:=user-2`, 4*rowsPerStream, -1)
+	f(`user_id:=""`, 0, -1)
+	f(`instance:=host-1`, rowsPerStream, -1)
+
+	// Re-open the storage without _msg in indexed fields
+	s.MustClose()
+	sc.IndexedFields = []string{"user_id"}
+	s = MustOpenStorage(path, sc)
+
+	f(`user_id:=user-1`, 2*rowsPerStream, 2)
+	f(`="message 3"`, streamsCount, -1)
+
+	// Re-open the storage with _msg in indexed fields again. The index for _msg mustn't be used,
+	// since logs could be ingested without indexing _msg before.
+	s.MustClose()
+	sc.IndexedFields = []string{"user_id", "_msg"}
+	s = MustOpenStorage(path, sc)
+
+	f(`user_id:=user-1`, 2*rowsPerStream, 2)
+	f(`="message 3"`, streamsCount, -1)
+
+	s.MustClose()
+	fs.MustRemoveAll(path)
+}
+
 func TestParseStreamFieldsSuccess(t *testing.T) {
 	t.Parallel()
 
 	f := func(s, resultExpected string) {
 		t.Helper()
 
-		labels, err := parseStreamFields(nil, s)
+		labels, err := ParseStreamFields(nil, s)
 		if err != nil {
 			t.Fatalf("unexpected error: %s", err)
 		}
@@ -104,3 +104,73 @@ func TestStorageMustAddRows(t *testing.T) {
 
 	fs.MustRemoveAll(path)
 }
+
+func TestStorageDropOldestPartitions(t *testing.T) {
+	t.Parallel()
+
+	path := t.Name()
+
+	cfg := &StorageConfig{
+		Retention: 365 * 24 * time.Hour,
+	}
+	s := MustOpenStorage(path, cfg)
+
+	// Write data to 10 different days in the past
+	lr := newTestLogRows(1, 10, 0)
+	now := time.Now().UTC().UnixNano()
+	for i := range lr.timestamps {
+		lr.timestamps[i] = now - int64(i)*nsecPerDay
+	}
+	s.MustAddRows(lr)
+	s.debugFlush()
+
+	checkPartitionsCount := func(nExpected int) {
+		t.Helper()
+
+		var sStats StorageStats
+		s.UpdateStats(&sStats)
+		if n := sStats.PartitionsCount; n != uint64(nExpected) {
+			t.Fatalf("unexpected number of partitions; got %d; want %d", n, nExpected)
+		}
+	}
+
+	// Nothing must be dropped if there is no need to free disk space
+	s.dropOldestPartition(0)
+	checkPartitionsCount(10)
+
+	// The oldest partition must be dropped
+	s.dropOldestPartition(1)
+	checkPartitionsCount(9)
+
+	// The next partition mustn't be dropped until the previously dropped partition is deleted from disk
+	s.partitionsLock.Lock()
+	ptw := s.partitions[0]
+	ptw.incRef()
+	s.partitionsLock.Unlock()
+	s.dropOldestPartition(1)
+	checkPartitionsCount(8)
+	s.dropOldestPartition(1)
+	checkPartitionsCount(8)
+	ptw.decRef()
+	s.dropOldestPartition(1)
+	checkPartitionsCount(7)
+
+	// At most one partition must be dropped per call
+	s.dropOldestPartition(1 << 62)
+	checkPartitionsCount(6)
+
+	// The last two partitions must be preserved
+	for i := 0; i < 10; i++ {
+		s.dropOldestPartition(1 << 62)
+	}
+	checkPartitionsCount(2)
+	var sStats StorageStats
+	s.UpdateStats(&sStats)
+	if n := sStats.RowsCount(); n != 2 {
+		t.Fatalf("unexpected number of rows after dropping all the partitions; got %d; want %d", n, 2)
+	}
+
+	s.MustClose()
+
+	fs.MustRemoveAll(path)
+}
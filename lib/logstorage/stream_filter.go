@@ -24,20 +24,7 @@ func (sf *StreamFilter) matchStreamName(s string) bool {
 	if !sn.parse(s) {
 		return false
 	}
-
-	for _, of := range sf.orFilters {
-		matchAndFilters := true
-		for _, tf := range of.tagFilters {
-			if !sn.match(tf) {
-				matchAndFilters = false
-				break
-			}
-		}
-		if matchAndFilters {
-			return true
-		}
-	}
-	return false
+	return sn.matchStreamFilter(sf)
 }
 
 func (sf *StreamFilter) isEmpty() bool {
@@ -266,6 +253,22 @@ func (sn *streamName) parse(s string) bool {
 	}
 }
 
+func (sn *streamName) matchStreamFilter(sf *StreamFilter) bool {
+	for _, of := range sf.orFilters {
+		matchAndFilters := true
+		for _, tf := range of.tagFilters {
+			if !sn.match(tf) {
+				matchAndFilters = false
+				break
+			}
+		}
+		if matchAndFilters {
+			return true
+		}
+	}
+	return false
+}
+
 func (sn *streamName) match(tf *streamTagFilter) bool {
 	v := sn.getTagValueByTagName(tf.tagName)
 	switch tf.op {
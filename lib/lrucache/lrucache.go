@@ -9,6 +9,7 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/cgroup"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fasttime"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/memory"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/timeutil"
 	"github.com/cespare/xxhash/v2"
 )
@@ -21,6 +22,9 @@ type Cache struct {
 
 	cleanerMustStopCh chan struct{}
 	cleanerStoppedCh  chan struct{}
+
+	// unregisterPressureCallback unregisters shrinkOnMemoryPressure callback.
+	unregisterPressureCallback func()
 }
 
 // NewCache creates new cache.
@@ -50,12 +54,14 @@ func NewCache(getMaxSizeBytes func() int) *Cache {
 		cleanerMustStopCh: make(chan struct{}),
 		cleanerStoppedCh:  make(chan struct{}),
 	}
+	c.unregisterPressureCallback = memory.RegisterPressureCallback(c.shrinkOnMemoryPressure)
 	go c.cleaner()
 	return c
 }
 
 // MustStop frees up resources occupied by c.
 func (c *Cache) MustStop() {
+	c.unregisterPressureCallback()
 	close(c.cleanerMustStopCh)
 	<-c.cleanerStoppedCh
 }
@@ -142,6 +148,14 @@ func (c *Cache) cleaner() {
 	}
 }
 
+// shrinkOnMemoryPressure removes the least recently accessed entries from c
+// until its size drops below the half of the maximum size when the memory pressure exceeds -memory.pressureThreshold.
+func (c *Cache) shrinkOnMemoryPressure() {
+	for _, shard := range c.shards {
+		shard.shrink()
+	}
+}
+
 func (c *Cache) cleanByTimeout() {
 	for _, shard := range c.shards {
 		shard.cleanByTimeout()
@@ -219,6 +233,16 @@ func (c *cache) cleanByTimeout() {
 	}
 }
 
+func (c *cache) shrink() {
+	maxSizeBytes := c.getMaxSizeBytes() / 2
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for c.SizeBytes() > maxSizeBytes && len(c.lah) > 0 {
+		c.removeLeastRecentlyAccessedItem()
+	}
+}
+
 func (c *cache) GetEntry(k string) Entry {
 	c.requests.Add(1)
 	c.mu.Lock()
@@ -45,6 +45,7 @@ func initOnce() {
 		remainingMemory = memoryLimit - allowedMemory
 		logger.Infof("limiting caches to %d bytes, leaving %d bytes to the OS according to -memory.allowedBytes=%s", allowedMemory, remainingMemory, allowedBytes.String())
 	}
+	startPressureWatcher()
 }
 
 // Allowed returns the amount of system memory allowed to use by the app.
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"flag"
+	"math"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/cgroup"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/metrics"
+)
+
+var pressureThreshold = flag.Float64("memory.pressureThreshold", 0, "The percentage of time over the last 10 seconds when the process is stalled on memory "+
+	"according to cgroup v2 memory.pressure, after which in-memory caches are proactively shrunk in order to reduce the probability of OOM kills. "+
+	"For example, -memory.pressureThreshold=10 shrinks caches when the process is stalled on memory for more than 10% of time. "+
+	"Zero value disables the check. See https://docs.kernel.org/accounting/psi.html")
+
+// pressureCheckInterval is the interval between memory pressure checks.
+const pressureCheckInterval = time.Second
+
+// pressureCallbackInterval is the minimum interval between subsequent calls of pressure callbacks while the memory pressure stays high.
+//
+// It matches the avg10 window, so caches have a chance to reduce the pressure before the next shrinking.
+const pressureCallbackInterval = 10 * time.Second
+
+var (
+	pressureCallbacksLock   sync.Mutex
+	pressureCallbacks       = make(map[uint64]func())
+	pressureCallbacksNextID uint64
+)
+
+// RegisterPressureCallback registers f, which is called when the memory pressure exceeds -memory.pressureThreshold.
+//
+// f must free up memory occupied by caches. The returned function must be called for unregistering f when it is no longer needed.
+func RegisterPressureCallback(f func()) func() {
+	pressureCallbacksLock.Lock()
+	id := pressureCallbacksNextID
+	pressureCallbacksNextID++
+	pressureCallbacks[id] = f
+	pressureCallbacksLock.Unlock()
+
+	return func() {
+		pressureCallbacksLock.Lock()
+		delete(pressureCallbacks, id)
+		pressureCallbacksLock.Unlock()
+	}
+}
+
+var lastPressure atomic.Uint64
+
+var (
+	_ = metrics.NewGauge("process_memory_pressure_percent", func() float64 {
+		return math.Float64frombits(lastPressure.Load())
+	})
+	pressureShrinks = metrics.NewCounter("vm_memory_pressure_cache_shrinks_total")
+)
+
+func startPressureWatcher() {
+	if *pressureThreshold <= 0 {
+		return
+	}
+	if _, err := cgroup.GetMemoryPressure(); err != nil {
+		logger.Warnf("ignoring -memory.pressureThreshold=%g, since memory pressure cannot be determined: %s", *pressureThreshold, err)
+		return
+	}
+	go pressureWatcher()
+}
+
+func pressureWatcher() {
+	t := time.NewTicker(pressureCheckInterval)
+	defer t.Stop()
+	var lastCallbacksCall time.Time
+	for range t.C {
+		p, err := cgroup.GetMemoryPressure()
+		if err != nil {
+			continue
+		}
+		lastPressure.Store(math.Float64bits(p))
+		if p <= *pressureThreshold {
+			continue
+		}
+		if time.Since(lastCallbacksCall) < pressureCallbackInterval {
+			continue
+		}
+		lastCallbacksCall = time.Now()
+		logger.Warnf("shrinking in-memory caches, since memory pressure %.2f%% exceeds -memory.pressureThreshold=%g", p, *pressureThreshold)
+		callPressureCallbacks()
+	}
+}
+
+func callPressureCallbacks() {
+	pressureCallbacksLock.Lock()
+	callbacks := make([]func(), 0, len(pressureCallbacks))
+	for _, f := range pressureCallbacks {
+		callbacks = append(callbacks, f)
+	}
+	pressureCallbacksLock.Unlock()
+
+	for _, f := range callbacks {
+		f()
+	}
+	pressureShrinks.Inc()
+}
@@ -56,12 +56,14 @@ func DialMaybeSRV(ctx context.Context, network, addr string) (net.Conn, error) {
 		n := rand.Intn(len(addrs))
 		addr = fmt.Sprintf("%s:%d", addrs[n].Target, addrs[n].Port)
 	}
-	return Dialer.DialContext(ctx, network, addr)
+	return dialTCP(ctx, network, addr)
 }
 
 // Dialer is default network dialer.
+//
+// It must be used for dialing non-TCP addresses such as unix sockets.
+// TCP addresses are dialed via DialMaybeSRV, which takes into account -tcpDialer.* command-line flags.
 var Dialer = &net.Dialer{
 	Timeout:   30 * time.Second,
 	KeepAlive: 30 * time.Second,
-	DualStack: TCP6Enabled(),
 }
@@ -0,0 +1,106 @@
+package netutil
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+)
+
+var (
+	tcpDialerFallbackDelay = flag.Duration("tcpDialer.fallbackDelay", 300*time.Millisecond, "The delay before trying IPv4 addresses if IPv6 addresses "+
+		"of the dialed host do not respond, and vice versa (aka Happy Eyeballs, see RFC 6555). It is used only if -enableTCP6 is set. "+
+		"Negative value disables the concurrent dialing, so the addresses are tried sequentially")
+	tcpDialerSourceAddr = flag.String("tcpDialer.sourceAddr", "", "Optional source IP address or network interface name for outgoing TCP connections. "+
+		"If network interface name is set, then its IPv6 address is used if -enableTCP6 is set, otherwise its IPv4 address is used. "+
+		"Note that only the hosts with the same address family as the source address can be dialed")
+)
+
+var (
+	tcpDialer     *net.Dialer
+	tcpDialerOnce sync.Once
+)
+
+func getTCPDialer() *net.Dialer {
+	tcpDialerOnce.Do(initTCPDialer)
+	return tcpDialer
+}
+
+func initTCPDialer() {
+	d, err := newTCPDialer(*tcpDialerFallbackDelay, *tcpDialerSourceAddr, TCP6Enabled())
+	if err != nil {
+		logger.Fatalf("cannot use -tcpDialer.sourceAddr=%q: %s", *tcpDialerSourceAddr, err)
+	}
+	tcpDialer = d
+}
+
+// newTCPDialer returns a dialer for the given -tcpDialer.* and -enableTCP6 command-line flag values.
+//
+// fallbackDelay is applied only if enableTCP6 is set, since only IPv4 addresses are dialed otherwise.
+func newTCPDialer(fallbackDelay time.Duration, sourceAddr string, enableTCP6 bool) (*net.Dialer, error) {
+	d := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if enableTCP6 {
+		d.FallbackDelay = fallbackDelay
+	}
+	if sourceAddr != "" {
+		addr, err := getSourceAddr(sourceAddr, enableTCP6)
+		if err != nil {
+			return nil, err
+		}
+		d.LocalAddr = addr
+	}
+	return d, nil
+}
+
+// dialTCP dials the given TCP addr via the dialer configured with -tcpDialer.* command-line flags.
+func dialTCP(ctx context.Context, network, addr string) (net.Conn, error) {
+	return getTCPDialer().DialContext(ctx, network, addr)
+}
+
+// getSourceAddr returns TCP address for the given source IP address or network interface name s.
+//
+// If s is network interface name, then its IPv6 address is preferred if preferIPv6 is set, otherwise its IPv4 address is preferred.
+func getSourceAddr(s string, preferIPv6 bool) (*net.TCPAddr, error) {
+	if ip := net.ParseIP(s); ip != nil {
+		return &net.TCPAddr{
+			IP: ip,
+		}, nil
+	}
+	iface, err := net.InterfaceByName(s)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %q as IP address or network interface name: %w", s, err)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("cannot obtain addresses for network interface %q: %w", s, err)
+	}
+	var ipFallback net.IP
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		ip := ipNet.IP
+		if isIPv6 := ip.To4() == nil; isIPv6 == preferIPv6 {
+			return &net.TCPAddr{
+				IP: ip,
+			}, nil
+		}
+		if ipFallback == nil {
+			ipFallback = ip
+		}
+	}
+	if ipFallback == nil {
+		return nil, fmt.Errorf("network interface %q has no suitable IP addresses", s)
+	}
+	return &net.TCPAddr{
+		IP: ipFallback,
+	}, nil
+}
@@ -0,0 +1,102 @@
+package netutil
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestNewTCPDialer(t *testing.T) {
+	f := func(fallbackDelay time.Duration, sourceAddr string, enableTCP6 bool, fallbackDelayExpected time.Duration, localAddrExpected string) {
+		t.Helper()
+
+		d, err := newTCPDialer(fallbackDelay, sourceAddr, enableTCP6)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if d.FallbackDelay != fallbackDelayExpected {
+			t.Fatalf("unexpected FallbackDelay; got %s; want %s", d.FallbackDelay, fallbackDelayExpected)
+		}
+		localAddr := ""
+		if d.LocalAddr != nil {
+			localAddr = d.LocalAddr.String()
+		}
+		if localAddr != localAddrExpected {
+			t.Fatalf("unexpected LocalAddr; got %q; want %q", localAddr, localAddrExpected)
+		}
+	}
+
+	// fallbackDelay is ignored without enableTCP6
+	f(time.Second, "", false, 0, "")
+	f(-1, "", false, 0, "")
+
+	// fallbackDelay is applied with enableTCP6
+	f(time.Second, "", true, time.Second, "")
+	f(-1, "", true, -1, "")
+
+	// sourceAddr
+	f(time.Second, "127.0.0.1", false, 0, "127.0.0.1:0")
+	f(time.Second, "::1", true, time.Second, "[::1]:0")
+
+	if _, err := newTCPDialer(time.Second, "1.2.3", false); err == nil {
+		t.Fatalf("expecting non-nil error for invalid sourceAddr")
+	}
+}
+
+func TestGetSourceAddr_Success(t *testing.T) {
+	f := func(s string, preferIPv6 bool, ipExpected string) {
+		t.Helper()
+
+		addr, err := getSourceAddr(s, preferIPv6)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if addr.IP.String() != ipExpected {
+			t.Fatalf("unexpected IP for %q; got %s; want %s", s, addr.IP, ipExpected)
+		}
+		if addr.Port != 0 {
+			t.Fatalf("unexpected non-zero port for %q: %d", s, addr.Port)
+		}
+	}
+
+	f("127.0.0.1", false, "127.0.0.1")
+	f("127.0.0.1", true, "127.0.0.1")
+	f("::1", false, "::1")
+	f("2001:db8::1", true, "2001:db8::1")
+}
+
+func TestGetSourceAddr_Interface(t *testing.T) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		t.Skipf("cannot obtain network interfaces: %s", err)
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback == 0 {
+			continue
+		}
+		addr, err := getSourceAddr(iface.Name, false)
+		if err != nil {
+			t.Fatalf("unexpected error for loopback interface %q: %s", iface.Name, err)
+		}
+		if !addr.IP.IsLoopback() {
+			t.Fatalf("expecting loopback IP for interface %q; got %s", iface.Name, addr.IP)
+		}
+		return
+	}
+	t.Skipf("missing loopback network interface")
+}
+
+func TestGetSourceAddr_Failure(t *testing.T) {
+	f := func(s string) {
+		t.Helper()
+
+		addr, err := getSourceAddr(s, false)
+		if err == nil {
+			t.Fatalf("expecting non-nil error for %q; got %s", s, addr)
+		}
+	}
+
+	f("")
+	f("1.2.3")
+	f("non-existing-interface")
+}
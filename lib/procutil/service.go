@@ -0,0 +1,96 @@
+package procutil
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// This file contains OS-independent parts of Windows service support, so they could be tested on any OS.
+// See service_windows.go for the integration with the Windows service control manager.
+
+// stopWaitHint is the estimated time needed for the graceful shutdown of the service.
+const stopWaitHint = time.Minute
+
+// parseServiceArgs returns the service name and the extra arguments from args passed by the service control manager.
+//
+// The first arg contains the service name, while the rest of args are passed via `sc start <name> <args>`.
+func parseServiceArgs(args []string) (string, []string) {
+	if len(args) == 0 {
+		return "", nil
+	}
+	return args[0], args[1:]
+}
+
+// getServiceWorkingDir returns the working directory for the service started from the executable at exePath.
+//
+// Windows services are started with C:\Windows\System32 working directory, so relative paths such as the default
+// -storageDataPath must be resolved against the directory with the executable in the same way as when it is started manually.
+func getServiceWorkingDir(exePath string) (string, error) {
+	if exePath == "" {
+		return "", fmt.Errorf("missing path to the executable")
+	}
+	if !filepath.IsAbs(exePath) {
+		return "", fmt.Errorf("path to the executable must be absolute; got %q", exePath)
+	}
+	return filepath.Dir(exePath), nil
+}
+
+// serviceCommand is a command sent by the service control manager.
+type serviceCommand int
+
+const (
+	serviceCommandUnknown serviceCommand = iota
+	serviceCommandInterrogate
+	serviceCommandStop
+	serviceCommandShutdown
+)
+
+// serviceState is the state of the service reported to the service control manager.
+type serviceState int
+
+const (
+	serviceStateRunning serviceState = iota
+	serviceStateStopPending
+)
+
+// serviceController converts commands from the service control manager into service state changes.
+type serviceController struct {
+	stopCh   chan struct{}
+	stopOnce sync.Once
+
+	mu    sync.Mutex
+	state serviceState
+}
+
+func newServiceController() *serviceController {
+	return &serviceController{
+		stopCh: make(chan struct{}),
+		state:  serviceStateRunning,
+	}
+}
+
+// handleCommand processes cmd and returns the service state, which must be reported to the service control manager.
+//
+// It returns false if cmd isn't supported and nothing must be reported.
+// Stop and shutdown commands close sc.stopCh, so the graceful shutdown could be started.
+func (sc *serviceController) handleCommand(cmd serviceCommand) (serviceState, bool) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	switch cmd {
+	case serviceCommandInterrogate:
+		return sc.state, true
+	case serviceCommandStop, serviceCommandShutdown:
+		// The graceful shutdown is performed by the caller of WaitForSigterm.
+		// The service control manager marks the service as stopped when the process exits.
+		sc.state = serviceStateStopPending
+		sc.stopOnce.Do(func() {
+			close(sc.stopCh)
+		})
+		return sc.state, true
+	default:
+		return sc.state, false
+	}
+}
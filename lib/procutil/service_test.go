@@ -0,0 +1,96 @@
+package procutil
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseServiceArgs(t *testing.T) {
+	f := func(args []string, nameExpected string, extraArgsExpected []string) {
+		t.Helper()
+
+		name, extraArgs := parseServiceArgs(args)
+		if name != nameExpected {
+			t.Fatalf("unexpected service name; got %q; want %q", name, nameExpected)
+		}
+		if len(extraArgs) != len(extraArgsExpected) || (len(extraArgs) > 0 && !reflect.DeepEqual(extraArgs, extraArgsExpected)) {
+			t.Fatalf("unexpected extra args; got %q; want %q", extraArgs, extraArgsExpected)
+		}
+	}
+
+	f(nil, "", nil)
+	f([]string{"victoria-metrics"}, "victoria-metrics", nil)
+	f([]string{"victoria-metrics", "-retentionPeriod=1y", "-httpListenAddr=:8429"}, "victoria-metrics", []string{"-retentionPeriod=1y", "-httpListenAddr=:8429"})
+}
+
+func TestGetServiceWorkingDir(t *testing.T) {
+	f := func(exePath, dirExpected string) {
+		t.Helper()
+
+		dir, err := getServiceWorkingDir(exePath)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if dir != dirExpected {
+			t.Fatalf("unexpected working dir; got %q; want %q", dir, dirExpected)
+		}
+	}
+
+	root, err := filepath.Abs(string(filepath.Separator))
+	if err != nil {
+		t.Fatalf("cannot obtain root dir: %s", err)
+	}
+	f(filepath.Join(root, "victoria-metrics-prod"), root)
+	f(filepath.Join(root, "Program Files", "VictoriaMetrics", "victoria-metrics-prod.exe"), filepath.Join(root, "Program Files", "VictoriaMetrics"))
+
+	fError := func(exePath string) {
+		t.Helper()
+
+		if _, err := getServiceWorkingDir(exePath); err == nil {
+			t.Fatalf("expecting non-nil error for %q", exePath)
+		}
+	}
+
+	fError("")
+	fError("victoria-metrics-prod")
+	fError(filepath.Join("bin", "victoria-metrics-prod"))
+}
+
+func TestServiceControllerHandleCommand(t *testing.T) {
+	sc := newServiceController()
+
+	f := func(cmd serviceCommand, stateExpected serviceState, okExpected, stoppedExpected bool) {
+		t.Helper()
+
+		state, ok := sc.handleCommand(cmd)
+		if ok != okExpected {
+			t.Fatalf("unexpected ok for command %d; got %v; want %v", cmd, ok, okExpected)
+		}
+		if state != stateExpected {
+			t.Fatalf("unexpected state for command %d; got %d; want %d", cmd, state, stateExpected)
+		}
+		stopped := false
+		select {
+		case <-sc.stopCh:
+			stopped = true
+		default:
+		}
+		if stopped != stoppedExpected {
+			t.Fatalf("unexpected stopped state after command %d; got %v; want %v", cmd, stopped, stoppedExpected)
+		}
+	}
+
+	f(serviceCommandUnknown, serviceStateRunning, false, false)
+	f(serviceCommandInterrogate, serviceStateRunning, true, false)
+	f(serviceCommandStop, serviceStateStopPending, true, true)
+	f(serviceCommandInterrogate, serviceStateStopPending, true, true)
+
+	// the repeated stop request mustn't panic on closing stopCh
+	f(serviceCommandShutdown, serviceStateStopPending, true, true)
+	f(serviceCommandStop, serviceStateStopPending, true, true)
+
+	// shutdown must stop the service in the same way as stop
+	sc = newServiceController()
+	f(serviceCommandShutdown, serviceStateStopPending, true, true)
+}
@@ -0,0 +1,78 @@
+//go:build windows
+
+package procutil
+
+import (
+	"os"
+	"time"
+
+	"golang.org/x/sys/windows/svc"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+)
+
+// serviceCtrl tracks the state of the process running as Windows service.
+//
+// serviceCtrl.stopCh is closed when the Windows service control manager requests stopping the service.
+var serviceCtrl = newServiceController()
+
+func init() {
+	isService, err := svc.IsWindowsService()
+	if err != nil || !isService {
+		return
+	}
+
+	if exe, err := os.Executable(); err == nil {
+		if dir, err := getServiceWorkingDir(exe); err == nil {
+			_ = os.Chdir(dir)
+		}
+	}
+
+	// The service control dispatcher must be started in 30 seconds after the process start,
+	// so start it before the app initialization, which may take a while.
+	go func() {
+		if err := svc.Run("", &windowsService{}); err != nil {
+			logger.Fatalf("FATAL: cannot run Windows service: %s", err)
+		}
+	}()
+}
+
+type windowsService struct{}
+
+// Execute implements svc.Handler interface.
+func (ws *windowsService) Execute(args []string, r <-chan svc.ChangeRequest, changes chan<- svc.Status) (bool, uint32) {
+	name, extraArgs := parseServiceArgs(args)
+	if len(extraArgs) > 0 {
+		logger.Warnf("ignoring start args %q for Windows service %q; pass command-line flags via the service binary path instead", extraArgs, name)
+	}
+
+	const accepts = svc.AcceptStop | svc.AcceptShutdown
+	changes <- svc.Status{State: svc.StartPending}
+	changes <- svc.Status{State: svc.Running, Accepts: accepts}
+	for c := range r {
+		state, ok := serviceCtrl.handleCommand(getServiceCommand(c.Cmd))
+		if !ok {
+			continue
+		}
+		switch state {
+		case serviceStateStopPending:
+			changes <- svc.Status{State: svc.StopPending, WaitHint: uint32(stopWaitHint / time.Millisecond)}
+		default:
+			changes <- svc.Status{State: svc.Running, Accepts: accepts}
+		}
+	}
+	return false, 0
+}
+
+func getServiceCommand(cmd svc.Cmd) serviceCommand {
+	switch cmd {
+	case svc.Interrogate:
+		return serviceCommandInterrogate
+	case svc.Stop:
+		return serviceCommandStop
+	case svc.Shutdown:
+		return serviceCommandShutdown
+	default:
+		return serviceCommandUnknown
+	}
+}
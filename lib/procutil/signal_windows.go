@@ -14,11 +14,17 @@ import (
 // Returns the caught signal.
 //
 // Windows dont have SIGHUP syscall.
+//
+// If the process runs as Windows service, then SIGTERM is returned when the service is stopped.
 func WaitForSigterm() os.Signal {
 	ch := make(chan os.Signal, 1)
 	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
-	sig := <-ch
-	return sig
+	select {
+	case sig := <-ch:
+		return sig
+	case <-serviceCtrl.stopCh:
+		return syscall.SIGTERM
+	}
 }
 
 type sigHUPNotifier struct {
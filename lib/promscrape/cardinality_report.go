@@ -0,0 +1,180 @@
+package promscrape
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/stringsutil"
+)
+
+// cardinalityWindowDuration is the duration of a single window for tracking target cardinality stats.
+//
+// Two consecutive windows are tracked per target, so the stats cover up to the last hour.
+const cardinalityWindowDuration = 30 * time.Minute
+
+// cardinalityWindow contains cardinality stats for a target collected during cardinalityWindowDuration.
+type cardinalityWindow struct {
+	// startTime is the start of the window in milliseconds.
+	startTime int64
+
+	scrapes           int
+	maxSeries         int
+	seriesAdded       int
+	maxScrapeDuration int64
+	scrapeDurationSum int64
+}
+
+func (cw *cardinalityWindow) update(seriesScraped, seriesAdded int, scrapeDuration int64) {
+	cw.scrapes++
+	if seriesScraped > cw.maxSeries {
+		cw.maxSeries = seriesScraped
+	}
+	cw.seriesAdded += seriesAdded
+	if scrapeDuration > cw.maxScrapeDuration {
+		cw.maxScrapeDuration = scrapeDuration
+	}
+	cw.scrapeDurationSum += scrapeDuration
+}
+
+func (cw *cardinalityWindow) merge(src *cardinalityWindow) {
+	cw.scrapes += src.scrapes
+	if src.maxSeries > cw.maxSeries {
+		cw.maxSeries = src.maxSeries
+	}
+	cw.seriesAdded += src.seriesAdded
+	if src.maxScrapeDuration > cw.maxScrapeDuration {
+		cw.maxScrapeDuration = src.maxScrapeDuration
+	}
+	cw.scrapeDurationSum += src.scrapeDurationSum
+}
+
+// cardinalityStats tracks cardinality stats for a target over the last hour.
+type cardinalityStats struct {
+	curr cardinalityWindow
+	prev cardinalityWindow
+}
+
+// update registers the scrape performed at scrapeTime (in milliseconds) in cs.
+func (cs *cardinalityStats) update(scrapeTime int64, seriesScraped, seriesAdded int, scrapeDuration int64) {
+	cs.rotate(scrapeTime)
+	cs.curr.update(seriesScraped, seriesAdded, scrapeDuration)
+}
+
+func (cs *cardinalityStats) rotate(currentTime int64) {
+	windowMsecs := cardinalityWindowDuration.Milliseconds()
+	d := currentTime - cs.curr.startTime
+	if d < windowMsecs {
+		return
+	}
+	if d < 2*windowMsecs {
+		cs.prev = cs.curr
+	} else {
+		cs.prev = cardinalityWindow{}
+	}
+	cs.curr = cardinalityWindow{
+		startTime: currentTime,
+	}
+}
+
+// get returns cardinality stats over the last hour at currentTime (in milliseconds).
+func (cs *cardinalityStats) get(currentTime int64) cardinalityWindow {
+	csCopy := *cs
+	csCopy.rotate(currentTime)
+	cw := csCopy.curr
+	cw.merge(&csCopy.prev)
+	return cw
+}
+
+// WriteTargetsCardinalityReport writes /api/v1/targets/cardinality response to w.
+//
+// The response contains the top targets by the number of scraped series, by new series churn
+// and by scrape duration over the last hour. The number of returned targets per list is limited by topN query arg.
+func WriteTargetsCardinalityReport(w http.ResponseWriter, r *http.Request) {
+	topN := 10
+	if s := r.FormValue("topN"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n <= 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"status":"error","errorType":"bad_data","error":%s}`, stringsutil.JSONString(fmt.Sprintf("cannot parse topN=%q: it must be a positive integer", s)))
+			return
+		}
+		topN = n
+	}
+	w.Header().Set("Content-Type", "application/json")
+	currentTime := time.Now().UnixMilli()
+	tsm := tsmGlobal
+	tsm.mu.Lock()
+	tcs := make([]targetCardinality, 0, len(tsm.m))
+	for sw, ts := range tsm.m {
+		tcs = append(tcs, targetCardinality{
+			sw: sw,
+			cw: ts.cardinality.get(currentTime),
+		})
+	}
+	tsm.mu.Unlock()
+	writeTargetsCardinalityReport(w, tcs, topN)
+}
+
+type targetCardinality struct {
+	sw *scrapeWork
+	cw cardinalityWindow
+}
+
+func writeTargetsCardinalityReport(w io.Writer, tcs []targetCardinality, topN int) {
+	fmt.Fprintf(w, `{"status":"success","data":{"topSeries":`)
+	writeTopTargetsCardinality(w, tcs, topN, func(cw *cardinalityWindow) int64 {
+		return int64(cw.maxSeries)
+	})
+	fmt.Fprintf(w, `,"topSeriesChurn":`)
+	writeTopTargetsCardinality(w, tcs, topN, func(cw *cardinalityWindow) int64 {
+		return int64(cw.seriesAdded)
+	})
+	fmt.Fprintf(w, `,"topScrapeDuration":`)
+	writeTopTargetsCardinality(w, tcs, topN, func(cw *cardinalityWindow) int64 {
+		return cw.maxScrapeDuration
+	})
+	fmt.Fprintf(w, `}}`)
+}
+
+func writeTopTargetsCardinality(w io.Writer, tcs []targetCardinality, topN int, getValue func(cw *cardinalityWindow) int64) {
+	sort.SliceStable(tcs, func(i, j int) bool {
+		vi, vj := getValue(&tcs[i].cw), getValue(&tcs[j].cw)
+		if vi != vj {
+			return vi > vj
+		}
+		return tcs[i].sw.Config.ScrapeURL < tcs[j].sw.Config.ScrapeURL
+	})
+	fmt.Fprintf(w, `[`)
+	n := 0
+	for i := range tcs {
+		tc := &tcs[i]
+		if n >= topN {
+			break
+		}
+		if tc.cw.scrapes == 0 {
+			// Skip targets without scrapes during the last hour.
+			continue
+		}
+		if n > 0 {
+			fmt.Fprintf(w, `,`)
+		}
+		n++
+		cw := &tc.cw
+		fmt.Fprintf(w, `{"scrapePool":%s`, stringsutil.JSONString(tc.sw.Config.Job()))
+		fmt.Fprintf(w, `,"scrapeUrl":%s`, stringsutil.JSONString(tc.sw.Config.ScrapeURL))
+		fmt.Fprintf(w, `,"labels":`)
+		writeLabelsJSON(w, tc.sw.Config.Labels)
+		fmt.Fprintf(w, `,"scrapes":%d`, cw.scrapes)
+		fmt.Fprintf(w, `,"maxSeries":%d`, cw.maxSeries)
+		fmt.Fprintf(w, `,"seriesAdded":%d`, cw.seriesAdded)
+		fmt.Fprintf(w, `,"seriesLimit":%d`, tc.sw.Config.SeriesLimit)
+		fmt.Fprintf(w, `,"maxScrapeDuration":%g`, (time.Millisecond * time.Duration(cw.maxScrapeDuration)).Seconds())
+		avgScrapeDuration := float64(cw.scrapeDurationSum) / float64(cw.scrapes) / 1e3
+		fmt.Fprintf(w, `,"avgScrapeDuration":%g}`, avgScrapeDuration)
+	}
+	fmt.Fprintf(w, `]`)
+}
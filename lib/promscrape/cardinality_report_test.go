@@ -0,0 +1,93 @@
+package promscrape
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promutils"
+)
+
+func TestCardinalityStats(t *testing.T) {
+	const windowMsecs = int64(cardinalityWindowDuration / 1e6)
+
+	f := func(cs *cardinalityStats, currentTime int64, scrapesExpected, maxSeriesExpected, seriesAddedExpected int, maxScrapeDurationExpected int64) {
+		t.Helper()
+
+		cw := cs.get(currentTime)
+		if cw.scrapes != scrapesExpected {
+			t.Fatalf("unexpected scrapes; got %d; want %d", cw.scrapes, scrapesExpected)
+		}
+		if cw.maxSeries != maxSeriesExpected {
+			t.Fatalf("unexpected maxSeries; got %d; want %d", cw.maxSeries, maxSeriesExpected)
+		}
+		if cw.seriesAdded != seriesAddedExpected {
+			t.Fatalf("unexpected seriesAdded; got %d; want %d", cw.seriesAdded, seriesAddedExpected)
+		}
+		if cw.maxScrapeDuration != maxScrapeDurationExpected {
+			t.Fatalf("unexpected maxScrapeDuration; got %d; want %d", cw.maxScrapeDuration, maxScrapeDurationExpected)
+		}
+	}
+
+	startTime := int64(1_700_000_000_000)
+	var cs cardinalityStats
+	f(&cs, startTime, 0, 0, 0, 0)
+
+	cs.update(startTime, 100, 100, 50)
+	cs.update(startTime+1000, 120, 20, 30)
+	f(&cs, startTime+2000, 2, 120, 120, 50)
+
+	// The previous window must be taken into account.
+	cs.update(startTime+windowMsecs, 80, 5, 10)
+	f(&cs, startTime+windowMsecs+1000, 3, 120, 125, 50)
+
+	// The stats older than an hour must be dropped.
+	f(&cs, startTime+2*windowMsecs+1000, 1, 80, 5, 10)
+	f(&cs, startTime+3*windowMsecs+1000, 0, 0, 0, 0)
+
+	// The stats must be reset after a long pause between scrapes.
+	cs.update(startTime+10*windowMsecs, 10, 1, 1)
+	f(&cs, startTime+10*windowMsecs, 1, 10, 1, 1)
+}
+
+func TestWriteTargetsCardinalityReport(t *testing.T) {
+	newTargetCardinality := func(scrapeURL string, scrapes, maxSeries, seriesAdded int, maxScrapeDuration int64) targetCardinality {
+		return targetCardinality{
+			sw: &scrapeWork{
+				Config: &ScrapeWork{
+					ScrapeURL:       scrapeURL,
+					Labels:          promutils.NewLabelsFromMap(map[string]string{"job": "foo"}),
+					jobNameOriginal: "foo",
+				},
+			},
+			cw: cardinalityWindow{
+				scrapes:           scrapes,
+				maxSeries:         maxSeries,
+				seriesAdded:       seriesAdded,
+				maxScrapeDuration: maxScrapeDuration,
+				scrapeDurationSum: maxScrapeDuration * int64(scrapes),
+			},
+		}
+	}
+	tcs := []targetCardinality{
+		newTargetCardinality("http://a/metrics", 1, 100, 10, 500),
+		newTargetCardinality("http://b/metrics", 2, 200, 5, 1500),
+		newTargetCardinality("http://c/metrics", 3, 50, 30, 100),
+		newTargetCardinality("http://d/metrics", 0, 0, 0, 0),
+	}
+	var bb bytes.Buffer
+	writeTargetsCardinalityReport(&bb, tcs, 2)
+	result := bb.String()
+	resultExpected := `{"status":"success","data":{"topSeries":[` +
+		`{"scrapePool":"foo","scrapeUrl":"http://b/metrics","labels":{"job":"foo"},"scrapes":2,"maxSeries":200,"seriesAdded":5,"seriesLimit":0,"maxScrapeDuration":1.5,"avgScrapeDuration":1.5},` +
+		`{"scrapePool":"foo","scrapeUrl":"http://a/metrics","labels":{"job":"foo"},"scrapes":1,"maxSeries":100,"seriesAdded":10,"seriesLimit":0,"maxScrapeDuration":0.5,"avgScrapeDuration":0.5}` +
+		`],"topSeriesChurn":[` +
+		`{"scrapePool":"foo","scrapeUrl":"http://c/metrics","labels":{"job":"foo"},"scrapes":3,"maxSeries":50,"seriesAdded":30,"seriesLimit":0,"maxScrapeDuration":0.1,"avgScrapeDuration":0.1},` +
+		`{"scrapePool":"foo","scrapeUrl":"http://a/metrics","labels":{"job":"foo"},"scrapes":1,"maxSeries":100,"seriesAdded":10,"seriesLimit":0,"maxScrapeDuration":0.5,"avgScrapeDuration":0.5}` +
+		`],"topScrapeDuration":[` +
+		`{"scrapePool":"foo","scrapeUrl":"http://b/metrics","labels":{"job":"foo"},"scrapes":2,"maxSeries":200,"seriesAdded":5,"seriesLimit":0,"maxScrapeDuration":1.5,"avgScrapeDuration":1.5},` +
+		`{"scrapePool":"foo","scrapeUrl":"http://a/metrics","labels":{"job":"foo"},"scrapes":1,"maxSeries":100,"seriesAdded":10,"seriesLimit":0,"maxScrapeDuration":0.5,"avgScrapeDuration":0.5}` +
+		`]}}`
+	if result != resultExpected {
+		t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, resultExpected)
+	}
+}
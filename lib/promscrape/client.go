@@ -2,6 +2,7 @@ package promscrape
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -15,6 +16,7 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/netutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/prometheus"
 )
 
 var (
@@ -31,11 +33,42 @@ var (
 		"It is possible to set 'stream_parse: true' individually per each 'scrape_config' section in '-promscrape.config' for fine-grained control")
 )
 
+// scrapeProtocolHeaders contains `Accept` header values for the supported `scrape_protocols` values.
+//
+// See https://prometheus.io/docs/prometheus/latest/configuration/configuration/#scrape_config
+var scrapeProtocolHeaders = map[string]string{
+	"PrometheusProto":     prometheus.ProtobufAcceptHeader,
+	"PrometheusText0.0.4": "text/plain;version=0.0.4",
+}
+
+// getAcceptHeader returns `Accept` header value for the given scrape protocols.
+func getAcceptHeader(protocols []string) string {
+	if len(protocols) == 0 {
+		// The following `Accept` header has been copied from Prometheus sources.
+		// See https://github.com/prometheus/prometheus/blob/f9d21f10ecd2a343a381044f131ea4e46381ce09/scrape/scrape.go#L532 .
+		// This is needed as a workaround for scraping stupid Java-based servers such as Spring Boot.
+		// See https://github.com/VictoriaMetrics/VictoriaMetrics/issues/608 for details.
+		// Do not bloat the `Accept` header with OpenMetrics shit, since it looks like dead standard now.
+		return "text/plain;version=0.0.4;q=1,*/*;q=0.1"
+	}
+	// Set decreasing weights for protocols in the order of their preference like Prometheus does.
+	var b []byte
+	weight := len(protocols) + 1
+	for _, protocol := range protocols {
+		b = append(b, scrapeProtocolHeaders[protocol]...)
+		b = fmt.Appendf(b, ";q=0.%d,", weight)
+		weight--
+	}
+	b = fmt.Appendf(b, "*/*;q=0.%d", weight)
+	return string(b)
+}
+
 type client struct {
 	c                       *http.Client
 	ctx                     context.Context
 	scrapeURL               string
 	scrapeTimeoutSecondsStr string
+	acceptHeader            string
 	setHeaders              func(req *http.Request) error
 	setProxyHeaders         func(req *http.Request) error
 	maxScrapeSize           int64
@@ -101,6 +134,7 @@ func newClient(ctx context.Context, sw *ScrapeWork) (*client, error) {
 		ctx:                     ctx,
 		scrapeURL:               sw.ScrapeURL,
 		scrapeTimeoutSecondsStr: fmt.Sprintf("%.3f", sw.ScrapeTimeout.Seconds()),
+		acceptHeader:            getAcceptHeader(sw.ScrapeProtocols),
 		setHeaders:              setHeaders,
 		setProxyHeaders:         setProxyHeaders,
 		maxScrapeSize:           sw.MaxScrapeSize,
@@ -109,6 +143,19 @@ func newClient(ctx context.Context, sw *ScrapeWork) (*client, error) {
 }
 
 func (c *client) ReadData(dst *bytesutil.ByteBuffer) error {
+	return c.ReadStream(func(r io.Reader) error {
+		if _, err := dst.ReadFrom(r); err != nil {
+			return fmt.Errorf("cannot read data from %s: %w", c.scrapeURL, err)
+		}
+		return nil
+	})
+}
+
+// ReadStream performs the scrape request and passes the response body to f.
+//
+// The response body is read directly from the network, so it isn't held in memory at once.
+// f must return an error if it cannot read the response body.
+func (c *client) ReadStream(f func(r io.Reader) error) error {
 	deadline := time.Now().Add(c.c.Timeout)
 	ctx, cancel := context.WithDeadline(c.ctx, deadline)
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.scrapeURL, nil)
@@ -116,12 +163,7 @@ func (c *client) ReadData(dst *bytesutil.ByteBuffer) error {
 		cancel()
 		return fmt.Errorf("cannot create request for %q: %w", c.scrapeURL, err)
 	}
-	// The following `Accept` header has been copied from Prometheus sources.
-	// See https://github.com/prometheus/prometheus/blob/f9d21f10ecd2a343a381044f131ea4e46381ce09/scrape/scrape.go#L532 .
-	// This is needed as a workaround for scraping stupid Java-based servers such as Spring Boot.
-	// See https://github.com/VictoriaMetrics/VictoriaMetrics/issues/608 for details.
-	// Do not bloat the `Accept` header with OpenMetrics shit, since it looks like dead standard now.
-	req.Header.Set("Accept", "text/plain;version=0.0.4;q=1,*/*;q=0.1")
+	req.Header.Set("Accept", c.acceptHeader)
 	// Set X-Prometheus-Scrape-Timeout-Seconds like Prometheus does, since it is used by some exporters such as PushProx.
 	// See https://github.com/VictoriaMetrics/VictoriaMetrics/issues/1179#issuecomment-813117162
 	req.Header.Set("X-Prometheus-Scrape-Timeout-Seconds", c.scrapeTimeoutSecondsStr)
@@ -154,20 +196,28 @@ func (c *client) ReadData(dst *bytesutil.ByteBuffer) error {
 	scrapesOK.Inc()
 
 	// Read the data from resp.Body
-	r := &io.LimitedReader{
-		R: resp.Body,
-		N: c.maxScrapeSize,
+	r := &countingReader{
+		r: &io.LimitedReader{
+			R: resp.Body,
+			N: c.maxScrapeSize,
+		},
+	}
+	if strings.HasPrefix(resp.Header.Get("Content-Type"), prometheus.ProtobufContentType) {
+		// Protobuf responses cannot be parsed in a streaming manner, so convert them to Prometheus text exposition format.
+		err = readProtobuf(r, f)
+	} else {
+		err = f(r)
 	}
-	_, err = dst.ReadFrom(r)
 	_ = resp.Body.Close()
 	cancel()
 	if err != nil {
-		if ue, ok := err.(*url.Error); ok && ue.Timeout() {
+		var ue *url.Error
+		if errors.As(err, &ue) && ue.Timeout() {
 			scrapesTimedout.Inc()
 		}
-		return fmt.Errorf("cannot read data from %s: %w", c.scrapeURL, err)
+		return err
 	}
-	if int64(len(dst.B)) >= c.maxScrapeSize {
+	if r.n >= c.maxScrapeSize {
 		maxScrapeSizeExceeded.Inc()
 		return fmt.Errorf("the response from %q exceeds -promscrape.maxScrapeSize or max_scrape_size in the scrape config (%d bytes). "+
 			"Possible solutions are: reduce the response size for the target, increase -promscrape.maxScrapeSize command-line flag, "+
@@ -176,9 +226,42 @@ func (c *client) ReadData(dst *bytesutil.ByteBuffer) error {
 	return nil
 }
 
+// readProtobuf reads Prometheus protobuf response from r, converts it to Prometheus text exposition format and passes it to f.
+func readProtobuf(r io.Reader, f func(r io.Reader) error) error {
+	bb := protobufBufPool.Get()
+	defer protobufBufPool.Put(bb)
+	if _, err := bb.ReadFrom(r); err != nil {
+		return fmt.Errorf("cannot read protobuf response: %w", err)
+	}
+	textBB := protobufBufPool.Get()
+	defer protobufBufPool.Put(textBB)
+	var err error
+	textBB.B, err = prometheus.AppendTextFromProtobuf(textBB.B[:0], bb.B)
+	if err != nil {
+		protobufParseErrors.Inc()
+		return fmt.Errorf("cannot parse protobuf response: %w", err)
+	}
+	return f(textBB.NewReader())
+}
+
+var protobufBufPool bytesutil.ByteBufferPool
+
+// countingReader counts the number of bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
 var (
 	maxScrapeSizeExceeded = metrics.NewCounter(`vm_promscrape_max_scrape_size_exceeded_errors_total`)
 	scrapesTimedout       = metrics.NewCounter(`vm_promscrape_scrapes_timed_out_total`)
 	scrapesOK             = metrics.NewCounter(`vm_promscrape_scrapes_total{status_code="200"}`)
 	scrapeRequests        = metrics.NewCounter(`vm_promscrape_scrape_requests_total`)
+	protobufParseErrors   = metrics.NewCounter(`vm_promscrape_protobuf_parse_errors_total`)
 )
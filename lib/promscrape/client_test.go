@@ -13,7 +13,9 @@ import (
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promauth"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/prometheus"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/proxy"
+	"github.com/VictoriaMetrics/easyproto"
 )
 
 func copyHeader(dst, src http.Header) {
@@ -187,3 +189,63 @@ func TestClientProxyReadOk(t *testing.T) {
 	// backend tls and proxy auth
 	f(true, false, nil, &promauth.BasicAuthConfig{Username: "proxy-test", Password: promauth.NewSecret("1234")})
 }
+
+func TestGetAcceptHeader(t *testing.T) {
+	f := func(protocols []string, resultExpected string) {
+		t.Helper()
+		result := getAcceptHeader(protocols)
+		if result != resultExpected {
+			t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, resultExpected)
+		}
+	}
+	f(nil, "text/plain;version=0.0.4;q=1,*/*;q=0.1")
+	f([]string{"PrometheusText0.0.4"}, "text/plain;version=0.0.4;q=0.2,*/*;q=0.1")
+	f([]string{"PrometheusProto", "PrometheusText0.0.4"}, prometheus.ProtobufAcceptHeader+";q=0.3,text/plain;version=0.0.4;q=0.2,*/*;q=0.1")
+}
+
+func TestClientReadDataProtobuf(t *testing.T) {
+	var mp easyproto.MarshalerPool
+	m := mp.Get()
+	mm := m.MessageMarshaler()
+	mm.AppendString(1, "foo")
+	mm.AppendInt32(3, 1)
+	metric := mm.AppendMessage(4)
+	lp := metric.AppendMessage(1)
+	lp.AppendString(1, "bar")
+	lp.AppendString(2, "baz")
+	metric.AppendMessage(2).AppendDouble(1, 123)
+	data := m.MarshalWithLen(nil)
+	mp.Put(m)
+
+	var acceptHeader string
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		acceptHeader = r.Header.Get("Accept")
+		w.Header().Set("Content-Type", prometheus.ProtobufAcceptHeader)
+		w.Write(data)
+	}))
+	defer backend.Close()
+
+	c, err := newClient(context.Background(), &ScrapeWork{
+		ScrapeURL:       backend.URL,
+		ScrapeTimeout:   2 * time.Second,
+		AuthConfig:      newTestAuthConfig(t, false, nil),
+		ProxyAuthConfig: newTestAuthConfig(t, false, nil),
+		MaxScrapeSize:   16000,
+		ScrapeProtocols: []string{"PrometheusProto"},
+	})
+	if err != nil {
+		t.Fatalf("failed to create client: %s", err)
+	}
+	var bb bytesutil.ByteBuffer
+	if err := c.ReadData(&bb); err != nil {
+		t.Fatalf("unexpected error at ReadData: %s", err)
+	}
+	acceptHeaderExpected := prometheus.ProtobufAcceptHeader + ";q=0.2,*/*;q=0.1"
+	if acceptHeader != acceptHeaderExpected {
+		t.Fatalf("unexpected Accept header; got %q; want %q", acceptHeader, acceptHeaderExpected)
+	}
+	resultExpected := `foo{bar="baz"} 123` + "\n"
+	if string(bb.B) != resultExpected {
+		t.Fatalf("unexpected response\ngot\n%s\nwant\n%s", bb.B, resultExpected)
+	}
+}
@@ -288,6 +288,7 @@ type ScrapeConfig struct {
 	RelabelConfigs       []promrelabel.RelabelConfig `yaml:"relabel_configs,omitempty"`
 	MetricRelabelConfigs []promrelabel.RelabelConfig `yaml:"metric_relabel_configs,omitempty"`
 	SampleLimit          int                         `yaml:"sample_limit,omitempty"`
+	ScrapeProtocols      []string                    `yaml:"scrape_protocols,omitempty"`
 
 	// This silly option is needed for compatibility with Prometheus.
 	// vmagent was supporting disable_compression option since the beginning, while Prometheus developers
@@ -905,6 +906,10 @@ func getScrapeWorkConfig(sc *ScrapeConfig, baseDir string, globalCfg *GlobalConf
 	if sc.SeriesLimit != nil {
 		seriesLimit = *sc.SeriesLimit
 	}
+	scrapeProtocols, err := getScrapeProtocols(sc.ScrapeProtocols)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse `scrape_protocols` for `job_name` %q: %w", jobName, err)
+	}
 	disableCompression := sc.DisableCompression
 	if sc.EnableCompression != nil {
 		disableCompression = !*sc.EnableCompression
@@ -929,6 +934,7 @@ func getScrapeWorkConfig(sc *ScrapeConfig, baseDir string, globalCfg *GlobalConf
 		relabelConfigs:       relabelConfigs,
 		metricRelabelConfigs: metricRelabelConfigs,
 		sampleLimit:          sc.SampleLimit,
+		scrapeProtocols:      scrapeProtocols,
 		disableCompression:   disableCompression,
 		disableKeepAlive:     sc.DisableKeepAlive,
 		streamParse:          sc.StreamParse,
@@ -940,6 +946,27 @@ func getScrapeWorkConfig(sc *ScrapeConfig, baseDir string, globalCfg *GlobalConf
 	return swc, nil
 }
 
+// getScrapeProtocols returns the supported protocols from the given `scrape_protocols` list in the order of their preference.
+//
+// OpenMetrics protocols are skipped, since the OpenMetrics format isn't requested from scrape targets.
+// This allows re-using `scrape_protocols` lists from Prometheus configs.
+func getScrapeProtocols(protocols []string) ([]string, error) {
+	var result []string
+	for _, protocol := range protocols {
+		if strings.HasPrefix(protocol, "OpenMetricsText") {
+			continue
+		}
+		if _, ok := scrapeProtocolHeaders[protocol]; !ok {
+			return nil, fmt.Errorf("unsupported protocol %q; supported protocols: PrometheusProto, PrometheusText0.0.4", protocol)
+		}
+		if slices.Contains(result, protocol) {
+			return nil, fmt.Errorf("duplicate protocol %q", protocol)
+		}
+		result = append(result, protocol)
+	}
+	return result, nil
+}
+
 type scrapeWorkConfig struct {
 	scrapeInterval       time.Duration
 	scrapeIntervalString string
@@ -960,6 +987,7 @@ type scrapeWorkConfig struct {
 	relabelConfigs       *promrelabel.ParsedConfigs
 	metricRelabelConfigs *promrelabel.ParsedConfigs
 	sampleLimit          int
+	scrapeProtocols      []string
 	disableCompression   bool
 	disableKeepAlive     bool
 	streamParse          bool
@@ -1243,6 +1271,7 @@ func (swc *scrapeWorkConfig) getScrapeWork(target string, extraLabels, metaLabel
 		RelabelConfigs:       swc.relabelConfigs,
 		MetricRelabelConfigs: swc.metricRelabelConfigs,
 		SampleLimit:          sampleLimit,
+		ScrapeProtocols:      swc.scrapeProtocols,
 		DisableCompression:   swc.disableCompression,
 		DisableKeepAlive:     swc.disableKeepAlive,
 		StreamParse:          streamParse,
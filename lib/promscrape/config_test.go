@@ -651,6 +651,24 @@ scrape_configs:
   - targets: ["foo"]
 `, []*ScrapeWork{})
 
+	// Scrape config with unsupported scrape_protocols must be skipped
+	f(`
+scrape_configs:
+- job_name: x
+  scrape_protocols: [foobar]
+  static_configs:
+  - targets: ["foo"]
+`, []*ScrapeWork{})
+
+	// Scrape config with duplicate scrape_protocols must be skipped
+	f(`
+scrape_configs:
+- job_name: x
+  scrape_protocols: [PrometheusProto, PrometheusProto]
+  static_configs:
+  - targets: ["foo"]
+`, []*ScrapeWork{})
+
 	// Scrape config with missing job_name must be skipped
 	f(`
 scrape_configs:
@@ -787,6 +805,26 @@ scrape_configs:
 		},
 	})
 	f(`
+scrape_configs:
+- job_name: foo
+  scrape_protocols: [OpenMetricsText1.0.0, PrometheusProto, PrometheusText0.0.4]
+  static_configs:
+  - targets: ["foo.bar:1234"]
+`, []*ScrapeWork{
+		{
+			ScrapeURL:       "http://foo.bar:1234/metrics",
+			ScrapeInterval:  defaultScrapeInterval,
+			ScrapeTimeout:   defaultScrapeTimeout,
+			MaxScrapeSize:   maxScrapeSize.N,
+			ScrapeProtocols: []string{"PrometheusProto", "PrometheusText0.0.4"},
+			Labels: promutils.NewLabelsFromMap(map[string]string{
+				"instance": "foo.bar:1234",
+				"job":      "foo",
+			}),
+			jobNameOriginal: "foo",
+		},
+	})
+	f(`
 global:
   external_labels:
     datacenter: foobar
@@ -457,6 +457,7 @@ func newScraper(sw *ScrapeWork, group string, pushData func(at *auth.Token, wr *
 	sc.sw.Config = sw
 	sc.sw.ScrapeGroup = group
 	sc.sw.ReadData = c.ReadData
+	sc.sw.ReadStream = c.ReadStream
 	sc.sw.PushData = pushData
 	return sc, nil
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"flag"
 	"fmt"
+	"io"
 	"math"
 	"math/bits"
 	"strings"
@@ -123,6 +124,11 @@ type ScrapeWork struct {
 	// The maximum number of metrics to scrape after relabeling.
 	SampleLimit int
 
+	// Optional `scrape_protocols` in the order of their preference.
+	//
+	// The default `Accept` header is sent to ScrapeURL if ScrapeProtocols is empty.
+	ScrapeProtocols []string
+
 	// Whether to disable response compression when querying ScrapeURL.
 	DisableCompression bool
 
@@ -168,12 +174,12 @@ func (sw *ScrapeWork) key() string {
 	key := fmt.Sprintf("JobNameOriginal=%s, ScrapeURL=%s, ScrapeInterval=%s, ScrapeTimeout=%s, HonorLabels=%v, HonorTimestamps=%v, DenyRedirects=%v, Labels=%s, "+
 		"ExternalLabels=%s, "+
 		"ProxyURL=%s, ProxyAuthConfig=%s, AuthConfig=%s, MetricRelabelConfigs=%q, "+
-		"SampleLimit=%d, DisableCompression=%v, DisableKeepAlive=%v, StreamParse=%v, "+
+		"SampleLimit=%d, ScrapeProtocols=%q, DisableCompression=%v, DisableKeepAlive=%v, StreamParse=%v, "+
 		"ScrapeAlignInterval=%s, ScrapeOffset=%s, SeriesLimit=%d, NoStaleMarkers=%v",
 		sw.jobNameOriginal, sw.ScrapeURL, sw.ScrapeInterval, sw.ScrapeTimeout, sw.HonorLabels, sw.HonorTimestamps, sw.DenyRedirects, sw.Labels.String(),
 		sw.ExternalLabels.String(),
 		sw.ProxyURL.String(), sw.ProxyAuthConfig.String(), sw.AuthConfig.String(), sw.MetricRelabelConfigs.String(),
-		sw.SampleLimit, sw.DisableCompression, sw.DisableKeepAlive, sw.StreamParse,
+		sw.SampleLimit, sw.ScrapeProtocols, sw.DisableCompression, sw.DisableKeepAlive, sw.StreamParse,
 		sw.ScrapeAlignInterval, sw.ScrapeOffset, sw.SeriesLimit, sw.NoStaleMarkers)
 	return key
 }
@@ -190,6 +196,11 @@ type scrapeWork struct {
 	// ReadData is called for reading the scrape response data into dst.
 	ReadData func(dst *bytesutil.ByteBuffer) error
 
+	// ReadStream is called for passing the scrape response body to f without reading it into memory.
+	//
+	// It is used instead of ReadData if needStreamResponse returns true.
+	ReadStream func(f func(r io.Reader) error) error
+
 	// PushData is called for pushing collected data.
 	PushData func(at *auth.Token, wr *prompbmarshal.WriteRequest)
 
@@ -417,7 +428,25 @@ func (sw *scrapeWork) getTargetResponse() ([]byte, error) {
 	return bb.B, nil
 }
 
+// needStreamResponse returns true if the scrape response must be parsed while it is read from the target.
+//
+// This is possible only in stream parsing mode if the response body isn't needed after parsing,
+// e.g. when staleness markers and series_limit are disabled for the target.
+func (sw *scrapeWork) needStreamResponse() bool {
+	if sw.ReadStream == nil {
+		return false
+	}
+	if !*streamParse && !sw.Config.StreamParse {
+		return false
+	}
+	return sw.Config.NoStaleMarkers && sw.Config.SeriesLimit <= 0
+}
+
 func (sw *scrapeWork) scrapeInternal(scrapeTimestamp, realTimestamp int64) error {
+	if sw.needStreamResponse() {
+		return sw.processResponseInStreamMode(scrapeTimestamp, realTimestamp)
+	}
+
 	body := leveledbytebufferpool.Get(sw.prevBodyLen)
 
 	// Read the scrape response into body.
@@ -524,7 +553,7 @@ func (sw *scrapeWork) processDataOneShot(scrapeTimestamp, realTimestamp int64, b
 		sw.storeLastScrape(body)
 	}
 	sw.finalizeLastScrape()
-	tsmGlobal.Update(sw, up == 1, realTimestamp, int64(scrapeDurationSeconds*1000), responseSize, samplesScraped, err)
+	tsmGlobal.Update(sw, up == 1, realTimestamp, int64(scrapeDurationSeconds*1000), responseSize, samplesScraped, samplesPostRelabeling, seriesAdded, err)
 	return err
 }
 
@@ -605,12 +634,88 @@ func (sw *scrapeWork) processDataInStreamMode(scrapeTimestamp, realTimestamp int
 		sw.storeLastScrape(body.B)
 	}
 	sw.finalizeLastScrape()
-	tsmGlobal.Update(sw, up == 1, realTimestamp, int64(scrapeDurationSeconds*1000), responseSize, samplesScraped, err)
+	tsmGlobal.Update(sw, up == 1, realTimestamp, int64(scrapeDurationSeconds*1000), responseSize, samplesScraped, samplesPostRelabeling, seriesAdded, err)
 	// Do not track active series in streaming mode, since this may need too big amounts of memory
 	// when the target exports too big number of metrics.
 	return err
 }
 
+// processResponseInStreamMode reads and processes the response from scrape target in streaming manner.
+//
+// Unlike processDataInStreamMode, it doesn't read the whole response body into memory before parsing,
+// so the memory usage doesn't depend on the response size. The scrape duration includes the time needed
+// for processing the response in this mode.
+func (sw *scrapeWork) processResponseInStreamMode(scrapeTimestamp, realTimestamp int64) error {
+	samplesScraped := 0
+	samplesPostRelabeling := 0
+	responseSize := 0
+	wc := writeRequestCtxPool.Get(sw.prevLabelsLen)
+
+	// There is no need in limiting the concurrency with processScrapedDataConcurrencyLimitCh here,
+	// since the response is parsed by a limited number of workers inside stream.Parse,
+	// while the reading of the response from the network may take long time.
+	var mu sync.Mutex
+	err := sw.ReadStream(func(r io.Reader) error {
+		cr := &countingReader{
+			r: r,
+		}
+		err := stream.Parse(cr, scrapeTimestamp, false, false, func(rows []parser.Row) error {
+			mu.Lock()
+			defer mu.Unlock()
+
+			samplesScraped += len(rows)
+			for i := range rows {
+				sw.addRowToTimeseries(wc, &rows[i], scrapeTimestamp, true)
+			}
+			samplesPostRelabeling += len(wc.writeRequest.Timeseries)
+			if sw.Config.SampleLimit > 0 && samplesPostRelabeling > sw.Config.SampleLimit {
+				wc.resetNoRows()
+				scrapesSkippedBySampleLimit.Inc()
+				return fmt.Errorf("the response from %q exceeds sample_limit=%d; "+
+					"either reduce the sample count for the target or increase sample_limit", sw.Config.ScrapeURL, sw.Config.SampleLimit)
+			}
+
+			// Push the collected rows to sw before returning from the callback, since they cannot be held
+			// after returning from the callback - this will result in data race.
+			// See https://github.com/VictoriaMetrics/VictoriaMetrics/issues/825#issuecomment-723198247
+			sw.pushData(sw.Config.AuthToken, &wc.writeRequest)
+			wc.resetNoRows()
+			return nil
+		}, sw.logError)
+		responseSize = int(cr.n)
+		return err
+	})
+
+	// Measure scrape duration.
+	endTimestamp := time.Now().UnixNano() / 1e6
+	scrapeDurationSeconds := float64(endTimestamp-realTimestamp) / 1e3
+	scrapeDuration.Update(scrapeDurationSeconds)
+	scrapeResponseSize.Update(float64(responseSize))
+
+	scrapedSamples.Update(float64(samplesScraped))
+	up := 1
+	if err != nil {
+		// Mark the scrape as failed even if it already read and pushed some samples
+		// to remote storage. This makes the logic compatible with Prometheus.
+		up = 0
+		scrapesFailed.Inc()
+	}
+	am := &autoMetrics{
+		up:                    up,
+		scrapeDurationSeconds: scrapeDurationSeconds,
+		scrapeResponseSize:    responseSize,
+		samplesScraped:        samplesScraped,
+		samplesPostRelabeling: samplesPostRelabeling,
+	}
+	sw.addAutoMetrics(am, wc, scrapeTimestamp)
+	sw.pushData(sw.Config.AuthToken, &wc.writeRequest)
+	sw.prevLabelsLen = len(wc.labels)
+	wc.reset()
+	writeRequestCtxPool.Put(wc)
+	tsmGlobal.Update(sw, up == 1, realTimestamp, int64(scrapeDurationSeconds*1000), responseSize, samplesScraped, samplesPostRelabeling, 0, err)
+	return err
+}
+
 func (sw *scrapeWork) pushData(at *auth.Token, wr *prompbmarshal.WriteRequest) {
 	startTime := time.Now()
 	sw.PushData(at, wr)
@@ -2,6 +2,7 @@ package promscrape
 
 import (
 	"fmt"
+	"io"
 	"strings"
 	"testing"
 	"time"
@@ -490,6 +491,95 @@ func TestScrapeWorkScrapeInternalSuccess(t *testing.T) {
 	`)
 }
 
+func TestScrapeWorkScrapeInternalStreamResponse(t *testing.T) {
+	f := func(data string, readErr error, cfg *ScrapeWork, dataExpected string) {
+		t.Helper()
+
+		common.StartUnmarshalWorkers()
+		defer common.StopUnmarshalWorkers()
+
+		var sw scrapeWork
+		sw.Config = cfg
+		sw.ReadData = func(_ *bytesutil.ByteBuffer) error {
+			t.Fatalf("unexpected ReadData call in stream response mode")
+			return nil
+		}
+		readStreamCalls := 0
+		sw.ReadStream = func(f func(r io.Reader) error) error {
+			readStreamCalls++
+			if err := f(strings.NewReader(data)); err != nil {
+				return err
+			}
+			return readErr
+		}
+
+		var tss []prompbmarshal.TimeSeries
+		sw.PushData = func(_ *auth.Token, wr *prompbmarshal.WriteRequest) {
+			for _, ts := range wr.Timeseries {
+				tss = append(tss, prompbmarshal.TimeSeries{
+					Labels:  append([]prompbmarshal.Label{}, ts.Labels...),
+					Samples: append([]prompbmarshal.Sample{}, ts.Samples...),
+				})
+			}
+		}
+
+		timestamp := int64(123000)
+		tsmGlobal.Register(&sw)
+		err := sw.scrapeInternal(timestamp, timestamp)
+		tsmGlobal.Unregister(&sw)
+		if readErr != nil && err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+		if readErr == nil && err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if readStreamCalls != 1 {
+			t.Fatalf("unexpected number of ReadStream calls; got %d; want %d", readStreamCalls, 1)
+		}
+		tssExpected := parseData(dataExpected)
+		if err := expectEqualTimeseries(tss, tssExpected); err != nil {
+			t.Fatalf("unexpected data pushed: %s\ngot\n%v\nwant\n%v", err, tss, tssExpected)
+		}
+	}
+
+	f(`
+		foo{bar="baz"} 34.45 3
+		abc -2
+	`, nil, &ScrapeWork{
+		ScrapeTimeout:  time.Second * 42,
+		StreamParse:    true,
+		NoStaleMarkers: true,
+	}, `
+		foo{bar="baz"} 34.45 123
+		abc -2 123
+		up 1 123
+		scrape_samples_scraped 2 123
+		scrape_response_size_bytes 36 123
+		scrape_duration_seconds 0 123
+		scrape_samples_post_metric_relabeling 2 123
+		scrape_series_added 0 123
+		scrape_timeout_seconds 42 123
+	`)
+
+	// the error after reading the response
+	f(`
+		foo 1
+	`, fmt.Errorf("the response exceeds max_scrape_size"), &ScrapeWork{
+		ScrapeTimeout:  time.Second * 42,
+		StreamParse:    true,
+		NoStaleMarkers: true,
+	}, `
+		foo 1 123
+		up 0 123
+		scrape_samples_scraped 1 123
+		scrape_response_size_bytes 10 123
+		scrape_duration_seconds 0 123
+		scrape_samples_post_metric_relabeling 1 123
+		scrape_series_added 0 123
+		scrape_timeout_seconds 42 123
+	`)
+}
+
 func TestAddRowToTimeseriesNoRelabeling(t *testing.T) {
 	f := func(row string, cfg *ScrapeWork, dataExpected string) {
 		t.Helper()
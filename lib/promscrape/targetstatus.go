@@ -179,7 +179,7 @@ func (tsm *targetStatusMap) Unregister(sw *scrapeWork) {
 	tsm.mu.Unlock()
 }
 
-func (tsm *targetStatusMap) Update(sw *scrapeWork, up bool, scrapeTime, scrapeDuration int64, scrapeResponseSize, samplesScraped int, err error) {
+func (tsm *targetStatusMap) Update(sw *scrapeWork, up bool, scrapeTime, scrapeDuration int64, scrapeResponseSize, samplesScraped, samplesPostRelabeling, seriesAdded int, err error) {
 	jobName := sw.Config.jobNameOriginal
 
 	tsm.mu.Lock()
@@ -204,6 +204,7 @@ func (tsm *targetStatusMap) Update(sw *scrapeWork, up bool, scrapeTime, scrapeDu
 		ts.scrapesFailed++
 	}
 	ts.err = err
+	ts.cardinality.update(scrapeTime, samplesPostRelabeling, seriesAdded, scrapeDuration)
 	tsm.mu.Unlock()
 }
 
@@ -306,6 +307,9 @@ type targetStatus struct {
 	scrapesTotal       int
 	scrapesFailed      int
 	err                error
+
+	// cardinality contains stats for /api/v1/targets/cardinality page.
+	cardinality cardinalityStats
 }
 
 func (ts *targetStatus) getDurationFromLastScrape() string {
@@ -6,6 +6,7 @@ import (
 
 	"github.com/klauspost/compress/gzip"
 	"github.com/klauspost/compress/zlib"
+	"github.com/klauspost/compress/zstd"
 )
 
 // GetGzipReader returns new gzip reader from the pool.
@@ -51,3 +52,27 @@ func PutZlibReader(zr io.ReadCloser) {
 }
 
 var zlibReaderPool sync.Pool
+
+// GetZstdReader returns zstd reader from the pool.
+//
+// Return back the zstd reader when it no longer needed with PutZstdReader.
+func GetZstdReader(r io.Reader) (*zstd.Decoder, error) {
+	v := zstdReaderPool.Get()
+	if v == nil {
+		return zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+	}
+	zr := v.(*zstd.Decoder)
+	if err := zr.Reset(r); err != nil {
+		return nil, err
+	}
+	return zr, nil
+}
+
+// PutZstdReader returns back zstd reader obtained via GetZstdReader.
+func PutZstdReader(zr *zstd.Decoder) {
+	// Do not call zr.Close(), since it releases the resources needed for reusing zr.
+	_ = zr.Reset(nil)
+	zstdReaderPool.Put(zr)
+}
+
+var zstdReaderPool sync.Pool
@@ -1,6 +1,7 @@
 package datadogsketches
 
 import (
+	"flag"
 	"fmt"
 	"math"
 	"strconv"
@@ -8,6 +9,10 @@ import (
 	"github.com/VictoriaMetrics/easyproto"
 )
 
+var convertToHistograms = flag.Bool("datadog.convertSketchesToHistograms", false, "Whether to convert DataDog sketches ingested via /api/beta/sketches "+
+	"into VictoriaMetrics histograms with vmrange buckets instead of summaries with the pre-defined quantiles. "+
+	"See https://docs.victoriametrics.com/#sending-metrics-to-victoriametrics")
+
 var (
 	// These constants were obtained from https://github.com/DataDog/opentelemetry-mapping-go/blob/48d52eeea60d28da2e14c154a24557c4d290c6e2/pkg/quantile/config.go
 	eps        = 1.0 / 128
@@ -147,11 +152,119 @@ func (s *Sketch) unmarshalProtobuf(src []byte) (err error) {
 
 // RowsCount returns the number of samples s generates.
 func (s *Sketch) RowsCount() int {
+	if *convertToHistograms {
+		// The sketch contains a bucket per each non-empty bin plus *_sum and *_count metrics
+		// per each Dogsketch in s.Dogsketches.
+		n := 0
+		for _, d := range s.Dogsketches {
+			n += d.nonEmptyBinsCount() + 2
+		}
+		return n
+	}
 	// The sketch contains len(quantiles) plus *_sum and *_count metrics
 	// per each Dogsketch in s.Dogsketches.
 	return (len(quantiles) + 2) * len(s.Dogsketches)
 }
 
+// ToMetrics converts s to metrics.
+//
+// s is converted to histogram if -datadog.convertSketchesToHistograms is set. Otherwise it is converted to summary.
+func (s *Sketch) ToMetrics() []*Metric {
+	if *convertToHistograms {
+		return s.ToHistogram()
+	}
+	return s.ToSummary()
+}
+
+// ToHistogram generates VictoriaMetrics histogram from the given s.
+//
+// Every non-empty DDSketch bin is converted into `<metric>_bucket{vmrange="<start>...<end>"}` bucket.
+// The bin boundaries are the same for all the sketches, so the resulting buckets can be aggregated with histogram functions.
+// See https://valyala.medium.com/improving-histogram-usability-for-prometheus-and-grafana-bc7e5df0e350
+func (s *Sketch) ToHistogram() []*Metric {
+	dogsketches := s.Dogsketches
+	sumPoints := make([]Point, len(dogsketches))
+	countPoints := make([]Point, len(dogsketches))
+
+	var buckets []*Metric
+	bucketsByVMRange := make(map[string]*Metric)
+	for j, d := range dogsketches {
+		timestamp := d.Ts * 1000
+		sumPoints[j] = Point{
+			Timestamp: timestamp,
+			Value:     d.Sum,
+		}
+		countPoints[j] = Point{
+			Timestamp: timestamp,
+			Value:     float64(d.Cnt),
+		}
+		if len(d.K) != len(d.N) {
+			// Skip malformed bins.
+			continue
+		}
+		for i, k := range d.K {
+			n := d.N[i]
+			if n == 0 {
+				continue
+			}
+			vmrange := binVMRange(k)
+			m := bucketsByVMRange[vmrange]
+			if m == nil {
+				m = &Metric{
+					Name: s.Metric + "_bucket",
+					Labels: []Label{{
+						Name:  "vmrange",
+						Value: vmrange,
+					}},
+				}
+				bucketsByVMRange[vmrange] = m
+				buckets = append(buckets, m)
+			}
+			if len(m.Points) > 0 && m.Points[len(m.Points)-1].Timestamp == timestamp {
+				// Multiple bins may map to the same vmrange, e.g. bins with infinite boundaries.
+				m.Points[len(m.Points)-1].Value += float64(n)
+				continue
+			}
+			m.Points = append(m.Points, Point{
+				Timestamp: timestamp,
+				Value:     float64(n),
+			})
+		}
+	}
+
+	return append(buckets, &Metric{
+		Name:   s.Metric + "_sum",
+		Points: sumPoints,
+	}, &Metric{
+		Name:   s.Metric + "_count",
+		Points: countPoints,
+	})
+}
+
+// binVMRange returns vmrange label value for the DDSketch bin with the given key k.
+//
+// The bin with the key k contains values in the range [f64(k) ... f64(k+1)] for positive k
+// and in the range [-f64(-k+1) ... -f64(-k)] for negative k.
+func binVMRange(k int32) string {
+	// f64 returns infinity for keys with big absolute values, so limit k in order to avoid overflow at k+1.
+	const maxKey = (1 << 15) - 1
+	if k > maxKey {
+		k = maxKey
+	} else if k < -maxKey {
+		k = -maxKey
+	}
+	var start, end float64
+	switch {
+	case k > 0:
+		start = f64(k)
+		end = f64(k + 1)
+	case k < 0:
+		start = -f64(-k + 1)
+		end = -f64(-k)
+	}
+	return fmt.Sprintf("%.3e...%.3e", start, end)
+}
+
 // ToSummary generates Prometheus summary from the given s.
 func (s *Sketch) ToSummary() []*Metric {
 	metrics := make([]*Metric, len(quantiles)+2)
@@ -285,6 +398,16 @@ func (d *Dogsketch) unmarshalProtobuf(src []byte) (err error) {
 	return nil
 }
 
+func (d *Dogsketch) nonEmptyBinsCount() int {
+	n := 0
+	for _, cnt := range d.N {
+		if cnt > 0 {
+			n++
+		}
+	}
+	return n
+}
+
 // This function has been copied from https://github.com/DataDog/opentelemetry-mapping-go/blob/48d52eeea60d28da2e14c154a24557c4d290c6e2/pkg/quantile/sparse.go#L92
 func (d *Dogsketch) quantile(q float64) float64 {
 	switch {
@@ -1,7 +1,10 @@
 package datadogsketches
 
 import (
+	"fmt"
 	"math"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -29,3 +32,74 @@ func TestDogsketchQuantile(t *testing.T) {
 	f(sketches, 0.99, 20.24)
 	f(sketches, 1, 21)
 }
+
+func TestBinVMRange(t *testing.T) {
+	f := func(k int32, vmrangeExpected string) {
+		t.Helper()
+		vmrange := binVMRange(k)
+		if vmrange != vmrangeExpected {
+			t.Fatalf("unexpected vmrange for k=%d; got %q; want %q", k, vmrange, vmrangeExpected)
+		}
+	}
+	f(0, "0.000e+00...0.000e+00")
+	f(1473, "8.110e+00...8.236e+00")
+	f(1474, "8.236e+00...8.365e+00")
+	f(-1473, "-8.236e+00...-8.110e+00")
+	f(32767, "+Inf...+Inf")
+	f(math.MaxInt32, "+Inf...+Inf")
+	f(-32767, "-Inf...-Inf")
+	f(math.MinInt32, "-Inf...-Inf")
+}
+
+func TestSketchToHistogram(t *testing.T) {
+	s := &Sketch{
+		Metric: "foo",
+		Dogsketches: []*Dogsketch{
+			{
+				Ts:  1,
+				Cnt: 3,
+				Sum: 25,
+				K:   []int32{1473, 1474},
+				N:   []uint32{2, 1},
+			},
+			{
+				Ts:  2,
+				Cnt: 4,
+				Sum: 0,
+				K:   []int32{0, 1473, 1480},
+				N:   []uint32{4, 0, 0},
+			},
+		},
+	}
+	ms := s.ToHistogram()
+	var result []string
+	for _, m := range ms {
+		var labels string
+		for _, label := range m.Labels {
+			labels += fmt.Sprintf("%s=%q", label.Name, label.Value)
+		}
+		for _, p := range m.Points {
+			result = append(result, fmt.Sprintf("%s{%s} %v %d", m.Name, labels, p.Value, p.Timestamp))
+		}
+	}
+	resultExpected := []string{
+		`foo_bucket{vmrange="8.110e+00...8.236e+00"} 2 1000`,
+		`foo_bucket{vmrange="8.236e+00...8.365e+00"} 1 1000`,
+		`foo_bucket{vmrange="0.000e+00...0.000e+00"} 4 2000`,
+		`foo_sum{} 25 1000`,
+		`foo_sum{} 0 2000`,
+		`foo_count{} 3 1000`,
+		`foo_count{} 4 2000`,
+	}
+	if !reflect.DeepEqual(result, resultExpected) {
+		t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", strings.Join(result, "\n"), strings.Join(resultExpected, "\n"))
+	}
+
+	rowsCount := 0
+	for _, d := range s.Dogsketches {
+		rowsCount += d.nonEmptyBinsCount() + 2
+	}
+	if rowsCount != len(result) {
+		t.Fatalf("unexpected rows count; got %d; want %d", rowsCount, len(result))
+	}
+}
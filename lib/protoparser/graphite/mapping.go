@@ -0,0 +1,266 @@
+package graphite
+
+import (
+	"flag"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/VictoriaMetrics/metrics"
+	"gopkg.in/yaml.v2"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/envtemplate"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fs/fscore"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+)
+
+var mappingConfigPath = flag.String("graphite.mappingConfig", "", "Optional path to a file with mapping rules for converting dotted Graphite metric names "+
+	"into metric names with labels at ingestion time. The path can point either to local file or to http url. "+
+	"See https://docs.victoriametrics.com/#graphite-mapping-rules")
+
+// maxMappingCacheSize is the maximum number of cached mapping results.
+const maxMappingCacheSize = 100_000
+
+var mappingConfigGlobal atomic.Pointer[MappingConfig]
+
+// InitMapping loads mapping rules from -graphite.mappingConfig if it is set.
+//
+// The loaded rules are applied to rows parsed by Rows.Unmarshal.
+func InitMapping() {
+	if *mappingConfigPath == "" {
+		return
+	}
+	mc, err := LoadMappingConfig(*mappingConfigPath)
+	if err != nil {
+		logger.Fatalf("cannot load -graphite.mappingConfig=%q: %s", *mappingConfigPath, err)
+	}
+	mappingConfigGlobal.Store(mc)
+}
+
+// MappingConfig contains rules for converting Graphite metric names into metric names with labels.
+//
+// The rules are compatible with mapping rules from graphite_exporter.
+// See https://github.com/prometheus/graphite_exporter#metric-mapping-and-configuration
+type MappingConfig struct {
+	rules       []*mappingRule
+	strictMatch bool
+
+	cacheLock sync.Mutex
+	cache     map[string]*mappingResult
+}
+
+// mappingConfigYAML represents the contents of -graphite.mappingConfig file.
+type mappingConfigYAML struct {
+	// StrictMatch instructs dropping metrics, which do not match any rule.
+	StrictMatch bool              `yaml:"strict_match,omitempty"`
+	Mappings    []mappingRuleYAML `yaml:"mappings"`
+}
+
+type mappingRuleYAML struct {
+	Match     string            `yaml:"match"`
+	MatchType string            `yaml:"match_type,omitempty"`
+	Name      string            `yaml:"name,omitempty"`
+	Labels    map[string]string `yaml:"labels,omitempty"`
+	Action    string            `yaml:"action,omitempty"`
+}
+
+type mappingRule struct {
+	re     *regexp.Regexp
+	name   string
+	labels []Tag
+	drop   bool
+}
+
+// mappingResult is the result of applying mapping rules to a metric name.
+type mappingResult struct {
+	drop   bool
+	name   string
+	labels []Tag
+}
+
+// LoadMappingConfig loads mapping rules from the given path.
+func LoadMappingConfig(path string) (*MappingConfig, error) {
+	data, err := fscore.ReadFileOrHTTP(path)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read mapping config: %w", err)
+	}
+	data, err = envtemplate.ReplaceBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot expand environment vars: %w", err)
+	}
+	return ParseMappingConfig(data)
+}
+
+// ParseMappingConfig parses mapping rules from data.
+func ParseMappingConfig(data []byte) (*MappingConfig, error) {
+	var cfg mappingConfigYAML
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return nil, fmt.Errorf("cannot parse mapping config: %w", err)
+	}
+	rules := make([]*mappingRule, 0, len(cfg.Mappings))
+	for i := range cfg.Mappings {
+		rule, err := newMappingRule(&cfg.Mappings[i])
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse mapping rule #%d: %w", i+1, err)
+		}
+		rules = append(rules, rule)
+	}
+	return &MappingConfig{
+		rules:       rules,
+		strictMatch: cfg.StrictMatch,
+		cache:       make(map[string]*mappingResult),
+	}, nil
+}
+
+func newMappingRule(mr *mappingRuleYAML) (*mappingRule, error) {
+	if mr.Match == "" {
+		return nil, fmt.Errorf("missing `match`")
+	}
+	var expr string
+	switch mr.MatchType {
+	case "", "glob":
+		expr = globToRegexp(mr.Match)
+	case "regex":
+		expr = mr.Match
+	default:
+		return nil, fmt.Errorf("unsupported `match_type: %q`; supported values: glob, regex", mr.MatchType)
+	}
+	re, err := regexp.Compile("^(?:" + expr + ")$")
+	if err != nil {
+		return nil, fmt.Errorf("cannot compile `match: %q`: %w", mr.Match, err)
+	}
+	rule := &mappingRule{
+		re:   re,
+		name: mr.Name,
+	}
+	switch mr.Action {
+	case "", "map":
+	case "drop":
+		rule.drop = true
+		return rule, nil
+	default:
+		return nil, fmt.Errorf("unsupported `action: %q`; supported values: map, drop", mr.Action)
+	}
+	for name, value := range mr.Labels {
+		if name == "" {
+			return nil, fmt.Errorf("label name cannot be empty")
+		}
+		rule.labels = append(rule.labels, Tag{
+			Key:   name,
+			Value: value,
+		})
+	}
+	// Sort labels in order to get deterministic results, since they are obtained from map.
+	sort.Slice(rule.labels, func(i, j int) bool {
+		return rule.labels[i].Key < rule.labels[j].Key
+	})
+	return rule, nil
+}
+
+// globToRegexp converts graphite_exporter glob pattern into regexp.
+//
+// Every `*` in the glob matches a non-empty part of a metric name without dots.
+func globToRegexp(glob string) string {
+	parts := strings.Split(glob, "*")
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return strings.Join(parts, "([^.]+)")
+}
+
+// getResult returns mapping result for the given metricName.
+//
+// nil is returned if metricName doesn't match any rule.
+func (mc *MappingConfig) getResult(metricName string) *mappingResult {
+	mc.cacheLock.Lock()
+	res, ok := mc.cache[metricName]
+	mc.cacheLock.Unlock()
+	if ok {
+		return res
+	}
+
+	res = mc.newResult(metricName)
+
+	mc.cacheLock.Lock()
+	if len(mc.cache) >= maxMappingCacheSize {
+		clear(mc.cache)
+	}
+	// Clone metricName, since it may refer to the parsed request buffer.
+	mc.cache[strings.Clone(metricName)] = res
+	mc.cacheLock.Unlock()
+	return res
+}
+
+func (mc *MappingConfig) newResult(metricName string) *mappingResult {
+	for _, rule := range mc.rules {
+		match := rule.re.FindStringSubmatchIndex(metricName)
+		if match == nil {
+			continue
+		}
+		if rule.drop {
+			return &mappingResult{
+				drop: true,
+			}
+		}
+		res := &mappingResult{
+			name: strings.Clone(metricName),
+		}
+		if rule.name != "" {
+			res.name = string(rule.re.ExpandString(nil, rule.name, metricName, match))
+		}
+		for _, label := range rule.labels {
+			value := string(rule.re.ExpandString(nil, label.Value, metricName, match))
+			if value == "" {
+				// Skip labels with empty values in the same way as empty tags are skipped.
+				continue
+			}
+			res.labels = append(res.labels, Tag{
+				Key:   label.Key,
+				Value: value,
+			})
+		}
+		return res
+	}
+	if mc.strictMatch {
+		return &mappingResult{
+			drop: true,
+		}
+	}
+	return nil
+}
+
+// apply applies mc to rows and returns the result.
+//
+// Labels obtained from mapping rules are appended to tagsPool.
+func (mc *MappingConfig) apply(rows []Row, tagsPool []Tag) ([]Row, []Tag) {
+	dst := rows[:0]
+	for i := range rows {
+		r := rows[i]
+		res := mc.getResult(r.Metric)
+		if res != nil {
+			if res.drop {
+				rowsDroppedByMapping.Inc()
+				continue
+			}
+			r.Metric = res.name
+			if len(res.labels) > 0 {
+				tagsStart := len(tagsPool)
+				tagsPool = append(tagsPool, r.Tags...)
+				tagsPool = append(tagsPool, res.labels...)
+				tags := tagsPool[tagsStart:]
+				r.Tags = tags[:len(tags):len(tags)]
+			}
+		}
+		dst = append(dst, r)
+	}
+	// Reset the remaining rows, so they can be GC'ed.
+	for i := len(dst); i < len(rows); i++ {
+		rows[i].reset()
+	}
+	return dst, tagsPool
+}
+
+var rowsDroppedByMapping = metrics.NewCounter(`vm_protoparser_rows_dropped_total{type="graphite",reason="mapping"}`)
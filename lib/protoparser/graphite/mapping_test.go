@@ -0,0 +1,266 @@
+package graphite
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMappingConfig_Failure(t *testing.T) {
+	f := func(data string) {
+		t.Helper()
+		mc, err := ParseMappingConfig([]byte(data))
+		if err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+		if mc != nil {
+			t.Fatalf("expecting nil mc")
+		}
+	}
+
+	// invalid yaml
+	f(`foobar`)
+
+	// unknown field
+	f(`
+mappings:
+- match: foo.*
+  foo: bar
+`)
+
+	// missing match
+	f(`
+mappings:
+- name: foo
+`)
+
+	// unsupported match_type
+	f(`
+mappings:
+- match: foo.*
+  match_type: foo
+`)
+
+	// invalid regex
+	f(`
+mappings:
+- match: foo.(bar
+  match_type: regex
+`)
+
+	// unsupported action
+	f(`
+mappings:
+- match: foo.*
+  action: keep
+`)
+
+	// empty label name
+	f(`
+mappings:
+- match: foo.*
+  labels:
+    "": bar
+`)
+}
+
+func TestMappingConfigApply(t *testing.T) {
+	f := func(config, s string, rowsExpected []Row) {
+		t.Helper()
+
+		mc, err := ParseMappingConfig([]byte(config))
+		if err != nil {
+			t.Fatalf("unexpected error when parsing mapping config: %s", err)
+		}
+		var rows []Row
+		rows, tagsPool := unmarshalRows(rows, s, nil)
+		rows, _ = mc.apply(rows, tagsPool)
+		if len(rows) == 0 && len(rowsExpected) == 0 {
+			return
+		}
+		if !reflect.DeepEqual(rows, rowsExpected) {
+			t.Fatalf("unexpected rows;\ngot\n%+v\nwant\n%+v", rows, rowsExpected)
+		}
+
+		// Verify the cached results are applied in the same way.
+		rows, tagsPool = unmarshalRows(rows[:0], s, nil)
+		rows, _ = mc.apply(rows, tagsPool)
+		if !reflect.DeepEqual(rows, rowsExpected) {
+			t.Fatalf("unexpected rows for cached results;\ngot\n%+v\nwant\n%+v", rows, rowsExpected)
+		}
+	}
+
+	// glob mapping
+	f(`
+mappings:
+- match: servers.*.cpu.*
+  name: cpu_usage
+  labels:
+    instance: $1
+    mode: ${2}
+`, "servers.host1.cpu.idle 12 123\nservers.host2.mem.free 34 123", []Row{
+		{
+			Metric: "cpu_usage",
+			Tags: []Tag{
+				{
+					Key:   "instance",
+					Value: "host1",
+				},
+				{
+					Key:   "mode",
+					Value: "idle",
+				},
+			},
+			Value:     12,
+			Timestamp: 123,
+		},
+		{
+			Metric:    "servers.host2.mem.free",
+			Value:     34,
+			Timestamp: 123,
+		},
+	})
+
+	// glob mustn't match dots
+	f(`
+mappings:
+- match: servers.*.cpu
+  name: cpu
+`, "servers.dc1.host1.cpu 1 123", []Row{
+		{
+			Metric:    "servers.dc1.host1.cpu",
+			Value:     1,
+			Timestamp: 123,
+		},
+	})
+
+	// regex mapping with name template
+	f(`
+mappings:
+- match: 'app\.([^.]+)\.requests\.(.+)'
+  match_type: regex
+  name: app_requests_${2}
+  labels:
+    app: $1
+`, "app.foo.requests.count 5 123", []Row{
+		{
+			Metric: "app_requests_count",
+			Tags: []Tag{
+				{
+					Key:   "app",
+					Value: "foo",
+				},
+			},
+			Value:     5,
+			Timestamp: 123,
+		},
+	})
+
+	// the first matching rule wins
+	f(`
+mappings:
+- match: foo.bar
+  name: first
+- match: foo.*
+  name: second
+`, "foo.bar 1 123\nfoo.baz 2 123", []Row{
+		{
+			Metric:    "first",
+			Value:     1,
+			Timestamp: 123,
+		},
+		{
+			Metric:    "second",
+			Value:     2,
+			Timestamp: 123,
+		},
+	})
+
+	// drop action
+	f(`
+mappings:
+- match: debug.*
+  action: drop
+`, "debug.foo 1 123\nfoo.bar 2 123", []Row{
+		{
+			Metric:    "foo.bar",
+			Value:     2,
+			Timestamp: 123,
+		},
+	})
+
+	// strict_match drops unmatched rows
+	f(`
+strict_match: true
+mappings:
+- match: foo.*
+  name: foo
+  labels:
+    bar: $1
+`, "foo.x 1 123\nbar.x 2 123", []Row{
+		{
+			Metric: "foo",
+			Tags: []Tag{
+				{
+					Key:   "bar",
+					Value: "x",
+				},
+			},
+			Value:     1,
+			Timestamp: 123,
+		},
+	})
+
+	// all the rows are dropped
+	f(`
+strict_match: true
+mappings:
+- match: foo.*
+`, "bar.x 2 123", nil)
+
+	// the existing tags are preserved, empty labels are skipped
+	f(`
+mappings:
+- match: 'foo\.(\w+)(\.(\w+))?'
+  match_type: regex
+  name: foo
+  labels:
+    a: $1
+    b: $3
+`, "foo.x;env=prod 1 123", []Row{
+		{
+			Metric: "foo",
+			Tags: []Tag{
+				{
+					Key:   "env",
+					Value: "prod",
+				},
+				{
+					Key:   "a",
+					Value: "x",
+				},
+			},
+			Value:     1,
+			Timestamp: 123,
+		},
+	})
+
+	// metric name is preserved if name isn't set
+	f(`
+mappings:
+- match: foo.*
+  labels:
+    bar: $1
+`, "foo.x 1 123", []Row{
+		{
+			Metric: "foo.x",
+			Tags: []Tag{
+				{
+					Key:   "bar",
+					Value: "x",
+				},
+			},
+			Value:     1,
+			Timestamp: 123,
+		},
+	})
+}
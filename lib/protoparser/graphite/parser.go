@@ -50,6 +50,9 @@ func (rs *Rows) Reset() {
 // s shouldn't be modified when rs is in use.
 func (rs *Rows) Unmarshal(s string) {
 	rs.Rows, rs.tagsPool = unmarshalRows(rs.Rows[:0], s, rs.tagsPool[:0])
+	if mc := mappingConfigGlobal.Load(); mc != nil {
+		rs.Rows, rs.tagsPool = mc.apply(rs.Rows, rs.tagsPool)
+	}
 }
 
 // Row is a single graphite row.
@@ -0,0 +1,397 @@
+package graphite
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/valyala/fastjson/fastfloat"
+)
+
+// UnmarshalPickle unmarshals graphite rows from a single message in pickle protocol.
+//
+// The message must contain pickled list of `(path, (timestamp, value))` tuples without the length header.
+// See https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-pickle-protocol
+//
+// data shouldn't be modified when rs is in use.
+func (rs *Rows) UnmarshalPickle(data []byte) error {
+	rs.Rows = rs.Rows[:0]
+	rs.tagsPool = rs.tagsPool[:0]
+	v, err := unpickle(data)
+	if err != nil {
+		return fmt.Errorf("cannot unpickle data: %w", err)
+	}
+	items, ok := v.([]any)
+	if !ok {
+		return fmt.Errorf("unexpected pickled value type; got %T; want list", v)
+	}
+	for _, item := range items {
+		rs.Rows, rs.tagsPool, err = appendRowFromPickledItem(rs.Rows, item, rs.tagsPool)
+		if err != nil {
+			logger.Errorf("cannot unmarshal Graphite pickle item %v: %s", item, err)
+			invalidLines.Inc()
+		}
+	}
+	if mc := mappingConfigGlobal.Load(); mc != nil {
+		rs.Rows, rs.tagsPool = mc.apply(rs.Rows, rs.tagsPool)
+	}
+	return nil
+}
+
+func appendRowFromPickledItem(dst []Row, item any, tagsPool []Tag) ([]Row, []Tag, error) {
+	a, ok := item.([]any)
+	if !ok || len(a) != 2 {
+		return dst, tagsPool, fmt.Errorf("item must be (path, (timestamp, value)) tuple")
+	}
+	path, ok := a[0].(string)
+	if !ok {
+		return dst, tagsPool, fmt.Errorf("unexpected path type; got %T; want string", a[0])
+	}
+	datapoint, ok := a[1].([]any)
+	if !ok || len(datapoint) != 2 {
+		return dst, tagsPool, fmt.Errorf("datapoint must be (timestamp, value) tuple")
+	}
+	ts, err := getPickledFloat64(datapoint[0])
+	if err != nil {
+		return dst, tagsPool, fmt.Errorf("cannot parse timestamp: %w", err)
+	}
+	value, err := getPickledFloat64(datapoint[1])
+	if err != nil {
+		return dst, tagsPool, fmt.Errorf("cannot parse value: %w", err)
+	}
+	var r Row
+	tagsPoolLen := len(tagsPool)
+	tagsPool, err = r.UnmarshalMetricAndTags(path, tagsPool)
+	if err != nil {
+		return dst, tagsPool[:tagsPoolLen], fmt.Errorf("cannot parse metric and tags from %q: %w", path, err)
+	}
+	r.Timestamp = int64(ts)
+	r.Value = value
+	dst = append(dst, r)
+	return dst, tagsPool, nil
+}
+
+func getPickledFloat64(v any) (float64, error) {
+	switch t := v.(type) {
+	case float64:
+		return t, nil
+	case int64:
+		return float64(t), nil
+	case string:
+		return fastfloat.Parse(t)
+	default:
+		return 0, fmt.Errorf("unexpected type %T; want number", v)
+	}
+}
+
+// pickleMark is used for marking the stack position for pickle opcodes working with the stack tail.
+type pickleMark struct{}
+
+// unpickle unpickles data and returns the result.
+//
+// Only the subset of pickle opcodes needed for unpickling lists and tuples with strings and numbers is supported.
+// Unpickled lists and tuples are returned as []any, strings are returned as string, integers are returned as int64,
+// floating-point numbers are returned as float64.
+//
+// The returned strings may refer to data.
+func unpickle(data []byte) (any, error) {
+	var stack []any
+	memo := make(map[uint64]any)
+	src := data
+
+	readBytes := func(n uint64) ([]byte, error) {
+		if uint64(len(src)) < n {
+			return nil, fmt.Errorf("cannot read %d bytes from %d bytes", n, len(src))
+		}
+		b := src[:n]
+		src = src[n:]
+		return b, nil
+	}
+	readLine := func() (string, error) {
+		n := strings.IndexByte(bytesutil.ToUnsafeString(src), '\n')
+		if n < 0 {
+			return "", fmt.Errorf("missing newline")
+		}
+		s := bytesutil.ToUnsafeString(src[:n])
+		src = src[n+1:]
+		return s, nil
+	}
+	pop := func() (any, error) {
+		if len(stack) == 0 {
+			return nil, fmt.Errorf("stack underflow")
+		}
+		v := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		return v, nil
+	}
+	popMark := func() ([]any, error) {
+		for i := len(stack) - 1; i >= 0; i-- {
+			if _, ok := stack[i].(pickleMark); ok {
+				items := append([]any{}, stack[i+1:]...)
+				stack = stack[:i]
+				return items, nil
+			}
+		}
+		return nil, fmt.Errorf("cannot find mark")
+	}
+	appendToList := func(items []any) error {
+		if len(stack) == 0 {
+			return fmt.Errorf("stack underflow")
+		}
+		list, ok := stack[len(stack)-1].([]any)
+		if !ok {
+			return fmt.Errorf("unexpected type to append to; got %T; want list", stack[len(stack)-1])
+		}
+		stack[len(stack)-1] = append(list, items...)
+		return nil
+	}
+	put := func(idx uint64) error {
+		if len(stack) == 0 {
+			return fmt.Errorf("stack underflow")
+		}
+		memo[idx] = stack[len(stack)-1]
+		return nil
+	}
+	get := func(idx uint64) error {
+		v, ok := memo[idx]
+		if !ok {
+			return fmt.Errorf("missing memo entry %d", idx)
+		}
+		stack = append(stack, v)
+		return nil
+	}
+
+	for {
+		b, err := readBytes(1)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read opcode: %w", err)
+		}
+		op := b[0]
+		switch op {
+		case 0x80: // PROTO
+			if _, err := readBytes(1); err != nil {
+				return nil, err
+			}
+		case 0x95: // FRAME
+			if _, err := readBytes(8); err != nil {
+				return nil, err
+			}
+		case '.': // STOP
+			v, err := pop()
+			if err != nil {
+				return nil, err
+			}
+			return v, nil
+		case '(': // MARK
+			stack = append(stack, pickleMark{})
+		case '0': // POP
+			if _, err := pop(); err != nil {
+				return nil, err
+			}
+		case 'N': // NONE
+			stack = append(stack, nil)
+		case 0x88: // NEWTRUE
+			stack = append(stack, int64(1))
+		case 0x89: // NEWFALSE
+			stack = append(stack, int64(0))
+		case ']', ')': // EMPTY_LIST, EMPTY_TUPLE
+			stack = append(stack, []any{})
+		case 'l', 't': // LIST, TUPLE
+			items, err := popMark()
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, items)
+		case 0x85, 0x86, 0x87: // TUPLE1, TUPLE2, TUPLE3
+			n := int(op-0x85) + 1
+			if len(stack) < n {
+				return nil, fmt.Errorf("stack underflow")
+			}
+			items := append([]any{}, stack[len(stack)-n:]...)
+			stack = append(stack[:len(stack)-n], items)
+		case 'a': // APPEND
+			v, err := pop()
+			if err != nil {
+				return nil, err
+			}
+			if err := appendToList([]any{v}); err != nil {
+				return nil, err
+			}
+		case 'e': // APPENDS
+			items, err := popMark()
+			if err != nil {
+				return nil, err
+			}
+			if err := appendToList(items); err != nil {
+				return nil, err
+			}
+		case 'X', 'T', 'B': // BINUNICODE, BINSTRING, BINBYTES
+			b, err := readBytes(4)
+			if err != nil {
+				return nil, err
+			}
+			s, err := readBytes(uint64(binary.LittleEndian.Uint32(b)))
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, bytesutil.ToUnsafeString(s))
+		case 0x8c, 'U', 'C': // SHORT_BINUNICODE, SHORT_BINSTRING, SHORT_BINBYTES
+			b, err := readBytes(1)
+			if err != nil {
+				return nil, err
+			}
+			s, err := readBytes(uint64(b[0]))
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, bytesutil.ToUnsafeString(s))
+		case 0x8d, 0x8e: // BINUNICODE8, BINBYTES8
+			b, err := readBytes(8)
+			if err != nil {
+				return nil, err
+			}
+			s, err := readBytes(binary.LittleEndian.Uint64(b))
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, bytesutil.ToUnsafeString(s))
+		case 'S': // STRING
+			s, err := readLine()
+			if err != nil {
+				return nil, err
+			}
+			if len(s) < 2 || (s[0] != '\'' && s[0] != '"') || s[len(s)-1] != s[0] || strings.IndexByte(s, '\\') >= 0 {
+				return nil, fmt.Errorf("unsupported quoted string %q", s)
+			}
+			stack = append(stack, s[1:len(s)-1])
+		case 'V': // UNICODE
+			s, err := readLine()
+			if err != nil {
+				return nil, err
+			}
+			if strings.IndexByte(s, '\\') >= 0 {
+				return nil, fmt.Errorf("unsupported escaped unicode string %q", s)
+			}
+			stack = append(stack, s)
+		case 'J': // BININT
+			b, err := readBytes(4)
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, int64(int32(binary.LittleEndian.Uint32(b))))
+		case 'K': // BININT1
+			b, err := readBytes(1)
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, int64(b[0]))
+		case 'M': // BININT2
+			b, err := readBytes(2)
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, int64(binary.LittleEndian.Uint16(b)))
+		case 0x8a: // LONG1
+			b, err := readBytes(1)
+			if err != nil {
+				return nil, err
+			}
+			if b[0] > 8 {
+				return nil, fmt.Errorf("too long integer with %d bytes; max supported length is 8 bytes", b[0])
+			}
+			b, err = readBytes(uint64(b[0]))
+			if err != nil {
+				return nil, err
+			}
+			var n int64
+			for i := len(b) - 1; i >= 0; i-- {
+				n = n<<8 | int64(b[i])
+			}
+			if len(b) > 0 && len(b) < 8 && b[len(b)-1]&0x80 != 0 {
+				// Negative number in two's complement.
+				n -= 1 << (8 * uint(len(b)))
+			}
+			stack = append(stack, n)
+		case 'I', 'L': // INT, LONG
+			s, err := readLine()
+			if err != nil {
+				return nil, err
+			}
+			n, err := strconv.ParseInt(strings.TrimSuffix(s, "L"), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse integer: %w", err)
+			}
+			stack = append(stack, n)
+		case 'F': // FLOAT
+			s, err := readLine()
+			if err != nil {
+				return nil, err
+			}
+			f, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse float: %w", err)
+			}
+			stack = append(stack, f)
+		case 'G': // BINFLOAT
+			b, err := readBytes(8)
+			if err != nil {
+				return nil, err
+			}
+			stack = append(stack, math.Float64frombits(binary.BigEndian.Uint64(b)))
+		case 'p', 'g': // PUT, GET
+			s, err := readLine()
+			if err != nil {
+				return nil, err
+			}
+			idx, err := strconv.ParseUint(s, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse memo index: %w", err)
+			}
+			if op == 'p' {
+				err = put(idx)
+			} else {
+				err = get(idx)
+			}
+			if err != nil {
+				return nil, err
+			}
+		case 'q', 'h': // BINPUT, BINGET
+			b, err := readBytes(1)
+			if err != nil {
+				return nil, err
+			}
+			if op == 'q' {
+				err = put(uint64(b[0]))
+			} else {
+				err = get(uint64(b[0]))
+			}
+			if err != nil {
+				return nil, err
+			}
+		case 'r', 'j': // LONG_BINPUT, LONG_BINGET
+			b, err := readBytes(4)
+			if err != nil {
+				return nil, err
+			}
+			idx := uint64(binary.LittleEndian.Uint32(b))
+			if op == 'r' {
+				err = put(idx)
+			} else {
+				err = get(idx)
+			}
+			if err != nil {
+				return nil, err
+			}
+		case 0x94: // MEMOIZE
+			if err := put(uint64(len(memo))); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unsupported pickle opcode 0x%02x", op)
+		}
+	}
+}
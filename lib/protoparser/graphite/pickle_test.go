@@ -0,0 +1,106 @@
+package graphite
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRowsUnmarshalPickleSuccess(t *testing.T) {
+	f := func(data string, rowsExpected []Row) {
+		t.Helper()
+		var rows Rows
+		if err := rows.UnmarshalPickle([]byte(data)); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(rows.Rows, rowsExpected) {
+			t.Fatalf("unexpected rows;\ngot\n%+v\nwant\n%+v", rows.Rows, rowsExpected)
+		}
+
+		// Try unmarshaling again
+		if err := rows.UnmarshalPickle([]byte(data)); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(rows.Rows, rowsExpected) {
+			t.Fatalf("unexpected rows;\ngot\n%+v\nwant\n%+v", rows.Rows, rowsExpected)
+		}
+	}
+
+	// empty list
+	f("\x80\x02]q\x00.", nil)
+
+	// [("foo.bar.baz", (1700000000, 1.5)), ("foo;env=prod;dc=x", (1700000001.5, -2)), ("a.b", (1700000002, 12345678901))]
+	rowsExpected := []Row{
+		{
+			Metric:    "foo.bar.baz",
+			Value:     1.5,
+			Timestamp: 1700000000,
+		},
+		{
+			Metric: "foo",
+			Tags: []Tag{
+				{
+					Key:   "env",
+					Value: "prod",
+				},
+				{
+					Key:   "dc",
+					Value: "x",
+				},
+			},
+			Value:     -2,
+			Timestamp: 1700000001,
+		},
+		{
+			Metric:    "a.b",
+			Value:     12345678901,
+			Timestamp: 1700000002,
+		},
+	}
+
+	// protocol 0
+	f("(lp0\x0a(Vfoo.bar.baz\x0ap1\x0a(I1700000000\x0aF1.5\x0atp2\x0atp3\x0aa(Vfoo;env=prod;dc=x\x0ap4\x0a(F1700000001.5\x0aI-2\x0atp5\x0atp6\x0aa(Va.b\x0ap7\x0a(I1700000002\x0aL12345678901L\x0atp8\x0atp9\x0aa.", rowsExpected)
+
+	// protocol 2
+	f("\x80\x02]q\x00(X\x0b\x00\x00\x00foo.bar.bazq\x01J\x00\xf1SeG?\xf8\x00\x00\x00\x00\x00\x00\x86q\x02\x86q\x03X\x11\x00\x00\x00foo;env=prod;dc=xq\x04GA\xd9T\xfc@`\x00\x00J\xfe\xff\xff\xff\x86q\x05\x86q\x06X\x03\x00\x00\x00a.bq\x07J\x02\xf1Se\x8a\x055\x1c\xdc\xdf\x02\x86q\x08\x86q\x09e.", rowsExpected)
+
+	// protocol 4
+	f("\x80\x04\x95a\x00\x00\x00\x00\x00\x00\x00]\x94(\x8c\x0bfoo.bar.baz\x94J\x00\xf1SeG?\xf8\x00\x00\x00\x00\x00\x00\x86\x94\x86\x94\x8c\x11foo;env=prod;dc=x\x94GA\xd9T\xfc@`\x00\x00J\xfe\xff\xff\xff\x86\x94\x86\x94\x8c\x03a.b\x94J\x02\xf1Se\x8a\x055\x1c\xdc\xdf\x02\x86\x94\x86\x94e.", rowsExpected)
+
+	// invalid items are skipped: [("x", (1, "foo")), ("y", (2, 3))]
+	f("\x80\x02]q\x00(X\x01\x00\x00\x00xq\x01K\x01X\x03\x00\x00\x00fooq\x02\x86q\x03\x86q\x04X\x01\x00\x00\x00yq\x05K\x02K\x03\x86q\x06\x86q\x07e.", []Row{{
+		Metric:    "y",
+		Value:     3,
+		Timestamp: 2,
+	}})
+}
+
+func TestRowsUnmarshalPickleFailure(t *testing.T) {
+	f := func(data string) {
+		t.Helper()
+		var rows Rows
+		if err := rows.UnmarshalPickle([]byte(data)); err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	// empty data
+	f("")
+
+	// missing STOP opcode
+	f("\x80\x02]q\x00")
+
+	// truncated string
+	f("\x80\x02]q\x00X\x01\x00\x00")
+
+	// unsupported opcode: {"a": 1}
+	f("\x80\x02}q\x00X\x01\x00\x00\x00aq\x01K\x01s.")
+
+	// non-list value
+	f("\x80\x02K\x01.")
+
+	// missing memo entry
+	f("\x80\x02h\x05.")
+
+	// stack underflow
+	f("\x80\x02a.")
+}
@@ -0,0 +1,66 @@
+package stream
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/graphite"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/writeconcurrencylimiter"
+	"github.com/VictoriaMetrics/metrics"
+)
+
+var maxPickleMessageSize = flagutil.NewBytes("graphite.maxPickleMessageSize", 64*1024*1024, "The maximum size in bytes of a single message "+
+	"accepted at -graphitePickleListenAddr")
+
+// ParsePickle parses Graphite messages in pickle protocol from r and calls callback for the parsed rows.
+//
+// Every message must start with 4-byte big-endian length header followed by pickled list of `(path, (timestamp, value))` tuples.
+// See https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-pickle-protocol
+//
+// callback shouldn't hold rows after returning.
+func ParsePickle(r io.Reader, callback func(rows []graphite.Row) error) error {
+	wcr := writeconcurrencylimiter.GetReader(r)
+	defer writeconcurrencylimiter.PutReader(wcr)
+
+	br := bufio.NewReaderSize(wcr, 64*1024)
+	var header [4]byte
+	var buf []byte
+	var rows graphite.Rows
+	for {
+		readCalls.Inc()
+		if _, err := io.ReadFull(br, header[:]); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			readErrors.Inc()
+			return fmt.Errorf("cannot read graphite pickle message header: %w", err)
+		}
+		size := binary.BigEndian.Uint32(header[:])
+		if uint64(size) > uint64(maxPickleMessageSize.IntN()) {
+			readErrors.Inc()
+			return fmt.Errorf("too big graphite pickle message; got %d bytes; mustn't exceed -graphite.maxPickleMessageSize=%d bytes", size, maxPickleMessageSize.IntN())
+		}
+		buf = bytesutil.ResizeNoCopyNoOverallocate(buf, int(size))
+		if _, err := io.ReadFull(br, buf); err != nil {
+			readErrors.Inc()
+			return fmt.Errorf("cannot read graphite pickle message with size %d bytes: %w", size, err)
+		}
+		if err := rows.UnmarshalPickle(buf); err != nil {
+			pickleUnmarshalErrors.Inc()
+			return fmt.Errorf("cannot unmarshal graphite pickle message: %w", err)
+		}
+		rowsRead.Add(len(rows.Rows))
+		normalizeTimestamps(rows.Rows)
+		if err := callback(rows.Rows); err != nil {
+			return fmt.Errorf("error when processing imported data: %w", err)
+		}
+		rows.Reset()
+		wcr.DecConcurrency()
+	}
+}
+
+var pickleUnmarshalErrors = metrics.NewCounter(`vm_protoparser_unmarshal_errors_total{type="graphite_pickle"}`)
@@ -0,0 +1,57 @@
+package stream
+
+import (
+	"bytes"
+	"encoding/binary"
+	"reflect"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/graphite"
+)
+
+func TestParsePickle(t *testing.T) {
+	// [("x", (1, 2))]
+	msg := []byte("\x80\x02]q\x00X\x01\x00\x00\x00xq\x01K\x01K\x02\x86q\x02\x86q\x03a.")
+
+	var data []byte
+	for i := 0; i < 3; i++ {
+		data = binary.BigEndian.AppendUint32(data, uint32(len(msg)))
+		data = append(data, msg...)
+	}
+
+	rowsExpected := []graphite.Row{{
+		Metric:    "x",
+		Value:     2,
+		Timestamp: 1000,
+	}}
+	callbackCalls := 0
+	err := ParsePickle(bytes.NewReader(data), func(rows []graphite.Row) error {
+		callbackCalls++
+		if !reflect.DeepEqual(rows, rowsExpected) {
+			t.Fatalf("unexpected rows;\ngot\n%+v\nwant\n%+v", rows, rowsExpected)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if callbackCalls != 3 {
+		t.Fatalf("unexpected number of callback calls; got %d; want 3", callbackCalls)
+	}
+
+	// truncated message
+	err = ParsePickle(bytes.NewReader(data[:len(data)-1]), func(_ []graphite.Row) error {
+		return nil
+	})
+	if err == nil {
+		t.Fatalf("expecting non-nil error for truncated message")
+	}
+
+	// too big message
+	err = ParsePickle(bytes.NewReader([]byte("\xff\xff\xff\xff")), func(_ []graphite.Row) error {
+		return nil
+	})
+	if err == nil {
+		t.Fatalf("expecting non-nil error for too big message")
+	}
+}
@@ -163,7 +163,13 @@ func (uw *unmarshalWork) Unmarshal() {
 	uw.rows.Unmarshal(bytesutil.ToUnsafeString(uw.reqBuf))
 	rows := uw.rows.Rows
 	rowsRead.Add(len(rows))
+	normalizeTimestamps(rows)
+	uw.runCallback(rows)
+	putUnmarshalWork(uw)
+}
 
+// normalizeTimestamps converts timestamps in seconds at rows to timestamps in milliseconds.
+func normalizeTimestamps(rows []graphite.Row) {
 	// Fill missing timestamps with the current timestamp rounded to seconds.
 	currentTimestamp := int64(fasttime.UnixTimestamp())
 	for i := range rows {
@@ -185,9 +191,6 @@ func (uw *unmarshalWork) Unmarshal() {
 			row.Timestamp -= row.Timestamp % tsTrim
 		}
 	}
-
-	uw.runCallback(rows)
-	putUnmarshalWork(uw)
 }
 
 func getUnmarshalWork() *unmarshalWork {
@@ -5,6 +5,7 @@ import (
 	"flag"
 	"fmt"
 	"io"
+	"slices"
 	"sync"
 	"time"
 
@@ -20,6 +21,10 @@ var (
 	maxLineSize   = flagutil.NewBytes("influx.maxLineSize", 256*1024, "The maximum size in bytes for a single InfluxDB line during parsing")
 	trimTimestamp = flag.Duration("influxTrimTimestamp", time.Millisecond, "Trim timestamps for InfluxDB line protocol data to this duration. "+
 		"Minimum practical duration is 1ms. Higher duration (i.e. 1s) may be used for reducing disk space usage for timestamp data")
+	allowedDatabases = flagutil.NewArrayString("influx.allowedDatabases", "Optional list of database names to accept InfluxDB line protocol data for. "+
+		"Data sent to other databases via 'db' query arg is dropped. By default, data for all the databases is accepted. See also -influx.deniedDatabases")
+	deniedDatabases = flagutil.NewArrayString("influx.deniedDatabases", "Optional list of database names to drop InfluxDB line protocol data for. "+
+		"The data sent to these databases via 'db' query arg is dropped. See also -influx.allowedDatabases")
 )
 
 // Parse parses r with the given args and calls callback for the parsed rows.
@@ -43,7 +48,7 @@ func Parse(r io.Reader, isGzipped bool, precision, db string, callback func(db s
 
 	tsMultiplier := int64(0)
 	switch precision {
-	case "ns":
+	case "n", "ns":
 		tsMultiplier = 1e6
 	case "u", "us", "µ":
 		tsMultiplier = 1e3
@@ -57,6 +62,9 @@ func Parse(r io.Reader, isGzipped bool, precision, db string, callback func(db s
 		tsMultiplier = -1e3 * 3600
 	}
 
+	// Data without db query arg, such as data received via -influxListenAddr, isn't filtered.
+	dropData := db != "" && !isDatabaseAllowed(db, *allowedDatabases, *deniedDatabases)
+
 	ctx := getStreamContext(r)
 	defer putStreamContext(ctx)
 	for ctx.Read() {
@@ -64,6 +72,7 @@ func Parse(r io.Reader, isGzipped bool, precision, db string, callback func(db s
 		uw.ctx = ctx
 		uw.callback = callback
 		uw.db = db
+		uw.dropData = dropData
 		uw.tsMultiplier = tsMultiplier
 		uw.reqBuf, ctx.reqBuf = ctx.reqBuf, uw.reqBuf
 		ctx.wg.Add(1)
@@ -77,6 +86,14 @@ func Parse(r io.Reader, isGzipped bool, precision, db string, callback func(db s
 	return ctx.callbackErr
 }
 
+// isDatabaseAllowed returns true if the data for the given db must be accepted according to allowed and denied lists.
+func isDatabaseAllowed(db string, allowed, denied []string) bool {
+	if len(allowed) > 0 && !slices.Contains(allowed, db) {
+		return false
+	}
+	return !slices.Contains(denied, db)
+}
+
 func (ctx *streamContext) Read() bool {
 	readCalls.Inc()
 	if ctx.err != nil || ctx.hasCallbackError() {
@@ -97,6 +114,8 @@ var (
 	readCalls  = metrics.NewCounter(`vm_protoparser_read_calls_total{type="influx"}`)
 	readErrors = metrics.NewCounter(`vm_protoparser_read_errors_total{type="influx"}`)
 	rowsRead   = metrics.NewCounter(`vm_protoparser_rows_read_total{type="influx"}`)
+
+	rowsDroppedByDatabase = metrics.NewCounter(`vm_protoparser_rows_dropped_total{type="influx",reason="database_filter"}`)
 )
 
 type streamContext struct {
@@ -155,6 +174,7 @@ type unmarshalWork struct {
 	ctx          *streamContext
 	callback     func(db string, rows []influx.Row) error
 	db           string
+	dropData     bool
 	tsMultiplier int64
 	reqBuf       []byte
 }
@@ -164,6 +184,7 @@ func (uw *unmarshalWork) reset() {
 	uw.ctx = nil
 	uw.callback = nil
 	uw.db = ""
+	uw.dropData = false
 	uw.tsMultiplier = 0
 	uw.reqBuf = uw.reqBuf[:0]
 }
@@ -186,6 +207,13 @@ func (uw *unmarshalWork) Unmarshal() {
 	rows := uw.rows.Rows
 	rowsRead.Add(len(rows))
 
+	if uw.dropData {
+		rowsDroppedByDatabase.Add(len(rows))
+		uw.ctx.wg.Done()
+		putUnmarshalWork(uw)
+		return
+	}
+
 	// Adjust timestamps according to uw.tsMultiplier
 	currentTs := time.Now().UnixNano() / 1e6
 	tsMultiplier := uw.tsMultiplier
@@ -28,3 +28,28 @@ func TestDetectTimestamp(t *testing.T) {
 	f(1e17, 1e11)
 	f(1e18, 1e12)
 }
+
+func TestIsDatabaseAllowed(t *testing.T) {
+	f := func(db string, allowed, denied []string, resultExpected bool) {
+		t.Helper()
+		result := isDatabaseAllowed(db, allowed, denied)
+		if result != resultExpected {
+			t.Fatalf("unexpected result for isDatabaseAllowed(%q, %q, %q); got %v; want %v", db, allowed, denied, result, resultExpected)
+		}
+	}
+
+	// empty lists
+	f("foo", nil, nil, true)
+
+	// allowed list
+	f("foo", []string{"foo", "bar"}, nil, true)
+	f("baz", []string{"foo", "bar"}, nil, false)
+
+	// denied list
+	f("foo", nil, []string{"foo"}, false)
+	f("bar", nil, []string{"foo"}, true)
+
+	// denied list has priority over allowed list
+	f("foo", []string{"foo", "bar"}, []string{"foo"}, false)
+	f("bar", []string{"foo", "bar"}, []string{"foo"}, true)
+}
@@ -0,0 +1,97 @@
+package jsonlimport
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/csvimport"
+)
+
+// FieldDescriptor represents parsing rules for a single field of JSON object.
+//
+// The field is transformed to either timestamp, tag or metric value
+// depending on the corresponding non-empty field.
+type FieldDescriptor struct {
+	// Field is the name of JSON object field.
+	Field string
+
+	// ParseTimestamp is set to a function, which is used for timestamp
+	// parsing from the given field.
+	ParseTimestamp func(s string) (int64, error)
+
+	// TagName is set to tag name for tag value, which should be obtained
+	// from the given field.
+	TagName string
+
+	// MetricName is set to metric name for value obtained from the given field.
+	MetricName string
+}
+
+// ParseFieldDescriptors parses field descriptors from s.
+//
+// s must have comma-separated list of the following entries:
+//
+//	<field>:<field_type>:<extension>
+//
+// Where:
+//
+//   - <field> is the name of JSON object field.
+//   - <field_type> is one of the following types:
+//   - time - the corresponding field contains timestamp. Timestamp format is determined by <extension>.
+//     See csvimport.ParseTimeFormat for supported formats.
+//   - label - the corresponding field contains metric label with the name set in <extension>.
+//   - metric - the corresponding field contains metric value with the name set in <extension>.
+//
+// s must contain at least a single 'metric' field and no more than a single `time` field.
+func ParseFieldDescriptors(s string) ([]FieldDescriptor, error) {
+	var fds []FieldDescriptor
+	fields := make(map[string]struct{})
+	hasValueField := false
+	hasTimeField := false
+	for i, entry := range strings.Split(s, ",") {
+		var fd FieldDescriptor
+		a := strings.SplitN(entry, ":", 3)
+		if len(a) != 3 {
+			return nil, fmt.Errorf("entry #%d must have the following form: <field>:<field_type>:<extension>; got %q", i+1, entry)
+		}
+		fd.Field = a[0]
+		if len(fd.Field) == 0 {
+			return nil, fmt.Errorf("field name cannot be empty in the entry #%d %q", i+1, entry)
+		}
+		if _, ok := fields[fd.Field]; ok {
+			return nil, fmt.Errorf("duplicate field %q for the entry #%d %q", fd.Field, i+1, entry)
+		}
+		fields[fd.Field] = struct{}{}
+		typ := a[1]
+		switch typ {
+		case "time":
+			if hasTimeField {
+				return nil, fmt.Errorf("duplicate time field has been found at entry #%d %q for %q", i+1, entry, s)
+			}
+			parseTimestamp, err := csvimport.ParseTimeFormat(a[2])
+			if err != nil {
+				return nil, fmt.Errorf("cannot parse time format from the entry #%d %q: %w", i+1, entry, err)
+			}
+			fd.ParseTimestamp = parseTimestamp
+			hasTimeField = true
+		case "label":
+			fd.TagName = a[2]
+			if len(fd.TagName) == 0 {
+				return nil, fmt.Errorf("label name cannot be empty in the entry #%d %q", i+1, entry)
+			}
+		case "metric":
+			fd.MetricName = a[2]
+			if len(fd.MetricName) == 0 {
+				return nil, fmt.Errorf("metric name cannot be empty in the entry #%d %q", i+1, entry)
+			}
+			hasValueField = true
+		default:
+			return nil, fmt.Errorf("unknown <field_type>: %q; allowed values: time, metric, label", typ)
+		}
+		fds = append(fds, fd)
+	}
+	if !hasValueField {
+		return nil, fmt.Errorf("missing 'metric' field in %q", s)
+	}
+	return fds, nil
+}
@@ -0,0 +1,63 @@
+package jsonlimport
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParseFieldDescriptorsSuccess(t *testing.T) {
+	f := func(s, resultExpected string) {
+		t.Helper()
+		fds, err := ParseFieldDescriptors(s)
+		if err != nil {
+			t.Fatalf("unexpected error on ParseFieldDescriptors(%q): %s", s, err)
+		}
+		var result string
+		for _, fd := range fds {
+			result += fmt.Sprintf("{Field:%s ParseTimestamp:%v TagName:%s MetricName:%s}", fd.Field, fd.ParseTimestamp != nil, fd.TagName, fd.MetricName)
+		}
+		if result != resultExpected {
+			t.Fatalf("unexpected fds returned from ParseFieldDescriptors(%q);\ngot\n%s\nwant\n%s", s, result, resultExpected)
+		}
+	}
+	f("temp:metric:temperature", "{Field:temp ParseTimestamp:false TagName: MetricName:temperature}")
+	f("ts:time:unix_s,temp:metric:temperature,city:label:location", "{Field:ts ParseTimestamp:true TagName: MetricName:}"+
+		"{Field:temp ParseTimestamp:false TagName: MetricName:temperature}"+
+		"{Field:city ParseTimestamp:false TagName:location MetricName:}")
+	f("t:time:custom:2006-01-02 15:04:05,v:metric:foo:bar", "{Field:t ParseTimestamp:true TagName: MetricName:}"+
+		"{Field:v ParseTimestamp:false TagName: MetricName:foo:bar}")
+}
+
+func TestParseFieldDescriptorsFailure(t *testing.T) {
+	f := func(s string) {
+		t.Helper()
+		fds, err := ParseFieldDescriptors(s)
+		if err == nil {
+			t.Fatalf("expecting non-nil error for ParseFieldDescriptors(%q)", s)
+		}
+		if fds != nil {
+			t.Fatalf("expecting nil fds; got %v", fds)
+		}
+	}
+	f("")
+	f(",")
+	f("foo")
+	f("foo:metric")
+	f(":metric:foo")
+	f("foo:metric:")
+	f("foo:label:,bar:metric:x")
+	f("foo:bar:baz")
+
+	// missing metric field
+	f("foo:label:bar")
+	f("ts:time:unix_s")
+
+	// invalid time format
+	f("ts:time:foobar,x:metric:y")
+
+	// duplicate time fields
+	f("ts1:time:unix_s,ts2:time:unix_ms,x:metric:y")
+
+	// duplicate fields
+	f("x:metric:y,x:label:z")
+}
@@ -0,0 +1,202 @@
+package jsonlimport
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/metrics"
+	"github.com/valyala/fastjson"
+	"github.com/valyala/fastjson/fastfloat"
+)
+
+// Rows represents rows parsed from JSON lines.
+type Rows struct {
+	// Rows contains parsed rows after the call to Unmarshal.
+	Rows []Row
+
+	p         fastjson.Parser
+	tagsPool  []Tag
+	bytesPool []byte
+	buf       []byte
+}
+
+// Reset resets rs.
+func (rs *Rows) Reset() {
+	rows := rs.Rows
+	for i := range rows {
+		r := &rows[i]
+		r.Metric = ""
+		r.Tags = nil
+		r.Value = 0
+		r.Timestamp = 0
+	}
+	rs.Rows = rs.Rows[:0]
+
+	tags := rs.tagsPool
+	for i := range tags {
+		t := &tags[i]
+		t.Key = ""
+		t.Value = ""
+	}
+	rs.tagsPool = rs.tagsPool[:0]
+
+	rs.bytesPool = rs.bytesPool[:0]
+	rs.buf = rs.buf[:0]
+}
+
+// Row represents a single metric row
+type Row struct {
+	Metric    string
+	Tags      []Tag
+	Value     float64
+	Timestamp int64
+}
+
+// Tag represents metric tag
+type Tag struct {
+	Key   string
+	Value string
+}
+
+// Unmarshal unmarshals JSON lines from s according to the given fds.
+//
+// Every line must contain JSON object. Fields missing in fds are ignored.
+//
+// s shouldn't be modified when rs is in use.
+func (rs *Rows) Unmarshal(s string, fds []FieldDescriptor) {
+	for len(s) > 0 {
+		line := s
+		s = ""
+		if n := strings.IndexByte(line, '\n'); n >= 0 {
+			s = line[n+1:]
+			line = line[:n]
+		}
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			// Skip empty line
+			continue
+		}
+		rowsLen := len(rs.Rows)
+		tagsPoolLen := len(rs.tagsPool)
+		bytesPoolLen := len(rs.bytesPool)
+		if err := rs.unmarshalLine(line, fds); err != nil {
+			rs.Rows = rs.Rows[:rowsLen]
+			rs.tagsPool = rs.tagsPool[:tagsPoolLen]
+			rs.bytesPool = rs.bytesPool[:bytesPoolLen]
+			logger.Errorf("error when parsing json line %q: %s; skipping this line", line, err)
+			invalidLines.Inc()
+		}
+	}
+}
+
+func (rs *Rows) unmarshalLine(line string, fds []FieldDescriptor) error {
+	v, err := rs.p.Parse(line)
+	if err != nil {
+		return fmt.Errorf("cannot parse json line: %w", err)
+	}
+	o, err := v.Object()
+	if err != nil {
+		return err
+	}
+	rowsLen := len(rs.Rows)
+	tagsLen := len(rs.tagsPool)
+	var timestamp int64
+	for i := range fds {
+		fd := &fds[i]
+		fv := o.Get(fd.Field)
+		if fv == nil || fv.Type() == fastjson.TypeNull {
+			// Ignore missing field.
+			continue
+		}
+		if parseTimestamp := fd.ParseTimestamp; parseTimestamp != nil {
+			s, err := rs.getString(fv)
+			if err != nil {
+				return fmt.Errorf("cannot parse timestamp from field %q: %w", fd.Field, err)
+			}
+			timestamp, err = parseTimestamp(s)
+			if err != nil {
+				return fmt.Errorf("cannot parse timestamp from field %q: %w", fd.Field, err)
+			}
+			continue
+		}
+		if tagName := fd.TagName; tagName != "" {
+			s, err := rs.getString(fv)
+			if err != nil {
+				return fmt.Errorf("cannot parse label %q value from field %q: %w", tagName, fd.Field, err)
+			}
+			if len(s) == 0 {
+				// Skip empty label.
+				continue
+			}
+			rs.tagsPool = append(rs.tagsPool, Tag{
+				Key:   tagName,
+				Value: rs.addString(s),
+			})
+			continue
+		}
+		value, err := getValue(fv)
+		if err != nil {
+			return fmt.Errorf("cannot parse metric value for %q from field %q: %w", fd.MetricName, fd.Field, err)
+		}
+		rs.Rows = append(rs.Rows, Row{
+			Metric: fd.MetricName,
+			Value:  value,
+		})
+	}
+	var tags []Tag
+	if len(rs.tagsPool) > tagsLen {
+		tags = rs.tagsPool[tagsLen:]
+		tags = tags[:len(tags):len(tags)]
+	}
+	rows := rs.Rows[rowsLen:]
+	for i := range rows {
+		r := &rows[i]
+		r.Tags = tags
+		r.Timestamp = timestamp
+	}
+	return nil
+}
+
+// getString returns string representation for fv.
+//
+// The returned string is valid until the next call to getString.
+func (rs *Rows) getString(fv *fastjson.Value) (string, error) {
+	switch fv.Type() {
+	case fastjson.TypeString:
+		b := fv.GetStringBytes()
+		return bytesutil.ToUnsafeString(b), nil
+	case fastjson.TypeNumber, fastjson.TypeTrue, fastjson.TypeFalse:
+		rs.buf = fv.MarshalTo(rs.buf[:0])
+		return bytesutil.ToUnsafeString(rs.buf), nil
+	default:
+		return "", fmt.Errorf("unsupported value type: %s; value=%s", fv.Type(), fv)
+	}
+}
+
+// addString copies s to rs.bytesPool, so it remains valid after parsing the next line.
+func (rs *Rows) addString(s string) string {
+	bytesPoolLen := len(rs.bytesPool)
+	rs.bytesPool = append(rs.bytesPool, s...)
+	b := rs.bytesPool[bytesPoolLen:]
+	return bytesutil.ToUnsafeString(b[:len(b):len(b)])
+}
+
+func getValue(fv *fastjson.Value) (float64, error) {
+	switch fv.Type() {
+	case fastjson.TypeNumber:
+		return fv.Float64()
+	case fastjson.TypeString:
+		b := fv.GetStringBytes()
+		return fastfloat.Parse(bytesutil.ToUnsafeString(b))
+	case fastjson.TypeTrue:
+		return 1, nil
+	case fastjson.TypeFalse:
+		return 0, nil
+	default:
+		return 0, fmt.Errorf("unsupported value type: %s; value=%s", fv.Type(), fv)
+	}
+}
+
+var invalidLines = metrics.NewCounter(`vm_rows_invalid_total{type="jsonlimport"}`)
@@ -0,0 +1,145 @@
+package jsonlimport
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRowsUnmarshalFailure(t *testing.T) {
+	f := func(format, s string) {
+		t.Helper()
+		fds, err := ParseFieldDescriptors(format)
+		if err != nil {
+			t.Fatalf("unexpected error when parsing %q: %s", format, err)
+		}
+		var rs Rows
+		rs.Unmarshal(s, fds)
+		if len(rs.Rows) != 0 {
+			t.Fatalf("expecting zero rows; got %d rows: %v", len(rs.Rows), rs.Rows)
+		}
+
+		// Try again
+		rs.Unmarshal(s, fds)
+		if len(rs.Rows) != 0 {
+			t.Fatalf("expecting zero rows; got %d rows: %v", len(rs.Rows), rs.Rows)
+		}
+	}
+
+	// invalid json
+	f("v:metric:x", `{"v":1`)
+	f("v:metric:x", `foobar`)
+
+	// non-object json
+	f("v:metric:x", `[1,2]`)
+	f("v:metric:x", `123`)
+
+	// invalid metric value
+	f("v:metric:x", `{"v":"foo"}`)
+	f("v:metric:x", `{"v":{"a":1}}`)
+
+	// invalid label value
+	f("v:metric:x,l:label:y", `{"v":1,"l":[1]}`)
+
+	// invalid timestamp
+	f("v:metric:x,t:time:unix_s", `{"v":1,"t":"foobar"}`)
+	f("v:metric:x,t:time:rfc3339", `{"v":1,"t":1234}`)
+
+	// missing metric fields
+	f("v:metric:x,l:label:y", `{"l":"foo"}`)
+	f("v:metric:x", `{"v":null}`)
+}
+
+func TestRowsUnmarshalSuccess(t *testing.T) {
+	f := func(format, s string, rowsExpected []Row) {
+		t.Helper()
+		fds, err := ParseFieldDescriptors(format)
+		if err != nil {
+			t.Fatalf("unexpected error when parsing %q: %s", format, err)
+		}
+		var rs Rows
+		rs.Unmarshal(s, fds)
+		if !reflect.DeepEqual(rs.Rows, rowsExpected) {
+			t.Fatalf("unexpected rows;\ngot\n%+v\nwant\n%+v", rs.Rows, rowsExpected)
+		}
+
+		// Try again
+		rs.Reset()
+		rs.Unmarshal(s, fds)
+		if !reflect.DeepEqual(rs.Rows, rowsExpected) {
+			t.Fatalf("unexpected rows on the second call;\ngot\n%+v\nwant\n%+v", rs.Rows, rowsExpected)
+		}
+	}
+
+	// empty lines
+	f("v:metric:x", "", nil)
+	f("v:metric:x", "\n  \n\r\n", nil)
+
+	// single metric
+	f("v:metric:x", `{"v":1.5}`, []Row{{
+		Metric: "x",
+		Value:  1.5,
+	}})
+
+	// multiple metrics with labels and timestamp
+	f("ts:time:unix_ms,temp:metric:temperature,hum:metric:humidity,city:label:city,id:label:sensor_id",
+		`{"ts":1700000000123,"temp":"-3.5","hum":40,"city":"Paris","id":42,"extra":{"foo":"bar"}}`, []Row{
+			{
+				Metric: "temperature",
+				Tags: []Tag{
+					{
+						Key:   "city",
+						Value: "Paris",
+					},
+					{
+						Key:   "sensor_id",
+						Value: "42",
+					},
+				},
+				Value:     -3.5,
+				Timestamp: 1700000000123,
+			},
+			{
+				Metric: "humidity",
+				Tags: []Tag{
+					{
+						Key:   "city",
+						Value: "Paris",
+					},
+					{
+						Key:   "sensor_id",
+						Value: "42",
+					},
+				},
+				Value:     40,
+				Timestamp: 1700000000123,
+			},
+		})
+
+	// missing and empty fields are ignored, bool values are supported
+	f("up:metric:up,temp:metric:temperature,host:label:host,dc:label:dc,t:time:rfc3339",
+		`{"up":true,"host":"","dc":null,"t":"2023-11-14T22:13:20Z"}
+{"up":false,"host":"foo","temp":12}
+{"up":"foo"}`, []Row{
+			{
+				Metric:    "up",
+				Value:     1,
+				Timestamp: 1700000000000,
+			},
+			{
+				Metric: "up",
+				Tags: []Tag{{
+					Key:   "host",
+					Value: "foo",
+				}},
+				Value: 0,
+			},
+			{
+				Metric: "temperature",
+				Tags: []Tag{{
+					Key:   "host",
+					Value: "foo",
+				}},
+				Value: 12,
+			},
+		})
+}
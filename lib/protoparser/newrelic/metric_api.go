@@ -0,0 +1,249 @@
+package newrelic
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/valyala/fastjson"
+)
+
+// UnmarshalMetricAPI parses NewRelic Metric API request from b to r.
+//
+// See https://docs.newrelic.com/docs/data-apis/ingest-apis/metric-api/report-metrics-metric-api/
+//
+// b can be re-used after returning from r.
+func (r *Rows) UnmarshalMetricAPI(b []byte) error {
+	p := jsonParserPool.Get()
+	defer jsonParserPool.Put(p)
+
+	r.Reset()
+	v, err := p.ParseBytes(b)
+	if err != nil {
+		return err
+	}
+	payloads, err := v.Array()
+	if err != nil {
+		return fmt.Errorf("cannot find the top-level array of metric payloads: %w", err)
+	}
+	for _, payload := range payloads {
+		if err := r.unmarshalMetricAPIPayload(payload); err != nil {
+			return fmt.Errorf("cannot parse metric payload: %w", err)
+		}
+	}
+	return nil
+}
+
+func (r *Rows) unmarshalMetricAPIPayload(v *fastjson.Value) error {
+	o, err := v.Object()
+	if err != nil {
+		return fmt.Errorf("cannot find payload object: %w", err)
+	}
+	var common metricAPIBlock
+	if vCommon := o.Get("common"); vCommon != nil {
+		if err := common.unmarshal(vCommon); err != nil {
+			return fmt.Errorf("cannot parse `common` block: %w", err)
+		}
+	}
+	vMetrics := o.Get("metrics")
+	if vMetrics == nil {
+		return nil
+	}
+	metrics, err := vMetrics.Array()
+	if err != nil {
+		return fmt.Errorf("cannot find `metrics` array: %w", err)
+	}
+	rows := r.Rows
+	for _, m := range metrics {
+		if cap(rows) > len(rows) {
+			rows = rows[:len(rows)+1]
+		} else {
+			rows = append(rows, Row{})
+		}
+		row := &rows[len(rows)-1]
+		if err := row.unmarshalMetricAPI(m, &common); err != nil {
+			r.Rows = rows[:len(rows)-1]
+			return fmt.Errorf("cannot parse metric: %w", err)
+		}
+	}
+	r.Rows = rows
+	return nil
+}
+
+// metricAPIBlock contains fields shared by the `common` block and metric objects in NewRelic Metric API.
+type metricAPIBlock struct {
+	timestamp  int64
+	intervalMs int64
+	attributes *fastjson.Object
+}
+
+func (b *metricAPIBlock) unmarshal(v *fastjson.Value) error {
+	o, err := v.Object()
+	if err != nil {
+		return err
+	}
+	if vTimestamp := o.Get("timestamp"); vTimestamp != nil {
+		ts, err := getFloat64(vTimestamp)
+		if err != nil {
+			return fmt.Errorf("cannot parse `timestamp` field: %w", err)
+		}
+		if ts < (1 << 32) {
+			// The timestamp is in seconds. Convert it to milliseconds.
+			ts *= 1e3
+		}
+		b.timestamp = int64(ts)
+	}
+	if vInterval := o.Get("interval.ms"); vInterval != nil {
+		interval, err := getFloat64(vInterval)
+		if err != nil {
+			return fmt.Errorf("cannot parse `interval.ms` field: %w", err)
+		}
+		if interval < 0 {
+			return fmt.Errorf("`interval.ms` field cannot be negative; got %v", interval)
+		}
+		b.intervalMs = int64(interval)
+	}
+	if vAttributes := o.Get("attributes"); vAttributes != nil {
+		attributes, err := vAttributes.Object()
+		if err != nil {
+			return fmt.Errorf("cannot find `attributes` object: %w", err)
+		}
+		b.attributes = attributes
+	}
+	return nil
+}
+
+func (r *Row) unmarshalMetricAPI(v *fastjson.Value, common *metricAPIBlock) error {
+	r.reset()
+
+	o, err := v.Object()
+	if err != nil {
+		return fmt.Errorf("cannot find metric object: %w", err)
+	}
+	var mb metricAPIBlock
+	if err := mb.unmarshal(v); err != nil {
+		return err
+	}
+	name := v.GetStringBytes("name")
+	if len(name) == 0 {
+		return fmt.Errorf("missing `name` field")
+	}
+
+	// Attributes from the metric override attributes from the common block.
+	r.addMetricAPIAttributes(common.attributes)
+	r.addMetricAPIAttributes(mb.attributes)
+
+	timestamp := mb.timestamp
+	if timestamp == 0 {
+		timestamp = common.timestamp
+	}
+	intervalMs := mb.intervalMs
+	if intervalMs == 0 {
+		intervalMs = common.intervalMs
+	}
+
+	vValue := o.Get("value")
+	if vValue == nil {
+		return fmt.Errorf("missing `value` field for metric %q", name)
+	}
+	metricType := v.GetStringBytes("type")
+	isIntervalMetric := false
+	switch string(metricType) {
+	case "", "gauge", "count":
+		isIntervalMetric = string(metricType) == "count"
+		value, err := getFloat64(vValue)
+		if err != nil {
+			return fmt.Errorf("cannot parse `value` field for metric %q: %w", name, err)
+		}
+		r.addSample(name, "", value)
+	case "summary":
+		summary, err := vValue.Object()
+		if err != nil {
+			return fmt.Errorf("cannot find `value` object for summary %q: %w", name, err)
+		}
+		for _, field := range []string{"count", "sum", "min", "max"} {
+			vField := summary.Get(field)
+			if vField == nil || vField.Type() == fastjson.TypeNull {
+				continue
+			}
+			value, err := getFloat64(vField)
+			if err != nil {
+				return fmt.Errorf("cannot parse `%s` field for summary %q: %w", field, name, err)
+			}
+			r.addSample(name, field, value)
+		}
+		isIntervalMetric = true
+	default:
+		return fmt.Errorf("unsupported `type` %q for metric %q; supported types: gauge, count, summary", metricType, name)
+	}
+
+	if isIntervalMetric && timestamp > 0 {
+		// The timestamp for count and summary metrics points to the start of the interval,
+		// while the value is accumulated over the interval.
+		// Store the value at the end of the interval.
+		timestamp += intervalMs
+	}
+	r.Timestamp = timestamp
+	return nil
+}
+
+func (r *Row) addMetricAPIAttributes(attributes *fastjson.Object) {
+	if attributes == nil {
+		return
+	}
+	attributes.Visit(func(k []byte, v *fastjson.Value) {
+		if len(k) == 0 {
+			return
+		}
+		var value []byte
+		switch v.Type() {
+		case fastjson.TypeString:
+			value = v.GetStringBytes()
+		case fastjson.TypeNumber:
+			value = strconv.AppendFloat(nil, v.GetFloat64(), 'g', -1, 64)
+		case fastjson.TypeTrue:
+			value = []byte("true")
+		case fastjson.TypeFalse:
+			value = []byte("false")
+		default:
+			// Ignore unsupported attribute types such as objects and arrays.
+			return
+		}
+		if len(value) == 0 {
+			return
+		}
+		tags := r.Tags
+		for i := range tags {
+			t := &tags[i]
+			if string(t.Key) == string(k) {
+				t.Value = append(t.Value[:0], value...)
+				return
+			}
+		}
+		if cap(tags) > len(tags) {
+			tags = tags[:len(tags)+1]
+		} else {
+			tags = append(tags, Tag{})
+		}
+		t := &tags[len(tags)-1]
+		t.Key = append(t.Key[:0], k...)
+		t.Value = append(t.Value[:0], value...)
+		r.Tags = tags
+	})
+}
+
+func (r *Row) addSample(name []byte, suffix string, value float64) {
+	samples := r.Samples
+	if cap(samples) > len(samples) {
+		samples = samples[:len(samples)+1]
+	} else {
+		samples = append(samples, Sample{})
+	}
+	s := &samples[len(samples)-1]
+	s.Name = append(s.Name[:0], name...)
+	if len(suffix) > 0 {
+		s.Name = append(s.Name, '_')
+		s.Name = append(s.Name, suffix...)
+	}
+	s.Value = value
+	r.Samples = samples
+}
@@ -0,0 +1,201 @@
+package newrelic
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRowsUnmarshalMetricAPIFailure(t *testing.T) {
+	f := func(data string) {
+		t.Helper()
+
+		var r Rows
+		if err := r.UnmarshalMetricAPI([]byte(data)); err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	// Empty JSON
+	f("")
+
+	// Invalid JSON
+	f("123")
+	f("[foo]")
+	f(`{"metrics":[]}`)
+	f(`[{"metrics":{}}]`)
+
+	// Missing name
+	f(`[{"metrics":[{"type":"gauge","value":1}]}]`)
+
+	// Missing value
+	f(`[{"metrics":[{"name":"foo","type":"gauge"}]}]`)
+
+	// Invalid value
+	f(`[{"metrics":[{"name":"foo","type":"gauge","value":"bar"}]}]`)
+	f(`[{"metrics":[{"name":"foo","type":"summary","value":1}]}]`)
+
+	// Unsupported type
+	f(`[{"metrics":[{"name":"foo","type":"distribution","value":1}]}]`)
+
+	// Invalid common block
+	f(`[{"common":{"timestamp":"foo"},"metrics":[]}]`)
+	f(`[{"common":{"interval.ms":-1},"metrics":[]}]`)
+	f(`[{"common":{"attributes":[]},"metrics":[]}]`)
+}
+
+func TestRowsUnmarshalMetricAPISuccess(t *testing.T) {
+	f := func(data string, expectedRows []Row) {
+		t.Helper()
+
+		var r Rows
+		if err := r.UnmarshalMetricAPI([]byte(data)); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(r.Rows, expectedRows) {
+			t.Fatalf("unexpected rows parsed\ngot\n%s\nwant\n%s", rowsToString(r.Rows), rowsToString(expectedRows))
+		}
+
+		// Try unmarshaling again
+		if err := r.UnmarshalMetricAPI([]byte(data)); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(r.Rows, expectedRows) {
+			t.Fatalf("unexpected rows parsed\ngot\n%s\nwant\n%s", rowsToString(r.Rows), rowsToString(expectedRows))
+		}
+	}
+
+	// empty array
+	f(`[]`, nil)
+
+	// zero metrics
+	f(`[{"common":{"timestamp":1690286061000},"metrics":[]}]`, nil)
+
+	// gauge without type and timestamp
+	f(`[{"metrics":[{"name":"memory.heap","value":2.3}]}]`, []Row{
+		{
+			Samples: []Sample{
+				{
+					Name:  []byte("memory.heap"),
+					Value: 2.3,
+				},
+			},
+		},
+	})
+
+	// gauge, count and summary with common block
+	f(`[{
+      "common":{
+        "timestamp":1690286061000,
+        "interval.ms":10000,
+        "attributes":{
+          "app.name":"foo",
+          "host.name":"bar",
+          "empty":""
+        }
+      },
+      "metrics":[
+        {
+          "name":"memory.heap",
+          "type":"gauge",
+          "value":2.3,
+          "timestamp":1690286062,
+          "attributes":{
+            "host.name":"baz",
+            "pid":1234,
+            "leader":true,
+            "nested":{"foo":"bar"}
+          }
+        },
+        {
+          "name":"service.errors.all",
+          "type":"count",
+          "value":15,
+          "attributes":{"error.type":"timeout"}
+        },
+        {
+          "name":"service.response.duration",
+          "type":"summary",
+          "value":{"count":5,"sum":0.004,"min":0.0005,"max":null},
+          "interval.ms":5000
+        }
+      ]
+    }]`, []Row{
+		{
+			Tags: []Tag{
+				{
+					Key:   []byte("app.name"),
+					Value: []byte("foo"),
+				},
+				{
+					Key:   []byte("host.name"),
+					Value: []byte("baz"),
+				},
+				{
+					Key:   []byte("pid"),
+					Value: []byte("1234"),
+				},
+				{
+					Key:   []byte("leader"),
+					Value: []byte("true"),
+				},
+			},
+			Samples: []Sample{
+				{
+					Name:  []byte("memory.heap"),
+					Value: 2.3,
+				},
+			},
+			Timestamp: 1690286062000,
+		},
+		{
+			Tags: []Tag{
+				{
+					Key:   []byte("app.name"),
+					Value: []byte("foo"),
+				},
+				{
+					Key:   []byte("host.name"),
+					Value: []byte("bar"),
+				},
+				{
+					Key:   []byte("error.type"),
+					Value: []byte("timeout"),
+				},
+			},
+			Samples: []Sample{
+				{
+					Name:  []byte("service.errors.all"),
+					Value: 15,
+				},
+			},
+			Timestamp: 1690286071000,
+		},
+		{
+			Tags: []Tag{
+				{
+					Key:   []byte("app.name"),
+					Value: []byte("foo"),
+				},
+				{
+					Key:   []byte("host.name"),
+					Value: []byte("bar"),
+				},
+			},
+			Samples: []Sample{
+				{
+					Name:  []byte("service.response.duration_count"),
+					Value: 5,
+				},
+				{
+					Name:  []byte("service.response.duration_sum"),
+					Value: 0.004,
+				},
+				{
+					Name:  []byte("service.response.duration_min"),
+					Value: 0.0005,
+				},
+			},
+			Timestamp: 1690286066000,
+		},
+	})
+}
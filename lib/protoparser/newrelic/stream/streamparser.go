@@ -18,13 +18,24 @@ import (
 
 var (
 	maxInsertRequestSize = flagutil.NewBytes("newrelic.maxInsertRequestSize", 64*1024*1024, "The maximum size in bytes of a single NewRelic request "+
-		"to /newrelic/infra/v2/metrics/events/bulk")
+		"to /newrelic/infra/v2/metrics/events/bulk and /newrelic/metric/v1")
 )
 
 // Parse parses NewRelic POST request for /newrelic/infra/v2/metrics/events/bulk from r and calls callback for the parsed request.
 //
 // callback shouldn't hold rows after returning.
 func Parse(r io.Reader, isGzip bool, callback func(rows []newrelic.Row) error) error {
+	return parse(r, isGzip, (*newrelic.Rows).Unmarshal, callback)
+}
+
+// ParseMetricAPI parses NewRelic Metric API POST request for /newrelic/metric/v1 from r and calls callback for the parsed request.
+//
+// callback shouldn't hold rows after returning.
+func ParseMetricAPI(r io.Reader, isGzip bool, callback func(rows []newrelic.Row) error) error {
+	return parse(r, isGzip, (*newrelic.Rows).UnmarshalMetricAPI, callback)
+}
+
+func parse(r io.Reader, isGzip bool, unmarshal func(rows *newrelic.Rows, b []byte) error, callback func(rows []newrelic.Row) error) error {
 	wcr := writeconcurrencylimiter.GetReader(r)
 	defer writeconcurrencylimiter.PutReader(wcr)
 	r = wcr
@@ -47,7 +58,7 @@ func Parse(r io.Reader, isGzip bool, callback func(rows []newrelic.Row) error) e
 	rows := getRows()
 	defer putRows(rows)
 
-	if err := rows.Unmarshal(ctx.reqBuf.B); err != nil {
+	if err := unmarshal(rows, ctx.reqBuf.B); err != nil {
 		unmarshalErrors.Inc()
 		return fmt.Errorf("cannot unmarshal NewRelic request: %w", err)
 	}
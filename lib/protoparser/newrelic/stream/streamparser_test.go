@@ -104,3 +104,34 @@ func TestParseSuccess(t *testing.T) {
 		},
 	})
 }
+
+func TestParseMetricAPISuccess(t *testing.T) {
+	req := `[{"common":{"attributes":{"host.name":"foo"}},"metrics":[{"name":"memory.heap","value":2.3,"timestamp":1690286061000}]}]`
+	expectedRows := []newrelic.Row{
+		{
+			Tags: []newrelic.Tag{
+				{
+					Key:   []byte("host.name"),
+					Value: []byte("foo"),
+				},
+			},
+			Samples: []newrelic.Sample{
+				{
+					Name:  []byte("memory.heap"),
+					Value: 2.3,
+				},
+			},
+			Timestamp: 1690286061000,
+		},
+	}
+	callback := func(rows []newrelic.Row) error {
+		if !reflect.DeepEqual(rows, expectedRows) {
+			return fmt.Errorf("unexpected rows\ngot\n%v\nwant\n%v", rows, expectedRows)
+		}
+		return nil
+	}
+	r := bytes.NewReader([]byte(req))
+	if err := ParseMetricAPI(r, false, callback); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
@@ -0,0 +1,293 @@
+package arrow
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// dataType is the type of Arrow column.
+type dataType int
+
+const (
+	typeNull = dataType(iota)
+	typeInt
+	typeFloat
+	typeBool
+	typeBinary
+	typeFixedSizeBinary
+	typeList
+	typeStruct
+)
+
+// field describes Arrow column.
+//
+// See https://github.com/apache/arrow/blob/main/format/Schema.fbs
+type field struct {
+	name string
+	typ  dataType
+
+	// bitWidth is the width in bits for typeInt, typeFloat and typeFixedSizeBinary values.
+	bitWidth int
+
+	// signed is set to true for signed typeInt values.
+	signed bool
+
+	// children contains child fields for typeList and typeStruct.
+	children []*field
+
+	// isDict is set to true if the column is dictionary-encoded.
+	isDict bool
+
+	// dictID is the id of the dictionary for the dictionary-encoded column.
+	dictID int64
+
+	// indexBitWidth and indexSigned describe indexes for the dictionary-encoded column.
+	indexBitWidth int
+	indexSigned   bool
+
+	// encoding is the value for the optional "encoding" key from the field metadata.
+	//
+	// OTel Arrow uses it for describing the encoding of id and parent_id columns.
+	encoding string
+}
+
+// valueField returns the field for dictionary values if f is dictionary-encoded.
+func (f *field) valueField() *field {
+	vf := *f
+	vf.isDict = false
+	return &vf
+}
+
+// column contains Arrow column data.
+//
+// All the methods return zero values for nil column, so missing optional columns may be handled as columns with null values.
+type column struct {
+	field  *field
+	length int
+
+	// validity is a bitmap with non-null values. It is empty if all the values are non-null.
+	validity []byte
+
+	// values contains primitive values or dictionary indexes.
+	values []byte
+
+	// offsets contains int32 offsets for typeBinary and typeList values.
+	offsets []byte
+
+	// data contains the data for typeBinary values.
+	data []byte
+
+	// children contains child columns for typeList and typeStruct.
+	children []*column
+
+	// dict contains values for dictionary-encoded column.
+	dict *dictionary
+}
+
+// Len returns the number of values in c.
+func (c *column) Len() int {
+	if c == nil {
+		return 0
+	}
+	return c.length
+}
+
+// IsNull returns true if the value at index i is null.
+func (c *column) IsNull(i int) bool {
+	if c == nil || i < 0 || i >= c.length {
+		return true
+	}
+	if len(c.validity) > 0 && c.validity[i/8]&(1<<(i%8)) == 0 {
+		return true
+	}
+	if c.field.isDict {
+		dc, idx := c.dictValue(i)
+		return dc.IsNull(idx)
+	}
+	return c.field.typ == typeNull
+}
+
+// Child returns the child column with the given name for struct column.
+//
+// nil is returned if c doesn't contain the child column with the given name.
+func (c *column) Child(name string) *column {
+	if c == nil {
+		return nil
+	}
+	for i, f := range c.field.children {
+		if f.name == name && i < len(c.children) {
+			return c.children[i]
+		}
+	}
+	return nil
+}
+
+// ListRange returns the range for list items at index i.
+//
+// The list items are located at c.ListValues() column.
+func (c *column) ListRange(i int) (int, int) {
+	if c.IsNull(i) || c.field.typ != typeList {
+		return 0, 0
+	}
+	start, end := c.offsetsAt(i)
+	if end > c.ListValues().Len() {
+		return 0, 0
+	}
+	return start, end
+}
+
+// ListValues returns list items for list column.
+func (c *column) ListValues() *column {
+	if c == nil || c.field.typ != typeList || len(c.children) == 0 {
+		return nil
+	}
+	return c.children[0]
+}
+
+// Uint64 returns integer value at index i as uint64.
+func (c *column) Uint64(i int) uint64 {
+	return uint64(c.Int64(i))
+}
+
+// Int64 returns integer value at index i.
+func (c *column) Int64(i int) int64 {
+	if c.IsNull(i) {
+		return 0
+	}
+	if c.field.isDict {
+		dc, idx := c.dictValue(i)
+		return dc.Int64(idx)
+	}
+	if c.field.typ != typeInt {
+		return 0
+	}
+	return readInt(c.values, i, c.field.bitWidth, c.field.signed)
+}
+
+// Float64 returns floating-point value at index i.
+func (c *column) Float64(i int) float64 {
+	if c.IsNull(i) {
+		return 0
+	}
+	if c.field.isDict {
+		dc, idx := c.dictValue(i)
+		return dc.Float64(idx)
+	}
+	switch {
+	case c.field.typ == typeFloat && c.field.bitWidth == 64:
+		return math.Float64frombits(binary.LittleEndian.Uint64(c.values[i*8:]))
+	case c.field.typ == typeFloat && c.field.bitWidth == 32:
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(c.values[i*4:])))
+	default:
+		return 0
+	}
+}
+
+// Bool returns bool value at index i.
+func (c *column) Bool(i int) bool {
+	if c.IsNull(i) {
+		return false
+	}
+	if c.field.isDict {
+		dc, idx := c.dictValue(i)
+		return dc.Bool(idx)
+	}
+	if c.field.typ != typeBool {
+		return false
+	}
+	return c.values[i/8]&(1<<(i%8)) != 0
+}
+
+// Bytes returns binary value at index i.
+//
+// The returned value is valid until the underlying record is in use.
+func (c *column) Bytes(i int) []byte {
+	if c.IsNull(i) {
+		return nil
+	}
+	if c.field.isDict {
+		dc, idx := c.dictValue(i)
+		return dc.Bytes(idx)
+	}
+	switch c.field.typ {
+	case typeBinary:
+		start, end := c.offsetsAt(i)
+		if end > len(c.data) {
+			return nil
+		}
+		return c.data[start:end]
+	case typeFixedSizeBinary:
+		n := c.field.bitWidth / 8
+		return c.values[i*n : (i+1)*n]
+	default:
+		return nil
+	}
+}
+
+// String returns string value at index i.
+func (c *column) String(i int) string {
+	return string(c.Bytes(i))
+}
+
+func (c *column) offsetsAt(i int) (int, int) {
+	start := int(int32(binary.LittleEndian.Uint32(c.offsets[i*4:])))
+	end := int(int32(binary.LittleEndian.Uint32(c.offsets[(i+1)*4:])))
+	if start < 0 || end < start {
+		return 0, 0
+	}
+	return start, end
+}
+
+func (c *column) dictValue(i int) (*column, int) {
+	idx := readInt(c.values, i, c.field.indexBitWidth, c.field.indexSigned)
+	return c.dict.get(int(idx))
+}
+
+func readInt(b []byte, i, bitWidth int, signed bool) int64 {
+	switch bitWidth {
+	case 8:
+		if signed {
+			return int64(int8(b[i]))
+		}
+		return int64(b[i])
+	case 16:
+		v := binary.LittleEndian.Uint16(b[i*2:])
+		if signed {
+			return int64(int16(v))
+		}
+		return int64(v)
+	case 32:
+		v := binary.LittleEndian.Uint32(b[i*4:])
+		if signed {
+			return int64(int32(v))
+		}
+		return int64(v)
+	case 64:
+		return int64(binary.LittleEndian.Uint64(b[i*8:]))
+	default:
+		return 0
+	}
+}
+
+// dictionary contains values for dictionary-encoded columns.
+//
+// Dictionary values may be split into multiple chunks if delta dictionary batches are used.
+type dictionary struct {
+	chunks []*column
+}
+
+// get returns the column and the index in this column for the dictionary value at index idx.
+//
+// nil column is returned if idx is out of range.
+func (d *dictionary) get(idx int) (*column, int) {
+	if d == nil || idx < 0 {
+		return nil, 0
+	}
+	for _, c := range d.chunks {
+		if idx < c.length {
+			return c, idx
+		}
+		idx -= c.length
+	}
+	return nil, 0
+}
@@ -0,0 +1,218 @@
+package arrow
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// fbBuf is a buffer with flatbuffers-encoded data.
+//
+// Out of bounds reads return zero values and set err, so the caller must check err after reading all the needed data.
+// See https://flatbuffers.dev/md__internals.html for the encoding details.
+type fbBuf struct {
+	b   []byte
+	err error
+}
+
+func (fb *fbBuf) setErr(format string, args ...any) {
+	if fb.err == nil {
+		fb.err = fmt.Errorf(format, args...)
+	}
+}
+
+func (fb *fbBuf) uint8At(pos int) uint8 {
+	if pos < 0 || pos+1 > len(fb.b) {
+		fb.setErr("cannot read uint8 at offset %d from %d bytes", pos, len(fb.b))
+		return 0
+	}
+	return fb.b[pos]
+}
+
+func (fb *fbBuf) uint16At(pos int) uint16 {
+	if pos < 0 || pos+2 > len(fb.b) {
+		fb.setErr("cannot read uint16 at offset %d from %d bytes", pos, len(fb.b))
+		return 0
+	}
+	return binary.LittleEndian.Uint16(fb.b[pos:])
+}
+
+func (fb *fbBuf) uint32At(pos int) uint32 {
+	if pos < 0 || pos+4 > len(fb.b) {
+		fb.setErr("cannot read uint32 at offset %d from %d bytes", pos, len(fb.b))
+		return 0
+	}
+	return binary.LittleEndian.Uint32(fb.b[pos:])
+}
+
+func (fb *fbBuf) uint64At(pos int) uint64 {
+	if pos < 0 || pos+8 > len(fb.b) {
+		fb.setErr("cannot read uint64 at offset %d from %d bytes", pos, len(fb.b))
+		return 0
+	}
+	return binary.LittleEndian.Uint64(fb.b[pos:])
+}
+
+// root returns the root table.
+func (fb *fbBuf) root() fbTable {
+	return fb.tableAt(int(fb.uint32At(0)))
+}
+
+// tableAt returns the table located at the given pos.
+func (fb *fbBuf) tableAt(pos int) fbTable {
+	vtable := pos - int(int32(fb.uint32At(pos)))
+	vtableLen := int(fb.uint16At(vtable))
+	if fb.err == nil && (vtableLen < 4 || vtable+vtableLen > len(fb.b)) {
+		fb.setErr("invalid vtable length %d at offset %d", vtableLen, vtable)
+	}
+	if fb.err != nil {
+		return fbTable{
+			fb: fb,
+		}
+	}
+	return fbTable{
+		fb:        fb,
+		pos:       pos,
+		vtable:    vtable,
+		vtableLen: vtableLen,
+	}
+}
+
+// fbTable is a flatbuffers table.
+type fbTable struct {
+	fb        *fbBuf
+	pos       int
+	vtable    int
+	vtableLen int
+}
+
+// fieldPos returns the position of the field with the given index.
+//
+// It returns 0 if the field is missing.
+func (t fbTable) fieldPos(idx int) int {
+	n := 4 + 2*idx
+	if n+2 > t.vtableLen {
+		return 0
+	}
+	off := int(t.fb.uint16At(t.vtable + n))
+	if off == 0 {
+		return 0
+	}
+	return t.pos + off
+}
+
+func (t fbTable) getUint8(idx int, defaultValue uint8) uint8 {
+	pos := t.fieldPos(idx)
+	if pos == 0 {
+		return defaultValue
+	}
+	return t.fb.uint8At(pos)
+}
+
+func (t fbTable) getBool(idx int) bool {
+	return t.getUint8(idx, 0) != 0
+}
+
+func (t fbTable) getInt16(idx int, defaultValue int16) int16 {
+	pos := t.fieldPos(idx)
+	if pos == 0 {
+		return defaultValue
+	}
+	return int16(t.fb.uint16At(pos))
+}
+
+func (t fbTable) getInt32(idx int, defaultValue int32) int32 {
+	pos := t.fieldPos(idx)
+	if pos == 0 {
+		return defaultValue
+	}
+	return int32(t.fb.uint32At(pos))
+}
+
+func (t fbTable) getInt64(idx int, defaultValue int64) int64 {
+	pos := t.fieldPos(idx)
+	if pos == 0 {
+		return defaultValue
+	}
+	return int64(t.fb.uint64At(pos))
+}
+
+// refPos returns the position of the object referenced by the field with the given idx.
+//
+// It returns 0 if the field is missing.
+func (t fbTable) refPos(idx int) int {
+	pos := t.fieldPos(idx)
+	if pos == 0 {
+		return 0
+	}
+	return pos + int(t.fb.uint32At(pos))
+}
+
+// getTable returns the table referenced by the field with the given idx.
+//
+// ok is set to false if the field is missing.
+func (t fbTable) getTable(idx int) (fbTable, bool) {
+	pos := t.refPos(idx)
+	if pos == 0 {
+		return fbTable{}, false
+	}
+	return t.fb.tableAt(pos), true
+}
+
+func (t fbTable) getString(idx int) string {
+	pos := t.refPos(idx)
+	if pos == 0 {
+		return ""
+	}
+	n := int(t.fb.uint32At(pos))
+	start := pos + 4
+	if t.fb.err != nil || n > len(t.fb.b)-start {
+		t.fb.setErr("cannot read string with length %d at offset %d", n, pos)
+		return ""
+	}
+	return string(t.fb.b[start : start+n])
+}
+
+// getVector returns the vector referenced by the field with the given idx.
+//
+// elemSize must contain the size of every vector element.
+func (t fbTable) getVector(idx, elemSize int) fbVector {
+	pos := t.refPos(idx)
+	if pos == 0 {
+		return fbVector{
+			fb: t.fb,
+		}
+	}
+	n := int(t.fb.uint32At(pos))
+	start := pos + 4
+	if t.fb.err != nil || n > (len(t.fb.b)-start)/elemSize {
+		t.fb.setErr("cannot read vector with %d items at offset %d", n, pos)
+		return fbVector{
+			fb: t.fb,
+		}
+	}
+	return fbVector{
+		fb:       t.fb,
+		start:    start,
+		n:        n,
+		elemSize: elemSize,
+	}
+}
+
+// fbVector is a flatbuffers vector.
+type fbVector struct {
+	fb       *fbBuf
+	start    int
+	n        int
+	elemSize int
+}
+
+// tableAt returns the table referenced by the vector item at index i.
+func (v fbVector) tableAt(i int) fbTable {
+	pos := v.start + i*v.elemSize
+	return v.fb.tableAt(pos + int(v.fb.uint32At(pos)))
+}
+
+// int64At returns the int64 at the given offset of the struct at index i.
+func (v fbVector) int64At(i, offset int) int64 {
+	return int64(v.fb.uint64At(v.start + i*v.elemSize + offset))
+}
@@ -0,0 +1,509 @@
+package arrow
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding/zstd"
+)
+
+// maxColumnLength is the maximum number of values in a single Arrow column.
+const maxColumnLength = 1 << 30
+
+// maxDecompressedBufferSize is the maximum size of a single decompressed buffer in Arrow record batch.
+const maxDecompressedBufferSize = 64 * 1024 * 1024
+
+// Record is Arrow record batch.
+type Record struct {
+	fields  []*field
+	columns []*column
+	length  int
+}
+
+// Len returns the number of rows in r.
+func (r *Record) Len() int {
+	return r.length
+}
+
+// Column returns the column with the given name.
+//
+// nil is returned if r doesn't contain the column with the given name.
+func (r *Record) Column(name string) *column {
+	for i, f := range r.fields {
+		if f.name == name {
+			return r.columns[i]
+		}
+	}
+	return nil
+}
+
+// StreamReader reads Arrow record batches in IPC streaming format.
+//
+// The schema and the dictionaries are preserved between ReadRecords calls,
+// so the stream may be passed to StreamReader in chunks split at message boundaries.
+//
+// See https://arrow.apache.org/docs/format/Columnar.html#ipc-streaming-format
+type StreamReader struct {
+	fields []*field
+
+	// dictFields contains fields for dictionary-encoded columns by dictionary id.
+	dictFields map[int64]*field
+
+	// dicts contains dictionaries by dictionary id.
+	dicts map[int64]*dictionary
+}
+
+// ReadRecords reads all the record batches from data.
+//
+// The returned records and the dictionaries read from data refer to data,
+// so data mustn't be modified while the returned records or sr are in use.
+func (sr *StreamReader) ReadRecords(data []byte) ([]*Record, error) {
+	var records []*Record
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, fmt.Errorf("cannot read message length from %d bytes", len(data))
+		}
+		metaLen := binary.LittleEndian.Uint32(data)
+		data = data[4:]
+		if metaLen == 0xffffffff {
+			// Continuation marker.
+			if len(data) < 4 {
+				return nil, fmt.Errorf("cannot read message length after continuation marker from %d bytes", len(data))
+			}
+			metaLen = binary.LittleEndian.Uint32(data)
+			data = data[4:]
+		}
+		if metaLen == 0 {
+			// End of stream.
+			break
+		}
+		if uint64(metaLen) > uint64(len(data)) {
+			return nil, fmt.Errorf("too big message metadata length: %d bytes; it cannot exceed %d bytes", metaLen, len(data))
+		}
+		meta := data[:metaLen]
+		data = data[metaLen:]
+
+		fb := &fbBuf{
+			b: meta,
+		}
+		msg := fb.root()
+		headerType := msg.getUint8(1, 0)
+		header, hasHeader := msg.getTable(2)
+		bodyLen := msg.getInt64(3, 0)
+		if fb.err != nil {
+			return nil, fmt.Errorf("cannot parse message metadata: %w", fb.err)
+		}
+		if bodyLen < 0 || bodyLen > int64(len(data)) {
+			return nil, fmt.Errorf("too big message body length: %d bytes; it cannot exceed %d bytes", bodyLen, len(data))
+		}
+		body := data[:bodyLen]
+		data = data[bodyLen:]
+		if !hasHeader {
+			return nil, fmt.Errorf("missing message header")
+		}
+
+		switch headerType {
+		case 1:
+			// Schema
+			if err := sr.readSchema(header); err != nil {
+				return nil, fmt.Errorf("cannot read schema: %w", err)
+			}
+		case 2:
+			// DictionaryBatch
+			if err := sr.readDictionaryBatch(header, body); err != nil {
+				return nil, fmt.Errorf("cannot read dictionary batch: %w", err)
+			}
+		case 3:
+			// RecordBatch
+			if sr.fields == nil {
+				return nil, fmt.Errorf("missing schema before record batch")
+			}
+			rr := &recordReader{
+				sr:   sr,
+				body: body,
+			}
+			columns, length, err := rr.readRecordBatch(header, sr.fields)
+			if err != nil {
+				return nil, fmt.Errorf("cannot read record batch: %w", err)
+			}
+			records = append(records, &Record{
+				fields:  sr.fields,
+				columns: columns,
+				length:  length,
+			})
+		default:
+			return nil, fmt.Errorf("unsupported message header type: %d", headerType)
+		}
+	}
+	return records, nil
+}
+
+func (sr *StreamReader) readSchema(t fbTable) error {
+	sr.dictFields = make(map[int64]*field)
+	sr.dicts = make(map[int64]*dictionary)
+	v := t.getVector(1, 4)
+	fields := make([]*field, 0, v.n)
+	for i := 0; i < v.n; i++ {
+		f, err := sr.readField(v.tableAt(i), 0)
+		if err != nil {
+			return err
+		}
+		fields = append(fields, f)
+	}
+	if t.fb.err != nil {
+		return t.fb.err
+	}
+	sr.fields = fields
+	return nil
+}
+
+// maxFieldDepth is the maximum nesting depth for fields in Arrow schema.
+const maxFieldDepth = 16
+
+func (sr *StreamReader) readField(t fbTable, depth int) (*field, error) {
+	if depth > maxFieldDepth {
+		return nil, fmt.Errorf("too deep nesting of fields; it cannot exceed %d", maxFieldDepth)
+	}
+	f := &field{
+		name: t.getString(0),
+	}
+	typeType := t.getUint8(2, 0)
+	tt, _ := t.getTable(3)
+	switch typeType {
+	case 1:
+		f.typ = typeNull
+	case 2:
+		f.typ = typeInt
+		f.bitWidth = int(tt.getInt32(0, 0))
+		f.signed = tt.getBool(1)
+	case 3:
+		f.typ = typeFloat
+		switch tt.getInt16(0, 0) {
+		case 1:
+			f.bitWidth = 32
+		case 2:
+			f.bitWidth = 64
+		default:
+			return nil, fmt.Errorf("unsupported floating point precision for field %q", f.name)
+		}
+	case 4, 5:
+		// Binary and Utf8
+		f.typ = typeBinary
+	case 6:
+		f.typ = typeBool
+	case 10, 18:
+		// Timestamp and Duration
+		f.typ = typeInt
+		f.bitWidth = 64
+		f.signed = true
+	case 12, 17:
+		// List and Map
+		f.typ = typeList
+	case 13:
+		f.typ = typeStruct
+	case 15:
+		f.typ = typeFixedSizeBinary
+		f.bitWidth = 8 * int(tt.getInt32(0, 0))
+	default:
+		return nil, fmt.Errorf("unsupported type %d for field %q", typeType, f.name)
+	}
+	if f.typ == typeInt {
+		switch f.bitWidth {
+		case 8, 16, 32, 64:
+		default:
+			return nil, fmt.Errorf("unsupported integer bit width %d for field %q", f.bitWidth, f.name)
+		}
+	}
+	if f.typ == typeFixedSizeBinary && f.bitWidth <= 0 {
+		return nil, fmt.Errorf("invalid byte width for fixed size binary field %q", f.name)
+	}
+
+	if dt, ok := t.getTable(4); ok {
+		f.isDict = true
+		f.dictID = dt.getInt64(0, 0)
+		f.indexBitWidth = 32
+		f.indexSigned = true
+		if it, ok := dt.getTable(1); ok {
+			f.indexBitWidth = int(it.getInt32(0, 0))
+			f.indexSigned = it.getBool(1)
+		}
+		switch f.indexBitWidth {
+		case 8, 16, 32, 64:
+		default:
+			return nil, fmt.Errorf("unsupported dictionary index bit width %d for field %q", f.indexBitWidth, f.name)
+		}
+	}
+
+	v := t.getVector(5, 4)
+	for i := 0; i < v.n; i++ {
+		child, err := sr.readField(v.tableAt(i), depth+1)
+		if err != nil {
+			return nil, err
+		}
+		f.children = append(f.children, child)
+	}
+	if f.isDict {
+		sr.dictFields[f.dictID] = f.valueField()
+	}
+	md := t.getVector(6, 4)
+	for i := 0; i < md.n; i++ {
+		kv := md.tableAt(i)
+		if kv.getString(0) == "encoding" {
+			f.encoding = kv.getString(1)
+		}
+	}
+	if f.typ == typeList && len(f.children) != 1 {
+		return nil, fmt.Errorf("unexpected number of children for list field %q; got %d; want 1", f.name, len(f.children))
+	}
+	if t.fb.err != nil {
+		return nil, t.fb.err
+	}
+	return f, nil
+}
+
+func (sr *StreamReader) readDictionaryBatch(t fbTable, body []byte) error {
+	id := t.getInt64(0, 0)
+	data, ok := t.getTable(1)
+	isDelta := t.getBool(2)
+	if t.fb.err != nil {
+		return t.fb.err
+	}
+	if !ok {
+		return fmt.Errorf("missing data for dictionary %d", id)
+	}
+	f := sr.dictFields[id]
+	if f == nil {
+		return fmt.Errorf("unknown dictionary id: %d", id)
+	}
+	rr := &recordReader{
+		sr:   sr,
+		body: body,
+	}
+	columns, _, err := rr.readRecordBatch(data, []*field{f})
+	if err != nil {
+		return fmt.Errorf("cannot read values for dictionary %d: %w", id, err)
+	}
+	d := sr.dicts[id]
+	if d == nil || !isDelta {
+		d = &dictionary{}
+		sr.dicts[id] = d
+	}
+	d.chunks = append(d.chunks, columns[0])
+	return nil
+}
+
+// recordReader reads columns from Arrow record batch.
+type recordReader struct {
+	sr   *StreamReader
+	body []byte
+
+	nodes   fbVector
+	nodeIdx int
+
+	buffers   fbVector
+	bufferIdx int
+
+	// codec is the compression codec for buffers. -1 means no compression.
+	codec int
+}
+
+// Compression codecs for record batch buffers.
+//
+// See CompressionType at https://github.com/apache/arrow/blob/main/format/Message.fbs
+const (
+	codecLZ4Frame = 0
+	codecZSTD     = 1
+)
+
+func (rr *recordReader) readRecordBatch(t fbTable, fields []*field) ([]*column, int, error) {
+	length := t.getInt64(0, 0)
+	rr.nodes = t.getVector(1, 16)
+	rr.buffers = t.getVector(2, 16)
+	rr.codec = -1
+	if ct, ok := t.getTable(3); ok {
+		rr.codec = int(ct.getUint8(0, 0))
+	}
+	if t.fb.err != nil {
+		return nil, 0, t.fb.err
+	}
+	if length < 0 || length > maxColumnLength {
+		return nil, 0, fmt.Errorf("invalid record batch length: %d", length)
+	}
+	columns := make([]*column, 0, len(fields))
+	for _, f := range fields {
+		c, err := rr.readColumn(f)
+		if err != nil {
+			return nil, 0, fmt.Errorf("cannot read column %q: %w", f.name, err)
+		}
+		if c.length != int(length) {
+			return nil, 0, fmt.Errorf("unexpected length for column %q; got %d; want %d", f.name, c.length, length)
+		}
+		columns = append(columns, c)
+	}
+	return columns, int(length), nil
+}
+
+func (rr *recordReader) readColumn(f *field) (*column, error) {
+	if rr.nodeIdx >= rr.nodes.n {
+		return nil, fmt.Errorf("missing field node")
+	}
+	length := rr.nodes.int64At(rr.nodeIdx, 0)
+	nullCount := rr.nodes.int64At(rr.nodeIdx, 8)
+	rr.nodeIdx++
+	if length < 0 || length > maxColumnLength {
+		return nil, fmt.Errorf("invalid field node length: %d", length)
+	}
+	c := &column{
+		field:  f,
+		length: int(length),
+	}
+	if f.typ == typeNull && !f.isDict {
+		return c, nil
+	}
+
+	validity, err := rr.nextBuffer()
+	if err != nil {
+		return nil, err
+	}
+	if nullCount > 0 {
+		if len(validity) < (c.length+7)/8 {
+			return nil, fmt.Errorf("too short validity buffer; got %d bytes; want at least %d bytes", len(validity), (c.length+7)/8)
+		}
+		c.validity = validity
+	}
+
+	if f.isDict {
+		c.values, err = rr.nextValuesBuffer(c.length, f.indexBitWidth)
+		if err != nil {
+			return nil, err
+		}
+		c.dict = rr.sr.dicts[f.dictID]
+		if c.dict == nil && int64(c.length) > nullCount {
+			return nil, fmt.Errorf("missing dictionary %d", f.dictID)
+		}
+		return c, nil
+	}
+
+	switch f.typ {
+	case typeInt, typeFloat, typeFixedSizeBinary:
+		c.values, err = rr.nextValuesBuffer(c.length, f.bitWidth)
+		if err != nil {
+			return nil, err
+		}
+	case typeBool:
+		c.values, err = rr.nextValuesBuffer(c.length, 1)
+		if err != nil {
+			return nil, err
+		}
+	case typeBinary:
+		c.offsets, err = rr.nextOffsetsBuffer(c.length)
+		if err != nil {
+			return nil, err
+		}
+		c.data, err = rr.nextBuffer()
+		if err != nil {
+			return nil, err
+		}
+	case typeList:
+		c.offsets, err = rr.nextOffsetsBuffer(c.length)
+		if err != nil {
+			return nil, err
+		}
+		child, err := rr.readColumn(f.children[0])
+		if err != nil {
+			return nil, err
+		}
+		c.children = []*column{child}
+	case typeStruct:
+		for _, cf := range f.children {
+			child, err := rr.readColumn(cf)
+			if err != nil {
+				return nil, fmt.Errorf("cannot read child column %q: %w", cf.name, err)
+			}
+			if child.length < c.length {
+				return nil, fmt.Errorf("too short child column %q; got %d rows; want at least %d rows", cf.name, child.length, c.length)
+			}
+			c.children = append(c.children, child)
+		}
+	}
+	return c, nil
+}
+
+func (rr *recordReader) nextValuesBuffer(length, bitWidth int) ([]byte, error) {
+	b, err := rr.nextBuffer()
+	if err != nil {
+		return nil, err
+	}
+	n := (length*bitWidth + 7) / 8
+	if len(b) < n {
+		return nil, fmt.Errorf("too short values buffer; got %d bytes; want at least %d bytes", len(b), n)
+	}
+	return b, nil
+}
+
+func (rr *recordReader) nextOffsetsBuffer(length int) ([]byte, error) {
+	b, err := rr.nextBuffer()
+	if err != nil {
+		return nil, err
+	}
+	if length == 0 {
+		// Offsets buffer may be empty for zero-length arrays.
+		return b, nil
+	}
+	n := (length + 1) * 4
+	if len(b) < n {
+		return nil, fmt.Errorf("too short offsets buffer; got %d bytes; want at least %d bytes", len(b), n)
+	}
+	return b, nil
+}
+
+func (rr *recordReader) nextBuffer() ([]byte, error) {
+	if rr.bufferIdx >= rr.buffers.n {
+		return nil, fmt.Errorf("missing buffer")
+	}
+	offset := rr.buffers.int64At(rr.bufferIdx, 0)
+	length := rr.buffers.int64At(rr.bufferIdx, 8)
+	rr.bufferIdx++
+	if offset < 0 || length < 0 || offset > int64(len(rr.body)) || length > int64(len(rr.body))-offset {
+		return nil, fmt.Errorf("buffer with offset=%d and length=%d is out of message body with length %d", offset, length, len(rr.body))
+	}
+	b := rr.body[offset : offset+length]
+	if rr.codec < 0 || len(b) == 0 {
+		return b, nil
+	}
+
+	// Compressed buffer starts with 64-bit uncompressed length. -1 means the buffer isn't compressed.
+	if len(b) < 8 {
+		return nil, fmt.Errorf("too short compressed buffer: %d bytes", len(b))
+	}
+	uncompressedLen := int64(binary.LittleEndian.Uint64(b))
+	b = b[8:]
+	if uncompressedLen == -1 {
+		return b, nil
+	}
+	if uncompressedLen < 0 || uncompressedLen > maxDecompressedBufferSize {
+		return nil, fmt.Errorf("invalid uncompressed buffer length: %d; it must be in the range [0..%d]", uncompressedLen, maxDecompressedBufferSize)
+	}
+	switch rr.codec {
+	case codecLZ4Frame:
+		dst, err := decompressLZ4Frame(make([]byte, 0, uncompressedLen), b, int(uncompressedLen))
+		if err != nil {
+			return nil, fmt.Errorf("cannot decompress lz4 buffer: %w", err)
+		}
+		if int64(len(dst)) != uncompressedLen {
+			return nil, fmt.Errorf("unexpected decompressed buffer length; got %d bytes; want %d bytes", len(dst), uncompressedLen)
+		}
+		return dst, nil
+	case codecZSTD:
+		dst, err := zstd.Decompress(make([]byte, 0, uncompressedLen), b)
+		if err != nil {
+			return nil, fmt.Errorf("cannot decompress zstd buffer: %w", err)
+		}
+		if int64(len(dst)) != uncompressedLen {
+			return nil, fmt.Errorf("unexpected decompressed buffer length; got %d bytes; want %d bytes", len(dst), uncompressedLen)
+		}
+		return dst, nil
+	default:
+		return nil, fmt.Errorf("unsupported compression codec %d; supported codecs: %d (lz4 frame), %d (zstd)", rr.codec, codecLZ4Frame, codecZSTD)
+	}
+}
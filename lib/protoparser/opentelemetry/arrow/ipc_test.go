@@ -0,0 +1,653 @@
+package arrow
+
+import (
+	"encoding/binary"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding/zstd"
+)
+
+// fbObject is flatbuffers object used for building test Arrow messages.
+type fbObject interface {
+	// marshal appends the object to dst and returns the result.
+	//
+	// It returns the position of the object in dst.
+	marshal(dst []byte) ([]byte, int)
+}
+
+// fbTableB is a flatbuffers table builder.
+type fbTableB struct {
+	// fields contains table fields by index. nil fields are skipped.
+	fields []any
+}
+
+// fbScalar is a scalar field value.
+type fbScalar []byte
+
+func fbUint8(v uint8) fbScalar {
+	return fbScalar{v}
+}
+
+func fbInt16(v int16) fbScalar {
+	return binary.LittleEndian.AppendUint16(nil, uint16(v))
+}
+
+func fbInt32(v int32) fbScalar {
+	return binary.LittleEndian.AppendUint32(nil, uint32(v))
+}
+
+func fbInt64(v int64) fbScalar {
+	return binary.LittleEndian.AppendUint64(nil, uint64(v))
+}
+
+// fbString is a string field value.
+type fbString string
+
+func (s fbString) marshal(dst []byte) ([]byte, int) {
+	pos := len(dst)
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(len(s)))
+	dst = append(dst, s...)
+	dst = append(dst, 0)
+	return dst, pos
+}
+
+// fbTables is a vector of tables.
+type fbTables []*fbTableB
+
+func (v fbTables) marshal(dst []byte) ([]byte, int) {
+	pos := len(dst)
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(len(v)))
+	refsPos := len(dst)
+	dst = append(dst, make([]byte, 4*len(v))...)
+	for i, t := range v {
+		var tPos int
+		dst, tPos = t.marshal(dst)
+		refPos := refsPos + 4*i
+		binary.LittleEndian.PutUint32(dst[refPos:], uint32(tPos-refPos))
+	}
+	return dst, pos
+}
+
+// fbStructs is a vector of structs.
+type fbStructs struct {
+	n    int
+	data []byte
+}
+
+func (v fbStructs) marshal(dst []byte) ([]byte, int) {
+	pos := len(dst)
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(v.n))
+	dst = append(dst, v.data...)
+	return dst, pos
+}
+
+func (t *fbTableB) marshal(dst []byte) ([]byte, int) {
+	// Write vtable.
+	vtablePos := len(dst)
+	vtableLen := 4 + 2*len(t.fields)
+	dst = binary.LittleEndian.AppendUint16(dst, uint16(vtableLen))
+	tableLenPos := len(dst)
+	dst = binary.LittleEndian.AppendUint16(dst, 0)
+	fieldOffsetsPos := len(dst)
+	dst = append(dst, make([]byte, 2*len(t.fields))...)
+
+	// Write table.
+	tablePos := len(dst)
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(int32(tablePos-vtablePos)))
+	refs := make(map[int]fbObject)
+	for i, f := range t.fields {
+		if f == nil {
+			continue
+		}
+		binary.LittleEndian.PutUint16(dst[fieldOffsetsPos+2*i:], uint16(len(dst)-tablePos))
+		switch f := f.(type) {
+		case fbScalar:
+			dst = append(dst, f...)
+		case fbObject:
+			refs[len(dst)] = f
+			dst = append(dst, 0, 0, 0, 0)
+		default:
+			panic("BUG: unexpected field type")
+		}
+	}
+	binary.LittleEndian.PutUint16(dst[tableLenPos:], uint16(len(dst)-tablePos))
+
+	// Write referenced objects after the table, since references must point forward.
+	for refPos, obj := range refs {
+		var objPos int
+		dst, objPos = obj.marshal(dst)
+		binary.LittleEndian.PutUint32(dst[refPos:], uint32(objPos-refPos))
+	}
+	return dst, tablePos
+}
+
+func marshalFlatbuffer(t *fbTableB) []byte {
+	dst := make([]byte, 4)
+	dst, pos := t.marshal(dst)
+	binary.LittleEndian.PutUint32(dst, uint32(pos))
+	return dst
+}
+
+// testColumn describes a column for building test Arrow record batches.
+type testColumn struct {
+	name string
+	typ  dataType
+
+	bitWidth int
+	signed   bool
+
+	// nulls contains true for null values.
+	nulls []bool
+
+	ints   []int64
+	floats []float64
+	bools  []bool
+	strs   []string
+
+	// offsets contains list offsets.
+	offsets  []int32
+	children []*testColumn
+
+	// dictID must be set to non-zero value for dictionary-encoded columns with strs values and ints indexes.
+	dictID int64
+
+	encoding string
+}
+
+func (tc *testColumn) length() int {
+	if tc.dictID != 0 {
+		return len(tc.ints)
+	}
+	switch tc.typ {
+	case typeInt:
+		return len(tc.ints)
+	case typeFloat:
+		return len(tc.floats)
+	case typeBool:
+		return len(tc.bools)
+	case typeBinary:
+		return len(tc.strs)
+	case typeList:
+		return len(tc.offsets) - 1
+	case typeStruct:
+		return tc.children[0].length()
+	default:
+		return len(tc.nulls)
+	}
+}
+
+func (tc *testColumn) fieldTable() *fbTableB {
+	var typeType uint8
+	var typeTable *fbTableB
+	switch tc.typ {
+	case typeNull:
+		typeType = 1
+		typeTable = &fbTableB{}
+	case typeInt:
+		typeType = 2
+		var signed uint8
+		if tc.signed {
+			signed = 1
+		}
+		typeTable = &fbTableB{
+			fields: []any{fbInt32(int32(tc.bitWidth)), fbUint8(signed)},
+		}
+	case typeFloat:
+		typeType = 3
+		typeTable = &fbTableB{
+			fields: []any{fbInt16(2)},
+		}
+	case typeBool:
+		typeType = 6
+		typeTable = &fbTableB{}
+	case typeBinary:
+		typeType = 5
+		typeTable = &fbTableB{}
+	case typeList:
+		typeType = 12
+		typeTable = &fbTableB{}
+	case typeStruct:
+		typeType = 13
+		typeTable = &fbTableB{}
+	}
+	var children fbTables
+	for _, child := range tc.children {
+		children = append(children, child.fieldTable())
+	}
+	fields := []any{fbString(tc.name), fbUint8(1), fbUint8(typeType), typeTable, nil, children, nil}
+	if tc.dictID != 0 {
+		fields[4] = &fbTableB{
+			fields: []any{fbInt64(tc.dictID), &fbTableB{
+				fields: []any{fbInt32(16), fbUint8(0)},
+			}},
+		}
+	}
+	if tc.encoding != "" {
+		fields[6] = fbTables{
+			{
+				fields: []any{fbString("encoding"), fbString(tc.encoding)},
+			},
+		}
+	}
+	return &fbTableB{
+		fields: fields,
+	}
+}
+
+// recordBatchBuilder builds Arrow record batch.
+type recordBatchBuilder struct {
+	nodes    []byte
+	nodesN   int
+	buffers  []byte
+	buffersN int
+	body     []byte
+
+	// codec is the compression codec for buffers. -1 means no compression.
+	codec int
+}
+
+func (rb *recordBatchBuilder) addNode(length, nullCount int) {
+	rb.nodes = binary.LittleEndian.AppendUint64(rb.nodes, uint64(length))
+	rb.nodes = binary.LittleEndian.AppendUint64(rb.nodes, uint64(nullCount))
+	rb.nodesN++
+}
+
+func (rb *recordBatchBuilder) addBuffer(b []byte) {
+	if rb.codec >= 0 && len(b) > 0 {
+		compressed := binary.LittleEndian.AppendUint64(nil, uint64(len(b)))
+		switch rb.codec {
+		case codecLZ4Frame:
+			b = compressLZ4Frame(compressed, b)
+		case codecZSTD:
+			b = zstd.CompressLevel(compressed, b, 1)
+		}
+	}
+	rb.buffers = binary.LittleEndian.AppendUint64(rb.buffers, uint64(len(rb.body)))
+	rb.buffers = binary.LittleEndian.AppendUint64(rb.buffers, uint64(len(b)))
+	rb.buffersN++
+	rb.body = append(rb.body, b...)
+	for len(rb.body)%8 != 0 {
+		rb.body = append(rb.body, 0)
+	}
+}
+
+func (rb *recordBatchBuilder) addColumn(tc *testColumn) {
+	length := tc.length()
+	nullCount := 0
+	var validity []byte
+	if tc.nulls != nil {
+		validity = make([]byte, (length+7)/8)
+		for i, isNull := range tc.nulls {
+			if isNull {
+				nullCount++
+			} else {
+				validity[i/8] |= 1 << (i % 8)
+			}
+		}
+	}
+	rb.addNode(length, nullCount)
+	if tc.typ == typeNull {
+		return
+	}
+	rb.addBuffer(validity)
+
+	if tc.dictID != 0 {
+		var b []byte
+		for _, n := range tc.ints {
+			b = binary.LittleEndian.AppendUint16(b, uint16(n))
+		}
+		rb.addBuffer(b)
+		return
+	}
+
+	switch tc.typ {
+	case typeInt:
+		var b []byte
+		for _, n := range tc.ints {
+			switch tc.bitWidth {
+			case 8:
+				b = append(b, byte(n))
+			case 16:
+				b = binary.LittleEndian.AppendUint16(b, uint16(n))
+			case 32:
+				b = binary.LittleEndian.AppendUint32(b, uint32(n))
+			case 64:
+				b = binary.LittleEndian.AppendUint64(b, uint64(n))
+			}
+		}
+		rb.addBuffer(b)
+	case typeFloat:
+		var b []byte
+		for _, f := range tc.floats {
+			b = binary.LittleEndian.AppendUint64(b, math.Float64bits(f))
+		}
+		rb.addBuffer(b)
+	case typeBool:
+		b := make([]byte, (length+7)/8)
+		for i, v := range tc.bools {
+			if v {
+				b[i/8] |= 1 << (i % 8)
+			}
+		}
+		rb.addBuffer(b)
+	case typeBinary:
+		var offsets, data []byte
+		offsets = binary.LittleEndian.AppendUint32(offsets, 0)
+		for _, s := range tc.strs {
+			data = append(data, s...)
+			offsets = binary.LittleEndian.AppendUint32(offsets, uint32(len(data)))
+		}
+		rb.addBuffer(offsets)
+		rb.addBuffer(data)
+	case typeList:
+		var offsets []byte
+		for _, n := range tc.offsets {
+			offsets = binary.LittleEndian.AppendUint32(offsets, uint32(n))
+		}
+		rb.addBuffer(offsets)
+		rb.addColumn(tc.children[0])
+	case typeStruct:
+		for _, child := range tc.children {
+			rb.addColumn(child)
+		}
+	}
+}
+
+func (rb *recordBatchBuilder) recordBatchTable(length int) *fbTableB {
+	fields := []any{fbInt64(int64(length)), fbStructs{n: rb.nodesN, data: rb.nodes}, fbStructs{n: rb.buffersN, data: rb.buffers}, nil}
+	if rb.codec >= 0 {
+		fields[3] = &fbTableB{
+			fields: []any{fbUint8(uint8(rb.codec)), fbUint8(0)},
+		}
+	}
+	return &fbTableB{
+		fields: fields,
+	}
+}
+
+func appendMessage(dst []byte, headerType uint8, header *fbTableB, body []byte) []byte {
+	msg := &fbTableB{
+		fields: []any{fbInt16(4), fbUint8(headerType), header, fbInt64(int64(len(body)))},
+	}
+	meta := marshalFlatbuffer(msg)
+	for len(meta)%8 != 0 {
+		meta = append(meta, 0)
+	}
+	dst = binary.LittleEndian.AppendUint32(dst, 0xffffffff)
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(len(meta)))
+	dst = append(dst, meta...)
+	dst = append(dst, body...)
+	return dst
+}
+
+// appendSchemaMessage appends Arrow IPC schema message for the given columns to dst.
+func appendSchemaMessage(dst []byte, columns []*testColumn) []byte {
+	var fields fbTables
+	for _, tc := range columns {
+		fields = append(fields, tc.fieldTable())
+	}
+	schema := &fbTableB{
+		fields: []any{fbInt16(0), fields},
+	}
+	return appendMessage(dst, 1, schema, nil)
+}
+
+// appendDictionaryMessages appends Arrow IPC dictionary batch messages for dictionary-encoded columns to dst.
+func appendDictionaryMessages(dst []byte, columns []*testColumn, isDelta bool, codec int) []byte {
+	for _, tc := range columns {
+		dst = appendDictionaryMessages(dst, tc.children, isDelta, codec)
+		if tc.dictID == 0 {
+			continue
+		}
+		rb := &recordBatchBuilder{
+			codec: codec,
+		}
+		rb.addColumn(&testColumn{
+			typ:  typeBinary,
+			strs: tc.strs,
+		})
+		var delta uint8
+		if isDelta {
+			delta = 1
+		}
+		dict := &fbTableB{
+			fields: []any{fbInt64(tc.dictID), rb.recordBatchTable(len(tc.strs)), fbUint8(delta)},
+		}
+		dst = appendMessage(dst, 2, dict, rb.body)
+	}
+	return dst
+}
+
+// appendRecordBatchMessage appends Arrow IPC record batch message for the given columns to dst.
+func appendRecordBatchMessage(dst []byte, columns []*testColumn, codec int) []byte {
+	rb := &recordBatchBuilder{
+		codec: codec,
+	}
+	for _, tc := range columns {
+		rb.addColumn(tc)
+	}
+	return appendMessage(dst, 3, rb.recordBatchTable(columns[0].length()), rb.body)
+}
+
+// newTestStream returns Arrow IPC stream with the given columns.
+//
+// Buffers are compressed with the given codec. -1 means no compression.
+func newTestStream(columns []*testColumn, codec int) []byte {
+	dst := appendSchemaMessage(nil, columns)
+	dst = appendDictionaryMessages(dst, columns, false, codec)
+	dst = appendRecordBatchMessage(dst, columns, codec)
+	return dst
+}
+
+func TestStreamReaderReadRecordsSuccess(t *testing.T) {
+	f := func(codec int) {
+		t.Helper()
+
+		columns := []*testColumn{
+			{
+				name:     "u16",
+				typ:      typeInt,
+				bitWidth: 16,
+				ints:     []int64{1, 0, 65535},
+				nulls:    []bool{false, true, false},
+			},
+			{
+				name:     "i64",
+				typ:      typeInt,
+				bitWidth: 64,
+				signed:   true,
+				ints:     []int64{-1, 2, math.MaxInt64},
+			},
+			{
+				name:   "f64",
+				typ:    typeFloat,
+				floats: []float64{1.5, -2, math.Inf(1)},
+			},
+			{
+				name:  "bool",
+				typ:   typeBool,
+				bools: []bool{true, false, true},
+			},
+			{
+				name: "str",
+				typ:  typeBinary,
+				strs: []string{"foo", "", "bar"},
+			},
+			{
+				name:   "dict",
+				typ:    typeBinary,
+				dictID: 1,
+				strs:   []string{"a", "b"},
+				ints:   []int64{1, 1, 0},
+			},
+			{
+				name:    "list",
+				typ:     typeList,
+				offsets: []int32{0, 2, 2, 3},
+				children: []*testColumn{
+					{
+						name:     "item",
+						typ:      typeInt,
+						bitWidth: 64,
+						ints:     []int64{10, 20, 30},
+					},
+				},
+			},
+			{
+				name: "struct",
+				typ:  typeStruct,
+				children: []*testColumn{
+					{
+						name: "name",
+						typ:  typeBinary,
+						strs: []string{"x", "y", "z"},
+					},
+				},
+			},
+		}
+		var sr StreamReader
+		records, err := sr.ReadRecords(newTestStream(columns, codec))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if len(records) != 1 {
+			t.Fatalf("unexpected number of records; got %d; want 1", len(records))
+		}
+		r := records[0]
+		if r.Len() != 3 {
+			t.Fatalf("unexpected number of rows; got %d; want 3", r.Len())
+		}
+
+		u16 := r.Column("u16")
+		if u16.Uint64(0) != 1 || !u16.IsNull(1) || u16.Uint64(2) != 65535 {
+			t.Fatalf("unexpected u16 column values")
+		}
+		i64 := r.Column("i64")
+		if i64.Int64(0) != -1 || i64.Int64(1) != 2 || i64.Int64(2) != math.MaxInt64 {
+			t.Fatalf("unexpected i64 column values")
+		}
+		f64 := r.Column("f64")
+		if f64.Float64(0) != 1.5 || f64.Float64(1) != -2 || !math.IsInf(f64.Float64(2), 1) {
+			t.Fatalf("unexpected f64 column values")
+		}
+		b := r.Column("bool")
+		if !b.Bool(0) || b.Bool(1) || !b.Bool(2) {
+			t.Fatalf("unexpected bool column values")
+		}
+		str := r.Column("str")
+		if str.String(0) != "foo" || str.String(1) != "" || str.String(2) != "bar" {
+			t.Fatalf("unexpected str column values")
+		}
+		dict := r.Column("dict")
+		if dict.String(0) != "b" || dict.String(1) != "b" || dict.String(2) != "a" {
+			t.Fatalf("unexpected dict column values")
+		}
+		list := r.Column("list")
+		var items []uint64
+		for i := 0; i < r.Len(); i++ {
+			items = appendUint64s(items, list, i)
+			items = append(items, 0)
+		}
+		if !reflect.DeepEqual(items, []uint64{10, 20, 0, 0, 30, 0}) {
+			t.Fatalf("unexpected list column values: %v", items)
+		}
+		name := r.Column("struct").Child("name")
+		if name.String(0) != "x" || name.String(2) != "z" {
+			t.Fatalf("unexpected struct column values")
+		}
+
+		// Missing columns must be handled as null columns.
+		missing := r.Column("missing")
+		if !missing.IsNull(0) || missing.Int64(0) != 0 || missing.String(0) != "" || missing.Child("foo") != nil {
+			t.Fatalf("unexpected values for missing column")
+		}
+	}
+
+	f(-1)
+	f(codecLZ4Frame)
+	f(codecZSTD)
+}
+
+func TestStreamReaderReadRecordsDeltaDictionary(t *testing.T) {
+	columns := []*testColumn{
+		{
+			name:   "dict",
+			typ:    typeBinary,
+			dictID: 1,
+			strs:   []string{"a", "b"},
+			ints:   []int64{0, 1},
+		},
+	}
+	var sr StreamReader
+	if _, err := sr.ReadRecords(newTestStream(columns, -1)); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// The next chunk of the stream contains delta dictionary and refers to values from the previous chunk.
+	columns[0].strs = []string{"c"}
+	columns[0].ints = []int64{2, 0}
+	data := appendDictionaryMessages(nil, columns, true, -1)
+	data = appendRecordBatchMessage(data, columns, -1)
+	records, err := sr.ReadRecords(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	dict := records[0].Column("dict")
+	if dict.String(0) != "c" || dict.String(1) != "a" {
+		t.Fatalf("unexpected dict column values: %q, %q", dict.String(0), dict.String(1))
+	}
+}
+
+func TestStreamReaderReadRecordsFailure(t *testing.T) {
+	f := func(data []byte) {
+		t.Helper()
+
+		var sr StreamReader
+		if _, err := sr.ReadRecords(data); err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	columns := []*testColumn{
+		{
+			name:     "u16",
+			typ:      typeInt,
+			bitWidth: 16,
+			ints:     []int64{1, 2},
+		},
+	}
+	stream := newTestStream(columns, -1)
+
+	// truncated stream
+	f(stream[:3])
+	f(stream[:20])
+	f(stream[:len(stream)-1])
+
+	// record batch without schema
+	f(appendRecordBatchMessage(nil, columns, -1))
+
+	// too short values buffer
+	columns[0].ints = columns[0].ints[:1]
+	data := appendSchemaMessage(nil, columns)
+	rb := &recordBatchBuilder{
+		codec: -1,
+	}
+	rb.addColumn(columns[0])
+	data = appendMessage(data, 3, rb.recordBatchTable(100), rb.body)
+	f(data)
+
+	// missing dictionary
+	columns = []*testColumn{
+		{
+			name:   "dict",
+			typ:    typeBinary,
+			dictID: 1,
+			ints:   []int64{0},
+		},
+	}
+	data = appendSchemaMessage(nil, columns)
+	data = appendRecordBatchMessage(data, columns, -1)
+	f(data)
+}
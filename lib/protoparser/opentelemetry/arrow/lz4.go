@@ -0,0 +1,274 @@
+package arrow
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/bits"
+)
+
+// lz4FrameMagic is the magic number, which starts every LZ4 frame.
+//
+// See https://github.com/lz4/lz4/blob/dev/doc/lz4_Frame_format.md
+const lz4FrameMagic = 0x184D2204
+
+// decompressLZ4Frame appends data decompressed from LZ4 frames at src to dst and returns the result.
+//
+// It returns an error if the decompressed data exceeds maxSize bytes.
+//
+// Arrow IPC uses LZ4 frame format for LZ4_FRAME compression codec.
+// See https://github.com/apache/arrow/blob/main/format/Message.fbs
+func decompressLZ4Frame(dst, src []byte, maxSize int) ([]byte, error) {
+	dstLen := len(dst)
+	for len(src) > 0 {
+		var err error
+		dst, src, err = decompressLZ4FrameInternal(dst, dstLen, src, maxSize)
+		if err != nil {
+			return dst, err
+		}
+	}
+	return dst, nil
+}
+
+func decompressLZ4FrameInternal(dst []byte, dstLen int, src []byte, maxSize int) ([]byte, []byte, error) {
+	if len(src) < 7 {
+		return dst, src, fmt.Errorf("too short LZ4 frame header; got %d bytes; want at least 7 bytes", len(src))
+	}
+	if magic := binary.LittleEndian.Uint32(src); magic != lz4FrameMagic {
+		return dst, src, fmt.Errorf("unexpected LZ4 frame magic number; got 0x%08X; want 0x%08X", magic, lz4FrameMagic)
+	}
+
+	// Parse frame descriptor
+	flg := src[4]
+	if version := flg >> 6; version != 1 {
+		return dst, src, fmt.Errorf("unsupported LZ4 frame version: %d", version)
+	}
+	if flg&0x02 != 0 || src[5]&0x8f != 0 {
+		return dst, src, fmt.Errorf("reserved bits must be zero in LZ4 frame descriptor")
+	}
+	hasBlockChecksum := flg&0x10 != 0
+	hasContentSize := flg&0x08 != 0
+	hasContentChecksum := flg&0x04 != 0
+	hasDictID := flg&0x01 != 0
+	blockMaxSizeCode := int(src[5] >> 4)
+	if blockMaxSizeCode < 4 {
+		return dst, src, fmt.Errorf("invalid LZ4 block maximum size code: %d", blockMaxSizeCode)
+	}
+	blockMaxSize := 1 << (2 * (blockMaxSizeCode + 4))
+	descriptorLen := 2
+	if hasContentSize {
+		descriptorLen += 8
+	}
+	if hasDictID {
+		descriptorLen += 4
+	}
+	if len(src) < 4+descriptorLen+1 {
+		return dst, src, fmt.Errorf("too short LZ4 frame descriptor; got %d bytes; want %d bytes", len(src)-4, descriptorLen+1)
+	}
+	if hasDictID {
+		return dst, src, fmt.Errorf("LZ4 frames with external dictionaries aren't supported")
+	}
+	descriptor := src[4 : 4+descriptorLen]
+	if hc := byte(xxhash32(descriptor) >> 8); hc != src[4+descriptorLen] {
+		return dst, src, fmt.Errorf("LZ4 frame descriptor checksum mismatch; got 0x%02X; want 0x%02X", src[4+descriptorLen], hc)
+	}
+	frameStart := len(dst)
+	src = src[4+descriptorLen+1:]
+
+	// Decompress data blocks till EndMark
+	for {
+		if len(src) < 4 {
+			return dst, src, fmt.Errorf("missing LZ4 block size")
+		}
+		blockSize := binary.LittleEndian.Uint32(src)
+		src = src[4:]
+		if blockSize == 0 {
+			break
+		}
+		isUncompressed := blockSize&(1<<31) != 0
+		blockSize &^= 1 << 31
+		if int64(blockSize) > int64(blockMaxSize) {
+			return dst, src, fmt.Errorf("too big LZ4 block size; got %d bytes; mustn't exceed %d bytes", blockSize, blockMaxSize)
+		}
+		if uint64(len(src)) < uint64(blockSize) {
+			return dst, src, fmt.Errorf("too short LZ4 block; got %d bytes; want %d bytes", len(src), blockSize)
+		}
+		block := src[:blockSize]
+		src = src[blockSize:]
+		if hasBlockChecksum {
+			if len(src) < 4 {
+				return dst, src, fmt.Errorf("missing LZ4 block checksum")
+			}
+			if h := xxhash32(block); h != binary.LittleEndian.Uint32(src) {
+				return dst, src, fmt.Errorf("LZ4 block checksum mismatch")
+			}
+			src = src[4:]
+		}
+		if isUncompressed {
+			if len(dst)-dstLen+len(block) > maxSize {
+				return dst, src, fmt.Errorf("too big decompressed LZ4 data; it mustn't exceed %d bytes", maxSize)
+			}
+			dst = append(dst, block...)
+			continue
+		}
+		var err error
+		dst, err = decompressLZ4Block(dst, block, dstLen+maxSize)
+		if err != nil {
+			return dst, src, err
+		}
+	}
+
+	if hasContentChecksum {
+		if len(src) < 4 {
+			return dst, src, fmt.Errorf("missing LZ4 content checksum")
+		}
+		if h := xxhash32(dst[frameStart:]); h != binary.LittleEndian.Uint32(src) {
+			return dst, src, fmt.Errorf("LZ4 content checksum mismatch")
+		}
+		src = src[4:]
+	}
+	if hasContentSize {
+		contentSize := binary.LittleEndian.Uint64(descriptor[2:])
+		if n := uint64(len(dst) - frameStart); n != contentSize {
+			return dst, src, fmt.Errorf("unexpected LZ4 frame content size; got %d bytes; want %d bytes", n, contentSize)
+		}
+	}
+	return dst, src, nil
+}
+
+// decompressLZ4Block appends data decompressed from LZ4 block at src to dst and returns the result.
+//
+// Matches may refer to the data at dst, so linked blocks from the same frame are decompressed properly.
+// It returns an error if len(dst) exceeds maxLen.
+//
+// See https://github.com/lz4/lz4/blob/dev/doc/lz4_Block_format.md
+func decompressLZ4Block(dst, src []byte, maxLen int) ([]byte, error) {
+	for len(src) > 0 {
+		token := src[0]
+		src = src[1:]
+
+		// Copy literals
+		literalsLen, tail, err := readLZ4Length(src, int(token>>4))
+		if err != nil {
+			return dst, fmt.Errorf("cannot read LZ4 literals length: %w", err)
+		}
+		src = tail
+		if literalsLen > len(src) {
+			return dst, fmt.Errorf("too short LZ4 literals; got %d bytes; want %d bytes", len(src), literalsLen)
+		}
+		if len(dst)+literalsLen > maxLen {
+			return dst, fmt.Errorf("too big decompressed LZ4 data; it mustn't exceed %d bytes", maxLen)
+		}
+		dst = append(dst, src[:literalsLen]...)
+		src = src[literalsLen:]
+		if len(src) == 0 {
+			// The last sequence contains only literals.
+			break
+		}
+
+		// Copy match
+		if len(src) < 2 {
+			return dst, fmt.Errorf("missing LZ4 match offset")
+		}
+		offset := int(binary.LittleEndian.Uint16(src))
+		src = src[2:]
+		if offset == 0 || offset > len(dst) {
+			return dst, fmt.Errorf("invalid LZ4 match offset %d; it must be in the range [1..%d]", offset, len(dst))
+		}
+		matchLen, tail, err := readLZ4Length(src, int(token&0x0f))
+		if err != nil {
+			return dst, fmt.Errorf("cannot read LZ4 match length: %w", err)
+		}
+		src = tail
+		matchLen += 4
+		if len(dst)+matchLen > maxLen {
+			return dst, fmt.Errorf("too big decompressed LZ4 data; it mustn't exceed %d bytes", maxLen)
+		}
+		start := len(dst) - offset
+		if offset >= matchLen {
+			dst = append(dst, dst[start:start+matchLen]...)
+			continue
+		}
+		// The match overlaps with the data being copied, so it must be copied byte by byte.
+		for i := 0; i < matchLen; i++ {
+			dst = append(dst, dst[start+i])
+		}
+	}
+	return dst, nil
+}
+
+// readLZ4Length reads the length for the given 4-bit n from the token.
+//
+// The length is extended with the following bytes from src if n equals to 15.
+func readLZ4Length(src []byte, n int) (int, []byte, error) {
+	if n != 15 {
+		return n, src, nil
+	}
+	for {
+		if len(src) == 0 {
+			return 0, src, fmt.Errorf("unexpected end of data")
+		}
+		b := src[0]
+		src = src[1:]
+		n += int(b)
+		if b != 255 {
+			return n, src, nil
+		}
+	}
+}
+
+const (
+	xxhash32Prime1 = 2654435761
+	xxhash32Prime2 = 2246822519
+	xxhash32Prime3 = 3266489917
+	xxhash32Prime4 = 668265263
+	xxhash32Prime5 = 374761393
+)
+
+// xxhash32 returns XXH32 hash with zero seed for b.
+//
+// It is used for verifying LZ4 frame checksums.
+// See https://github.com/Cyan4973/xxHash/blob/dev/doc/xxhash_spec.md
+func xxhash32(b []byte) uint32 {
+	n := len(b)
+	var h uint32
+	if n >= 16 {
+		v1 := uint32(xxhash32Prime1)
+		v1 += xxhash32Prime2
+		v2 := uint32(xxhash32Prime2)
+		v3 := uint32(0)
+		v4 := uint32(0)
+		v4 -= xxhash32Prime1
+		for len(b) >= 16 {
+			v1 = xxhash32Round(v1, binary.LittleEndian.Uint32(b))
+			v2 = xxhash32Round(v2, binary.LittleEndian.Uint32(b[4:]))
+			v3 = xxhash32Round(v3, binary.LittleEndian.Uint32(b[8:]))
+			v4 = xxhash32Round(v4, binary.LittleEndian.Uint32(b[12:]))
+			b = b[16:]
+		}
+		h = bits.RotateLeft32(v1, 1) + bits.RotateLeft32(v2, 7) + bits.RotateLeft32(v3, 12) + bits.RotateLeft32(v4, 18)
+	} else {
+		h = xxhash32Prime5
+	}
+	h += uint32(n)
+	for len(b) >= 4 {
+		h += binary.LittleEndian.Uint32(b) * xxhash32Prime3
+		h = bits.RotateLeft32(h, 17) * xxhash32Prime4
+		b = b[4:]
+	}
+	for _, c := range b {
+		h += uint32(c) * xxhash32Prime5
+		h = bits.RotateLeft32(h, 11) * xxhash32Prime1
+	}
+	h ^= h >> 15
+	h *= xxhash32Prime2
+	h ^= h >> 13
+	h *= xxhash32Prime3
+	h ^= h >> 16
+	return h
+}
+
+func xxhash32Round(acc, v uint32) uint32 {
+	acc += v * xxhash32Prime2
+	acc = bits.RotateLeft32(acc, 13)
+	return acc * xxhash32Prime1
+}
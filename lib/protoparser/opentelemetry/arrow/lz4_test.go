@@ -0,0 +1,191 @@
+package arrow
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// compressLZ4Frame appends LZ4 frame with src to dst and returns the result.
+//
+// The frame consists of literal-only blocks, so it is valid, while it isn't compressed actually.
+func compressLZ4Frame(dst, src []byte) []byte {
+	dst = binary.LittleEndian.AppendUint32(dst, lz4FrameMagic)
+	descriptor := []byte{0x60, 0x40}
+	dst = append(dst, descriptor...)
+	dst = append(dst, byte(xxhash32(descriptor)>>8))
+	for len(src) > 0 {
+		n := min(len(src), 32*1024)
+		var block []byte
+		if n < 15 {
+			block = append(block, byte(n<<4))
+		} else {
+			block = append(block, 0xf0)
+			m := n - 15
+			for m >= 255 {
+				block = append(block, 255)
+				m -= 255
+			}
+			block = append(block, byte(m))
+		}
+		block = append(block, src[:n]...)
+		src = src[n:]
+		dst = binary.LittleEndian.AppendUint32(dst, uint32(len(block)))
+		dst = append(dst, block...)
+	}
+	return binary.LittleEndian.AppendUint32(dst, 0)
+}
+
+func mustDecodeHex(s string) []byte {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		panic(fmt.Errorf("cannot decode hex: %w", err))
+	}
+	return data
+}
+
+func TestDecompressLZ4FrameSuccess(t *testing.T) {
+	f := func(src []byte, resultExpected string) {
+		t.Helper()
+
+		result, err := decompressLZ4Frame([]byte("prefix"), src, len(resultExpected))
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if string(result) != "prefix"+resultExpected {
+			t.Fatalf("unexpected result\ngot\n%q\nwant\n%q", result, "prefix"+resultExpected)
+		}
+	}
+
+	var sb strings.Builder
+	for i := 0; i < 12; i++ {
+		fmt.Fprintf(&sb, "metric_%d{job=\"foo\"} %d\n", i%3, i)
+	}
+	metrics := sb.String()
+
+	// The frames below are generated by lz4 command-line tool.
+
+	// empty frame
+	f(mustDecodeHex("04224d186440a700000000055dcc02"), "")
+
+	// frame with content checksum
+	f(mustDecodeHex("04224d186440a70300008061626300000000ff53d132"), "abc")
+
+	// frame with dependent blocks, block checksums, content size and content checksum
+	f(mustDecodeHex("04224d187c400a01000000000000106b000000f3076d65747269635f307b6a6f623d22666f6f227d20300a"+
+		"160018311600143116001832160014321600094200143316000942001434160009420014351600094200143616000942"+
+		"001437160009420014381600094200143916000942001531dd00074300507d2031310a11ce1ef9000000004453dc3f"), metrics)
+
+	// frame with block checksums and without content checksum
+	f(mustDecodeHex("04224d187040ad6b000000f3076d65747269635f307b6a6f623d22666f6f227d20300a16001831160014311600"+
+		"1832160014321600094200143316000942001434160009420014351600094200143616000942001437160009420014381600"+
+		"094200143916000942001531dd00074300507d2031310a11ce1ef900000000"), metrics)
+
+	// concatenated frames
+	f(mustDecodeHex("04224d186440a70300008061626300000000ff53d132"+"04224d186440a70300008061626300000000ff53d132"), "abcabc")
+
+	// literal-only blocks
+	f(compressLZ4Frame(nil, []byte(metrics)), metrics)
+	long := strings.Repeat(metrics, 300)
+	f(compressLZ4Frame(nil, []byte(long)), long)
+}
+
+func TestDecompressLZ4FrameFailure(t *testing.T) {
+	f := func(src []byte, maxSize int) {
+		t.Helper()
+
+		if _, err := decompressLZ4Frame(nil, src, maxSize); err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	frame := mustDecodeHex("04224d186440a70300008061626300000000ff53d132")
+
+	// too small maxSize
+	f(frame, 2)
+
+	// truncated frame
+	for i := 1; i < len(frame); i++ {
+		f(frame[:i], 3)
+	}
+
+	// invalid magic
+	f(append([]byte{0}, frame[1:]...), 3)
+
+	// invalid header checksum
+	data := append([]byte{}, frame...)
+	data[6]++
+	f(data, 3)
+
+	// invalid content checksum
+	data = append([]byte{}, frame...)
+	data[len(data)-1]++
+	f(data, 3)
+
+	// trailing garbage
+	f(append(frame, 1, 2, 3), 3)
+}
+
+func TestDecompressLZ4Block(t *testing.T) {
+	f := func(src []byte, maxLen int, resultExpected string) {
+		t.Helper()
+
+		result, err := decompressLZ4Block(nil, src, maxLen)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if string(result) != resultExpected {
+			t.Fatalf("unexpected result\ngot\n%q\nwant\n%q", result, resultExpected)
+		}
+	}
+	fError := func(src []byte, maxLen int) {
+		t.Helper()
+
+		if _, err := decompressLZ4Block(nil, src, maxLen); err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	// literals only
+	f([]byte("\x30abc"), 3, "abc")
+
+	// overlapping match
+	f([]byte("\x36abc\x03\x00\x50bcabc"), 100, "abcabcabcabcabcabc")
+
+	// extended literals and match lengths
+	f([]byte("\xff\x01"+strings.Repeat("x", 16)+"\x01\x00\xff\x01"), 300, strings.Repeat("x", 16+15+255+1+4))
+
+	// too small maxLen
+	fError([]byte("\x30abc"), 2)
+	fError([]byte("\x36abc\x03\x00\x50bcabc"), 17)
+
+	// zero offset
+	fError([]byte("\x10a\x00\x00"), 100)
+
+	// offset out of the decompressed data
+	fError([]byte("\x10a\x02\x00"), 100)
+
+	// missing offset
+	fError([]byte("\x10a\x01"), 100)
+
+	// truncated literals
+	fError([]byte("\x30ab"), 100)
+
+	// truncated length
+	fError([]byte("\xf0"), 100)
+}
+
+func TestXXHash32(t *testing.T) {
+	f := func(s string, hExpected uint32) {
+		t.Helper()
+
+		if h := xxhash32([]byte(s)); h != hExpected {
+			t.Fatalf("unexpected hash for %q; got 0x%08X; want 0x%08X", s, h, hExpected)
+		}
+	}
+
+	f("", 0x02CC5D05)
+	f("abc", 0x32D153FF)
+}
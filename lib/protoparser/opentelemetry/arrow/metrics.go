@@ -0,0 +1,534 @@
+package arrow
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+)
+
+// maxStreamReaders is the maximum number of Arrow IPC streams tracked per MetricsConsumer.
+//
+// OTel Arrow producer starts a new Arrow IPC stream with a new schema id every time the schema changes.
+// Old streams are never used after that, so they can be safely dropped.
+const maxStreamReaders = 1024
+
+// MetricsConsumer converts BatchArrowRecords with metrics to ExportMetricsServiceRequest.
+//
+// A separate MetricsConsumer must be used per each OTel Arrow stream, since the stream is stateful.
+//
+// See https://github.com/open-telemetry/otel-arrow/blob/main/docs/data_model.md
+type MetricsConsumer struct {
+	readers map[string]*StreamReader
+}
+
+// NewMetricsConsumer returns new MetricsConsumer.
+func NewMetricsConsumer() *MetricsConsumer {
+	return &MetricsConsumer{
+		readers: make(map[string]*StreamReader),
+	}
+}
+
+// Convert converts bar to ExportMetricsServiceRequest.
+//
+// bar must contain metrics payloads. Unsupported payloads such as exemplars are ignored.
+func (mc *MetricsConsumer) Convert(bar *BatchArrowRecords) (*pb.ExportMetricsServiceRequest, error) {
+	records := make(map[ArrowPayloadType][]*Record)
+	for i := range bar.ArrowPayloads {
+		ap := &bar.ArrowPayloads[i]
+		sr := mc.readers[ap.SchemaID]
+		if sr == nil {
+			if len(mc.readers) >= maxStreamReaders {
+				clear(mc.readers)
+			}
+			sr = &StreamReader{}
+			mc.readers[ap.SchemaID] = sr
+		}
+		// Copy the record, since dictionaries in sr refer to it after returning from Convert.
+		rs, err := sr.ReadRecords(bytes.Clone(ap.Record))
+		if err != nil {
+			return nil, fmt.Errorf("cannot read Arrow records for payload type %d with schema id %q: %w", ap.Type, ap.SchemaID, err)
+		}
+		records[ap.Type] = append(records[ap.Type], rs...)
+	}
+
+	var mb metricsBuilder
+	mb.resourceAttrs = readAttrs(records[ArrowPayloadTypeResourceAttrs])
+	mb.scopeAttrs = readAttrs(records[ArrowPayloadTypeScopeAttrs])
+	for _, r := range records[ArrowPayloadTypeUnivariateMetrics] {
+		mb.addMetrics(r)
+	}
+
+	attrs := readAttrs(records[ArrowPayloadTypeNumberDataPointAttrs])
+	for _, r := range records[ArrowPayloadTypeNumberDataPoints] {
+		mb.addNumberDataPoints(r, attrs)
+	}
+	attrs = readAttrs(records[ArrowPayloadTypeSummaryDataPointAttrs])
+	for _, r := range records[ArrowPayloadTypeSummaryDataPoints] {
+		mb.addSummaryDataPoints(r, attrs)
+	}
+	attrs = readAttrs(records[ArrowPayloadTypeHistogramDataPointAttrs])
+	for _, r := range records[ArrowPayloadTypeHistogramDataPoints] {
+		mb.addHistogramDataPoints(r, attrs)
+	}
+	attrs = readAttrs(records[ArrowPayloadTypeExpHistogramDataPointAttrs])
+	for _, r := range records[ArrowPayloadTypeExpHistogramDataPoints] {
+		mb.addExpHistogramDataPoints(r, attrs)
+	}
+	return &mb.req, nil
+}
+
+// The metric types used at metric_type column.
+const (
+	metricTypeGauge                = 1
+	metricTypeSum                  = 2
+	metricTypeHistogram            = 3
+	metricTypeExponentialHistogram = 4
+	metricTypeSummary              = 5
+)
+
+// metricsBuilder builds ExportMetricsServiceRequest from OTel Arrow records.
+type metricsBuilder struct {
+	req pb.ExportMetricsServiceRequest
+
+	resourceAttrs map[uint32][]*pb.KeyValue
+	scopeAttrs    map[uint32][]*pb.KeyValue
+
+	// metrics contains metrics by id.
+	metrics map[uint32]*pb.Metric
+}
+
+func (mb *metricsBuilder) addMetrics(r *Record) {
+	if mb.metrics == nil {
+		mb.metrics = make(map[uint32]*pb.Metric)
+	}
+
+	ids := newIDDecoder(r.Column("id"))
+	resource := r.Column("resource")
+	resourceIDs := newIDDecoder(resource.Child("id"))
+	scope := r.Column("scope")
+	scopeIDs := newIDDecoder(scope.Child("id"))
+	scopeSchemaURL := r.Column("schema_url")
+	metricType := r.Column("metric_type")
+	name := r.Column("name")
+	unit := r.Column("unit")
+	aggregationTemporality := r.Column("aggregation_temporality")
+	isMonotonic := r.Column("is_monotonic")
+
+	var rm *pb.ResourceMetrics
+	var sm *pb.ScopeMetrics
+	var resourceIDPrev, scopeIDPrev uint32
+	for i := 0; i < r.Len(); i++ {
+		id, hasID := ids.next(i)
+		resourceID, _ := resourceIDs.next(i)
+		scopeID, _ := scopeIDs.next(i)
+
+		if rm == nil || resourceID != resourceIDPrev {
+			rm = &pb.ResourceMetrics{
+				Resource: &pb.Resource{
+					Attributes: mb.resourceAttrs[resourceID],
+				},
+			}
+			mb.req.ResourceMetrics = append(mb.req.ResourceMetrics, rm)
+			resourceIDPrev = resourceID
+			sm = nil
+		}
+		if sm == nil || scopeID != scopeIDPrev {
+			sm = &pb.ScopeMetrics{
+				Scope: &pb.InstrumentationScope{
+					Name:                   scope.Child("name").String(i),
+					Version:                scope.Child("version").String(i),
+					Attributes:             mb.scopeAttrs[scopeID],
+					DroppedAttributesCount: uint32(scope.Child("dropped_attributes_count").Uint64(i)),
+				},
+				SchemaURL: scopeSchemaURL.String(i),
+			}
+			rm.ScopeMetrics = append(rm.ScopeMetrics, sm)
+			scopeIDPrev = scopeID
+		}
+
+		m := &pb.Metric{
+			Name: name.String(i),
+			Unit: unit.String(i),
+		}
+		at := pb.AggregationTemporality(aggregationTemporality.Int64(i))
+		switch metricType.Int64(i) {
+		case metricTypeGauge:
+			m.Gauge = &pb.Gauge{}
+		case metricTypeSum:
+			m.Sum = &pb.Sum{
+				AggregationTemporality: at,
+				IsMonotonic:            isMonotonic.Bool(i),
+			}
+		case metricTypeHistogram:
+			m.Histogram = &pb.Histogram{
+				AggregationTemporality: at,
+			}
+		case metricTypeExponentialHistogram:
+			m.ExponentialHistogram = &pb.ExponentialHistogram{
+				AggregationTemporality: at,
+			}
+		case metricTypeSummary:
+			m.Summary = &pb.Summary{}
+		}
+		sm.Metrics = append(sm.Metrics, m)
+		if hasID {
+			mb.metrics[id] = m
+		}
+	}
+}
+
+func (mb *metricsBuilder) addNumberDataPoints(r *Record, attrs map[uint32][]*pb.KeyValue) {
+	ids := newIDDecoder(r.Column("id"))
+	parentIDs := newParentIDDecoder(r.Column("parent_id"))
+	timestamps := r.Column("time_unix_nano")
+	intValues := r.Column("int_value")
+	doubleValues := r.Column("double_value")
+	flags := r.Column("flags")
+	for i := 0; i < r.Len(); i++ {
+		id, hasID := ids.next(i)
+		parentID := parentIDs.next(i)
+		p := &pb.NumberDataPoint{
+			TimeUnixNano: timestamps.Uint64(i),
+			Flags:        uint32(flags.Uint64(i)),
+		}
+		if hasID {
+			p.Attributes = attrs[id]
+		}
+		switch {
+		case !intValues.IsNull(i):
+			v := intValues.Int64(i)
+			p.IntValue = &v
+		case !doubleValues.IsNull(i):
+			v := doubleValues.Float64(i)
+			p.DoubleValue = &v
+		}
+		switch m := mb.metrics[parentID]; {
+		case m == nil:
+		case m.Gauge != nil:
+			m.Gauge.DataPoints = append(m.Gauge.DataPoints, p)
+		case m.Sum != nil:
+			m.Sum.DataPoints = append(m.Sum.DataPoints, p)
+		}
+	}
+}
+
+func (mb *metricsBuilder) addSummaryDataPoints(r *Record, attrs map[uint32][]*pb.KeyValue) {
+	ids := newIDDecoder(r.Column("id"))
+	parentIDs := newParentIDDecoder(r.Column("parent_id"))
+	timestamps := r.Column("time_unix_nano")
+	counts := r.Column("count")
+	sums := r.Column("sum")
+	quantiles := r.Column("quantile")
+	quantileValues := quantiles.ListValues()
+	quantile := quantileValues.Child("quantile")
+	value := quantileValues.Child("value")
+	flags := r.Column("flags")
+	for i := 0; i < r.Len(); i++ {
+		id, hasID := ids.next(i)
+		parentID := parentIDs.next(i)
+		p := &pb.SummaryDataPoint{
+			TimeUnixNano: timestamps.Uint64(i),
+			Count:        counts.Uint64(i),
+			Sum:          sums.Float64(i),
+			Flags:        uint32(flags.Uint64(i)),
+		}
+		if hasID {
+			p.Attributes = attrs[id]
+		}
+		start, end := quantiles.ListRange(i)
+		for j := start; j < end; j++ {
+			p.QuantileValues = append(p.QuantileValues, &pb.ValueAtQuantile{
+				Quantile: quantile.Float64(j),
+				Value:    value.Float64(j),
+			})
+		}
+		if m := mb.metrics[parentID]; m != nil && m.Summary != nil {
+			m.Summary.DataPoints = append(m.Summary.DataPoints, p)
+		}
+	}
+}
+
+func (mb *metricsBuilder) addHistogramDataPoints(r *Record, attrs map[uint32][]*pb.KeyValue) {
+	ids := newIDDecoder(r.Column("id"))
+	parentIDs := newParentIDDecoder(r.Column("parent_id"))
+	timestamps := r.Column("time_unix_nano")
+	counts := r.Column("count")
+	sums := r.Column("sum")
+	bucketCounts := r.Column("bucket_counts")
+	explicitBounds := r.Column("explicit_bounds")
+	flags := r.Column("flags")
+	for i := 0; i < r.Len(); i++ {
+		id, hasID := ids.next(i)
+		parentID := parentIDs.next(i)
+		p := &pb.HistogramDataPoint{
+			TimeUnixNano:   timestamps.Uint64(i),
+			Count:          counts.Uint64(i),
+			BucketCounts:   appendUint64s(nil, bucketCounts, i),
+			ExplicitBounds: appendFloat64s(nil, explicitBounds, i),
+			Flags:          uint32(flags.Uint64(i)),
+		}
+		if hasID {
+			p.Attributes = attrs[id]
+		}
+		if !sums.IsNull(i) {
+			v := sums.Float64(i)
+			p.Sum = &v
+		}
+		if m := mb.metrics[parentID]; m != nil && m.Histogram != nil {
+			m.Histogram.DataPoints = append(m.Histogram.DataPoints, p)
+		}
+	}
+}
+
+func (mb *metricsBuilder) addExpHistogramDataPoints(r *Record, attrs map[uint32][]*pb.KeyValue) {
+	ids := newIDDecoder(r.Column("id"))
+	parentIDs := newParentIDDecoder(r.Column("parent_id"))
+	timestamps := r.Column("time_unix_nano")
+	counts := r.Column("count")
+	sums := r.Column("sum")
+	scales := r.Column("scale")
+	zeroCounts := r.Column("zero_count")
+	positive := r.Column("positive")
+	negative := r.Column("negative")
+	flags := r.Column("flags")
+	zeroThresholds := r.Column("zero_threshold")
+	for i := 0; i < r.Len(); i++ {
+		id, hasID := ids.next(i)
+		parentID := parentIDs.next(i)
+		p := &pb.ExponentialHistogramDataPoint{
+			TimeUnixNano:  timestamps.Uint64(i),
+			Count:         counts.Uint64(i),
+			Scale:         int32(scales.Int64(i)),
+			ZeroCount:     zeroCounts.Uint64(i),
+			Positive:      newBuckets(positive, i),
+			Negative:      newBuckets(negative, i),
+			Flags:         uint32(flags.Uint64(i)),
+			ZeroThreshold: zeroThresholds.Float64(i),
+		}
+		if hasID {
+			p.Attributes = attrs[id]
+		}
+		if !sums.IsNull(i) {
+			v := sums.Float64(i)
+			p.Sum = &v
+		}
+		if m := mb.metrics[parentID]; m != nil && m.ExponentialHistogram != nil {
+			m.ExponentialHistogram.DataPoints = append(m.ExponentialHistogram.DataPoints, p)
+		}
+	}
+}
+
+func newBuckets(c *column, i int) *pb.Buckets {
+	if c.IsNull(i) {
+		return nil
+	}
+	return &pb.Buckets{
+		Offset:       int32(c.Child("offset").Int64(i)),
+		BucketCounts: appendUint64s(nil, c.Child("bucket_counts"), i),
+	}
+}
+
+func appendUint64s(dst []uint64, c *column, i int) []uint64 {
+	values := c.ListValues()
+	start, end := c.ListRange(i)
+	for j := start; j < end; j++ {
+		dst = append(dst, values.Uint64(j))
+	}
+	return dst
+}
+
+func appendFloat64s(dst []float64, c *column, i int) []float64 {
+	values := c.ListValues()
+	start, end := c.ListRange(i)
+	for j := start; j < end; j++ {
+		dst = append(dst, values.Float64(j))
+	}
+	return dst
+}
+
+// The attribute value types used at type column of attributes records.
+const (
+	attrTypeStr    = 1
+	attrTypeInt    = 2
+	attrTypeDouble = 3
+	attrTypeBool   = 4
+	attrTypeBytes  = 7
+)
+
+// readAttrs returns attributes from records grouped by parent id.
+//
+// Map and slice attributes are skipped, since they are rarely used in metrics.
+func readAttrs(records []*Record) map[uint32][]*pb.KeyValue {
+	if len(records) == 0 {
+		return nil
+	}
+	m := make(map[uint32][]*pb.KeyValue)
+	for _, r := range records {
+		parentIDs := newAttrsParentIDDecoder(r.Column("parent_id"))
+		keys := r.Column("key")
+		types := r.Column("type")
+		strs := r.Column("str")
+		ints := r.Column("int")
+		doubles := r.Column("double")
+		bools := r.Column("bool")
+		bs := r.Column("bytes")
+		for i := 0; i < r.Len(); i++ {
+			key := keys.Bytes(i)
+			v := &pb.AnyValue{}
+			typ := types.Int64(i)
+			switch typ {
+			case attrTypeStr:
+				s := strs.String(i)
+				v.StringValue = &s
+			case attrTypeInt:
+				n := ints.Int64(i)
+				v.IntValue = &n
+			case attrTypeDouble:
+				f := doubles.Float64(i)
+				v.DoubleValue = &f
+			case attrTypeBool:
+				b := bools.Bool(i)
+				v.BoolValue = &b
+			case attrTypeBytes:
+				b := bytes.Clone(bs.Bytes(i))
+				v.BytesValue = &b
+			}
+			parentID := parentIDs.next(i, typ, key, v)
+			if v.StringValue == nil && v.IntValue == nil && v.DoubleValue == nil && v.BoolValue == nil && v.BytesValue == nil {
+				continue
+			}
+			m[parentID] = append(m[parentID], &pb.KeyValue{
+				Key:   string(key),
+				Value: v,
+			})
+		}
+	}
+	return m
+}
+
+// The encodings for id and parent_id columns, which may be set via "encoding" field metadata.
+const (
+	encodingPlain      = "plain"
+	encodingDelta      = "delta"
+	encodingQuasiDelta = "quasidelta"
+)
+
+// idDecoder decodes id column.
+//
+// The id column is delta-encoded by default.
+type idDecoder struct {
+	c       *column
+	isDelta bool
+	id      uint32
+}
+
+func newIDDecoder(c *column) *idDecoder {
+	return &idDecoder{
+		c:       c,
+		isDelta: c == nil || c.field.encoding != encodingPlain,
+	}
+}
+
+// next returns the id for the row i.
+//
+// false is returned if the id is missing.
+func (d *idDecoder) next(i int) (uint32, bool) {
+	if d.c.IsNull(i) {
+		return 0, false
+	}
+	v := uint32(d.c.Uint64(i))
+	if d.isDelta {
+		d.id += v
+		return d.id, true
+	}
+	return v, true
+}
+
+// parentIDDecoder decodes parent_id column for data points.
+//
+// The parent_id column is delta-encoded by default.
+type parentIDDecoder struct {
+	c       *column
+	isDelta bool
+	id      uint32
+}
+
+func newParentIDDecoder(c *column) *parentIDDecoder {
+	return &parentIDDecoder{
+		c:       c,
+		isDelta: c == nil || c.field.encoding != encodingPlain,
+	}
+}
+
+func (d *parentIDDecoder) next(i int) uint32 {
+	v := uint32(d.c.Uint64(i))
+	if d.isDelta {
+		d.id += v
+		return d.id
+	}
+	return v
+}
+
+// attrsParentIDDecoder decodes parent_id column for attributes.
+//
+// The parent_id column for attributes is quasi-delta encoded by default: it is delta-encoded
+// for consecutive rows with equal keys and values, and it is stored as is otherwise.
+type attrsParentIDDecoder struct {
+	c        *column
+	encoding string
+
+	id        uint32
+	prevType  int64
+	prevKey   []byte
+	prevValue pb.AnyValue
+}
+
+func newAttrsParentIDDecoder(c *column) *attrsParentIDDecoder {
+	encoding := encodingQuasiDelta
+	if c != nil && c.field.encoding != "" {
+		encoding = c.field.encoding
+	}
+	return &attrsParentIDDecoder{
+		c:        c,
+		encoding: encoding,
+	}
+}
+
+func (d *attrsParentIDDecoder) next(i int, typ int64, key []byte, v *pb.AnyValue) uint32 {
+	n := uint32(d.c.Uint64(i))
+	switch d.encoding {
+	case encodingPlain:
+		return n
+	case encodingDelta:
+		d.id += n
+		return d.id
+	default:
+		if typ == d.prevType && bytes.Equal(key, d.prevKey) && equalAnyValues(v, &d.prevValue) {
+			d.id += n
+		} else {
+			d.id = n
+		}
+		d.prevType = typ
+		d.prevKey = append(d.prevKey[:0], key...)
+		d.prevValue = *v
+		return d.id
+	}
+}
+
+func equalAnyValues(a, b *pb.AnyValue) bool {
+	switch {
+	case a.StringValue != nil:
+		return b.StringValue != nil && *a.StringValue == *b.StringValue
+	case a.IntValue != nil:
+		return b.IntValue != nil && *a.IntValue == *b.IntValue
+	case a.DoubleValue != nil:
+		return b.DoubleValue != nil && *a.DoubleValue == *b.DoubleValue
+	case a.BoolValue != nil:
+		return b.BoolValue != nil && *a.BoolValue == *b.BoolValue
+	case a.BytesValue != nil:
+		return b.BytesValue != nil && bytes.Equal(*a.BytesValue, *b.BytesValue)
+	default:
+		return b.StringValue == nil && b.IntValue == nil && b.DoubleValue == nil && b.BoolValue == nil && b.BytesValue == nil
+	}
+}
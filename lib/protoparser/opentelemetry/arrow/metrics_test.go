@@ -0,0 +1,375 @@
+package arrow
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+)
+
+func TestMetricsConsumerConvert(t *testing.T) {
+	resourceAttrs := []*testColumn{
+		{
+			name:     "parent_id",
+			typ:      typeInt,
+			bitWidth: 16,
+			ints:     []int64{0, 0},
+		},
+		{
+			name:   "key",
+			typ:    typeBinary,
+			dictID: 1,
+			strs:   []string{"job", "instance"},
+			ints:   []int64{0, 1},
+		},
+		{
+			name:     "type",
+			typ:      typeInt,
+			bitWidth: 8,
+			ints:     []int64{attrTypeStr, attrTypeInt},
+		},
+		{
+			name:  "str",
+			typ:   typeBinary,
+			strs:  []string{"foo", ""},
+			nulls: []bool{false, true},
+		},
+		{
+			name:     "int",
+			typ:      typeInt,
+			bitWidth: 64,
+			signed:   true,
+			ints:     []int64{0, 123},
+			nulls:    []bool{true, false},
+		},
+	}
+	metrics := []*testColumn{
+		{
+			name:     "id",
+			typ:      typeInt,
+			bitWidth: 16,
+			ints:     []int64{0, 1, 1},
+		},
+		{
+			name: "resource",
+			typ:  typeStruct,
+			children: []*testColumn{
+				{
+					name:     "id",
+					typ:      typeInt,
+					bitWidth: 16,
+					ints:     []int64{0, 0, 0},
+				},
+			},
+		},
+		{
+			name: "scope",
+			typ:  typeStruct,
+			children: []*testColumn{
+				{
+					name:     "id",
+					typ:      typeInt,
+					bitWidth: 16,
+					ints:     []int64{0, 0, 0},
+				},
+				{
+					name: "name",
+					typ:  typeBinary,
+					strs: []string{"scope", "scope", "scope"},
+				},
+			},
+		},
+		{
+			name:     "metric_type",
+			typ:      typeInt,
+			bitWidth: 8,
+			ints:     []int64{metricTypeGauge, metricTypeSum, metricTypeHistogram},
+		},
+		{
+			name: "name",
+			typ:  typeBinary,
+			strs: []string{"gauge", "sum", "histogram"},
+		},
+		{
+			name:     "aggregation_temporality",
+			typ:      typeInt,
+			bitWidth: 32,
+			signed:   true,
+			ints:     []int64{0, 2, 1},
+		},
+		{
+			name:  "is_monotonic",
+			typ:   typeBool,
+			bools: []bool{false, true, false},
+		},
+	}
+	numberDataPoints := []*testColumn{
+		{
+			name:     "id",
+			typ:      typeInt,
+			bitWidth: 32,
+			ints:     []int64{0, 1},
+		},
+		{
+			name:     "parent_id",
+			typ:      typeInt,
+			bitWidth: 16,
+			ints:     []int64{0, 1},
+		},
+		{
+			name:     "time_unix_nano",
+			typ:      typeInt,
+			bitWidth: 64,
+			signed:   true,
+			ints:     []int64{1000, 2000},
+		},
+		{
+			name:     "int_value",
+			typ:      typeInt,
+			bitWidth: 64,
+			signed:   true,
+			ints:     []int64{0, 42},
+			nulls:    []bool{true, false},
+		},
+		{
+			name:   "double_value",
+			typ:    typeFloat,
+			floats: []float64{1.5, 0},
+			nulls:  []bool{false, true},
+		},
+	}
+	numberDataPointAttrs := []*testColumn{
+		{
+			name:     "parent_id",
+			typ:      typeInt,
+			bitWidth: 32,
+			ints:     []int64{1},
+			encoding: encodingPlain,
+		},
+		{
+			name: "key",
+			typ:  typeBinary,
+			strs: []string{"foo"},
+		},
+		{
+			name:     "type",
+			typ:      typeInt,
+			bitWidth: 8,
+			ints:     []int64{attrTypeStr},
+		},
+		{
+			name: "str",
+			typ:  typeBinary,
+			strs: []string{"bar"},
+		},
+	}
+	histogramDataPoints := []*testColumn{
+		{
+			name:     "parent_id",
+			typ:      typeInt,
+			bitWidth: 16,
+			ints:     []int64{2},
+		},
+		{
+			name:     "time_unix_nano",
+			typ:      typeInt,
+			bitWidth: 64,
+			signed:   true,
+			ints:     []int64{3000},
+		},
+		{
+			name:     "count",
+			typ:      typeInt,
+			bitWidth: 64,
+			ints:     []int64{3},
+		},
+		{
+			name:   "sum",
+			typ:    typeFloat,
+			floats: []float64{4.5},
+		},
+		{
+			name:    "bucket_counts",
+			typ:     typeList,
+			offsets: []int32{0, 2},
+			children: []*testColumn{
+				{
+					name:     "item",
+					typ:      typeInt,
+					bitWidth: 64,
+					ints:     []int64{1, 2},
+				},
+			},
+		},
+		{
+			name:    "explicit_bounds",
+			typ:     typeList,
+			offsets: []int32{0, 1},
+			children: []*testColumn{
+				{
+					name:   "item",
+					typ:    typeFloat,
+					floats: []float64{1},
+				},
+			},
+		},
+	}
+
+	bar := &BatchArrowRecords{
+		BatchID: 1,
+		ArrowPayloads: []ArrowPayload{
+			{
+				SchemaID: "resource_attrs",
+				Type:     ArrowPayloadTypeResourceAttrs,
+				Record:   newTestStream(resourceAttrs, -1),
+			},
+			{
+				SchemaID: "metrics",
+				Type:     ArrowPayloadTypeUnivariateMetrics,
+				Record:   newTestStream(metrics, codecZSTD),
+			},
+			{
+				SchemaID: "number_dp",
+				Type:     ArrowPayloadTypeNumberDataPoints,
+				Record:   newTestStream(numberDataPoints, codecLZ4Frame),
+			},
+			{
+				SchemaID: "number_dp_attrs",
+				Type:     ArrowPayloadTypeNumberDataPointAttrs,
+				Record:   newTestStream(numberDataPointAttrs, -1),
+			},
+			{
+				SchemaID: "histogram_dp",
+				Type:     ArrowPayloadTypeHistogramDataPoints,
+				Record:   newTestStream(histogramDataPoints, -1),
+			},
+		},
+	}
+
+	// Verify that bar passes protobuf marshaling round trip.
+	data := marshalBatchArrowRecords(bar)
+	var barUnmarshaled BatchArrowRecords
+	if err := barUnmarshaled.UnmarshalProtobuf(data); err != nil {
+		t.Fatalf("cannot unmarshal BatchArrowRecords: %s", err)
+	}
+	if !reflect.DeepEqual(&barUnmarshaled, bar) {
+		t.Fatalf("unexpected BatchArrowRecords after unmarshaling\ngot\n%#v\nwant\n%#v", &barUnmarshaled, bar)
+	}
+
+	mc := NewMetricsConsumer()
+	req, err := mc.Convert(&barUnmarshaled)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	strValue := func(s string) *pb.AnyValue {
+		return &pb.AnyValue{
+			StringValue: &s,
+		}
+	}
+	intValue := func(n int64) *pb.AnyValue {
+		return &pb.AnyValue{
+			IntValue: &n,
+		}
+	}
+	doubleValue := 1.5
+	int64Value := int64(42)
+	sum := 4.5
+	reqExpected := &pb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*pb.ResourceMetrics{
+			{
+				Resource: &pb.Resource{
+					Attributes: []*pb.KeyValue{
+						{
+							Key:   "job",
+							Value: strValue("foo"),
+						},
+						{
+							Key:   "instance",
+							Value: intValue(123),
+						},
+					},
+				},
+				ScopeMetrics: []*pb.ScopeMetrics{
+					{
+						Scope: &pb.InstrumentationScope{
+							Name: "scope",
+						},
+						Metrics: []*pb.Metric{
+							{
+								Name: "gauge",
+								Gauge: &pb.Gauge{
+									DataPoints: []*pb.NumberDataPoint{
+										{
+											TimeUnixNano: 1000,
+											DoubleValue:  &doubleValue,
+										},
+									},
+								},
+							},
+							{
+								Name: "sum",
+								Sum: &pb.Sum{
+									AggregationTemporality: pb.AggregationTemporalityCumulative,
+									IsMonotonic:            true,
+									DataPoints: []*pb.NumberDataPoint{
+										{
+											Attributes: []*pb.KeyValue{
+												{
+													Key:   "foo",
+													Value: strValue("bar"),
+												},
+											},
+											TimeUnixNano: 2000,
+											IntValue:     &int64Value,
+										},
+									},
+								},
+							},
+							{
+								Name: "histogram",
+								Histogram: &pb.Histogram{
+									AggregationTemporality: pb.AggregationTemporalityDelta,
+									DataPoints: []*pb.HistogramDataPoint{
+										{
+											TimeUnixNano:   3000,
+											Count:          3,
+											Sum:            &sum,
+											BucketCounts:   []uint64{1, 2},
+											ExplicitBounds: []float64{1},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(req, reqExpected) {
+		t.Fatalf("unexpected request\ngot\n%s\nwant\n%s", req.MarshalProtobuf(nil), reqExpected.MarshalProtobuf(nil))
+	}
+
+	// Invalid Arrow payload must result in error.
+	bar.ArrowPayloads[0].Record = bar.ArrowPayloads[0].Record[:10]
+	bar.ArrowPayloads[0].SchemaID = "invalid"
+	if _, err := mc.Convert(bar); err == nil {
+		t.Fatalf("expecting non-nil error for invalid payload")
+	}
+}
+
+func marshalBatchArrowRecords(bar *BatchArrowRecords) []byte {
+	m := mp.Get()
+	mm := m.MessageMarshaler()
+	mm.AppendInt64(1, bar.BatchID)
+	for _, ap := range bar.ArrowPayloads {
+		apm := mm.AppendMessage(2)
+		apm.AppendString(1, ap.SchemaID)
+		apm.AppendInt32(2, int32(ap.Type))
+		apm.AppendBytes(3, ap.Record)
+	}
+	dst := m.Marshal(nil)
+	mp.Put(m)
+	return dst
+}
@@ -0,0 +1,155 @@
+package arrow
+
+import (
+	"fmt"
+
+	"github.com/VictoriaMetrics/easyproto"
+)
+
+// ArrowPayloadType is the type of ArrowPayload.
+//
+// See https://github.com/open-telemetry/otel-arrow/blob/main/proto/opentelemetry/proto/experimental/arrow/v1/arrow_service.proto
+type ArrowPayloadType int32
+
+// The supported ArrowPayload types.
+const (
+	ArrowPayloadTypeResourceAttrs              = ArrowPayloadType(1)
+	ArrowPayloadTypeScopeAttrs                 = ArrowPayloadType(2)
+	ArrowPayloadTypeUnivariateMetrics          = ArrowPayloadType(10)
+	ArrowPayloadTypeNumberDataPoints           = ArrowPayloadType(11)
+	ArrowPayloadTypeSummaryDataPoints          = ArrowPayloadType(12)
+	ArrowPayloadTypeHistogramDataPoints        = ArrowPayloadType(13)
+	ArrowPayloadTypeExpHistogramDataPoints     = ArrowPayloadType(14)
+	ArrowPayloadTypeNumberDataPointAttrs       = ArrowPayloadType(15)
+	ArrowPayloadTypeSummaryDataPointAttrs      = ArrowPayloadType(16)
+	ArrowPayloadTypeHistogramDataPointAttrs    = ArrowPayloadType(17)
+	ArrowPayloadTypeExpHistogramDataPointAttrs = ArrowPayloadType(18)
+)
+
+// BatchArrowRecords represents the corresponding OTel Arrow protobuf message.
+type BatchArrowRecords struct {
+	BatchID       int64
+	ArrowPayloads []ArrowPayload
+}
+
+// ArrowPayload represents the corresponding OTel Arrow protobuf message.
+type ArrowPayload struct {
+	// SchemaID identifies Arrow IPC stream the Record belongs to.
+	SchemaID string
+
+	Type ArrowPayloadType
+
+	// Record contains Arrow IPC stream messages.
+	Record []byte
+}
+
+// UnmarshalProtobuf unmarshals bar from protobuf message at src.
+//
+// bar refers to src, so src mustn't be modified while bar is in use.
+func (bar *BatchArrowRecords) UnmarshalProtobuf(src []byte) (err error) {
+	// message BatchArrowRecords {
+	//   int64 batch_id = 1;
+	//   repeated ArrowPayload arrow_payloads = 2;
+	//   bytes headers = 3;
+	// }
+	bar.BatchID = 0
+	bar.ArrowPayloads = bar.ArrowPayloads[:0]
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in BatchArrowRecords: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			batchID, ok := fc.Int64()
+			if !ok {
+				return fmt.Errorf("cannot read BatchID")
+			}
+			bar.BatchID = batchID
+		case 2:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read ArrowPayload data")
+			}
+			bar.ArrowPayloads = append(bar.ArrowPayloads, ArrowPayload{})
+			ap := &bar.ArrowPayloads[len(bar.ArrowPayloads)-1]
+			if err := ap.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal ArrowPayload: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (ap *ArrowPayload) unmarshalProtobuf(src []byte) (err error) {
+	// message ArrowPayload {
+	//   string schema_id = 1;
+	//   ArrowPayloadType type = 2;
+	//   bytes record = 3;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in ArrowPayload: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			schemaID, ok := fc.String()
+			if !ok {
+				return fmt.Errorf("cannot read SchemaID")
+			}
+			ap.SchemaID = schemaID
+		case 2:
+			typ, ok := fc.Int32()
+			if !ok {
+				return fmt.Errorf("cannot read Type")
+			}
+			ap.Type = ArrowPayloadType(typ)
+		case 3:
+			record, ok := fc.Bytes()
+			if !ok {
+				return fmt.Errorf("cannot read Record")
+			}
+			ap.Record = record
+		}
+	}
+	return nil
+}
+
+// StatusCode is the status code for BatchStatus.
+type StatusCode int32
+
+// The supported status codes.
+const (
+	StatusCodeOK              = StatusCode(0)
+	StatusCodeInvalidArgument = StatusCode(3)
+	StatusCodeUnavailable     = StatusCode(14)
+)
+
+// BatchStatus represents the corresponding OTel Arrow protobuf message.
+type BatchStatus struct {
+	BatchID       int64
+	StatusCode    StatusCode
+	StatusMessage string
+}
+
+// MarshalProtobuf marshals bs to protobuf message, appends it to dst and returns the result.
+func (bs *BatchStatus) MarshalProtobuf(dst []byte) []byte {
+	// message BatchStatus {
+	//   int64 batch_id = 1;
+	//   StatusCode status_code = 2;
+	//   string status_message = 3;
+	// }
+	m := mp.Get()
+	mm := m.MessageMarshaler()
+	mm.AppendInt64(1, bs.BatchID)
+	mm.AppendInt32(2, int32(bs.StatusCode))
+	mm.AppendString(3, bs.StatusMessage)
+	dst = m.Marshal(dst)
+	mp.Put(m)
+	return dst
+}
+
+var mp easyproto.MarshalerPool
@@ -0,0 +1,38 @@
+package arrow
+
+import (
+	"testing"
+
+	"github.com/VictoriaMetrics/easyproto"
+)
+
+func TestBatchStatusMarshalProtobuf(t *testing.T) {
+	bs := &BatchStatus{
+		BatchID:       123,
+		StatusCode:    StatusCodeInvalidArgument,
+		StatusMessage: "foo",
+	}
+	data := bs.MarshalProtobuf(nil)
+
+	var bsGot BatchStatus
+	var fc easyproto.FieldContext
+	for len(data) > 0 {
+		var err error
+		data, err = fc.NextField(data)
+		if err != nil {
+			t.Fatalf("cannot read next field: %s", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			bsGot.BatchID, _ = fc.Int64()
+		case 2:
+			code, _ := fc.Int32()
+			bsGot.StatusCode = StatusCode(code)
+		case 3:
+			bsGot.StatusMessage, _ = fc.String()
+		}
+	}
+	if bsGot != *bs {
+		t.Fatalf("unexpected BatchStatus; got %#v; want %#v", &bsGot, bs)
+	}
+}
@@ -0,0 +1,150 @@
+package firehose
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+)
+
+// isCloudWatchJSON returns true if data contains CloudWatch metric stream records in JSON format.
+//
+// Records in OpenTelemetry format start with varint-encoded message length followed by protobuf field tag,
+// so they cannot start with `{"`.
+func isCloudWatchJSON(data []byte) bool {
+	return len(data) > 1 && data[0] == '{' && data[1] == '"'
+}
+
+// cloudWatchMetric represents CloudWatch metric stream record in JSON format.
+//
+// See https://docs.aws.amazon.com/AmazonCloudWatch/latest/monitoring/CloudWatch-metric-streams-formats-json.html
+type cloudWatchMetric struct {
+	MetricStreamName string             `json:"metric_stream_name"`
+	AccountID        string             `json:"account_id"`
+	Region           string             `json:"region"`
+	Namespace        string             `json:"namespace"`
+	MetricName       string             `json:"metric_name"`
+	Dimensions       map[string]string  `json:"dimensions"`
+	Timestamp        int64              `json:"timestamp"`
+	Value            map[string]float64 `json:"value"`
+	Unit             string             `json:"unit"`
+}
+
+// appendOpenTelemetryFromCloudWatchJSON converts newline-delimited CloudWatch metric stream records in JSON format at data
+// to OpenTelemetry protobuf message, appends it to dst and returns the result.
+//
+// Every record is converted to summary with the same metric name and labels as CloudWatch metric streams
+// in OpenTelemetry 0.7 format have, so the collected metrics do not depend on the format selected for the metric stream.
+func appendOpenTelemetryFromCloudWatchJSON(dst, data []byte) ([]byte, error) {
+	var req pb.ExportMetricsServiceRequest
+	for len(data) > 0 {
+		line := data
+		data = nil
+		if n := bytes.IndexByte(line, '\n'); n >= 0 {
+			data = line[n+1:]
+			line = line[:n]
+		}
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var m cloudWatchMetric
+		if err := json.Unmarshal(line, &m); err != nil {
+			return dst, fmt.Errorf("cannot unmarshal CloudWatch metric stream record %q: %w", line, err)
+		}
+		if m.Namespace == "" || m.MetricName == "" {
+			return dst, fmt.Errorf("missing namespace or metric_name in CloudWatch metric stream record %q", line)
+		}
+		req.ResourceMetrics = append(req.ResourceMetrics, m.toResourceMetrics())
+	}
+	return req.MarshalProtobuf(dst), nil
+}
+
+func (m *cloudWatchMetric) toResourceMetrics() *pb.ResourceMetrics {
+	arn := fmt.Sprintf("arn:aws:cloudwatch:%s:%s:metric-stream/%s", m.Region, m.AccountID, m.MetricStreamName)
+	resource := &pb.Resource{
+		Attributes: []*pb.KeyValue{
+			newStringKeyValue("cloud.provider", "aws"),
+			newStringKeyValue("cloud.account.id", m.AccountID),
+			newStringKeyValue("cloud.region", m.Region),
+			newStringKeyValue("aws.exporter.arn", arn),
+		},
+	}
+
+	attrs := []*pb.KeyValue{
+		newStringKeyValue("Namespace", m.Namespace),
+		newStringKeyValue("MetricName", m.MetricName),
+	}
+	dimensions := make([]string, 0, len(m.Dimensions))
+	for k := range m.Dimensions {
+		dimensions = append(dimensions, k)
+	}
+	sort.Strings(dimensions)
+	for _, k := range dimensions {
+		attrs = append(attrs, newStringKeyValue(k, m.Dimensions[k]))
+	}
+
+	// min and max values are exported as 0 and 1 quantiles, while percentiles such as p99 are exported as the corresponding quantiles.
+	// See https://docs.aws.amazon.com/AmazonCloudWatch/latest/monitoring/CloudWatch-metric-streams-formats-opentelemetry-translation.html
+	quantiles := []*pb.ValueAtQuantile{
+		{
+			Quantile: 0,
+			Value:    m.Value["min"],
+		},
+		{
+			Quantile: 1,
+			Value:    m.Value["max"],
+		},
+	}
+	for k, v := range m.Value {
+		if !strings.HasPrefix(k, "p") {
+			continue
+		}
+		percentile, err := strconv.ParseFloat(k[1:], 64)
+		if err != nil || percentile <= 0 || percentile >= 100 {
+			continue
+		}
+		quantiles = append(quantiles, &pb.ValueAtQuantile{
+			Quantile: percentile / 100,
+			Value:    v,
+		})
+	}
+	sort.Slice(quantiles, func(i, j int) bool {
+		return quantiles[i].Quantile < quantiles[j].Quantile
+	})
+
+	metric := &pb.Metric{
+		Name: "amazonaws.com/" + m.Namespace + "/" + m.MetricName,
+		Summary: &pb.Summary{
+			DataPoints: []*pb.SummaryDataPoint{{
+				Attributes:     attrs,
+				TimeUnixNano:   uint64(m.Timestamp) * 1e6,
+				Count:          uint64(m.Value["count"]),
+				Sum:            m.Value["sum"],
+				QuantileValues: quantiles,
+			}},
+		},
+	}
+	if m.Unit != "" {
+		metric.Unit = "{" + m.Unit + "}"
+	}
+	return &pb.ResourceMetrics{
+		Resource: resource,
+		ScopeMetrics: []*pb.ScopeMetrics{{
+			Metrics: []*pb.Metric{metric},
+		}},
+	}
+}
+
+func newStringKeyValue(key, value string) *pb.KeyValue {
+	return &pb.KeyValue{
+		Key: key,
+		Value: &pb.AnyValue{
+			StringValue: &value,
+		},
+	}
+}
@@ -0,0 +1,73 @@
+package firehose
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/stream"
+)
+
+func TestProcessRequestBodyCloudWatchJSON(t *testing.T) {
+	f := func(records []string, sExpected string) {
+		t.Helper()
+		var bb bytes.Buffer
+		bb.WriteString(`{"requestId":"94885867-d282-4110-a3c5-4af3f9ce1150","timestamp":1709217414040,"records":[`)
+		for i, r := range records {
+			if i > 0 {
+				bb.WriteString(",")
+			}
+			fmt.Fprintf(&bb, `{"data":%q}`, base64.StdEncoding.EncodeToString([]byte(r)))
+		}
+		bb.WriteString(`]}`)
+
+		var s string
+		err := stream.ParseStream(&bb, "", ProcessRequestBody, func(tss []prompbmarshal.TimeSeries) error {
+			s += formatTimeseries(tss)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if s != sExpected {
+			t.Fatalf("unexpected timeseries; got\n%s\nwant\n%s", s, sExpected)
+		}
+	}
+
+	// empty record
+	f([]string{""}, "")
+
+	// multiple records with dimensions and percentiles
+	f([]string{
+		`{"metric_stream_name":"s","account_id":"123","region":"us-east-1","namespace":"AWS/EC2","metric_name":"DiskWriteOps","dimensions":{"InstanceId":"i-1"},"timestamp":1611929698000,"value":{"max":3.0,"min":0.0,"sum":9.0,"count":4.0,"p99":2.5},"unit":"Count"}
+{"metric_stream_name":"s","account_id":"123","region":"us-east-1","namespace":"AWS/EBS","metric_name":"VolumeReadOps","dimensions":{},"timestamp":1611929698000,"value":{"max":1.0,"min":1.0,"sum":1.0,"count":1.0},"unit":"Count"}
+`,
+	}, `{__name__="amazonaws.com/AWS/EC2/DiskWriteOps_sum",cloud.provider="aws",cloud.account.id="123",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:123:metric-stream/s",Namespace="AWS/EC2",MetricName="DiskWriteOps",InstanceId="i-1"} 9 1611929698000
+{__name__="amazonaws.com/AWS/EC2/DiskWriteOps_count",cloud.provider="aws",cloud.account.id="123",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:123:metric-stream/s",Namespace="AWS/EC2",MetricName="DiskWriteOps",InstanceId="i-1"} 4 1611929698000
+{__name__="amazonaws.com/AWS/EC2/DiskWriteOps",cloud.provider="aws",cloud.account.id="123",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:123:metric-stream/s",Namespace="AWS/EC2",MetricName="DiskWriteOps",InstanceId="i-1",quantile="0"} 0 1611929698000
+{__name__="amazonaws.com/AWS/EC2/DiskWriteOps",cloud.provider="aws",cloud.account.id="123",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:123:metric-stream/s",Namespace="AWS/EC2",MetricName="DiskWriteOps",InstanceId="i-1",quantile="0.99"} 2.5 1611929698000
+{__name__="amazonaws.com/AWS/EC2/DiskWriteOps",cloud.provider="aws",cloud.account.id="123",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:123:metric-stream/s",Namespace="AWS/EC2",MetricName="DiskWriteOps",InstanceId="i-1",quantile="1"} 3 1611929698000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="123",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:123:metric-stream/s",Namespace="AWS/EBS",MetricName="VolumeReadOps"} 1 1611929698000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="123",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:123:metric-stream/s",Namespace="AWS/EBS",MetricName="VolumeReadOps"} 1 1611929698000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="123",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:123:metric-stream/s",Namespace="AWS/EBS",MetricName="VolumeReadOps",quantile="0"} 1 1611929698000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="123",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:123:metric-stream/s",Namespace="AWS/EBS",MetricName="VolumeReadOps",quantile="1"} 1 1611929698000
+`)
+}
+
+func TestProcessRequestBodyCloudWatchJSONFailure(t *testing.T) {
+	f := func(record string) {
+		t.Helper()
+		body := fmt.Sprintf(`{"records":[{"data":%q}]}`, base64.StdEncoding.EncodeToString([]byte(record)))
+		if _, err := ProcessRequestBody([]byte(body)); err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	// invalid JSON
+	f(`{"metric_stream_name":`)
+
+	// missing metric_name
+	f(`{"namespace":"AWS/EC2","value":{"sum":1}}`)
+}
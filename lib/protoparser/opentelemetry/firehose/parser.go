@@ -12,6 +12,8 @@ import (
 //
 // See https://docs.aws.amazon.com/AmazonCloudWatch/latest/monitoring/CloudWatch-Metric-Streams.html
 //
+// Records in both OpenTelemetry 0.7 and JSON formats are supported. Records in JSON format are converted into OpenTelemetry summaries.
+//
 // It joins decoded "data" fields from "record" list:
 //
 //	{
@@ -35,6 +37,14 @@ func ProcessRequestBody(b []byte) ([]byte, error) {
 
 	var dst []byte
 	for _, r := range req.Records {
+		if isCloudWatchJSON(r.Data) {
+			var err error
+			dst, err = appendOpenTelemetryFromCloudWatchJSON(dst, r.Data)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
 		for len(r.Data) > 0 {
 			messageLength, varIntLength := binary.Uvarint(r.Data)
 			if varIntLength > binary.MaxVarintLen32 {
@@ -14,217 +14,217 @@ import (
 func TestProcessRequestBody(t *testing.T) {
 	data := []byte(`{"requestId":"94885867-d282-4110-a3c5-4af3f9ce1150","timestamp":1709217414040,"records":[{"data":"oB0KnR0KuwEKFwoOY2xvdWQucHJvdmlkZXISBQoDYXdzCiIKEGNsb3VkLmFjY291bnQuaWQSDgoMNjc3NDM1ODkwNTk4ChsKDGNsb3VkLnJlZ2lvbhILCgl1cy1lYXN0LTEKXwoQYXdzLmV4cG9ydGVyLmFybhJLCklhcm46YXdzOmNsb3Vkd2F0Y2g6dXMtZWFzdC0xOjY3NzQzNTg5MDU5ODptZXRyaWMtc3RyZWFtL2N1c3RvbV9lYnNfbWV0cmljEtwbErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMDg2Y2ZjMTA4NTQwOGUwZGMRABjlKfhbuBcZAHAsIgZcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMDZkMDc4YWIxYmNjMDBlYzMRABjlKfhbuBcZAHAsIgZcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMGJkMTU0NjVkNjljMjNhOWERABjlKfhbuBcZAHAsIgZcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMDM3YjdmMjg3ZWViNzlmYTkRABjlKfhbuBcZAHAsIgZcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMGJlZWY0OWRlMGQ2OGYzMmMRABjlKfhbuBcZAHAsIgZcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMDMzMTMzMjU5ZGY2N2JiOTcRABjlKfhbuBcZAHAsIgZcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMDljZDEwMGIxNTliYjI1ZDYRABjlKfhbuBcZAHAsIgZcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMGU4MzgzMTkyMWQ3MzU1NjMRABjlKfhbuBcZAHAsIgZcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMDIzZWQzMjZhZTg2MDA1NWERABjlKfhbuBcZAHAsIgZcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMGJjYTQ2ZTAyMjQzZjdhNTQRABjlKfhbuBcZAHAsIgZcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMGZmOGMzODkzNDNmZTZlMGYRABjlKfhbuBcZAHAsIgZcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMDYyZDE4MmE5ZTNkNjk3MWYRABjlKfhbuBcZAHAsIgZcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMDAzMDUyZjNiMzlkNjI3OWMRABjlKfhbuBcZAHAsIgZcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErECCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAgp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMGM0NDEwOTk3YmUyMzEzNDARABjlKfhbuBcZAHAsIgZcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/Cn4KFAoJTmFtZXNwYWNlEgdBV1MvRUJTChsKCk1ldHJpY05hbWUSDVZvbHVtZVJlYWRPcHMKIQoIVm9sdW1lSWQSFXZvbC0wYzQ0MTA5OTdiZTIzMTM0MBEAwJ0x6lu4FxkAGOUp+Fu4FyEBAAAAAAAAADIAMgkJAAAAAAAA8D8SsQEKI2FtYXpvbmF3cy5jb20vQVdTL0VCUy9Wb2x1bWVSZWFkT3BzGgd7Q291bnR9WoABCn4KFAoJTmFtZXNwYWNlEgdBV1MvRUJTChsKCk1ldHJpY05hbWUSDVZvbHVtZVJlYWRPcHMKIQoIVm9sdW1lSWQSFXZvbC0wM2VlNzM1Y2VkZjFmNDZjZREAGOUp+Fu4FxkAcCwiBly4FyEBAAAAAAAAADIAMgkJAAAAAAAA8D8SsQEKI2FtYXpvbmF3cy5jb20vQVdTL0VCUy9Wb2x1bWVSZWFkT3BzGgd7Q291bnR9WoABCn4KFAoJTmFtZXNwYWNlEgdBV1MvRUJTChsKCk1ldHJpY05hbWUSDVZvbHVtZVJlYWRPcHMKIQoIVm9sdW1lSWQSFXZvbC0wOThmZmY4ZTc5ZmJkMmVmNxEAGOUp+Fu4FxkAcCwiBly4FyEBAAAAAAAAADIAMgkJAAAAAAAA8D8SsQEKI2FtYXpvbmF3cy5jb20vQVdTL0VCUy9Wb2x1bWVSZWFkT3BzGgd7Q291bnR9WoABCn4KFAoJTmFtZXNwYWNlEgdBV1MvRUJTChsKCk1ldHJpY05hbWUSDVZvbHVtZVJlYWRPcHMKIQoIVm9sdW1lSWQSFXZvbC0wMWY2ZjNhMzEwNGM4ZWRjYREAGOUp+Fu4FxkAcCwiBly4FyEBAAAAAAAAADIAMgkJAAAAAAAA8D8SsQEKI2FtYXpvbmF3cy5jb20vQVdTL0VCUy9Wb2x1bWVSZWFkT3BzGgd7Q291bnR9WoABCn4KFAoJTmFtZXNwYWNlEgdBV1MvRUJTChsKCk1ldHJpY05hbWUSDVZvbHVtZVJlYWRPcHMKIQoIVm9sdW1lSWQSFXZvbC0wN2E3NmViNGJhMDVlODNkMREAwJ0x6lu4FxkAGOUp+Fu4FyEBAAAAAAAAADIAMgkJAAAAAAAA8D8SsQEKI2FtYXpvbmF3cy5jb20vQVdTL0VCUy9Wb2x1bWVSZWFkT3BzGgd7Q291bnR9WoABCn4KFAoJTmFtZXNwYWNlEgdBV1MvRUJTChsKCk1ldHJpY05hbWUSDVZvbHVtZVJlYWRPcHMKIQoIVm9sdW1lSWQSFXZvbC0wMTFiYjVjNWJkZDc2ZDk2NREAGOUp+Fu4FxkAcCwiBly4FyEBAAAAAAAAADIAMgkJAAAAAAAA8D8="},{"data":"9CwK8SwKuwEKFwoOY2xvdWQucHJvdmlkZXISBQoDYXdzCiIKEGNsb3VkLmFjY291bnQuaWQSDgoMNjc3NDM1ODkwNTk4ChsKDGNsb3VkLnJlZ2lvbhILCgl1cy1lYXN0LTEKXwoQYXdzLmV4cG9ydGVyLmFybhJLCklhcm46YXdzOmNsb3Vkd2F0Y2g6dXMtZWFzdC0xOjY3NzQzNTg5MDU5ODptZXRyaWMtc3RyZWFtL2N1c3RvbV9lYnNfbWV0cmljErArErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMGQ1NDc2ZGI3ZWQ2OWVlMTcRABjlKfhbuBcZAHAsIgZcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMDRmZGY0YTExZGQ5Yzk1ZTURAHAsIgZcuBcZAMhzGhRcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMDM0ODNlODQwYTg5MjkxZDURAHAsIgZcuBcZAMhzGhRcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMDRhYjlkN2VkM2M4MzEyNjQRABjlKfhbuBcZAHAsIgZcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMDA5OGQwODYyNWYxNGVlMDkRAHAsIgZcuBcZAMhzGhRcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMDUyOGEyNDhiNGQ3Nzk2ZTARAHAsIgZcuBcZAMhzGhRcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMDdiY2FlNDRiMGFlZGVhNTURAHAsIgZcuBcZAMhzGhRcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMGNhZDQzNzQzOWUzODFjZjYRAHAsIgZcuBcZAMhzGhRcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErECCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAgp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMDJmNjM0ZDEyNjY2N2NjYjgRAHAsIgZcuBcZAMhzGhRcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/Cn4KFAoJTmFtZXNwYWNlEgdBV1MvRUJTChsKCk1ldHJpY05hbWUSDVZvbHVtZVJlYWRPcHMKIQoIVm9sdW1lSWQSFXZvbC0wMmY2MzRkMTI2NjY3Y2NiOBEAGOUp+Fu4FxkAcCwiBly4FyEBAAAAAAAAADIAMgkJAAAAAAAA8D8SsQEKI2FtYXpvbmF3cy5jb20vQVdTL0VCUy9Wb2x1bWVSZWFkT3BzGgd7Q291bnR9WoABCn4KFAoJTmFtZXNwYWNlEgdBV1MvRUJTChsKCk1ldHJpY05hbWUSDVZvbHVtZVJlYWRPcHMKIQoIVm9sdW1lSWQSFXZvbC0wNTgxYWMwZjJkMWVmODM4ZBEAcCwiBly4FxkAyHMaFFy4FyEBAAAAAAAAADIAMgkJAAAAAAAA8D8SsQEKI2FtYXpvbmF3cy5jb20vQVdTL0VCUy9Wb2x1bWVSZWFkT3BzGgd7Q291bnR9WoABCn4KFAoJTmFtZXNwYWNlEgdBV1MvRUJTChsKCk1ldHJpY05hbWUSDVZvbHVtZVJlYWRPcHMKIQoIVm9sdW1lSWQSFXZvbC0wZDhmNWQ5MWFiYjMxNTNiNREAcCwiBly4FxkAyHMaFFy4FyEBAAAAAAAAADIAMgkJAAAAAAAA8D8SsQEKI2FtYXpvbmF3cy5jb20vQVdTL0VCUy9Wb2x1bWVSZWFkT3BzGgd7Q291bnR9WoABCn4KFAoJTmFtZXNwYWNlEgdBV1MvRUJTChsKCk1ldHJpY05hbWUSDVZvbHVtZVJlYWRPcHMKIQoIVm9sdW1lSWQSFXZvbC0wY2U2ZGMwN2QyZDQ1MGUwMhEAcCwiBly4FxkAyHMaFFy4FyEBAAAAAAAAADIAMgkJAAAAAAAA8D8SsQEKI2FtYXpvbmF3cy5jb20vQVdTL0VCUy9Wb2x1bWVSZWFkT3BzGgd7Q291bnR9WoABCn4KFAoJTmFtZXNwYWNlEgdBV1MvRUJTChsKCk1ldHJpY05hbWUSDVZvbHVtZVJlYWRPcHMKIQoIVm9sdW1lSWQSFXZvbC0wNWJjNjZjNmM5NDZjMzRlNhEAcCwiBly4FxkAyHMaFFy4FyEBAAAAAAAAADIAMgkJAAAAAAAA8D8SsQIKI2FtYXpvbmF3cy5jb20vQVdTL0VCUy9Wb2x1bWVSZWFkT3BzGgd7Q291bnR9WoACCn4KFAoJTmFtZXNwYWNlEgdBV1MvRUJTChsKCk1ldHJpY05hbWUSDVZvbHVtZVJlYWRPcHMKIQoIVm9sdW1lSWQSFXZvbC0wNDkwMjlmZTZjNDdhZjdhNhEAcCwiBly4FxkAyHMaFFy4FyEBAAAAAAAAADIAMgkJAAAAAAAA8D8KfgoUCglOYW1lc3BhY2USB0FXUy9FQlMKGwoKTWV0cmljTmFtZRINVm9sdW1lUmVhZE9wcwohCghWb2x1bWVJZBIVdm9sLTA0OTAyOWZlNmM0N2FmN2E2EQAY5Sn4W7gXGQBwLCIGXLgXIQEAAAAAAAAAMgAyCQkAAAAAAADwPxKxAQojYW1hem9uYXdzLmNvbS9BV1MvRUJTL1ZvbHVtZVJlYWRPcHMaB3tDb3VudH1agAEKfgoUCglOYW1lc3BhY2USB0FXUy9FQlMKGwoKTWV0cmljTmFtZRINVm9sdW1lUmVhZE9wcwohCghWb2x1bWVJZBIVdm9sLTAwMDNmMTcyMDYzMmJhM2FhEQBwLCIGXLgXGQDIcxoUXLgXIQEAAAAAAAAAMgAyCQkAAAAAAADwPxKxAQojYW1hem9uYXdzLmNvbS9BV1MvRUJTL1ZvbHVtZVJlYWRPcHMaB3tDb3VudH1agAEKfgoUCglOYW1lc3BhY2USB0FXUy9FQlMKGwoKTWV0cmljTmFtZRINVm9sdW1lUmVhZE9wcwohCghWb2x1bWVJZBIVdm9sLTAyMjZkMWUzMGNmMTFjYzE3EQAY5Sn4W7gXGQBwLCIGXLgXIQEAAAAAAAAAMgAyCQkAAAAAAADwPxKxAQojYW1hem9uYXdzLmNvbS9BV1MvRUJTL1ZvbHVtZVJlYWRPcHMaB3tDb3VudH1agAEKfgoUCglOYW1lc3BhY2USB0FXUy9FQlMKGwoKTWV0cmljTmFtZRINVm9sdW1lUmVhZE9wcwohCghWb2x1bWVJZBIVdm9sLTA4YmNiZGU4ODkwNDcwYjdmEQBwLCIGXLgXGQDIcxoUXLgXIQEAAAAAAAAAMgAyCQkAAAAAAADwPxKxAQojYW1hem9uYXdzLmNvbS9BV1MvRUJTL1ZvbHVtZVJlYWRPcHMaB3tDb3VudH1agAEKfgoUCglOYW1lc3BhY2USB0FXUy9FQlMKGwoKTWV0cmljTmFtZRINVm9sdW1lUmVhZE9wcwohCghWb2x1bWVJZBIVdm9sLTBhZjA3MGJjMzkxMDRjYzQ1EQBwLCIGXLgXGQDIcxoUXLgXIQEAAAAAAAAAMgAyCQkAAAAAAADwPxKxAQojYW1hem9uYXdzLmNvbS9BV1MvRUJTL1ZvbHVtZVJlYWRPcHMaB3tDb3VudH1agAEKfgoUCglOYW1lc3BhY2USB0FXUy9FQlMKGwoKTWV0cmljTmFtZRINVm9sdW1lUmVhZE9wcwohCghWb2x1bWVJZBIVdm9sLTA0YjZhMTZiYTYyM2UyZjQxEQAY5Sn4W7gXGQBwLCIGXLgXIQEAAAAAAAAAMgAyCQkAAAAAAADwPxKxAQojYW1hem9uYXdzLmNvbS9BV1MvRUJTL1ZvbHVtZVJlYWRPcHMaB3tDb3VudH1agAEKfgoUCglOYW1lc3BhY2USB0FXUy9FQlMKGwoKTWV0cmljTmFtZRINVm9sdW1lUmVhZE9wcwohCghWb2x1bWVJZBIVdm9sLTAwNTliNzExODZmZjI3MDQ1EQAY5Sn4W7gXGQBwLCIGXLgXIQEAAAAAAAAAMgAyCQkAAAAAAADwPxKxAQojYW1hem9uYXdzLmNvbS9BV1MvRUJTL1ZvbHVtZVJlYWRPcHMaB3tDb3VudH1agAEKfgoUCglOYW1lc3BhY2USB0FXUy9FQlMKGwoKTWV0cmljTmFtZRINVm9sdW1lUmVhZE9wcwohCghWb2x1bWVJZBIVdm9sLTBiM2QwNGFjYmQ3YWIyNjVhEQBwLCIGXLgXGQDIcxoUXLgXIQEAAAAAAAAAMgAyCQkAAAAAAADwPxKxAQojYW1hem9uYXdzLmNvbS9BV1MvRUJTL1ZvbHVtZVJlYWRPcHMaB3tDb3VudH1agAEKfgoUCglOYW1lc3BhY2USB0FXUy9FQlMKGwoKTWV0cmljTmFtZRINVm9sdW1lUmVhZE9wcwohCghWb2x1bWVJZBIVdm9sLTA0YjdmOGRjMjY0NjZmYTZjEQBwLCIGXLgXGQDIcxoUXLgXIQEAAAAAAAAAMgAyCQkAAAAAAADwPxKxAQojYW1hem9uYXdzLmNvbS9BV1MvRUJTL1ZvbHVtZVJlYWRPcHMaB3tDb3VudH1agAEKfgoUCglOYW1lc3BhY2USB0FXUy9FQlMKGwoKTWV0cmljTmFtZRINVm9sdW1lUmVhZE9wcwohCghWb2x1bWVJZBIVdm9sLTBkOTUwOGMxOGEyNzYxOTdkEQBwLCIGXLgXGQDIcxoUXLgXIQEAAAAAAAAAMgAyCQkAAAAAAADwPxKxAgojYW1hem9uYXdzLmNvbS9BV1MvRUJTL1ZvbHVtZVJlYWRPcHMaB3tDb3VudH1agAIKfgoUCglOYW1lc3BhY2USB0FXUy9FQlMKGwoKTWV0cmljTmFtZRINVm9sdW1lUmVhZE9wcwohCghWb2x1bWVJZBIVdm9sLTAyMjZhZWJjYTYxMTk4ZDY0EQBwLCIGXLgXGQDIcxoUXLgXIQEAAAAAAAAAMgAyCQkAAAAAAADwPwp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMDIyNmFlYmNhNjExOThkNjQRABjlKfhbuBcZAHAsIgZcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMDAzMmE4OTVmZGU1OWQ2OWQRAHAsIgZcuBcZAMhzGhRcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErEBCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAQp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMDg5ZWYxMzZiOWE2NjE2N2YRABjlKfhbuBcZAHAsIgZcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/ErECCiNhbWF6b25hd3MuY29tL0FXUy9FQlMvVm9sdW1lUmVhZE9wcxoHe0NvdW50fVqAAgp+ChQKCU5hbWVzcGFjZRIHQVdTL0VCUwobCgpNZXRyaWNOYW1lEg1Wb2x1bWVSZWFkT3BzCiEKCFZvbHVtZUlkEhV2b2wtMDA4ZTc3ZmNjOTFkNjM2NzARAHAsIgZcuBcZAMhzGhRcuBchAQAAAAAAAAAyADIJCQAAAAAAAPA/Cn4KFAoJTmFtZXNwYWNlEgdBV1MvRUJTChsKCk1ldHJpY05hbWUSDVZvbHVtZVJlYWRPcHMKIQoIVm9sdW1lSWQSFXZvbC0wMDhlNzdmY2M5MWQ2MzY3MBEAGOUp+Fu4FxkAcCwiBly4FyEBAAAAAAAAADIAMgkJAAAAAAAA8D8SsQEKI2FtYXpvbmF3cy5jb20vQVdTL0VCUy9Wb2x1bWVSZWFkT3BzGgd7Q291bnR9WoABCn4KFAoJTmFtZXNwYWNlEgdBV1MvRUJTChsKCk1ldHJpY05hbWUSDVZvbHVtZVJlYWRPcHMKIQoIVm9sdW1lSWQSFXZvbC0wNmMzNTlhOTc1NzUxMzYzYhEAcCwiBly4FxkAyHMaFFy4FyEBAAAAAAAAADIAMgkJAAAAAAAA8D8="}]}`)
 
-	sExpected := `{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217180000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217180000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217180000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217180000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217180000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217180000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217180000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217180000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217240000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric"} 1 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="0"} 0 1709217300000
-{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",quantile="1"} 0 1709217300000
+	sExpected := `{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-086cfc1085408e0dc"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-086cfc1085408e0dc"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-086cfc1085408e0dc",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-086cfc1085408e0dc",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-06d078ab1bcc00ec3"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-06d078ab1bcc00ec3"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-06d078ab1bcc00ec3",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-06d078ab1bcc00ec3",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0bd15465d69c23a9a"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0bd15465d69c23a9a"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0bd15465d69c23a9a",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0bd15465d69c23a9a",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-037b7f287eeb79fa9"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-037b7f287eeb79fa9"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-037b7f287eeb79fa9",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-037b7f287eeb79fa9",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0beef49de0d68f32c"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0beef49de0d68f32c"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0beef49de0d68f32c",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0beef49de0d68f32c",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-033133259df67bb97"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-033133259df67bb97"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-033133259df67bb97",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-033133259df67bb97",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-09cd100b159bb25d6"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-09cd100b159bb25d6"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-09cd100b159bb25d6",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-09cd100b159bb25d6",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0e83831921d735563"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0e83831921d735563"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0e83831921d735563",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0e83831921d735563",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-023ed326ae860055a"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-023ed326ae860055a"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-023ed326ae860055a",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-023ed326ae860055a",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0bca46e02243f7a54"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0bca46e02243f7a54"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0bca46e02243f7a54",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0bca46e02243f7a54",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0ff8c389343fe6e0f"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0ff8c389343fe6e0f"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0ff8c389343fe6e0f",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0ff8c389343fe6e0f",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-062d182a9e3d6971f"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-062d182a9e3d6971f"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-062d182a9e3d6971f",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-062d182a9e3d6971f",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-003052f3b39d6279c"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-003052f3b39d6279c"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-003052f3b39d6279c",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-003052f3b39d6279c",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0c4410997be231340"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0c4410997be231340"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0c4410997be231340",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0c4410997be231340",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0c4410997be231340"} 0 1709217180000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0c4410997be231340"} 1 1709217180000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0c4410997be231340",quantile="0"} 0 1709217180000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0c4410997be231340",quantile="1"} 0 1709217180000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-03ee735cedf1f46ce"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-03ee735cedf1f46ce"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-03ee735cedf1f46ce",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-03ee735cedf1f46ce",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-098fff8e79fbd2ef7"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-098fff8e79fbd2ef7"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-098fff8e79fbd2ef7",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-098fff8e79fbd2ef7",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-01f6f3a3104c8edca"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-01f6f3a3104c8edca"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-01f6f3a3104c8edca",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-01f6f3a3104c8edca",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-07a76eb4ba05e83d1"} 0 1709217180000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-07a76eb4ba05e83d1"} 1 1709217180000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-07a76eb4ba05e83d1",quantile="0"} 0 1709217180000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-07a76eb4ba05e83d1",quantile="1"} 0 1709217180000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-011bb5c5bdd76d965"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-011bb5c5bdd76d965"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-011bb5c5bdd76d965",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-011bb5c5bdd76d965",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0d5476db7ed69ee17"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0d5476db7ed69ee17"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0d5476db7ed69ee17",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0d5476db7ed69ee17",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-04fdf4a11dd9c95e5"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-04fdf4a11dd9c95e5"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-04fdf4a11dd9c95e5",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-04fdf4a11dd9c95e5",quantile="1"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-03483e840a89291d5"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-03483e840a89291d5"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-03483e840a89291d5",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-03483e840a89291d5",quantile="1"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-04ab9d7ed3c831264"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-04ab9d7ed3c831264"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-04ab9d7ed3c831264",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-04ab9d7ed3c831264",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0098d08625f14ee09"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0098d08625f14ee09"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0098d08625f14ee09",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0098d08625f14ee09",quantile="1"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0528a248b4d7796e0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0528a248b4d7796e0"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0528a248b4d7796e0",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0528a248b4d7796e0",quantile="1"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-07bcae44b0aedea55"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-07bcae44b0aedea55"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-07bcae44b0aedea55",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-07bcae44b0aedea55",quantile="1"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0cad437439e381cf6"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0cad437439e381cf6"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0cad437439e381cf6",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0cad437439e381cf6",quantile="1"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-02f634d126667ccb8"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-02f634d126667ccb8"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-02f634d126667ccb8",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-02f634d126667ccb8",quantile="1"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-02f634d126667ccb8"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-02f634d126667ccb8"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-02f634d126667ccb8",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-02f634d126667ccb8",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0581ac0f2d1ef838d"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0581ac0f2d1ef838d"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0581ac0f2d1ef838d",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0581ac0f2d1ef838d",quantile="1"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0d8f5d91abb3153b5"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0d8f5d91abb3153b5"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0d8f5d91abb3153b5",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0d8f5d91abb3153b5",quantile="1"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0ce6dc07d2d450e02"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0ce6dc07d2d450e02"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0ce6dc07d2d450e02",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0ce6dc07d2d450e02",quantile="1"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-05bc66c6c946c34e6"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-05bc66c6c946c34e6"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-05bc66c6c946c34e6",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-05bc66c6c946c34e6",quantile="1"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-049029fe6c47af7a6"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-049029fe6c47af7a6"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-049029fe6c47af7a6",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-049029fe6c47af7a6",quantile="1"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-049029fe6c47af7a6"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-049029fe6c47af7a6"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-049029fe6c47af7a6",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-049029fe6c47af7a6",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0003f1720632ba3aa"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0003f1720632ba3aa"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0003f1720632ba3aa",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0003f1720632ba3aa",quantile="1"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0226d1e30cf11cc17"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0226d1e30cf11cc17"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0226d1e30cf11cc17",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0226d1e30cf11cc17",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-08bcbde8890470b7f"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-08bcbde8890470b7f"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-08bcbde8890470b7f",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-08bcbde8890470b7f",quantile="1"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0af070bc39104cc45"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0af070bc39104cc45"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0af070bc39104cc45",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0af070bc39104cc45",quantile="1"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-04b6a16ba623e2f41"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-04b6a16ba623e2f41"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-04b6a16ba623e2f41",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-04b6a16ba623e2f41",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0059b71186ff27045"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0059b71186ff27045"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0059b71186ff27045",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0059b71186ff27045",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0b3d04acbd7ab265a"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0b3d04acbd7ab265a"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0b3d04acbd7ab265a",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0b3d04acbd7ab265a",quantile="1"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-04b7f8dc26466fa6c"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-04b7f8dc26466fa6c"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-04b7f8dc26466fa6c",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-04b7f8dc26466fa6c",quantile="1"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0d9508c18a276197d"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0d9508c18a276197d"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0d9508c18a276197d",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0d9508c18a276197d",quantile="1"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0226aebca61198d64"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0226aebca61198d64"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0226aebca61198d64",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0226aebca61198d64",quantile="1"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0226aebca61198d64"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0226aebca61198d64"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0226aebca61198d64",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0226aebca61198d64",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0032a895fde59d69d"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0032a895fde59d69d"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0032a895fde59d69d",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-0032a895fde59d69d",quantile="1"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-089ef136b9a66167f"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-089ef136b9a66167f"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-089ef136b9a66167f",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-089ef136b9a66167f",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-008e77fcc91d63670"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-008e77fcc91d63670"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-008e77fcc91d63670",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-008e77fcc91d63670",quantile="1"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-008e77fcc91d63670"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-008e77fcc91d63670"} 1 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-008e77fcc91d63670",quantile="0"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-008e77fcc91d63670",quantile="1"} 0 1709217240000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_sum",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-06c359a975751363b"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps_count",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-06c359a975751363b"} 1 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-06c359a975751363b",quantile="0"} 0 1709217300000
+{__name__="amazonaws.com/AWS/EBS/VolumeReadOps",cloud.provider="aws",cloud.account.id="677435890598",cloud.region="us-east-1",aws.exporter.arn="arn:aws:cloudwatch:us-east-1:677435890598:metric-stream/custom_ebs_metric",Namespace="AWS/EBS",MetricName="VolumeReadOps",VolumeId="vol-06c359a975751363b",quantile="1"} 0 1709217300000
 `
 	var callbackCalls atomic.Uint64
-	err := stream.ParseStream(bytes.NewReader(data), false, ProcessRequestBody, func(tss []prompbmarshal.TimeSeries) error {
+	err := stream.ParseStream(bytes.NewReader(data), "", ProcessRequestBody, func(tss []prompbmarshal.TimeSeries) error {
 		callbackCalls.Add(1)
 		s := formatTimeseries(tss)
 		if s != sExpected {
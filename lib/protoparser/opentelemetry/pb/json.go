@@ -0,0 +1,694 @@
+package pb
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/valyala/fastjson"
+)
+
+// UnmarshalJSON unmarshals r from OTLP/JSON encoded message at src.
+//
+// See https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+func (r *ExportMetricsServiceRequest) UnmarshalJSON(src []byte) error {
+	r.ResourceMetrics = nil
+
+	p := jsonParserPool.Get()
+	defer jsonParserPool.Put(p)
+
+	v, err := p.ParseBytes(src)
+	if err != nil {
+		return fmt.Errorf("cannot parse JSON: %w", err)
+	}
+	return r.unmarshalJSON(v)
+}
+
+var jsonParserPool fastjson.ParserPool
+
+func (r *ExportMetricsServiceRequest) unmarshalJSON(v *fastjson.Value) error {
+	a, err := getJSONArray(v, "resourceMetrics", "resource_metrics")
+	if err != nil {
+		return err
+	}
+	for _, av := range a {
+		rm := &ResourceMetrics{}
+		if err := rm.unmarshalJSON(av); err != nil {
+			return fmt.Errorf("cannot unmarshal ResourceMetrics: %w", err)
+		}
+		r.ResourceMetrics = append(r.ResourceMetrics, rm)
+	}
+	return nil
+}
+
+func (rm *ResourceMetrics) unmarshalJSON(v *fastjson.Value) error {
+	if rv := getJSONField(v, "resource", "resource"); rv != nil {
+		rm.Resource = &Resource{}
+		if err := rm.Resource.unmarshalJSON(rv); err != nil {
+			return fmt.Errorf("cannot unmarshal Resource: %w", err)
+		}
+	}
+	a, err := getJSONArray(v, "scopeMetrics", "scope_metrics")
+	if err != nil {
+		return err
+	}
+	for _, av := range a {
+		sm := &ScopeMetrics{}
+		if err := sm.unmarshalJSON(av); err != nil {
+			return fmt.Errorf("cannot unmarshal ScopeMetrics: %w", err)
+		}
+		rm.ScopeMetrics = append(rm.ScopeMetrics, sm)
+	}
+	rm.SchemaURL, err = getJSONString(v, "schemaUrl", "schema_url")
+	return err
+}
+
+func (r *Resource) unmarshalJSON(v *fastjson.Value) (err error) {
+	r.Attributes, err = unmarshalJSONAttributes(v)
+	return err
+}
+
+func (sm *ScopeMetrics) unmarshalJSON(v *fastjson.Value) (err error) {
+	if sv := getJSONField(v, "scope", "scope"); sv != nil {
+		sm.Scope = &InstrumentationScope{}
+		if err := sm.Scope.unmarshalJSON(sv); err != nil {
+			return fmt.Errorf("cannot unmarshal InstrumentationScope: %w", err)
+		}
+	}
+	a, err := getJSONArray(v, "metrics", "metrics")
+	if err != nil {
+		return err
+	}
+	for _, av := range a {
+		m := &Metric{}
+		if err := m.unmarshalJSON(av); err != nil {
+			return fmt.Errorf("cannot unmarshal Metric: %w", err)
+		}
+		sm.Metrics = append(sm.Metrics, m)
+	}
+	sm.SchemaURL, err = getJSONString(v, "schemaUrl", "schema_url")
+	return err
+}
+
+func (is *InstrumentationScope) unmarshalJSON(v *fastjson.Value) (err error) {
+	if is.Name, err = getJSONString(v, "name", "name"); err != nil {
+		return err
+	}
+	if is.Version, err = getJSONString(v, "version", "version"); err != nil {
+		return err
+	}
+	if is.Attributes, err = unmarshalJSONAttributes(v); err != nil {
+		return err
+	}
+	is.DroppedAttributesCount, err = getJSONUint32(v, "droppedAttributesCount", "dropped_attributes_count")
+	return err
+}
+
+func (m *Metric) unmarshalJSON(v *fastjson.Value) (err error) {
+	if m.Name, err = getJSONString(v, "name", "name"); err != nil {
+		return err
+	}
+	if m.Unit, err = getJSONString(v, "unit", "unit"); err != nil {
+		return err
+	}
+	switch {
+	case v.Exists("gauge"):
+		m.Gauge = &Gauge{}
+		err = m.Gauge.unmarshalJSON(v.Get("gauge"))
+	case v.Exists("sum"):
+		m.Sum = &Sum{}
+		err = m.Sum.unmarshalJSON(v.Get("sum"))
+	case v.Exists("histogram"):
+		m.Histogram = &Histogram{}
+		err = m.Histogram.unmarshalJSON(v.Get("histogram"))
+	case v.Exists("summary"):
+		m.Summary = &Summary{}
+		err = m.Summary.unmarshalJSON(v.Get("summary"))
+	case getJSONField(v, "exponentialHistogram", "exponential_histogram") != nil:
+		m.ExponentialHistogram = &ExponentialHistogram{}
+		err = m.ExponentialHistogram.unmarshalJSON(getJSONField(v, "exponentialHistogram", "exponential_histogram"))
+	}
+	if err != nil {
+		return fmt.Errorf("cannot unmarshal data for metric %q: %w", m.Name, err)
+	}
+	return nil
+}
+
+func (kv *KeyValue) unmarshalJSON(v *fastjson.Value) (err error) {
+	if kv.Key, err = getJSONString(v, "key", "key"); err != nil {
+		return err
+	}
+	if vv := getJSONField(v, "value", "value"); vv != nil {
+		kv.Value = &AnyValue{}
+		if err := kv.Value.unmarshalJSON(vv); err != nil {
+			return fmt.Errorf("cannot unmarshal value for key %q: %w", kv.Key, err)
+		}
+	}
+	return nil
+}
+
+func (av *AnyValue) unmarshalJSON(v *fastjson.Value) error {
+	switch {
+	case getJSONField(v, "stringValue", "string_value") != nil:
+		s, err := getJSONString(v, "stringValue", "string_value")
+		if err != nil {
+			return err
+		}
+		av.StringValue = &s
+	case getJSONField(v, "boolValue", "bool_value") != nil:
+		b, err := getJSONField(v, "boolValue", "bool_value").Bool()
+		if err != nil {
+			return fmt.Errorf("cannot unmarshal boolValue: %w", err)
+		}
+		av.BoolValue = &b
+	case getJSONField(v, "intValue", "int_value") != nil:
+		n, err := getJSONInt64(v, "intValue", "int_value")
+		if err != nil {
+			return err
+		}
+		av.IntValue = &n
+	case getJSONField(v, "doubleValue", "double_value") != nil:
+		f, err := getJSONFloat64(v, "doubleValue", "double_value")
+		if err != nil {
+			return err
+		}
+		av.DoubleValue = &f
+	case getJSONField(v, "arrayValue", "array_value") != nil:
+		a, err := getJSONArray(getJSONField(v, "arrayValue", "array_value"), "values", "values")
+		if err != nil {
+			return err
+		}
+		av.ArrayValue = &ArrayValue{}
+		for _, x := range a {
+			item := &AnyValue{}
+			if err := item.unmarshalJSON(x); err != nil {
+				return fmt.Errorf("cannot unmarshal arrayValue item: %w", err)
+			}
+			av.ArrayValue.Values = append(av.ArrayValue.Values, item)
+		}
+	case getJSONField(v, "kvlistValue", "kvlist_value") != nil:
+		kvs, err := unmarshalJSONKeyValues(getJSONField(v, "kvlistValue", "kvlist_value"), "values", "values")
+		if err != nil {
+			return err
+		}
+		av.KeyValueList = &KeyValueList{
+			Values: kvs,
+		}
+	case getJSONField(v, "bytesValue", "bytes_value") != nil:
+		s, err := getJSONString(v, "bytesValue", "bytes_value")
+		if err != nil {
+			return err
+		}
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return fmt.Errorf("cannot decode base64-encoded bytesValue: %w", err)
+		}
+		av.BytesValue = &b
+	}
+	return nil
+}
+
+func (g *Gauge) unmarshalJSON(v *fastjson.Value) (err error) {
+	g.DataPoints, err = unmarshalJSONNumberDataPoints(v)
+	return err
+}
+
+func (s *Sum) unmarshalJSON(v *fastjson.Value) (err error) {
+	if s.DataPoints, err = unmarshalJSONNumberDataPoints(v); err != nil {
+		return err
+	}
+	if s.AggregationTemporality, err = getJSONAggregationTemporality(v); err != nil {
+		return err
+	}
+	if mv := getJSONField(v, "isMonotonic", "is_monotonic"); mv != nil {
+		if s.IsMonotonic, err = mv.Bool(); err != nil {
+			return fmt.Errorf("cannot unmarshal isMonotonic: %w", err)
+		}
+	}
+	return nil
+}
+
+func (h *Histogram) unmarshalJSON(v *fastjson.Value) error {
+	a, err := getJSONArray(v, "dataPoints", "data_points")
+	if err != nil {
+		return err
+	}
+	for _, av := range a {
+		dp := &HistogramDataPoint{}
+		if err := dp.unmarshalJSON(av); err != nil {
+			return fmt.Errorf("cannot unmarshal HistogramDataPoint: %w", err)
+		}
+		h.DataPoints = append(h.DataPoints, dp)
+	}
+	h.AggregationTemporality, err = getJSONAggregationTemporality(v)
+	return err
+}
+
+func (h *ExponentialHistogram) unmarshalJSON(v *fastjson.Value) error {
+	a, err := getJSONArray(v, "dataPoints", "data_points")
+	if err != nil {
+		return err
+	}
+	for _, av := range a {
+		dp := &ExponentialHistogramDataPoint{}
+		if err := dp.unmarshalJSON(av); err != nil {
+			return fmt.Errorf("cannot unmarshal ExponentialHistogramDataPoint: %w", err)
+		}
+		h.DataPoints = append(h.DataPoints, dp)
+	}
+	h.AggregationTemporality, err = getJSONAggregationTemporality(v)
+	return err
+}
+
+func (s *Summary) unmarshalJSON(v *fastjson.Value) error {
+	a, err := getJSONArray(v, "dataPoints", "data_points")
+	if err != nil {
+		return err
+	}
+	for _, av := range a {
+		dp := &SummaryDataPoint{}
+		if err := dp.unmarshalJSON(av); err != nil {
+			return fmt.Errorf("cannot unmarshal SummaryDataPoint: %w", err)
+		}
+		s.DataPoints = append(s.DataPoints, dp)
+	}
+	return nil
+}
+
+func (ndp *NumberDataPoint) unmarshalJSON(v *fastjson.Value) (err error) {
+	if ndp.Attributes, err = unmarshalJSONAttributes(v); err != nil {
+		return err
+	}
+	if ndp.TimeUnixNano, err = getJSONUint64(v, "timeUnixNano", "time_unix_nano"); err != nil {
+		return err
+	}
+	switch {
+	case getJSONField(v, "asDouble", "as_double") != nil:
+		f, err := getJSONFloat64(v, "asDouble", "as_double")
+		if err != nil {
+			return err
+		}
+		ndp.DoubleValue = &f
+	case getJSONField(v, "asInt", "as_int") != nil:
+		n, err := getJSONInt64(v, "asInt", "as_int")
+		if err != nil {
+			return err
+		}
+		ndp.IntValue = &n
+	}
+	if ndp.Exemplars, err = unmarshalJSONExemplars(v); err != nil {
+		return err
+	}
+	ndp.Flags, err = getJSONUint32(v, "flags", "flags")
+	return err
+}
+
+func (dp *HistogramDataPoint) unmarshalJSON(v *fastjson.Value) (err error) {
+	if dp.Attributes, err = unmarshalJSONAttributes(v); err != nil {
+		return err
+	}
+	if dp.TimeUnixNano, err = getJSONUint64(v, "timeUnixNano", "time_unix_nano"); err != nil {
+		return err
+	}
+	if dp.Count, err = getJSONUint64(v, "count", "count"); err != nil {
+		return err
+	}
+	if getJSONField(v, "sum", "sum") != nil {
+		sum, err := getJSONFloat64(v, "sum", "sum")
+		if err != nil {
+			return err
+		}
+		dp.Sum = &sum
+	}
+	a, err := getJSONArray(v, "bucketCounts", "bucket_counts")
+	if err != nil {
+		return err
+	}
+	for _, av := range a {
+		n, err := parseJSONUint64(av)
+		if err != nil {
+			return fmt.Errorf("cannot unmarshal bucketCounts item: %w", err)
+		}
+		dp.BucketCounts = append(dp.BucketCounts, n)
+	}
+	a, err = getJSONArray(v, "explicitBounds", "explicit_bounds")
+	if err != nil {
+		return err
+	}
+	for _, av := range a {
+		f, err := parseJSONFloat64(av)
+		if err != nil {
+			return fmt.Errorf("cannot unmarshal explicitBounds item: %w", err)
+		}
+		dp.ExplicitBounds = append(dp.ExplicitBounds, f)
+	}
+	if dp.Exemplars, err = unmarshalJSONExemplars(v); err != nil {
+		return err
+	}
+	dp.Flags, err = getJSONUint32(v, "flags", "flags")
+	return err
+}
+
+func (dp *ExponentialHistogramDataPoint) unmarshalJSON(v *fastjson.Value) (err error) {
+	if dp.Attributes, err = unmarshalJSONAttributes(v); err != nil {
+		return err
+	}
+	if dp.TimeUnixNano, err = getJSONUint64(v, "timeUnixNano", "time_unix_nano"); err != nil {
+		return err
+	}
+	if dp.Count, err = getJSONUint64(v, "count", "count"); err != nil {
+		return err
+	}
+	if getJSONField(v, "sum", "sum") != nil {
+		sum, err := getJSONFloat64(v, "sum", "sum")
+		if err != nil {
+			return err
+		}
+		dp.Sum = &sum
+	}
+	scale, err := getJSONInt64(v, "scale", "scale")
+	if err != nil {
+		return err
+	}
+	if scale < math.MinInt32 || scale > math.MaxInt32 {
+		return fmt.Errorf("scale=%d is out of int32 range", scale)
+	}
+	dp.Scale = int32(scale)
+	if dp.ZeroCount, err = getJSONUint64(v, "zeroCount", "zero_count"); err != nil {
+		return err
+	}
+	if bv := getJSONField(v, "positive", "positive"); bv != nil {
+		dp.Positive = &Buckets{}
+		if err := dp.Positive.unmarshalJSON(bv); err != nil {
+			return fmt.Errorf("cannot unmarshal positive buckets: %w", err)
+		}
+	}
+	if bv := getJSONField(v, "negative", "negative"); bv != nil {
+		dp.Negative = &Buckets{}
+		if err := dp.Negative.unmarshalJSON(bv); err != nil {
+			return fmt.Errorf("cannot unmarshal negative buckets: %w", err)
+		}
+	}
+	if dp.Flags, err = getJSONUint32(v, "flags", "flags"); err != nil {
+		return err
+	}
+	dp.ZeroThreshold, err = getJSONFloat64(v, "zeroThreshold", "zero_threshold")
+	return err
+}
+
+func (b *Buckets) unmarshalJSON(v *fastjson.Value) error {
+	offset, err := getJSONInt64(v, "offset", "offset")
+	if err != nil {
+		return err
+	}
+	if offset < math.MinInt32 || offset > math.MaxInt32 {
+		return fmt.Errorf("offset=%d is out of int32 range", offset)
+	}
+	b.Offset = int32(offset)
+	a, err := getJSONArray(v, "bucketCounts", "bucket_counts")
+	if err != nil {
+		return err
+	}
+	for _, av := range a {
+		n, err := parseJSONUint64(av)
+		if err != nil {
+			return fmt.Errorf("cannot unmarshal bucketCounts item: %w", err)
+		}
+		b.BucketCounts = append(b.BucketCounts, n)
+	}
+	return nil
+}
+
+func (dp *SummaryDataPoint) unmarshalJSON(v *fastjson.Value) (err error) {
+	if dp.Attributes, err = unmarshalJSONAttributes(v); err != nil {
+		return err
+	}
+	if dp.TimeUnixNano, err = getJSONUint64(v, "timeUnixNano", "time_unix_nano"); err != nil {
+		return err
+	}
+	if dp.Count, err = getJSONUint64(v, "count", "count"); err != nil {
+		return err
+	}
+	if dp.Sum, err = getJSONFloat64(v, "sum", "sum"); err != nil {
+		return err
+	}
+	a, err := getJSONArray(v, "quantileValues", "quantile_values")
+	if err != nil {
+		return err
+	}
+	for _, av := range a {
+		q := &ValueAtQuantile{}
+		if q.Quantile, err = getJSONFloat64(av, "quantile", "quantile"); err != nil {
+			return err
+		}
+		if q.Value, err = getJSONFloat64(av, "value", "value"); err != nil {
+			return err
+		}
+		dp.QuantileValues = append(dp.QuantileValues, q)
+	}
+	dp.Flags, err = getJSONUint32(v, "flags", "flags")
+	return err
+}
+
+func (e *Exemplar) unmarshalJSON(v *fastjson.Value) (err error) {
+	if e.FilteredAttributes, err = unmarshalJSONKeyValues(v, "filteredAttributes", "filtered_attributes"); err != nil {
+		return err
+	}
+	if e.TimeUnixNano, err = getJSONUint64(v, "timeUnixNano", "time_unix_nano"); err != nil {
+		return err
+	}
+	switch {
+	case getJSONField(v, "asDouble", "as_double") != nil:
+		f, err := getJSONFloat64(v, "asDouble", "as_double")
+		if err != nil {
+			return err
+		}
+		e.DoubleValue = &f
+	case getJSONField(v, "asInt", "as_int") != nil:
+		n, err := getJSONInt64(v, "asInt", "as_int")
+		if err != nil {
+			return err
+		}
+		e.IntValue = &n
+	}
+	if e.SpanID, err = getJSONHexBytes(v, "spanId", "span_id"); err != nil {
+		return err
+	}
+	e.TraceID, err = getJSONHexBytes(v, "traceId", "trace_id")
+	return err
+}
+
+func unmarshalJSONExemplars(v *fastjson.Value) ([]*Exemplar, error) {
+	a, err := getJSONArray(v, "exemplars", "exemplars")
+	if err != nil {
+		return nil, err
+	}
+	var es []*Exemplar
+	for _, av := range a {
+		e := &Exemplar{}
+		if err := e.unmarshalJSON(av); err != nil {
+			return nil, fmt.Errorf("cannot unmarshal Exemplar: %w", err)
+		}
+		es = append(es, e)
+	}
+	return es, nil
+}
+
+func unmarshalJSONNumberDataPoints(v *fastjson.Value) ([]*NumberDataPoint, error) {
+	a, err := getJSONArray(v, "dataPoints", "data_points")
+	if err != nil {
+		return nil, err
+	}
+	var dps []*NumberDataPoint
+	for _, av := range a {
+		dp := &NumberDataPoint{}
+		if err := dp.unmarshalJSON(av); err != nil {
+			return nil, fmt.Errorf("cannot unmarshal NumberDataPoint: %w", err)
+		}
+		dps = append(dps, dp)
+	}
+	return dps, nil
+}
+
+func unmarshalJSONAttributes(v *fastjson.Value) ([]*KeyValue, error) {
+	return unmarshalJSONKeyValues(v, "attributes", "attributes")
+}
+
+func unmarshalJSONKeyValues(v *fastjson.Value, camelKey, snakeKey string) ([]*KeyValue, error) {
+	a, err := getJSONArray(v, camelKey, snakeKey)
+	if err != nil {
+		return nil, err
+	}
+	var kvs []*KeyValue
+	for _, av := range a {
+		kv := &KeyValue{}
+		if err := kv.unmarshalJSON(av); err != nil {
+			return nil, fmt.Errorf("cannot unmarshal %s: %w", camelKey, err)
+		}
+		kvs = append(kvs, kv)
+	}
+	return kvs, nil
+}
+
+func getJSONAggregationTemporality(v *fastjson.Value) (AggregationTemporality, error) {
+	n, err := getJSONInt64(v, "aggregationTemporality", "aggregation_temporality")
+	if err == nil {
+		return AggregationTemporality(n), nil
+	}
+	// Enum values may be encoded as strings with enum names.
+	s, errStr := getJSONString(v, "aggregationTemporality", "aggregation_temporality")
+	if errStr != nil {
+		return 0, err
+	}
+	switch s {
+	case "AGGREGATION_TEMPORALITY_UNSPECIFIED":
+		return AggregationTemporalityUnspecified, nil
+	case "AGGREGATION_TEMPORALITY_DELTA":
+		return AggregationTemporalityDelta, nil
+	case "AGGREGATION_TEMPORALITY_CUMULATIVE":
+		return AggregationTemporalityCumulative, nil
+	default:
+		return 0, fmt.Errorf("unsupported aggregationTemporality=%q", s)
+	}
+}
+
+// getJSONField returns the field value from v by camelKey or snakeKey.
+//
+// OTLP/JSON uses lowerCamelCase field names, while protobuf JSON mapping allows original snake_case field names as well.
+// It returns nil if the field is missing or contains null.
+func getJSONField(v *fastjson.Value, camelKey, snakeKey string) *fastjson.Value {
+	fv := v.Get(camelKey)
+	if fv == nil && snakeKey != camelKey {
+		fv = v.Get(snakeKey)
+	}
+	if fv == nil || fv.Type() == fastjson.TypeNull {
+		return nil
+	}
+	return fv
+}
+
+func getJSONArray(v *fastjson.Value, camelKey, snakeKey string) ([]*fastjson.Value, error) {
+	fv := getJSONField(v, camelKey, snakeKey)
+	if fv == nil {
+		return nil, nil
+	}
+	a, err := fv.Array()
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal %s: %w", camelKey, err)
+	}
+	return a, nil
+}
+
+func getJSONString(v *fastjson.Value, camelKey, snakeKey string) (string, error) {
+	fv := getJSONField(v, camelKey, snakeKey)
+	if fv == nil {
+		return "", nil
+	}
+	b, err := fv.StringBytes()
+	if err != nil {
+		return "", fmt.Errorf("cannot unmarshal %s: %w", camelKey, err)
+	}
+	return string(b), nil
+}
+
+// getJSONHexBytes returns bytes for the given hex-encoded field.
+//
+// OTLP/JSON encodes trace and span ids as hex strings instead of base64 strings.
+func getJSONHexBytes(v *fastjson.Value, camelKey, snakeKey string) ([]byte, error) {
+	s, err := getJSONString(v, camelKey, snakeKey)
+	if err != nil || s == "" {
+		return nil, err
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode hex-encoded %s: %w", camelKey, err)
+	}
+	return b, nil
+}
+
+func getJSONUint32(v *fastjson.Value, camelKey, snakeKey string) (uint32, error) {
+	fv := getJSONField(v, camelKey, snakeKey)
+	if fv == nil {
+		return 0, nil
+	}
+	n, err := fv.Uint()
+	if err != nil || n > math.MaxUint32 {
+		return 0, fmt.Errorf("cannot unmarshal %s into uint32: %s", camelKey, fv)
+	}
+	return uint32(n), nil
+}
+
+// getJSONUint64 returns uint64 value for the given field.
+//
+// OTLP/JSON encodes 64-bit integers as decimal strings, while numbers are accepted as well.
+func getJSONUint64(v *fastjson.Value, camelKey, snakeKey string) (uint64, error) {
+	fv := getJSONField(v, camelKey, snakeKey)
+	if fv == nil {
+		return 0, nil
+	}
+	n, err := parseJSONUint64(fv)
+	if err != nil {
+		return 0, fmt.Errorf("cannot unmarshal %s: %w", camelKey, err)
+	}
+	return n, nil
+}
+
+func parseJSONUint64(v *fastjson.Value) (uint64, error) {
+	if v.Type() == fastjson.TypeString {
+		return strconv.ParseUint(string(v.GetStringBytes()), 10, 64)
+	}
+	return v.Uint64()
+}
+
+func getJSONInt64(v *fastjson.Value, camelKey, snakeKey string) (int64, error) {
+	fv := getJSONField(v, camelKey, snakeKey)
+	if fv == nil {
+		return 0, nil
+	}
+	var n int64
+	var err error
+	if fv.Type() == fastjson.TypeString {
+		n, err = strconv.ParseInt(string(fv.GetStringBytes()), 10, 64)
+	} else {
+		n, err = fv.Int64()
+	}
+	if err != nil {
+		return 0, fmt.Errorf("cannot unmarshal %s: %w", camelKey, err)
+	}
+	return n, nil
+}
+
+func getJSONFloat64(v *fastjson.Value, camelKey, snakeKey string) (float64, error) {
+	fv := getJSONField(v, camelKey, snakeKey)
+	if fv == nil {
+		return 0, nil
+	}
+	f, err := parseJSONFloat64(fv)
+	if err != nil {
+		return 0, fmt.Errorf("cannot unmarshal %s: %w", camelKey, err)
+	}
+	return f, nil
+}
+
+// parseJSONFloat64 parses float64 from v.
+//
+// Protobuf JSON mapping encodes special float values as "NaN", "Infinity" and "-Infinity" strings.
+func parseJSONFloat64(v *fastjson.Value) (float64, error) {
+	if v.Type() != fastjson.TypeString {
+		return v.Float64()
+	}
+	s := string(v.GetStringBytes())
+	switch s {
+	case "NaN":
+		return math.NaN(), nil
+	case "Infinity":
+		return math.Inf(1), nil
+	case "-Infinity":
+		return math.Inf(-1), nil
+	default:
+		return strconv.ParseFloat(strings.TrimSpace(s), 64)
+	}
+}
@@ -0,0 +1,537 @@
+package pb
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"math"
+	"strconv"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/stringsutil"
+)
+
+// MarshalJSON marshals r to OTLP/JSON encoded message.
+//
+// Fields with default values are omitted, 64-bit integers are encoded as decimal strings, bytes are encoded as base64 strings,
+// while trace and span ids are encoded as hex strings according to OTLP/JSON spec.
+//
+// See https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+func (r *ExportMetricsServiceRequest) MarshalJSON() ([]byte, error) {
+	return r.appendJSON(nil), nil
+}
+
+func (r *ExportMetricsServiceRequest) appendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	start := len(dst)
+	if len(r.ResourceMetrics) > 0 {
+		dst = appendJSONKey(dst, start, "resourceMetrics")
+		dst = append(dst, '[')
+		for i, rm := range r.ResourceMetrics {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = rm.appendJSON(dst)
+		}
+		dst = append(dst, ']')
+	}
+	return append(dst, '}')
+}
+
+func (rm *ResourceMetrics) appendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	start := len(dst)
+	if rm.Resource != nil {
+		dst = appendJSONKey(dst, start, "resource")
+		dst = rm.Resource.appendJSON(dst)
+	}
+	if len(rm.ScopeMetrics) > 0 {
+		dst = appendJSONKey(dst, start, "scopeMetrics")
+		dst = append(dst, '[')
+		for i, sm := range rm.ScopeMetrics {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = sm.appendJSON(dst)
+		}
+		dst = append(dst, ']')
+	}
+	dst = appendJSONStringField(dst, start, "schemaUrl", rm.SchemaURL)
+	return append(dst, '}')
+}
+
+func (r *Resource) appendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	start := len(dst)
+	dst = appendJSONKeyValuesField(dst, start, "attributes", r.Attributes)
+	return append(dst, '}')
+}
+
+func (sm *ScopeMetrics) appendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	start := len(dst)
+	if sm.Scope != nil {
+		dst = appendJSONKey(dst, start, "scope")
+		dst = sm.Scope.appendJSON(dst)
+	}
+	if len(sm.Metrics) > 0 {
+		dst = appendJSONKey(dst, start, "metrics")
+		dst = append(dst, '[')
+		for i, m := range sm.Metrics {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = m.appendJSON(dst)
+		}
+		dst = append(dst, ']')
+	}
+	dst = appendJSONStringField(dst, start, "schemaUrl", sm.SchemaURL)
+	return append(dst, '}')
+}
+
+func (is *InstrumentationScope) appendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	start := len(dst)
+	dst = appendJSONStringField(dst, start, "name", is.Name)
+	dst = appendJSONStringField(dst, start, "version", is.Version)
+	dst = appendJSONKeyValuesField(dst, start, "attributes", is.Attributes)
+	dst = appendJSONUint32Field(dst, start, "droppedAttributesCount", is.DroppedAttributesCount)
+	return append(dst, '}')
+}
+
+func (m *Metric) appendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	start := len(dst)
+	dst = appendJSONStringField(dst, start, "name", m.Name)
+	dst = appendJSONStringField(dst, start, "unit", m.Unit)
+	switch {
+	case m.Gauge != nil:
+		dst = appendJSONKey(dst, start, "gauge")
+		dst = m.Gauge.appendJSON(dst)
+	case m.Sum != nil:
+		dst = appendJSONKey(dst, start, "sum")
+		dst = m.Sum.appendJSON(dst)
+	case m.Histogram != nil:
+		dst = appendJSONKey(dst, start, "histogram")
+		dst = m.Histogram.appendJSON(dst)
+	case m.ExponentialHistogram != nil:
+		dst = appendJSONKey(dst, start, "exponentialHistogram")
+		dst = m.ExponentialHistogram.appendJSON(dst)
+	case m.Summary != nil:
+		dst = appendJSONKey(dst, start, "summary")
+		dst = m.Summary.appendJSON(dst)
+	}
+	return append(dst, '}')
+}
+
+func (kv *KeyValue) appendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	start := len(dst)
+	dst = appendJSONStringField(dst, start, "key", kv.Key)
+	if kv.Value != nil {
+		dst = appendJSONKey(dst, start, "value")
+		dst = kv.Value.appendJSON(dst)
+	}
+	return append(dst, '}')
+}
+
+func (av *AnyValue) appendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	start := len(dst)
+	switch {
+	case av.StringValue != nil:
+		dst = appendJSONKey(dst, start, "stringValue")
+		dst = append(dst, stringsutil.JSONString(*av.StringValue)...)
+	case av.BoolValue != nil:
+		dst = appendJSONKey(dst, start, "boolValue")
+		dst = strconv.AppendBool(dst, *av.BoolValue)
+	case av.IntValue != nil:
+		dst = appendJSONKey(dst, start, "intValue")
+		dst = appendJSONInt64(dst, *av.IntValue)
+	case av.DoubleValue != nil:
+		dst = appendJSONKey(dst, start, "doubleValue")
+		dst = appendJSONFloat64(dst, *av.DoubleValue)
+	case av.ArrayValue != nil:
+		dst = appendJSONKey(dst, start, "arrayValue")
+		dst = append(dst, '{')
+		arrayStart := len(dst)
+		if len(av.ArrayValue.Values) > 0 {
+			dst = appendJSONKey(dst, arrayStart, "values")
+			dst = append(dst, '[')
+			for i, v := range av.ArrayValue.Values {
+				if i > 0 {
+					dst = append(dst, ',')
+				}
+				dst = v.appendJSON(dst)
+			}
+			dst = append(dst, ']')
+		}
+		dst = append(dst, '}')
+	case av.KeyValueList != nil:
+		dst = appendJSONKey(dst, start, "kvlistValue")
+		dst = append(dst, '{')
+		dst = appendJSONKeyValuesField(dst, len(dst), "values", av.KeyValueList.Values)
+		dst = append(dst, '}')
+	case av.BytesValue != nil:
+		dst = appendJSONKey(dst, start, "bytesValue")
+		dst = append(dst, '"')
+		dst = base64.StdEncoding.AppendEncode(dst, *av.BytesValue)
+		dst = append(dst, '"')
+	}
+	return append(dst, '}')
+}
+
+func (g *Gauge) appendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	dst = appendJSONNumberDataPointsField(dst, len(dst), g.DataPoints)
+	return append(dst, '}')
+}
+
+func (s *Sum) appendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	start := len(dst)
+	dst = appendJSONNumberDataPointsField(dst, start, s.DataPoints)
+	dst = appendJSONAggregationTemporalityField(dst, start, s.AggregationTemporality)
+	if s.IsMonotonic {
+		dst = appendJSONKey(dst, start, "isMonotonic")
+		dst = append(dst, "true"...)
+	}
+	return append(dst, '}')
+}
+
+func (h *Histogram) appendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	start := len(dst)
+	if len(h.DataPoints) > 0 {
+		dst = appendJSONKey(dst, start, "dataPoints")
+		dst = append(dst, '[')
+		for i, dp := range h.DataPoints {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = dp.appendJSON(dst)
+		}
+		dst = append(dst, ']')
+	}
+	dst = appendJSONAggregationTemporalityField(dst, start, h.AggregationTemporality)
+	return append(dst, '}')
+}
+
+func (h *ExponentialHistogram) appendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	start := len(dst)
+	if len(h.DataPoints) > 0 {
+		dst = appendJSONKey(dst, start, "dataPoints")
+		dst = append(dst, '[')
+		for i, dp := range h.DataPoints {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = dp.appendJSON(dst)
+		}
+		dst = append(dst, ']')
+	}
+	dst = appendJSONAggregationTemporalityField(dst, start, h.AggregationTemporality)
+	return append(dst, '}')
+}
+
+func (s *Summary) appendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	start := len(dst)
+	if len(s.DataPoints) > 0 {
+		dst = appendJSONKey(dst, start, "dataPoints")
+		dst = append(dst, '[')
+		for i, dp := range s.DataPoints {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = dp.appendJSON(dst)
+		}
+		dst = append(dst, ']')
+	}
+	return append(dst, '}')
+}
+
+func (ndp *NumberDataPoint) appendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	start := len(dst)
+	dst = appendJSONKeyValuesField(dst, start, "attributes", ndp.Attributes)
+	dst = appendJSONUint64Field(dst, start, "timeUnixNano", ndp.TimeUnixNano)
+	switch {
+	case ndp.DoubleValue != nil:
+		dst = appendJSONKey(dst, start, "asDouble")
+		dst = appendJSONFloat64(dst, *ndp.DoubleValue)
+	case ndp.IntValue != nil:
+		dst = appendJSONKey(dst, start, "asInt")
+		dst = appendJSONInt64(dst, *ndp.IntValue)
+	}
+	dst = appendJSONExemplarsField(dst, start, ndp.Exemplars)
+	dst = appendJSONUint32Field(dst, start, "flags", ndp.Flags)
+	return append(dst, '}')
+}
+
+func (dp *HistogramDataPoint) appendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	start := len(dst)
+	dst = appendJSONKeyValuesField(dst, start, "attributes", dp.Attributes)
+	dst = appendJSONUint64Field(dst, start, "timeUnixNano", dp.TimeUnixNano)
+	dst = appendJSONUint64Field(dst, start, "count", dp.Count)
+	if dp.Sum != nil {
+		dst = appendJSONKey(dst, start, "sum")
+		dst = appendJSONFloat64(dst, *dp.Sum)
+	}
+	dst = appendJSONUint64sField(dst, start, "bucketCounts", dp.BucketCounts)
+	if len(dp.ExplicitBounds) > 0 {
+		dst = appendJSONKey(dst, start, "explicitBounds")
+		dst = append(dst, '[')
+		for i, f := range dp.ExplicitBounds {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendJSONFloat64(dst, f)
+		}
+		dst = append(dst, ']')
+	}
+	dst = appendJSONExemplarsField(dst, start, dp.Exemplars)
+	dst = appendJSONUint32Field(dst, start, "flags", dp.Flags)
+	return append(dst, '}')
+}
+
+func (dp *ExponentialHistogramDataPoint) appendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	start := len(dst)
+	dst = appendJSONKeyValuesField(dst, start, "attributes", dp.Attributes)
+	dst = appendJSONUint64Field(dst, start, "timeUnixNano", dp.TimeUnixNano)
+	dst = appendJSONUint64Field(dst, start, "count", dp.Count)
+	if dp.Sum != nil {
+		dst = appendJSONKey(dst, start, "sum")
+		dst = appendJSONFloat64(dst, *dp.Sum)
+	}
+	if dp.Scale != 0 {
+		// int32 fields are encoded as numbers according to proto3 JSON mapping.
+		dst = appendJSONKey(dst, start, "scale")
+		dst = strconv.AppendInt(dst, int64(dp.Scale), 10)
+	}
+	dst = appendJSONUint64Field(dst, start, "zeroCount", dp.ZeroCount)
+	if dp.Positive != nil {
+		dst = appendJSONKey(dst, start, "positive")
+		dst = dp.Positive.appendJSON(dst)
+	}
+	if dp.Negative != nil {
+		dst = appendJSONKey(dst, start, "negative")
+		dst = dp.Negative.appendJSON(dst)
+	}
+	dst = appendJSONUint32Field(dst, start, "flags", dp.Flags)
+	if dp.ZeroThreshold != 0 {
+		dst = appendJSONKey(dst, start, "zeroThreshold")
+		dst = appendJSONFloat64(dst, dp.ZeroThreshold)
+	}
+	return append(dst, '}')
+}
+
+func (b *Buckets) appendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	start := len(dst)
+	if b.Offset != 0 {
+		dst = appendJSONKey(dst, start, "offset")
+		dst = strconv.AppendInt(dst, int64(b.Offset), 10)
+	}
+	dst = appendJSONUint64sField(dst, start, "bucketCounts", b.BucketCounts)
+	return append(dst, '}')
+}
+
+func (dp *SummaryDataPoint) appendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	start := len(dst)
+	dst = appendJSONKeyValuesField(dst, start, "attributes", dp.Attributes)
+	dst = appendJSONUint64Field(dst, start, "timeUnixNano", dp.TimeUnixNano)
+	dst = appendJSONUint64Field(dst, start, "count", dp.Count)
+	if dp.Sum != 0 {
+		dst = appendJSONKey(dst, start, "sum")
+		dst = appendJSONFloat64(dst, dp.Sum)
+	}
+	if len(dp.QuantileValues) > 0 {
+		dst = appendJSONKey(dst, start, "quantileValues")
+		dst = append(dst, '[')
+		for i, q := range dp.QuantileValues {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = append(dst, '{')
+			qStart := len(dst)
+			if q.Quantile != 0 {
+				dst = appendJSONKey(dst, qStart, "quantile")
+				dst = appendJSONFloat64(dst, q.Quantile)
+			}
+			if q.Value != 0 {
+				dst = appendJSONKey(dst, qStart, "value")
+				dst = appendJSONFloat64(dst, q.Value)
+			}
+			dst = append(dst, '}')
+		}
+		dst = append(dst, ']')
+	}
+	dst = appendJSONUint32Field(dst, start, "flags", dp.Flags)
+	return append(dst, '}')
+}
+
+func (e *Exemplar) appendJSON(dst []byte) []byte {
+	dst = append(dst, '{')
+	start := len(dst)
+	dst = appendJSONKeyValuesField(dst, start, "filteredAttributes", e.FilteredAttributes)
+	dst = appendJSONUint64Field(dst, start, "timeUnixNano", e.TimeUnixNano)
+	switch {
+	case e.DoubleValue != nil:
+		dst = appendJSONKey(dst, start, "asDouble")
+		dst = appendJSONFloat64(dst, *e.DoubleValue)
+	case e.IntValue != nil:
+		dst = appendJSONKey(dst, start, "asInt")
+		dst = appendJSONInt64(dst, *e.IntValue)
+	}
+	dst = appendJSONHexBytesField(dst, start, "spanId", e.SpanID)
+	dst = appendJSONHexBytesField(dst, start, "traceId", e.TraceID)
+	return append(dst, '}')
+}
+
+// appendJSONKey appends the given key to dst for the JSON object, which starts at objStart position in dst.
+//
+// The comma is appended before the key if the object already contains fields.
+func appendJSONKey(dst []byte, objStart int, key string) []byte {
+	if len(dst) > objStart {
+		dst = append(dst, ',')
+	}
+	dst = append(dst, '"')
+	dst = append(dst, key...)
+	return append(dst, `":`...)
+}
+
+func appendJSONStringField(dst []byte, objStart int, key, s string) []byte {
+	if s == "" {
+		return dst
+	}
+	dst = appendJSONKey(dst, objStart, key)
+	return append(dst, stringsutil.JSONString(s)...)
+}
+
+func appendJSONUint32Field(dst []byte, objStart int, key string, n uint32) []byte {
+	if n == 0 {
+		return dst
+	}
+	dst = appendJSONKey(dst, objStart, key)
+	return strconv.AppendUint(dst, uint64(n), 10)
+}
+
+func appendJSONUint64Field(dst []byte, objStart int, key string, n uint64) []byte {
+	if n == 0 {
+		return dst
+	}
+	dst = appendJSONKey(dst, objStart, key)
+	return appendJSONUint64(dst, n)
+}
+
+func appendJSONUint64sField(dst []byte, objStart int, key string, a []uint64) []byte {
+	if len(a) == 0 {
+		return dst
+	}
+	dst = appendJSONKey(dst, objStart, key)
+	dst = append(dst, '[')
+	for i, n := range a {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = appendJSONUint64(dst, n)
+	}
+	return append(dst, ']')
+}
+
+func appendJSONHexBytesField(dst []byte, objStart int, key string, b []byte) []byte {
+	if len(b) == 0 {
+		return dst
+	}
+	dst = appendJSONKey(dst, objStart, key)
+	dst = append(dst, '"')
+	dst = hex.AppendEncode(dst, b)
+	return append(dst, '"')
+}
+
+func appendJSONAggregationTemporalityField(dst []byte, objStart int, at AggregationTemporality) []byte {
+	if at == AggregationTemporalityUnspecified {
+		return dst
+	}
+	// OTLP/JSON encodes enum values as integers.
+	dst = appendJSONKey(dst, objStart, "aggregationTemporality")
+	return strconv.AppendInt(dst, int64(at), 10)
+}
+
+func appendJSONKeyValuesField(dst []byte, objStart int, key string, kvs []*KeyValue) []byte {
+	if len(kvs) == 0 {
+		return dst
+	}
+	dst = appendJSONKey(dst, objStart, key)
+	dst = append(dst, '[')
+	for i, kv := range kvs {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = kv.appendJSON(dst)
+	}
+	return append(dst, ']')
+}
+
+func appendJSONNumberDataPointsField(dst []byte, objStart int, dps []*NumberDataPoint) []byte {
+	if len(dps) == 0 {
+		return dst
+	}
+	dst = appendJSONKey(dst, objStart, "dataPoints")
+	dst = append(dst, '[')
+	for i, dp := range dps {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = dp.appendJSON(dst)
+	}
+	return append(dst, ']')
+}
+
+func appendJSONExemplarsField(dst []byte, objStart int, es []*Exemplar) []byte {
+	if len(es) == 0 {
+		return dst
+	}
+	dst = appendJSONKey(dst, objStart, "exemplars")
+	dst = append(dst, '[')
+	for i, e := range es {
+		if i > 0 {
+			dst = append(dst, ',')
+		}
+		dst = e.appendJSON(dst)
+	}
+	return append(dst, ']')
+}
+
+// appendJSONUint64 appends n to dst as a decimal string according to proto3 JSON mapping for 64-bit integers.
+func appendJSONUint64(dst []byte, n uint64) []byte {
+	dst = append(dst, '"')
+	dst = strconv.AppendUint(dst, n, 10)
+	return append(dst, '"')
+}
+
+// appendJSONInt64 appends n to dst as a decimal string according to proto3 JSON mapping for 64-bit integers.
+func appendJSONInt64(dst []byte, n int64) []byte {
+	dst = append(dst, '"')
+	dst = strconv.AppendInt(dst, n, 10)
+	return append(dst, '"')
+}
+
+// appendJSONFloat64 appends f to dst.
+//
+// Special float values are encoded as "NaN", "Infinity" and "-Infinity" strings according to proto3 JSON mapping.
+func appendJSONFloat64(dst []byte, f float64) []byte {
+	switch {
+	case math.IsNaN(f):
+		return append(dst, `"NaN"`...)
+	case math.IsInf(f, 1):
+		return append(dst, `"Infinity"`...)
+	case math.IsInf(f, -1):
+		return append(dst, `"-Infinity"`...)
+	default:
+		return strconv.AppendFloat(dst, f, 'g', -1, 64)
+	}
+}
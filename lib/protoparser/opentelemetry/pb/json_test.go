@@ -0,0 +1,525 @@
+package pb
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestExportMetricsServiceRequestUnmarshalJSONSuccess(t *testing.T) {
+	f := func(data string, resultExpected *ExportMetricsServiceRequest) {
+		t.Helper()
+
+		var result ExportMetricsServiceRequest
+		if err := result.UnmarshalJSON([]byte(data)); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(&result, resultExpected) {
+			t.Fatalf("unexpected result\ngot\n%#v\nwant\n%#v", &result, resultExpected)
+		}
+
+		// Verify that the result can be marshaled into protobuf and unmarshaled back
+		var resultPB ExportMetricsServiceRequest
+		if err := resultPB.UnmarshalProtobuf(result.MarshalProtobuf(nil)); err != nil {
+			t.Fatalf("cannot unmarshal protobuf: %s", err)
+		}
+		if !reflect.DeepEqual(&resultPB, resultExpected) {
+			t.Fatalf("unexpected result after protobuf round-trip\ngot\n%#v\nwant\n%#v", &resultPB, resultExpected)
+		}
+
+		// Verify that the result can be marshaled into JSON and unmarshaled back
+		dataJSON, err := result.MarshalJSON()
+		if err != nil {
+			t.Fatalf("cannot marshal JSON: %s", err)
+		}
+		var resultJSON ExportMetricsServiceRequest
+		if err := resultJSON.UnmarshalJSON(dataJSON); err != nil {
+			t.Fatalf("cannot unmarshal JSON %s: %s", dataJSON, err)
+		}
+		if !reflect.DeepEqual(&resultJSON, resultExpected) {
+			t.Fatalf("unexpected result after JSON round-trip\ngot\n%#v\nwant\n%#v", &resultJSON, resultExpected)
+		}
+	}
+
+	stringValue := func(s string) *AnyValue {
+		return &AnyValue{
+			StringValue: &s,
+		}
+	}
+	float64Ptr := func(f float64) *float64 {
+		return &f
+	}
+	int64Ptr := func(n int64) *int64 {
+		return &n
+	}
+	boolPtr := func(b bool) *bool {
+		return &b
+	}
+
+	// empty request
+	f(`{}`, &ExportMetricsServiceRequest{})
+	f(`{"resourceMetrics":[]}`, &ExportMetricsServiceRequest{})
+
+	// gauge and sum with lowerCamelCase field names and 64-bit ints encoded as strings
+	f(`{
+  "resourceMetrics": [{
+    "resource": {"attributes": [{"key": "service.name", "value": {"stringValue": "foo"}}]},
+    "scopeMetrics": [{
+      "scope": {"name": "my.library", "version": "1.0.0"},
+      "metrics": [
+        {
+          "name": "my.gauge",
+          "unit": "1",
+          "gauge": {"dataPoints": [{"asDouble": 1.5, "timeUnixNano": "1544712660300000000", "attributes": [
+            {"key": "bool", "value": {"boolValue": true}},
+            {"key": "int", "value": {"intValue": "-42"}},
+            {"key": "double", "value": {"doubleValue": 0.5}}
+          ]}]}
+        },
+        {
+          "name": "my.counter",
+          "sum": {"aggregationTemporality": 2, "isMonotonic": true, "dataPoints": [{"asInt": "5", "timeUnixNano": 1544712660300000000}]}
+        }
+      ]
+    }]
+  }]
+}`, &ExportMetricsServiceRequest{
+		ResourceMetrics: []*ResourceMetrics{
+			{
+				Resource: &Resource{
+					Attributes: []*KeyValue{
+						{
+							Key:   "service.name",
+							Value: stringValue("foo"),
+						},
+					},
+				},
+				ScopeMetrics: []*ScopeMetrics{
+					{
+						Scope: &InstrumentationScope{
+							Name:    "my.library",
+							Version: "1.0.0",
+						},
+						Metrics: []*Metric{
+							{
+								Name: "my.gauge",
+								Unit: "1",
+								Gauge: &Gauge{
+									DataPoints: []*NumberDataPoint{
+										{
+											Attributes: []*KeyValue{
+												{
+													Key: "bool",
+													Value: &AnyValue{
+														BoolValue: boolPtr(true),
+													},
+												},
+												{
+													Key: "int",
+													Value: &AnyValue{
+														IntValue: int64Ptr(-42),
+													},
+												},
+												{
+													Key: "double",
+													Value: &AnyValue{
+														DoubleValue: float64Ptr(0.5),
+													},
+												},
+											},
+											TimeUnixNano: 1544712660300000000,
+											DoubleValue:  float64Ptr(1.5),
+										},
+									},
+								},
+							},
+							{
+								Name: "my.counter",
+								Sum: &Sum{
+									DataPoints: []*NumberDataPoint{
+										{
+											TimeUnixNano: 1544712660300000000,
+											IntValue:     int64Ptr(5),
+										},
+									},
+									AggregationTemporality: AggregationTemporalityCumulative,
+									IsMonotonic:            true,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	// exemplars with hex-encoded trace and span ids
+	f(`{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"name":"my.counter","sum":{"dataPoints":[{
+  "asInt": "5",
+  "exemplars": [{
+    "timeUnixNano": "1000",
+    "asDouble": 0.5,
+    "traceId": "5b8efff798038103d269b633813fc60c",
+    "spanId": "eee19b7ec3c1b174",
+    "filteredAttributes": [{"key": "user", "value": {"stringValue": "foo"}}]
+  }]
+}]}}]}]}]}`, &ExportMetricsServiceRequest{
+		ResourceMetrics: []*ResourceMetrics{
+			{
+				ScopeMetrics: []*ScopeMetrics{
+					{
+						Metrics: []*Metric{
+							{
+								Name: "my.counter",
+								Sum: &Sum{
+									DataPoints: []*NumberDataPoint{
+										{
+											IntValue: int64Ptr(5),
+											Exemplars: []*Exemplar{
+												{
+													FilteredAttributes: []*KeyValue{
+														{
+															Key:   "user",
+															Value: stringValue("foo"),
+														},
+													},
+													TimeUnixNano: 1000,
+													DoubleValue:  float64Ptr(0.5),
+													SpanID:       []byte{0xee, 0xe1, 0x9b, 0x7e, 0xc3, 0xc1, 0xb1, 0x74},
+													TraceID:      []byte{0x5b, 0x8e, 0xff, 0xf7, 0x98, 0x03, 0x81, 0x03, 0xd2, 0x69, 0xb6, 0x33, 0x81, 0x3f, 0xc6, 0x0c},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	// histogram, exponential histogram and summary with snake_case field names and enum names
+	f(`{
+  "resource_metrics": [{
+    "schema_url": "https://opentelemetry.io/schemas/1.23.0",
+    "scope_metrics": [{
+      "schema_url": "https://opentelemetry.io/schemas/1.24.0",
+      "metrics": [
+        {
+          "name": "h",
+          "histogram": {"aggregation_temporality": "AGGREGATION_TEMPORALITY_CUMULATIVE", "data_points": [{
+            "time_unix_nano": "1000", "count": "3", "sum": 4.5, "bucket_counts": ["1", 2], "explicit_bounds": [1, "Infinity"]
+          }]}
+        },
+        {
+          "name": "eh",
+          "exponentialHistogram": {"aggregationTemporality": 2, "dataPoints": [{
+            "timeUnixNano": "1000", "count": "4", "scale": -1, "zeroCount": "1", "zeroThreshold": 0.001, "flags": 1,
+            "positive": {"offset": 2, "bucketCounts": ["1", "2"]},
+            "negative": {"offset": -1, "bucketCounts": ["0"]}
+          }]}
+        },
+        {
+          "name": "s",
+          "summary": {"dataPoints": [{
+            "timeUnixNano": "1000", "count": "2", "sum": 3, "quantileValues": [{"quantile": 0.5, "value": 1}, {"quantile": 1, "value": 2}]
+          }]}
+        }
+      ]
+    }]
+  }]
+}`, &ExportMetricsServiceRequest{
+		ResourceMetrics: []*ResourceMetrics{
+			{
+				ScopeMetrics: []*ScopeMetrics{
+					{
+						Metrics: []*Metric{
+							{
+								Name: "h",
+								Histogram: &Histogram{
+									DataPoints: []*HistogramDataPoint{
+										{
+											TimeUnixNano:   1000,
+											Count:          3,
+											Sum:            float64Ptr(4.5),
+											BucketCounts:   []uint64{1, 2},
+											ExplicitBounds: []float64{1, math.Inf(1)},
+										},
+									},
+									AggregationTemporality: AggregationTemporalityCumulative,
+								},
+							},
+							{
+								Name: "eh",
+								ExponentialHistogram: &ExponentialHistogram{
+									DataPoints: []*ExponentialHistogramDataPoint{
+										{
+											TimeUnixNano: 1000,
+											Count:        4,
+											Scale:        -1,
+											ZeroCount:    1,
+											Positive: &Buckets{
+												Offset:       2,
+												BucketCounts: []uint64{1, 2},
+											},
+											Negative: &Buckets{
+												Offset:       -1,
+												BucketCounts: []uint64{0},
+											},
+											Flags:         1,
+											ZeroThreshold: 0.001,
+										},
+									},
+									AggregationTemporality: AggregationTemporalityCumulative,
+								},
+							},
+							{
+								Name: "s",
+								Summary: &Summary{
+									DataPoints: []*SummaryDataPoint{
+										{
+											TimeUnixNano: 1000,
+											Count:        2,
+											Sum:          3,
+											QuantileValues: []*ValueAtQuantile{
+												{
+													Quantile: 0.5,
+													Value:    1,
+												},
+												{
+													Quantile: 1,
+													Value:    2,
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+						SchemaURL: "https://opentelemetry.io/schemas/1.24.0",
+					},
+				},
+				SchemaURL: "https://opentelemetry.io/schemas/1.23.0",
+			},
+		},
+	})
+}
+
+func TestExportMetricsServiceRequestUnmarshalJSONFailure(t *testing.T) {
+	f := func(data string) {
+		t.Helper()
+
+		var result ExportMetricsServiceRequest
+		if err := result.UnmarshalJSON([]byte(data)); err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	// invalid exemplar trace id
+	f(`{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"gauge":{"dataPoints":[{"exemplars":[{"traceId":"xyz"}]}]}}]}]}]}`)
+
+	// invalid json
+	f(``)
+	f(`{"resourceMetrics":`)
+
+	// invalid types
+	f(`{"resourceMetrics":{}}`)
+	f(`{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"name":1}]}]}]}`)
+	f(`{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"gauge":{"dataPoints":[{"timeUnixNano":"foo"}]}}]}]}]}`)
+	f(`{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"gauge":{"dataPoints":[{"asInt":"1.5"}]}}]}]}]}`)
+	f(`{"resourceMetrics":[{"resource":{"attributes":[{"key":"foo","value":{"bytesValue":"!!!"}}]}}]}`)
+
+	// unsupported aggregation temporality
+	f(`{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"sum":{"aggregationTemporality":"foo"}}]}]}]}`)
+
+	// out of range values
+	f(`{"resourceMetrics":[{"scopeMetrics":[{"metrics":[{"exponentialHistogram":{"dataPoints":[{"scale":"4294967296"}]}}]}]}]}`)
+}
+
+func TestExportMetricsServiceRequestMarshalJSON(t *testing.T) {
+	f := func(r *ExportMetricsServiceRequest, resultExpected string) {
+		t.Helper()
+
+		result, err := r.MarshalJSON()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if string(result) != resultExpected {
+			t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, resultExpected)
+		}
+	}
+
+	stringValue := func(s string) *AnyValue {
+		return &AnyValue{
+			StringValue: &s,
+		}
+	}
+	float64Ptr := func(f float64) *float64 {
+		return &f
+	}
+	int64Ptr := func(n int64) *int64 {
+		return &n
+	}
+	bytesValue := []byte("foo")
+
+	// empty request
+	f(&ExportMetricsServiceRequest{}, `{}`)
+
+	// gauge and sum with attributes of various types
+	f(&ExportMetricsServiceRequest{
+		ResourceMetrics: []*ResourceMetrics{
+			{
+				Resource: &Resource{
+					Attributes: []*KeyValue{
+						{
+							Key:   "service.name",
+							Value: stringValue("vm\"agent"),
+						},
+						{
+							Key: "bytes",
+							Value: &AnyValue{
+								BytesValue: &bytesValue,
+							},
+						},
+						{
+							Key: "array",
+							Value: &AnyValue{
+								ArrayValue: &ArrayValue{
+									Values: []*AnyValue{
+										{
+											IntValue: int64Ptr(-5),
+										},
+										{
+											DoubleValue: float64Ptr(1.5),
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+				ScopeMetrics: []*ScopeMetrics{
+					{
+						Metrics: []*Metric{
+							{
+								Name: "gauge",
+								Gauge: &Gauge{
+									DataPoints: []*NumberDataPoint{
+										{
+											TimeUnixNano: 1000,
+											DoubleValue:  float64Ptr(math.Inf(1)),
+										},
+									},
+								},
+							},
+							{
+								Name: "sum",
+								Unit: "ms",
+								Sum: &Sum{
+									DataPoints: []*NumberDataPoint{
+										{
+											TimeUnixNano: 18446744073709551615,
+											IntValue:     int64Ptr(9007199254740993),
+											Exemplars: []*Exemplar{
+												{
+													TimeUnixNano: 2000,
+													IntValue:     int64Ptr(1),
+													SpanID:       []byte{0x01, 0xab},
+												},
+											},
+											Flags: 1,
+										},
+									},
+									AggregationTemporality: AggregationTemporalityCumulative,
+									IsMonotonic:            true,
+								},
+							},
+						},
+						SchemaURL: "https://opentelemetry.io/schemas/1.24.0",
+					},
+				},
+			},
+		},
+	}, `{"resourceMetrics":[{"resource":{"attributes":[{"key":"service.name","value":{"stringValue":"vm\"agent"}},`+
+		`{"key":"bytes","value":{"bytesValue":"Zm9v"}},{"key":"array","value":{"arrayValue":{"values":[{"intValue":"-5"},{"doubleValue":1.5}]}}}]},`+
+		`"scopeMetrics":[{"metrics":[{"name":"gauge","gauge":{"dataPoints":[{"timeUnixNano":"1000","asDouble":"Infinity"}]}},`+
+		`{"name":"sum","unit":"ms","sum":{"dataPoints":[{"timeUnixNano":"18446744073709551615","asInt":"9007199254740993",`+
+		`"exemplars":[{"timeUnixNano":"2000","asInt":"1","spanId":"01ab"}],"flags":1}],"aggregationTemporality":2,"isMonotonic":true}}],`+
+		`"schemaUrl":"https://opentelemetry.io/schemas/1.24.0"}]}]}`)
+
+	// histogram, exponential histogram and summary
+	f(&ExportMetricsServiceRequest{
+		ResourceMetrics: []*ResourceMetrics{
+			{
+				ScopeMetrics: []*ScopeMetrics{
+					{
+						Scope: &InstrumentationScope{
+							Name:    "scope",
+							Version: "v1",
+						},
+						Metrics: []*Metric{
+							{
+								Name: "h",
+								Histogram: &Histogram{
+									DataPoints: []*HistogramDataPoint{
+										{
+											Count:          3,
+											Sum:            float64Ptr(4.5),
+											BucketCounts:   []uint64{1, 2},
+											ExplicitBounds: []float64{1},
+										},
+									},
+									AggregationTemporality: AggregationTemporalityDelta,
+								},
+							},
+							{
+								Name: "eh",
+								ExponentialHistogram: &ExponentialHistogram{
+									DataPoints: []*ExponentialHistogramDataPoint{
+										{
+											Count:     2,
+											Scale:     -1,
+											ZeroCount: 1,
+											Positive: &Buckets{
+												Offset:       -2,
+												BucketCounts: []uint64{1},
+											},
+											ZeroThreshold: 1e-9,
+										},
+									},
+									AggregationTemporality: AggregationTemporalityCumulative,
+								},
+							},
+							{
+								Name: "s",
+								Summary: &Summary{
+									DataPoints: []*SummaryDataPoint{
+										{
+											Count: 2,
+											Sum:   3,
+											QuantileValues: []*ValueAtQuantile{
+												{
+													Quantile: 0,
+													Value:    1,
+												},
+												{
+													Quantile: 1,
+													Value:    math.NaN(),
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}, `{"resourceMetrics":[{"scopeMetrics":[{"scope":{"name":"scope","version":"v1"},"metrics":[`+
+		`{"name":"h","histogram":{"dataPoints":[{"count":"3","sum":4.5,"bucketCounts":["1","2"],"explicitBounds":[1]}],"aggregationTemporality":1}},`+
+		`{"name":"eh","exponentialHistogram":{"dataPoints":[{"count":"2","scale":-1,"zeroCount":"1","positive":{"offset":-2,"bucketCounts":["1"]},"zeroThreshold":1e-09}],"aggregationTemporality":2}},`+
+		`{"name":"s","summary":{"dataPoints":[{"count":"2","sum":3,"quantileValues":[{"value":1},{"quantile":1,"value":"NaN"}]}]}}]}]}]}`)
+}
@@ -0,0 +1,302 @@
+package pb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/VictoriaMetrics/easyproto"
+)
+
+// ExportLogsServiceRequest represents the corresponding OTEL protobuf message
+type ExportLogsServiceRequest struct {
+	ResourceLogs []*ResourceLogs
+}
+
+// UnmarshalProtobuf unmarshals r from protobuf message at src.
+func (r *ExportLogsServiceRequest) UnmarshalProtobuf(src []byte) error {
+	r.ResourceLogs = nil
+	return r.unmarshalProtobuf(src)
+}
+
+// MarshalProtobuf marshals r to protobuf message, appends it to dst and returns the result.
+func (r *ExportLogsServiceRequest) MarshalProtobuf(dst []byte) []byte {
+	m := mp.Get()
+	r.marshalProtobuf(m.MessageMarshaler())
+	dst = m.Marshal(dst)
+	mp.Put(m)
+	return dst
+}
+
+func (r *ExportLogsServiceRequest) marshalProtobuf(mm *easyproto.MessageMarshaler) {
+	for _, rl := range r.ResourceLogs {
+		rl.marshalProtobuf(mm.AppendMessage(1))
+	}
+}
+
+func (r *ExportLogsServiceRequest) unmarshalProtobuf(src []byte) (err error) {
+	// message ExportLogsServiceRequest {
+	//   repeated ResourceLogs resource_logs = 1;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in ExportLogsServiceRequest: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read ResourceLogs data")
+			}
+			r.ResourceLogs = append(r.ResourceLogs, &ResourceLogs{})
+			rl := r.ResourceLogs[len(r.ResourceLogs)-1]
+			if err := rl.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal ResourceLogs: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// ResourceLogs represents the corresponding OTEL protobuf message
+type ResourceLogs struct {
+	Resource  *Resource
+	ScopeLogs []*ScopeLogs
+}
+
+func (rl *ResourceLogs) marshalProtobuf(mm *easyproto.MessageMarshaler) {
+	if rl.Resource != nil {
+		rl.Resource.marshalProtobuf(mm.AppendMessage(1))
+	}
+	for _, sl := range rl.ScopeLogs {
+		sl.marshalProtobuf(mm.AppendMessage(2))
+	}
+}
+
+func (rl *ResourceLogs) unmarshalProtobuf(src []byte) (err error) {
+	// message ResourceLogs {
+	//   Resource resource = 1;
+	//   repeated ScopeLogs scope_logs = 2;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in ResourceLogs: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Resource data")
+			}
+			rl.Resource = &Resource{}
+			if err := rl.Resource.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot umarshal Resource: %w", err)
+			}
+		case 2:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read ScopeLogs data")
+			}
+			rl.ScopeLogs = append(rl.ScopeLogs, &ScopeLogs{})
+			sl := rl.ScopeLogs[len(rl.ScopeLogs)-1]
+			if err := sl.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal ScopeLogs: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// ScopeLogs represents the corresponding OTEL protobuf message
+type ScopeLogs struct {
+	Scope      *InstrumentationScope
+	LogRecords []*LogRecord
+}
+
+func (sl *ScopeLogs) marshalProtobuf(mm *easyproto.MessageMarshaler) {
+	if sl.Scope != nil {
+		sl.Scope.marshalProtobuf(mm.AppendMessage(1))
+	}
+	for _, lr := range sl.LogRecords {
+		lr.marshalProtobuf(mm.AppendMessage(2))
+	}
+}
+
+func (sl *ScopeLogs) unmarshalProtobuf(src []byte) (err error) {
+	// message ScopeLogs {
+	//   InstrumentationScope scope = 1;
+	//   repeated LogRecord log_records = 2;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in ScopeLogs: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read InstrumentationScope data")
+			}
+			sl.Scope = &InstrumentationScope{}
+			if err := sl.Scope.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal InstrumentationScope: %w", err)
+			}
+		case 2:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read LogRecord data")
+			}
+			sl.LogRecords = append(sl.LogRecords, &LogRecord{})
+			lr := sl.LogRecords[len(sl.LogRecords)-1]
+			if err := lr.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal LogRecord: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// LogRecord represents the corresponding OTEL protobuf message
+//
+// See https://github.com/open-telemetry/opentelemetry-proto/blob/34d29fe5ad4689b5db0259d3750de2bfa195bc85/opentelemetry/proto/logs/v1/logs.proto
+type LogRecord struct {
+	TimeUnixNano         uint64
+	ObservedTimeUnixNano uint64
+	SeverityNumber       int32
+	SeverityText         string
+	Body                 *AnyValue
+	Attributes           []*KeyValue
+	Flags                uint32
+	TraceID              []byte
+	SpanID               []byte
+}
+
+func (lr *LogRecord) marshalProtobuf(mm *easyproto.MessageMarshaler) {
+	mm.AppendFixed64(1, lr.TimeUnixNano)
+	mm.AppendInt32(2, lr.SeverityNumber)
+	mm.AppendString(3, lr.SeverityText)
+	if lr.Body != nil {
+		lr.Body.marshalProtobuf(mm.AppendMessage(5))
+	}
+	for _, a := range lr.Attributes {
+		a.marshalProtobuf(mm.AppendMessage(6))
+	}
+	mm.AppendFixed32(8, lr.Flags)
+	mm.AppendBytes(9, lr.TraceID)
+	mm.AppendBytes(10, lr.SpanID)
+	mm.AppendFixed64(11, lr.ObservedTimeUnixNano)
+}
+
+func (lr *LogRecord) unmarshalProtobuf(src []byte) (err error) {
+	// message LogRecord {
+	//   fixed64 time_unix_nano = 1;
+	//   fixed64 observed_time_unix_nano = 11;
+	//   SeverityNumber severity_number = 2;
+	//   string severity_text = 3;
+	//   AnyValue body = 5;
+	//   repeated KeyValue attributes = 6;
+	//   fixed32 flags = 8;
+	//   bytes trace_id = 9;
+	//   bytes span_id = 10;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in LogRecord: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			timeUnixNano, ok := fc.Fixed64()
+			if !ok {
+				return fmt.Errorf("cannot read log record timestamp")
+			}
+			lr.TimeUnixNano = timeUnixNano
+		case 11:
+			observedTimeUnixNano, ok := fc.Fixed64()
+			if !ok {
+				return fmt.Errorf("cannot read log record observed timestamp")
+			}
+			lr.ObservedTimeUnixNano = observedTimeUnixNano
+		case 2:
+			severityNumber, ok := fc.Int32()
+			if !ok {
+				return fmt.Errorf("cannot read severity number")
+			}
+			lr.SeverityNumber = severityNumber
+		case 3:
+			severityText, ok := fc.String()
+			if !ok {
+				return fmt.Errorf("cannot read severity text")
+			}
+			lr.SeverityText = strings.Clone(severityText)
+		case 5:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Body")
+			}
+			lr.Body = &AnyValue{}
+			if err := lr.Body.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Body: %w", err)
+			}
+		case 6:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Attribute data")
+			}
+			lr.Attributes = append(lr.Attributes, &KeyValue{})
+			a := lr.Attributes[len(lr.Attributes)-1]
+			if err := a.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Attribute: %w", err)
+			}
+		case 8:
+			flags, ok := fc.Fixed32()
+			if !ok {
+				return fmt.Errorf("cannot read flags")
+			}
+			lr.Flags = flags
+		case 9:
+			traceID, ok := fc.Bytes()
+			if !ok {
+				return fmt.Errorf("cannot read trace id")
+			}
+			lr.TraceID = append(lr.TraceID[:0], traceID...)
+		case 10:
+			spanID, ok := fc.Bytes()
+			if !ok {
+				return fmt.Errorf("cannot read span id")
+			}
+			lr.SpanID = append(lr.SpanID[:0], spanID...)
+		}
+	}
+	return nil
+}
+
+// FormatSeverity returns string representation for lr severity.
+//
+// It returns SeverityText if it is set. Otherwise the short name for SeverityNumber is returned.
+// See https://opentelemetry.io/docs/specs/otel/logs/data-model/#displaying-severity
+func (lr *LogRecord) FormatSeverity() string {
+	if lr.SeverityText != "" {
+		return lr.SeverityText
+	}
+	n := lr.SeverityNumber
+	if n <= 0 || n > int32(len(logSeverities)) {
+		return ""
+	}
+	return logSeverities[n-1]
+}
+
+var logSeverities = []string{
+	"TRACE", "TRACE2", "TRACE3", "TRACE4",
+	"DEBUG", "DEBUG2", "DEBUG3", "DEBUG4",
+	"INFO", "INFO2", "INFO3", "INFO4",
+	"WARN", "WARN2", "WARN3", "WARN4",
+	"ERROR", "ERROR2", "ERROR3", "ERROR4",
+	"FATAL", "FATAL2", "FATAL3", "FATAL4",
+}
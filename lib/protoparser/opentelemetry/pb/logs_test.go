@@ -0,0 +1,92 @@
+package pb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExportLogsServiceRequestMarshalUnmarshal(t *testing.T) {
+	f := func(r *ExportLogsServiceRequest) {
+		t.Helper()
+
+		data := r.MarshalProtobuf(nil)
+		var result ExportLogsServiceRequest
+		if err := result.UnmarshalProtobuf(data); err != nil {
+			t.Fatalf("cannot unmarshal ExportLogsServiceRequest: %s", err)
+		}
+		if !reflect.DeepEqual(&result, r) {
+			t.Fatalf("unexpected result after marshal/unmarshal round-trip\ngot\n%#v\nwant\n%#v", &result, r)
+		}
+	}
+
+	stringValue := func(s string) *AnyValue {
+		return &AnyValue{
+			StringValue: &s,
+		}
+	}
+
+	f(&ExportLogsServiceRequest{})
+
+	f(&ExportLogsServiceRequest{
+		ResourceLogs: []*ResourceLogs{
+			{
+				Resource: &Resource{
+					Attributes: []*KeyValue{
+						{
+							Key:   "service.name",
+							Value: stringValue("foo"),
+						},
+					},
+				},
+				ScopeLogs: []*ScopeLogs{
+					{
+						Scope: &InstrumentationScope{
+							Name: "bar",
+						},
+						LogRecords: []*LogRecord{
+							{
+								TimeUnixNano:         1234,
+								ObservedTimeUnixNano: 5678,
+								SeverityNumber:       9,
+								SeverityText:         "Information",
+								Body:                 stringValue("some log message"),
+								Attributes: []*KeyValue{
+									{
+										Key:   "user_id",
+										Value: stringValue("123"),
+									},
+								},
+								Flags:   1,
+								TraceID: []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+								SpanID:  []byte{1, 2, 3, 4, 5, 6, 7, 8},
+							},
+							{
+								SeverityNumber: 17,
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestLogRecordFormatSeverity(t *testing.T) {
+	f := func(lr *LogRecord, resultExpected string) {
+		t.Helper()
+
+		result := lr.FormatSeverity()
+		if result != resultExpected {
+			t.Fatalf("unexpected result; got %q; want %q", result, resultExpected)
+		}
+	}
+
+	f(&LogRecord{}, "")
+	f(&LogRecord{SeverityText: "Information", SeverityNumber: 9}, "Information")
+	f(&LogRecord{SeverityNumber: 1}, "TRACE")
+	f(&LogRecord{SeverityNumber: 9}, "INFO")
+	f(&LogRecord{SeverityNumber: 18}, "ERROR2")
+	f(&LogRecord{SeverityNumber: 24}, "FATAL4")
+	f(&LogRecord{SeverityNumber: 25}, "")
+	f(&LogRecord{SeverityNumber: -1}, "")
+}
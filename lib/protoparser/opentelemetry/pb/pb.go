@@ -16,7 +16,20 @@ type ExportMetricsServiceRequest struct {
 // UnmarshalProtobuf unmarshals r from protobuf message at src.
 func (r *ExportMetricsServiceRequest) UnmarshalProtobuf(src []byte) error {
 	r.ResourceMetrics = nil
-	return r.unmarshalProtobuf(src)
+	return r.unmarshalProtobuf(src, nil)
+}
+
+// UnmarshalProtobufLenient unmarshals r from protobuf message at src in lenient mode.
+//
+// Contrary to UnmarshalProtobuf, it skips nested ResourceMetrics, ScopeMetrics and Metric messages, which cannot be unmarshaled,
+// instead of returning an error. This allows accepting the remaining data from slightly off-spec payloads.
+//
+// It returns the number of skipped messages.
+func (r *ExportMetricsServiceRequest) UnmarshalProtobufLenient(src []byte) (int, error) {
+	r.ResourceMetrics = nil
+	skipped := 0
+	err := r.unmarshalProtobuf(src, &skipped)
+	return skipped, err
 }
 
 // MarshalProtobuf marshals r to protobuf message, appends it to dst and returns the result.
@@ -36,7 +49,10 @@ func (r *ExportMetricsServiceRequest) marshalProtobuf(mm *easyproto.MessageMarsh
 	}
 }
 
-func (r *ExportMetricsServiceRequest) unmarshalProtobuf(src []byte) (err error) {
+// unmarshalProtobuf unmarshals r from src.
+//
+// If skipped isn't nil, then nested messages, which cannot be unmarshaled, are skipped and counted in skipped.
+func (r *ExportMetricsServiceRequest) unmarshalProtobuf(src []byte, skipped *int) (err error) {
 	// message ExportMetricsServiceRequest {
 	//   repeated ResourceMetrics resource_metrics = 1;
 	// }
@@ -52,11 +68,15 @@ func (r *ExportMetricsServiceRequest) unmarshalProtobuf(src []byte) (err error)
 			if !ok {
 				return fmt.Errorf("cannot read ResourceMetrics data")
 			}
-			r.ResourceMetrics = append(r.ResourceMetrics, &ResourceMetrics{})
-			rm := r.ResourceMetrics[len(r.ResourceMetrics)-1]
-			if err := rm.unmarshalProtobuf(data); err != nil {
-				return fmt.Errorf("cannot unmarshal ResourceMetrics: %w", err)
+			rm := &ResourceMetrics{}
+			if err := rm.unmarshalProtobuf(data, skipped); err != nil {
+				if skipped == nil {
+					return fmt.Errorf("cannot unmarshal ResourceMetrics: %w", err)
+				}
+				*skipped++
+				continue
 			}
+			r.ResourceMetrics = append(r.ResourceMetrics, rm)
 		}
 	}
 	return nil
@@ -66,6 +86,7 @@ func (r *ExportMetricsServiceRequest) unmarshalProtobuf(src []byte) (err error)
 type ResourceMetrics struct {
 	Resource     *Resource
 	ScopeMetrics []*ScopeMetrics
+	SchemaURL    string
 }
 
 func (rm *ResourceMetrics) marshalProtobuf(mm *easyproto.MessageMarshaler) {
@@ -75,12 +96,16 @@ func (rm *ResourceMetrics) marshalProtobuf(mm *easyproto.MessageMarshaler) {
 	for _, sm := range rm.ScopeMetrics {
 		sm.marshalProtobuf(mm.AppendMessage(2))
 	}
+	if rm.SchemaURL != "" {
+		mm.AppendString(3, rm.SchemaURL)
+	}
 }
 
-func (rm *ResourceMetrics) unmarshalProtobuf(src []byte) (err error) {
+func (rm *ResourceMetrics) unmarshalProtobuf(src []byte, skipped *int) (err error) {
 	// message ResourceMetrics {
 	//   Resource resource = 1;
 	//   repeated ScopeMetrics scope_metrics = 2;
+	//   string schema_url = 3;
 	// }
 	var fc easyproto.FieldContext
 	for len(src) > 0 {
@@ -103,11 +128,21 @@ func (rm *ResourceMetrics) unmarshalProtobuf(src []byte) (err error) {
 			if !ok {
 				return fmt.Errorf("cannot read ScopeMetrics data")
 			}
-			rm.ScopeMetrics = append(rm.ScopeMetrics, &ScopeMetrics{})
-			sm := rm.ScopeMetrics[len(rm.ScopeMetrics)-1]
-			if err := sm.unmarshalProtobuf(data); err != nil {
-				return fmt.Errorf("cannot unmarshal ScopeMetrics: %w", err)
+			sm := &ScopeMetrics{}
+			if err := sm.unmarshalProtobuf(data, skipped); err != nil {
+				if skipped == nil {
+					return fmt.Errorf("cannot unmarshal ScopeMetrics: %w", err)
+				}
+				*skipped++
+				continue
 			}
+			rm.ScopeMetrics = append(rm.ScopeMetrics, sm)
+		case 3:
+			schemaURL, ok := fc.String()
+			if !ok {
+				return fmt.Errorf("cannot read schema_url")
+			}
+			rm.SchemaURL = strings.Clone(schemaURL)
 		}
 	}
 	return nil
@@ -140,8 +175,7 @@ func (r *Resource) unmarshalProtobuf(src []byte) (err error) {
 			if !ok {
 				return fmt.Errorf("cannot read Attribute data")
 			}
-			r.Attributes = append(r.Attributes, &KeyValue{})
-			a := r.Attributes[len(r.Attributes)-1]
+			a := appendReused(&r.Attributes)
 			if err := a.unmarshalProtobuf(data); err != nil {
 				return fmt.Errorf("cannot unmarshal Attribute: %w", err)
 			}
@@ -152,18 +186,26 @@ func (r *Resource) unmarshalProtobuf(src []byte) (err error) {
 
 // ScopeMetrics represents the corresponding OTEL protobuf message
 type ScopeMetrics struct {
-	Metrics []*Metric
+	Scope     *InstrumentationScope
+	Metrics   []*Metric
+	SchemaURL string
 }
 
 func (sm *ScopeMetrics) marshalProtobuf(mm *easyproto.MessageMarshaler) {
+	if sm.Scope != nil {
+		sm.Scope.marshalProtobuf(mm.AppendMessage(1))
+	}
 	for _, m := range sm.Metrics {
 		m.marshalProtobuf(mm.AppendMessage(2))
 	}
+	mm.AppendString(3, sm.SchemaURL)
 }
 
-func (sm *ScopeMetrics) unmarshalProtobuf(src []byte) (err error) {
+func (sm *ScopeMetrics) unmarshalProtobuf(src []byte, skipped *int) (err error) {
 	// message ScopeMetrics {
+	//   InstrumentationScope scope = 1;
 	//   repeated Metric metrics = 2;
+	//   string schema_url = 3;
 	// }
 	var fc easyproto.FieldContext
 	for len(src) > 0 {
@@ -172,16 +214,98 @@ func (sm *ScopeMetrics) unmarshalProtobuf(src []byte) (err error) {
 			return fmt.Errorf("cannot read next field in ScopeMetrics: %w", err)
 		}
 		switch fc.FieldNum {
+		case 1:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read InstrumentationScope data")
+			}
+			sm.Scope = &InstrumentationScope{}
+			if err := sm.Scope.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal InstrumentationScope: %w", err)
+			}
 		case 2:
 			data, ok := fc.MessageData()
 			if !ok {
 				return fmt.Errorf("cannot read Metric data")
 			}
-			sm.Metrics = append(sm.Metrics, &Metric{})
-			m := sm.Metrics[len(sm.Metrics)-1]
+			m := &Metric{}
 			if err := m.unmarshalProtobuf(data); err != nil {
-				return fmt.Errorf("cannot unmarshal Metric: %w", err)
+				if skipped == nil {
+					return fmt.Errorf("cannot unmarshal Metric: %w", err)
+				}
+				*skipped++
+				continue
+			}
+			sm.Metrics = append(sm.Metrics, m)
+		case 3:
+			schemaURL, ok := fc.String()
+			if !ok {
+				return fmt.Errorf("cannot read schema_url")
+			}
+			sm.SchemaURL = strings.Clone(schemaURL)
+		}
+	}
+	return nil
+}
+
+// InstrumentationScope represents the corresponding OTEL protobuf message
+type InstrumentationScope struct {
+	Name                   string
+	Version                string
+	Attributes             []*KeyValue
+	DroppedAttributesCount uint32
+}
+
+func (is *InstrumentationScope) marshalProtobuf(mm *easyproto.MessageMarshaler) {
+	mm.AppendString(1, is.Name)
+	mm.AppendString(2, is.Version)
+	for _, a := range is.Attributes {
+		a.marshalProtobuf(mm.AppendMessage(3))
+	}
+	mm.AppendUint32(4, is.DroppedAttributesCount)
+}
+
+func (is *InstrumentationScope) unmarshalProtobuf(src []byte) (err error) {
+	// message InstrumentationScope {
+	//   string name = 1;
+	//   string version = 2;
+	//   repeated KeyValue attributes = 3;
+	//   uint32 dropped_attributes_count = 4;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in InstrumentationScope: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			name, ok := fc.String()
+			if !ok {
+				return fmt.Errorf("cannot read scope name")
+			}
+			is.Name = strings.Clone(name)
+		case 2:
+			version, ok := fc.String()
+			if !ok {
+				return fmt.Errorf("cannot read scope version")
+			}
+			is.Version = strings.Clone(version)
+		case 3:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Attribute data")
+			}
+			a := appendReused(&is.Attributes)
+			if err := a.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Attribute: %w", err)
 			}
+		case 4:
+			droppedAttributesCount, ok := fc.Uint32()
+			if !ok {
+				return fmt.Errorf("cannot read dropped_attributes_count")
+			}
+			is.DroppedAttributesCount = droppedAttributesCount
 		}
 	}
 	return nil
@@ -195,6 +319,48 @@ type Metric struct {
 	Sum       *Sum
 	Histogram *Histogram
 	Summary   *Summary
+
+	ExponentialHistogram *ExponentialHistogram
+
+	// spare* fields hold reset messages, which can be reused during unmarshaling.
+	spareGauge                *Gauge
+	spareSum                  *Sum
+	spareHistogram            *Histogram
+	spareSummary              *Summary
+	spareExponentialHistogram *ExponentialHistogram
+}
+
+// Reset resets m, so it can be reused.
+//
+// The allocated memory is retained for the subsequent unmarshaling.
+func (m *Metric) Reset() {
+	m.Name = ""
+	m.Unit = ""
+	if m.Gauge != nil {
+		m.Gauge.Reset()
+		m.spareGauge = m.Gauge
+		m.Gauge = nil
+	}
+	if m.Sum != nil {
+		m.Sum.Reset()
+		m.spareSum = m.Sum
+		m.Sum = nil
+	}
+	if m.Histogram != nil {
+		m.Histogram.Reset()
+		m.spareHistogram = m.Histogram
+		m.Histogram = nil
+	}
+	if m.Summary != nil {
+		m.Summary.Reset()
+		m.spareSummary = m.Summary
+		m.Summary = nil
+	}
+	if m.ExponentialHistogram != nil {
+		m.ExponentialHistogram.Reset()
+		m.spareExponentialHistogram = m.ExponentialHistogram
+		m.ExponentialHistogram = nil
+	}
 }
 
 func (m *Metric) marshalProtobuf(mm *easyproto.MessageMarshaler) {
@@ -207,6 +373,8 @@ func (m *Metric) marshalProtobuf(mm *easyproto.MessageMarshaler) {
 		m.Sum.marshalProtobuf(mm.AppendMessage(7))
 	case m.Histogram != nil:
 		m.Histogram.marshalProtobuf(mm.AppendMessage(9))
+	case m.ExponentialHistogram != nil:
+		m.ExponentialHistogram.marshalProtobuf(mm.AppendMessage(10))
 	case m.Summary != nil:
 		m.Summary.marshalProtobuf(mm.AppendMessage(11))
 	}
@@ -220,6 +388,7 @@ func (m *Metric) unmarshalProtobuf(src []byte) (err error) {
 	//     Gauge gauge = 5;
 	//     Sum sum = 7;
 	//     Histogram histogram = 9;
+	//     ExponentialHistogram exponential_histogram = 10;
 	//     Summary summary = 11;
 	//   }
 	// }
@@ -247,7 +416,7 @@ func (m *Metric) unmarshalProtobuf(src []byte) (err error) {
 			if !ok {
 				return fmt.Errorf("cannot read Gauge data")
 			}
-			m.Gauge = &Gauge{}
+			m.Gauge = reuseMessage(&m.spareGauge)
 			if err := m.Gauge.unmarshalProtobuf(data); err != nil {
 				return fmt.Errorf("cannot unmarshal Gauge: %w", err)
 			}
@@ -256,7 +425,7 @@ func (m *Metric) unmarshalProtobuf(src []byte) (err error) {
 			if !ok {
 				return fmt.Errorf("cannot read Sum data")
 			}
-			m.Sum = &Sum{}
+			m.Sum = reuseMessage(&m.spareSum)
 			if err := m.Sum.unmarshalProtobuf(data); err != nil {
 				return fmt.Errorf("cannot unmarshal Sum: %w", err)
 			}
@@ -265,16 +434,25 @@ func (m *Metric) unmarshalProtobuf(src []byte) (err error) {
 			if !ok {
 				return fmt.Errorf("cannot read Histogram data")
 			}
-			m.Histogram = &Histogram{}
+			m.Histogram = reuseMessage(&m.spareHistogram)
 			if err := m.Histogram.unmarshalProtobuf(data); err != nil {
 				return fmt.Errorf("cannot unmarshal Histogram: %w", err)
 			}
+		case 10:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read ExponentialHistogram data")
+			}
+			m.ExponentialHistogram = reuseMessage(&m.spareExponentialHistogram)
+			if err := m.ExponentialHistogram.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal ExponentialHistogram: %w", err)
+			}
 		case 11:
 			data, ok := fc.MessageData()
 			if !ok {
 				return fmt.Errorf("cannot read Summary data")
 			}
-			m.Summary = &Summary{}
+			m.Summary = reuseMessage(&m.spareSummary)
 			if err := m.Summary.unmarshalProtobuf(data); err != nil {
 				return fmt.Errorf("cannot unmarshal Summary: %w", err)
 			}
@@ -287,6 +465,21 @@ func (m *Metric) unmarshalProtobuf(src []byte) (err error) {
 type KeyValue struct {
 	Key   string
 	Value *AnyValue
+
+	// spareValue holds reset AnyValue, which can be reused during unmarshaling.
+	spareValue *AnyValue
+}
+
+// Reset resets kv, so it can be reused.
+//
+// The allocated memory is retained for the subsequent unmarshaling.
+func (kv *KeyValue) Reset() {
+	kv.Key = ""
+	if kv.Value != nil {
+		kv.Value.Reset()
+		kv.spareValue = kv.Value
+		kv.Value = nil
+	}
 }
 
 func (kv *KeyValue) marshalProtobuf(mm *easyproto.MessageMarshaler) {
@@ -319,7 +512,7 @@ func (kv *KeyValue) unmarshalProtobuf(src []byte) (err error) {
 			if !ok {
 				return fmt.Errorf("cannot read Value")
 			}
-			kv.Value = &AnyValue{}
+			kv.Value = reuseMessage(&kv.spareValue)
 			if err := kv.Value.unmarshalProtobuf(data); err != nil {
 				return fmt.Errorf("cannot unmarshal Value: %w", err)
 			}
@@ -328,6 +521,42 @@ func (kv *KeyValue) unmarshalProtobuf(src []byte) (err error) {
 	return nil
 }
 
+// unmarshalProtobufStringKeyValue unmarshals kv from the deprecated StringKeyValue message at src.
+//
+// StringKeyValue was used for data point labels in OpenTelemetry protocol before v0.9.
+// For example, it is used by AWS CloudWatch metric streams in OpenTelemetry 0.7 format.
+func (kv *KeyValue) unmarshalProtobufStringKeyValue(src []byte) (err error) {
+	// message StringKeyValue {
+	//   string key = 1;
+	//   string value = 2;
+	// }
+	kv.Value = reuseMessage(&kv.spareValue)
+	kv.Value.Reset()
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in StringKeyValue: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			key, ok := fc.String()
+			if !ok {
+				return fmt.Errorf("cannot read Key")
+			}
+			kv.Key = strings.Clone(key)
+		case 2:
+			value, ok := fc.String()
+			if !ok {
+				return fmt.Errorf("cannot read Value")
+			}
+			value = strings.Clone(value)
+			kv.Value.StringValue = &value
+		}
+	}
+	return nil
+}
+
 // AnyValue represents the corresponding OTEL protobuf message
 type AnyValue struct {
 	StringValue  *string
@@ -339,6 +568,17 @@ type AnyValue struct {
 	BytesValue   *[]byte
 }
 
+// Reset resets av, so it can be reused.
+func (av *AnyValue) Reset() {
+	av.StringValue = nil
+	av.BoolValue = nil
+	av.IntValue = nil
+	av.DoubleValue = nil
+	av.ArrayValue = nil
+	av.KeyValueList = nil
+	av.BytesValue = nil
+}
+
 func (av *AnyValue) marshalProtobuf(mm *easyproto.MessageMarshaler) {
 	switch {
 	case av.StringValue != nil:
@@ -437,6 +677,13 @@ type ArrayValue struct {
 	Values []*AnyValue
 }
 
+// Reset resets av, so it can be reused.
+//
+// The allocated memory is retained for the subsequent unmarshaling.
+func (av *ArrayValue) Reset() {
+	av.Values = av.Values[:0]
+}
+
 func (av *ArrayValue) marshalProtobuf(mm *easyproto.MessageMarshaler) {
 	for _, v := range av.Values {
 		v.marshalProtobuf(mm.AppendMessage(1))
@@ -459,8 +706,7 @@ func (av *ArrayValue) unmarshalProtobuf(src []byte) (err error) {
 			if !ok {
 				return fmt.Errorf("cannot read Value data")
 			}
-			av.Values = append(av.Values, &AnyValue{})
-			v := av.Values[len(av.Values)-1]
+			v := appendReused(&av.Values)
 			if err := v.unmarshalProtobuf(data); err != nil {
 				return fmt.Errorf("cannot unmarshal Value: %w", err)
 			}
@@ -474,6 +720,13 @@ type KeyValueList struct {
 	Values []*KeyValue
 }
 
+// Reset resets kvl, so it can be reused.
+//
+// The allocated memory is retained for the subsequent unmarshaling.
+func (kvl *KeyValueList) Reset() {
+	kvl.Values = kvl.Values[:0]
+}
+
 func (kvl *KeyValueList) marshalProtobuf(mm *easyproto.MessageMarshaler) {
 	for _, v := range kvl.Values {
 		v.marshalProtobuf(mm.AppendMessage(1))
@@ -496,8 +749,7 @@ func (kvl *KeyValueList) unmarshalProtobuf(src []byte) (err error) {
 			if !ok {
 				return fmt.Errorf("cannot read Value data")
 			}
-			kvl.Values = append(kvl.Values, &KeyValue{})
-			v := kvl.Values[len(kvl.Values)-1]
+			v := appendReused(&kvl.Values)
 			if err := v.unmarshalProtobuf(data); err != nil {
 				return fmt.Errorf("cannot unmarshal Value: %w", err)
 			}
@@ -511,6 +763,13 @@ type Gauge struct {
 	DataPoints []*NumberDataPoint
 }
 
+// Reset resets g, so it can be reused.
+//
+// The allocated memory is retained for the subsequent unmarshaling.
+func (g *Gauge) Reset() {
+	g.DataPoints = g.DataPoints[:0]
+}
+
 func (g *Gauge) marshalProtobuf(mm *easyproto.MessageMarshaler) {
 	for _, dp := range g.DataPoints {
 		dp.marshalProtobuf(mm.AppendMessage(1))
@@ -533,8 +792,7 @@ func (g *Gauge) unmarshalProtobuf(src []byte) (err error) {
 			if !ok {
 				return fmt.Errorf("cannot read DataPoint data")
 			}
-			g.DataPoints = append(g.DataPoints, &NumberDataPoint{})
-			dp := g.DataPoints[len(g.DataPoints)-1]
+			dp := appendReused(&g.DataPoints)
 			if err := dp.unmarshalProtobuf(data); err != nil {
 				return fmt.Errorf("cannot unmarshal DataPoint: %w", err)
 			}
@@ -545,17 +803,35 @@ func (g *Gauge) unmarshalProtobuf(src []byte) (err error) {
 
 // NumberDataPoint represents the corresponding OTEL protobuf message
 type NumberDataPoint struct {
-	Attributes   []*KeyValue
-	TimeUnixNano uint64
-	DoubleValue  *float64
-	IntValue     *int64
-	Flags        uint32
+	Attributes        []*KeyValue
+	StartTimeUnixNano uint64
+	TimeUnixNano      uint64
+	DoubleValue       *float64
+	IntValue          *int64
+	Exemplars         []*Exemplar
+	Flags             uint32
+}
+
+// Reset resets ndp, so it can be reused.
+//
+// The allocated memory is retained for the subsequent unmarshaling.
+func (ndp *NumberDataPoint) Reset() {
+	ndp.Attributes = ndp.Attributes[:0]
+	ndp.StartTimeUnixNano = 0
+	ndp.TimeUnixNano = 0
+	ndp.DoubleValue = nil
+	ndp.IntValue = nil
+	ndp.Exemplars = ndp.Exemplars[:0]
+	ndp.Flags = 0
 }
 
 func (ndp *NumberDataPoint) marshalProtobuf(mm *easyproto.MessageMarshaler) {
 	for _, a := range ndp.Attributes {
 		a.marshalProtobuf(mm.AppendMessage(7))
 	}
+	if ndp.StartTimeUnixNano > 0 {
+		mm.AppendFixed64(2, ndp.StartTimeUnixNano)
+	}
 	mm.AppendFixed64(3, ndp.TimeUnixNano)
 	switch {
 	case ndp.DoubleValue != nil:
@@ -563,17 +839,23 @@ func (ndp *NumberDataPoint) marshalProtobuf(mm *easyproto.MessageMarshaler) {
 	case ndp.IntValue != nil:
 		mm.AppendSfixed64(6, *ndp.IntValue)
 	}
+	for _, e := range ndp.Exemplars {
+		e.marshalProtobuf(mm.AppendMessage(5))
+	}
 	mm.AppendUint32(8, ndp.Flags)
 }
 
 func (ndp *NumberDataPoint) unmarshalProtobuf(src []byte) (err error) {
 	// message NumberDataPoint {
+	//   repeated StringKeyValue labels = 1; // deprecated
 	//   repeated KeyValue attributes = 7;
+	//   fixed64 start_time_unix_nano = 2;
 	//   fixed64 time_unix_nano = 3;
 	//   oneof value {
 	//     double as_double = 4;
 	//     sfixed64 as_int = 6;
 	//   }
+	//   repeated Exemplar exemplars = 5;
 	//   uint32 flags = 8;
 	// }
 	var fc easyproto.FieldContext
@@ -583,16 +865,31 @@ func (ndp *NumberDataPoint) unmarshalProtobuf(src []byte) (err error) {
 			return fmt.Errorf("cannot read next field in NumberDataPoint: %w", err)
 		}
 		switch fc.FieldNum {
+		case 1:
+			// Deprecated labels field from OpenTelemetry protocol before v0.9.
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Label")
+			}
+			a := appendReused(&ndp.Attributes)
+			if err := a.unmarshalProtobufStringKeyValue(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Label: %w", err)
+			}
 		case 7:
 			data, ok := fc.MessageData()
 			if !ok {
 				return fmt.Errorf("cannot read Attribute")
 			}
-			ndp.Attributes = append(ndp.Attributes, &KeyValue{})
-			a := ndp.Attributes[len(ndp.Attributes)-1]
+			a := appendReused(&ndp.Attributes)
 			if err := a.unmarshalProtobuf(data); err != nil {
 				return fmt.Errorf("cannot unmarshal Attribute: %w", err)
 			}
+		case 2:
+			startTimeUnixNano, ok := fc.Fixed64()
+			if !ok {
+				return fmt.Errorf("cannot read StartTimeUnixNano")
+			}
+			ndp.StartTimeUnixNano = startTimeUnixNano
 		case 3:
 			timeUnixNano, ok := fc.Fixed64()
 			if !ok {
@@ -611,6 +908,15 @@ func (ndp *NumberDataPoint) unmarshalProtobuf(src []byte) (err error) {
 				return fmt.Errorf("cannot read IntValue")
 			}
 			ndp.IntValue = &intValue
+		case 5:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Exemplar")
+			}
+			e := appendReused(&ndp.Exemplars)
+			if err := e.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Exemplar: %w", err)
+			}
 		case 8:
 			flags, ok := fc.Uint32()
 			if !ok {
@@ -629,6 +935,15 @@ type Sum struct {
 	IsMonotonic            bool
 }
 
+// Reset resets s, so it can be reused.
+//
+// The allocated memory is retained for the subsequent unmarshaling.
+func (s *Sum) Reset() {
+	s.DataPoints = s.DataPoints[:0]
+	s.AggregationTemporality = AggregationTemporalityUnspecified
+	s.IsMonotonic = false
+}
+
 // AggregationTemporality represents the corresponding OTEL protobuf enum
 type AggregationTemporality int
 
@@ -666,8 +981,7 @@ func (s *Sum) unmarshalProtobuf(src []byte) (err error) {
 			if !ok {
 				return fmt.Errorf("cannot read DataPoint data")
 			}
-			s.DataPoints = append(s.DataPoints, &NumberDataPoint{})
-			dp := s.DataPoints[len(s.DataPoints)-1]
+			dp := appendReused(&s.DataPoints)
 			if err := dp.unmarshalProtobuf(data); err != nil {
 				return fmt.Errorf("cannot unmarshal DataPoint: %w", err)
 			}
@@ -694,6 +1008,14 @@ type Histogram struct {
 	AggregationTemporality AggregationTemporality
 }
 
+// Reset resets h, so it can be reused.
+//
+// The allocated memory is retained for the subsequent unmarshaling.
+func (h *Histogram) Reset() {
+	h.DataPoints = h.DataPoints[:0]
+	h.AggregationTemporality = AggregationTemporalityUnspecified
+}
+
 func (h *Histogram) marshalProtobuf(mm *easyproto.MessageMarshaler) {
 	for _, dp := range h.DataPoints {
 		dp.marshalProtobuf(mm.AppendMessage(1))
@@ -718,8 +1040,60 @@ func (h *Histogram) unmarshalProtobuf(src []byte) (err error) {
 			if !ok {
 				return fmt.Errorf("cannot read DataPoint")
 			}
-			h.DataPoints = append(h.DataPoints, &HistogramDataPoint{})
-			dp := h.DataPoints[len(h.DataPoints)-1]
+			dp := appendReused(&h.DataPoints)
+			if err := dp.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal DataPoint: %w", err)
+			}
+		case 2:
+			at, ok := fc.Int64()
+			if !ok {
+				return fmt.Errorf("cannot read AggregationTemporality")
+			}
+			h.AggregationTemporality = AggregationTemporality(at)
+		}
+	}
+	return nil
+}
+
+// ExponentialHistogram represents the corresponding OTEL protobuf message
+type ExponentialHistogram struct {
+	DataPoints             []*ExponentialHistogramDataPoint
+	AggregationTemporality AggregationTemporality
+}
+
+// Reset resets h, so it can be reused.
+//
+// The allocated memory is retained for the subsequent unmarshaling.
+func (h *ExponentialHistogram) Reset() {
+	h.DataPoints = h.DataPoints[:0]
+	h.AggregationTemporality = AggregationTemporalityUnspecified
+}
+
+func (h *ExponentialHistogram) marshalProtobuf(mm *easyproto.MessageMarshaler) {
+	for _, dp := range h.DataPoints {
+		dp.marshalProtobuf(mm.AppendMessage(1))
+	}
+	mm.AppendInt64(2, int64(h.AggregationTemporality))
+}
+
+func (h *ExponentialHistogram) unmarshalProtobuf(src []byte) (err error) {
+	// message ExponentialHistogram {
+	//   repeated ExponentialHistogramDataPoint data_points = 1;
+	//   AggregationTemporality aggregation_temporality = 2;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in ExponentialHistogram: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read DataPoint")
+			}
+			dp := appendReused(&h.DataPoints)
 			if err := dp.unmarshalProtobuf(data); err != nil {
 				return fmt.Errorf("cannot unmarshal DataPoint: %w", err)
 			}
@@ -739,6 +1113,13 @@ type Summary struct {
 	DataPoints []*SummaryDataPoint
 }
 
+// Reset resets s, so it can be reused.
+//
+// The allocated memory is retained for the subsequent unmarshaling.
+func (s *Summary) Reset() {
+	s.DataPoints = s.DataPoints[:0]
+}
+
 func (s *Summary) marshalProtobuf(mm *easyproto.MessageMarshaler) {
 	for _, dp := range s.DataPoints {
 		dp.marshalProtobuf(mm.AppendMessage(1))
@@ -761,8 +1142,7 @@ func (s *Summary) unmarshalProtobuf(src []byte) (err error) {
 			if !ok {
 				return fmt.Errorf("cannot read DataPoint")
 			}
-			s.DataPoints = append(s.DataPoints, &SummaryDataPoint{})
-			dp := s.DataPoints[len(s.DataPoints)-1]
+			dp := appendReused(&s.DataPoints)
 			if err := dp.unmarshalProtobuf(data); err != nil {
 				return fmt.Errorf("cannot unmarshal DataPoint: %w", err)
 			}
@@ -779,9 +1159,24 @@ type HistogramDataPoint struct {
 	Sum            *float64
 	BucketCounts   []uint64
 	ExplicitBounds []float64
+	Exemplars      []*Exemplar
 	Flags          uint32
 }
 
+// Reset resets dp, so it can be reused.
+//
+// The allocated memory is retained for the subsequent unmarshaling.
+func (dp *HistogramDataPoint) Reset() {
+	dp.Attributes = dp.Attributes[:0]
+	dp.TimeUnixNano = 0
+	dp.Count = 0
+	dp.Sum = nil
+	dp.BucketCounts = dp.BucketCounts[:0]
+	dp.ExplicitBounds = dp.ExplicitBounds[:0]
+	dp.Exemplars = dp.Exemplars[:0]
+	dp.Flags = 0
+}
+
 func (dp *HistogramDataPoint) marshalProtobuf(mm *easyproto.MessageMarshaler) {
 	for _, a := range dp.Attributes {
 		a.marshalProtobuf(mm.AppendMessage(9))
@@ -793,17 +1188,22 @@ func (dp *HistogramDataPoint) marshalProtobuf(mm *easyproto.MessageMarshaler) {
 	}
 	mm.AppendFixed64s(6, dp.BucketCounts)
 	mm.AppendDoubles(7, dp.ExplicitBounds)
+	for _, e := range dp.Exemplars {
+		e.marshalProtobuf(mm.AppendMessage(8))
+	}
 	mm.AppendUint32(10, dp.Flags)
 }
 
 func (dp *HistogramDataPoint) unmarshalProtobuf(src []byte) (err error) {
 	// message HistogramDataPoint {
+	//   repeated StringKeyValue labels = 1; // deprecated
 	//   repeated KeyValue attributes = 9;
 	//   fixed64 time_unix_nano = 3;
 	//   fixed64 count = 4;
 	//   optional double sum = 5;
 	//   repeated fixed64 bucket_counts = 6;
 	//   repeated double explicit_bounds = 7;
+	//   repeated Exemplar exemplars = 8;
 	//   uint32 flags = 10;
 	// }
 	var fc easyproto.FieldContext
@@ -813,13 +1213,22 @@ func (dp *HistogramDataPoint) unmarshalProtobuf(src []byte) (err error) {
 			return fmt.Errorf("cannot read next field in HistogramDataPoint: %w", err)
 		}
 		switch fc.FieldNum {
+		case 1:
+			// Deprecated labels field from OpenTelemetry protocol before v0.9.
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Label")
+			}
+			a := appendReused(&dp.Attributes)
+			if err := a.unmarshalProtobufStringKeyValue(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Label: %w", err)
+			}
 		case 9:
 			data, ok := fc.MessageData()
 			if !ok {
 				return fmt.Errorf("cannot read Attribute")
 			}
-			dp.Attributes = append(dp.Attributes, &KeyValue{})
-			a := dp.Attributes[len(dp.Attributes)-1]
+			a := appendReused(&dp.Attributes)
 			if err := a.unmarshalProtobuf(data); err != nil {
 				return fmt.Errorf("cannot unmarshal Attribute: %w", err)
 			}
@@ -853,6 +1262,15 @@ func (dp *HistogramDataPoint) unmarshalProtobuf(src []byte) (err error) {
 				return fmt.Errorf("cannot read ExplicitBounds")
 			}
 			dp.ExplicitBounds = explicitBounds
+		case 8:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Exemplar")
+			}
+			e := appendReused(&dp.Exemplars)
+			if err := e.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Exemplar: %w", err)
+			}
 		case 10:
 			flags, ok := fc.Uint32()
 			if !ok {
@@ -864,6 +1282,312 @@ func (dp *HistogramDataPoint) unmarshalProtobuf(src []byte) (err error) {
 	return nil
 }
 
+// Exemplar represents the corresponding OTEL protobuf message
+type Exemplar struct {
+	FilteredAttributes []*KeyValue
+	TimeUnixNano       uint64
+	DoubleValue        *float64
+	IntValue           *int64
+	SpanID             []byte
+	TraceID            []byte
+}
+
+// Reset resets e, so it can be reused.
+//
+// The allocated memory is retained for the subsequent unmarshaling.
+func (e *Exemplar) Reset() {
+	e.FilteredAttributes = e.FilteredAttributes[:0]
+	e.TimeUnixNano = 0
+	e.DoubleValue = nil
+	e.IntValue = nil
+	e.SpanID = e.SpanID[:0]
+	e.TraceID = e.TraceID[:0]
+}
+
+func (e *Exemplar) marshalProtobuf(mm *easyproto.MessageMarshaler) {
+	for _, a := range e.FilteredAttributes {
+		a.marshalProtobuf(mm.AppendMessage(7))
+	}
+	mm.AppendFixed64(2, e.TimeUnixNano)
+	switch {
+	case e.DoubleValue != nil:
+		mm.AppendDouble(3, *e.DoubleValue)
+	case e.IntValue != nil:
+		mm.AppendSfixed64(6, *e.IntValue)
+	}
+	mm.AppendBytes(4, e.SpanID)
+	mm.AppendBytes(5, e.TraceID)
+}
+
+func (e *Exemplar) unmarshalProtobuf(src []byte) (err error) {
+	// message Exemplar {
+	//   repeated KeyValue filtered_attributes = 7;
+	//   fixed64 time_unix_nano = 2;
+	//   oneof value {
+	//     double as_double = 3;
+	//     sfixed64 as_int = 6;
+	//   }
+	//   bytes span_id = 4;
+	//   bytes trace_id = 5;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in Exemplar: %w", err)
+		}
+		switch fc.FieldNum {
+		case 7:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read FilteredAttribute")
+			}
+			a := appendReused(&e.FilteredAttributes)
+			if err := a.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal FilteredAttribute: %w", err)
+			}
+		case 2:
+			timeUnixNano, ok := fc.Fixed64()
+			if !ok {
+				return fmt.Errorf("cannot read TimeUnixNano")
+			}
+			e.TimeUnixNano = timeUnixNano
+		case 3:
+			doubleValue, ok := fc.Double()
+			if !ok {
+				return fmt.Errorf("cannot read DoubleValue")
+			}
+			e.DoubleValue = &doubleValue
+		case 6:
+			intValue, ok := fc.Sfixed64()
+			if !ok {
+				return fmt.Errorf("cannot read IntValue")
+			}
+			e.IntValue = &intValue
+		case 4:
+			spanID, ok := fc.Bytes()
+			if !ok {
+				return fmt.Errorf("cannot read SpanID")
+			}
+			e.SpanID = append(e.SpanID[:0], spanID...)
+		case 5:
+			traceID, ok := fc.Bytes()
+			if !ok {
+				return fmt.Errorf("cannot read TraceID")
+			}
+			e.TraceID = append(e.TraceID[:0], traceID...)
+		}
+	}
+	return nil
+}
+
+// ExponentialHistogramDataPoint represents the corresponding OTEL protobuf message
+type ExponentialHistogramDataPoint struct {
+	Attributes    []*KeyValue
+	TimeUnixNano  uint64
+	Count         uint64
+	Sum           *float64
+	Scale         int32
+	ZeroCount     uint64
+	Positive      *Buckets
+	Negative      *Buckets
+	Flags         uint32
+	ZeroThreshold float64
+
+	// sparePositive and spareNegative hold reset Buckets, which can be reused during unmarshaling.
+	sparePositive *Buckets
+	spareNegative *Buckets
+}
+
+// Reset resets dp, so it can be reused.
+//
+// The allocated memory is retained for the subsequent unmarshaling.
+func (dp *ExponentialHistogramDataPoint) Reset() {
+	dp.Attributes = dp.Attributes[:0]
+	dp.TimeUnixNano = 0
+	dp.Count = 0
+	dp.Sum = nil
+	dp.Scale = 0
+	dp.ZeroCount = 0
+	if dp.Positive != nil {
+		dp.Positive.Reset()
+		dp.sparePositive = dp.Positive
+		dp.Positive = nil
+	}
+	if dp.Negative != nil {
+		dp.Negative.Reset()
+		dp.spareNegative = dp.Negative
+		dp.Negative = nil
+	}
+	dp.Flags = 0
+	dp.ZeroThreshold = 0
+}
+
+func (dp *ExponentialHistogramDataPoint) marshalProtobuf(mm *easyproto.MessageMarshaler) {
+	for _, a := range dp.Attributes {
+		a.marshalProtobuf(mm.AppendMessage(1))
+	}
+	mm.AppendFixed64(3, dp.TimeUnixNano)
+	mm.AppendFixed64(4, dp.Count)
+	if dp.Sum != nil {
+		mm.AppendDouble(5, *dp.Sum)
+	}
+	mm.AppendSint32(6, dp.Scale)
+	mm.AppendFixed64(7, dp.ZeroCount)
+	if dp.Positive != nil {
+		dp.Positive.marshalProtobuf(mm.AppendMessage(8))
+	}
+	if dp.Negative != nil {
+		dp.Negative.marshalProtobuf(mm.AppendMessage(9))
+	}
+	mm.AppendUint32(10, dp.Flags)
+	mm.AppendDouble(14, dp.ZeroThreshold)
+}
+
+func (dp *ExponentialHistogramDataPoint) unmarshalProtobuf(src []byte) (err error) {
+	// message ExponentialHistogramDataPoint {
+	//   repeated KeyValue attributes = 1;
+	//   fixed64 time_unix_nano = 3;
+	//   fixed64 count = 4;
+	//   optional double sum = 5;
+	//   sint32 scale = 6;
+	//   fixed64 zero_count = 7;
+	//   Buckets positive = 8;
+	//   Buckets negative = 9;
+	//   uint32 flags = 10;
+	//   double zero_threshold = 14;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in ExponentialHistogramDataPoint: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Attribute")
+			}
+			a := appendReused(&dp.Attributes)
+			if err := a.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Attribute: %w", err)
+			}
+		case 3:
+			timeUnixNano, ok := fc.Fixed64()
+			if !ok {
+				return fmt.Errorf("cannot read TimeUnixNano")
+			}
+			dp.TimeUnixNano = timeUnixNano
+		case 4:
+			count, ok := fc.Fixed64()
+			if !ok {
+				return fmt.Errorf("cannot read Count")
+			}
+			dp.Count = count
+		case 5:
+			sum, ok := fc.Double()
+			if !ok {
+				return fmt.Errorf("cannot read Sum")
+			}
+			dp.Sum = &sum
+		case 6:
+			scale, ok := fc.Sint32()
+			if !ok {
+				return fmt.Errorf("cannot read Scale")
+			}
+			dp.Scale = scale
+		case 7:
+			zeroCount, ok := fc.Fixed64()
+			if !ok {
+				return fmt.Errorf("cannot read ZeroCount")
+			}
+			dp.ZeroCount = zeroCount
+		case 8:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Positive")
+			}
+			dp.Positive = reuseMessage(&dp.sparePositive)
+			if err := dp.Positive.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Positive: %w", err)
+			}
+		case 9:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Negative")
+			}
+			dp.Negative = reuseMessage(&dp.spareNegative)
+			if err := dp.Negative.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Negative: %w", err)
+			}
+		case 10:
+			flags, ok := fc.Uint32()
+			if !ok {
+				return fmt.Errorf("cannot read Flags")
+			}
+			dp.Flags = flags
+		case 14:
+			zeroThreshold, ok := fc.Double()
+			if !ok {
+				return fmt.Errorf("cannot read ZeroThreshold")
+			}
+			dp.ZeroThreshold = zeroThreshold
+		}
+	}
+	return nil
+}
+
+// Buckets represents the corresponding OTEL protobuf message
+//
+// The bucket at index i covers the (base^(Offset+i), base^(Offset+i+1)] range, where base = 2^(2^-scale).
+type Buckets struct {
+	Offset       int32
+	BucketCounts []uint64
+}
+
+// Reset resets b, so it can be reused.
+//
+// The allocated memory is retained for the subsequent unmarshaling.
+func (b *Buckets) Reset() {
+	b.Offset = 0
+	b.BucketCounts = b.BucketCounts[:0]
+}
+
+func (b *Buckets) marshalProtobuf(mm *easyproto.MessageMarshaler) {
+	mm.AppendSint32(1, b.Offset)
+	mm.AppendUint64s(2, b.BucketCounts)
+}
+
+func (b *Buckets) unmarshalProtobuf(src []byte) (err error) {
+	// message Buckets {
+	//   sint32 offset = 1;
+	//   repeated uint64 bucket_counts = 2;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in Buckets: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			offset, ok := fc.Sint32()
+			if !ok {
+				return fmt.Errorf("cannot read Offset")
+			}
+			b.Offset = offset
+		case 2:
+			bucketCounts, ok := fc.UnpackUint64s(b.BucketCounts)
+			if !ok {
+				return fmt.Errorf("cannot read BucketCounts")
+			}
+			b.BucketCounts = bucketCounts
+		}
+	}
+	return nil
+}
+
 // SummaryDataPoint represents the corresponding OTEL protobuf message
 type SummaryDataPoint struct {
 	Attributes     []*KeyValue
@@ -874,6 +1598,18 @@ type SummaryDataPoint struct {
 	Flags          uint32
 }
 
+// Reset resets dp, so it can be reused.
+//
+// The allocated memory is retained for the subsequent unmarshaling.
+func (dp *SummaryDataPoint) Reset() {
+	dp.Attributes = dp.Attributes[:0]
+	dp.TimeUnixNano = 0
+	dp.Count = 0
+	dp.Sum = 0
+	dp.QuantileValues = dp.QuantileValues[:0]
+	dp.Flags = 0
+}
+
 func (dp *SummaryDataPoint) marshalProtobuf(mm *easyproto.MessageMarshaler) {
 	for _, a := range dp.Attributes {
 		a.marshalProtobuf(mm.AppendMessage(7))
@@ -889,6 +1625,7 @@ func (dp *SummaryDataPoint) marshalProtobuf(mm *easyproto.MessageMarshaler) {
 
 func (dp *SummaryDataPoint) unmarshalProtobuf(src []byte) (err error) {
 	// message SummaryDataPoint {
+	//   repeated StringKeyValue labels = 1; // deprecated
 	//   repeated KeyValue attributes = 7;
 	//   fixed64 time_unix_nano = 3;
 	//   fixed64 count = 4;
@@ -903,13 +1640,22 @@ func (dp *SummaryDataPoint) unmarshalProtobuf(src []byte) (err error) {
 			return fmt.Errorf("cannot read next field in SummaryDataPoint: %w", err)
 		}
 		switch fc.FieldNum {
+		case 1:
+			// Deprecated labels field from OpenTelemetry protocol before v0.9.
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Label")
+			}
+			a := appendReused(&dp.Attributes)
+			if err := a.unmarshalProtobufStringKeyValue(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Label: %w", err)
+			}
 		case 7:
 			data, ok := fc.MessageData()
 			if !ok {
 				return fmt.Errorf("cannot read Attribute")
 			}
-			dp.Attributes = append(dp.Attributes, &KeyValue{})
-			a := dp.Attributes[len(dp.Attributes)-1]
+			a := appendReused(&dp.Attributes)
 			if err := a.unmarshalProtobuf(data); err != nil {
 				return fmt.Errorf("cannot unmarshal Attribute: %w", err)
 			}
@@ -936,8 +1682,7 @@ func (dp *SummaryDataPoint) unmarshalProtobuf(src []byte) (err error) {
 			if !ok {
 				return fmt.Errorf("cannot read QuantileValue")
 			}
-			dp.QuantileValues = append(dp.QuantileValues, &ValueAtQuantile{})
-			v := dp.QuantileValues[len(dp.QuantileValues)-1]
+			v := appendReused(&dp.QuantileValues)
 			if err := v.unmarshalProtobuf(data); err != nil {
 				return fmt.Errorf("cannot unmarshal QuantileValue: %w", err)
 			}
@@ -958,6 +1703,12 @@ type ValueAtQuantile struct {
 	Value    float64
 }
 
+// Reset resets v, so it can be reused.
+func (v *ValueAtQuantile) Reset() {
+	v.Quantile = 0
+	v.Value = 0
+}
+
 func (v *ValueAtQuantile) marshalProtobuf(mm *easyproto.MessageMarshaler) {
 	mm.AppendDouble(1, v.Quantile)
 	mm.AppendDouble(2, v.Value)
@@ -0,0 +1,387 @@
+package pb
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/VictoriaMetrics/easyproto"
+)
+
+func TestExportMetricsServiceRequestMarshalUnmarshal(t *testing.T) {
+	f := func(r *ExportMetricsServiceRequest) {
+		t.Helper()
+
+		data := r.MarshalProtobuf(nil)
+		var result ExportMetricsServiceRequest
+		if err := result.UnmarshalProtobuf(data); err != nil {
+			t.Fatalf("cannot unmarshal ExportMetricsServiceRequest: %s", err)
+		}
+		if !reflect.DeepEqual(&result, r) {
+			t.Fatalf("unexpected result after marshal/unmarshal round-trip\ngot\n%#v\nwant\n%#v", &result, r)
+		}
+	}
+
+	stringValue := func(s string) *AnyValue {
+		return &AnyValue{
+			StringValue: &s,
+		}
+	}
+
+	// empty scope
+	f(&ExportMetricsServiceRequest{
+		ResourceMetrics: []*ResourceMetrics{
+			{
+				ScopeMetrics: []*ScopeMetrics{
+					{
+						Scope: &InstrumentationScope{},
+					},
+				},
+			},
+		},
+	})
+
+	// scope with all the fields
+	f(&ExportMetricsServiceRequest{
+		ResourceMetrics: []*ResourceMetrics{
+			{
+				Resource: &Resource{
+					Attributes: []*KeyValue{
+						{
+							Key:   "service.name",
+							Value: stringValue("foo"),
+						},
+					},
+				},
+				ScopeMetrics: []*ScopeMetrics{
+					{
+						Scope: &InstrumentationScope{
+							Name:    "go.opentelemetry.io/otel/sdk/metric",
+							Version: "v1.2.3",
+							Attributes: []*KeyValue{
+								{
+									Key:   "scope.attr",
+									Value: stringValue("bar"),
+								},
+							},
+							DroppedAttributesCount: 5,
+						},
+						Metrics: []*Metric{
+							{
+								Name: "my-gauge",
+								Unit: "ms",
+								Gauge: &Gauge{
+									DataPoints: []*NumberDataPoint{
+										{
+											TimeUnixNano: 1234,
+										},
+									},
+								},
+							},
+						},
+						SchemaURL: "https://opentelemetry.io/schemas/1.21.0",
+					},
+					{
+						SchemaURL: "https://opentelemetry.io/schemas/1.20.0",
+					},
+				},
+				SchemaURL: "https://opentelemetry.io/schemas/1.22.0",
+			},
+		},
+	})
+
+	// exponential histogram
+	sum := 12.5
+	f(&ExportMetricsServiceRequest{
+		ResourceMetrics: []*ResourceMetrics{
+			{
+				ScopeMetrics: []*ScopeMetrics{
+					{
+						Metrics: []*Metric{
+							{
+								Name: "my-exp-histogram",
+								ExponentialHistogram: &ExponentialHistogram{
+									AggregationTemporality: AggregationTemporalityCumulative,
+									DataPoints: []*ExponentialHistogramDataPoint{
+										{
+											Attributes: []*KeyValue{
+												{
+													Key:   "foo",
+													Value: stringValue("bar"),
+												},
+											},
+											TimeUnixNano:  1234,
+											Count:         10,
+											Sum:           &sum,
+											Scale:         -3,
+											ZeroCount:     1,
+											ZeroThreshold: 1e-9,
+											Positive: &Buckets{
+												Offset:       -5,
+												BucketCounts: []uint64{1, 0, 4},
+											},
+											Negative: &Buckets{
+												Offset:       2,
+												BucketCounts: []uint64{4},
+											},
+											Flags: 1,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	// exemplars
+	exemplarValue := 1.5
+	exemplarIntValue := int64(3)
+	f(&ExportMetricsServiceRequest{
+		ResourceMetrics: []*ResourceMetrics{
+			{
+				ScopeMetrics: []*ScopeMetrics{
+					{
+						Metrics: []*Metric{
+							{
+								Name: "my-sum",
+								Sum: &Sum{
+									AggregationTemporality: AggregationTemporalityCumulative,
+									IsMonotonic:            true,
+									DataPoints: []*NumberDataPoint{
+										{
+											TimeUnixNano: 1234,
+											IntValue:     &exemplarIntValue,
+											Exemplars: []*Exemplar{
+												{
+													FilteredAttributes: []*KeyValue{
+														{
+															Key:   "user",
+															Value: stringValue("foo"),
+														},
+													},
+													TimeUnixNano: 1230,
+													IntValue:     &exemplarIntValue,
+													SpanID:       []byte{1, 2, 3, 4, 5, 6, 7, 8},
+													TraceID:      []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+												},
+											},
+										},
+									},
+								},
+							},
+							{
+								Name: "my-histogram",
+								Histogram: &Histogram{
+									AggregationTemporality: AggregationTemporalityCumulative,
+									DataPoints: []*HistogramDataPoint{
+										{
+											TimeUnixNano:   1234,
+											Count:          1,
+											BucketCounts:   []uint64{1, 0},
+											ExplicitBounds: []float64{2},
+											Exemplars: []*Exemplar{
+												{
+													TimeUnixNano: 1230,
+													DoubleValue:  &exemplarValue,
+													TraceID:      []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+												},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestExportMetricsServiceRequestUnmarshalLenient(t *testing.T) {
+	f := func(data []byte, skippedExpected int, resultExpected *ExportMetricsServiceRequest) {
+		t.Helper()
+
+		var result ExportMetricsServiceRequest
+		skipped, err := result.UnmarshalProtobufLenient(data)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if skipped != skippedExpected {
+			t.Fatalf("unexpected number of skipped messages; got %d; want %d", skipped, skippedExpected)
+		}
+		if !reflect.DeepEqual(&result, resultExpected) {
+			t.Fatalf("unexpected result\ngot\n%#v\nwant\n%#v", &result, resultExpected)
+		}
+		if skipped > 0 {
+			// The strict mode must fail on the same data
+			if err := result.UnmarshalProtobuf(data); err == nil {
+				t.Fatalf("expecting non-nil error in strict mode")
+			}
+		}
+	}
+
+	corrupted := []byte{0xff}
+	marshal := func(fn func(mm *easyproto.MessageMarshaler)) []byte {
+		m := mp.Get()
+		fn(m.MessageMarshaler())
+		data := m.Marshal(nil)
+		mp.Put(m)
+		return data
+	}
+
+	// valid data
+	r := &ExportMetricsServiceRequest{
+		ResourceMetrics: []*ResourceMetrics{
+			{
+				ScopeMetrics: []*ScopeMetrics{
+					{
+						Metrics: []*Metric{
+							{
+								Name: "foo",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	f(r.MarshalProtobuf(nil), 0, r)
+
+	// corrupted Metric is skipped
+	data := marshal(func(mm *easyproto.MessageMarshaler) {
+		sm := mm.AppendMessage(1).AppendMessage(2)
+		sm.AppendMessage(2).AppendString(1, "foo")
+		sm.AppendBytes(2, corrupted)
+		sm.AppendMessage(2).AppendString(1, "bar")
+	})
+	f(data, 1, &ExportMetricsServiceRequest{
+		ResourceMetrics: []*ResourceMetrics{
+			{
+				ScopeMetrics: []*ScopeMetrics{
+					{
+						Metrics: []*Metric{
+							{
+								Name: "foo",
+							},
+							{
+								Name: "bar",
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	// ScopeMetrics with corrupted scope is skipped
+	data = marshal(func(mm *easyproto.MessageMarshaler) {
+		rm := mm.AppendMessage(1)
+		sm := rm.AppendMessage(2)
+		sm.AppendBytes(1, corrupted)
+		sm.AppendMessage(2).AppendString(1, "foo")
+		rm.AppendMessage(2).AppendMessage(2).AppendString(1, "bar")
+	})
+	f(data, 1, &ExportMetricsServiceRequest{
+		ResourceMetrics: []*ResourceMetrics{
+			{
+				ScopeMetrics: []*ScopeMetrics{
+					{
+						Metrics: []*Metric{
+							{
+								Name: "bar",
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	// ResourceMetrics with corrupted resource is skipped
+	data = marshal(func(mm *easyproto.MessageMarshaler) {
+		rm := mm.AppendMessage(1)
+		rm.AppendBytes(1, corrupted)
+		rm.AppendMessage(2).AppendMessage(2).AppendString(1, "foo")
+		mm.AppendMessage(1).AppendMessage(2).AppendMessage(2).AppendString(1, "bar")
+		mm.AppendBytes(1, corrupted)
+	})
+	f(data, 2, &ExportMetricsServiceRequest{
+		ResourceMetrics: []*ResourceMetrics{
+			{
+				ScopeMetrics: []*ScopeMetrics{
+					{
+						Metrics: []*Metric{
+							{
+								Name: "bar",
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
+func FuzzExportMetricsServiceRequestUnmarshalProtobuf(f *testing.F) {
+	r := &ExportMetricsServiceRequest{
+		ResourceMetrics: []*ResourceMetrics{
+			{
+				ScopeMetrics: []*ScopeMetrics{
+					{
+						Scope: &InstrumentationScope{
+							Name: "foo",
+						},
+						Metrics: []*Metric{
+							{
+								Name: "bar",
+								Sum: &Sum{
+									DataPoints: []*NumberDataPoint{
+										{
+											TimeUnixNano: 1234,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	f.Add(r.MarshalProtobuf(nil))
+	f.Add([]byte{})
+	f.Add([]byte{0x0a, 0x01, 0xff})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var strict ExportMetricsServiceRequest
+		strictErr := strict.UnmarshalProtobuf(data)
+
+		var lenient ExportMetricsServiceRequest
+		skipped, err := lenient.UnmarshalProtobufLenient(data)
+		if err != nil {
+			if strictErr == nil {
+				t.Fatalf("lenient mode mustn't fail when strict mode succeeds; error: %s", err)
+			}
+			return
+		}
+		if strictErr == nil {
+			if skipped != 0 {
+				t.Fatalf("unexpected number of skipped messages for valid data; got %d; want 0", skipped)
+			}
+			// Compare marshaled results, since reflect.DeepEqual doesn't treat NaN values as equal
+			if !bytes.Equal(lenient.MarshalProtobuf(nil), strict.MarshalProtobuf(nil)) {
+				t.Fatalf("unexpected result in lenient mode\ngot\n%#v\nwant\n%#v", &lenient, &strict)
+			}
+		}
+
+		// The result of lenient unmarshaling must be marshaled to valid data
+		var result ExportMetricsServiceRequest
+		if err := result.UnmarshalProtobuf(lenient.MarshalProtobuf(nil)); err != nil {
+			t.Fatalf("cannot unmarshal the marshaled result of lenient unmarshaling: %s", err)
+		}
+	})
+}
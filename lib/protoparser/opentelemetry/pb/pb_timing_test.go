@@ -0,0 +1,109 @@
+package pb
+
+import (
+	"fmt"
+	"testing"
+)
+
+func BenchmarkExportMetricsServiceRequestUnmarshalProtobuf(b *testing.B) {
+	data := newBenchRequest().MarshalProtobuf(nil)
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		var r ExportMetricsServiceRequest
+		for pb.Next() {
+			if err := r.UnmarshalProtobuf(data); err != nil {
+				panic(fmt.Errorf("cannot unmarshal request: %w", err))
+			}
+		}
+	})
+}
+
+func BenchmarkVisitMetrics(b *testing.B) {
+	data := newBenchRequest().MarshalProtobuf(nil)
+
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			err := VisitMetrics(data, nil, func(_ *ResourceMetrics, _ *ScopeMetrics, _ *Metric) error {
+				return nil
+			})
+			if err != nil {
+				panic(fmt.Errorf("cannot visit metrics: %w", err))
+			}
+		}
+	})
+}
+
+func newBenchRequest() *ExportMetricsServiceRequest {
+	stringValue := func(s string) *AnyValue {
+		return &AnyValue{
+			StringValue: &s,
+		}
+	}
+	attributes := func(i int) []*KeyValue {
+		return []*KeyValue{
+			{
+				Key:   "instance",
+				Value: stringValue(fmt.Sprintf("host-%d", i)),
+			},
+			{
+				Key:   "job",
+				Value: stringValue("bench"),
+			},
+		}
+	}
+	var metrics []*Metric
+	for i := 0; i < 100; i++ {
+		value := float64(i)
+		sum := float64(i) * 10
+		metrics = append(metrics, &Metric{
+			Name: fmt.Sprintf("gauge_%d", i),
+			Gauge: &Gauge{
+				DataPoints: []*NumberDataPoint{
+					{
+						Attributes:   attributes(i),
+						TimeUnixNano: 1234,
+						DoubleValue:  &value,
+					},
+				},
+			},
+		}, &Metric{
+			Name: fmt.Sprintf("histogram_%d", i),
+			Histogram: &Histogram{
+				AggregationTemporality: AggregationTemporalityCumulative,
+				DataPoints: []*HistogramDataPoint{
+					{
+						Attributes:     attributes(i),
+						TimeUnixNano:   1234,
+						Count:          10,
+						Sum:            &sum,
+						BucketCounts:   []uint64{1, 2, 3, 4},
+						ExplicitBounds: []float64{0.1, 1, 10},
+					},
+				},
+			},
+		})
+	}
+	return &ExportMetricsServiceRequest{
+		ResourceMetrics: []*ResourceMetrics{
+			{
+				Resource: &Resource{
+					Attributes: []*KeyValue{
+						{
+							Key:   "service.name",
+							Value: stringValue("bench"),
+						},
+					},
+				},
+				ScopeMetrics: []*ScopeMetrics{
+					{
+						Metrics: metrics,
+					},
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,117 @@
+package pb
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/VictoriaMetrics/easyproto"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/stringsutil"
+)
+
+// ExportMetricsServiceResponse represents the corresponding OTEL protobuf message
+type ExportMetricsServiceResponse struct {
+	PartialSuccess *ExportMetricsPartialSuccess
+}
+
+// MarshalProtobuf marshals r to protobuf message, appends it to dst and returns the result.
+func (r *ExportMetricsServiceResponse) MarshalProtobuf(dst []byte) []byte {
+	m := mp.Get()
+	r.marshalProtobuf(m.MessageMarshaler())
+	dst = m.Marshal(dst)
+	mp.Put(m)
+	return dst
+}
+
+func (r *ExportMetricsServiceResponse) marshalProtobuf(mm *easyproto.MessageMarshaler) {
+	if r.PartialSuccess != nil {
+		r.PartialSuccess.marshalProtobuf(mm.AppendMessage(1))
+	}
+}
+
+// UnmarshalProtobuf unmarshals r from protobuf message at src.
+func (r *ExportMetricsServiceResponse) UnmarshalProtobuf(src []byte) (err error) {
+	// message ExportMetricsServiceResponse {
+	//   ExportMetricsPartialSuccess partial_success = 1;
+	// }
+	r.PartialSuccess = nil
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in ExportMetricsServiceResponse: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read PartialSuccess data")
+			}
+			r.PartialSuccess = &ExportMetricsPartialSuccess{}
+			if err := r.PartialSuccess.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal PartialSuccess: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// MarshalJSON marshals r to OTLP/JSON encoded message.
+//
+// See https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+func (r *ExportMetricsServiceResponse) MarshalJSON() ([]byte, error) {
+	ps := r.PartialSuccess
+	if ps == nil {
+		return []byte("{}"), nil
+	}
+	// int64 fields are encoded as strings according to proto3 JSON mapping.
+	dst := []byte(`{"partialSuccess":{"rejectedDataPoints":"`)
+	dst = strconv.AppendInt(dst, ps.RejectedDataPoints, 10)
+	dst = append(dst, `","errorMessage":`...)
+	dst = append(dst, stringsutil.JSONString(ps.ErrorMessage)...)
+	dst = append(dst, "}}"...)
+	return dst, nil
+}
+
+// ExportMetricsPartialSuccess represents the corresponding OTEL protobuf message
+//
+// See https://opentelemetry.io/docs/specs/otlp/#partial-success
+type ExportMetricsPartialSuccess struct {
+	RejectedDataPoints int64
+	ErrorMessage       string
+}
+
+func (ps *ExportMetricsPartialSuccess) marshalProtobuf(mm *easyproto.MessageMarshaler) {
+	mm.AppendInt64(1, ps.RejectedDataPoints)
+	mm.AppendString(2, ps.ErrorMessage)
+}
+
+func (ps *ExportMetricsPartialSuccess) unmarshalProtobuf(src []byte) (err error) {
+	// message ExportMetricsPartialSuccess {
+	//   int64 rejected_data_points = 1;
+	//   string error_message = 2;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in ExportMetricsPartialSuccess: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			rejectedDataPoints, ok := fc.Int64()
+			if !ok {
+				return fmt.Errorf("cannot read RejectedDataPoints")
+			}
+			ps.RejectedDataPoints = rejectedDataPoints
+		case 2:
+			errorMessage, ok := fc.String()
+			if !ok {
+				return fmt.Errorf("cannot read ErrorMessage")
+			}
+			ps.ErrorMessage = strings.Clone(errorMessage)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package pb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExportMetricsServiceResponseMarshalUnmarshal(t *testing.T) {
+	f := func(resp *ExportMetricsServiceResponse) {
+		t.Helper()
+
+		data := resp.MarshalProtobuf(nil)
+		var respResult ExportMetricsServiceResponse
+		if err := respResult.UnmarshalProtobuf(data); err != nil {
+			t.Fatalf("cannot unmarshal response: %s", err)
+		}
+		if !reflect.DeepEqual(&respResult, resp) {
+			t.Fatalf("unexpected response\ngot\n%#v\nwant\n%#v", respResult.PartialSuccess, resp.PartialSuccess)
+		}
+	}
+
+	f(&ExportMetricsServiceResponse{})
+	f(&ExportMetricsServiceResponse{
+		PartialSuccess: &ExportMetricsPartialSuccess{},
+	})
+	f(&ExportMetricsServiceResponse{
+		PartialSuccess: &ExportMetricsPartialSuccess{
+			RejectedDataPoints: 42,
+			ErrorMessage:       "foo bar",
+		},
+	})
+}
+
+func TestExportMetricsServiceResponseMarshalJSON(t *testing.T) {
+	f := func(resp *ExportMetricsServiceResponse, resultExpected string) {
+		t.Helper()
+
+		data, err := resp.MarshalJSON()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if string(data) != resultExpected {
+			t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", data, resultExpected)
+		}
+	}
+
+	f(&ExportMetricsServiceResponse{}, `{}`)
+	f(&ExportMetricsServiceResponse{
+		PartialSuccess: &ExportMetricsPartialSuccess{
+			RejectedDataPoints: 42,
+			ErrorMessage:       "invalid \"foo\"\n",
+		},
+	}, `{"partialSuccess":{"rejectedDataPoints":"42","errorMessage":"invalid \"foo\"\n"}}`)
+}
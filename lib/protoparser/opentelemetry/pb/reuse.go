@@ -0,0 +1,60 @@
+package pb
+
+import (
+	"sync"
+)
+
+// resetter is a pointer to message of type T, which can be reset for the reuse.
+type resetter[T any] interface {
+	*T
+	Reset()
+}
+
+// appendReused appends a message to *dst and returns it.
+//
+// The message is reused from the unused capacity of *dst if possible in order to reduce memory allocations.
+// Such a message is reset before returning it.
+func appendReused[T any, PT resetter[T]](dst *[]PT) PT {
+	a := *dst
+	if len(a) < cap(a) {
+		a = a[:len(a)+1]
+		if v := a[len(a)-1]; v != nil {
+			v.Reset()
+			*dst = a
+			return v
+		}
+	} else {
+		a = append(a, nil)
+	}
+	v := PT(new(T))
+	a[len(a)-1] = v
+	*dst = a
+	return v
+}
+
+func getMetric() *Metric {
+	v := metricPool.Get()
+	if v == nil {
+		return &Metric{}
+	}
+	return v.(*Metric)
+}
+
+func putMetric(m *Metric) {
+	m.Reset()
+	metricPool.Put(m)
+}
+
+var metricPool sync.Pool
+
+// reuseMessage returns the message from *spare if it isn't nil. Otherwise a new message is returned.
+//
+// *spare is set to nil, so the returned message is owned by the caller.
+func reuseMessage[T any](spare **T) *T {
+	v := *spare
+	if v == nil {
+		return new(T)
+	}
+	*spare = nil
+	return v
+}
@@ -0,0 +1,141 @@
+package pb
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMetricResetReuse(t *testing.T) {
+	stringValue := func(s string) *AnyValue {
+		return &AnyValue{
+			StringValue: &s,
+		}
+	}
+	value := 1.5
+	intValue := int64(3)
+	sum := 12.5
+	metrics := []*Metric{
+		{
+			Name: "my-gauge",
+			Unit: "ms",
+			Gauge: &Gauge{
+				DataPoints: []*NumberDataPoint{
+					{
+						Attributes: []*KeyValue{
+							{
+								Key:   "foo",
+								Value: stringValue("bar"),
+							},
+							{
+								Key:   "baz",
+								Value: stringValue("qux"),
+							},
+						},
+						TimeUnixNano: 1234,
+						DoubleValue:  &value,
+					},
+					{
+						TimeUnixNano: 1235,
+						IntValue:     &intValue,
+						Flags:        1,
+					},
+				},
+			},
+		},
+		{
+			Name: "my-sum",
+			Sum: &Sum{
+				AggregationTemporality: AggregationTemporalityCumulative,
+				IsMonotonic:            true,
+				DataPoints: []*NumberDataPoint{
+					{
+						Attributes: []*KeyValue{
+							{
+								Key: "foo",
+							},
+						},
+						TimeUnixNano: 1234,
+						IntValue:     &intValue,
+						Exemplars: []*Exemplar{
+							{
+								TimeUnixNano: 1230,
+								DoubleValue:  &value,
+								SpanID:       []byte{1, 2, 3, 4, 5, 6, 7, 8},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "my-histogram",
+			Histogram: &Histogram{
+				AggregationTemporality: AggregationTemporalityDelta,
+				DataPoints: []*HistogramDataPoint{
+					{
+						TimeUnixNano:   1234,
+						Count:          1,
+						Sum:            &sum,
+						BucketCounts:   []uint64{1, 0},
+						ExplicitBounds: []float64{2},
+					},
+				},
+			},
+		},
+		{
+			Name: "my-exp-histogram",
+			ExponentialHistogram: &ExponentialHistogram{
+				AggregationTemporality: AggregationTemporalityCumulative,
+				DataPoints: []*ExponentialHistogramDataPoint{
+					{
+						TimeUnixNano: 1234,
+						Count:        10,
+						Scale:        -3,
+						Positive: &Buckets{
+							Offset:       -5,
+							BucketCounts: []uint64{1, 0, 4},
+						},
+					},
+				},
+			},
+		},
+		{
+			Name: "my-summary",
+			Summary: &Summary{
+				DataPoints: []*SummaryDataPoint{
+					{
+						TimeUnixNano: 1234,
+						Count:        3,
+						Sum:          4.5,
+						QuantileValues: []*ValueAtQuantile{
+							{
+								Quantile: 0.5,
+								Value:    1,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// Unmarshal all the metrics into the same Metric in various orders,
+	// and verify that the previous contents doesn't leak into the unmarshaled metric.
+	m := getMetric()
+	defer putMetric(m)
+	for i := 0; i < 3; i++ {
+		for j := range metrics {
+			mExpected := metrics[(i+j)%len(metrics)]
+			dataExpected := marshalMessage(mExpected.marshalProtobuf)
+
+			m.Reset()
+			if err := m.unmarshalProtobuf(dataExpected); err != nil {
+				t.Fatalf("cannot unmarshal metric %q: %s", mExpected.Name, err)
+			}
+			data := marshalMessage(m.marshalProtobuf)
+			if !bytes.Equal(data, dataExpected) {
+				t.Fatalf("unexpected metric after reuse\ngot\n%#v\nwant\n%#v", m, mExpected)
+			}
+		}
+	}
+}
@@ -0,0 +1,502 @@
+package pb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/VictoriaMetrics/easyproto"
+)
+
+// UnmarshalProtobufStrict unmarshals r from protobuf message at src in strict mode.
+//
+// Contrary to UnmarshalProtobuf, it rejects messages with field numbers unknown to OpenTelemetry protocol,
+// metrics without name or data and data points without timestamp or value.
+//
+// *ValidationError is returned if src can be unmarshaled, but it doesn't pass the validation.
+// In this case r contains the unmarshaled request.
+func (r *ExportMetricsServiceRequest) UnmarshalProtobufStrict(src []byte) error {
+	if err := r.UnmarshalProtobuf(src); err != nil {
+		return err
+	}
+	return ValidateProtobufStrict(src)
+}
+
+// ValidateProtobufStrict verifies protobuf-encoded ExportMetricsServiceRequest at src in the same way as UnmarshalProtobufStrict does.
+//
+// Contrary to UnmarshalProtobufStrict, it doesn't unmarshal the whole request at once.
+// Only a single Metric is unmarshaled at a time, so memory usage doesn't depend on the request size.
+//
+// *ValidationError is returned if src can be unmarshaled, but it doesn't pass the validation.
+func ValidateProtobufStrict(src []byte) error {
+	m := getMetric()
+	defer putMetric(m)
+
+	var ve ValidationError
+	if err := ve.validateRequest(src, m); err != nil {
+		return err
+	}
+	if len(ve.Errors) > 0 {
+		return &ve
+	}
+	return nil
+}
+
+// ValidationError is returned from ExportMetricsServiceRequest.UnmarshalProtobufStrict.
+type ValidationError struct {
+	// Errors contains validation errors for the request in the order of their occurrence.
+	Errors []*MetricError
+}
+
+// Error implements error interface.
+func (ve *ValidationError) Error() string {
+	const maxErrors = 10
+
+	a := make([]string, 0, maxErrors)
+	for i, me := range ve.Errors {
+		if i >= maxErrors {
+			a = append(a, fmt.Sprintf("and %d more errors", len(ve.Errors)-maxErrors))
+			break
+		}
+		a = append(a, me.Error())
+	}
+	return fmt.Sprintf("invalid OpenTelemetry request: %s", strings.Join(a, "; "))
+}
+
+// MetricError is a validation error for a particular part of ExportMetricsServiceRequest.
+type MetricError struct {
+	// ResourceMetricsIndex is the index of ResourceMetrics with the error.
+	//
+	// It is set to -1 if the error belongs to ExportMetricsServiceRequest itself.
+	ResourceMetricsIndex int
+
+	// ScopeMetricsIndex is the index of ScopeMetrics with the error inside ResourceMetrics.
+	//
+	// It is set to -1 if the error belongs to ResourceMetrics itself.
+	ScopeMetricsIndex int
+
+	// MetricIndex is the index of Metric with the error inside ScopeMetrics.
+	//
+	// It is set to -1 if the error doesn't belong to a particular Metric.
+	MetricIndex int
+
+	// MetricName is the name of the metric with the error.
+	MetricName string
+
+	// Err is the error.
+	Err error
+}
+
+// Error implements error interface.
+func (me *MetricError) Error() string {
+	switch {
+	case me.ResourceMetricsIndex < 0:
+		return me.Err.Error()
+	case me.ScopeMetricsIndex < 0:
+		return fmt.Sprintf("resource_metrics[%d]: %s", me.ResourceMetricsIndex, me.Err)
+	case me.MetricIndex < 0:
+		return fmt.Sprintf("resource_metrics[%d].scope_metrics[%d]: %s", me.ResourceMetricsIndex, me.ScopeMetricsIndex, me.Err)
+	default:
+		return fmt.Sprintf("resource_metrics[%d].scope_metrics[%d].metrics[%d] (name=%q): %s",
+			me.ResourceMetricsIndex, me.ScopeMetricsIndex, me.MetricIndex, me.MetricName, me.Err)
+	}
+}
+
+// Unwrap returns the underlying error.
+func (me *MetricError) Unwrap() error {
+	return me.Err
+}
+
+func (ve *ValidationError) add(rmIdx, smIdx, mIdx int, metricName string, err error) {
+	ve.Errors = append(ve.Errors, &MetricError{
+		ResourceMetricsIndex: rmIdx,
+		ScopeMetricsIndex:    smIdx,
+		MetricIndex:          mIdx,
+		MetricName:           metricName,
+		Err:                  err,
+	})
+}
+
+// validateRequest validates ExportMetricsServiceRequest at src.
+//
+// Validation errors are added to ve, while the returned error means that src cannot be unmarshaled.
+// m is used as a temporary storage for the unmarshaled metrics.
+func (ve *ValidationError) validateRequest(src []byte, m *Metric) error {
+	rmIdx := 0
+	return visitFields(src, func(fieldNum uint32, fc *easyproto.FieldContext) error {
+		if fieldNum != 1 {
+			ve.add(-1, -1, -1, "", fmt.Errorf("unknown field #%d in ExportMetricsServiceRequest", fieldNum))
+			return nil
+		}
+		data, ok := fc.MessageData()
+		if !ok {
+			return fmt.Errorf("cannot read ResourceMetrics data")
+		}
+		if err := ve.validateResourceMetrics(data, rmIdx, m); err != nil {
+			return fmt.Errorf("cannot unmarshal ResourceMetrics: %w", err)
+		}
+		rmIdx++
+		return nil
+	})
+}
+
+func (ve *ValidationError) validateResourceMetrics(src []byte, rmIdx int, m *Metric) error {
+	smIdx := 0
+	return visitFields(src, func(fieldNum uint32, fc *easyproto.FieldContext) error {
+		switch fieldNum {
+		case 1:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Resource data")
+			}
+			var r Resource
+			if err := r.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Resource: %w", err)
+			}
+			if err := checkKnownFields(data, resourceSchema); err != nil {
+				ve.add(rmIdx, -1, -1, "", fmt.Errorf("invalid resource: %w", err))
+			}
+		case 2:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read ScopeMetrics data")
+			}
+			if err := ve.validateScopeMetrics(data, rmIdx, smIdx, m); err != nil {
+				return fmt.Errorf("cannot unmarshal ScopeMetrics: %w", err)
+			}
+			smIdx++
+		case 3:
+			if _, ok := fc.String(); !ok {
+				return fmt.Errorf("cannot read schema_url")
+			}
+		default:
+			ve.add(rmIdx, -1, -1, "", fmt.Errorf("unknown field #%d in ResourceMetrics", fieldNum))
+		}
+		return nil
+	})
+}
+
+func (ve *ValidationError) validateScopeMetrics(src []byte, rmIdx, smIdx int, m *Metric) error {
+	mIdx := 0
+	return visitFields(src, func(fieldNum uint32, fc *easyproto.FieldContext) error {
+		switch fieldNum {
+		case 1:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read InstrumentationScope data")
+			}
+			var is InstrumentationScope
+			if err := is.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal InstrumentationScope: %w", err)
+			}
+			if err := checkKnownFields(data, instrumentationScopeSchema); err != nil {
+				ve.add(rmIdx, smIdx, -1, "", fmt.Errorf("invalid scope: %w", err))
+			}
+		case 2:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Metric data")
+			}
+			m.Reset()
+			if err := m.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Metric: %w", err)
+			}
+			if err := checkKnownFields(data, metricSchema); err != nil {
+				ve.add(rmIdx, smIdx, mIdx, m.Name, err)
+			}
+			if err := m.validate(); err != nil {
+				ve.add(rmIdx, smIdx, mIdx, m.Name, err)
+			}
+			mIdx++
+		case 3:
+			if _, ok := fc.String(); !ok {
+				return fmt.Errorf("cannot read schema_url")
+			}
+		default:
+			ve.add(rmIdx, smIdx, -1, "", fmt.Errorf("unknown field #%d in ScopeMetrics", fieldNum))
+		}
+		return nil
+	})
+}
+
+// validate verifies whether m contains all the required data.
+func (m *Metric) validate() error {
+	if m.Name == "" {
+		return fmt.Errorf("missing metric name")
+	}
+	switch {
+	case m.Gauge != nil:
+		return validateNumberDataPoints(m.Gauge.DataPoints)
+	case m.Sum != nil:
+		return validateNumberDataPoints(m.Sum.DataPoints)
+	case m.Histogram != nil:
+		for i, dp := range m.Histogram.DataPoints {
+			if dp.TimeUnixNano == 0 {
+				return fmt.Errorf("missing time_unix_nano at data_points[%d]", i)
+			}
+			if len(dp.BucketCounts) > 0 && len(dp.BucketCounts) != len(dp.ExplicitBounds)+1 {
+				return fmt.Errorf("unexpected number of bucket_counts at data_points[%d]; got %d; want %d", i, len(dp.BucketCounts), len(dp.ExplicitBounds)+1)
+			}
+		}
+		return nil
+	case m.ExponentialHistogram != nil:
+		for i, dp := range m.ExponentialHistogram.DataPoints {
+			if dp.TimeUnixNano == 0 {
+				return fmt.Errorf("missing time_unix_nano at data_points[%d]", i)
+			}
+		}
+		return nil
+	case m.Summary != nil:
+		for i, dp := range m.Summary.DataPoints {
+			if dp.TimeUnixNano == 0 {
+				return fmt.Errorf("missing time_unix_nano at data_points[%d]", i)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("missing metric data")
+	}
+}
+
+func validateNumberDataPoints(dps []*NumberDataPoint) error {
+	for i, dp := range dps {
+		if dp.TimeUnixNano == 0 {
+			return fmt.Errorf("missing time_unix_nano at data_points[%d]", i)
+		}
+		if dp.DoubleValue == nil && dp.IntValue == nil {
+			return fmt.Errorf("missing value at data_points[%d]", i)
+		}
+	}
+	return nil
+}
+
+// visitFields calls f for every field in protobuf message at src.
+func visitFields(src []byte, f func(fieldNum uint32, fc *easyproto.FieldContext) error) (err error) {
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field: %w", err)
+		}
+		if err := f(fc.FieldNum, &fc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// messageSchema describes fields of OpenTelemetry protobuf message.
+type messageSchema struct {
+	name string
+
+	// fields maps known field numbers to the schema of nested messages.
+	//
+	// nil schema is used for scalar fields and for messages, which mustn't be checked.
+	fields map[uint32]*messageSchema
+}
+
+// checkKnownFields returns an error if the message at src contains fields unknown to s.
+func checkKnownFields(src []byte, s *messageSchema) error {
+	return visitFields(src, func(fieldNum uint32, fc *easyproto.FieldContext) error {
+		nested, ok := s.fields[fieldNum]
+		if !ok {
+			return fmt.Errorf("unknown field #%d in %s", fieldNum, s.name)
+		}
+		if nested == nil {
+			return nil
+		}
+		data, ok := fc.MessageData()
+		if !ok {
+			return fmt.Errorf("cannot read field #%d in %s", fieldNum, s.name)
+		}
+		return checkKnownFields(data, nested)
+	})
+}
+
+// The following schemas contain all the fields from OpenTelemetry protocol v1,
+// including the fields, which aren't unmarshaled by this package.
+//
+// See https://github.com/open-telemetry/opentelemetry-proto/blob/main/opentelemetry/proto/metrics/v1/metrics.proto
+var (
+	anyValueSchema = &messageSchema{
+		name: "AnyValue",
+	}
+	keyValueSchema = &messageSchema{
+		name: "KeyValue",
+		fields: map[uint32]*messageSchema{
+			1: nil,
+			2: anyValueSchema,
+		},
+	}
+	arrayValueSchema = &messageSchema{
+		name: "ArrayValue",
+		fields: map[uint32]*messageSchema{
+			1: anyValueSchema,
+		},
+	}
+	keyValueListSchema = &messageSchema{
+		name: "KeyValueList",
+		fields: map[uint32]*messageSchema{
+			1: keyValueSchema,
+		},
+	}
+
+	resourceSchema = &messageSchema{
+		name: "Resource",
+		fields: map[uint32]*messageSchema{
+			1: keyValueSchema,
+			2: nil,
+			3: nil,
+		},
+	}
+	instrumentationScopeSchema = &messageSchema{
+		name: "InstrumentationScope",
+		fields: map[uint32]*messageSchema{
+			1: nil,
+			2: nil,
+			3: keyValueSchema,
+			4: nil,
+		},
+	}
+
+	exemplarSchema = &messageSchema{
+		name: "Exemplar",
+		fields: map[uint32]*messageSchema{
+			2: nil,
+			3: nil,
+			4: nil,
+			5: nil,
+			6: nil,
+			7: keyValueSchema,
+		},
+	}
+	numberDataPointSchema = &messageSchema{
+		name: "NumberDataPoint",
+		fields: map[uint32]*messageSchema{
+			2: nil,
+			3: nil,
+			4: nil,
+			5: exemplarSchema,
+			6: nil,
+			7: keyValueSchema,
+			8: nil,
+		},
+	}
+	histogramDataPointSchema = &messageSchema{
+		name: "HistogramDataPoint",
+		fields: map[uint32]*messageSchema{
+			2:  nil,
+			3:  nil,
+			4:  nil,
+			5:  nil,
+			6:  nil,
+			7:  nil,
+			8:  exemplarSchema,
+			9:  keyValueSchema,
+			10: nil,
+			11: nil,
+			12: nil,
+		},
+	}
+	bucketsSchema = &messageSchema{
+		name: "Buckets",
+		fields: map[uint32]*messageSchema{
+			1: nil,
+			2: nil,
+		},
+	}
+	exponentialHistogramDataPointSchema = &messageSchema{
+		name: "ExponentialHistogramDataPoint",
+		fields: map[uint32]*messageSchema{
+			1:  keyValueSchema,
+			2:  nil,
+			3:  nil,
+			4:  nil,
+			5:  nil,
+			6:  nil,
+			7:  nil,
+			8:  bucketsSchema,
+			9:  bucketsSchema,
+			10: nil,
+			11: exemplarSchema,
+			12: nil,
+			13: nil,
+			14: nil,
+		},
+	}
+	valueAtQuantileSchema = &messageSchema{
+		name: "ValueAtQuantile",
+		fields: map[uint32]*messageSchema{
+			1: nil,
+			2: nil,
+		},
+	}
+	summaryDataPointSchema = &messageSchema{
+		name: "SummaryDataPoint",
+		fields: map[uint32]*messageSchema{
+			2: nil,
+			3: nil,
+			4: nil,
+			5: nil,
+			6: valueAtQuantileSchema,
+			7: keyValueSchema,
+			8: nil,
+		},
+	}
+
+	metricSchema = &messageSchema{
+		name: "Metric",
+		fields: map[uint32]*messageSchema{
+			1: nil,
+			2: nil,
+			3: nil,
+			5: {
+				name: "Gauge",
+				fields: map[uint32]*messageSchema{
+					1: numberDataPointSchema,
+				},
+			},
+			7: {
+				name: "Sum",
+				fields: map[uint32]*messageSchema{
+					1: numberDataPointSchema,
+					2: nil,
+					3: nil,
+				},
+			},
+			9: {
+				name: "Histogram",
+				fields: map[uint32]*messageSchema{
+					1: histogramDataPointSchema,
+					2: nil,
+				},
+			},
+			10: {
+				name: "ExponentialHistogram",
+				fields: map[uint32]*messageSchema{
+					1: exponentialHistogramDataPointSchema,
+					2: nil,
+				},
+			},
+			11: {
+				name: "Summary",
+				fields: map[uint32]*messageSchema{
+					1: summaryDataPointSchema,
+				},
+			},
+			12: keyValueSchema,
+		},
+	}
+)
+
+func init() {
+	// AnyValue refers to ArrayValue and KeyValueList, which refer to AnyValue, so initialize its fields here.
+	anyValueSchema.fields = map[uint32]*messageSchema{
+		1: nil,
+		2: nil,
+		3: nil,
+		4: nil,
+		5: arrayValueSchema,
+		6: keyValueListSchema,
+		7: nil,
+	}
+}
@@ -0,0 +1,175 @@
+package pb
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/VictoriaMetrics/easyproto"
+)
+
+func TestExportMetricsServiceRequestUnmarshalProtobufStrict(t *testing.T) {
+	f := func(data []byte, errorsExpected []string) {
+		t.Helper()
+
+		var r ExportMetricsServiceRequest
+		err := r.UnmarshalProtobufStrict(data)
+
+		// ValidateProtobufStrict must return the same result as UnmarshalProtobufStrict
+		errValidate := ValidateProtobufStrict(data)
+		if (err == nil) != (errValidate == nil) || (err != nil && err.Error() != errValidate.Error()) {
+			t.Fatalf("unexpected error from ValidateProtobufStrict; got %v; want %v", errValidate, err)
+		}
+
+		if len(errorsExpected) == 0 {
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			return
+		}
+		var ve *ValidationError
+		if !errors.As(err, &ve) {
+			t.Fatalf("expecting ValidationError; got %v", err)
+		}
+		var errs []string
+		for _, me := range ve.Errors {
+			errs = append(errs, me.Error())
+		}
+		if !reflect.DeepEqual(errs, errorsExpected) {
+			t.Fatalf("unexpected errors\ngot\n%q\nwant\n%q", errs, errorsExpected)
+		}
+	}
+
+	value := 1.5
+	gauge := func(name string, timestamp uint64) *Metric {
+		return &Metric{
+			Name: name,
+			Gauge: &Gauge{
+				DataPoints: []*NumberDataPoint{
+					{
+						TimeUnixNano: timestamp,
+						DoubleValue:  &value,
+					},
+				},
+			},
+		}
+	}
+	request := func(metrics ...*Metric) *ExportMetricsServiceRequest {
+		return &ExportMetricsServiceRequest{
+			ResourceMetrics: []*ResourceMetrics{
+				{
+					ScopeMetrics: []*ScopeMetrics{
+						{
+							Metrics: metrics,
+						},
+					},
+				},
+			},
+		}
+	}
+	requestWithRawMetric := func(f func(mm *easyproto.MessageMarshaler)) []byte {
+		return marshalMessage(func(mm *easyproto.MessageMarshaler) {
+			sm := mm.AppendMessage(1).AppendMessage(2)
+			f(sm.AppendMessage(2))
+		})
+	}
+
+	// valid request
+	f(request(gauge("foo", 123), gauge("bar", 456)).MarshalProtobuf(nil), nil)
+
+	// fields, which aren't unmarshaled, but which are known to OpenTelemetry protocol
+	f(requestWithRawMetric(func(mm *easyproto.MessageMarshaler) {
+		mm.AppendString(1, "foo")
+		mm.AppendString(2, "description")
+		dp := mm.AppendMessage(5).AppendMessage(1)
+		dp.AppendFixed64(2, 100)
+		dp.AppendFixed64(3, 123)
+		dp.AppendDouble(4, 1)
+	}), nil)
+
+	// missing metric name and missing timestamp
+	f(request(gauge("", 123), gauge("bar", 0)).MarshalProtobuf(nil), []string{
+		`resource_metrics[0].scope_metrics[0].metrics[0] (name=""): missing metric name`,
+		`resource_metrics[0].scope_metrics[0].metrics[1] (name="bar"): missing time_unix_nano at data_points[0]`,
+	})
+
+	// missing metric data
+	f(request(&Metric{
+		Name: "foo",
+	}).MarshalProtobuf(nil), []string{
+		`resource_metrics[0].scope_metrics[0].metrics[0] (name="foo"): missing metric data`,
+	})
+
+	// missing data point value
+	f(request(&Metric{
+		Name: "foo",
+		Sum: &Sum{
+			AggregationTemporality: AggregationTemporalityCumulative,
+			DataPoints: []*NumberDataPoint{
+				{
+					TimeUnixNano: 123,
+				},
+			},
+		},
+	}).MarshalProtobuf(nil), []string{
+		`resource_metrics[0].scope_metrics[0].metrics[0] (name="foo"): missing value at data_points[0]`,
+	})
+
+	// invalid number of histogram buckets
+	f(request(&Metric{
+		Name: "foo",
+		Histogram: &Histogram{
+			AggregationTemporality: AggregationTemporalityCumulative,
+			DataPoints: []*HistogramDataPoint{
+				{
+					TimeUnixNano:   123,
+					BucketCounts:   []uint64{1, 2, 3},
+					ExplicitBounds: []float64{1},
+				},
+			},
+		},
+	}).MarshalProtobuf(nil), []string{
+		`resource_metrics[0].scope_metrics[0].metrics[0] (name="foo"): unexpected number of bucket_counts at data_points[0]; got 3; want 2`,
+	})
+
+	// unknown field in nested data point
+	f(requestWithRawMetric(func(mm *easyproto.MessageMarshaler) {
+		mm.AppendString(1, "foo")
+		dp := mm.AppendMessage(5).AppendMessage(1)
+		dp.AppendFixed64(3, 123)
+		dp.AppendDouble(4, 1)
+		dp.AppendString(99, "unknown")
+	}), []string{
+		`resource_metrics[0].scope_metrics[0].metrics[0] (name="foo"): unknown field #99 in NumberDataPoint`,
+	})
+
+	// unknown fields outside metrics
+	f(marshalMessage(func(mm *easyproto.MessageMarshaler) {
+		rm := mm.AppendMessage(1)
+		rm.AppendMessage(1).AppendString(42, "unknown")
+		sm := rm.AppendMessage(2)
+		sm.AppendMessage(1).AppendString(42, "unknown")
+		sm.AppendUint32(42, 1)
+		rm.AppendUint32(42, 1)
+		mm.AppendUint32(42, 1)
+	}), []string{
+		`resource_metrics[0]: invalid resource: unknown field #42 in Resource`,
+		`resource_metrics[0].scope_metrics[0]: invalid scope: unknown field #42 in InstrumentationScope`,
+		`resource_metrics[0].scope_metrics[0]: unknown field #42 in ScopeMetrics`,
+		`resource_metrics[0]: unknown field #42 in ResourceMetrics`,
+		`unknown field #42 in ExportMetricsServiceRequest`,
+	})
+
+	// malformed request must return an error other than ValidationError
+	data := request(gauge("foo", 123)).MarshalProtobuf(nil)
+	var r ExportMetricsServiceRequest
+	err := r.UnmarshalProtobufStrict(data[:len(data)-1])
+	var ve *ValidationError
+	if err == nil || errors.As(err, &ve) {
+		t.Fatalf("expecting non-validation error for malformed request; got %v", err)
+	}
+	err = ValidateProtobufStrict(data[:len(data)-1])
+	if err == nil || errors.As(err, &ve) {
+		t.Fatalf("expecting non-validation error from ValidateProtobufStrict for malformed request; got %v", err)
+	}
+}
@@ -0,0 +1,547 @@
+package pb
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/VictoriaMetrics/easyproto"
+)
+
+// ExportTraceServiceRequest represents the corresponding OTEL protobuf message
+type ExportTraceServiceRequest struct {
+	ResourceSpans []*ResourceSpans
+}
+
+// UnmarshalProtobuf unmarshals r from protobuf message at src.
+func (r *ExportTraceServiceRequest) UnmarshalProtobuf(src []byte) error {
+	r.ResourceSpans = nil
+	return r.unmarshalProtobuf(src)
+}
+
+// MarshalProtobuf marshals r to protobuf message, appends it to dst and returns the result.
+func (r *ExportTraceServiceRequest) MarshalProtobuf(dst []byte) []byte {
+	m := mp.Get()
+	r.marshalProtobuf(m.MessageMarshaler())
+	dst = m.Marshal(dst)
+	mp.Put(m)
+	return dst
+}
+
+func (r *ExportTraceServiceRequest) marshalProtobuf(mm *easyproto.MessageMarshaler) {
+	for _, rs := range r.ResourceSpans {
+		rs.marshalProtobuf(mm.AppendMessage(1))
+	}
+}
+
+func (r *ExportTraceServiceRequest) unmarshalProtobuf(src []byte) (err error) {
+	// message ExportTraceServiceRequest {
+	//   repeated ResourceSpans resource_spans = 1;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in ExportTraceServiceRequest: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read ResourceSpans data")
+			}
+			r.ResourceSpans = append(r.ResourceSpans, &ResourceSpans{})
+			rs := r.ResourceSpans[len(r.ResourceSpans)-1]
+			if err := rs.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal ResourceSpans: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// ResourceSpans represents the corresponding OTEL protobuf message
+type ResourceSpans struct {
+	Resource   *Resource
+	ScopeSpans []*ScopeSpans
+}
+
+func (rs *ResourceSpans) marshalProtobuf(mm *easyproto.MessageMarshaler) {
+	if rs.Resource != nil {
+		rs.Resource.marshalProtobuf(mm.AppendMessage(1))
+	}
+	for _, ss := range rs.ScopeSpans {
+		ss.marshalProtobuf(mm.AppendMessage(2))
+	}
+}
+
+func (rs *ResourceSpans) unmarshalProtobuf(src []byte) (err error) {
+	// message ResourceSpans {
+	//   Resource resource = 1;
+	//   repeated ScopeSpans scope_spans = 2;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in ResourceSpans: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Resource data")
+			}
+			rs.Resource = &Resource{}
+			if err := rs.Resource.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot umarshal Resource: %w", err)
+			}
+		case 2:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read ScopeSpans data")
+			}
+			rs.ScopeSpans = append(rs.ScopeSpans, &ScopeSpans{})
+			ss := rs.ScopeSpans[len(rs.ScopeSpans)-1]
+			if err := ss.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal ScopeSpans: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// ScopeSpans represents the corresponding OTEL protobuf message
+type ScopeSpans struct {
+	Scope *InstrumentationScope
+	Spans []*Span
+}
+
+func (ss *ScopeSpans) marshalProtobuf(mm *easyproto.MessageMarshaler) {
+	if ss.Scope != nil {
+		ss.Scope.marshalProtobuf(mm.AppendMessage(1))
+	}
+	for _, s := range ss.Spans {
+		s.marshalProtobuf(mm.AppendMessage(2))
+	}
+}
+
+func (ss *ScopeSpans) unmarshalProtobuf(src []byte) (err error) {
+	// message ScopeSpans {
+	//   InstrumentationScope scope = 1;
+	//   repeated Span spans = 2;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in ScopeSpans: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read InstrumentationScope data")
+			}
+			ss.Scope = &InstrumentationScope{}
+			if err := ss.Scope.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal InstrumentationScope: %w", err)
+			}
+		case 2:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Span data")
+			}
+			ss.Spans = append(ss.Spans, &Span{})
+			s := ss.Spans[len(ss.Spans)-1]
+			if err := s.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Span: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// Span represents the corresponding OTEL protobuf message
+//
+// See https://github.com/open-telemetry/opentelemetry-proto/blob/34d29fe5ad4689b5db0259d3750de2bfa195bc85/opentelemetry/proto/trace/v1/trace.proto
+type Span struct {
+	TraceID           []byte
+	SpanID            []byte
+	TraceState        string
+	ParentSpanID      []byte
+	Name              string
+	Kind              SpanKind
+	StartTimeUnixNano uint64
+	EndTimeUnixNano   uint64
+	Attributes        []*KeyValue
+	Events            []*SpanEvent
+	Links             []*SpanLink
+	Status            *SpanStatus
+}
+
+func (s *Span) marshalProtobuf(mm *easyproto.MessageMarshaler) {
+	mm.AppendBytes(1, s.TraceID)
+	mm.AppendBytes(2, s.SpanID)
+	mm.AppendString(3, s.TraceState)
+	mm.AppendBytes(4, s.ParentSpanID)
+	mm.AppendString(5, s.Name)
+	mm.AppendInt32(6, int32(s.Kind))
+	mm.AppendFixed64(7, s.StartTimeUnixNano)
+	mm.AppendFixed64(8, s.EndTimeUnixNano)
+	for _, a := range s.Attributes {
+		a.marshalProtobuf(mm.AppendMessage(9))
+	}
+	for _, e := range s.Events {
+		e.marshalProtobuf(mm.AppendMessage(11))
+	}
+	for _, l := range s.Links {
+		l.marshalProtobuf(mm.AppendMessage(13))
+	}
+	if s.Status != nil {
+		s.Status.marshalProtobuf(mm.AppendMessage(15))
+	}
+}
+
+func (s *Span) unmarshalProtobuf(src []byte) (err error) {
+	// message Span {
+	//   bytes trace_id = 1;
+	//   bytes span_id = 2;
+	//   string trace_state = 3;
+	//   bytes parent_span_id = 4;
+	//   string name = 5;
+	//   SpanKind kind = 6;
+	//   fixed64 start_time_unix_nano = 7;
+	//   fixed64 end_time_unix_nano = 8;
+	//   repeated KeyValue attributes = 9;
+	//   repeated Event events = 11;
+	//   repeated Link links = 13;
+	//   Status status = 15;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in Span: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			traceID, ok := fc.Bytes()
+			if !ok {
+				return fmt.Errorf("cannot read trace id")
+			}
+			s.TraceID = append(s.TraceID[:0], traceID...)
+		case 2:
+			spanID, ok := fc.Bytes()
+			if !ok {
+				return fmt.Errorf("cannot read span id")
+			}
+			s.SpanID = append(s.SpanID[:0], spanID...)
+		case 3:
+			traceState, ok := fc.String()
+			if !ok {
+				return fmt.Errorf("cannot read trace state")
+			}
+			s.TraceState = strings.Clone(traceState)
+		case 4:
+			parentSpanID, ok := fc.Bytes()
+			if !ok {
+				return fmt.Errorf("cannot read parent span id")
+			}
+			s.ParentSpanID = append(s.ParentSpanID[:0], parentSpanID...)
+		case 5:
+			name, ok := fc.String()
+			if !ok {
+				return fmt.Errorf("cannot read span name")
+			}
+			s.Name = strings.Clone(name)
+		case 6:
+			kind, ok := fc.Int32()
+			if !ok {
+				return fmt.Errorf("cannot read span kind")
+			}
+			s.Kind = SpanKind(kind)
+		case 7:
+			startTimeUnixNano, ok := fc.Fixed64()
+			if !ok {
+				return fmt.Errorf("cannot read span start timestamp")
+			}
+			s.StartTimeUnixNano = startTimeUnixNano
+		case 8:
+			endTimeUnixNano, ok := fc.Fixed64()
+			if !ok {
+				return fmt.Errorf("cannot read span end timestamp")
+			}
+			s.EndTimeUnixNano = endTimeUnixNano
+		case 9:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Attribute data")
+			}
+			s.Attributes = append(s.Attributes, &KeyValue{})
+			a := s.Attributes[len(s.Attributes)-1]
+			if err := a.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Attribute: %w", err)
+			}
+		case 11:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Event data")
+			}
+			s.Events = append(s.Events, &SpanEvent{})
+			e := s.Events[len(s.Events)-1]
+			if err := e.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Event: %w", err)
+			}
+		case 13:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Link data")
+			}
+			s.Links = append(s.Links, &SpanLink{})
+			l := s.Links[len(s.Links)-1]
+			if err := l.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Link: %w", err)
+			}
+		case 15:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Status data")
+			}
+			s.Status = &SpanStatus{}
+			if err := s.Status.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Status: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// SpanKind represents the corresponding OTEL protobuf enum
+type SpanKind int32
+
+const (
+	// SpanKindUnspecified is unspecified span kind
+	SpanKindUnspecified = SpanKind(0)
+
+	// SpanKindInternal is an internal operation within an application
+	SpanKindInternal = SpanKind(1)
+
+	// SpanKindServer is a server-side handling of a synchronous RPC or other remote request
+	SpanKindServer = SpanKind(2)
+
+	// SpanKindClient is a request to some remote service
+	SpanKindClient = SpanKind(3)
+
+	// SpanKindProducer is an initiator of an asynchronous request
+	SpanKindProducer = SpanKind(4)
+
+	// SpanKindConsumer is a child of an asynchronous producer request
+	SpanKindConsumer = SpanKind(5)
+)
+
+// String returns string representation for sk.
+func (sk SpanKind) String() string {
+	switch sk {
+	case SpanKindUnspecified:
+		return "unspecified"
+	case SpanKindInternal:
+		return "internal"
+	case SpanKindServer:
+		return "server"
+	case SpanKindClient:
+		return "client"
+	case SpanKindProducer:
+		return "producer"
+	case SpanKindConsumer:
+		return "consumer"
+	default:
+		return fmt.Sprintf("unknown(%d)", int32(sk))
+	}
+}
+
+// SpanEvent represents Span.Event OTEL protobuf message
+type SpanEvent struct {
+	TimeUnixNano uint64
+	Name         string
+	Attributes   []*KeyValue
+}
+
+func (e *SpanEvent) marshalProtobuf(mm *easyproto.MessageMarshaler) {
+	mm.AppendFixed64(1, e.TimeUnixNano)
+	mm.AppendString(2, e.Name)
+	for _, a := range e.Attributes {
+		a.marshalProtobuf(mm.AppendMessage(3))
+	}
+}
+
+func (e *SpanEvent) unmarshalProtobuf(src []byte) (err error) {
+	// message Event {
+	//   fixed64 time_unix_nano = 1;
+	//   string name = 2;
+	//   repeated KeyValue attributes = 3;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in Event: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			timeUnixNano, ok := fc.Fixed64()
+			if !ok {
+				return fmt.Errorf("cannot read event timestamp")
+			}
+			e.TimeUnixNano = timeUnixNano
+		case 2:
+			name, ok := fc.String()
+			if !ok {
+				return fmt.Errorf("cannot read event name")
+			}
+			e.Name = strings.Clone(name)
+		case 3:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Attribute data")
+			}
+			e.Attributes = append(e.Attributes, &KeyValue{})
+			a := e.Attributes[len(e.Attributes)-1]
+			if err := a.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Attribute: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// SpanLink represents Span.Link OTEL protobuf message
+type SpanLink struct {
+	TraceID    []byte
+	SpanID     []byte
+	TraceState string
+	Attributes []*KeyValue
+}
+
+func (l *SpanLink) marshalProtobuf(mm *easyproto.MessageMarshaler) {
+	mm.AppendBytes(1, l.TraceID)
+	mm.AppendBytes(2, l.SpanID)
+	mm.AppendString(3, l.TraceState)
+	for _, a := range l.Attributes {
+		a.marshalProtobuf(mm.AppendMessage(4))
+	}
+}
+
+func (l *SpanLink) unmarshalProtobuf(src []byte) (err error) {
+	// message Link {
+	//   bytes trace_id = 1;
+	//   bytes span_id = 2;
+	//   string trace_state = 3;
+	//   repeated KeyValue attributes = 4;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in Link: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			traceID, ok := fc.Bytes()
+			if !ok {
+				return fmt.Errorf("cannot read link trace id")
+			}
+			l.TraceID = append(l.TraceID[:0], traceID...)
+		case 2:
+			spanID, ok := fc.Bytes()
+			if !ok {
+				return fmt.Errorf("cannot read link span id")
+			}
+			l.SpanID = append(l.SpanID[:0], spanID...)
+		case 3:
+			traceState, ok := fc.String()
+			if !ok {
+				return fmt.Errorf("cannot read link trace state")
+			}
+			l.TraceState = strings.Clone(traceState)
+		case 4:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Attribute data")
+			}
+			l.Attributes = append(l.Attributes, &KeyValue{})
+			a := l.Attributes[len(l.Attributes)-1]
+			if err := a.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Attribute: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// SpanStatus represents Status OTEL protobuf message
+type SpanStatus struct {
+	Message string
+	Code    SpanStatusCode
+}
+
+func (st *SpanStatus) marshalProtobuf(mm *easyproto.MessageMarshaler) {
+	mm.AppendString(2, st.Message)
+	mm.AppendInt32(3, int32(st.Code))
+}
+
+func (st *SpanStatus) unmarshalProtobuf(src []byte) (err error) {
+	// message Status {
+	//   string message = 2;
+	//   StatusCode code = 3;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in Status: %w", err)
+		}
+		switch fc.FieldNum {
+		case 2:
+			message, ok := fc.String()
+			if !ok {
+				return fmt.Errorf("cannot read status message")
+			}
+			st.Message = strings.Clone(message)
+		case 3:
+			code, ok := fc.Int32()
+			if !ok {
+				return fmt.Errorf("cannot read status code")
+			}
+			st.Code = SpanStatusCode(code)
+		}
+	}
+	return nil
+}
+
+// SpanStatusCode represents the corresponding OTEL protobuf enum
+type SpanStatusCode int32
+
+const (
+	// SpanStatusCodeUnset is the default status
+	SpanStatusCodeUnset = SpanStatusCode(0)
+
+	// SpanStatusCodeOk means the operation has been validated to have completed successfully
+	SpanStatusCodeOk = SpanStatusCode(1)
+
+	// SpanStatusCodeError means the operation contains an error
+	SpanStatusCodeError = SpanStatusCode(2)
+)
+
+// String returns string representation for sc.
+func (sc SpanStatusCode) String() string {
+	switch sc {
+	case SpanStatusCodeUnset:
+		return "unset"
+	case SpanStatusCodeOk:
+		return "ok"
+	case SpanStatusCodeError:
+		return "error"
+	default:
+		return fmt.Sprintf("unknown(%d)", int32(sc))
+	}
+}
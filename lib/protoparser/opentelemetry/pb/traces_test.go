@@ -0,0 +1,95 @@
+package pb
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExportTraceServiceRequestMarshalUnmarshal(t *testing.T) {
+	f := func(r *ExportTraceServiceRequest) {
+		t.Helper()
+
+		data := r.MarshalProtobuf(nil)
+		var result ExportTraceServiceRequest
+		if err := result.UnmarshalProtobuf(data); err != nil {
+			t.Fatalf("cannot unmarshal ExportTraceServiceRequest: %s", err)
+		}
+		if !reflect.DeepEqual(&result, r) {
+			t.Fatalf("unexpected result after marshal/unmarshal round-trip\ngot\n%#v\nwant\n%#v", &result, r)
+		}
+	}
+
+	stringValue := func(s string) *AnyValue {
+		return &AnyValue{
+			StringValue: &s,
+		}
+	}
+
+	f(&ExportTraceServiceRequest{})
+
+	f(&ExportTraceServiceRequest{
+		ResourceSpans: []*ResourceSpans{
+			{
+				Resource: &Resource{
+					Attributes: []*KeyValue{
+						{
+							Key:   "service.name",
+							Value: stringValue("foo"),
+						},
+					},
+				},
+				ScopeSpans: []*ScopeSpans{
+					{
+						Scope: &InstrumentationScope{
+							Name: "bar",
+						},
+						Spans: []*Span{
+							{
+								TraceID:           []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+								SpanID:            []byte{1, 2, 3, 4, 5, 6, 7, 8},
+								TraceState:        "k=v",
+								ParentSpanID:      []byte{8, 7, 6, 5, 4, 3, 2, 1},
+								Name:              "GET /api",
+								Kind:              SpanKindServer,
+								StartTimeUnixNano: 1000,
+								EndTimeUnixNano:   3000,
+								Attributes: []*KeyValue{
+									{
+										Key:   "http.status_code",
+										Value: stringValue("200"),
+									},
+								},
+								Events: []*SpanEvent{
+									{
+										TimeUnixNano: 2000,
+										Name:         "exception",
+										Attributes: []*KeyValue{
+											{
+												Key:   "exception.message",
+												Value: stringValue("boom"),
+											},
+										},
+									},
+								},
+								Links: []*SpanLink{
+									{
+										TraceID:    []byte{1, 1},
+										SpanID:     []byte{2, 2},
+										TraceState: "a=b",
+									},
+								},
+								Status: &SpanStatus{
+									Message: "failure",
+									Code:    SpanStatusCodeError,
+								},
+							},
+							{
+								Name: "empty",
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
@@ -0,0 +1,200 @@
+package pb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/VictoriaMetrics/easyproto"
+)
+
+// VisitMetrics calls f for every Metric in protobuf-encoded ExportMetricsServiceRequest at src.
+//
+// Contrary to ExportMetricsServiceRequest.UnmarshalProtobuf, it doesn't unmarshal the whole request at once.
+// Only a single Metric with its data points is unmarshaled at a time, so memory usage doesn't depend on the request size.
+// The rm and sm passed to f contain only Resource, Scope and SchemaURL fields, while their ScopeMetrics and Metrics fields are empty.
+// The same rm and sm are passed to f for all the metrics belonging to them.
+//
+// The m passed to f is reused for the subsequent metrics in order to reduce memory allocations,
+// so f mustn't hold references to m and its contents after returning.
+//
+// If skipped isn't nil, then nested messages, which cannot be unmarshaled, are skipped and counted in skipped.
+// Note that f may be already called for some metrics when an error is returned.
+func VisitMetrics(src []byte, skipped *int, f func(rm *ResourceMetrics, sm *ScopeMetrics, m *Metric) error) error {
+	// message ExportMetricsServiceRequest {
+	//   repeated ResourceMetrics resource_metrics = 1;
+	// }
+
+	// Verify the message structure before calling f, so malformed requests are rejected without processing.
+	var fc easyproto.FieldContext
+	var err error
+	for tail := src; len(tail) > 0; {
+		tail, err = fc.NextField(tail)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in ExportMetricsServiceRequest: %w", err)
+		}
+		if fc.FieldNum != 1 {
+			continue
+		}
+		if _, ok := fc.MessageData(); !ok {
+			return fmt.Errorf("cannot read ResourceMetrics data")
+		}
+	}
+
+	m := getMetric()
+	defer putMetric(m)
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in ExportMetricsServiceRequest: %w", err)
+		}
+		if fc.FieldNum != 1 {
+			continue
+		}
+		data, _ := fc.MessageData()
+		if err := visitResourceMetrics(data, m, skipped, f); err != nil {
+			var ce *visitCallbackError
+			if errors.As(err, &ce) {
+				return ce.err
+			}
+			if skipped == nil {
+				return fmt.Errorf("cannot unmarshal ResourceMetrics: %w", err)
+			}
+			*skipped++
+		}
+	}
+	return nil
+}
+
+// visitCallbackError wraps the error returned from the callback passed to VisitMetrics.
+//
+// Such errors must be returned to the caller instead of skipping the corresponding messages.
+type visitCallbackError struct {
+	err error
+}
+
+func (e *visitCallbackError) Error() string {
+	return e.err.Error()
+}
+
+func visitResourceMetrics(src []byte, m *Metric, skipped *int, f func(rm *ResourceMetrics, sm *ScopeMetrics, m *Metric) error) (err error) {
+	// message ResourceMetrics {
+	//   Resource resource = 1;
+	//   repeated ScopeMetrics scope_metrics = 2;
+	//   string schema_url = 3;
+	// }
+
+	// The resource and schema_url may be located after scope_metrics, so read them at the first pass.
+	var rm ResourceMetrics
+	var fc easyproto.FieldContext
+	for tail := src; len(tail) > 0; {
+		tail, err = fc.NextField(tail)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in ResourceMetrics: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Resource data")
+			}
+			rm.Resource = &Resource{}
+			if err := rm.Resource.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot umarshal Resource: %w", err)
+			}
+		case 3:
+			schemaURL, ok := fc.String()
+			if !ok {
+				return fmt.Errorf("cannot read schema_url")
+			}
+			rm.SchemaURL = strings.Clone(schemaURL)
+		}
+	}
+
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in ResourceMetrics: %w", err)
+		}
+		if fc.FieldNum != 2 {
+			continue
+		}
+		data, ok := fc.MessageData()
+		if !ok {
+			return fmt.Errorf("cannot read ScopeMetrics data")
+		}
+		if err := visitScopeMetrics(data, &rm, m, skipped, f); err != nil {
+			var ce *visitCallbackError
+			if errors.As(err, &ce) {
+				return err
+			}
+			if skipped == nil {
+				return fmt.Errorf("cannot unmarshal ScopeMetrics: %w", err)
+			}
+			*skipped++
+		}
+	}
+	return nil
+}
+
+func visitScopeMetrics(src []byte, rm *ResourceMetrics, m *Metric, skipped *int, f func(rm *ResourceMetrics, sm *ScopeMetrics, m *Metric) error) (err error) {
+	// message ScopeMetrics {
+	//   InstrumentationScope scope = 1;
+	//   repeated Metric metrics = 2;
+	//   string schema_url = 3;
+	// }
+
+	// The scope and schema_url may be located after metrics, so read them at the first pass.
+	var sm ScopeMetrics
+	var fc easyproto.FieldContext
+	for tail := src; len(tail) > 0; {
+		tail, err = fc.NextField(tail)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in ScopeMetrics: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read InstrumentationScope data")
+			}
+			sm.Scope = &InstrumentationScope{}
+			if err := sm.Scope.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal InstrumentationScope: %w", err)
+			}
+		case 2:
+			if _, ok := fc.MessageData(); !ok {
+				return fmt.Errorf("cannot read Metric data")
+			}
+		case 3:
+			schemaURL, ok := fc.String()
+			if !ok {
+				return fmt.Errorf("cannot read schema_url")
+			}
+			sm.SchemaURL = strings.Clone(schemaURL)
+		}
+	}
+
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in ScopeMetrics: %w", err)
+		}
+		if fc.FieldNum != 2 {
+			continue
+		}
+		data, _ := fc.MessageData()
+		m.Reset()
+		if err := m.unmarshalProtobuf(data); err != nil {
+			if skipped == nil {
+				return fmt.Errorf("cannot unmarshal Metric: %w", err)
+			}
+			*skipped++
+			continue
+		}
+		if err := f(rm, &sm, m); err != nil {
+			return &visitCallbackError{err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package pb
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/VictoriaMetrics/easyproto"
+)
+
+func TestVisitMetrics(t *testing.T) {
+	f := func(data []byte, resultExpected *ExportMetricsServiceRequest) {
+		t.Helper()
+
+		// Assemble the visited metrics back into ExportMetricsServiceRequest
+		var result ExportMetricsServiceRequest
+		var rmPrev *ResourceMetrics
+		var smPrev *ScopeMetrics
+		err := VisitMetrics(data, nil, func(rm *ResourceMetrics, sm *ScopeMetrics, m *Metric) error {
+			if rm != rmPrev {
+				result.ResourceMetrics = append(result.ResourceMetrics, &ResourceMetrics{
+					Resource:  rm.Resource,
+					SchemaURL: rm.SchemaURL,
+				})
+				rmPrev = rm
+				smPrev = nil
+			}
+			rmDst := result.ResourceMetrics[len(result.ResourceMetrics)-1]
+			if sm != smPrev {
+				rmDst.ScopeMetrics = append(rmDst.ScopeMetrics, &ScopeMetrics{
+					Scope:     sm.Scope,
+					SchemaURL: sm.SchemaURL,
+				})
+				smPrev = sm
+			}
+			smDst := rmDst.ScopeMetrics[len(rmDst.ScopeMetrics)-1]
+
+			// Copy m, since it is reused by VisitMetrics for the subsequent metrics
+			mCopy := &Metric{}
+			if err := mCopy.unmarshalProtobuf(marshalMessage(m.marshalProtobuf)); err != nil {
+				return fmt.Errorf("cannot copy metric: %w", err)
+			}
+			smDst.Metrics = append(smDst.Metrics, mCopy)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(&result, resultExpected) {
+			t.Fatalf("unexpected result\ngot\n%#v\nwant\n%#v", &result, resultExpected)
+		}
+	}
+
+	stringValue := func(s string) *AnyValue {
+		return &AnyValue{
+			StringValue: &s,
+		}
+	}
+	newGauge := func(name string, value float64) *Metric {
+		return &Metric{
+			Name: name,
+			Gauge: &Gauge{
+				DataPoints: []*NumberDataPoint{
+					{
+						DoubleValue:  &value,
+						TimeUnixNano: 1000,
+					},
+				},
+			},
+		}
+	}
+
+	// empty request
+	f(nil, &ExportMetricsServiceRequest{})
+
+	// multiple resources and scopes
+	r := &ExportMetricsServiceRequest{
+		ResourceMetrics: []*ResourceMetrics{
+			{
+				Resource: &Resource{
+					Attributes: []*KeyValue{
+						{
+							Key:   "service.name",
+							Value: stringValue("foo"),
+						},
+					},
+				},
+				ScopeMetrics: []*ScopeMetrics{
+					{
+						Scope: &InstrumentationScope{
+							Name: "scope1",
+						},
+						Metrics: []*Metric{
+							newGauge("m1", 1),
+							newGauge("m2", 2),
+						},
+						SchemaURL: "https://opentelemetry.io/schemas/1.24.0",
+					},
+					{
+						Metrics: []*Metric{
+							newGauge("m3", 3),
+						},
+					},
+				},
+				SchemaURL: "https://opentelemetry.io/schemas/1.23.0",
+			},
+			{
+				ScopeMetrics: []*ScopeMetrics{
+					{
+						Metrics: []*Metric{
+							newGauge("m4", 4),
+						},
+					},
+				},
+			},
+		},
+	}
+	f(r.MarshalProtobuf(nil), r)
+
+	// resource and scope are located after the metrics
+	sm := &ScopeMetrics{
+		Metrics: []*Metric{
+			newGauge("m1", 1),
+		},
+	}
+	scope := &ScopeMetrics{
+		Scope: &InstrumentationScope{
+			Name: "scope1",
+		},
+	}
+	smData := append(marshalMessage(sm.marshalProtobuf), marshalMessage(scope.marshalProtobuf)...)
+	resource := &Resource{
+		Attributes: []*KeyValue{
+			{
+				Key:   "service.name",
+				Value: stringValue("foo"),
+			},
+		},
+	}
+	rmData := marshalMessage(func(mm *easyproto.MessageMarshaler) {
+		mm.AppendBytes(2, smData)
+		resource.marshalProtobuf(mm.AppendMessage(1))
+	})
+	data := marshalMessage(func(mm *easyproto.MessageMarshaler) {
+		mm.AppendBytes(1, rmData)
+	})
+	f(data, &ExportMetricsServiceRequest{
+		ResourceMetrics: []*ResourceMetrics{
+			{
+				Resource: resource,
+				ScopeMetrics: []*ScopeMetrics{
+					{
+						Scope:   scope.Scope,
+						Metrics: sm.Metrics,
+					},
+				},
+			},
+		},
+	})
+}
+
+func TestVisitMetricsFailure(t *testing.T) {
+	value := 1.0
+	r := &ExportMetricsServiceRequest{
+		ResourceMetrics: []*ResourceMetrics{
+			{
+				ScopeMetrics: []*ScopeMetrics{
+					{
+						Metrics: []*Metric{
+							{
+								Name: "m1",
+								Gauge: &Gauge{
+									DataPoints: []*NumberDataPoint{
+										{
+											DoubleValue: &value,
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	data := r.MarshalProtobuf(nil)
+
+	// malformed request
+	calls := 0
+	err := VisitMetrics(data[:len(data)-1], nil, func(_ *ResourceMetrics, _ *ScopeMetrics, _ *Metric) error {
+		calls++
+		return nil
+	})
+	if err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+	if calls > 0 {
+		t.Fatalf("unexpected calls for malformed request: %d", calls)
+	}
+
+	// the error returned from the callback must be returned as is in lenient mode
+	errExpected := fmt.Errorf("callback error")
+	skipped := 0
+	err = VisitMetrics(data, &skipped, func(_ *ResourceMetrics, _ *ScopeMetrics, _ *Metric) error {
+		return errExpected
+	})
+	if err != errExpected {
+		t.Fatalf("unexpected error; got %v; want %v", err, errExpected)
+	}
+	if skipped != 0 {
+		t.Fatalf("unexpected number of skipped messages; got %d; want 0", skipped)
+	}
+}
+
+func marshalMessage(f func(mm *easyproto.MessageMarshaler)) []byte {
+	m := mp.Get()
+	f(m.MessageMarshaler())
+	data := m.Marshal(nil)
+	mp.Put(m)
+	return data
+}
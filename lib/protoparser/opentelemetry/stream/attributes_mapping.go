@@ -0,0 +1,248 @@
+package stream
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/VictoriaMetrics/metrics"
+	"gopkg.in/yaml.v2"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/envtemplate"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fs/fscore"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+)
+
+var attributesMappingConfigPath = flag.String("opentelemetry.attributesMappingConfig", "", "Optional path to a file with mapping rules for renaming "+
+	"OpenTelemetry resource, scope and data point attributes before converting them into labels. This allows normalizing attributes "+
+	"renamed between versions of OpenTelemetry semantic conventions, such as http.method -> http.request.method. "+
+	"The path can point either to local file or to http url. See https://docs.victoriametrics.com/#sending-data-via-opentelemetry")
+
+// maxAttributesMappingCacheSize is the maximum number of cached attribute renames per schema url.
+const maxAttributesMappingCacheSize = 1000
+
+var attributesMappingGlobal atomic.Pointer[attributesMapping]
+
+// InitAttributesMapping loads attributes mapping rules from -opentelemetry.attributesMappingConfig if it is set.
+func InitAttributesMapping() {
+	if *attributesMappingConfigPath == "" {
+		return
+	}
+	data, err := fscore.ReadFileOrHTTP(*attributesMappingConfigPath)
+	if err != nil {
+		logger.Fatalf("cannot read -opentelemetry.attributesMappingConfig=%q: %s", *attributesMappingConfigPath, err)
+	}
+	data, err = envtemplate.ReplaceBytes(data)
+	if err != nil {
+		logger.Fatalf("cannot expand environment vars at -opentelemetry.attributesMappingConfig=%q: %s", *attributesMappingConfigPath, err)
+	}
+	am, err := parseAttributesMapping(data)
+	if err != nil {
+		logger.Fatalf("cannot parse -opentelemetry.attributesMappingConfig=%q: %s", *attributesMappingConfigPath, err)
+	}
+	attributesMappingGlobal.Store(am)
+}
+
+// attributesMapping contains rules for renaming OpenTelemetry attributes.
+type attributesMapping struct {
+	rules []attributesMappingRule
+
+	cacheLock sync.Mutex
+	cache     map[string]map[string]string
+}
+
+type attributesMappingConfig struct {
+	Mappings []attributesMappingRuleYAML `yaml:"mappings"`
+}
+
+type attributesMappingRuleYAML struct {
+	From string `yaml:"from"`
+	To   string `yaml:"to"`
+
+	// BeforeSchemaVersion limits the rule to data with schema_url pointing to semantic conventions older than the given version.
+	BeforeSchemaVersion string `yaml:"before_schema_version,omitempty"`
+}
+
+type attributesMappingRule struct {
+	from string
+	to   string
+
+	// beforeSchemaVersion is nil if the rule must be applied regardless of schema_url.
+	beforeSchemaVersion []int
+}
+
+func parseAttributesMapping(data []byte) (*attributesMapping, error) {
+	var cfg attributesMappingConfig
+	if err := yaml.UnmarshalStrict(data, &cfg); err != nil {
+		return nil, err
+	}
+	rules := make([]attributesMappingRule, 0, len(cfg.Mappings))
+	from := make(map[string]bool, len(cfg.Mappings))
+	for i, mr := range cfg.Mappings {
+		if mr.From == "" || mr.To == "" {
+			return nil, fmt.Errorf("mapping #%d: `from` and `to` must be non-empty", i+1)
+		}
+		if mr.From == mr.To {
+			return nil, fmt.Errorf("mapping #%d: `from` and `to` must differ; got %q", i+1, mr.From)
+		}
+		if from[mr.From] {
+			return nil, fmt.Errorf("mapping #%d: duplicate `from: %q`", i+1, mr.From)
+		}
+		from[mr.From] = true
+		rule := attributesMappingRule{
+			from: mr.From,
+			to:   mr.To,
+		}
+		if mr.BeforeSchemaVersion != "" {
+			v, ok := parseSchemaVersion(mr.BeforeSchemaVersion)
+			if !ok {
+				return nil, fmt.Errorf("mapping #%d: cannot parse `before_schema_version: %q`; it must have the form 1.2.3", i+1, mr.BeforeSchemaVersion)
+			}
+			rule.beforeSchemaVersion = v
+		}
+		rules = append(rules, rule)
+	}
+	return &attributesMapping{
+		rules: rules,
+		cache: make(map[string]map[string]string),
+	}, nil
+}
+
+// getRenames returns attribute renames for the data with the given schemaURL.
+//
+// nil is returned if attributes mustn't be renamed.
+func (am *attributesMapping) getRenames(schemaURL string) map[string]string {
+	am.cacheLock.Lock()
+	renames, ok := am.cache[schemaURL]
+	am.cacheLock.Unlock()
+	if ok {
+		return renames
+	}
+
+	// Apply all the rules if schemaURL is missing or if it doesn't contain a version.
+	version, hasVersion := parseSchemaVersion(schemaURL[strings.LastIndexByte(schemaURL, '/')+1:])
+	for _, rule := range am.rules {
+		if hasVersion && rule.beforeSchemaVersion != nil && compareSchemaVersions(version, rule.beforeSchemaVersion) >= 0 {
+			continue
+		}
+		if renames == nil {
+			renames = make(map[string]string)
+		}
+		renames[rule.from] = rule.to
+	}
+
+	am.cacheLock.Lock()
+	if len(am.cache) >= maxAttributesMappingCacheSize {
+		clear(am.cache)
+	}
+	am.cache[strings.Clone(schemaURL)] = renames
+	am.cacheLock.Unlock()
+	return renames
+}
+
+// parseSchemaVersion parses semantic conventions version such as 1.21.0 from s.
+func parseSchemaVersion(s string) ([]int, bool) {
+	if s == "" {
+		return nil, false
+	}
+	parts := strings.Split(s, ".")
+	version := make([]int, len(parts))
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		version[i] = n
+	}
+	return version, true
+}
+
+// compareSchemaVersions returns -1, 0 or 1 if a is smaller, equal or bigger than b.
+//
+// Missing version parts are treated as zeros, e.g. 1.21 equals to 1.21.0.
+func compareSchemaVersions(a, b []int) int {
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y int
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+		if x < y {
+			return -1
+		}
+		if x > y {
+			return 1
+		}
+	}
+	return 0
+}
+
+// renameAttributes renames attributes according to renames and returns the result.
+//
+// Attributes are renamed in place. If the attribute with the new name already exists, then the attribute with the old name is dropped,
+// so the result doesn't contain duplicate attributes.
+func renameAttributes(attributes []*pb.KeyValue, renames map[string]string) []*pb.KeyValue {
+	if len(renames) == 0 {
+		return attributes
+	}
+	dst := attributes[:0]
+	for i, at := range attributes {
+		to, ok := renames[at.Key]
+		if ok {
+			if hasAttribute(attributes[:i], to) || hasAttribute(attributes[i+1:], to) {
+				attributesDropped.Inc()
+				continue
+			}
+			at.Key = to
+		}
+		dst = append(dst, at)
+	}
+	clear(attributes[len(dst):])
+	return dst
+}
+
+func hasAttribute(attributes []*pb.KeyValue, key string) bool {
+	for _, at := range attributes {
+		if at != nil && at.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// renameMetricAttributes renames data point attributes for m according to renames.
+func renameMetricAttributes(m *pb.Metric, renames map[string]string) {
+	if len(renames) == 0 {
+		return
+	}
+	switch {
+	case m.Gauge != nil:
+		for _, p := range m.Gauge.DataPoints {
+			p.Attributes = renameAttributes(p.Attributes, renames)
+		}
+	case m.Sum != nil:
+		for _, p := range m.Sum.DataPoints {
+			p.Attributes = renameAttributes(p.Attributes, renames)
+		}
+	case m.Summary != nil:
+		for _, p := range m.Summary.DataPoints {
+			p.Attributes = renameAttributes(p.Attributes, renames)
+		}
+	case m.Histogram != nil:
+		for _, p := range m.Histogram.DataPoints {
+			p.Attributes = renameAttributes(p.Attributes, renames)
+		}
+	case m.ExponentialHistogram != nil:
+		for _, p := range m.ExponentialHistogram.DataPoints {
+			p.Attributes = renameAttributes(p.Attributes, renames)
+		}
+	}
+}
+
+var attributesDropped = metrics.NewCounter(`vm_protoparser_attributes_dropped_total{type="opentelemetry",reason="duplicate_after_mapping"}`)
@@ -0,0 +1,249 @@
+package stream
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+)
+
+func TestParseAttributesMappingFailure(t *testing.T) {
+	f := func(data string) {
+		t.Helper()
+		am, err := parseAttributesMapping([]byte(data))
+		if err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+		if am != nil {
+			t.Fatalf("expecting nil am")
+		}
+	}
+
+	// invalid yaml
+	f(`foobar`)
+
+	// unknown field
+	f(`
+mappings:
+- from: http.method
+  to: http.request.method
+  foo: bar
+`)
+
+	// missing from
+	f(`
+mappings:
+- to: http.request.method
+`)
+
+	// missing to
+	f(`
+mappings:
+- from: http.method
+`)
+
+	// the same from and to
+	f(`
+mappings:
+- from: http.method
+  to: http.method
+`)
+
+	// duplicate from
+	f(`
+mappings:
+- from: http.method
+  to: http.request.method
+- from: http.method
+  to: method
+`)
+
+	// invalid before_schema_version
+	f(`
+mappings:
+- from: http.method
+  to: http.request.method
+  before_schema_version: v1.21
+`)
+}
+
+func TestAttributesMappingGetRenames(t *testing.T) {
+	am, err := parseAttributesMapping([]byte(`
+mappings:
+- from: http.method
+  to: http.request.method
+  before_schema_version: 1.21.0
+- from: net.host.name
+  to: server.address
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	f := func(schemaURL string, renamesExpected map[string]string) {
+		t.Helper()
+		for i := 0; i < 2; i++ {
+			// The second iteration verifies the cached result.
+			renames := am.getRenames(schemaURL)
+			if !reflect.DeepEqual(renames, renamesExpected) {
+				t.Fatalf("unexpected renames for schemaURL=%q\ngot\n%v\nwant\n%v", schemaURL, renames, renamesExpected)
+			}
+		}
+	}
+
+	allRenames := map[string]string{
+		"http.method":   "http.request.method",
+		"net.host.name": "server.address",
+	}
+
+	// missing schema url
+	f("", allRenames)
+
+	// schema url without version
+	f("https://example.com/schemas/latest", allRenames)
+
+	// older schema version
+	f("https://opentelemetry.io/schemas/1.20.0", allRenames)
+	f("https://opentelemetry.io/schemas/1.9", allRenames)
+
+	// the same or newer schema version
+	f("https://opentelemetry.io/schemas/1.21.0", map[string]string{
+		"net.host.name": "server.address",
+	})
+	f("https://opentelemetry.io/schemas/1.24.0", map[string]string{
+		"net.host.name": "server.address",
+	})
+}
+
+func TestCompareSchemaVersions(t *testing.T) {
+	f := func(a, b string, resultExpected int) {
+		t.Helper()
+		va, ok := parseSchemaVersion(a)
+		if !ok {
+			t.Fatalf("cannot parse %q", a)
+		}
+		vb, ok := parseSchemaVersion(b)
+		if !ok {
+			t.Fatalf("cannot parse %q", b)
+		}
+		result := compareSchemaVersions(va, vb)
+		if result != resultExpected {
+			t.Fatalf("unexpected result for compareSchemaVersions(%q, %q); got %d; want %d", a, b, result, resultExpected)
+		}
+	}
+	f("1.21.0", "1.21.0", 0)
+	f("1.21", "1.21.0", 0)
+	f("1.20.0", "1.21.0", -1)
+	f("1.9.0", "1.21.0", -1)
+	f("1.21.1", "1.21.0", 1)
+	f("2", "1.21.0", 1)
+}
+
+func TestRenameAttributes(t *testing.T) {
+	f := func(attributes []*pb.KeyValue, renames map[string]string, keysExpected []string) {
+		t.Helper()
+		result := renameAttributes(attributes, renames)
+		var keys []string
+		for _, at := range result {
+			keys = append(keys, at.Key)
+		}
+		if !reflect.DeepEqual(keys, keysExpected) {
+			t.Fatalf("unexpected keys\ngot\n%q\nwant\n%q", keys, keysExpected)
+		}
+	}
+
+	newAttributes := func(keys ...string) []*pb.KeyValue {
+		var attributes []*pb.KeyValue
+		for _, key := range keys {
+			attributes = append(attributes, attributesFromKV(key, "value")...)
+		}
+		return attributes
+	}
+	renames := map[string]string{
+		"http.method":      "http.request.method",
+		"http.status_code": "http.response.status_code",
+	}
+
+	// no renames
+	f(newAttributes("http.method", "foo"), nil, []string{"http.method", "foo"})
+
+	// no matching attributes
+	f(newAttributes("foo", "bar"), renames, []string{"foo", "bar"})
+
+	// rename attributes
+	f(newAttributes("foo", "http.method", "http.status_code"), renames, []string{"foo", "http.request.method", "http.response.status_code"})
+
+	// the attribute with the old name is dropped if the attribute with the new name exists
+	f(newAttributes("http.method", "foo", "http.request.method"), renames, []string{"foo", "http.request.method"})
+	f(newAttributes("http.request.method", "http.method", "foo"), renames, []string{"http.request.method", "foo"})
+}
+
+func TestParseStreamAttributesMapping(t *testing.T) {
+	am, err := parseAttributesMapping([]byte(`
+mappings:
+- from: http.method
+  to: http.request.method
+  before_schema_version: 1.21.0
+- from: service.name
+  to: service
+`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	attributesMappingGlobal.Store(am)
+	defer attributesMappingGlobal.Store(nil)
+
+	newGauge := func() *pb.Metric {
+		m := generateGauge("my-gauge", "")
+		m.Gauge.DataPoints[0].Attributes = attributesFromKV("http.method", "GET")
+		return m
+	}
+	req := &pb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*pb.ResourceMetrics{
+			{
+				Resource: &pb.Resource{
+					Attributes: attributesFromKV("service.name", "vm"),
+				},
+				ScopeMetrics: []*pb.ScopeMetrics{
+					{
+						Metrics: []*pb.Metric{newGauge()},
+					},
+					{
+						// The schema_url from ScopeMetrics has priority over the schema_url from ResourceMetrics
+						Metrics:   []*pb.Metric{newGauge()},
+						SchemaURL: "https://opentelemetry.io/schemas/1.20.0",
+					},
+				},
+				SchemaURL: "https://opentelemetry.io/schemas/1.24.0",
+			},
+		},
+	}
+	var labelsResult [][]prompbmarshal.Label
+	err = ParseStream(bytes.NewBuffer(req.MarshalProtobuf(nil)), "", nil, func(tss []prompbmarshal.TimeSeries) error {
+		for _, ts := range tss {
+			labelsResult = append(labelsResult, append([]prompbmarshal.Label{}, ts.Labels...))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	labelsExpected := [][]prompbmarshal.Label{
+		{
+			{Name: "__name__", Value: "my-gauge"},
+			{Name: "service", Value: "vm"},
+			{Name: "http.method", Value: "GET"},
+		},
+		{
+			{Name: "__name__", Value: "my-gauge"},
+			{Name: "service", Value: "vm"},
+			{Name: "http.request.method", Value: "GET"},
+		},
+	}
+	if !reflect.DeepEqual(labelsResult, labelsExpected) {
+		t.Fatalf("unexpected labels\ngot\n%v\nwant\n%v", labelsResult, labelsExpected)
+	}
+}
@@ -0,0 +1,202 @@
+package stream
+
+import (
+	"flag"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fasttime"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+)
+
+var (
+	convertDeltaToCumulative = flag.Bool("opentelemetry.convertDeltaToCumulative", false, "Whether to convert OpenTelemetry sums and histograms "+
+		"with delta aggregation temporality to cumulative values. Such sums and histograms are dropped if this flag isn't set. "+
+		"See https://docs.victoriametrics.com/#sending-data-via-opentelemetry")
+	deltaToCumulativeStaleInterval = flag.Duration("opentelemetry.deltaToCumulativeStaleInterval", time.Hour, "The interval after which the state "+
+		"for converting delta OpenTelemetry sums and histograms to cumulative values is dropped for series without new samples. "+
+		"See -opentelemetry.convertDeltaToCumulative")
+)
+
+// deltaStates holds the accumulated values for OpenTelemetry series with delta aggregation temporality.
+var deltaStates = newDeltaStateStorage()
+
+type deltaStateStorage struct {
+	mu sync.Mutex
+
+	// m contains the accumulated state per each series key.
+	m map[string]*deltaState
+
+	// lastCleanupTime is unix timestamp in seconds for the last cleanup of stale entries in m.
+	lastCleanupTime uint64
+}
+
+// deltaState contains the accumulated values for a single series with delta aggregation temporality.
+type deltaState struct {
+	// lastTimestamp is the timestamp in nanoseconds of the last accumulated data point.
+	lastTimestamp uint64
+
+	// lastAccessTime is unix timestamp in seconds for the last access to the state.
+	lastAccessTime uint64
+
+	// value is the accumulated value for sums.
+	value float64
+
+	// count, sum and bucketCounts are the accumulated values for histograms.
+	count        uint64
+	sum          float64
+	bucketCounts []uint64
+
+	// explicitBounds are the bounds for bucketCounts.
+	explicitBounds []float64
+}
+
+func newDeltaStateStorage() *deltaStateStorage {
+	dss := &deltaStateStorage{
+		m:               make(map[string]*deltaState),
+		lastCleanupTime: fasttime.UnixTimestamp(),
+	}
+	_ = metrics.NewGauge(`vm_protoparser_delta_to_cumulative_series{type="opentelemetry"}`, func() float64 {
+		dss.mu.Lock()
+		n := len(dss.m)
+		dss.mu.Unlock()
+		return float64(n)
+	})
+	return dss
+}
+
+// getLocked returns the state for the given key.
+//
+// It returns nil if the data point with the given timestamp must be dropped, since it is out of order or duplicate.
+// dss.mu must be locked by the caller.
+func (dss *deltaStateStorage) getLocked(key []byte, timestamp uint64) *deltaState {
+	currentTime := fasttime.UnixTimestamp()
+	staleSecs := uint64(deltaToCumulativeStaleInterval.Seconds())
+	if currentTime-dss.lastCleanupTime > staleSecs/2 {
+		for k, ds := range dss.m {
+			if currentTime-ds.lastAccessTime > staleSecs {
+				delete(dss.m, k)
+			}
+		}
+		dss.lastCleanupTime = currentTime
+	}
+
+	ds := dss.m[string(key)]
+	if ds == nil {
+		ds = &deltaState{}
+		dss.m[string(key)] = ds
+	} else if timestamp > 0 && timestamp <= ds.lastTimestamp {
+		return nil
+	}
+	ds.lastTimestamp = timestamp
+	ds.lastAccessTime = currentTime
+	return ds
+}
+
+// deleteLocked deletes the state for the given key.
+//
+// dss.mu must be locked by the caller.
+func (dss *deltaStateStorage) deleteLocked(key []byte) {
+	delete(dss.m, string(key))
+}
+
+// convertDeltaNumberDataPoint converts p with delta value to p with cumulative value.
+//
+// false is returned if p must be dropped.
+func (wr *writeContext) convertDeltaNumberDataPoint(metricName string, p *pb.NumberDataPoint) bool {
+	wr.deltaKey = wr.marshalDeltaKey(wr.deltaKey[:0], metricName, p.Attributes)
+	isStale := (p.Flags)&uint32(1) != 0
+
+	deltaStates.mu.Lock()
+	defer deltaStates.mu.Unlock()
+
+	if isStale {
+		// The series is finished, so drop its state.
+		deltaStates.deleteLocked(wr.deltaKey)
+		return true
+	}
+	ds := deltaStates.getLocked(wr.deltaKey, p.TimeUnixNano)
+	if ds == nil {
+		rowsDroppedOutOfOrderDelta.Inc()
+		return false
+	}
+	switch {
+	case p.IntValue != nil:
+		ds.value += float64(*p.IntValue)
+	case p.DoubleValue != nil:
+		ds.value += *p.DoubleValue
+	}
+	v := ds.value
+	p.IntValue = nil
+	p.DoubleValue = &v
+	return true
+}
+
+// convertDeltaHistogramDataPoint converts p with delta values to p with cumulative values.
+//
+// false is returned if p must be dropped.
+func (wr *writeContext) convertDeltaHistogramDataPoint(metricName string, p *pb.HistogramDataPoint) bool {
+	wr.deltaKey = wr.marshalDeltaKey(wr.deltaKey[:0], metricName, p.Attributes)
+	isStale := (p.Flags)&uint32(1) != 0
+
+	deltaStates.mu.Lock()
+	defer deltaStates.mu.Unlock()
+
+	if isStale {
+		// The series is finished, so drop its state.
+		deltaStates.deleteLocked(wr.deltaKey)
+		return true
+	}
+	ds := deltaStates.getLocked(wr.deltaKey, p.TimeUnixNano)
+	if ds == nil {
+		rowsDroppedOutOfOrderDelta.Inc()
+		return false
+	}
+	if !slices.Equal(ds.explicitBounds, p.ExplicitBounds) || len(ds.bucketCounts) != len(p.BucketCounts) {
+		// Bucket layout has been changed, so start accumulating from scratch.
+		ds.explicitBounds = append(ds.explicitBounds[:0], p.ExplicitBounds...)
+		ds.bucketCounts = append(ds.bucketCounts[:0], make([]uint64, len(p.BucketCounts))...)
+		ds.count = 0
+		ds.sum = 0
+	}
+	ds.count += p.Count
+	p.Count = ds.count
+	if p.Sum != nil {
+		ds.sum += *p.Sum
+		sum := ds.sum
+		p.Sum = &sum
+	}
+	for i, n := range p.BucketCounts {
+		ds.bucketCounts[i] += n
+		p.BucketCounts[i] = ds.bucketCounts[i]
+	}
+	return true
+}
+
+// marshalDeltaKey appends the key for the series with the given metricName and attributes to dst and returns the result.
+//
+// The key includes wr.baseLabels, since they identify the resource the series belongs to.
+func (wr *writeContext) marshalDeltaKey(dst []byte, metricName string, attributes []*pb.KeyValue) []byte {
+	dst = append(dst, metricName...)
+	dst = append(dst, 0)
+	dst = marshalDeltaKeyLabels(dst, wr.baseLabels)
+	wr.deltaLabels = appendAttributesToPromLabels(wr.deltaLabels[:0], attributes)
+	dst = marshalDeltaKeyLabels(dst, wr.deltaLabels)
+	return dst
+}
+
+func marshalDeltaKeyLabels(dst []byte, labels []prompbmarshal.Label) []byte {
+	for _, label := range labels {
+		dst = append(dst, label.Name...)
+		dst = append(dst, 0)
+		dst = append(dst, label.Value...)
+		dst = append(dst, 0)
+	}
+	return dst
+}
+
+var rowsDroppedOutOfOrderDelta = metrics.NewCounter(`vm_protoparser_rows_dropped_total{type="opentelemetry",reason="out_of_order_delta"}`)
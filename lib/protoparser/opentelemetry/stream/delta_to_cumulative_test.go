@@ -0,0 +1,85 @@
+package stream
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+)
+
+func TestParseStreamDeltaToCumulative(t *testing.T) {
+	*convertDeltaToCumulative = true
+	defer func() {
+		*convertDeltaToCumulative = false
+	}()
+
+	f := func(data string, resultExpected []string) {
+		t.Helper()
+
+		var result []string
+		err := ParseStream(bytes.NewBufferString(data), "", ProcessJSONRequestBody, func(tss []prompbmarshal.TimeSeries) error {
+			for _, ts := range tss {
+				s := ""
+				for _, label := range ts.Labels {
+					s += fmt.Sprintf("%s=%q,", label.Name, label.Value)
+				}
+				for _, sample := range ts.Samples {
+					s += fmt.Sprintf(" %g %d", sample.Value, sample.Timestamp)
+				}
+				result = append(result, s)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("cannot parse request: %s", err)
+		}
+		if fmt.Sprintf("%q", result) != fmt.Sprintf("%q", resultExpected) {
+			t.Fatalf("unexpected result\ngot\n%q\nwant\n%q", result, resultExpected)
+		}
+	}
+
+	deltaSum := func(job string, value, timestamp int) string {
+		return fmt.Sprintf(`{"resourceMetrics":[{"resource":{"attributes":[{"key":"job","value":{"stringValue":%q}}]},"scopeMetrics":[{"metrics":[
+{"name":"requests","sum":{"aggregationTemporality":1,"isMonotonic":true,"dataPoints":[{"asInt":"%d","timeUnixNano":"%d000000000"}]}}
+]}]}]}`, job, value, timestamp)
+	}
+
+	// delta values are accumulated per series
+	f(deltaSum("foo", 3, 10), []string{`__name__="requests",job="foo", 3 10000`})
+	f(deltaSum("foo", 2, 20), []string{`__name__="requests",job="foo", 5 20000`})
+	f(deltaSum("bar", 7, 20), []string{`__name__="requests",job="bar", 7 20000`})
+
+	// duplicate and out-of-order data points are dropped
+	f(deltaSum("foo", 2, 20), nil)
+	f(deltaSum("foo", 2, 15), nil)
+	f(deltaSum("foo", 4, 30), []string{`__name__="requests",job="foo", 9 30000`})
+
+	deltaHistogram := func(bucketCounts string, bounds string, timestamp int) string {
+		return fmt.Sprintf(`{"resourceMetrics":[{"scopeMetrics":[{"metrics":[
+{"name":"latency","histogram":{"aggregationTemporality":1,"dataPoints":[{"timeUnixNano":"%d000000000","count":"3","sum":1.5,"bucketCounts":%s,"explicitBounds":%s}]}}
+]}]}]}`, timestamp, bucketCounts, bounds)
+	}
+
+	// histogram buckets are accumulated
+	f(deltaHistogram(`["1","2"]`, `[1]`, 10), []string{
+		`__name__="latency_count", 3 10000`,
+		`__name__="latency_sum", 1.5 10000`,
+		`__name__="latency_bucket",le="1", 1 10000`,
+		`__name__="latency_bucket",le="+Inf", 3 10000`,
+	})
+	f(deltaHistogram(`["2","1"]`, `[1]`, 20), []string{
+		`__name__="latency_count", 6 20000`,
+		`__name__="latency_sum", 3 20000`,
+		`__name__="latency_bucket",le="1", 3 20000`,
+		`__name__="latency_bucket",le="+Inf", 6 20000`,
+	})
+
+	// histogram state is reset on bucket layout change
+	f(deltaHistogram(`["2","1"]`, `[2]`, 30), []string{
+		`__name__="latency_count", 3 30000`,
+		`__name__="latency_sum", 1.5 30000`,
+		`__name__="latency_bucket",le="2", 2 30000`,
+		`__name__="latency_bucket",le="+Inf", 3 30000`,
+	})
+}
@@ -0,0 +1,67 @@
+package stream
+
+import (
+	"encoding/hex"
+	"sort"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/exemplars"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+)
+
+// addExemplars adds es to exemplars storage for the time series in ts.
+func (wr *writeContext) addExemplars(ts *prompbmarshal.TimeSeries, es []*pb.Exemplar) {
+	for _, e := range es {
+		wr.addExemplar(ts, e)
+	}
+}
+
+// addHistogramExemplars adds es to exemplars storage for the matching `le` buckets in bucketTss.
+//
+// bucketTss must contain time series for buckets in the order of bounds followed by `le="+Inf"` bucket.
+func (wr *writeContext) addHistogramExemplars(bucketTss []prompbmarshal.TimeSeries, bounds []float64, es []*pb.Exemplar) {
+	for _, e := range es {
+		// The bucket with the smallest `le` bound, which is bigger or equal to the exemplar value, contains the exemplar.
+		n := sort.SearchFloat64s(bounds, getExemplarValue(e))
+		wr.addExemplar(&bucketTss[n], e)
+	}
+}
+
+func (wr *writeContext) addExemplar(ts *prompbmarshal.TimeSeries, e *pb.Exemplar) {
+	labels := wr.exemplarLabels[:0]
+	if len(e.TraceID) > 0 {
+		labels = append(labels, prompbmarshal.Label{
+			Name:  "trace_id",
+			Value: hex.EncodeToString(e.TraceID),
+		})
+	}
+	if len(e.SpanID) > 0 {
+		labels = append(labels, prompbmarshal.Label{
+			Name:  "span_id",
+			Value: hex.EncodeToString(e.SpanID),
+		})
+	}
+	labels = appendAttributesToPromLabels(labels, e.FilteredAttributes)
+	wr.exemplarLabels = labels
+
+	t := int64(e.TimeUnixNano / 1e6)
+	if t <= 0 {
+		t = ts.Samples[0].Timestamp
+	}
+	exemplars.Add(ts.Labels, &exemplars.Exemplar{
+		Labels:    labels,
+		Value:     getExemplarValue(e),
+		Timestamp: t,
+	})
+}
+
+func getExemplarValue(e *pb.Exemplar) float64 {
+	switch {
+	case e.IntValue != nil:
+		return float64(*e.IntValue)
+	case e.DoubleValue != nil:
+		return *e.DoubleValue
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,117 @@
+package stream
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/exemplars"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+)
+
+func TestParseStreamExemplars(t *testing.T) {
+	exemplars.Init()
+
+	data := []byte(`{"resourceMetrics":[{
+  "resource":{"attributes":[{"key":"job","value":{"stringValue":"exemplars-test"}}]},
+  "scopeMetrics":[{"metrics":[
+    {"name":"my-sum","sum":{"aggregationTemporality":2,"isMonotonic":true,"dataPoints":[{
+      "asInt":"15","timeUnixNano":"15000000000",
+      "exemplars":[{"asInt":"1","traceId":"5b8efff798038103d269b633813fc60c","spanId":"eee19b7ec3c1b174"}]
+    }]}},
+    {"name":"my-histogram","histogram":{"aggregationTemporality":2,"dataPoints":[{
+      "timeUnixNano":"30000000000","count":"3","sum":5.5,"bucketCounts":["1","1","1"],"explicitBounds":[1,2],
+      "exemplars":[
+        {"asDouble":1.5,"timeUnixNano":"29000000000","traceId":"0102030405060708090a0b0c0d0e0f10","filteredAttributes":[{"key":"user","value":{"stringValue":"foo"}}]},
+        {"asDouble":3,"timeUnixNano":"29500000000","traceId":"1102030405060708090a0b0c0d0e0f10"}
+      ]
+    }]}}
+  ]}]
+}]}`)
+	err := ParseStream(bytes.NewBuffer(data), "", ProcessJSONRequestBody, func(_ []prompbmarshal.TimeSeries) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("cannot parse OTLP/JSON: %s", err)
+	}
+
+	seriesLabels := func(name string, extraLabels ...prompbmarshal.Label) []prompbmarshal.Label {
+		labels := []prompbmarshal.Label{
+			{
+				Name:  "__name__",
+				Value: name,
+			},
+			{
+				Name:  "job",
+				Value: "exemplars-test",
+			},
+		}
+		return append(labels, extraLabels...)
+	}
+	result := exemplars.Search(0, 60000, func(labels []prompbmarshal.Label) bool {
+		for _, label := range labels {
+			if label.Name == "job" && label.Value == "exemplars-test" {
+				return true
+			}
+		}
+		return false
+	})
+	resultExpected := []exemplars.SeriesExemplars{
+		{
+			SeriesLabels: seriesLabels("my-histogram_bucket", prompbmarshal.Label{Name: "le", Value: "+Inf"}),
+			Exemplars: []exemplars.Exemplar{
+				{
+					Labels: []prompbmarshal.Label{
+						{
+							Name:  "trace_id",
+							Value: "1102030405060708090a0b0c0d0e0f10",
+						},
+					},
+					Value:     3,
+					Timestamp: 29500,
+				},
+			},
+		},
+		{
+			SeriesLabels: seriesLabels("my-histogram_bucket", prompbmarshal.Label{Name: "le", Value: "2"}),
+			Exemplars: []exemplars.Exemplar{
+				{
+					Labels: []prompbmarshal.Label{
+						{
+							Name:  "trace_id",
+							Value: "0102030405060708090a0b0c0d0e0f10",
+						},
+						{
+							Name:  "user",
+							Value: "foo",
+						},
+					},
+					Value:     1.5,
+					Timestamp: 29000,
+				},
+			},
+		},
+		{
+			SeriesLabels: seriesLabels("my-sum"),
+			Exemplars: []exemplars.Exemplar{
+				{
+					Labels: []prompbmarshal.Label{
+						{
+							Name:  "span_id",
+							Value: "eee19b7ec3c1b174",
+						},
+						{
+							Name:  "trace_id",
+							Value: "5b8efff798038103d269b633813fc60c",
+						},
+					},
+					Value:     1,
+					Timestamp: 15000,
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(result, resultExpected) {
+		t.Fatalf("unexpected exemplars\ngot\n%v\nwant\n%v", result, resultExpected)
+	}
+}
@@ -0,0 +1,103 @@
+package stream
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+)
+
+// RejectedSamplesError may be returned from the callback passed to ParseStreamExt and ParseRequest
+// if some of the samples passed to the callback were rejected, e.g. because of timestamps outside the retention or cardinality limits.
+//
+// Such samples are counted as rejected data points in the returned partial success instead of failing the whole request.
+type RejectedSamplesError struct {
+	// Count is the number of rejected samples.
+	Count int
+
+	// Reason is the reason for the rejection.
+	Reason string
+}
+
+// Error implements error interface.
+func (e *RejectedSamplesError) Error() string {
+	return fmt.Sprintf("rejected %d samples: %s", e.Count, e.Reason)
+}
+
+// rejectDataPoints registers n data points for the metric with the given metricName, which were rejected because of the given reason.
+//
+// n may be zero if the rejected data cannot be counted in data points. Such a rejection is reported as a warning to the client.
+// metricName may be empty if the rejected data doesn't belong to a particular metric.
+func (wr *writeContext) rejectDataPoints(n int, metricName, reason string) {
+	if wr.rejectedCount == 0 {
+		wr.rejectedFirstErr = reason
+		if metricName != "" {
+			wr.rejectedFirstErr = fmt.Sprintf("metric %q: %s", metricName, reason)
+		}
+	}
+	wr.rejectedCount++
+	wr.rejectedDataPoints += int64(n)
+	dataPointsRejected.Add(n)
+}
+
+// partialSuccess returns partial success for the processed request.
+//
+// nil is returned if all the data points from the request were accepted.
+func (wr *writeContext) partialSuccess() *pb.ExportMetricsPartialSuccess {
+	if wr.rejectedCount == 0 {
+		return nil
+	}
+	errorMessage := wr.rejectedFirstErr
+	if wr.rejectedCount > 1 {
+		errorMessage = fmt.Sprintf("%s; %d more rejections", errorMessage, wr.rejectedCount-1)
+	}
+	return &pb.ExportMetricsPartialSuccess{
+		RejectedDataPoints: wr.rejectedDataPoints,
+		ErrorMessage:       errorMessage,
+	}
+}
+
+// getDataPointsCount returns the number of data points in m.
+func getDataPointsCount(m *pb.Metric) int {
+	switch {
+	case m.Gauge != nil:
+		return len(m.Gauge.DataPoints)
+	case m.Sum != nil:
+		return len(m.Sum.DataPoints)
+	case m.Summary != nil:
+		return len(m.Summary.DataPoints)
+	case m.Histogram != nil:
+		return len(m.Histogram.DataPoints)
+	case m.ExponentialHistogram != nil:
+		return len(m.ExponentialHistogram.DataPoints)
+	default:
+		return 0
+	}
+}
+
+// IsJSONRequest returns true if r contains OTLP/HTTP request in JSON format according to its Content-Type header.
+func IsJSONRequest(r *http.Request) bool {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	return err == nil && mediaType == "application/json"
+}
+
+// WritePartialSuccessResponse writes OTLP/HTTP response with the given ps to w.
+//
+// The response is encoded in the same format as the request r.
+//
+// See https://opentelemetry.io/docs/specs/otlp/#partial-success-1
+func WritePartialSuccessResponse(w http.ResponseWriter, r *http.Request, ps *pb.ExportMetricsPartialSuccess) {
+	resp := &pb.ExportMetricsServiceResponse{
+		PartialSuccess: ps,
+	}
+	var data []byte
+	if IsJSONRequest(r) {
+		data, _ = resp.MarshalJSON()
+		w.Header().Set("Content-Type", "application/json")
+	} else {
+		data = resp.MarshalProtobuf(nil)
+		w.Header().Set("Content-Type", "application/x-protobuf")
+	}
+	w.Write(data)
+}
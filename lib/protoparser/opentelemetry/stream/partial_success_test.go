@@ -0,0 +1,131 @@
+package stream
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+)
+
+func TestParseStreamExtPartialSuccess(t *testing.T) {
+	f := func(metrics []*pb.Metric, psExpected *pb.ExportMetricsPartialSuccess) {
+		t.Helper()
+		req := &pb.ExportMetricsServiceRequest{
+			ResourceMetrics: []*pb.ResourceMetrics{generateOTLPSamples(metrics)},
+		}
+		ps, err := ParseStreamExt(bytes.NewBuffer(req.MarshalProtobuf(nil)), "", nil, func(_ []prompbmarshal.TimeSeries) error {
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(ps, psExpected) {
+			t.Fatalf("unexpected partial success\ngot\n%#v\nwant\n%#v", ps, psExpected)
+		}
+	}
+
+	// all the data points are accepted
+	f([]*pb.Metric{generateGauge("my-gauge", ""), generateHistogram("my-histogram", "")}, nil)
+
+	// metric without name
+	f([]*pb.Metric{generateGauge("my-gauge", ""), generateGauge("", "")}, &pb.ExportMetricsPartialSuccess{
+		RejectedDataPoints: 1,
+		ErrorMessage:       "missing metric name",
+	})
+
+	// delta sum and histogram aren't supported by default
+	deltaSum := generateSum("my-sum", "", true)
+	deltaSum.Sum.AggregationTemporality = pb.AggregationTemporalityDelta
+	deltaHistogram := generateHistogram("my-histogram", "")
+	deltaHistogram.Histogram.AggregationTemporality = pb.AggregationTemporalityDelta
+	f([]*pb.Metric{deltaSum, generateGauge("my-gauge", ""), deltaHistogram}, &pb.ExportMetricsPartialSuccess{
+		RejectedDataPoints: 2,
+		ErrorMessage:       `metric "my-sum": unsupported aggregation temporality 1 for sum; 1 more rejections`,
+	})
+
+	// histogram with invalid number of buckets
+	badHistogram := generateHistogram("my-histogram", "")
+	badHistogram.Histogram.DataPoints[0].ExplicitBounds = nil
+	f([]*pb.Metric{badHistogram}, &pb.ExportMetricsPartialSuccess{
+		RejectedDataPoints: 1,
+		ErrorMessage:       `metric "my-histogram": unexpected number of bucket_counts; got 5; want 1`,
+	})
+
+	// metric without data
+	f([]*pb.Metric{{Name: "foo"}}, &pb.ExportMetricsPartialSuccess{
+		ErrorMessage: `metric "foo": unsupported metric type`,
+	})
+}
+
+func TestParseStreamExtRejectedSamples(t *testing.T) {
+	req := &pb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*pb.ResourceMetrics{generateOTLPSamples([]*pb.Metric{generateGauge("my-gauge", ""), generateGauge("", "")})},
+	}
+	data := req.MarshalProtobuf(nil)
+
+	// samples rejected by the callback must be reported via partial success
+	ps, err := ParseStreamExt(bytes.NewBuffer(data), "", nil, func(tss []prompbmarshal.TimeSeries) error {
+		return &RejectedSamplesError{
+			Count:  len(tss),
+			Reason: "too small timestamp",
+		}
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	psExpected := &pb.ExportMetricsPartialSuccess{
+		RejectedDataPoints: 2,
+		ErrorMessage:       "missing metric name; 1 more rejections",
+	}
+	if !reflect.DeepEqual(ps, psExpected) {
+		t.Fatalf("unexpected partial success\ngot\n%#v\nwant\n%#v", ps, psExpected)
+	}
+
+	// other errors must fail the request
+	_, err = ParseStreamExt(bytes.NewBuffer(data), "", nil, func(_ []prompbmarshal.TimeSeries) error {
+		return fmt.Errorf("some error")
+	})
+	if err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+}
+
+func TestWritePartialSuccessResponse(t *testing.T) {
+	f := func(contentType, contentTypeExpected string, bodyExpected []byte) {
+		t.Helper()
+
+		r := httptest.NewRequest(http.MethodPost, "/opentelemetry/v1/metrics", nil)
+		r.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
+		WritePartialSuccessResponse(w, r, &pb.ExportMetricsPartialSuccess{
+			RejectedDataPoints: 3,
+			ErrorMessage:       `metric "foo": bar`,
+		})
+		if w.Code != http.StatusOK {
+			t.Fatalf("unexpected status code; got %d; want %d", w.Code, http.StatusOK)
+		}
+		if ct := w.Header().Get("Content-Type"); ct != contentTypeExpected {
+			t.Fatalf("unexpected Content-Type; got %q; want %q", ct, contentTypeExpected)
+		}
+		if body := w.Body.Bytes(); !bytes.Equal(body, bodyExpected) {
+			t.Fatalf("unexpected response body\ngot\n%q\nwant\n%q", body, bodyExpected)
+		}
+	}
+
+	resp := &pb.ExportMetricsServiceResponse{
+		PartialSuccess: &pb.ExportMetricsPartialSuccess{
+			RejectedDataPoints: 3,
+			ErrorMessage:       `metric "foo": bar`,
+		},
+	}
+	f("application/x-protobuf", "application/x-protobuf", resp.MarshalProtobuf(nil))
+	f("application/json", "application/json", []byte(`{"partialSuccess":{"rejectedDataPoints":"3","errorMessage":"metric \"foo\": bar"}}`))
+	f("application/json; charset=utf-8", "application/json", []byte(`{"partialSuccess":{"rejectedDataPoints":"3","errorMessage":"metric \"foo\": bar"}}`))
+	f("Application/JSON", "application/json", []byte(`{"partialSuccess":{"rejectedDataPoints":"3","errorMessage":"metric \"foo\": bar"}}`))
+	f("", "application/x-protobuf", resp.MarshalProtobuf(nil))
+}
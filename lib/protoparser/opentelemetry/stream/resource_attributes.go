@@ -0,0 +1,108 @@
+package stream
+
+import (
+	"flag"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+)
+
+var (
+	promoteResourceAttributes = flagutil.NewArrayString("opentelemetry.promoteResourceAttributes", "Optional list of OpenTelemetry resource attribute names, "+
+		"which must be converted into labels for the metrics ingested via OpenTelemetry protocol. Names may contain '*' and '?' wildcards. "+
+		"For example, -opentelemetry.promoteResourceAttributes='service.*,k8s.namespace.name' converts only the matching resource attributes into labels. "+
+		"By default all the resource attributes are converted into labels. See also -opentelemetry.nonPromotedResourceAttributesLabel")
+	nonPromotedResourceAttributesLabel = flag.String("opentelemetry.nonPromotedResourceAttributesLabel", "", "Optional label name for storing the hash of OpenTelemetry "+
+		"resource attributes, which aren't promoted to labels via -opentelemetry.promoteResourceAttributes. This allows distinguishing time series "+
+		"from resources with distinct non-promoted attributes. By default non-promoted resource attributes are dropped")
+)
+
+// appendResourceAttributesToPromLabels appends resource attributes to dst according to -opentelemetry.promoteResourceAttributes
+// and -opentelemetry.nonPromotedResourceAttributesLabel and returns the result.
+func appendResourceAttributesToPromLabels(dst []prompbmarshal.Label, attributes []*pb.KeyValue) []prompbmarshal.Label {
+	re := getPromoteResourceAttributesRegexp()
+	if re == nil {
+		// Fast path - all the resource attributes are promoted.
+		return appendAttributesToPromLabels(dst, attributes)
+	}
+	return appendPromotedAttributesToPromLabels(dst, attributes, re, *nonPromotedResourceAttributesLabel)
+}
+
+// appendPromotedAttributesToPromLabels appends attributes with names matching re to dst and returns the result.
+//
+// If hashLabelName isn't empty, then the label with hashLabelName name and the hash of the remaining attributes is appended to dst.
+func appendPromotedAttributesToPromLabels(dst []prompbmarshal.Label, attributes []*pb.KeyValue, re *regexp.Regexp, hashLabelName string) []prompbmarshal.Label {
+	var nonPromoted []string
+	for _, at := range attributes {
+		if re.MatchString(at.Key) {
+			dst = append(dst, prompbmarshal.Label{
+				Name:  sanitizeLabelName(at.Key),
+				Value: at.Value.FormatString(),
+			})
+			continue
+		}
+		if hashLabelName != "" {
+			nonPromoted = append(nonPromoted, at.Key+"="+at.Value.FormatString())
+		}
+	}
+	if len(nonPromoted) == 0 {
+		return dst
+	}
+
+	// Sort non-promoted attributes, so the hash doesn't depend on the order of attributes in the request.
+	sort.Strings(nonPromoted)
+	h := xxhash.Sum64String(strings.Join(nonPromoted, "\n"))
+	return append(dst, prompbmarshal.Label{
+		Name:  hashLabelName,
+		Value: fmt.Sprintf("%016x", h),
+	})
+}
+
+var (
+	promoteResourceAttributesRe     *regexp.Regexp
+	promoteResourceAttributesReOnce sync.Once
+)
+
+func getPromoteResourceAttributesRegexp() *regexp.Regexp {
+	promoteResourceAttributesReOnce.Do(func() {
+		promoteResourceAttributesRe = newGlobsRegexp(*promoteResourceAttributes)
+	})
+	return promoteResourceAttributesRe
+}
+
+// newGlobsRegexp returns regexp, which matches any of the given globs.
+//
+// Globs may contain '*' for matching any number of chars and '?' for matching a single char.
+// It returns nil if globs are empty.
+func newGlobsRegexp(globs []string) *regexp.Regexp {
+	if len(globs) == 0 {
+		return nil
+	}
+	var b strings.Builder
+	b.WriteString("^(?:")
+	for i, glob := range globs {
+		if i > 0 {
+			b.WriteByte('|')
+		}
+		for _, c := range glob {
+			switch c {
+			case '*':
+				b.WriteString(".*")
+			case '?':
+				b.WriteByte('.')
+			default:
+				b.WriteString(regexp.QuoteMeta(string(c)))
+			}
+		}
+	}
+	b.WriteString(")$")
+	return regexp.MustCompile(b.String())
+}
@@ -0,0 +1,102 @@
+package stream
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+)
+
+func TestNewGlobsRegexp(t *testing.T) {
+	f := func(globs []string, s string, resultExpected bool) {
+		t.Helper()
+
+		re := newGlobsRegexp(globs)
+		result := re.MatchString(s)
+		if result != resultExpected {
+			t.Fatalf("unexpected result for matching %q against %q; got %v; want %v", s, globs, result, resultExpected)
+		}
+	}
+
+	f([]string{"service.name"}, "service.name", true)
+	f([]string{"service.name"}, "serviceXname", false)
+	f([]string{"service.name"}, "service.namespace", false)
+	f([]string{"service.*"}, "service.name", true)
+	f([]string{"service.*"}, "service.", true)
+	f([]string{"service.*"}, "k8s.service.name", false)
+	f([]string{"k8s.?od.name"}, "k8s.pod.name", true)
+	f([]string{"k8s.?od.name"}, "k8s.od.name", false)
+	f([]string{"foo", "bar*"}, "barbaz", true)
+	f([]string{"foo", "bar*"}, "baz", false)
+	f([]string{"a(b|c)"}, "ab", false)
+	f([]string{"a(b|c)"}, "a(b|c)", true)
+
+	if re := newGlobsRegexp(nil); re != nil {
+		t.Fatalf("expecting nil regexp for empty globs; got %s", re)
+	}
+}
+
+func TestAppendPromotedAttributesToPromLabels(t *testing.T) {
+	f := func(attributes []*pb.KeyValue, globs []string, hashLabelName string, resultExpected []prompbmarshal.Label) {
+		t.Helper()
+
+		re := newGlobsRegexp(globs)
+		result := appendPromotedAttributesToPromLabels(nil, attributes, re, hashLabelName)
+		if !reflect.DeepEqual(result, resultExpected) {
+			t.Fatalf("unexpected result\ngot\n%v\nwant\n%v", result, resultExpected)
+		}
+	}
+
+	attrs := func(kvs ...string) []*pb.KeyValue {
+		var a []*pb.KeyValue
+		for i := 0; i < len(kvs); i += 2 {
+			a = append(a, attributesFromKV(kvs[i], kvs[i+1])...)
+		}
+		return a
+	}
+
+	// drop non-promoted attributes
+	f(attrs("service.name", "foo", "host.name", "bar", "service.version", "v1"), []string{"service.*"}, "", []prompbmarshal.Label{
+		{
+			Name:  "service.name",
+			Value: "foo",
+		},
+		{
+			Name:  "service.version",
+			Value: "v1",
+		},
+	})
+
+	// no non-promoted attributes
+	f(attrs("service.name", "foo"), []string{"service.*"}, "resource_hash", []prompbmarshal.Label{
+		{
+			Name:  "service.name",
+			Value: "foo",
+		},
+	})
+
+	// collapse non-promoted attributes into hash label
+	f(attrs("service.name", "foo", "host.name", "bar", "host.id", "1"), []string{"service.name"}, "resource_hash", []prompbmarshal.Label{
+		{
+			Name:  "service.name",
+			Value: "foo",
+		},
+		{
+			Name:  "resource_hash",
+			Value: "3ab6adbc5b013a5b",
+		},
+	})
+
+	// the hash doesn't depend on the order of attributes
+	f(attrs("host.id", "1", "host.name", "bar", "service.name", "foo"), []string{"service.name"}, "resource_hash", []prompbmarshal.Label{
+		{
+			Name:  "service.name",
+			Value: "foo",
+		},
+		{
+			Name:  "resource_hash",
+			Value: "3ab6adbc5b013a5b",
+		},
+	})
+}
@@ -0,0 +1,76 @@
+package stream
+
+import (
+	"flag"
+	"regexp"
+	"sync"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+)
+
+var (
+	promoteScopeMetadata = flag.Bool("opentelemetry.promoteScopeMetadata", false, "Whether to add otel_scope_name and otel_scope_version labels "+
+		"with the name and the version of OpenTelemetry instrumentation scope to the metrics ingested via OpenTelemetry protocol. "+
+		"See also -opentelemetry.promoteScopeAttributes")
+	promoteScopeAttributes = flagutil.NewArrayString("opentelemetry.promoteScopeAttributes", "Optional list of OpenTelemetry instrumentation scope attribute names, "+
+		"which must be converted into labels with otel_scope_ prefix for the metrics ingested via OpenTelemetry protocol. Names may contain '*' and '?' wildcards. "+
+		"By default scope attributes are dropped. See also -opentelemetry.promoteScopeMetadata")
+)
+
+// appendScopeLabelsToPromLabels appends labels for the given instrumentation scope to dst according to -opentelemetry.promoteScopeMetadata
+// and -opentelemetry.promoteScopeAttributes and returns the result.
+//
+// See https://opentelemetry.io/docs/specs/otel/compatibility/prometheus_and_openmetrics/#instrumentation-scope-1
+func appendScopeLabelsToPromLabels(dst []prompbmarshal.Label, scope *pb.InstrumentationScope) []prompbmarshal.Label {
+	return appendScopeLabels(dst, scope, *promoteScopeMetadata, getPromoteScopeAttributesRegexp())
+}
+
+// appendScopeLabels appends labels for the given scope to dst and returns the result.
+//
+// Scope name and version are appended if promoteMetadata is set. Scope attributes with names matching re are appended if re isn't nil.
+func appendScopeLabels(dst []prompbmarshal.Label, scope *pb.InstrumentationScope, promoteMetadata bool, re *regexp.Regexp) []prompbmarshal.Label {
+	if scope == nil {
+		return dst
+	}
+	if promoteMetadata {
+		if scope.Name != "" {
+			dst = append(dst, prompbmarshal.Label{
+				Name:  "otel_scope_name",
+				Value: scope.Name,
+			})
+		}
+		if scope.Version != "" {
+			dst = append(dst, prompbmarshal.Label{
+				Name:  "otel_scope_version",
+				Value: scope.Version,
+			})
+		}
+	}
+	if re == nil {
+		return dst
+	}
+	for _, at := range scope.Attributes {
+		if !re.MatchString(at.Key) {
+			continue
+		}
+		dst = append(dst, prompbmarshal.Label{
+			Name:  "otel_scope_" + sanitizeLabelName(at.Key),
+			Value: at.Value.FormatString(),
+		})
+	}
+	return dst
+}
+
+var (
+	promoteScopeAttributesRe     *regexp.Regexp
+	promoteScopeAttributesReOnce sync.Once
+)
+
+func getPromoteScopeAttributesRegexp() *regexp.Regexp {
+	promoteScopeAttributesReOnce.Do(func() {
+		promoteScopeAttributesRe = newGlobsRegexp(*promoteScopeAttributes)
+	})
+	return promoteScopeAttributesRe
+}
@@ -0,0 +1,147 @@
+package stream
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+)
+
+func TestAppendScopeLabels(t *testing.T) {
+	f := func(scope *pb.InstrumentationScope, promoteMetadata bool, globs []string, resultExpected []prompbmarshal.Label) {
+		t.Helper()
+
+		re := newGlobsRegexp(globs)
+		result := appendScopeLabels(nil, scope, promoteMetadata, re)
+		if !reflect.DeepEqual(result, resultExpected) {
+			t.Fatalf("unexpected result\ngot\n%v\nwant\n%v", result, resultExpected)
+		}
+	}
+
+	scope := &pb.InstrumentationScope{
+		Name:    "my-library",
+		Version: "1.2.3",
+		Attributes: append(attributesFromKV("library.language", "go"),
+			attributesFromKV("library.mode", "sync")...),
+	}
+
+	// missing scope
+	f(nil, true, []string{"*"}, nil)
+
+	// nothing is promoted
+	f(scope, false, nil, nil)
+
+	// promote scope name and version
+	f(scope, true, nil, []prompbmarshal.Label{
+		{
+			Name:  "otel_scope_name",
+			Value: "my-library",
+		},
+		{
+			Name:  "otel_scope_version",
+			Value: "1.2.3",
+		},
+	})
+
+	// empty scope name and version aren't promoted
+	f(&pb.InstrumentationScope{}, true, nil, nil)
+
+	// promote the selected scope attributes
+	f(scope, false, []string{"library.lang*"}, []prompbmarshal.Label{
+		{
+			Name:  "otel_scope_library.language",
+			Value: "go",
+		},
+	})
+
+	// promote everything
+	f(scope, true, []string{"*"}, []prompbmarshal.Label{
+		{
+			Name:  "otel_scope_name",
+			Value: "my-library",
+		},
+		{
+			Name:  "otel_scope_version",
+			Value: "1.2.3",
+		},
+		{
+			Name:  "otel_scope_library.language",
+			Value: "go",
+		},
+		{
+			Name:  "otel_scope_library.mode",
+			Value: "sync",
+		},
+	})
+}
+
+func TestParseStreamPromoteScopeMetadata(t *testing.T) {
+	*promoteScopeMetadata = true
+	defer func() {
+		*promoteScopeMetadata = false
+	}()
+
+	req := &pb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*pb.ResourceMetrics{
+			{
+				Resource: &pb.Resource{
+					Attributes: attributesFromKV("job", "vm"),
+				},
+				ScopeMetrics: []*pb.ScopeMetrics{
+					{
+						Scope: &pb.InstrumentationScope{
+							Name:    "scope-a",
+							Version: "v1",
+						},
+						Metrics: []*pb.Metric{generateGauge("my-gauge", "")},
+					},
+					{
+						Scope: &pb.InstrumentationScope{
+							Name: "scope-b",
+						},
+						Metrics: []*pb.Metric{generateGauge("my-gauge", "")},
+					},
+					{
+						Metrics: []*pb.Metric{generateGauge("my-gauge", "")},
+					},
+				},
+			},
+		},
+	}
+	var labelsResult [][]prompbmarshal.Label
+	err := ParseStream(bytes.NewBuffer(req.MarshalProtobuf(nil)), "", nil, func(tss []prompbmarshal.TimeSeries) error {
+		for _, ts := range tss {
+			labelsResult = append(labelsResult, append([]prompbmarshal.Label{}, ts.Labels...))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	labelsExpected := [][]prompbmarshal.Label{
+		{
+			{Name: "__name__", Value: "my-gauge"},
+			{Name: "job", Value: "vm"},
+			{Name: "otel_scope_name", Value: "scope-a"},
+			{Name: "otel_scope_version", Value: "v1"},
+			{Name: "label1", Value: "value1"},
+		},
+		{
+			{Name: "__name__", Value: "my-gauge"},
+			{Name: "job", Value: "vm"},
+			{Name: "otel_scope_name", Value: "scope-b"},
+			{Name: "label1", Value: "value1"},
+		},
+		{
+			{Name: "__name__", Value: "my-gauge"},
+			{Name: "job", Value: "vm"},
+			{Name: "label1", Value: "value1"},
+		},
+	}
+	if !reflect.DeepEqual(labelsResult, labelsExpected) {
+		t.Fatalf("unexpected labels\ngot\n%v\nwant\n%v", labelsResult, labelsExpected)
+	}
+}
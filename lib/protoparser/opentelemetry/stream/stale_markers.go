@@ -0,0 +1,195 @@
+package stream
+
+import (
+	"flag"
+	"sync"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/decimal"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fasttime"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+)
+
+var generateStaleMarkers = flag.Bool("opentelemetry.generateStaleMarkers", false, "Whether to generate staleness markers for series, "+
+	"which disappear from subsequent OpenTelemetry exports for the same resource. This works similar to staleness markers for scraped targets. "+
+	"See https://docs.victoriametrics.com/#sending-data-via-opentelemetry")
+
+// staleResourceTimeoutSecs is the interval in seconds after which the state for resources without new exports is dropped.
+//
+// Staleness markers aren't generated for series of such resources, since queries stop returning their values after 5 minutes anyway.
+const staleResourceTimeoutSecs = 3600
+
+// staleStates holds the series from the last export for every OpenTelemetry resource.
+var staleStates = newStaleStateStorage()
+
+type staleStateStorage struct {
+	mu sync.Mutex
+
+	// m contains the state per each resource key.
+	m map[string]*staleState
+
+	// lastCleanupTime is unix timestamp in seconds for the last cleanup of stale entries in m.
+	lastCleanupTime uint64
+}
+
+// staleState contains series from the last export for a single resource.
+type staleState struct {
+	// series contains keys for the series from the last export.
+	series map[string]struct{}
+
+	// lastAccessTime is unix timestamp in seconds for the last export for the resource.
+	lastAccessTime uint64
+}
+
+func newStaleStateStorage() *staleStateStorage {
+	sss := &staleStateStorage{
+		m:               make(map[string]*staleState),
+		lastCleanupTime: fasttime.UnixTimestamp(),
+	}
+	_ = metrics.NewGauge(`vm_protoparser_stale_markers_tracked_series{type="opentelemetry"}`, func() float64 {
+		sss.mu.Lock()
+		n := 0
+		for _, ss := range sss.m {
+			n += len(ss.series)
+		}
+		sss.mu.Unlock()
+		return float64(n)
+	})
+	return sss
+}
+
+// getLocked returns the state for the given resourceKey.
+//
+// sss.mu must be locked by the caller.
+func (sss *staleStateStorage) getLocked(resourceKey string) *staleState {
+	currentTime := fasttime.UnixTimestamp()
+	if currentTime-sss.lastCleanupTime > staleResourceTimeoutSecs/2 {
+		for k, ss := range sss.m {
+			if currentTime-ss.lastAccessTime > staleResourceTimeoutSecs {
+				delete(sss.m, k)
+			}
+		}
+		sss.lastCleanupTime = currentTime
+	}
+
+	ss := sss.m[resourceKey]
+	if ss == nil {
+		ss = &staleState{
+			series: make(map[string]struct{}),
+		}
+		sss.m[resourceKey] = ss
+	}
+	ss.lastAccessTime = currentTime
+	return ss
+}
+
+// setStaleResource sets the resource for the series registered via registerStaleSeries.
+//
+// resourceLabels must contain labels obtained from the resource attributes.
+func (wr *writeContext) setStaleResource(resourceLabels []prompbmarshal.Label) {
+	if wr.staleSeries == nil {
+		wr.staleSeries = make(map[string]map[string]bool)
+	}
+	wr.staleKey = marshalDeltaKeyLabels(wr.staleKey[:0], resourceLabels)
+	series := wr.staleSeries[string(wr.staleKey)]
+	if series == nil {
+		series = make(map[string]bool)
+		wr.staleSeries[string(wr.staleKey)] = series
+	}
+	wr.staleResourceSeries = series
+}
+
+// registerStaleSeries registers the series with the given labels for the current resource.
+//
+// isStale must be set if the series is explicitly marked as stale in the export.
+func (wr *writeContext) registerStaleSeries(labels []prompbmarshal.Label, isStale bool) {
+	wr.staleKey = marshalStaleSeriesKey(wr.staleKey[:0], labels)
+	if _, ok := wr.staleResourceSeries[string(wr.staleKey)]; ok && !isStale {
+		return
+	}
+	wr.staleResourceSeries[string(wr.staleKey)] = isStale
+}
+
+// appendStaleMarkers appends staleness markers to wr.tss for the series, which were present in the previous export
+// for the resources seen in the current request, but are missing in the current request.
+func (wr *writeContext) appendStaleMarkers() {
+	if len(wr.staleSeries) == 0 {
+		return
+	}
+	t := int64(fasttime.UnixTimestamp()) * 1000
+
+	staleStates.mu.Lock()
+	defer staleStates.mu.Unlock()
+
+	for resourceKey, series := range wr.staleSeries {
+		ss := staleStates.getLocked(resourceKey)
+		for k := range ss.series {
+			if _, ok := series[k]; !ok {
+				wr.appendStaleMarker(k, t)
+			}
+		}
+		clear(ss.series)
+		for k, isStale := range series {
+			if !isStale {
+				ss.series[k] = struct{}{}
+			}
+		}
+	}
+}
+
+// appendStaleMarker appends staleness marker with the given timestamp t for the series with the given key to wr.tss.
+func (wr *writeContext) appendStaleMarker(key string, t int64) {
+	labelsPool := wr.labelsPool
+	labelsLen := len(labelsPool)
+	src := bytesutil.ToUnsafeBytes(key)
+	for len(src) > 0 {
+		name, n := encoding.UnmarshalBytes(src)
+		if n <= 0 {
+			logger.Panicf("BUG: cannot unmarshal label name from series key %q", key)
+		}
+		src = src[n:]
+		value, n := encoding.UnmarshalBytes(src)
+		if n <= 0 {
+			logger.Panicf("BUG: cannot unmarshal label value from series key %q", key)
+		}
+		src = src[n:]
+		labelsPool = append(labelsPool, prompbmarshal.Label{
+			Name:  bytesutil.ToUnsafeString(name),
+			Value: bytesutil.ToUnsafeString(value),
+		})
+	}
+
+	samplesPool := wr.samplesPool
+	samplesLen := len(samplesPool)
+	samplesPool = append(samplesPool, prompbmarshal.Sample{
+		Timestamp: t,
+		Value:     decimal.StaleNaN,
+	})
+
+	wr.tss = append(wr.tss, prompbmarshal.TimeSeries{
+		Labels:  labelsPool[labelsLen:],
+		Samples: samplesPool[samplesLen:],
+	})
+
+	wr.labelsPool = labelsPool
+	wr.samplesPool = samplesPool
+
+	staleMarkersGenerated.Inc()
+}
+
+// marshalStaleSeriesKey appends the key for the series with the given labels to dst and returns the result.
+//
+// The key can be converted back to labels by appendStaleMarker.
+func marshalStaleSeriesKey(dst []byte, labels []prompbmarshal.Label) []byte {
+	for _, label := range labels {
+		dst = encoding.MarshalBytes(dst, bytesutil.ToUnsafeBytes(label.Name))
+		dst = encoding.MarshalBytes(dst, bytesutil.ToUnsafeBytes(label.Value))
+	}
+	return dst
+}
+
+var staleMarkersGenerated = metrics.NewCounter(`vm_protoparser_stale_markers_generated_total{type="opentelemetry"}`)
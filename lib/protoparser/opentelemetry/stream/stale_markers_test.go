@@ -0,0 +1,114 @@
+package stream
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/decimal"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+)
+
+func TestMarshalStaleSeriesKey(t *testing.T) {
+	f := func(labels []prompbmarshal.Label) {
+		t.Helper()
+
+		key := marshalStaleSeriesKey(nil, labels)
+		var wr writeContext
+		wr.appendStaleMarker(string(key), 123)
+		if len(wr.tss) != 1 {
+			t.Fatalf("unexpected number of time series; got %d; want 1", len(wr.tss))
+		}
+		ts := wr.tss[0]
+		if !reflect.DeepEqual(ts.Labels, labels) {
+			t.Fatalf("unexpected labels\ngot\n%v\nwant\n%v", ts.Labels, labels)
+		}
+		if len(ts.Samples) != 1 || ts.Samples[0].Timestamp != 123 || !decimal.IsStaleNaN(ts.Samples[0].Value) {
+			t.Fatalf("unexpected samples: %v", ts.Samples)
+		}
+	}
+
+	f([]prompbmarshal.Label{{Name: "__name__", Value: "foo"}})
+	f([]prompbmarshal.Label{{Name: "__name__", Value: "foo"}, {Name: "job", Value: "bar"}})
+
+	// labels with zero bytes and empty values
+	f([]prompbmarshal.Label{{Name: "__name__", Value: "foo\x00bar"}, {Name: "job", Value: ""}})
+}
+
+func TestParseStreamGenerateStaleMarkers(t *testing.T) {
+	*generateStaleMarkers = true
+	defer func() {
+		*generateStaleMarkers = false
+	}()
+
+	f := func(req *pb.ExportMetricsServiceRequest, staleLabelsExpected [][]prompbmarshal.Label) {
+		t.Helper()
+
+		var staleLabels [][]prompbmarshal.Label
+		err := ParseStream(bytes.NewBuffer(req.MarshalProtobuf(nil)), "", nil, func(tss []prompbmarshal.TimeSeries) error {
+			for _, ts := range tss {
+				if decimal.IsStaleNaN(ts.Samples[0].Value) {
+					staleLabels = append(staleLabels, append([]prompbmarshal.Label{}, ts.Labels...))
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if !reflect.DeepEqual(staleLabels, staleLabelsExpected) {
+			t.Fatalf("unexpected staleness markers\ngot\n%v\nwant\n%v", staleLabels, staleLabelsExpected)
+		}
+	}
+
+	newRequest := func(job string, metrics ...*pb.Metric) *pb.ExportMetricsServiceRequest {
+		return &pb.ExportMetricsServiceRequest{
+			ResourceMetrics: []*pb.ResourceMetrics{
+				{
+					Resource: &pb.Resource{
+						Attributes: attributesFromKV("job", job),
+					},
+					ScopeMetrics: []*pb.ScopeMetrics{
+						{
+							Metrics: metrics,
+						},
+					},
+				},
+			},
+		}
+	}
+
+	// the first export doesn't generate staleness markers
+	f(newRequest("stale-test", generateGauge("foo", ""), generateGauge("bar", "")), nil)
+
+	// the same series don't generate staleness markers
+	f(newRequest("stale-test", generateGauge("foo", ""), generateGauge("bar", "")), nil)
+
+	// exports for other resources don't generate staleness markers
+	f(newRequest("stale-test-other", generateGauge("baz", "")), nil)
+
+	// the disappeared series gets staleness marker
+	f(newRequest("stale-test", generateGauge("foo", "")), [][]prompbmarshal.Label{
+		{
+			{Name: "__name__", Value: "bar"},
+			{Name: "job", Value: "stale-test"},
+			{Name: "label1", Value: "value1"},
+		},
+	})
+
+	// staleness marker is generated only once
+	f(newRequest("stale-test", generateGauge("foo", "")), nil)
+
+	// the explicitly stale series doesn't get additional staleness marker in the next export
+	m := generateGauge("foo", "")
+	m.Gauge.DataPoints[0].Flags = 1
+	f(newRequest("stale-test", m), [][]prompbmarshal.Label{
+		{
+			{Name: "__name__", Value: "foo"},
+			{Name: "job", Value: "stale-test"},
+			{Name: "label1", Value: "value1"},
+		},
+	})
+	f(newRequest("stale-test", generateGauge("bar", "")), nil)
+}
@@ -1,15 +1,22 @@
 package stream
 
 import (
+	"bytes"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
+	"math"
 	"strconv"
+	"strings"
 	"sync"
 
 	"github.com/VictoriaMetrics/metrics"
+	"github.com/golang/snappy"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/decimal"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/exemplars"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fasttime"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
@@ -18,76 +25,307 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/writeconcurrencylimiter"
 )
 
+var (
+	lenientDecoding = flag.Bool("opentelemetry.lenientDecoding", false, "Whether to skip malformed nested messages in OpenTelemetry protobuf requests "+
+		"instead of rejecting the whole request. The number of skipped messages is exposed via vm_protoparser_messages_skipped_total metric. "+
+		"See also -opentelemetry.strictValidation")
+	strictValidation = flag.Bool("opentelemetry.strictValidation", false, "Whether to reject OpenTelemetry protobuf requests with fields unknown to OpenTelemetry protocol, "+
+		"metrics without name or data and data points without timestamp or value. By default such data is silently skipped or accepted. "+
+		"This flag has priority over -opentelemetry.lenientDecoding")
+)
+
 // ParseStream parses OpenTelemetry protobuf or json data from r and calls callback for the parsed rows.
 //
 // callback shouldn't hold tss items after returning.
 //
+// contentEncoding must contain the value of Content-Encoding http request header. Supported values: gzip, zstd, snappy.
+// Empty contentEncoding means uncompressed data.
+//
 // optional processBody can be used for pre-processing the read request body from r before parsing it in OpenTelemetry format.
-func ParseStream(r io.Reader, isGzipped bool, processBody func([]byte) ([]byte, error), callback func(tss []prompbmarshal.TimeSeries) error) error {
+func ParseStream(r io.Reader, contentEncoding string, processBody func([]byte) ([]byte, error), callback func(tss []prompbmarshal.TimeSeries) error) error {
+	_, err := ParseStreamExt(r, contentEncoding, processBody, callback)
+	return err
+}
+
+// ParseStreamExt works the same as ParseStream, but it also returns partial success for the parsed request.
+//
+// The returned partial success contains the number of rejected data points and the reason for the rejection.
+// It is nil if all the data points from the request were accepted.
+// callback may return *RejectedSamplesError in order to report samples rejected after the parsing.
+// See https://opentelemetry.io/docs/specs/otlp/#partial-success
+func ParseStreamExt(r io.Reader, contentEncoding string, processBody func([]byte) ([]byte, error), callback func(tss []prompbmarshal.TimeSeries) error) (*pb.ExportMetricsPartialSuccess, error) {
 	wcr := writeconcurrencylimiter.GetReader(r)
 	defer writeconcurrencylimiter.PutReader(wcr)
 	r = wcr
 
-	if isGzipped {
+	switch contentEncoding {
+	case "", "identity":
+	case "gzip":
 		zr, err := common.GetGzipReader(r)
 		if err != nil {
-			return fmt.Errorf("cannot read gzip-compressed OpenTelemetry protocol data: %w", err)
+			return nil, fmt.Errorf("cannot read gzip-compressed OpenTelemetry protocol data: %w", err)
 		}
 		defer common.PutGzipReader(zr)
 		r = zr
+	case "zstd":
+		zr, err := common.GetZstdReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read zstd-compressed OpenTelemetry protocol data: %w", err)
+		}
+		defer common.PutZstdReader(zr)
+		r = zr
+	case "snappy":
+		// snappy-compressed data is decompressed after reading the whole request body in readRequest,
+		// since snappy block format doesn't support stream decompression.
+	default:
+		return nil, fmt.Errorf("unsupported Content-Encoding=%q for OpenTelemetry protocol data; supported values: gzip, zstd, snappy", contentEncoding)
 	}
 
 	wr := getWriteContext()
 	defer putWriteContext(wr)
-	req, err := wr.readAndUnpackRequest(r, processBody)
-	if err != nil {
-		return fmt.Errorf("cannot unpack OpenTelemetry metrics: %w", err)
+	if err := wr.readRequest(r, contentEncoding == "snappy", processBody); err != nil {
+		return nil, fmt.Errorf("cannot unpack OpenTelemetry metrics: %w", err)
+	}
+	if err := wr.parseRequest(callback); err != nil {
+		return nil, err
 	}
-	wr.parseRequestToTss(req)
+	return wr.partialSuccess(), nil
+}
 
-	if err := callback(wr.tss); err != nil {
-		return fmt.Errorf("error when processing OpenTelemetry samples: %w", err)
+// maxSamplesPerCallback is the maximum number of samples passed to ParseStream callback at once.
+//
+// This limits memory usage when processing big requests.
+const maxSamplesPerCallback = 10_000
+
+// parseRequest parses protobuf-encoded ExportMetricsServiceRequest at wr.bb and calls callback for the parsed samples.
+//
+// The request is parsed metric by metric, and callback is called every time when maxSamplesPerCallback samples are collected.
+// This allows bounding memory usage for big requests, since they aren't unmarshaled into memory at once.
+//
+// The whole request is verified before calling callback, so malformed requests are rejected without partial ingestion.
+func (wr *writeContext) parseRequest(callback func(tss []prompbmarshal.TimeSeries) error) error {
+	if *strictValidation {
+		if err := pb.ValidateProtobufStrict(wr.bb.B); err != nil {
+			requestsRejectedStrictValidation.Inc()
+			return fmt.Errorf("cannot unpack OpenTelemetry metrics in strict mode: %w", err)
+		}
+	} else if !*lenientDecoding {
+		// Malformed messages aren't skipped, so verify that all the metrics can be unmarshaled before passing them to callback.
+		err := pb.VisitMetrics(wr.bb.B, nil, func(_ *pb.ResourceMetrics, _ *pb.ScopeMetrics, _ *pb.Metric) error {
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("cannot unpack OpenTelemetry metrics: cannot unmarshal request from %d bytes: %w", len(wr.bb.B), err)
+		}
 	}
 
+	var skipped int
+	var skippedPtr *int
+	if *lenientDecoding && !*strictValidation {
+		skippedPtr = &skipped
+	}
+	mv := newMetricsVisitor(wr, callback)
+	err := pb.VisitMetrics(wr.bb.B, skippedPtr, mv.visit)
+	if skipped > 0 {
+		messagesSkipped.Add(skipped)
+		wr.rejectDataPoints(0, "", fmt.Sprintf("skipped %d malformed messages", skipped))
+	}
+	if err == nil && mv.callbackErr == nil {
+		mv.finish()
+	}
+	if mv.callbackErr != nil {
+		return fmt.Errorf("error when processing OpenTelemetry samples: %w", mv.callbackErr)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot unpack OpenTelemetry metrics: cannot unmarshal request from %d bytes: %w", len(wr.bb.B), err)
+	}
 	return nil
 }
 
-func (wr *writeContext) appendSamplesFromScopeMetrics(sc *pb.ScopeMetrics) {
-	for _, m := range sc.Metrics {
-		if len(m.Name) == 0 {
-			// skip metrics without names
-			continue
-		}
-		metricName := sanitizeMetricName(m)
-		switch {
-		case m.Gauge != nil:
-			for _, p := range m.Gauge.DataPoints {
-				wr.appendSampleFromNumericPoint(metricName, p)
+// ParseRequest converts the already decoded req into samples and calls callback for them.
+//
+// It is intended for requests obtained from other representations such as OpenTelemetry Arrow,
+// so they don't need to be marshaled into protobuf and parsed again.
+// The caller is responsible for limiting the concurrency of ParseRequest calls.
+//
+// callback shouldn't hold tss items after returning. Attributes at req may be modified by ParseRequest.
+func ParseRequest(req *pb.ExportMetricsServiceRequest, callback func(tss []prompbmarshal.TimeSeries) error) (*pb.ExportMetricsPartialSuccess, error) {
+	wr := getWriteContext()
+	defer putWriteContext(wr)
+
+	mv := newMetricsVisitor(wr, callback)
+	mv.visitRequest(req)
+	if mv.callbackErr == nil {
+		mv.finish()
+	}
+	if mv.callbackErr != nil {
+		return nil, fmt.Errorf("error when processing OpenTelemetry samples: %w", mv.callbackErr)
+	}
+	return wr.partialSuccess(), nil
+}
+
+// metricsVisitor converts the visited metrics into samples at wr and passes them to callback
+// every time maxSamplesPerCallback samples are collected.
+type metricsVisitor struct {
+	wr       *writeContext
+	callback func(tss []prompbmarshal.TimeSeries) error
+	am       *attributesMapping
+
+	renames           map[string]string
+	rmPrev            *pb.ResourceMetrics
+	smPrev            *pb.ScopeMetrics
+	resourceLabelsLen int
+
+	callbackErr error
+}
+
+func newMetricsVisitor(wr *writeContext, callback func(tss []prompbmarshal.TimeSeries) error) *metricsVisitor {
+	return &metricsVisitor{
+		wr:       wr,
+		callback: callback,
+		am:       attributesMappingGlobal.Load(),
+	}
+}
+
+// visitRequest visits all the metrics at req in the same order as pb.VisitMetrics does.
+func (mv *metricsVisitor) visitRequest(req *pb.ExportMetricsServiceRequest) {
+	for _, rm := range req.ResourceMetrics {
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if err := mv.visit(rm, sm, m); err != nil {
+					return
+				}
 			}
-		case m.Sum != nil:
-			if m.Sum.AggregationTemporality != pb.AggregationTemporalityCumulative {
-				rowsDroppedUnsupportedSum.Inc()
-				continue
+		}
+	}
+}
+
+func (mv *metricsVisitor) visit(rm *pb.ResourceMetrics, sm *pb.ScopeMetrics, m *pb.Metric) error {
+	wr := mv.wr
+	am := mv.am
+	if rm != mv.rmPrev {
+		var attributes []*pb.KeyValue
+		if rm.Resource != nil {
+			if am != nil {
+				rm.Resource.Attributes = renameAttributes(rm.Resource.Attributes, am.getRenames(rm.SchemaURL))
 			}
-			for _, p := range m.Sum.DataPoints {
-				wr.appendSampleFromNumericPoint(metricName, p)
+			attributes = rm.Resource.Attributes
+		}
+		wr.baseLabels = appendResourceAttributesToPromLabels(wr.baseLabels[:0], attributes)
+		mv.resourceLabelsLen = len(wr.baseLabels)
+		if *generateStaleMarkers {
+			wr.setStaleResource(wr.baseLabels)
+		}
+		mv.rmPrev = rm
+		mv.smPrev = nil
+	}
+	if sm != mv.smPrev {
+		if am != nil {
+			// The schema_url from ScopeMetrics has priority over the schema_url from ResourceMetrics.
+			// See https://github.com/open-telemetry/opentelemetry-proto/blob/main/opentelemetry/proto/metrics/v1/metrics.proto
+			schemaURL := sm.SchemaURL
+			if schemaURL == "" {
+				schemaURL = rm.SchemaURL
 			}
-		case m.Summary != nil:
-			for _, p := range m.Summary.DataPoints {
-				wr.appendSamplesFromSummary(metricName, p)
+			mv.renames = am.getRenames(schemaURL)
+			if sm.Scope != nil {
+				sm.Scope.Attributes = renameAttributes(sm.Scope.Attributes, mv.renames)
 			}
-		case m.Histogram != nil:
-			if m.Histogram.AggregationTemporality != pb.AggregationTemporalityCumulative {
-				rowsDroppedUnsupportedHistogram.Inc()
+		}
+		wr.baseLabels = appendScopeLabelsToPromLabels(wr.baseLabels[:mv.resourceLabelsLen], sm.Scope)
+		mv.smPrev = sm
+	}
+	renameMetricAttributes(m, mv.renames)
+	wr.appendSamplesFromMetric(m)
+	if len(wr.samplesPool) < maxSamplesPerCallback {
+		return nil
+	}
+	mv.callbackErr = wr.flush(mv.callback)
+	return mv.callbackErr
+}
+
+// finish appends stale markers if needed and passes the remaining samples to callback.
+func (mv *metricsVisitor) finish() {
+	if *generateStaleMarkers {
+		mv.wr.appendStaleMarkers()
+	}
+	mv.callbackErr = mv.wr.flush(mv.callback)
+}
+
+// flush passes the collected samples to callback and then resets them.
+func (wr *writeContext) flush(callback func(tss []prompbmarshal.TimeSeries) error) error {
+	err := callback(wr.tss)
+	var rse *RejectedSamplesError
+	if errors.As(err, &rse) {
+		// Samples rejected by the callback are reported via partial success.
+		wr.rejectDataPoints(rse.Count, "", rse.Reason)
+		err = nil
+	}
+
+	clear(wr.tss)
+	wr.tss = wr.tss[:0]
+	wr.labelsPool = resetLabels(wr.labelsPool)
+	wr.samplesPool = wr.samplesPool[:0]
+
+	return err
+}
+
+func (wr *writeContext) appendSamplesFromMetric(m *pb.Metric) {
+	if len(m.Name) == 0 {
+		// skip metrics without names
+		wr.rejectDataPoints(getDataPointsCount(m), "", "missing metric name")
+		return
+	}
+	metricName := sanitizeMetricName(m)
+	switch {
+	case m.Gauge != nil:
+		for _, p := range m.Gauge.DataPoints {
+			wr.appendSampleFromNumericPoint(metricName, p)
+		}
+	case m.Sum != nil:
+		isDelta := m.Sum.AggregationTemporality == pb.AggregationTemporalityDelta && *convertDeltaToCumulative
+		if m.Sum.AggregationTemporality != pb.AggregationTemporalityCumulative && !isDelta {
+			rowsDroppedUnsupportedSum.Inc()
+			wr.rejectDataPoints(len(m.Sum.DataPoints), metricName, fmt.Sprintf("unsupported aggregation temporality %d for sum", m.Sum.AggregationTemporality))
+			return
+		}
+		for _, p := range m.Sum.DataPoints {
+			if isDelta && !wr.convertDeltaNumberDataPoint(metricName, p) {
 				continue
 			}
-			for _, p := range m.Histogram.DataPoints {
-				wr.appendSamplesFromHistogram(metricName, p)
+			wr.appendSampleFromNumericPoint(metricName, p)
+		}
+	case m.Summary != nil:
+		for _, p := range m.Summary.DataPoints {
+			wr.appendSamplesFromSummary(metricName, p)
+		}
+	case m.Histogram != nil:
+		isDelta := m.Histogram.AggregationTemporality == pb.AggregationTemporalityDelta && *convertDeltaToCumulative
+		if m.Histogram.AggregationTemporality != pb.AggregationTemporalityCumulative && !isDelta {
+			rowsDroppedUnsupportedHistogram.Inc()
+			wr.rejectDataPoints(len(m.Histogram.DataPoints), metricName, fmt.Sprintf("unsupported aggregation temporality %d for histogram", m.Histogram.AggregationTemporality))
+			return
+		}
+		for _, p := range m.Histogram.DataPoints {
+			if isDelta && !wr.convertDeltaHistogramDataPoint(metricName, p) {
+				continue
 			}
-		default:
-			rowsDroppedUnsupportedMetricType.Inc()
-			logger.Warnf("unsupported type for metric %q", metricName)
+			wr.appendSamplesFromHistogram(metricName, p)
+		}
+	case m.ExponentialHistogram != nil:
+		if m.ExponentialHistogram.AggregationTemporality != pb.AggregationTemporalityCumulative {
+			rowsDroppedUnsupportedHistogram.Inc()
+			wr.rejectDataPoints(len(m.ExponentialHistogram.DataPoints), metricName,
+				fmt.Sprintf("unsupported aggregation temporality %d for exponential histogram", m.ExponentialHistogram.AggregationTemporality))
+			return
 		}
+		for _, p := range m.ExponentialHistogram.DataPoints {
+			wr.appendSamplesFromExponentialHistogram(metricName, p)
+		}
+	default:
+		rowsDroppedUnsupportedMetricType.Inc()
+		logger.Warnf("unsupported type for metric %q", metricName)
+		wr.rejectDataPoints(0, metricName, "unsupported metric type")
 	}
 }
 
@@ -106,6 +344,9 @@ func (wr *writeContext) appendSampleFromNumericPoint(metricName string, p *pb.Nu
 	wr.pointLabels = appendAttributesToPromLabels(wr.pointLabels[:0], p.Attributes)
 
 	wr.appendSample(metricName, t, v, isStale)
+	if len(p.Exemplars) > 0 && !isStale && exemplars.Enabled() {
+		wr.addExemplars(&wr.tss[len(wr.tss)-1], p.Exemplars)
+	}
 }
 
 // appendSamplesFromSummary appends summary p to wr.tss
@@ -131,6 +372,7 @@ func (wr *writeContext) appendSamplesFromHistogram(metricName string, p *pb.Hist
 	if len(p.BucketCounts) != len(p.ExplicitBounds)+1 {
 		// fast path, broken data format
 		logger.Warnf("opentelemetry bad histogram format: %q, size of buckets: %d, size of bounds: %d", metricName, len(p.BucketCounts), len(p.ExplicitBounds))
+		wr.rejectDataPoints(1, metricName, fmt.Sprintf("unexpected number of bucket_counts; got %d; want %d", len(p.BucketCounts), len(p.ExplicitBounds)+1))
 		return
 	}
 
@@ -148,6 +390,7 @@ func (wr *writeContext) appendSamplesFromHistogram(metricName string, p *pb.Hist
 
 	wr.appendSample(metricName+"_sum", t, *p.Sum, isStale)
 
+	bucketsStart := len(wr.tss)
 	var cumulative uint64
 	for index, bound := range p.ExplicitBounds {
 		cumulative += p.BucketCounts[index]
@@ -156,6 +399,56 @@ func (wr *writeContext) appendSamplesFromHistogram(metricName string, p *pb.Hist
 	}
 	cumulative += p.BucketCounts[len(p.BucketCounts)-1]
 	wr.appendSampleWithExtraLabel(metricName+"_bucket", "le", "+Inf", t, float64(cumulative), isStale)
+	if len(p.Exemplars) > 0 && !isStale && exemplars.Enabled() {
+		wr.addHistogramExemplars(wr.tss[bucketsStart:], p.ExplicitBounds, p.Exemplars)
+	}
+}
+
+// appendSamplesFromExponentialHistogram appends exponential histogram p to wr.tss
+//
+// Exponential buckets are converted into VictoriaMetrics histogram buckets with `vmrange` label,
+// so they can be used in histogram functions. See https://valyala.medium.com/improving-histogram-usability-for-prometheus-and-grafana-bc7e5df0e350
+func (wr *writeContext) appendSamplesFromExponentialHistogram(metricName string, p *pb.ExponentialHistogramDataPoint) {
+	t := int64(p.TimeUnixNano / 1e6)
+	isStale := (p.Flags)&uint32(1) != 0
+	wr.pointLabels = appendAttributesToPromLabels(wr.pointLabels[:0], p.Attributes)
+	wr.appendSample(metricName+"_count", t, float64(p.Count), isStale)
+	if p.Sum != nil {
+		wr.appendSample(metricName+"_sum", t, *p.Sum, isStale)
+	}
+
+	bucketName := metricName + "_bucket"
+	if p.ZeroCount > 0 {
+		vmrange := fmt.Sprintf("0...%.3e", p.ZeroThreshold)
+		wr.appendSampleWithExtraLabel(bucketName, "vmrange", vmrange, t, float64(p.ZeroCount), isStale)
+	}
+	// The bucket at index i covers (base^(offset+i), base^(offset+i+1)] range, where base = 2^(2^-scale).
+	// See https://opentelemetry.io/docs/specs/otel/metrics/data-model/#exponentialhistogram
+	ratio := math.Exp2(-float64(p.Scale))
+	if b := p.Negative; b != nil {
+		for i, count := range b.BucketCounts {
+			if count == 0 {
+				continue
+			}
+			idx := float64(b.Offset) + float64(i)
+			lower := -math.Exp2((idx + 1) * ratio)
+			upper := -math.Exp2(idx * ratio)
+			vmrange := fmt.Sprintf("%.3e...%.3e", lower, upper)
+			wr.appendSampleWithExtraLabel(bucketName, "vmrange", vmrange, t, float64(count), isStale)
+		}
+	}
+	if b := p.Positive; b != nil {
+		for i, count := range b.BucketCounts {
+			if count == 0 {
+				continue
+			}
+			idx := float64(b.Offset) + float64(i)
+			lower := math.Exp2(idx * ratio)
+			upper := math.Exp2((idx + 1) * ratio)
+			vmrange := fmt.Sprintf("%.3e...%.3e", lower, upper)
+			wr.appendSampleWithExtraLabel(bucketName, "vmrange", vmrange, t, float64(count), isStale)
+		}
+	}
 }
 
 // appendSample appends sample with the given metricName to wr.tss
@@ -203,6 +496,10 @@ func (wr *writeContext) appendSampleWithExtraLabel(metricName, labelName, labelV
 	wr.labelsPool = labelsPool
 	wr.samplesPool = samplesPool
 
+	if wr.staleResourceSeries != nil {
+		wr.registerStaleSeries(labelsPool[labelsLen:], isStale)
+	}
+
 	rowsRead.Inc()
 }
 
@@ -224,15 +521,36 @@ type writeContext struct {
 	// tss holds parsed time series
 	tss []prompbmarshal.TimeSeries
 
-	// baseLabels are labels, which must be added to all the ingested samples
+	// baseLabels are labels, which must be added to all the ingested samples.
+	// They contain resource labels followed by instrumentation scope labels.
 	baseLabels []prompbmarshal.Label
 
 	// pointLabels are labels, which must be added to the ingested OpenTelemetry points
 	pointLabels []prompbmarshal.Label
 
+	// exemplarLabels is a buffer for labels of the ingested exemplars
+	exemplarLabels []prompbmarshal.Label
+
+	// deltaKey and deltaLabels are buffers for the series key used for converting delta values to cumulative values
+	deltaKey    []byte
+	deltaLabels []prompbmarshal.Label
+
+	// staleSeries contains series per each resource seen in the request if -opentelemetry.generateStaleMarkers is set.
+	// staleResourceSeries points to the series for the currently processed resource.
+	// staleKey is a buffer for the keys in staleSeries.
+	staleSeries         map[string]map[string]bool
+	staleResourceSeries map[string]bool
+	staleKey            []byte
+
 	// pools are used for reducing memory allocations when parsing time series
 	labelsPool  []prompbmarshal.Label
 	samplesPool []prompbmarshal.Sample
+
+	// rejectedCount, rejectedDataPoints and rejectedFirstErr hold information about the rejected data,
+	// which is returned to the client as partial success.
+	rejectedCount      int
+	rejectedDataPoints int64
+	rejectedFirstErr   string
 }
 
 func (wr *writeContext) reset() {
@@ -243,9 +561,21 @@ func (wr *writeContext) reset() {
 
 	wr.baseLabels = resetLabels(wr.baseLabels)
 	wr.pointLabels = resetLabels(wr.pointLabels)
+	wr.exemplarLabels = resetLabels(wr.exemplarLabels)
+
+	wr.deltaKey = wr.deltaKey[:0]
+	wr.deltaLabels = resetLabels(wr.deltaLabels)
+
+	clear(wr.staleSeries)
+	wr.staleResourceSeries = nil
+	wr.staleKey = wr.staleKey[:0]
 
 	wr.labelsPool = resetLabels(wr.labelsPool)
 	wr.samplesPool = wr.samplesPool[:0]
+
+	wr.rejectedCount = 0
+	wr.rejectedDataPoints = 0
+	wr.rejectedFirstErr = ""
 }
 
 func resetLabels(labels []prompbmarshal.Label) []prompbmarshal.Label {
@@ -253,37 +583,76 @@ func resetLabels(labels []prompbmarshal.Label) []prompbmarshal.Label {
 	return labels[:0]
 }
 
-func (wr *writeContext) readAndUnpackRequest(r io.Reader, processBody func([]byte) ([]byte, error)) (*pb.ExportMetricsServiceRequest, error) {
+// readRequest reads the request body from r into wr.bb.
+func (wr *writeContext) readRequest(r io.Reader, isSnappy bool, processBody func([]byte) ([]byte, error)) error {
 	if _, err := wr.bb.ReadFrom(r); err != nil {
-		return nil, fmt.Errorf("cannot read request: %w", err)
+		return fmt.Errorf("cannot read request: %w", err)
+	}
+	if isSnappy {
+		if err := wr.decompressSnappy(); err != nil {
+			return fmt.Errorf("cannot decompress snappy-encoded request with length %d: %w", len(wr.bb.B), err)
+		}
 	}
-	var req pb.ExportMetricsServiceRequest
 	if processBody != nil {
 		data, err := processBody(wr.bb.B)
 		if err != nil {
-			return nil, fmt.Errorf("cannot process request body: %w", err)
+			return fmt.Errorf("cannot process request body: %w", err)
 		}
 		wr.bb.B = append(wr.bb.B[:0], data...)
 	}
-	if err := req.UnmarshalProtobuf(wr.bb.B); err != nil {
-		return nil, fmt.Errorf("cannot unmarshal request from %d bytes: %w", len(wr.bb.B), err)
-	}
-	return &req, nil
+	return nil
 }
 
-func (wr *writeContext) parseRequestToTss(req *pb.ExportMetricsServiceRequest) {
-	for _, rm := range req.ResourceMetrics {
-		var attributes []*pb.KeyValue
-		if rm.Resource != nil {
-			attributes = rm.Resource.Attributes
+// snappyFramedStreamPrefix is the prefix of snappy framed stream.
+//
+// See https://github.com/google/snappy/blob/main/framing_format.txt
+const snappyFramedStreamPrefix = "\xff\x06\x00\x00sNaPpY"
+
+// decompressSnappy decompresses snappy-encoded data at wr.bb.
+//
+// Both snappy block format and snappy framed format are supported, since OpenTelemetry collector used framed format
+// for 'Content-Encoding: snappy' in the past, while the recent versions use block format.
+func (wr *writeContext) decompressSnappy() error {
+	bb := snappyBufPool.Get()
+	defer snappyBufPool.Put(bb)
+
+	if strings.HasPrefix(bytesutil.ToUnsafeString(wr.bb.B), snappyFramedStreamPrefix) {
+		sr := getSnappyReader(bytes.NewReader(wr.bb.B))
+		_, err := bb.ReadFrom(sr)
+		putSnappyReader(sr)
+		if err != nil {
+			return err
 		}
-		wr.baseLabels = appendAttributesToPromLabels(wr.baseLabels[:0], attributes)
-		for _, sc := range rm.ScopeMetrics {
-			wr.appendSamplesFromScopeMetrics(sc)
+	} else {
+		var err error
+		bb.B, err = snappy.Decode(bb.B[:cap(bb.B)], wr.bb.B)
+		if err != nil {
+			return err
 		}
 	}
+	wr.bb.B = append(wr.bb.B[:0], bb.B...)
+	return nil
 }
 
+var snappyBufPool bytesutil.ByteBufferPool
+
+func getSnappyReader(r io.Reader) *snappy.Reader {
+	v := snappyReaderPool.Get()
+	if v == nil {
+		return snappy.NewReader(r)
+	}
+	sr := v.(*snappy.Reader)
+	sr.Reset(r)
+	return sr
+}
+
+func putSnappyReader(sr *snappy.Reader) {
+	sr.Reset(nil)
+	snappyReaderPool.Put(sr)
+}
+
+var snappyReaderPool sync.Pool
+
 var wrPool sync.Pool
 
 func getWriteContext() *writeContext {
@@ -304,4 +673,20 @@ var (
 	rowsDroppedUnsupportedHistogram  = metrics.NewCounter(`vm_protoparser_rows_dropped_total{type="opentelemetry",reason="unsupported_histogram_aggregation"}`)
 	rowsDroppedUnsupportedSum        = metrics.NewCounter(`vm_protoparser_rows_dropped_total{type="opentelemetry",reason="unsupported_sum_aggregation"}`)
 	rowsDroppedUnsupportedMetricType = metrics.NewCounter(`vm_protoparser_rows_dropped_total{type="opentelemetry",reason="unsupported_metric_type"}`)
+	messagesSkipped                  = metrics.NewCounter(`vm_protoparser_messages_skipped_total{type="opentelemetry"}`)
+	dataPointsRejected               = metrics.NewCounter(`vm_protoparser_data_points_rejected_total{type="opentelemetry"}`)
+	requestsRejectedStrictValidation = metrics.NewCounter(`vm_protoparser_requests_rejected_total{type="opentelemetry",reason="strict_validation"}`)
 )
+
+// ProcessJSONRequestBody converts OTLP/JSON-encoded ExportMetricsServiceRequest at b into protobuf-encoded message.
+//
+// It can be passed as processBody to ParseStream for requests with `Content-Type: application/json`.
+//
+// See https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+func ProcessJSONRequestBody(b []byte) ([]byte, error) {
+	var req pb.ExportMetricsServiceRequest
+	if err := req.UnmarshalJSON(b); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal OTLP/JSON request: %w", err)
+	}
+	return req.MarshalProtobuf(nil), nil
+}
@@ -9,6 +9,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/golang/snappy"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding/zstd"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fasttime"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
@@ -70,6 +73,11 @@ func TestParseStream(t *testing.T) {
 		if err := checkParseStream(pbData, checkSeries); err != nil {
 			t.Fatalf("cannot parse protobuf: %s", err)
 		}
+
+		// Verify parsing of the decoded request
+		if _, err := ParseRequest(req, checkSeries); err != nil {
+			t.Fatalf("cannot parse decoded request: %s", err)
+		}
 	}
 
 	jobLabelValue := prompbmarshal.Label{
@@ -192,11 +200,76 @@ func TestParseStream(t *testing.T) {
 		},
 		true,
 	)
+
+	// Test exponential histogram
+	f(
+		[]*pb.Metric{
+			generateExponentialHistogram("my-exp-histogram", ""),
+		},
+		[]prompbmarshal.TimeSeries{
+			newPromPBTs("my-exp-histogram_count", 30000, 7.0, jobLabelValue, kvLabel("label3", "value3")),
+			newPromPBTs("my-exp-histogram_sum", 30000, 20.0, jobLabelValue, kvLabel("label3", "value3")),
+			newPromPBTs("my-exp-histogram_bucket", 30000, 1.0, jobLabelValue, kvLabel("label3", "value3"), kvLabel("vmrange", "0...1.000e-03")),
+			newPromPBTs("my-exp-histogram_bucket", 30000, 1.0, jobLabelValue, kvLabel("label3", "value3"), kvLabel("vmrange", "-2.000e+00...-1.000e+00")),
+			newPromPBTs("my-exp-histogram_bucket", 30000, 2.0, jobLabelValue, kvLabel("label3", "value3"), kvLabel("vmrange", "1.000e+00...2.000e+00")),
+			newPromPBTs("my-exp-histogram_bucket", 30000, 3.0, jobLabelValue, kvLabel("label3", "value3"), kvLabel("vmrange", "4.000e+00...8.000e+00")),
+		},
+		false,
+	)
+
+	// Test exponential histogram with non-zero scale and offset
+	f(
+		[]*pb.Metric{
+			{
+				Name: "my-exp-histogram",
+				ExponentialHistogram: &pb.ExponentialHistogram{
+					AggregationTemporality: pb.AggregationTemporalityCumulative,
+					DataPoints: []*pb.ExponentialHistogramDataPoint{
+						{
+							Count:        3,
+							Scale:        1,
+							TimeUnixNano: uint64(30 * time.Second),
+							Positive: &pb.Buckets{
+								Offset:       -2,
+								BucketCounts: []uint64{1, 2},
+							},
+						},
+					},
+				},
+			},
+		},
+		[]prompbmarshal.TimeSeries{
+			newPromPBTs("my-exp-histogram_count", 30000, 3.0, jobLabelValue),
+			newPromPBTs("my-exp-histogram_bucket", 30000, 1.0, jobLabelValue, kvLabel("vmrange", "5.000e-01...7.071e-01")),
+			newPromPBTs("my-exp-histogram_bucket", 30000, 2.0, jobLabelValue, kvLabel("vmrange", "7.071e-01...1.000e+00")),
+		},
+		false,
+	)
+
+	// Test exponential histogram with delta temporality
+	f(
+		[]*pb.Metric{
+			{
+				Name: "my-exp-histogram",
+				ExponentialHistogram: &pb.ExponentialHistogram{
+					AggregationTemporality: pb.AggregationTemporalityDelta,
+					DataPoints: []*pb.ExponentialHistogramDataPoint{
+						{
+							Count:        3,
+							TimeUnixNano: uint64(30 * time.Second),
+						},
+					},
+				},
+			},
+		},
+		nil,
+		false,
+	)
 }
 
 func checkParseStream(data []byte, checkSeries func(tss []prompbmarshal.TimeSeries) error) error {
 	// Verify parsing without compression
-	if err := ParseStream(bytes.NewBuffer(data), false, nil, checkSeries); err != nil {
+	if err := ParseStream(bytes.NewBuffer(data), "", nil, checkSeries); err != nil {
 		return fmt.Errorf("error when parsing data: %w", err)
 	}
 
@@ -209,13 +282,87 @@ func checkParseStream(data []byte, checkSeries func(tss []prompbmarshal.TimeSeri
 	if err := zw.Close(); err != nil {
 		return fmt.Errorf("cannot close gzip writer: %w", err)
 	}
-	if err := ParseStream(&bb, true, nil, checkSeries); err != nil {
+	if err := ParseStream(&bb, "gzip", nil, checkSeries); err != nil {
 		return fmt.Errorf("error when parsing compressed data: %w", err)
 	}
 
+	// Verify parsing with zstd compression
+	zstdData := zstd.CompressLevel(nil, data, 1)
+	if err := ParseStream(bytes.NewBuffer(zstdData), "zstd", nil, checkSeries); err != nil {
+		return fmt.Errorf("error when parsing zstd-compressed data: %w", err)
+	}
+
+	// Verify parsing with snappy block compression
+	snappyData := snappy.Encode(nil, data)
+	if err := ParseStream(bytes.NewBuffer(snappyData), "snappy", nil, checkSeries); err != nil {
+		return fmt.Errorf("error when parsing snappy-compressed data: %w", err)
+	}
+
+	// Verify parsing with snappy framed compression
+	bb.Reset()
+	sw := snappy.NewBufferedWriter(&bb)
+	if _, err := sw.Write(data); err != nil {
+		return fmt.Errorf("cannot compress data: %w", err)
+	}
+	if err := sw.Close(); err != nil {
+		return fmt.Errorf("cannot close snappy writer: %w", err)
+	}
+	if err := ParseStream(&bb, "snappy", nil, checkSeries); err != nil {
+		return fmt.Errorf("error when parsing snappy-framed data: %w", err)
+	}
+
 	return nil
 }
 
+func TestParseStreamUnsupportedContentEncoding(t *testing.T) {
+	err := ParseStream(bytes.NewBufferString("foobar"), "br", nil, func(_ []prompbmarshal.TimeSeries) error {
+		panic(fmt.Errorf("unexpected call"))
+	})
+	if err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+}
+
+func TestParseStreamStrictValidation(t *testing.T) {
+	*strictValidation = true
+	defer func() {
+		*strictValidation = false
+	}()
+
+	f := func(metrics []*pb.Metric, isErrorExpected bool) {
+		t.Helper()
+		req := &pb.ExportMetricsServiceRequest{
+			ResourceMetrics: []*pb.ResourceMetrics{generateOTLPSamples(metrics)},
+		}
+		calls := 0
+		err := ParseStream(bytes.NewBuffer(req.MarshalProtobuf(nil)), "", nil, func(_ []prompbmarshal.TimeSeries) error {
+			calls++
+			return nil
+		})
+		if isErrorExpected {
+			if err == nil {
+				t.Fatalf("expecting non-nil error")
+			}
+			if calls > 0 {
+				t.Fatalf("unexpected callback calls for invalid request: %d", calls)
+			}
+			return
+		}
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+
+	// valid request
+	f([]*pb.Metric{generateGauge("my-gauge", "")}, false)
+
+	// metric without name
+	f([]*pb.Metric{generateGauge("my-gauge", ""), generateGauge("", "")}, true)
+
+	// metric without data
+	f([]*pb.Metric{{Name: "foo"}}, true)
+}
+
 func attributesFromKV(k, v string) []*pb.KeyValue {
 	return []*pb.KeyValue{
 		{
@@ -267,6 +414,36 @@ func generateHistogram(name, unit string) *pb.Metric {
 	}
 }
 
+func generateExponentialHistogram(name, unit string) *pb.Metric {
+	points := []*pb.ExponentialHistogramDataPoint{
+		{
+			Attributes:    attributesFromKV("label3", "value3"),
+			Count:         7,
+			Sum:           func() *float64 { v := 20.0; return &v }(),
+			Scale:         0,
+			ZeroCount:     1,
+			ZeroThreshold: 0.001,
+			Positive: &pb.Buckets{
+				Offset:       0,
+				BucketCounts: []uint64{2, 0, 3},
+			},
+			Negative: &pb.Buckets{
+				Offset:       0,
+				BucketCounts: []uint64{1},
+			},
+			TimeUnixNano: uint64(30 * time.Second),
+		},
+	}
+	return &pb.Metric{
+		Name: name,
+		Unit: unit,
+		ExponentialHistogram: &pb.ExponentialHistogram{
+			AggregationTemporality: pb.AggregationTemporalityCumulative,
+			DataPoints:             points,
+		},
+	}
+}
+
 func generateSum(name, unit string, isMonotonic bool) *pb.Metric {
 	d := float64(15.5)
 	points := []*pb.NumberDataPoint{
@@ -384,3 +561,171 @@ func sortLabels(labels []prompbmarshal.Label) {
 		return labels[i].Name < labels[j].Name
 	})
 }
+
+func TestParseStreamJSON(t *testing.T) {
+	data := []byte(`{"resourceMetrics":[{
+  "resource":{"attributes":[{"key":"job","value":{"stringValue":"vm"}}]},
+  "scopeMetrics":[{"metrics":[
+    {"name":"my-gauge","gauge":{"dataPoints":[{"asInt":"15","timeUnixNano":"15000000000","attributes":[{"key":"label1","value":{"stringValue":"value1"}}]}]}},
+    {"name":"my-sum","sum":{"aggregationTemporality":2,"isMonotonic":true,"dataPoints":[{"asDouble":15.5,"timeUnixNano":"150000000000"}]}}
+  ]}]
+}]}`)
+	tssExpected := []prompbmarshal.TimeSeries{
+		newPromPBTs("my-gauge", 15000, 15.0, prompbmarshal.Label{Name: "job", Value: "vm"}, prompbmarshal.Label{Name: "label1", Value: "value1"}),
+		newPromPBTs("my-sum", 150000, 15.5, prompbmarshal.Label{Name: "job", Value: "vm"}),
+	}
+	err := ParseStream(bytes.NewBuffer(data), "", ProcessJSONRequestBody, func(tss []prompbmarshal.TimeSeries) error {
+		sortByMetricName(tss)
+		for i := range tss {
+			sortLabels(tss[i].Labels)
+		}
+		if !reflect.DeepEqual(tss, tssExpected) {
+			return fmt.Errorf("unexpected series\ngot\n%v\nwant\n%v", tss, tssExpected)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("cannot parse OTLP/JSON: %s", err)
+	}
+
+	// invalid json
+	err = ParseStream(bytes.NewBufferString(`{"resourceMetrics":{}}`), "", ProcessJSONRequestBody, func(_ []prompbmarshal.TimeSeries) error {
+		return nil
+	})
+	if err == nil {
+		t.Fatalf("expecting non-nil error for invalid OTLP/JSON")
+	}
+}
+
+func TestParseStreamBigRequest(t *testing.T) {
+	const metricsCount = 2*maxSamplesPerCallback + 123
+	metrics := make([]*pb.Metric, metricsCount)
+	for i := range metrics {
+		metrics[i] = generateGauge(fmt.Sprintf("metric_%d", i), "")
+	}
+	req := &pb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*pb.ResourceMetrics{
+			generateOTLPSamples(metrics),
+		},
+	}
+	data := req.MarshalProtobuf(nil)
+
+	calls := 0
+	samples := 0
+	err := ParseStream(bytes.NewBuffer(data), "", nil, func(tss []prompbmarshal.TimeSeries) error {
+		if len(tss) > maxSamplesPerCallback {
+			return fmt.Errorf("too many time series passed to callback; got %d; mustn't exceed %d", len(tss), maxSamplesPerCallback)
+		}
+		for _, ts := range tss {
+			if metricName := getMetricName(ts.Labels); metricName != fmt.Sprintf("metric_%d", samples) {
+				return fmt.Errorf("unexpected metric name; got %q; want %q", metricName, fmt.Sprintf("metric_%d", samples))
+			}
+			samples += len(ts.Samples)
+		}
+		calls++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("cannot parse request: %s", err)
+	}
+	if samples != metricsCount {
+		t.Fatalf("unexpected number of samples; got %d; want %d", samples, metricsCount)
+	}
+	if calls != 3 {
+		t.Fatalf("unexpected number of callback calls; got %d; want 3", calls)
+	}
+}
+
+func TestParseStreamBigRequestMalformedTail(t *testing.T) {
+	const metricsCount = 2*maxSamplesPerCallback + 123
+	metrics := make([]*pb.Metric, metricsCount)
+	for i := range metrics {
+		metrics[i] = generateGauge(fmt.Sprintf("metric_%d", i), "")
+	}
+	req := &pb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*pb.ResourceMetrics{
+			generateOTLPSamples(metrics),
+		},
+	}
+	data := req.MarshalProtobuf(nil)
+
+	// Append ResourceMetrics with a single Metric, which contains varint instead of string name.
+	data = append(data, 0x0a, 0x06, 0x12, 0x04, 0x12, 0x02, 0x08, 0x01)
+
+	f := func(lenient, strict bool) {
+		t.Helper()
+
+		lenientOrig, strictOrig := *lenientDecoding, *strictValidation
+		*lenientDecoding, *strictValidation = lenient, strict
+		defer func() {
+			*lenientDecoding, *strictValidation = lenientOrig, strictOrig
+		}()
+
+		calls := 0
+		err := ParseStream(bytes.NewBuffer(data), "", nil, func(_ []prompbmarshal.TimeSeries) error {
+			calls++
+			return nil
+		})
+		if err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+		if calls != 0 {
+			t.Fatalf("callback mustn't be called for malformed request; got %d calls", calls)
+		}
+	}
+
+	f(false, false)
+	f(false, true)
+	f(true, true)
+}
+
+func TestParseRequestBigRequest(t *testing.T) {
+	const metricsCount = 2*maxSamplesPerCallback + 123
+	metrics := make([]*pb.Metric, metricsCount)
+	for i := range metrics {
+		metrics[i] = generateGauge(fmt.Sprintf("metric_%d", i), "")
+	}
+	req := &pb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*pb.ResourceMetrics{
+			generateOTLPSamples(metrics),
+		},
+	}
+
+	calls := 0
+	samples := 0
+	_, err := ParseRequest(req, func(tss []prompbmarshal.TimeSeries) error {
+		if len(tss) > maxSamplesPerCallback {
+			return fmt.Errorf("too many time series passed to callback; got %d; mustn't exceed %d", len(tss), maxSamplesPerCallback)
+		}
+		for _, ts := range tss {
+			if metricName := getMetricName(ts.Labels); metricName != fmt.Sprintf("metric_%d", samples) {
+				return fmt.Errorf("unexpected metric name; got %q; want %q", metricName, fmt.Sprintf("metric_%d", samples))
+			}
+			samples += len(ts.Samples)
+		}
+		calls++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("cannot parse request: %s", err)
+	}
+	if samples != metricsCount {
+		t.Fatalf("unexpected number of samples; got %d; want %d", samples, metricsCount)
+	}
+	if calls != 3 {
+		t.Fatalf("unexpected number of callback calls; got %d; want 3", calls)
+	}
+
+	// callback error must stop the processing
+	calls = 0
+	_, err = ParseRequest(req, func(_ []prompbmarshal.TimeSeries) error {
+		calls++
+		return fmt.Errorf("some error")
+	})
+	if err == nil {
+		t.Fatalf("expecting non-nil error")
+	}
+	if calls != 1 {
+		t.Fatalf("unexpected number of callback calls; got %d; want 1", calls)
+	}
+}
@@ -0,0 +1,746 @@
+package prometheus
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/VictoriaMetrics/easyproto"
+)
+
+// ProtobufContentType is the Content-Type prefix for Prometheus protobuf exposition format.
+//
+// See https://github.com/prometheus/prometheus/blob/main/prompb/io/prometheus/client/metrics.proto
+const ProtobufContentType = "application/vnd.google.protobuf"
+
+// ProtobufAcceptHeader is the Accept header value for requesting Prometheus protobuf exposition format.
+const ProtobufAcceptHeader = ProtobufContentType + ";proto=io.prometheus.client.MetricFamily;encoding=delimited"
+
+// AppendTextFromProtobuf converts length-delimited Prometheus MetricFamily protobuf messages at src
+// to Prometheus text exposition format, appends the result to dst and returns it.
+//
+// Native histograms are converted to VictoriaMetrics histograms with `vmrange` buckets,
+// so they can be queried with histogram_quantile() and other histogram functions.
+// See https://valyala.medium.com/improving-histogram-usability-for-prometheus-and-grafana-bc7e5df0e350
+func AppendTextFromProtobuf(dst, src []byte) ([]byte, error) {
+	var mf metricFamily
+	for len(src) > 0 {
+		msgLen, tail, ok := easyproto.UnmarshalMessageLen(src)
+		if !ok {
+			return dst, fmt.Errorf("cannot read MetricFamily message length")
+		}
+		if msgLen > len(tail) {
+			return dst, fmt.Errorf("too short data for MetricFamily message; got %d bytes; want %d bytes", len(tail), msgLen)
+		}
+		if err := mf.unmarshalProtobuf(tail[:msgLen]); err != nil {
+			return dst, fmt.Errorf("cannot unmarshal MetricFamily: %w", err)
+		}
+		src = tail[msgLen:]
+		var err error
+		dst, err = mf.appendText(dst)
+		if err != nil {
+			return dst, fmt.Errorf("cannot convert MetricFamily %q: %w", mf.name, err)
+		}
+	}
+	return dst, nil
+}
+
+// MetricType values from metrics.proto
+const (
+	metricTypeCounter        = 0
+	metricTypeGauge          = 1
+	metricTypeSummary        = 2
+	metricTypeUntyped        = 3
+	metricTypeHistogram      = 4
+	metricTypeGaugeHistogram = 5
+)
+
+type metricFamily struct {
+	name    string
+	typ     int32
+	metrics [][]byte
+}
+
+func (mf *metricFamily) unmarshalProtobuf(src []byte) (err error) {
+	// message MetricFamily {
+	//   optional string     name   = 1;
+	//   optional string     help   = 2;
+	//   optional MetricType type   = 3;
+	//   repeated Metric     metric = 4;
+	//   optional string     unit   = 5;
+	// }
+	mf.name = ""
+	mf.typ = metricTypeUntyped
+	mf.metrics = mf.metrics[:0]
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in MetricFamily: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			name, ok := fc.String()
+			if !ok {
+				return fmt.Errorf("cannot read metric name")
+			}
+			mf.name = name
+		case 3:
+			typ, ok := fc.Int32()
+			if !ok {
+				return fmt.Errorf("cannot read metric type")
+			}
+			mf.typ = typ
+		case 4:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Metric data")
+			}
+			mf.metrics = append(mf.metrics, data)
+		}
+	}
+	return nil
+}
+
+func (mf *metricFamily) appendText(dst []byte) ([]byte, error) {
+	if mf.name == "" {
+		return dst, fmt.Errorf("metric name cannot be empty")
+	}
+	var m metric
+	for _, data := range mf.metrics {
+		if err := m.unmarshalProtobuf(data); err != nil {
+			return dst, fmt.Errorf("cannot unmarshal Metric: %w", err)
+		}
+		dst = m.appendText(dst, mf.name, mf.typ)
+	}
+	return dst, nil
+}
+
+type label struct {
+	name  string
+	value string
+}
+
+type metric struct {
+	labels      []label
+	value       float64
+	timestampMs int64
+
+	summary   summary
+	histogram histogram
+}
+
+func (m *metric) reset() {
+	m.labels = m.labels[:0]
+	m.value = 0
+	m.timestampMs = 0
+	m.summary.reset()
+	m.histogram.reset()
+}
+
+func (m *metric) unmarshalProtobuf(src []byte) (err error) {
+	// message Metric {
+	//   repeated LabelPair label        = 1;
+	//   optional Gauge     gauge        = 2;
+	//   optional Counter   counter      = 3;
+	//   optional Summary   summary      = 4;
+	//   optional Untyped   untyped      = 5;
+	//   optional Histogram histogram    = 7;
+	//   optional int64     timestamp_ms = 6;
+	// }
+	m.reset()
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in Metric: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read LabelPair data")
+			}
+			l, err := unmarshalLabelPair(data)
+			if err != nil {
+				return fmt.Errorf("cannot unmarshal LabelPair: %w", err)
+			}
+			m.labels = append(m.labels, l)
+		case 2, 3, 5:
+			// Gauge, Counter and Untyped messages contain the value at the field 1.
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read value data")
+			}
+			v, err := unmarshalValue(data)
+			if err != nil {
+				return fmt.Errorf("cannot unmarshal value: %w", err)
+			}
+			m.value = v
+		case 4:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Summary data")
+			}
+			if err := m.summary.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Summary: %w", err)
+			}
+		case 6:
+			ts, ok := fc.Int64()
+			if !ok {
+				return fmt.Errorf("cannot read timestamp_ms")
+			}
+			m.timestampMs = ts
+		case 7:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Histogram data")
+			}
+			if err := m.histogram.unmarshalProtobuf(data); err != nil {
+				return fmt.Errorf("cannot unmarshal Histogram: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func unmarshalLabelPair(src []byte) (l label, err error) {
+	// message LabelPair {
+	//   optional string name  = 1;
+	//   optional string value = 2;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return l, fmt.Errorf("cannot read next field in LabelPair: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			name, ok := fc.String()
+			if !ok {
+				return l, fmt.Errorf("cannot read label name")
+			}
+			l.name = name
+		case 2:
+			value, ok := fc.String()
+			if !ok {
+				return l, fmt.Errorf("cannot read label value")
+			}
+			l.value = value
+		}
+	}
+	return l, nil
+}
+
+func unmarshalValue(src []byte) (v float64, err error) {
+	// message Gauge {
+	//   optional double value = 1;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return 0, fmt.Errorf("cannot read next field: %w", err)
+		}
+		if fc.FieldNum == 1 {
+			f, ok := fc.Double()
+			if !ok {
+				return 0, fmt.Errorf("cannot read value")
+			}
+			v = f
+		}
+	}
+	return v, nil
+}
+
+func (m *metric) appendText(dst []byte, name string, typ int32) []byte {
+	switch typ {
+	case metricTypeSummary:
+		s := &m.summary
+		for _, q := range s.quantiles {
+			dst = m.appendSample(dst, name, "", "quantile", formatFloat(q.quantile), q.value)
+		}
+		dst = m.appendSample(dst, name, "_sum", "", "", s.sampleSum)
+		dst = m.appendSample(dst, name, "_count", "", "", float64(s.sampleCount))
+	case metricTypeHistogram, metricTypeGaugeHistogram:
+		h := &m.histogram
+		if h.isNative() {
+			dst = m.appendNativeHistogramBuckets(dst, name)
+		} else {
+			hasInf := false
+			for _, b := range h.buckets {
+				if math.IsInf(b.upperBound, 1) {
+					hasInf = true
+				}
+				dst = m.appendSample(dst, name, "_bucket", "le", formatFloat(b.upperBound), b.cumulativeCount)
+			}
+			if !hasInf {
+				dst = m.appendSample(dst, name, "_bucket", "le", "+Inf", h.getSampleCount())
+			}
+		}
+		dst = m.appendSample(dst, name, "_sum", "", "", h.sampleSum)
+		dst = m.appendSample(dst, name, "_count", "", "", h.getSampleCount())
+	default:
+		dst = m.appendSample(dst, name, "", "", "", m.value)
+	}
+	return dst
+}
+
+func (m *metric) appendNativeHistogramBuckets(dst []byte, name string) []byte {
+	h := &m.histogram
+	base := math.Pow(2, math.Pow(2, -float64(h.schema)))
+	zt := h.zeroThreshold
+
+	// Negative buckets are mirrored positive buckets. Emit them in ascending order of their bounds.
+	var buckets []nativeBucket
+	buckets = h.appendBuckets(buckets[:0], h.negativeSpans, h.negativeDeltas, h.negativeCounts)
+	for i := len(buckets) - 1; i >= 0; i-- {
+		b := buckets[i]
+		lower, upper := nativeBucketBounds(base, b.index, zt)
+		dst = m.appendSample(dst, name, "_bucket", "vmrange", formatVMRange(-upper, -lower), b.count)
+	}
+	if zc := h.getZeroCount(); zc > 0 {
+		dst = m.appendSample(dst, name, "_bucket", "vmrange", formatVMRange(-zt, zt), zc)
+	}
+	buckets = h.appendBuckets(buckets[:0], h.positiveSpans, h.positiveDeltas, h.positiveCounts)
+	for _, b := range buckets {
+		lower, upper := nativeBucketBounds(base, b.index, zt)
+		dst = m.appendSample(dst, name, "_bucket", "vmrange", formatVMRange(lower, upper), b.count)
+	}
+	return dst
+}
+
+// nativeBucketBounds returns bounds for the native histogram bucket with the given index.
+//
+// The bucket with the index i covers the range (base^(i-1) .. base^i]. The lower bound cannot be smaller than the zero threshold.
+func nativeBucketBounds(base float64, index int32, zeroThreshold float64) (float64, float64) {
+	lower := math.Pow(base, float64(index-1))
+	upper := math.Pow(base, float64(index))
+	if lower < zeroThreshold {
+		lower = zeroThreshold
+	}
+	return lower, upper
+}
+
+func formatVMRange(lower, upper float64) string {
+	return fmt.Sprintf("%.3e...%.3e", lower, upper)
+}
+
+func formatFloat(f float64) string {
+	if math.IsInf(f, 1) {
+		return "+Inf"
+	}
+	if math.IsInf(f, -1) {
+		return "-Inf"
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func (m *metric) appendSample(dst []byte, name, suffix, extraName, extraValue string, value float64) []byte {
+	dst = append(dst, name...)
+	dst = append(dst, suffix...)
+	if len(m.labels) > 0 || extraName != "" {
+		dst = append(dst, '{')
+		for i, l := range m.labels {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendLabel(dst, l.name, l.value)
+		}
+		if extraName != "" {
+			if len(m.labels) > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendLabel(dst, extraName, extraValue)
+		}
+		dst = append(dst, '}')
+	}
+	dst = append(dst, ' ')
+	dst = append(dst, formatFloat(value)...)
+	if m.timestampMs != 0 {
+		dst = append(dst, ' ')
+		dst = strconv.AppendInt(dst, m.timestampMs, 10)
+	}
+	return append(dst, '\n')
+}
+
+func appendLabel(dst []byte, name, value string) []byte {
+	dst = append(dst, name...)
+	dst = append(dst, `="`...)
+	dst = appendEscapedValue(dst, value)
+	return append(dst, '"')
+}
+
+type quantile struct {
+	quantile float64
+	value    float64
+}
+
+type summary struct {
+	sampleCount uint64
+	sampleSum   float64
+	quantiles   []quantile
+}
+
+func (s *summary) reset() {
+	s.sampleCount = 0
+	s.sampleSum = 0
+	s.quantiles = s.quantiles[:0]
+}
+
+func (s *summary) unmarshalProtobuf(src []byte) (err error) {
+	// message Summary {
+	//   optional uint64   sample_count = 1;
+	//   optional double   sample_sum   = 2;
+	//   repeated Quantile quantile     = 3;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in Summary: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			n, ok := fc.Uint64()
+			if !ok {
+				return fmt.Errorf("cannot read sample_count")
+			}
+			s.sampleCount = n
+		case 2:
+			v, ok := fc.Double()
+			if !ok {
+				return fmt.Errorf("cannot read sample_sum")
+			}
+			s.sampleSum = v
+		case 3:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Quantile data")
+			}
+			q, err := unmarshalQuantile(data)
+			if err != nil {
+				return fmt.Errorf("cannot unmarshal Quantile: %w", err)
+			}
+			s.quantiles = append(s.quantiles, q)
+		}
+	}
+	return nil
+}
+
+func unmarshalQuantile(src []byte) (q quantile, err error) {
+	// message Quantile {
+	//   optional double quantile = 1;
+	//   optional double value    = 2;
+	// }
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return q, fmt.Errorf("cannot read next field in Quantile: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			v, ok := fc.Double()
+			if !ok {
+				return q, fmt.Errorf("cannot read quantile")
+			}
+			q.quantile = v
+		case 2:
+			v, ok := fc.Double()
+			if !ok {
+				return q, fmt.Errorf("cannot read value")
+			}
+			q.value = v
+		}
+	}
+	return q, nil
+}
+
+type bucket struct {
+	cumulativeCount float64
+	upperBound      float64
+}
+
+type bucketSpan struct {
+	offset int32
+	length uint32
+}
+
+type nativeBucket struct {
+	index int32
+	count float64
+}
+
+type histogram struct {
+	sampleCount      uint64
+	sampleCountFloat float64
+	sampleSum        float64
+	buckets          []bucket
+
+	// Native histogram fields.
+	schema         int32
+	zeroThreshold  float64
+	zeroCount      uint64
+	zeroCountFloat float64
+	negativeSpans  []bucketSpan
+	negativeDeltas []int64
+	negativeCounts []float64
+	positiveSpans  []bucketSpan
+	positiveDeltas []int64
+	positiveCounts []float64
+}
+
+func (h *histogram) reset() {
+	h.sampleCount = 0
+	h.sampleCountFloat = 0
+	h.sampleSum = 0
+	h.buckets = h.buckets[:0]
+
+	h.schema = 0
+	h.zeroThreshold = 0
+	h.zeroCount = 0
+	h.zeroCountFloat = 0
+	h.negativeSpans = h.negativeSpans[:0]
+	h.negativeDeltas = h.negativeDeltas[:0]
+	h.negativeCounts = h.negativeCounts[:0]
+	h.positiveSpans = h.positiveSpans[:0]
+	h.positiveDeltas = h.positiveDeltas[:0]
+	h.positiveCounts = h.positiveCounts[:0]
+}
+
+// isNative returns true if h is a native histogram with exponential buckets.
+//
+// The check is performed in the same way as Prometheus does.
+// Native histograms take precedence over classic buckets if the target exposes both.
+func (h *histogram) isNative() bool {
+	if h.schema < -4 || h.schema > 8 {
+		// Custom buckets and unknown schemas aren't supported.
+		return false
+	}
+	return len(h.negativeSpans) > 0 || len(h.positiveSpans) > 0 || h.zeroThreshold > 0 || h.zeroCount > 0 || h.zeroCountFloat > 0
+}
+
+func (h *histogram) getSampleCount() float64 {
+	if h.sampleCountFloat > 0 {
+		return h.sampleCountFloat
+	}
+	return float64(h.sampleCount)
+}
+
+func (h *histogram) getZeroCount() float64 {
+	if h.zeroCountFloat > 0 {
+		return h.zeroCountFloat
+	}
+	return float64(h.zeroCount)
+}
+
+// appendBuckets appends non-empty native histogram buckets defined by spans to dst and returns the result.
+//
+// Bucket counts are taken from counts for float histograms and are delta-encoded in deltas for integer histograms.
+func (h *histogram) appendBuckets(dst []nativeBucket, spans []bucketSpan, deltas []int64, counts []float64) []nativeBucket {
+	idx := int32(0)
+	n := 0
+	count := int64(0)
+	for _, span := range spans {
+		idx += span.offset
+		for i := uint32(0); i < span.length; i++ {
+			var v float64
+			if n < len(counts) {
+				v = counts[n]
+			} else if n < len(deltas) {
+				count += deltas[n]
+				v = float64(count)
+			}
+			if v > 0 {
+				dst = append(dst, nativeBucket{
+					index: idx,
+					count: v,
+				})
+			}
+			idx++
+			n++
+		}
+	}
+	return dst
+}
+
+func (h *histogram) unmarshalProtobuf(src []byte) (err error) {
+	// message Histogram {
+	//   optional uint64     sample_count           = 1;
+	//   optional double     sample_count_float     = 4;
+	//   optional double     sample_sum             = 2;
+	//   repeated Bucket     bucket                 = 3;
+	//   optional sint32     schema                 = 5;
+	//   optional double     zero_threshold         = 6;
+	//   optional uint64     zero_count             = 7;
+	//   optional double     zero_count_float       = 8;
+	//   repeated BucketSpan negative_span          = 9;
+	//   repeated sint64     negative_delta         = 10;
+	//   repeated double     negative_count         = 11;
+	//   repeated BucketSpan positive_span          = 12;
+	//   repeated sint64     positive_delta         = 13;
+	//   repeated double     positive_count         = 14;
+	// }
+	var fc easyproto.FieldContext
+	var ok bool
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return fmt.Errorf("cannot read next field in Histogram: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			h.sampleCount, ok = fc.Uint64()
+			if !ok {
+				return fmt.Errorf("cannot read sample_count")
+			}
+		case 2:
+			h.sampleSum, ok = fc.Double()
+			if !ok {
+				return fmt.Errorf("cannot read sample_sum")
+			}
+		case 3:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read Bucket data")
+			}
+			b, err := unmarshalBucket(data)
+			if err != nil {
+				return fmt.Errorf("cannot unmarshal Bucket: %w", err)
+			}
+			h.buckets = append(h.buckets, b)
+		case 4:
+			h.sampleCountFloat, ok = fc.Double()
+			if !ok {
+				return fmt.Errorf("cannot read sample_count_float")
+			}
+		case 5:
+			h.schema, ok = fc.Sint32()
+			if !ok {
+				return fmt.Errorf("cannot read schema")
+			}
+		case 6:
+			h.zeroThreshold, ok = fc.Double()
+			if !ok {
+				return fmt.Errorf("cannot read zero_threshold")
+			}
+		case 7:
+			h.zeroCount, ok = fc.Uint64()
+			if !ok {
+				return fmt.Errorf("cannot read zero_count")
+			}
+		case 8:
+			h.zeroCountFloat, ok = fc.Double()
+			if !ok {
+				return fmt.Errorf("cannot read zero_count_float")
+			}
+		case 9, 12:
+			data, ok := fc.MessageData()
+			if !ok {
+				return fmt.Errorf("cannot read BucketSpan data")
+			}
+			span, err := unmarshalBucketSpan(data)
+			if err != nil {
+				return fmt.Errorf("cannot unmarshal BucketSpan: %w", err)
+			}
+			if fc.FieldNum == 9 {
+				h.negativeSpans = append(h.negativeSpans, span)
+			} else {
+				h.positiveSpans = append(h.positiveSpans, span)
+			}
+		case 10:
+			h.negativeDeltas, ok = fc.UnpackSint64s(h.negativeDeltas)
+			if !ok {
+				return fmt.Errorf("cannot read negative_delta")
+			}
+		case 11:
+			h.negativeCounts, ok = fc.UnpackDoubles(h.negativeCounts)
+			if !ok {
+				return fmt.Errorf("cannot read negative_count")
+			}
+		case 13:
+			h.positiveDeltas, ok = fc.UnpackSint64s(h.positiveDeltas)
+			if !ok {
+				return fmt.Errorf("cannot read positive_delta")
+			}
+		case 14:
+			h.positiveCounts, ok = fc.UnpackDoubles(h.positiveCounts)
+			if !ok {
+				return fmt.Errorf("cannot read positive_count")
+			}
+		}
+	}
+	return nil
+}
+
+func unmarshalBucket(src []byte) (b bucket, err error) {
+	// message Bucket {
+	//   optional uint64 cumulative_count       = 1;
+	//   optional double cumulative_count_float = 4;
+	//   optional double upper_bound            = 2;
+	// }
+	var cumulativeCount uint64
+	var cumulativeCountFloat float64
+	var fc easyproto.FieldContext
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return b, fmt.Errorf("cannot read next field in Bucket: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			n, ok := fc.Uint64()
+			if !ok {
+				return b, fmt.Errorf("cannot read cumulative_count")
+			}
+			cumulativeCount = n
+		case 2:
+			v, ok := fc.Double()
+			if !ok {
+				return b, fmt.Errorf("cannot read upper_bound")
+			}
+			b.upperBound = v
+		case 4:
+			v, ok := fc.Double()
+			if !ok {
+				return b, fmt.Errorf("cannot read cumulative_count_float")
+			}
+			cumulativeCountFloat = v
+		}
+	}
+	b.cumulativeCount = float64(cumulativeCount)
+	if cumulativeCountFloat > 0 {
+		b.cumulativeCount = cumulativeCountFloat
+	}
+	return b, nil
+}
+
+func unmarshalBucketSpan(src []byte) (span bucketSpan, err error) {
+	// message BucketSpan {
+	//   optional sint32 offset = 1;
+	//   optional uint32 length = 2;
+	// }
+	var fc easyproto.FieldContext
+	var ok bool
+	for len(src) > 0 {
+		src, err = fc.NextField(src)
+		if err != nil {
+			return span, fmt.Errorf("cannot read next field in BucketSpan: %w", err)
+		}
+		switch fc.FieldNum {
+		case 1:
+			span.offset, ok = fc.Sint32()
+			if !ok {
+				return span, fmt.Errorf("cannot read offset")
+			}
+		case 2:
+			span.length, ok = fc.Uint32()
+			if !ok {
+				return span, fmt.Errorf("cannot read length")
+			}
+		}
+	}
+	return span, nil
+}
@@ -0,0 +1,199 @@
+package prometheus
+
+import (
+	"math"
+	"testing"
+
+	"github.com/VictoriaMetrics/easyproto"
+)
+
+func TestAppendTextFromProtobufSuccess(t *testing.T) {
+	f := func(families []func(mm *easyproto.MessageMarshaler), resultExpected string) {
+		t.Helper()
+		var mp easyproto.MarshalerPool
+		var src []byte
+		for _, mf := range families {
+			m := mp.Get()
+			mf(m.MessageMarshaler())
+			src = m.MarshalWithLen(src)
+			mp.Put(m)
+		}
+		result, err := AppendTextFromProtobuf(nil, src)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if string(result) != resultExpected {
+			t.Fatalf("unexpected result\ngot\n%s\nwant\n%s", result, resultExpected)
+		}
+	}
+
+	// empty data
+	f(nil, "")
+
+	// counter, gauge and untyped
+	f([]func(mm *easyproto.MessageMarshaler){
+		func(mm *easyproto.MessageMarshaler) {
+			mm.AppendString(1, "http_requests_total")
+			mm.AppendString(2, "The number of requests")
+			mm.AppendInt32(3, metricTypeCounter)
+			m := mm.AppendMessage(4)
+			appendLabelPair(m, "path", "/foo")
+			appendLabelPair(m, "code", "200")
+			m.AppendMessage(3).AppendDouble(1, 123)
+			m = mm.AppendMessage(4)
+			appendLabelPair(m, "path", `a"b\c`)
+			m.AppendMessage(3).AppendDouble(1, 4.5)
+			m.AppendInt64(6, 1700000000123)
+		},
+		func(mm *easyproto.MessageMarshaler) {
+			mm.AppendString(1, "temperature")
+			mm.AppendInt32(3, metricTypeGauge)
+			mm.AppendMessage(4).AppendMessage(2).AppendDouble(1, -1.25)
+		},
+		func(mm *easyproto.MessageMarshaler) {
+			mm.AppendString(1, "foo")
+			mm.AppendInt32(3, metricTypeUntyped)
+			mm.AppendMessage(4).AppendMessage(5).AppendDouble(1, math.Inf(1))
+		},
+	}, `http_requests_total{path="/foo",code="200"} 123
+http_requests_total{path="a\"b\\c"} 4.5 1700000000123
+temperature -1.25
+foo +Inf
+`)
+
+	// summary
+	f([]func(mm *easyproto.MessageMarshaler){
+		func(mm *easyproto.MessageMarshaler) {
+			mm.AppendString(1, "rpc_duration_seconds")
+			mm.AppendInt32(3, metricTypeSummary)
+			m := mm.AppendMessage(4)
+			appendLabelPair(m, "service", "x")
+			s := m.AppendMessage(4)
+			s.AppendUint64(1, 10)
+			s.AppendDouble(2, 2.5)
+			q := s.AppendMessage(3)
+			q.AppendDouble(1, 0.5)
+			q.AppendDouble(2, 0.2)
+			q = s.AppendMessage(3)
+			q.AppendDouble(1, 0.99)
+			q.AppendDouble(2, 0.9)
+		},
+	}, `rpc_duration_seconds{service="x",quantile="0.5"} 0.2
+rpc_duration_seconds{service="x",quantile="0.99"} 0.9
+rpc_duration_seconds_sum{service="x"} 2.5
+rpc_duration_seconds_count{service="x"} 10
+`)
+
+	// classic histogram without +Inf bucket
+	f([]func(mm *easyproto.MessageMarshaler){
+		func(mm *easyproto.MessageMarshaler) {
+			mm.AppendString(1, "request_duration_seconds")
+			mm.AppendInt32(3, metricTypeHistogram)
+			h := mm.AppendMessage(4).AppendMessage(7)
+			h.AppendUint64(1, 5)
+			h.AppendDouble(2, 1.5)
+			b := h.AppendMessage(3)
+			b.AppendUint64(1, 2)
+			b.AppendDouble(2, 0.1)
+			b = h.AppendMessage(3)
+			b.AppendUint64(1, 4)
+			b.AppendDouble(2, 1)
+		},
+	}, `request_duration_seconds_bucket{le="0.1"} 2
+request_duration_seconds_bucket{le="1"} 4
+request_duration_seconds_bucket{le="+Inf"} 5
+request_duration_seconds_sum 1.5
+request_duration_seconds_count 5
+`)
+
+	// native histogram with integer counts
+	f([]func(mm *easyproto.MessageMarshaler){
+		func(mm *easyproto.MessageMarshaler) {
+			mm.AppendString(1, "latency_seconds")
+			mm.AppendInt32(3, metricTypeHistogram)
+			m := mm.AppendMessage(4)
+			appendLabelPair(m, "job", "a")
+			h := m.AppendMessage(7)
+			h.AppendUint64(1, 12)
+			h.AppendDouble(2, 10)
+			h.AppendSint32(5, 0)
+			h.AppendDouble(6, 0.001)
+			h.AppendUint64(7, 2)
+			// negative buckets: index 1 with count 1
+			s := h.AppendMessage(9)
+			s.AppendSint32(1, 1)
+			s.AppendUint32(2, 1)
+			h.AppendSint64s(10, []int64{1})
+			// positive buckets: indexes 0, 1 with counts 3, 4 and index 3 with count 2
+			s = h.AppendMessage(12)
+			s.AppendSint32(1, 0)
+			s.AppendUint32(2, 2)
+			s = h.AppendMessage(12)
+			s.AppendSint32(1, 1)
+			s.AppendUint32(2, 1)
+			h.AppendSint64s(13, []int64{3, 1, -2})
+			// classic buckets must be ignored
+			b := h.AppendMessage(3)
+			b.AppendUint64(1, 12)
+			b.AppendDouble(2, 100)
+		},
+	}, `latency_seconds_bucket{job="a",vmrange="-2.000e+00...-1.000e+00"} 1
+latency_seconds_bucket{job="a",vmrange="-1.000e-03...1.000e-03"} 2
+latency_seconds_bucket{job="a",vmrange="5.000e-01...1.000e+00"} 3
+latency_seconds_bucket{job="a",vmrange="1.000e+00...2.000e+00"} 4
+latency_seconds_bucket{job="a",vmrange="4.000e+00...8.000e+00"} 2
+latency_seconds_sum{job="a"} 10
+latency_seconds_count{job="a"} 12
+`)
+
+	// native float histogram with schema 1
+	f([]func(mm *easyproto.MessageMarshaler){
+		func(mm *easyproto.MessageMarshaler) {
+			mm.AppendString(1, "x")
+			mm.AppendInt32(3, metricTypeGaugeHistogram)
+			h := mm.AppendMessage(4).AppendMessage(7)
+			h.AppendDouble(4, 2.5)
+			h.AppendDouble(2, 3)
+			h.AppendSint32(5, 1)
+			s := h.AppendMessage(12)
+			s.AppendSint32(1, 2)
+			s.AppendUint32(2, 2)
+			h.AppendDoubles(14, []float64{1.5, 1})
+		},
+	}, `x_bucket{vmrange="1.414e+00...2.000e+00"} 1.5
+x_bucket{vmrange="2.000e+00...2.828e+00"} 1
+x_sum 3
+x_count 2.5
+`)
+}
+
+func TestAppendTextFromProtobufFailure(t *testing.T) {
+	f := func(src []byte) {
+		t.Helper()
+		_, err := AppendTextFromProtobuf(nil, src)
+		if err == nil {
+			t.Fatalf("expecting non-nil error")
+		}
+	}
+
+	// missing message length
+	f([]byte{0x80})
+
+	// too short message
+	f([]byte{10, 1, 2})
+
+	// missing metric name
+	var mp easyproto.MarshalerPool
+	m := mp.Get()
+	mm := m.MessageMarshaler()
+	mm.AppendInt32(3, metricTypeGauge)
+	mm.AppendMessage(4).AppendMessage(2).AppendDouble(1, 1)
+	f(m.MarshalWithLen(nil))
+	mp.Put(m)
+}
+
+func appendLabelPair(mm *easyproto.MessageMarshaler, name, value string) {
+	lp := mm.AppendMessage(1)
+	lp.AppendString(1, name)
+	lp.AppendString(2, value)
+}
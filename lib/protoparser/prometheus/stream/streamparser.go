@@ -6,6 +6,7 @@ import (
 	"io"
 	"sync"
 	"time"
+	"unsafe"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/common"
@@ -192,8 +193,10 @@ func (uw *unmarshalWork) Unmarshal() {
 func getUnmarshalWork() *unmarshalWork {
 	v := unmarshalWorkPool.Get()
 	if v == nil {
+		unmarshalWorkPoolStats.Update(int(unsafe.Sizeof(unmarshalWork{})))
 		return &unmarshalWork{}
 	}
+	unmarshalWorkPoolStats.Update(0)
 	return v.(*unmarshalWork)
 }
 
@@ -203,3 +206,5 @@ func putUnmarshalWork(uw *unmarshalWork) {
 }
 
 var unmarshalWorkPool sync.Pool
+
+var unmarshalWorkPoolStats = bytesutil.NewPoolStats("prometheus_unmarshal_work")
@@ -0,0 +1,281 @@
+package statsd
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+	"github.com/valyala/histogram"
+)
+
+// timerQuantiles contains quantiles, which are calculated for timers, histograms and distributions.
+var timerQuantiles = []float64{0.5, 0.9, 0.99}
+
+// gaugeStaleFlushes is the number of flush intervals without updates, after which the gauge value is forgotten.
+//
+// Gauge values are preserved between flushes, so relative gauge updates such as `metric:+1|g` work as expected.
+const gaugeStaleFlushes = 10
+
+// Aggregator aggregates StatsD rows on the client side per every flush interval.
+//
+// The aggregated samples are passed to pushFunc in the end of every flush interval:
+//
+//   - counters are converted to the sum of the values received during the interval, which are scaled by sample rate;
+//   - gauges are converted to the last value received during the interval;
+//   - timers, histograms and distributions are converted to <metric>_count, <metric>_sum, <metric>_min, <metric>_max
+//     and <metric>{quantile="..."} samples over the values received during the interval;
+//   - sets are converted to the number of unique values received during the interval.
+type Aggregator struct {
+	flushInterval time.Duration
+	pushFunc      func(tss []prompbmarshal.TimeSeries)
+
+	mu     sync.Mutex
+	m      map[string]*aggrState
+	keyBuf []byte
+	tags   []Tag
+
+	stopCh chan struct{}
+	wg     sync.WaitGroup
+}
+
+type aggrState struct {
+	metric string
+	typ    string
+	labels []prompbmarshal.Label
+
+	// updated is set if the state has been updated during the current flush interval.
+	updated bool
+
+	// staleFlushes is the number of flush intervals without updates for gauges.
+	staleFlushes int
+
+	// value is used by counters and gauges.
+	value float64
+
+	// count, sum, min, max and h are used by timers, histograms and distributions.
+	count float64
+	sum   float64
+	min   float64
+	max   float64
+	h     *histogram.Fast
+
+	// set is used by sets.
+	set map[string]struct{}
+}
+
+// NewAggregator returns new Aggregator, which passes aggregated samples to pushFunc every flushInterval.
+//
+// pushFunc mustn't hold references to tss after returning.
+//
+// MustStop must be called when the returned Aggregator is no longer needed.
+func NewAggregator(flushInterval time.Duration, pushFunc func(tss []prompbmarshal.TimeSeries)) *Aggregator {
+	a := &Aggregator{
+		flushInterval: flushInterval,
+		pushFunc:      pushFunc,
+		m:             make(map[string]*aggrState),
+		stopCh:        make(chan struct{}),
+	}
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		a.runFlusher()
+	}()
+	return a
+}
+
+// MustStop stops a and flushes the remaining aggregated samples to pushFunc.
+func (a *Aggregator) MustStop() {
+	close(a.stopCh)
+	a.wg.Wait()
+	a.flush(time.Now().UnixMilli())
+}
+
+func (a *Aggregator) runFlusher() {
+	t := time.NewTicker(a.flushInterval)
+	defer t.Stop()
+	for {
+		select {
+		case <-a.stopCh:
+			return
+		case <-t.C:
+			a.flush(time.Now().UnixMilli())
+		}
+	}
+}
+
+// Push adds rows to a.
+func (a *Aggregator) Push(rows []Row) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for i := range rows {
+		r := &rows[i]
+		a.keyBuf, a.tags = marshalKey(a.keyBuf[:0], a.tags[:0], r)
+		as := a.m[string(a.keyBuf)]
+		if as == nil {
+			as = newAggrState(r, a.tags)
+			a.m[string(a.keyBuf)] = as
+		}
+		as.update(r)
+	}
+}
+
+func marshalKey(dst []byte, tags []Tag, r *Row) ([]byte, []Tag) {
+	tags = append(tags, r.Tags...)
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Key < tags[j].Key
+	})
+	dst = append(dst, r.Metric...)
+	dst = append(dst, 0)
+	dst = append(dst, r.Type...)
+	for _, tag := range tags {
+		dst = append(dst, 0)
+		dst = append(dst, tag.Key...)
+		dst = append(dst, 0)
+		dst = append(dst, tag.Value...)
+	}
+	return dst, tags
+}
+
+func newAggrState(r *Row, sortedTags []Tag) *aggrState {
+	// Copy metric name and tags, since they may refer to the buffer, which is re-used after returning from Push.
+	labels := make([]prompbmarshal.Label, 0, len(sortedTags))
+	for _, tag := range sortedTags {
+		labels = append(labels, prompbmarshal.Label{
+			Name:  strings.Clone(tag.Key),
+			Value: strings.Clone(tag.Value),
+		})
+	}
+	as := &aggrState{
+		metric: strings.Clone(r.Metric),
+		typ:    r.Type,
+		labels: labels,
+	}
+	as.resetInterval()
+	return as
+}
+
+func (as *aggrState) resetInterval() {
+	as.updated = false
+	as.count = 0
+	as.sum = 0
+	as.min = math.Inf(1)
+	as.max = math.Inf(-1)
+	if as.h != nil {
+		as.h.Reset()
+	}
+	as.set = nil
+	if as.typ == TypeCounter {
+		as.value = 0
+	}
+}
+
+func (as *aggrState) update(r *Row) {
+	as.updated = true
+	as.staleFlushes = 0
+	switch as.typ {
+	case TypeCounter:
+		as.value += r.Value / r.SampleRate
+	case TypeGauge:
+		if r.IsGaugeDelta {
+			as.value += r.Value
+		} else {
+			as.value = r.Value
+		}
+	case TypeTimer, TypeHistogram, TypeDistribution:
+		as.count += 1 / r.SampleRate
+		as.sum += r.Value / r.SampleRate
+		if r.Value < as.min {
+			as.min = r.Value
+		}
+		if r.Value > as.max {
+			as.max = r.Value
+		}
+		if as.h == nil {
+			as.h = histogram.GetFast()
+		}
+		as.h.Update(r.Value)
+	case TypeSet:
+		if as.set == nil {
+			as.set = make(map[string]struct{})
+		}
+		if _, ok := as.set[r.SetValue]; !ok {
+			// Copy the value, since it may refer to the buffer, which is re-used after returning from Push.
+			as.set[strings.Clone(r.SetValue)] = struct{}{}
+		}
+	}
+}
+
+func (a *Aggregator) flush(timestamp int64) {
+	a.mu.Lock()
+	var tss []prompbmarshal.TimeSeries
+	for k, as := range a.m {
+		if as.updated {
+			tss = as.appendTimeSeries(tss, timestamp)
+		}
+		if as.typ == TypeGauge {
+			if !as.updated {
+				as.staleFlushes++
+			}
+			if as.staleFlushes < gaugeStaleFlushes {
+				as.resetInterval()
+				continue
+			}
+		}
+		if as.h != nil {
+			histogram.PutFast(as.h)
+			as.h = nil
+		}
+		delete(a.m, k)
+	}
+	a.mu.Unlock()
+
+	if len(tss) > 0 {
+		a.pushFunc(tss)
+	}
+}
+
+func (as *aggrState) appendTimeSeries(dst []prompbmarshal.TimeSeries, timestamp int64) []prompbmarshal.TimeSeries {
+	switch as.typ {
+	case TypeCounter, TypeGauge:
+		dst = appendTimeSeries(dst, as.metric, as.labels, "", "", as.value, timestamp)
+	case TypeTimer, TypeHistogram, TypeDistribution:
+		dst = appendTimeSeries(dst, as.metric+"_count", as.labels, "", "", as.count, timestamp)
+		dst = appendTimeSeries(dst, as.metric+"_sum", as.labels, "", "", as.sum, timestamp)
+		dst = appendTimeSeries(dst, as.metric+"_min", as.labels, "", "", as.min, timestamp)
+		dst = appendTimeSeries(dst, as.metric+"_max", as.labels, "", "", as.max, timestamp)
+		for _, phi := range timerQuantiles {
+			q := as.h.Quantile(phi)
+			dst = appendTimeSeries(dst, as.metric, as.labels, "quantile", strconv.FormatFloat(phi, 'g', -1, 64), q, timestamp)
+		}
+	case TypeSet:
+		dst = appendTimeSeries(dst, as.metric, as.labels, "", "", float64(len(as.set)), timestamp)
+	}
+	return dst
+}
+
+func appendTimeSeries(dst []prompbmarshal.TimeSeries, metric string, labels []prompbmarshal.Label, extraName, extraValue string, value float64, timestamp int64) []prompbmarshal.TimeSeries {
+	ls := make([]prompbmarshal.Label, 0, len(labels)+2)
+	ls = append(ls, prompbmarshal.Label{
+		Name:  "__name__",
+		Value: metric,
+	})
+	ls = append(ls, labels...)
+	if extraName != "" {
+		ls = append(ls, prompbmarshal.Label{
+			Name:  extraName,
+			Value: extraValue,
+		})
+	}
+	return append(dst, prompbmarshal.TimeSeries{
+		Labels: ls,
+		Samples: []prompbmarshal.Sample{{
+			Value:     value,
+			Timestamp: timestamp,
+		}},
+	})
+}
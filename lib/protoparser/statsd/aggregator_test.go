@@ -0,0 +1,137 @@
+package statsd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
+)
+
+func TestAggregator(t *testing.T) {
+	f := func(inputs []string, outputsExpected []string) {
+		t.Helper()
+
+		var outputsLock sync.Mutex
+		var outputs []string
+		pushFunc := func(tss []prompbmarshal.TimeSeries) {
+			outputsLock.Lock()
+			outputs = append(outputs, timeSeriesToString(tss))
+			outputsLock.Unlock()
+		}
+		a := NewAggregator(time.Hour, pushFunc)
+		for i, input := range inputs {
+			var rows Rows
+			rows.Unmarshal(input)
+			a.Push(rows.Rows)
+			a.flush(int64(i+1) * 1000)
+		}
+		a.MustStop()
+
+		// Drop the output from MustStop, since it contains the current timestamp.
+		if len(outputs) > len(inputs) {
+			outputs = outputs[:len(inputs)]
+		}
+		if strings.Join(outputs, "---\n") != strings.Join(outputsExpected, "---\n") {
+			t.Fatalf("unexpected outputs;\ngot\n%s\nwant\n%s", strings.Join(outputs, "---\n"), strings.Join(outputsExpected, "---\n"))
+		}
+	}
+
+	// Counters are summed and scaled by sample rate
+	f([]string{`
+foo:1|c
+foo:2|c|#env:prod
+foo:3|c|@0.5
+bar;env=dev:4|c
+`, `
+foo:5|c
+`}, []string{
+		`bar{env="dev"} 4 1000
+foo 7 1000
+foo{env="prod"} 2 1000
+`, `foo 5 2000
+`,
+	})
+
+	// Tags order doesn't matter
+	f([]string{`
+foo:1|c|#a:1,b:2
+foo:2|c|#b:2,a:1
+`}, []string{
+		`foo{a="1",b="2"} 3 1000
+`,
+	})
+
+	// Gauges keep the last value, while relative updates are applied to the previous value
+	f([]string{`
+foo:1|g
+foo:5|g
+bar:10|g
+bar:-3|g
+`, `
+bar:+1|g
+`, ``, `
+bar:-2|g
+`}, []string{
+		`bar 7 1000
+foo 5 1000
+`, `bar 8 2000
+`, `bar 6 4000
+`,
+	})
+
+	// Timers
+	f([]string{`
+foo:10|ms
+foo:20:30|ms|@0.5
+`}, []string{
+		`foo_count 5 1000
+foo_max 30 1000
+foo_min 10 1000
+foo_sum 110 1000
+foo{quantile="0.5"} 20 1000
+foo{quantile="0.9"} 30 1000
+foo{quantile="0.99"} 30 1000
+`,
+	})
+
+	// Sets
+	f([]string{`
+foo:a|s
+foo:b|s
+foo:a|s
+`, `
+foo:c|s
+`}, []string{
+		`foo 2 1000
+`, `foo 1 2000
+`,
+	})
+}
+
+func timeSeriesToString(tss []prompbmarshal.TimeSeries) string {
+	var lines []string
+	for _, ts := range tss {
+		var name string
+		var labels []string
+		for _, label := range ts.Labels {
+			if label.Name == "__name__" {
+				name = label.Value
+				continue
+			}
+			labels = append(labels, fmt.Sprintf("%s=%q", label.Name, label.Value))
+		}
+		labelsStr := ""
+		if len(labels) > 0 {
+			labelsStr = "{" + strings.Join(labels, ",") + "}"
+		}
+		for _, s := range ts.Samples {
+			lines = append(lines, fmt.Sprintf("%s%s %g %d\n", name, labelsStr, s.Value, s.Timestamp))
+		}
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, "")
+}
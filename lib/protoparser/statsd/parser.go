@@ -0,0 +1,282 @@
+package statsd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/metrics"
+	"github.com/valyala/fastjson/fastfloat"
+)
+
+// Metric types supported by StatsD protocol.
+//
+// See https://github.com/statsd/statsd/blob/master/docs/metric_types.md
+const (
+	TypeCounter      = "c"
+	TypeGauge        = "g"
+	TypeTimer        = "ms"
+	TypeHistogram    = "h"
+	TypeDistribution = "d"
+	TypeSet          = "s"
+)
+
+// Rows contains parsed StatsD rows.
+type Rows struct {
+	Rows []Row
+
+	tagsPool []Tag
+}
+
+// Reset resets rs.
+func (rs *Rows) Reset() {
+	// Reset items, so they can be GC'ed
+
+	for i := range rs.Rows {
+		rs.Rows[i].reset()
+	}
+	rs.Rows = rs.Rows[:0]
+
+	for i := range rs.tagsPool {
+		rs.tagsPool[i].reset()
+	}
+	rs.tagsPool = rs.tagsPool[:0]
+}
+
+// Unmarshal unmarshals StatsD rows from s.
+//
+// Every line may contain multiple values delimited by ':'. Every such value results in a separate row.
+//
+// See https://github.com/statsd/statsd/blob/master/docs/metric_types.md
+// and https://docs.datadoghq.com/developers/dogstatsd/datagram_shell/
+//
+// s shouldn't be modified when rs is in use.
+func (rs *Rows) Unmarshal(s string) {
+	rs.Rows, rs.tagsPool = unmarshalRows(rs.Rows[:0], s, rs.tagsPool[:0])
+}
+
+// Row is a single StatsD row.
+type Row struct {
+	Metric string
+	Tags   []Tag
+
+	// Type is the metric type. See Type* constants.
+	Type string
+
+	// Value is the metric value. It is unset for TypeSet.
+	Value float64
+
+	// SetValue is the value for TypeSet.
+	SetValue string
+
+	// IsGaugeDelta is set for TypeGauge values with explicit sign, which must be added to the current gauge value.
+	IsGaugeDelta bool
+
+	// SampleRate is the sample rate in the range (0..1] the value was sent with.
+	SampleRate float64
+}
+
+func (r *Row) reset() {
+	r.Metric = ""
+	r.Tags = nil
+	r.Type = ""
+	r.Value = 0
+	r.SetValue = ""
+	r.IsGaugeDelta = false
+	r.SampleRate = 0
+}
+
+func unmarshalRows(dst []Row, s string, tagsPool []Tag) ([]Row, []Tag) {
+	for len(s) > 0 {
+		n := strings.IndexByte(s, '\n')
+		if n < 0 {
+			// The last line.
+			return unmarshalRow(dst, s, tagsPool)
+		}
+		dst, tagsPool = unmarshalRow(dst, s[:n], tagsPool)
+		s = s[n+1:]
+	}
+	return dst, tagsPool
+}
+
+func unmarshalRow(dst []Row, s string, tagsPool []Tag) ([]Row, []Tag) {
+	if len(s) > 0 && s[len(s)-1] == '\r' {
+		s = s[:len(s)-1]
+	}
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		// Skip empty line
+		return dst, tagsPool
+	}
+	dstLen := len(dst)
+	tagsPoolLen := len(tagsPool)
+	var err error
+	dst, tagsPool, err = appendRowsFromLine(dst, s, tagsPool)
+	if err != nil {
+		dst = dst[:dstLen]
+		tagsPool = tagsPool[:tagsPoolLen]
+		logger.Errorf("cannot unmarshal StatsD line %q: %s", s, err)
+		invalidLines.Inc()
+	}
+	return dst, tagsPool
+}
+
+var invalidLines = metrics.NewCounter(`vm_rows_invalid_total{type="statsd"}`)
+
+func appendRowsFromLine(dst []Row, s string, tagsPool []Tag) ([]Row, []Tag, error) {
+	n := strings.IndexByte(s, '|')
+	if n < 0 {
+		return dst, tagsPool, fmt.Errorf("cannot find metric type")
+	}
+	metricAndValues := s[:n]
+	s = s[n+1:]
+
+	n = strings.IndexByte(metricAndValues, ':')
+	if n < 0 {
+		return dst, tagsPool, fmt.Errorf("cannot find value for the metric %q", metricAndValues)
+	}
+	metricAndTags := metricAndValues[:n]
+	values := metricAndValues[n+1:]
+
+	// Parse metric name and optional Graphite-style tags such as `metric;tag1=value1;tag2=value2`
+	metric := metricAndTags
+	tagsStart := len(tagsPool)
+	if n := strings.IndexByte(metricAndTags, ';'); n >= 0 {
+		metric = metricAndTags[:n]
+		tagsPool = unmarshalTags(tagsPool, metricAndTags[n+1:], ';', '=')
+	}
+	if len(metric) == 0 {
+		return dst, tagsPool, fmt.Errorf("metric name cannot be empty")
+	}
+
+	// Parse metric type and optional fields such as sample rate and DogStatsD tags.
+	typ := s
+	s = ""
+	if n := strings.IndexByte(typ, '|'); n >= 0 {
+		s = typ[n+1:]
+		typ = typ[:n]
+	}
+	typ, err := getMetricType(typ)
+	if err != nil {
+		return dst, tagsPool, err
+	}
+	sampleRate := float64(1)
+	for len(s) > 0 {
+		field := s
+		s = ""
+		if n := strings.IndexByte(field, '|'); n >= 0 {
+			s = field[n+1:]
+			field = field[:n]
+		}
+		switch {
+		case strings.HasPrefix(field, "@"):
+			sr, err := fastfloat.Parse(field[1:])
+			if err != nil {
+				return dst, tagsPool, fmt.Errorf("cannot parse sample rate %q: %w", field[1:], err)
+			}
+			if sr <= 0 || sr > 1 {
+				return dst, tagsPool, fmt.Errorf("sample rate must be in the range (0..1]; got %g", sr)
+			}
+			sampleRate = sr
+		case strings.HasPrefix(field, "#"):
+			tagsPool = unmarshalTags(tagsPool, field[1:], ',', ':')
+		default:
+			// Ignore unknown fields such as DogStatsD timestamps and container ids,
+			// so newer clients may send data to VictoriaMetrics.
+		}
+	}
+	var tags []Tag
+	if len(tagsPool) > tagsStart {
+		tags = tagsPool[tagsStart:]
+		tags = tags[:len(tags):len(tags)]
+	}
+
+	for {
+		v := values
+		values = ""
+		if n := strings.IndexByte(v, ':'); n >= 0 {
+			values = v[n+1:]
+			v = v[:n]
+		}
+		if cap(dst) > len(dst) {
+			dst = dst[:len(dst)+1]
+		} else {
+			dst = append(dst, Row{})
+		}
+		r := &dst[len(dst)-1]
+		r.Metric = metric
+		r.Tags = tags
+		r.Type = typ
+		r.SampleRate = sampleRate
+		if typ == TypeSet {
+			if len(v) == 0 {
+				return dst, tagsPool, fmt.Errorf("set value cannot be empty")
+			}
+			r.SetValue = v
+		} else {
+			r.IsGaugeDelta = typ == TypeGauge && (strings.HasPrefix(v, "+") || strings.HasPrefix(v, "-"))
+			f, err := fastfloat.Parse(strings.TrimPrefix(v, "+"))
+			if err != nil {
+				return dst, tagsPool, fmt.Errorf("cannot parse value %q: %w", v, err)
+			}
+			r.Value = f
+		}
+		if len(values) == 0 {
+			return dst, tagsPool, nil
+		}
+	}
+}
+
+// getMetricType returns the Type* constant for s.
+//
+// The constant is returned instead of s, since s may refer to the buffer, which is re-used for parsing the next data.
+func getMetricType(s string) (string, error) {
+	switch s {
+	case TypeCounter:
+		return TypeCounter, nil
+	case TypeGauge:
+		return TypeGauge, nil
+	case TypeTimer:
+		return TypeTimer, nil
+	case TypeHistogram:
+		return TypeHistogram, nil
+	case TypeDistribution:
+		return TypeDistribution, nil
+	case TypeSet:
+		return TypeSet, nil
+	default:
+		return "", fmt.Errorf("unsupported metric type %q; supported types: c, g, ms, h, d, s", s)
+	}
+}
+
+func unmarshalTags(dst []Tag, s string, tagsDelimiter, kvDelimiter byte) []Tag {
+	for len(s) > 0 {
+		tag := s
+		s = ""
+		if n := strings.IndexByte(tag, tagsDelimiter); n >= 0 {
+			s = tag[n+1:]
+			tag = tag[:n]
+		}
+		n := strings.IndexByte(tag, kvDelimiter)
+		if n <= 0 || n == len(tag)-1 {
+			// Skip tags with empty key or empty value, since they cannot be stored as labels.
+			continue
+		}
+		dst = append(dst, Tag{
+			Key:   tag[:n],
+			Value: tag[n+1:],
+		})
+	}
+	return dst
+}
+
+// Tag is a StatsD tag.
+type Tag struct {
+	Key   string
+	Value string
+}
+
+func (t *Tag) reset() {
+	t.Key = ""
+	t.Value = ""
+}
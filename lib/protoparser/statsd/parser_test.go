@@ -0,0 +1,204 @@
+package statsd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRowsUnmarshalFailure(t *testing.T) {
+	f := func(s string) {
+		t.Helper()
+		var rows Rows
+		rows.Unmarshal(s)
+		if len(rows.Rows) != 0 {
+			t.Fatalf("expecting zero rows; got %d rows", len(rows.Rows))
+		}
+
+		// Try again
+		rows.Unmarshal(s)
+		if len(rows.Rows) != 0 {
+			t.Fatalf("expecting zero rows; got %d rows", len(rows.Rows))
+		}
+	}
+
+	// missing type
+	f("foo:1")
+
+	// missing value
+	f("foo|c")
+	f("foo:|c")
+
+	// empty metric name
+	f(":1|c")
+	f(";bar=baz:1|c")
+
+	// unsupported type
+	f("foo:1|x")
+	f("foo:1|")
+
+	// invalid value
+	f("foo:bar|c")
+	f("foo:1:bar|ms")
+
+	// empty set value
+	f("foo:|s")
+
+	// invalid sample rate
+	f("foo:1|c|@")
+	f("foo:1|c|@bar")
+	f("foo:1|c|@0")
+	f("foo:1|c|@1.5")
+}
+
+func TestRowsUnmarshalSuccess(t *testing.T) {
+	f := func(s string, rowsExpected []Row) {
+		t.Helper()
+		var rows Rows
+		rows.Unmarshal(s)
+		if !reflect.DeepEqual(rows.Rows, rowsExpected) {
+			t.Fatalf("unexpected rows;\ngot\n%+v\nwant\n%+v", rows.Rows, rowsExpected)
+		}
+
+		// Try unmarshaling again
+		rows.Unmarshal(s)
+		if !reflect.DeepEqual(rows.Rows, rowsExpected) {
+			t.Fatalf("unexpected rows on the second unmarshal;\ngot\n%+v\nwant\n%+v", rows.Rows, rowsExpected)
+		}
+
+		rows.Reset()
+		if len(rows.Rows) != 0 {
+			t.Fatalf("non-empty rows after reset: %+v", rows.Rows)
+		}
+	}
+
+	// Empty line
+	f("", nil)
+	f("\r", nil)
+	f("\n\n", nil)
+
+	// Counter
+	f("foo.bar:123|c", []Row{{
+		Metric:     "foo.bar",
+		Type:       TypeCounter,
+		Value:      123,
+		SampleRate: 1,
+	}})
+
+	// Counter with sample rate
+	f("foo:2|c|@0.1", []Row{{
+		Metric:     "foo",
+		Type:       TypeCounter,
+		Value:      2,
+		SampleRate: 0.1,
+	}})
+
+	// Gauges
+	f("foo:-1.5|g\nbar:+2|g\r\nbaz:3|g", []Row{
+		{
+			Metric:       "foo",
+			Type:         TypeGauge,
+			Value:        -1.5,
+			IsGaugeDelta: true,
+			SampleRate:   1,
+		},
+		{
+			Metric:       "bar",
+			Type:         TypeGauge,
+			Value:        2,
+			IsGaugeDelta: true,
+			SampleRate:   1,
+		},
+		{
+			Metric:     "baz",
+			Type:       TypeGauge,
+			Value:      3,
+			SampleRate: 1,
+		},
+	})
+
+	// Timer with multiple values
+	f("foo:1:2.5|ms", []Row{
+		{
+			Metric:     "foo",
+			Type:       TypeTimer,
+			Value:      1,
+			SampleRate: 1,
+		},
+		{
+			Metric:     "foo",
+			Type:       TypeTimer,
+			Value:      2.5,
+			SampleRate: 1,
+		},
+	})
+
+	// Histogram and distribution
+	f("foo:1|h\nbar:2|d", []Row{
+		{
+			Metric:     "foo",
+			Type:       TypeHistogram,
+			Value:      1,
+			SampleRate: 1,
+		},
+		{
+			Metric:     "bar",
+			Type:       TypeDistribution,
+			Value:      2,
+			SampleRate: 1,
+		},
+	})
+
+	// Set
+	f("foo:user-1|s", []Row{{
+		Metric:     "foo",
+		Type:       TypeSet,
+		SetValue:   "user-1",
+		SampleRate: 1,
+	}})
+
+	// DogStatsD tags, Graphite-style tags and unknown fields
+	f("foo;env=prod:1|c|@0.5|#host:a,empty:,noval,:x|c:container-id|T1656581400", []Row{{
+		Metric: "foo",
+		Tags: []Tag{
+			{
+				Key:   "env",
+				Value: "prod",
+			},
+			{
+				Key:   "host",
+				Value: "a",
+			},
+		},
+		Type:       TypeCounter,
+		Value:      1,
+		SampleRate: 0.5,
+	}})
+
+	// Tag values containing the key-value delimiter
+	f("foo:1|g|#url:http://foo", []Row{{
+		Metric: "foo",
+		Tags: []Tag{{
+			Key:   "url",
+			Value: "http://foo",
+		}},
+		Type:       TypeGauge,
+		Value:      1,
+		SampleRate: 1,
+	}})
+
+	// Invalid lines are skipped
+	f("foo:1|c\nbar|c\nbaz:2|c", []Row{
+		{
+			Metric:     "foo",
+			Type:       TypeCounter,
+			Value:      1,
+			SampleRate: 1,
+		},
+		{
+			Metric:     "baz",
+			Type:       TypeCounter,
+			Value:      2,
+			SampleRate: 1,
+		},
+	})
+}
@@ -0,0 +1,60 @@
+package stream
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/statsd"
+)
+
+func TestStreamContextRead(t *testing.T) {
+	f := func(s string, rowsExpected []statsd.Row) {
+		t.Helper()
+		ctx := getStreamContext(strings.NewReader(s))
+		if !ctx.Read() {
+			t.Fatalf("expecting successful read")
+		}
+		uw := getUnmarshalWork()
+		callbackCalls := 0
+		uw.ctx = ctx
+		uw.callback = func(rows []statsd.Row) error {
+			callbackCalls++
+			if !reflect.DeepEqual(rows, rowsExpected) {
+				t.Fatalf("unexpected rows;\ngot\n%+v;\nwant\n%+v", rows, rowsExpected)
+			}
+			return nil
+		}
+		uw.reqBuf = append(uw.reqBuf[:0], ctx.reqBuf...)
+		ctx.wg.Add(1)
+		uw.Unmarshal()
+		if callbackCalls != 1 {
+			t.Fatalf("unexpected number of callback calls; got %d; want 1", callbackCalls)
+		}
+	}
+
+	f("foo:1|c", []statsd.Row{{
+		Metric:     "foo",
+		Type:       statsd.TypeCounter,
+		Value:      1,
+		SampleRate: 1,
+	}})
+	f("foo:1|g|#x:y\nbar:abc|s\n", []statsd.Row{
+		{
+			Metric: "foo",
+			Tags: []statsd.Tag{{
+				Key:   "x",
+				Value: "y",
+			}},
+			Type:       statsd.TypeGauge,
+			Value:      1,
+			SampleRate: 1,
+		},
+		{
+			Metric:     "bar",
+			Type:       statsd.TypeSet,
+			SetValue:   "abc",
+			SampleRate: 1,
+		},
+	})
+}
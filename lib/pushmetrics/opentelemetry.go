@@ -0,0 +1,224 @@
+package pushmetrics
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/appmetrics"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/buildinfo"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/prometheus"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/timeutil"
+)
+
+var (
+	otlpPushURL = flagutil.NewArrayString("opentelemetry.pushMetrics.url", "Optional URL to push metrics exposed at /metrics page in OpenTelemetry protocol (OTLP) format. "+
+		"For example, http://otel-collector:4318/v1/metrics . See https://docs.victoriametrics.com/#push-metrics . "+
+		"By default, metrics exposed at /metrics page aren't pushed to any OpenTelemetry collector")
+	otlpPushInterval = flag.Duration("opentelemetry.pushMetrics.interval", 10*time.Second, "Interval for pushing metrics to every -opentelemetry.pushMetrics.url")
+	otlpPushHeader   = flagutil.NewArrayString("opentelemetry.pushMetrics.header", "Optional HTTP request header to send to every -opentelemetry.pushMetrics.url . "+
+		"For example, -opentelemetry.pushMetrics.header='Authorization: Basic foobar' adds 'Authorization: Basic foobar' header to every request")
+)
+
+func init() {
+	// The -opentelemetry.pushMetrics.url flag can contain basic auth creds, so it mustn't be visible when exposing the flags.
+	flagutil.RegisterSecretFlag("opentelemetry.pushMetrics.url")
+}
+
+var (
+	otlpPushesTotal     = metrics.NewCounter(`vm_opentelemetry_push_metrics_pushes_total`)
+	otlpPushErrorsTotal = metrics.NewCounter(`vm_opentelemetry_push_metrics_push_errors_total`)
+)
+
+func initOpenTelemetryPush() {
+	if len(*otlpPushURL) == 0 {
+		return
+	}
+	if *otlpPushInterval <= 0 {
+		logger.Fatalf("-opentelemetry.pushMetrics.interval must be positive; got %s", *otlpPushInterval)
+	}
+	headers := make(http.Header)
+	for _, h := range *otlpPushHeader {
+		n := strings.IndexByte(h, ':')
+		if n < 0 {
+			logger.Fatalf("missing ':' in -opentelemetry.pushMetrics.header=%q; expecting 'Name: value'", h)
+		}
+		headers.Add(strings.TrimSpace(h[:n]), strings.TrimSpace(h[n+1:]))
+	}
+	resource := newOpenTelemetryResource(filepath.Base(os.Args[0]), buildinfo.Version)
+	hc := &http.Client{
+		Timeout: *otlpPushInterval,
+	}
+	for _, pu := range *otlpPushURL {
+		wgDone.Add(1)
+		go func(pushURL string) {
+			defer wgDone.Done()
+			runOpenTelemetryPusher(pushCtx, hc, pushURL, headers, resource)
+		}(pu)
+	}
+}
+
+func runOpenTelemetryPusher(ctx context.Context, hc *http.Client, pushURL string, headers http.Header, resource *pb.Resource) {
+	d := timeutil.AddJitterToDuration(*otlpPushInterval)
+	t := time.NewTicker(d)
+	defer t.Stop()
+	var bb bytes.Buffer
+	var rows prometheus.Rows
+	var body []byte
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		bb.Reset()
+		appmetrics.WritePrometheusMetrics(&bb)
+		rows.UnmarshalWithErrLogger(bb.String(), func(s string) {
+			logger.Errorf("cannot parse metrics for pushing them to -opentelemetry.pushMetrics.url: %s", s)
+		})
+		req := newOpenTelemetryRequest(resource, rows.Rows, appmetrics.GetStartTime(), time.Now())
+		body = req.MarshalProtobuf(body[:0])
+
+		otlpPushesTotal.Inc()
+		if err := pushOpenTelemetryRequest(ctx, hc, pushURL, headers, body); err != nil {
+			otlpPushErrorsTotal.Inc()
+			logger.Warnf("cannot push metrics to -opentelemetry.pushMetrics.url: %s", err)
+		}
+	}
+}
+
+func pushOpenTelemetryRequest(ctx context.Context, hc *http.Client, pushURL string, headers http.Header, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, pushURL, bytes.NewReader(body))
+	if err != nil {
+		// Do not include pushURL in the error message, since it may contain auth creds.
+		return fmt.Errorf("cannot create request")
+	}
+	for k, vs := range headers {
+		req.Header[k] = vs
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+	resp, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("cannot send request to %s: %w", req.URL.Redacted(), err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode/100 != 2 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code in response from %s: %d; expecting 2xx; response body: %q", req.URL.Redacted(), resp.StatusCode, respBody)
+	}
+	return nil
+}
+
+func newOpenTelemetryResource(serviceName, serviceVersion string) *pb.Resource {
+	r := &pb.Resource{}
+	r.Attributes = append(r.Attributes, newStringKeyValue("service.name", serviceName))
+	if serviceVersion != "" {
+		r.Attributes = append(r.Attributes, newStringKeyValue("service.version", serviceVersion))
+	}
+	return r
+}
+
+// newOpenTelemetryRequest converts Prometheus rows into OpenTelemetry request.
+//
+// Counters and _count, _sum and _bucket series of histograms and summaries are converted into monotonic cumulative sums
+// starting at startTime, while the rest of metrics are converted into gauges.
+func newOpenTelemetryRequest(resource *pb.Resource, rows []prometheus.Row, startTime, timestamp time.Time) *pb.ExportMetricsServiceRequest {
+	startTimeUnixNano := uint64(startTime.UnixNano())
+	timeUnixNano := uint64(timestamp.UnixNano())
+	metricNames := make(map[string]struct{})
+	for i := range rows {
+		metricNames[rows[i].Metric] = struct{}{}
+	}
+	var ms []*pb.Metric
+	metricsByName := make(map[string]*pb.Metric)
+	for i := range rows {
+		r := &rows[i]
+		dp := &pb.NumberDataPoint{
+			TimeUnixNano: timeUnixNano,
+		}
+		v := r.Value
+		dp.DoubleValue = &v
+		for _, tag := range r.Tags {
+			dp.Attributes = append(dp.Attributes, newStringKeyValue(tag.Key, tag.Value))
+		}
+
+		m := metricsByName[r.Metric]
+		if m == nil {
+			m = &pb.Metric{
+				Name: r.Metric,
+			}
+			if isCumulativeMetric(r.Metric, metricNames) {
+				m.Sum = &pb.Sum{
+					AggregationTemporality: pb.AggregationTemporalityCumulative,
+					IsMonotonic:            true,
+				}
+			} else {
+				m.Gauge = &pb.Gauge{}
+			}
+			metricsByName[r.Metric] = m
+			ms = append(ms, m)
+		}
+		if m.Sum != nil {
+			dp.StartTimeUnixNano = startTimeUnixNano
+			m.Sum.DataPoints = append(m.Sum.DataPoints, dp)
+		} else {
+			m.Gauge.DataPoints = append(m.Gauge.DataPoints, dp)
+		}
+	}
+	return &pb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*pb.ResourceMetrics{
+			{
+				Resource: resource,
+				ScopeMetrics: []*pb.ScopeMetrics{
+					{
+						Metrics: ms,
+					},
+				},
+			},
+		},
+	}
+}
+
+// isCumulativeMetric returns true if the metric with the given name is a counter or a part of histogram or summary.
+//
+// The _count, _sum and _bucket series are considered parts of histogram or summary only if the corresponding _count and _sum series exist in metricNames,
+// since gauges may have such suffixes too.
+func isCumulativeMetric(name string, metricNames map[string]struct{}) bool {
+	if strings.HasSuffix(name, "_total") {
+		return true
+	}
+	for _, suffix := range []string{"_count", "_sum", "_bucket"} {
+		if !strings.HasSuffix(name, suffix) {
+			continue
+		}
+		base := name[:len(name)-len(suffix)]
+		_, hasCount := metricNames[base+"_count"]
+		_, hasSum := metricNames[base+"_sum"]
+		return hasCount && hasSum
+	}
+	return false
+}
+
+func newStringKeyValue(key, value string) *pb.KeyValue {
+	return &pb.KeyValue{
+		Key: key,
+		Value: &pb.AnyValue{
+			StringValue: &value,
+		},
+	}
+}
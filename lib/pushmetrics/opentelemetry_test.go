@@ -0,0 +1,92 @@
+package pushmetrics
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/opentelemetry/pb"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/protoparser/prometheus"
+)
+
+func TestNewOpenTelemetryRequest(t *testing.T) {
+	f := func(s string, resultExpected string) {
+		t.Helper()
+
+		var rows prometheus.Rows
+		rows.UnmarshalWithErrLogger(s, func(errStr string) {
+			t.Fatalf("unexpected error when parsing %q: %s", s, errStr)
+		})
+		resource := newOpenTelemetryResource("vmagent", "v1.2.3")
+		req := newOpenTelemetryRequest(resource, rows.Rows, time.Unix(100, 0), time.Unix(123, 0))
+
+		// Verify the request can be marshaled and unmarshaled
+		data := req.MarshalProtobuf(nil)
+		var reqUnmarshaled pb.ExportMetricsServiceRequest
+		if err := reqUnmarshaled.UnmarshalProtobuf(data); err != nil {
+			t.Fatalf("cannot unmarshal request: %s", err)
+		}
+
+		var lines []string
+		for _, rm := range reqUnmarshaled.ResourceMetrics {
+			lines = append(lines, "resource "+formatAttributes(rm.Resource.Attributes))
+			for _, sm := range rm.ScopeMetrics {
+				for _, m := range sm.Metrics {
+					kind := "gauge"
+					dps := []*pb.NumberDataPoint(nil)
+					if m.Sum != nil {
+						kind = "sum"
+						dps = m.Sum.DataPoints
+					} else if m.Gauge != nil {
+						dps = m.Gauge.DataPoints
+					}
+					for _, dp := range dps {
+						lines = append(lines, fmt.Sprintf("%s %s%s %v %d %d", kind, m.Name, formatAttributes(dp.Attributes), *dp.DoubleValue, dp.StartTimeUnixNano, dp.TimeUnixNano))
+					}
+				}
+			}
+		}
+		result := strings.Join(lines, "\n")
+		if result != resultExpected {
+			t.Fatalf("unexpected result;\ngot\n%s\nwant\n%s", result, resultExpected)
+		}
+	}
+
+	f(`
+vm_rows_inserted_total{type="graphite"} 10
+vm_rows_inserted_total{type="influx"} 20
+process_resident_memory_bytes 1234
+`, `resource {service.name="vmagent",service.version="v1.2.3"}
+sum vm_rows_inserted_total{type="graphite"} 10 100000000000 123000000000
+sum vm_rows_inserted_total{type="influx"} 20 100000000000 123000000000
+gauge process_resident_memory_bytes{} 1234 0 123000000000`)
+
+	// histograms and summaries
+	f(`
+vm_request_duration_seconds_bucket{vmrange="0.1...0.2"} 3
+vm_request_duration_seconds_sum 0.5
+vm_request_duration_seconds_count 3
+go_gc_duration_seconds{quantile="0.5"} 0.01
+go_gc_duration_seconds_sum 0.2
+go_gc_duration_seconds_count 10
+vm_cache_entries_count 42
+`, `resource {service.name="vmagent",service.version="v1.2.3"}
+sum vm_request_duration_seconds_bucket{vmrange="0.1...0.2"} 3 100000000000 123000000000
+sum vm_request_duration_seconds_sum{} 0.5 100000000000 123000000000
+sum vm_request_duration_seconds_count{} 3 100000000000 123000000000
+gauge go_gc_duration_seconds{quantile="0.5"} 0.01 0 123000000000
+sum go_gc_duration_seconds_sum{} 0.2 100000000000 123000000000
+sum go_gc_duration_seconds_count{} 10 100000000000 123000000000
+gauge vm_cache_entries_count{} 42 0 123000000000`)
+}
+
+func formatAttributes(kvs []*pb.KeyValue) string {
+	var a []string
+	for _, kv := range kvs {
+		a = append(a, fmt.Sprintf("%s=%q", kv.Key, kv.Value.FormatString()))
+	}
+	sort.Strings(a)
+	return "{" + strings.Join(a, ",") + "}"
+}
@@ -48,6 +48,7 @@ func Init() {
 			logger.Fatalf("cannot initialize pushmetrics: %s", err)
 		}
 	}
+	initOpenTelemetryPush()
 }
 
 // Stop stops the periodic push of metrics.
@@ -0,0 +1,243 @@
+package pushprofiles
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime/pprof"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/VictoriaMetrics/metrics"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/buildinfo"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/logger"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/timeutil"
+)
+
+var (
+	pushURL = flagutil.NewArrayString("pushprofiles.url", "Optional URL to push cpu and heap profiles in pprof format to. See https://docs.victoriametrics.com/#push-profiles . "+
+		"By default, profiles aren't pushed to any remote storage")
+	pushInterval       = flag.Duration("pushprofiles.interval", time.Minute, "Interval for collecting and pushing profiles to every -pushprofiles.url")
+	cpuProfileDuration = flag.Duration("pushprofiles.cpuProfileDuration", 10*time.Second, "The duration for collecting cpu profile before pushing it to every -pushprofiles.url . "+
+		"It must be smaller than -pushprofiles.interval")
+	pushExtraLabel = flagutil.NewArrayString("pushprofiles.extraLabel", "Optional labels to add to profiles pushed to every -pushprofiles.url . "+
+		"For example, -pushprofiles.extraLabel='env=prod' adds env=prod label to all the profiles pushed to every -pushprofiles.url")
+	pushHeader = flagutil.NewArrayString("pushprofiles.header", "Optional HTTP request header to send to every -pushprofiles.url . "+
+		"For example, -pushprofiles.header='Authorization: Basic foobar' adds 'Authorization: Basic foobar' header to every request to every -pushprofiles.url")
+)
+
+func init() {
+	// The -pushprofiles.url flag can contain basic auth creds, so it mustn't be visible when exposing the flags.
+	flagutil.RegisterSecretFlag("pushprofiles.url")
+}
+
+var (
+	pushesTotal        = metrics.NewCounter(`vm_pushprofiles_pushes_total`)
+	pushErrorsTotal    = metrics.NewCounter(`vm_pushprofiles_push_errors_total`)
+	collectErrorsTotal = metrics.NewCounter(`vm_pushprofiles_collect_errors_total`)
+)
+
+var (
+	pushCtx, cancelPushCtx = context.WithCancel(context.Background())
+	wgDone                 sync.WaitGroup
+)
+
+// Init starts periodic collecting and pushing of profiles to -pushprofiles.url.
+//
+// Init must be called after logger.Init
+func Init() {
+	if len(*pushURL) == 0 {
+		return
+	}
+	if *pushInterval <= 0 {
+		logger.Fatalf("-pushprofiles.interval must be positive; got %s", *pushInterval)
+	}
+	if *cpuProfileDuration < 0 || *cpuProfileDuration >= *pushInterval {
+		logger.Fatalf("-pushprofiles.cpuProfileDuration=%s must be in the range [0 ... -pushprofiles.interval=%s)", *cpuProfileDuration, *pushInterval)
+	}
+	headers, err := parseHeaders(*pushHeader)
+	if err != nil {
+		logger.Fatalf("cannot parse -pushprofiles.header: %s", err)
+	}
+	labels, err := getLabels(filepath.Base(os.Args[0]), buildinfo.Version, *pushExtraLabel)
+	if err != nil {
+		logger.Fatalf("cannot parse -pushprofiles.extraLabel: %s", err)
+	}
+	pp := &profilesPusher{
+		urls:    *pushURL,
+		headers: headers,
+		labels:  labels,
+		hc: &http.Client{
+			Timeout: *pushInterval,
+		},
+	}
+	wgDone.Add(1)
+	go func() {
+		defer wgDone.Done()
+		pp.run(pushCtx)
+	}()
+}
+
+// Stop stops the periodic push of profiles.
+//
+// Stop must be called after Init.
+func Stop() {
+	cancelPushCtx()
+	wgDone.Wait()
+}
+
+type profilesPusher struct {
+	urls    []string
+	headers http.Header
+
+	// labels contains labels in the form {name1="value1",...,nameN="valueN"}.
+	labels string
+
+	hc *http.Client
+}
+
+func (pp *profilesPusher) run(ctx context.Context) {
+	d := timeutil.AddJitterToDuration(*pushInterval)
+	t := time.NewTicker(d)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		}
+
+		if *cpuProfileDuration > 0 {
+			startTime := time.Now()
+			data, err := collectCPUProfile(ctx, *cpuProfileDuration)
+			if err != nil {
+				collectErrorsTotal.Inc()
+				logger.Warnf("cannot collect cpu profile: %s", err)
+			} else if ctx.Err() == nil {
+				pp.push(ctx, "cpu", data, startTime, time.Now())
+			}
+		}
+
+		startTime := time.Now()
+		data, err := collectHeapProfile()
+		if err != nil {
+			collectErrorsTotal.Inc()
+			logger.Warnf("cannot collect heap profile: %s", err)
+			continue
+		}
+		pp.push(ctx, "heap", data, startTime, startTime)
+	}
+}
+
+func collectCPUProfile(ctx context.Context, d time.Duration) ([]byte, error) {
+	var bb bytes.Buffer
+	if err := pprof.StartCPUProfile(&bb); err != nil {
+		// This may happen if the cpu profile is collected via /debug/pprof/profile at the moment.
+		return nil, err
+	}
+	t := time.NewTimer(d)
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+	t.Stop()
+	pprof.StopCPUProfile()
+	return bb.Bytes(), nil
+}
+
+func collectHeapProfile() ([]byte, error) {
+	var bb bytes.Buffer
+	if err := pprof.Lookup("heap").WriteTo(&bb, 0); err != nil {
+		return nil, err
+	}
+	return bb.Bytes(), nil
+}
+
+func (pp *profilesPusher) push(ctx context.Context, profileType string, data []byte, startTime, endTime time.Time) {
+	for _, u := range pp.urls {
+		pushesTotal.Inc()
+		if err := pp.pushToURL(ctx, u, profileType, data, startTime, endTime); err != nil {
+			pushErrorsTotal.Inc()
+			logger.Warnf("cannot push %s profile to -pushprofiles.url: %s", profileType, err)
+		}
+	}
+}
+
+func (pp *profilesPusher) pushToURL(ctx context.Context, pushURL, profileType string, data []byte, startTime, endTime time.Time) error {
+	u, err := url.Parse(pushURL)
+	if err != nil {
+		return fmt.Errorf("cannot parse url: %w", err)
+	}
+	qs := u.Query()
+	qs.Set("name", profileType+pp.labels)
+	qs.Set("from", strconv.FormatInt(startTime.Unix(), 10))
+	qs.Set("until", strconv.FormatInt(endTime.Unix(), 10))
+	qs.Set("format", "pprof")
+	u.RawQuery = qs.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("cannot create request: %w", err)
+	}
+	for k, vs := range pp.headers {
+		req.Header[k] = vs
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := pp.hc.Do(req)
+	if err != nil {
+		// Use redacted url in the error message, since the url may contain auth creds.
+		return fmt.Errorf("cannot send request to %s: %w", u.Redacted(), err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("unexpected status code in response from %s: %d; expecting 2xx; response body: %q", u.Redacted(), resp.StatusCode, body)
+	}
+	return nil
+}
+
+// getLabels returns labels in the form {component="...",version="...",extraLabels...} for the pushed profiles.
+func getLabels(component, version string, extraLabels []string) (string, error) {
+	a := []string{
+		fmt.Sprintf("component=%q", component),
+	}
+	if version != "" {
+		a = append(a, fmt.Sprintf("version=%q", version))
+	}
+	for _, label := range extraLabels {
+		n := strings.IndexByte(label, '=')
+		if n <= 0 {
+			return "", fmt.Errorf("missing '=' in %q; expecting name=value", label)
+		}
+		name := label[:n]
+		value := strings.Trim(label[n+1:], `"`)
+		a = append(a, fmt.Sprintf("%s=%q", name, value))
+	}
+	return "{" + strings.Join(a, ",") + "}", nil
+}
+
+func parseHeaders(headers []string) (http.Header, error) {
+	h := make(http.Header)
+	for _, header := range headers {
+		n := strings.IndexByte(header, ':')
+		if n < 0 {
+			return nil, fmt.Errorf("missing ':' in %q; expecting 'Name: value'", header)
+		}
+		name := strings.TrimSpace(header[:n])
+		value := strings.TrimSpace(header[n+1:])
+		h.Add(name, value)
+	}
+	return h, nil
+}
@@ -0,0 +1,83 @@
+package pushprofiles
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestGetLabels(t *testing.T) {
+	f := func(component, version string, extraLabels []string, resultExpected string) {
+		t.Helper()
+		result, err := getLabels(component, version, extraLabels)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if result != resultExpected {
+			t.Fatalf("unexpected result; got %s; want %s", result, resultExpected)
+		}
+	}
+
+	f("vmagent", "", nil, `{component="vmagent"}`)
+	f("vmagent", "vmagent-v1.2.3", nil, `{component="vmagent",version="vmagent-v1.2.3"}`)
+	f("victoria-metrics", "v1", []string{"env=prod", `instance="foo"`}, `{component="victoria-metrics",version="v1",env="prod",instance="foo"}`)
+}
+
+func TestGetLabels_Failure(t *testing.T) {
+	f := func(extraLabel string) {
+		t.Helper()
+		if _, err := getLabels("vmagent", "", []string{extraLabel}); err == nil {
+			t.Fatalf("expecting non-nil error for -pushprofiles.extraLabel=%q", extraLabel)
+		}
+	}
+
+	f("foo")
+	f("=bar")
+}
+
+func TestProfilesPusherPushToURL(t *testing.T) {
+	var query, authHeader, body string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		authHeader = r.Header.Get("Authorization")
+		data, _ := io.ReadAll(r.Body)
+		body = string(data)
+		if r.URL.Query().Get("fail") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer ts.Close()
+
+	headers, err := parseHeaders([]string{"Authorization: Bearer foo"})
+	if err != nil {
+		t.Fatalf("cannot parse headers: %s", err)
+	}
+	pp := &profilesPusher{
+		headers: headers,
+		labels:  `{component="vmagent"}`,
+		hc:      ts.Client(),
+	}
+	startTime := time.Unix(1700000000, 0)
+	endTime := startTime.Add(10 * time.Second)
+	if err := pp.pushToURL(context.Background(), ts.URL+"/ingest", "cpu", []byte("profile"), startTime, endTime); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	queryExpected := `format=pprof&from=1700000000&name=cpu%7Bcomponent%3D%22vmagent%22%7D&until=1700000010`
+	if query != queryExpected {
+		t.Fatalf("unexpected query; got %s; want %s", query, queryExpected)
+	}
+	if authHeader != "Bearer foo" {
+		t.Fatalf("unexpected Authorization header; got %q; want %q", authHeader, "Bearer foo")
+	}
+	if body != "profile" {
+		t.Fatalf("unexpected body; got %q; want %q", body, "profile")
+	}
+
+	// non-2xx response
+	if err := pp.pushToURL(context.Background(), ts.URL+"/ingest?fail=1", "heap", []byte("profile"), startTime, endTime); err == nil {
+		t.Fatalf("expecting non-nil error for non-2xx response")
+	}
+}
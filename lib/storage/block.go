@@ -18,6 +18,28 @@ const (
 	maxBlockSize = 8 * maxRowsPerBlock
 )
 
+// SetValuesXOREncoding enables Gorilla XOR encoding for gauge values if it gives smaller blocks than the default encoding.
+//
+// Data parts containing XOR-encoded blocks cannot be read by VictoriaMetrics versions without XOR encoding support.
+//
+// This function must be called before any calling any storage functions.
+func SetValuesXOREncoding(ok bool) {
+	valuesXOREncoding = ok
+}
+
+var valuesXOREncoding = false
+
+// SetTimestampsDeltaOfDeltaEncoding enables Gorilla delta-of-delta encoding for timestamps if it gives smaller blocks than the default encoding.
+//
+// Data parts containing delta-of-delta-encoded blocks cannot be read by VictoriaMetrics versions without delta-of-delta encoding support.
+//
+// This function must be called before any calling any storage functions.
+func SetTimestampsDeltaOfDeltaEncoding(ok bool) {
+	timestampsDeltaOfDeltaEncoding = ok
+}
+
+var timestampsDeltaOfDeltaEncoding = false
+
 // Block represents a block of time series values for a single TSID.
 type Block struct {
 	bh blockHeader
@@ -227,12 +249,20 @@ func (b *Block) MarshalData(timestampsBlockOffset, valuesBlockOffset uint64) ([]
 		logger.Panicf("BUG: the number of values must match the number of timestamps; got %d vs %d", len(values), len(timestamps))
 	}
 
-	b.valuesData, b.bh.ValuesMarshalType, b.bh.FirstValue = encoding.MarshalValues(b.valuesData[:0], values, b.bh.PrecisionBits)
+	if valuesXOREncoding {
+		b.valuesData, b.bh.ValuesMarshalType, b.bh.FirstValue = encoding.MarshalValuesWithXOR(b.valuesData[:0], values, b.bh.PrecisionBits)
+	} else {
+		b.valuesData, b.bh.ValuesMarshalType, b.bh.FirstValue = encoding.MarshalValues(b.valuesData[:0], values, b.bh.PrecisionBits)
+	}
 	b.bh.ValuesBlockOffset = valuesBlockOffset
 	b.bh.ValuesBlockSize = uint32(len(b.valuesData))
 	b.values = b.values[:0]
 
-	b.timestampsData, b.bh.TimestampsMarshalType, b.bh.MinTimestamp = encoding.MarshalTimestamps(b.timestampsData[:0], timestamps, b.bh.PrecisionBits)
+	if timestampsDeltaOfDeltaEncoding {
+		b.timestampsData, b.bh.TimestampsMarshalType, b.bh.MinTimestamp = encoding.MarshalTimestampsWithDeltaOfDelta(b.timestampsData[:0], timestamps, b.bh.PrecisionBits)
+	} else {
+		b.timestampsData, b.bh.TimestampsMarshalType, b.bh.MinTimestamp = encoding.MarshalTimestamps(b.timestampsData[:0], timestamps, b.bh.PrecisionBits)
+	}
 	b.bh.TimestampsBlockOffset = timestampsBlockOffset
 	b.bh.TimestampsBlockSize = uint32(len(b.timestampsData))
 	b.bh.MaxTimestamp = timestamps[len(timestamps)-1]
@@ -148,3 +148,40 @@ func getRandTimestamps(rowsCount int) []int64 {
 	}
 	return a
 }
+
+func TestBlockMarshalUnmarshalDataGorillaEncodings(t *testing.T) {
+	defer func() {
+		SetValuesXOREncoding(false)
+		SetTimestampsDeltaOfDeltaEncoding(false)
+	}()
+	SetValuesXOREncoding(true)
+	SetTimestampsDeltaOfDeltaEncoding(true)
+
+	// Timestamps with occasional jitter and gauge with frequently repeated values
+	timestamps := []int64{0, 15000, 30000, 45000, 60001, 75000, 90000, 105000, 120000, 135000, 149998, 165000, 180000, 195000, 210000, 225000}
+	values := []int64{5, 5, 5, 5, 6, 6, 6, 6, 5, 5, 5, 5, 5, 6, 6, 6}
+
+	var b Block
+	b.Init(&TSID{}, timestamps, values, 0, 64)
+	_, timestampsData, valuesData := b.MarshalData(0, 0)
+	if b.bh.TimestampsMarshalType != encoding.MarshalTypeDeltaOfDelta {
+		t.Fatalf("unexpected TimestampsMarshalType; got %d; want %d", b.bh.TimestampsMarshalType, encoding.MarshalTypeDeltaOfDelta)
+	}
+	if b.bh.ValuesMarshalType != encoding.MarshalTypeXOR {
+		t.Fatalf("unexpected ValuesMarshalType; got %d; want %d", b.bh.ValuesMarshalType, encoding.MarshalTypeXOR)
+	}
+
+	var b2 Block
+	b2.bh = b.bh
+	b2.timestampsData = append(b2.timestampsData[:0], timestampsData...)
+	b2.valuesData = append(b2.valuesData[:0], valuesData...)
+	if err := b2.UnmarshalData(); err != nil {
+		t.Fatalf("cannot unmarshal block data: %s", err)
+	}
+	if !reflect.DeepEqual(b2.timestamps, timestamps) {
+		t.Fatalf("unexpected timestamps; got\n%d\nwant\n%d", b2.timestamps, timestamps)
+	}
+	if !reflect.DeepEqual(b2.values, values) {
+		t.Fatalf("unexpected values; got\n%d\nwant\n%d", b2.values, values)
+	}
+}
@@ -619,9 +619,8 @@ func (db *indexDB) SearchLabelNamesWithFiltersOnTimeRange(qt *querytracer.Tracer
 }
 
 func (is *indexSearch) searchLabelNamesWithFiltersOnTimeRange(qt *querytracer.Tracer, lns map[string]struct{}, tfss []*TagFilters, tr TimeRange, maxLabelNames, maxMetrics int) error {
-	minDate := uint64(tr.MinTimestamp) / msecPerDay
-	maxDate := uint64(tr.MaxTimestamp-1) / msecPerDay
-	if maxDate == 0 || minDate > maxDate || maxDate-minDate > maxDaysForPerDaySearch {
+	minDate, maxDate := is.getDateRangeForLabelsSearch(tr)
+	if maxDate == 0 || minDate > maxDate || maxDate-minDate > maxDaysForPerDayLabelsSearch {
 		qtChild := qt.NewChild("search for label names in global index: filters=%s", tfss)
 		err := is.searchLabelNamesWithFiltersOnDate(qtChild, lns, tfss, 0, maxLabelNames, maxMetrics)
 		qtChild.Done()
@@ -843,9 +842,8 @@ func (db *indexDB) SearchLabelValuesWithFiltersOnTimeRange(qt *querytracer.Trace
 
 func (is *indexSearch) searchLabelValuesWithFiltersOnTimeRange(qt *querytracer.Tracer, lvs map[string]struct{}, labelName string, tfss []*TagFilters,
 	tr TimeRange, maxLabelValues, maxMetrics int) error {
-	minDate := uint64(tr.MinTimestamp) / msecPerDay
-	maxDate := uint64(tr.MaxTimestamp-1) / msecPerDay
-	if maxDate == 0 || minDate > maxDate || maxDate-minDate > maxDaysForPerDaySearch {
+	minDate, maxDate := is.getDateRangeForLabelsSearch(tr)
+	if maxDate == 0 || minDate > maxDate || maxDate-minDate > maxDaysForPerDayLabelsSearch {
 		qtChild := qt.NewChild("search for label values in global index: labelName=%q, filters=%s", labelName, tfss)
 		err := is.searchLabelValuesWithFiltersOnDate(qtChild, lvs, labelName, tfss, 0, maxLabelValues, maxMetrics)
 		qtChild.Done()
@@ -1051,9 +1049,8 @@ func (db *indexDB) SearchTagValueSuffixes(qt *querytracer.Tracer, tr TimeRange,
 }
 
 func (is *indexSearch) searchTagValueSuffixesForTimeRange(tvss map[string]struct{}, tr TimeRange, tagKey, tagValuePrefix string, delimiter byte, maxTagValueSuffixes int) error {
-	minDate := uint64(tr.MinTimestamp) / msecPerDay
-	maxDate := uint64(tr.MaxTimestamp-1) / msecPerDay
-	if minDate > maxDate || maxDate-minDate > maxDaysForPerDaySearch {
+	minDate, maxDate := is.getDateRangeForLabelsSearch(tr)
+	if minDate > maxDate || maxDate-minDate > maxDaysForPerDayLabelsSearch {
 		return is.searchTagValueSuffixesAll(tvss, tagKey, tagValuePrefix, delimiter, maxTagValueSuffixes)
 	}
 	// Query over multiple days in parallel.
@@ -2278,6 +2275,38 @@ func (is *indexSearch) searchMetricIDsInternal(qt *querytracer.Tracer, tfss []*T
 
 const maxDaysForPerDaySearch = 40
 
+// SetMaxDaysForPerDayLabelsSearch sets the maximum number of days on the requested time range,
+// which can be searched in the per-day index when looking up label names, label values and tag value suffixes.
+//
+// The global index is used for longer time ranges.
+// This function must be called before any calling any storage functions.
+func SetMaxDaysForPerDayLabelsSearch(days int) {
+	if days <= 0 {
+		days = maxDaysForPerDaySearch
+	}
+	maxDaysForPerDayLabelsSearch = uint64(days)
+}
+
+var maxDaysForPerDayLabelsSearch uint64 = maxDaysForPerDaySearch
+
+// getDateRangeForLabelsSearch returns the range of dates in the per-day index, which must be searched for labels on the given tr.
+//
+// The start of tr is limited by the retention, since there is no need in scanning the per-day index for dates without data.
+// This allows using the per-day index instead of the global index for time ranges starting at 0
+// on installations with short retention.
+func (is *indexSearch) getDateRangeForLabelsSearch(tr TimeRange) (uint64, uint64) {
+	minTimestamp := tr.MinTimestamp
+	if s := is.db.s; s != nil && s.retentionMsecs > 0 {
+		retentionDeadline := int64(fasttime.UnixTimestamp()*1e3) - s.retentionMsecs
+		if minTimestamp < retentionDeadline && tr.MaxTimestamp > retentionDeadline {
+			minTimestamp = retentionDeadline
+		}
+	}
+	minDate := uint64(minTimestamp) / msecPerDay
+	maxDate := uint64(tr.MaxTimestamp-1) / msecPerDay
+	return minDate, maxDate
+}
+
 func (is *indexSearch) updateMetricIDsForTagFilters(qt *querytracer.Tracer, metricIDs *uint64set.Set, tfs *TagFilters, tr TimeRange, maxMetrics int) error {
 	minDate := uint64(tr.MinTimestamp) / msecPerDay
 	maxDate := uint64(tr.MaxTimestamp-1) / msecPerDay
@@ -14,6 +14,7 @@ import (
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fasttime"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fs"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/mergeset"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/uint64set"
@@ -1451,6 +1452,57 @@ func TestMatchTagFilters(t *testing.T) {
 	}
 }
 
+func TestGetDateRangeForLabelsSearch(t *testing.T) {
+	const retentionMsecs = 10 * msecPerDay
+	is := &indexSearch{
+		db: &indexDB{
+			s: &Storage{
+				retentionMsecs: retentionMsecs,
+			},
+		},
+	}
+	currentTimestamp := int64(fasttime.UnixTimestamp() * 1e3)
+	retentionDate := uint64(currentTimestamp-retentionMsecs) / msecPerDay
+	currentDate := uint64(currentTimestamp) / msecPerDay
+
+	f := func(tr TimeRange, minDateExpected, maxDateExpected uint64) {
+		t.Helper()
+
+		minDate, maxDate := is.getDateRangeForLabelsSearch(tr)
+		if minDate != minDateExpected {
+			t.Fatalf("unexpected minDate for %s; got %s; want %s", &tr, dateToString(minDate), dateToString(minDateExpected))
+		}
+		if maxDate != maxDateExpected {
+			t.Fatalf("unexpected maxDate for %s; got %s; want %s", &tr, dateToString(maxDate), dateToString(maxDateExpected))
+		}
+	}
+
+	// time range inside the retention
+	f(TimeRange{
+		MinTimestamp: currentTimestamp - 2*msecPerDay,
+		MaxTimestamp: currentTimestamp,
+	}, currentDate-2, uint64(currentTimestamp-1)/msecPerDay)
+
+	// the start of the time range is outside the retention
+	f(TimeRange{
+		MinTimestamp: 0,
+		MaxTimestamp: currentTimestamp,
+	}, retentionDate, uint64(currentTimestamp-1)/msecPerDay)
+
+	// the whole time range is outside the retention
+	f(TimeRange{
+		MinTimestamp: msecPerDay,
+		MaxTimestamp: 3 * msecPerDay,
+	}, 1, 2)
+
+	// missing storage
+	is.db.s = nil
+	f(TimeRange{
+		MinTimestamp: 0,
+		MaxTimestamp: currentTimestamp,
+	}, 0, uint64(currentTimestamp-1)/msecPerDay)
+}
+
 func TestIndexDBRepopulateAfterRotation(t *testing.T) {
 	r := rand.New(rand.NewSource(1))
 	path := "TestIndexRepopulateAfterRotation"
@@ -434,6 +434,12 @@ func (mn *MetricName) Marshal(dst []byte) []byte {
 	return dst
 }
 
+// SortAndMarshal sorts mn tags and then marshals them to dst.
+func (mn *MetricName) SortAndMarshal(dst []byte) []byte {
+	mn.sortTags()
+	return mn.Marshal(dst)
+}
+
 // UnmarshalString unmarshals mn from s
 func (mn *MetricName) UnmarshalString(s string) error {
 	b := bytesutil.ToUnsafeBytes(s)
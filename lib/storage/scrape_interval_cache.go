@@ -0,0 +1,294 @@
+package storage
+
+import (
+	"math"
+	"sync"
+	"unsafe"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fasttime"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/querytracer"
+)
+
+// SetTrackScrapeIntervals enables tracking of the observed intervals between samples per each time series.
+//
+// This function must be called before any calling any storage functions.
+func SetTrackScrapeIntervals(ok bool) {
+	trackScrapeIntervals = ok
+}
+
+var trackScrapeIntervals = false
+
+// ScrapeInterval contains the observed interval between samples for a single time series.
+type ScrapeInterval struct {
+	// Interval is the smoothed interval between the ingested samples in milliseconds.
+	Interval int64
+
+	// FirstTimestamp is the timestamp in milliseconds of the first ingested sample, which was used for estimating the Interval.
+	//
+	// It is reset to the timestamp of the last sample if the interval between samples changes significantly, e.g. after a gap.
+	FirstTimestamp int64
+
+	// LastTimestamp is the timestamp in milliseconds of the last ingested sample.
+	LastTimestamp int64
+
+	// Samples is the number of ingested samples, which were used for estimating the Interval.
+	Samples uint32
+}
+
+// IntervalForTimeRange returns si.Interval if it was observed on the time range covering [minTimestamp ... maxTimestamp].
+//
+// Zero is returned otherwise, since the interval between samples outside the observed time range may differ from si.Interval.
+// For example, historical data may be ingested with another scrape interval or it may be downsampled.
+func (si *ScrapeInterval) IntervalForTimeRange(minTimestamp, maxTimestamp int64) int64 {
+	if si.Interval <= 0 || minTimestamp < si.FirstTimestamp || maxTimestamp > si.LastTimestamp {
+		return 0
+	}
+	return si.Interval
+}
+
+// GetScrapeInterval returns the observed interval between samples for the time series with the given metricID.
+//
+// false is returned if the interval isn't known for the given metricID.
+// See SetTrackScrapeIntervals.
+func (s *Storage) GetScrapeInterval(metricID uint64) (ScrapeInterval, bool) {
+	if s.scrapeIntervalCache == nil {
+		return ScrapeInterval{}, false
+	}
+	return s.scrapeIntervalCache.Get(metricID)
+}
+
+// ForEachScrapeInterval calls f for every unique marshaled metric name matching the given tfss on the given tr
+// with the observed interval between the ingested samples for this metric name.
+//
+// Time series without the observed interval are skipped. See SetTrackScrapeIntervals.
+//
+// If limit > 0, then the search stops after f is called for limit unique metric names.
+//
+// f mustn't hold metricName after returning. The marshaled metric names must be unmarshaled via MetricName.UnmarshalString().
+func (s *Storage) ForEachScrapeInterval(qt *querytracer.Tracer, tfss []*TagFilters, tr TimeRange, maxMetrics, limit int, deadline uint64,
+	f func(metricName []byte, si *ScrapeInterval) error) error {
+	qt = qt.NewChild("search for scrape intervals: filters=%s, timeRange=%s, limit=%d", tfss, &tr, limit)
+	defer qt.Done()
+
+	if s.scrapeIntervalCache == nil {
+		qt.Printf("scrape intervals tracking is disabled")
+		return nil
+	}
+	metricIDs, err := s.idb().searchMetricIDs(qt, tfss, tr, maxMetrics, deadline)
+	if err != nil {
+		return err
+	}
+	idb := s.idb()
+	metricNamesSeen := make(map[string]struct{})
+	var metricName []byte
+	for i, metricID := range metricIDs {
+		if i&paceLimiterSlowIterationsMask == 0 {
+			if err := checkSearchDeadlineAndPace(deadline); err != nil {
+				return err
+			}
+		}
+		si, ok := s.scrapeIntervalCache.Get(metricID)
+		if !ok {
+			continue
+		}
+		metricName, ok = idb.searchMetricNameWithCache(metricName[:0], metricID)
+		if !ok {
+			// Skip missing metricName for metricID.
+			// It should be automatically fixed. See indexDB.searchMetricNameWithCache for details.
+			continue
+		}
+		if _, ok := metricNamesSeen[string(metricName)]; ok {
+			// The given metric name was already seen; skip it
+			continue
+		}
+		metricNamesSeen[string(metricName)] = struct{}{}
+		if err := f(metricName, &si); err != nil {
+			return err
+		}
+		if limit > 0 && len(metricNamesSeen) >= limit {
+			break
+		}
+	}
+	qt.Printf("found %d series with scrape intervals", len(metricNamesSeen))
+	return nil
+}
+
+// scrapeIntervalCache holds compact sketches of the intervals between the ingested samples per each MetricID.
+//
+// Sketches for time series without new samples during the last hour are dropped.
+type scrapeIntervalCache struct {
+	shards []scrapeIntervalCacheShard
+}
+
+type scrapeIntervalCacheShard struct {
+	scrapeIntervalCacheShardNopad
+
+	// The padding prevents false sharing on widespread platforms with
+	// 128 mod (cache line size) = 0 .
+	_ [128 - unsafe.Sizeof(scrapeIntervalCacheShardNopad{})%128]byte
+}
+
+type scrapeIntervalCacheShardNopad struct {
+	mu sync.Mutex
+
+	// curr contains sketches updated after the last rotation.
+	curr map[uint64]*scrapeIntervalSketch
+
+	// prev contains sketches updated before the last rotation.
+	prev map[uint64]*scrapeIntervalSketch
+
+	// rotateDeadline is unix timestamp in seconds for the next rotation of curr to prev.
+	rotateDeadline uint64
+}
+
+// scrapeIntervalSketch is a compact sketch of intervals between samples for a single time series.
+type scrapeIntervalSketch struct {
+	firstTimestamp int64
+	lastTimestamp  int64
+	interval       uint32
+	samples        uint32
+}
+
+const scrapeIntervalCacheRotationInterval = 3600
+
+func newScrapeIntervalCache(shardsCount int) *scrapeIntervalCache {
+	var c scrapeIntervalCache
+	c.shards = make([]scrapeIntervalCacheShard, shardsCount)
+	deadline := fasttime.UnixTimestamp() + scrapeIntervalCacheRotationInterval
+	for i := range c.shards {
+		sh := &c.shards[i]
+		sh.curr = make(map[uint64]*scrapeIntervalSketch)
+		sh.prev = make(map[uint64]*scrapeIntervalSketch)
+		sh.rotateDeadline = deadline
+	}
+	return &c
+}
+
+func (c *scrapeIntervalCache) getShard(metricID uint64) *scrapeIntervalCacheShard {
+	idx := fastHashUint64(metricID) % uint64(len(c.shards))
+	return &c.shards[idx]
+}
+
+// Get returns the observed interval between samples for the given metricID.
+func (c *scrapeIntervalCache) Get(metricID uint64) (ScrapeInterval, bool) {
+	sh := c.getShard(metricID)
+	sh.mu.Lock()
+	sk := sh.curr[metricID]
+	if sk == nil {
+		sk = sh.prev[metricID]
+	}
+	var si ScrapeInterval
+	if sk != nil {
+		si = ScrapeInterval{
+			Interval:       int64(sk.interval),
+			FirstTimestamp: sk.firstTimestamp,
+			LastTimestamp:  sk.lastTimestamp,
+			Samples:        sk.samples,
+		}
+	}
+	sh.mu.Unlock()
+	return si, si.Interval > 0
+}
+
+// UpdateRows registers samples from rows in c.
+func (c *scrapeIntervalCache) UpdateRows(rows []rawRow) {
+	currentTime := fasttime.UnixTimestamp()
+	var sh *scrapeIntervalCacheShard
+	for i := range rows {
+		r := &rows[i]
+		metricID := r.TSID.MetricID
+		// Hold the shard lock while consecutive rows belong to the same shard.
+		// This reduces locking overhead for rows sorted by time series.
+		if shNext := c.getShard(metricID); shNext != sh {
+			if sh != nil {
+				sh.mu.Unlock()
+			}
+			sh = shNext
+			sh.mu.Lock()
+			sh.rotateIfNeededLocked(currentTime)
+		}
+		sh.updateLocked(metricID, r.Timestamp)
+	}
+	if sh != nil {
+		sh.mu.Unlock()
+	}
+}
+
+// EntriesCount returns the number of time series tracked in c.
+func (c *scrapeIntervalCache) EntriesCount() uint64 {
+	n := 0
+	for i := range c.shards {
+		sh := &c.shards[i]
+		sh.mu.Lock()
+		n += len(sh.curr) + len(sh.prev)
+		sh.mu.Unlock()
+	}
+	return uint64(n)
+}
+
+// SizeBytes returns the approximate size in bytes of the data held by c.
+func (c *scrapeIntervalCache) SizeBytes() uint64 {
+	// Every entry occupies a map slot with the key and the pointer plus the sketch itself.
+	return c.EntriesCount() * uint64(unsafe.Sizeof(uint64(0))+unsafe.Sizeof(uintptr(0))+unsafe.Sizeof(scrapeIntervalSketch{}))
+}
+
+func (sh *scrapeIntervalCacheShard) rotateIfNeededLocked(currentTime uint64) {
+	if currentTime < sh.rotateDeadline {
+		return
+	}
+	sh.prev = sh.curr
+	sh.curr = make(map[uint64]*scrapeIntervalSketch, len(sh.prev))
+	sh.rotateDeadline = currentTime + scrapeIntervalCacheRotationInterval
+}
+
+func (sh *scrapeIntervalCacheShard) updateLocked(metricID uint64, timestamp int64) {
+	sk := sh.curr[metricID]
+	if sk == nil {
+		sk = sh.prev[metricID]
+		if sk == nil {
+			sk = &scrapeIntervalSketch{}
+		} else {
+			delete(sh.prev, metricID)
+		}
+		sh.curr[metricID] = sk
+	}
+	sk.update(timestamp)
+}
+
+func (sk *scrapeIntervalSketch) update(timestamp int64) {
+	if sk.samples == 0 {
+		sk.firstTimestamp = timestamp
+		sk.lastTimestamp = timestamp
+		sk.samples = 1
+		return
+	}
+	d := timestamp - sk.lastTimestamp
+	if d <= 0 {
+		// Skip duplicate and out-of-order samples, since they do not carry information about the interval between samples.
+		return
+	}
+	sk.lastTimestamp = timestamp
+	if d > math.MaxUint32 {
+		d = math.MaxUint32
+	}
+	if sk.interval == 0 {
+		sk.interval = uint32(d)
+	} else {
+		interval := int64(sk.interval)
+		if d < interval/2 || d > 2*interval {
+			// The interval between samples has been changed or there is a gap in samples.
+			// Restart the time range where the interval is observed, since the interval before the change may differ.
+			sk.firstTimestamp = timestamp
+		}
+
+		// Limit the observed interval to [interval/2 ... interval*2] in order to reduce the impact of gaps and jitter,
+		// and then smooth it with exponentially weighted moving average.
+		// This allows the estimated interval to follow scrape interval changes in a dozen of samples.
+		d = min(max(d, interval/2), 2*interval)
+		interval += (d - interval) / 8
+		sk.interval = uint32(min(max(interval, 1), math.MaxUint32))
+	}
+	if sk.samples < math.MaxUint32 {
+		sk.samples++
+	}
+}
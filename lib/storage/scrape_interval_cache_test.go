@@ -0,0 +1,230 @@
+package storage
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestScrapeIntervalSketchUpdate(t *testing.T) {
+	f := func(timestamps []int64, intervalExpected, samplesExpected uint32, firstTimestampExpected int64) {
+		t.Helper()
+
+		var sk scrapeIntervalSketch
+		for _, timestamp := range timestamps {
+			sk.update(timestamp)
+		}
+		if sk.firstTimestamp != firstTimestampExpected {
+			t.Fatalf("unexpected firstTimestamp; got %d; want %d", sk.firstTimestamp, firstTimestampExpected)
+		}
+		if sk.interval != intervalExpected {
+			t.Fatalf("unexpected interval; got %d; want %d", sk.interval, intervalExpected)
+		}
+		if sk.samples != samplesExpected {
+			t.Fatalf("unexpected samples; got %d; want %d", sk.samples, samplesExpected)
+		}
+	}
+
+	// no samples
+	f(nil, 0, 0, 0)
+
+	// a single sample
+	f([]int64{1000}, 0, 1, 1000)
+
+	// regular intervals
+	f([]int64{1000, 16000, 31000, 46000}, 15000, 4, 1000)
+
+	// duplicate and out-of-order samples are ignored
+	f([]int64{1000, 16000, 16000, 5000, 31000}, 15000, 3, 1000)
+
+	// jitter
+	f([]int64{1000, 16000, 31800, 46000, 61000}, 14989, 5, 1000)
+
+	// a gap has limited impact on the interval, while it restarts the time range where the interval is observed
+	f([]int64{1000, 16000, 31000, 331000}, 16875, 4, 331000)
+
+	// the interval follows scrape interval changes
+	timestamps := []int64{0}
+	for i := 1; i < 20; i++ {
+		timestamps = append(timestamps, timestamps[len(timestamps)-1]+10000)
+	}
+	for i := 0; i < 50; i++ {
+		timestamps = append(timestamps, timestamps[len(timestamps)-1]+30000)
+	}
+	// the time range where the interval is observed is restarted until the interval approaches the new scrape interval
+	f(timestamps, 29966, 70, 310000)
+}
+
+func TestScrapeIntervalIntervalForTimeRange(t *testing.T) {
+	f := func(si *ScrapeInterval, minTimestamp, maxTimestamp, intervalExpected int64) {
+		t.Helper()
+
+		interval := si.IntervalForTimeRange(minTimestamp, maxTimestamp)
+		if interval != intervalExpected {
+			t.Fatalf("unexpected interval for [%d ... %d]; got %d; want %d", minTimestamp, maxTimestamp, interval, intervalExpected)
+		}
+	}
+
+	si := &ScrapeInterval{
+		Interval:       10000,
+		FirstTimestamp: 100000,
+		LastTimestamp:  200000,
+		Samples:        11,
+	}
+
+	// the time range is covered by the observed time range
+	f(si, 100000, 200000, 10000)
+	f(si, 150000, 160000, 10000)
+
+	// the time range starts before the observed time range
+	f(si, 50000, 200000, 0)
+
+	// the time range ends after the observed time range
+	f(si, 100000, 210000, 0)
+
+	// the interval is unknown
+	f(&ScrapeInterval{}, 0, 0, 0)
+}
+
+func TestScrapeIntervalCacheRotation(t *testing.T) {
+	c := newScrapeIntervalCache(4)
+
+	rows := []rawRow{
+		{
+			TSID:      TSID{MetricID: 1},
+			Timestamp: 1000,
+		},
+		{
+			TSID:      TSID{MetricID: 2},
+			Timestamp: 1000,
+		},
+		{
+			TSID:      TSID{MetricID: 1},
+			Timestamp: 11000,
+		},
+	}
+	c.UpdateRows(rows)
+
+	f := func(metricID uint64, siExpected ScrapeInterval, okExpected bool) {
+		t.Helper()
+
+		si, ok := c.Get(metricID)
+		if ok != okExpected {
+			t.Fatalf("unexpected ok for metricID=%d; got %v; want %v", metricID, ok, okExpected)
+		}
+		if si != siExpected {
+			t.Fatalf("unexpected scrape interval for metricID=%d; got %+v; want %+v", metricID, si, siExpected)
+		}
+	}
+	forceRotation := func() {
+		for i := range c.shards {
+			sh := &c.shards[i]
+			sh.mu.Lock()
+			sh.rotateIfNeededLocked(sh.rotateDeadline)
+			sh.mu.Unlock()
+		}
+	}
+
+	f(1, ScrapeInterval{Interval: 10000, FirstTimestamp: 1000, LastTimestamp: 11000, Samples: 2}, true)
+
+	// The interval is unknown for a single sample
+	f(2, ScrapeInterval{FirstTimestamp: 1000, LastTimestamp: 1000, Samples: 1}, false)
+
+	// Missing metricID
+	f(3, ScrapeInterval{}, false)
+
+	if n := c.EntriesCount(); n != 2 {
+		t.Fatalf("unexpected number of entries; got %d; want 2", n)
+	}
+
+	// The entries are still available after the first rotation
+	forceRotation()
+	f(1, ScrapeInterval{Interval: 10000, FirstTimestamp: 1000, LastTimestamp: 11000, Samples: 2}, true)
+
+	// Updated entries survive the next rotation, while the rest of entries are dropped
+	c.UpdateRows(rows[:1])
+	c.UpdateRows([]rawRow{
+		{
+			TSID:      TSID{MetricID: 1},
+			Timestamp: 21000,
+		},
+	})
+	forceRotation()
+	f(1, ScrapeInterval{Interval: 10000, FirstTimestamp: 1000, LastTimestamp: 21000, Samples: 3}, true)
+	f(2, ScrapeInterval{}, false)
+	if n := c.EntriesCount(); n != 1 {
+		t.Fatalf("unexpected number of entries; got %d; want 1", n)
+	}
+}
+
+func TestStorageForEachScrapeInterval(t *testing.T) {
+	defer testRemoveAll(t)
+
+	SetTrackScrapeIntervals(true)
+	defer SetTrackScrapeIntervals(false)
+
+	tr := TimeRange{
+		MinTimestamp: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli(),
+		MaxTimestamp: time.Date(2000, 1, 1, 23, 59, 59, 999, time.UTC).UnixMilli(),
+	}
+	var mrs []MetricRow
+	addSeries := func(metricGroup string, interval int64) {
+		mn := MetricName{
+			MetricGroup: []byte(metricGroup),
+		}
+		metricNameRaw := mn.marshalRaw(nil)
+		for i := int64(0); i < 10; i++ {
+			mrs = append(mrs, MetricRow{
+				MetricNameRaw: metricNameRaw,
+				Timestamp:     tr.MinTimestamp + i*interval,
+				Value:         float64(i),
+			})
+		}
+	}
+	addSeries("metric_15s", 15000)
+	addSeries("metric_60s", 60000)
+
+	s := MustOpenStorage(t.Name(), 0, 0, 0)
+	defer s.MustClose()
+	s.AddRows(mrs, defaultPrecisionBits)
+	s.DebugFlush()
+
+	tfsAll := NewTagFilters()
+	if err := tfsAll.Add([]byte("__name__"), []byte(".*"), false, true); err != nil {
+		t.Fatalf("unexpected error in TagFilters.Add: %s", err)
+	}
+	result := make(map[string]ScrapeInterval)
+	err := s.ForEachScrapeInterval(nil, []*TagFilters{tfsAll}, tr, 1e9, 0, noDeadline, func(metricName []byte, si *ScrapeInterval) error {
+		var mn MetricName
+		if err := mn.Unmarshal(metricName); err != nil {
+			return fmt.Errorf("cannot unmarshal metric name: %w", err)
+		}
+		result[string(mn.MetricGroup)] = *si
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	resultExpected := map[string]ScrapeInterval{
+		"metric_15s": {
+			Interval:       15000,
+			FirstTimestamp: tr.MinTimestamp,
+			LastTimestamp:  tr.MinTimestamp + 9*15000,
+			Samples:        10,
+		},
+		"metric_60s": {
+			Interval:       60000,
+			FirstTimestamp: tr.MinTimestamp,
+			LastTimestamp:  tr.MinTimestamp + 9*60000,
+			Samples:        10,
+		},
+	}
+	if len(result) != len(resultExpected) {
+		t.Fatalf("unexpected number of series; got %d; want %d", len(result), len(resultExpected))
+	}
+	for name, siExpected := range resultExpected {
+		if si := result[name]; si != siExpected {
+			t.Fatalf("unexpected scrape interval for %s; got %+v; want %+v", name, si, siExpected)
+		}
+	}
+}
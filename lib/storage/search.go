@@ -56,6 +56,11 @@ func (br *BlockRef) RowsCount() int {
 	return int(br.bh.RowsCount)
 }
 
+// MetricID returns MetricID for the time series stored in br.
+func (br *BlockRef) MetricID() uint64 {
+	return br.bh.TSID.MetricID
+}
+
 // PartRef returns PartRef from br.
 func (br *BlockRef) PartRef() PartRef {
 	return PartRef{
@@ -18,6 +18,7 @@ import (
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/backup/backupnames"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bloomfilter"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/cgroup"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/decimal"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fasttime"
@@ -99,6 +100,11 @@ type Storage struct {
 	// See generationTSID for details.
 	dateMetricIDCache *dateMetricIDCache
 
+	// scrapeIntervalCache holds the observed intervals between samples per each MetricID.
+	//
+	// It is nil if scrape intervals tracking is disabled. See SetTrackScrapeIntervals.
+	scrapeIntervalCache *scrapeIntervalCache
+
 	// Fast cache for MetricID values occurred during the current hour.
 	currHourMetricIDs atomic.Pointer[hourMetricIDs]
 
@@ -219,6 +225,9 @@ func MustOpenStorage(path string, retention time.Duration, maxHourlySeries, maxD
 	s.metricIDCache = s.mustLoadCache("metricID_tsid", mem/16)
 	s.metricNameCache = s.mustLoadCache("metricID_metricName", mem/10)
 	s.dateMetricIDCache = newDateMetricIDCache()
+	if trackScrapeIntervals {
+		s.scrapeIntervalCache = newScrapeIntervalCache(cgroup.AvailableCPUs())
+	}
 
 	hour := fasttime.UnixHour()
 	hmCurr := s.mustLoadHourMetricIDs(hour, "curr_hour_metric_ids")
@@ -538,6 +547,9 @@ type Metrics struct {
 	NextDayMetricIDCacheSize      uint64
 	NextDayMetricIDCacheSizeBytes uint64
 
+	ScrapeIntervalCacheSize      uint64
+	ScrapeIntervalCacheSizeBytes uint64
+
 	PrefetchedMetricIDsSize      uint64
 	PrefetchedMetricIDsSizeBytes uint64
 
@@ -629,6 +641,11 @@ func (s *Storage) UpdateMetrics(m *Metrics) {
 	m.NextDayMetricIDCacheSize += uint64(nextDayMetricIDs.Len())
 	m.NextDayMetricIDCacheSizeBytes += nextDayMetricIDs.SizeBytes()
 
+	if sic := s.scrapeIntervalCache; sic != nil {
+		m.ScrapeIntervalCacheSize += sic.EntriesCount()
+		m.ScrapeIntervalCacheSizeBytes += sic.SizeBytes()
+	}
+
 	s.prefetchedMetricIDsLock.Lock()
 	prefetchedMetricIDs := s.prefetchedMetricIDs
 	m.PrefetchedMetricIDsSize += uint64(prefetchedMetricIDs.Len())
@@ -1128,27 +1145,44 @@ func nextRetentionDeadlineSeconds(atSecs, retentionSecs, offsetSecs int64) int64
 //
 // The marshaled metric names must be unmarshaled via MetricName.UnmarshalString().
 func (s *Storage) SearchMetricNames(qt *querytracer.Tracer, tfss []*TagFilters, tr TimeRange, maxMetrics int, deadline uint64) ([]string, error) {
+	var metricNames []string
+	err := s.ForEachMetricName(qt, tfss, tr, maxMetrics, deadline, func(metricName []byte) error {
+		metricNames = append(metricNames, string(metricName))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return metricNames, nil
+}
+
+// ForEachMetricName calls f for every unique marshaled metric name matching the given tfss on the given tr.
+//
+// Metric names are passed to f in the order of their metricIDs. Duplicate metric names,
+// which may appear for distinct metricIDs, are passed to f only once.
+//
+// f mustn't hold metricName after returning. The marshaled metric names must be unmarshaled via MetricName.UnmarshalString().
+func (s *Storage) ForEachMetricName(qt *querytracer.Tracer, tfss []*TagFilters, tr TimeRange, maxMetrics int, deadline uint64, f func(metricName []byte) error) error {
 	qt = qt.NewChild("search for matching metric names: filters=%s, timeRange=%s", tfss, &tr)
 	defer qt.Done()
 
 	metricIDs, err := s.idb().searchMetricIDs(qt, tfss, tr, maxMetrics, deadline)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if len(metricIDs) == 0 {
-		return nil, nil
+		return nil
 	}
 	if err = s.prefetchMetricNames(qt, metricIDs, deadline); err != nil {
-		return nil, err
+		return err
 	}
 	idb := s.idb()
-	metricNames := make([]string, 0, len(metricIDs))
 	metricNamesSeen := make(map[string]struct{}, len(metricIDs))
 	var metricName []byte
 	for i, metricID := range metricIDs {
 		if i&paceLimiterSlowIterationsMask == 0 {
 			if err := checkSearchDeadlineAndPace(deadline); err != nil {
-				return nil, err
+				return err
 			}
 		}
 		var ok bool
@@ -1162,11 +1196,13 @@ func (s *Storage) SearchMetricNames(qt *querytracer.Tracer, tfss []*TagFilters,
 			// The given metric name was already seen; skip it
 			continue
 		}
-		metricNames = append(metricNames, string(metricName))
-		metricNamesSeen[metricNames[len(metricNames)-1]] = struct{}{}
+		metricNamesSeen[string(metricName)] = struct{}{}
+		if err := f(metricName); err != nil {
+			return err
+		}
 	}
-	qt.Printf("loaded %d metric names", len(metricNames))
-	return metricNames, nil
+	qt.Printf("loaded %d metric names", len(metricNamesSeen))
+	return nil
 }
 
 // prefetchMetricNames pre-fetches metric names for the given srcMetricIDs into metricID->metricName cache.
@@ -1613,13 +1649,23 @@ func (s *Storage) ForceMergePartitions(partitionNamePrefix string) error {
 // The caller should limit the number of concurrent AddRows calls to the number
 // of available CPU cores in order to limit memory usage.
 func (s *Storage) AddRows(mrs []MetricRow, precisionBits uint8) {
+	_, _ = s.AddRowsExt(mrs, precisionBits)
+}
+
+// AddRowsExt works the same as AddRows, but also returns the number of rows rejected
+// because of timestamps outside the retention or because of -storage.maxHourlySeries and -storage.maxDailySeries limits.
+//
+// The reason for the first rejection is returned additionally to the number of rejected rows.
+func (s *Storage) AddRowsExt(mrs []MetricRow, precisionBits uint8) (int, string) {
 	if len(mrs) == 0 {
-		return
+		return 0, ""
 	}
 
 	// Add rows to the storage in blocks with limited size in order to reduce memory usage.
 	ic := getMetricRowsInsertCtx()
 	maxBlockLen := len(ic.rrs)
+	rowsRejected := 0
+	rejectReason := ""
 	for len(mrs) > 0 {
 		mrsBlock := mrs
 		if len(mrs) > maxBlockLen {
@@ -1628,10 +1674,15 @@ func (s *Storage) AddRows(mrs []MetricRow, precisionBits uint8) {
 		} else {
 			mrs = nil
 		}
-		s.add(ic.rrs, ic.tmpMrs, mrsBlock, precisionBits)
+		n, reason := s.add(ic.rrs, ic.tmpMrs, mrsBlock, precisionBits)
+		if rowsRejected == 0 {
+			rejectReason = reason
+		}
+		rowsRejected += n
 		s.rowsAddedTotal.Add(uint64(len(mrsBlock)))
 	}
 	putMetricRowsInsertCtx(ic)
+	return rowsRejected, rejectReason
 }
 
 type metricRowsInsertCtx struct {
@@ -1785,7 +1836,8 @@ func (s *Storage) RegisterMetricNames(qt *querytracer.Tracer, mrs []MetricRow) {
 	}
 }
 
-func (s *Storage) add(rows []rawRow, dstMrs []*MetricRow, mrs []MetricRow, precisionBits uint8) {
+// add adds mrs to s and returns the number of rejected rows together with the reason for the first rejection.
+func (s *Storage) add(rows []rawRow, dstMrs []*MetricRow, mrs []MetricRow, precisionBits uint8) (int, string) {
 	idb := s.idb()
 	generation := idb.generation
 	is := idb.getIndexSearch(noDeadline)
@@ -1812,6 +1864,15 @@ func (s *Storage) add(rows []rawRow, dstMrs []*MetricRow, mrs []MetricRow, preci
 	// Log only the first error, since it has no sense in logging all errors.
 	var firstWarn error
 
+	rowsRejected := 0
+	rejectReason := ""
+	rejectRow := func(reason string) {
+		if rowsRejected == 0 {
+			rejectReason = reason
+		}
+		rowsRejected++
+	}
+
 	j := 0
 	for i := range mrs {
 		mr := &mrs[i]
@@ -1833,6 +1894,7 @@ func (s *Storage) add(rows []rawRow, dstMrs []*MetricRow, mrs []MetricRow, preci
 					mr.Timestamp, minTimestamp, metricName)
 			}
 			s.tooSmallTimestampRows.Add(1)
+			rejectRow("too small timestamp outside the retention")
 			continue
 		}
 		if mr.Timestamp > maxTimestamp {
@@ -1843,6 +1905,7 @@ func (s *Storage) add(rows []rawRow, dstMrs []*MetricRow, mrs []MetricRow, preci
 					mr.Timestamp, maxTimestamp, metricName)
 			}
 			s.tooBigTimestampRows.Add(1)
+			rejectRow("too big timestamp exceeding the current time")
 			continue
 		}
 		dstMrs[j] = mr
@@ -1867,6 +1930,7 @@ func (s *Storage) add(rows []rawRow, dstMrs []*MetricRow, mrs []MetricRow, preci
 
 			if !s.registerSeriesCardinality(r.TSID.MetricID, mr.MetricNameRaw) {
 				// Skip row, since it exceeds cardinality limit
+				rejectRow(seriesCardinalityLimitReason)
 				j--
 				continue
 			}
@@ -1918,6 +1982,7 @@ func (s *Storage) add(rows []rawRow, dstMrs []*MetricRow, mrs []MetricRow, preci
 
 			if !s.registerSeriesCardinality(genTSID.TSID.MetricID, mr.MetricNameRaw) {
 				// Skip the row, since it exceeds the configured cardinality limit.
+				rejectRow(seriesCardinalityLimitReason)
 				j--
 				continue
 			}
@@ -1948,6 +2013,7 @@ func (s *Storage) add(rows []rawRow, dstMrs []*MetricRow, mrs []MetricRow, preci
 
 		if !s.registerSeriesCardinality(genTSID.TSID.MetricID, mr.MetricNameRaw) {
 			// Skip the row, since it exceeds the configured cardinality limit.
+			rejectRow(seriesCardinalityLimitReason)
 			j--
 			continue
 		}
@@ -1984,13 +2050,21 @@ func (s *Storage) add(rows []rawRow, dstMrs []*MetricRow, mrs []MetricRow, preci
 		}
 	}
 
+	if sic := s.scrapeIntervalCache; sic != nil {
+		sic.UpdateRows(rows)
+	}
+
 	if firstWarn != nil {
 		storageAddRowsLogger.Warnf("warn occurred during rows addition: %s", firstWarn)
 	}
 
 	s.tb.MustAddRows(rows)
+
+	return rowsRejected, rejectReason
 }
 
+const seriesCardinalityLimitReason = "the series exceeds -storage.maxHourlySeries or -storage.maxDailySeries limit"
+
 var storageAddRowsLogger = logger.WithThrottler("storageAddRows", 5*time.Second)
 
 // SetLogNewSeries updates new series logging.
@@ -1421,11 +1421,18 @@ func TestStorageRowsNotAdded_SeriesLimitExceeded(t *testing.T) {
 		path := fmt.Sprintf("%s/%s", t.Name(), name)
 		s := MustOpenStorage(path, 0, maxHourlySeries, maxDailySeries)
 		defer s.MustClose()
-		s.AddRows(mrs, defaultPrecisionBits)
+		rowsRejected, rejectReason := s.AddRowsExt(mrs, defaultPrecisionBits)
 		s.DebugFlush()
 		s.UpdateMetrics(&gotMetrics)
 
-		want := numRows - (gotMetrics.HourlySeriesLimitRowsDropped + gotMetrics.DailySeriesLimitRowsDropped)
+		rowsDropped := gotMetrics.HourlySeriesLimitRowsDropped + gotMetrics.DailySeriesLimitRowsDropped
+		if uint64(rowsRejected) != rowsDropped {
+			t.Fatalf("unexpected number of rejected rows; got %d; want %d", rowsRejected, rowsDropped)
+		}
+		if rejectReason != seriesCardinalityLimitReason {
+			t.Fatalf("unexpected reject reason; got %q; want %q", rejectReason, seriesCardinalityLimitReason)
+		}
+		want := numRows - rowsDropped
 		if got := testCountAllMetricNames(s, TimeRange{minTimestamp, maxTimestamp}); uint64(got) != want {
 			t.Fatalf("unexpected metric name count: %d, want %d", got, want)
 		}
@@ -1440,6 +1447,26 @@ func TestStorageRowsNotAdded_SeriesLimitExceeded(t *testing.T) {
 	f("DailyLimitExceeded", maxHourlySeries, maxDailySeries)
 }
 
+func TestStorageAddRowsExt_TimestampOutsideRetention(t *testing.T) {
+	defer testRemoveAll(t)
+
+	rng := rand.New(rand.NewSource(1))
+	now := time.Now().UnixMilli()
+	mrs := testGenerateMetricRows(rng, 10, now-1000, now)
+	mrs = append(mrs, testGenerateMetricRows(rng, 3, now-10*msecPerDay, now-9*msecPerDay)...)
+	mrs = append(mrs, testGenerateMetricRows(rng, 5, now+10*msecPerDay, now+11*msecPerDay)...)
+
+	s := MustOpenStorage(t.Name(), 24*time.Hour, 0, 0)
+	defer s.MustClose()
+	rowsRejected, rejectReason := s.AddRowsExt(mrs, defaultPrecisionBits)
+	if rowsRejected != 8 {
+		t.Fatalf("unexpected number of rejected rows; got %d; want 8", rowsRejected)
+	}
+	if want := "too small timestamp outside the retention"; rejectReason != want {
+		t.Fatalf("unexpected reject reason; got %q; want %q", rejectReason, want)
+	}
+}
+
 // testCountAllMetricNames is a test helper function that counts the names of
 // all time series within the given time range.
 func testCountAllMetricNames(s *Storage, tr TimeRange) int {
@@ -1454,6 +1481,56 @@ func testCountAllMetricNames(s *Storage, tr TimeRange) int {
 	return len(names)
 }
 
+func TestStorageForEachMetricName(t *testing.T) {
+	defer testRemoveAll(t)
+
+	const numRows = 100
+	rng := rand.New(rand.NewSource(1))
+	tr := TimeRange{
+		MinTimestamp: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli(),
+		MaxTimestamp: time.Date(2000, 1, 1, 23, 59, 59, 999, time.UTC).UnixMilli(),
+	}
+	mrs := testGenerateMetricRowsWithPrefix(rng, numRows, "metric", tr)
+
+	s := MustOpenStorage(t.Name(), 0, 0, 0)
+	defer s.MustClose()
+	s.AddRows(mrs, defaultPrecisionBits)
+	s.DebugFlush()
+
+	tfsAll := NewTagFilters()
+	if err := tfsAll.Add([]byte("__name__"), []byte(".*"), false, true); err != nil {
+		t.Fatalf("unexpected error in TagFilters.Add: %s", err)
+	}
+	seen := make(map[string]struct{})
+	err := s.ForEachMetricName(nil, []*TagFilters{tfsAll}, tr, 1e9, noDeadline, func(metricName []byte) error {
+		if _, ok := seen[string(metricName)]; ok {
+			return fmt.Errorf("duplicate metric name %q", metricName)
+		}
+		seen[string(metricName)] = struct{}{}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(seen) != numRows {
+		t.Fatalf("unexpected number of metric names; got %d; want %d", len(seen), numRows)
+	}
+
+	// the error returned by f must stop the search
+	errStop := fmt.Errorf("stop")
+	calls := 0
+	err = s.ForEachMetricName(nil, []*TagFilters{tfsAll}, tr, 1e9, noDeadline, func(_ []byte) error {
+		calls++
+		return errStop
+	})
+	if err != errStop {
+		t.Fatalf("unexpected error; got %v; want %v", err, errStop)
+	}
+	if calls != 1 {
+		t.Fatalf("unexpected number of calls; got %d; want 1", calls)
+	}
+}
+
 func TestStorageSearchMetricNames_TooManyTimeseries(t *testing.T) {
 	defer testRemoveAll(t)
 
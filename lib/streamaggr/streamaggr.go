@@ -14,6 +14,7 @@ import (
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/cgroup"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/decimal"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/encoding"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/envtemplate"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/fs/fscore"
@@ -390,6 +391,9 @@ type aggregator struct {
 	// aggrOutputs contains aggregate states for the given outputs
 	aggrOutputs []aggrOutput
 
+	// hasStaleSamplesPushers is set if some of aggrOutputs need staleness markers for input series
+	hasStaleSamplesPushers bool
+
 	// minTimestamp is used for ignoring old samples when ignoreOldSamples is set
 	minTimestamp atomic.Int64
 
@@ -430,6 +434,16 @@ type aggrState interface {
 	flushState(ctx *flushCtx)
 }
 
+// staleSamplesPusher is implemented by aggrState, which needs staleness markers for input series.
+//
+// Staleness markers are passed to pushStaleSamples without deduplication.
+type staleSamplesPusher interface {
+	// pushStaleSamples must push staleness markers to the aggrState.
+	//
+	// samples[].key must be cloned by aggrState, since it may change after returning from pushStaleSamples.
+	pushStaleSamples(samples []pushSample)
+}
+
 // PushFunc is called by Aggregators when it needs to push its state to metrics storage
 type PushFunc func(tss []prompbmarshal.TimeSeries)
 
@@ -556,11 +570,15 @@ func newAggregator(cfg *Config, path string, pushFunc PushFunc, ms *metrics.Set,
 	}
 	aggrOutputs := make([]aggrOutput, len(cfg.Outputs))
 	outputsSeen := make(map[string]struct{}, len(cfg.Outputs))
+	hasStaleSamplesPushers := false
 	for i, output := range cfg.Outputs {
 		as, err := newAggrState(output, outputsSeen, stalenessInterval)
 		if err != nil {
 			return nil, err
 		}
+		if _, ok := as.(staleSamplesPusher); ok {
+			hasStaleSamplesPushers = true
+		}
 		aggrOutputs[i] = aggrOutput{
 			as: as,
 
@@ -596,7 +614,8 @@ func newAggregator(cfg *Config, path string, pushFunc PushFunc, ms *metrics.Set,
 		interval:      interval,
 		dedupInterval: dedupInterval,
 
-		aggrOutputs: aggrOutputs,
+		aggrOutputs:            aggrOutputs,
+		hasStaleSamplesPushers: hasStaleSamplesPushers,
 
 		suffix: suffix,
 
@@ -892,6 +911,7 @@ func (a *aggregator) Push(tss []prompbmarshal.TimeSeries, matchIdxs []byte) {
 	defer putPushCtx(ctx)
 
 	samples := ctx.samples
+	staleSamples := ctx.staleSamples
 	buf := ctx.buf
 	labels := &ctx.labels
 	inputLabels := &ctx.inputLabels
@@ -936,6 +956,14 @@ func (a *aggregator) Push(tss []prompbmarshal.TimeSeries, matchIdxs []byte) {
 		key := bytesutil.ToUnsafeString(buf[bufLen:])
 		for _, s := range ts.Samples {
 			if math.IsNaN(s.Value) {
+				if decimal.IsStaleNaN(s.Value) && a.hasStaleSamplesPushers {
+					staleSamples = append(staleSamples, pushSample{
+						key:       key,
+						value:     s.Value,
+						timestamp: s.Timestamp,
+					})
+					continue
+				}
 				a.ignoredNaNSamples.Inc()
 				// Skip NaN values
 				continue
@@ -961,6 +989,7 @@ func (a *aggregator) Push(tss []prompbmarshal.TimeSeries, matchIdxs []byte) {
 		a.samplesLag.Update(float64(maxLagMsec) / 1_000)
 	}
 	ctx.samples = samples
+	ctx.staleSamples = staleSamples
 	ctx.buf = buf
 
 	if a.da != nil {
@@ -968,6 +997,9 @@ func (a *aggregator) Push(tss []prompbmarshal.TimeSeries, matchIdxs []byte) {
 	} else {
 		a.pushSamples(samples)
 	}
+	if len(staleSamples) > 0 {
+		a.pushStaleSamples(staleSamples)
+	}
 }
 
 func compressLabels(dst []byte, inputLabels, outputLabels []prompbmarshal.Label) []byte {
@@ -1013,8 +1045,17 @@ func (a *aggregator) pushSamples(samples []pushSample) {
 	}
 }
 
+func (a *aggregator) pushStaleSamples(samples []pushSample) {
+	for _, ao := range a.aggrOutputs {
+		if sp, ok := ao.as.(staleSamplesPusher); ok {
+			sp.pushStaleSamples(samples)
+		}
+	}
+}
+
 type pushCtx struct {
 	samples      []pushSample
+	staleSamples []pushSample
 	labels       promutils.Labels
 	inputLabels  promutils.Labels
 	outputLabels promutils.Labels
@@ -1025,6 +1066,9 @@ func (ctx *pushCtx) reset() {
 	clear(ctx.samples)
 	ctx.samples = ctx.samples[:0]
 
+	clear(ctx.staleSamples)
+	ctx.staleSamples = ctx.staleSamples[:0]
+
 	ctx.labels.Reset()
 	ctx.inputLabels.Reset()
 	ctx.outputLabels.Reset()
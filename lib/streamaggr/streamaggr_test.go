@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/decimal"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/prompbmarshal"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/promrelabel"
 )
@@ -1073,3 +1074,55 @@ func appendClonedTimeseries(dst, src []prompbmarshal.TimeSeries) []prompbmarshal
 	}
 	return dst
 }
+
+func TestTotalAggrStateStaleSamples(t *testing.T) {
+	f := func(keepFirstSample bool, totalExpected float64) {
+		t.Helper()
+
+		as := newTotalAggrState(time.Minute, false, keepFirstSample)
+		// Do not ignore the first sample for new series
+		as.ignoreFirstSampleDeadline = 0
+
+		inputLabels := []prompbmarshal.Label{{Name: "instance", Value: "foo"}}
+		outputLabels := []prompbmarshal.Label{{Name: "__name__", Value: "requests_total"}}
+		key := string(compressLabels(nil, inputLabels, outputLabels))
+		push := func(value float64, timestamp int64) {
+			as.pushSamples([]pushSample{{
+				key:       key,
+				value:     value,
+				timestamp: timestamp,
+			}})
+		}
+
+		push(10, 1000)
+		push(15, 2000)
+		as.pushStaleSamples([]pushSample{{
+			key:       key,
+			value:     decimal.StaleNaN,
+			timestamp: 3000,
+		}})
+
+		// delayed sample must be applied to the series before the staleness marker
+		push(17, 2500)
+
+		// the series is restarted after the staleness marker
+		push(20, 4000)
+		push(25, 5000)
+
+		_, outputKey := getInputOutputKey(key)
+		v, ok := as.m.Load(outputKey)
+		if !ok {
+			t.Fatalf("missing state for the output key")
+		}
+		sv := v.(*totalStateValue)
+		if sv.total != totalExpected {
+			t.Fatalf("unexpected total; got %v; want %v", sv.total, totalExpected)
+		}
+	}
+
+	// total: 10 + (15-10) + (17-15) + 20 + (25-20)
+	f(true, 42)
+
+	// total_prometheus: (15-10) + (17-15) + (25-20)
+	f(false, 12)
+}
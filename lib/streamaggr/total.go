@@ -43,6 +43,13 @@ type totalLastValueState struct {
 	value          float64
 	timestamp      int64
 	deleteDeadline uint64
+
+	// stale is set when staleness marker is received for the input series.
+	//
+	// Samples with timestamps bigger than staleTimestamp are treated as samples for a new series,
+	// since the series has been restarted after the staleness marker.
+	stale          bool
+	staleTimestamp int64
 }
 
 func newTotalAggrState(stalenessInterval time.Duration, resetTotalOnFlush, keepFirstSample bool) *totalAggrState {
@@ -84,6 +91,11 @@ func (as *totalAggrState) pushSamples(samples []pushSample) {
 		deleted := sv.deleted
 		if !deleted {
 			lv, ok := sv.lastValues[inputKey]
+			if ok && lv.stale && s.timestamp > lv.staleTimestamp {
+				// The series has been restarted after the staleness marker, so treat it as a new series.
+				ok = false
+				lv = totalLastValueState{}
+			}
 			if ok || keepFirstSample {
 				if s.timestamp < lv.timestamp {
 					// Skip out of order sample
@@ -115,6 +127,29 @@ func (as *totalAggrState) pushSamples(samples []pushSample) {
 	}
 }
 
+func (as *totalAggrState) pushStaleSamples(samples []pushSample) {
+	for i := range samples {
+		s := &samples[i]
+		inputKey, outputKey := getInputOutputKey(s.key)
+		v, ok := as.m.Load(outputKey)
+		if !ok {
+			// Nothing to mark as stale.
+			continue
+		}
+		sv := v.(*totalStateValue)
+		sv.mu.Lock()
+		lv, ok := sv.lastValues[inputKey]
+		if ok && s.timestamp >= lv.timestamp {
+			lv.stale = true
+			lv.staleTimestamp = s.timestamp
+
+			inputKey = bytesutil.InternString(inputKey)
+			sv.lastValues[inputKey] = lv
+		}
+		sv.mu.Unlock()
+	}
+}
+
 func (as *totalAggrState) flushState(ctx *flushCtx) {
 	currentTime := fasttime.UnixTimestamp()
 
@@ -0,0 +1,54 @@
+package tenantmetrics
+
+import (
+	"sync/atomic"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/auth"
+	"github.com/VictoriaMetrics/metrics"
+)
+
+// HistogramMap is a map of histograms keyed by tenant.
+type HistogramMap struct {
+	metric string
+
+	// do not use atomic.Pointer, since the stored map there is already a pointer type.
+	m atomic.Value
+}
+
+// NewHistogramMap creates new HistogramMap for the given metric.
+func NewHistogramMap(metric string) *HistogramMap {
+	hm := &HistogramMap{
+		metric: metric,
+	}
+	hm.m.Store(make(map[TenantID]*metrics.Histogram))
+	return hm
+}
+
+// Get returns histogram for the given at
+func (hm *HistogramMap) Get(at *auth.Token) *metrics.Histogram {
+	key := TenantID{
+		AccountID: at.AccountID,
+		ProjectID: at.ProjectID,
+	}
+	return hm.GetByTenant(key)
+}
+
+// GetByTenant returns histogram for the given key.
+func (hm *HistogramMap) GetByTenant(key TenantID) *metrics.Histogram {
+	m := hm.m.Load().(map[TenantID]*metrics.Histogram)
+	if h := m[key]; h != nil {
+		// Fast path - the histogram for k already exists.
+		return h
+	}
+
+	// Slow path - create missing histogram for k and re-create m.
+	newM := make(map[TenantID]*metrics.Histogram, len(m)+1)
+	for k, h := range m {
+		newM[k] = h
+	}
+	metricName := createMetricName(hm.metric, key)
+	h := metrics.GetOrCreateHistogram(metricName)
+	newM[key] = h
+	hm.m.Store(newM)
+	return h
+}
@@ -0,0 +1,28 @@
+package tenantmetrics
+
+import (
+	"testing"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/auth"
+)
+
+func TestHistogramMap(t *testing.T) {
+	hm := NewHistogramMap(`histogram_map_test{foo="bar"}`)
+	hm.Get(&auth.Token{AccountID: 1, ProjectID: 2}).Update(1)
+	hm.Get(&auth.Token{AccountID: 1, ProjectID: 2}).Update(3)
+	hm.Get(&auth.Token{AccountID: 4, ProjectID: 0}).Update(12)
+
+	f := func(at *auth.Token, countExpected uint64) {
+		t.Helper()
+		var count uint64
+		hm.Get(at).VisitNonZeroBuckets(func(_ string, c uint64) {
+			count += c
+		})
+		if count != countExpected {
+			t.Fatalf("unexpected histogram count for %v; got %d; want %d", at, count, countExpected)
+		}
+	}
+	f(&auth.Token{AccountID: 1, ProjectID: 2}, 2)
+	f(&auth.Token{AccountID: 4, ProjectID: 0}, 1)
+	f(&auth.Token{}, 0)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/bytesutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/cgroup"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/memory"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/timeutil"
 	"github.com/VictoriaMetrics/fastcache"
 )
@@ -49,9 +50,12 @@ type Cache struct {
 	maxBytes int
 
 	// mu serializes access to curr, prev and mode
-	// in expirationWatcher, prevCacheWatcher and cacheSizeWatcher.
+	// in expirationWatcher, prevCacheWatcher, cacheSizeWatcher and shrinkOnMemoryPressure.
 	mu sync.Mutex
 
+	// unregisterPressureCallback unregisters shrinkOnMemoryPressure callback.
+	unregisterPressureCallback func()
+
 	wg     sync.WaitGroup
 	stopCh chan struct{}
 }
@@ -111,9 +115,30 @@ func newCacheInternal(curr, prev *fastcache.Cache, mode, maxBytes int) *Cache {
 	c.prev.Store(prev)
 	c.stopCh = make(chan struct{})
 	c.mode.Store(uint32(mode))
+	c.unregisterPressureCallback = memory.RegisterPressureCallback(c.shrinkOnMemoryPressure)
 	return &c
 }
 
+// shrinkOnMemoryPressure frees up memory occupied by c when the memory pressure exceeds -memory.pressureThreshold.
+func (c *Cache) shrinkOnMemoryPressure() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// The prev cache contains less frequently accessed entries, so it is dropped at first.
+	var cs fastcache.Stats
+	prev := c.prev.Load()
+	prev.UpdateStats(&cs)
+	prev.Reset()
+	if c.mode.Load() == whole {
+		// There is no prev cache in whole mode, so drop the curr cache.
+		// This is better than OOM kill.
+		curr := c.curr.Load()
+		curr.UpdateStats(&cs)
+		curr.Reset()
+	}
+	updateCacheStatsHistory(&c.csHistory, &cs)
+}
+
 func (c *Cache) runWatchers(expireDuration time.Duration) {
 	c.wg.Add(1)
 	go func() {
@@ -301,6 +326,7 @@ func (c *Cache) Save(filePath string) error {
 //
 // The cache cannot be used after the Stop call.
 func (c *Cache) Stop() {
+	c.unregisterPressureCallback()
 	close(c.stopCh)
 	c.wg.Wait()
 
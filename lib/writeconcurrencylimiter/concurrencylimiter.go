@@ -6,10 +6,13 @@ import (
 	"io"
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/cgroup"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/flagutil"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/httpserver"
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/memory"
 	"github.com/VictoriaMetrics/VictoriaMetrics/lib/timerpool"
 	"github.com/VictoriaMetrics/metrics"
 )
@@ -21,6 +24,10 @@ var (
 		"See also -insert.maxQueueDuration")
 	maxQueueDuration = flag.Duration("insert.maxQueueDuration", time.Minute, "The maximum duration to wait in the queue when -maxConcurrentInserts "+
 		"concurrent insert requests are executed")
+	maxConcurrentInsertsBytes = flagutil.NewBytes("maxConcurrentInsertsBytes", 0, "The maximum summary size of insert requests' data, which is read concurrently. "+
+		"New insert requests wait in the queue for up to -insert.maxQueueDuration while the size of in-flight data exceeds this limit. "+
+		"This prevents from out of memory errors when clients send a few big requests, while still allowing many concurrent small requests. "+
+		"Zero value means 25% of the memory allowed via -memory.allowedPercent or -memory.allowedBytes. See also -maxConcurrentInserts")
 )
 
 // Reader is a reader, which increases the concurrency after the first Read() call
@@ -30,6 +37,9 @@ var (
 type Reader struct {
 	r                    io.Reader
 	increasedConcurrency bool
+
+	// inflightBytes is the number of bytes read since the concurrency has been increased.
+	inflightBytes int64
 }
 
 // GetReader returns the Reader for r.
@@ -64,24 +74,67 @@ var readerPool sync.Pool
 func (r *Reader) Read(p []byte) (int, error) {
 	n, err := r.r.Read(p)
 	if !r.increasedConcurrency {
-		if !incConcurrency() {
-			err = &httpserver.ErrorWithStatusCode{
-				Err: fmt.Errorf("cannot process insert request for %.3f seconds because %d concurrent insert requests are executed. "+
-					"Possible solutions: to reduce workload; to increase compute resources at the server; "+
-					"to increase -insert.maxQueueDuration; to increase -maxConcurrentInserts",
-					maxQueueDuration.Seconds(), *maxConcurrentInserts),
-				StatusCode: http.StatusServiceUnavailable,
-			}
+		if err := acquireConcurrency(); err != nil {
 			return 0, err
 		}
 		r.increasedConcurrency = true
 	}
+	if n > 0 {
+		inflightBytes.Add(int64(n))
+		r.inflightBytes += int64(n)
+	}
 	return n, err
 }
 
+// Do calls f with the increased concurrency for processing insert request with the given size in bytes.
+//
+// It is intended for insert requests, which are already read into memory, so they cannot be passed via Reader.
+// The size is accounted against -maxConcurrentInsertsBytes until f returns.
+func Do(size int, f func() error) error {
+	if err := acquireConcurrency(); err != nil {
+		return err
+	}
+	inflightBytes.Add(int64(size))
+	defer func() {
+		releaseInflightBytes(int64(size))
+		decConcurrency()
+	}()
+	return f()
+}
+
+// acquireConcurrency increases the concurrency and waits until in-flight bytes drop below -maxConcurrentInsertsBytes.
+//
+// decConcurrency() must be called when the concurrency is no longer needed if acquireConcurrency returns nil error.
+func acquireConcurrency() error {
+	if !incConcurrency() {
+		return &httpserver.ErrorWithStatusCode{
+			Err: fmt.Errorf("cannot process insert request for %.3f seconds because %d concurrent insert requests are executed. "+
+				"Possible solutions: to reduce workload; to increase compute resources at the server; "+
+				"to increase -insert.maxQueueDuration; to increase -maxConcurrentInserts",
+				maxQueueDuration.Seconds(), *maxConcurrentInserts),
+			StatusCode: http.StatusServiceUnavailable,
+		}
+	}
+	if !waitForInflightBytes() {
+		decConcurrency()
+		return &httpserver.ErrorWithStatusCode{
+			Err: fmt.Errorf("cannot process insert request for %.3f seconds because %d bytes of concurrent insert requests are being read. "+
+				"Possible solutions: to reduce workload; to increase compute resources at the server; "+
+				"to increase -insert.maxQueueDuration; to increase -maxConcurrentInsertsBytes",
+				maxQueueDuration.Seconds(), inflightBytes.Load()),
+			StatusCode: http.StatusServiceUnavailable,
+		}
+	}
+	return nil
+}
+
 // DecConcurrency decreases the concurrency, so it could be increased again after the next Read() call.
+//
+// It also releases the bytes read since the concurrency has been increased.
 func (r *Reader) DecConcurrency() {
 	if r.increasedConcurrency {
+		releaseInflightBytes(r.inflightBytes)
+		r.inflightBytes = 0
 		decConcurrency()
 		r.increasedConcurrency = false
 	}
@@ -122,6 +175,70 @@ func decConcurrency() {
 	<-concurrencyLimitCh
 }
 
+var (
+	// inflightBytes is the number of bytes read by all the Reader instances with increased concurrency.
+	inflightBytes atomic.Int64
+
+	// inflightBytesReleasedCh is closed and re-created every time inflightBytes is decreased.
+	inflightBytesReleasedCh   = make(chan struct{})
+	inflightBytesReleasedChMu sync.Mutex
+)
+
+func getMaxInflightBytes() int64 {
+	if n := maxConcurrentInsertsBytes.N; n > 0 {
+		return n
+	}
+	return int64(memory.Allowed() / 4)
+}
+
+// waitForInflightBytes waits until the number of in-flight bytes drops below -maxConcurrentInsertsBytes.
+//
+// The limit is checked only when the request starts reading its data. This allows a single request
+// exceeding the limit to proceed if there are no other in-flight requests, and prevents from deadlocks
+// between in-flight requests.
+//
+// It returns false if the in-flight bytes don't drop below the limit during -insert.maxQueueDuration.
+func waitForInflightBytes() bool {
+	maxBytes := getMaxInflightBytes()
+	var t *time.Timer
+	for {
+		// Obtain the channel before checking inflightBytes, so the release, which happens after the check, isn't missed.
+		inflightBytesReleasedChMu.Lock()
+		ch := inflightBytesReleasedCh
+		inflightBytesReleasedChMu.Unlock()
+
+		if inflightBytes.Load() < maxBytes {
+			if t != nil {
+				timerpool.Put(t)
+			}
+			return true
+		}
+		if t == nil {
+			concurrencyBytesLimitReached.Inc()
+			t = timerpool.Get(*maxQueueDuration)
+		}
+		select {
+		case <-ch:
+		case <-t.C:
+			timerpool.Put(t)
+			concurrencyBytesLimitTimeout.Inc()
+			return false
+		}
+	}
+}
+
+func releaseInflightBytes(n int64) {
+	if n <= 0 {
+		return
+	}
+	inflightBytes.Add(-n)
+
+	inflightBytesReleasedChMu.Lock()
+	close(inflightBytesReleasedCh)
+	inflightBytesReleasedCh = make(chan struct{})
+	inflightBytesReleasedChMu.Unlock()
+}
+
 var (
 	concurrencyLimitReached = metrics.NewCounter(`vm_concurrent_insert_limit_reached_total`)
 	concurrencyLimitTimeout = metrics.NewCounter(`vm_concurrent_insert_limit_timeout_total`)
@@ -134,4 +251,14 @@ var (
 		concurrencyLimitChOnce.Do(initConcurrencyLimitCh)
 		return float64(len(concurrencyLimitCh))
 	})
+
+	concurrencyBytesLimitReached = metrics.NewCounter(`vm_concurrent_insert_bytes_limit_reached_total`)
+	concurrencyBytesLimitTimeout = metrics.NewCounter(`vm_concurrent_insert_bytes_limit_timeout_total`)
+
+	_ = metrics.NewGauge(`vm_concurrent_insert_bytes_capacity`, func() float64 {
+		return float64(getMaxInflightBytes())
+	})
+	_ = metrics.NewGauge(`vm_concurrent_insert_bytes_current`, func() float64 {
+		return float64(inflightBytes.Load())
+	})
 )
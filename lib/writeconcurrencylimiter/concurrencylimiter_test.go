@@ -0,0 +1,101 @@
+package writeconcurrencylimiter
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/VictoriaMetrics/VictoriaMetrics/lib/httpserver"
+)
+
+func TestReaderInflightBytesLimit(t *testing.T) {
+	defer func(n int64, d time.Duration) {
+		maxConcurrentInsertsBytes.N = n
+		*maxQueueDuration = d
+	}(maxConcurrentInsertsBytes.N, *maxQueueDuration)
+	maxConcurrentInsertsBytes.N = 10
+	*maxQueueDuration = 10 * time.Millisecond
+
+	buf := make([]byte, 100)
+
+	// A single request exceeding the limit must be allowed if there are no other in-flight requests
+	r1 := GetReader(bytes.NewBufferString("0123456789abcdef"))
+	n, err := r1.Read(buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if n != 16 {
+		t.Fatalf("unexpected number of bytes read; got %d; want 16", n)
+	}
+	if v := inflightBytes.Load(); v != 16 {
+		t.Fatalf("unexpected inflight bytes; got %d; want 16", v)
+	}
+
+	// The next request must wait until the in-flight bytes drop below the limit
+	r2 := GetReader(bytes.NewBufferString("foo"))
+	_, err = r2.Read(buf)
+	var esc *httpserver.ErrorWithStatusCode
+	if !errors.As(err, &esc) || esc.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expecting error with status code %d; got %v", http.StatusServiceUnavailable, err)
+	}
+	PutReader(r2)
+
+	// The waiting request must proceed after the in-flight bytes are released
+	*maxQueueDuration = 10 * time.Second
+	resultCh := make(chan error, 1)
+	r3 := GetReader(bytes.NewBufferString("bar"))
+	go func() {
+		_, err := r3.Read(buf[:10])
+		resultCh <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	r1.DecConcurrency()
+	select {
+	case err := <-resultCh:
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("timeout when waiting for the in-flight bytes to be released")
+	}
+	PutReader(r3)
+	PutReader(r1)
+
+	if v := inflightBytes.Load(); v != 0 {
+		t.Fatalf("unexpected inflight bytes after releasing all the readers; got %d; want 0", v)
+	}
+}
+
+func TestDoInflightBytesLimit(t *testing.T) {
+	defer func(n int64, d time.Duration) {
+		maxConcurrentInsertsBytes.N = n
+		*maxQueueDuration = d
+	}(maxConcurrentInsertsBytes.N, *maxQueueDuration)
+	maxConcurrentInsertsBytes.N = 10
+	*maxQueueDuration = 10 * time.Millisecond
+
+	err := Do(16, func() error {
+		if v := inflightBytes.Load(); v != 16 {
+			t.Fatalf("unexpected inflight bytes; got %d; want 16", v)
+		}
+
+		// Concurrent requests must fail when the in-flight bytes exceed the limit
+		err := Do(3, func() error {
+			t.Fatalf("unexpected call")
+			return nil
+		})
+		var esc *httpserver.ErrorWithStatusCode
+		if !errors.As(err, &esc) || esc.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("expecting error with status code %d; got %v", http.StatusServiceUnavailable, err)
+		}
+		return errors.New("some error")
+	})
+	if err == nil || err.Error() != "some error" {
+		t.Fatalf("expecting the error returned from f; got %v", err)
+	}
+	if v := inflightBytes.Load(); v != 0 {
+		t.Fatalf("unexpected inflight bytes after Do; got %d; want 0", v)
+	}
+}